@@ -0,0 +1,149 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/migrations"
+	"code.gitea.io/gitea/modules/log"
+	repo_migrations "code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/urfave/cli"
+)
+
+// CmdDumpRepo represents the available dump-repo sub-command.
+var CmdDumpRepo = cli.Command{
+	Name:        "dump-repo",
+	Usage:       "Dump the issue tracker of a repository",
+	Description: "This is a command for dumping the labels, the milestones and the issues of a repository with their comments to a GitHub migration archive.",
+	Action:      runDumpRepo,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "repo, r",
+			Value: "",
+			Usage: "Repository to dump, as owner/name",
+		},
+		cli.StringFlag{
+			Name:  "file, f",
+			Value: "",
+			Usage: "Name of the archive, defaults to <name>-issues.tar.gz",
+		},
+	},
+}
+
+// CmdRestoreRepo represents the available restore-repo sub-command.
+var CmdRestoreRepo = cli.Command{
+	Name:        "restore-repo",
+	Usage:       "Restore the issue tracker of a repository",
+	Description: "This is a command for restoring the labels, the milestones and the issues of a GitHub migration archive into a repository without issues nor pull requests.",
+	Action:      runRestoreRepo,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "repo, r",
+			Value: "",
+			Usage: "Repository to restore into, as owner/name",
+		},
+		cli.StringFlag{
+			Name:  "file, f",
+			Value: "",
+			Usage: "Name of the archive",
+		},
+		cli.StringFlag{
+			Name:  "doer",
+			Value: "",
+			Usage: "User restoring the issues of the users not of this instance, defaults to the owner of the repository",
+		},
+	},
+}
+
+func initDumpRepoDB() error {
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	log.Trace("AppPath: %s", setting.AppPath)
+	log.Trace("AppWorkPath: %s", setting.AppWorkPath)
+	log.Trace("Custom path: %s", setting.CustomPath)
+	log.Trace("Log path: %s", setting.LogRootPath)
+	setting.InitDBConfig()
+
+	return models.NewEngine(context.Background(), migrations.EnsureUpToDate)
+}
+
+func getDumpedRepo(ctx *cli.Context) (*models.Repository, error) {
+	ownerAndName := strings.SplitN(ctx.String("repo"), "/", 2)
+	if len(ownerAndName) != 2 {
+		return nil, errors.New("the repository must be given as owner/name")
+	}
+	return models.GetRepositoryByOwnerAndName(ownerAndName[0], ownerAndName[1])
+}
+
+func runDumpRepo(ctx *cli.Context) error {
+	if err := initDumpRepoDB(); err != nil {
+		return err
+	}
+
+	repo, err := getDumpedRepo(ctx)
+	if err != nil {
+		return err
+	}
+	fileName := ctx.String("file")
+	if len(fileName) == 0 {
+		fileName = repo.Name + "-issues.tar.gz"
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := repo_migrations.DumpGithubArchive(repo, f); err != nil {
+		return err
+	}
+
+	fmt.Printf("The issue tracker of %s has been dumped to %s\n", repo.FullName(), fileName)
+	return nil
+}
+
+func runRestoreRepo(ctx *cli.Context) error {
+	if err := initDumpRepoDB(); err != nil {
+		return err
+	}
+
+	repo, err := getDumpedRepo(ctx)
+	if err != nil {
+		return err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	doer := repo.Owner
+	if doerName := ctx.String("doer"); len(doerName) > 0 {
+		if doer, err = models.GetUserByName(doerName); err != nil {
+			return err
+		}
+	} else if doer.IsOrganization() {
+		return errors.New("the doer must be given to restore into the repository of an organization")
+	}
+
+	f, err := os.Open(ctx.String("file"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := repo_migrations.RestoreGithubArchive(context.Background(), doer, repo, f); err != nil {
+		return err
+	}
+
+	fmt.Printf("The issue tracker of %s has been restored from %s\n", repo.FullName(), ctx.String("file"))
+	return nil
+}
@@ -435,6 +435,9 @@ func hookPrintResults(results []private.HookPostReceiveBranchResult) {
 		}
 
 		fmt.Fprintln(os.Stderr, "")
+		if len(res.Warning) > 0 {
+			fmt.Fprintf(os.Stderr, "%s\n\n", res.Warning)
+		}
 		if res.Create {
 			fmt.Fprintf(os.Stderr, "Create a new pull request for '%s':\n", res.Branch)
 			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
@@ -455,6 +458,9 @@ func pushOptions() map[string]string {
 			kv := strings.SplitN(opt, "=", 2)
 			if len(kv) == 2 {
 				opts[kv[0]] = kv[1]
+			} else if len(kv[0]) > 0 {
+				// a flag like "-o pr.create" is set
+				opts[kv[0]] = "true"
 			}
 		}
 	}
@@ -22,13 +22,13 @@ import (
 var CmdMigrateStorage = cli.Command{
 	Name:        "migrate-storage",
 	Usage:       "Migrate the storage",
-	Description: "This is a command for migrating storage.",
+	Description: "This is a command for migrating storage. Files already in the new storage are skipped, so an interrupted migration can be resumed and the files added while Gitea is running can be migrated by running it again.",
 	Action:      runMigrateStorage,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "type, t",
 			Value: "",
-			Usage: "Kinds of files to migrate, currently only 'attachments' is supported",
+			Usage: "Kinds of files to migrate: attachments, lfs, avatars or repo-avatars",
 		},
 		cli.StringFlag{
 			Name:  "storage, s",
@@ -74,37 +74,83 @@ var CmdMigrateStorage = cli.Command{
 			Name:  "minio-use-ssl",
 			Usage: "Enable SSL for minio",
 		},
+		cli.StringFlag{
+			Name:  "from",
+			Value: "",
+			Usage: "Name of a configured [storage.NAME] section to migrate the files from (leave blank for the storage of the type)",
+		},
+		cli.StringFlag{
+			Name:  "to",
+			Value: "",
+			Usage: "Name of a configured [storage.NAME] section to migrate the files to, instead of the new storage given by the flags above",
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "Verify the SHA-256 hashes of the migrated files and of the files already in the new storage",
+		},
 	},
 }
 
-func migrateAttachments(dstStorage storage.ObjectStorage) error {
+// migrateStats counts what the migration did with the files
+type migrateStats struct {
+	copied  int
+	skipped int
+	missing int
+}
+
+func (stats *migrateStats) sync(dstStorage, srcStorage storage.ObjectStorage, p string, verify bool) error {
+	if p == "" {
+		return nil
+	}
+	result, err := storage.Sync(dstStorage, p, srcStorage, p, verify)
+	if err != nil {
+		return fmt.Errorf("Unable to migrate %s: %v", p, err)
+	}
+	switch result {
+	case storage.SyncCopied:
+		stats.copied++
+	case storage.SyncSkipped:
+		stats.skipped++
+	case storage.SyncMissing:
+		log.Warn("%s is missing in the original storage", p)
+		stats.missing++
+	}
+	return nil
+}
+
+func migrateAttachments(dstStorage, srcStorage storage.ObjectStorage, verify bool, stats *migrateStats) error {
 	return models.IterateAttachment(func(attach *models.Attachment) error {
-		_, err := storage.Copy(dstStorage, attach.RelativePath(), storage.Attachments, attach.RelativePath())
-		return err
+		return stats.sync(dstStorage, srcStorage, attach.RelativePath(), verify)
 	})
 }
 
-func migrateLFS(dstStorage storage.ObjectStorage) error {
+func migrateLFS(dstStorage, srcStorage storage.ObjectStorage, verify bool, stats *migrateStats) error {
 	return models.IterateLFS(func(mo *models.LFSMetaObject) error {
-		_, err := storage.Copy(dstStorage, mo.RelativePath(), storage.LFS, mo.RelativePath())
-		return err
+		return stats.sync(dstStorage, srcStorage, mo.RelativePath(), verify)
 	})
 }
 
-func migrateAvatars(dstStorage storage.ObjectStorage) error {
+func migrateAvatars(dstStorage, srcStorage storage.ObjectStorage, verify bool, stats *migrateStats) error {
 	return models.IterateUser(func(user *models.User) error {
-		_, err := storage.Copy(dstStorage, user.CustomAvatarRelativePath(), storage.Avatars, user.CustomAvatarRelativePath())
-		return err
+		return stats.sync(dstStorage, srcStorage, user.CustomAvatarRelativePath(), verify)
 	})
 }
 
-func migrateRepoAvatars(dstStorage storage.ObjectStorage) error {
+func migrateRepoAvatars(dstStorage, srcStorage storage.ObjectStorage, verify bool, stats *migrateStats) error {
 	return models.IterateRepository(func(repo *models.Repository) error {
-		_, err := storage.Copy(dstStorage, repo.CustomAvatarRelativePath(), storage.RepoAvatars, repo.CustomAvatarRelativePath())
-		return err
+		return stats.sync(dstStorage, srcStorage, repo.CustomAvatarRelativePath(), verify)
 	})
 }
 
+// getConfiguredStorage returns the storage configured by the section [storage.name]
+func getConfiguredStorage(name string) (storage.ObjectStorage, error) {
+	cfg, err := setting.GetStorage(name)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewStorage(cfg.Type, &cfg)
+}
+
 func runMigrateStorage(ctx *cli.Context) error {
 	if err := initDB(); err != nil {
 		return err
@@ -129,22 +175,69 @@ func runMigrateStorage(ctx *cli.Context) error {
 
 	var dstStorage storage.ObjectStorage
 	var err error
+	if to := ctx.String("to"); to != "" {
+		dstStorage, err = getConfiguredStorage(to)
+	} else {
+		dstStorage, err = newMigrateStorage(goCtx, ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	tp := strings.ToLower(ctx.String("type"))
+	srcStorage := map[string]storage.ObjectStorage{
+		"attachments":  storage.Attachments,
+		"lfs":          storage.LFS,
+		"avatars":      storage.Avatars,
+		"repo-avatars": storage.RepoAvatars,
+	}[tp]
+	if from := ctx.String("from"); from != "" {
+		if srcStorage, err = getConfiguredStorage(from); err != nil {
+			return err
+		}
+	}
+
+	verify := ctx.Bool("verify")
+	stats := &migrateStats{}
+	switch tp {
+	case "attachments":
+		err = migrateAttachments(dstStorage, srcStorage, verify, stats)
+	case "lfs":
+		err = migrateLFS(dstStorage, srcStorage, verify, stats)
+	case "avatars":
+		err = migrateAvatars(dstStorage, srcStorage, verify, stats)
+	case "repo-avatars":
+		err = migrateRepoAvatars(dstStorage, srcStorage, verify, stats)
+	default:
+		return fmt.Errorf("Unsupported storage: %s", ctx.String("type"))
+	}
+	log.Info("Migrated %s: %d copied, %d already in the new storage, %d missing", tp, stats.copied, stats.skipped, stats.missing)
+	if err != nil {
+		return err
+	}
+
+	log.Warn("All files have been copied to the new placement but old files are still on the orignial placement.")
+
+	return nil
+}
+
+// newMigrateStorage returns the new storage given by the flags
+func newMigrateStorage(goCtx context.Context, ctx *cli.Context) (storage.ObjectStorage, error) {
 	switch strings.ToLower(ctx.String("storage")) {
 	case "":
 		fallthrough
 	case string(storage.LocalStorageType):
 		p := ctx.String("path")
 		if p == "" {
-			log.Fatal("Path must be given when storage is loal")
-			return nil
+			return nil, fmt.Errorf("Path must be given when storage is local")
 		}
-		dstStorage, err = storage.NewLocalStorage(
+		return storage.NewLocalStorage(
 			goCtx,
 			storage.LocalStorageConfig{
 				Path: p,
 			})
 	case string(storage.MinioStorageType):
-		dstStorage, err = storage.NewMinioStorage(
+		return storage.NewMinioStorage(
 			goCtx,
 			storage.MinioStorageConfig{
 				Endpoint:        ctx.String("minio-endpoint"),
@@ -156,35 +249,6 @@ func runMigrateStorage(ctx *cli.Context) error {
 				UseSSL:          ctx.Bool("minio-use-ssl"),
 			})
 	default:
-		return fmt.Errorf("Unsupported storage type: %s", ctx.String("storage"))
-	}
-	if err != nil {
-		return err
-	}
-
-	tp := strings.ToLower(ctx.String("type"))
-	switch tp {
-	case "attachments":
-		if err := migrateAttachments(dstStorage); err != nil {
-			return err
-		}
-	case "lfs":
-		if err := migrateLFS(dstStorage); err != nil {
-			return err
-		}
-	case "avatars":
-		if err := migrateAvatars(dstStorage); err != nil {
-			return err
-		}
-	case "repo-avatars":
-		if err := migrateRepoAvatars(dstStorage); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("Unsupported storage: %s", ctx.String("type"))
+		return nil, fmt.Errorf("Unsupported storage type: %s", ctx.String("storage"))
 	}
-
-	log.Warn("All files have been copied to the new placement but old files are still on the orignial placement.")
-
-	return nil
 }
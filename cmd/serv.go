@@ -284,7 +284,12 @@ func runServ(c *cli.Context) error {
 
 	// Update user key activity.
 	if results.KeyID > 0 {
-		if err = private.UpdatePublicKeyInRepo(results.KeyID, results.RepoID); err != nil {
+		// SSH_CONNECTION is "client_ip client_port server_ip server_port"
+		var ip string
+		if fields := strings.Fields(os.Getenv("SSH_CONNECTION")); len(fields) > 0 {
+			ip = fields[0]
+		}
+		if err = private.UpdatePublicKeyInRepo(results.KeyID, results.RepoID, ip); err != nil {
 			fail("Internal error", "UpdatePublicKeyInRepo: %v", err)
 		}
 	}
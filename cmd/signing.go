@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/signing"
+
+	"github.com/urfave/cli"
+)
+
+// CmdSigning represents the available signing sub-command.
+var CmdSigning = cli.Command{
+	Name:        "signing",
+	Usage:       "Sign the commits with the key of the external signing backend",
+	Description: "This command should only be called by Git as its gpg program, it takes the arguments of gpg after --",
+	Action:      runSigning,
+}
+
+func runSigning(c *cli.Context) error {
+	setup("signing.log", false)
+
+	if !signing.IsExternal() {
+		return errors.New("the signing key is not held by an external backend")
+	}
+
+	// git runs its gpg program with --status-fd=2 -bsau <key>
+	var keyID string
+	args := c.Args()
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--status-fd=2":
+		case arg == "-bsau" && i+1 < len(args):
+			i++
+			keyID = args[i]
+		default:
+			return fmt.Errorf("unsupported gpg argument: %s", arg)
+		}
+	}
+
+	expected, err := signing.KeyID()
+	if err != nil {
+		return err
+	}
+	if keyID != expected && !strings.HasSuffix(strings.ToUpper(keyID), expected) {
+		return fmt.Errorf("unknown signing key: %s", keyID)
+	}
+
+	var signature strings.Builder
+	if err := signing.Sign(os.Stdin, &signature); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(os.Stderr, "[GNUPG:] BEGIN_SIGNING H8\n[GNUPG:] SIG_CREATED D 1 8 00 %d %s\n", time.Now().Unix(), expected); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, signature.String())
+	return err
+}
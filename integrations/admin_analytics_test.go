@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	analytics_service "code.gitea.io/gitea/services/analytics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAnalytics(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// only the site admins see the analytics
+	session := loginUser(t, "user2")
+	session.MakeRequest(t, NewRequest(t, "GET", "/admin/analytics"), http.StatusForbidden)
+
+	// the API requests of the signed in users are counted
+	token := getTokenForLoggedInUser(t, session)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/user?token="+token), http.StatusOK)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/user/repos?token="+token), http.StatusOK)
+	assert.NoError(t, analytics_service.AggregateInstanceStats(context.Background()))
+
+	adminSession := loginUser(t, "user1")
+	resp := adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/analytics?days=7"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".analytics table").First().Find("tbody tr").Length())
+
+	resp = adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/analytics/export?days=7"), http.StatusOK)
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "instance-stats-7d.json")
+	var export struct {
+		Days []struct {
+			Users       int64 `json:"users"`
+			APIRequests int64 `json:"api_requests"`
+		} `json:"days"`
+		TopAPIConsumers []struct {
+			UserName string `json:"user_name"`
+			Requests int64  `json:"requests"`
+		} `json:"top_api_consumers"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &export))
+	if assert.Len(t, export.Days, 1) {
+		assert.Equal(t, models.CountUsers(), export.Days[0].Users)
+		assert.True(t, export.Days[0].APIRequests >= 2)
+	}
+	// the requests of the previous tests may be counted too
+	var requests int64
+	for _, consumer := range export.TopAPIConsumers {
+		if consumer.UserName == "user2" {
+			requests = consumer.Requests
+		}
+	}
+	assert.True(t, requests >= 2)
+}
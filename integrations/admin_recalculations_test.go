@@ -0,0 +1,66 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/recalculation"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminRecalculations(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// only the site admins see the recalculations
+	session := loginUser(t, "user2")
+	session.MakeRequest(t, NewRequest(t, "GET", "/admin/recalculations"), http.StatusForbidden)
+
+	adminSession := loginUser(t, "user1")
+	resp := adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/recalculations"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 4, htmlDoc.doc.Find("#recalculations tbody tr").Length())
+
+	req := NewRequestWithValues(t, "POST", "/admin/recalculations/label_counts/start", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+	for i := 0; i < 100 && recalculation.IsRunning("label_counts"); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	resp = adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/recalculations/progress"), http.StatusOK)
+	var progresses []struct {
+		Name    string `json:"name"`
+		Status  int    `json:"status"`
+		Running bool   `json:"running"`
+		Percent int    `json:"percent"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &progresses))
+	for _, progress := range progresses {
+		if progress.Name == "label_counts" {
+			assert.EqualValues(t, models.RecalculationFinished, progress.Status)
+			assert.False(t, progress.Running)
+			assert.Equal(t, 100, progress.Percent)
+		} else {
+			assert.EqualValues(t, models.RecalculationNotStarted, progress.Status)
+		}
+	}
+	models.AssertExistsAndLoadBean(t, &models.Label{ID: 1, NumIssues: 2})
+
+	resp = adminSession.MakeRequest(t, NewRequest(t, "GET", "/admin/recalculations"), http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#recalculations .ui.progress").Length())
+
+	req = NewRequestWithValues(t, "POST", "/admin/recalculations/unknown/start", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	adminSession.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminOrgWebhookHosts(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// only the site admins manage the hosts
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/admin/orgs/3/webhook_hosts")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	adminSession := loginUser(t, "user1")
+	req = NewRequest(t, "GET", "/admin/orgs/2/webhook_hosts")
+	adminSession.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/admin/orgs/3/webhook_hosts")
+	resp := adminSession.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	req = NewRequestWithValues(t, "POST", "/admin/orgs/3/webhook_hosts", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"hosts": "*.example.com\nhooks.example.org",
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+
+	// the webhook of the organization calls an invalid host, it is disabled
+	models.AssertExistsAndLoadBean(t, &models.Webhook{ID: 3}, models.Cond("is_active = ?", false))
+	allowlist, err := models.GetWebhookHostAllowlist(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*.example.com", "hooks.example.org"}, allowlist.HostList())
+
+	token := getTokenForLoggedInUser(t, session)
+	for hookURL, status := range map[string]int{
+		"http://evil.com/hook":             http.StatusUnprocessableEntity,
+		"https://hooks.example.org/hook":   http.StatusCreated,
+		"https://ci.example.com:8443/hook": http.StatusCreated,
+	} {
+		for _, link := range []string{"/api/v1/orgs/user3/hooks", "/api/v1/repos/user3/repo3/hooks"} {
+			req = NewRequestWithJSON(t, "POST", link+"?token="+token, &api.CreateHookOption{
+				Type:   "gitea",
+				Config: api.CreateHookOptionConfig{"content_type": "json", "url": hookURL},
+				Active: true,
+			})
+			session.MakeRequest(t, req, status)
+		}
+	}
+
+	// the other organizations aren't restricted
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/hooks?token="+token, &api.CreateHookOption{
+		Type:   "gitea",
+		Config: api.CreateHookOptionConfig{"content_type": "json", "url": "http://evil.com/hook"},
+		Active: true,
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminAudit(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(enabled bool) {
+		setting.Audit.Enabled = enabled
+	}(setting.Audit.Enabled)
+	setting.Audit.Enabled = true
+
+	// the users log in again as their sessions may have been cached by previous tests
+	user2Session := loginUserWithPassword(t, "user2", userPassword)
+	user2Token := getTokenForLoggedInUser(t, user2Session)
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/collaborators/user5?token="+user2Token, &api.AddCollaboratorOption{})
+	user2Session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithValues(t, "POST", "/user/login", map[string]string{
+		"_csrf":     GetCSRF(t, emptyTestSession(t), "/user/login"),
+		"user_name": "user4",
+		"password":  "wrong password",
+	})
+	MakeRequest(t, req, http.StatusOK)
+
+	// user1 is an admin user
+	session := loginUserWithPassword(t, "user1", userPassword)
+	token := getTokenForLoggedInUser(t, session)
+
+	// only the site admins see the audit log
+	req = NewRequest(t, "GET", "/api/v1/admin/audit?token="+user2Token)
+	user2Session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/audit?action=collaborator_add&token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var events []*api.AuditEvent
+	DecodeJSON(t, resp, &events)
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, models.AuditActionCollaboratorAdd, events[0].Action)
+		assert.Equal(t, "user2", events[0].DoerName)
+		assert.Equal(t, models.AuditTargetRepository, events[0].TargetType)
+		assert.Equal(t, "user2/repo1", events[0].TargetName)
+		assert.Equal(t, "Added the collaborator user5", events[0].Description)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/admin/audit?action=user_login_failed&target_type=user&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &events)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "user4", events[0].TargetName)
+		assert.EqualValues(t, 0, events[0].DoerID)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/admin/audit?doer=user1&action=user_login&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &events)
+	assert.Len(t, events, 1)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/audit?since=yesterday&token="+token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/audit/export?doer=user2&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	records, err := csv.NewReader(strings.NewReader(resp.Body.String())).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, "action", records[0][2])
+		assert.Equal(t, string(models.AuditActionUserLogin), records[1][2])
+		assert.Equal(t, string(models.AuditActionCollaboratorAdd), records[2][2])
+	}
+
+	// the admin page shows and exports the same events
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/admin/audit?action=collaborator_add"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#audit-events tbody tr").Length())
+	assert.Contains(t, htmlDoc.doc.Find("#audit-events tbody tr").Text(), "user2/repo1")
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/admin/audit/export?target_type=repository"), http.StatusOK)
+	records, err = csv.NewReader(strings.NewReader(resp.Body.String())).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	legalhold_service "code.gitea.io/gitea/services/legalhold"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminLegalHold(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/admin/legal_holds?token="+token, &api.CreateLegalHoldOption{
+		Username: "user2",
+		Owner:    "user2",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/legal_holds?token="+token, &api.CreateLegalHoldOption{
+		Owner:  "user2",
+		Repo:   "repo1",
+		Reason: "litigation",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var hold api.LegalHold
+	DecodeJSON(t, resp, &hold)
+	assert.Equal(t, "litigation", hold.Reason)
+	assert.Nil(t, hold.User)
+	if assert.NotNil(t, hold.Repository) {
+		assert.Equal(t, "user2/repo1", hold.Repository.FullName)
+	}
+	assert.Equal(t, "user1", hold.Creator.UserName)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/legal_holds?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var holds []*api.LegalHold
+	DecodeJSON(t, resp, &holds)
+	assert.Len(t, holds, 1)
+
+	// the edits of the held repository are kept
+	user2Session := loginUser(t, "user2")
+	user2Token := getTokenForLoggedInUser(t, user2Session)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/issues/1?token="+user2Token, &api.EditIssueOption{
+		Body: func(s string) *string { return &s }("edited content"),
+	})
+	user2Session.MakeRequest(t, req, http.StatusCreated)
+	models.AssertExistsAndLoadBean(t, &models.ContentRevision{RepoID: 1, IssueID: 1, Content: "content for the first issue"})
+
+	// the held repository can't be deleted
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1?token="+user2Token)
+	user2Session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1})
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/legal_holds/%d/export?token=%s", hold.ID, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	assert.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		files[f.Name], err = ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		r.Close()
+	}
+	assert.True(t, legalhold_service.Verify(files[legalhold_service.ContentFileName], string(files[legalhold_service.SignatureFileName])))
+	assert.False(t, legalhold_service.Verify(append(files[legalhold_service.ContentFileName], ' '), string(files[legalhold_service.SignatureFileName])))
+
+	var content struct {
+		Issues []struct {
+			ID      int64  `json:"id"`
+			Content string `json:"content"`
+		} `json:"issues"`
+		Revisions []struct {
+			IssueID int64  `json:"issue_id"`
+			Content string `json:"content"`
+		} `json:"revisions"`
+	}
+	assert.NoError(t, json.Unmarshal(files[legalhold_service.ContentFileName], &content))
+	assert.NotEmpty(t, content.Issues)
+	assert.Equal(t, "edited content", content.Issues[0].Content)
+	if assert.Len(t, content.Revisions, 1) {
+		assert.Equal(t, "content for the first issue", content.Revisions[0].Content)
+	}
+
+	// only admins can manage legal holds
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/legal_holds/%d?token=%s", hold.ID, user2Token))
+	user2Session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/legal_holds/%d?token=%s", hold.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/legal_holds/%d/export?token=%s", hold.ID, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
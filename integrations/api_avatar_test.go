@@ -0,0 +1,90 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testAvatarImage(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 64))))
+	return buf.Bytes()
+}
+
+func TestAPIRepoAvatar(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	data := testAvatarImage(t)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	url := fmt.Sprintf("/api/v1/repos/user2/repo1/avatar?token=%s", token)
+
+	req := NewRequestWithJSON(t, "POST", url, &api.UpdateRepoAvatarOption{Image: "not an image"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", url, &api.UpdateRepoAvatarOption{
+		Image: base64.StdEncoding.EncodeToString(data),
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NotEmpty(t, repo.Avatar)
+
+	req = NewRequestf(t, "GET", "/repo-avatars/%s?size=32", repo.Avatar)
+	resp := MakeRequest(t, req, http.StatusOK)
+	img, err := png.Decode(bytes.NewReader(resp.Body.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 32, img.Bounds().Dx())
+	assert.Equal(t, 32, img.Bounds().Dy())
+
+	req = NewRequestf(t, "GET", "/repo-avatars/%s?size=abc", repo.Avatar)
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	// only the repository admins may change the avatar
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/user2/repo1/avatar?token=%s", token4)
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", url)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Empty(t, repo.Avatar)
+}
+
+func TestAPIOrgAvatar(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	data := testAvatarImage(t)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	url := fmt.Sprintf("/api/v1/orgs/user3/avatar?token=%s", token)
+
+	req := NewRequestWithJSON(t, "POST", url, &api.UpdateOrgAvatarOption{
+		Image: base64.StdEncoding.EncodeToString(data),
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	assert.True(t, org.UseCustomAvatar)
+
+	req = NewRequest(t, "DELETE", url)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	org = models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	assert.False(t, org.UseCustomAvatar)
+}
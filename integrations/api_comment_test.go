@@ -177,3 +177,42 @@ func TestAPIDeleteComment(t *testing.T) {
 
 	models.AssertNotExistsBean(t, &models.Comment{ID: comment.ID})
 }
+
+func TestAPIListIssueTimeline(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: issue.RepoID}).(*models.Repository)
+	repoOwner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, repoOwner.Name)
+	link := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/timeline", repoOwner.Name, repo.Name, issue.Index)
+	req := NewRequest(t, "GET", link)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var events []*api.TimelineComment
+	DecodeJSON(t, resp, &events)
+	if assert.Len(t, events, 3) {
+		assert.EqualValues(t, "label", events[0].Type)
+		assert.NotNil(t, events[0].Label)
+		assert.EqualValues(t, "comment", events[1].Type)
+		assert.EqualValues(t, "comment", events[2].Type)
+	}
+
+	req = NewRequest(t, "GET", link+"?types=label")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &events)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 1, events[0].ID)
+	}
+
+	req = NewRequest(t, "GET", link+"?since_id=2")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &events)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 3, events[0].ID)
+	}
+
+	req = NewRequest(t, "GET", link+"?types=nonsense")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
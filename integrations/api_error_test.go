@@ -0,0 +1,56 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/not-a-repo?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusNotFound)
+	var apiError api.APIError
+	DecodeJSON(t, resp, &apiError)
+	assert.Equal(t, api.APIError{
+		Code:    "not_found",
+		Message: "Not Found",
+		URL:     setting.API.SwaggerURL,
+		Version: api.APIErrorVersion,
+	}, apiError)
+
+	// the invalid fields of the requests are reported with their names in the json
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, &api.CreateIssueOption{})
+	resp = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	apiError = api.APIError{}
+	DecodeJSON(t, resp, &apiError)
+	assert.Equal(t, "validation_failed", apiError.Code)
+	assert.Equal(t, "title: Required", apiError.Message)
+	assert.Equal(t, []*api.APIErrorDetail{{Field: "title", Code: "required", Message: "Required"}}, apiError.Errors)
+
+	req = NewRequestWithBody(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, nil)
+	resp = session.MakeRequest(t, req, http.StatusUnsupportedMediaType)
+	apiError = api.APIError{}
+	DecodeJSON(t, resp, &apiError)
+	assert.Equal(t, "unsupported_media_type", apiError.Code)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/topics?token="+token, &api.RepoTopicOptions{
+		Topics: []string{"valid", "#invalid"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	apiError = api.APIError{}
+	DecodeJSON(t, resp, &apiError)
+	assert.Equal(t, "validation_failed", apiError.Code)
+	assert.Equal(t, []*api.APIErrorDetail{{Field: "topics", Code: "invalid", Message: "#invalid"}}, apiError.Errors)
+}
@@ -6,9 +6,15 @@ package integrations
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/repofiles"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateForkNoLogin(t *testing.T) {
@@ -16,3 +22,43 @@ func TestCreateForkNoLogin(t *testing.T) {
 	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks", &api.CreateForkOption{})
 	MakeRequest(t, req, http.StatusUnauthorized)
 }
+
+func TestAPIListForksDivergence(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		assert.NoError(t, repo.GetOwner())
+		fork, err := repo_module.ForkRepository(user4, user4, repo, "repo1", "")
+		assert.NoError(t, err)
+
+		for _, commit := range []struct {
+			doer *models.User
+			repo *models.Repository
+			path string
+		}{
+			{user4, fork, "fork1.txt"},
+			{user4, fork, "fork2.txt"},
+			{user2, repo, "upstream.txt"},
+		} {
+			_, err = repofiles.CreateOrUpdateRepoFile(commit.repo, commit.doer, &repofiles.UpdateRepoFileOptions{
+				TreePath:  commit.path,
+				Message:   "Add " + commit.path,
+				Content:   commit.path,
+				IsNewFile: true,
+			})
+			assert.NoError(t, err)
+		}
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/forks")
+		resp := MakeRequest(t, req, http.StatusOK)
+		var forks []*api.Repository
+		DecodeJSON(t, resp, &forks)
+		assert.Len(t, forks, 1)
+		assert.Equal(t, &api.ForkDivergence{AheadBy: 2, BehindBy: 1}, forks[0].ForkDivergence)
+
+		req = NewRequest(t, "GET", "/user2/repo1/forks")
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "2 commits ahead, 1 commits behind master")
+	})
+}
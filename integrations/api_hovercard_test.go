@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIHovercard(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	getHovercard := func(session *TestSession, link string, expectedStatus int) *api.Hovercard {
+		req := NewRequest(t, "GET", "/api/v1/hovercard?url="+url.QueryEscape(link))
+		resp := session.MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+		var card api.Hovercard
+		DecodeJSON(t, resp, &card)
+		return &card
+	}
+
+	session := emptyTestSession(t)
+
+	card := getHovercard(session, setting.AppURL+"user2/repo1/issues/1", http.StatusOK)
+	assert.EqualValues(t, "issue", card.Type)
+	assert.EqualValues(t, "issue1", card.Title)
+	assert.EqualValues(t, "open", card.State)
+	assert.EqualValues(t, 1, card.Number)
+	assert.EqualValues(t, "user2/repo1", card.Repo.FullName)
+	assert.EqualValues(t, "user1", card.User.UserName)
+	assert.EqualValues(t, 2, card.Comments)
+	assert.NotEmpty(t, card.Labels)
+
+	card = getHovercard(session, "/user2/repo1/pulls/2", http.StatusOK)
+	assert.EqualValues(t, "pull", card.Type)
+	assert.EqualValues(t, "merged", card.State)
+
+	card = getHovercard(session, "/user2/repo1/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d", http.StatusOK)
+	assert.EqualValues(t, "commit", card.Type)
+	assert.EqualValues(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", card.SHA)
+	assert.NotNil(t, card.CommitAuthor)
+
+	card = getHovercard(session, "/user2", http.StatusOK)
+	assert.EqualValues(t, "user", card.Type)
+	assert.EqualValues(t, "user2", card.User.UserName)
+
+	// the private repositories are only readable by their collaborators
+	getHovercard(session, "/user2/repo2/issues/1", http.StatusNotFound)
+	getHovercard(loginUser(t, "user2"), "/user2/repo2/issues/1", http.StatusOK)
+
+	// the links of other instances are unknown
+	getHovercard(session, "https://example.com/user2/repo1/issues/1", http.StatusNotFound)
+	getHovercard(session, "/user2/repo1/issues/999", http.StatusNotFound)
+	getHovercard(session, "", http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func restoreIssueArchive(t *testing.T, session *TestSession, token, repoPath string, archive []byte, expectedStatus int) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("archive", "issues.tar.gz")
+	assert.NoError(t, err)
+	_, err = part.Write(archive)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req := NewRequestWithBody(t, "POST", fmt.Sprintf("/api/v1/repos/%s/issues/archive?token=%s", repoPath, token), body)
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	session.MakeRequest(t, req, expectedStatus)
+}
+
+func TestAPIIssueArchive(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/issues/archive?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "application/gzip", resp.Header().Get("Content-Type"))
+	archive := resp.Body.Bytes()
+
+	restoreIssueArchive(t, session, token, "user2/utf8", []byte("not an archive"), http.StatusUnprocessableEntity)
+	restoreIssueArchive(t, session, token, "user2/utf8", archive, http.StatusNoContent)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 33}).(*models.Repository)
+	assert.EqualValues(t, 2, repo.NumIssues)
+	issue, err := models.GetIssueByIndex(repo.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue1", issue.Title)
+
+	// the issue numbers can't be kept if the tracker isn't empty
+	restoreIssueArchive(t, session, token, "user2/utf8", archive, http.StatusConflict)
+
+	// only the administrators of the repository can dump it
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/issues/archive?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueDependencyGraph(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	issue1 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	issue2 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue)
+	issue5 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5}).(*models.Issue)
+	// #1 is blocked by the pull request #2, the closed #4 is blocked by #1
+	assert.NoError(t, models.CreateIssueDependency(user2, issue1, issue2))
+	assert.NoError(t, models.CreateIssueDependency(user2, issue5, issue1))
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	for _, url := range []string{
+		"/api/v1/repos/user2/repo1/issues/1/dependencies/graph?token=" + token,
+		"/api/v1/repos/user2/repo1/milestones/1/dependencies/graph?token=" + token,
+	} {
+		req := NewRequest(t, "GET", url)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var graph api.IssueDependencyGraph
+		DecodeJSON(t, resp, &graph)
+
+		assert.False(t, graph.Truncated)
+		assert.Len(t, graph.Nodes, 3)
+		assert.ElementsMatch(t, []*api.IssueDependencyEdge{
+			{BlockerID: 2, BlockedID: 1},
+			{BlockerID: 1, BlockedID: 5},
+		}, graph.Edges)
+		nodes := make(map[int64]*api.IssueDependencyNode)
+		for _, node := range graph.Nodes {
+			nodes[node.ID] = node
+		}
+		assert.Equal(t, 0, nodes[2].Level)
+		assert.True(t, nodes[2].IsPull)
+		assert.Equal(t, 1, nodes[1].Level)
+		assert.True(t, nodes[1].Blocked)
+		assert.Equal(t, 2, nodes[5].Level)
+		assert.False(t, nodes[5].Blocked)
+		assert.Equal(t, "user2/repo1", nodes[5].Repo.FullName)
+	}
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/9999/dependencies/graph?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1/dependencies")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 3, htmlDoc.doc.Find(".dependency-level").Length())
+	htmlDoc.AssertElement(t, "#dependency-issue-1.secondary", true)
+
+	req = NewRequest(t, "GET", "/user2/repo1/milestone/1/dependencies")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, "#dependency-issue-2.secondary", true)
+}
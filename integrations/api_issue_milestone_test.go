@@ -75,3 +75,67 @@ func TestAPIIssuesMilestone(t *testing.T) {
 	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s/milestones/%d?token=%s", owner.Name, repo.Name, apiMilestone.ID, token))
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPIMilestoneStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/milestones?token=%s", owner.Name, repo.Name, token), structs.CreateMilestoneOption{
+		Title: "burndown",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiMilestone structs.Milestone
+	DecodeJSON(t, resp, &apiMilestone)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues?token=%s", owner.Name, repo.Name, token), structs.CreateIssueOption{
+		Title:     "estimated issue",
+		Milestone: apiMilestone.ID,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue structs.Issue
+	DecodeJSON(t, resp, &apiIssue)
+
+	issueURL := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d?token=%s", owner.Name, repo.Name, apiIssue.Index, token)
+	estimate := int64(-1)
+	req = NewRequestWithJSON(t, "PATCH", issueURL, structs.EditIssueOption{TimeEstimate: &estimate})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	estimate = 3600
+	req = NewRequestWithJSON(t, "PATCH", issueURL, structs.EditIssueOption{TimeEstimate: &estimate})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 3600, apiIssue.TimeEstimate)
+
+	statsURL := fmt.Sprintf("/api/v1/repos/%s/%s/milestones/%d/stats?token=%s", owner.Name, repo.Name, apiMilestone.ID, token)
+	req = NewRequest(t, "GET", statsURL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var stats structs.MilestoneStats
+	DecodeJSON(t, resp, &stats)
+	assert.Equal(t, "day", stats.Interval)
+	if assert.NotEmpty(t, stats.Buckets) {
+		last := stats.Buckets[len(stats.Buckets)-1]
+		assert.Equal(t, 1, last.OpenIssues)
+		assert.Equal(t, 0, last.ClosedIssues)
+		assert.EqualValues(t, 3600, last.EstimatedTime)
+	}
+
+	closed := "closed"
+	req = NewRequestWithJSON(t, "PATCH", issueURL, structs.EditIssueOption{State: &closed})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", statsURL+"&interval=week")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &stats)
+	assert.Equal(t, "week", stats.Interval)
+	if assert.Len(t, stats.Buckets, 1) {
+		assert.Equal(t, 0, stats.Buckets[0].OpenIssues)
+		assert.Equal(t, 1, stats.Buckets[0].ClosedIssues)
+	}
+
+	req = NewRequest(t, "GET", statsURL+"&interval=year")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueReminders(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/user2/repo1/issues/%d/reminders?token=%s", issue.Index, token)
+
+	before := time.Now()
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueReminderOption{In: "3d"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var reminder api.IssueReminder
+	DecodeJSON(t, resp, &reminder)
+	assert.False(t, reminder.RemindAt.Before(before.Add(72*time.Hour).Truncate(time.Second)))
+	models.AssertExistsAndLoadBean(t, &models.IssueReminder{ID: reminder.ID, UserID: 2, IssueID: issue.ID})
+
+	remindAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueReminderOption{RemindAt: &remindAt})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	past := time.Now().Add(-time.Hour)
+	for _, form := range []*api.CreateIssueReminderOption{
+		{},
+		{In: "soon"},
+		{RemindAt: &past},
+		{RemindAt: &remindAt, In: "3d"},
+	} {
+		req = NewRequestWithJSON(t, "POST", urlStr, form)
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var reminders []*api.IssueReminder
+	DecodeJSON(t, resp, &reminders)
+	if assert.Len(t, reminders, 2) {
+		assert.True(t, remindAt.Equal(reminders[0].RemindAt))
+		assert.EqualValues(t, reminder.ID, reminders[1].ID)
+	}
+
+	// the reminders of other users are not visible
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/issues/%d/reminders?token=%s", issue.Index, token4)
+	resp = session4.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reminders)
+	assert.Empty(t, reminders)
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/user2/repo1/issues/%d/reminders/%d?token=%s", issue.Index, reminder.ID, token4)
+	session4.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/user2/repo1/issues/%d/reminders/%d?token=%s", issue.Index, reminder.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.IssueReminder{ID: reminder.ID})
+}
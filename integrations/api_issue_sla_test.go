@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueSLAPolicy(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := "/api/v1/orgs/user3/sla_policies"
+
+	req := NewRequestWithJSON(t, "POST", link+"?token="+token, &api.CreateIssueSLAPolicyOption{Name: "P1 bugs", Label: "orglabel3"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", link+"?token="+token, &api.CreateIssueSLAPolicyOption{
+		Name:          "P1 bugs",
+		Enabled:       true,
+		Label:         "orglabel3",
+		ResponseHours: 4,
+		BreachLabel:   "sla-breached",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var policy api.IssueSLAPolicy
+	DecodeJSON(t, resp, &policy)
+	assert.Equal(t, "P1 bugs", policy.Name)
+	assert.Equal(t, 4, policy.ResponseHours)
+	assert.EqualValues(t, 2, policy.Doer.ID)
+	policyLink := fmt.Sprintf("%s/%d", link, policy.ID)
+
+	resolutionHours := 48
+	req = NewRequestWithJSON(t, "PATCH", policyLink+"?token="+token, &api.EditIssueSLAPolicyOption{ResolutionHours: &resolutionHours})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &policy)
+	assert.Equal(t, 4, policy.ResponseHours)
+	assert.Equal(t, 48, policy.ResolutionHours)
+
+	// the members can read the policies and the reports but only the owners can change them
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	resp = session4.MakeRequest(t, NewRequest(t, "GET", link+"?token="+token4), http.StatusOK)
+	var policies []*api.IssueSLAPolicy
+	DecodeJSON(t, resp, &policies)
+	assert.Len(t, policies, 1)
+	session4.MakeRequest(t, NewRequest(t, "DELETE", policyLink+"?token="+token4), http.StatusForbidden)
+
+	resp = session4.MakeRequest(t, NewRequest(t, "GET", "/api/v1/orgs/user3/sla_report?token="+token4), http.StatusOK)
+	var reports []*api.IssueSLAReport
+	DecodeJSON(t, resp, &reports)
+	if assert.Len(t, reports, 1) {
+		assert.Equal(t, policy.ID, reports[0].Policy.ID)
+	}
+
+	session.MakeRequest(t, NewRequest(t, "DELETE", policyLink+"?token="+token), http.StatusNoContent)
+	session.MakeRequest(t, NewRequest(t, "GET", policyLink+"?token="+token), http.StatusNotFound)
+}
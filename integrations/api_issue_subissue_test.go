@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISubIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/issues/1/children?token=" + token
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, &api.CreateIssueOption{
+		Title: "open sub-issue",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var open api.Issue
+	DecodeJSON(t, resp, &open)
+
+	for _, index := range []int64{4, open.Index} {
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.AttachSubIssueOption{Index: index})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var child api.Issue
+		DecodeJSON(t, resp, &child)
+		assert.EqualValues(t, index, child.Index)
+		assert.EqualValues(t, 1, child.ParentID)
+	}
+
+	// already attached, a pull request, the parent itself and a missing issue
+	for index, status := range map[int64]int{
+		4:    http.StatusConflict,
+		2:    http.StatusUnprocessableEntity,
+		1:    http.StatusUnprocessableEntity,
+		9999: http.StatusUnprocessableEntity,
+	} {
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.AttachSubIssueOption{Index: index})
+		session.MakeRequest(t, req, status)
+	}
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var children []*api.Issue
+	DecodeJSON(t, resp, &children)
+	if assert.Len(t, children, 2) {
+		assert.EqualValues(t, 4, children[0].Index)
+		assert.EqualValues(t, open.Index, children[1].Index)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/1/children/progress?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var progress api.SubIssuesProgress
+	DecodeJSON(t, resp, &progress)
+	assert.Equal(t, api.SubIssuesProgress{Total: 2, Closed: 1, Percent: 50}, progress)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues?state=all&parent=1&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var issues []*api.Issue
+	DecodeJSON(t, resp, &issues)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues?state=all&parent=9999&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &issues)
+	assert.Len(t, issues, 0)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/user2/repo1/issues/1/children/4?token=%s", token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	session.MakeRequest(t, req, http.StatusNotFound)
+	issue5 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5}).(*models.Issue)
+	assert.EqualValues(t, 0, issue5.ParentID)
+
+	// a reader can list the sub-issues but not attach them
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/1/children?token="+token)
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/children?token="+token, &api.AttachSubIssueOption{Index: 4})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOEmbed(t *testing.T) {
+	defer prepareTestEnv(t)()
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	getOEmbed := func(session *TestSession, link string, expectedStatus int) *api.OEmbed {
+		req := NewRequest(t, "GET", "/api/v1/oembed?url="+url.QueryEscape(link))
+		resp := session.MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+		var oembed api.OEmbed
+		DecodeJSON(t, resp, &oembed)
+		return &oembed
+	}
+
+	session := emptyTestSession(t)
+	oembed := getOEmbed(session, setting.AppURL+"user2/repo1/src/commit/"+commitID+"/README.md#L3", http.StatusOK)
+	assert.Equal(t, "rich", oembed.Type)
+	assert.Equal(t, "user2/repo1/README.md", oembed.Title)
+	assert.Contains(t, oembed.HTML, "line 3 at 65f1bf27bc")
+	assert.Contains(t, oembed.HTML, "Description for repo1")
+	assert.NotContains(t, oembed.HTML, "# repo1")
+	assert.NotContains(t, oembed.HTML, "class=")
+	assert.Contains(t, oembed.HTML, `href="`+setting.AppURL+"user2/repo1/src/commit/"+commitID+`/README.md#L3-L3"`)
+
+	// the lines may also be given by the embed links
+	oembed = getOEmbed(session, "/user2/repo1/embed/commit/"+commitID+"/README.md?lines=1-2", http.StatusOK)
+	assert.Contains(t, oembed.HTML, "lines 1 to 2")
+	assert.NotContains(t, oembed.HTML, "Description for repo1")
+
+	getOEmbed(session, "/user2/repo1/src/commit/"+commitID+"/README.md#L10", http.StatusNotFound)
+	getOEmbed(session, "/user2/repo1/src/commit/"+commitID+"/missing.md", http.StatusNotFound)
+	getOEmbed(session, "/user2/repo1/src/branch/master/README.md", http.StatusNotFound)
+	getOEmbed(session, "https://example.com/user2/repo1/src/commit/"+commitID+"/README.md", http.StatusNotFound)
+
+	// the files of private repositories are only embedded for their readers
+	getOEmbed(session, "/user2/repo16/src/commit/69554a64c1e6030f051e5c3f94bfbd773cd6a324/readme.md", http.StatusNotFound)
+
+	req := NewRequest(t, "GET", "/api/v1/oembed?format=xml&url="+url.QueryEscape("/user2/repo1/src/commit/"+commitID+"/README.md"))
+	session.MakeRequest(t, req, http.StatusNotImplemented)
+}
+
+func TestEmbedFile(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/user2/repo1/embed/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md?lines=3")
+	resp := MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Empty(t, resp.Header().Get("X-Frame-Options"))
+	assert.Contains(t, resp.Header().Get("Content-Security-Policy"), "default-src 'none'")
+	assert.Contains(t, resp.Body.String(), "Description for repo1")
+
+	req = NewRequest(t, "GET", "/user2/repo1/embed/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md?lines=3-1")
+	MakeRequest(t, req, http.StatusBadRequest)
+	req = NewRequest(t, "GET", "/user2/repo1/embed/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/missing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the file view lets the oEmbed consumers discover the snippets
+	req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/README.md")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	href, exists := htmlDoc.doc.Find(`link[type="application/json+oembed"]`).Attr("href")
+	assert.True(t, exists)
+	assert.Contains(t, href, "api/v1/oembed?url=")
+}
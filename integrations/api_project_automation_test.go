@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIProjectAutomationRules(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	createRule := func(form api.CreateProjectAutomationRuleOption, expectedStatus int) *api.ProjectAutomationRule {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/projects/1/automation_rules?token="+token, &form)
+		resp := session.MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusCreated {
+			return nil
+		}
+		var rule api.ProjectAutomationRule
+		DecodeJSON(t, resp, &rule)
+		return &rule
+	}
+
+	closed := createRule(api.CreateProjectAutomationRuleOption{Event: "closed", BoardID: 3}, http.StatusCreated)
+	assert.EqualValues(t, "closed", closed.Event)
+	assert.EqualValues(t, 3, closed.BoardID)
+	createRule(api.CreateProjectAutomationRuleOption{Event: "labeled", LabelID: 2, BoardID: 2}, http.StatusCreated)
+
+	// the events, the labels and the boards are validated
+	createRule(api.CreateProjectAutomationRuleOption{Event: "commented"}, http.StatusUnprocessableEntity)
+	createRule(api.CreateProjectAutomationRuleOption{Event: "labeled", LabelID: 5}, http.StatusUnprocessableEntity)
+	createRule(api.CreateProjectAutomationRuleOption{Event: "closed", BoardID: 4}, http.StatusUnprocessableEntity)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/projects/1/automation_rules?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var rules []*api.ProjectAutomationRule
+	DecodeJSON(t, resp, &rules)
+	assert.Len(t, rules, 2)
+
+	// the project must be a project of the repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/projects/2/automation_rules?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// the cards are moved by the rules
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/labels?token="+token, &api.IssueLabelsOption{
+		Labels: []int64{2},
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: 1, ProjectBoardID: 2})
+
+	state := api.StateClosed
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/issues/1?token="+token, &api.EditIssueOption{
+		State: (*string)(&state),
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: 1, ProjectBoardID: 3})
+
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/user2/repo1/projects/1/automation_rules/%d?token=%s", closed.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.ProjectAutomationRule{ID: closed.ID})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullAutoPublish(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/draft.md?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{
+				BranchName:    "master",
+				NewBranchName: "draft",
+				Message:       "add draft",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte("draft")),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "draft",
+			Base:  "master",
+			Title: "WIP: add draft",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName:          "master",
+			EnablePush:          true,
+			EnableStatusCheck:   true,
+			StatusCheckContexts: []string{"ci/test"},
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		autoPublishURL := fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/auto_publish?token=%s", pull.Index, token)
+		req = NewRequestWithJSON(t, "POST", autoPublishURL, &api.PullAutoPublishOptions{
+			Reviewers: []string{"user4"},
+		})
+		session.MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", autoPublishURL)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var autoPublish api.PullAutoPublish
+		DecodeJSON(t, resp, &autoPublish)
+		assert.Equal(t, pull.Index, autoPublish.Index)
+		assert.Equal(t, "user2", autoPublish.EnabledBy.UserName)
+		if assert.Len(t, autoPublish.Reviewers, 1) {
+			assert.Equal(t, "user4", autoPublish.Reviewers[0].UserName)
+		}
+
+		createStatus := func(context string, state api.StatusState) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/statuses/%s?token=%s", pull.Head.Sha, token), &api.CreateStatusOption{
+				State:   state,
+				Context: context,
+			})
+			session.MakeRequest(t, req, http.StatusCreated)
+		}
+
+		// only the required status checks publish the pull request
+		createStatus("ci/other", api.StatusSuccess)
+		createStatus("ci/test", api.StatusPending)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID}).(*models.PullRequest)
+		issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: pr.IssueID}).(*models.Issue)
+		assert.Equal(t, "WIP: add draft", issue.Title)
+		models.AssertNotExistsBean(t, &models.Review{IssueID: issue.ID, ReviewerID: 4, Type: models.ReviewTypeRequest})
+
+		createStatus("ci/test", api.StatusSuccess)
+		issue = models.AssertExistsAndLoadBean(t, &models.Issue{ID: pr.IssueID}).(*models.Issue)
+		assert.Equal(t, "add draft", issue.Title)
+		models.AssertExistsAndLoadBean(t, &models.Review{IssueID: issue.ID, ReviewerID: 4, Type: models.ReviewTypeRequest})
+
+		req = NewRequest(t, "GET", autoPublishURL)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		// a published pull request can't be published automatically
+		req = NewRequestWithJSON(t, "POST", autoPublishURL, &api.PullAutoPublishOptions{})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// only the poster and the writers can change the auto publishing
+		session5 := loginUser(t, "user5")
+		token5 := getTokenForLoggedInUser(t, session5)
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/auto_publish?token=%s", pull.Index, token5))
+		session5.MakeRequest(t, req, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullCodeOwnerApproval(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: 1, Index: 3}).(*models.PullRequest)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/.gitea/CODEOWNERS?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{
+				BranchName: "master",
+				Message:    "add CODEOWNERS",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte("*.txt @user3/team1\n3 @user5\n")),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName:               "master",
+			EnablePush:               true,
+			DismissStaleApprovals:    true,
+			RequireCodeOwnerApproval: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.RequireCodeOwnerApproval)
+
+		// the approval of user4 is stale
+		files, err := pull_service.GetFilesMissingCodeOwnerApproval(pr)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3", "iso-8859-1.txt"}, files)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/merge?token=%s", pr.Index, token), &auth.MergePullRequestForm{
+			Do: string(models.MergeStyleMerge),
+		})
+		session.MakeRequest(t, req, http.StatusMethodNotAllowed)
+
+		// user4 approves for user3/team1
+		session4 := loginUser(t, "user4")
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/reviews?token=%s", pr.Index, getTokenForLoggedInUser(t, session4)), &api.CreatePullReviewOptions{
+			Event: "APPROVED",
+		})
+		session4.MakeRequest(t, req, http.StatusOK)
+
+		files, err = pull_service.GetFilesMissingCodeOwnerApproval(pr)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3"}, files)
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullDiffFiles(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		block := "moved block line one\nmoved block line two\n"
+		unchanged := "a\nb\nc\nd\ne\n"
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/code.txt?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{BranchName: "master", Message: "add code"},
+			Content:     base64.StdEncoding.EncodeToString([]byte(block + unchanged)),
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var created api.FileResponse
+		DecodeJSON(t, resp, &created)
+
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/code.txt?token="+token, &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{
+				FileOptions: api.FileOptions{BranchName: "master", NewBranchName: "move", Message: "move block"},
+				SHA:         created.Content.SHA,
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte(unchanged + block)),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "move",
+			Base:  "master",
+			Title: "move block",
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/files", pull.Index))
+		resp = MakeRequest(t, req, http.StatusOK)
+		var files []*api.DiffFile
+		DecodeJSON(t, resp, &files)
+		if !assert.Len(t, files, 1) {
+			return
+		}
+		assert.Equal(t, "code.txt", files[0].Name)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 2, files[0].Additions)
+		assert.Equal(t, 2, files[0].Deletions)
+		if !assert.Len(t, files[0].Hunks, 1) {
+			return
+		}
+		var moved int
+		for _, line := range files[0].Hunks[0].Lines {
+			// the block is moved, nothing is changed
+			assert.Equal(t, line.Type != "context", line.Moved, line.Content)
+			if line.Moved {
+				moved++
+			}
+		}
+		assert.Equal(t, 4, moved)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/files?context=-1", pull.Index))
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
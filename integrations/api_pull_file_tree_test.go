@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullFileTree(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		branch := "master"
+		for _, file := range []struct {
+			path    string
+			content string
+		}{
+			{"docs/guide/install.md", "install\n"},
+			{"docs/guide/usage.md", "usage\nmore usage\n"},
+			{"docs/index.md", "index\n"},
+		} {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+file.path+"?token="+token, &api.CreateFileOptions{
+				FileOptions: api.FileOptions{
+					BranchName:    branch,
+					NewBranchName: "docs",
+					Message:       "add " + file.path,
+				},
+				Content: base64.StdEncoding.EncodeToString([]byte(file.content)),
+			})
+			session.MakeRequest(t, req, http.StatusCreated)
+			branch = "docs"
+		}
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "docs",
+			Base:  "master",
+			Title: "add docs",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/file_tree", pull.Index))
+		resp = MakeRequest(t, req, http.StatusOK)
+		var root api.PullFileTreeNode
+		DecodeJSON(t, resp, &root)
+		assert.Equal(t, "dir", root.Type)
+		assert.Equal(t, 3, root.ChangedFiles)
+		assert.Equal(t, 4, root.Additions)
+		if !assert.Len(t, root.Children, 1) {
+			return
+		}
+		docs := root.Children[0]
+		assert.Equal(t, "docs", docs.Path)
+		assert.Equal(t, 3, docs.ChangedFiles)
+		if !assert.Len(t, docs.Children, 2) {
+			return
+		}
+		// the directories are listed first
+		guide, index := docs.Children[0], docs.Children[1]
+		assert.Equal(t, "dir", guide.Type)
+		assert.Equal(t, "docs/guide", guide.Path)
+		assert.Equal(t, 2, guide.ChangedFiles)
+		assert.Equal(t, 3, guide.Additions)
+		if assert.Len(t, guide.Children, 2) {
+			assert.Equal(t, "install.md", guide.Children[0].Name)
+			assert.Equal(t, "usage.md", guide.Children[1].Name)
+			assert.Equal(t, 2, guide.Children[1].Additions)
+		}
+		assert.Equal(t, "file", index.Type)
+		assert.Equal(t, "added", index.Status)
+		assert.Equal(t, 1, index.Additions)
+
+		// the diff of a file is loaded separately
+		diffURL, err := url.Parse(index.DiffURL)
+		assert.NoError(t, err)
+		req = NewRequest(t, "GET", diffURL.RequestURI())
+		resp = MakeRequest(t, req, http.StatusOK)
+		diff := resp.Body.String()
+		assert.Contains(t, diff, "+++ b/docs/index.md")
+		assert.False(t, strings.Contains(diff, "docs/guide"))
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d.diff?path=README.md", pull.Index))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
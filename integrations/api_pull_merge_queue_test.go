@@ -0,0 +1,90 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullMergeQueue(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo.ID, Status: models.PullRequestStatusMergeable}, models.Cond("has_merged = ?", false)).(*models.PullRequest)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// the required status check keeps the pull request in the queue
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/branch_protections?token=%s", owner.Name, repo.Name, token), &api.CreateBranchProtectionOption{
+		BranchName:          pr.BaseBranch,
+		EnableStatusCheck:   true,
+		StatusCheckContexts: []string{"ci"},
+		EnableMergeQueue:    true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var protection api.BranchProtection
+	DecodeJSON(t, resp, &protection)
+	assert.True(t, protection.EnableMergeQueue)
+
+	mergeForm := &auth.MergePullRequestForm{Do: string(models.MergeStyleMerge)}
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner.Name, repo.Name, pr.Index, token), mergeForm)
+	session.MakeRequest(t, req, http.StatusMethodNotAllowed)
+
+	// protecting the branch checks its pull requests again
+	for i := 0; i < 10; i++ {
+		pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID}).(*models.PullRequest)
+		if !pr.IsChecking() {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	assert.Equal(t, models.PullRequestStatusMergeable, pr.Status)
+
+	queueURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge_queue?token=%s", owner.Name, repo.Name, pr.Index, token)
+	req = NewRequest(t, "GET", queueURL)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "POST", queueURL, mergeForm)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var entry api.MergeQueueEntry
+	DecodeJSON(t, resp, &entry)
+	assert.Equal(t, 1, entry.Position)
+	assert.EqualValues(t, pr.Index, entry.Index)
+	assert.Equal(t, pr.BaseBranch, entry.BaseBranch)
+	assert.Equal(t, string(models.MergeStyleMerge), entry.MergeStyle)
+	assert.Equal(t, owner.Name, entry.EnqueuedBy.UserName)
+
+	req = NewRequestWithJSON(t, "POST", queueURL, mergeForm)
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/merge_queues/%s?token=%s", owner.Name, repo.Name, pr.BaseBranch, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var entries []*api.MergeQueueEntry
+	DecodeJSON(t, resp, &entries)
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, pr.Index, entries[0].Index)
+	}
+
+	req = NewRequest(t, "GET", queueURL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &entry)
+	assert.Equal(t, 1, entry.Position)
+
+	req = NewRequest(t, "DELETE", queueURL)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", queueURL)
+	session.MakeRequest(t, req, http.StatusNotFound)
+	models.AssertNotExistsBean(t, &models.MergeQueueEntry{PullID: pr.ID})
+}
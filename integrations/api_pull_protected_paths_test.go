@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullProtectedPathApproval(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		// user2 owns the organization user3
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/contents/notes.md?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{
+				BranchName:    "master",
+				NewBranchName: "notes",
+				Message:       "add notes",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte("notes")),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "notes",
+			Base:  "master",
+			Title: "add notes",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID}).(*models.PullRequest)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName: "master",
+			EnablePush: true,
+			ProtectedPathRules: []*api.ProtectedPathRule{
+				{Pattern: "*.md", Team: "nonexistent"},
+			},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName: "master",
+			EnablePush: true,
+			ProtectedPathRules: []*api.ProtectedPathRule{
+				{Pattern: "docs/**", Team: "Owners"},
+				{Pattern: "*.md", Team: "team1"},
+			},
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		if assert.Len(t, protection.ProtectedPathRules, 2) {
+			assert.Equal(t, "*.md", protection.ProtectedPathRules[1].Pattern)
+			assert.Equal(t, "team1", protection.ProtectedPathRules[1].Team)
+		}
+
+		// the pull request adds notes.md
+		unapproved, err := pull_service.GetUnapprovedProtectedPaths(pr)
+		assert.NoError(t, err)
+		if assert.Len(t, unapproved, 1) {
+			assert.Equal(t, "*.md", unapproved[0].Rule.Pattern)
+			assert.Equal(t, "team1", unapproved[0].Team.Name)
+			assert.Equal(t, []string{"notes.md"}, unapproved[0].Files)
+		}
+
+		err = pull_service.CheckPRReadyToMerge(pr, false)
+		assert.True(t, models.IsErrNotAllowedToMerge(err))
+		assert.Contains(t, err.Error(), "*.md (team team1)")
+
+		// user4 approves for team1
+		session4 := loginUser(t, "user4")
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user3/repo3/pulls/%d/reviews?token=%s", pr.Index, getTokenForLoggedInUser(t, session4)), &api.CreatePullReviewOptions{
+			Event: "APPROVED",
+		})
+		session4.MakeRequest(t, req, http.StatusOK)
+
+		unapproved, err = pull_service.GetUnapprovedProtectedPaths(pr)
+		assert.NoError(t, err)
+		assert.Empty(t, unapproved)
+		assert.NoError(t, pull_service.CheckPRReadyToMerge(pr, false))
+
+		// the rules are removed by an empty list
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/repo3/branch_protections/master?token="+token, &api.EditBranchProtectionOption{
+			ProtectedPathRules: []*api.ProtectedPathRule{},
+		})
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &protection)
+		assert.Empty(t, protection.ProtectedPathRules)
+	})
+}
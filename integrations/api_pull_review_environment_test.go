@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullReviewEnvironments(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	envsURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/environments", owner.Name, repo.Name, pr.Index)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/hooks?token=%s", owner.Name, repo.Name, token), &api.CreateHookOption{
+		Type:   "gitea",
+		Config: api.CreateHookOptionConfig{"url": "http://example.com/review_environment", "content_type": "json"},
+		Events: []string{"review_environment"},
+		Active: true,
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	// readers can't register environments
+	readerToken := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+	option := &api.CreateReviewEnvironmentOption{Name: "docs", URL: "https://docs.example.com/repo1/pulls/3"}
+	req = NewRequestWithJSON(t, "POST", envsURL+"?token="+readerToken, option)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", envsURL+"?token="+token, option)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var env api.ReviewEnvironment
+	DecodeJSON(t, resp, &env)
+	assert.Equal(t, "docs", env.Name)
+	assert.Equal(t, api.StatusState("pending"), env.State)
+	assert.Equal(t, owner.Name, env.Creator.UserName)
+
+	req = NewRequestWithJSON(t, "POST", envsURL+"?token="+token, &api.CreateReviewEnvironmentOption{Name: "docs", URL: option.URL, State: "deployed"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", envsURL+"?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var envs []*api.ReviewEnvironment
+	DecodeJSON(t, resp, &envs)
+	if assert.Len(t, envs, 2) {
+		assert.Equal(t, "docs", envs[0].Name)
+		assert.Equal(t, "preview", envs[1].Name)
+	}
+
+	// the environments are shown on the pull request
+	req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/pulls/%d", owner.Name, repo.Name, pr.Index))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 1, htmlDoc.Find(`a[href="https://docs.example.com/repo1/pulls/3"]`).Length())
+
+	req = NewRequest(t, "DELETE", envsURL+"/docs?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", envsURL+"/docs?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// closing the pull request expires its environments
+	closed := "closed"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner.Name, repo.Name, pr.Index, token), &api.EditPullRequestOption{State: &closed})
+	session.MakeRequest(t, req, http.StatusCreated)
+	req = NewRequest(t, "GET", envsURL+"?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &envs)
+	assert.Empty(t, envs)
+	req = NewRequest(t, "GET", envsURL+"?expired=true&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &envs)
+	if assert.Len(t, envs, 1) {
+		assert.Equal(t, "preview", envs[0].Name)
+		assert.True(t, envs[0].Expired)
+	}
+	models.AssertExistsAndLoadBean(t, &models.HookTask{RepoID: repo.ID, EventType: models.HookEventReviewEnvironment})
+
+	req = NewRequestWithJSON(t, "POST", envsURL+"?token="+token, option)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -5,8 +5,10 @@
 package integrations
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -229,3 +231,135 @@ func TestAPIPullReviewRequest(t *testing.T) {
 	req = NewRequestWithJSON(t, http.MethodDelete, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo3.OwnerName, repo3.Name, pullIssue12.Index, token), &api.PullReviewRequestOptions{})
 	session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPIPullReviewSuggestions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, http.MethodPost, "/api/v1/repos/user2/repo1/contents/suggestions.txt?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{
+				BranchName:    "master",
+				NewBranchName: "suggestions",
+				Message:       "add suggestions.txt",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte("line1\nline2\nline3\n")),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithJSON(t, http.MethodPost, "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "suggestions",
+			Base:  "master",
+			Title: "suggestions",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+
+		req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/reviews?token=%s", pull.Index, token), &api.CreatePullReviewOptions{
+			Event: "COMMENT",
+			Body:  "some suggestions",
+			Comments: []api.CreatePullReviewComment{{
+				Path:       "suggestions.txt",
+				Body:       "```suggestion\nLINE1\n```",
+				NewLineNum: 1,
+			}, {
+				Path:       "suggestions.txt",
+				Body:       "Split it:\n```suggestion\nline3a\nline3b\n```",
+				NewLineNum: 3,
+			}, {
+				Path:       "suggestions.txt",
+				Body:       "no suggestion",
+				NewLineNum: 2,
+			}},
+		})
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var review api.PullReview
+		DecodeJSON(t, resp, &review)
+
+		req = NewRequestf(t, http.MethodGet, "/api/v1/repos/user2/repo1/pulls/%d/reviews/%d/comments?token=%s", pull.Index, review.ID, token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var comments []*api.PullReviewComment
+		DecodeJSON(t, resp, &comments)
+		assert.Len(t, comments, 3)
+		commentIDs := make(map[string]int64, len(comments))
+		for _, comment := range comments {
+			commentIDs[comment.Body] = comment.ID
+		}
+
+		// all the comments must have a suggestion
+		suggestionsURL := fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/suggestions?token=%s", pull.Index, token)
+		req = NewRequestWithJSON(t, http.MethodPost, suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{
+			CommentIDs: []int64{commentIDs["```suggestion\nLINE1\n```"], commentIDs["no suggestion"]},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, http.MethodPost, suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{
+			CommentIDs: []int64{commentIDs["```suggestion\nLINE1\n```"], commentIDs["Split it:\n```suggestion\nline3a\nline3b\n```"]},
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var commit api.Commit
+		DecodeJSON(t, resp, &commit)
+		assert.Equal(t, "Apply 2 suggestions from code review\n", commit.RepoCommit.Message)
+
+		req = NewRequestf(t, http.MethodGet, "/api/v1/repos/user2/repo1/raw/suggestions/suggestions.txt?token=%s", token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "LINE1\nline2\nline3a\nline3b\n", resp.Body.String())
+
+		// the applied suggestions are resolved
+		for _, id := range []int64{commentIDs["```suggestion\nLINE1\n```"], commentIDs["Split it:\n```suggestion\nline3a\nline3b\n```"]} {
+			comment := models.AssertExistsAndLoadBean(t, &models.Comment{ID: id}).(*models.Comment)
+			assert.EqualValues(t, 2, comment.ResolveDoerID)
+		}
+	})
+}
+
+func TestAPIPullReviewChecklist(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	checklist := []string{"Tests are added", "Documentation is updated"}
+	hasPullRequests := true
+	req := NewRequestWithJSON(t, http.MethodPatch, "/api/v1/repos/user2/repo1?token="+token, &api.EditRepoOption{
+		HasPullRequests: &hasPullRequests,
+		ReviewChecklist: &checklist,
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.EqualValues(t, checklist, repo.ReviewChecklist)
+
+	reviewsURL := "/api/v1/repos/user2/repo1/pulls/3/reviews?token=" + token
+
+	// an approval has to acknowledge all items
+	req = NewRequestWithJSON(t, http.MethodPost, reviewsURL, &api.CreatePullReviewOptions{
+		Event:     api.ReviewStateApproved,
+		Checklist: checklist[:1],
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// other reviews don't
+	req = NewRequestWithJSON(t, http.MethodPost, reviewsURL, &api.CreatePullReviewOptions{
+		Event:     api.ReviewStateComment,
+		Body:      "not yet",
+		Checklist: []string{checklist[1], "unknown"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var review api.PullReview
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, checklist[1:], review.Checklist)
+
+	req = NewRequestWithJSON(t, http.MethodPost, reviewsURL, &api.CreatePullReviewOptions{
+		Event:     api.ReviewStateApproved,
+		Checklist: checklist,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, api.ReviewStateApproved, review.State)
+
+	req = NewRequestf(t, http.MethodGet, "/api/v1/repos/user2/repo1/pulls/3/reviews/%d?token=%s", review.ID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, checklist, review.Checklist)
+}
@@ -108,3 +108,84 @@ func TestAPIEditPull(t *testing.T) {
 	})
 	session.MakeRequest(t, req, 404)
 }
+
+func TestAPIStackedPull(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo10 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 10}).(*models.Repository)
+	owner10 := models.AssertExistsAndLoadBean(t, &models.User{ID: repo10.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner10.Name)
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls?token=%s", owner10.Name, repo10.Name, token), &api.CreatePullRequestOption{
+		Head:  "feature/1",
+		Base:  "master",
+		Title: "base pr",
+	})
+	basePull := new(api.PullRequest)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, basePull)
+	assert.EqualValues(t, 0, basePull.BasePull)
+	assert.Empty(t, basePull.StackedPulls)
+
+	// the base must be the head branch of the base pull request
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls?token=%s", owner10.Name, repo10.Name, token), &api.CreatePullRequestOption{
+		Head:     "develop",
+		Base:     "master",
+		Title:    "stacked pr",
+		BasePull: basePull.Index,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls?token=%s", owner10.Name, repo10.Name, token), &api.CreatePullRequestOption{
+		Head:     "develop",
+		Base:     "feature/1",
+		Title:    "stacked pr",
+		BasePull: basePull.Index,
+	})
+	pull := new(api.PullRequest)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.EqualValues(t, basePull.Index, pull.BasePull)
+
+	req = NewRequestf(t, http.MethodGet, "/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, basePull.Index, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, basePull)
+	assert.EqualValues(t, []int64{pull.Index}, basePull.StackedPulls)
+
+	unstack := int64(0)
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		BasePull: &unstack,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.EqualValues(t, 0, pull.BasePull)
+	assert.EqualValues(t, "feature/1", pull.Base.Name)
+
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		BasePull: &basePull.Index,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.EqualValues(t, basePull.Index, pull.BasePull)
+
+	// the stack is shown in the sidebar of the pull requests
+	req = NewRequestf(t, http.MethodGet, "/%s/%s/pulls/%d", owner10.Name, repo10.Name, basePull.Index)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Contains(t, htmlDoc.doc.Find(".pull-stack").Text(), fmt.Sprintf("#%d", pull.Index))
+
+	// a pull request can't be stacked on the pull requests stacked on it
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, basePull.Index, token), &api.EditPullRequestOption{
+		BasePull: &pull.Index,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// changing the base branch unstacks the pull request
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Base: "master",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.EqualValues(t, 0, pull.BasePull)
+	assert.EqualValues(t, "master", pull.Base.Name)
+}
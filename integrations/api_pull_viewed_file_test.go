@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullViewedFiles(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/pulls/3/viewed_files?token=" + token
+
+	req := NewRequest(t, "GET", urlStr)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var files []*api.PullViewedFile
+	DecodeJSON(t, resp, &files)
+	assert.Empty(t, files)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.UpdatePullViewedFilesOption{
+		Viewed: []string{"iso-8859-1.txt", "3"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "3", files[0].Path)
+		assert.Equal(t, "iso-8859-1.txt", files[1].Path)
+		assert.False(t, files[1].HasChanged)
+	}
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.UpdatePullViewedFilesOption{
+		Unviewed: []string{"iso-8859-1.txt"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "3", files[0].Path)
+	}
+
+	// the marks are per user
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/viewed_files?token="+getTokenForLoggedInUser(t, loginUser(t, "user1")))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	assert.Empty(t, files)
+
+	// the files page shows the viewed files folded
+	req = NewRequest(t, "GET", "/user2/repo1/pulls/3/files")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, `.viewed-file-checkbox input[data-path="3"][checked]`, true)
+	htmlDoc.AssertElement(t, `.diff-file-box[data-folded="true"]`, true)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/pulls/3/files/viewed", map[string]string{
+		"_csrf":  htmlDoc.GetCSRF(),
+		"path":   "3",
+		"viewed": "false",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	assert.Empty(t, files)
+}
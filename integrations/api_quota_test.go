@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIStorageQuota(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(enabled bool, defaultMaxSize int64) {
+		setting.Quota.Enabled = enabled
+		setting.Quota.DefaultMaxSize = defaultMaxSize
+	}(setting.Quota.Enabled, setting.Quota.DefaultMaxSize)
+	setting.Quota.Enabled = true
+	setting.Quota.DefaultMaxSize = -1
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.UpdateSize(models.DefaultDBContext()))
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// the attachments can be uploaded while the quota isn't exceeded
+	createAttachment(t, session, "user2/repo1", "image.png", generateImg(), http.StatusOK)
+
+	var quota api.StorageQuota
+	req := NewRequest(t, "GET", "/api/v1/user/quota?token="+token)
+	DecodeJSON(t, session.MakeRequest(t, req, http.StatusOK), &quota)
+	assert.EqualValues(t, -1, quota.Limit)
+	assert.True(t, quota.Git > 0)
+	assert.EqualValues(t, quota.Git+quota.LFS+quota.Attachments, quota.Used)
+
+	// an admin limits the storage of user2
+	adminSession := loginUser(t, "user1")
+	adminToken := getTokenForLoggedInUser(t, adminSession)
+	maxStorageSize := quota.Used
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/users/user2?token="+adminToken, &api.EditUserOption{
+		LoginName:      "user2",
+		MaxStorageSize: &maxStorageSize,
+	})
+	adminSession.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.User{ID: 2, MaxStorageSize: maxStorageSize})
+
+	req = NewRequest(t, "GET", "/api/v1/user/quota?token="+token)
+	DecodeJSON(t, session.MakeRequest(t, req, http.StatusOK), &quota)
+	assert.EqualValues(t, maxStorageSize, quota.Limit)
+
+	createAttachment(t, session, "user2/repo1", "image.png", generateImg(), http.StatusRequestEntityTooLarge)
+
+	// the quotas of the organizations are read by their owners
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/quota?token="+token)
+	DecodeJSON(t, session.MakeRequest(t, req, http.StatusOK), &quota)
+	assert.EqualValues(t, -1, quota.Limit)
+
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/quota?token="+token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
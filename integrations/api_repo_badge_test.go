@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoBadges(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := "/api/v1/repos/user2/repo1/badges"
+
+	// the badges are listed in their order
+	resp := MakeRequest(t, NewRequest(t, "GET", link), http.StatusOK)
+	var badges []*api.RepoBadge
+	DecodeJSON(t, resp, &badges)
+	if assert.Len(t, badges, 2) {
+		assert.Equal(t, "build", badges[0].Name)
+		assert.Equal(t, "docs", badges[1].Name)
+	}
+
+	req := NewRequestWithJSON(t, "POST", link+"?token="+token, &api.CreateRepoBadgeOption{Name: "coverage", LinkURL: "javascript:alert(1)"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "POST", link+"?token="+token, &api.CreateRepoBadgeOption{
+		Name:     "coverage",
+		LinkURL:  "https://coverage.example.com/user2/repo1",
+		ImageURL: "https://coverage.example.com/user2/repo1/badge.svg",
+		Sort:     3,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var badge api.RepoBadge
+	DecodeJSON(t, resp, &badge)
+	assert.Equal(t, "coverage", badge.Name)
+
+	sort := 0
+	badgeLink := fmt.Sprintf("%s/%d?token=%s", link, badge.ID, token)
+	req = NewRequestWithJSON(t, "PATCH", badgeLink, &api.EditRepoBadgeOption{Sort: &sort})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &badge)
+	assert.Equal(t, 0, badge.Sort)
+	assert.Equal(t, "https://coverage.example.com/user2/repo1", badge.LinkURL)
+	invalid := "ftp://coverage.example.com"
+	req = NewRequestWithJSON(t, "PATCH", badgeLink, &api.EditRepoBadgeOption{ImageURL: &invalid})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only the admins of the repository can change the badges
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", link, badge.ID, token4))
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	// the badges are shown on the home of the repository
+	resp = MakeRequest(t, NewRequest(t, "GET", "/user2/repo1"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 3, htmlDoc.Find("#repo-badges a.repo-badge").Length())
+	src, _ := htmlDoc.Find("#repo-badges a.repo-badge img").First().Attr("src")
+	assert.Equal(t, "https://coverage.example.com/user2/repo1/badge.svg", src)
+
+	session.MakeRequest(t, NewRequest(t, "DELETE", badgeLink), http.StatusNoContent)
+	session.MakeRequest(t, NewRequest(t, "DELETE", badgeLink), http.StatusNotFound)
+	models.AssertNotExistsBean(t, &models.RepoBadge{ID: badge.ID})
+}
+
+func TestRepoSettingsBadges(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	link := "/user2/repo1/settings/badges"
+	csrf := GetCSRF(t, session, link)
+	req := NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":    csrf,
+		"name":     "docs",
+		"link_url": "https://docs.example.com/user2/repo1/latest",
+		"sort":     "5",
+		"id":       "1",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	badge := models.AssertExistsAndLoadBean(t, &models.RepoBadge{ID: 1}).(*models.RepoBadge)
+	assert.Equal(t, "https://docs.example.com/user2/repo1/latest", badge.LinkURL)
+	assert.Equal(t, 5, badge.Sort)
+
+	resp := session.MakeRequest(t, NewRequest(t, "GET", link+"?edit=1"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "https://docs.example.com/user2/repo1/latest", htmlDoc.GetInputValueByName("link_url"))
+
+	req = NewRequestWithValues(t, "POST", link+"/delete", map[string]string{
+		"_csrf": csrf,
+		"id":    "1",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertNotExistsBean(t, &models.RepoBadge{ID: 1})
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetBlame(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		readmeCommitSHA := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+		session := emptyTestSession(t)
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/blame/README.md", user2.Name, repo1.Name)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var blame api.FileBlameResponse
+		DecodeJSON(t, resp, &blame)
+		assert.Equal(t, "README.md", blame.Path)
+		assert.Equal(t, readmeCommitSHA, blame.SHA)
+		assert.False(t, blame.IgnoresRevs)
+		assert.Equal(t, git.BlameAgeBuckets, blame.AgeBuckets)
+		if assert.Len(t, blame.Hunks, 1) {
+			assert.Equal(t, readmeCommitSHA, blame.Hunks[0].SHA)
+			assert.Equal(t, 1, blame.Hunks[0].StartLine)
+			assert.Equal(t, blame.Hunks[0].LineCount, len(blame.Hunks[0].Lines))
+			// the only commit of the blame is the most recent one
+			assert.EqualValues(t, 1, blame.Hunks[0].AgeScore)
+			assert.Equal(t, git.BlameAgeBuckets-1, blame.Hunks[0].AgeBucket)
+		}
+		if assert.Len(t, blame.Commits, 1) {
+			assert.Equal(t, readmeCommitSHA, blame.Commits[0].SHA)
+		}
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/blame/does-not-exist", user2.Name, repo1.Name)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		// list the README commit in the .git-blame-ignore-revs file of the default branch
+		_, err := repofiles.CreateOrUpdateRepoFile(repo1, user2, &repofiles.UpdateRepoFileOptions{
+			OldBranch: repo1.DefaultBranch,
+			TreePath:  git.BlameIgnoreRevsFileName,
+			Content:   "# initial commit\n" + readmeCommitSHA + "\n",
+			IsNewFile: true,
+		})
+		assert.NoError(t, err)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/blame/README.md", user2.Name, repo1.Name)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &blame)
+		assert.True(t, blame.IgnoresRevs)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/blame/README.md?bypass_blame_ignore=true", user2.Name, repo1.Name)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &blame)
+		assert.False(t, blame.IgnoresRevs)
+
+		// the web view lets users bypass the ignored revisions
+		req = NewRequestf(t, "GET", "/%s/%s/blame/branch/%s/README.md", user2.Name, repo1.Name, repo1.DefaultBranch)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "bypass-blame-ignore=true")
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCompare(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// same repository
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...branch2?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var compare api.Compare
+	DecodeJSON(t, resp, &compare)
+	assert.EqualValues(t, 1, compare.BaseRepo.ID)
+	assert.EqualValues(t, 1, compare.HeadRepo.ID)
+	assert.Len(t, compare.Commits, 2)
+	assert.Equal(t, 2, compare.TotalCommits)
+	assert.False(t, compare.Truncated)
+
+	// fork of the repository owned by a user
+	req = NewRequestf(t, "GET", "/api/v1/repos/user12/repo10/compare/master...user13:branch2?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	compare = api.Compare{}
+	DecodeJSON(t, resp, &compare)
+	assert.EqualValues(t, 10, compare.BaseRepo.ID)
+	assert.EqualValues(t, 11, compare.HeadRepo.ID)
+	assert.NotEmpty(t, compare.MergeBase)
+
+	// the user has no fork of the repository
+	req = NewRequestf(t, "GET", "/api/v1/repos/user12/repo10/compare/master...user4:master?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// reference which doesn't exist
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...branch-not-exist?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// unrelated repositories
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...user12/repo10:master?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	compare = api.Compare{}
+	DecodeJSON(t, resp, &compare)
+	assert.EqualValues(t, 10, compare.HeadRepo.ID)
+
+	// commits are limited
+	defer func(maxCommits int) {
+		setting.Repository.Compare.MaxCommits = maxCommits
+	}(setting.Repository.Compare.MaxCommits)
+	setting.Repository.Compare.MaxCommits = 1
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...branch2?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	compare = api.Compare{}
+	DecodeJSON(t, resp, &compare)
+	assert.Len(t, compare.Commits, 1)
+	assert.Equal(t, 2, compare.TotalCommits)
+	assert.True(t, compare.Truncated)
+
+	defer func(allow bool) {
+		setting.Repository.Compare.AllowUnrelatedRepos = allow
+	}(setting.Repository.Compare.AllowUnrelatedRepos)
+	setting.Repository.Compare.AllowUnrelatedRepos = false
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...user12/repo10:master?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIRepoCompareDiffOptions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/README.md?token="+token, &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{
+				FileOptions: api.FileOptions{
+					BranchName:    "master",
+					NewBranchName: "spaces",
+					Message:       "double spaces",
+				},
+				SHA: "4b4851ad51df6a7d9f25c979345979eaeb5b349f",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte("# repo1\n\nDescription  for repo1")),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		getDiff := func(query string, expectedStatus int) string {
+			req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...spaces.diff?token=%s&%s", token, query)
+			return session.MakeRequest(t, req, expectedStatus).Body.String()
+		}
+		diff := getDiff("", http.StatusOK)
+		assert.Contains(t, diff, "-Description for repo1")
+		assert.Contains(t, diff, " # repo1")
+		assert.NotContains(t, getDiff("whitespace=ignore-change", http.StatusOK), "-Description for repo1")
+		assert.NotContains(t, getDiff("context=1", http.StatusOK), " # repo1")
+		getDiff("whitespace=-w", http.StatusUnprocessableEntity)
+		getDiff("context=-1", http.StatusUnprocessableEntity)
+
+		// the choices of the diff views of pull requests are remembered
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "spaces",
+			Base:  "master",
+			Title: "double spaces",
+		})
+		var pull api.PullRequest
+		DecodeJSON(t, session.MakeRequest(t, req, http.StatusCreated), &pull)
+		req = NewRequestf(t, "GET", "/user2/repo1/pulls/%d/files?whitespace=ignore-all&context=10", pull.Index)
+		session.MakeRequest(t, req, http.StatusOK)
+		user := models.AssertExistsAndLoadBean(t, &models.User{Name: "user2"}).(*models.User)
+		assert.Equal(t, "ignore-all", user.DiffWhitespaceBehavior)
+		assert.Equal(t, 10, user.DiffContextLines)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/pulls/%d.diff?token=%s", pull.Index, token)
+		assert.NotContains(t, session.MakeRequest(t, req, http.StatusOK).Body.String(), "-Description for repo1")
+		req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/pulls/%d.diff?whitespace=", pull.Index)
+		assert.Contains(t, MakeRequest(t, req, http.StatusOK).Body.String(), "-Description for repo1")
+
+		req = NewRequestf(t, "GET", "/user2/repo1/pulls/%d/files?whitespace=", pull.Index)
+		session.MakeRequest(t, req, http.StatusOK)
+		user = models.AssertExistsAndLoadBean(t, &models.User{Name: "user2"}).(*models.User)
+		assert.Empty(t, user.DiffWhitespaceBehavior)
+		assert.Equal(t, 10, user.DiffContextLines)
+	})
+}
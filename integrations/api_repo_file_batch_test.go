@@ -0,0 +1,122 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIChangeFiles(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		updated, err := createFile(user2, repo1, "batch/update.txt")
+		assert.NoError(t, err)
+		deleted, err := createFile(user2, repo1, "batch/delete.txt")
+		assert.NoError(t, err)
+		moved, err := createFile(user2, repo1, "batch/move.txt")
+		assert.NoError(t, err)
+
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session)
+		batchURL := fmt.Sprintf("/api/v1/repos/%s/%s/contents:batch?token=%s", user2.Name, repo1.Name, token)
+		content := base64.StdEncoding.EncodeToString([]byte("batch content"))
+
+		// a file must be read before it's updated or deleted
+		req := NewRequestWithJSON(t, "POST", batchURL, &api.ChangeFilesOptions{
+			Files: []*api.ChangeFileOperation{
+				{Operation: "delete", Path: "batch/delete.txt"},
+			},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "POST", batchURL, &api.ChangeFilesOptions{
+			Files: []*api.ChangeFileOperation{
+				{Operation: "delete", Path: "batch/delete.txt", SHA: "12345678901234567890123456789012345678ab"},
+			},
+		})
+		session.MakeRequest(t, req, http.StatusConflict)
+
+		// a file can only be changed once
+		req = NewRequestWithJSON(t, "POST", batchURL, &api.ChangeFilesOptions{
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "batch/new.txt", Content: content},
+				{Operation: "update", Path: "batch/new.txt", Content: content, FromPath: "batch/move.txt", SHA: moved.Content.SHA},
+			},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// the changes are committed in a single commit
+		req = NewRequestWithJSON(t, "POST", batchURL, &api.ChangeFilesOptions{
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "batch/new.txt", Content: content},
+				{Operation: "update", Path: "batch/update.txt", Content: content, SHA: updated.Content.SHA},
+				{Operation: "delete", Path: "batch/delete.txt", SHA: deleted.Content.SHA},
+				{Operation: "update", Path: "batch/moved.txt", Content: content, FromPath: "batch/move.txt", SHA: moved.Content.SHA},
+			},
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var filesResponse api.FilesResponse
+		DecodeJSON(t, resp, &filesResponse)
+		assert.Equal(t, "Update 4 files\n", filesResponse.Commit.Message)
+		if assert.Len(t, filesResponse.Files, 4) {
+			assert.Equal(t, "batch/new.txt", filesResponse.Files[0].Path)
+			assert.Equal(t, content, *filesResponse.Files[0].Content)
+			assert.Equal(t, "batch/update.txt", filesResponse.Files[1].Path)
+			assert.Equal(t, content, *filesResponse.Files[1].Content)
+			assert.Nil(t, filesResponse.Files[2])
+			assert.Equal(t, "batch/moved.txt", filesResponse.Files[3].Path)
+		}
+		assert.Len(t, filesResponse.Commit.Parents, 1)
+
+		gitRepo, err := git.OpenRepository(repo1.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		commit, err := gitRepo.GetBranchCommit(repo1.DefaultBranch)
+		assert.NoError(t, err)
+		assert.Equal(t, filesResponse.Commit.SHA, commit.ID.String())
+		for _, treePath := range []string{"batch/delete.txt", "batch/move.txt"} {
+			_, err = commit.GetTreeEntryByPath(treePath)
+			assert.Error(t, err, treePath)
+		}
+
+		// the changes can be committed to a new branch
+		req = NewRequestWithJSON(t, "POST", batchURL, &api.ChangeFilesOptions{
+			FileOptions: api.FileOptions{
+				NewBranchName: "batch-branch",
+				Message:       "Batch to a new branch",
+			},
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "batch/other.txt", Content: content},
+			},
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		DecodeJSON(t, resp, &filesResponse)
+		assert.Equal(t, "Batch to a new branch\n", filesResponse.Commit.Message)
+		_, err = gitRepo.GetBranchCommit("batch-branch")
+		assert.NoError(t, err)
+
+		// the user must be able to write the code
+		session = loginUser(t, user4.Name)
+		token4 := getTokenForLoggedInUser(t, session)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/contents:batch?token=%s", user2.Name, repo1.Name, token4), &api.ChangeFilesOptions{
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "batch/forbidden.txt", Content: content},
+			},
+		})
+		session.MakeRequest(t, req, http.StatusForbidden)
+	})
+}
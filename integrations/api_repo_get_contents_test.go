@@ -154,3 +154,41 @@ func testAPIGetContents(t *testing.T, u *url.URL) {
 	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/contents/%s?token=%s", user3.Name, repo3.Name, treePath, token2)
 	session.MakeRequest(t, req, http.StatusOK)
 }
+
+func TestAPIGetContentsLines(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?lines=2-3")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var contentsResponse api.ContentsResponse
+	DecodeJSON(t, resp, &contentsResponse)
+	assert.Equal(t, "README.md", contentsResponse.Path)
+	assert.Nil(t, contentsResponse.Content)
+	if assert.NotNil(t, contentsResponse.Lines) {
+		assert.Equal(t, 2, contentsResponse.Lines.Start)
+		assert.Equal(t, 3, contentsResponse.Lines.End)
+		assert.Equal(t, 3, contentsResponse.Lines.Total)
+		assert.Equal(t, "\nDescription for repo1", contentsResponse.Lines.Content)
+		assert.Empty(t, contentsResponse.Lines.Highlighted)
+	}
+
+	// the end of the range is limited to the end of the file
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?lines=L1-L10&highlighted=true")
+	resp = MakeRequest(t, req, http.StatusOK)
+	contentsResponse = api.ContentsResponse{}
+	DecodeJSON(t, resp, &contentsResponse)
+	if assert.NotNil(t, contentsResponse.Lines) {
+		assert.Equal(t, 3, contentsResponse.Lines.End)
+		assert.Equal(t, "markdown", contentsResponse.Lines.Lexer)
+		if assert.Len(t, contentsResponse.Lines.Highlighted, 3) {
+			assert.Contains(t, contentsResponse.Lines.Highlighted[0], "repo1")
+		}
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?lines=4")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?lines=3-2")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/missing.md?lines=1")
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -6,9 +6,11 @@ package integrations
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/repofiles"
 	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -74,3 +76,36 @@ func TestAPIReposGitBlobs(t *testing.T) {
 	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/blobs/d56a3073c1dbb7b15963110a049d50cdb5db99fc?access=%s", user3.Name, repo3.Name, token4)
 	session.MakeRequest(t, req, http.StatusNotFound)
 }
+
+func TestAPIReposGitBlobFoldRegions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		file, err := repofiles.CreateOrUpdateRepoFile(repo1, user2, &repofiles.UpdateRepoFileOptions{
+			OldBranch: repo1.DefaultBranch,
+			TreePath:  "fold.go",
+			Content:   "package fold\n\nfunc a() {\n\tif b {\n\t\tc()\n\t}\n}\n",
+			IsNewFile: true,
+		})
+		assert.NoError(t, err)
+
+		session := emptyTestSession(t)
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/blobs/%s", user2.Name, repo1.Name, file.Content.SHA)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var gitBlobResponse api.GitBlobResponse
+		DecodeJSON(t, resp, &gitBlobResponse)
+		assert.Empty(t, gitBlobResponse.FoldRegions)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/blobs/%s?fold_regions=true", user2.Name, repo1.Name, file.Content.SHA)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &gitBlobResponse)
+		assert.EqualValues(t, []*api.FoldRegion{{Start: 3, End: 6}, {Start: 4, End: 5}}, gitBlobResponse.FoldRegions)
+
+		req = NewRequestf(t, "GET", "/%s/%s/src/branch/%s/fold.go", user2.Name, repo1.Name, repo1.DefaultBranch)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		foldEnd, _ := doc.doc.Find(`.code-view tr[data-line="3"]`).Attr("data-fold-end")
+		assert.Equal(t, "6", foldEnd)
+		assert.Equal(t, 2, doc.doc.Find(".code-view .fold-toggle").Length())
+	})
+}
@@ -96,3 +96,55 @@ func TestAPIReposGitCommitListDifferentBranch(t *testing.T) {
 	assert.Equal(t, 1, len(apiData))
 	assert.Equal(t, "f27c2b2b03dcab38beaf89b0ab4ff61f6de63441", apiData[0].CommitMeta.SHA)
 }
+
+func TestAPIReposGitCommitGraph(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	// Login as User2.
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// Test getting the graph of all branches
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/git/graph?token="+token, user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "4", resp.Header().Get("X-Total-Count"))
+
+	var apiData api.CommitGraph
+	DecodeJSON(t, resp, &apiData)
+	assert.Len(t, apiData.Commits, 4)
+	assert.Equal(t, 1, apiData.Width)
+	assert.Len(t, apiData.Flows, 1)
+	for _, commit := range apiData.Commits {
+		assert.Equal(t, 0, commit.Column)
+		assert.Equal(t, apiData.Flows[0].ID, commit.FlowID)
+	}
+
+	// Test getting the graph of a branch
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/git/graph?token="+token+"&branch=master", user.Name)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiData = api.CommitGraph{}
+	DecodeJSON(t, resp, &apiData)
+	assert.Len(t, apiData.Commits, 3)
+	assert.Equal(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", apiData.Commits[0].SHA)
+	assert.Equal(t, []string{"27566bd5738fc8b4e3fef3c5e72cce608537bd95"}, apiData.Commits[0].Parents)
+	assert.Equal(t, "user2@example.com", apiData.Commits[0].Author.Email)
+
+	// Test filtering by author and paging
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/git/graph?token="+token+"&author=user21@", user.Name)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiData = api.CommitGraph{}
+	DecodeJSON(t, resp, &apiData)
+	assert.Len(t, apiData.Commits, 1)
+	assert.Equal(t, "27566bd5738fc8b4e3fef3c5e72cce608537bd95", apiData.Commits[0].SHA)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/git/graph?token="+token+"&branch=master&limit=2&page=2", user.Name)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiData = api.CommitGraph{}
+	DecodeJSON(t, resp, &apiData)
+	assert.Len(t, apiData.Commits, 1)
+	assert.Equal(t, "5099b81332712fe655e34e8dd63574f503f61811", apiData.Commits[0].SHA)
+	assert.Equal(t, 0, apiData.Commits[0].Row)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/git/graph?token="+token+"&branch=branch-not-exist", user.Name)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoHookPayloadVersion(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/hooks?token=" + token
+
+	// the new hooks use the latest version
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateHookOption{
+		Type:   "gitea",
+		Config: api.CreateHookOptionConfig{"url": "http://www.example.com/latest", "content_type": "json"},
+		Active: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var hook api.Hook
+	DecodeJSON(t, resp, &hook)
+	assert.EqualValues(t, api.WebhookPayloadVersion, hook.PayloadVersion)
+
+	// a hook may be pinned to a previous version
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateHookOption{
+		Type:           "gitea",
+		Config:         api.CreateHookOptionConfig{"url": "http://www.example.com/compat", "content_type": "json"},
+		Active:         true,
+		PayloadVersion: 1,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &hook)
+	assert.EqualValues(t, 1, hook.PayloadVersion)
+	models.AssertExistsAndLoadBean(t, &models.Webhook{ID: hook.ID, PayloadVersion: 1})
+
+	version := api.WebhookPayloadVersion
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/repo1/hooks/%d?token=%s", hook.ID, token), &api.EditHookOption{
+		PayloadVersion: &version,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &hook)
+	assert.EqualValues(t, api.WebhookPayloadVersion, hook.PayloadVersion)
+
+	// the unknown versions are rejected
+	version = api.WebhookPayloadVersion + 1
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/repo1/hooks/%d?token=%s", hook.ID, token), &api.EditHookOption{
+		PayloadVersion: &version,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateHookOption{
+		Type:           "gitea",
+		Config:         api.CreateHookOptionConfig{"url": "http://www.example.com/unknown", "content_type": "json"},
+		PayloadVersion: -1,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	_ "code.gitea.io/gitea/modules/markup/orgmode"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoRenderMarkup(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/markup?token=" + token
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.MarkupOption{
+		Text:     "[other](other.md) fixes #1\n\n<script>alert(1)</script>",
+		FilePath: "docs/README.md",
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	body := resp.Body.String()
+	assert.Contains(t, body, `href="/user2/repo1/src/branch/master/docs/other.md"`)
+	assert.Contains(t, body, `href="http://localhost:3003/user2/repo1/issues/1"`)
+	assert.NotContains(t, body, "<script>")
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MarkupOption{
+		Text:     "[[file:other.org][other]]",
+		FilePath: "notes.org",
+		Ref:      "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), `/user2/repo1/src/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/other.org`)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MarkupOption{Text: "text", FilePath: "notes.txt"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MarkupOption{Text: "text"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MarkupOption{Text: "text", FilePath: "README.md", Ref: "no-such-ref"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISearchCode(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo, err := models.GetRepositoryByOwnerAndName("user2", "repo1")
+	assert.NoError(t, err)
+	executeIndexer(t, repo, code_indexer.UpdateRepoIndexer)
+
+	testAPISearchCode := func(t *testing.T) {
+		for query, expected := range map[string]int64{
+			"q=description":                     1,
+			"q=description&case_sensitive=true": 0,
+			"q=Description&case_sensitive=true": 1,
+			"q=Desc&whole_word=true":            0,
+			`q=Desc\w%2Bfor&regex=true`:         0,
+			`q=Desc\w%2B\s%2Bfor&regex=true`:    1,
+		} {
+			req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/search/code?%s", query)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var results api.CodeSearchResults
+			DecodeJSON(t, resp, &results)
+			assert.EqualValues(t, expected, results.TotalCount, query)
+			if expected > 0 && assert.Len(t, results.Items, 1, query) {
+				assert.Equal(t, "README.md", results.Items[0].Path)
+				assert.NotEmpty(t, results.Items[0].Lines)
+			}
+		}
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search/code?q=Desc(&regex=true")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+
+	t.Run("Indexer", testAPISearchCode)
+
+	defer func(enabled bool) {
+		setting.Indexer.RepoIndexerEnabled = enabled
+	}(setting.Indexer.RepoIndexerEnabled)
+	setting.Indexer.RepoIndexerEnabled = false
+	t.Run("GitGrep", testAPISearchCode)
+
+	// the web search falls back to git grep as well
+	testSearch(t, "/user2/repo1/search?q=Description&case_sensitive=true", []string{"README.md"})
+	testSearch(t, "/user2/repo1/search?q=description&case_sensitive=true", []string{})
+}
+
+func TestAPISearchCodeQualifiers(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		for treePath, content := range map[string]string{
+			"lib/greet.go": "package lib\n\n// Greet greets\nfunc Greet(name string) string {\n\treturn \"Hello \" + name\n}\n",
+			"main.go":      "package main\n\nimport \"lib\"\n\nfunc main() {\n\tlib.Greet(\"world\")\n}\n",
+		} {
+			_, err := repofiles.CreateOrUpdateRepoFile(repo1, user2, &repofiles.UpdateRepoFileOptions{
+				OldBranch: repo1.DefaultBranch,
+				TreePath:  treePath,
+				Content:   content,
+				IsNewFile: true,
+			})
+			assert.NoError(t, err)
+		}
+		executeIndexer(t, repo1, code_indexer.UpdateRepoIndexer)
+
+		testAPISearchCodeQualifiers := func(t *testing.T) {
+			for query, expected := range map[string][]string{
+				"symbol:greet":                {"lib/greet.go"},
+				"symbol:Greet lang:go":        {"lib/greet.go"},
+				"symbol:Greet lang:python":    {},
+				"world path:main":             {"main.go"},
+				"world path:lib/":             {},
+				`Hello path:"*.md"`:           {},
+				"description path:readme.md":  {"README.md"},
+				"symbol:nonexist path:lib/*.": {},
+			} {
+				req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/search/code?q=%s", url.QueryEscape(query))
+				resp := MakeRequest(t, req, http.StatusOK)
+				var results api.CodeSearchResults
+				DecodeJSON(t, resp, &results)
+				paths := make([]string, 0, len(results.Items))
+				for _, item := range results.Items {
+					paths = append(paths, item.Path)
+				}
+				assert.ElementsMatch(t, expected, paths, query)
+			}
+
+			// the definition is highlighted by a symbol search
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search/code?q=symbol:Greet")
+			resp := MakeRequest(t, req, http.StatusOK)
+			var results api.CodeSearchResults
+			DecodeJSON(t, resp, &results)
+			if assert.Len(t, results.Items, 1) {
+				numbers := make([]int, 0, len(results.Items[0].Lines))
+				for _, line := range results.Items[0].Lines {
+					numbers = append(numbers, line.Number)
+				}
+				assert.Contains(t, numbers, 4)
+			}
+		}
+
+		t.Run("Indexer", testAPISearchCodeQualifiers)
+
+		defer func(enabled bool) {
+			setting.Indexer.RepoIndexerEnabled = enabled
+		}(setting.Indexer.RepoIndexerEnabled)
+		setting.Indexer.RepoIndexerEnabled = false
+		t.Run("GitGrep", testAPISearchCodeQualifiers)
+	})
+}
+
+func TestAPISearchAllCode(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	for _, name := range []string{"user2/repo1", "user3/repo3"} {
+		repo, err := models.GetRepositoryByOwnerAndName(path.Dir(name), path.Base(name))
+		assert.NoError(t, err)
+		executeIndexer(t, repo, code_indexer.UpdateRepoIndexer)
+	}
+
+	searchRepos := func(t *testing.T, session *TestSession, token, query string) []string {
+		req := NewRequestf(t, "GET", "/api/v1/repos/search/code?%s&token=%s", query, token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var results api.CodeSearchResults
+		DecodeJSON(t, resp, &results)
+		repos := make([]string, 0, len(results.Items))
+		for _, item := range results.Items {
+			repos = append(repos, item.Repository.FullName)
+		}
+		return repos
+	}
+
+	// anonymous users only search the public repositories
+	session := emptyTestSession(t)
+	repos := searchRepos(t, session, "", "q=Description")
+	assert.Contains(t, repos, "user2/repo1")
+	assert.NotContains(t, repos, "user3/repo3")
+	assert.Empty(t, searchRepos(t, session, "", "q=Description&owner=user3"))
+
+	// user2 is an owner of the user3 organization
+	session = loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	repos = searchRepos(t, session, token, "q=Description")
+	assert.Contains(t, repos, "user2/repo1")
+	assert.Contains(t, repos, "user3/repo3")
+	assert.Equal(t, []string{"user3/repo3"}, searchRepos(t, session, token, "q=Description&owner=user3"))
+	assert.Empty(t, searchRepos(t, session, token, "q=Description&owner=user3&page=2&limit=1"))
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/search/code?q=Description&owner=nonexist&token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// the explore page is scoped the same way
+	req = NewRequest(t, "GET", "/explore/code?q=Description&owner=user3")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, []string{"user3/repo3 - README.md"}, resultFilenames(t, NewHTMLParser(t, resp.Body)))
+
+	req = NewRequest(t, "GET", "/explore/code?q=Description&owner=user3")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.NotContains(t, resp.Body.String(), "user3/repo3")
+}
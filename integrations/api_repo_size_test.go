@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoSize(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// only the admins of the repository get the size
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/size?token="+token), http.StatusForbidden)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/size"), http.StatusUnauthorized)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/size?token="+token)
+	resp := session.MakeRequest(t, req, NoExpectedStatus)
+	for i := 0; i < 100 && resp.Code == http.StatusAccepted; i++ {
+		time.Sleep(50 * time.Millisecond)
+		resp = session.MakeRequest(t, req, NoExpectedStatus)
+	}
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var size api.RepoSize
+	DecodeJSON(t, resp, &size)
+	assert.NotZero(t, size.Git)
+	assert.Equal(t, size.Git+size.LFS+size.Attachments, size.Total)
+	assert.False(t, size.Computed.IsZero())
+
+	// the cached breakdown is returned directly
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var cached api.RepoSize
+	DecodeJSON(t, resp, &cached)
+	assert.Equal(t, size.Total, cached.Total)
+}
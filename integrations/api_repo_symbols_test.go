@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	symbols_indexer "code.gitea.io/gitea/modules/indexer/symbols"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIListSymbols(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		session := emptyTestSession(t)
+
+		// the API isn't available while the indexer is disabled
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/symbols", user2.Name, repo1.Name)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		defer func(enabled bool) {
+			setting.Indexer.SymbolIndexerEnabled = enabled
+		}(setting.Indexer.SymbolIndexerEnabled)
+		setting.Indexer.SymbolIndexerEnabled = true
+
+		for treePath, content := range map[string]string{
+			"lib/greet.go": "package lib\n\n// Greet greets\nfunc Greet(name string) string {\n\treturn \"Hello \" + name\n}\n",
+			"main.go":      "package main\n\nimport \"lib\"\n\nfunc main() {\n\tlib.Greet(\"world\")\n}\n",
+		} {
+			_, err := repofiles.CreateOrUpdateRepoFile(repo1, user2, &repofiles.UpdateRepoFileOptions{
+				OldBranch: repo1.DefaultBranch,
+				TreePath:  treePath,
+				Content:   content,
+				IsNewFile: true,
+			})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, (&symbols_indexer.DBIndexer{}).Index(repo1.ID))
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/symbols", user2.Name, repo1.Name)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var symbols []*api.CodeSymbol
+		DecodeJSON(t, resp, &symbols)
+		assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+		if assert.Len(t, symbols, 2) {
+			assert.Equal(t, "Greet", symbols[0].Name)
+			assert.Equal(t, "function", symbols[0].Kind)
+			assert.Equal(t, "Go", symbols[0].Language)
+			assert.Equal(t, "lib/greet.go", symbols[0].Path)
+			assert.Equal(t, 4, symbols[0].Line)
+			assert.Contains(t, symbols[0].HTMLURL, "/lib/greet.go#L4")
+			assert.Equal(t, "main", symbols[1].Name)
+		}
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/symbols?q=gre&path=lib", user2.Name, repo1.Name)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &symbols)
+		assert.Len(t, symbols, 1)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/symbols?path=README.md", user2.Name, repo1.Name)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &symbols)
+		assert.Empty(t, symbols)
+
+		// identifiers of the file view jump to their definition
+		req = NewRequestf(t, "GET", "/%s/%s/src/branch/%s/main.go", user2.Name, repo1.Name, repo1.DefaultBranch)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		link, exists := doc.doc.Find(".lines-code a.symbol-link:contains(Greet)").Attr("href")
+		assert.True(t, exists)
+		assert.Contains(t, link, "/lib/greet.go#L4")
+	})
+}
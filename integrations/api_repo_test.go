@@ -325,7 +325,7 @@ func TestAPIRepoMigrate(t *testing.T) {
 		})
 		resp := MakeRequest(t, req, NoExpectedStatus)
 		if resp.Code == http.StatusUnprocessableEntity {
-			respJSON := map[string]string{}
+			respJSON := map[string]interface{}{}
 			DecodeJSON(t, resp, &respJSON)
 			switch respJSON["message"] {
 			case "Remote visit addressed rate limitation.":
@@ -376,7 +376,7 @@ func testAPIRepoMigrateConflict(t *testing.T, u *url.URL) {
 				RepoName:    httpContext.Reponame,
 			})
 		resp := httpContext.Session.MakeRequest(t, req, http.StatusConflict)
-		respJSON := map[string]string{}
+		respJSON := map[string]interface{}{}
 		DecodeJSON(t, resp, &respJSON)
 		assert.Equal(t, "The repository with the same name already exists.", respJSON["message"])
 	})
@@ -431,7 +431,7 @@ func testAPIRepoCreateConflict(t *testing.T, u *url.URL) {
 				Name: httpContext.Reponame,
 			})
 		resp := httpContext.Session.MakeRequest(t, req, http.StatusConflict)
-		respJSON := map[string]string{}
+		respJSON := map[string]interface{}{}
 		DecodeJSON(t, resp, &respJSON)
 		assert.Equal(t, respJSON["message"], "The repository with the same name already exists.")
 	})
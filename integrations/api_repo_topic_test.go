@@ -74,11 +74,21 @@ func TestAPIRepoTopic(t *testing.T) {
 		Topics: newTopics,
 	})
 	res = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	var invalidTopicsError api.APIInvalidTopicsError
+	DecodeJSON(t, res, &invalidTopicsError)
+	assert.Equal(t, []interface{}{"topicname!"}, invalidTopicsError.InvalidTopics)
 	req = NewRequest(t, "GET", url)
 	res = session.MakeRequest(t, req, http.StatusOK)
 	DecodeJSON(t, res, &topics)
 	assert.ElementsMatch(t, []string{"windows", "mac"}, topics.TopicNames)
 
+	// Test add an invalid topic
+	req = NewRequestf(t, "PUT", "/api/v1/repos/%s/%s/topics/%s?token=%s", user2.Name, repo2.Name, "topicname!", token2)
+	res = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	invalidTopicsError = api.APIInvalidTopicsError{}
+	DecodeJSON(t, res, &invalidTopicsError)
+	assert.Equal(t, "topicname!", invalidTopicsError.InvalidTopics)
+
 	// Test with some topics multiple times, less than 25 unique
 	newTopics = []string{"t1", "t2", "t1", "t3", "t4", "t5", "t6", "t7", "t8", "t9", "t10", "t11", "t12", "t13", "t14", "t15", "t16", "17", "t18", "t19", "t20", "t21", "t22", "t23", "t24", "t25"}
 	req = NewRequestWithJSON(t, "PUT", url, &api.RepoTopicOptions{
@@ -96,6 +106,10 @@ func TestAPIRepoTopic(t *testing.T) {
 		Topics: newTopics,
 	})
 	res = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	var body map[string]interface{}
+	DecodeJSON(t, res, &body)
+	assert.Contains(t, body, "invalidTopics")
+	assert.Nil(t, body["invalidTopics"])
 
 	// Test add a topic when there is already maximum
 	req = NewRequestf(t, "PUT", "/api/v1/repos/%s/%s/topics/%s?token=%s", user2.Name, repo2.Name, "t26", token2)
@@ -0,0 +1,119 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIEditRepoUnits(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	var repo api.Repository
+	req := NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/units?token="+token, &api.EditRepoUnitsOption{
+		Enable:  []string{"repo.projects"},
+		Disable: []string{"repo.wiki"},
+	})
+	DecodeJSON(t, session.MakeRequest(t, req, http.StatusOK), &repo)
+	assert.False(t, repo.HasWiki)
+	assert.True(t, repo.HasProjects)
+	assert.True(t, repo.HasIssues)
+
+	// the code and external units can't be toggled, and the unknown units are rejected
+	for _, unit := range []string{"repo.code", "repo.ext_wiki", "repo.actions"} {
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/units?token="+token, &api.EditRepoUnitsOption{
+			Disable: []string{unit},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/units?token="+token, &api.EditRepoUnitsOption{
+		Enable:  []string{"repo.wiki"},
+		Disable: []string{"repo.wiki"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only the admins of the repository can edit its units
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/units?token="+token, &api.EditRepoUnitsOption{
+		Enable: []string{"repo.wiki"},
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIBulkEditRepoUnits(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	var task api.RepoUnitsTask
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos/units?token="+token, &api.BulkEditRepoUnitsOption{
+		Enable:       []string{"repo.projects"},
+		Disable:      []string{"repo.wiki"},
+		Pattern:      "repo[35]",
+		Repositories: []string{"repo3", "repo5", "repo21", "unknown"},
+	})
+	DecodeJSON(t, session.MakeRequest(t, req, http.StatusAccepted), &task)
+	assert.NotZero(t, task.ID)
+
+	// wait for the task to be run
+	for i := 0; i < 50 && task.Status != "finished" && task.Status != "failed"; i++ {
+		time.Sleep(100 * time.Millisecond)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/user3/repos/units/%d?token=%s", task.ID, token))
+		DecodeJSON(t, session.MakeRequest(t, req, http.StatusOK), &task)
+	}
+	assert.Equal(t, "finished", task.Status)
+	assert.NotNil(t, task.Finished)
+	if assert.Len(t, task.Results, 3) {
+		assert.Equal(t, "unknown", task.Results[0].Repository)
+		assert.NotEmpty(t, task.Results[0].Error)
+		assert.ElementsMatch(t, []string{"repo3", "repo5"}, []string{task.Results[1].Repository, task.Results[2].Repository})
+		assert.Empty(t, task.Results[1].Error)
+		assert.Empty(t, task.Results[2].Error)
+	}
+
+	for _, repoID := range []int64{3, 5} {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: repoID}).(*models.Repository)
+		assert.False(t, repo.UnitEnabled(models.UnitTypeWiki))
+		assert.True(t, repo.UnitEnabled(models.UnitTypeProjects))
+	}
+	// the repositories which don't match the pattern are left untouched
+	repo21 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 32}).(*models.Repository)
+	assert.False(t, repo21.UnitEnabled(models.UnitTypeProjects))
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos/units?token="+token, &api.BulkEditRepoUnitsOption{
+		Enable: []string{"repo.actions"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos/units?token="+token, &api.BulkEditRepoUnitsOption{})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos/units?token="+token, &api.BulkEditRepoUnitsOption{
+		Enable:  []string{"repo.wiki"},
+		Pattern: "repo[",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only the owners of the organization can edit the units of its repositories
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos/units?token="+token, &api.BulkEditRepoUnitsOption{
+		Enable: []string{"repo.wiki"},
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/user3/repos/units/%d?token=%s", task.ID, token))
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
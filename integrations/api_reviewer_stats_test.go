@@ -0,0 +1,96 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReviewerStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/insights/reviewers")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var stats []*api.ReviewerStats
+	DecodeJSON(t, resp, &stats)
+	if assert.Len(t, stats, 4) {
+		assert.Equal(t, "user1", stats[0].Reviewer.UserName)
+		assert.EqualValues(t, 2, stats[0].Reviews)
+		assert.EqualValues(t, 1, stats[0].Approvals)
+		assert.EqualValues(t, 1, stats[0].ApprovalRatio)
+		assert.Equal(t, "user2", stats[1].Reviewer.UserName)
+		assert.EqualValues(t, 1, stats[1].Rejections)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/insights/reviewers?since=2000-01-01T00:00:12Z")
+	resp = MakeRequest(t, req, http.StatusOK)
+	stats = nil
+	DecodeJSON(t, resp, &stats)
+	assert.Len(t, stats, 3)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/insights/reviewers?since=yesterday")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the pull requests of the private repository can't be read
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/insights/reviewers")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/insights/reviewers")
+	resp = MakeRequest(t, req, http.StatusOK)
+	stats = nil
+	DecodeJSON(t, resp, &stats)
+	assert.Empty(t, stats)
+
+	// the review stats of user2 are hidden from the others once it keeps them private
+	session := loginUser(t, "user2")
+	req = NewRequestWithValues(t, "POST", "/user/settings", map[string]string{
+		"_csrf":                     GetCSRF(t, session, "/user/settings"),
+		"name":                      "user2",
+		"email":                     "user2@example.com",
+		"language":                  "en-US",
+		"keep_review_stats_private": "1",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/insights/reviewers")
+	resp = MakeRequest(t, req, http.StatusOK)
+	stats = nil
+	DecodeJSON(t, resp, &stats)
+	assert.Len(t, stats, 3)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/insights/reviewers?token=%s", getTokenForLoggedInUser(t, session))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	stats = nil
+	DecodeJSON(t, resp, &stats)
+	assert.Len(t, stats, 4)
+}
+
+func TestRepoActivityReviewers(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// the reviews of the fixtures are too old for the period
+	req := NewRequest(t, "GET", "/user2/repo1/activity/yearly")
+	resp := MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 0, htmlDoc.doc.Find("#reviewers").Length())
+
+	session := loginUser(t, "user4")
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls/3/reviews?token="+getTokenForLoggedInUser(t, session), &api.CreatePullReviewOptions{
+		Body:  "looks good",
+		Event: api.ReviewStateComment,
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/user2/repo1/activity/yearly")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 1, htmlDoc.doc.Find("#reviewers").Length())
+	assert.Contains(t, htmlDoc.doc.Find("#reviewers + table tbody tr").Text(), "user4")
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISavedReplies(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/replies?token="+token, &api.CreateSavedReplyOption{
+		Title:   "Thanks",
+		Content: "Thanks for the report!",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var reply api.SavedReply
+	DecodeJSON(t, resp, &reply)
+	assert.Equal(t, "Thanks", reply.Title)
+	assert.EqualValues(t, 2, reply.Owner.ID)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/replies?token="+token, &api.CreateSavedReplyOption{Title: "Empty"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// user2 owns the organization user3
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/replies?token="+token, &api.CreateSavedReplyOption{
+		Title:   "Duplicate",
+		Content: "Duplicate of #1",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var orgReply api.SavedReply
+	DecodeJSON(t, resp, &orgReply)
+	assert.EqualValues(t, 3, orgReply.Owner.ID)
+
+	var replies []*api.SavedReply
+	req = NewRequest(t, "GET", "/api/v1/user/replies?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &replies)
+	assert.Len(t, replies, 1)
+
+	req = NewRequest(t, "GET", "/api/v1/user/replies?include_orgs=true&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &replies)
+	assert.Len(t, replies, 2)
+
+	content := "Thank you!"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/user/replies/%d?token=%s", reply.ID, token), &api.EditSavedReplyOption{
+		Content: &content,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reply)
+	assert.Equal(t, "Thanks", reply.Title)
+	assert.Equal(t, content, reply.Content)
+
+	// the replies are private to their owner and to the members of the organization
+	session5 := loginUser(t, "user5")
+	token5 := getTokenForLoggedInUser(t, session5)
+	req = NewRequestf(t, "GET", "/api/v1/user/replies/%d?token=%s", reply.ID, token5)
+	session5.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/replies/%d?token=%s", orgReply.ID, token5)
+	session5.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/orgs/user3/replies/%d?token=%s", orgReply.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "DELETE", "/api/v1/user/replies/%d?token=%s", reply.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.SavedReply{ID: reply.ID})
+	models.AssertNotExistsBean(t, &models.SavedReply{ID: orgReply.ID})
+}
+
+func TestSavedRepliesInsertion(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user/settings/replies")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user/settings/replies", map[string]string{
+		"_csrf":   htmlDoc.GetCSRF(),
+		"title":   "Thanks",
+		"content": "Thanks for the report!",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.SavedReply{OwnerID: 2, Title: "Thanks"})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(`select.saved-replies option[value="Thanks for the report!"]`).Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/pulls/2/files")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.True(t, htmlDoc.doc.Find(`#review-box select.saved-replies`).Length() > 0)
+
+	req = NewRequest(t, "GET", "/org/user3/settings/replies")
+	session.MakeRequest(t, req, http.StatusOK)
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/scim"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISCIM(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func() {
+		setting.SCIM.Enabled = false
+		setting.SCIM.DryRun = false
+	}()
+
+	admin := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	token := &models.AccessToken{UID: admin.ID, Name: "test-scim", Scope: models.AccessTokenScopeSCIM}
+	assert.NoError(t, models.NewAccessToken(token))
+
+	scimRequest := func(method, url string, body interface{}, status int) *scim.Error {
+		req := NewRequestWithJSON(t, method, "/api/scim/v2"+url, body)
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+		resp := MakeRequest(t, req, status)
+		if status >= http.StatusBadRequest {
+			scimErr := new(scim.Error)
+			DecodeJSON(t, resp, scimErr)
+			return scimErr
+		}
+		if status != http.StatusNoContent {
+			assert.Equal(t, scim.ContentType, resp.Header().Get("Content-Type"))
+			DecodeJSON(t, resp, body)
+		}
+		return nil
+	}
+
+	scimRequest("GET", "/ServiceProviderConfig", nil, http.StatusNotFound)
+	setting.SCIM.Enabled = true
+
+	// only the tokens of site administrators allowed to use SCIM are accepted
+	req := NewRequest(t, "GET", "/api/scim/v2/Users?token="+getTokenForLoggedInUser(t, loginUser(t, "user2")))
+	MakeRequest(t, req, http.StatusForbidden)
+	ciToken := &models.AccessToken{UID: admin.ID, Name: "test-ci", Scope: "read:code"}
+	assert.NoError(t, models.NewAccessToken(ciToken))
+	req = NewRequest(t, "GET", "/api/scim/v2/Users?token="+ciToken.Token)
+	MakeRequest(t, req, http.StatusForbidden)
+	// the tokens without scopes aren't accepted either
+	req = NewRequest(t, "GET", "/api/scim/v2/Users?token="+getTokenForLoggedInUser(t, loginUser(t, "user1")))
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/scim/v2/Users")
+	req = AddBasicAuthHeader(req, admin.Name)
+	MakeRequest(t, req, http.StatusUnauthorized)
+	req = NewRequest(t, "GET", "/api/v1/user?token="+token.Token)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	config := new(scim.ServiceProviderConfig)
+	scimRequest("GET", "/ServiceProviderConfig", config, http.StatusOK)
+	assert.True(t, config.Patch.Supported)
+
+	// users
+	user := &scim.User{
+		Schemas:  []string{scim.SchemaUser},
+		UserName: "jdoe",
+		Name:     &scim.Name{GivenName: "John", FamilyName: "Doe"},
+		Emails:   []scim.Email{{Value: "jdoe@example.com", Primary: true}},
+	}
+	scimRequest("POST", "/Users", user, http.StatusCreated)
+	assert.NotEmpty(t, user.ID)
+	assert.True(t, *user.Active)
+	u := models.AssertExistsAndLoadBean(t, &models.User{Name: "jdoe"}).(*models.User)
+	assert.Equal(t, "John Doe", u.FullName)
+	assert.Equal(t, "jdoe@example.com", u.Email)
+	assert.False(t, u.ProhibitLogin)
+
+	err := scimRequest("POST", "/Users", &scim.User{UserName: "jdoe", Emails: []scim.Email{{Value: "other@example.com"}}}, http.StatusConflict)
+	assert.Equal(t, scim.ErrorTypeUniqueness, err.ScimType)
+	err = scimRequest("POST", "/Users", &scim.User{UserName: "nomail"}, http.StatusBadRequest)
+	assert.Equal(t, scim.ErrorTypeInvalidValue, err.ScimType)
+
+	list := &scim.ListResponse{Resources: &[]*scim.User{}}
+	scimRequest("GET", `/Users?filter=userName+eq+"JDoe"`, list, http.StatusOK)
+	assert.EqualValues(t, 1, list.TotalResults)
+	if users := *list.Resources.(*[]*scim.User); assert.Len(t, users, 1) {
+		assert.Equal(t, user.ID, users[0].ID)
+	}
+	list = &scim.ListResponse{Resources: &[]*scim.User{}}
+	scimRequest("GET", "/Users?startIndex=2&count=1", list, http.StatusOK)
+	assert.EqualValues(t, models.CountUsers(), list.TotalResults)
+	assert.Equal(t, 2, list.StartIndex)
+	assert.Len(t, *list.Resources.(*[]*scim.User), 1)
+	err = scimRequest("GET", `/Users?filter=title+eq+"x"`, nil, http.StatusBadRequest)
+	assert.Equal(t, scim.ErrorTypeInvalidFilter, err.ScimType)
+
+	// deactivation
+	patched := new(scim.User)
+	scimRequest("PATCH", "/Users/"+user.ID, &scim.PatchRequest{
+		Schemas:    []string{scim.SchemaPatchOp},
+		Operations: []scim.PatchOperation{{Op: "replace", Path: "active", Value: []byte("false")}},
+	}, http.StatusOK)
+	scimRequest("GET", "/Users/"+user.ID, patched, http.StatusOK)
+	assert.False(t, *patched.Active)
+	u = models.AssertExistsAndLoadBean(t, &models.User{ID: u.ID}).(*models.User)
+	assert.True(t, u.ProhibitLogin)
+
+	user.UserName = "john"
+	user.Active = nil
+	scimRequest("PUT", "/Users/"+user.ID, user, http.StatusOK)
+	assert.Equal(t, "john", user.UserName)
+	u = models.AssertExistsAndLoadBean(t, &models.User{ID: u.ID}).(*models.User)
+	assert.Equal(t, "john", u.Name)
+	assert.False(t, u.ProhibitLogin)
+	scimRequest("GET", "/Users/999999", nil, http.StatusNotFound)
+	scimRequest("GET", "/Users/3", nil, http.StatusNotFound)
+
+	// groups are teams
+	groups := &scim.ListResponse{Resources: &[]*scim.Group{}}
+	scimRequest("GET", `/Groups?filter=displayName+eq+"user3/team1"`, groups, http.StatusOK)
+	group := new(scim.Group)
+	if list := *groups.Resources.(*[]*scim.Group); assert.Len(t, list, 1) {
+		group = list[0]
+	}
+	assert.Equal(t, "2", group.ID)
+	assert.Len(t, group.Members, 2)
+
+	scimRequest("PATCH", "/Groups/2", &scim.PatchRequest{
+		Operations: []scim.PatchOperation{{Op: "add", Path: "members", Value: []byte(`[{"value": "` + user.ID + `"}]`)}},
+	}, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.TeamUser{TeamID: 2, UID: u.ID})
+	scimRequest("PATCH", "/Groups/2", &scim.PatchRequest{
+		Operations: []scim.PatchOperation{{Op: "remove", Path: `members[value eq "2"]`}},
+	}, http.StatusOK)
+	models.AssertNotExistsBean(t, &models.TeamUser{TeamID: 2, UID: 2})
+	err = scimRequest("PATCH", "/Groups/2", &scim.PatchRequest{
+		Operations: []scim.PatchOperation{{Op: "replace", Path: "displayName", Value: []byte(`"user2/team1"`)}},
+	}, http.StatusBadRequest)
+	assert.Equal(t, scim.ErrorTypeMutability, err.ScimType)
+
+	created := &scim.Group{DisplayName: "user3/developers", Members: []scim.Member{{Value: user.ID}}}
+	scimRequest("POST", "/Groups", created, http.StatusCreated)
+	team := models.AssertExistsAndLoadBean(t, &models.Team{OrgID: 3, LowerName: "developers"}).(*models.Team)
+	models.AssertExistsAndLoadBean(t, &models.TeamUser{TeamID: team.ID, UID: u.ID})
+	scimRequest("POST", "/Groups", &scim.Group{DisplayName: "user3/developers"}, http.StatusConflict)
+	scimRequest("POST", "/Groups", &scim.Group{DisplayName: "missing/developers"}, http.StatusBadRequest)
+	scimRequest("DELETE", "/Groups/"+created.ID, nil, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Team{ID: team.ID})
+	scimRequest("DELETE", "/Groups/1", nil, http.StatusBadRequest)
+
+	// dry run
+	setting.SCIM.DryRun = true
+	dry := &scim.User{UserName: "dry", Emails: []scim.Email{{Value: "dry@example.com"}}}
+	scimRequest("POST", "/Users", dry, http.StatusCreated)
+	assert.Empty(t, dry.ID)
+	models.AssertNotExistsBean(t, &models.User{Name: "dry"})
+	scimRequest("DELETE", "/Users/"+user.ID, nil, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.User{ID: u.ID})
+	setting.SCIM.DryRun = false
+
+	// the members of organizations aren't deleted
+	scimRequest("DELETE", "/Users/"+user.ID, nil, http.StatusConflict)
+	scimRequest("PATCH", "/Groups/2", &scim.PatchRequest{
+		Operations: []scim.PatchOperation{{Op: "remove", Path: "members", Value: []byte(`[{"value": "` + user.ID + `"}]`)}},
+	}, http.StatusOK)
+	scimRequest("DELETE", "/Users/"+user.ID, nil, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.User{ID: u.ID})
+}
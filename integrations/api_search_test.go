@@ -0,0 +1,82 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchFacetCount(results *api.GlobalSearchResults, searchType string) int64 {
+	for _, facet := range results.Facets {
+		if facet.Type == searchType {
+			return facet.Count
+		}
+	}
+	return -1
+}
+
+func TestAPIGlobalSearch(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	executeIndexer(t, repo, code_indexer.UpdateRepoIndexer)
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/search"), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/search?q=repo&type=packages"), http.StatusUnprocessableEntity)
+
+	// the private repository repo16 of user2 is only found by the users who can read it
+	var results api.GlobalSearchResults
+	resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/search?q=repo16"), http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	assert.Len(t, results.Facets, 6)
+	assert.EqualValues(t, 0, searchFacetCount(&results, "repositories"))
+	assert.Empty(t, results.Repositories)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/search?q=repo16&token="+token), http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	assert.EqualValues(t, 1, searchFacetCount(&results, "repositories"))
+	if assert.Len(t, results.Repositories, 1) {
+		assert.Equal(t, "user2/repo16", results.Repositories[0].FullName)
+	}
+
+	// only the results of the requested type are listed but every type is counted
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/search?q=user2&type=users&limit=1&token="+token), http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	assert.Len(t, results.Users, 1)
+	assert.Empty(t, results.Repositories)
+	assert.Empty(t, results.Organizations)
+	assert.True(t, searchFacetCount(&results, "users") >= 1)
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/search?q=Description&type=code&token="+token), http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	assert.True(t, searchFacetCount(&results, "code") >= 1)
+	assert.NotEmpty(t, results.Code)
+}
+
+func TestGlobalSearchPage(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/search?q=repo1")
+	resp := MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 7, htmlDoc.doc.Find(".search-facets .item").Length())
+	assert.EqualValues(t, 6, htmlDoc.doc.Find(".search-results").Length())
+	assert.True(t, htmlDoc.doc.Find(`.search-results[data-type="repositories"] .item`).Length() > 0)
+
+	req = NewRequest(t, "GET", "/search?q=user2&type=organizations")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".search-results").Length())
+	htmlDoc.AssertElement(t, `.search-facets .active.item[data-type="organizations"]`, true)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIShortLink(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/short_links?token="+token, &api.CreateShortLinkOption{
+		Path:      "README.md",
+		LineStart: 1,
+		LineEnd:   2,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var fileLink api.ShortLink
+	DecodeJSON(t, resp, &fileLink)
+	assert.Equal(t, "file", fileLink.Type)
+	assert.Equal(t, commitID, fileLink.CommitID)
+	assert.Equal(t, setting.AppURL+"s/"+fileLink.Token, fileLink.URL)
+	assert.Equal(t, setting.AppURL+"user2/repo1/src/commit/"+commitID+"/README.md#L1-L2", fileLink.Permalink)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/short_links/"+fileLink.Token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var got api.ShortLink
+	DecodeJSON(t, resp, &got)
+	assert.Equal(t, fileLink.Permalink, got.Permalink)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/short_links/"+fileLink.Token+"?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/short_links?token="+token, &api.CreateShortLinkOption{
+		Type:      "diff",
+		Ref:       "master",
+		Path:      "README.md",
+		LineStart: 3,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var diffLink api.ShortLink
+	DecodeJSON(t, resp, &diffLink)
+	assert.Equal(t, setting.AppURL+"user2/repo1/commit/"+commitID+"#diff-"+base.EncodeSha1("README.md")+"R3", diffLink.Permalink)
+
+	// missing files and invalid lines are rejected
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/short_links?token="+token, &api.CreateShortLinkOption{Path: "missing.md"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/short_links?token="+token, &api.CreateShortLinkOption{Ref: "missing", Path: "README.md"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/short_links?token="+token, &api.CreateShortLinkOption{Path: "README.md", LineStart: 3, LineEnd: 2})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the short links require to sign in
+	req = NewRequest(t, "GET", "/s/"+fileLink.Token)
+	resp = MakeRequest(t, req, http.StatusFound)
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Location"), "/user/login"))
+
+	req = NewRequest(t, "GET", "/s/"+fileLink.Token)
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user2/repo1/src/commit/"+commitID+"/README.md#L1-L2", resp.Header().Get("Location"))
+	req = NewRequest(t, "GET", "/s/missing")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// the links keep resolving when the repository is renamed
+	newName := "repo1-renamed"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1?token="+token, &api.EditRepoOption{Name: &newName})
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/s/"+diffLink.Token)
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user2/repo1-renamed/commit/"+commitID+"#diff-"+base.EncodeSha1("README.md")+"R3", resp.Header().Get("Location"))
+
+	// the links of a private repository can only be resolved by its readers
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo16/short_links?token="+token, &api.CreateShortLinkOption{Path: "readme.md"})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var privateLink api.ShortLink
+	DecodeJSON(t, resp, &privateLink)
+	req = NewRequest(t, "GET", "/s/"+privateLink.Token)
+	loginUser(t, "user4").MakeRequest(t, req, http.StatusNotFound)
+}
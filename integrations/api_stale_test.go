@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIStalePolicy(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := "/api/v1/repos/user2/repo1/stale_policy?token=" + token
+
+	session.MakeRequest(t, NewRequest(t, "GET", link), http.StatusNotFound)
+
+	req := NewRequestWithJSON(t, "PUT", link, &api.EditStalePolicyOption{DaysUntilStale: -1})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", link, &api.EditStalePolicyOption{
+		Enabled:        true,
+		IncludeIssues:  true,
+		DaysUntilStale: 30,
+		DaysUntilClose: 0,
+		ExemptLabels:   []string{"label2"},
+		WarningComment: "This issue is stale.",
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var policy api.StalePolicy
+	DecodeJSON(t, resp, &policy)
+	assert.True(t, policy.Enabled)
+	assert.Equal(t, models.DefaultStaleLabel, policy.StaleLabel)
+	assert.Equal(t, []string{"label2"}, policy.ExemptLabels)
+	assert.EqualValues(t, 2, policy.Doer.ID)
+
+	session.MakeRequest(t, NewRequest(t, "GET", link), http.StatusOK)
+
+	// only the admins of the repository can read the policy
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	session4.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stale_policy?token="+token4), http.StatusForbidden)
+
+	assert.NoError(t, issue_service.ProcessStaleIssues(context.Background()))
+	label := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: 1, Name: models.DefaultStaleLabel}).(*models.Label)
+	assert.True(t, models.HasIssueLabel(1, label.ID))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stale_policy/actions?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var actions []*api.StaleAction
+	DecodeJSON(t, resp, &actions)
+	if assert.Len(t, actions, 1) {
+		assert.Equal(t, "mark", actions[0].Action)
+		assert.EqualValues(t, 1, actions[0].Issue.Index)
+	}
+
+	session.MakeRequest(t, NewRequest(t, "DELETE", link), http.StatusNoContent)
+	session.MakeRequest(t, NewRequest(t, "DELETE", link), http.StatusNotFound)
+}
+
+func TestStalePolicySettings(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/settings/stale")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/stale", map[string]string{
+		"_csrf":            htmlDoc.GetCSRF(),
+		"enabled":          "on",
+		"include_pulls":    "on",
+		"days_until_stale": "10",
+		"days_until_close": "3",
+		"stale_label":      "inactive",
+		"exempt_labels":    "label1,label2",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	policy := models.AssertExistsAndLoadBean(t, &models.StalePolicy{RepoID: 1}).(*models.StalePolicy)
+	assert.True(t, policy.Enabled)
+	assert.False(t, policy.IncludeIssues)
+	assert.True(t, policy.IncludePulls)
+	assert.Equal(t, 10, policy.DaysUntilStale)
+	assert.Equal(t, 3, policy.DaysUntilClose)
+	assert.Equal(t, "inactive", policy.StaleLabelName())
+	assert.EqualValues(t, 2, policy.DoerID)
+
+	req = NewRequest(t, "GET", "/user2/repo1/settings/stale")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, "input[name=include_pulls][checked]", true)
+	htmlDoc.AssertElement(t, "input[name=include_issues][checked]", false)
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITeamWatchRules(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "PUT", "/api/v1/teams/2/watches/user3/repo3?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.Watch{UserID: 4, RepoID: 3, Mode: models.RepoWatchModeAuto})
+
+	// the repository must be a repository of the team
+	req = NewRequestf(t, "PUT", "/api/v1/teams/2/watches/user3/repo5?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "GET", "/api/v1/teams/2/watches?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repos []*api.Repository
+	DecodeJSON(t, resp, &repos)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 3, repos[0].ID)
+	}
+
+	// only the owners of the organization can manage the rules
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequestf(t, "GET", "/api/v1/teams/2/watches?token=%s", token4)
+	session4.MakeRequest(t, req, http.StatusOK)
+	req = NewRequestf(t, "DELETE", "/api/v1/teams/2/watches/user3/repo3?token=%s", token4)
+	session4.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/teams/2/watches/user3/repo3?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.TeamWatchRule{TeamID: 2, RepoID: 3})
+	models.AssertNotExistsBean(t, &models.Watch{UserID: 4, RepoID: 3})
+}
@@ -5,11 +5,14 @@
 package integrations
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
 	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestAPICreateAndDeleteToken tests that token that was just created can be deleted
@@ -61,3 +64,161 @@ func TestAPIDeleteMissingToken(t *testing.T) {
 	req = AddBasicAuthHeader(req, user.Name)
 	MakeRequest(t, req, http.StatusNotFound)
 }
+
+// TestAPIRestrictedToken tests the permissions of a token restricted to the scopes of CI systems
+func TestAPIRestrictedToken(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", map[string]interface{}{
+		"name":   "test-ci",
+		"scopes": []string{"write:status", "read:code"},
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var ciToken api.AccessToken
+	DecodeJSON(t, resp, &ciToken)
+	assert.EqualValues(t, []string{"read:code", "write:status"}, ciToken.Scopes)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", map[string]interface{}{
+		"name":   "test-invalid",
+		"scopes": []string{"write:everything"},
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	for _, kase := range []struct {
+		method string
+		url    string
+		status int
+	}{
+		{"GET", "/api/v1/repos/user2/repo1", http.StatusOK},
+		{"GET", "/api/v1/repos/user2/repo1/contents/README.md", http.StatusOK},
+		{"GET", "/api/v1/repos/user2/repo1/issues", http.StatusNotFound},
+		{"PATCH", "/api/v1/repos/user2/repo1", http.StatusForbidden},
+		{"DELETE", "/api/v1/repos/user2/repo1", http.StatusForbidden},
+		{"GET", "/api/v1/user", http.StatusForbidden},
+		{"GET", "/api/v1/users/user2/tokens", http.StatusForbidden},
+		{"POST", "/api/v1/user/repos", http.StatusForbidden},
+	} {
+		req = NewRequestWithJSON(t, kase.method, kase.url+"?token="+ciToken.Token, map[string]string{})
+		resp = MakeRequest(t, req, NoExpectedStatus)
+		assert.EqualValues(t, kase.status, resp.Code, "%s %s", kase.method, kase.url)
+	}
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d?token="+ciToken.Token,
+		api.CreateStatusOption{
+			State:   api.StatusSuccess,
+			Context: "ci/test",
+		})
+	MakeRequest(t, req, http.StatusCreated)
+
+	// the token can clone but not push
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth(ciToken.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(ciToken.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// the token of a site administrator doesn't grant its privileges
+	admin := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	adminToken := &models.AccessToken{UID: admin.ID, Name: "test-ci", Scope: "read:code,write:status"}
+	assert.NoError(t, models.NewAccessToken(adminToken))
+	req = NewRequest(t, "GET", "/api/v1/admin/orgs?token="+adminToken.Token)
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?sudo=user2&token="+adminToken.Token)
+	MakeRequest(t, req, http.StatusForbidden)
+}
+
+// TestAPIFineGrainedToken tests the permissions of a token restricted by unit scopes and to repositories
+func TestAPIFineGrainedToken(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", api.CreateAccessTokenOption{
+		Name:         "test-issues",
+		Scopes:       []string{"write:issues", "read:code"},
+		Repositories: []string{"user2/repo1"},
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var token api.AccessToken
+	DecodeJSON(t, resp, &token)
+	assert.EqualValues(t, []string{"read:code", "write:issues"}, token.Scopes)
+	assert.EqualValues(t, []string{"user2/repo1"}, token.Repositories)
+	assert.Empty(t, token.Owners)
+
+	// the private repositories of others can't be given
+	req = NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", api.CreateAccessTokenOption{
+		Name:         "test-invalid",
+		Repositories: []string{"user10/repo6"},
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	for _, kase := range []struct {
+		method string
+		url    string
+		status int
+	}{
+		{"GET", "/api/v1/repos/user2/repo1/issues", http.StatusOK},
+		{"POST", "/api/v1/repos/user2/repo1/issues", http.StatusCreated},
+		{"GET", "/api/v1/repos/user2/repo1/releases", http.StatusForbidden},
+		{"PATCH", "/api/v1/repos/user2/repo1", http.StatusForbidden},
+		{"GET", "/api/v1/repos/user2/repo16", http.StatusNotFound},
+		{"GET", "/api/v1/user", http.StatusForbidden},
+	} {
+		req = NewRequestWithJSON(t, kase.method, kase.url+"?token="+token.Token, map[string]string{"title": "test"})
+		resp = MakeRequest(t, req, NoExpectedStatus)
+		assert.EqualValues(t, kase.status, resp.Code, "%s %s", kase.method, kase.url)
+	}
+
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// the token is edited without changing its secret
+	scopes := []string{"write:code"}
+	owners := []string{"user3"}
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/users/user2/tokens/%d", token.ID), api.EditAccessTokenOption{
+		Scopes: &scopes,
+		Owners: &owners,
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/api/v1/users/user2/tokens/test-issues")
+	req = AddBasicAuthHeader(req, "user2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var edited api.AccessToken
+	DecodeJSON(t, resp, &edited)
+	assert.EqualValues(t, []string{"write:code"}, edited.Scopes)
+	assert.EqualValues(t, []string{"user2/repo1"}, edited.Repositories)
+	assert.EqualValues(t, []string{"user3"}, edited.Owners)
+	assert.Empty(t, edited.Token)
+
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/user3/repo3.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/user2/repo2.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// without scopes, the token has all the permissions of its owner on the repositories
+	noScopes := []string{}
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/users/user2/tokens/test-issues", api.EditAccessTokenOption{
+		Scopes: &noScopes,
+	})
+	req = AddBasicAuthHeader(req, "user2")
+	MakeRequest(t, req, http.StatusOK)
+
+	description := "edited with a restricted token"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1?token="+token.Token, api.EditRepoOption{
+		Description: &description,
+	})
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2?token="+token.Token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
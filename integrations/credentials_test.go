@@ -0,0 +1,61 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialsLastUsed(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(days int) {
+		setting.StaleCredentialDays = days
+	}(setting.StaleCredentialDays)
+	setting.StaleCredentialDays = 90
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequest(t, "GET", "/api/v1/user?token="+token)
+	req.Header.Set("X-Real-IP", "203.0.113.5")
+	MakeRequest(t, req, http.StatusOK)
+
+	accessToken, err := models.GetAccessTokenBySHA(token)
+	assert.NoError(t, err)
+	assert.True(t, accessToken.HasUsed)
+	assert.Equal(t, "203.0.113.5", accessToken.LastUsedIP)
+
+	// the settings show where the token was last used from and warn about the stale ones
+	req = NewRequest(t, "GET", "/user/settings/applications")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "203.0.113.5")
+	assert.Contains(t, resp.Body.String(), "Not used for more than 90 days")
+
+	// only the site admins see the stale credentials of the instance
+	req = NewRequest(t, "GET", "/admin/credentials")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	adminSession := loginUser(t, "user1")
+	req = NewRequest(t, "GET", "/admin/credentials?type=access_token")
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	rows := htmlDoc.doc.Find("#stale-credentials tbody tr")
+	assert.Equal(t, 3, rows.Length())
+	rows.Each(func(_ int, row *goquery.Selection) {
+		assert.NotContains(t, row.Text(), "api-testing-token")
+	})
+
+	req = NewRequest(t, "GET", "/admin/credentials?type=ssh_key")
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.True(t, strings.Contains(htmlDoc.doc.Find("#stale-credentials tbody").Text(), "user2@localhost"))
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditorStagedChanges(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		_, err := createFile(user2, repo1, "staged/delete.txt")
+		assert.NoError(t, err)
+
+		gitRepo, err := git.OpenRepository(repo1.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		before, err := gitRepo.GetBranchCommit("master")
+		assert.NoError(t, err)
+
+		session := loginUser(t, "user2")
+
+		// stage a new file
+		req := NewRequest(t, "GET", "/user2/repo1/_new/master/")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		assert.EqualValues(t, 1, doc.doc.Find("#stage-button").Length())
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_new/master/", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "staged/new.txt",
+			"content":       "Staged content",
+			"commit_choice": "direct",
+			"stage":         "true",
+		})
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.EqualValues(t, "/user2/repo1/_staged/master", resp.Header().Get("Location"))
+
+		// the staged file can be edited again
+		req = NewRequest(t, "GET", "/user2/repo1/_edit/master/staged/new.txt")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.Contains(t, doc.doc.Find("#edit_area").Text(), "Staged content")
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_edit/master/staged/new.txt", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "staged/new.txt",
+			"content":       "Staged content edited",
+			"commit_choice": "direct",
+			"stage":         "true",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+
+		// stage the update of an existing file and the deletion of another one
+		req = NewRequest(t, "GET", "/user2/repo1/_edit/master/README.md")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_edit/master/README.md", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "README.md",
+			"content":       "Staged README",
+			"commit_choice": "direct",
+			"stage":         "true",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		req = NewRequest(t, "GET", "/user2/repo1/_delete/master/staged/delete.txt")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_delete/master/staged/delete.txt", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"commit_choice": "direct",
+			"stage":         "true",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+
+		// nothing has been committed yet
+		commit, err := gitRepo.GetBranchCommit("master")
+		assert.NoError(t, err)
+		assert.Equal(t, before.ID, commit.ID)
+
+		req = NewRequest(t, "GET", "/user2/repo1")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "3 change(s) are staged on this branch.")
+
+		req = NewRequest(t, "GET", "/user2/repo1/_staged/master")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.EqualValues(t, 3, doc.doc.Find("#staged-changes-table tr").Length())
+
+		// unstage the update of the README
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_unstage/master", map[string]string{
+			"_csrf":     doc.GetCSRF(),
+			"tree_path": "README.md",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+
+		// commit the staged changes in a single commit
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_staged/master", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"commit_choice": "direct",
+		})
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.EqualValues(t, "/user2/repo1/src/branch/master", resp.Header().Get("Location"))
+
+		commit, err = gitRepo.GetBranchCommit("master")
+		assert.NoError(t, err)
+		assert.Equal(t, "Update 2 files\n", commit.Message())
+		parent, err := commit.Parent(0)
+		assert.NoError(t, err)
+		assert.Equal(t, before.ID, parent.ID)
+		entry, err := commit.GetTreeEntryByPath("staged/new.txt")
+		assert.NoError(t, err)
+		data, err := entry.Blob().GetBlobContent()
+		assert.NoError(t, err)
+		assert.Equal(t, "Staged content edited", data)
+		_, err = commit.GetTreeEntryByPath("staged/delete.txt")
+		assert.Error(t, err)
+		changed, err := commit.FileChangedSinceCommit("README.md", before.ID.String())
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		// the staged changes have been cleared
+		req = NewRequest(t, "GET", "/user2/repo1/_staged/master")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.EqualValues(t, 0, doc.doc.Find(".commit-form").Length())
+	})
+}
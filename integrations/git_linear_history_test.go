@@ -0,0 +1,89 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushLinearHistory(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName:           "master",
+			EnablePush:           true,
+			RequireLinearHistory: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.RequireLinearHistory)
+
+		dstPath, err := ioutil.TempDir("", "repo1-linear")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		env := append(os.Environ(),
+			"GIT_AUTHOR_NAME=User Two", "GIT_AUTHOR_EMAIL=user2@example.com",
+			"GIT_COMMITTER_NAME=User Two", "GIT_COMMITTER_EMAIL=user2@example.com")
+		commit := func(name string) {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, name), []byte(name), 0644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			_, err := git.NewCommand("commit", "-m", fmt.Sprintf("add %s", name)).RunInDirWithEnv(dstPath, env)
+			assert.NoError(t, err)
+		}
+
+		t.Run("CreateSideBranch", doGitCreateBranch(dstPath, "side"))
+		commit("side.txt")
+		t.Run("PushSideBranch", doGitPushTestRepository(dstPath, "origin", "side"))
+		t.Run("CheckoutMaster", doGitCheckoutBranch(dstPath, "master"))
+		commit("master.txt")
+		t.Run("PushLinear", doGitPushTestRepository(dstPath, "origin", "master"))
+
+		// pull requests can't be merged with a merge commit
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "side",
+			Base:  "master",
+			Title: "side",
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID}).(*models.PullRequest)
+		user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		gitRepo, err := git.OpenRepository(models.RepoPath("user2", "repo1"))
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		err = pull_service.Merge(pr, user, gitRepo, models.MergeStyleMerge, "merge side")
+		assert.True(t, models.IsErrInvalidMergeStyle(err))
+		err = pull_service.Merge(pr, user, gitRepo, models.MergeStyleRebaseMerge, "merge side")
+		assert.True(t, models.IsErrInvalidMergeStyle(err))
+
+		// merge commits can't be pushed
+		_, err = git.NewCommand("merge", "--no-ff", "side").RunInDirWithEnv(dstPath, env)
+		assert.NoError(t, err)
+		t.Run("PushMergeCommit", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushOptionsCreatePullRequest(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		dstPath, err := ioutil.TempDir("", "repo1-push-options")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		env := append(os.Environ(),
+			"GIT_AUTHOR_NAME=User Two", "GIT_AUTHOR_EMAIL=user2@example.com",
+			"GIT_COMMITTER_NAME=User Two", "GIT_COMMITTER_EMAIL=user2@example.com")
+		commit := func(name string) {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, name), []byte(name), 0644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			_, err := git.NewCommand("commit", "-m", fmt.Sprintf("add %s\n\ndescription of %s", name, name)).RunInDirWithEnv(dstPath, env)
+			assert.NoError(t, err)
+		}
+
+		t.Run("CreateBranch", doGitCreateBranch(dstPath, "push-options"))
+		commit("push-options.txt")
+
+		// no pull request is created to a missing base branch
+		t.Run("PushMissingBase", doGitPushTestRepository(dstPath, "-o", "pr.create", "-o", "pr.base=missing", "origin", "push-options"))
+		models.AssertNotExistsBean(t, &models.PullRequest{BaseRepoID: 1, HeadBranch: "push-options"})
+
+		// the pull request is only created for the branch of the topic
+		commit("other.txt")
+		t.Run("PushOtherTopic", doGitPushTestRepository(dstPath, "-o", "pr.create", "-o", "pr.topic=other", "origin", "push-options"))
+		models.AssertNotExistsBean(t, &models.PullRequest{BaseRepoID: 1, HeadBranch: "push-options"})
+
+		commit("labels.txt")
+		t.Run("PushCreate", doGitPushTestRepository(dstPath,
+			"-o", "pr.create",
+			"-o", "pr.title=Created by push options",
+			"-o", "pr.description=the description",
+			"-o", "pr.labels=label1, missing",
+			"origin", "push-options"))
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: 1, HeadRepoID: 1, HeadBranch: "push-options"}).(*models.PullRequest)
+		assert.Equal(t, "master", pr.BaseBranch)
+		assert.NoError(t, pr.LoadIssue())
+		assert.Equal(t, "Created by push options", pr.Issue.Title)
+		assert.Equal(t, "the description", pr.Issue.Content)
+		assert.EqualValues(t, 2, pr.Issue.PosterID)
+		assert.NoError(t, pr.Issue.LoadLabels())
+		if assert.Len(t, pr.Issue.Labels, 1) {
+			assert.Equal(t, "label1", pr.Issue.Labels[0].Name)
+		}
+
+		// the existing pull request is kept
+		commit("again.txt")
+		t.Run("PushAgain", doGitPushTestRepository(dstPath, "-o", "pr.create", "-o", "pr.title=Again", "origin", "push-options"))
+		assert.Equal(t, 1, models.GetCount(t, &models.PullRequest{BaseRepoID: 1, HeadBranch: "push-options"}))
+
+		// the title defaults to the summary of a single commit
+		t.Run("CreateSingleBranch", doGitCheckoutBranch(dstPath, "-B", "push-options-single", "origin/master"))
+		commit("single.txt")
+		t.Run("PushSingle", doGitPushTestRepository(dstPath, "-o", "pr.create", "origin", "push-options-single"))
+		pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: 1, HeadBranch: "push-options-single"}).(*models.PullRequest)
+		assert.NoError(t, pr.LoadIssue())
+		assert.Equal(t, "add single.txt", pr.Issue.Title)
+		assert.Equal(t, "description of single.txt", pr.Issue.Content)
+	})
+}
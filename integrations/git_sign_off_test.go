@@ -0,0 +1,116 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushSignOff(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName:     "master",
+			EnablePush:     true,
+			RequireSignOff: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.RequireSignOff)
+
+		dstPath, err := ioutil.TempDir("", "repo1-sign-off")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		env := append(os.Environ(),
+			"GIT_AUTHOR_NAME=User Two", "GIT_AUTHOR_EMAIL=user2@example.com",
+			"GIT_COMMITTER_NAME=User Two", "GIT_COMMITTER_EMAIL=user2@example.com")
+		commit := func(name string, args ...string) {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, name), []byte(name), 0644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			_, err := git.NewCommand(append([]string{"commit", "-m", fmt.Sprintf("add %s", name)}, args...)...).RunInDirWithEnv(dstPath, env)
+			assert.NoError(t, err)
+		}
+
+		// commits must be signed off by their author
+		commit("unsigned.txt")
+		t.Run("PushNotSignedOff", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+		_, err = git.NewCommand("commit", "--amend", "--no-edit", "--signoff", "--trailer", "Co-authored-by: User Four <user4@example.com>").RunInDirWithEnv(dstPath, env)
+		if err != nil {
+			// older git versions don't support --trailer
+			_, err = git.NewCommand("commit", "--amend", "-m", "add unsigned.txt\n\nCo-authored-by: User Four <user4@example.com>", "--signoff").RunInDirWithEnv(dstPath, env)
+		}
+		assert.NoError(t, err)
+		t.Run("PushSignedOff", doGitPushTestRepository(dstPath, "origin", "master"))
+
+		// the trailers are included in the commits API
+		sha, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+		assert.NoError(t, err)
+		req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/git/commits/%s?token=%s", strings.TrimSpace(sha), token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var apiCommit api.Commit
+		DecodeJSON(t, resp, &apiCommit)
+		assert.True(t, apiCommit.RepoCommit.SignedOff)
+		if assert.Len(t, apiCommit.RepoCommit.Trailers, 2) {
+			for _, trailer := range apiCommit.RepoCommit.Trailers {
+				if assert.NotNil(t, trailer.User) {
+					if trailer.Key == git.TrailerSignedOffBy {
+						assert.EqualValues(t, 2, trailer.User.ID)
+					} else {
+						assert.EqualValues(t, git.TrailerCoAuthoredBy, trailer.Key)
+						assert.EqualValues(t, 4, trailer.User.ID)
+					}
+				}
+			}
+		}
+
+		// pull requests with commits which aren't signed off can't be merged
+		t.Run("CreateSideBranch", doGitCreateBranch(dstPath, "side"))
+		commit("signed.txt", "--signoff")
+		commit("side.txt")
+		t.Run("PushSideBranch", doGitPushTestRepository(dstPath, "origin", "side"))
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "side",
+			Base:  "master",
+			Title: "side",
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID}).(*models.PullRequest)
+		missing, err := pull_service.GetCommitsMissingSignOff(pr)
+		assert.NoError(t, err)
+		if assert.Len(t, missing, 1) {
+			assert.EqualValues(t, "add side.txt", missing[0].Summary())
+		}
+		assert.True(t, models.IsErrNotAllowedToMerge(pull_service.CheckPRReadyToMerge(pr, false)))
+
+		resp = session.MakeRequest(t, NewRequestf(t, "GET", "/user2/repo1/pulls/%d", pull.Index), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "add side.txt")
+		session.MakeRequest(t, NewRequestf(t, "GET", "/user2/repo1/pulls/%d/commits", pull.Index), http.StatusOK)
+	})
+}
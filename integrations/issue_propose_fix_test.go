@@ -0,0 +1,73 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testProposeFix(t *testing.T, session *TestSession, treePath, content, title string) *models.PullRequest {
+	link := "/user2/repo1/issues/1/propose_fix"
+	req := NewRequest(t, "GET", link+"?path="+url.QueryEscape(treePath))
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	lastCommit, _ := htmlDoc.Find(`input[name="last_commit"]`).Attr("value")
+	assert.NotEmpty(t, lastCommit)
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"tree_path":   treePath,
+		"content":     content,
+		"last_commit": lastCommit,
+		"title":       title,
+		"description": "Proposed fix",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	redirect := resp.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(redirect, "/user2/repo1/pulls/"), redirect)
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, Title: title, IsPull: true}).(*models.Issue)
+	assert.Equal(t, "Proposed fix\n\nclose #1", issue.Content)
+	return models.AssertExistsAndLoadBean(t, &models.PullRequest{IssueID: issue.ID}).(*models.PullRequest)
+}
+
+func TestIssueProposeFix(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		// a writer proposes the fix from a branch of the repository
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/user2/repo1/issues/1/propose_fix?path=README.md")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "Description for repo1")
+
+		pr := testProposeFix(t, session, "README.md", "# repo1\n\nFixed description\n", "Fix issue1")
+		assert.EqualValues(t, 1, pr.HeadRepoID)
+		assert.Equal(t, "fix-issue-1", pr.HeadBranch)
+		assert.Equal(t, "master", pr.BaseBranch)
+		models.AssertNotExistsBean(t, &models.Repository{OwnerID: 2, ForkID: 1})
+
+		// a reader proposes it from a fork created for it
+		session = loginUser(t, "user4")
+		req = NewRequest(t, "GET", "/user2/repo1/issues/1/propose_fix?path=docs/fix.md")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "user4/repo1")
+
+		pr = testProposeFix(t, session, "docs/fix.md", "the fix\n", "Fix issue1 from a fork")
+		fork := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 4, ForkID: 1}).(*models.Repository)
+		assert.Equal(t, fork.ID, pr.HeadRepoID)
+		// the fork has the branch of the first fix
+		assert.Equal(t, "fix-issue-1-2", pr.HeadBranch)
+
+		// pull requests can't be fixed
+		req = NewRequest(t, "GET", "/user2/repo1/issues/2/propose_fix")
+		session.MakeRequest(t, req, http.StatusNotFound)
+	})
+}
@@ -7,6 +7,7 @@ package integrations
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
@@ -16,11 +17,13 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/indexer/issues"
 	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/repofiles"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/test"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
+	"github.com/unknwon/i18n"
 )
 
 func getIssuesSelection(t testing.TB, htmlDoc *HTMLDoc) *goquery.Selection {
@@ -57,6 +60,49 @@ func TestNoLoginViewIssues(t *testing.T) {
 	MakeRequest(t, req, http.StatusOK)
 }
 
+func TestViewIssueTimelineFilter(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#issuecomment-2").Length())
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#issuecomment-3").Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1?types=label")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 0, htmlDoc.doc.Find("#issuecomment-2").Length())
+	assert.EqualValues(t, 0, htmlDoc.doc.Find("#issuecomment-3").Length())
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".timeline-filter .menu .active.item").Length())
+}
+
+func TestIssueCommentCodePreview(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	issueURL := testNewIssue(t, session, "user2", "repo1", "Title", "Description")
+	permalink := setting.AppURL + "user2/repo1/src/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md#L1-L2"
+	req := NewRequest(t, "GET", issueURL)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	link, exists := htmlDoc.doc.Find("#comment-form").Attr("action")
+	assert.True(t, exists, "The template has changed")
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":   htmlDoc.GetCSRF(),
+		"content": permalink,
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	req = NewRequest(t, "GET", issueURL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	preview := htmlDoc.doc.Find(".comment .render-content .code-preview")
+	assert.EqualValues(t, 1, preview.Length())
+	assert.EqualValues(t, 2, preview.Find("td.lines-code").Length())
+	assert.Contains(t, preview.Find("td.lines-code").First().Text(), "repo1")
+}
+
 func TestViewIssuesSortByType(t *testing.T) {
 	defer prepareTestEnv(t)()
 
@@ -340,3 +386,173 @@ func TestIssueRedirect(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusFound)
 	assert.Equal(t, "/"+path.Join("org26", "repo_external_tracker_alpha", "pulls", "1"), test.RedirectURL(resp))
 }
+
+func TestIssuesExport(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithValues(t, "POST", "/user2/repo1/issues/export?issue_ids=1,2", map[string]string{
+		"_csrf": GetCSRF(t, session, "/user2/repo1/issues"),
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var result struct {
+		Complete bool `json:"complete"`
+	}
+	DecodeJSON(t, resp, &result)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/export?issue_ids=1,2")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	issue1 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	issue2 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue)
+	assert.Contains(t, htmlDoc.doc.Find("#issue-1 h1").Text(), issue1.Title)
+	assert.Contains(t, htmlDoc.doc.Find("#issue-2 h1").Text(), issue2.Title)
+	assert.Contains(t, htmlDoc.doc.Find("#issue-1 table a").Text(), "attach1")
+
+	// issues of another repository
+	req = NewRequest(t, "GET", "/user2/repo1/issues/export?issue_ids=4")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/export")
+	session.MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestNewIssueFromForm(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		_, err := repofiles.CreateOrUpdateRepoFile(repo1, user2, &repofiles.UpdateRepoFileOptions{
+			OldBranch: repo1.DefaultBranch,
+			TreePath:  ".gitea/ISSUE_TEMPLATE/bug.yml",
+			Content: `name: Bug Report
+about: File a bug report
+title: "[Bug]: "
+body:
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      required: true
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Terms
+      options:
+        - label: I searched the existing issues
+`,
+			IsNewFile: true,
+		})
+		assert.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/user2/repo1/issues/new?template=bug.yml")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, "input[name=form-field-version]", true)
+		htmlDoc.AssertElement(t, "#content", false)
+		title, _ := htmlDoc.doc.Find("#issue_title").Attr("value")
+		assert.Equal(t, "[Bug]: ", title)
+
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/new", map[string]string{
+			"_csrf":    htmlDoc.GetCSRF(),
+			"title":    "[Bug]: crash",
+			"template": "bug.yml",
+		})
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find(".flash-error").Text(), i18n.Tr("en", "repo.issues.form.field_invalid", "Version"))
+
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/new", map[string]string{
+			"_csrf":              htmlDoc.GetCSRF(),
+			"title":              "[Bug]: crash",
+			"template":           "bug.yml",
+			"form-field-version": "1.13.0",
+			"form-field-terms-0": "on",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo1.ID, Title: "[Bug]: crash"}).(*models.Issue)
+		assert.Equal(t, "### Version\n\n1.13.0\n\n### Terms\n\n- [x] I searched the existing issues", issue.Content)
+	})
+}
+
+func TestSubIssuesWeb(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/children/add", map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"child_index": "4",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5, ParentID: 1})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".sub-issues .sub-issue.is-closed a[href='/user2/repo1/issues/4']", true)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/4")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".sub-issues .sub-issue a[href='/user2/repo1/issues/1']", true)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues?parent=1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".sub-issues-filter", true)
+	assert.EqualValues(t, 1, getIssuesSelection(t, htmlDoc).Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".issue.list a.sub-issues[href='/user2/repo1/issues?parent=1']", true)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/children/delete", map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"child_index": "4",
+		"redirect_to": "/user2/repo1/issues/4",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user2/repo1/issues/4", resp.Header().Get("Location"))
+	issue5 := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5}).(*models.Issue)
+	assert.EqualValues(t, 0, issue5.ParentID)
+}
+
+func TestIssueReminderCommand(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+	commentCount := models.GetCount(t, &models.Comment{IssueID: 1})
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/comments", map[string]string{
+		"_csrf":   htmlDoc.GetCSRF(),
+		"content": "/remind 2h",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	reminder := models.AssertExistsAndLoadBean(t, &models.IssueReminder{UserID: 2, IssueID: 1}).(*models.IssueReminder)
+	assert.Equal(t, commentCount, models.GetCount(t, &models.Comment{IssueID: 1}))
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	htmlDoc = NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+	htmlDoc.AssertElement(t, fmt.Sprintf(`form[action="/user2/repo1/issues/1/reminders/%d/delete"]`, reminder.ID), true)
+
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/user2/repo1/issues/1/reminders/%d/delete", reminder.ID), map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	models.AssertNotExistsBean(t, &models.IssueReminder{ID: reminder.ID})
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/reminders", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"remind_at": "1w",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	models.AssertExistsAndLoadBean(t, &models.IssueReminder{UserID: 2, IssueID: 1})
+}
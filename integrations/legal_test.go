@@ -0,0 +1,108 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/legal"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testAcceptLegalPages(t *testing.T, session *TestSession) {
+	req := NewRequest(t, "GET", "/user/legal")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	fingerprint, _ := htmlDoc.Find(`input[name="fingerprint"]`).Attr("value")
+	req = NewRequestWithValues(t, "POST", "/user/legal", map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"fingerprint": fingerprint,
+		"accept":      "on",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+}
+
+func TestLegalPages(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	dir := path.Join(setting.CustomPath, "templates", "legal")
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+		// the templates directory is removed when the test created it
+		_ = os.Remove(path.Dir(dir))
+		setting.Legal.RequireAcceptance = false
+		assert.NoError(t, legal.Init())
+	}()
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "terms.tmpl"), []byte("<p>Terms of {{.SignedUser.Name}}</p>"), 0644))
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "terms.fr-FR.tmpl"), []byte("<p>Conditions</p>"), 0644))
+	assert.NoError(t, legal.Init())
+	setting.Legal.RequireAcceptance = true
+
+	// the privacy policy has no template
+	req := NewRequest(t, "GET", "/legal/privacy")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/legal/terms")
+	req.Header.Set("Accept-Language", "fr-FR")
+	resp := MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "<p>Conditions</p>")
+
+	session := loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/user2")
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user/legal", resp.Header().Get("Location"))
+
+	req = NewRequest(t, "GET", "/legal/terms")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "<p>Terms of user2</p>")
+
+	// the pages changed since they were read
+	req = NewRequest(t, "GET", "/user/legal")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithValues(t, "POST", "/user/legal", map[string]string{
+		"_csrf":       NewHTMLParser(t, resp.Body).GetCSRF(),
+		"fingerprint": "0",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user/legal", resp.Header().Get("Location"))
+	models.AssertNotExistsBean(t, &models.LegalAcceptance{UserID: 2})
+
+	testAcceptLegalPages(t, session)
+	page := models.AssertExistsAndLoadBean(t, &models.LegalPage{Name: "terms", Version: 1}).(*models.LegalPage)
+	models.AssertExistsAndLoadBean(t, &models.LegalAcceptance{UserID: 2, PageID: page.ID})
+	req = NewRequest(t, "GET", "/user2")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// a new version of the terms has to be accepted again
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "terms.tmpl"), []byte("<p>New terms</p>"), 0644))
+	assert.NoError(t, legal.Init())
+	req = NewRequest(t, "GET", "/user2")
+	session.MakeRequest(t, req, http.StatusFound)
+	testAcceptLegalPages(t, session)
+	newPage := models.AssertExistsAndLoadBean(t, &models.LegalPage{Name: "terms", Version: 2}).(*models.LegalPage)
+	models.AssertExistsAndLoadBean(t, &models.LegalAcceptance{UserID: 2, PageID: newPage.ID})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// the acceptances are reported to the administrators
+	session = loginUser(t, "user1")
+	testAcceptLegalPages(t, session)
+	req = NewRequest(t, "GET", "/admin/legal")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.Find(fmt.Sprintf(`a[href="/admin/legal/%d"]`, page.ID)).Length())
+
+	req = NewRequestf(t, "GET", "/admin/legal/%d", newPage.ID)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 2, htmlDoc.Find(`table tbody tr`).Length())
+}
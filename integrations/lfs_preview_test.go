@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFSImagePreview(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+		content := buf.Bytes()
+		oid := storeObjectInRepo(t, repo.ID, &content)
+		defer repo.RemoveLFSMetaObjectByOid(oid)
+
+		meta := &models.LFSMetaObject{Oid: oid, Size: int64(len(content))}
+		resp, err := repofiles.CreateOrUpdateRepoFile(repo, user, &repofiles.UpdateRepoFileOptions{
+			TreePath:  "image.png",
+			Content:   meta.Pointer(),
+			IsNewFile: true,
+		})
+		assert.NoError(t, err)
+		sha := resp.Commit.SHA
+
+		session := loginUser(t, user.Name)
+		req := NewRequest(t, "GET", "/user2/repo1/commit/"+sha)
+		htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, `img[src="/user2/repo1/media/commit/`+sha+`/image.png"]`, true)
+		assert.Equal(t, "3", htmlDoc.doc.Find(".diff-file-box span.text").First().Text())
+
+		req = NewRequest(t, "GET", "/user2/repo1/media/commit/"+sha+"/image.png")
+		assert.Equal(t, content, session.MakeRequest(t, req, http.StatusOK).Body.Bytes())
+
+		req = NewRequest(t, "GET", "/user2/repo1/src/commit/"+sha+"/image.png")
+		htmlDoc = NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, ".view-raw img", true)
+
+		defer func(maxPreviewSize int64) {
+			setting.LFS.MaxPreviewSize = maxPreviewSize
+		}(setting.LFS.MaxPreviewSize)
+		setting.LFS.MaxPreviewSize = int64(len(content)) - 1
+
+		req = NewRequest(t, "GET", "/user2/repo1/commit/"+sha)
+		htmlDoc = NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, `img[src="/user2/repo1/media/commit/`+sha+`/image.png"]`, false)
+
+		req = NewRequest(t, "GET", "/user2/repo1/src/commit/"+sha+"/image.png")
+		htmlDoc = NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, ".view-raw img", false)
+	})
+}
@@ -33,6 +33,8 @@ func TestLinksNoLogin(t *testing.T) {
 		"/user/forgot_password",
 		"/api/swagger",
 		"/api/v1/swagger",
+		"/swagger.v1.json",
+		"/openapi.v1.json",
 		"/user2/repo1",
 		"/user2/repo1/projects",
 		"/user2/repo1/projects/1",
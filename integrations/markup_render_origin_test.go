@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/external"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkupRenderOrigin(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		defer func(renderOrigin string) {
+			setting.MarkupRenderOrigin = renderOrigin
+		}(setting.MarkupRenderOrigin)
+		markup.RegisterParser(&external.Parser{MarkupParser: setting.MarkupParser{
+			Enabled:           true,
+			MarkupName:        "render-origin-test",
+			Command:           "cat",
+			FileExtensions:    []string{".render-origin-test"},
+			RenderContentMode: setting.RenderContentModeIframe,
+		}})
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo16/contents/doc.render-origin-test?token=%s", token), &api.CreateFileOptions{
+			FileOptions: api.FileOptions{BranchName: "master"},
+			Content:     base64.StdEncoding.EncodeToString([]byte("<script>alert(1)</script>")),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		getIframeLink := func() string {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo16/src/branch/master/doc.render-origin-test"), http.StatusOK)
+			link, exists := NewHTMLParser(t, resp.Body).doc.Find("iframe.markup-render-iframe").Attr("src")
+			assert.True(t, exists)
+			return link
+		}
+
+		// without render origin, the documents are served by the instance
+		link := getIframeLink()
+		assert.True(t, strings.HasPrefix(link, "/user2/repo16/render/commit/"))
+		resp := session.MakeRequest(t, NewRequest(t, "GET", link), http.StatusOK)
+		assert.Equal(t, "<script>alert(1)</script>", resp.Body.String())
+		assert.Equal(t, "sandbox allow-scripts allow-popups", resp.Header().Get("Content-Security-Policy"))
+
+		// with a render origin, the links identify the user as the cookies aren't sent there
+		setting.MarkupRenderOrigin = "http://render.example.com"
+		link = getIframeLink()
+		renderURL, err := url.Parse(link)
+		assert.NoError(t, err)
+		assert.Equal(t, "render.example.com", renderURL.Host)
+		assert.EqualValues(t, "2", renderURL.Query().Get("uid"))
+
+		renderRequest := func(requestURI string, status int) *httptest.ResponseRecorder {
+			req := NewRequest(t, "GET", requestURI)
+			req.Host = "render.example.com"
+			return MakeRequest(t, req, status)
+		}
+		resp = renderRequest(renderURL.RequestURI(), http.StatusOK)
+		assert.Equal(t, "<script>alert(1)</script>", resp.Body.String())
+		assert.Equal(t, "sandbox allow-scripts allow-popups", resp.Header().Get("Content-Security-Policy"))
+
+		// the links can't be used for other users or documents
+		renderRequest(renderURL.Path, http.StatusNotFound)
+		renderRequest(strings.Replace(renderURL.RequestURI(), "uid=2", "uid=1", 1), http.StatusNotFound)
+		otherPath := strings.Replace(renderURL.RequestURI(), "doc.render-origin-test", "README.md", 1)
+		renderRequest(otherPath, http.StatusNotFound)
+
+		// the render origin only serves the rendered documents, which are no longer served by the instance
+		renderRequest("/user2/repo1", http.StatusNotFound)
+		renderRequest("/user/login", http.StatusNotFound)
+		session.MakeRequest(t, NewRequest(t, "GET", renderURL.Path), http.StatusNotFound)
+	})
+}
@@ -7,10 +7,14 @@ package integrations
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http/httptest"
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/user"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -253,3 +257,81 @@ func TestRefreshTokenInvalidation(t *testing.T) {
 	refreshReq.Body = ioutil.NopCloser(bytes.NewReader(bs))
 	MakeRequest(t, refreshReq, 400)
 }
+
+func TestDeviceAuthorizationGrant(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(interval int64) {
+		setting.OAuth2.DeviceCodePollingInterval = interval
+	}(setting.OAuth2.DeviceCodePollingInterval)
+
+	req := NewRequestWithValues(t, "POST", "/login/oauth/device_authorization", map[string]string{
+		"client_id": "unknown",
+	})
+	MakeRequest(t, req, 400)
+
+	startDeviceAuthorization := func() *user.DeviceAuthorizationResponse {
+		req := NewRequestWithValues(t, "POST", "/login/oauth/device_authorization", map[string]string{
+			"client_id": "da7da3ba-9a13-4167-856f-3899de0b0138",
+		})
+		resp := MakeRequest(t, req, 200)
+		parsed := new(user.DeviceAuthorizationResponse)
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), parsed))
+		assert.Equal(t, setting.AppURL+"login/device", parsed.VerificationURI)
+		return parsed
+	}
+	pollAccessToken := func(deviceCode string, status int) *httptest.ResponseRecorder {
+		req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+			"client_id":   "da7da3ba-9a13-4167-856f-3899de0b0138",
+			"device_code": deviceCode,
+		})
+		return MakeRequest(t, req, status)
+	}
+	assertPollError := func(deviceCode, errorCode string) {
+		resp := pollAccessToken(deviceCode, 400)
+		parsed := new(user.AccessTokenError)
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), parsed))
+		assert.EqualValues(t, errorCode, parsed.ErrorCode)
+	}
+	grantDevice := func(session *TestSession, userCode string, granted bool) {
+		req := NewRequest(t, "GET", "/login/device?user_code="+userCode)
+		resp := session.MakeRequest(t, req, 200)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, "#authorize-device", true)
+		req = NewRequestWithValues(t, "POST", "/login/device", map[string]string{
+			"_csrf":     htmlDoc.GetCSRF(),
+			"user_code": userCode,
+			"granted":   fmt.Sprint(granted),
+		})
+		session.MakeRequest(t, req, 302)
+	}
+
+	device := startDeviceAuthorization()
+	assertPollError(device.DeviceCode, "authorization_pending")
+	assertPollError(device.DeviceCode, "slow_down")
+	setting.OAuth2.DeviceCodePollingInterval = 0
+	assertPollError(device.DeviceCode, "authorization_pending")
+
+	session := loginUser(t, "user4")
+	req = NewRequest(t, "GET", "/login/device?user_code=BCDF-GHJK")
+	resp := session.MakeRequest(t, req, 200)
+	NewHTMLParser(t, resp.Body).AssertElement(t, ".flash-error", true)
+	grantDevice(session, device.UserCode, true)
+
+	resp = pollAccessToken(device.DeviceCode, 200)
+	parsed := new(user.AccessTokenResponse)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), parsed))
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.Header.Set("Authorization", "bearer "+parsed.AccessToken)
+	resp = MakeRequest(t, req, 200)
+	var apiUser api.User
+	DecodeJSON(t, resp, &apiUser)
+	assert.Equal(t, "user4", apiUser.UserName)
+
+	// the device code can only be exchanged once
+	assertPollError(device.DeviceCode, "invalid_grant")
+
+	device = startDeviceAuthorization()
+	grantDevice(session, device.UserCode, false)
+	assertPollError(device.DeviceCode, "access_denied")
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullCommitMessageComment(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/pulls/3/commits")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	link, exists := htmlDoc.doc.Find(`a[href$="/message"]`).First().Attr("href")
+	assert.True(t, exists)
+	commitID := strings.TrimSuffix(strings.TrimPrefix(link, "/user2/repo1/pulls/3/commits/"), "/message")
+
+	req = NewRequest(t, "GET", link)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#line-1").Length())
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":            htmlDoc.GetCSRF(),
+		"line":             "1",
+		"content":          "Please describe the change",
+		"latest_commit_id": commitID,
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Header().Get("Location"), "/user2/repo1/pulls/3#issuecomment-")
+
+	req = NewRequest(t, "GET", "/user2/repo1/pulls/3")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "Please describe the change")
+
+	req = NewRequest(t, "GET", link)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "Please describe the change")
+
+	// lines out of the message and commits out of the pull request can't be commented
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":   htmlDoc.GetCSRF(),
+		"line":    "1000",
+		"content": "out of the message",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/user2/repo1/pulls/3/commits/65f1bf27bc3bf70f64657658635e66094edbcb4d/message")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// the API creates them as part of reviews
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls/3/reviews?token="+token, &api.CreatePullReviewOptions{
+		Event: api.ReviewStateComment,
+		Body:  "Some messages need work",
+		Comments: []api.CreatePullReviewComment{{
+			CommitID:   commitID,
+			NewLineNum: 1,
+			Body:       "Fix the typo",
+		}},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var review api.PullReview
+	DecodeJSON(t, resp, &review)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/3/reviews/%d/comments?token=%s", review.ID, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var comments []*api.PullReviewComment
+	DecodeJSON(t, resp, &comments)
+	if assert.Len(t, comments, 1) {
+		assert.True(t, comments[0].CommitMessage)
+		assert.EqualValues(t, commitID, comments[0].CommitID)
+		assert.EqualValues(t, 1, comments[0].LineNum)
+		assert.Equal(t, "Fix the typo", comments[0].Body)
+	}
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls/3/reviews?token="+token, &api.CreatePullReviewOptions{
+		Event: api.ReviewStateComment,
+		Body:  "Some messages need work",
+		Comments: []api.CreatePullReviewComment{{
+			CommitID:   commitID,
+			NewLineNum: 1000,
+			Body:       "out of the message",
+		}},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -25,3 +25,20 @@ func TestPullCompare(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusOK)
 	assert.EqualValues(t, http.StatusOK, resp.Code)
 }
+
+func TestPullCompareUnrelatedRepos(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/compare/master...user12/repo10:master")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".ui.info.message", true)
+	htmlDoc.AssertElement(t, ".pullrequest-form", false)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/compare/master...user12/repo10:master", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"title": "Unrelated pull request",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
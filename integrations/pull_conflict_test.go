@@ -0,0 +1,120 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createConflictingPR(t *testing.T, user *models.User, repo *models.Repository) *models.PullRequest {
+	for _, commit := range []struct{ branch, content string }{
+		{"conflict", "line of the branch\n"},
+		{"master", "line of master\n"},
+	} {
+		_, err := repofiles.CreateOrUpdateRepoFile(repo, user, &repofiles.UpdateRepoFileOptions{
+			TreePath:  "conflict.txt",
+			Message:   "Add conflict.txt",
+			Content:   "first line\n" + commit.content,
+			IsNewFile: true,
+			OldBranch: "master",
+			NewBranch: commit.branch,
+		})
+		assert.NoError(t, err)
+	}
+
+	issue := &models.Issue{
+		RepoID:   repo.ID,
+		Title:    "Test conflicting pull",
+		PosterID: user.ID,
+		Poster:   user,
+		IsPull:   true,
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: repo.ID,
+		BaseRepoID: repo.ID,
+		HeadBranch: "conflict",
+		BaseBranch: "master",
+		HeadRepo:   repo,
+		BaseRepo:   repo,
+		Type:       models.PullRequestGitea,
+	}
+	assert.NoError(t, pull_service.NewPullRequest(repo, issue, nil, nil, pr, nil))
+	assert.NoError(t, pr.LoadIssue())
+	return pr
+}
+
+func TestPullConflicts(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		pr := createConflictingPR(t, user, repo)
+		link := fmt.Sprintf("/user2/repo1/pulls/%d/conflicts", pr.Issue.Index)
+
+		// readers can't resolve the conflicts
+		session := loginUser(t, "user4")
+		req := NewRequest(t, "GET", link)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		session = loginUser(t, "user2")
+		req = NewRequest(t, "GET", link)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		body := resp.Body.String()
+		assert.Contains(t, body, "conflict.txt")
+		assert.Contains(t, body, "&lt;&lt;&lt;&lt;&lt;&lt;&lt; conflict")
+		assert.Contains(t, body, "&gt;&gt;&gt;&gt;&gt;&gt;&gt; master")
+
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		headCommitID, _ := htmlDoc.Find(`input[name="head_commit_id"]`).Attr("value")
+		baseCommitID, _ := htmlDoc.Find(`input[name="base_commit_id"]`).Attr("value")
+		assert.NotEmpty(t, headCommitID)
+		assert.NotEmpty(t, baseCommitID)
+		values := map[string]string{
+			"_csrf":          htmlDoc.GetCSRF(),
+			"head_commit_id": headCommitID,
+			"base_commit_id": baseCommitID,
+			"resolution_0":   "edit",
+			"content_0":      htmlDoc.Find(`textarea[name="content_0"]`).Text(),
+		}
+
+		// the conflict markers must be removed
+		req = NewRequestWithValues(t, "POST", link, values)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "still has conflict markers")
+
+		values["content_0"] = "first line\r\nline of the branch\r\nline of master\r\n"
+		req = NewRequestWithValues(t, "POST", link, values)
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.Equal(t, strings.TrimSuffix(link, "/conflicts"), resp.Header().Get("Location"))
+
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		commit, err := gitRepo.GetBranchCommit("conflict")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{headCommitID, baseCommitID}, []string{commit.Parents[0].String(), commit.Parents[1].String()})
+		assert.Equal(t, "Merge branch 'master' into conflict\n", commit.CommitMessage)
+		entry, err := commit.GetTreeEntryByPath("conflict.txt")
+		assert.NoError(t, err)
+		content, err := entry.Blob().GetBlobContent()
+		assert.NoError(t, err)
+		assert.Equal(t, "first line\nline of the branch\nline of master\n", content)
+
+		// the branches have no conflicts anymore
+		req = NewRequest(t, "GET", link)
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.Equal(t, strings.TrimSuffix(link, "/conflicts"), resp.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullLazyDiff(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		defer func(lazyLoadDiffFiles int) {
+			setting.Git.LazyLoadDiffFiles = lazyLoadDiffFiles
+		}(setting.Git.LazyLoadDiffFiles)
+		setting.Git.LazyLoadDiffFiles = 1
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		branch := "master"
+		for _, treePath := range []string{"lazy/a.txt", "lazy/b.txt"} {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+treePath+"?token="+token, &api.CreateFileOptions{
+				FileOptions: api.FileOptions{
+					BranchName:    branch,
+					NewBranchName: "lazy",
+					Message:       "add " + treePath,
+				},
+				Content: base64.StdEncoding.EncodeToString([]byte("content of " + treePath + "\n")),
+			})
+			session.MakeRequest(t, req, http.StatusCreated)
+			branch = "lazy"
+		}
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &api.CreatePullRequestOption{
+			Head:  "lazy",
+			Base:  "master",
+			Title: "add lazy files",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pull api.PullRequest
+		DecodeJSON(t, resp, &pull)
+
+		// the files page lists the files without their diffs
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/pulls/%d/files", pull.Index))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		loaders := htmlDoc.doc.Find(".diff-file-loader")
+		assert.Equal(t, 2, loaders.Length())
+		assert.Equal(t, 0, htmlDoc.doc.Find(".code-inner").Length())
+		dataURL, _ := loaders.Eq(1).Attr("data-url")
+		assert.Contains(t, dataURL, fmt.Sprintf("/user2/repo1/pulls/%d/files/lazy/b.txt/diff?index=2", pull.Index))
+
+		// the diff of each file is loaded on its own
+		req = NewRequest(t, "GET", dataURL)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, "#diff-2.diff-file-box", true)
+		htmlDoc.AssertElement(t, ".diff-file-loader", false)
+		assert.Contains(t, htmlDoc.doc.Find(".code-inner").Text(), "content of lazy/b.txt")
+		assert.NotContains(t, htmlDoc.doc.Text(), "content of lazy/a.txt")
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/pulls/%d/files/README.md/diff", pull.Index))
+		session.MakeRequest(t, req, http.StatusNotFound)
+	})
+}
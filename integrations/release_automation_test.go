@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseAutomation(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/user2/repo1/settings/release_automation")
+		htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, "input[name=draft][checked]", true)
+
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/release_automation", map[string]string{
+			"_csrf":          htmlDoc.GetCSRF(),
+			"tag_pattern":    "v[1",
+			"title_template": "${RepoName} ${TagName}",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		models.AssertNotExistsBean(t, &models.ReleaseAutomation{RepoID: 1})
+
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/release_automation", map[string]string{
+			"_csrf":          htmlDoc.GetCSRF(),
+			"enabled":        "on",
+			"tag_pattern":    "v*",
+			"title_template": "${RepoName} ${TagName}",
+			"note_template":  "Tagged by ${PusherName}",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		automation := models.AssertExistsAndLoadBean(t, &models.ReleaseAutomation{RepoID: 1}).(*models.ReleaseAutomation)
+		assert.True(t, automation.Enabled)
+		assert.False(t, automation.Draft)
+		assert.EqualValues(t, 2, automation.DoerID)
+
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		doGitClone(dstPath, u)(t)
+
+		for _, tagName := range []string{"v3.0.0", "nightly"} {
+			_, err = git.NewCommand("tag", tagName).RunInDir(dstPath)
+			assert.NoError(t, err)
+			doGitPushTestRepository(dstPath, "origin", tagName)(t)
+		}
+
+		rel := models.AssertExistsAndLoadBean(t, &models.Release{RepoID: 1, TagName: "v3.0.0"}).(*models.Release)
+		assert.False(t, rel.IsTag)
+		assert.False(t, rel.IsDraft)
+		assert.Equal(t, "repo1 v3.0.0", rel.Title)
+		assert.Equal(t, "Tagged by user2", rel.Note)
+		models.AssertExistsAndLoadBean(t, &models.Release{RepoID: 1, TagName: "nightly", IsTag: true})
+	})
+}
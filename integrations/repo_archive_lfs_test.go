@@ -0,0 +1,100 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/repofiles"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readZipArchiveFile(t *testing.T, archive []byte, name string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		defer rc.Close()
+		content, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		return content
+	}
+	return nil
+}
+
+func readTarGzArchiveFile(t *testing.T, archive []byte, name string) []byte {
+	gzr, err := gzip.NewReader(bytes.NewReader(archive))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		assert.NoError(t, err)
+		if header.Name == name {
+			content, err := ioutil.ReadAll(tr)
+			assert.NoError(t, err)
+			return content
+		}
+	}
+}
+
+func TestRepoArchiveLFS(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		content := bytes.Repeat([]byte("large asset\n"), 200)
+		oid := storeObjectInRepo(t, repo.ID, &content)
+		defer repo.RemoveLFSMetaObjectByOid(oid)
+
+		pointer := (&models.LFSMetaObject{Oid: oid, Size: int64(len(content))}).Pointer()
+		resp, err := repofiles.CreateOrUpdateRepoFile(repo, user, &repofiles.UpdateRepoFileOptions{
+			TreePath:  "asset.bin",
+			Content:   pointer,
+			IsNewFile: true,
+		})
+		assert.NoError(t, err)
+		sha := resp.Commit.SHA
+
+		session := loginUser(t, user.Name)
+		req := NewRequest(t, "GET", "/user2/repo1")
+		htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+		htmlDoc.AssertElement(t, "input.archive-lfs", true)
+
+		req = NewRequest(t, "GET", "/user2/repo1/archive/"+sha+".zip")
+		archive := session.MakeRequest(t, req, http.StatusOK).Body.Bytes()
+		assert.Equal(t, pointer, string(readZipArchiveFile(t, archive, "repo1/asset.bin")))
+
+		req = NewRequest(t, "GET", "/user2/repo1/archive/"+sha+".zip?lfs=true")
+		archive = session.MakeRequest(t, req, http.StatusOK).Body.Bytes()
+		assert.Equal(t, content, readZipArchiveFile(t, archive, "repo1/asset.bin"))
+		assert.Equal(t, "# repo1\n\nDescription for repo1", string(readZipArchiveFile(t, archive, "repo1/README.md")))
+
+		req = NewRequest(t, "GET", "/user2/repo1/archive/"+sha+".tar.gz?lfs=true")
+		archive = session.MakeRequest(t, req, http.StatusOK).Body.Bytes()
+		assert.Equal(t, content, readTarGzArchiveFile(t, archive, "repo1/asset.bin"))
+
+		token := getTokenForLoggedInUser(t, session)
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/"+sha+".tar.gz?lfs=true&token="+token)
+		archive = session.MakeRequest(t, req, http.StatusOK).Body.Bytes()
+		assert.Equal(t, content, readTarGzArchiveFile(t, archive, "repo1/asset.bin"))
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCherryPick(t *testing.T, session *TestSession, link string, values map[string]string, status int) *httptest.ResponseRecorder {
+	req := NewRequest(t, "GET", link)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	values["_csrf"] = htmlDoc.GetCSRF()
+	values["last_commit"] = htmlDoc.GetInputValueByName("last_commit")
+	values["revert"] = htmlDoc.GetInputValueByName("revert")
+	values["commit_summary"] = htmlDoc.GetInputValueByName("commit_summary")
+	values["commit_message"] = htmlDoc.Find(`textarea[name="commit_message"]`).Text()
+
+	req = NewRequestWithValues(t, "POST", strings.TrimSuffix(link, "?revert=true"), values)
+	return session.MakeRequest(t, req, status)
+}
+
+func getBranchFile(t *testing.T, repo *models.Repository, branch, treePath string) (*git.Commit, string) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetBranchCommit(branch)
+	assert.NoError(t, err)
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if git.IsErrNotExist(err) {
+		return commit, ""
+	}
+	assert.NoError(t, err)
+	content, err := entry.Blob().GetBlobContent()
+	assert.NoError(t, err)
+	return commit, content
+}
+
+func TestCherryPick(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		resp, err := repofiles.CreateOrUpdateRepoFile(repo, user, &repofiles.UpdateRepoFileOptions{
+			TreePath:  "pick.txt",
+			Message:   "Add pick.txt",
+			Content:   "first line\n",
+			IsNewFile: true,
+			OldBranch: "master",
+			NewBranch: "pick",
+		})
+		assert.NoError(t, err)
+		link := "/user2/repo1/_cherrypick/" + resp.Commit.SHA + "/master"
+
+		// readers can't cherry-pick
+		session := loginUser(t, "user4")
+		req := NewRequest(t, "GET", link)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		// cherry-pick the commit onto master
+		session = loginUser(t, "user2")
+		testCherryPick(t, session, link, map[string]string{"commit_choice": "direct"}, http.StatusFound)
+		commit, content := getBranchFile(t, repo, "master", "pick.txt")
+		assert.Equal(t, "first line\n", content)
+		assert.Equal(t, "Add pick.txt\n\n(cherry picked from commit "+resp.Commit.SHA+")\n", commit.CommitMessage)
+
+		// a revert onto the protected branch is proposed by a pull request
+		csrf := GetCSRF(t, session, "/user2/repo1/settings/branches")
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/branches/master", map[string]string{
+			"_csrf":     csrf,
+			"protected": "on",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		revertResp := testCherryPick(t, session, "/user2/repo1/_cherrypick/"+commit.ID.String()+"/master?revert=true", map[string]string{
+			"commit_choice":   "direct",
+			"new_branch_name": "revert-pick",
+		}, http.StatusFound)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo.ID, HeadBranch: "revert-pick", BaseBranch: "master"}).(*models.PullRequest)
+		assert.NoError(t, pr.LoadIssue())
+		assert.Equal(t, fmt.Sprintf("/user2/repo1/pulls/%d", pr.Issue.Index), revertResp.Header().Get("Location"))
+		assert.Equal(t, `Revert "Add pick.txt"`, pr.Issue.Title)
+		_, content = getBranchFile(t, repo, "revert-pick", "pick.txt")
+		assert.Empty(t, content)
+
+		// the conflicts of a cherry-pick are resolved in the conflict editor
+		for _, commit := range []struct{ branch, content string }{
+			{"revert-pick", "line of the revert\n"},
+			{"pick", "line of the feature\n"},
+		} {
+			resp, err = repofiles.CreateOrUpdateRepoFile(repo, user, &repofiles.UpdateRepoFileOptions{
+				TreePath:  "pick.txt",
+				Message:   "Update pick.txt",
+				Content:   commit.content,
+				IsNewFile: commit.branch == "revert-pick",
+				OldBranch: commit.branch,
+				NewBranch: commit.branch,
+			})
+			assert.NoError(t, err)
+		}
+		link = "/user2/repo1/_cherrypick/" + resp.Commit.SHA + "/revert-pick"
+		conflictResp := testCherryPick(t, session, link, map[string]string{"commit_choice": "direct"}, http.StatusOK)
+		body := conflictResp.Body.String()
+		assert.Contains(t, body, "&lt;&lt;&lt;&lt;&lt;&lt;&lt; revert-pick")
+		htmlDoc := NewHTMLParser(t, conflictResp.Body)
+		req = NewRequestWithValues(t, "POST", link, map[string]string{
+			"_csrf":          htmlDoc.GetCSRF(),
+			"last_commit":    htmlDoc.GetInputValueByName("last_commit"),
+			"commit_summary": "Update pick.txt",
+			"commit_choice":  "direct",
+			"resolution_0":   "edit",
+			"content_0":      "line of the revert\nline of the feature\n",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		_, content = getBranchFile(t, repo, "revert-pick", "pick.txt")
+		assert.Equal(t, "line of the revert\nline of the feature\n", content)
+	})
+}
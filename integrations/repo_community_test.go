@@ -0,0 +1,73 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/options"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCreateCommunityFile(t *testing.T, session *TestSession, token, treePath string, content []byte) {
+	opts := getCreateFileOptions()
+	opts.Content = base64.StdEncoding.EncodeToString(content)
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+treePath+"?token="+token, &opts)
+	session.MakeRequest(t, req, http.StatusCreated)
+}
+
+func TestRepoCommunity(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var repo api.Repository
+		DecodeJSON(t, resp, &repo)
+		assert.Empty(t, repo.License)
+		assert.False(t, repo.HasCodeOfConduct)
+
+		mit, err := options.License("MIT")
+		assert.NoError(t, err)
+		testCreateCommunityFile(t, session, token, "LICENSE", mit)
+		testCreateCommunityFile(t, session, token, "docs/CODE_OF_CONDUCT.md", []byte("Be nice"))
+
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &repo)
+		assert.Equal(t, "MIT", repo.License)
+		assert.True(t, repo.HasCodeOfConduct)
+
+		var result api.SearchResults
+		req = NewRequest(t, "GET", "/api/v1/repos/search?license=MIT&has_code_of_conduct=true")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &result)
+		if assert.Len(t, result.Data, 1) {
+			assert.EqualValues(t, 1, result.Data[0].ID)
+		}
+
+		req = NewRequest(t, "GET", "/api/v1/repos/search?license=Apache-2.0")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &result)
+		assert.Empty(t, result.Data)
+
+		req = NewRequest(t, "GET", "/user2/repo1/community")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "MIT")
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.EqualValues(t, 1, htmlDoc.Find(`a[href="/user2/repo1/src/branch/master/LICENSE"]`).Length())
+		assert.EqualValues(t, 1, htmlDoc.Find(`a[href="/user2/repo1/src/branch/master/docs/CODE_OF_CONDUCT.md"]`).Length())
+
+		req = NewRequest(t, "GET", "/org/user3/community")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.NotZero(t, htmlDoc.Find(`a[href="/user3/repo3/community"]`).Length())
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestPNG(t *testing.T, path string, changed bool) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 200, B: 200, A: 255})
+		}
+	}
+	if changed {
+		img.SetNRGBA(1, 2, color.NRGBA{R: 0, G: 0, B: 0, A: 255})
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	assert.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func commitAndPushTestPNG(t *testing.T, dstPath string, changed bool) string {
+	writeTestPNG(t, filepath.Join(dstPath, "image.png"), changed)
+	assert.NoError(t, git.AddChanges(dstPath, true))
+	signature := git.Signature{Email: "user2@example.com", Name: "User Two", When: time.Now()}
+	assert.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{
+		Committer: &signature,
+		Author:    &signature,
+		Message:   "change image",
+	}))
+	_, err := git.NewCommand("push", "origin", "master").RunInDir(dstPath)
+	assert.NoError(t, err)
+	commitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+	assert.NoError(t, err)
+	return strings.TrimSpace(commitID)
+}
+
+func TestRepoImageDiff(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		dstPath, err := ioutil.TempDir("", "repo-image-diff")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		before := commitAndPushTestPNG(t, dstPath, false)
+		after := commitAndPushTestPNG(t, dstPath, true)
+		session := loginUser(t, "user2")
+
+		// the diff of the commit offers the modes of the diffs of images
+		req := NewRequest(t, "GET", "/user2/repo1/commit/"+after)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".image-diff-modes .item[data-mode=swipe]", true)
+		diffURL, _ := htmlDoc.doc.Find(".image-diff-difference").Attr("data-url")
+		assert.Equal(t, fmt.Sprintf("/user2/repo1/image-diff/%s/%s?path=image.png", before, after), diffURL)
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", diffURL), http.StatusOK)
+		assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "1", resp.Header().Get("X-Gitea-Changed-Pixels"))
+		img, err := png.Decode(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 4, 4), img.Bounds())
+
+		// the files which aren't images can't be compared
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/image-diff/%s/%s?path=README.md", before, after))
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/image-diff/%s/%s?path=missing.png", before, after))
+		session.MakeRequest(t, req, http.StatusNotFound)
+	})
+}
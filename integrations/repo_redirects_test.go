@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoRedirects(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		content := "refs:\n" +
+			"  - name: old-master\n" +
+			"    to: master\n" +
+			"  - name: removed\n" +
+			"    message: The removed branch was merged.\n" +
+			"paths:\n" +
+			"  - path: docs/README.md\n" +
+			"    to: README.md\n" +
+			"  - path: docs/removed/\n" +
+			"    message: The documentation has moved to the wiki.\n"
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/.gitea/redirects.yml?token="+token, &api.CreateFileOptions{
+			FileOptions: api.FileOptions{
+				BranchName: "master",
+				Message:    "Add redirects",
+			},
+			Content: base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		// paths are redirected permanently
+		req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/docs/README.md")
+		resp := session.MakeRequest(t, req, http.StatusMovedPermanently)
+		assert.Equal(t, "/user2/repo1/src/branch/master/README.md", resp.Header().Get("Location"))
+		req = NewRequest(t, "GET", "/user2/repo1/raw/branch/master/docs/README.md")
+		resp = session.MakeRequest(t, req, http.StatusMovedPermanently)
+		assert.Equal(t, "/user2/repo1/raw/branch/master/README.md", resp.Header().Get("Location"))
+
+		req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/docs/removed/install.md")
+		resp = session.MakeRequest(t, req, http.StatusNotFound)
+		assert.Contains(t, resp.Body.String(), "The documentation has moved to the wiki.")
+
+		// refs are redirected temporarily
+		req = NewRequest(t, "GET", "/user2/repo1/src/branch/old-master/README.md")
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.Equal(t, "/user2/repo1/src/branch/master/README.md", resp.Header().Get("Location"))
+
+		req = NewRequest(t, "GET", "/user2/repo1/src/branch/removed")
+		resp = session.MakeRequest(t, req, http.StatusNotFound)
+		assert.Contains(t, resp.Body.String(), "The removed branch was merged.")
+
+		// other missing paths aren't changed
+		req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/missing.md")
+		resp = session.MakeRequest(t, req, http.StatusNotFound)
+		assert.NotContains(t, resp.Body.String(), "ui info message")
+	})
+}
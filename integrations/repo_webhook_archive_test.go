@@ -0,0 +1,48 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoWebhookArchiveRestore(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	assert.NoError(t, webhook.CleanupHookTasks(context.Background(), time.Hour, true, 0))
+	models.AssertNotExistsBean(t, &models.HookTask{ID: 1})
+	archives, err := models.GetHookTaskArchives(1)
+	assert.NoError(t, err)
+	if !assert.Len(t, archives, 1) {
+		return
+	}
+
+	session := loginUser(t, "user2")
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/settings/hooks/1"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find("#hook-task-archives .item").Length())
+
+	link := fmt.Sprintf("/user2/repo1/settings/hooks/1/archives/%d/restore", archives[0].ID)
+	req := NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.HookTask{ID: 1, UUID: "uuid1"})
+	models.AssertNotExistsBean(t, &models.HookTaskArchive{ID: archives[0].ID})
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
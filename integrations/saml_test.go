@@ -0,0 +1,192 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	giteasaml "code.gitea.io/gitea/modules/auth/saml"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+)
+
+// samlTestIdentityProvider is an identity provider trusting the service provider of its metadata
+type samlTestIdentityProvider struct {
+	*saml.IdentityProvider
+	serviceProvider *saml.EntityDescriptor
+}
+
+func (idp *samlTestIdentityProvider) GetServiceProvider(r *http.Request, serviceProviderID string) (*saml.EntityDescriptor, error) {
+	return idp.serviceProvider, nil
+}
+
+func newSAMLTestIdentityProvider(t *testing.T) *samlTestIdentityProvider {
+	certPEM, keyPEM, err := giteasaml.GenerateKeyPair("idp")
+	assert.NoError(t, err)
+	certBlock, _ := pem.Decode([]byte(certPEM))
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	assert.NoError(t, err)
+	keyBlock, _ := pem.Decode([]byte(keyPEM))
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	assert.NoError(t, err)
+
+	idp := &samlTestIdentityProvider{
+		IdentityProvider: &saml.IdentityProvider{
+			Key:         key,
+			Certificate: cert,
+			MetadataURL: url.URL{Scheme: "https", Host: "idp.example.com", Path: "/metadata"},
+			SSOURL:      url.URL{Scheme: "https", Host: "idp.example.com", Path: "/sso"},
+			LogoutURL:   url.URL{Scheme: "https", Host: "idp.example.com", Path: "/slo"},
+		},
+	}
+	idp.ServiceProviderProvider = idp
+	return idp
+}
+
+// signIn answers the authentication request of the redirect to the identity provider with the assertion of the session
+func (idp *samlTestIdentityProvider) signIn(t *testing.T, redirect string, session *saml.Session) map[string]string {
+	authnRequest, err := saml.NewIdpAuthnRequest(idp.IdentityProvider, httptest.NewRequest("GET", redirect, nil))
+	assert.NoError(t, err)
+	assert.NoError(t, authnRequest.Validate())
+	assert.NoError(t, saml.DefaultAssertionMaker{}.MakeAssertion(authnRequest, session))
+	assert.NoError(t, authnRequest.MakeAssertionEl())
+	assert.NoError(t, authnRequest.MakeResponse())
+
+	doc := etree.NewDocument()
+	doc.SetRoot(authnRequest.ResponseEl)
+	response, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return map[string]string{
+		"SAMLResponse": base64.StdEncoding.EncodeToString(response),
+		"RelayState":   authnRequest.RelayState,
+	}
+}
+
+// logoutRequest returns the signed logout request of the name ID to the single logout URL
+func (idp *samlTestIdentityProvider) logoutRequest(t *testing.T, sloURL, nameID string) string {
+	issuer := &saml.ServiceProvider{
+		EntityID:        idp.MetadataURL.String(),
+		Key:             idp.Key.(*rsa.PrivateKey),
+		Certificate:     idp.Certificate,
+		IDPMetadata:     idp.serviceProvider,
+		SignatureMethod: dsig.RSASHA256SignatureMethod,
+	}
+	req, err := issuer.MakeLogoutRequest(sloURL, nameID)
+	assert.NoError(t, err)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.Element())
+	data, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestSAMLSignIn(t *testing.T) {
+	defer prepareTestEnv(t)()
+	idp := newSAMLTestIdentityProvider(t)
+	idpMetadata, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+
+	adminSession := loginUser(t, "user1")
+	csrf := GetCSRF(t, adminSession, "/admin/auths/new")
+	req := NewRequestWithValues(t, "POST", "/admin/auths/new", map[string]string{
+		"_csrf":                           csrf,
+		"type":                            "8",
+		"name":                            "saml",
+		"saml_identity_provider_metadata": string(idpMetadata),
+		"saml_username_attribute":         "uid",
+		"saml_email_attribute":            "eduPersonPrincipalName",
+		"saml_groups_attribute":           "eduPersonAffiliation",
+		"saml_group_team_map":             `{"developers": {"user3": ["team1"]}}`,
+		"saml_auto_create_users":          "on",
+		"is_active":                       "on",
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+
+	// the identity provider is registered with the metadata of the service provider
+	req = NewRequest(t, "GET", "/user/saml/saml/metadata")
+	resp := MakeRequest(t, req, http.StatusOK)
+	idp.serviceProvider = new(saml.EntityDescriptor)
+	assert.NoError(t, xml.Unmarshal(resp.Body.Bytes(), idp.serviceProvider))
+	if assert.Len(t, idp.serviceProvider.SPSSODescriptors, 1) {
+		assert.Equal(t, setting.AppURL+"user/saml/saml/acs", idp.serviceProvider.SPSSODescriptors[0].AssertionConsumerServices[0].Location)
+	}
+
+	// the sign-in page links to the source
+	req = NewRequest(t, "GET", "/user/login")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "/user/saml/saml")
+
+	signIn := func() *TestSession {
+		session := emptyTestSession(t)
+		req := NewRequest(t, "GET", "/user/saml/saml")
+		resp := session.MakeRequest(t, req, http.StatusFound)
+		redirect := resp.Header().Get("Location")
+		assert.True(t, strings.HasPrefix(redirect, idp.SSOURL.String()+"?"))
+
+		values := idp.signIn(t, redirect, &saml.Session{
+			ID:        "id-1",
+			Index:     "session-1",
+			NameID:    "jdoe-id",
+			UserName:  "jdoe",
+			UserEmail: "jdoe@example.com",
+			Groups:    []string{"developers"},
+		})
+		req = NewRequestWithValues(t, "POST", "/user/saml/saml/acs", values)
+		resp = session.MakeRequest(t, req, http.StatusFound)
+		assert.Equal(t, "/", resp.Header().Get("Location"))
+		return session
+	}
+
+	session := signIn()
+	req = NewRequest(t, "GET", "/user/settings")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{Name: "jdoe"}).(*models.User)
+	assert.Equal(t, models.LoginSAML, user.LoginType)
+	assert.Equal(t, "jdoe-id", user.LoginName)
+	assert.Equal(t, "jdoe@example.com", user.Email)
+	models.AssertExistsAndLoadBean(t, &models.TeamUser{TeamID: 2, UID: user.ID})
+
+	// the invalid responses are rejected
+	req = NewRequestWithValues(t, "POST", "/user/saml/saml/acs", map[string]string{"SAMLResponse": "invalid"})
+	resp = emptyTestSession(t).MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user/login", resp.Header().Get("Location"))
+
+	// the single logout of the identity provider ends the session, it's answered with the binding it supports
+	req = NewRequestWithValues(t, "POST", "/user/saml/saml/slo", map[string]string{
+		"SAMLRequest": idp.logoutRequest(t, setting.AppURL+"user/saml/saml/slo", "jdoe-id"),
+	})
+	resp = MakeRequest(t, req, http.StatusFound)
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Location"), idp.LogoutURL.String()+"?SAMLResponse="))
+	models.AssertNotExistsBean(t, &models.SAMLSession{UserID: user.ID})
+
+	req = NewRequest(t, "GET", "/user/settings")
+	session.MakeRequest(t, req, http.StatusFound)
+
+	// the users signing out are signed out of the identity provider
+	session = signIn()
+	models.AssertExistsAndLoadBean(t, &models.SAMLSession{UserID: user.ID, SessionIndex: "session-1"})
+	req = NewRequestWithValues(t, "POST", "/user/logout", map[string]string{
+		"_csrf": GetCSRF(t, session, "/user/settings"),
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Location"), idp.LogoutURL.String()+"?"))
+	models.AssertNotExistsBean(t, &models.SAMLSession{UserID: user.ID})
+}
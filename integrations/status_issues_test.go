@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/settings/status_issues")
+	htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/status_issues", map[string]string{
+		"_csrf":           htmlDoc.GetCSRF(),
+		"enabled":         "on",
+		"context_pattern": "ci/[",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.StatusIssueRule{RepoID: 1})
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/status_issues", map[string]string{
+		"_csrf":           htmlDoc.GetCSRF(),
+		"enabled":         "on",
+		"context_pattern": "ci/*",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	rule := models.AssertExistsAndLoadBean(t, &models.StatusIssueRule{RepoID: 1}).(*models.StatusIssueRule)
+	assert.True(t, rule.Enabled)
+	assert.EqualValues(t, 2, rule.DoerID)
+
+	token := getTokenForLoggedInUser(t, session)
+	createStatus := func(state api.StatusState) {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/statuses/65f1bf27bc3bf70f64657658635e66094edbcb4d?token="+token, &api.CreateStatusOption{
+			State:   state,
+			Context: "ci/build",
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+	}
+
+	createStatus(api.StatusFailure)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, Title: "ci/build fails on master"}).(*models.Issue)
+	assert.False(t, issue.IsClosed)
+
+	createStatus(api.StatusSuccess)
+	issue = models.AssertExistsAndLoadBean(t, &models.Issue{ID: issue.ID}).(*models.Issue)
+	assert.True(t, issue.IsClosed)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unknwon/i18n"
+)
+
+func TestSystemWebhookInstanceEvents(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	hook := &models.Webhook{
+		IsSystemWebhook: true,
+		URL:             "http://127.0.0.1:1/system",
+		ContentType:     models.ContentTypeJSON,
+		HookEvent: &models.HookEvent{
+			ChooseEvents: true,
+			HookEvents: models.HookEvents{
+				User:         true,
+				Organization: true,
+				AuthFailure:  true,
+			},
+		},
+		IsActive:     true,
+		HookTaskType: models.GITEA,
+	}
+	assert.NoError(t, hook.UpdateEvent())
+	assert.NoError(t, models.CreateWebhook(hook))
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithValues(t, "POST", "/api/v1/admin/users?token="+token, map[string]string{
+		"email":                "hooked@example.com",
+		"username":             "hooked",
+		"password":             "password",
+		"must_change_password": "false",
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+	req = NewRequest(t, "DELETE", "/api/v1/admin/users/hooked?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	assert.Equal(t, 2, models.GetCount(t, &models.HookTask{HookID: hook.ID, EventType: models.HookEventUser}))
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs?token="+token, map[string]string{
+		"username": "hooked_org",
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+	models.AssertExistsAndLoadBean(t, &models.HookTask{HookID: hook.ID, EventType: models.HookEventOrganization})
+
+	testLoginFailed(t, "user2", "wrongPassword", i18n.Tr("en", "form.username_password_incorrect"))
+	models.AssertExistsAndLoadBean(t, &models.HookTask{RepoID: 0, HookID: hook.ID, EventType: models.HookEventAuthFailure})
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorPolicy(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(requirement string, gracePeriod time.Duration) {
+		setting.TwoFactor.Requirement = requirement
+		setting.TwoFactor.GracePeriod = gracePeriod
+	}(setting.TwoFactor.Requirement, setting.TwoFactor.GracePeriod)
+	setting.TwoFactor.Requirement = "all"
+	setting.TwoFactor.GracePeriod = 24 * time.Hour
+
+	// the users are warned during the grace period, new sessions are used as they remember the compliance
+	session := loginUserWithPassword(t, "user4", userPassword)
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "/user/settings/security\">Enroll</a>")
+	MakeRequest(t, AddBasicAuthHeader(NewRequest(t, "GET", "/api/v1/user"), "user4"), http.StatusOK)
+
+	// then they are only allowed to enroll
+	setting.TwoFactor.GracePeriod = 0
+	session = loginUserWithPassword(t, "user2", userPassword)
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+	assert.EqualValues(t, "/user/settings/security", resp.Header().Get("Location"))
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/settings/security"), http.StatusOK)
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/settings/security/two_factor/enroll"), http.StatusOK)
+
+	// nor can they authenticate with their password on the API and git over HTTP, e.g. to create an access token
+	MakeRequest(t, AddBasicAuthHeader(NewRequest(t, "GET", "/api/v1/user"), "user2"), http.StatusForbidden)
+	req := NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", map[string]string{"name": "test-two-factor-basic"})
+	MakeRequest(t, AddBasicAuthHeader(req, "user2"), http.StatusForbidden)
+	models.AssertNotExistsBean(t, &models.AccessToken{UID: 2, Name: "test-two-factor-basic"})
+	MakeRequest(t, AddBasicAuthHeader(NewRequest(t, "GET", "/user2/repo16.git/info/refs?service=git-upload-pack"), "user2"), http.StatusUnauthorized)
+
+	// the compliance is available from the API, which is usable with access tokens
+	token := &models.AccessToken{UID: 2, Name: "test-two-factor"}
+	assert.NoError(t, models.NewAccessToken(token))
+	req = NewRequest(t, "GET", "/user2/repo16.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth("user2", token.Token)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.SetBasicAuth("user2", token.Token)
+	MakeRequest(t, req, http.StatusOK)
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/user/two_factor_status?token="+token.Token), http.StatusOK)
+	status := new(api.TwoFactorStatus)
+	DecodeJSON(t, resp, status)
+	assert.True(t, status.Required)
+	assert.True(t, status.RequiredByInstance)
+	assert.False(t, status.Enrolled)
+	assert.True(t, status.Enforced)
+	assert.NotNil(t, status.GraceDeadline)
+	assert.Empty(t, status.RequiredByOrgs)
+
+	adminToken := &models.AccessToken{UID: 1, Name: "test-two-factor"}
+	assert.NoError(t, models.NewAccessToken(adminToken))
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/users/user24/two_factor_status?token="+adminToken.Token), http.StatusOK)
+	status = new(api.TwoFactorStatus)
+	DecodeJSON(t, resp, status)
+	assert.True(t, status.Enrolled)
+	assert.True(t, status.Compliant)
+	assert.False(t, status.Enforced)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/users/user3/two_factor_status?token="+adminToken.Token), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/users/user24/two_factor_status?token="+token.Token), http.StatusForbidden)
+}
@@ -71,7 +71,10 @@ arguments - which can alternatively be run by running the subcommand web.`
 		cmd.CmdManager,
 		cmd.Cmdembedded,
 		cmd.CmdMigrateStorage,
+		cmd.CmdDumpRepo,
+		cmd.CmdRestoreRepo,
 		cmd.CmdDocs,
+		cmd.CmdSigning,
 	}
 	// Now adjust these commands to add our global configuration options
 
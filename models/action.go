@@ -19,6 +19,7 @@ import (
 
 	"github.com/unknwon/com"
 	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // ActionType represents the type of an action.
@@ -72,6 +73,13 @@ type Action struct {
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 }
 
+// BeforeInsert will be invoked by XORM before inserting a record
+func (a *Action) BeforeInsert() {
+	if a.ID == 0 {
+		a.ID = nextHighWriteID()
+	}
+}
+
 // GetOpType gets the ActionType of this action.
 func (a *Action) GetOpType() ActionType {
 	return a.OpType
@@ -293,6 +301,7 @@ type GetFeedsOptions struct {
 	IncludePrivate  bool  // include private actions
 	OnlyPerformedBy bool  // only actions performed by requested user
 	IncludeDeleted  bool  // include deleted actions
+	UsePrimary      bool  // read from the primary even if a replica of the database is available
 }
 
 // GetFeeds returns actions according to the provided options
@@ -341,7 +350,9 @@ func GetFeeds(opts GetFeedsOptions) ([]*Action, error) {
 
 	actions := make([]*Action, 0, setting.UI.FeedPagingNum)
 
-	if err := x.Limit(setting.UI.FeedPagingNum).Desc("id").Where(cond).Find(&actions); err != nil {
+	if err := withReadEngine(opts.UsePrimary, func(e *xorm.Engine) error {
+		return e.Limit(setting.UI.FeedPagingNum).Desc("id").Where(cond).Find(&actions)
+	}); err != nil {
 		return nil, fmt.Errorf("Find: %v", err)
 	}
 
@@ -258,19 +258,19 @@ func DeleteAttachmentsByRelease(releaseID int64) error {
 	return err
 }
 
-// IterateAttachment iterates attachments; it should not be used when Gitea is servicing users.
+// IterateAttachment iterates attachments by their ids, the attachments added while iterating are iterated too
 func IterateAttachment(f func(attach *Attachment) error) error {
-	var start int
+	var lastID int64
 	const batchSize = 100
 	for {
 		var attachments = make([]*Attachment, 0, batchSize)
-		if err := x.Limit(batchSize, start).Find(&attachments); err != nil {
+		if err := x.Where("id > ?", lastID).OrderBy("id").Limit(batchSize).Find(&attachments); err != nil {
 			return err
 		}
 		if len(attachments) == 0 {
 			return nil
 		}
-		start += len(attachments)
+		lastID = attachments[len(attachments)-1].ID
 
 		for _, attach := range attachments {
 			if err := f(attach); err != nil {
@@ -0,0 +1,148 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AuditAction represents the kind of a security-relevant event recorded in the audit log
+type AuditAction string
+
+// The actions recorded in the audit log
+const (
+	AuditActionUserLogin              AuditAction = "user_login"
+	AuditActionUserLoginFailed        AuditAction = "user_login_failed"
+	AuditActionAdminUserCreate        AuditAction = "admin_user_create"
+	AuditActionAdminUserEdit          AuditAction = "admin_user_edit"
+	AuditActionAdminUserDelete        AuditAction = "admin_user_delete"
+	AuditActionCollaboratorAdd        AuditAction = "collaborator_add"
+	AuditActionCollaboratorChangeMode AuditAction = "collaborator_change_mode"
+	AuditActionCollaboratorRemove     AuditAction = "collaborator_remove"
+	AuditActionTeamMemberAdd          AuditAction = "team_member_add"
+	AuditActionTeamMemberRemove       AuditAction = "team_member_remove"
+	AuditActionRepoTransfer           AuditAction = "repo_transfer"
+	AuditActionDeployKeyAdd           AuditAction = "deploy_key_add"
+	AuditActionDeployKeyRemove        AuditAction = "deploy_key_remove"
+	AuditActionWebhookCreate          AuditAction = "webhook_create"
+	AuditActionWebhookEdit            AuditAction = "webhook_edit"
+	AuditActionWebhookDelete          AuditAction = "webhook_delete"
+)
+
+// AuditActions are all the actions recorded in the audit log
+var AuditActions = []AuditAction{
+	AuditActionUserLogin,
+	AuditActionUserLoginFailed,
+	AuditActionAdminUserCreate,
+	AuditActionAdminUserEdit,
+	AuditActionAdminUserDelete,
+	AuditActionCollaboratorAdd,
+	AuditActionCollaboratorChangeMode,
+	AuditActionCollaboratorRemove,
+	AuditActionTeamMemberAdd,
+	AuditActionTeamMemberRemove,
+	AuditActionRepoTransfer,
+	AuditActionDeployKeyAdd,
+	AuditActionDeployKeyRemove,
+	AuditActionWebhookCreate,
+	AuditActionWebhookEdit,
+	AuditActionWebhookDelete,
+}
+
+// The types of the targets of the audit events
+const (
+	AuditTargetUser         = "user"
+	AuditTargetOrganization = "organization"
+	AuditTargetRepository   = "repository"
+	AuditTargetInstance     = "instance"
+)
+
+// AuditEvent represents a security-relevant event recorded in the audit log, the events are never
+// changed once recorded and only deleted when they are older than the retention of the log
+type AuditEvent struct {
+	ID     int64       `xorm:"pk autoincr"`
+	Action AuditAction `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	// DoerID is 0 if the event isn't done by a known user, like a failed login
+	DoerID    int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	DoerName  string `xorm:"NOT NULL"`
+	IPAddress string `xorm:"VARCHAR(50)"`
+	// TargetType, TargetID and TargetName are the type, the ID and the name at the time of the event of
+	// the user, organization or repository the event applies to
+	TargetType  string             `xorm:"VARCHAR(20) INDEX NOT NULL"`
+	TargetID    int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	TargetName  string             `xorm:"NOT NULL"`
+	Description string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// CreateAuditEvent records an event in the audit log
+func CreateAuditEvent(event *AuditEvent) error {
+	_, err := x.Insert(event)
+	return err
+}
+
+// FindAuditEventsOptions represents the filters of the audit events, the zero values are ignored
+type FindAuditEventsOptions struct {
+	ListOptions
+	Action     AuditAction
+	DoerID     int64
+	DoerName   string
+	TargetType string
+	TargetID   int64
+	Since      timeutil.TimeStamp
+	Before     timeutil.TimeStamp
+}
+
+func (opts *FindAuditEventsOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.Action) > 0 {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	if opts.DoerID != 0 {
+		cond = cond.And(builder.Eq{"doer_id": opts.DoerID})
+	}
+	if len(opts.DoerName) > 0 {
+		cond = cond.And(builder.Eq{"doer_name": opts.DoerName})
+	}
+	if len(opts.TargetType) > 0 {
+		cond = cond.And(builder.Eq{"target_type": opts.TargetType})
+	}
+	if opts.TargetID != 0 {
+		cond = cond.And(builder.Eq{"target_id": opts.TargetID})
+	}
+	if opts.Since != 0 {
+		cond = cond.And(builder.Gte{"created_unix": opts.Since})
+	}
+	if opts.Before != 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.Before})
+	}
+	return cond
+}
+
+// FindAuditEvents returns a page of the audit events matching the options, the latest first, and
+// the number of matching events
+func FindAuditEvents(opts *FindAuditEventsOptions) ([]*AuditEvent, int64, error) {
+	count, err := x.Where(opts.toCond()).Count(new(AuditEvent))
+	if err != nil {
+		return nil, 0, err
+	}
+	events := make([]*AuditEvent, 0, opts.PageSize)
+	sess := opts.setSessionPagination(x.Where(opts.toCond()).Desc("created_unix", "id"))
+	return events, count, sess.Find(&events)
+}
+
+// IterateAuditEvents calls f for all the audit events matching the options, the oldest first
+func IterateAuditEvents(opts *FindAuditEventsOptions, f func(event *AuditEvent) error) error {
+	return x.Where(opts.toCond()).Asc("created_unix", "id").Iterate(new(AuditEvent), func(_ int, bean interface{}) error {
+		return f(bean.(*AuditEvent))
+	})
+}
+
+// DeleteAuditEventsBefore deletes the audit events recorded before a time and returns their number
+func DeleteAuditEventsBefore(before timeutil.TimeStamp) (int64, error) {
+	return x.Where("created_unix < ?", before).Delete(new(AuditEvent))
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEvents(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	login := &AuditEvent{Action: AuditActionUserLogin, DoerID: 2, DoerName: "user2", TargetType: AuditTargetUser, TargetID: 2, TargetName: "user2"}
+	assert.NoError(t, CreateAuditEvent(login))
+	collaborator := &AuditEvent{Action: AuditActionCollaboratorAdd, DoerID: 2, DoerName: "user2", TargetType: AuditTargetRepository, TargetID: 1, TargetName: "user2/repo1"}
+	assert.NoError(t, CreateAuditEvent(collaborator))
+	failed := &AuditEvent{Action: AuditActionUserLoginFailed, TargetType: AuditTargetUser, TargetID: 4, TargetName: "user4"}
+	assert.NoError(t, CreateAuditEvent(failed))
+	_, err := x.Exec("UPDATE audit_event SET created_unix = ? WHERE id = ?", 1000, login.ID)
+	assert.NoError(t, err)
+
+	events, count, err := FindAuditEvents(&FindAuditEventsOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, failed.ID, events[0].ID)
+		assert.Equal(t, login.ID, events[2].ID)
+	}
+
+	events, count, err = FindAuditEvents(&FindAuditEventsOptions{ListOptions: ListOptions{Page: 2, PageSize: 2}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, login.ID, events[0].ID)
+	}
+
+	events, count, err = FindAuditEvents(&FindAuditEventsOptions{DoerName: "user2", TargetType: AuditTargetRepository})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, collaborator.ID, events[0].ID)
+	}
+
+	events, _, err = FindAuditEvents(&FindAuditEventsOptions{Action: AuditActionUserLoginFailed, TargetID: 4})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, _, err = FindAuditEvents(&FindAuditEventsOptions{Before: 2000})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, login.ID, events[0].ID)
+	}
+
+	var ids []int64
+	assert.NoError(t, IterateAuditEvents(&FindAuditEventsOptions{Since: 2000}, func(event *AuditEvent) error {
+		ids = append(ids, event.ID)
+		return nil
+	}))
+	assert.Equal(t, []int64{collaborator.ID, failed.ID}, ids)
+
+	deleted, err := DeleteAuditEventsBefore(timeutil.TimeStamp(2000))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	AssertNotExistsBean(t, &AuditEvent{ID: login.ID})
+	AssertExistsAndLoadBean(t, &AuditEvent{ID: failed.ID})
+}
@@ -7,6 +7,7 @@ package models
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -26,24 +27,31 @@ type ProtectedBranch struct {
 	BranchName                    string `xorm:"UNIQUE(s)"`
 	CanPush                       bool   `xorm:"NOT NULL DEFAULT false"`
 	EnableWhitelist               bool
-	WhitelistUserIDs              []int64  `xorm:"JSON TEXT"`
-	WhitelistTeamIDs              []int64  `xorm:"JSON TEXT"`
-	EnableMergeWhitelist          bool     `xorm:"NOT NULL DEFAULT false"`
-	WhitelistDeployKeys           bool     `xorm:"NOT NULL DEFAULT false"`
-	MergeWhitelistUserIDs         []int64  `xorm:"JSON TEXT"`
-	MergeWhitelistTeamIDs         []int64  `xorm:"JSON TEXT"`
-	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
-	StatusCheckContexts           []string `xorm:"JSON TEXT"`
-	EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
-	ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
-	ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
-	RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
-	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
-	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
-	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
-	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
-	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
-	ProtectedFilePatterns         string   `xorm:"TEXT"`
+	WhitelistUserIDs              []int64              `xorm:"JSON TEXT"`
+	WhitelistTeamIDs              []int64              `xorm:"JSON TEXT"`
+	EnableMergeWhitelist          bool                 `xorm:"NOT NULL DEFAULT false"`
+	WhitelistDeployKeys           bool                 `xorm:"NOT NULL DEFAULT false"`
+	MergeWhitelistUserIDs         []int64              `xorm:"JSON TEXT"`
+	MergeWhitelistTeamIDs         []int64              `xorm:"JSON TEXT"`
+	EnableStatusCheck             bool                 `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts           []string             `xorm:"JSON TEXT"`
+	EnableApprovalsWhitelist      bool                 `xorm:"NOT NULL DEFAULT false"`
+	ApprovalsWhitelistUserIDs     []int64              `xorm:"JSON TEXT"`
+	ApprovalsWhitelistTeamIDs     []int64              `xorm:"JSON TEXT"`
+	RequiredApprovals             int64                `xorm:"NOT NULL DEFAULT 0"`
+	BlockOnRejectedReviews        bool                 `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOfficialReviewRequests bool                 `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOutdatedBranch         bool                 `xorm:"NOT NULL DEFAULT false"`
+	DismissStaleApprovals         bool                 `xorm:"NOT NULL DEFAULT false"`
+	RequireSignedCommits          bool                 `xorm:"NOT NULL DEFAULT false"`
+	ProtectedFilePatterns         string               `xorm:"TEXT"`
+	EnableMergeQueue              bool                 `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool                 `xorm:"NOT NULL DEFAULT false"`
+	RequireLinearHistory          bool                 `xorm:"NOT NULL DEFAULT false"`
+	DismissApprovalsOnPush        bool                 `xorm:"NOT NULL DEFAULT false"`
+	DismissApprovalsOnForcePush   bool                 `xorm:"NOT NULL DEFAULT false"`
+	ProtectedPathRules            []*ProtectedPathRule `xorm:"JSON TEXT"`
+	RequireSignOff                bool                 `xorm:"NOT NULL DEFAULT false"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
@@ -171,6 +179,14 @@ func (protectBranch *ProtectedBranch) GetGrantedApprovalsCount(pr *PullRequest)
 	return approvals
 }
 
+// IsMergeStyleAllowed returns if the merge style keeps the history of the branch linear when it is required
+func (protectBranch *ProtectedBranch) IsMergeStyleAllowed(mergeStyle MergeStyle) bool {
+	if !protectBranch.RequireLinearHistory {
+		return true
+	}
+	return mergeStyle != MergeStyleMerge && mergeStyle != MergeStyleRebaseMerge
+}
+
 // MergeBlockedByRejectedReview returns true if merge is blocked by rejected reviews
 func (protectBranch *ProtectedBranch) MergeBlockedByRejectedReview(pr *PullRequest) bool {
 	if !protectBranch.BlockOnRejectedReviews {
@@ -259,6 +275,62 @@ func (protectBranch *ProtectedBranch) IsProtectedFile(patterns []glob.Glob, path
 	return r
 }
 
+// ProtectedPathRule requires the changes of the files matching a path pattern to be approved by a member
+// of a team before they are merged into a protected branch
+type ProtectedPathRule struct {
+	Pattern string `json:"pattern"`
+	TeamID  int64  `json:"team_id"`
+
+	glob glob.Glob
+}
+
+// NewProtectedPathRule returns a rule requiring the changes of the files matching a pattern to be approved by
+// a team of the organization owning a repository, the team must have access to the repository
+func NewProtectedPathRule(repo *Repository, pattern, teamName string) (*ProtectedPathRule, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, ErrInvalidProtectedPathRule{Pattern: pattern, Reason: "the pattern is empty"}
+	}
+	if _, err := glob.Compile(pattern, '/'); err != nil {
+		return nil, ErrInvalidProtectedPathRule{Pattern: pattern, Reason: err.Error()}
+	}
+	if err := repo.GetOwner(); err != nil {
+		return nil, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return nil, ErrInvalidProtectedPathRule{Pattern: pattern, Reason: "only the repositories of organizations have teams"}
+	}
+	team, err := GetTeam(repo.OwnerID, teamName)
+	if err != nil {
+		if IsErrTeamNotExist(err) {
+			return nil, ErrInvalidProtectedPathRule{Pattern: pattern, Reason: fmt.Sprintf("team %s does not exist", teamName)}
+		}
+		return nil, err
+	}
+	if !team.HasRepository(repo.ID) {
+		return nil, ErrInvalidProtectedPathRule{Pattern: pattern, Reason: fmt.Sprintf("team %s has no access to the repository", teamName)}
+	}
+	return &ProtectedPathRule{Pattern: pattern, TeamID: team.ID}, nil
+}
+
+// Match returns if a path matches the pattern of the rule, case insensitively.
+// A pattern without a slash also matches the files of any directory by their names, e.g. *.tf
+func (rule *ProtectedPathRule) Match(treePath string) bool {
+	if rule.glob == nil {
+		g, err := glob.Compile(strings.ToLower(rule.Pattern), '/')
+		if err != nil {
+			log.Info("Invalid glob expresion '%s' (skipped): %v", rule.Pattern, err)
+			return false
+		}
+		rule.glob = g
+	}
+	treePath = strings.ToLower(treePath)
+	if rule.glob.Match(treePath) {
+		return true
+	}
+	return !strings.Contains(rule.Pattern, "/") && rule.glob.Match(path.Base(treePath))
+}
+
 // GetProtectedBranchByRepoID getting protected branch by repo ID
 func GetProtectedBranchByRepoID(repoID int64) ([]*ProtectedBranch, error) {
 	protectedBranches := make([]*ProtectedBranch, 0)
@@ -78,3 +78,53 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestProtectedBranch_IsMergeStyleAllowed(t *testing.T) {
+	protectBranch := &ProtectedBranch{}
+	for _, style := range []MergeStyle{MergeStyleMerge, MergeStyleRebase, MergeStyleRebaseMerge, MergeStyleSquash} {
+		assert.True(t, protectBranch.IsMergeStyleAllowed(style))
+	}
+
+	protectBranch.RequireLinearHistory = true
+	assert.False(t, protectBranch.IsMergeStyleAllowed(MergeStyleMerge))
+	assert.True(t, protectBranch.IsMergeStyleAllowed(MergeStyleRebase))
+	assert.False(t, protectBranch.IsMergeStyleAllowed(MergeStyleRebaseMerge))
+	assert.True(t, protectBranch.IsMergeStyleAllowed(MergeStyleSquash))
+}
+
+func TestProtectedPathRule_Match(t *testing.T) {
+	rule := &ProtectedPathRule{Pattern: "migrations/**"}
+	assert.True(t, rule.Match("migrations/v1.go"))
+	assert.True(t, rule.Match("Migrations/sub/v1.go"))
+	assert.False(t, rule.Match("models/migrations/v1.go"))
+	assert.False(t, rule.Match("migrations.go"))
+
+	rule = &ProtectedPathRule{Pattern: "*.tf"}
+	assert.True(t, rule.Match("main.tf"))
+	assert.True(t, rule.Match("infra/prod/main.tf"))
+	assert.False(t, rule.Match("main.tfvars"))
+
+	rule = &ProtectedPathRule{Pattern: "infra/*.tf"}
+	assert.True(t, rule.Match("infra/main.tf"))
+	assert.False(t, rule.Match("infra/prod/main.tf"))
+}
+
+func TestNewProtectedPathRule(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	rule, err := NewProtectedPathRule(repo, " migrations/** ", "team1")
+	assert.NoError(t, err)
+	assert.Equal(t, "migrations/**", rule.Pattern)
+	assert.EqualValues(t, 2, rule.TeamID)
+
+	_, err = NewProtectedPathRule(repo, "migrations/[", "team1")
+	assert.True(t, IsErrInvalidProtectedPathRule(err))
+	_, err = NewProtectedPathRule(repo, "*.tf", "nonexistent")
+	assert.True(t, IsErrInvalidProtectedPathRule(err))
+
+	// the repositories of users have no teams
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	_, err = NewProtectedPathRule(repo, "*.tf", "team1")
+	assert.True(t, IsErrInvalidProtectedPathRule(err))
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/git"
+)
+
+// CommitTrailer represents a trailer of a commit message with the user of its identity
+type CommitTrailer struct {
+	*git.CommitTrailer
+	User *User
+}
+
+// GetCommitTrailers returns the trailers of the commit message, with the users of their identities
+func GetCommitTrailers(c *git.Commit) []*CommitTrailer {
+	return getCommitTrailers(c, map[string]*User{})
+}
+
+func getCommitTrailers(c *git.Commit, emails map[string]*User) []*CommitTrailer {
+	gitTrailers := c.Trailers()
+	trailers := make([]*CommitTrailer, 0, len(gitTrailers))
+	for _, gitTrailer := range gitTrailers {
+		trailer := &CommitTrailer{CommitTrailer: gitTrailer}
+		if gitTrailer.IsIdentity() {
+			u, ok := emails[gitTrailer.Email]
+			if !ok {
+				u, _ = GetUserByEmail(gitTrailer.Email)
+				emails[gitTrailer.Email] = u
+			}
+			trailer.User = u
+		}
+		trailers = append(trailers, trailer)
+	}
+	return trailers
+}
+
+// getCommitCoAuthors returns the Co-authored-by trailers of the commit message
+func getCommitCoAuthors(c *git.Commit, emails map[string]*User) []*CommitTrailer {
+	var coAuthors []*CommitTrailer
+	for _, trailer := range getCommitTrailers(c, emails) {
+		if trailer.HasKey(git.TrailerCoAuthoredBy) && trailer.IsIdentity() {
+			coAuthors = append(coAuthors, trailer)
+		}
+	}
+	return coAuthors
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"container/list"
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommitTrailers(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	commit := &git.Commit{
+		Author: &git.Signature{Name: "User Two", Email: "user2@example.com"},
+		CommitMessage: `Fix the thing
+
+Co-authored-by: User Four <user4@example.com>
+Co-authored-by: Someone <someone@example.com>
+Reviewed-by: User Five <user5@example.com>
+`,
+	}
+
+	trailers := GetCommitTrailers(commit)
+	if assert.Len(t, trailers, 3) {
+		if assert.NotNil(t, trailers[0].User) {
+			assert.EqualValues(t, 4, trailers[0].User.ID)
+		}
+		assert.Nil(t, trailers[1].User)
+		if assert.NotNil(t, trailers[2].User) {
+			assert.EqualValues(t, 5, trailers[2].User.ID)
+		}
+	}
+
+	commits := list.New()
+	commits.PushBack(commit)
+	userCommit := ValidateCommitsWithEmails(commits).Front().Value.(UserCommit)
+	if assert.Len(t, userCommit.CoAuthors, 2) {
+		assert.Equal(t, "User Four", userCommit.CoAuthors[0].Name)
+		assert.Equal(t, "someone@example.com", userCommit.CoAuthors[1].Email)
+	}
+}
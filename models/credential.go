@@ -0,0 +1,194 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// CredentialType is the type of a credential used to access the instance
+type CredentialType string
+
+// The types of the credentials
+const (
+	CredentialSSHKey      CredentialType = "ssh_key"
+	CredentialDeployKey   CredentialType = "deploy_key"
+	CredentialAccessToken CredentialType = "access_token"
+)
+
+// CredentialTypes are all the types of the credentials
+var CredentialTypes = []CredentialType{CredentialSSHKey, CredentialDeployKey, CredentialAccessToken}
+
+// StaleCredentialCutoff returns the time before which the credentials not used since are stale, zero if the
+// stale credentials are not reported
+func StaleCredentialCutoff() timeutil.TimeStamp {
+	if setting.StaleCredentialDays <= 0 {
+		return 0
+	}
+	return timeutil.TimeStampNow().AddDuration(-time.Duration(setting.StaleCredentialDays) * 24 * time.Hour)
+}
+
+// isStaleCredential returns whether a credential created and last used at these times is stale
+func isStaleCredential(created, lastUsed timeutil.TimeStamp) bool {
+	cutoff := StaleCredentialCutoff()
+	if cutoff == 0 {
+		return false
+	}
+	if lastUsed > 0 {
+		return lastUsed < cutoff
+	}
+	return created < cutoff
+}
+
+// staleCredentialCond returns the condition of the credentials not used since the cutoff, the ones never
+// used are stale when they were created before it
+func staleCredentialCond(cutoff timeutil.TimeStamp) builder.Cond {
+	return builder.And(builder.Gt{"last_used_unix": 0}, builder.Lt{"last_used_unix": cutoff}).
+		Or(builder.Eq{"last_used_unix": 0}.And(builder.Lt{"created_unix": cutoff}))
+}
+
+// StaleCredential is a credential reported as stale
+type StaleCredential struct {
+	Type CredentialType
+	ID   int64
+	Name string
+	// Fingerprint is the fingerprint of a key or the last eight characters of a token
+	Fingerprint string
+	// OwnerName is the name of the owner of a key or token, or the full name of the repository of a deploy key
+	OwnerName    string
+	OwnerLink    string
+	CreatedUnix  timeutil.TimeStamp
+	LastUsedUnix timeutil.TimeStamp
+	LastUsedIP   string
+}
+
+// FindStaleCredentials returns the credentials of the type not used since the cutoff, the ones not used
+// for the longest time first, and their total number
+func FindStaleCredentials(typ CredentialType, cutoff timeutil.TimeStamp, opts ListOptions) ([]*StaleCredential, int64, error) {
+	cond := staleCredentialCond(cutoff)
+	switch typ {
+	case CredentialSSHKey:
+		cond = cond.And(builder.Neq{"type": KeyTypeDeploy})
+		count, err := x.Where(cond).Count(new(PublicKey))
+		if err != nil {
+			return nil, 0, err
+		}
+		keys := make([]*PublicKey, 0, opts.PageSize)
+		if err := opts.setSessionPagination(x.Where(cond).Asc("last_used_unix", "created_unix", "id")).Find(&keys); err != nil {
+			return nil, 0, err
+		}
+		ownerIDs := make([]int64, 0, len(keys))
+		for _, key := range keys {
+			ownerIDs = append(ownerIDs, key.OwnerID)
+		}
+		owners, err := getUserMapByIDs(ownerIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		credentials := make([]*StaleCredential, 0, len(keys))
+		for _, key := range keys {
+			credential := &StaleCredential{
+				Type:         typ,
+				ID:           key.ID,
+				Name:         key.Name,
+				Fingerprint:  key.Fingerprint,
+				CreatedUnix:  key.CreatedUnix,
+				LastUsedUnix: key.LastUsedUnix,
+				LastUsedIP:   key.LastUsedIP,
+			}
+			if owner, ok := owners[key.OwnerID]; ok {
+				credential.OwnerName = owner.Name
+				credential.OwnerLink = owner.HomeLink()
+			}
+			credentials = append(credentials, credential)
+		}
+		return credentials, count, nil
+
+	case CredentialDeployKey:
+		count, err := x.Where(cond).Count(new(DeployKey))
+		if err != nil {
+			return nil, 0, err
+		}
+		keys := make([]*DeployKey, 0, opts.PageSize)
+		if err := opts.setSessionPagination(x.Where(cond).Asc("last_used_unix", "created_unix", "id")).Find(&keys); err != nil {
+			return nil, 0, err
+		}
+		repoIDs := make([]int64, 0, len(keys))
+		for _, key := range keys {
+			repoIDs = append(repoIDs, key.RepoID)
+		}
+		repos, err := GetRepositoriesMapByIDs(repoIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		credentials := make([]*StaleCredential, 0, len(keys))
+		for _, key := range keys {
+			credential := &StaleCredential{
+				Type:         typ,
+				ID:           key.ID,
+				Name:         key.Name,
+				Fingerprint:  key.Fingerprint,
+				CreatedUnix:  key.CreatedUnix,
+				LastUsedUnix: key.LastUsedUnix,
+				LastUsedIP:   key.LastUsedIP,
+			}
+			if repo, ok := repos[key.RepoID]; ok {
+				credential.OwnerName = repo.FullName()
+				credential.OwnerLink = repo.Link()
+			}
+			credentials = append(credentials, credential)
+		}
+		return credentials, count, nil
+
+	case CredentialAccessToken:
+		count, err := x.Where(cond).Count(new(AccessToken))
+		if err != nil {
+			return nil, 0, err
+		}
+		tokens := make([]*AccessToken, 0, opts.PageSize)
+		if err := opts.setSessionPagination(x.Where(cond).Asc("last_used_unix", "created_unix", "id")).Find(&tokens); err != nil {
+			return nil, 0, err
+		}
+		ownerIDs := make([]int64, 0, len(tokens))
+		for _, token := range tokens {
+			ownerIDs = append(ownerIDs, token.UID)
+		}
+		owners, err := getUserMapByIDs(ownerIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		credentials := make([]*StaleCredential, 0, len(tokens))
+		for _, token := range tokens {
+			credential := &StaleCredential{
+				Type:         typ,
+				ID:           token.ID,
+				Name:         token.Name,
+				Fingerprint:  token.TokenLastEight,
+				CreatedUnix:  token.CreatedUnix,
+				LastUsedUnix: token.LastUsedUnix,
+				LastUsedIP:   token.LastUsedIP,
+			}
+			if owner, ok := owners[token.UID]; ok {
+				credential.OwnerName = owner.Name
+				credential.OwnerLink = owner.HomeLink()
+			}
+			credentials = append(credentials, credential)
+		}
+		return credentials, count, nil
+	}
+	return nil, 0, fmt.Errorf("unknown credential type: %s", typ)
+}
+
+// getUserMapByIDs returns the users of the IDs by ID
+func getUserMapByIDs(ids []int64) (map[int64]*User, error) {
+	users := make(map[int64]*User, len(ids))
+	return users, x.In("id", ids).Find(&users)
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindStaleCredentials(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(days int) {
+		setting.StaleCredentialDays = days
+	}(setting.StaleCredentialDays)
+	setting.StaleCredentialDays = 90
+
+	// the fixtures were created and used long ago
+	credentials, count, err := FindStaleCredentials(CredentialSSHKey, StaleCredentialCutoff(), ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, credentials, 1) {
+		assert.EqualValues(t, 1, credentials[0].ID)
+		assert.Equal(t, "user2", credentials[0].OwnerName)
+		assert.EqualValues(t, 1565224552, credentials[0].LastUsedUnix)
+	}
+
+	token := AssertExistsAndLoadBean(t, &AccessToken{ID: 1}).(*AccessToken)
+	assert.True(t, token.IsStale)
+	assert.False(t, token.HasUsed)
+	assert.NoError(t, UpdateAccessTokenLastUsed(token, "192.0.2.1"))
+	token = AssertExistsAndLoadBean(t, &AccessToken{ID: 1}).(*AccessToken)
+	assert.False(t, token.IsStale)
+	assert.True(t, token.HasUsed)
+	assert.Equal(t, "192.0.2.1", token.LastUsedIP)
+
+	credentials, count, err = FindStaleCredentials(CredentialAccessToken, StaleCredentialCutoff(), ListOptions{Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, credentials, 1) {
+		assert.EqualValues(t, 2, credentials[0].ID)
+		assert.Equal(t, "user1", credentials[0].OwnerName)
+	}
+
+	assert.NoError(t, UpdatePublicKeyUpdated(1, "192.0.2.2"))
+	key := AssertExistsAndLoadBean(t, &PublicKey{ID: 1}).(*PublicKey)
+	assert.False(t, key.IsStale)
+	assert.Equal(t, "192.0.2.2", key.LastUsedIP)
+	_, count, err = FindStaleCredentials(CredentialSSHKey, StaleCredentialCutoff(), ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	// the stale credentials are not reported
+	setting.StaleCredentialDays = 0
+	assert.EqualValues(t, 0, StaleCredentialCutoff())
+	assert.False(t, isStaleCredential(timeutil.TimeStamp(946687980), 0))
+}
@@ -8,6 +8,7 @@ package models
 import (
 	"fmt"
 
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/git"
 )
 
@@ -601,6 +602,21 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return "access token is empty"
 }
 
+// ErrAccessTokenInvalidScope represents a "AccessTokenInvalidScope" kind of error.
+type ErrAccessTokenInvalidScope struct {
+	Scope string
+}
+
+// IsErrAccessTokenInvalidScope checks if an error is a ErrAccessTokenInvalidScope.
+func IsErrAccessTokenInvalidScope(err error) bool {
+	_, ok := err.(ErrAccessTokenInvalidScope)
+	return ok
+}
+
+func (err ErrAccessTokenInvalidScope) Error() string {
+	return fmt.Sprintf("access token scope is invalid [scope: %s]", err.Scope)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -1110,6 +1126,22 @@ func (err ErrBranchesEqual) Error() string {
 	return fmt.Sprintf("branches are equal [head: %sm base: %s]", err.HeadBranchName, err.BaseBranchName)
 }
 
+// ErrInvalidProtectedPathRule represents an error that a protected path rule of a branch protection is invalid.
+type ErrInvalidProtectedPathRule struct {
+	Pattern string
+	Reason  string
+}
+
+// IsErrInvalidProtectedPathRule checks if an error is an ErrInvalidProtectedPathRule.
+func IsErrInvalidProtectedPathRule(err error) bool {
+	_, ok := err.(ErrInvalidProtectedPathRule)
+	return ok
+}
+
+func (err ErrInvalidProtectedPathRule) Error() string {
+	return fmt.Sprintf("invalid protected path rule [pattern: %s]: %s", err.Pattern, err.Reason)
+}
+
 // ErrNotAllowedToMerge represents an error that a branch is protected and the current user is not allowed to modify it.
 type ErrNotAllowedToMerge struct {
 	Reason string
@@ -1201,6 +1233,21 @@ func (err ErrSHAOrCommitIDNotProvided) Error() string {
 	return "a SHA or commmit ID must be proved when updating a file"
 }
 
+// ErrMergeConflictNotResolved represents a "MergeConflictNotResolved" kind of error.
+type ErrMergeConflictNotResolved struct {
+	Path string
+}
+
+// IsErrMergeConflictNotResolved checks if an error is a ErrMergeConflictNotResolved.
+func IsErrMergeConflictNotResolved(err error) bool {
+	_, ok := err.(ErrMergeConflictNotResolved)
+	return ok
+}
+
+func (err ErrMergeConflictNotResolved) Error() string {
+	return fmt.Sprintf("merge conflict is not resolved [path: %s]", err.Path)
+}
+
 //  __      __      ___.   .__                   __
 // /  \    /  \ ____\_ |__ |  |__   ____   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \ /  _ \ /  _ \|  |/ /
@@ -1223,6 +1270,55 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrStorageQuotaExceeded represents a "StorageQuotaExceeded" kind of error.
+type ErrStorageQuotaExceeded struct {
+	Owner string
+	Quota int64
+	Used  int64
+	Size  int64
+}
+
+// IsErrStorageQuotaExceeded checks if an error is a ErrStorageQuotaExceeded.
+func IsErrStorageQuotaExceeded(err error) bool {
+	_, ok := err.(ErrStorageQuotaExceeded)
+	return ok
+}
+
+func (err ErrStorageQuotaExceeded) Error() string {
+	return fmt.Sprintf("the storage quota of %s is exceeded: %s are used of %s, %s can't be added",
+		err.Owner, base.FileSize(err.Used), base.FileSize(err.Quota), base.FileSize(err.Size))
+}
+
+// ErrHookTaskArchiveNotExist represents a "HookTaskArchiveNotExist" kind of error.
+type ErrHookTaskArchiveNotExist struct {
+	ID int64
+}
+
+// IsErrHookTaskArchiveNotExist checks if an error is a ErrHookTaskArchiveNotExist.
+func IsErrHookTaskArchiveNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskArchiveNotExist)
+	return ok
+}
+
+func (err ErrHookTaskArchiveNotExist) Error() string {
+	return fmt.Sprintf("hook task archive does not exist [id: %d]", err.ID)
+}
+
+// ErrWebhookHostNotAllowed represents a "WebhookHostNotAllowed" kind of error.
+type ErrWebhookHostNotAllowed struct {
+	Host string
+}
+
+// IsErrWebhookHostNotAllowed checks if an error is a ErrWebhookHostNotAllowed.
+func IsErrWebhookHostNotAllowed(err error) bool {
+	_, ok := err.(ErrWebhookHostNotAllowed)
+	return ok
+}
+
+func (err ErrWebhookHostNotAllowed) Error() string {
+	return fmt.Sprintf("webhook host is not allowed [host: %s]", err.Host)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -1519,6 +1615,84 @@ func (err ErrPullRequestHasMerged) Error() string {
 		err.ID, err.IssueID, err.HeadRepoID, err.BaseRepoID, err.HeadBranch, err.BaseBranch)
 }
 
+// ErrMergeQueueEntryNotExist represents a "MergeQueueEntryNotExist" kind of error.
+type ErrMergeQueueEntryNotExist struct {
+	PullID int64
+}
+
+// IsErrMergeQueueEntryNotExist checks if an error is a ErrMergeQueueEntryNotExist.
+func IsErrMergeQueueEntryNotExist(err error) bool {
+	_, ok := err.(ErrMergeQueueEntryNotExist)
+	return ok
+}
+
+func (err ErrMergeQueueEntryNotExist) Error() string {
+	return fmt.Sprintf("pull request is not in a merge queue [pull_id: %d]", err.PullID)
+}
+
+// ErrMergeQueueEntryAlreadyExists represents a "MergeQueueEntryAlreadyExists" kind of error.
+type ErrMergeQueueEntryAlreadyExists struct {
+	PullID int64
+}
+
+// IsErrMergeQueueEntryAlreadyExists checks if an error is a ErrMergeQueueEntryAlreadyExists.
+func IsErrMergeQueueEntryAlreadyExists(err error) bool {
+	_, ok := err.(ErrMergeQueueEntryAlreadyExists)
+	return ok
+}
+
+func (err ErrMergeQueueEntryAlreadyExists) Error() string {
+	return fmt.Sprintf("pull request is already in a merge queue [pull_id: %d]", err.PullID)
+}
+
+// ErrPullAutoPublishNotExist represents a "PullAutoPublishNotExist" kind of error.
+type ErrPullAutoPublishNotExist struct {
+	PullID int64
+}
+
+// IsErrPullAutoPublishNotExist checks if an error is a ErrPullAutoPublishNotExist.
+func IsErrPullAutoPublishNotExist(err error) bool {
+	_, ok := err.(ErrPullAutoPublishNotExist)
+	return ok
+}
+
+func (err ErrPullAutoPublishNotExist) Error() string {
+	return fmt.Sprintf("pull request is not published automatically [pull_id: %d]", err.PullID)
+}
+
+// ErrInvalidBasePull represents a "InvalidBasePull" kind of error.
+type ErrInvalidBasePull struct {
+	PullID     int64
+	BasePullID int64
+	Reason     string
+}
+
+// IsErrInvalidBasePull checks if an error is a ErrInvalidBasePull.
+func IsErrInvalidBasePull(err error) bool {
+	_, ok := err.(ErrInvalidBasePull)
+	return ok
+}
+
+func (err ErrInvalidBasePull) Error() string {
+	return fmt.Sprintf("pull request can not be stacked on the pull request [pull_id: %d, base_pull_id: %d]: %s", err.PullID, err.BasePullID, err.Reason)
+}
+
+// ErrInvalidSuggestion represents a "InvalidSuggestion" kind of error.
+type ErrInvalidSuggestion struct {
+	CommentID int64
+	Reason    string
+}
+
+// IsErrInvalidSuggestion checks if an error is a ErrInvalidSuggestion.
+func IsErrInvalidSuggestion(err error) bool {
+	_, ok := err.(ErrInvalidSuggestion)
+	return ok
+}
+
+func (err ErrInvalidSuggestion) Error() string {
+	return fmt.Sprintf("suggestion can not be applied [comment_id: %d]: %s", err.CommentID, err.Reason)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -1678,6 +1852,22 @@ func (err ErrProjectBoardNotExist) Error() string {
 	return fmt.Sprintf("project board does not exist [id: %d]", err.BoardID)
 }
 
+// ErrProjectAutomationRuleNotExist represents a "ProjectAutomationRuleNotExist" kind of error.
+type ErrProjectAutomationRuleNotExist struct {
+	ID        int64
+	ProjectID int64
+}
+
+// IsErrProjectAutomationRuleNotExist checks if an error is a ErrProjectAutomationRuleNotExist
+func IsErrProjectAutomationRuleNotExist(err error) bool {
+	_, ok := err.(ErrProjectAutomationRuleNotExist)
+	return ok
+}
+
+func (err ErrProjectAutomationRuleNotExist) Error() string {
+	return fmt.Sprintf("project automation rule does not exist [id: %d, project_id: %d]", err.ID, err.ProjectID)
+}
+
 //    _____  .__.__                   __
 //   /     \ |__|  |   ____   _______/  |_  ____   ____   ____
 //  /  \ /  \|  |  | _/ __ \ /  ___/\   __\/  _ \ /    \_/ __ \
@@ -1820,6 +2010,22 @@ func (err ErrTeamNotExist) Error() string {
 	return fmt.Sprintf("team does not exist [org_id %d, team_id %d, name: %s]", err.OrgID, err.TeamID, err.Name)
 }
 
+// ErrTeamRepoNotExist represents a "TeamRepoNotExist" error
+type ErrTeamRepoNotExist struct {
+	TeamID int64
+	RepoID int64
+}
+
+// IsErrTeamRepoNotExist checks if an error is a ErrTeamRepoNotExist.
+func IsErrTeamRepoNotExist(err error) bool {
+	_, ok := err.(ErrTeamRepoNotExist)
+	return ok
+}
+
+func (err ErrTeamRepoNotExist) Error() string {
+	return fmt.Sprintf("repository isn't a repository of the team [team_id: %d, repo_id: %d]", err.TeamID, err.RepoID)
+}
+
 //
 // Two-factor authentication
 //
@@ -2010,6 +2216,175 @@ func (err ErrUnknownDependencyType) Error() string {
 	return fmt.Sprintf("unknown dependency type [type: %d]", err.Type)
 }
 
+// ErrSubIssueHasParent represents an error where the issue to attach already has a parent issue.
+type ErrSubIssueHasParent struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueHasParent checks if an error is a ErrSubIssueHasParent.
+func IsErrSubIssueHasParent(err error) bool {
+	_, ok := err.(ErrSubIssueHasParent)
+	return ok
+}
+
+func (err ErrSubIssueHasParent) Error() string {
+	return fmt.Sprintf("issue already has a parent issue [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrSubIssueNotExist represents an error where an issue isn't a sub-issue of the given parent issue.
+type ErrSubIssueNotExist struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueNotExist checks if an error is a ErrSubIssueNotExist.
+func IsErrSubIssueNotExist(err error) bool {
+	_, ok := err.(ErrSubIssueNotExist)
+	return ok
+}
+
+func (err ErrSubIssueNotExist) Error() string {
+	return fmt.Sprintf("issue is not a sub-issue of the parent issue [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrCircularSubIssue represents an error where an issue would become an ancestor of itself.
+type ErrCircularSubIssue struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrCircularSubIssue checks if an error is a ErrCircularSubIssue.
+func IsErrCircularSubIssue(err error) bool {
+	_, ok := err.(ErrCircularSubIssue)
+	return ok
+}
+
+func (err ErrCircularSubIssue) Error() string {
+	return fmt.Sprintf("issue is an ancestor of the parent issue [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrSubIssueNotSameRepo represents an error where a sub-issue isn't in the repository of its parent issue.
+type ErrSubIssueNotSameRepo struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueNotSameRepo checks if an error is a ErrSubIssueNotSameRepo.
+func IsErrSubIssueNotSameRepo(err error) bool {
+	_, ok := err.(ErrSubIssueNotSameRepo)
+	return ok
+}
+
+func (err ErrSubIssueNotSameRepo) Error() string {
+	return fmt.Sprintf("issue is not in the repository of the parent issue [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrIssueReminderNotExist represents a "IssueReminderNotExist" kind of error.
+type ErrIssueReminderNotExist struct {
+	ID int64
+}
+
+// IsErrIssueReminderNotExist checks if an error is a ErrIssueReminderNotExist.
+func IsErrIssueReminderNotExist(err error) bool {
+	_, ok := err.(ErrIssueReminderNotExist)
+	return ok
+}
+
+func (err ErrIssueReminderNotExist) Error() string {
+	return fmt.Sprintf("issue reminder does not exist [id: %d]", err.ID)
+}
+
+// ErrSavedReplyNotExist represents a "SavedReplyNotExist" kind of error.
+type ErrSavedReplyNotExist struct {
+	ID int64
+}
+
+// IsErrSavedReplyNotExist checks if an error is a ErrSavedReplyNotExist.
+func IsErrSavedReplyNotExist(err error) bool {
+	_, ok := err.(ErrSavedReplyNotExist)
+	return ok
+}
+
+func (err ErrSavedReplyNotExist) Error() string {
+	return fmt.Sprintf("saved reply does not exist [id: %d]", err.ID)
+}
+
+// ErrLegalPageNotExist represents a "LegalPageNotExist" kind of error.
+type ErrLegalPageNotExist struct {
+	ID int64
+}
+
+// IsErrLegalPageNotExist checks if an error is a ErrLegalPageNotExist.
+func IsErrLegalPageNotExist(err error) bool {
+	_, ok := err.(ErrLegalPageNotExist)
+	return ok
+}
+
+func (err ErrLegalPageNotExist) Error() string {
+	return fmt.Sprintf("legal page does not exist [id: %d]", err.ID)
+}
+
+// ErrStalePolicyNotExist represents a "StalePolicyNotExist" kind of error.
+type ErrStalePolicyNotExist struct {
+	RepoID int64
+}
+
+// IsErrStalePolicyNotExist checks if an error is a ErrStalePolicyNotExist.
+func IsErrStalePolicyNotExist(err error) bool {
+	_, ok := err.(ErrStalePolicyNotExist)
+	return ok
+}
+
+func (err ErrStalePolicyNotExist) Error() string {
+	return fmt.Sprintf("stale policy does not exist [repo_id: %d]", err.RepoID)
+}
+
+// ErrReleaseAutomationNotExist represents a "ReleaseAutomationNotExist" kind of error.
+type ErrReleaseAutomationNotExist struct {
+	RepoID int64
+}
+
+// IsErrReleaseAutomationNotExist checks if an error is a ErrReleaseAutomationNotExist.
+func IsErrReleaseAutomationNotExist(err error) bool {
+	_, ok := err.(ErrReleaseAutomationNotExist)
+	return ok
+}
+
+func (err ErrReleaseAutomationNotExist) Error() string {
+	return fmt.Sprintf("release automation does not exist [repo_id: %d]", err.RepoID)
+}
+
+// ErrStatusIssueRuleNotExist represents a "StatusIssueRuleNotExist" kind of error.
+type ErrStatusIssueRuleNotExist struct {
+	RepoID int64
+}
+
+// IsErrStatusIssueRuleNotExist checks if an error is a ErrStatusIssueRuleNotExist.
+func IsErrStatusIssueRuleNotExist(err error) bool {
+	_, ok := err.(ErrStatusIssueRuleNotExist)
+	return ok
+}
+
+func (err ErrStatusIssueRuleNotExist) Error() string {
+	return fmt.Sprintf("status issue rule does not exist [repo_id: %d]", err.RepoID)
+}
+
+// ErrIssueSLAPolicyNotExist represents a "IssueSLAPolicyNotExist" kind of error.
+type ErrIssueSLAPolicyNotExist struct {
+	ID int64
+}
+
+// IsErrIssueSLAPolicyNotExist checks if an error is a ErrIssueSLAPolicyNotExist.
+func IsErrIssueSLAPolicyNotExist(err error) bool {
+	_, ok := err.(ErrIssueSLAPolicyNotExist)
+	return ok
+}
+
+func (err ErrIssueSLAPolicyNotExist) Error() string {
+	return fmt.Sprintf("issue SLA policy does not exist [id: %d]", err.ID)
+}
+
 //  __________            .__
 //  \______   \ _______  _|__| ______  _  __
 //  |       _// __ \  \/ /  |/ __ \ \/ \/ /
@@ -2090,3 +2465,35 @@ func IsErrOAuthApplicationNotFound(err error) bool {
 func (err ErrOAuthApplicationNotFound) Error() string {
 	return fmt.Sprintf("OAuth application not found [ID: %d]", err.ID)
 }
+
+// ErrOAuth2DeviceCodeNotExist will be thrown if a device code was already exchanged or deleted
+type ErrOAuth2DeviceCodeNotExist struct {
+	ID int64
+}
+
+// IsErrOAuth2DeviceCodeNotExist checks if an error is a ErrOAuth2DeviceCodeNotExist.
+func IsErrOAuth2DeviceCodeNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2DeviceCodeNotExist)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuth2DeviceCodeNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 device code does not exist [ID: %d]", err.ID)
+}
+
+// ErrOAuth2DeviceCodeNotPending will be thrown if the user already approved or denied a device code
+type ErrOAuth2DeviceCodeNotPending struct {
+	ID int64
+}
+
+// IsErrOAuth2DeviceCodeNotPending checks if an error is a ErrOAuth2DeviceCodeNotPending.
+func IsErrOAuth2DeviceCodeNotPending(err error) bool {
+	_, ok := err.(ErrOAuth2DeviceCodeNotPending)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuth2DeviceCodeNotPending) Error() string {
+	return fmt.Sprintf("OAuth2 device code is not pending [ID: %d]", err.ID)
+}
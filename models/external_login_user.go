@@ -186,3 +186,38 @@ func UpdateMigrationsByType(tp structs.GitServiceType, externalUserID string, us
 	}
 	return UpdateReviewsMigrationsByType(tp, externalUserID, userID)
 }
+
+// oauth2ClaimGroups returns the groups listed by the claim of the raw data of the provider, the claim is either
+// a list or a single group
+func oauth2ClaimGroups(rawData map[string]interface{}, claim string) []string {
+	groups := make([]string, 0)
+	switch v := rawData[claim].(type) {
+	case string:
+		if len(v) > 0 {
+			groups = append(groups, v)
+		}
+	case []string:
+		groups = append(groups, v...)
+	case []interface{}:
+		for _, group := range v {
+			if s, ok := group.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	return groups
+}
+
+// SyncOAuth2GroupTeams syncs the teams of the user with the groups of the claim of the OAuth2 source of the
+// external user, when they're mapped to teams
+func SyncOAuth2GroupTeams(user *User, gothUser goth.User) error {
+	loginSource, err := GetActiveOAuth2LoginSourceByName(gothUser.Provider)
+	if err != nil {
+		return err
+	}
+	cfg := loginSource.OAuth2()
+	if len(cfg.GroupClaimName) == 0 {
+		return nil
+	}
+	return SyncGroupTeams(user, cfg.GroupTeamMap, cfg.GroupTeamMapRemoval, oauth2ClaimGroups(gothUser.RawData, cfg.GroupClaimName))
+}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/signing"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/keybase/go-crypto/openpgp"
@@ -383,6 +384,7 @@ type CommitVerification struct {
 	CommittingUser *User
 	SigningEmail   string
 	SigningKey     *GPGKey
+	SigningSSHKey  *PublicKey
 	TrustStatus    string
 }
 
@@ -646,6 +648,10 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
+	if isSSHSignature(c.Signature.Signature) {
+		return parseCommitWithSSHSignature(c, committer)
+	}
+
 	//Parsing signature
 	sig, err := extractSignature(c.Signature.Signature)
 	if err != nil { //Skipping failed to extract sign
@@ -715,7 +721,8 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
-	if setting.Repository.Signing.SigningKey != "" && setting.Repository.Signing.SigningKey != "default" && setting.Repository.Signing.SigningKey != "none" {
+	if setting.Repository.Signing.SigningKey != "none" && setting.Repository.Signing.Format == git.SigningKeyFormatOpenPGP && (signing.IsExternal() ||
+		setting.Repository.Signing.SigningKey != "" && setting.Repository.Signing.SigningKey != "default") {
 		// OK we should try the default key
 		gpgSettings := git.GPGSettings{
 			Sign:  true,
@@ -723,7 +730,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 			Name:  setting.Repository.Signing.SigningName,
 			Email: setting.Repository.Signing.SigningEmail,
 		}
-		if err := gpgSettings.LoadPublicKeyContent(); err != nil {
+		if err := loadInstanceSigningKey(&gpgSettings); err != nil {
 			log.Error("Error getting default signing key: %s %v", gpgSettings.KeyID, err)
 		} else if commitVerification := verifyWithGPGSettings(&gpgSettings, sig, c.Signature.Payload, committer, keyID); commitVerification != nil {
 			if commitVerification.Reason == BadSignature {
@@ -739,7 +746,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		log.Error("Error getting default public gpg key: %v", err)
 	} else if defaultGPGSettings == nil {
 		log.Warn("Unable to get defaultGPGSettings for unattached commit: %s", c.ID.String())
-	} else if defaultGPGSettings.Sign {
+	} else if defaultGPGSettings.Sign && defaultGPGSettings.Format == git.SigningKeyFormatOpenPGP {
 		if commitVerification := verifyWithGPGSettings(defaultGPGSettings, sig, c.Signature.Payload, committer, keyID); commitVerification != nil {
 			if commitVerification.Reason == BadSignature {
 				defaultReason = BadSignature
@@ -892,11 +899,17 @@ func CalculateTrustStatus(verification *CommitVerification, repository *Reposito
 
 	var isMember bool
 	if keyMap != nil {
+		keyID := ""
+		if verification.SigningKey != nil {
+			keyID = verification.SigningKey.KeyID
+		} else if verification.SigningSSHKey != nil {
+			keyID = verification.SigningSSHKey.Fingerprint
+		}
 		var has bool
-		isMember, has = (*keyMap)[verification.SigningKey.KeyID]
+		isMember, has = (*keyMap)[keyID]
 		if !has {
 			isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
-			(*keyMap)[verification.SigningKey.KeyID] = isMember
+			(*keyMap)[keyID] = isMember
 		}
 	} else {
 		isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// HookTaskArchive represents the deliveries of a webhook which were archived to the storage before
+// their deletion, the archive is deleted when its deliveries are restored
+type HookTaskArchive struct {
+	ID     int64 `xorm:"pk autoincr"`
+	HookID int64 `xorm:"INDEX"`
+	// Path is the path of the gzipped JSON of the deliveries in the storage
+	Path     string
+	NumTasks int
+	// FirstDelivered and LastDelivered are in nanoseconds like HookTask.Delivered
+	FirstDelivered int64
+	LastDelivered  int64
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// FirstDeliveredString returns the time of the first archived delivery formatted like HookTask.DeliveredString
+func (a *HookTaskArchive) FirstDeliveredString() string {
+	return time.Unix(0, a.FirstDelivered).Format("2006-01-02 15:04:05 MST")
+}
+
+// LastDeliveredString returns the time of the last archived delivery formatted like HookTask.DeliveredString
+func (a *HookTaskArchive) LastDeliveredString() string {
+	return time.Unix(0, a.LastDelivered).Format("2006-01-02 15:04:05 MST")
+}
+
+// GetHookIDsOfTasksDeliveredBefore returns the IDs of the webhooks having deliveries done before a time in nanoseconds
+func GetHookIDsOfTasksDeliveredBefore(before int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, x.Table("hook_task").
+		Where(builder.Eq{"is_delivered": true}.And(builder.Lt{"delivered": before})).
+		Distinct("hook_id").
+		Find(&ids)
+}
+
+// FindHookTasksDeliveredBefore returns the oldest deliveries of a webhook done before a time in nanoseconds
+func FindHookTasksDeliveredBefore(hookID, before int64, limit int) ([]*HookTask, error) {
+	tasks := make([]*HookTask, 0, limit)
+	return tasks, x.Where(builder.Eq{"hook_id": hookID, "is_delivered": true}.And(builder.Lt{"delivered": before})).
+		Asc("delivered", "id").
+		Limit(limit).
+		Find(&tasks)
+}
+
+// DeleteHookTasks deletes the hook tasks of the given IDs
+func DeleteHookTasks(ids []int64) error {
+	_, err := x.In("id", ids).Delete(new(HookTask))
+	return err
+}
+
+// CreateHookTaskArchive inserts the record of an archive of deliveries
+func CreateHookTaskArchive(archive *HookTaskArchive) error {
+	_, err := x.Insert(archive)
+	return err
+}
+
+// GetHookTaskArchives returns the archived deliveries of a webhook, the newest first
+func GetHookTaskArchives(hookID int64) ([]*HookTaskArchive, error) {
+	archives := make([]*HookTaskArchive, 0, 5)
+	return archives, x.Where("hook_id = ?", hookID).Desc("last_delivered").Find(&archives)
+}
+
+// GetHookTaskArchive returns an archive of deliveries of a webhook
+func GetHookTaskArchive(hookID, id int64) (*HookTaskArchive, error) {
+	archive := new(HookTaskArchive)
+	has, err := x.Where("id = ? AND hook_id = ?", id, hookID).Get(archive)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskArchiveNotExist{ID: id}
+	}
+	return archive, nil
+}
+
+// GetHookTaskArchivesCreatedBefore returns the archives of deliveries created before a time
+func GetHookTaskArchivesCreatedBefore(before timeutil.TimeStamp) ([]*HookTaskArchive, error) {
+	archives := make([]*HookTaskArchive, 0, 10)
+	return archives, x.Where("created_unix < ?", before).Find(&archives)
+}
+
+// DeleteHookTaskArchive deletes the record of an archive of deliveries
+func DeleteHookTaskArchive(id int64) error {
+	_, err := x.ID(id).Delete(new(HookTaskArchive))
+	return err
+}
+
+// RestoreHookTaskArchive inserts the archived deliveries which don't exist anymore and deletes the record of their archive
+func RestoreHookTaskArchive(archive *HookTaskArchive, tasks []*HookTask) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.HookID != archive.HookID {
+			continue
+		}
+		has, err := sess.ID(task.ID).Exist(new(HookTask))
+		if err != nil {
+			return err
+		} else if has {
+			continue
+		}
+		if _, err := sess.Insert(task); err != nil {
+			return err
+		}
+	}
+	if _, err := sess.ID(archive.ID).Delete(new(HookTaskArchive)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
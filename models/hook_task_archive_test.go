@@ -0,0 +1,56 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindHookTasksDeliveredBefore(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	now := time.Now().UnixNano()
+	hookIDs, err := GetHookIDsOfTasksDeliveredBefore(now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, hookIDs)
+
+	tasks, err := FindHookTasksDeliveredBefore(1, now, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 1) {
+		assert.EqualValues(t, 1, tasks[0].ID)
+	}
+
+	hookIDs, err = GetHookIDsOfTasksDeliveredBefore(0)
+	assert.NoError(t, err)
+	assert.Empty(t, hookIDs)
+}
+
+func TestRestoreHookTaskArchive(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	task := AssertExistsAndLoadBean(t, &HookTask{ID: 1}).(*HookTask)
+	archive := &HookTaskArchive{HookID: 1, Path: "1/archive.json.gz", NumTasks: 1}
+	assert.NoError(t, CreateHookTaskArchive(archive))
+	assert.NoError(t, DeleteHookTasks([]int64{task.ID}))
+	AssertNotExistsBean(t, &HookTask{ID: 1})
+
+	archives, err := GetHookTaskArchives(1)
+	assert.NoError(t, err)
+	assert.Len(t, archives, 1)
+	_, err = GetHookTaskArchive(2, archive.ID)
+	assert.True(t, IsErrHookTaskArchiveNotExist(err))
+	archive, err = GetHookTaskArchive(1, archive.ID)
+	assert.NoError(t, err)
+
+	// the tasks of other webhooks aren't restored
+	other := &HookTask{ID: 100, HookID: 2, UUID: "uuid100", IsDelivered: true}
+	assert.NoError(t, RestoreHookTaskArchive(archive, []*HookTask{task, other}))
+	AssertExistsAndLoadBean(t, &HookTask{ID: 1, HookID: 1, UUID: "uuid1"})
+	AssertNotExistsBean(t, &HookTask{ID: 100})
+	AssertNotExistsBean(t, &HookTaskArchive{ID: archive.ID})
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/modules/idgen"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// highWriteIDGenerator issues the IDs of the rows of the tables written the most, i.e. the actions,
+// the notifications and the webhook deliveries, the database assigns them if it's nil
+var highWriteIDGenerator idgen.Generator
+
+// initIDGenerator sets up the issuance of the IDs of the tables written the most from the settings
+func initIDGenerator() error {
+	highWriteIDGenerator = nil
+	if setting.Database.IDGenerator != "snowflake" {
+		return nil
+	}
+	// MSSQL rejects the values of identity columns
+	if setting.Database.UseMSSQL {
+		return errors.New("snowflake IDs aren't supported by MSSQL")
+	}
+	generator, err := idgen.NewSnowflake(setting.Database.SnowflakeNodeID)
+	if err != nil {
+		return err
+	}
+	highWriteIDGenerator = generator
+	return nil
+}
+
+// nextHighWriteID returns the ID of a new row of a table written the most, it's 0 if the database assigns it
+func nextHighWriteID() int64 {
+	if highWriteIDGenerator == nil {
+		return 0
+	}
+	return highWriteIDGenerator.NextID()
+}
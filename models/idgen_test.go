@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/idgen"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighWriteIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// the database assigns the IDs by default
+	notification := &Notification{UserID: 2, RepoID: 1, IssueID: 1, Status: NotificationStatusUnread, Source: NotificationSourceIssue}
+	_, err := x.Insert(notification)
+	assert.NoError(t, err)
+	assert.Less(t, notification.ID, int64(1000))
+
+	generator, err := idgen.NewSnowflake(3)
+	assert.NoError(t, err)
+	highWriteIDGenerator = generator
+	defer func() { highWriteIDGenerator = nil }()
+
+	notification = &Notification{UserID: 2, RepoID: 1, IssueID: 2, Status: NotificationStatusUnread, Source: NotificationSourceIssue}
+	_, err = x.Insert(notification)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, idgen.NodeID(notification.ID))
+	AssertExistsAndLoadBean(t, &Notification{ID: notification.ID, IssueID: 2})
+
+	action := &Action{UserID: 2, OpType: ActionCreateIssue, ActUserID: 2, RepoID: 1, Content: "1|test"}
+	_, err = x.Insert(action)
+	assert.NoError(t, err)
+	assert.Greater(t, action.ID, notification.ID)
+	AssertExistsAndLoadBean(t, &Action{ID: action.ID})
+
+	task := &HookTask{RepoID: 1, HookID: 1, UUID: "snowflake-uuid", EventType: HookEventPush}
+	_, err = x.Insert(task)
+	assert.NoError(t, err)
+	assert.Greater(t, task.ID, action.ID)
+	AssertExistsAndLoadBean(t, &HookTask{ID: task.ID, UUID: "snowflake-uuid"})
+}
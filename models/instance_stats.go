@@ -0,0 +1,204 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// activeUserPeriod is the period over which the users who signed in are active
+const activeUserPeriod = 30 * 24 * time.Hour
+
+// InstanceStats represents a daily snapshot of the growth and the storage consumption of the instance,
+// the snapshot of the current day is updated until the day is over
+type InstanceStats struct {
+	ID int64 `xorm:"pk autoincr"`
+	// Day is the beginning of the day in UTC
+	Day   timeutil.TimeStamp `xorm:"UNIQUE NOT NULL"`
+	Users int64              `xorm:"NOT NULL DEFAULT 0"`
+	// NewUsers is the number of users who signed up during the day
+	NewUsers int64 `xorm:"NOT NULL DEFAULT 0"`
+	// ActiveUsers is the number of users who signed in during the last 30 days
+	ActiveUsers int64 `xorm:"NOT NULL DEFAULT 0"`
+	Orgs        int64 `xorm:"NOT NULL DEFAULT 0"`
+	Repos       int64 `xorm:"NOT NULL DEFAULT 0"`
+	// NewRepos is the number of repositories created during the day
+	NewRepos int64 `xorm:"NOT NULL DEFAULT 0"`
+	// RepoSize, LFSSize and AttachmentSize are in bytes
+	RepoSize       int64 `xorm:"NOT NULL DEFAULT 0"`
+	LFSSize        int64 `xorm:"NOT NULL DEFAULT 0"`
+	AttachmentSize int64 `xorm:"NOT NULL DEFAULT 0"`
+	// APIRequests is the number of API requests of signed in users during the day
+	APIRequests int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// StorageSize returns the total storage consumption of the instance in bytes
+func (s *InstanceStats) StorageSize() int64 {
+	return s.RepoSize + s.LFSSize + s.AttachmentSize
+}
+
+// DayDate returns the date of the day of the snapshot
+func (s *InstanceStats) DayDate() string {
+	return s.Day.FormatInLocation("2006-01-02", time.UTC)
+}
+
+// APIUsage represents the number of API requests of a user during a day
+type APIUsage struct {
+	ID       int64              `xorm:"pk autoincr"`
+	UserID   int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Day      timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Requests int64              `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// StatsDay returns the beginning of the day in UTC of a time
+func StatsDay(t time.Time) timeutil.TimeStamp {
+	t = t.UTC()
+	return timeutil.TimeStamp(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix())
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// AddAPIUsage adds API requests of users during a day
+func AddAPIUsage(day timeutil.TimeStamp, requests map[int64]int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	for userID, count := range requests {
+		updated, err := sess.Where("user_id = ? AND day = ?", userID, day).Incr("requests", count).Update(new(APIUsage))
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			if _, err := sess.Insert(&APIUsage{UserID: userID, Day: day, Requests: count}); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.Commit()
+}
+
+// APIConsumer represents the number of API requests of a user over a period
+type APIConsumer struct {
+	UserID   int64
+	User     *User `xorm:"-"`
+	Requests int64
+}
+
+// GetTopAPIConsumers returns the users who made the most API requests during the days of a period
+func GetTopAPIConsumers(since, before timeutil.TimeStamp, limit int) ([]*APIConsumer, error) {
+	consumers := make([]*APIConsumer, 0, limit)
+	if err := x.Table("api_usage").
+		Select("user_id, SUM(requests) AS requests").
+		Where(builder.Gte{"day": since}.And(builder.Lt{"day": before})).
+		GroupBy("user_id").
+		OrderBy("requests DESC, user_id").
+		Limit(limit).
+		Find(&consumers); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(consumers))
+	for _, consumer := range consumers {
+		userIDs = append(userIDs, consumer.UserID)
+	}
+	users := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return nil, err
+	}
+	for _, consumer := range consumers {
+		// the requests of the deleted users stay anonymous
+		consumer.User = users[consumer.UserID]
+	}
+	return consumers, nil
+}
+
+// sumSQL returns the sum computed by a SQL query
+func sumSQL(query string, args ...interface{}) (int64, error) {
+	var sum int64
+	if _, err := x.SQL(query, args...).Get(&sum); err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+// AggregateInstanceStats creates or updates the snapshot of the day of a time
+func AggregateInstanceStats(now time.Time) (*InstanceStats, error) {
+	day := StatsDay(now)
+	nextDay := day + secondsPerDay
+	createdCond := builder.Gte{"created_unix": day}.And(builder.Lt{"created_unix": nextDay})
+
+	stats := &InstanceStats{
+		Day:   day,
+		Users: CountUsers(),
+		Orgs:  CountOrganizations(),
+		Repos: CountRepositories(true),
+	}
+	var err error
+	if stats.NewUsers, err = x.Where(builder.Eq{"type": UserTypeIndividual}.And(createdCond)).Count(new(User)); err != nil {
+		return nil, err
+	}
+	activeSince := timeutil.TimeStamp(now.Add(-activeUserPeriod).Unix())
+	if stats.ActiveUsers, err = x.Where(builder.Eq{"type": UserTypeIndividual}.And(builder.Gte{"last_login_unix": activeSince})).Count(new(User)); err != nil {
+		return nil, err
+	}
+	if stats.NewRepos, err = x.Where(createdCond).Count(new(Repository)); err != nil {
+		return nil, err
+	}
+	// the size of the repositories is in KiB
+	if stats.RepoSize, err = sumSQL("SELECT COALESCE(SUM(size), 0) FROM repository"); err != nil {
+		return nil, err
+	}
+	stats.RepoSize *= 1024
+	// the LFS objects are shared by the repositories of a fork network
+	if stats.LFSSize, err = sumSQL("SELECT COALESCE(SUM(size), 0) FROM (SELECT DISTINCT oid, size FROM lfs_meta_object) lfs_objects"); err != nil {
+		return nil, err
+	}
+	if stats.AttachmentSize, err = sumSQL("SELECT COALESCE(SUM(size), 0) FROM attachment"); err != nil {
+		return nil, err
+	}
+	if stats.APIRequests, err = sumSQL("SELECT COALESCE(SUM(requests), 0) FROM api_usage WHERE day = ?", day); err != nil {
+		return nil, err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+	existing := new(InstanceStats)
+	has, err := sess.Where("day = ?", day).Get(existing)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		stats.ID = existing.ID
+		_, err = sess.ID(stats.ID).AllCols().Update(stats)
+	} else {
+		_, err = sess.Insert(stats)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stats, sess.Commit()
+}
+
+// GetInstanceStats returns the daily snapshots of the days of a period, the oldest first
+func GetInstanceStats(since, before timeutil.TimeStamp) ([]*InstanceStats, error) {
+	stats := make([]*InstanceStats, 0, (before-since)/secondsPerDay)
+	if err := x.Where(builder.Gte{"day": since}.And(builder.Lt{"day": before})).
+		Asc("day").
+		Find(&stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
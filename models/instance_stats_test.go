@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateInstanceStats(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	now := time.Date(2020, 10, 20, 15, 4, 5, 0, time.UTC)
+	day := StatsDay(now)
+	assert.EqualValues(t, time.Date(2020, 10, 20, 0, 0, 0, 0, time.UTC).Unix(), day)
+
+	assert.NoError(t, AddAPIUsage(day, map[int64]int64{1: 3, 2: 5}))
+	assert.NoError(t, AddAPIUsage(day, map[int64]int64{1: 4}))
+	assert.NoError(t, AddAPIUsage(day-secondsPerDay, map[int64]int64{2: 1}))
+
+	consumers, err := GetTopAPIConsumers(day-secondsPerDay, day+secondsPerDay, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, consumers, 2) {
+		assert.EqualValues(t, 1, consumers[0].UserID)
+		assert.EqualValues(t, 7, consumers[0].Requests)
+		assert.Equal(t, "user1", consumers[0].User.Name)
+		assert.EqualValues(t, 6, consumers[1].Requests)
+	}
+
+	stats, err := AggregateInstanceStats(now)
+	assert.NoError(t, err)
+	assert.Equal(t, CountUsers(), stats.Users)
+	assert.Equal(t, CountRepositories(true), stats.Repos)
+	assert.EqualValues(t, 12, stats.APIRequests)
+
+	// the snapshot of the day is updated
+	assert.NoError(t, AddAPIUsage(day, map[int64]int64{2: 1}))
+	_, err = AggregateInstanceStats(now.Add(time.Hour))
+	assert.NoError(t, err)
+	all, err := GetInstanceStats(day-secondsPerDay, day+secondsPerDay)
+	assert.NoError(t, err)
+	if assert.Len(t, all, 1) {
+		assert.EqualValues(t, 13, all[0].APIRequests)
+		assert.Equal(t, "2020-10-20", all[0].DayDate())
+	}
+}
@@ -51,8 +51,12 @@ type Issue struct {
 	PullRequest      *PullRequest `xorm:"-"`
 	NumComments      int
 	Ref              string
+	ParentID         int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Parent           *Issue `xorm:"-"`
 
 	DeadlineUnix timeutil.TimeStamp `xorm:"INDEX"`
+	// TimeEstimate is the estimated time to resolve the issue in seconds
+	TimeEstimate int64 `xorm:"NOT NULL DEFAULT 0"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -780,6 +784,7 @@ func (issue *Issue) UpdateAttachments(uuids []string) (err error) {
 
 // ChangeContent changes issue content, as the given user.
 func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
+	oldContent := issue.Content
 	issue.Content = content
 
 	sess := x.NewSession()
@@ -788,6 +793,16 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 		return err
 	}
 
+	if err = recordContentRevision(sess, &ContentRevision{
+		RepoID:   issue.RepoID,
+		IssueID:  issue.ID,
+		PosterID: issue.PosterID,
+		DoerID:   doer.ID,
+		Content:  oldContent,
+	}); err != nil {
+		return fmt.Errorf("recordContentRevision: %v", err)
+	}
+
 	if err = updateIssueCols(sess, issue, "content"); err != nil {
 		return fmt.Errorf("UpdateIssueCols: %v", err)
 	}
@@ -1093,6 +1108,7 @@ type IssuesOptions struct {
 	MilestoneIDs       []int64
 	ProjectID          int64
 	ProjectBoardID     int64
+	ParentID           int64
 	IsClosed           util.OptionalBool
 	IsPull             util.OptionalBool
 	LabelIDs           []int64
@@ -1104,6 +1120,8 @@ type IssuesOptions struct {
 	UpdatedBeforeUnix  int64
 	// prioritize issues from this repo
 	PriorityRepoID int64
+	// read from the primary even if a replica of the database is available
+	UsePrimary bool
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
@@ -1180,6 +1198,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		sess.In("issue.milestone_id", opts.MilestoneIDs)
 	}
 
+	if opts.ParentID > 0 {
+		sess.And("issue.parent_id=?", opts.ParentID)
+	}
+
 	if opts.UpdatedAfterUnix != 0 {
 		sess.And(builder.Gte{"issue.updated_unix": opts.UpdatedAfterUnix})
 	}
@@ -1274,17 +1296,17 @@ func GetRepoIDsForIssuesOptions(opts *IssuesOptions, user *User) ([]int64, error
 
 // Issues returns a list of issues by given conditions.
 func Issues(opts *IssuesOptions) ([]*Issue, error) {
-	sess := x.NewSession()
-	defer sess.Close()
-
-	opts.setupSession(sess)
-	sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID)
-
 	issues := make([]*Issue, 0, opts.ListOptions.PageSize)
-	if err := sess.Find(&issues); err != nil {
+	if err := withReadEngine(opts.UsePrimary, func(e *xorm.Engine) error {
+		sess := e.NewSession()
+		defer sess.Close()
+
+		opts.setupSession(sess)
+		sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID)
+		return sess.Find(&issues)
+	}); err != nil {
 		return nil, fmt.Errorf("Find: %v", err)
 	}
-	sess.Close()
 
 	if err := IssueList(issues).LoadAttributes(); err != nil {
 		return nil, fmt.Errorf("LoadAttributes: %v", err)
@@ -1295,17 +1317,19 @@ func Issues(opts *IssuesOptions) ([]*Issue, error) {
 
 // CountIssues number return of issues by given conditions.
 func CountIssues(opts *IssuesOptions) (int64, error) {
-	sess := x.NewSession()
-	defer sess.Close()
-
 	countsSlice := make([]*struct {
 		RepoID int64
 		Count  int64
 	}, 0, 1)
 
-	sess.Select("COUNT(issue.id) AS count").Table("issue")
-	opts.setupSession(sess)
-	if err := sess.Find(&countsSlice); err != nil {
+	if err := withReadEngine(opts.UsePrimary, func(e *xorm.Engine) error {
+		sess := e.NewSession()
+		defer sess.Close()
+
+		sess.Select("COUNT(issue.id) AS count").Table("issue")
+		opts.setupSession(sess)
+		return sess.Find(&countsSlice)
+	}); err != nil {
 		return 0, fmt.Errorf("Find: %v", err)
 	}
 	if len(countsSlice) < 1 {
@@ -1384,6 +1408,7 @@ type IssueStatsOptions struct {
 	RepoID      int64
 	Labels      string
 	MilestoneID int64
+	ParentID    int64
 	AssigneeID  int64
 	MentionedID int64
 	PosterID    int64
@@ -1453,6 +1478,10 @@ func getIssueStatsChunk(opts *IssueStatsOptions, issueIDs []int64) (*IssueStats,
 			sess.And("issue.milestone_id = ?", opts.MilestoneID)
 		}
 
+		if opts.ParentID > 0 {
+			sess.And("issue.parent_id = ?", opts.ParentID)
+		}
+
 		if opts.AssigneeID > 0 {
 			sess.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
 				And("issue_assignees.assignee_id = ?", opts.AssigneeID)
@@ -1694,6 +1723,18 @@ func UpdateIssueByAPI(issue *Issue, doer *User) (statusChangeComment *Comment, t
 		return nil, false, err
 	}
 
+	if currentIssue.Content != issue.Content {
+		if err := recordContentRevision(sess, &ContentRevision{
+			RepoID:   issue.RepoID,
+			IssueID:  issue.ID,
+			PosterID: issue.PosterID,
+			DoerID:   doer.ID,
+			Content:  currentIssue.Content,
+		}); err != nil {
+			return nil, false, fmt.Errorf("recordContentRevision: %v", err)
+		}
+	}
+
 	if _, err := sess.ID(issue.ID).Cols(
 		"name", "content", "milestone_id", "priority",
 		"deadline_unix", "updated_unix", "is_locked").
@@ -1757,6 +1798,18 @@ func UpdateIssueDeadline(issue *Issue, deadlineUnix timeutil.TimeStamp, doer *Us
 	return sess.Commit()
 }
 
+// UpdateIssueTimeEstimate updates the time estimate of an issue
+func UpdateIssueTimeEstimate(issue *Issue, estimate int64) error {
+	if issue.TimeEstimate == estimate {
+		return nil
+	}
+	if err := updateIssueCols(x, &Issue{ID: issue.ID, TimeEstimate: estimate}, "time_estimate"); err != nil {
+		return err
+	}
+	issue.TimeEstimate = estimate
+	return nil
+}
+
 // DependencyInfo represents high level information about an issue which is a dependency of another issue.
 type DependencyInfo struct {
 	Issue      `xorm:"extends"`
@@ -2022,6 +2075,11 @@ func deleteIssuesByRepoID(sess Engine, repoID int64) (attachmentPaths []string,
 		return
 	}
 
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueReminder{}); err != nil {
+		return
+	}
+
 	if _, err = sess.In("issue_id", deleteCond).
 		Delete(&TrackedTime{}); err != nil {
 		return
@@ -100,8 +100,64 @@ const (
 	CommentTypeProject
 	// Project board changed
 	CommentTypeProjectBoard
+	// Comment a line of the message of a commit of a pull request
+	CommentTypeCommitMessage
 )
 
+var commentStrings = []string{
+	"comment",
+	"reopen",
+	"close",
+	"issue_ref",
+	"commit_ref",
+	"comment_ref",
+	"pull_ref",
+	"label",
+	"milestone",
+	"assignees",
+	"change_title",
+	"delete_branch",
+	"start_tracking",
+	"stop_tracking",
+	"add_time_manual",
+	"cancel_tracking",
+	"added_deadline",
+	"modified_deadline",
+	"removed_deadline",
+	"add_dependency",
+	"remove_dependency",
+	"code",
+	"review",
+	"lock",
+	"unlock",
+	"change_target_branch",
+	"delete_time_manual",
+	"review_request",
+	"merge_pull",
+	"pull_push",
+	"project",
+	"project_board",
+	"commit_message",
+}
+
+// String returns the name of the comment type as used by the API
+func (t CommentType) String() string {
+	if t < 0 || int(t) >= len(commentStrings) {
+		return "unknown"
+	}
+	return commentStrings[t]
+}
+
+// AsCommentType returns the comment type matching the given name, CommentTypeUnknown if there is none
+func AsCommentType(typeName string) CommentType {
+	for index, name := range commentStrings {
+		if typeName == name {
+			return CommentType(index)
+		}
+	}
+	return CommentTypeUnknown
+}
+
 // CommentTag defines comment tag type
 type CommentTag int
 
@@ -282,6 +338,16 @@ func (c *Comment) HTMLURL() string {
 		log.Error("loadRepo(%d): %v", c.Issue.RepoID, err)
 		return ""
 	}
+	if c.Type == CommentTypeCommitMessage && c.ReviewID != 0 {
+		if c.Review == nil {
+			if err := c.LoadReview(); err != nil {
+				log.Warn("LoadReview(%d): %v", c.ReviewID, err)
+			}
+		}
+		if c.Review == nil || c.Review.Type <= ReviewTypePending {
+			return fmt.Sprintf("%s/commits/%s/message#%s", c.Issue.HTMLURL(), c.CommitSHA, c.HashTag())
+		}
+	}
 	if c.Type == CommentTypeCode {
 		if c.ReviewID == 0 {
 			return fmt.Sprintf("%s/files#%s", c.Issue.HTMLURL(), c.HashTag())
@@ -736,7 +802,7 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 func updateCommentInfos(e *xorm.Session, opts *CreateCommentOptions, comment *Comment) (err error) {
 	// Check comment type.
 	switch opts.Type {
-	case CommentTypeCode:
+	case CommentTypeCode, CommentTypeCommitMessage:
 		if comment.ReviewID != 0 {
 			if comment.Review == nil {
 				if err := comment.loadReview(e); err != nil {
@@ -958,6 +1024,10 @@ type FindCommentsOptions struct {
 	Line     int64
 	TreePath string
 	Type     CommentType
+	// Types restricts the comments to several types if Type is CommentTypeUnknown
+	Types []CommentType
+	// SinceID only returns the comments created after the one with this ID
+	SinceID int64
 }
 
 func (opts *FindCommentsOptions) toConds() builder.Cond {
@@ -979,6 +1049,11 @@ func (opts *FindCommentsOptions) toConds() builder.Cond {
 	}
 	if opts.Type != CommentTypeUnknown {
 		cond = cond.And(builder.Eq{"comment.type": opts.Type})
+	} else if len(opts.Types) > 0 {
+		cond = cond.And(builder.In("comment.type", opts.Types))
+	}
+	if opts.SinceID > 0 {
+		cond = cond.And(builder.Gt{"comment.id": opts.SinceID})
 	}
 	if opts.Line > 0 {
 		cond = cond.And(builder.Eq{"comment.line": opts.Line})
@@ -1021,10 +1096,26 @@ func UpdateComment(c *Comment, doer *User) error {
 		return err
 	}
 
-	if _, err := sess.ID(c.ID).AllCols().Update(c); err != nil {
+	if err := c.loadIssue(sess); err != nil {
 		return err
 	}
-	if err := c.loadIssue(sess); err != nil {
+	oldComment := new(Comment)
+	if has, err := sess.ID(c.ID).Cols("content").Get(oldComment); err != nil {
+		return err
+	} else if has && oldComment.Content != c.Content {
+		if err := recordContentRevision(sess, &ContentRevision{
+			RepoID:    c.Issue.RepoID,
+			IssueID:   c.IssueID,
+			CommentID: c.ID,
+			PosterID:  c.PosterID,
+			DoerID:    doer.ID,
+			Content:   oldComment.Content,
+		}); err != nil {
+			return fmt.Errorf("recordContentRevision: %v", err)
+		}
+	}
+
+	if _, err := sess.ID(c.ID).AllCols().Update(c); err != nil {
 		return err
 	}
 	if err := c.addCrossReferences(sess, doer, true); err != nil {
@@ -1045,6 +1136,21 @@ func DeleteComment(comment *Comment, doer *User) error {
 		return err
 	}
 
+	if err := comment.loadIssue(sess); err != nil {
+		return err
+	}
+	if err := recordContentRevision(sess, &ContentRevision{
+		RepoID:    comment.Issue.RepoID,
+		IssueID:   comment.IssueID,
+		CommentID: comment.ID,
+		PosterID:  comment.PosterID,
+		DoerID:    doer.ID,
+		Content:   comment.Content,
+		IsDeleted: true,
+	}); err != nil {
+		return fmt.Errorf("recordContentRevision: %v", err)
+	}
+
 	if _, err := sess.Delete(&Comment{
 		ID: comment.ID,
 	}); err != nil {
@@ -1153,6 +1259,51 @@ func FetchCodeComments(issue *Issue, currentUser *User) (CodeComments, error) {
 	return fetchCodeComments(x, issue, currentUser)
 }
 
+func fetchCommitMessageComments(e Engine, issue *Issue, cond builder.Cond) ([]*Comment, error) {
+	var comments []*Comment
+	if err := e.Where(cond.And(builder.Eq{"comment.issue_id": issue.ID, "comment.type": CommentTypeCommitMessage})).
+		Asc("comment.created_unix").
+		Asc("comment.id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+
+	if err := issue.loadRepo(e); err != nil {
+		return nil, err
+	}
+	if err := CommentList(comments).loadPosters(e); err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		comment.Issue = issue
+		comment.RenderedContent = string(markdown.Render([]byte(comment.Content), issue.Repo.Link(),
+			issue.Repo.ComposeMetas()))
+	}
+	return comments, nil
+}
+
+// FetchCommitMessageComments returns the comments on the lines of the message of a commit of a pull request,
+// the comments of a pending review are only returned to its reviewer
+func FetchCommitMessageComments(issue *Issue, commitID string, currentUser *User) ([]*Comment, error) {
+	comments, err := fetchCommitMessageComments(x, issue, builder.Eq{"comment.commit_sha": commitID})
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*Comment, 0, len(comments))
+	for _, comment := range comments {
+		if err := comment.loadReview(x); err != nil && !IsErrReviewNotExist(err) {
+			return nil, err
+		}
+		if comment.Review != nil && comment.Review.Type == ReviewTypePending &&
+			(currentUser == nil || currentUser.ID != comment.Review.ReviewerID) {
+			continue
+		}
+		visible = append(visible, comment)
+	}
+	return visible, nil
+}
+
 // UpdateCommentsMigrationsByType updates comments' migrations information via given git service type and original id and poster id
 func UpdateCommentsMigrationsByType(tp structs.GitServiceType, originalAuthorID string, posterID int64) error {
 	_, err := x.Table("comment").
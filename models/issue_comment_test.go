@@ -57,3 +57,44 @@ func TestFetchCodeComments(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, res, 1)
 }
+
+func TestAsCommentType(t *testing.T) {
+	assert.Equal(t, CommentTypeUnknown, AsCommentType(""))
+	assert.Equal(t, CommentTypeUnknown, AsCommentType("nonsense"))
+	assert.Equal(t, CommentTypeComment, AsCommentType("comment"))
+	assert.Equal(t, CommentTypeProjectBoard, AsCommentType("project_board"))
+	assert.Equal(t, "pull_push", CommentTypePullPush.String())
+	assert.Equal(t, "unknown", CommentTypeUnknown.String())
+}
+
+func TestFindCommentsByTypes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	comments, err := FindComments(FindCommentsOptions{
+		IssueID: 1,
+		Type:    CommentTypeUnknown,
+		Types:   []CommentType{CommentTypeLabel, CommentTypeComment},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, comments, 3)
+
+	comments, err = FindComments(FindCommentsOptions{
+		IssueID: 1,
+		Type:    CommentTypeUnknown,
+		Types:   []CommentType{CommentTypeLabel},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 1, comments[0].ID)
+	}
+
+	comments, err = FindComments(FindCommentsOptions{
+		IssueID: 1,
+		Type:    CommentTypeUnknown,
+		SinceID: 2,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 3, comments[0].ID)
+	}
+}
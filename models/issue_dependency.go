@@ -5,9 +5,13 @@
 package models
 
 import (
+	"sort"
+
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // IssueDependency represents an issue dependency
@@ -142,3 +146,147 @@ func (repo *Repository) isDependenciesEnabled(e Engine) bool {
 	}
 	return u.IssuesConfig().EnableDependencies
 }
+
+// MaxIssueDependencyGraphIssues is the maximum number of issues of a dependency graph
+const MaxIssueDependencyGraphIssues = 500
+
+// IssueDependencyGraph represents the issues which transitively block or are blocked
+// by a set of issues, and their dependencies
+type IssueDependencyGraph struct {
+	// issues of the graph, with their repository loaded
+	Issues       IssueList
+	Dependencies []*IssueDependency
+	// whether the graph has more than MaxIssueDependencyGraphIssues issues
+	Truncated bool
+}
+
+// GetIssueDependencyGraph returns the dependency graph of a set of issues
+func GetIssueDependencyGraph(issueIDs []int64) (*IssueDependencyGraph, error) {
+	return getIssueDependencyGraph(x, issueIDs)
+}
+
+// GetMilestoneDependencyGraph returns the dependency graph of the issues of a milestone
+func GetMilestoneDependencyGraph(milestoneID int64) (*IssueDependencyGraph, error) {
+	issueIDs := make([]int64, 0, 10)
+	if err := x.Table("issue").Where("milestone_id = ?", milestoneID).Cols("id").Find(&issueIDs); err != nil {
+		return nil, err
+	}
+	return getIssueDependencyGraph(x, issueIDs)
+}
+
+func getIssueDependencyGraph(e Engine, issueIDs []int64) (*IssueDependencyGraph, error) {
+	graph := &IssueDependencyGraph{}
+	inGraph := make(map[int64]bool, len(issueIDs))
+	ids := make([]int64, 0, len(issueIDs))
+	for _, id := range issueIDs {
+		if !inGraph[id] && len(ids) < MaxIssueDependencyGraphIssues {
+			inGraph[id] = true
+			ids = append(ids, id)
+		}
+	}
+	graph.Truncated = len(ids) < len(issueIDs)
+
+	deps := make(map[int64]*IssueDependency)
+	for frontier := ids; len(frontier) > 0; {
+		found := make([]*IssueDependency, 0, 10)
+		if err := e.
+			Where(builder.In("issue_id", frontier).Or(builder.In("dependency_id", frontier))).
+			Find(&found); err != nil {
+			return nil, err
+		}
+
+		frontier = make([]int64, 0, len(found))
+		for _, dep := range found {
+			if _, ok := deps[dep.ID]; ok {
+				continue
+			}
+			deps[dep.ID] = dep
+			for _, id := range []int64{dep.IssueID, dep.DependencyID} {
+				if inGraph[id] {
+					continue
+				}
+				if len(ids) >= MaxIssueDependencyGraphIssues {
+					graph.Truncated = true
+					continue
+				}
+				inGraph[id] = true
+				ids = append(ids, id)
+				frontier = append(frontier, id)
+			}
+		}
+	}
+
+	issues, err := getIssuesByIDs(e, ids)
+	if err != nil {
+		return nil, err
+	}
+	graph.Issues = issues
+	if _, err = graph.Issues.loadRepositories(e); err != nil {
+		return nil, err
+	}
+
+	graph.Dependencies = make([]*IssueDependency, 0, len(deps))
+	for _, dep := range deps {
+		if inGraph[dep.IssueID] && inGraph[dep.DependencyID] {
+			graph.Dependencies = append(graph.Dependencies, dep)
+		}
+	}
+	sort.Slice(graph.Dependencies, func(i, j int) bool {
+		return graph.Dependencies[i].ID < graph.Dependencies[j].ID
+	})
+	return graph, nil
+}
+
+// Levels returns the level of each issue of the graph by ID: 0 for an issue which isn't
+// blocked by any issue of the graph, otherwise one more than the level of its blockers
+func (graph *IssueDependencyGraph) Levels() map[int64]int {
+	levels := make(map[int64]int, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		levels[issue.ID] = 0
+	}
+	// a dependency chain has at most one issue per level, stop relaxing if there is a cycle
+	for i := 0; i < len(graph.Issues); i++ {
+		changed := false
+		for _, dep := range graph.Dependencies {
+			if level := levels[dep.DependencyID] + 1; level > levels[dep.IssueID] && level < len(graph.Issues) {
+				levels[dep.IssueID] = level
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return levels
+}
+
+// FilterReadable removes the issues the user can't read from the graph, with their dependencies
+func (graph *IssueDependencyGraph) FilterReadable(user *User) error {
+	perms := make(map[int64]Permission)
+	readable := make(map[int64]bool, len(graph.Issues))
+	issues := make(IssueList, 0, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		perm, ok := perms[issue.RepoID]
+		if !ok {
+			var err error
+			if perm, err = GetUserRepoPermission(issue.Repo, user); err != nil {
+				return err
+			}
+			perms[issue.RepoID] = perm
+		}
+		if perm.CanReadIssuesOrPulls(issue.IsPull) {
+			readable[issue.ID] = true
+			issues = append(issues, issue)
+		}
+	}
+	graph.Issues = issues
+
+	deps := make([]*IssueDependency, 0, len(graph.Dependencies))
+	for _, dep := range graph.Dependencies {
+		if readable[dep.IssueID] && readable[dep.DependencyID] {
+			deps = append(deps, dep)
+		}
+	}
+	graph.Dependencies = deps
+	return nil
+}
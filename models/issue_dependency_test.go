@@ -56,3 +56,35 @@ func TestCreateIssueDependency(t *testing.T) {
 	err = RemoveIssueDependency(user1, issue1, issue2, DependencyTypeBlockedBy)
 	assert.NoError(t, err)
 }
+
+func TestGetIssueDependencyGraph(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	// #1 is blocked by #2 which is blocked by #3, #5 is blocked by #1
+	assert.NoError(t, CreateIssueDependency(user1, issue1, issue2))
+	assert.NoError(t, CreateIssueDependency(user1, issue2, issue3))
+	assert.NoError(t, CreateIssueDependency(user1, issue5, issue1))
+
+	for _, id := range []int64{1, 2, 3, 5} {
+		graph, err := GetIssueDependencyGraph([]int64{id})
+		assert.NoError(t, err)
+		assert.False(t, graph.Truncated)
+		assert.Len(t, graph.Issues, 4)
+		assert.Len(t, graph.Dependencies, 3)
+		for _, issue := range graph.Issues {
+			assert.NotNil(t, issue.Repo)
+		}
+		assert.Equal(t, map[int64]int{3: 0, 2: 1, 1: 2, 5: 3}, graph.Levels())
+	}
+
+	graph, err := GetIssueDependencyGraph([]int64{4})
+	assert.NoError(t, err)
+	assert.Len(t, graph.Issues, 1)
+	assert.Empty(t, graph.Dependencies)
+}
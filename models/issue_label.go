@@ -284,6 +284,10 @@ func DeleteLabel(id, labelID int64) error {
 		Where("label_id = ?", labelID).
 		Delete(new(IssueLabel)); err != nil {
 		return err
+	} else if _, err = sess.
+		Where("label_id = ?", labelID).
+		Delete(new(ProjectAutomationRule)); err != nil {
+		return err
 	}
 
 	// delete comments about now deleted label_id
@@ -590,6 +594,11 @@ func GetLabelsByIssueID(issueID int64) ([]*Label, error) {
 	return getLabelsByIssueID(x, issueID)
 }
 
+// RecalculateLabelCounts recalculates the numbers of issues and closed issues of a label
+func RecalculateLabelCounts(labelID int64) error {
+	return updateLabelCols(x, &Label{ID: labelID})
+}
+
 func updateLabelCols(e Engine, l *Label, cols ...string) error {
 	_, err := e.ID(l.ID).
 		SetExpr("num_issues",
@@ -0,0 +1,216 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MilestoneStatsInterval is the length of the buckets of the milestone stats
+type MilestoneStatsInterval string
+
+// Milestone stats intervals
+const (
+	MilestoneStatsIntervalDay  MilestoneStatsInterval = "day"
+	MilestoneStatsIntervalWeek MilestoneStatsInterval = "week"
+)
+
+// IsValid returns whether the interval is supported
+func (interval MilestoneStatsInterval) IsValid() bool {
+	return interval == MilestoneStatsIntervalDay || interval == MilestoneStatsIntervalWeek
+}
+
+func (interval MilestoneStatsInterval) duration() time.Duration {
+	if interval == MilestoneStatsIntervalWeek {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// MaxMilestoneStatsBuckets is the maximum number of buckets of the milestone stats,
+// only the latest buckets are returned for older milestones
+const MaxMilestoneStatsBuckets = 366
+
+// MilestoneStatsBucket represents the state of the issues of a milestone at the end of a bucket
+type MilestoneStatsBucket struct {
+	End          timeutil.TimeStamp
+	OpenIssues   int
+	ClosedIssues int
+	// sum of the time estimates of the issues of the milestone
+	EstimatedTime int64
+	// time tracked on the issues of the milestone until the end of the bucket
+	TrackedTime int64
+}
+
+// MilestoneStats represents the burndown of a milestone
+type MilestoneStats struct {
+	Interval MilestoneStatsInterval
+	Buckets  []*MilestoneStatsBucket
+}
+
+type historyChange struct {
+	unix  timeutil.TimeStamp
+	value bool
+}
+
+// issueHistory represents the changes of the milestone and the state of an issue
+type issueHistory struct {
+	issue       *Issue
+	inMilestone bool
+	milestone   []historyChange
+	closed      []historyChange
+	tracked     []*TrackedTime
+}
+
+func valueAt(initial bool, changes []historyChange, unix timeutil.TimeStamp) bool {
+	value := initial
+	for _, change := range changes {
+		if change.unix > unix {
+			break
+		}
+		value = change.value
+	}
+	return value
+}
+
+func (h *issueHistory) trackedTimeAt(unix timeutil.TimeStamp) int64 {
+	var total int64
+	for _, t := range h.tracked {
+		if timeutil.TimeStamp(t.CreatedUnix) <= unix {
+			total += t.Time
+		}
+	}
+	return total
+}
+
+// GetMilestoneStats returns the number of open and closed issues, and the estimated and tracked
+// time of a milestone at the end of each interval since its creation, computed from the
+// history of its issues
+func GetMilestoneStats(m *Milestone, interval MilestoneStatsInterval) (*MilestoneStats, error) {
+	return getMilestoneStats(m, interval, timeutil.TimeStampNow())
+}
+
+func getMilestoneStats(m *Milestone, interval MilestoneStatsInterval, now timeutil.TimeStamp) (*MilestoneStats, error) {
+	histories, err := getMilestoneIssueHistories(m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	until := now
+	if m.IsClosed && m.ClosedDateUnix > 0 && m.ClosedDateUnix < until {
+		until = m.ClosedDateUnix
+	}
+	step := interval.duration()
+	created := m.CreatedUnix.AsTime().UTC()
+	start := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+	if skip := int(until.AsTime().Sub(start)/step) + 1 - MaxMilestoneStatsBuckets; skip > 0 {
+		start = start.Add(time.Duration(skip) * step)
+	}
+
+	stats := &MilestoneStats{
+		Interval: interval,
+		Buckets:  make([]*MilestoneStatsBucket, 0, 10),
+	}
+	for end := start.Add(step); ; end = end.Add(step) {
+		bucket := &MilestoneStatsBucket{End: timeutil.TimeStamp(end.Unix())}
+		if bucket.End > until {
+			bucket.End = until
+		}
+		for _, h := range histories {
+			if h.issue.CreatedUnix > bucket.End || !valueAt(h.inMilestone, h.milestone, bucket.End) {
+				continue
+			}
+			if valueAt(false, h.closed, bucket.End) {
+				bucket.ClosedIssues++
+			} else {
+				bucket.OpenIssues++
+			}
+			bucket.EstimatedTime += h.issue.TimeEstimate
+			bucket.TrackedTime += h.trackedTimeAt(bucket.End)
+		}
+		stats.Buckets = append(stats.Buckets, bucket)
+		if bucket.End >= until {
+			break
+		}
+	}
+	return stats, nil
+}
+
+// getMilestoneIssueHistories returns the histories of the issues which are or were in a milestone
+func getMilestoneIssueHistories(milestoneID int64) ([]*issueHistory, error) {
+	issueIDs := make([]int64, 0, 10)
+	if err := x.Table("issue").Where(builder.Eq{"milestone_id": milestoneID}.Or(builder.In("id",
+		builder.Select("issue_id").From("comment").Where(builder.Eq{"type": CommentTypeMilestone}.And(
+			builder.Eq{"milestone_id": milestoneID}.Or(builder.Eq{"old_milestone_id": milestoneID})))))).
+		Cols("id").Find(&issueIDs); err != nil {
+		return nil, err
+	}
+	if len(issueIDs) == 0 {
+		return nil, nil
+	}
+
+	issues, err := getIssuesByIDs(x, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	histories := make(map[int64]*issueHistory, len(issues))
+	result := make([]*issueHistory, 0, len(issues))
+	for _, issue := range issues {
+		h := &issueHistory{issue: issue}
+		histories[issue.ID] = h
+		result = append(result, h)
+	}
+
+	comments := make([]*Comment, 0, 10)
+	if err := x.In("issue_id", issueIDs).
+		In("type", CommentTypeMilestone, CommentTypeClose, CommentTypeReopen, CommentTypeMergePull).
+		Asc("created_unix", "id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+	hasMilestoneComment := make(map[int64]bool, len(issues))
+	for _, c := range comments {
+		h := histories[c.IssueID]
+		switch c.Type {
+		case CommentTypeMilestone:
+			// the milestone of an issue before its first change is the old milestone of the change
+			if !hasMilestoneComment[c.IssueID] {
+				hasMilestoneComment[c.IssueID] = true
+				h.inMilestone = c.OldMilestoneID == milestoneID
+			}
+			h.milestone = append(h.milestone, historyChange{unix: c.CreatedUnix, value: c.MilestoneID == milestoneID})
+		case CommentTypeClose, CommentTypeMergePull:
+			h.closed = append(h.closed, historyChange{unix: c.CreatedUnix, value: true})
+		case CommentTypeReopen:
+			h.closed = append(h.closed, historyChange{unix: c.CreatedUnix, value: false})
+		}
+	}
+	for _, h := range result {
+		if !hasMilestoneComment[h.issue.ID] {
+			h.inMilestone = h.issue.MilestoneID == milestoneID
+		}
+		// e.g. migrated issues are closed without a comment
+		if len(h.closed) == 0 && h.issue.IsClosed {
+			closed := h.issue.ClosedUnix
+			if closed == 0 {
+				closed = h.issue.CreatedUnix
+			}
+			h.closed = append(h.closed, historyChange{unix: closed, value: true})
+		}
+	}
+
+	tracked := make([]*TrackedTime, 0, 10)
+	if err := x.In("issue_id", issueIDs).And("deleted = ?", false).Find(&tracked); err != nil {
+		return nil, err
+	}
+	for _, t := range tracked {
+		histories[t.IssueID].tracked = append(histories[t.IssueID].tracked, t)
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMilestoneStats(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	const day = timeutil.TimeStamp(24 * 60 * 60)
+	const hour = timeutil.TimeStamp(60 * 60)
+	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	milestone.CreatedUnix = 946684800
+
+	// issue 1 is moved into the milestone on the first day and out of it on the second one,
+	// issue 2 is in the milestone since its creation and closed on the first day
+	_, err := x.NoAutoTime().Insert(
+		&Comment{Type: CommentTypeMilestone, PosterID: 1, IssueID: 1, MilestoneID: 1, CreatedUnix: milestone.CreatedUnix + day + hour},
+		&Comment{Type: CommentTypeMilestone, PosterID: 1, IssueID: 1, OldMilestoneID: 1, CreatedUnix: milestone.CreatedUnix + 2*day + hour},
+		&Comment{Type: CommentTypeClose, PosterID: 1, IssueID: 2, CreatedUnix: milestone.CreatedUnix + day + 2*hour},
+		&TrackedTime{UserID: 1, IssueID: 2, Time: 60, CreatedUnix: int64(milestone.CreatedUnix + day + hour)},
+	)
+	assert.NoError(t, err)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, UpdateIssueTimeEstimate(issue2, 7200))
+
+	stats, err := getMilestoneStats(milestone, MilestoneStatsIntervalDay, milestone.CreatedUnix+3*day+hour)
+	assert.NoError(t, err)
+	assert.Equal(t, MilestoneStatsIntervalDay, stats.Interval)
+	assert.Equal(t, []*MilestoneStatsBucket{
+		{End: milestone.CreatedUnix + day, OpenIssues: 1, EstimatedTime: 7200, TrackedTime: 3682},
+		{End: milestone.CreatedUnix + 2*day, OpenIssues: 1, ClosedIssues: 1, EstimatedTime: 7200, TrackedTime: 400 + 3682 + 60},
+		{End: milestone.CreatedUnix + 3*day, ClosedIssues: 1, EstimatedTime: 7200, TrackedTime: 3682 + 60},
+		{End: milestone.CreatedUnix + 3*day + hour, ClosedIssues: 1, EstimatedTime: 7200, TrackedTime: 3682 + 60},
+	}, stats.Buckets)
+
+	stats, err = getMilestoneStats(milestone, MilestoneStatsIntervalWeek, milestone.CreatedUnix+3*day+hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MilestoneStatsBucket{
+		{End: milestone.CreatedUnix + 3*day + hour, ClosedIssues: 1, EstimatedTime: 7200, TrackedTime: 3682 + 60},
+	}, stats.Buckets)
+
+	// only the latest buckets of old milestones are returned
+	milestone.CreatedUnix = 0
+	stats, err = getMilestoneStats(milestone, MilestoneStatsIntervalDay, 946684800+3*day+hour)
+	assert.NoError(t, err)
+	assert.Len(t, stats.Buckets, MaxMilestoneStatsBuckets)
+	assert.EqualValues(t, 946684800+3*day+hour, stats.Buckets[MaxMilestoneStatsBuckets-1].End)
+
+	stats, err = getMilestoneStats(&Milestone{ID: 2, CreatedUnix: 946684800}, MilestoneStatsIntervalDay, 946684800+hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MilestoneStatsBucket{{End: 946684800 + hour}}, stats.Buckets)
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueReminder represents a notification of an issue scheduled by a user
+type IssueReminder struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"INDEX NOT NULL"`
+	IssueID     int64              `xorm:"INDEX NOT NULL"`
+	RemindUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// CreateIssueReminder schedules a notification of the issue for the user
+func CreateIssueReminder(user *User, issue *Issue, remindUnix timeutil.TimeStamp) (*IssueReminder, error) {
+	reminder := &IssueReminder{
+		UserID:     user.ID,
+		IssueID:    issue.ID,
+		RemindUnix: remindUnix,
+	}
+	if _, err := x.Insert(reminder); err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+// GetIssueReminders returns the pending reminders of the issue scheduled by the user
+func GetIssueReminders(userID, issueID int64) ([]*IssueReminder, error) {
+	reminders := make([]*IssueReminder, 0, 2)
+	return reminders, x.
+		Where("user_id = ?", userID).
+		And("issue_id = ?", issueID).
+		Asc("remind_unix").
+		Find(&reminders)
+}
+
+// GetIssueReminderByID returns the reminder of the user of the given ID
+func GetIssueReminderByID(userID, id int64) (*IssueReminder, error) {
+	reminder := new(IssueReminder)
+	has, err := x.
+		Where("id = ?", id).
+		And("user_id = ?", userID).
+		Get(reminder)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueReminderNotExist{ID: id}
+	}
+	return reminder, nil
+}
+
+// DeleteIssueReminder cancels a reminder of the user
+func DeleteIssueReminder(userID, id int64) error {
+	affected, err := x.
+		Where("id = ?", id).
+		And("user_id = ?", userID).
+		Delete(new(IssueReminder))
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrIssueReminderNotExist{ID: id}
+	}
+	return nil
+}
+
+// DeliverIssueReminders notifies the users of the issues of their reminders which are due,
+// the delivered reminders are deleted
+func DeliverIssueReminders(ctx context.Context) error {
+	log.Trace("Doing: DeliverIssueReminders")
+
+	reminders := make([]*IssueReminder, 0, 50)
+	if err := x.
+		Where("remind_unix <= ?", timeutil.TimeStampNow()).
+		Asc("remind_unix").
+		Find(&reminders); err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before delivering the reminder %d", reminder.ID)
+		default:
+		}
+
+		if err := deliverIssueReminder(reminder); err != nil {
+			log.Error("deliverIssueReminder[%d]: %v", reminder.ID, err)
+		}
+	}
+
+	log.Trace("Finished: DeliverIssueReminders")
+	return nil
+}
+
+func deliverIssueReminder(reminder *IssueReminder) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	// The user is only notified if the issue is still readable
+	err := createOrUpdateIssueNotifications(sess, reminder.IssueID, 0, reminder.UserID, reminder.UserID)
+	if err != nil && !IsErrIssueNotExist(err) {
+		return err
+	}
+	if _, err = sess.ID(reminder.ID).Delete(new(IssueReminder)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliverIssueReminders(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	AssertNotExistsBean(t, &Notification{UserID: user.ID, IssueID: issue.ID})
+
+	due, err := CreateIssueReminder(user, issue, timeutil.TimeStampNow()-60)
+	assert.NoError(t, err)
+	pending, err := CreateIssueReminder(user, issue, timeutil.TimeStampNow()+3600)
+	assert.NoError(t, err)
+
+	reminders, err := GetIssueReminders(user.ID, issue.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, reminders, 2) {
+		assert.EqualValues(t, due.ID, reminders[0].ID)
+		assert.EqualValues(t, pending.ID, reminders[1].ID)
+	}
+
+	assert.NoError(t, DeliverIssueReminders(context.Background()))
+	AssertExistsAndLoadBean(t, &Notification{UserID: user.ID, IssueID: issue.ID, Status: NotificationStatusUnread})
+	AssertNotExistsBean(t, &IssueReminder{ID: due.ID})
+	AssertExistsAndLoadBean(t, &IssueReminder{ID: pending.ID})
+
+	assert.True(t, IsErrIssueReminderNotExist(DeleteIssueReminder(1, pending.ID)))
+	assert.NoError(t, DeleteIssueReminder(user.ID, pending.ID))
+	AssertNotExistsBean(t, &IssueReminder{ID: pending.ID})
+}
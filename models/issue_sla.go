@@ -0,0 +1,355 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueSLAPolicy represents a service level agreement of an organization, the issues having its label
+// in the repositories of the organization must get a first response and be resolved in time
+type IssueSLAPolicy struct {
+	ID      int64  `xorm:"pk autoincr"`
+	OrgID   int64  `xorm:"INDEX NOT NULL"`
+	Name    string `xorm:"NOT NULL"`
+	Enabled bool   `xorm:"INDEX NOT NULL DEFAULT false"`
+	// Label is the name of the label of the issues the policy applies to, it may be a label of the
+	// organization or of any of its repositories
+	Label        string `xorm:"NOT NULL"`
+	IncludePulls bool   `xorm:"NOT NULL DEFAULT false"`
+	// ResponseHours is the number of hours the issues must get a first response from a member of the
+	// organization within, there is no response target if it is 0
+	ResponseHours int `xorm:"NOT NULL DEFAULT 0"`
+	// ResolutionHours is the number of hours the issues must be closed within, there is no resolution
+	// target if it is 0
+	ResolutionHours int `xorm:"NOT NULL DEFAULT 0"`
+	// BreachLabel is the name of the label added to the issues breaching a target, none is added if it is empty
+	BreachLabel string
+	// BreachComment is posted on the issues breaching a target, e.g. to mention the people to notify
+	BreachComment string `xorm:"TEXT"`
+	// DoerID is the user which last changed the policy, the actions are taken on its behalf
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// CreateIssueSLAPolicy creates a SLA policy
+func CreateIssueSLAPolicy(policy *IssueSLAPolicy) error {
+	_, err := x.Insert(policy)
+	return err
+}
+
+// GetIssueSLAPolicyByID returns the SLA policy of the organization of the given ID
+func GetIssueSLAPolicyByID(orgID, id int64) (*IssueSLAPolicy, error) {
+	policy := new(IssueSLAPolicy)
+	has, err := x.
+		Where("id = ?", id).
+		And("org_id = ?", orgID).
+		Get(policy)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueSLAPolicyNotExist{ID: id}
+	}
+	return policy, nil
+}
+
+// GetIssueSLAPolicies returns the SLA policies of the organization
+func GetIssueSLAPolicies(orgID int64) ([]*IssueSLAPolicy, error) {
+	policies := make([]*IssueSLAPolicy, 0, 5)
+	return policies, x.Where("org_id = ?", orgID).Asc("name", "id").Find(&policies)
+}
+
+// GetEnabledIssueSLAPolicies returns the enabled SLA policies of all the organizations
+func GetEnabledIssueSLAPolicies() ([]*IssueSLAPolicy, error) {
+	policies := make([]*IssueSLAPolicy, 0, 10)
+	return policies, x.Where("enabled = ?", true).Asc("id").Find(&policies)
+}
+
+// UpdateIssueSLAPolicy updates all the columns of a SLA policy
+func UpdateIssueSLAPolicy(policy *IssueSLAPolicy) error {
+	_, err := x.ID(policy.ID).AllCols().Omit("created_unix").Update(policy)
+	return err
+}
+
+// DeleteIssueSLAPolicy deletes a SLA policy of the organization and its breaches
+func DeleteIssueSLAPolicy(orgID, id int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	affected, err := sess.
+		Where("id = ?", id).
+		And("org_id = ?", orgID).
+		Delete(new(IssueSLAPolicy))
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrIssueSLAPolicyNotExist{ID: id}
+	}
+	if _, err := sess.Where("policy_id = ?", id).Delete(new(IssueSLABreach)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// labelIDsBuilder selects the IDs of the labels of the policy, i.e. the labels of the organization and
+// of its repositories which have the name of the policy
+func (p *IssueSLAPolicy) labelIDsBuilder() *builder.Builder {
+	return builder.Select("id").From("label").Where(builder.Eq{"name": p.Label}.And(
+		builder.Eq{"org_id": p.OrgID}.Or(
+			builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": p.OrgID})))))
+}
+
+// issuesCond returns the condition of the issues of the repositories of the organization which have
+// the label of the policy
+func (p *IssueSLAPolicy) issuesCond() builder.Cond {
+	cond := builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": p.OrgID})).
+		And(builder.In("id", builder.Select("issue_id").From("issue_label").Where(builder.In("label_id", p.labelIDsBuilder()))))
+	if !p.IncludePulls {
+		cond = cond.And(builder.Eq{"is_pull": false})
+	}
+	return cond
+}
+
+// FindOpenIssues returns the open issues the policy applies to
+func (p *IssueSLAPolicy) FindOpenIssues() ([]*Issue, error) {
+	issues := make([]*Issue, 0, 10)
+	return issues, x.
+		Where(p.issuesCond()).
+		And("is_closed = ?", false).
+		Asc("id").
+		Find(&issues)
+}
+
+// FindIssues returns the issues the policy applies to which have been created over a period, since
+// and before are ignored if they are 0
+func (p *IssueSLAPolicy) FindIssues(since, before timeutil.TimeStamp) ([]*Issue, error) {
+	cond := p.issuesCond()
+	if since > 0 {
+		cond = cond.And(builder.Gte{"created_unix": since})
+	}
+	if before > 0 {
+		cond = cond.And(builder.Lte{"created_unix": before})
+	}
+	issues := make([]*Issue, 0, 10)
+	return issues, x.Where(cond).Asc("id").Find(&issues)
+}
+
+// IssueSLAStatus represents the times of an issue which a SLA policy applies to and whether it
+// breaches the targets of the policy
+type IssueSLAStatus struct {
+	Issue *Issue
+	// StartUnix is the time the label of the policy has last been added to the issue, or the time the
+	// issue has been created if it had the label from the start
+	StartUnix timeutil.TimeStamp
+	// ResponseUnix is the time of the first comment of a member of the organization except the poster
+	// since the start, or the time the issue has been closed if it is earlier. It is 0 if there is neither.
+	ResponseUnix timeutil.TimeStamp
+	// ResolutionUnix is the time the issue has been closed, it is 0 if it is open
+	ResolutionUnix     timeutil.TimeStamp
+	ResponseBreached   bool
+	ResolutionBreached bool
+}
+
+// ResponseDeadline returns the time the issue must get a first response before
+func (p *IssueSLAPolicy) ResponseDeadline(status *IssueSLAStatus) timeutil.TimeStamp {
+	return status.StartUnix.Add(int64(p.ResponseHours) * 3600)
+}
+
+// ResolutionDeadline returns the time the issue must be closed before
+func (p *IssueSLAPolicy) ResolutionDeadline(status *IssueSLAStatus) timeutil.TimeStamp {
+	return status.StartUnix.Add(int64(p.ResolutionHours) * 3600)
+}
+
+// isBreached returns whether a target is breached at the time now, the target is met by an event
+// occurring before its deadline
+func isBreached(deadline, eventUnix, now timeutil.TimeStamp) bool {
+	if eventUnix > 0 {
+		return eventUnix > deadline
+	}
+	return now > deadline
+}
+
+// GetIssueSLAStatuses returns the statuses of issues which the policy applies to at the time now
+func (p *IssueSLAPolicy) GetIssueSLAStatuses(issues []*Issue, now timeutil.TimeStamp) ([]*IssueSLAStatus, error) {
+	if len(issues) == 0 {
+		return []*IssueSLAStatus{}, nil
+	}
+	issueIDs := make([]int64, 0, len(issues))
+	statuses := make(map[int64]*IssueSLAStatus, len(issues))
+	for _, issue := range issues {
+		issueIDs = append(issueIDs, issue.ID)
+		statuses[issue.ID] = &IssueSLAStatus{Issue: issue, StartUnix: issue.CreatedUnix}
+		if issue.IsClosed {
+			statuses[issue.ID].ResolutionUnix = issue.ClosedUnix
+		}
+	}
+
+	labelComments := make([]*Comment, 0, len(issues))
+	if err := x.
+		In("issue_id", issueIDs).
+		And(builder.Eq{"type": CommentTypeLabel, "content": "1"}).
+		And(builder.In("label_id", p.labelIDsBuilder())).
+		Find(&labelComments); err != nil {
+		return nil, err
+	}
+	for _, comment := range labelComments {
+		if status := statuses[comment.IssueID]; comment.CreatedUnix > status.StartUnix {
+			status.StartUnix = comment.CreatedUnix
+		}
+	}
+
+	responses := make([]*Comment, 0, len(issues))
+	if err := x.
+		In("issue_id", issueIDs).
+		And(builder.In("type", CommentTypeComment, CommentTypeReview)).
+		And(builder.In("poster_id", builder.Select("uid").From("org_user").Where(builder.Eq{"org_id": p.OrgID}))).
+		Asc("created_unix", "id").
+		Find(&responses); err != nil {
+		return nil, err
+	}
+	for _, comment := range responses {
+		status := statuses[comment.IssueID]
+		if comment.PosterID == status.Issue.PosterID || comment.CreatedUnix < status.StartUnix {
+			continue
+		}
+		if status.ResponseUnix == 0 {
+			status.ResponseUnix = comment.CreatedUnix
+		}
+	}
+
+	results := make([]*IssueSLAStatus, 0, len(issues))
+	for _, issue := range issues {
+		status := statuses[issue.ID]
+		if status.ResolutionUnix > 0 && (status.ResponseUnix == 0 || status.ResolutionUnix < status.ResponseUnix) {
+			status.ResponseUnix = status.ResolutionUnix
+		}
+		if p.ResponseHours > 0 {
+			status.ResponseBreached = isBreached(p.ResponseDeadline(status), status.ResponseUnix, now)
+		}
+		if p.ResolutionHours > 0 {
+			status.ResolutionBreached = isBreached(p.ResolutionDeadline(status), status.ResolutionUnix, now)
+		}
+		results = append(results, status)
+	}
+	return results, nil
+}
+
+// IssueSLATarget represents a target of a SLA policy
+type IssueSLATarget int
+
+// The targets of the SLA policies
+const (
+	IssueSLATargetResponse IssueSLATarget = iota + 1
+	IssueSLATargetResolution
+)
+
+var issueSLATargetNames = map[IssueSLATarget]string{
+	IssueSLATargetResponse:   "response",
+	IssueSLATargetResolution: "resolution",
+}
+
+func (t IssueSLATarget) String() string {
+	return issueSLATargetNames[t]
+}
+
+// IssueSLABreach represents the record of an issue breaching a target of a SLA policy, an issue
+// breaches each target of a policy at most once
+type IssueSLABreach struct {
+	ID           int64              `xorm:"pk autoincr"`
+	PolicyID     int64              `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Target       IssueSLATarget     `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID       int64              `xorm:"INDEX NOT NULL"`
+	DeadlineUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// HasIssueSLABreach returns whether the breach of a target of the policy by the issue has been recorded
+func HasIssueSLABreach(policyID, issueID int64, target IssueSLATarget) (bool, error) {
+	return x.Exist(&IssueSLABreach{PolicyID: policyID, IssueID: issueID, Target: target})
+}
+
+// CreateIssueSLABreach records the breach of a target of the policy by the issue
+func CreateIssueSLABreach(policy *IssueSLAPolicy, issue *Issue, target IssueSLATarget, deadline timeutil.TimeStamp) error {
+	_, err := x.Insert(&IssueSLABreach{
+		PolicyID:     policy.ID,
+		IssueID:      issue.ID,
+		Target:       target,
+		RepoID:       issue.RepoID,
+		DeadlineUnix: deadline,
+	})
+	return err
+}
+
+// IssueSLAReport represents how the issues created over a period comply with a SLA policy
+type IssueSLAReport struct {
+	Policy             *IssueSLAPolicy
+	Issues             int64
+	Responded          int64
+	ResponseBreaches   int64
+	Resolved           int64
+	ResolutionBreaches int64
+	// TotalResponseTime is the sum of the seconds until the first responses of the responded issues
+	TotalResponseTime int64
+	// TotalResolutionTime is the sum of the seconds until the resolutions of the resolved issues
+	TotalResolutionTime int64
+}
+
+// AverageResponseTime returns the average number of seconds until the first response of the
+// responded issues, it is 0 if no issue has been responded
+func (r *IssueSLAReport) AverageResponseTime() int64 {
+	if r.Responded == 0 {
+		return 0
+	}
+	return r.TotalResponseTime / r.Responded
+}
+
+// AverageResolutionTime returns the average number of seconds until the resolution of the
+// resolved issues, it is 0 if no issue has been resolved
+func (r *IssueSLAReport) AverageResolutionTime() int64 {
+	if r.Resolved == 0 {
+		return 0
+	}
+	return r.TotalResolutionTime / r.Resolved
+}
+
+// GetIssueSLAReport returns the report of the issues the policy applies to which have been created
+// over a period at the time now, since and before are ignored if they are 0
+func GetIssueSLAReport(policy *IssueSLAPolicy, since, before, now timeutil.TimeStamp) (*IssueSLAReport, error) {
+	issues, err := policy.FindIssues(since, before)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := policy.GetIssueSLAStatuses(issues, now)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IssueSLAReport{Policy: policy, Issues: int64(len(statuses))}
+	for _, status := range statuses {
+		if status.ResponseUnix > 0 {
+			report.Responded++
+			report.TotalResponseTime += int64(status.ResponseUnix - status.StartUnix)
+		}
+		if status.ResolutionUnix > 0 {
+			report.Resolved++
+			report.TotalResolutionTime += int64(status.ResolutionUnix - status.StartUnix)
+		}
+		if status.ResponseBreached {
+			report.ResponseBreaches++
+		}
+		if status.ResolutionBreached {
+			report.ResolutionBreaches++
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueSLAPolicy(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	policy := &IssueSLAPolicy{OrgID: 3, Name: "P1 bugs", Label: "orglabel3", ResponseHours: 4, DoerID: 2}
+	assert.NoError(t, CreateIssueSLAPolicy(policy))
+	_, err := GetIssueSLAPolicyByID(2, policy.ID)
+	assert.True(t, IsErrIssueSLAPolicyNotExist(err))
+
+	policy.Enabled = true
+	assert.NoError(t, UpdateIssueSLAPolicy(policy))
+	policies, err := GetEnabledIssueSLAPolicies()
+	assert.NoError(t, err)
+	if assert.Len(t, policies, 1) {
+		assert.Equal(t, "P1 bugs", policies[0].Name)
+	}
+
+	assert.NoError(t, CreateIssueSLABreach(policy, &Issue{ID: 6, RepoID: 3}, IssueSLATargetResponse, 1))
+	has, err := HasIssueSLABreach(policy.ID, 6, IssueSLATargetResponse)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	assert.True(t, IsErrIssueSLAPolicyNotExist(DeleteIssueSLAPolicy(2, policy.ID)))
+	assert.NoError(t, DeleteIssueSLAPolicy(3, policy.ID))
+	AssertNotExistsBean(t, &IssueSLABreach{PolicyID: policy.ID})
+}
+
+func TestIssueSLAPolicy_GetIssueSLAStatuses(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// issue 6 of the repository 3 of the organization 3 is posted by user 1 which isn't a member
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 6}).(*Issue)
+	_, err := x.Insert(&IssueLabel{IssueID: issue.ID, LabelID: 3})
+	assert.NoError(t, err)
+	created := issue.CreatedUnix
+	hour := int64(3600)
+
+	policy := &IssueSLAPolicy{OrgID: 3, Label: "orglabel3", ResponseHours: 4, ResolutionHours: 24}
+	issues, err := policy.FindOpenIssues()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{6}, issueIDs(issues))
+
+	statuses, err := policy.GetIssueSLAStatuses(issues, created.Add(3*hour))
+	assert.NoError(t, err)
+	if assert.Len(t, statuses, 1) {
+		assert.Equal(t, created, statuses[0].StartUnix)
+		assert.False(t, statuses[0].ResponseBreached)
+		assert.False(t, statuses[0].ResolutionBreached)
+	}
+	statuses, err = policy.GetIssueSLAStatuses(issues, created.Add(5*hour))
+	assert.NoError(t, err)
+	assert.True(t, statuses[0].ResponseBreached)
+	assert.False(t, statuses[0].ResolutionBreached)
+
+	// the comments of the poster don't respond, the comments of the members do
+	_, err = x.NoAutoTime().Insert(
+		&Comment{Type: CommentTypeComment, IssueID: issue.ID, PosterID: 1, CreatedUnix: created.Add(hour)},
+		&Comment{Type: CommentTypeComment, IssueID: issue.ID, PosterID: 2, CreatedUnix: created.Add(2 * hour)},
+	)
+	assert.NoError(t, err)
+	statuses, err = policy.GetIssueSLAStatuses(issues, created.Add(5*hour))
+	assert.NoError(t, err)
+	assert.Equal(t, created.Add(2*hour), statuses[0].ResponseUnix)
+	assert.False(t, statuses[0].ResponseBreached)
+
+	report, err := GetIssueSLAReport(policy, 0, 0, created.Add(25*hour))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, report.Issues)
+	assert.EqualValues(t, 1, report.Responded)
+	assert.EqualValues(t, 0, report.ResponseBreaches)
+	assert.EqualValues(t, 2*hour, report.AverageResponseTime())
+	assert.EqualValues(t, 0, report.Resolved)
+	assert.EqualValues(t, 1, report.ResolutionBreaches)
+
+	// adding the label again restarts the clock, the earlier responses don't count
+	_, err = x.NoAutoTime().Insert(&Comment{Type: CommentTypeLabel, IssueID: issue.ID, PosterID: 2, LabelID: 3, Content: "1", CreatedUnix: created.Add(3 * hour)})
+	assert.NoError(t, err)
+	statuses, err = policy.GetIssueSLAStatuses(issues, created.Add(5*hour))
+	assert.NoError(t, err)
+	assert.Equal(t, created.Add(3*hour), statuses[0].StartUnix)
+	assert.Equal(t, timeutil.TimeStamp(0), statuses[0].ResponseUnix)
+	assert.False(t, statuses[0].ResponseBreached)
+
+	report, err = GetIssueSLAReport(policy, created.Add(hour), 0, created.Add(25*hour))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, report.Issues)
+}
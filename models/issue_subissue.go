@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"xorm.io/builder"
+)
+
+// SubIssuesProgress represents the roll-up progress of the sub-issues of an issue
+type SubIssuesProgress struct {
+	Total  int64
+	Closed int64
+}
+
+// Percent returns the percentage of the sub-issues which are closed
+func (p *SubIssuesProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return int(p.Closed * 100 / p.Total)
+}
+
+func (issue *Issue) loadParent(e Engine) (err error) {
+	if issue.ParentID > 0 && issue.Parent == nil {
+		issue.Parent, err = getIssueByID(e, issue.ParentID)
+		if IsErrIssueNotExist(err) {
+			issue.ParentID = 0
+			return nil
+		}
+	}
+	return err
+}
+
+// LoadParent loads the parent issue of a sub-issue
+func (issue *Issue) LoadParent() error {
+	return issue.loadParent(x)
+}
+
+func getSubIssues(e Engine, parentID int64) (IssueList, error) {
+	issues := make(IssueList, 0, 10)
+	return issues, e.
+		Where("parent_id = ?", parentID).
+		Asc("`index`").
+		Find(&issues)
+}
+
+// GetSubIssues returns the sub-issues of an issue
+func GetSubIssues(parentID int64) (IssueList, error) {
+	return getSubIssues(x, parentID)
+}
+
+// GetSubIssuesProgress returns the roll-up progress of the sub-issues of the given issues,
+// the issues without sub-issues are not in the map
+func GetSubIssuesProgress(parentIDs []int64) (map[int64]*SubIssuesProgress, error) {
+	progress := make(map[int64]*SubIssuesProgress, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return progress, nil
+	}
+
+	type subIssuesCount struct {
+		ParentID int64
+		IsClosed bool
+		Count    int64
+	}
+	counts := make([]*subIssuesCount, 0, len(parentIDs))
+	if err := x.Table("issue").
+		Select("parent_id, is_closed, count(*) AS count").
+		Where(builder.In("parent_id", parentIDs)).
+		GroupBy("parent_id, is_closed").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		p, ok := progress[count.ParentID]
+		if !ok {
+			p = &SubIssuesProgress{}
+			progress[count.ParentID] = p
+		}
+		p.Total += count.Count
+		if count.IsClosed {
+			p.Closed += count.Count
+		}
+	}
+	return progress, nil
+}
+
+// GetSubIssuesProgress returns the roll-up progress of the sub-issues of an issue
+func (issue *Issue) GetSubIssuesProgress() (*SubIssuesProgress, error) {
+	progress, err := GetSubIssuesProgress([]int64{issue.ID})
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := progress[issue.ID]; ok {
+		return p, nil
+	}
+	return &SubIssuesProgress{}, nil
+}
+
+// AttachSubIssue makes an issue a sub-issue of another issue of the same repository
+func AttachSubIssue(parent, child *Issue) error {
+	if parent.RepoID != child.RepoID {
+		return ErrSubIssueNotSameRepo{IssueID: child.ID, ParentID: parent.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	child, err := getIssueByID(sess, child.ID)
+	if err != nil {
+		return err
+	}
+	if child.ParentID > 0 {
+		return ErrSubIssueHasParent{IssueID: child.ID, ParentID: child.ParentID}
+	}
+
+	// The child can't be an ancestor of its new parent
+	for ancestorID := parent.ID; ancestorID > 0; {
+		if ancestorID == child.ID {
+			return ErrCircularSubIssue{IssueID: child.ID, ParentID: parent.ID}
+		}
+		ancestor, err := getIssueByID(sess, ancestorID)
+		if err != nil {
+			return err
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	if _, err = sess.ID(child.ID).Cols("parent_id").NoAutoTime().Update(&Issue{ParentID: parent.ID}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DetachSubIssue removes a sub-issue from its parent issue
+func DetachSubIssue(parent, child *Issue) error {
+	affected, err := x.ID(child.ID).
+		Where("parent_id = ?", parent.ID).
+		Cols("parent_id").
+		NoAutoTime().
+		Update(&Issue{ParentID: 0})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSubIssueNotExist{IssueID: child.ID, ParentID: parent.ID}
+	}
+	return nil
+}
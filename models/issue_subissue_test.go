@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachSubIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	parent := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	closed := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	open := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	assert.NoError(t, AttachSubIssue(parent, closed))
+	assert.NoError(t, AttachSubIssue(parent, open))
+	AssertExistsAndLoadBean(t, &Issue{ID: 5, ParentID: 1})
+
+	err := AttachSubIssue(open, closed)
+	assert.True(t, IsErrSubIssueHasParent(err))
+	err = AttachSubIssue(open, parent)
+	assert.True(t, IsErrCircularSubIssue(err))
+	err = AttachSubIssue(parent, parent)
+	assert.True(t, IsErrCircularSubIssue(err))
+	err = AttachSubIssue(parent, AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue))
+	assert.True(t, IsErrSubIssueNotSameRepo(err))
+
+	children, err := GetSubIssues(parent.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, children, 2) {
+		assert.EqualValues(t, 2, children[0].ID)
+		assert.EqualValues(t, 5, children[1].ID)
+	}
+
+	progress, err := parent.GetSubIssuesProgress()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, progress.Total)
+	assert.EqualValues(t, 1, progress.Closed)
+	assert.Equal(t, 50, progress.Percent())
+
+	issues, err := Issues(&IssuesOptions{ParentID: parent.ID})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+
+	assert.NoError(t, DetachSubIssue(parent, closed))
+	assert.True(t, IsErrSubIssueNotExist(DetachSubIssue(parent, closed)))
+	assert.EqualValues(t, 0, AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue).ParentID)
+
+	progress, err = AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue).GetSubIssuesProgress()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, progress.Total)
+	assert.Equal(t, 0, progress.Percent())
+}
@@ -144,14 +144,16 @@ func (issue *Issue) getCrossReferences(e *xorm.Session, ctx *crossReferencesCont
 		err       error
 	)
 
-	allrefs := append(references.FindAllIssueReferences(plaincontent), references.FindAllIssueReferencesMarkdown(mdcontent)...)
+	if err := ctx.OrigIssue.loadRepo(e); err != nil {
+		return nil, err
+	}
+	keywords := ctx.OrigIssue.Repo.issuesConfig(e).Keywords()
+	allrefs := append(references.FindAllIssueReferencesWithKeywords(plaincontent, keywords),
+		references.FindAllIssueReferencesMarkdownWithKeywords(mdcontent, keywords)...)
 
 	for _, ref := range allrefs {
 		if ref.Owner == "" && ref.Name == "" {
 			// Issues in the same repository
-			if err := ctx.OrigIssue.loadRepo(e); err != nil {
-				return nil, err
-			}
 			refRepo = ctx.OrigIssue.Repo
 		} else {
 			// Issues in other repositories
@@ -211,6 +213,12 @@ func (issue *Issue) verifyReferencedIssue(e Engine, ctx *crossReferencesContext,
 		refAction = references.XRefActionNone
 	}
 
+	// The repositories can prevent the others from closing or reopening their issues
+	if refAction != references.XRefActionNone && refIssue.RepoID != ctx.OrigIssue.RepoID &&
+		refIssue.Repo.issuesConfig(e).DisableCrossRepoClose {
+		refAction = references.XRefActionNone
+	}
+
 	// Check doer permissions; set action to None if the doer can't change the destination
 	if refIssue.RepoID != ctx.OrigIssue.RepoID || ref.Action != references.XRefActionNone {
 		perm, err := getUserRepoPermission(e, refIssue.Repo, ctx.Doer)
@@ -0,0 +1,182 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// LegalPage represents a version of a legal page of the instance, like its terms of service,
+// a new version is recorded each time the page changes
+type LegalPage struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+	Version     int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Hash        string             `xorm:"VARCHAR(64) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+
+	NumAcceptances int64 `xorm:"-"`
+}
+
+// LegalAcceptance represents the acceptance of a version of a legal page by a user
+type LegalAcceptance struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PageID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	UserID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	User        *User              `xorm:"-"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// UpdateLegalPage returns the latest version of a legal page, a new version is recorded
+// when the hash of its content changed
+func UpdateLegalPage(name, hash string) (*LegalPage, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	page := new(LegalPage)
+	has, err := sess.Where("name = ?", name).Desc("version").Get(page)
+	if err != nil {
+		return nil, err
+	} else if has && page.Hash == hash {
+		return page, nil
+	}
+
+	page = &LegalPage{Name: name, Version: page.Version + 1, Hash: hash}
+	if _, err := sess.Insert(page); err != nil {
+		return nil, err
+	}
+	return page, sess.Commit()
+}
+
+// GetLegalPageByID returns the version of a legal page of the given ID
+func GetLegalPageByID(id int64) (*LegalPage, error) {
+	page := new(LegalPage)
+	has, err := x.ID(id).Get(page)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrLegalPageNotExist{ID: id}
+	}
+	return page, nil
+}
+
+// GetLegalPages returns all versions of the legal pages with their number of acceptances,
+// the latest version of each page first
+func GetLegalPages() ([]*LegalPage, error) {
+	pages := make([]*LegalPage, 0, 10)
+	if err := x.Asc("name").Desc("version").Find(&pages); err != nil {
+		return nil, err
+	}
+
+	type pageCount struct {
+		PageID int64
+		Count  int64
+	}
+	counts := make([]*pageCount, 0, len(pages))
+	if err := x.Table("legal_acceptance").
+		Select("page_id, COUNT(*) AS count").
+		GroupBy("page_id").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+	countsMap := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		countsMap[count.PageID] = count.Count
+	}
+	for _, page := range pages {
+		page.NumAcceptances = countsMap[page.ID]
+	}
+	return pages, nil
+}
+
+// GetUnacceptedLegalPages returns the versions of the legal pages the user didn't accept
+func GetUnacceptedLegalPages(userID int64, pages []*LegalPage) ([]*LegalPage, error) {
+	if len(pages) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(pages))
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	accepted := make([]int64, 0, len(pages))
+	if err := x.Table("legal_acceptance").
+		Where("user_id = ?", userID).
+		And(builder.In("page_id", ids)).
+		Cols("page_id").
+		Find(&accepted); err != nil {
+		return nil, err
+	}
+
+	acceptedMap := make(map[int64]bool, len(accepted))
+	for _, id := range accepted {
+		acceptedMap[id] = true
+	}
+	unaccepted := make([]*LegalPage, 0, len(pages))
+	for _, page := range pages {
+		if !acceptedMap[page.ID] {
+			unaccepted = append(unaccepted, page)
+		}
+	}
+	return unaccepted, nil
+}
+
+// AcceptLegalPages records the acceptance of versions of legal pages by a user
+func AcceptLegalPages(userID int64, pages []*LegalPage) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		has, err := sess.Exist(&LegalAcceptance{PageID: page.ID, UserID: userID})
+		if err != nil {
+			return err
+		} else if has {
+			continue
+		}
+		if _, err := sess.Insert(&LegalAcceptance{PageID: page.ID, UserID: userID}); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// GetLegalAcceptances returns the acceptances of a version of a legal page with their users, the latest first
+func GetLegalAcceptances(pageID int64, listOptions ListOptions) ([]*LegalAcceptance, error) {
+	sess := x.Where("page_id = ?", pageID).Desc("created_unix", "id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	acceptances := make([]*LegalAcceptance, 0, listOptions.PageSize)
+	if err := sess.Find(&acceptances); err != nil || len(acceptances) == 0 {
+		return acceptances, err
+	}
+
+	userIDs := make([]int64, 0, len(acceptances))
+	for _, acceptance := range acceptances {
+		userIDs = append(userIDs, acceptance.UserID)
+	}
+	users := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return nil, err
+	}
+	for _, acceptance := range acceptances {
+		if acceptance.User = users[acceptance.UserID]; acceptance.User == nil {
+			acceptance.User = NewGhostUser()
+		}
+	}
+	return acceptances, nil
+}
+
+// CountLegalAcceptances returns the number of acceptances of a version of a legal page
+func CountLegalAcceptances(pageID int64) (int64, error) {
+	return x.Where("page_id = ?", pageID).Count(new(LegalAcceptance))
+}
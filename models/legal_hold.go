@@ -0,0 +1,222 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// LegalHold represents a hold placed by an administrator on the content of a user or a repository,
+// e.g. for an e-discovery. Content under a hold can't be purged and its revisions are kept.
+type LegalHold struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	User        *User              `xorm:"-"`
+	RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Repo        *Repository        `xorm:"-"`
+	Reason      string             `xorm:"TEXT"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	Doer        *User              `xorm:"-"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ContentRevision represents a previous content of an issue or a comment under a legal hold,
+// which is recorded when the content is edited or deleted
+type ContentRevision struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	// IssueID is the issue the content belongs to
+	IssueID int64 `xorm:"INDEX NOT NULL"`
+	// CommentID is the comment the content belongs to, it is 0 for the content of the issue itself
+	CommentID   int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	PosterID    int64              `xorm:"INDEX NOT NULL"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	Content     string             `xorm:"LONGTEXT"`
+	IsDeleted   bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ErrLegalHoldNotExist represents a "LegalHoldNotExist" kind of error.
+type ErrLegalHoldNotExist struct {
+	ID int64
+}
+
+// IsErrLegalHoldNotExist checks if an error is a ErrLegalHoldNotExist.
+func IsErrLegalHoldNotExist(err error) bool {
+	_, ok := err.(ErrLegalHoldNotExist)
+	return ok
+}
+
+func (err ErrLegalHoldNotExist) Error() string {
+	return fmt.Sprintf("legal hold does not exist [id: %d]", err.ID)
+}
+
+// ErrUnderLegalHold represents an error when content under a legal hold is going to be purged
+type ErrUnderLegalHold struct {
+	UserID int64
+	RepoID int64
+}
+
+// IsErrUnderLegalHold checks if an error is a ErrUnderLegalHold.
+func IsErrUnderLegalHold(err error) bool {
+	_, ok := err.(ErrUnderLegalHold)
+	return ok
+}
+
+func (err ErrUnderLegalHold) Error() string {
+	return fmt.Sprintf("content is under a legal hold [user_id: %d, repo_id: %d]", err.UserID, err.RepoID)
+}
+
+func (hold *LegalHold) loadAttributes(e Engine) (err error) {
+	if hold.UserID > 0 && hold.User == nil {
+		if hold.User, err = getUserByID(e, hold.UserID); err != nil && !IsErrUserNotExist(err) {
+			return err
+		}
+	}
+	if hold.RepoID > 0 && hold.Repo == nil {
+		if hold.Repo, err = getRepositoryByID(e, hold.RepoID); err != nil && !IsErrRepoNotExist(err) {
+			return err
+		}
+	}
+	if hold.Doer == nil {
+		if hold.Doer, err = getUserByID(e, hold.DoerID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			hold.Doer = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// LoadAttributes loads the user, the repository and the doer of a legal hold
+func (hold *LegalHold) LoadAttributes() error {
+	return hold.loadAttributes(x)
+}
+
+// CreateLegalHold places a legal hold on a user or a repository
+func CreateLegalHold(hold *LegalHold) error {
+	if (hold.UserID == 0) == (hold.RepoID == 0) {
+		return fmt.Errorf("a legal hold must be placed on either a user or a repository")
+	}
+	_, err := x.Insert(hold)
+	return err
+}
+
+// GetLegalHoldByID returns a legal hold by its ID
+func GetLegalHoldByID(id int64) (*LegalHold, error) {
+	hold := new(LegalHold)
+	has, err := x.ID(id).Get(hold)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrLegalHoldNotExist{ID: id}
+	}
+	return hold, nil
+}
+
+// GetLegalHolds returns the legal holds, the latest first
+func GetLegalHolds(listOptions ListOptions) ([]*LegalHold, int64, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	holds := make([]*LegalHold, 0, listOptions.PageSize)
+	count, err := sess.Desc("id").FindAndCount(&holds)
+	return holds, count, err
+}
+
+// DeleteLegalHold releases a legal hold, the revisions recorded while it was placed are kept
+// until the content is purged
+func DeleteLegalHold(id int64) error {
+	affected, err := x.ID(id).Delete(new(LegalHold))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrLegalHoldNotExist{ID: id}
+	}
+	return nil
+}
+
+func isUnderLegalHold(e Engine, userID, repoID int64) (bool, error) {
+	cond := builder.NewCond()
+	if userID > 0 {
+		cond = cond.Or(builder.Eq{"user_id": userID})
+	}
+	if repoID > 0 {
+		cond = cond.Or(builder.Eq{"repo_id": repoID})
+	}
+	if !cond.IsValid() {
+		return false, nil
+	}
+	return e.Where(cond).Exist(new(LegalHold))
+}
+
+// IsUserUnderLegalHold checks if there is a legal hold on a user
+func IsUserUnderLegalHold(userID int64) (bool, error) {
+	return isUnderLegalHold(x, userID, 0)
+}
+
+// IsRepoUnderLegalHold checks if there is a legal hold on a repository
+func IsRepoUnderLegalHold(repoID int64) (bool, error) {
+	return isUnderLegalHold(x, 0, repoID)
+}
+
+// recordContentRevision keeps the previous content of an issue or a comment if it is under a legal hold,
+// either by its repository or by its poster
+func recordContentRevision(e Engine, rev *ContentRevision) error {
+	held, err := isUnderLegalHold(e, rev.PosterID, rev.RepoID)
+	if err != nil {
+		return err
+	} else if !held {
+		return nil
+	}
+	_, err = e.Insert(rev)
+	return err
+}
+
+// LegalHoldContent represents the content held by a legal hold
+type LegalHoldContent struct {
+	Issues    []*Issue
+	Comments  []*Comment
+	Revisions []*ContentRevision
+}
+
+// GetLegalHoldContent returns the current issues and comments held by a legal hold with their previous
+// and deleted revisions
+func GetLegalHoldContent(hold *LegalHold) (*LegalHoldContent, error) {
+	content := &LegalHoldContent{
+		Issues:    make([]*Issue, 0, 10),
+		Comments:  make([]*Comment, 0, 10),
+		Revisions: make([]*ContentRevision, 0, 10),
+	}
+
+	var issueCond, commentCond, revisionCond builder.Cond
+	if hold.RepoID > 0 {
+		issueCond = builder.Eq{"repo_id": hold.RepoID}
+		commentCond = builder.In("issue_id", builder.Select("id").From("issue").Where(issueCond))
+		revisionCond = builder.Eq{"repo_id": hold.RepoID}
+	} else {
+		issueCond = builder.Eq{"poster_id": hold.UserID}
+		commentCond = builder.Eq{"poster_id": hold.UserID}
+		revisionCond = builder.Eq{"poster_id": hold.UserID}
+	}
+
+	if err := x.Where(issueCond).Asc("id").Find(&content.Issues); err != nil {
+		return nil, fmt.Errorf("find issues: %v", err)
+	}
+	if err := x.Where(commentCond).Asc("id").Find(&content.Comments); err != nil {
+		return nil, fmt.Errorf("find comments: %v", err)
+	}
+	if err := x.Where(revisionCond).Asc("id").Find(&content.Revisions); err != nil {
+		return nil, fmt.Errorf("find revisions: %v", err)
+	}
+	return content, nil
+}
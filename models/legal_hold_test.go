@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalHold_Repo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+
+	// revisions aren't recorded without a legal hold
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, issue.ChangeContent(doer, "first change"))
+	AssertNotExistsBean(t, &ContentRevision{IssueID: 1})
+
+	hold := &LegalHold{RepoID: 1, Reason: "litigation", DoerID: doer.ID}
+	assert.NoError(t, CreateLegalHold(hold))
+	held, err := IsRepoUnderLegalHold(1)
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	assert.NoError(t, issue.ChangeContent(doer, "second change"))
+	AssertExistsAndLoadBean(t, &ContentRevision{RepoID: 1, IssueID: 1, CommentID: 0, Content: "first change"})
+
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	comment.Content = "edited"
+	assert.NoError(t, UpdateComment(comment, doer))
+	AssertExistsAndLoadBean(t, &ContentRevision{IssueID: 1, CommentID: 2, PosterID: 3, Content: "good work!", IsDeleted: false})
+	assert.NoError(t, DeleteComment(comment, doer))
+	AssertExistsAndLoadBean(t, &ContentRevision{IssueID: 1, CommentID: 2, Content: "edited", IsDeleted: true})
+
+	content, err := GetLegalHoldContent(hold)
+	assert.NoError(t, err)
+	assert.Len(t, content.Revisions, 3)
+	for _, issue := range content.Issues {
+		assert.EqualValues(t, 1, issue.RepoID)
+	}
+	assert.NotEmpty(t, content.Comments)
+
+	// the repository can't be deleted until the hold is released
+	err = DeleteRepository(doer, 2, 1)
+	assert.True(t, IsErrUnderLegalHold(err))
+	AssertExistsAndLoadBean(t, &Repository{ID: 1})
+
+	assert.NoError(t, DeleteLegalHold(hold.ID))
+	assert.True(t, IsErrLegalHoldNotExist(DeleteLegalHold(hold.ID)))
+	held, err = IsRepoUnderLegalHold(1)
+	assert.NoError(t, err)
+	assert.False(t, held)
+}
+
+func TestLegalHold_User(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user := AssertExistsAndLoadBean(t, &User{ID: 8}).(*User)
+
+	hold := &LegalHold{UserID: user.ID, DoerID: doer.ID}
+	assert.NoError(t, CreateLegalHold(hold))
+	assert.Error(t, CreateLegalHold(&LegalHold{UserID: user.ID, RepoID: 1, DoerID: doer.ID}))
+	assert.Error(t, CreateLegalHold(&LegalHold{DoerID: doer.ID}))
+
+	holds, count, err := GetLegalHolds(ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, holds, 1) {
+		assert.NoError(t, holds[0].LoadAttributes())
+		assert.Equal(t, user.Name, holds[0].User.Name)
+		assert.Nil(t, holds[0].Repo)
+	}
+
+	err = DeleteUser(user)
+	assert.True(t, IsErrUnderLegalHold(err))
+	AssertExistsAndLoadBean(t, &User{ID: user.ID})
+
+	assert.NoError(t, DeleteLegalHold(hold.ID))
+	assert.NoError(t, DeleteUser(user))
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalPages(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	terms, err := UpdateLegalPage("terms", "hash1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, terms.Version)
+	same, err := UpdateLegalPage("terms", "hash1")
+	assert.NoError(t, err)
+	assert.Equal(t, terms.ID, same.ID)
+	privacy, err := UpdateLegalPage("privacy", "hash1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, privacy.Version)
+
+	assert.NoError(t, AcceptLegalPages(2, []*LegalPage{terms}))
+	// accepting a page again is ignored
+	assert.NoError(t, AcceptLegalPages(2, []*LegalPage{terms, privacy}))
+	unaccepted, err := GetUnacceptedLegalPages(2, []*LegalPage{terms, privacy})
+	assert.NoError(t, err)
+	assert.Empty(t, unaccepted)
+
+	newTerms, err := UpdateLegalPage("terms", "hash2")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, newTerms.Version)
+	unaccepted, err = GetUnacceptedLegalPages(2, []*LegalPage{newTerms, privacy})
+	assert.NoError(t, err)
+	if assert.Len(t, unaccepted, 1) {
+		assert.Equal(t, newTerms.ID, unaccepted[0].ID)
+	}
+
+	pages, err := GetLegalPages()
+	assert.NoError(t, err)
+	if assert.Len(t, pages, 3) {
+		assert.Equal(t, privacy.ID, pages[0].ID)
+		assert.EqualValues(t, 1, pages[0].NumAcceptances)
+		assert.Equal(t, newTerms.ID, pages[1].ID)
+		assert.EqualValues(t, 0, pages[1].NumAcceptances)
+		assert.Equal(t, terms.ID, pages[2].ID)
+		assert.EqualValues(t, 1, pages[2].NumAcceptances)
+	}
+
+	acceptances, err := GetLegalAcceptances(terms.ID, ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, acceptances, 1) {
+		assert.EqualValues(t, 2, acceptances[0].User.ID)
+	}
+}
@@ -215,17 +215,17 @@ func LFSAutoAssociate(metas []*LFSMetaObject, user *User, repoID int64) error {
 
 // IterateLFS iterates lfs object
 func IterateLFS(f func(mo *LFSMetaObject) error) error {
-	var start int
+	var lastID int64
 	const batchSize = 100
 	for {
 		var mos = make([]*LFSMetaObject, 0, batchSize)
-		if err := x.Limit(batchSize, start).Find(&mos); err != nil {
+		if err := x.Where("id > ?", lastID).OrderBy("id").Limit(batchSize).Find(&mos); err != nil {
 			return err
 		}
 		if len(mos) == 0 {
 			return nil
 		}
-		start += len(mos)
+		lastID = mos[len(mos)-1].ID
 
 		for _, mo := range mos {
 			if err := f(mo); err != nil {
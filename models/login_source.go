@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/auth/ldap"
 	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/auth/pam"
+	"code.gitea.io/gitea/modules/auth/saml"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -39,6 +40,7 @@ const (
 	LoginDLDAP            // 5
 	LoginOAuth2           // 6
 	LoginSSPI             // 7
+	LoginSAML             // 8
 )
 
 // LoginNames contains the name of LoginType values.
@@ -49,6 +51,7 @@ var LoginNames = map[LoginType]string{
 	LoginPAM:    "PAM",
 	LoginOAuth2: "OAuth2",
 	LoginSSPI:   "SPNEGO with SSPI",
+	LoginSAML:   "SAML 2.0",
 }
 
 // SecurityProtocolNames contains the name of SecurityProtocol values.
@@ -65,6 +68,7 @@ var (
 	_ convert.Conversion = &PAMConfig{}
 	_ convert.Conversion = &OAuth2Config{}
 	_ convert.Conversion = &SSPIConfig{}
+	_ convert.Conversion = &SAMLConfig{}
 )
 
 // LDAPConfig holds configuration for LDAP login source.
@@ -130,6 +134,12 @@ type OAuth2Config struct {
 	ClientSecret                  string
 	OpenIDConnectAutoDiscoveryURL string
 	CustomURLMapping              *oauth2.CustomURLMapping
+	// GroupClaimName is the claim of the groups of the users, e.g. groups
+	GroupClaimName string
+	// GroupTeamMap maps the groups of the claim to the teams of the organizations as JSON
+	GroupTeamMap string
+	// GroupTeamMapRemoval removes the users from the mapped teams of the groups they're not a member of
+	GroupTeamMapRemoval bool
 }
 
 // FromDB fills up an OAuth2Config from serialized format.
@@ -161,6 +171,21 @@ func (cfg *SSPIConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// SAMLConfig holds configuration for the SAML login source.
+type SAMLConfig struct {
+	*saml.Source
+}
+
+// FromDB fills up a SAMLConfig from serialized format.
+func (cfg *SAMLConfig) FromDB(bs []byte) error {
+	return json.Unmarshal(bs, &cfg)
+}
+
+// ToDB exports a SAMLConfig to a serialized format.
+func (cfg *SAMLConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
 // LoginSource represents an external way for authorizing users.
 type LoginSource struct {
 	ID            int64 `xorm:"pk autoincr"`
@@ -199,6 +224,8 @@ func (source *LoginSource) BeforeSet(colName string, val xorm.Cell) {
 			source.Cfg = new(OAuth2Config)
 		case LoginSSPI:
 			source.Cfg = new(SSPIConfig)
+		case LoginSAML:
+			source.Cfg = new(SAMLConfig)
 		default:
 			panic("unrecognized login source type: " + com.ToStr(*val))
 		}
@@ -240,6 +267,11 @@ func (source *LoginSource) IsSSPI() bool {
 	return source.Type == LoginSSPI
 }
 
+// IsSAML returns true of this source is of the SAML type.
+func (source *LoginSource) IsSAML() bool {
+	return source.Type == LoginSAML
+}
+
 // HasTLS returns true of this source supports TLS.
 func (source *LoginSource) HasTLS() bool {
 	return ((source.IsLDAP() || source.IsDLDAP()) &&
@@ -297,6 +329,11 @@ func (source *LoginSource) SSPI() *SSPIConfig {
 	return source.Cfg.(*SSPIConfig)
 }
 
+// SAML returns SAMLConfig for this source, if of SAML type.
+func (source *LoginSource) SAML() *SAMLConfig {
+	return source.Cfg.(*SAMLConfig)
+}
+
 // CreateLoginSource inserts a LoginSource in the DB if not already
 // existing with the given name.
 func CreateLoginSource(source *LoginSource) error {
@@ -497,6 +534,8 @@ func LoginViaLDAP(user *User, login, password string, source *LoginSource) (*Use
 	}
 
 	if user != nil {
+		syncLDAPGroupTeams(user, source, sr)
+		syncLDAPAvatar(user, source, sr)
 		if isAttributeSSHPublicKeySet && synchronizeLdapSSHPublicKeys(user, source, sr.SSHPublicKey) {
 			return user, RewriteAllPublicKeys()
 		}
@@ -527,6 +566,10 @@ func LoginViaLDAP(user *User, login, password string, source *LoginSource) (*Use
 	}
 
 	err := CreateUser(user)
+	if err == nil {
+		syncLDAPGroupTeams(user, source, sr)
+		syncLDAPAvatar(user, source, sr)
+	}
 
 	if err == nil && isAttributeSSHPublicKeySet && addLdapSSHPublicKeys(user, source, sr.SSHPublicKey) {
 		err = RewriteAllPublicKeys()
@@ -535,6 +578,27 @@ func LoginViaLDAP(user *User, login, password string, source *LoginSource) (*Use
 	return user, err
 }
 
+// syncLDAPAvatar replaces the avatar of the user with its LDAP photo when it has changed
+func syncLDAPAvatar(user *User, source *LoginSource, sr *ldap.SearchResult) {
+	if len(sr.Avatar) == 0 || !user.IsUploadAvatarChanged(sr.Avatar) {
+		return
+	}
+	if err := user.UploadAvatar(sr.Avatar); err != nil {
+		log.Error("UploadAvatar [user: %s, source: %s]: %v", user.Name, source.Name, err)
+	}
+}
+
+// syncLDAPGroupTeams syncs the teams of the user with its LDAP groups, when they're mapped to teams
+func syncLDAPGroupTeams(user *User, source *LoginSource, sr *ldap.SearchResult) {
+	if sr.Groups == nil {
+		return
+	}
+	cfg := source.LDAP()
+	if err := SyncGroupTeams(user, cfg.GroupTeamMap, cfg.GroupTeamMapRemoval, sr.Groups); err != nil {
+		log.Error("SyncGroupTeams [user: %s, source: %s]: %v", user.Name, source.Name, err)
+	}
+}
+
 //   _________   __________________________
 //  /   _____/  /     \__    ___/\______   \
 //  \_____  \  /  \ /  \|    |    |     ___/
@@ -803,8 +867,8 @@ func UserSignIn(username, password string) (*User, error) {
 	}
 
 	for _, source := range sources {
-		if source.IsOAuth2() || source.IsSSPI() {
-			// don't try to authenticate against OAuth2 and SSPI sources here
+		if source.IsOAuth2() || source.IsSSPI() || source.IsSAML() {
+			// don't try to authenticate against OAuth2, SSPI and SAML sources here
 			continue
 		}
 		authUser, err := ExternalUserLogin(nil, username, password, source)
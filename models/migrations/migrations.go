@@ -265,6 +265,90 @@ var migrations = []Migration{
 	NewMigration("update reactions constraint", updateReactionConstraint),
 	// v160 -> v161
 	NewMigration("Add block on official review requests branch protection", addBlockOnOfficialReviewRequests),
+	// v161 -> v162
+	NewMigration("Add repo_symbol table", addRepoSymbolTable),
+	// v162 -> v163
+	NewMigration("Add scope column to access_token", addScopeToAccessToken),
+	// v163 -> v164
+	NewMigration("Add webhook_host_allowlist table", addWebhookHostAllowlistTable),
+	// v164 -> v165
+	NewMigration("Add parent_id column to issue", addParentIDToIssue),
+	// v165 -> v166
+	NewMigration("Add issue_reminder table", addIssueReminderTable),
+	// v166 -> v167
+	NewMigration("Add saved_reply table", addSavedReplyTable),
+	// v167 -> v168
+	NewMigration("Add stale_policy and stale_action_log tables", addStalePolicyTables),
+	// v168 -> v169
+	NewMigration("Add team_watch_rule table", addTeamWatchRuleTable),
+	// v169 -> v170
+	NewMigration("Add project_automation_rule table", addProjectAutomationRuleTable),
+	// v170 -> v171
+	NewMigration("Add time_estimate to issue", addTimeEstimateToIssue),
+	// v171 -> v172
+	NewMigration("Add merge_queue_entry table and enable_merge_queue to protected_branch", addMergeQueue),
+	// v172 -> v173
+	NewMigration("Add base_pull_id to pull_request", addBasePullIDToPullRequest),
+	// v173 -> v174
+	NewMigration("Add payload_version to webhook and hook_task", addPayloadVersionToWebhook),
+	// v174 -> v175
+	NewMigration("Add pull_viewed_file table", addPullViewedFileTable),
+	// v175 -> v176
+	NewMigration("Add require_code_owner_approval to protected_branch", addRequireCodeOwnerApprovalToProtectedBranch),
+	// v176 -> v177
+	NewMigration("Add checklist to review", addChecklistToReview),
+	// v177 -> v178
+	NewMigration("Add license and has_code_of_conduct to repository", addLicenseAndCodeOfConductToRepository),
+	// v178 -> v179
+	NewMigration("Add legal_page and legal_acceptance tables", addLegalPageTables),
+	// v179 -> v180
+	NewMigration("Add review_environment table", addReviewEnvironmentTable),
+	// v180 -> v181
+	NewMigration("Add repo_badge table", addRepoBadgeTable),
+	// v181 -> v182
+	NewMigration("Add linear history and approval dismissal options to protected_branch", addLinearHistoryAndApprovalDismissalToProtectedBranch),
+	// v182 -> v183
+	NewMigration("Add legal_hold and content_revision tables", addLegalHoldTables),
+	// v183 -> v184
+	NewMigration("Add protected_path_rules to protected_branch", addProtectedPathRulesToProtectedBranch),
+	// v184 -> v185
+	NewMigration("Add pull_auto_publish table", addPullAutoPublishTable),
+	// v185 -> v186
+	NewMigration("Add short_link table", addShortLinkTable),
+	// v186 -> v187
+	NewMigration("Add diff whitespace behavior and context lines to user", addDiffPreferencesToUser),
+	// v187 -> v188
+	NewMigration("Add keep_review_stats_private to user", addKeepReviewStatsPrivateToUser),
+	// v188 -> v189
+	NewMigration("Add issue SLA policy tables", addIssueSLAPolicyTables),
+	// v189 -> v190
+	NewMigration("Add instance stats and API usage tables", addInstanceStatsTables),
+	// v190 -> v191
+	NewMigration("Add recalculation table", addRecalculationTable),
+	// v191 -> v192
+	NewMigration("Add hook task archive table", addHookTaskArchiveTable),
+	// v192 -> v193
+	NewMigration("Add storage quota of users and organizations", addUserMaxStorageSize),
+	// v193 -> v194
+	NewMigration("Add release automation table", addReleaseAutomationTable),
+	// v194 -> v195
+	NewMigration("Add audit event table", addAuditEventTable),
+	// v195 -> v196
+	NewMigration("Add status issue tables", addStatusIssueTables),
+	// v196 -> v197
+	NewMigration("Add last use of SSH keys, deploy keys and access tokens", addCredentialLastUsed),
+	// v197 -> v198
+	NewMigration("Add SAML sessions table", addSAMLSessionTable),
+	// v198 -> v199
+	NewMigration("Add two-factor requirement of organizations and users", addTwoFactorRequirement),
+	// v199 -> v200
+	NewMigration("Add require sign-off to protected_branch", addRequireSignOffToProtectedBranch),
+	// v200 -> v201
+	NewMigration("Add repository restrictions to access_token", addRepoRestrictionsToAccessToken),
+	// v201 -> v202
+	NewMigration("Add OAuth2 device code table", addOAuth2DeviceCodeTable),
+	// v202 -> v203
+	NewMigration("Add result column to task", addResultToTask),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addRepoSymbolTable(x *xorm.Engine) error {
+	type RepoSymbol struct {
+		ID        int64  `xorm:"pk autoincr"`
+		RepoID    int64  `xorm:"INDEX(s) NOT NULL"`
+		LowerName string `xorm:"VARCHAR(255) INDEX(s) NOT NULL"`
+		Name      string `xorm:"VARCHAR(255) NOT NULL"`
+		Kind      string `xorm:"VARCHAR(20)"`
+		Language  string `xorm:"VARCHAR(50)"`
+		Path      string `xorm:"TEXT NOT NULL"`
+		BlobSHA   string `xorm:"VARCHAR(40) INDEX"`
+		Line      int
+	}
+
+	if err := x.Sync2(new(RepoSymbol)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
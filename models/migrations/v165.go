@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueReminderTable(x *xorm.Engine) error {
+	type IssueReminder struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		RemindUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(IssueReminder)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
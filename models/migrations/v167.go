@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStalePolicyTables(x *xorm.Engine) error {
+	type StalePolicy struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+		Enabled        bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		IncludeIssues  bool               `xorm:"NOT NULL DEFAULT true"`
+		IncludePulls   bool               `xorm:"NOT NULL DEFAULT false"`
+		DaysUntilStale int                `xorm:"NOT NULL DEFAULT 60"`
+		DaysUntilClose int                `xorm:"NOT NULL DEFAULT 7"`
+		StaleLabel     string             `xorm:"NOT NULL"`
+		ExemptLabels   string             `xorm:"TEXT"`
+		WarningComment string             `xorm:"TEXT"`
+		CloseComment   string             `xorm:"TEXT"`
+		DoerID         int64              `xorm:"NOT NULL"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type StaleActionLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		Action      int                `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	if err := x.Sync2(new(StalePolicy), new(StaleActionLog)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMergeQueue(x *xorm.Engine) error {
+	type MergeQueueEntry struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX NOT NULL"`
+		BaseBranch  string `xorm:"INDEX NOT NULL"`
+		PullID      int64  `xorm:"UNIQUE NOT NULL"`
+		DoerID      int64  `xorm:"NOT NULL"`
+		MergeStyle  string `xorm:"VARCHAR(50) NOT NULL"`
+		Message     string `xorm:"TEXT"`
+		Status      int    `xorm:"NOT NULL DEFAULT 0"`
+		TestBaseSHA string `xorm:"VARCHAR(40)"`
+		TestHeadSHA string `xorm:"VARCHAR(40)"`
+		TestSHA     string `xorm:"VARCHAR(40) INDEX"`
+		FailReason  string `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ProtectedBranch struct {
+		EnableMergeQueue bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(MergeQueueEntry)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPullViewedFileTable(x *xorm.Engine) error {
+	type PullViewedFile struct {
+		ID       int64  `xorm:"pk autoincr"`
+		RepoID   int64  `xorm:"INDEX NOT NULL"`
+		PullID   int64  `xorm:"INDEX(s) NOT NULL"`
+		UserID   int64  `xorm:"INDEX(s) NOT NULL"`
+		TreePath string `xorm:"TEXT NOT NULL"`
+		BlobSHA  string `xorm:"VARCHAR(40)"`
+
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(PullViewedFile)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
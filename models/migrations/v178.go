@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addLegalPageTables(x *xorm.Engine) error {
+	type LegalPage struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Version     int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Hash        string             `xorm:"VARCHAR(64) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type LegalAcceptance struct {
+		ID          int64              `xorm:"pk autoincr"`
+		PageID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		UserID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(LegalPage), new(LegalAcceptance)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addReviewEnvironmentTable(x *xorm.Engine) error {
+	type ReviewEnvironment struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		PullID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		URL         string             `xorm:"TEXT NOT NULL"`
+		State       string             `xorm:"VARCHAR(7) NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		IsExpired   bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		ExpiredUnix timeutil.TimeStamp `xorm:"INDEX"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(ReviewEnvironment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addLinearHistoryAndApprovalDismissalToProtectedBranch(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		RequireLinearHistory        bool `xorm:"NOT NULL DEFAULT false"`
+		DismissApprovalsOnPush      bool `xorm:"NOT NULL DEFAULT false"`
+		DismissApprovalsOnForcePush bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addLegalHoldTables(x *xorm.Engine) error {
+	type LegalHold struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Reason      string             `xorm:"TEXT"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type ContentRevision struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		CommentID   int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		PosterID    int64              `xorm:"INDEX NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		Content     string             `xorm:"LONGTEXT"`
+		IsDeleted   bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(LegalHold), new(ContentRevision)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
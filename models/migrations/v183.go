@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addProtectedPathRulesToProtectedBranch(x *xorm.Engine) error {
+	type ProtectedPathRule struct {
+		Pattern string `json:"pattern"`
+		TeamID  int64  `json:"team_id"`
+	}
+
+	type ProtectedBranch struct {
+		ProtectedPathRules []*ProtectedPathRule `xorm:"JSON TEXT"`
+	}
+
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
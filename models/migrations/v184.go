@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPullAutoPublishTable(x *xorm.Engine) error {
+	type PullAutoPublish struct {
+		ID              int64              `xorm:"pk autoincr"`
+		RepoID          int64              `xorm:"INDEX NOT NULL"`
+		PullID          int64              `xorm:"UNIQUE NOT NULL"`
+		DoerID          int64              `xorm:"NOT NULL"`
+		ReviewerIDs     []int64            `xorm:"JSON TEXT"`
+		TeamReviewerIDs []int64            `xorm:"JSON TEXT"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(PullAutoPublish)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
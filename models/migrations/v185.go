@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addShortLinkTable(x *xorm.Engine) error {
+	type ShortLink struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Token       string             `xorm:"VARCHAR(20) UNIQUE NOT NULL"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		Type        int                `xorm:"NOT NULL DEFAULT 0"`
+		CommitID    string             `xorm:"VARCHAR(40) NOT NULL"`
+		TreePath    string             `xorm:"TEXT"`
+		BlobID      string             `xorm:"VARCHAR(40)"`
+		LineStart   int                `xorm:"NOT NULL DEFAULT 0"`
+		LineEnd     int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(ShortLink)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "xorm.io/xorm"
+
+func addDiffPreferencesToUser(x *xorm.Engine) error {
+	// User see models/user.go
+	type User struct {
+		DiffWhitespaceBehavior string `xorm:"NOT NULL DEFAULT ''"`
+		DiffContextLines       int    `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(User))
+}
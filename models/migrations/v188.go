@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueSLAPolicyTables(x *xorm.Engine) error {
+	type IssueSLAPolicy struct {
+		ID              int64  `xorm:"pk autoincr"`
+		OrgID           int64  `xorm:"INDEX NOT NULL"`
+		Name            string `xorm:"NOT NULL"`
+		Enabled         bool   `xorm:"INDEX NOT NULL DEFAULT false"`
+		Label           string `xorm:"NOT NULL"`
+		IncludePulls    bool   `xorm:"NOT NULL DEFAULT false"`
+		ResponseHours   int    `xorm:"NOT NULL DEFAULT 0"`
+		ResolutionHours int    `xorm:"NOT NULL DEFAULT 0"`
+		BreachLabel     string
+		BreachComment   string             `xorm:"TEXT"`
+		DoerID          int64              `xorm:"NOT NULL"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueSLABreach struct {
+		ID           int64              `xorm:"pk autoincr"`
+		PolicyID     int64              `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Target       int                `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID       int64              `xorm:"INDEX NOT NULL"`
+		DeadlineUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(IssueSLAPolicy), new(IssueSLABreach)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addInstanceStatsTables(x *xorm.Engine) error {
+	type InstanceStats struct {
+		ID             int64              `xorm:"pk autoincr"`
+		Day            timeutil.TimeStamp `xorm:"UNIQUE NOT NULL"`
+		Users          int64              `xorm:"NOT NULL DEFAULT 0"`
+		NewUsers       int64              `xorm:"NOT NULL DEFAULT 0"`
+		ActiveUsers    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Orgs           int64              `xorm:"NOT NULL DEFAULT 0"`
+		Repos          int64              `xorm:"NOT NULL DEFAULT 0"`
+		NewRepos       int64              `xorm:"NOT NULL DEFAULT 0"`
+		RepoSize       int64              `xorm:"NOT NULL DEFAULT 0"`
+		LFSSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+		APIRequests    int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type APIUsage struct {
+		ID       int64              `xorm:"pk autoincr"`
+		UserID   int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Day      timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Requests int64              `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(InstanceStats), new(APIUsage)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
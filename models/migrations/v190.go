@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRecalculationTable(x *xorm.Engine) error {
+	type Recalculation struct {
+		ID           int64  `xorm:"pk autoincr"`
+		Name         string `xorm:"UNIQUE NOT NULL"`
+		Status       int    `xorm:"NOT NULL DEFAULT 0"`
+		LastID       int64  `xorm:"NOT NULL DEFAULT 0"`
+		Done         int64  `xorm:"NOT NULL DEFAULT 0"`
+		Total        int64  `xorm:"NOT NULL DEFAULT 0"`
+		Error        string `xorm:"TEXT"`
+		StartedUnix  timeutil.TimeStamp
+		FinishedUnix timeutil.TimeStamp
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(Recalculation)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addHookTaskArchiveTable(x *xorm.Engine) error {
+	type HookTaskArchive struct {
+		ID             int64 `xorm:"pk autoincr"`
+		HookID         int64 `xorm:"INDEX"`
+		Path           string
+		NumTasks       int
+		FirstDelivered int64
+		LastDelivered  int64
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(HookTaskArchive)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
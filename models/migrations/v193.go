@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addReleaseAutomationTable(x *xorm.Engine) error {
+	type ReleaseAutomation struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"UNIQUE NOT NULL"`
+		Enabled       bool               `xorm:"NOT NULL DEFAULT false"`
+		TagPattern    string             `xorm:"NOT NULL"`
+		Draft         bool               `xorm:"NOT NULL DEFAULT true"`
+		Prerelease    bool               `xorm:"NOT NULL DEFAULT false"`
+		TitleTemplate string             `xorm:"NOT NULL"`
+		NoteTemplate  string             `xorm:"TEXT"`
+		DoerID        int64              `xorm:"NOT NULL"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(ReleaseAutomation)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addAuditEventTable(x *xorm.Engine) error {
+	type AuditEvent struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Action      string             `xorm:"VARCHAR(50) INDEX NOT NULL"`
+		DoerID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		DoerName    string             `xorm:"NOT NULL"`
+		IPAddress   string             `xorm:"VARCHAR(50)"`
+		TargetType  string             `xorm:"VARCHAR(20) INDEX NOT NULL"`
+		TargetID    int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		TargetName  string             `xorm:"NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(AuditEvent)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
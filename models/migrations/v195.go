@@ -0,0 +1,39 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStatusIssueTables(x *xorm.Engine) error {
+	type StatusIssueRule struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+		Enabled        bool               `xorm:"NOT NULL DEFAULT false"`
+		ContextPattern string             `xorm:"NOT NULL"`
+		LabelName      string             `xorm:"NOT NULL"`
+		DoerID         int64              `xorm:"NOT NULL"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type StatusIssue struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"UNIQUE(s) NOT NULL"`
+		ContextHash string `xorm:"CHAR(40) UNIQUE(s) NOT NULL"`
+		Context     string `xorm:"TEXT"`
+		IssueID     int64  `xorm:"NOT NULL"`
+	}
+
+	if err := x.Sync2(new(StatusIssueRule), new(StatusIssue)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
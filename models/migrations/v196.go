@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCredentialLastUsed(x *xorm.Engine) error {
+	type PublicKey struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		LastUsedIP   string             `xorm:"VARCHAR(50)"`
+	}
+
+	type DeployKey struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		LastUsedIP   string             `xorm:"VARCHAR(50)"`
+	}
+
+	type AccessToken struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		LastUsedIP   string             `xorm:"VARCHAR(50)"`
+	}
+
+	if err := x.Sync2(new(PublicKey), new(DeployKey), new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// the update time of the credentials used to be their last use time
+	for _, table := range []string{"public_key", "deploy_key", "access_token"} {
+		if _, err := x.Exec(fmt.Sprintf("UPDATE %s SET last_used_unix = updated_unix WHERE updated_unix > created_unix", table)); err != nil {
+			return fmt.Errorf("backfill %s: %v", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addSAMLSessionTable(x *xorm.Engine) error {
+	type SAMLSession struct {
+		ID            int64 `xorm:"pk autoincr"`
+		LoginSourceID int64 `xorm:"INDEX NOT NULL"`
+		UserID        int64 `xorm:"INDEX NOT NULL"`
+		NameID        string
+		SessionIndex  string
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(SAMLSession)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
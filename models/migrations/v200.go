@@ -0,0 +1,23 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addRepoRestrictionsToAccessToken(x *xorm.Engine) error {
+	type AccessToken struct {
+		RepoIDs  []int64 `xorm:"JSON TEXT"`
+		OwnerIDs []int64 `xorm:"JSON TEXT"`
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
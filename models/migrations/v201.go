@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addOAuth2DeviceCodeTable(x *xorm.Engine) error {
+	// named to be mapped to the oauth2_device_code table of models.OAuth2DeviceCode
+	type Oauth2DeviceCode struct {
+		ID             int64  `xorm:"pk autoincr"`
+		ApplicationID  int64  `xorm:"INDEX"`
+		DeviceCode     string `xorm:"UNIQUE"`
+		UserCode       string `xorm:"UNIQUE"`
+		UserID         int64
+		Denied         bool `xorm:"NOT NULL DEFAULT false"`
+		LastPolledUnix timeutil.TimeStamp
+		ValidUntil     timeutil.TimeStamp `xorm:"INDEX"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(Oauth2DeviceCode)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
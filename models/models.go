@@ -125,12 +125,42 @@ func init() {
 		new(OAuth2Application),
 		new(OAuth2AuthorizationCode),
 		new(OAuth2Grant),
+		new(OAuth2DeviceCode),
 		new(Task),
 		new(LanguageStat),
+		new(RepoSymbol),
 		new(EmailHash),
 		new(Project),
 		new(ProjectBoard),
 		new(ProjectIssue),
+		new(WebhookHostAllowlist),
+		new(IssueReminder),
+		new(SavedReply),
+		new(StalePolicy),
+		new(StaleActionLog),
+		new(TeamWatchRule),
+		new(ProjectAutomationRule),
+		new(MergeQueueEntry),
+		new(PullViewedFile),
+		new(LegalPage),
+		new(LegalAcceptance),
+		new(ReviewEnvironment),
+		new(RepoBadge),
+		new(LegalHold),
+		new(ContentRevision),
+		new(PullAutoPublish),
+		new(ShortLink),
+		new(IssueSLAPolicy),
+		new(IssueSLABreach),
+		new(InstanceStats),
+		new(APIUsage),
+		new(Recalculation),
+		new(HookTaskArchive),
+		new(ReleaseAutomation),
+		new(AuditEvent),
+		new(StatusIssueRule),
+		new(StatusIssue),
+		new(SAMLSession),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -144,7 +174,11 @@ func getEngine() (*xorm.Engine, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newEngine(connStr)
+}
 
+// newEngine returns a xorm.Engine connected to a database of the configured type
+func newEngine(connStr string) (*xorm.Engine, error) {
 	engine, err := xorm.NewEngine(setting.Database.Type, connStr)
 	if err != nil {
 		return nil, err
@@ -196,7 +230,10 @@ func SetEngine() (err error) {
 	x.SetMaxOpenConns(setting.Database.MaxOpenConns)
 	x.SetMaxIdleConns(setting.Database.MaxIdleConns)
 	x.SetConnMaxLifetime(setting.Database.ConnMaxLifetime)
-	return nil
+	if err = initIDGenerator(); err != nil {
+		return err
+	}
+	return initReplica()
 }
 
 // NewEngine initializes a new xorm.Engine
@@ -223,6 +260,9 @@ func NewEngine(ctx context.Context, migrateFunc func(*xorm.Engine) error) (err e
 		return fmt.Errorf("sync database struct error: %v", err)
 	}
 
+	if replica != nil {
+		go monitorReplica(ctx)
+	}
 	return nil
 }
 
@@ -331,6 +331,13 @@ func countUnread(e Engine, userID int64) int64 {
 	return exist
 }
 
+// BeforeInsert will be invoked by XORM before inserting a record
+func (n *Notification) BeforeInsert() {
+	if n.ID == 0 {
+		n.ID = nextHighWriteID()
+	}
+}
+
 // LoadAttributes load Repo Issue User and Comment if not loaded
 func (n *Notification) LoadAttributes() (err error) {
 	return n.loadAttributes(x)
@@ -0,0 +1,225 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// userCodeAlphabet are the characters of the user codes, without vowels and look-alike
+// characters as recommended by RFC 8628
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// OAuth2DeviceCode is a code of the device authorization grant (RFC 8628), the device polls the
+// access token endpoint with the device code while the user approves the request with the user code
+type OAuth2DeviceCode struct {
+	ID            int64              `xorm:"pk autoincr"`
+	ApplicationID int64              `xorm:"INDEX"`
+	Application   *OAuth2Application `xorm:"-"`
+	DeviceCode    string             `xorm:"UNIQUE"`
+	UserCode      string             `xorm:"UNIQUE"`
+	// the user approving or denying the request, zero while it's pending
+	UserID         int64
+	Denied         bool `xorm:"NOT NULL DEFAULT false"`
+	LastPolledUnix timeutil.TimeStamp
+	ValidUntil     timeutil.TimeStamp `xorm:"INDEX"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+}
+
+// TableName sets the table name to `oauth2_device_code`
+func (code *OAuth2DeviceCode) TableName() string {
+	return "oauth2_device_code"
+}
+
+// IsExpired returns whether the code can't be used anymore
+func (code *OAuth2DeviceCode) IsExpired() bool {
+	return code.ValidUntil < timeutil.TimeStampNow()
+}
+
+// IsPending returns whether the user hasn't approved nor denied the request yet
+func (code *OAuth2DeviceCode) IsPending() bool {
+	return code.UserID == 0
+}
+
+// IsApproved returns whether the user approved the request
+func (code *OAuth2DeviceCode) IsApproved() bool {
+	return !code.IsPending() && !code.Denied
+}
+
+// LoadApplication loads the application which requested the code
+func (code *OAuth2DeviceCode) LoadApplication() (err error) {
+	if code.Application == nil {
+		code.Application, err = GetOAuth2ApplicationByID(code.ApplicationID)
+	}
+	return
+}
+
+// Poll records a request of the device for an access token, it returns false if the device polls
+// faster than the interval it was given
+func (code *OAuth2DeviceCode) Poll() (bool, error) {
+	now := timeutil.TimeStampNow()
+	tooFast := code.LastPolledUnix.Add(setting.OAuth2.DeviceCodePollingInterval) > now
+	code.LastPolledUnix = now
+	_, err := x.ID(code.ID).Cols("last_polled_unix").Update(code)
+	return !tooFast, err
+}
+
+// Approve records that the user approved the request, the application is granted access to the account
+func (code *OAuth2DeviceCode) Approve(userID int64) (*OAuth2Grant, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	app, err := getOAuth2ApplicationByID(sess, code.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := app.getGrantByUserID(sess, userID)
+	if err != nil {
+		return nil, err
+	}
+	if grant == nil {
+		if grant, err = app.createGrant(sess, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := code.decide(sess, userID, false); err != nil {
+		return nil, err
+	}
+	return grant, sess.Commit()
+}
+
+// Deny records that the user denied the request
+func (code *OAuth2DeviceCode) Deny(userID int64) error {
+	return code.decide(x, userID, true)
+}
+
+// decide records the decision of the user if the request is still pending
+func (code *OAuth2DeviceCode) decide(e Engine, userID int64, denied bool) error {
+	affected, err := e.ID(code.ID).Where("user_id = 0").Cols("user_id", "denied").
+		Update(&OAuth2DeviceCode{UserID: userID, Denied: denied})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrOAuth2DeviceCodeNotPending{ID: code.ID}
+	}
+	code.UserID = userID
+	code.Denied = denied
+	return nil
+}
+
+// Invalidate deletes the code once the device obtained an access token or was denied, it fails
+// if the code was already deleted by a concurrent request
+func (code *OAuth2DeviceCode) Invalidate() error {
+	affected, err := x.ID(code.ID).Delete(new(OAuth2DeviceCode))
+	if err != nil {
+		return err
+	} else if affected != 1 {
+		return ErrOAuth2DeviceCodeNotExist{ID: code.ID}
+	}
+	return nil
+}
+
+// CreateDeviceCode starts a device authorization request of the application, the expired requests
+// of all the applications are deleted beforehand
+func (app *OAuth2Application) CreateDeviceCode() (*OAuth2DeviceCode, error) {
+	if err := deleteExpiredOAuth2DeviceCodes(x); err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := secret.New()
+	if err != nil {
+		return nil, err
+	}
+	code := &OAuth2DeviceCode{
+		ApplicationID: app.ID,
+		Application:   app,
+		DeviceCode:    deviceCode,
+		ValidUntil:    timeutil.TimeStampNow().Add(setting.OAuth2.DeviceCodeExpirationTime),
+	}
+
+	// the user codes are short, they are generated again on the rare collisions
+	for i := 0; i < 3; i++ {
+		if code.UserCode, err = generateUserCode(); err != nil {
+			return nil, err
+		}
+		has, err := x.Exist(&OAuth2DeviceCode{UserCode: code.UserCode})
+		if err != nil {
+			return nil, err
+		} else if !has {
+			break
+		}
+	}
+
+	if _, err := x.Insert(code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// generateUserCode generates a user code like "BCDF-GHJK"
+func generateUserCode() (string, error) {
+	var code strings.Builder
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// NormalizeUserCode returns the user code in its canonical format, the users may omit
+// the dash and type it in lower case
+func NormalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(userCode))
+	if len(userCode) != 8 {
+		return userCode
+	}
+	return userCode[:4] + "-" + userCode[4:]
+}
+
+// GetOAuth2DeviceCodeByDeviceCode returns the device authorization request with the given device code,
+// or nil if it doesn't exist
+func GetOAuth2DeviceCodeByDeviceCode(deviceCode string) (*OAuth2DeviceCode, error) {
+	return getOAuth2DeviceCode(&OAuth2DeviceCode{DeviceCode: deviceCode})
+}
+
+// GetOAuth2DeviceCodeByUserCode returns the device authorization request with the given user code,
+// or nil if it doesn't exist
+func GetOAuth2DeviceCodeByUserCode(userCode string) (*OAuth2DeviceCode, error) {
+	return getOAuth2DeviceCode(&OAuth2DeviceCode{UserCode: NormalizeUserCode(userCode)})
+}
+
+func getOAuth2DeviceCode(code *OAuth2DeviceCode) (*OAuth2DeviceCode, error) {
+	if len(code.DeviceCode) == 0 && len(code.UserCode) == 0 {
+		return nil, nil
+	}
+	if has, err := x.Get(code); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return code, nil
+}
+
+func deleteExpiredOAuth2DeviceCodes(e Engine) error {
+	_, err := e.Where("valid_until < ?", timeutil.TimeStampNow()).Delete(new(OAuth2DeviceCode))
+	return err
+}
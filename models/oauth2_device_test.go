@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"regexp"
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2Application_CreateDeviceCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+
+	code, err := app.CreateDeviceCode()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[B-Z]{4}-[B-Z]{4}$`), code.UserCode)
+	assert.NotEmpty(t, code.DeviceCode)
+	assert.True(t, code.IsPending())
+	assert.False(t, code.IsExpired())
+
+	loaded, err := GetOAuth2DeviceCodeByUserCode(" " + code.UserCode[:4] + code.UserCode[5:] + " ")
+	assert.NoError(t, err)
+	if assert.NotNil(t, loaded) {
+		assert.Equal(t, code.ID, loaded.ID)
+	}
+	loaded, err = GetOAuth2DeviceCodeByDeviceCode(code.DeviceCode)
+	assert.NoError(t, err)
+	if assert.NotNil(t, loaded) {
+		assert.Equal(t, code.UserCode, loaded.UserCode)
+	}
+	loaded, err = GetOAuth2DeviceCodeByDeviceCode("")
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	// the expired codes are deleted when a new one is created
+	expired := &OAuth2DeviceCode{ApplicationID: app.ID, DeviceCode: "expired", UserCode: "BBBB-BBBB", ValidUntil: timeutil.TimeStampNow() - 1}
+	_, err = x.Insert(expired)
+	assert.NoError(t, err)
+	assert.True(t, expired.IsExpired())
+	_, err = app.CreateDeviceCode()
+	assert.NoError(t, err)
+	AssertNotExistsBean(t, &OAuth2DeviceCode{ID: expired.ID})
+}
+
+func TestOAuth2DeviceCode_Approve(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+
+	code, err := app.CreateDeviceCode()
+	assert.NoError(t, err)
+
+	inInterval, err := code.Poll()
+	assert.NoError(t, err)
+	assert.True(t, inInterval)
+	inInterval, err = code.Poll()
+	assert.NoError(t, err)
+	assert.False(t, inInterval)
+
+	// a grant is created for users who never authorized the application
+	grant, err := code.Approve(2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, grant.UserID)
+	assert.True(t, code.IsApproved())
+	AssertExistsAndLoadBean(t, &OAuth2DeviceCode{ID: code.ID, UserID: 2})
+	AssertExistsAndLoadBean(t, &OAuth2Grant{UserID: 2, ApplicationID: app.ID})
+
+	code, err = app.CreateDeviceCode()
+	assert.NoError(t, err)
+	grant, err = code.Approve(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, grant.ID)
+
+	code, err = app.CreateDeviceCode()
+	assert.NoError(t, err)
+	assert.NoError(t, code.Deny(1))
+	assert.False(t, code.IsPending())
+	assert.False(t, code.IsApproved())
+
+	// the decision can't be changed
+	pending := AssertExistsAndLoadBean(t, &OAuth2DeviceCode{ID: code.ID}).(*OAuth2DeviceCode)
+	pending.UserID = 0
+	_, err = pending.Approve(2)
+	assert.True(t, IsErrOAuth2DeviceCodeNotPending(err))
+	assert.True(t, IsErrOAuth2DeviceCodeNotPending(pending.Deny(2)))
+	AssertExistsAndLoadBean(t, &OAuth2DeviceCode{ID: code.ID, UserID: 1, Denied: true})
+
+	// the code can only be invalidated once
+	assert.NoError(t, code.Invalidate())
+	AssertNotExistsBean(t, &OAuth2DeviceCode{ID: code.ID})
+	assert.True(t, IsErrOAuth2DeviceCodeNotExist(code.Invalidate()))
+}
@@ -161,6 +161,7 @@ func CreateOrganization(org, owner *User) (err error) {
 	}
 	org.UseCustomAvatar = true
 	org.MaxRepoCreation = -1
+	org.MaxStorageSize = -1
 	org.NumTeams = 1
 	org.NumMembers = 1
 	org.Type = UserTypeOrganization
@@ -268,7 +269,7 @@ func DeleteOrganization(org *User) (err error) {
 	}
 
 	if err = deleteOrg(sess, org); err != nil {
-		if IsErrUserOwnRepos(err) {
+		if IsErrUserOwnRepos(err) || IsErrUnderLegalHold(err) {
 			return err
 		} else if err != nil {
 			return fmt.Errorf("deleteOrg: %v", err)
@@ -287,11 +288,22 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		return ErrUserOwnRepos{UID: u.ID}
 	}
 
+	// Check legal holds on the content of the organization.
+	if held, err := isUnderLegalHold(e, u.ID, 0); err != nil {
+		return fmt.Errorf("isUnderLegalHold: %v", err)
+	} else if held {
+		return ErrUnderLegalHold{UserID: u.ID}
+	}
+
 	if err := deleteBeans(e,
 		&Team{OrgID: u.ID},
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&WebhookHostAllowlist{OwnerID: u.ID},
+		&SavedReply{OwnerID: u.ID},
+		&TeamWatchRule{OrgID: u.ID},
+		&IssueSLAPolicy{OrgID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -668,6 +680,13 @@ func RemoveOrgUser(orgID, userID int64) error {
 }
 
 func removeOrgRepo(e Engine, orgID, repoID int64) error {
+	if _, err := e.Delete(&TeamWatchRule{
+		OrgID:  orgID,
+		RepoID: repoID,
+	}); err != nil {
+		return err
+	}
+
 	teamRepos := make([]*TeamRepo, 0, 10)
 	if err := e.Find(&teamRepos, &TeamRepo{OrgID: orgID, RepoID: repoID}); err != nil {
 		return err
@@ -314,6 +314,10 @@ func (t *Team) RemoveAllRepositories() (err error) {
 // removeAllRepositories removes all repositories from team and recalculates access
 // Note: Shall not be called if team includes all repositories
 func (t *Team) removeAllRepositories(e Engine) (err error) {
+	if err = t.removeWatchRules(e); err != nil {
+		return err
+	}
+
 	// Delete all accesses.
 	for _, repo := range t.Repos {
 		if err := repo.recalculateTeamAccesses(e, t.ID); err != nil {
@@ -358,6 +362,10 @@ func (t *Team) removeAllRepositories(e Engine) (err error) {
 // removeRepository removes a repository from a team and recalculates access
 // Note: Repository shall not be removed from team if it includes all repositories (unless the repository is deleted)
 func (t *Team) removeRepository(e Engine, repo *Repository, recalculate bool) (err error) {
+	if err = t.removeWatchRules(e, repo.ID); err != nil {
+		return err
+	}
+
 	if err = removeTeamRepo(e, t.ID, repo.ID); err != nil {
 		return err
 	}
@@ -877,6 +885,10 @@ func AddTeamMember(team *Team, userID int64) error {
 		}
 	}
 
+	if err := team.autoWatchTeamRepos(sess, userID); err != nil {
+		return err
+	}
+
 	return sess.Commit()
 }
 
@@ -927,6 +939,11 @@ func removeTeamMember(e *xorm.Session, team *Team, userID int64) error {
 		}
 	}
 
+	// Remove the watches of the team rules
+	if err := team.unwatchTeamRepos(e, userID); err != nil {
+		return err
+	}
+
 	// Check if the user is a member of any team in the organization.
 	if count, err := e.Count(&TeamUser{
 		UID:   userID,
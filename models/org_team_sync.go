@@ -0,0 +1,103 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ParseGroupTeamMap parses the JSON mapping of the groups of an authentication source to the teams of the
+// organizations, e.g. {"developers": {"org1": ["team1", "team2"]}}
+func ParseGroupTeamMap(s string) (map[string]map[string][]string, error) {
+	groupTeamMap := make(map[string]map[string][]string)
+	if len(strings.TrimSpace(s)) == 0 {
+		return groupTeamMap, nil
+	}
+	if err := json.Unmarshal([]byte(s), &groupTeamMap); err != nil {
+		return nil, fmt.Errorf("invalid group team map: %v", err)
+	}
+	return groupTeamMap, nil
+}
+
+// SyncGroupTeams adds the user to the teams mapped to its groups, and removes it from the teams mapped to
+// the other groups when removal is enabled
+func SyncGroupTeams(user *User, groupTeamMapJSON string, removal bool, groups []string) error {
+	groupTeamMap, err := ParseGroupTeamMap(groupTeamMapJSON)
+	if err != nil {
+		return err
+	}
+	if len(groupTeamMap) == 0 {
+		return nil
+	}
+
+	isMember := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		isMember[strings.ToLower(group)] = true
+	}
+
+	// the teams of the groups of the user, by organization name and team name
+	memberTeams := make(map[string]map[string]bool)
+	for group, orgTeams := range groupTeamMap {
+		if !isMember[strings.ToLower(group)] {
+			continue
+		}
+		for orgName, teamNames := range orgTeams {
+			orgName = strings.ToLower(orgName)
+			if memberTeams[orgName] == nil {
+				memberTeams[orgName] = make(map[string]bool)
+			}
+			for _, teamName := range teamNames {
+				memberTeams[orgName][strings.ToLower(teamName)] = true
+			}
+		}
+	}
+
+	for _, orgTeams := range groupTeamMap {
+		for orgName, teamNames := range orgTeams {
+			org, err := GetOrgByName(orgName)
+			if err != nil {
+				if IsErrOrgNotExist(err) {
+					log.Warn("SyncGroupTeams: organization %s doesn't exist", orgName)
+					continue
+				}
+				return err
+			}
+			for _, teamName := range teamNames {
+				team, err := GetTeam(org.ID, teamName)
+				if err != nil {
+					if IsErrTeamNotExist(err) {
+						log.Warn("SyncGroupTeams: team %s of organization %s doesn't exist", teamName, orgName)
+						continue
+					}
+					return err
+				}
+				isTeamMember, err := IsTeamMember(org.ID, team.ID, user.ID)
+				if err != nil {
+					return err
+				}
+				if memberTeams[strings.ToLower(orgName)][strings.ToLower(teamName)] {
+					if !isTeamMember {
+						if err := AddTeamMember(team, user.ID); err != nil {
+							return err
+						}
+					}
+				} else if isTeamMember && removal {
+					if err := RemoveTeamMember(team, user.ID); err != nil {
+						if IsErrLastOrgOwner(err) {
+							log.Warn("SyncGroupTeams: %s is the last owner of %s", user.Name, orgName)
+							continue
+						}
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
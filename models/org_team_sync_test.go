@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGroupTeamMap(t *testing.T) {
+	groupTeamMap, err := ParseGroupTeamMap("")
+	assert.NoError(t, err)
+	assert.Empty(t, groupTeamMap)
+
+	groupTeamMap, err = ParseGroupTeamMap(`{"developers": {"org1": ["team1", "team2"]}, "admins": {"org2": ["owners"]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string][]string{
+		"developers": {"org1": {"team1", "team2"}},
+		"admins":     {"org2": {"owners"}},
+	}, groupTeamMap)
+
+	_, err = ParseGroupTeamMap(`{"developers": ["team1"]}`)
+	assert.Error(t, err)
+}
+
+func TestSyncGroupTeams(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	groupTeamMap := `{"cn=developers,ou=groups,dc=example,dc=org": {"user3": ["team1"], "missing": ["team1"]}, "owners": {"user3": ["Owners", "missing"]}}`
+
+	// the user is kept in the mapped teams of the other groups without removal
+	assert.NoError(t, SyncGroupTeams(user, groupTeamMap, false, []string{"owners"}))
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 1, UID: user.ID})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: user.ID})
+
+	// the groups are compared case-insensitively, like the distinguished names of LDAP groups
+	assert.NoError(t, SyncGroupTeams(user, groupTeamMap, true, []string{"CN=Developers,OU=groups,DC=example,DC=org"}))
+	AssertNotExistsBean(t, &TeamUser{TeamID: 1, UID: user.ID})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: user.ID})
+
+	assert.NoError(t, SyncGroupTeams(user, groupTeamMap, true, nil))
+	AssertNotExistsBean(t, &TeamUser{TeamID: 2, UID: user.ID})
+
+	assert.Error(t, SyncGroupTeams(user, "invalid", true, nil))
+}
+
+func TestOAuth2ClaimGroups(t *testing.T) {
+	rawData := map[string]interface{}{
+		"groups": []interface{}{"developers", 1, "admins"},
+		"roles":  []string{"owner"},
+		"group":  "developers",
+		"empty":  "",
+	}
+	assert.Equal(t, []string{"developers", "admins"}, oauth2ClaimGroups(rawData, "groups"))
+	assert.Equal(t, []string{"owner"}, oauth2ClaimGroups(rawData, "roles"))
+	assert.Equal(t, []string{"developers"}, oauth2ClaimGroups(rawData, "group"))
+	assert.Empty(t, oauth2ClaimGroups(rawData, "empty"))
+	assert.Empty(t, oauth2ClaimGroups(rawData, "missing"))
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// TeamWatchRule represents a rule making the members of a team watch a repository of the team.
+// The rule watches are auto watches, so they don't override the choice of the members which
+// stopped watching the repository, and are removed when the members leave the team.
+type TeamWatchRule struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	TeamID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func getTeamWatchRepoIDs(e Engine, teamID int64) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, e.Table("team_watch_rule").
+		Where("team_id = ?", teamID).
+		Cols("repo_id").
+		Find(&repoIDs)
+}
+
+// GetWatchRuleRepoIDs returns the IDs of the repositories watched by the members of the team
+func (t *Team) GetWatchRuleRepoIDs() ([]int64, error) {
+	return getTeamWatchRepoIDs(x, t.ID)
+}
+
+// GetWatchRuleRepositories returns the repositories watched by the members of the team
+func (t *Team) GetWatchRuleRepositories(opts ListOptions) ([]*Repository, error) {
+	sess := x.
+		Join("INNER", "team_watch_rule", "team_watch_rule.repo_id = repository.id").
+		Where("team_watch_rule.team_id = ?", t.ID).
+		OrderBy("repository.lower_name")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	repos := make([]*Repository, 0, opts.PageSize)
+	return repos, sess.Find(&repos)
+}
+
+// HasWatchRule returns true if the members of the team watch the repository
+func (t *Team) HasWatchRule(repoID int64) (bool, error) {
+	return x.Exist(&TeamWatchRule{TeamID: t.ID, RepoID: repoID})
+}
+
+// AddWatchRule makes the current and the future members of the team watch the repository
+func (t *Team) AddWatchRule(repo *Repository) error {
+	if repo.OwnerID != t.OrgID || !t.HasRepository(repo.ID) {
+		return ErrTeamRepoNotExist{TeamID: t.ID, RepoID: repo.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if has, err := sess.Exist(&TeamWatchRule{TeamID: t.ID, RepoID: repo.ID}); err != nil || has {
+		return err
+	}
+	if _, err := sess.Insert(&TeamWatchRule{
+		OrgID:  t.OrgID,
+		TeamID: t.ID,
+		RepoID: repo.ID,
+	}); err != nil {
+		return err
+	}
+
+	teamUsers, err := getTeamUsersByTeamID(sess, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, teamUser := range teamUsers {
+		if err := autoWatchRepo(sess, teamUser.UID, repo.ID); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// RemoveWatchRule removes the rule making the members of the team watch the repository, the
+// members stop watching it unless they watch it by themselves or by the rule of another team
+func (t *Team) RemoveWatchRule(repoID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := t.removeWatchRules(sess, repoID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// removeWatchRules removes the rules of the team for the repositories, or all its rules if no
+// repository is given, and the auto watches of its members which no other rule justifies
+func (t *Team) removeWatchRules(e Engine, repoIDs ...int64) error {
+	rules := make([]*TeamWatchRule, 0, 10)
+	sess := e.Where("team_id = ?", t.ID)
+	if len(repoIDs) > 0 {
+		sess = sess.In("repo_id", repoIDs)
+	}
+	if err := sess.Find(&rules); err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	ruleIDs := make([]int64, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	if _, err := e.In("id", ruleIDs).Delete(new(TeamWatchRule)); err != nil {
+		return err
+	}
+
+	teamUsers, err := getTeamUsersByTeamID(e, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, teamUser := range teamUsers {
+		for _, rule := range rules {
+			if err := unwatchAutoWatchedRepo(e, teamUser.UID, rule.RepoID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// autoWatchTeamRepos makes the new member of the team watch the repositories of its rules
+func (t *Team) autoWatchTeamRepos(e Engine, userID int64) error {
+	repoIDs, err := getTeamWatchRepoIDs(e, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, repoID := range repoIDs {
+		if err := autoWatchRepo(e, userID, repoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwatchTeamRepos makes the former member of the team stop watching the repositories of its
+// rules which no rule of the other teams of the user justifies
+func (t *Team) unwatchTeamRepos(e Engine, userID int64) error {
+	repoIDs, err := getTeamWatchRepoIDs(e, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, repoID := range repoIDs {
+		if err := unwatchAutoWatchedRepo(e, userID, repoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func autoWatchRepo(e Engine, userID, repoID int64) error {
+	watch, err := getWatch(e, userID, repoID)
+	if err != nil {
+		return err
+	}
+	return watchRepoMode(e, watch, RepoWatchModeAuto)
+}
+
+// unwatchAutoWatchedRepo removes the auto watch of the repository by the user if none of the
+// rules of the teams of the user makes the user watch it
+func unwatchAutoWatchedRepo(e Engine, userID, repoID int64) error {
+	watch, err := getWatch(e, userID, repoID)
+	if err != nil || watch.Mode != RepoWatchModeAuto {
+		return err
+	}
+	if has, err := e.Table("team_watch_rule").
+		Join("INNER", "team_user", "team_user.team_id = team_watch_rule.team_id").
+		Where("team_user.uid = ?", userID).
+		And("team_watch_rule.repo_id = ?", repoID).
+		Exist(); err != nil || has {
+		return err
+	}
+	return watchRepoMode(e, watch, RepoWatchModeNone)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeam_AddWatchRule(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	team := AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.NoError(t, team.AddWatchRule(repo))
+	AssertExistsAndLoadBean(t, &TeamWatchRule{TeamID: team.ID, RepoID: repo.ID})
+	AssertExistsAndLoadBean(t, &Watch{UserID: 2, RepoID: repo.ID, Mode: RepoWatchModeAuto})
+	AssertExistsAndLoadBean(t, &Watch{UserID: 4, RepoID: repo.ID, Mode: RepoWatchModeAuto})
+	CheckConsistencyFor(t, &Repository{ID: repo.ID})
+
+	// adding the rule again is a no-op
+	assert.NoError(t, team.AddWatchRule(repo))
+
+	// the new members watch the repositories of the rules
+	assert.NoError(t, AddTeamMember(team, 5))
+	AssertExistsAndLoadBean(t, &Watch{UserID: 5, RepoID: repo.ID, Mode: RepoWatchModeAuto})
+
+	// the repository must be a repository of the team
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 5}).(*Repository)
+	assert.True(t, IsErrTeamRepoNotExist(team.AddWatchRule(repo)))
+}
+
+func TestTeam_RemoveWatchRule(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	owners := AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team)
+	team := AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	assert.NoError(t, owners.AddWatchRule(repo))
+	assert.NoError(t, team.AddWatchRule(repo))
+
+	// the user 2 is a member of both teams, the rule of the owners keeps the watch
+	assert.NoError(t, team.RemoveWatchRule(repo.ID))
+	AssertNotExistsBean(t, &TeamWatchRule{TeamID: team.ID, RepoID: repo.ID})
+	AssertExistsAndLoadBean(t, &Watch{UserID: 2, RepoID: repo.ID, Mode: RepoWatchModeAuto})
+	AssertNotExistsBean(t, &Watch{UserID: 4, RepoID: repo.ID})
+	CheckConsistencyFor(t, &Repository{ID: repo.ID})
+
+	// the members which leave the team stop watching the repositories of the rules
+	assert.NoError(t, team.AddWatchRule(repo))
+	assert.NoError(t, owners.RemoveWatchRule(repo.ID))
+	assert.NoError(t, RemoveTeamMember(team, 2))
+	AssertNotExistsBean(t, &Watch{UserID: 2, RepoID: repo.ID})
+	AssertExistsAndLoadBean(t, &Watch{UserID: 4, RepoID: repo.ID, Mode: RepoWatchModeAuto})
+
+	// the rules are removed with the repositories of the team
+	assert.NoError(t, team.RemoveRepository(repo.ID))
+	AssertNotExistsBean(t, &TeamWatchRule{TeamID: team.ID, RepoID: repo.ID})
+	AssertNotExistsBean(t, &Watch{UserID: 4, RepoID: repo.ID})
+	CheckConsistencyFor(t, &Repository{ID: repo.ID})
+}
+
+func TestUnwatchAutoWatchedRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// the watches chosen by the users are kept
+	assert.NoError(t, unwatchAutoWatchedRepo(x, 4, 1))
+	AssertExistsAndLoadBean(t, &Watch{UserID: 4, RepoID: 1, Mode: RepoWatchModeNormal})
+	assert.NoError(t, unwatchAutoWatchedRepo(x, 11, 1))
+	AssertNotExistsBean(t, &Watch{UserID: 11, RepoID: 1})
+}
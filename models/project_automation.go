@@ -0,0 +1,106 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ProjectAutomationEvent is the event of an issue or a pull request which triggers a project automation rule
+type ProjectAutomationEvent string
+
+const (
+	// ProjectAutomationEventClosed the issue or the pull request is closed without being merged
+	ProjectAutomationEventClosed ProjectAutomationEvent = "closed"
+	// ProjectAutomationEventReopened the issue or the pull request is reopened
+	ProjectAutomationEventReopened ProjectAutomationEvent = "reopened"
+	// ProjectAutomationEventMerged the pull request is merged
+	ProjectAutomationEventMerged ProjectAutomationEvent = "merged"
+	// ProjectAutomationEventLabeled the label of the rule is added to the issue or the pull request
+	ProjectAutomationEventLabeled ProjectAutomationEvent = "labeled"
+)
+
+// IsValid checks if the event is a known event
+func (event ProjectAutomationEvent) IsValid() bool {
+	switch event {
+	case ProjectAutomationEventClosed, ProjectAutomationEventReopened, ProjectAutomationEventMerged, ProjectAutomationEventLabeled:
+		return true
+	}
+	return false
+}
+
+// ProjectAutomationRule moves the cards of a project to a board when their issues or pull requests trigger its event
+type ProjectAutomationRule struct {
+	ID        int64                  `xorm:"pk autoincr"`
+	ProjectID int64                  `xorm:"INDEX NOT NULL"`
+	Event     ProjectAutomationEvent `xorm:"VARCHAR(20) NOT NULL"`
+	// LabelID is the label of the labeled event
+	LabelID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// BoardID is the board the cards are moved to, 0 is the uncategorized board
+	BoardID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatorID   int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// GetProjectAutomationRules returns the automation rules of a project
+func GetProjectAutomationRules(projectID int64) ([]*ProjectAutomationRule, error) {
+	rules := make([]*ProjectAutomationRule, 0, 5)
+	return rules, x.Where("project_id=?", projectID).Asc("id").Find(&rules)
+}
+
+// NewProjectAutomationRule adds an automation rule to a project
+func NewProjectAutomationRule(rule *ProjectAutomationRule) error {
+	if rule.Event != ProjectAutomationEventLabeled {
+		rule.LabelID = 0
+	}
+	_, err := x.Insert(rule)
+	return err
+}
+
+// DeleteProjectAutomationRule removes an automation rule of a project
+func DeleteProjectAutomationRule(projectID, id int64) error {
+	deleted, err := x.ID(id).Where("project_id=?", projectID).Delete(new(ProjectAutomationRule))
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrProjectAutomationRuleNotExist{ID: id, ProjectID: projectID}
+	}
+	return nil
+}
+
+func deleteProjectAutomationRulesByProjectID(e Engine, projectID int64) error {
+	_, err := e.Where("project_id=?", projectID).Delete(new(ProjectAutomationRule))
+	return err
+}
+
+// ApplyProjectAutomationRules moves the card of the issue to the board of the first automation rule of its project
+// triggered by the event, the labeled event triggers the rules of the given labels only
+func ApplyProjectAutomationRules(issue *Issue, event ProjectAutomationEvent, labelIDs ...int64) error {
+	var pi ProjectIssue
+	has, err := x.Where("issue_id=? AND project_id>0", issue.ID).Get(&pi)
+	if err != nil || !has {
+		return err
+	}
+
+	cond := builder.Eq{"project_id": pi.ProjectID, "event": event}
+	if event == ProjectAutomationEventLabeled {
+		if len(labelIDs) == 0 {
+			return nil
+		}
+		cond["label_id"] = labelIDs
+	}
+
+	var rule ProjectAutomationRule
+	has, err = x.Where(cond).Asc("id").Get(&rule)
+	if err != nil || !has || rule.BoardID == pi.ProjectBoardID {
+		return err
+	}
+
+	pi.ProjectBoardID = rule.BoardID
+	_, err = x.ID(pi.ID).Cols("project_board_id").Update(&pi)
+	return err
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyProjectAutomationRules(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, NewProjectAutomationRule(&ProjectAutomationRule{ProjectID: 1, Event: ProjectAutomationEventClosed, BoardID: 3, CreatorID: 2}))
+	assert.NoError(t, NewProjectAutomationRule(&ProjectAutomationRule{ProjectID: 1, Event: ProjectAutomationEventLabeled, LabelID: 1, BoardID: 2, CreatorID: 2}))
+	assert.NoError(t, NewProjectAutomationRule(&ProjectAutomationRule{ProjectID: 1, Event: ProjectAutomationEventReopened, BoardID: 0, CreatorID: 2}))
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, ApplyProjectAutomationRules(issue, ProjectAutomationEventClosed))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1, ProjectBoardID: 3})
+
+	// only the rules of the added labels are triggered
+	assert.NoError(t, ApplyProjectAutomationRules(issue, ProjectAutomationEventLabeled, 2))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1, ProjectBoardID: 3})
+	assert.NoError(t, ApplyProjectAutomationRules(issue, ProjectAutomationEventLabeled, 1, 2))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1, ProjectBoardID: 2})
+
+	// the cards can be moved to the uncategorized board
+	assert.NoError(t, ApplyProjectAutomationRules(issue, ProjectAutomationEventReopened))
+	pi := AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1}).(*ProjectIssue)
+	assert.EqualValues(t, 0, pi.ProjectBoardID)
+
+	// the issues which aren't in a project are ignored
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+	assert.NoError(t, ApplyProjectAutomationRules(issue, ProjectAutomationEventClosed))
+}
+
+func TestDeleteProjectAutomationRule(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	rule := &ProjectAutomationRule{ProjectID: 1, Event: ProjectAutomationEventMerged, LabelID: 1, BoardID: 3, CreatorID: 2}
+	assert.NoError(t, NewProjectAutomationRule(rule))
+	assert.EqualValues(t, 0, rule.LabelID)
+
+	assert.True(t, IsErrProjectAutomationRuleNotExist(DeleteProjectAutomationRule(2, rule.ID)))
+	assert.NoError(t, DeleteProjectAutomationRule(1, rule.ID))
+	AssertNotExistsBean(t, &ProjectAutomationRule{ID: rule.ID})
+
+	// the rules are removed with their boards
+	assert.NoError(t, NewProjectAutomationRule(&ProjectAutomationRule{ProjectID: 1, Event: ProjectAutomationEventClosed, BoardID: 3, CreatorID: 2}))
+	assert.NoError(t, DeleteProjectBoardByID(3))
+	AssertNotExistsBean(t, &ProjectAutomationRule{BoardID: 3})
+}
@@ -127,6 +127,10 @@ func deleteProjectBoardByID(e Engine, boardID int64) error {
 		return err
 	}
 
+	if _, err = e.Where("board_id=?", board.ID).Delete(new(ProjectAutomationRule)); err != nil {
+		return err
+	}
+
 	if _, err := e.ID(board.ID).Delete(board); err != nil {
 		return err
 	}
@@ -59,6 +59,9 @@ type PullRequest struct {
 	BaseBranch      string
 	ProtectedBranch *ProtectedBranch `xorm:"-"`
 	MergeBase       string           `xorm:"VARCHAR(40)"`
+	// the pull request this pull request is stacked on, whose head branch is its base branch
+	BasePullID int64        `xorm:"INDEX NOT NULL DEFAULT 0"`
+	BasePull   *PullRequest `xorm:"-"`
 
 	HasMerged      bool               `xorm:"INDEX"`
 	MergedCommitID string             `xorm:"VARCHAR(40)"`
@@ -0,0 +1,108 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// PullAutoPublish represents a work in progress pull request which is marked ready for review
+// once the status checks of its head commit pass, its reviewers are requested then
+type PullAutoPublish struct {
+	ID int64 `xorm:"pk autoincr"`
+	// RepoID is the base repository of the pull request
+	RepoID          int64              `xorm:"INDEX NOT NULL"`
+	PullID          int64              `xorm:"UNIQUE NOT NULL"`
+	Pull            *PullRequest       `xorm:"-"`
+	DoerID          int64              `xorm:"NOT NULL"`
+	Doer            *User              `xorm:"-"`
+	ReviewerIDs     []int64            `xorm:"JSON TEXT"`
+	Reviewers       []*User            `xorm:"-"`
+	TeamReviewerIDs []int64            `xorm:"JSON TEXT"`
+	TeamReviewers   []*Team            `xorm:"-"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+}
+
+// LoadAttributes loads the pull request, the user who enabled the auto publishing and the reviewers
+// which still exist
+func (ap *PullAutoPublish) LoadAttributes() (err error) {
+	if ap.Pull == nil {
+		if ap.Pull, err = GetPullRequestByID(ap.PullID); err != nil {
+			return err
+		}
+	}
+	if ap.Doer == nil {
+		if ap.Doer, err = GetUserByID(ap.DoerID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			ap.Doer = NewGhostUser()
+		}
+	}
+	if ap.Reviewers == nil && len(ap.ReviewerIDs) > 0 {
+		if ap.Reviewers, err = GetUsersByIDs(ap.ReviewerIDs); err != nil {
+			return err
+		}
+	}
+	if ap.TeamReviewers == nil && len(ap.TeamReviewerIDs) > 0 {
+		ap.TeamReviewers = make([]*Team, 0, len(ap.TeamReviewerIDs))
+		for _, id := range ap.TeamReviewerIDs {
+			team, err := GetTeamByID(id)
+			if err != nil {
+				if IsErrTeamNotExist(err) {
+					continue
+				}
+				return err
+			}
+			ap.TeamReviewers = append(ap.TeamReviewers, team)
+		}
+	}
+	return nil
+}
+
+// SetPullAutoPublish enables the auto publishing of a pull request, it replaces the previous options
+func SetPullAutoPublish(ap *PullAutoPublish) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if _, err := sess.Where("pull_id = ?", ap.PullID).Delete(new(PullAutoPublish)); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(ap); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetPullAutoPublishByPullID returns the auto publishing of a pull request
+func GetPullAutoPublishByPullID(pullID int64) (*PullAutoPublish, error) {
+	ap := new(PullAutoPublish)
+	has, err := x.Where("pull_id = ?", pullID).Get(ap)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPullAutoPublishNotExist{PullID: pullID}
+	}
+	return ap, nil
+}
+
+// GetPullAutoPublishesByRepoID returns the auto publishings of the pull requests of a base repository
+func GetPullAutoPublishesByRepoID(repoID int64) ([]*PullAutoPublish, error) {
+	aps := make([]*PullAutoPublish, 0, 5)
+	return aps, x.Where("repo_id = ?", repoID).Asc("id").Find(&aps)
+}
+
+// DeletePullAutoPublish disables the auto publishing of a pull request
+func DeletePullAutoPublish(pullID int64) error {
+	affected, err := x.Where("pull_id = ?", pullID).Delete(new(PullAutoPublish))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrPullAutoPublishNotExist{PullID: pullID}
+	}
+	return nil
+}
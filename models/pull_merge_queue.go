@@ -0,0 +1,177 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MergeQueueBranchPrefix is the prefix of the branches the merges of the merge queues are tested on,
+// the merge queue of a branch pushes its tested merge to MergeQueueBranchPrefix + the name of the branch
+const MergeQueueBranchPrefix = "merge-queue/"
+
+// MergeQueueStatus represents the status of a pull request in a merge queue
+type MergeQueueStatus int
+
+// Merge queue statuses
+const (
+	// MergeQueueStatusWaiting waits for the pull requests before it to be merged
+	MergeQueueStatusWaiting MergeQueueStatus = iota
+	// MergeQueueStatusTesting waits for the required status checks of its merge
+	MergeQueueStatusTesting
+	// MergeQueueStatusFailed could not be merged and no longer blocks the queue
+	MergeQueueStatusFailed
+)
+
+func (status MergeQueueStatus) String() string {
+	switch status {
+	case MergeQueueStatusWaiting:
+		return "waiting"
+	case MergeQueueStatusTesting:
+		return "testing"
+	case MergeQueueStatusFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch,
+// the entries of a queue are merged in the order they were added
+type MergeQueueEntry struct {
+	ID         int64            `xorm:"pk autoincr"`
+	RepoID     int64            `xorm:"INDEX NOT NULL"`
+	BaseBranch string           `xorm:"INDEX NOT NULL"`
+	PullID     int64            `xorm:"UNIQUE NOT NULL"`
+	Pull       *PullRequest     `xorm:"-"`
+	DoerID     int64            `xorm:"NOT NULL"`
+	Doer       *User            `xorm:"-"`
+	MergeStyle MergeStyle       `xorm:"VARCHAR(50) NOT NULL"`
+	Message    string           `xorm:"TEXT"`
+	Status     MergeQueueStatus `xorm:"NOT NULL DEFAULT 0"`
+	// the base and head commits the merge tested by TestSHA was made of
+	TestBaseSHA string `xorm:"VARCHAR(40)"`
+	TestHeadSHA string `xorm:"VARCHAR(40)"`
+	TestSHA     string `xorm:"VARCHAR(40) INDEX"`
+	FailReason  string `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TestBranch returns the name of the branch the merges of the queue are tested on
+func (entry *MergeQueueEntry) TestBranch() string {
+	return MergeQueueBranchPrefix + entry.BaseBranch
+}
+
+// LoadAttributes loads the pull request and the user who added it to the queue
+func (entry *MergeQueueEntry) LoadAttributes() (err error) {
+	if entry.Pull == nil {
+		if entry.Pull, err = GetPullRequestByID(entry.PullID); err != nil {
+			return err
+		}
+	}
+	if entry.Doer == nil {
+		if entry.Doer, err = GetUserByID(entry.DoerID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			entry.Doer = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// AddToMergeQueue adds a pull request to the end of the merge queue of its base branch,
+// a pull request which failed to be merged by the queue is added again
+func AddToMergeQueue(entry *MergeQueueEntry) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	existing := new(MergeQueueEntry)
+	has, err := sess.Where("pull_id = ?", entry.PullID).Get(existing)
+	if err != nil {
+		return err
+	} else if has {
+		if existing.Status != MergeQueueStatusFailed {
+			return ErrMergeQueueEntryAlreadyExists{PullID: entry.PullID}
+		}
+		if _, err = sess.ID(existing.ID).Delete(new(MergeQueueEntry)); err != nil {
+			return err
+		}
+	}
+
+	entry.Status = MergeQueueStatusWaiting
+	if _, err = sess.Insert(entry); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetMergeQueueEntryByPullID returns the merge queue entry of a pull request
+func GetMergeQueueEntryByPullID(pullID int64) (*MergeQueueEntry, error) {
+	entry := new(MergeQueueEntry)
+	has, err := x.Where("pull_id = ?", pullID).Get(entry)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMergeQueueEntryNotExist{PullID: pullID}
+	}
+	return entry, nil
+}
+
+// GetMergeQueueEntries returns the entries of the merge queue of a branch in their order,
+// the failed entries are listed after the others
+func GetMergeQueueEntries(repoID int64, branch string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	if err := x.
+		Where("repo_id = ? AND base_branch = ?", repoID, branch).
+		Asc("id").
+		Find(&entries); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Status != MergeQueueStatusFailed && entries[j].Status == MergeQueueStatusFailed
+	})
+	return entries, nil
+}
+
+// GetMergeQueueEntriesByTestSHA returns the merge queue entries testing a commit
+func GetMergeQueueEntriesByTestSHA(repoID int64, sha string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 1)
+	return entries, x.
+		Where("repo_id = ? AND test_sha = ? AND status = ?", repoID, sha, MergeQueueStatusTesting).
+		Find(&entries)
+}
+
+// GetMergeQueueEntriesToProcess returns the merge queue entries which are not failed
+func GetMergeQueueEntriesToProcess() ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	return entries, x.
+		Where("status <> ?", MergeQueueStatusFailed).
+		Asc("id").
+		Find(&entries)
+}
+
+// UpdateMergeQueueEntryCols updates the given columns of a merge queue entry
+func UpdateMergeQueueEntryCols(entry *MergeQueueEntry, cols ...string) error {
+	_, err := x.ID(entry.ID).Cols(cols...).Update(entry)
+	return err
+}
+
+// RemoveFromMergeQueue removes a pull request from its merge queue
+func RemoveFromMergeQueue(pullID int64) error {
+	deleted, err := x.Where("pull_id = ?", pullID).Delete(new(MergeQueueEntry))
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrMergeQueueEntryNotExist{PullID: pullID}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQueue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, AddToMergeQueue(&MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 1, DoerID: 1, MergeStyle: MergeStyleMerge}))
+	assert.NoError(t, AddToMergeQueue(&MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 2, DoerID: 2, MergeStyle: MergeStyleSquash}))
+	err := AddToMergeQueue(&MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 1, DoerID: 1, MergeStyle: MergeStyleMerge})
+	assert.True(t, IsErrMergeQueueEntryAlreadyExists(err))
+
+	entry, err := GetMergeQueueEntryByPullID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeQueueStatusWaiting, entry.Status)
+	assert.Equal(t, "merge-queue/master", entry.TestBranch())
+	assert.NoError(t, entry.LoadAttributes())
+	assert.EqualValues(t, 1, entry.Pull.ID)
+	assert.EqualValues(t, 1, entry.Doer.ID)
+
+	entry.Status = MergeQueueStatusTesting
+	entry.TestSHA = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	assert.NoError(t, UpdateMergeQueueEntryCols(entry, "status", "test_sha"))
+	entries, err := GetMergeQueueEntriesByTestSHA(1, entry.TestSHA)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, 1, entries[0].PullID)
+	}
+
+	// failed entries are listed last and can be added again
+	entry.Status = MergeQueueStatusFailed
+	entry.FailReason = "Merge conflicts with the base branch"
+	assert.NoError(t, UpdateMergeQueueEntryCols(entry, "status", "fail_reason"))
+	entries, err = GetMergeQueueEntries(1, "master")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.EqualValues(t, 2, entries[0].PullID)
+		assert.EqualValues(t, 1, entries[1].PullID)
+	}
+	entries, err = GetMergeQueueEntriesToProcess()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, AddToMergeQueue(&MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 1, DoerID: 1, MergeStyle: MergeStyleMerge}))
+	entry, err = GetMergeQueueEntryByPullID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeQueueStatusWaiting, entry.Status)
+	assert.Empty(t, entry.FailReason)
+	entries, err = GetMergeQueueEntries(1, "master")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.EqualValues(t, 2, entries[0].PullID)
+		assert.EqualValues(t, 1, entries[1].PullID)
+	}
+
+	assert.NoError(t, RemoveFromMergeQueue(2))
+	assert.True(t, IsErrMergeQueueEntryNotExist(RemoveFromMergeQueue(2)))
+	_, err = GetMergeQueueEntryByPullID(2)
+	assert.True(t, IsErrMergeQueueEntryNotExist(err))
+}
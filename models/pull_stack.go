@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// LoadBasePull loads the pull request this pull request is stacked on
+func (pr *PullRequest) LoadBasePull() (err error) {
+	if pr.BasePullID == 0 || pr.BasePull != nil {
+		return nil
+	}
+	pr.BasePull, err = GetPullRequestByID(pr.BasePullID)
+	return err
+}
+
+// CheckBasePull checks whether a pull request can be stacked on a base pull request,
+// the pull request does not need to exist yet
+func CheckBasePull(pr, basePull *PullRequest) error {
+	invalid := func(reason string) error {
+		return ErrInvalidBasePull{PullID: pr.ID, BasePullID: basePull.ID, Reason: reason}
+	}
+
+	if err := basePull.LoadIssue(); err != nil {
+		return err
+	}
+	if basePull.HasMerged || basePull.Issue.IsClosed {
+		return invalid("the base pull request is closed")
+	}
+	if basePull.HeadRepoID != pr.BaseRepoID || basePull.BaseRepoID != pr.BaseRepoID {
+		return invalid("the base pull request must be a pull request between branches of the repository")
+	}
+	if basePull.HeadRepoID == pr.HeadRepoID && basePull.HeadBranch == pr.HeadBranch {
+		return invalid("the pull requests have the same head branch")
+	}
+
+	// the base pull requests of the base pull request can't be stacked on the pull request
+	for current := basePull; current.BasePullID != 0; current = current.BasePull {
+		if current.BasePullID == pr.ID {
+			return invalid("the base pull request is stacked on the pull request")
+		}
+		if err := current.LoadBasePull(); err != nil {
+			if IsErrPullRequestNotExist(err) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateBasePull stacks a pull request on its base pull request, or unstacks it if it has none
+func (pr *PullRequest) UpdateBasePull() error {
+	_, err := x.ID(pr.ID).Cols("base_pull_id").Update(pr)
+	return err
+}
+
+// GetStackedPullRequests returns the open pull requests stacked on a pull request
+func GetStackedPullRequests(basePullID int64) ([]*PullRequest, error) {
+	prs := make([]*PullRequest, 0, 2)
+	return prs, x.
+		Where("base_pull_id=? AND has_merged=? AND issue.is_closed=?", basePullID, false, false).
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Asc("pull_request.id").
+		Find(&prs)
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestStack(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	basePull := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr := &PullRequest{HeadRepoID: 1, BaseRepoID: 1, HeadBranch: "feature", BaseBranch: basePull.HeadBranch}
+	assert.NoError(t, CheckBasePull(pr, basePull))
+
+	// merged pull requests and pull requests of other repositories can't be stacked on
+	err := CheckBasePull(pr, AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest))
+	assert.True(t, IsErrInvalidBasePull(err))
+	err = CheckBasePull(pr, AssertExistsAndLoadBean(t, &PullRequest{ID: 3}).(*PullRequest))
+	assert.True(t, IsErrInvalidBasePull(err))
+	err = CheckBasePull(&PullRequest{HeadRepoID: 1, BaseRepoID: 1, HeadBranch: basePull.HeadBranch}, basePull)
+	assert.True(t, IsErrInvalidBasePull(err))
+
+	stacked := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	stacked.ID = 0
+	stacked.Index = 0
+	stacked.HeadBranch = "feature"
+	stacked.BaseBranch = basePull.HeadBranch
+	stacked.BasePullID = basePull.ID
+	assert.NoError(t, NewPullRequest(AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository),
+		&Issue{RepoID: 1, PosterID: 1, Title: "stacked", IsPull: true}, nil, nil, stacked))
+
+	prs, err := GetStackedPullRequests(basePull.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, prs, 1) {
+		assert.Equal(t, stacked.ID, prs[0].ID)
+		assert.NoError(t, prs[0].LoadBasePull())
+		assert.Equal(t, basePull.ID, prs[0].BasePull.ID)
+	}
+
+	// a pull request can't be stacked on the pull requests stacked on it
+	err = CheckBasePull(basePull, stacked)
+	assert.True(t, IsErrInvalidBasePull(err))
+
+	stacked.BasePullID = 0
+	assert.NoError(t, stacked.UpdateBasePull())
+	prs, err = GetStackedPullRequests(basePull.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prs, 0)
+}
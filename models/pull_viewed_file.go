@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// PullViewedFile represents a file of a pull request a user marked as viewed, the mark only
+// applies to the version of the file it was made on: it is ignored once the file changes
+type PullViewedFile struct {
+	ID       int64  `xorm:"pk autoincr"`
+	RepoID   int64  `xorm:"INDEX NOT NULL"`
+	PullID   int64  `xorm:"INDEX(s) NOT NULL"`
+	UserID   int64  `xorm:"INDEX(s) NOT NULL"`
+	TreePath string `xorm:"TEXT NOT NULL"`
+	// the blob of the file at the head of the pull request when it was marked, empty if
+	// the pull request deletes the file
+	BlobSHA string `xorm:"VARCHAR(40)"`
+
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// GetPullViewedFiles returns the files of a pull request a user marked as viewed, on any version
+// of the files, ordered by path
+func GetPullViewedFiles(pullID, userID int64) ([]*PullViewedFile, error) {
+	files := make([]*PullViewedFile, 0, 10)
+	return files, x.
+		Where("pull_id = ? AND user_id = ?", pullID, userID).
+		OrderBy("tree_path").
+		Find(&files)
+}
+
+// SetPullFilesViewed marks the files of a pull request as viewed by a user, the files are
+// mapped to their blob at the head of the pull request
+func SetPullFilesViewed(pr *PullRequest, userID int64, blobs map[string]string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	for treePath, blobSHA := range blobs {
+		file := &PullViewedFile{
+			RepoID:   pr.BaseRepoID,
+			PullID:   pr.ID,
+			UserID:   userID,
+			TreePath: treePath,
+			BlobSHA:  blobSHA,
+		}
+		has, err := sess.
+			Where("pull_id = ? AND user_id = ? AND tree_path = ?", pr.ID, userID, treePath).
+			Get(&PullViewedFile{})
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = sess.
+				Where("pull_id = ? AND user_id = ? AND tree_path = ?", pr.ID, userID, treePath).
+				Cols("blob_sha", "updated_unix").
+				Update(file)
+		} else {
+			_, err = sess.Insert(file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// UnsetPullFilesViewed removes the viewed marks of a user on files of a pull request
+func UnsetPullFilesViewed(pullID, userID int64, treePaths []string) error {
+	if len(treePaths) == 0 {
+		return nil
+	}
+	_, err := x.
+		Where("pull_id = ? AND user_id = ?", pullID, userID).
+		In("tree_path", treePaths).
+		Delete(new(PullViewedFile))
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullViewedFiles(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	assert.NoError(t, SetPullFilesViewed(pr, 2, map[string]string{
+		"README.md": "4b4851ad51df6a7d9f25c979345979eaeb5b349f",
+		"deleted":   "",
+	}))
+	// marking a file again updates its blob
+	assert.NoError(t, SetPullFilesViewed(pr, 2, map[string]string{
+		"README.md": "00750edc07d6415dcc07ae0351e9397b0222b7ba",
+	}))
+	assert.NoError(t, SetPullFilesViewed(pr, 1, map[string]string{
+		"README.md": "4b4851ad51df6a7d9f25c979345979eaeb5b349f",
+	}))
+
+	files, err := GetPullViewedFiles(pr.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "README.md", files[0].TreePath)
+		assert.Equal(t, "00750edc07d6415dcc07ae0351e9397b0222b7ba", files[0].BlobSHA)
+		assert.EqualValues(t, 1, files[0].RepoID)
+		assert.Equal(t, "deleted", files[1].TreePath)
+		assert.Empty(t, files[1].BlobSHA)
+	}
+
+	assert.NoError(t, UnsetPullFilesViewed(pr.ID, 2, []string{"README.md", "unknown"}))
+	files, err = GetPullViewedFiles(pr.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "deleted", files[0].TreePath)
+	}
+
+	// the marks of the other users are kept
+	files, err = GetPullViewedFiles(pr.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
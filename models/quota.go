@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// StorageUsage represents the storage used by the repositories of a user or an organization, the sizes are in bytes
+type StorageUsage struct {
+	Git         int64
+	LFS         int64
+	Attachments int64
+}
+
+// Total returns the total storage used
+func (u *StorageUsage) Total() int64 {
+	return u.Git + u.LFS + u.Attachments
+}
+
+// GetStorageUsage returns the storage used by the repositories of a user or an organization
+func GetStorageUsage(ownerID int64) (*StorageUsage, error) {
+	repoIDs := builder.Select("id").From("repository").Where(builder.Eq{"owner_id": ownerID})
+
+	// the size of the repositories includes their LFS objects
+	size, err := x.Where("owner_id = ?", ownerID).SumInt(new(Repository), "size")
+	if err != nil {
+		return nil, fmt.Errorf("sum size of repositories: %v", err)
+	}
+	usage := new(StorageUsage)
+	if usage.LFS, err = x.Where(builder.In("repository_id", repoIDs)).SumInt(new(LFSMetaObject), "size"); err != nil {
+		return nil, fmt.Errorf("sum size of LFS objects: %v", err)
+	}
+	if usage.Attachments, err = x.Where(attachmentsOfReposCond(repoIDs)).SumInt(new(Attachment), "size"); err != nil {
+		return nil, fmt.Errorf("sum size of attachments: %v", err)
+	}
+	if size > usage.LFS {
+		usage.Git = size - usage.LFS
+	}
+	return usage, nil
+}
+
+// StorageQuota returns the storage quota in bytes of the user or the organization, -1 means unlimited
+func (u *User) StorageQuota() int64 {
+	if !setting.Quota.Enabled {
+		return -1
+	}
+	if u.MaxStorageSize <= -1 {
+		return setting.Quota.DefaultMaxSize
+	}
+	return u.MaxStorageSize
+}
+
+// CheckStorageQuota returns ErrStorageQuotaExceeded if adding size bytes to the storage used by the
+// repositories of the user or the organization exceeds its quota
+func CheckStorageQuota(owner *User, size int64) error {
+	quota := owner.StorageQuota()
+	if quota <= -1 {
+		return nil
+	}
+	usage, err := GetStorageUsage(owner.ID)
+	if err != nil {
+		return err
+	}
+	if usage.Total()+size > quota {
+		return ErrStorageQuotaExceeded{Owner: owner.Name, Quota: quota, Used: usage.Total(), Size: size}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStorageUsage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// attachments of issues of repo1 and repo2 of user2 and of repo3 of user3
+	for id, size := range map[int64]int64{1: 10, 2: 100, 8: 1000} {
+		_, err := x.ID(id).Cols("size").Update(&Attachment{Size: size})
+		assert.NoError(t, err)
+	}
+	_, err := x.ID(1).Cols("size").Update(&Repository{Size: 5000})
+	assert.NoError(t, err)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	meta, err := NewLFSMetaObject(&LFSMetaObject{Oid: "5c2c3a4c3a1bf1ab0e4d4fd5d1c2e7cf5e66b1d5e9d3e7bfe0d0a0c4b2b9f5a1", Size: 42, RepositoryID: repo.ID})
+	assert.NoError(t, err)
+	defer repo.RemoveLFSMetaObjectByOid(meta.Oid)
+
+	usage, err := GetStorageUsage(2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &StorageUsage{Git: 4958, LFS: 42, Attachments: 110}, usage)
+	assert.EqualValues(t, 5110, usage.Total())
+
+	usage, err = GetStorageUsage(3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &StorageUsage{Attachments: 1000}, usage)
+}
+
+func TestCheckStorageQuota(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := x.ID(1).Cols("size").Update(&Repository{Size: 5000})
+	assert.NoError(t, err)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.EqualValues(t, -1, user.MaxStorageSize)
+
+	defer func(enabled bool, defaultMaxSize int64) {
+		setting.Quota.Enabled = enabled
+		setting.Quota.DefaultMaxSize = defaultMaxSize
+	}(setting.Quota.Enabled, setting.Quota.DefaultMaxSize)
+
+	// the quotas are ignored unless they're enabled
+	setting.Quota.Enabled = false
+	user.MaxStorageSize = 1000
+	assert.EqualValues(t, -1, user.StorageQuota())
+	assert.NoError(t, CheckStorageQuota(user, 1000))
+
+	setting.Quota.Enabled = true
+	setting.Quota.DefaultMaxSize = -1
+	user.MaxStorageSize = -1
+	assert.EqualValues(t, -1, user.StorageQuota())
+	assert.NoError(t, CheckStorageQuota(user, 1<<40))
+
+	setting.Quota.DefaultMaxSize = 6000
+	assert.EqualValues(t, 6000, user.StorageQuota())
+	assert.NoError(t, CheckStorageQuota(user, 1000))
+	err = CheckStorageQuota(user, 1001)
+	assert.True(t, IsErrStorageQuotaExceeded(err))
+	assert.EqualValues(t, ErrStorageQuotaExceeded{Owner: "user2", Quota: 6000, Used: 5000, Size: 1001}, err)
+
+	user.MaxStorageSize = 10000
+	assert.EqualValues(t, 10000, user.StorageQuota())
+	assert.NoError(t, CheckStorageQuota(user, 1001))
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RecalculationStatus represents the status of a recalculation
+type RecalculationStatus int
+
+// Enumerate all the statuses of a recalculation
+const (
+	// RecalculationNotStarted means the recalculation was never started
+	RecalculationNotStarted RecalculationStatus = iota
+	// RecalculationRunning means the recalculation is running or is resumed at the next start
+	RecalculationRunning
+	// RecalculationFinished means all the items were recalculated
+	RecalculationFinished
+	// RecalculationFailed means the recalculation stopped because of an error
+	RecalculationFailed
+	// RecalculationCancelled means the recalculation was cancelled by an admin
+	RecalculationCancelled
+)
+
+// Recalculation represents the persisted progress of a long-running recomputation of the items of a kind,
+// e.g. the stats of all the repositories, the items are recalculated in the order of their IDs
+type Recalculation struct {
+	ID     int64               `xorm:"pk autoincr"`
+	Name   string              `xorm:"UNIQUE NOT NULL"`
+	Status RecalculationStatus `xorm:"NOT NULL DEFAULT 0"`
+	// LastID is the ID of the last recalculated item, the recalculation is resumed after it
+	LastID int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Done is the number of recalculated items and Total the number of items when the recalculation started
+	Done  int64  `xorm:"NOT NULL DEFAULT 0"`
+	Total int64  `xorm:"NOT NULL DEFAULT 0"`
+	Error string `xorm:"TEXT"`
+
+	StartedUnix  timeutil.TimeStamp
+	FinishedUnix timeutil.TimeStamp
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsRunning returns whether the recalculation is running
+func (r *Recalculation) IsRunning() bool {
+	return r.Status == RecalculationRunning
+}
+
+// Percent returns the percentage of the recalculated items
+func (r *Recalculation) Percent() int {
+	if r.Status == RecalculationFinished {
+		return 100
+	}
+	if r.Total <= 0 {
+		return 0
+	}
+	percent := int(r.Done * 100 / r.Total)
+	// the number of items may have grown since the recalculation started
+	if percent > 99 {
+		return 99
+	}
+	return percent
+}
+
+// GetRecalculation returns the progress of the recalculation of a name, its ID is 0 if it was never started
+func GetRecalculation(name string) (*Recalculation, error) {
+	r := &Recalculation{Name: name}
+	if _, err := x.Where("name = ?", name).Get(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetRecalculations returns the progress of all the recalculations which were started
+func GetRecalculations() ([]*Recalculation, error) {
+	recalculations := make([]*Recalculation, 0, 5)
+	return recalculations, x.Asc("name").Find(&recalculations)
+}
+
+// GetRunningRecalculations returns the recalculations which are running
+func GetRunningRecalculations() ([]*Recalculation, error) {
+	recalculations := make([]*Recalculation, 0, 5)
+	return recalculations, x.Where("status = ?", RecalculationRunning).Asc("id").Find(&recalculations)
+}
+
+// SaveRecalculation saves the progress of a recalculation
+func SaveRecalculation(r *Recalculation) error {
+	if r.ID == 0 {
+		_, err := x.Insert(r)
+		return err
+	}
+	_, err := x.ID(r.ID).AllCols().Update(r)
+	return err
+}
+
+// GetRepositoryIDsAfter returns up to limit IDs of the repositories greater than afterID in increasing order
+func GetRepositoryIDsAfter(afterID int64, limit int) ([]int64, error) {
+	ids := make([]int64, 0, limit)
+	return ids, x.Table("repository").Where(builder.Gt{"id": afterID}).Asc("id").Limit(limit).Cols("id").Find(&ids)
+}
+
+// GetLabelIDsAfter returns up to limit IDs of the labels greater than afterID in increasing order
+func GetLabelIDsAfter(afterID int64, limit int) ([]int64, error) {
+	ids := make([]int64, 0, limit)
+	return ids, x.Table("label").Where(builder.Gt{"id": afterID}).Asc("id").Limit(limit).Cols("id").Find(&ids)
+}
+
+// CountLabels returns the number of labels of the repositories and the organizations
+func CountLabels() (int64, error) {
+	return x.Count(new(Label))
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveRecalculation(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	r, err := GetRecalculation("repo_stats")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, r.ID)
+	assert.Equal(t, RecalculationNotStarted, r.Status)
+	assert.Equal(t, 0, r.Percent())
+
+	r.Status = RecalculationRunning
+	r.Total = 4
+	r.Done = 1
+	r.LastID = 3
+	assert.NoError(t, SaveRecalculation(r))
+	assert.NotZero(t, r.ID)
+	assert.Equal(t, 25, r.Percent())
+
+	r.Done = 5
+	assert.NoError(t, SaveRecalculation(r))
+	r, err = GetRecalculation("repo_stats")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, r.LastID)
+	assert.Equal(t, 99, r.Percent())
+
+	running, err := GetRunningRecalculations()
+	assert.NoError(t, err)
+	assert.Len(t, running, 1)
+
+	r.Status = RecalculationFinished
+	assert.NoError(t, SaveRecalculation(r))
+	assert.Equal(t, 100, r.Percent())
+	running, err = GetRunningRecalculations()
+	assert.NoError(t, err)
+	assert.Len(t, running, 0)
+}
+
+func TestGetIDsAfter(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	ids, err := GetRepositoryIDsAfter(0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	ids, err = GetRepositoryIDsAfter(3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, ids)
+
+	ids, err = GetLabelIDsAfter(2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids)
+}
+
+func TestRecalculateRepositoryCounts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := x.ID(1).Cols("num_watches", "num_stars", "num_forks", "num_issues", "num_closed_issues", "num_pulls", "num_closed_pulls").
+		Update(&Repository{NumWatches: 10, NumStars: 10, NumForks: 10, NumIssues: 10, NumClosedIssues: 10, NumPulls: 10, NumClosedPulls: 10})
+	assert.NoError(t, err)
+
+	assert.NoError(t, RecalculateRepositoryCounts(1))
+	CheckConsistencyFor(t, &Repository{ID: 1})
+}
+
+func TestRecalculateLabelCounts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := x.ID(1).Cols("num_issues", "num_closed_issues").Update(&Label{NumIssues: 10, NumClosedIssues: 10})
+	assert.NoError(t, err)
+
+	assert.NoError(t, RecalculateLabelCounts(1))
+	label := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
+	assert.EqualValues(t, 2, label.NumIssues)
+	assert.EqualValues(t, 0, label.NumClosedIssues)
+	CheckConsistencyFor(t, &Label{ID: 1})
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// DefaultReleaseTitleTemplate is the template of the titles of the releases created from the pushed
+// tags if an automation doesn't have one
+const DefaultReleaseTitleTemplate = "${TagName}"
+
+// ReleaseAutomation represents the automation of a repository creating a release when a tag
+// matching its pattern is pushed
+type ReleaseAutomation struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+	Enabled bool  `xorm:"NOT NULL DEFAULT false"`
+	// TagPattern is the glob pattern of the names of the tags, like v*, all the tags match an empty pattern
+	TagPattern    string `xorm:"NOT NULL"`
+	Draft         bool   `xorm:"NOT NULL DEFAULT true"`
+	Prerelease    bool   `xorm:"NOT NULL DEFAULT false"`
+	TitleTemplate string `xorm:"NOT NULL"`
+	NoteTemplate  string `xorm:"TEXT"`
+	// DoerID is the user which last changed the automation
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// MatchTag returns whether the automation creates a release for a tag
+func (a *ReleaseAutomation) MatchTag(tagName string) bool {
+	pattern := strings.TrimSpace(a.TagPattern)
+	if len(pattern) == 0 {
+		return true
+	}
+	g, err := glob.Compile(pattern)
+	if err != nil {
+		log.Info("Invalid tag pattern '%s' of the release automation of repository %d: %v", pattern, a.RepoID, err)
+		return false
+	}
+	return g.Match(tagName)
+}
+
+// GetTitleTemplate returns the template of the titles of the releases
+func (a *ReleaseAutomation) GetTitleTemplate() string {
+	if len(strings.TrimSpace(a.TitleTemplate)) == 0 {
+		return DefaultReleaseTitleTemplate
+	}
+	return a.TitleTemplate
+}
+
+// GetReleaseAutomation returns the release automation of the repository
+func GetReleaseAutomation(repoID int64) (*ReleaseAutomation, error) {
+	automation := new(ReleaseAutomation)
+	has, err := x.Where("repo_id = ?", repoID).Get(automation)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReleaseAutomationNotExist{RepoID: repoID}
+	}
+	return automation, nil
+}
+
+// SaveReleaseAutomation creates the release automation of its repository or replaces the existing one
+func SaveReleaseAutomation(automation *ReleaseAutomation) error {
+	existing, err := GetReleaseAutomation(automation.RepoID)
+	if err != nil {
+		if !IsErrReleaseAutomationNotExist(err) {
+			return err
+		}
+		_, err = x.Insert(automation)
+		return err
+	}
+	automation.ID = existing.ID
+	_, err = x.ID(automation.ID).AllCols().Omit("created_unix").Update(automation)
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseAutomation(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := GetReleaseAutomation(1)
+	assert.True(t, IsErrReleaseAutomationNotExist(err))
+
+	automation := &ReleaseAutomation{
+		RepoID:     1,
+		Enabled:    true,
+		TagPattern: "v*",
+		Draft:      true,
+		DoerID:     2,
+	}
+	assert.NoError(t, SaveReleaseAutomation(automation))
+	assert.Equal(t, DefaultReleaseTitleTemplate, automation.GetTitleTemplate())
+	assert.True(t, automation.MatchTag("v1.0.0"))
+	assert.False(t, automation.MatchTag("nightly"))
+
+	automation = &ReleaseAutomation{
+		RepoID:        1,
+		Enabled:       true,
+		TitleTemplate: "Release ${TagName}",
+		DoerID:        2,
+	}
+	assert.NoError(t, SaveReleaseAutomation(automation))
+	assert.True(t, automation.MatchTag("nightly"))
+
+	automation, err = GetReleaseAutomation(1)
+	assert.NoError(t, err)
+	assert.Empty(t, automation.TagPattern)
+	assert.False(t, automation.Draft)
+	assert.Equal(t, "Release ${TagName}", automation.GetTitleTemplate())
+	AssertCount(t, &ReleaseAutomation{}, 1)
+
+	// an invalid pattern matches no tag
+	automation.TagPattern = "v[1"
+	assert.False(t, automation.MatchTag("v1"))
+}
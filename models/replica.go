@@ -0,0 +1,141 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/names"
+)
+
+var (
+	// replica is the read-only replica of the database the expensive list and search queries
+	// are read from, it's nil if none is configured
+	replica *xorm.Engine
+	// replicaAvailable is 1 while the replica is reachable and doesn't lag too much behind the primary
+	replicaAvailable int32
+
+	replicaMonitorOnce sync.Once
+)
+
+// initReplica connects to the read-only replica of the database of the settings
+func initReplica() error {
+	replica = nil
+	atomic.StoreInt32(&replicaAvailable, 0)
+	if len(setting.Database.ReplicaDSN) == 0 {
+		return nil
+	}
+
+	engine, err := newEngine(setting.Database.ReplicaDSN)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to the replica of the database: %v", err)
+	}
+	engine.SetMapper(names.GonicMapper{})
+	engine.SetLogger(NewXORMLogger(setting.Database.LogSQL))
+	engine.ShowSQL(setting.Database.LogSQL)
+	engine.SetMaxOpenConns(setting.Database.MaxOpenConns)
+	engine.SetMaxIdleConns(setting.Database.MaxIdleConns)
+	engine.SetConnMaxLifetime(setting.Database.ConnMaxLifetime)
+	replica = engine
+
+	// an unavailable replica doesn't prevent the start, the primary is used until it's available
+	checkReplica()
+	return nil
+}
+
+// replicaLag returns how far the replica lags behind the primary, it's only measured for PostgreSQL and MySQL
+func replicaLag(e *xorm.Engine) (time.Duration, error) {
+	switch {
+	case setting.Database.UsePostgreSQL:
+		// the time of the last replayed transaction is old if the primary is idle
+		var seconds float64
+		if _, err := e.SQL(`SELECT CASE
+			WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END`).Get(&seconds); err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	case setting.Database.UseMySQL:
+		status, err := e.QueryString("SHOW SLAVE STATUS")
+		if err != nil {
+			return 0, err
+		}
+		if len(status) == 0 {
+			// not a replica
+			return 0, nil
+		}
+		seconds, err := strconv.ParseInt(status[0]["Seconds_Behind_Master"], 10, 64)
+		if err != nil {
+			// it's NULL if the replication is stopped
+			return 0, fmt.Errorf("replication isn't running")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, nil
+}
+
+// checkReplica updates whether the replica is available
+func checkReplica() {
+	if replica == nil {
+		return
+	}
+
+	available := int32(1)
+	if err := replica.Ping(); err != nil {
+		log.Warn("The replica of the database is unreachable: %v", err)
+		available = 0
+	} else if lag, err := replicaLag(replica); err != nil {
+		log.Warn("Unable to measure the lag of the replica of the database: %v", err)
+		available = 0
+	} else if lag > setting.Database.ReplicaMaxLag {
+		log.Warn("The replica of the database lags %v behind the primary", lag)
+		available = 0
+	}
+	if atomic.SwapInt32(&replicaAvailable, available) != available && available == 1 {
+		log.Info("Reading from the replica of the database")
+	}
+}
+
+// monitorReplica checks the replica periodically until the context is done
+func monitorReplica(ctx context.Context) {
+	replicaMonitorOnce.Do(func() {
+		ticker := time.NewTicker(setting.Database.ReplicaCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkReplica()
+			}
+		}
+	})
+}
+
+// withReadEngine runs a read-only query on the replica if it's available and usePrimary is false,
+// the query is run again on the primary if it fails on the replica. Transactional paths reading
+// their own writes must use the primary.
+func withReadEngine(usePrimary bool, query func(e *xorm.Engine) error) error {
+	if usePrimary || replica == nil || atomic.LoadInt32(&replicaAvailable) == 0 {
+		return query(x)
+	}
+
+	err := query(replica)
+	if err == nil {
+		return nil
+	}
+	log.Warn("Query failed on the replica of the database, the primary is used until it's checked again: %v", err)
+	atomic.StoreInt32(&replicaAvailable, 0)
+	return query(x)
+}
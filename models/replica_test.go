@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+)
+
+func TestWithReadEngine(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	usedEngine := func(usePrimary bool) *xorm.Engine {
+		var used *xorm.Engine
+		assert.NoError(t, withReadEngine(usePrimary, func(e *xorm.Engine) error {
+			used = e
+			return nil
+		}))
+		return used
+	}
+
+	// without replica
+	assert.Equal(t, x, usedEngine(false))
+
+	// an empty database stands for a replica the queries fail on
+	emptyReplica, err := xorm.NewEngine("sqlite3", "file::memory:")
+	assert.NoError(t, err)
+	defer emptyReplica.Close()
+	replica = emptyReplica
+	defer func() {
+		replica = nil
+		atomic.StoreInt32(&replicaAvailable, 0)
+	}()
+
+	// the replica isn't used until it has been checked
+	assert.Equal(t, x, usedEngine(false))
+	checkReplica()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&replicaAvailable))
+	assert.Equal(t, emptyReplica, usedEngine(false))
+	assert.Equal(t, x, usedEngine(true))
+
+	// the query is run again on the primary and the primary is used until the next check
+	repos, count, err := SearchRepository(&SearchRepoOptions{OwnerID: 2, ListOptions: ListOptions{Page: 1, PageSize: 10}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, count, len(repos))
+	assert.NotEmpty(t, repos)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&replicaAvailable))
+	assert.Equal(t, x, usedEngine(false))
+}
@@ -240,6 +240,10 @@ type Repository struct {
 	CloseIssuesViaCommitInAnyBranch bool               `xorm:"NOT NULL DEFAULT false"`
 	Topics                          []string           `xorm:"TEXT JSON"`
 
+	// License is the SPDX identifier of the license detected on the default branch
+	License          string `xorm:"VARCHAR(255) INDEX"`
+	HasCodeOfConduct bool   `xorm:"NOT NULL DEFAULT false"`
+
 	TrustModel TrustModelType
 
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
@@ -1062,28 +1066,7 @@ func CreateRepository(ctx DBContext, doer, u *User, repo *Repository, overwriteO
 	// insert units for repo
 	var units = make([]RepoUnit, 0, len(DefaultRepoUnits))
 	for _, tp := range DefaultRepoUnits {
-		if tp == UnitTypeIssues {
-			units = append(units, RepoUnit{
-				RepoID: repo.ID,
-				Type:   tp,
-				Config: &IssuesConfig{
-					EnableTimetracker:                setting.Service.DefaultEnableTimetracking,
-					AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime,
-					EnableDependencies:               setting.Service.DefaultEnableDependencies,
-				},
-			})
-		} else if tp == UnitTypePullRequests {
-			units = append(units, RepoUnit{
-				RepoID: repo.ID,
-				Type:   tp,
-				Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true},
-			})
-		} else {
-			units = append(units, RepoUnit{
-				RepoID: repo.ID,
-				Type:   tp,
-			})
-		}
+		units = append(units, NewRepoUnit(repo.ID, tp))
 	}
 
 	if _, err = ctx.e.Insert(&units); err != nil {
@@ -1537,6 +1520,12 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return ErrRepoNotExist{repoID, uid, "", ""}
 	}
 
+	if held, err := isUnderLegalHold(sess, 0, repoID); err != nil {
+		return fmt.Errorf("isUnderLegalHold: %v", err)
+	} else if held {
+		return ErrUnderLegalHold{RepoID: repoID}
+	}
+
 	// Delete Deploy Keys
 	deployKeys, err := listDeployKeys(sess, repo.ID, ListOptions{})
 	if err != nil {
@@ -1597,8 +1586,23 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&CommitStatus{RepoID: repoID},
 		&RepoIndexerStatus{RepoID: repoID},
 		&LanguageStat{RepoID: repoID},
+		&RepoSymbol{RepoID: repoID},
 		&Comment{RefRepoID: repoID},
 		&Task{RepoID: repoID},
+		&StalePolicy{RepoID: repoID},
+		&StaleActionLog{RepoID: repoID},
+		&ReleaseAutomation{RepoID: repoID},
+		&StatusIssueRule{RepoID: repoID},
+		&StatusIssue{RepoID: repoID},
+		&TeamWatchRule{RepoID: repoID},
+		&MergeQueueEntry{RepoID: repoID},
+		&PullViewedFile{RepoID: repoID},
+		&ReviewEnvironment{RepoID: repoID},
+		&RepoBadge{RepoID: repoID},
+		&ContentRevision{RepoID: repoID},
+		&PullAutoPublish{RepoID: repoID},
+		&ShortLink{RepoID: repoID},
+		&IssueSLABreach{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -2079,6 +2083,34 @@ func CheckRepoStats(ctx context.Context) error {
 	return nil
 }
 
+// RecalculateRepositoryCounts recalculates the numbers of watchers, stars, forks, issues and pull requests of a repository
+func RecalculateRepositoryCounts(repoID int64) error {
+	countIssues := func(isPull bool, isClosed ...bool) *builder.Builder {
+		cond := builder.Eq{"repo_id": repoID, "is_pull": isPull}
+		if len(isClosed) > 0 {
+			cond["is_closed"] = isClosed[0]
+		}
+		return builder.Select("COUNT(*)").From("issue").Where(cond)
+	}
+	// MySQL can't select from the table being updated
+	numForks, err := x.Where("fork_id = ?", repoID).Count(new(Repository))
+	if err != nil {
+		return err
+	}
+	_, err = x.ID(repoID).
+		Cols("num_forks").
+		SetExpr("num_watches", builder.Select("COUNT(*)").From("watch").
+			Where(builder.Eq{"repo_id": repoID}.And(builder.Neq{"mode": RepoWatchModeDont}))).
+		SetExpr("num_stars", builder.Select("COUNT(*)").From("star").Where(builder.Eq{"repo_id": repoID})).
+		SetExpr("num_issues", countIssues(false)).
+		SetExpr("num_closed_issues", countIssues(false, true)).
+		SetExpr("num_pulls", countIssues(true)).
+		SetExpr("num_closed_pulls", countIssues(true, true)).
+		NoAutoTime().
+		Update(&Repository{NumForks: int(numForks)})
+	return err
+}
+
 // SetArchiveRepoState sets if a repo is archived
 func (repo *Repository) SetArchiveRepoState(isArchived bool) (err error) {
 	repo.IsArchived = isArchived
@@ -2230,17 +2262,17 @@ func DoctorUserStarNum() (err error) {
 
 // IterateRepository iterate repositories
 func IterateRepository(f func(repo *Repository) error) error {
-	var start int
+	var lastID int64
 	var batchSize = setting.Database.IterateBufferSize
 	for {
 		var repos = make([]*Repository, 0, batchSize)
-		if err := x.Limit(batchSize, start).Find(&repos); err != nil {
+		if err := x.Where("id > ?", lastID).OrderBy("id").Limit(batchSize).Find(&repos); err != nil {
 			return err
 		}
 		if len(repos) == 0 {
 			return nil
 		}
-		start += len(repos)
+		lastID = repos[len(repos)-1].ID
 
 		for _, repo := range repos {
 			if err := f(repo); err != nil {
@@ -0,0 +1,81 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoBadge represents a badge or a quick link shown on the home of a repository, e.g. the build
+// status or the documentation, a badge without an image is shown as a link
+type RepoBadge struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	Name        string             `xorm:"NOT NULL"`
+	LinkURL     string             `xorm:"TEXT NOT NULL"`
+	ImageURL    string             `xorm:"TEXT"`
+	Sort        int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrRepoBadgeNotExist represents a "RepoBadgeNotExist" kind of error.
+type ErrRepoBadgeNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrRepoBadgeNotExist checks if an error is a ErrRepoBadgeNotExist.
+func IsErrRepoBadgeNotExist(err error) bool {
+	_, ok := err.(ErrRepoBadgeNotExist)
+	return ok
+}
+
+func (err ErrRepoBadgeNotExist) Error() string {
+	return fmt.Sprintf("repository badge does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// GetRepoBadges returns the badges of a repository in their order
+func GetRepoBadges(repoID int64) ([]*RepoBadge, error) {
+	badges := make([]*RepoBadge, 0, 5)
+	return badges, x.Where("repo_id = ?", repoID).Asc("sort", "id").Find(&badges)
+}
+
+// GetRepoBadge returns a badge of a repository by its ID
+func GetRepoBadge(repoID, id int64) (*RepoBadge, error) {
+	badge := new(RepoBadge)
+	has, err := x.Where("id = ? AND repo_id = ?", id, repoID).Get(badge)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoBadgeNotExist{ID: id, RepoID: repoID}
+	}
+	return badge, nil
+}
+
+// CreateRepoBadge adds a badge to a repository
+func CreateRepoBadge(badge *RepoBadge) error {
+	_, err := x.Insert(badge)
+	return err
+}
+
+// UpdateRepoBadge updates a badge of a repository
+func UpdateRepoBadge(badge *RepoBadge) error {
+	_, err := x.ID(badge.ID).Cols("name", "link_url", "image_url", "sort").Update(badge)
+	return err
+}
+
+// DeleteRepoBadge deletes a badge of a repository
+func DeleteRepoBadge(repoID, id int64) error {
+	affected, err := x.Where("id = ? AND repo_id = ?", id, repoID).Delete(new(RepoBadge))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrRepoBadgeNotExist{ID: id, RepoID: repoID}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoBadges(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	badges, err := GetRepoBadges(1)
+	assert.NoError(t, err)
+	if assert.Len(t, badges, 2) {
+		assert.Equal(t, "build", badges[0].Name)
+		assert.Equal(t, "docs", badges[1].Name)
+	}
+
+	badge := &RepoBadge{RepoID: 1, Name: "coverage", LinkURL: "https://coverage.example.com", ImageURL: "https://coverage.example.com/badge.svg"}
+	assert.NoError(t, CreateRepoBadge(badge))
+	badge.Sort = 3
+	badge.Name = "cov"
+	assert.NoError(t, UpdateRepoBadge(badge))
+	badge, err = GetRepoBadge(1, badge.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cov", badge.Name)
+	assert.Equal(t, 3, badge.Sort)
+
+	// badges of other repositories are not found
+	_, err = GetRepoBadge(2, badge.ID)
+	assert.True(t, IsErrRepoBadgeNotExist(err))
+	assert.True(t, IsErrRepoBadgeNotExist(DeleteRepoBadge(2, badge.ID)))
+
+	assert.NoError(t, DeleteRepoBadge(1, badge.ID))
+	AssertNotExistsBean(t, &RepoBadge{ID: badge.ID})
+}
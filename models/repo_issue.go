@@ -33,6 +33,18 @@ func (repo *Repository) IsTimetrackerEnabled() bool {
 	return u.IssuesConfig().EnableTimetracker
 }
 
+// IssuesConfig returns the configuration of the issues of the repository, the default one when they aren't enabled
+func (repo *Repository) IssuesConfig() *IssuesConfig {
+	return repo.issuesConfig(x)
+}
+
+func (repo *Repository) issuesConfig(e Engine) *IssuesConfig {
+	if u, err := repo.getUnit(e, UnitTypeIssues); err == nil {
+		return u.IssuesConfig()
+	}
+	return new(IssuesConfig)
+}
+
 // AllowOnlyContributorsToTrackTime returns value of IssuesConfig or the default value
 func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	var u *RepoUnit
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // RepositoryListDefaultPageSize is the default number of repositories
@@ -175,8 +176,16 @@ type SearchRepoOptions struct {
 	// True -> include just has milestones
 	// False -> include just has no milestone
 	HasMilestones util.OptionalBool
+	// SPDX identifier of the license of the repositories
+	License string
+	// None -> include repositories with AND without code of conduct
+	// True -> include just repositories with a code of conduct
+	// False -> include just repositories without code of conduct
+	HasCodeOfConduct util.OptionalBool
 	// LowerNames represents valid lower names to restrict to
 	LowerNames []string
+	// read from the primary even if a replica of the database is available
+	UsePrimary bool
 }
 
 //SearchOrderBy is used to sort the result
@@ -346,6 +355,14 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.Eq{"num_milestones": 0}.Or(builder.IsNull{"num_milestones"}))
 	}
 
+	if len(opts.License) > 0 {
+		cond = cond.And(builder.Eq{"license": opts.License})
+	}
+
+	if opts.HasCodeOfConduct != util.OptionalBoolNone {
+		cond = cond.And(builder.Eq{"has_code_of_conduct": opts.HasCodeOfConduct == util.OptionalBoolTrue})
+	}
+
 	return cond
 }
 
@@ -370,35 +387,62 @@ func SearchRepositoryByCondition(opts *SearchRepoOptions, cond builder.Cond, loa
 		opts.OrderBy = SearchOrderBy(fmt.Sprintf("CASE WHEN owner_id = %d THEN 0 ELSE owner_id END, %s", opts.PriorityOwnerID, opts.OrderBy))
 	}
 
-	sess := x.NewSession()
-	defer sess.Close()
+	var count int64
+	var repos RepositoryList
+	err := withReadEngine(opts.UsePrimary, func(e *xorm.Engine) error {
+		sess := e.NewSession()
+		defer sess.Close()
 
-	count, err := sess.
-		Where(cond).
-		Count(new(Repository))
+		var err error
+		count, err = sess.
+			Where(cond).
+			Count(new(Repository))
 
-	if err != nil {
-		return nil, 0, fmt.Errorf("Count: %v", err)
-	}
+		if err != nil {
+			return fmt.Errorf("Count: %v", err)
+		}
 
-	repos := make(RepositoryList, 0, opts.PageSize)
-	sess.Where(cond).OrderBy(opts.OrderBy.String())
-	if opts.PageSize > 0 {
-		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
-	}
-	if err = sess.Find(&repos); err != nil {
-		return nil, 0, fmt.Errorf("Repo: %v", err)
-	}
+		repos = make(RepositoryList, 0, opts.PageSize)
+		sess.Where(cond).OrderBy(opts.OrderBy.String())
+		if opts.PageSize > 0 {
+			sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+		}
+		if err = sess.Find(&repos); err != nil {
+			return fmt.Errorf("Repo: %v", err)
+		}
 
-	if loadAttributes {
-		if err = repos.loadAttributes(sess); err != nil {
-			return nil, 0, fmt.Errorf("LoadAttributes: %v", err)
+		if loadAttributes {
+			if err = repos.loadAttributes(sess); err != nil {
+				return fmt.Errorf("LoadAttributes: %v", err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return repos, count, nil
 }
 
+// RepositoryLicenseCount represents the number of repositories with a license
+type RepositoryLicenseCount struct {
+	License string
+	Count   int64
+}
+
+// CountSearchRepositoryLicenses returns the number of repositories matching the options for each of their licenses,
+// the most common license first
+func CountSearchRepositoryLicenses(opts *SearchRepoOptions) ([]*RepositoryLicenseCount, error) {
+	counts := make([]*RepositoryLicenseCount, 0, 10)
+	return counts, x.Table("repository").
+		Select("license, COUNT(*) AS count").
+		Where(SearchRepositoryCondition(opts)).
+		GroupBy("license").
+		OrderBy("count DESC, license").
+		Find(&counts)
+}
+
 // accessibleRepositoryCondition takes a user a returns a condition for checking if a repository is accessible
 func accessibleRepositoryCondition(user *User) builder.Cond {
 	var cond = builder.NewCond()
@@ -470,3 +514,42 @@ func FindUserAccessibleRepoIDs(user *User) ([]int64, error) {
 	}
 	return repoIDs, nil
 }
+
+// FindUserCodeAccessibleRepoIDs finds the IDs of the repositories whose code can be read by a user,
+// or by anyone if user is nil. If ownerID isn't 0, only the repositories of this owner are returned.
+func FindUserCodeAccessibleRepoIDs(user *User, ownerID int64) ([]int64, error) {
+	return FindUserUnitAccessibleRepoIDs(user, ownerID, UnitTypeCode)
+}
+
+// FindUserUnitAccessibleRepoIDs finds the IDs of the repositories whose unit can be read by a user,
+// or by anyone if user is nil. If ownerID isn't 0, only the repositories of this owner are returned.
+func FindUserUnitAccessibleRepoIDs(user *User, ownerID int64, unitType UnitType) ([]int64, error) {
+	isAdmin := user != nil && user.IsAdmin
+	var cond = builder.NewCond()
+	if !isAdmin {
+		cond = cond.And(accessibleRepositoryCondition(user))
+	}
+	if ownerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": ownerID})
+	}
+
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where(cond).Find(&repos); err != nil {
+		return nil, fmt.Errorf("FindUserUnitAccessibleRepoIDs: %v", err)
+	}
+
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		if !isAdmin {
+			perm, err := getUserRepoPermission(x, repo, user)
+			if err != nil {
+				return nil, fmt.Errorf("FindUserUnitAccessibleRepoIDs: %v", err)
+			}
+			if !perm.CanRead(unitType) {
+				continue
+			}
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	return repoIDs, nil
+}
@@ -293,3 +293,32 @@ func TestSearchRepositoryByTopicName(t *testing.T) {
 		})
 	}
 }
+
+func TestFindUserCodeAccessibleRepoIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	for _, kase := range []struct {
+		user    *User
+		ownerID int64
+		repoIDs []int64
+	}{
+		{user: nil, ownerID: 3, repoIDs: []int64{32}},
+		{user: user1, ownerID: 3, repoIDs: []int64{3, 5, 32}},
+		{user: user2, ownerID: 3, repoIDs: []int64{3, 5, 32}},
+		{user: user4, ownerID: 3, repoIDs: []int64{3, 32}},
+	} {
+		repoIDs, err := FindUserCodeAccessibleRepoIDs(kase.user, kase.ownerID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, kase.repoIDs, repoIDs)
+	}
+
+	// the code of public repositories is readable by anyone
+	repoIDs, err := FindUserCodeAccessibleRepoIDs(nil, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, repoIDs, int64(1))
+	assert.NotContains(t, repoIDs, int64(2))
+}
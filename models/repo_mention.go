@@ -0,0 +1,200 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// recentInteractionWindow is the number of most recent posters considered
+// when ranking mention candidates by interaction
+const recentInteractionWindow = 50
+
+// MentionOptions holds the options to look up mention candidates of a repository
+type MentionOptions struct {
+	Keyword string
+	// ExcludeUserID is usually the doer, who doesn't need to mention itself
+	ExcludeUserID int64
+	Limit         int
+}
+
+type repoInteraction struct {
+	PosterID        int64
+	LastInteraction timeutil.TimeStamp
+}
+
+// getRecentInteractions returns the most recent activity time on issues and comments
+// of the repository per poster
+func (repo *Repository) getRecentInteractions(e Engine) (map[int64]timeutil.TimeStamp, error) {
+	issues := make([]*repoInteraction, 0, recentInteractionWindow)
+	if err := e.Table("issue").
+		Select("poster_id, MAX(created_unix) AS last_interaction").
+		Where("repo_id = ?", repo.ID).
+		GroupBy("poster_id").
+		OrderBy("last_interaction DESC").
+		Limit(recentInteractionWindow).
+		Find(&issues); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*repoInteraction, 0, recentInteractionWindow)
+	if err := e.Table("comment").
+		Select("comment.poster_id, MAX(comment.created_unix) AS last_interaction").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where("issue.repo_id = ?", repo.ID).
+		GroupBy("comment.poster_id").
+		OrderBy("last_interaction DESC").
+		Limit(recentInteractionWindow).
+		Find(&comments); err != nil {
+		return nil, err
+	}
+
+	interactions := make(map[int64]timeutil.TimeStamp, len(issues)+len(comments))
+	for _, list := range [][]*repoInteraction{issues, comments} {
+		for _, in := range list {
+			if in.PosterID <= 0 {
+				continue
+			}
+			if in.LastInteraction > interactions[in.PosterID] {
+				interactions[in.PosterID] = in.LastInteraction
+			}
+		}
+	}
+	return interactions, nil
+}
+
+func mentionKeywordCond(keyword string) builder.Cond {
+	if len(keyword) == 0 {
+		return builder.NewCond()
+	}
+	lowerKeyword := strings.ToLower(keyword)
+	return builder.Or(
+		builder.Like{"`user`.lower_name", lowerKeyword},
+		builder.Like{"LOWER(`user`.full_name)", lowerKeyword},
+	)
+}
+
+func (repo *Repository) getMentionableUsers(e Engine, opts MentionOptions) ([]*User, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 10
+	}
+
+	interactions, err := repo.getRecentInteractions(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users who can read the repository through an access entry or by owning it.
+	readerCond := builder.Or(
+		builder.In("`user`.id", builder.Select("user_id").From("access").
+			Where(builder.Eq{"repo_id": repo.ID}.And(builder.Gte{"mode": AccessModeRead}))),
+		builder.Eq{"`user`.id": repo.OwnerID},
+	)
+
+	baseCond := builder.Eq{
+		"`user`.type":           UserTypeIndividual,
+		"`user`.is_active":      true,
+		"`user`.prohibit_login": false,
+	}.And(mentionKeywordCond(opts.Keyword))
+	if opts.ExcludeUserID > 0 {
+		baseCond = baseCond.And(builder.Neq{"`user`.id": opts.ExcludeUserID})
+	}
+
+	users := make([]*User, 0, opts.Limit)
+	if len(interactions) > 0 {
+		ids := make([]int64, 0, len(interactions))
+		for id := range interactions {
+			ids = append(ids, id)
+		}
+		cond := baseCond.And(builder.In("`user`.id", ids))
+		if repo.IsPrivate || (repo.Owner.IsOrganization() && !repo.Owner.Visibility.IsPublic()) {
+			// Former collaborators of a private repository can't read it anymore
+			cond = cond.And(readerCond)
+		}
+		if err := e.Where(cond).Find(&users); err != nil {
+			return nil, err
+		}
+		sort.SliceStable(users, func(i, j int) bool {
+			if interactions[users[i].ID] != interactions[users[j].ID] {
+				return interactions[users[i].ID] > interactions[users[j].ID]
+			}
+			return users[i].LowerName < users[j].LowerName
+		})
+		if len(users) >= opts.Limit {
+			return users[:opts.Limit], nil
+		}
+	}
+
+	// Fill the remaining seats with the readers of the repository who didn't interact recently
+	cond := baseCond.And(readerCond)
+	if len(users) > 0 {
+		found := make([]int64, len(users))
+		for i := range users {
+			found[i] = users[i].ID
+		}
+		cond = cond.And(builder.NotIn("`user`.id", found))
+	}
+	readers := make([]*User, 0, opts.Limit-len(users))
+	if err := e.Where(cond).
+		OrderBy("`user`.lower_name ASC").
+		Limit(opts.Limit - len(users)).
+		Find(&readers); err != nil {
+		return nil, err
+	}
+
+	return append(users, readers...), nil
+}
+
+// GetMentionableUsers returns the users who can read the repository and may be mentioned
+// in its issues and pull requests. Users who recently interacted with the repository
+// are ranked first, the remaining readers follow in alphabetical order.
+func (repo *Repository) GetMentionableUsers(opts MentionOptions) ([]*User, error) {
+	return repo.getMentionableUsers(x, opts)
+}
+
+func (repo *Repository) getMentionableTeams(e Engine, opts MentionOptions) ([]*Team, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return nil, nil
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 10
+	}
+
+	// Teams must be able to read issues or pull requests to be notified about a mention,
+	// see ResolveMentionsByVisibility.
+	cond := builder.Eq{"team.org_id": repo.OwnerID}.
+		And(builder.In("team.id", builder.Select("team_id").From("team_repo").Where(builder.Eq{"repo_id": repo.ID}))).
+		And(builder.Or(
+			builder.Gte{"team.authorize": AccessModeOwner},
+			builder.In("team.id", builder.Select("team_id").From("team_unit").
+				Where(builder.In("`type`", UnitTypeIssues, UnitTypePullRequests))),
+		))
+	if len(opts.Keyword) > 0 {
+		cond = cond.And(builder.Like{"team.lower_name", strings.ToLower(opts.Keyword)})
+	}
+
+	teams := make([]*Team, 0, opts.Limit)
+	return teams, e.Where(cond).
+		OrderBy("team.lower_name ASC").
+		Limit(opts.Limit).
+		Find(&teams)
+}
+
+// GetMentionableTeams returns the teams of the owner organization which can read
+// the issues or pull requests of the repository and may be mentioned.
+func (repo *Repository) GetMentionableTeams(opts MentionOptions) ([]*Team, error) {
+	return repo.getMentionableTeams(x, opts)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetMentionableUsers(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	userNames := func(users []*User) []string {
+		names := make([]string, len(users))
+		for i := range users {
+			names[i] = users[i].Name
+		}
+		return names
+	}
+
+	// public repository: recent posters first, then readers
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	users, err := repo.GetMentionableUsers(MentionOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2", "user5"}, userNames(users))
+
+	users, err = repo.GetMentionableUsers(MentionOptions{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, userNames(users))
+
+	users, err = repo.GetMentionableUsers(MentionOptions{Keyword: "user5", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user5"}, userNames(users))
+
+	users, err = repo.GetMentionableUsers(MentionOptions{ExcludeUserID: 1, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2", "user5"}, userNames(users))
+
+	// private organization repository: only users with access
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	users, err = repo.GetMentionableUsers(MentionOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2", "user4"}, userNames(users))
+}
+
+func TestRepository_GetMentionableTeams(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	teams, err := repo.GetMentionableTeams(MentionOptions{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, teams, 2) {
+		assert.Equal(t, "Owners", teams[0].Name)
+		assert.Equal(t, 1, teams[0].NumMembers)
+		assert.Equal(t, "team1", teams[1].Name)
+		assert.Equal(t, 2, teams[1].NumMembers)
+	}
+
+	teams, err = repo.GetMentionableTeams(MentionOptions{Keyword: "team", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, teams, 1)
+
+	// repositories owned by individuals have no teams
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	teams, err = repo.GetMentionableTeams(MentionOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, teams, 0)
+}
@@ -9,8 +9,8 @@ import (
 
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/signing"
 )
 
 type signingMode string
@@ -59,10 +59,25 @@ func signingModeFromStrings(modeStrings []string) []signingMode {
 	return returnable
 }
 
-// SigningKey returns the KeyID and git Signature for the repo
-func SigningKey(repoPath string) (string, *git.Signature) {
+// SigningKey returns the signing key and git Signature for the repo
+func SigningKey(repoPath string) (*git.SigningKey, *git.Signature) {
 	if setting.Repository.Signing.SigningKey == "none" {
-		return "", nil
+		return nil, nil
+	}
+
+	if signing.IsExternal() {
+		keyID, err := signing.KeyID()
+		if err != nil {
+			log.Error("Unable to get the signing key of the %s backend: %v", setting.SigningBackend.Type, err)
+			return nil, nil
+		}
+		return &git.SigningKey{
+			KeyID:  keyID,
+			Format: git.SigningKeyFormatOpenPGP,
+		}, &git.Signature{
+			Name:  setting.Repository.Signing.SigningName,
+			Email: setting.Repository.Signing.SigningEmail,
+		}
 	}
 
 	if setting.Repository.Signing.SigningKey == "default" || setting.Repository.Signing.SigningKey == "" {
@@ -70,70 +85,123 @@ func SigningKey(repoPath string) (string, *git.Signature) {
 		value, _ := git.NewCommand("config", "--get", "commit.gpgsign").RunInDir(repoPath)
 		sign, valid := git.ParseBool(strings.TrimSpace(value))
 		if !sign || !valid {
-			return "", nil
+			return nil, nil
 		}
 
+		format, _ := git.NewCommand("config", "--get", "gpg.format").RunInDir(repoPath)
 		signingKey, _ := git.NewCommand("config", "--get", "user.signingkey").RunInDir(repoPath)
 		signingName, _ := git.NewCommand("config", "--get", "user.name").RunInDir(repoPath)
 		signingEmail, _ := git.NewCommand("config", "--get", "user.email").RunInDir(repoPath)
-		return strings.TrimSpace(signingKey), &git.Signature{
+		return &git.SigningKey{
+			KeyID:  strings.TrimSpace(signingKey),
+			Format: git.ParseSigningKeyFormat(format),
+		}, &git.Signature{
 			Name:  strings.TrimSpace(signingName),
 			Email: strings.TrimSpace(signingEmail),
 		}
 	}
 
-	return setting.Repository.Signing.SigningKey, &git.Signature{
+	return &git.SigningKey{
+		KeyID:  setting.Repository.Signing.SigningKey,
+		Format: setting.Repository.Signing.Format,
+	}, &git.Signature{
 		Name:  setting.Repository.Signing.SigningName,
 		Email: setting.Repository.Signing.SigningEmail,
 	}
 }
 
-// PublicSigningKey gets the public signing key within a provided repository directory
-func PublicSigningKey(repoPath string) (string, error) {
+// PublicSigningKey gets the public signing key of the format within a provided repository directory,
+// it is empty when the signing key has another format
+func PublicSigningKey(repoPath, format string) (string, error) {
 	signingKey, _ := SigningKey(repoPath)
-	if signingKey == "" {
+	if signingKey == nil || signingKey.Format != format {
 		return "", nil
 	}
+	if signing.IsExternal() {
+		return signing.PublicKey()
+	}
 
-	content, stderr, err := process.GetManager().ExecDir(-1, repoPath,
-		"gpg --export -a", "gpg", "--export", "-a", signingKey)
-	if err != nil {
-		log.Error("Unable to get default signing key in %s: %s, %s, %v", repoPath, signingKey, stderr, err)
+	gpgSettings := git.GPGSettings{
+		KeyID:  signingKey.KeyID,
+		Format: signingKey.Format,
+	}
+	if err := gpgSettings.LoadPublicKeyContent(); err != nil {
+		log.Error("Unable to get default signing key in %s: %s, %v", repoPath, signingKey.KeyID, err)
 		return "", err
 	}
-	return content, nil
+	return gpgSettings.PublicKeyContent, nil
+}
+
+// DisplaySigningKey returns the ID of a signing key shown to the users, the fingerprint of an SSH key
+// as its ID is the path of its key file
+func DisplaySigningKey(key *git.SigningKey) string {
+	if key == nil {
+		return ""
+	} else if key.Format != git.SigningKeyFormatSSH {
+		return key.KeyID
+	}
+
+	gpgSettings := git.GPGSettings{
+		KeyID:  key.KeyID,
+		Format: key.Format,
+	}
+	if err := gpgSettings.LoadPublicKeyContent(); err != nil {
+		log.Error("Unable to get the SSH signing key: %v", err)
+		return ""
+	}
+	fingerprint, err := calcFingerprintNative(gpgSettings.PublicKeyContent)
+	if err != nil {
+		log.Error("Unable to calculate the fingerprint of the SSH signing key %s: %v", key.KeyID, err)
+		return ""
+	}
+	return fingerprint
+}
+
+// loadInstanceSigningKey loads the public key of the instance signing key, which is held by gpg unless an
+// external backend holds it
+func loadInstanceSigningKey(gpgSettings *git.GPGSettings) error {
+	if !signing.IsExternal() {
+		return gpgSettings.LoadPublicKeyContent()
+	}
+	keyID, err := signing.KeyID()
+	if err != nil {
+		return err
+	}
+	gpgSettings.KeyID = keyID
+	gpgSettings.PublicKeyContent, err = signing.PublicKey()
+	return err
 }
 
 // SignInitialCommit determines if we should sign the initial commit to this repository
-func SignInitialCommit(repoPath string, u *User) (bool, string, *git.Signature, error) {
+func SignInitialCommit(repoPath string, u *User) (bool, *git.SigningKey, *git.Signature, error) {
 	rules := signingModeFromStrings(setting.Repository.Signing.InitialCommit)
 	signingKey, sig := SigningKey(repoPath)
-	if signingKey == "" {
-		return false, "", nil, &ErrWontSign{noKey}
+	if signingKey == nil {
+		return false, nil, nil, &ErrWontSign{noKey}
 	}
 
 Loop:
 	for _, rule := range rules {
 		switch rule {
 		case never:
-			return false, "", nil, &ErrWontSign{never}
+			return false, nil, nil, &ErrWontSign{never}
 		case always:
 			break Loop
 		case pubkey:
 			keys, err := ListGPGKeys(u.ID, ListOptions{})
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if len(keys) == 0 {
-				return false, "", nil, &ErrWontSign{pubkey}
+				return false, nil, nil, &ErrWontSign{pubkey}
 			}
 		case twofa:
 			twofaModel, err := GetTwoFactorByUID(u.ID)
 			if err != nil && !IsErrTwoFactorNotEnrolled(err) {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if twofaModel == nil {
-				return false, "", nil, &ErrWontSign{twofa}
+				return false, nil, nil, &ErrWontSign{twofa}
 			}
 		}
 	}
@@ -141,52 +209,52 @@ Loop:
 }
 
 // SignWikiCommit determines if we should sign the commits to this repository wiki
-func (repo *Repository) SignWikiCommit(u *User) (bool, string, *git.Signature, error) {
+func (repo *Repository) SignWikiCommit(u *User) (bool, *git.SigningKey, *git.Signature, error) {
 	rules := signingModeFromStrings(setting.Repository.Signing.Wiki)
 	signingKey, sig := SigningKey(repo.WikiPath())
-	if signingKey == "" {
-		return false, "", nil, &ErrWontSign{noKey}
+	if signingKey == nil {
+		return false, nil, nil, &ErrWontSign{noKey}
 	}
 
 Loop:
 	for _, rule := range rules {
 		switch rule {
 		case never:
-			return false, "", nil, &ErrWontSign{never}
+			return false, nil, nil, &ErrWontSign{never}
 		case always:
 			break Loop
 		case pubkey:
 			keys, err := ListGPGKeys(u.ID, ListOptions{})
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if len(keys) == 0 {
-				return false, "", nil, &ErrWontSign{pubkey}
+				return false, nil, nil, &ErrWontSign{pubkey}
 			}
 		case twofa:
 			twofaModel, err := GetTwoFactorByUID(u.ID)
 			if err != nil && !IsErrTwoFactorNotEnrolled(err) {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if twofaModel == nil {
-				return false, "", nil, &ErrWontSign{twofa}
+				return false, nil, nil, &ErrWontSign{twofa}
 			}
 		case parentSigned:
 			gitRepo, err := git.OpenRepository(repo.WikiPath())
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			defer gitRepo.Close()
 			commit, err := gitRepo.GetCommit("HEAD")
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if commit.Signature == nil {
-				return false, "", nil, &ErrWontSign{parentSigned}
+				return false, nil, nil, &ErrWontSign{parentSigned}
 			}
 			verification := ParseCommitWithSignature(commit)
 			if !verification.Verified {
-				return false, "", nil, &ErrWontSign{parentSigned}
+				return false, nil, nil, &ErrWontSign{parentSigned}
 			}
 		}
 	}
@@ -194,52 +262,52 @@ Loop:
 }
 
 // SignCRUDAction determines if we should sign a CRUD commit to this repository
-func (repo *Repository) SignCRUDAction(u *User, tmpBasePath, parentCommit string) (bool, string, *git.Signature, error) {
+func (repo *Repository) SignCRUDAction(u *User, tmpBasePath, parentCommit string) (bool, *git.SigningKey, *git.Signature, error) {
 	rules := signingModeFromStrings(setting.Repository.Signing.CRUDActions)
 	signingKey, sig := SigningKey(repo.RepoPath())
-	if signingKey == "" {
-		return false, "", nil, &ErrWontSign{noKey}
+	if signingKey == nil {
+		return false, nil, nil, &ErrWontSign{noKey}
 	}
 
 Loop:
 	for _, rule := range rules {
 		switch rule {
 		case never:
-			return false, "", nil, &ErrWontSign{never}
+			return false, nil, nil, &ErrWontSign{never}
 		case always:
 			break Loop
 		case pubkey:
 			keys, err := ListGPGKeys(u.ID, ListOptions{})
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if len(keys) == 0 {
-				return false, "", nil, &ErrWontSign{pubkey}
+				return false, nil, nil, &ErrWontSign{pubkey}
 			}
 		case twofa:
 			twofaModel, err := GetTwoFactorByUID(u.ID)
 			if err != nil && !IsErrTwoFactorNotEnrolled(err) {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if twofaModel == nil {
-				return false, "", nil, &ErrWontSign{twofa}
+				return false, nil, nil, &ErrWontSign{twofa}
 			}
 		case parentSigned:
 			gitRepo, err := git.OpenRepository(tmpBasePath)
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			defer gitRepo.Close()
 			commit, err := gitRepo.GetCommit(parentCommit)
 			if err != nil {
-				return false, "", nil, err
+				return false, nil, nil, err
 			}
 			if commit.Signature == nil {
-				return false, "", nil, &ErrWontSign{parentSigned}
+				return false, nil, nil, &ErrWontSign{parentSigned}
 			}
 			verification := ParseCommitWithSignature(commit)
 			if !verification.Verified {
-				return false, "", nil, &ErrWontSign{parentSigned}
+				return false, nil, nil, &ErrWontSign{parentSigned}
 			}
 		}
 	}
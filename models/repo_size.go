@@ -0,0 +1,36 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"xorm.io/builder"
+)
+
+// GetLFSSize returns the total size of the LFS objects of the repository
+func (repo *Repository) GetLFSSize() (int64, error) {
+	size, err := x.Where("repository_id = ?", repo.ID).SumInt(new(LFSMetaObject), "size")
+	if err != nil {
+		return 0, fmt.Errorf("GetLFSSize: %v", err)
+	}
+	return size, nil
+}
+
+// attachmentsOfReposCond returns the condition of the attachments of the issues, comments and releases of
+// the repositories, repoIDs is an ID or a subquery of IDs
+func attachmentsOfReposCond(repoIDs interface{}) builder.Cond {
+	return builder.In("issue_id", builder.Select("id").From("issue").Where(builder.In("repo_id", repoIDs))).
+		Or(builder.In("release_id", builder.Select("id").From("`release`").Where(builder.In("repo_id", repoIDs))))
+}
+
+// GetAttachmentsSize returns the total size of the attachments of the issues, comments and releases of the repository
+func (repo *Repository) GetAttachmentsSize() (int64, error) {
+	size, err := x.Where(attachmentsOfReposCond(repo.ID)).SumInt(new(Attachment), "size")
+	if err != nil {
+		return 0, fmt.Errorf("GetAttachmentsSize: %v", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetAttachmentsSize(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// attachments of an issue and a release of repo1 and of an issue of repo2
+	for id, size := range map[int64]int64{1: 10, 2: 100, 9: 1000} {
+		_, err := x.ID(id).Cols("size").Update(&Attachment{Size: size})
+		assert.NoError(t, err)
+	}
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	size, err := repo.GetAttachmentsSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1010, size)
+
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	size, err = repo.GetAttachmentsSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, size)
+}
+
+func TestRepository_GetLFSSize(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	size, err := repo.GetLFSSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, size)
+
+	_, err = NewLFSMetaObject(&LFSMetaObject{Oid: "5c2c3a4c3a1bf1ab0e4d4fd5d1c2e7cf5e66b1d5e9d3e7bfe0d0a0c4b2b9f5a1", Size: 42, RepositoryID: repo.ID})
+	assert.NoError(t, err)
+	size, err = repo.GetLFSSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 42, size)
+}
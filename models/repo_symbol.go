@@ -0,0 +1,164 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"xorm.io/builder"
+)
+
+// RepoSymbol represents a definition found in a file of the default branch of a repository
+type RepoSymbol struct {
+	ID        int64  `xorm:"pk autoincr"`
+	RepoID    int64  `xorm:"INDEX(s) NOT NULL"`
+	LowerName string `xorm:"VARCHAR(255) INDEX(s) NOT NULL"`
+	Name      string `xorm:"VARCHAR(255) NOT NULL"`
+	Kind      string `xorm:"VARCHAR(20)"`
+	Language  string `xorm:"VARCHAR(50)"`
+	Path      string `xorm:"TEXT NOT NULL"`
+	BlobSHA   string `xorm:"VARCHAR(40) INDEX"`
+	Line      int
+}
+
+// symbolsBatchSize is the number of rows or arguments handled by a single query,
+// it keeps the queries below the limit of bound parameters of the databases
+const symbolsBatchSize = 200
+
+// UpdateSymbols replaces the symbols of the given paths by the given ones and records the
+// commit they have been extracted from. If paths is nil, all the symbols of the repository
+// are replaced.
+func (repo *Repository) UpdateSymbols(commitID string, paths []string, symbols []*RepoSymbol) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if paths == nil {
+		if _, err := sess.Delete(&RepoSymbol{RepoID: repo.ID}); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < len(paths); i += symbolsBatchSize {
+		end := i + symbolsBatchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+		if _, err := sess.Where("repo_id = ?", repo.ID).In("path", paths[i:end]).Delete(new(RepoSymbol)); err != nil {
+			return err
+		}
+	}
+
+	for _, symbol := range symbols {
+		symbol.ID = 0
+		symbol.RepoID = repo.ID
+		symbol.LowerName = strings.ToLower(symbol.Name)
+	}
+	for i := 0; i < len(symbols); i += symbolsBatchSize {
+		end := i + symbolsBatchSize
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		if _, err := sess.Insert(symbols[i:end]); err != nil {
+			return err
+		}
+	}
+
+	if err := repo.updateIndexerStatus(sess, RepoIndexerTypeSymbols, commitID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// SearchSymbolOptions represents the options to search the symbols of a repository
+type SearchSymbolOptions struct {
+	ListOptions
+	RepoID int64
+	// Keyword matches the symbols whose name contains it, case insensitively
+	Keyword string
+	// Name matches the symbols with exactly this name, case insensitively
+	Name     string
+	Kind     string
+	Language string
+	// Path restricts the search to a file or a directory
+	Path string
+}
+
+func (opts *SearchSymbolOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
+	if len(opts.Keyword) > 0 {
+		cond = cond.And(builder.Like{"lower_name", strings.ToLower(opts.Keyword)})
+	}
+	if len(opts.Name) > 0 {
+		cond = cond.And(builder.Eq{"lower_name": strings.ToLower(opts.Name)})
+	}
+	if len(opts.Kind) > 0 {
+		cond = cond.And(builder.Eq{"kind": opts.Kind})
+	}
+	if len(opts.Language) > 0 {
+		cond = cond.And(builder.Eq{"language": opts.Language})
+	}
+	if path := strings.Trim(opts.Path, "/"); len(path) > 0 {
+		cond = cond.And(builder.Or(
+			builder.Eq{"path": path},
+			builder.Like{"path", path + "/%"},
+		))
+	}
+	return cond
+}
+
+// SearchSymbols returns the symbols of a repository matching the given options
+// and the total number of matching symbols
+func SearchSymbols(opts *SearchSymbolOptions) ([]*RepoSymbol, int64, error) {
+	cond := opts.toConds()
+	count, err := x.Where(cond).Count(new(RepoSymbol))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(cond).OrderBy("lower_name ASC, path ASC, line ASC")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	symbols := make([]*RepoSymbol, 0, opts.PageSize)
+	return symbols, count, sess.Find(&symbols)
+}
+
+// GetSymbolsByNames returns the symbols of the repository having exactly one of the given names
+func (repo *Repository) GetSymbolsByNames(names []string) ([]*RepoSymbol, error) {
+	wanted := make(map[string]bool, len(names))
+	lowerWanted := make(map[string]bool, len(names))
+	lowerNames := make([]string, 0, len(names))
+	for _, name := range names {
+		wanted[name] = true
+		if lowerName := strings.ToLower(name); !lowerWanted[lowerName] {
+			lowerWanted[lowerName] = true
+			lowerNames = append(lowerNames, lowerName)
+		}
+	}
+
+	symbols := make([]*RepoSymbol, 0, len(lowerNames))
+	for i := 0; i < len(lowerNames); i += symbolsBatchSize {
+		end := i + symbolsBatchSize
+		if end > len(lowerNames) {
+			end = len(lowerNames)
+		}
+		batch := make([]*RepoSymbol, 0, end-i)
+		if err := x.Where("repo_id = ?", repo.ID).
+			In("lower_name", lowerNames[i:end]).
+			OrderBy("path ASC, line ASC").
+			Find(&batch); err != nil {
+			return nil, err
+		}
+		// The names are looked up case insensitively but identifiers are case sensitive
+		for _, symbol := range batch {
+			if wanted[symbol.Name] {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	return symbols, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_UpdateSymbols(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	assert.NoError(t, repo.UpdateSymbols("sha1", nil, []*RepoSymbol{
+		{Name: "Foo", Kind: "function", Path: "a/foo.go", Line: 3},
+		{Name: "foo", Kind: "variable", Path: "a/foo.go", Line: 10},
+		{Name: "Bar", Kind: "type", Path: "b/bar.go", Line: 1},
+	}))
+	status, err := repo.GetIndexerStatus(RepoIndexerTypeSymbols)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "sha1", status.CommitSha)
+
+	symbols, count, err := SearchSymbols(&SearchSymbolOptions{RepoID: repo.ID, Keyword: "FO"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, symbols, 2)
+
+	symbols, count, err = SearchSymbols(&SearchSymbolOptions{RepoID: repo.ID, Path: "b"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Equal(t, "Bar", symbols[0].Name)
+
+	symbols, err = repo.GetSymbolsByNames([]string{"Foo", "Baz"})
+	assert.NoError(t, err)
+	if assert.Len(t, symbols, 1) {
+		assert.Equal(t, "function", symbols[0].Kind)
+	}
+
+	// Only the symbols of the updated paths are replaced
+	assert.NoError(t, repo.UpdateSymbols("sha2", []string{"a/foo.go"}, []*RepoSymbol{
+		{Name: "Baz", Kind: "function", Path: "a/foo.go", Line: 5},
+	}))
+	_, count, err = SearchSymbols(&SearchSymbolOptions{RepoID: repo.ID})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	symbols, err = repo.GetSymbolsByNames([]string{"Foo", "Baz", "Bar"})
+	assert.NoError(t, err)
+	assert.Len(t, symbols, 2)
+	status, err = repo.GetIndexerStatus(RepoIndexerTypeSymbols)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "sha2", status.CommitSha)
+}
@@ -7,6 +7,8 @@ package models
 import (
 	"encoding/json"
 
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/unknwon/com"
@@ -74,6 +76,16 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	// CloseKeywords and ReopenKeywords are added to the keywords of the instance closing and reopening the
+	// issues referenced by the commits and the pull requests of the repository
+	CloseKeywords  []string
+	ReopenKeywords []string
+	// KeywordLanguages are the languages of the built-in keyword sets added to the keywords of the instance
+	KeywordLanguages []string
+	// DisableCrossRepoClose prevents the references from other repositories from closing or reopening the issues
+	DisableCrossRepoClose bool
+	// CloseOnDefaultBranchOnly only resolves the references of the pull requests merged into the default branch
+	CloseOnDefaultBranchOnly bool
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -86,6 +98,11 @@ func (cfg *IssuesConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// Keywords returns the keywords closing and reopening the referenced issues
+func (cfg *IssuesConfig) Keywords() *references.Keywords {
+	return references.NewKeywords(cfg.KeywordLanguages, cfg.CloseKeywords, cfg.ReopenKeywords)
+}
+
 // PullRequestsConfig describes pull requests config
 type PullRequestsConfig struct {
 	IgnoreWhitespaceConflicts bool
@@ -93,6 +110,13 @@ type PullRequestsConfig struct {
 	AllowRebase               bool
 	AllowRebaseMerge          bool
 	AllowSquash               bool
+	// ReviewChecklist are the items reviewers have to acknowledge to approve a pull request
+	ReviewChecklist []string
+	// MergeMessageTemplate, RebaseMergeMessageTemplate and SquashMessageTemplate are the templates of the
+	// default messages of the merge styles, the first line of a template is the title of the message
+	MergeMessageTemplate       string
+	RebaseMergeMessageTemplate string
+	SquashMessageTemplate      string
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -113,6 +137,19 @@ func (cfg *PullRequestsConfig) IsMergeStyleAllowed(mergeStyle MergeStyle) bool {
 		mergeStyle == MergeStyleSquash && cfg.AllowSquash
 }
 
+// GetMergeMessageTemplate returns the template of the default message of a merge style
+func (cfg *PullRequestsConfig) GetMergeMessageTemplate(mergeStyle MergeStyle) string {
+	switch mergeStyle {
+	case MergeStyleMerge:
+		return cfg.MergeMessageTemplate
+	case MergeStyleRebaseMerge:
+		return cfg.RebaseMergeMessageTemplate
+	case MergeStyleSquash:
+		return cfg.SquashMessageTemplate
+	}
+	return ""
+}
+
 // AllowedMergeStyleCount returns the total count of allowed merge styles for the PullRequestsConfig
 func (cfg *PullRequestsConfig) AllowedMergeStyleCount() int {
 	count := 0
@@ -187,6 +224,32 @@ func (r *RepoUnit) ExternalTrackerConfig() *ExternalTrackerConfig {
 	return r.Config.(*ExternalTrackerConfig)
 }
 
+// NewRepoUnit returns a unit of the repository with the default config of its type
+func NewRepoUnit(repoID int64, tp UnitType) RepoUnit {
+	switch tp {
+	case UnitTypeIssues:
+		return RepoUnit{
+			RepoID: repoID,
+			Type:   tp,
+			Config: &IssuesConfig{
+				EnableTimetracker:                setting.Service.DefaultEnableTimetracking,
+				AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime,
+				EnableDependencies:               setting.Service.DefaultEnableDependencies,
+			},
+		}
+	case UnitTypePullRequests:
+		return RepoUnit{
+			RepoID: repoID,
+			Type:   tp,
+			Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true},
+		}
+	}
+	return RepoUnit{
+		RepoID: repoID,
+		Type:   tp,
+	}
+}
+
 func getUnitsByRepoID(e Engine, repoID int64) (units []*RepoUnit, err error) {
 	var tmpUnits []*RepoUnit
 	if err := e.Where("repo_id = ?", repoID).Find(&tmpUnits); err != nil {
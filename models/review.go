@@ -66,12 +66,16 @@ type Review struct {
 	Official bool   `xorm:"NOT NULL DEFAULT false"`
 	CommitID string `xorm:"VARCHAR(40)"`
 	Stale    bool   `xorm:"NOT NULL DEFAULT false"`
+	// Checklist are the items of the review checklist of the repository acknowledged by the reviewer
+	Checklist []string `xorm:"TEXT JSON"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 
 	// CodeComments are the initial code comments of the review
 	CodeComments CodeComments `xorm:"-"`
+	// CommitMessageComments are the comments of the review on lines of commit messages
+	CommitMessageComments []*Comment `xorm:"-"`
 
 	Comments []*Comment `xorm:"-"`
 }
@@ -92,6 +96,25 @@ func (r *Review) LoadCodeComments() error {
 	return r.loadCodeComments(x)
 }
 
+func (r *Review) loadCommitMessageComments(e Engine) (err error) {
+	if r.CommitMessageComments != nil {
+		return
+	}
+	if err = r.loadIssue(e); err != nil {
+		return
+	}
+	r.CommitMessageComments, err = fetchCommitMessageComments(e, r.Issue, builder.Eq{"comment.review_id": r.ID})
+	for _, comment := range r.CommitMessageComments {
+		comment.Review = r
+	}
+	return
+}
+
+// LoadCommitMessageComments loads CommitMessageComments
+func (r *Review) LoadCommitMessageComments() error {
+	return r.loadCommitMessageComments(x)
+}
+
 func (r *Review) loadIssue(e Engine) (err error) {
 	if r.Issue != nil {
 		return
@@ -134,6 +157,9 @@ func (r *Review) loadAttributes(e Engine) (err error) {
 	if err = r.loadCodeComments(e); err != nil {
 		return
 	}
+	if err = r.loadCommitMessageComments(e); err != nil {
+		return
+	}
 	if err = r.loadReviewer(e); err != nil {
 		return
 	}
@@ -217,6 +243,7 @@ type CreateReviewOptions struct {
 	Official     bool
 	CommitID     string
 	Stale        bool
+	Checklist    []string
 }
 
 // IsOfficialReviewer check if at least one of the provided reviewers can make official reviews in issue (counts towards required approvals)
@@ -281,6 +308,7 @@ func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
 		Official:     opts.Official,
 		CommitID:     opts.CommitID,
 		Stale:        opts.Stale,
+		Checklist:    opts.Checklist,
 	}
 	if opts.Reviewer != nil {
 		review.ReviewerID = opts.Reviewer.ID
@@ -346,8 +374,39 @@ func IsContentEmptyErr(err error) bool {
 	return ok
 }
 
+// ErrReviewChecklistNotAcknowledged represents an approval missing items of the review checklist
+type ErrReviewChecklistNotAcknowledged struct {
+	Items []string
+}
+
+// IsErrReviewChecklistNotAcknowledged returns true if err is a ErrReviewChecklistNotAcknowledged
+func IsErrReviewChecklistNotAcknowledged(err error) bool {
+	_, ok := err.(ErrReviewChecklistNotAcknowledged)
+	return ok
+}
+
+func (err ErrReviewChecklistNotAcknowledged) Error() string {
+	return fmt.Sprintf("review checklist items are not acknowledged: %s", strings.Join(err.Items, ", "))
+}
+
+// ErrCommitMessageLineNotExist represents a line which doesn't exist in the message of a commit of a pull request
+type ErrCommitMessageLineNotExist struct {
+	CommitID string
+	Line     int64
+}
+
+// IsErrCommitMessageLineNotExist returns true if err is a ErrCommitMessageLineNotExist
+func IsErrCommitMessageLineNotExist(err error) bool {
+	_, ok := err.(ErrCommitMessageLineNotExist)
+	return ok
+}
+
+func (err ErrCommitMessageLineNotExist) Error() string {
+	return fmt.Sprintf("line does not exist in the message of the commit of the pull request [commit_id: %s, line: %d]", err.CommitID, err.Line)
+}
+
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
-func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool) (*Review, *Comment, error) {
+func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool, checklist []string) (*Review, *Comment, error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -378,13 +437,14 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 
 		// No current review. Create a new one!
 		if review, err = createReview(sess, CreateReviewOptions{
-			Type:     reviewType,
-			Issue:    issue,
-			Reviewer: doer,
-			Content:  content,
-			Official: official,
-			CommitID: commitID,
-			Stale:    stale,
+			Type:      reviewType,
+			Issue:     issue,
+			Reviewer:  doer,
+			Content:   content,
+			Official:  official,
+			CommitID:  commitID,
+			Stale:     stale,
+			Checklist: checklist,
 		}); err != nil {
 			return nil, nil, err
 		}
@@ -392,7 +452,11 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 		if err := review.loadCodeComments(sess); err != nil {
 			return nil, nil, err
 		}
-		if reviewType != ReviewTypeApprove && len(review.CodeComments) == 0 && len(strings.TrimSpace(content)) == 0 {
+		if err := review.loadCommitMessageComments(sess); err != nil {
+			return nil, nil, err
+		}
+		if reviewType != ReviewTypeApprove && len(review.CodeComments) == 0 && len(review.CommitMessageComments) == 0 &&
+			len(strings.TrimSpace(content)) == 0 {
 			return nil, nil, ContentEmptyErr{}
 		}
 
@@ -412,8 +476,9 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 		review.Type = reviewType
 		review.CommitID = commitID
 		review.Stale = stale
+		review.Checklist = checklist
 
-		if _, err := sess.ID(review.ID).Cols("content, type, official, commit_id, stale").Update(review); err != nil {
+		if _, err := sess.ID(review.ID).Cols("content, type, official, commit_id, stale, checklist").Update(review); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -654,7 +719,7 @@ func AddReviewRequest(issue *Issue, reviewer, doer *User) (*Comment, error) {
 	return comment, sess.Commit()
 }
 
-//RemoveReviewRequest remove a review request from one reviewer
+// RemoveReviewRequest remove a review request from one reviewer
 func RemoveReviewRequest(issue *Issue, reviewer, doer *User) (*Comment, error) {
 	sess := x.NewSession()
 	defer sess.Close()
@@ -766,7 +831,7 @@ func AddTeamReviewRequest(issue *Issue, reviewer *Team, doer *User) (*Comment, e
 	return comment, sess.Commit()
 }
 
-//RemoveTeamReviewRequest remove a review request from one team
+// RemoveTeamReviewRequest remove a review request from one team
 func RemoveTeamReviewRequest(issue *Issue, reviewer *Team, doer *User) (*Comment, error) {
 	sess := x.NewSession()
 	defer sess.Close()
@@ -911,6 +976,16 @@ func DeleteReview(r *Review) error {
 		return err
 	}
 
+	opts = FindCommentsOptions{
+		Type:     CommentTypeCommitMessage,
+		IssueID:  r.IssueID,
+		ReviewID: r.ID,
+	}
+
+	if _, err := sess.Where(opts.toConds()).Delete(new(Comment)); err != nil {
+		return err
+	}
+
 	opts = FindCommentsOptions{
 		Type:     CommentTypeReview,
 		IssueID:  r.IssueID,
@@ -0,0 +1,159 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ReviewEnvironment represents a preview of the changes of a pull request deployed by CI, a review environment
+// expires when its pull request is closed and the expired environments are deleted after a while
+type ReviewEnvironment struct {
+	ID          int64                 `xorm:"pk autoincr"`
+	RepoID      int64                 `xorm:"INDEX NOT NULL"`
+	PullID      int64                 `xorm:"UNIQUE(s) NOT NULL"`
+	Name        string                `xorm:"UNIQUE(s) NOT NULL"`
+	URL         string                `xorm:"TEXT NOT NULL"`
+	State       api.CommitStatusState `xorm:"VARCHAR(7) NOT NULL"`
+	Description string                `xorm:"TEXT"`
+	CreatorID   int64                 `xorm:"NOT NULL"`
+	Creator     *User                 `xorm:"-"`
+
+	IsExpired   bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	ExpiredUnix timeutil.TimeStamp `xorm:"INDEX"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// LoadCreator loads the user who registered the review environment
+func (env *ReviewEnvironment) LoadCreator() (err error) {
+	if env.Creator == nil {
+		env.Creator, err = GetUserByID(env.CreatorID)
+		if IsErrUserNotExist(err) {
+			env.Creator, err = NewGhostUser(), nil
+		}
+	}
+	return err
+}
+
+// ErrReviewEnvironmentNotExist represents a "ReviewEnvironmentNotExist" kind of error.
+type ErrReviewEnvironmentNotExist struct {
+	PullID int64
+	Name   string
+}
+
+// IsErrReviewEnvironmentNotExist checks if an error is a ErrReviewEnvironmentNotExist.
+func IsErrReviewEnvironmentNotExist(err error) bool {
+	_, ok := err.(ErrReviewEnvironmentNotExist)
+	return ok
+}
+
+func (err ErrReviewEnvironmentNotExist) Error() string {
+	return fmt.Sprintf("review environment does not exist [pull_id: %d, name: %s]", err.PullID, err.Name)
+}
+
+// UpsertReviewEnvironment registers a review environment of a pull request or updates the environment
+// of the pull request with the same name, an expired environment is registered again
+func UpsertReviewEnvironment(env *ReviewEnvironment) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	existing := new(ReviewEnvironment)
+	has, err := sess.Where("pull_id = ? AND name = ?", env.PullID, env.Name).Get(existing)
+	if err != nil {
+		return err
+	}
+	env.IsExpired = false
+	env.ExpiredUnix = 0
+	if has {
+		env.ID = existing.ID
+		env.CreatedUnix = existing.CreatedUnix
+		if _, err = sess.ID(env.ID).
+			Cols("url", "state", "description", "creator_id", "is_expired", "expired_unix").
+			Update(env); err != nil {
+			return err
+		}
+	} else if _, err = sess.Insert(env); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetReviewEnvironment returns the review environment of a pull request by its name
+func GetReviewEnvironment(pullID int64, name string) (*ReviewEnvironment, error) {
+	env := new(ReviewEnvironment)
+	has, err := x.Where("pull_id = ? AND name = ?", pullID, name).Get(env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReviewEnvironmentNotExist{PullID: pullID, Name: name}
+	}
+	return env, nil
+}
+
+// GetReviewEnvironments returns the review environments of a pull request by their names
+func GetReviewEnvironments(pullID int64, includeExpired bool) ([]*ReviewEnvironment, error) {
+	sess := x.Where("pull_id = ?", pullID)
+	if !includeExpired {
+		sess.And("is_expired = ?", false)
+	}
+	envs := make([]*ReviewEnvironment, 0, 2)
+	return envs, sess.Asc("name").Find(&envs)
+}
+
+// DeleteReviewEnvironment deletes a review environment
+func DeleteReviewEnvironment(env *ReviewEnvironment) error {
+	_, err := x.ID(env.ID).Delete(new(ReviewEnvironment))
+	return err
+}
+
+// ExpireReviewEnvironments expires the review environments of a pull request and returns the environments
+// which have expired
+func ExpireReviewEnvironments(pullID int64) ([]*ReviewEnvironment, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	envs := make([]*ReviewEnvironment, 0, 2)
+	if err := sess.Where("pull_id = ? AND is_expired = ?", pullID, false).Asc("name").Find(&envs); err != nil {
+		return nil, err
+	}
+	if len(envs) == 0 {
+		return envs, nil
+	}
+	now := timeutil.TimeStampNow()
+	for _, env := range envs {
+		env.IsExpired = true
+		env.ExpiredUnix = now
+	}
+	if _, err := sess.Where("pull_id = ? AND is_expired = ?", pullID, false).
+		Cols("is_expired", "expired_unix").
+		Update(&ReviewEnvironment{IsExpired: true, ExpiredUnix: now}); err != nil {
+		return nil, err
+	}
+	return envs, sess.Commit()
+}
+
+// DeleteExpiredReviewEnvironments deletes the review environments which have expired for longer than olderThan
+func DeleteExpiredReviewEnvironments(ctx context.Context, olderThan time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ErrCancelledf("before deleting the expired review environments")
+	default:
+	}
+	_, err := x.Where("is_expired = ? AND expired_unix < ?", true, time.Now().Add(-olderThan).Unix()).
+		Delete(new(ReviewEnvironment))
+	return err
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewEnvironments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	env, err := GetReviewEnvironment(2, "preview")
+	assert.NoError(t, err)
+	assert.Equal(t, api.CommitStatusSuccess, env.State)
+	assert.NoError(t, env.LoadCreator())
+	assert.EqualValues(t, 2, env.Creator.ID)
+	_, err = GetReviewEnvironment(2, "docs")
+	assert.True(t, IsErrReviewEnvironmentNotExist(err))
+
+	// the environment with the same name is updated
+	assert.NoError(t, UpsertReviewEnvironment(&ReviewEnvironment{RepoID: 1, PullID: 2, Name: "preview", URL: "https://preview.example.com/2", State: api.CommitStatusPending, CreatorID: 1}))
+	assert.NoError(t, UpsertReviewEnvironment(&ReviewEnvironment{RepoID: 1, PullID: 2, Name: "docs", URL: "https://docs.example.com/2", State: api.CommitStatusSuccess, CreatorID: 1}))
+	envs, err := GetReviewEnvironments(2, false)
+	assert.NoError(t, err)
+	if assert.Len(t, envs, 2) {
+		assert.Equal(t, "docs", envs[0].Name)
+		assert.Equal(t, "preview", envs[1].Name)
+		assert.Equal(t, "https://preview.example.com/2", envs[1].URL)
+		assert.Equal(t, api.CommitStatusPending, envs[1].State)
+		assert.EqualValues(t, 1, envs[1].ID)
+	}
+
+	// the environments expire once
+	expired, err := ExpireReviewEnvironments(2)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 2)
+	expired, err = ExpireReviewEnvironments(2)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	envs, err = GetReviewEnvironments(2, false)
+	assert.NoError(t, err)
+	assert.Empty(t, envs)
+	envs, err = GetReviewEnvironments(2, true)
+	assert.NoError(t, err)
+	assert.Len(t, envs, 2)
+
+	// an expired environment is registered again
+	assert.NoError(t, UpsertReviewEnvironment(&ReviewEnvironment{RepoID: 1, PullID: 2, Name: "docs", URL: "https://docs.example.com/2", State: api.CommitStatusSuccess, CreatorID: 1}))
+	envs, err = GetReviewEnvironments(2, false)
+	assert.NoError(t, err)
+	assert.Len(t, envs, 1)
+
+	// only the environments which expired long enough ago are deleted
+	assert.NoError(t, DeleteExpiredReviewEnvironments(context.Background(), time.Hour))
+	AssertExistsAndLoadBean(t, &ReviewEnvironment{PullID: 2, Name: "preview"})
+	_, err = x.Exec("UPDATE review_environment SET expired_unix = ? WHERE name = ?", time.Now().Add(-2*time.Hour).Unix(), "preview")
+	assert.NoError(t, err)
+	assert.NoError(t, DeleteExpiredReviewEnvironments(context.Background(), time.Hour))
+	AssertNotExistsBean(t, &ReviewEnvironment{PullID: 2, Name: "preview"})
+	AssertExistsAndLoadBean(t, &ReviewEnvironment{PullID: 2, Name: "docs"})
+
+	env, err = GetReviewEnvironment(2, "docs")
+	assert.NoError(t, err)
+	assert.NoError(t, DeleteReviewEnvironment(env))
+	AssertNotExistsBean(t, &ReviewEnvironment{PullID: 2, Name: "docs"})
+}
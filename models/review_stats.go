@@ -0,0 +1,197 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ReviewerStats represents the review activity of a reviewer in some repositories over a period
+type ReviewerStats struct {
+	ReviewerID int64
+	Reviewer   *User
+	// Reviews is the number of submitted reviews, i.e. the approvals, the rejections and the comments
+	Reviews    int64
+	Approvals  int64
+	Rejections int64
+	// Comments is the number of code comments of the submitted reviews
+	Comments int64
+	// Requests is the number of reviews requested from the reviewer
+	Requests int64
+	// RespondedRequests is the number of requests the reviewer submitted a review for in the period
+	RespondedRequests int64
+	// TotalTurnaround is the sum of the seconds from the responded requests until their reviews
+	TotalTurnaround int64
+}
+
+// ApprovalRatio returns the ratio of the approvals to the approvals and the rejections of a reviewer,
+// it's 0 if the reviewer neither approved nor rejected anything
+func (s *ReviewerStats) ApprovalRatio() float64 {
+	if s.Approvals+s.Rejections == 0 {
+		return 0
+	}
+	return float64(s.Approvals) / float64(s.Approvals+s.Rejections)
+}
+
+// ApprovalPercent returns the approval ratio of a reviewer as a rounded percentage
+func (s *ReviewerStats) ApprovalPercent() int {
+	return int(s.ApprovalRatio()*100 + 0.5)
+}
+
+// AverageTurnaround returns the average number of seconds from a review request until the review of
+// a reviewer, it's 0 if the reviewer didn't respond to any request
+func (s *ReviewerStats) AverageTurnaround() int64 {
+	if s.RespondedRequests == 0 {
+		return 0
+	}
+	return s.TotalTurnaround / s.RespondedRequests
+}
+
+// ReviewerStatsOptions represents the options of GetReviewerStats
+type ReviewerStatsOptions struct {
+	RepoIDs []int64
+	// Since and Before limit the period, they are ignored if they are 0
+	Since  timeutil.TimeStamp
+	Before timeutil.TimeStamp
+	// Doer can see the stats of the reviewers keeping them private if it's one of them or an admin
+	Doer *User
+}
+
+func (opts *ReviewerStatsOptions) periodCond(column string) builder.Cond {
+	cond := builder.NewCond()
+	if opts.Since > 0 {
+		cond = cond.And(builder.Gte{column: opts.Since})
+	}
+	if opts.Before > 0 {
+		cond = cond.And(builder.Lte{column: opts.Before})
+	}
+	return cond
+}
+
+var submittedReviewTypes = []interface{}{ReviewTypeApprove, ReviewTypeComment, ReviewTypeReject}
+
+// GetReviewerStats returns the review activity of the reviewers of some repositories over a period, the most
+// active reviewers first. The turnaround only counts the requests and the reviews of the period. The reviewers
+// keeping their review stats private are left out unless the doer is one of them or an admin.
+func GetReviewerStats(opts *ReviewerStatsOptions) ([]*ReviewerStats, error) {
+	if len(opts.RepoIDs) == 0 {
+		return []*ReviewerStats{}, nil
+	}
+
+	stats := make(map[int64]*ReviewerStats)
+	getStats := func(reviewerID int64) *ReviewerStats {
+		s, ok := stats[reviewerID]
+		if !ok {
+			s = &ReviewerStats{ReviewerID: reviewerID}
+			stats[reviewerID] = s
+		}
+		return s
+	}
+
+	reviews := make([]*Review, 0, 10)
+	if err := x.Join("INNER", "issue", "issue.id = review.issue_id").
+		Where(builder.In("issue.repo_id", opts.RepoIDs)).
+		And(builder.In("review.type", submittedReviewTypes...)).
+		And(builder.Gt{"review.reviewer_id": 0}).
+		And(opts.periodCond("review.created_unix")).
+		Asc("review.created_unix").
+		Find(&reviews); err != nil {
+		return nil, err
+	}
+	// reviewTimes are the times of the reviews of a reviewer on an issue in chronological order
+	type reviewKey struct{ issueID, reviewerID int64 }
+	reviewTimes := make(map[reviewKey][]timeutil.TimeStamp)
+	for _, review := range reviews {
+		s := getStats(review.ReviewerID)
+		s.Reviews++
+		switch review.Type {
+		case ReviewTypeApprove:
+			s.Approvals++
+		case ReviewTypeReject:
+			s.Rejections++
+		}
+		key := reviewKey{review.IssueID, review.ReviewerID}
+		reviewTimes[key] = append(reviewTimes[key], review.CreatedUnix)
+	}
+
+	commentCounts := make([]*struct {
+		ReviewerID int64
+		Count      int64
+	}, 0, 10)
+	if err := x.Table("comment").
+		Select("comment.poster_id AS reviewer_id, COUNT(*) AS count").
+		Join("INNER", "review", "review.id = comment.review_id").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where(builder.In("issue.repo_id", opts.RepoIDs)).
+		And(builder.Eq{"comment.type": CommentTypeCode}).
+		And(builder.In("review.type", submittedReviewTypes...)).
+		And(opts.periodCond("comment.created_unix")).
+		GroupBy("comment.poster_id").
+		Find(&commentCounts); err != nil {
+		return nil, err
+	}
+	for _, count := range commentCounts {
+		if count.ReviewerID > 0 {
+			getStats(count.ReviewerID).Comments = count.Count
+		}
+	}
+
+	requests := make([]*Comment, 0, 10)
+	if err := x.Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where(builder.In("issue.repo_id", opts.RepoIDs)).
+		And(builder.Eq{"comment.type": CommentTypeReviewRequest, "comment.removed_assignee": false}).
+		And(builder.Gt{"comment.assignee_id": 0}).
+		And(opts.periodCond("comment.created_unix")).
+		Asc("comment.created_unix").
+		Find(&requests); err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		s := getStats(request.AssigneeID)
+		s.Requests++
+		// the request is responded by the first review of the reviewer following it
+		times := reviewTimes[reviewKey{request.IssueID, request.AssigneeID}]
+		for _, reviewTime := range times {
+			if reviewTime >= request.CreatedUnix {
+				s.RespondedRequests++
+				s.TotalTurnaround += int64(reviewTime - request.CreatedUnix)
+				break
+			}
+		}
+	}
+
+	reviewerIDs := make([]int64, 0, len(stats))
+	for reviewerID := range stats {
+		reviewerIDs = append(reviewerIDs, reviewerID)
+	}
+	reviewers := make(map[int64]*User, len(reviewerIDs))
+	if err := x.In("id", reviewerIDs).Find(&reviewers); err != nil {
+		return nil, err
+	}
+
+	results := make([]*ReviewerStats, 0, len(stats))
+	for reviewerID, s := range stats {
+		reviewer, ok := reviewers[reviewerID]
+		if !ok {
+			continue
+		}
+		if reviewer.KeepReviewStatsPrivate && (opts.Doer == nil || (opts.Doer.ID != reviewer.ID && !opts.Doer.IsAdmin)) {
+			continue
+		}
+		s.Reviewer = reviewer
+		results = append(results, s)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Reviews != results[j].Reviews {
+			return results[i].Reviews > results[j].Reviews
+		}
+		return results[i].Reviewer.LowerName < results[j].Reviewer.LowerName
+	})
+	return results, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReviewerStats(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// the review of user2 on the issue 3 is requested 10 seconds before it
+	_, err := x.NoAutoTime().Insert(&Comment{
+		Type:        CommentTypeReviewRequest,
+		PosterID:    1,
+		IssueID:     3,
+		AssigneeID:  2,
+		CreatedUnix: 946684804,
+		UpdatedUnix: 946684804,
+	})
+	assert.NoError(t, err)
+
+	stats, err := GetReviewerStats(&ReviewerStatsOptions{RepoIDs: []int64{1}})
+	assert.NoError(t, err)
+	// the review of the deleted user100 isn't counted
+	if assert.Len(t, stats, 4) {
+		assert.EqualValues(t, 1, stats[0].ReviewerID)
+		assert.EqualValues(t, 2, stats[0].Reviews)
+		assert.EqualValues(t, 1, stats[0].Approvals)
+		assert.EqualValues(t, 1.0, stats[0].ApprovalRatio())
+		assert.EqualValues(t, 0, stats[0].AverageTurnaround())
+
+		assert.EqualValues(t, 2, stats[1].ReviewerID)
+		assert.EqualValues(t, 1, stats[1].Reviews)
+		assert.EqualValues(t, 1, stats[1].Rejections)
+		assert.EqualValues(t, 0, stats[1].ApprovalRatio())
+		assert.EqualValues(t, 1, stats[1].Requests)
+		assert.EqualValues(t, 1, stats[1].RespondedRequests)
+		assert.EqualValues(t, 10, stats[1].AverageTurnaround())
+
+		assert.EqualValues(t, 3, stats[2].ReviewerID)
+		assert.EqualValues(t, 4, stats[3].ReviewerID)
+	}
+
+	stats, err = GetReviewerStats(&ReviewerStatsOptions{RepoIDs: []int64{1}, Since: 946684812})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 3) {
+		assert.EqualValues(t, 2, stats[0].ReviewerID)
+		assert.EqualValues(t, 0, stats[0].Requests, "the request is counted only in its period")
+		assert.EqualValues(t, 0, stats[0].RespondedRequests)
+	}
+
+	stats, err = GetReviewerStats(&ReviewerStatsOptions{RepoIDs: []int64{2}})
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	// the stats of the reviewers keeping them private are only visible for them and the admins
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user2.KeepReviewStatsPrivate = true
+	assert.NoError(t, UpdateUserCols(user2, "keep_review_stats_private"))
+	for _, doer := range []*User{nil, AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)} {
+		stats, err = GetReviewerStats(&ReviewerStatsOptions{RepoIDs: []int64{1}, Doer: doer})
+		assert.NoError(t, err)
+		assert.Len(t, stats, 3)
+	}
+	for _, doer := range []*User{user2, AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)} {
+		stats, err = GetReviewerStats(&ReviewerStatsOptions{RepoIDs: []int64{1}, Doer: doer})
+		assert.NoError(t, err)
+		assert.Len(t, stats, 4)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/auth/saml"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SAMLSession represents a session of a user signed in with a SAML login source, it's ended by the
+// single logout of the identity provider
+type SAMLSession struct {
+	ID            int64 `xorm:"pk autoincr"`
+	LoginSourceID int64 `xorm:"INDEX NOT NULL"`
+	UserID        int64 `xorm:"INDEX NOT NULL"`
+	NameID        string
+	SessionIndex  string
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+}
+
+// GetActiveSAMLLoginSourceByName returns the active SAML login source by given name, nil if it doesn't exist
+func GetActiveSAMLLoginSourceByName(name string) (*LoginSource, error) {
+	source := new(LoginSource)
+	has, err := x.Where("name = ? and type = ? and is_actived = ?", name, LoginSAML, true).Get(source)
+	if !has || err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// CreateSAMLSession records the session of a user signed in with a SAML login source
+func CreateSAMLSession(sourceID, userID int64, claims *saml.Claims) (*SAMLSession, error) {
+	session := &SAMLSession{
+		LoginSourceID: sourceID,
+		UserID:        userID,
+		NameID:        claims.NameID,
+		SessionIndex:  claims.SessionIndex,
+	}
+	_, err := x.Insert(session)
+	return session, err
+}
+
+// GetSAMLSessionByID returns the SAML session by given ID
+func GetSAMLSessionByID(id int64) (*SAMLSession, error) {
+	session := new(SAMLSession)
+	has, err := x.ID(id).Get(session)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// IsSAMLSessionActive returns whether the SAML session has not been ended
+func IsSAMLSessionActive(id int64) (bool, error) {
+	return x.ID(id).Exist(new(SAMLSession))
+}
+
+// DeleteSAMLSession ends the SAML session by given ID
+func DeleteSAMLSession(id int64) error {
+	_, err := x.ID(id).Delete(new(SAMLSession))
+	return err
+}
+
+// DeleteSAMLSessionsByNameID ends the SAML sessions of the name ID with the login source, only the session
+// with the index when it is not empty, and returns the number of the ended sessions
+func DeleteSAMLSessionsByNameID(sourceID int64, nameID string, sessionIndexes []string) (int64, error) {
+	sess := x.Where("login_source_id = ? AND name_id = ?", sourceID, nameID)
+	if len(sessionIndexes) > 0 {
+		sess.In("session_index", sessionIndexes)
+	}
+	return sess.Delete(new(SAMLSession))
+}
+
+// LoginViaSAML returns the user of the claims of a SAML login source, the user is created if it doesn't
+// exist yet and the source creates the users automatically
+func LoginViaSAML(source *LoginSource, claims *saml.Claims) (*User, error) {
+	if !source.IsActived {
+		return nil, ErrLoginSourceNotActived
+	}
+	cfg := source.SAML()
+
+	user := &User{
+		LoginType:   LoginSAML,
+		LoginSource: source.ID,
+		LoginName:   claims.NameID,
+	}
+	has, err := x.Get(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if has {
+		if user.ProhibitLogin {
+			return nil, ErrUserProhibitLogin{user.ID, user.Name}
+		}
+		// change the admin flag only if the admin group is set
+		if len(cfg.AdminGroup) > 0 && user.IsAdmin != cfg.IsAdmin(claims) {
+			user.IsAdmin = cfg.IsAdmin(claims)
+			if err := UpdateUserCols(user, "is_admin"); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if !cfg.AutoCreateUsers {
+			return nil, ErrUserNotExist{0, claims.Username, 0}
+		}
+
+		email := claims.Email
+		if len(email) == 0 {
+			email = fmt.Sprintf("%s@localhost", claims.Username)
+		}
+		user = &User{
+			LowerName:   strings.ToLower(claims.Username),
+			Name:        claims.Username,
+			FullName:    claims.FullName,
+			Email:       email,
+			LoginType:   LoginSAML,
+			LoginSource: source.ID,
+			LoginName:   claims.NameID,
+			IsActive:    true,
+			IsAdmin:     cfg.IsAdmin(claims),
+		}
+		if err := CreateUser(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := SyncGroupTeams(user, cfg.GroupTeamMap, cfg.GroupTeamMapRemoval, claims.Groups); err != nil {
+		log.Error("SyncGroupTeams [user: %s, source: %s]: %v", user.Name, source.Name, err)
+	}
+	return user, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/auth/saml"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginViaSAML(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	source := &LoginSource{
+		Type:      LoginSAML,
+		Name:      "saml",
+		IsActived: true,
+		Cfg: &SAMLConfig{Source: &saml.Source{
+			AdminGroup:          "admins",
+			GroupTeamMap:        `{"developers": {"user3": ["team1"]}, "owners": {"user3": ["Owners"]}}`,
+			GroupTeamMapRemoval: true,
+		}},
+	}
+	assert.NoError(t, CreateLoginSource(source))
+	claims := &saml.Claims{
+		NameID:       "jdoe-id",
+		SessionIndex: "session-1",
+		Username:     "jdoe",
+		Email:        "jdoe@example.com",
+		FullName:     "John Doe",
+		Groups:       []string{"developers", "admins"},
+	}
+
+	// the users are not created automatically
+	_, err := LoginViaSAML(source, claims)
+	assert.True(t, IsErrUserNotExist(err))
+
+	source.SAML().AutoCreateUsers = true
+	assert.NoError(t, UpdateSource(source))
+	source, err = GetLoginSourceByID(source.ID)
+	assert.NoError(t, err)
+
+	user, err := LoginViaSAML(source, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", user.Name)
+	assert.Equal(t, "jdoe@example.com", user.Email)
+	assert.Equal(t, "John Doe", user.FullName)
+	assert.Equal(t, "jdoe-id", user.LoginName)
+	assert.Equal(t, LoginSAML, user.LoginType)
+	assert.True(t, user.IsAdmin)
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: user.ID})
+	AssertNotExistsBean(t, &TeamUser{TeamID: 1, UID: user.ID})
+
+	// the same user is matched by its name ID, its groups are synchronized
+	claims.Username = "renamed"
+	claims.Groups = []string{"owners"}
+	again, err := LoginViaSAML(source, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, again.ID)
+	assert.False(t, again.IsAdmin)
+	AssertNotExistsBean(t, &TeamUser{TeamID: 2, UID: user.ID})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 1, UID: user.ID})
+
+	source.IsActived = false
+	_, err = LoginViaSAML(source, claims)
+	assert.Equal(t, ErrLoginSourceNotActived, err)
+}
+
+func TestSAMLSessions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	first, err := CreateSAMLSession(1, 2, &saml.Claims{NameID: "user2-id", SessionIndex: "session-1"})
+	assert.NoError(t, err)
+	second, err := CreateSAMLSession(1, 2, &saml.Claims{NameID: "user2-id", SessionIndex: "session-2"})
+	assert.NoError(t, err)
+	other, err := CreateSAMLSession(2, 2, &saml.Claims{NameID: "user2-id"})
+	assert.NoError(t, err)
+
+	session, err := GetSAMLSessionByID(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", session.SessionIndex)
+
+	// only the session of the index is ended
+	count, err := DeleteSAMLSessionsByNameID(1, "user2-id", []string{"session-2"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	active, err := IsSAMLSessionActive(second.ID)
+	assert.NoError(t, err)
+	assert.False(t, active)
+	active, err = IsSAMLSessionActive(first.ID)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	// all the sessions of the name ID with the source are ended
+	count, err = DeleteSAMLSessionsByNameID(1, "user2-id", nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	active, err = IsSAMLSessionActive(other.ID)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	assert.NoError(t, DeleteSAMLSession(other.ID))
+	session, err = GetSAMLSessionByID(other.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// SavedReply represents a reusable reply of a user, the saved replies of an organization
+// are shared with its members
+type SavedReply struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL"`
+	Owner       *User              `xorm:"-"`
+	Title       string             `xorm:"NOT NULL"`
+	Content     string             `xorm:"TEXT NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// LoadOwner loads the user or the organization owning the saved reply
+func (r *SavedReply) LoadOwner() (err error) {
+	if r.Owner == nil {
+		r.Owner, err = GetUserByID(r.OwnerID)
+	}
+	return err
+}
+
+// CreateSavedReply creates a saved reply
+func CreateSavedReply(reply *SavedReply) error {
+	_, err := x.Insert(reply)
+	return err
+}
+
+// GetSavedReplyByID returns the saved reply of the owner of the given ID
+func GetSavedReplyByID(ownerID, id int64) (*SavedReply, error) {
+	reply := new(SavedReply)
+	has, err := x.
+		Where("id = ?", id).
+		And("owner_id = ?", ownerID).
+		Get(reply)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSavedReplyNotExist{ID: id}
+	}
+	return reply, nil
+}
+
+// GetSavedReplies returns the saved replies of the user or of the organization
+func GetSavedReplies(ownerID int64, listOptions ListOptions) ([]*SavedReply, error) {
+	sess := x.Where("owner_id = ?", ownerID).Asc("title")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	replies := make([]*SavedReply, 0, 10)
+	return replies, sess.Find(&replies)
+}
+
+// GetAvailableSavedReplies returns the saved replies which the user can insert, they are the
+// replies of the user and the replies shared by the organizations of the user
+func GetAvailableSavedReplies(userID int64) ([]*SavedReply, error) {
+	replies := make([]*SavedReply, 0, 10)
+	if err := x.
+		Where(builder.Eq{"owner_id": userID}.Or(
+			builder.In("owner_id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": userID})))).
+		Asc("title").
+		Find(&replies); err != nil {
+		return nil, err
+	}
+
+	owners := make(map[int64]*User)
+	for _, reply := range replies {
+		if owner, ok := owners[reply.OwnerID]; ok {
+			reply.Owner = owner
+			continue
+		}
+		if err := reply.LoadOwner(); err != nil {
+			return nil, err
+		}
+		owners[reply.OwnerID] = reply.Owner
+	}
+	return replies, nil
+}
+
+// UpdateSavedReply updates the title and the content of a saved reply
+func UpdateSavedReply(reply *SavedReply) error {
+	_, err := x.ID(reply.ID).Cols("title", "content").Update(reply)
+	return err
+}
+
+// DeleteSavedReply deletes a saved reply of the owner
+func DeleteSavedReply(ownerID, id int64) error {
+	affected, err := x.
+		Where("id = ?", id).
+		And("owner_id = ?", ownerID).
+		Delete(new(SavedReply))
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSavedReplyNotExist{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedReplies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	own := &SavedReply{OwnerID: 2, Title: "Thanks", Content: "Thanks for the report!"}
+	assert.NoError(t, CreateSavedReply(own))
+	// user2 is a member of the organization user3
+	shared := &SavedReply{OwnerID: 3, Title: "Duplicate", Content: "Duplicate of #1"}
+	assert.NoError(t, CreateSavedReply(shared))
+	other := &SavedReply{OwnerID: 4, Title: "Private", Content: "Not shared"}
+	assert.NoError(t, CreateSavedReply(other))
+
+	replies, err := GetSavedReplies(2, ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, replies, 1) {
+		assert.Equal(t, own.ID, replies[0].ID)
+	}
+
+	replies, err = GetAvailableSavedReplies(2)
+	assert.NoError(t, err)
+	if assert.Len(t, replies, 2) {
+		assert.Equal(t, shared.ID, replies[0].ID)
+		assert.EqualValues(t, 3, replies[0].Owner.ID)
+		assert.Equal(t, own.ID, replies[1].ID)
+	}
+
+	_, err = GetSavedReplyByID(2, other.ID)
+	assert.True(t, IsErrSavedReplyNotExist(err))
+
+	own.Content = "Thank you!"
+	assert.NoError(t, UpdateSavedReply(own))
+	reply, err := GetSavedReplyByID(2, own.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Thank you!", reply.Content)
+
+	assert.True(t, IsErrSavedReplyNotExist(DeleteSavedReply(2, other.ID)))
+	assert.NoError(t, DeleteSavedReply(2, own.ID))
+	AssertNotExistsBean(t, &SavedReply{ID: own.ID})
+}
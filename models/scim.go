@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"xorm.io/builder"
+)
+
+// FindSCIMUsersOptions are the options of the users provisioned through SCIM
+type FindSCIMUsersOptions struct {
+	// Name and Email filter the users by name or by primary email address, case insensitively
+	Name  string
+	Email string
+	// Start is the offset of the first user, Limit the maximum number of users
+	Start int
+	Limit int
+}
+
+func (opts *FindSCIMUsersOptions) toCond() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"`type`": UserTypeIndividual})
+	if len(opts.Name) > 0 {
+		cond = cond.And(builder.Eq{"lower_name": strings.ToLower(opts.Name)})
+	}
+	if len(opts.Email) > 0 {
+		cond = cond.And(builder.Expr("LOWER(email) = ?", strings.ToLower(opts.Email)))
+	}
+	return cond
+}
+
+// FindSCIMUsers returns the users matching the options, ordered by ID, and the number of the matching users
+func FindSCIMUsers(opts *FindSCIMUsersOptions) ([]*User, int64, error) {
+	count, err := x.Where(opts.toCond()).Count(new(User))
+	if err != nil {
+		return nil, 0, err
+	}
+	users := make([]*User, 0, opts.Limit)
+	if opts.Limit <= 0 {
+		return users, count, nil
+	}
+	return users, count, x.Where(opts.toCond()).Asc("id").Limit(opts.Limit, opts.Start).Find(&users)
+}
+
+// FindSCIMTeamsOptions are the options of the teams synced to the groups of the identity providers
+type FindSCIMTeamsOptions struct {
+	// OrgName and TeamName filter the teams by the name of their organization and their name, case insensitively
+	OrgName  string
+	TeamName string
+	// Start is the offset of the first team, Limit the maximum number of teams
+	Start int
+	Limit int
+}
+
+func (opts *FindSCIMTeamsOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.OrgName) > 0 {
+		cond = cond.And(builder.In("team.org_id",
+			builder.Select("id").From("`user`").Where(builder.Eq{"lower_name": strings.ToLower(opts.OrgName), "`type`": UserTypeOrganization})))
+	}
+	if len(opts.TeamName) > 0 {
+		cond = cond.And(builder.Eq{"team.lower_name": strings.ToLower(opts.TeamName)})
+	}
+	return cond
+}
+
+// FindSCIMTeams returns the teams matching the options, ordered by ID, and the number of the matching teams
+func FindSCIMTeams(opts *FindSCIMTeamsOptions) ([]*Team, int64, error) {
+	count, err := x.Where(opts.toCond()).Count(new(Team))
+	if err != nil {
+		return nil, 0, err
+	}
+	teams := make([]*Team, 0, opts.Limit)
+	if opts.Limit <= 0 {
+		return teams, count, nil
+	}
+	return teams, count, x.Where(opts.toCond()).Asc("team.id").Limit(opts.Limit, opts.Start).Find(&teams)
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSCIMUsers(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	users, count, err := FindSCIMUsers(&FindSCIMUsersOptions{Limit: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, CountUsers(), count)
+	if assert.Len(t, users, 2) {
+		assert.EqualValues(t, 1, users[0].ID)
+		assert.EqualValues(t, 2, users[1].ID)
+	}
+
+	users, count, err = FindSCIMUsers(&FindSCIMUsersOptions{Start: 1, Limit: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, CountUsers(), count)
+	if assert.Len(t, users, 1) {
+		assert.EqualValues(t, 2, users[0].ID)
+	}
+
+	users, count, err = FindSCIMUsers(&FindSCIMUsersOptions{Name: "User2", Limit: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, users, 1) {
+		assert.EqualValues(t, 2, users[0].ID)
+	}
+
+	users, count, err = FindSCIMUsers(&FindSCIMUsersOptions{Email: "USER2@example.com", Limit: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, users, 1)
+
+	// the organizations aren't users
+	_, count, err = FindSCIMUsers(&FindSCIMUsersOptions{Name: "user3", Limit: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	users, count, err = FindSCIMUsers(&FindSCIMUsersOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, CountUsers(), count)
+	assert.Empty(t, users)
+}
+
+func TestFindSCIMTeams(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	teams, count, err := FindSCIMTeams(&FindSCIMTeamsOptions{Limit: 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, GetCount(t, &Team{}), count)
+	assert.Len(t, teams, 3)
+
+	teams, count, err = FindSCIMTeams(&FindSCIMTeamsOptions{OrgName: "User3", TeamName: "Team1", Limit: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, teams, 1) {
+		assert.EqualValues(t, 2, teams[0].ID)
+	}
+
+	_, count, err = FindSCIMTeams(&FindSCIMTeamsOptions{OrgName: "user2", TeamName: "team1", Limit: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ShortLinkType represents the kind of permalink a short link resolves to
+type ShortLinkType int
+
+const (
+	// ShortLinkTypeFile resolves to lines of a file at a commit
+	ShortLinkTypeFile ShortLinkType = iota
+	// ShortLinkTypeDiff resolves to the diff of a commit, possibly to a hunk of a file
+	ShortLinkTypeDiff
+)
+
+// Name returns the name of a short link type
+func (t ShortLinkType) Name() string {
+	if t == ShortLinkTypeDiff {
+		return "diff"
+	}
+	return "file"
+}
+
+// shortLinkTokenLength is the length of the tokens of the short links
+const shortLinkTokenLength = 10
+
+// ShortLink represents a short link to a permalink of a repository. The repository is referenced by its ID
+// and the permalink by the commit and the blob it was created at, so the link stays valid when they are renamed.
+type ShortLink struct {
+	ID        int64         `xorm:"pk autoincr"`
+	Token     string        `xorm:"VARCHAR(20) UNIQUE NOT NULL"`
+	RepoID    int64         `xorm:"INDEX NOT NULL"`
+	Repo      *Repository   `xorm:"-"`
+	CreatorID int64         `xorm:"NOT NULL"`
+	Type      ShortLinkType `xorm:"NOT NULL DEFAULT 0"`
+	CommitID  string        `xorm:"VARCHAR(40) NOT NULL"`
+	TreePath  string        `xorm:"TEXT"`
+	// BlobID is the blob of the file at the commit, it locates the file if the commit doesn't exist anymore
+	BlobID      string             `xorm:"VARCHAR(40)"`
+	LineStart   int                `xorm:"NOT NULL DEFAULT 0"`
+	LineEnd     int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ErrShortLinkNotExist represents a "ShortLinkNotExist" kind of error.
+type ErrShortLinkNotExist struct {
+	Token string
+}
+
+// IsErrShortLinkNotExist checks if an error is a ErrShortLinkNotExist.
+func IsErrShortLinkNotExist(err error) bool {
+	_, ok := err.(ErrShortLinkNotExist)
+	return ok
+}
+
+func (err ErrShortLinkNotExist) Error() string {
+	return fmt.Sprintf("short link does not exist [token: %s]", err.Token)
+}
+
+// LoadRepo loads the repository of a short link
+func (link *ShortLink) LoadRepo() (err error) {
+	if link.Repo == nil {
+		link.Repo, err = GetRepositoryByID(link.RepoID)
+	}
+	return err
+}
+
+// CreateShortLink creates a short link with a new random token
+func CreateShortLink(link *ShortLink) error {
+	for {
+		token, err := generate.GetRandomString(shortLinkTokenLength)
+		if err != nil {
+			return err
+		}
+		has, err := x.Exist(&ShortLink{Token: token})
+		if err != nil {
+			return err
+		} else if !has {
+			link.Token = token
+			break
+		}
+	}
+	_, err := x.Insert(link)
+	return err
+}
+
+// GetShortLinkByToken returns a short link by its token
+func GetShortLinkByToken(token string) (*ShortLink, error) {
+	if len(token) == 0 {
+		return nil, ErrShortLinkNotExist{Token: token}
+	}
+	link := &ShortLink{Token: token}
+	has, err := x.Get(link)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrShortLinkNotExist{Token: token}
+	}
+	return link, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortLink(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	link, err := GetShortLinkByToken("aBcDeFgHiJ")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, link.RepoID)
+	assert.Equal(t, "README.md", link.TreePath)
+	assert.NoError(t, link.LoadRepo())
+	assert.Equal(t, "repo1", link.Repo.Name)
+
+	_, err = GetShortLinkByToken("missing")
+	assert.True(t, IsErrShortLinkNotExist(err))
+	_, err = GetShortLinkByToken("")
+	assert.True(t, IsErrShortLinkNotExist(err))
+
+	created := &ShortLink{RepoID: 1, CreatorID: 2, Type: ShortLinkTypeDiff, CommitID: link.CommitID}
+	assert.NoError(t, CreateShortLink(created))
+	assert.Len(t, created.Token, shortLinkTokenLength)
+	AssertExistsAndLoadBean(t, &ShortLink{Token: created.Token, Type: ShortLinkTypeDiff})
+}
@@ -71,14 +71,18 @@ type PublicKey struct {
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	LastUsedIP        string             `xorm:"VARCHAR(50)"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
+	IsStale           bool               `xorm:"-"`
 }
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (key *PublicKey) AfterLoad() {
-	key.HasUsed = key.UpdatedUnix > key.CreatedUnix
-	key.HasRecentActivity = key.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.HasUsed = key.LastUsedUnix > 0
+	key.HasRecentActivity = key.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.IsStale = isStaleCredential(key.CreatedUnix, key.LastUsedUnix)
 }
 
 // OmitEmail returns content of public key without email address.
@@ -632,8 +636,8 @@ func ListPublicLdapSSHKeys(uid int64, loginSourceID int64) ([]*PublicKey, error)
 		Find(&keys)
 }
 
-// UpdatePublicKeyUpdated updates public key use time.
-func UpdatePublicKeyUpdated(id int64) error {
+// UpdatePublicKeyUpdated updates public key use time and the address it was used from.
+func UpdatePublicKeyUpdated(id int64, ip string) error {
 	// Check if key exists before update as affected rows count is unreliable
 	//    and will return 0 affected rows if two updates are made at the same time
 	if cnt, err := x.ID(id).Count(&PublicKey{}); err != nil {
@@ -642,8 +646,11 @@ func UpdatePublicKeyUpdated(id int64) error {
 		return ErrKeyNotExist{id}
 	}
 
-	_, err := x.ID(id).Cols("updated_unix").Update(&PublicKey{
-		UpdatedUnix: timeutil.TimeStampNow(),
+	now := timeutil.TimeStampNow()
+	_, err := x.ID(id).Cols("updated_unix", "last_used_unix", "last_used_ip").Update(&PublicKey{
+		UpdatedUnix:  now,
+		LastUsedUnix: now,
+		LastUsedIP:   ip,
 	})
 	if err != nil {
 		return err
@@ -820,14 +827,18 @@ type DeployKey struct {
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	LastUsedIP        string             `xorm:"VARCHAR(50)"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
+	IsStale           bool               `xorm:"-"`
 }
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (key *DeployKey) AfterLoad() {
-	key.HasUsed = key.UpdatedUnix > key.CreatedUnix
-	key.HasRecentActivity = key.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.HasUsed = key.LastUsedUnix > 0
+	key.HasRecentActivity = key.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.IsStale = isStaleCredential(key.CreatedUnix, key.LastUsedUnix)
 }
 
 // GetContent gets associated public key content.
@@ -0,0 +1,233 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshSignatureBegin     = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureEnd       = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic     = "SSHSIG"
+	sshSignatureNamespace = "git"
+)
+
+// sshSignature represents the blob of a signature made by ssh-keygen -Y sign
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData represents the data signed by ssh-keygen -Y sign
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// isSSHSignature checks if the signature of a commit is an armored SSH signature
+func isSSHSignature(signature string) bool {
+	return strings.HasPrefix(strings.TrimSpace(signature), sshSignatureBegin)
+}
+
+// extractSSHSignature parses an armored SSH signature
+func extractSSHSignature(signature string) (*sshSignature, ssh.PublicKey, error) {
+	armored := strings.TrimSpace(signature)
+	if !strings.HasPrefix(armored, sshSignatureBegin) || !strings.HasSuffix(armored, sshSignatureEnd) {
+		return nil, nil, fmt.Errorf("not an armored SSH signature")
+	}
+	armored = strings.TrimSuffix(strings.TrimPrefix(armored, sshSignatureBegin), sshSignatureEnd)
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(armored), ""))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.HasPrefix(blob, []byte(sshSignatureMagic)) {
+		return nil, nil, fmt.Errorf("invalid SSH signature magic")
+	}
+
+	sig := new(sshSignature)
+	if err := ssh.Unmarshal(blob[len(sshSignatureMagic):], sig); err != nil {
+		return nil, nil, err
+	}
+	if sig.Version != 1 {
+		return nil, nil, fmt.Errorf("unsupported SSH signature version: %d", sig.Version)
+	}
+	if sig.Namespace != sshSignatureNamespace {
+		return nil, nil, fmt.Errorf("unexpected SSH signature namespace: %s", sig.Namespace)
+	}
+	pub, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sig, pub, nil
+}
+
+// verifySSHSignature checks the signature of the payload against the public key of the signature
+func verifySSHSignature(sig *sshSignature, pub ssh.PublicKey, payload string) error {
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash algorithm: %s", sig.HashAlgorithm)
+	}
+	_, _ = h.Write([]byte(payload))
+
+	signature := new(ssh.Signature)
+	if err := ssh.Unmarshal(sig.Signature, signature); err != nil {
+		return err
+	}
+	signed := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	return pub.Verify(signed, signature)
+}
+
+// parseCommitWithSSHSignature checks if an SSH signature of a commit is good against the SSH keys of the users
+// and the default signing keys
+func parseCommitWithSSHSignature(c *git.Commit, committer *User) *CommitVerification {
+	sig, pub, err := extractSSHSignature(c.Signature.Signature)
+	if err != nil {
+		log.Error("SSH signature read err: %v", err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Reason:         "gpg.error.extract_sign",
+		}
+	}
+	fingerprint := ssh.FingerprintSHA256(pub)
+	if err := verifySSHSignature(sig, pub, c.Signature.Payload); err != nil {
+		log.Debug("SSH signature of %s doesn't match its key %s: %v", c.ID, fingerprint, err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Warning:        true,
+			Reason:         BadSignature,
+			SigningSSHKey:  &PublicKey{Fingerprint: fingerprint},
+		}
+	}
+
+	// The signature is good, now find whose key made it
+	keys, err := SearchPublicKey(0, fingerprint)
+	if err != nil {
+		log.Error("SearchPublicKey: %v", err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Reason:         "gpg.error.failed_retrieval_gpg_keys",
+		}
+	}
+	for _, key := range keys {
+		if key.Type != KeyTypeUser {
+			continue
+		}
+		signer, err := GetUserByID(key.OwnerID)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			log.Error("Failed to GetUserByID: %d for key ID: %d (%s) %v", key.OwnerID, key.ID, key.Fingerprint, err)
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       false,
+				Reason:         "gpg.error.no_committer_account",
+			}
+		}
+		email := signer.Email
+		if committer.ID == signer.ID {
+			email = c.Committer.Email
+		}
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       true,
+			Reason:         fmt.Sprintf("%s / %s", signer.Name, key.Fingerprint),
+			SigningUser:    signer,
+			SigningSSHKey:  key,
+			SigningEmail:   email,
+		}
+	}
+
+	if setting.Repository.Signing.Format == git.SigningKeyFormatSSH &&
+		setting.Repository.Signing.SigningKey != "none" &&
+		setting.Repository.Signing.SigningKey != "" && setting.Repository.Signing.SigningKey != "default" {
+		gpgSettings := &git.GPGSettings{
+			Sign:   true,
+			KeyID:  setting.Repository.Signing.SigningKey,
+			Format: git.SigningKeyFormatSSH,
+			Name:   setting.Repository.Signing.SigningName,
+			Email:  setting.Repository.Signing.SigningEmail,
+		}
+		if err := gpgSettings.LoadPublicKeyContent(); err != nil {
+			log.Error("Error getting default signing key: %s %v", gpgSettings.KeyID, err)
+		} else if commitVerification := verifyWithSSHSettings(gpgSettings, committer, fingerprint); commitVerification != nil {
+			return commitVerification
+		}
+	}
+
+	defaultGPGSettings, err := c.GetRepositoryDefaultPublicGPGKey(false)
+	if err != nil {
+		log.Error("Error getting default public gpg key: %v", err)
+	} else if defaultGPGSettings != nil && defaultGPGSettings.Sign && defaultGPGSettings.Format == git.SigningKeyFormatSSH {
+		if commitVerification := verifyWithSSHSettings(defaultGPGSettings, committer, fingerprint); commitVerification != nil {
+			return commitVerification
+		}
+	}
+
+	return &CommitVerification{
+		CommittingUser: committer,
+		Verified:       false,
+		Reason:         NoKeyFound,
+		SigningSSHKey:  &PublicKey{Fingerprint: fingerprint},
+	}
+}
+
+// verifyWithSSHSettings checks if the SSH signing key of the settings made a signature by its fingerprint
+func verifyWithSSHSettings(gpgSettings *git.GPGSettings, committer *User, fingerprint string) *CommitVerification {
+	keyFingerprint, err := calcFingerprintNative(gpgSettings.PublicKeyContent)
+	if err != nil {
+		log.Error("Unable to calculate the fingerprint of the SSH signing key %s: %v", gpgSettings.KeyID, err)
+		return nil
+	}
+	if keyFingerprint != fingerprint {
+		return nil
+	}
+	return &CommitVerification{
+		CommittingUser: committer,
+		Verified:       true,
+		Reason:         fmt.Sprintf("%s / %s", gpgSettings.Name, fingerprint),
+		SigningUser: &User{
+			Name:  gpgSettings.Name,
+			Email: gpgSettings.Email,
+		},
+		SigningSSHKey: &PublicKey{
+			Fingerprint: fingerprint,
+			Content:     gpgSettings.PublicKeyContent,
+		},
+		SigningEmail: gpgSettings.Email,
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// signSSH signs the payload like ssh-keygen -Y sign -n git
+func signSSH(t *testing.T, signer ssh.Signer, payload string) string {
+	hash := sha512.Sum512([]byte(payload))
+	signature, err := signer.Sign(rand.Reader, append([]byte(sshSignatureMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          hash[:],
+	})...))
+	assert.NoError(t, err)
+
+	blob := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignature{
+		Version:       1,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(signature),
+	})...)
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	lines := []string{sshSignatureBegin}
+	for len(encoded) > 70 {
+		lines = append(lines, encoded[:70])
+		encoded = encoded[70:]
+	}
+	lines = append(lines, encoded, sshSignatureEnd)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestSSHSignature(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	assert.NoError(t, err)
+
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nsigned commit\n"
+	armored := signSSH(t, signer, payload)
+	assert.True(t, isSSHSignature(armored))
+	assert.False(t, isSSHSignature("-----BEGIN PGP SIGNATURE-----\n"))
+
+	sig, pub, err := extractSSHSignature(armored)
+	assert.NoError(t, err)
+	assert.Equal(t, ssh.FingerprintSHA256(signer.PublicKey()), ssh.FingerprintSHA256(pub))
+	assert.NoError(t, verifySSHSignature(sig, pub, payload))
+	assert.Error(t, verifySSHSignature(sig, pub, payload+"tampered"))
+
+	_, _, err = extractSSHSignature(sshSignatureBegin + "\nbm90IGEgc2lnbmF0dXJl\n" + sshSignatureEnd)
+	assert.Error(t, err)
+}
+
+func TestParseCommitWithSSHSignature(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	assert.NoError(t, err)
+	fingerprint := ssh.FingerprintSHA256(signer.PublicKey())
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nsigned commit\n"
+	commit := &git.Commit{
+		Committer: &git.Signature{Name: user.Name, Email: user.Email},
+		Signature: &git.CommitGPGSignature{
+			Signature: signSSH(t, signer, payload),
+			Payload:   payload,
+		},
+	}
+
+	// the key isn't known yet
+	verification := ParseCommitWithSignature(commit)
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+	assert.Equal(t, fingerprint, verification.SigningSSHKey.Fingerprint)
+
+	_, err = x.Insert(&PublicKey{
+		OwnerID:     user.ID,
+		Name:        "signing",
+		Fingerprint: fingerprint,
+		Content:     strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))),
+		Mode:        AccessModeWrite,
+		Type:        KeyTypeUser,
+	})
+	assert.NoError(t, err)
+
+	verification = ParseCommitWithSignature(commit)
+	assert.True(t, verification.Verified)
+	assert.EqualValues(t, user.ID, verification.SigningUser.ID)
+	assert.Equal(t, user.Email, verification.SigningEmail)
+	assert.Equal(t, fingerprint, verification.SigningSSHKey.Fingerprint)
+
+	// a tampered commit has a bad signature
+	commit.Signature.Payload += "tampered"
+	verification = ParseCommitWithSignature(commit)
+	assert.False(t, verification.Verified)
+	assert.True(t, verification.Warning)
+	assert.Equal(t, BadSignature, verification.Reason)
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// DefaultStaleLabel is the name of the label of the stale issues if a policy doesn't name one
+const DefaultStaleLabel = "stale"
+
+// StalePolicy represents the policy of a repository to mark the inactive issues and pull
+// requests as stale and to close them if they stay inactive
+type StalePolicy struct {
+	ID             int64       `xorm:"pk autoincr"`
+	RepoID         int64       `xorm:"UNIQUE NOT NULL"`
+	Repo           *Repository `xorm:"-"`
+	Enabled        bool        `xorm:"INDEX NOT NULL DEFAULT false"`
+	IncludeIssues  bool        `xorm:"NOT NULL DEFAULT true"`
+	IncludePulls   bool        `xorm:"NOT NULL DEFAULT false"`
+	DaysUntilStale int         `xorm:"NOT NULL DEFAULT 60"`
+	// DaysUntilClose is the number of days a stale issue is closed after, it is never closed if it is 0
+	DaysUntilClose int    `xorm:"NOT NULL DEFAULT 7"`
+	StaleLabel     string `xorm:"NOT NULL"`
+	// ExemptLabels are the comma separated names of the labels of the issues which are never stale
+	ExemptLabels   string `xorm:"TEXT"`
+	WarningComment string `xorm:"TEXT"`
+	CloseComment   string `xorm:"TEXT"`
+	// DoerID is the user which last changed the policy, the actions are taken on its behalf
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// LoadRepo loads the repository of the policy
+func (p *StalePolicy) LoadRepo() (err error) {
+	if p.Repo == nil {
+		p.Repo, err = GetRepositoryByID(p.RepoID)
+	}
+	return err
+}
+
+// StaleLabelName returns the name of the label of the stale issues
+func (p *StalePolicy) StaleLabelName() string {
+	if len(p.StaleLabel) == 0 {
+		return DefaultStaleLabel
+	}
+	return p.StaleLabel
+}
+
+// ExemptLabelNames returns the names of the labels of the issues which are never stale
+func (p *StalePolicy) ExemptLabelNames() []string {
+	names := make([]string, 0, 2)
+	for _, name := range strings.Split(p.ExemptLabels, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetStalePolicy returns the stale policy of the repository
+func GetStalePolicy(repoID int64) (*StalePolicy, error) {
+	policy := new(StalePolicy)
+	has, err := x.Where("repo_id = ?", repoID).Get(policy)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrStalePolicyNotExist{RepoID: repoID}
+	}
+	return policy, nil
+}
+
+// SaveStalePolicy creates the stale policy of its repository or replaces the existing one
+func SaveStalePolicy(policy *StalePolicy) error {
+	existing, err := GetStalePolicy(policy.RepoID)
+	if err != nil {
+		if !IsErrStalePolicyNotExist(err) {
+			return err
+		}
+		_, err = x.Insert(policy)
+		return err
+	}
+	policy.ID = existing.ID
+	_, err = x.ID(policy.ID).AllCols().Omit("created_unix").Update(policy)
+	return err
+}
+
+// DeleteStalePolicy deletes the stale policy of the repository
+func DeleteStalePolicy(repoID int64) error {
+	affected, err := x.Where("repo_id = ?", repoID).Delete(new(StalePolicy))
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrStalePolicyNotExist{RepoID: repoID}
+	}
+	return nil
+}
+
+// GetEnabledStalePolicies returns the enabled stale policies of all the repositories
+func GetEnabledStalePolicies() ([]*StalePolicy, error) {
+	policies := make([]*StalePolicy, 0, 10)
+	return policies, x.Where("enabled = ?", true).Asc("repo_id").Find(&policies)
+}
+
+// issuesCond returns the condition of the open issues and pull requests of the repository
+// which the policy applies to
+func (p *StalePolicy) issuesCond() builder.Cond {
+	cond := builder.Eq{"repo_id": p.RepoID, "is_closed": false}
+	switch {
+	case p.IncludeIssues && p.IncludePulls:
+		return cond
+	case p.IncludeIssues:
+		return cond.And(builder.Eq{"is_pull": false})
+	case p.IncludePulls:
+		return cond.And(builder.Eq{"is_pull": true})
+	default:
+		return builder.Expr("1 = 0")
+	}
+}
+
+// FindIssuesToMark returns the issues which have been inactive since the number of days of the
+// policy and which have neither the stale label nor an exempt label
+func (p *StalePolicy) FindIssuesToMark(staleLabelID int64, exemptLabelIDs []int64, now timeutil.TimeStamp) ([]*Issue, error) {
+	labelIDs := append([]int64{staleLabelID}, exemptLabelIDs...)
+	issues := make([]*Issue, 0, 10)
+	return issues, x.
+		Where(p.issuesCond()).
+		And("updated_unix < ?", now.Add(-int64(p.DaysUntilStale)*86400)).
+		NotIn("id", builder.Select("issue_id").From("issue_label").Where(builder.In("label_id", labelIDs))).
+		Asc("id").
+		Find(&issues)
+}
+
+// FindMarkedIssues returns the open issues which have the stale label
+func (p *StalePolicy) FindMarkedIssues(staleLabelID int64) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 10)
+	return issues, x.
+		Where(p.issuesCond()).
+		In("id", builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": staleLabelID})).
+		Asc("id").
+		Find(&issues)
+}
+
+// StaleAction represents an action taken on an issue by a stale policy
+type StaleAction int
+
+// The actions taken on the issues by the stale policies
+const (
+	StaleActionMark StaleAction = iota + 1
+	StaleActionUnmark
+	StaleActionClose
+)
+
+var staleActionNames = map[StaleAction]string{
+	StaleActionMark:   "mark",
+	StaleActionUnmark: "unmark",
+	StaleActionClose:  "close",
+}
+
+func (a StaleAction) String() string {
+	return staleActionNames[a]
+}
+
+// StaleActionLog represents the audit record of an action taken on an issue by a stale policy
+type StaleActionLog struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	IssueID     int64              `xorm:"INDEX NOT NULL"`
+	Issue       *Issue             `xorm:"-"`
+	Action      StaleAction        `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+// CreateStaleActionLog records an action taken on the issue at the given time
+func CreateStaleActionLog(issue *Issue, action StaleAction, createdUnix timeutil.TimeStamp) error {
+	_, err := x.Insert(&StaleActionLog{
+		RepoID:      issue.RepoID,
+		IssueID:     issue.ID,
+		Action:      action,
+		CreatedUnix: createdUnix,
+	})
+	return err
+}
+
+// GetLastStaleMark returns the last record of the issue being marked as stale, it returns nil
+// if the issue has never been marked
+func GetLastStaleMark(issueID int64) (*StaleActionLog, error) {
+	log := new(StaleActionLog)
+	has, err := x.
+		Where("issue_id = ?", issueID).
+		And("action = ?", StaleActionMark).
+		Desc("created_unix", "id").
+		Get(log)
+	if err != nil || !has {
+		return nil, err
+	}
+	return log, nil
+}
+
+// GetStaleActionLogs returns the latest actions taken on the issues of the repository and their number
+func GetStaleActionLogs(repoID int64, listOptions ListOptions) ([]*StaleActionLog, int64, error) {
+	sess := x.Where("repo_id = ?", repoID).Desc("created_unix", "id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	logs := make([]*StaleActionLog, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&logs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	issueIDs := make([]int64, 0, len(logs))
+	for _, log := range logs {
+		issueIDs = append(issueIDs, log.IssueID)
+	}
+	issues, err := getIssuesByIDs(x, issueIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	issuesMap := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issuesMap[issue.ID] = issue
+	}
+	for _, log := range logs {
+		log.Issue = issuesMap[log.IssueID]
+	}
+	return logs, count, nil
+}
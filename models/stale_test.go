@@ -0,0 +1,117 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func issueIDs(issues []*Issue) []int64 {
+	ids := make([]int64, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+func TestStalePolicy(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := GetStalePolicy(1)
+	assert.True(t, IsErrStalePolicyNotExist(err))
+
+	policy := &StalePolicy{
+		RepoID:         1,
+		Enabled:        true,
+		IncludeIssues:  true,
+		DaysUntilStale: 30,
+		ExemptLabels:   " label1, ,label2",
+		DoerID:         2,
+	}
+	assert.NoError(t, SaveStalePolicy(policy))
+	assert.Equal(t, DefaultStaleLabel, policy.StaleLabelName())
+	assert.Equal(t, []string{"label1", "label2"}, policy.ExemptLabelNames())
+
+	policy = &StalePolicy{RepoID: 1, IncludeIssues: true, IncludePulls: true, DaysUntilStale: 60, StaleLabel: "inactive", DoerID: 2}
+	assert.NoError(t, SaveStalePolicy(policy))
+	saved, err := GetStalePolicy(1)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.ID, saved.ID)
+	assert.Equal(t, 60, saved.DaysUntilStale)
+	assert.Equal(t, "inactive", saved.StaleLabelName())
+
+	policies, err := GetEnabledStalePolicies()
+	assert.NoError(t, err)
+	assert.Len(t, policies, 0)
+
+	assert.NoError(t, DeleteStalePolicy(1))
+	assert.True(t, IsErrStalePolicyNotExist(DeleteStalePolicy(1)))
+}
+
+func TestStalePolicy_FindIssues(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	now := timeutil.TimeStamp(1600000000)
+	policy := &StalePolicy{RepoID: 1, IncludeIssues: true, IncludePulls: true, DaysUntilStale: 30}
+
+	// the issue 5 is closed and the label 2 is the stale label
+	issues, err := policy.FindIssuesToMark(2, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 11}, issueIDs(issues))
+
+	issues, err = policy.FindIssuesToMark(2, []int64{1}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 11}, issueIDs(issues))
+
+	policy.DaysUntilStale = 365
+	issues, err = policy.FindIssuesToMark(2, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, issueIDs(issues))
+
+	policy.IncludePulls = false
+	issues, err = policy.FindIssuesToMark(2, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, issueIDs(issues))
+
+	issues, err = policy.FindMarkedIssues(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, issueIDs(issues))
+
+	policy.IncludeIssues = false
+	issues, err = policy.FindMarkedIssues(1)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+}
+
+func TestStaleActionLogs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	mark, err := GetLastStaleMark(issue.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, mark)
+
+	assert.NoError(t, CreateStaleActionLog(issue, StaleActionMark, 100))
+	assert.NoError(t, CreateStaleActionLog(issue, StaleActionUnmark, 200))
+	assert.NoError(t, CreateStaleActionLog(issue, StaleActionMark, 300))
+
+	mark, err = GetLastStaleMark(issue.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 300, mark.CreatedUnix)
+
+	logs, count, err := GetStaleActionLogs(1, ListOptions{Page: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, logs, 2) {
+		assert.Equal(t, StaleActionMark, logs[0].Action)
+		assert.Equal(t, StaleActionUnmark, logs[1].Action)
+		assert.Equal(t, "unmark", logs[1].Action.String())
+		assert.Equal(t, issue.ID, logs[1].Issue.ID)
+	}
+}
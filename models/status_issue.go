@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// StatusIssueRule represents the rule of a repository opening a tracking issue when a commit status of
+// the head of its default branch fails, and closing it when a status of the same context succeeds again
+type StatusIssueRule struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+	Enabled bool  `xorm:"NOT NULL DEFAULT false"`
+	// ContextPattern is the glob pattern of the contexts of the statuses, like ci/*, all the contexts match an empty pattern
+	ContextPattern string `xorm:"NOT NULL"`
+	// LabelName is the name of the label added to the tracking issues, none is added if it's empty
+	LabelName string `xorm:"NOT NULL"`
+	// DoerID is the user which last changed the rule, the issues are opened on its behalf
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// MatchContext returns whether the rule tracks the failures of the statuses of a context
+func (rule *StatusIssueRule) MatchContext(context string) bool {
+	pattern := strings.TrimSpace(rule.ContextPattern)
+	if len(pattern) == 0 {
+		return true
+	}
+	g, err := glob.Compile(pattern)
+	if err != nil {
+		log.Info("Invalid context pattern '%s' of the status issue rule of repository %d: %v", pattern, rule.RepoID, err)
+		return false
+	}
+	return g.Match(context)
+}
+
+// GetStatusIssueRule returns the status issue rule of the repository
+func GetStatusIssueRule(repoID int64) (*StatusIssueRule, error) {
+	rule := new(StatusIssueRule)
+	has, err := x.Where("repo_id = ?", repoID).Get(rule)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrStatusIssueRuleNotExist{RepoID: repoID}
+	}
+	return rule, nil
+}
+
+// SaveStatusIssueRule creates the status issue rule of its repository or replaces the existing one
+func SaveStatusIssueRule(rule *StatusIssueRule) error {
+	existing, err := GetStatusIssueRule(rule.RepoID)
+	if err != nil {
+		if !IsErrStatusIssueRuleNotExist(err) {
+			return err
+		}
+		_, err = x.Insert(rule)
+		return err
+	}
+	rule.ID = existing.ID
+	_, err = x.ID(rule.ID).AllCols().Omit("created_unix").Update(rule)
+	return err
+}
+
+// StatusIssue links a context of the commit statuses of a repository to the latest issue tracking its failures
+type StatusIssue struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"UNIQUE(s) NOT NULL"`
+	ContextHash string `xorm:"CHAR(40) UNIQUE(s) NOT NULL"`
+	Context     string `xorm:"TEXT"`
+	IssueID     int64  `xorm:"NOT NULL"`
+}
+
+// GetStatusIssue returns the latest issue tracking the failures of the statuses of a context of the
+// repository, it's nil if there is none
+func GetStatusIssue(repoID int64, context string) (*Issue, error) {
+	link := new(StatusIssue)
+	has, err := x.Where("repo_id = ? AND context_hash = ?", repoID, hashCommitStatusContext(context)).Get(link)
+	if err != nil || !has {
+		return nil, err
+	}
+	issue, err := GetIssueByID(link.IssueID)
+	if IsErrIssueNotExist(err) {
+		return nil, nil
+	}
+	return issue, err
+}
+
+// SetStatusIssue sets the issue tracking the failures of the statuses of a context of the repository
+func SetStatusIssue(repoID int64, context string, issueID int64) error {
+	link := &StatusIssue{
+		RepoID:      repoID,
+		ContextHash: hashCommitStatusContext(context),
+		Context:     context,
+		IssueID:     issueID,
+	}
+	n, err := x.Where("repo_id = ? AND context_hash = ?", link.RepoID, link.ContextHash).Cols("issue_id").Update(link)
+	if err != nil || n > 0 {
+		return err
+	}
+	_, err = x.Insert(link)
+	return err
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusIssueRule(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := GetStatusIssueRule(1)
+	assert.True(t, IsErrStatusIssueRuleNotExist(err))
+
+	rule := &StatusIssueRule{RepoID: 1, Enabled: true, ContextPattern: "ci/*", DoerID: 2}
+	assert.NoError(t, SaveStatusIssueRule(rule))
+	assert.True(t, rule.MatchContext("ci/build"))
+	assert.False(t, rule.MatchContext("lint"))
+
+	assert.NoError(t, SaveStatusIssueRule(&StatusIssueRule{RepoID: 1, LabelName: "ci", DoerID: 1}))
+	rule, err = GetStatusIssueRule(1)
+	assert.NoError(t, err)
+	assert.False(t, rule.Enabled)
+	assert.Equal(t, "ci", rule.LabelName)
+	assert.EqualValues(t, 1, rule.DoerID)
+	assert.True(t, rule.MatchContext("lint"))
+}
+
+func TestStatusIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue, err := GetStatusIssue(1, "ci/build")
+	assert.NoError(t, err)
+	assert.Nil(t, issue)
+
+	assert.NoError(t, SetStatusIssue(1, "ci/build", 1))
+	assert.NoError(t, SetStatusIssue(1, "ci/lint", 2))
+	issue, err = GetStatusIssue(1, "ci/build")
+	assert.NoError(t, err)
+	if assert.NotNil(t, issue) {
+		assert.EqualValues(t, 1, issue.ID)
+	}
+
+	assert.NoError(t, SetStatusIssue(1, "ci/build", 3))
+	issue, err = GetStatusIssue(1, "ci/build")
+	assert.NoError(t, err)
+	if assert.NotNil(t, issue) {
+		assert.EqualValues(t, 3, issue.ID)
+	}
+
+	// the link to a deleted issue is ignored
+	assert.NoError(t, SetStatusIssue(1, "ci/build", 1000))
+	issue, err = GetStatusIssue(1, "ci/build")
+	assert.NoError(t, err)
+	assert.Nil(t, issue)
+}
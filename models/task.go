@@ -30,6 +30,7 @@ type Task struct {
 	EndTime        timeutil.TimeStamp
 	PayloadContent string             `xorm:"TEXT"`
 	Errors         string             `xorm:"TEXT"` // if task failed, saved the error reason
+	Result         string             `xorm:"TEXT"` // report of the task once finished
 	Created        timeutil.TimeStamp `xorm:"created"`
 }
 
@@ -168,6 +169,22 @@ func GetMigratingTaskByID(id, doerID int64) (*Task, *migration.MigrateOptions, e
 	return &task, &opts, nil
 }
 
+// GetTaskByID returns the task of the given type by its id and the id of its owner
+func GetTaskByID(id, ownerID int64, tp structs.TaskType) (*Task, error) {
+	var task = Task{
+		ID:      id,
+		OwnerID: ownerID,
+		Type:    tp,
+	}
+	has, err := x.Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{id, 0, tp}
+	}
+	return &task, nil
+}
+
 // FindTaskOptions find all tasks
 type FindTaskOptions struct {
 	Status int
@@ -7,11 +7,13 @@ package models
 
 import (
 	"crypto/subtle"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/generate"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	gouuid "github.com/google/uuid"
 )
@@ -25,17 +27,283 @@ type AccessToken struct {
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
 	TokenLastEight string `xorm:"token_last_eight"`
+	// comma separated scopes, a token without scope has all the permissions of its owner
+	Scope string `xorm:"NOT NULL DEFAULT ''"`
+	// the repositories, and the owners of the repositories, the token is restricted to
+	RepoIDs  []int64 `xorm:"JSON TEXT"`
+	OwnerIDs []int64 `xorm:"JSON TEXT"`
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"INDEX updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	LastUsedIP        string             `xorm:"VARCHAR(50)"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
+	IsStale           bool               `xorm:"-"`
 }
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (t *AccessToken) AfterLoad() {
-	t.HasUsed = t.UpdatedUnix > t.CreatedUnix
-	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	t.HasUsed = t.LastUsedUnix > 0
+	t.HasRecentActivity = t.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	t.IsStale = isStaleCredential(t.CreatedUnix, t.LastUsedUnix)
+}
+
+// Scopes restricting the permissions of an access token
+const (
+	// AccessTokenScopeReadCode allows to read the code of the repositories, through the API and git
+	AccessTokenScopeReadCode = "read:code"
+	// AccessTokenScopeWriteCode allows to read and push the code of the repositories
+	AccessTokenScopeWriteCode = "write:code"
+	// AccessTokenScopeReadIssues allows to read the issues of the repositories
+	AccessTokenScopeReadIssues = "read:issues"
+	// AccessTokenScopeWriteIssues allows to read, create and modify the issues of the repositories
+	AccessTokenScopeWriteIssues = "write:issues"
+	// AccessTokenScopeReadPulls allows to read the pull requests of the repositories
+	AccessTokenScopeReadPulls = "read:pulls"
+	// AccessTokenScopeWritePulls allows to read, create, review and merge the pull requests of the repositories
+	AccessTokenScopeWritePulls = "write:pulls"
+	// AccessTokenScopeReadReleases allows to read the releases of the repositories
+	AccessTokenScopeReadReleases = "read:releases"
+	// AccessTokenScopeWriteReleases allows to read and publish the releases of the repositories
+	AccessTokenScopeWriteReleases = "write:releases"
+	// AccessTokenScopeReadWiki allows to read the wikis of the repositories
+	AccessTokenScopeReadWiki = "read:wiki"
+	// AccessTokenScopeWriteWiki allows to read and edit the wikis of the repositories
+	AccessTokenScopeWriteWiki = "write:wiki"
+	// AccessTokenScopeWriteStatus allows to create commit statuses, together with AccessTokenScopeReadCode
+	AccessTokenScopeWriteStatus = "write:status"
+	// AccessTokenScopeSCIM allows the tokens of site administrators to provision the users and the teams through SCIM
+	AccessTokenScopeSCIM = "admin:scim"
+)
+
+// AccessTokenScopes are the valid scopes of an access token
+var AccessTokenScopes = []string{
+	AccessTokenScopeReadCode, AccessTokenScopeWriteCode,
+	AccessTokenScopeReadIssues, AccessTokenScopeWriteIssues,
+	AccessTokenScopeReadPulls, AccessTokenScopeWritePulls,
+	AccessTokenScopeReadReleases, AccessTokenScopeWriteReleases,
+	AccessTokenScopeReadWiki, AccessTokenScopeWriteWiki,
+	AccessTokenScopeWriteStatus, AccessTokenScopeSCIM,
+}
+
+// accessTokenUnitScope are the scopes granting the read and the write access to a unit of the repositories
+type accessTokenUnitScope struct {
+	Unit  UnitType
+	Read  string
+	Write string
+}
+
+var accessTokenUnitScopes = []accessTokenUnitScope{
+	{UnitTypeCode, AccessTokenScopeReadCode, AccessTokenScopeWriteCode},
+	{UnitTypeIssues, AccessTokenScopeReadIssues, AccessTokenScopeWriteIssues},
+	{UnitTypePullRequests, AccessTokenScopeReadPulls, AccessTokenScopeWritePulls},
+	{UnitTypeReleases, AccessTokenScopeReadReleases, AccessTokenScopeWriteReleases},
+	{UnitTypeWiki, AccessTokenScopeReadWiki, AccessTokenScopeWriteWiki},
+}
+
+// AccessTokenWriteScopes are the scopes granting the write access to a unit of the repositories
+var AccessTokenWriteScopes = []string{
+	AccessTokenScopeWriteCode, AccessTokenScopeWriteIssues, AccessTokenScopeWritePulls,
+	AccessTokenScopeWriteReleases, AccessTokenScopeWriteWiki,
+}
+
+// AccessTokenTemplates are the combinations of scopes selectable when creating an access token
+var AccessTokenTemplates = map[string][]string{
+	// CI systems need to clone the repositories and report the status of the commits
+	"ci": {AccessTokenScopeReadCode, AccessTokenScopeWriteStatus},
+	// identity providers provisioning the users and the teams, only useful to site administrators
+	"scim": {AccessTokenScopeSCIM},
+}
+
+// NormalizeAccessTokenScope validates scopes and returns them in the format stored with the tokens
+func NormalizeAccessTokenScope(scopes []string) (string, error) {
+	granted := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		s = strings.TrimSpace(s)
+		if !isValidAccessTokenScope(s) {
+			return "", ErrAccessTokenInvalidScope{s}
+		}
+		granted[s] = true
+	}
+
+	normalized := make([]string, 0, len(granted))
+	for _, scope := range AccessTokenScopes {
+		if granted[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+func isValidAccessTokenScope(scope string) bool {
+	for _, s := range AccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRestricted returns whether the permissions of the token are restricted by scopes or to some repositories
+func (t *AccessToken) IsRestricted() bool {
+	return len(t.Scope) > 0 || t.IsRestrictedToRepos()
+}
+
+// IsRestrictedToRepos returns whether the token is restricted to some repositories or owners
+func (t *AccessToken) IsRestrictedToRepos() bool {
+	return len(t.RepoIDs) > 0 || len(t.OwnerIDs) > 0
+}
+
+// Scopes returns the scopes of the token
+func (t *AccessToken) Scopes() []string {
+	if len(t.Scope) == 0 {
+		return []string{}
+	}
+	return strings.Split(t.Scope, ",")
+}
+
+// HasAnyScope returns whether the token is granted one of the scopes, which is always true for
+// tokens without scopes
+func (t *AccessToken) HasAnyScope(scopes ...string) bool {
+	if len(t.Scope) == 0 {
+		return true
+	}
+	for _, scope := range t.Scopes() {
+		for _, s := range scopes {
+			if s == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope returns whether the token has explicitly been granted the scope, unlike HasAnyScope
+// it's false for tokens without scopes
+func (t *AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessRepo returns whether the token isn't restricted to other repositories
+func (t *AccessToken) CanAccessRepo(repo *Repository) bool {
+	if !t.IsRestrictedToRepos() {
+		return true
+	}
+	return util.IsInt64InSlice(repo.ID, t.RepoIDs) || util.IsInt64InSlice(repo.OwnerID, t.OwnerIDs)
+}
+
+// RestrictPermission returns the permission the token grants on a repository the owner of the
+// token has the given permission on
+func (t *AccessToken) RestrictPermission(repo *Repository, perm Permission) Permission {
+	restricted := Permission{
+		AccessMode: AccessModeNone,
+		UnitsMode:  make(map[UnitType]AccessMode),
+	}
+	if !t.CanAccessRepo(repo) {
+		return restricted
+	}
+	if len(t.Scope) == 0 {
+		return perm
+	}
+	for _, scope := range accessTokenUnitScopes {
+		mode := AccessModeNone
+		if t.HasAnyScope(scope.Write) {
+			mode = AccessModeWrite
+		} else if t.HasAnyScope(scope.Read) {
+			mode = AccessModeRead
+		}
+		if granted := perm.UnitAccessMode(scope.Unit); granted < mode {
+			mode = granted
+		}
+		if mode == AccessModeNone {
+			continue
+		}
+		for _, unit := range perm.Units {
+			if unit.Type == scope.Unit {
+				restricted.Units = append(restricted.Units, unit)
+			}
+		}
+		restricted.UnitsMode[scope.Unit] = mode
+	}
+	return restricted
+}
+
+// ResolveAccessTokenRepos returns the IDs of the repositories, given by their full names, and of the owners,
+// given by their names, a token of the user is restricted to
+func ResolveAccessTokenRepos(user *User, repoNames, ownerNames []string) (repoIDs, ownerIDs []int64, err error) {
+	for _, name := range repoNames {
+		parts := strings.SplitN(strings.TrimSpace(name), "/", 2)
+		if len(parts) != 2 {
+			return nil, nil, ErrRepoNotExist{OwnerName: name}
+		}
+		repo, err := GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		// the existence of the private repositories of others isn't disclosed
+		perm, err := GetUserRepoPermission(repo, user)
+		if err != nil {
+			return nil, nil, err
+		} else if !perm.HasAccess() {
+			return nil, nil, ErrRepoNotExist{OwnerName: parts[0], Name: parts[1]}
+		}
+		if !util.IsInt64InSlice(repo.ID, repoIDs) {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	for _, name := range ownerNames {
+		owner, err := GetUserByName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !util.IsInt64InSlice(owner.ID, ownerIDs) {
+			ownerIDs = append(ownerIDs, owner.ID)
+		}
+	}
+	return repoIDs, ownerIDs, nil
+}
+
+// RepoNames returns the full names of the repositories the token is restricted to
+func (t *AccessToken) RepoNames() ([]string, error) {
+	if len(t.RepoIDs) == 0 {
+		return []string{}, nil
+	}
+	repos, err := GetRepositoriesMapByIDs(t.RepoIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(repos))
+	for _, id := range t.RepoIDs {
+		if repo, ok := repos[id]; ok {
+			if err := repo.GetOwner(); err != nil {
+				return nil, err
+			}
+			names = append(names, repo.FullName())
+		}
+	}
+	return names, nil
+}
+
+// OwnerNames returns the names of the owners of the repositories the token is restricted to
+func (t *AccessToken) OwnerNames() ([]string, error) {
+	if len(t.OwnerIDs) == 0 {
+		return []string{}, nil
+	}
+	owners, err := GetUsersByIDs(t.OwnerIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		names = append(names, owner.Name)
+	}
+	return names, nil
 }
 
 // NewAccessToken creates new access token.
@@ -110,12 +378,32 @@ func ListAccessTokens(opts ListAccessTokensOptions) ([]*AccessToken, error) {
 	return tokens, sess.Find(&tokens)
 }
 
+// GetAccessTokenByID returns the access token of the user with the given ID
+func GetAccessTokenByID(id, userID int64) (*AccessToken, error) {
+	t := new(AccessToken)
+	has, err := x.ID(id).And("uid = ?", userID).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAccessTokenNotExist{}
+	}
+	return t, nil
+}
+
 // UpdateAccessToken updates information of access token.
 func UpdateAccessToken(t *AccessToken) error {
 	_, err := x.ID(t.ID).AllCols().Update(t)
 	return err
 }
 
+// UpdateAccessTokenLastUsed records that the access token has just been used from the address
+func UpdateAccessTokenLastUsed(t *AccessToken, ip string) error {
+	t.LastUsedUnix = timeutil.TimeStampNow()
+	t.LastUsedIP = ip
+	_, err := x.ID(t.ID).Cols("updated_unix", "last_used_unix", "last_used_ip").Update(t)
+	return err
+}
+
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
 	cnt, err := x.ID(id).Delete(&AccessToken{
@@ -128,3 +128,103 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, IsErrAccessTokenNotExist(err))
 }
+
+func TestNormalizeAccessTokenScope(t *testing.T) {
+	scope, err := NormalizeAccessTokenScope(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, scope)
+
+	scope, err = NormalizeAccessTokenScope([]string{"write:status", " read:code", "write:issues", "write:status"})
+	assert.NoError(t, err)
+	assert.Equal(t, "read:code,write:issues,write:status", scope)
+
+	_, err = NormalizeAccessTokenScope([]string{"read:code", "admin"})
+	assert.True(t, IsErrAccessTokenInvalidScope(err))
+}
+
+func TestAccessToken_RestrictPermission(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	perm, err := GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+
+	token := &AccessToken{}
+	assert.False(t, token.IsRestricted())
+	assert.True(t, token.HasAnyScope())
+	assert.False(t, token.HasScope(AccessTokenScopeWriteStatus))
+	assert.Equal(t, perm, token.RestrictPermission(repo, perm))
+
+	token.Scope = "read:code,write:status"
+	assert.True(t, token.IsRestricted())
+	assert.True(t, token.HasAnyScope(AccessTokenScopeWriteStatus))
+	assert.True(t, token.HasScope(AccessTokenScopeWriteStatus))
+	assert.False(t, token.HasAnyScope())
+	restricted := token.RestrictPermission(repo, perm)
+	assert.False(t, restricted.IsAdmin())
+	assert.True(t, restricted.HasAccess())
+	assert.True(t, restricted.CanRead(UnitTypeCode))
+	assert.False(t, restricted.CanWrite(UnitTypeCode))
+	assert.False(t, restricted.CanRead(UnitTypeIssues))
+
+	token.Scope = "read:code,write:issues,read:pulls"
+	restricted = token.RestrictPermission(repo, perm)
+	assert.True(t, restricted.CanRead(UnitTypeCode))
+	assert.True(t, restricted.CanWrite(UnitTypeIssues))
+	assert.True(t, restricted.CanRead(UnitTypePullRequests))
+	assert.False(t, restricted.CanWrite(UnitTypePullRequests))
+	assert.False(t, restricted.CanRead(UnitTypeWiki))
+
+	token.Scope = "write:status"
+	restricted = token.RestrictPermission(repo, perm)
+	assert.False(t, restricted.HasAccess())
+}
+
+func TestAccessToken_CanAccessRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo2 := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	repo3 := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	perm, err := GetUserRepoPermission(repo1, user)
+	assert.NoError(t, err)
+
+	token := &AccessToken{RepoIDs: []int64{repo1.ID}}
+	assert.True(t, token.IsRestricted())
+	assert.True(t, token.HasAnyScope())
+	assert.True(t, token.CanAccessRepo(repo1))
+	assert.False(t, token.CanAccessRepo(repo2))
+	assert.Equal(t, perm, token.RestrictPermission(repo1, perm))
+	restricted := token.RestrictPermission(repo2, perm)
+	assert.False(t, restricted.HasAccess())
+
+	token.OwnerIDs = []int64{repo3.OwnerID}
+	assert.True(t, token.CanAccessRepo(repo3))
+	assert.False(t, token.CanAccessRepo(repo2))
+}
+
+func TestResolveAccessTokenRepos(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	repoIDs, ownerIDs, err := ResolveAccessTokenRepos(user, []string{"user2/repo1", "user2/repo1"}, []string{"user3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, repoIDs)
+	assert.Equal(t, []int64{3}, ownerIDs)
+
+	token := &AccessToken{RepoIDs: repoIDs, OwnerIDs: ownerIDs}
+	names, err := token.RepoNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2/repo1"}, names)
+	names, err = token.OwnerNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user3"}, names)
+
+	// the private repositories of others aren't disclosed
+	_, _, err = ResolveAccessTokenRepos(user, []string{"user10/repo6"}, nil)
+	assert.True(t, IsErrRepoNotExist(err))
+	_, _, err = ResolveAccessTokenRepos(user, []string{"repo1"}, nil)
+	assert.True(t, IsErrRepoNotExist(err))
+	_, _, err = ResolveAccessTokenRepos(user, nil, []string{"unknown"})
+	assert.True(t, IsErrUserNotExist(err))
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// TwoFactorStatus is the compliance of a user with the two-factor authentication requirements of the instance
+// and of its organizations
+type TwoFactorStatus struct {
+	// RequiredByInstance is true when the instance requires all the users or the administrators to enroll
+	RequiredByInstance bool
+	// RequiredByOrgs are the organizations of the user requiring their members to enroll
+	RequiredByOrgs []*User
+	Enrolled       bool
+	// GraceDeadline is when the user must be enrolled, only set when it's required
+	GraceDeadline timeutil.TimeStamp
+}
+
+// IsRequired returns whether the user is required to enroll into two-factor authentication
+func (s *TwoFactorStatus) IsRequired() bool {
+	return s.RequiredByInstance || len(s.RequiredByOrgs) > 0
+}
+
+// IsCompliant returns whether the user is enrolled or isn't required to
+func (s *TwoFactorStatus) IsCompliant() bool {
+	return s.Enrolled || !s.IsRequired()
+}
+
+// IsEnforced returns whether the grace period of a user who isn't compliant has ended
+func (s *TwoFactorStatus) IsEnforced() bool {
+	return !s.IsCompliant() && timeutil.TimeStampNow() >= s.GraceDeadline
+}
+
+// isTwoFactorRequiredByInstance returns whether the instance requires the user to enroll
+func isTwoFactorRequiredByInstance(u *User) bool {
+	switch setting.TwoFactor.Requirement {
+	case "all":
+		return true
+	case "admins":
+		return u.IsAdmin
+	}
+	return false
+}
+
+// GetTwoFactorStatus returns the compliance of the user with the two-factor authentication requirements, the
+// grace period starts the first time the user is found to be required to enroll
+func GetTwoFactorStatus(u *User) (*TwoFactorStatus, error) {
+	status := &TwoFactorStatus{
+		RequiredByInstance: isTwoFactorRequiredByInstance(u),
+		RequiredByOrgs:     make([]*User, 0, 1),
+	}
+	if err := x.Join("INNER", "org_user", "org_user.org_id = `user`.id").
+		Where("org_user.uid = ? AND `user`.require_two_factor = ?", u.ID, true).
+		Asc("`user`.lower_name").
+		Find(&status.RequiredByOrgs); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if status.Enrolled, err = x.Where("uid = ?", u.ID).Exist(new(TwoFactor)); err != nil {
+		return nil, err
+	}
+
+	// the grace period isn't restarted when an enrolled user disables two-factor authentication
+	if status.IsRequired() && u.TwoFactorRequiredSince == 0 {
+		u.TwoFactorRequiredSince = timeutil.TimeStampNow()
+		if _, err := x.ID(u.ID).Cols("two_factor_required_since").NoAutoTime().Update(u); err != nil {
+			return nil, err
+		}
+	} else if !status.IsRequired() && u.TwoFactorRequiredSince != 0 {
+		u.TwoFactorRequiredSince = 0
+		if _, err := x.ID(u.ID).Cols("two_factor_required_since").NoAutoTime().Update(u); err != nil {
+			return nil, err
+		}
+	}
+	if status.IsRequired() {
+		status.GraceDeadline = u.TwoFactorRequiredSince.AddDuration(setting.TwoFactor.GracePeriod)
+	}
+	return status, nil
+}
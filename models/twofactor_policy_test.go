@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTwoFactorStatus(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(requirement string, gracePeriod time.Duration) {
+		setting.TwoFactor.Requirement = requirement
+		setting.TwoFactor.GracePeriod = gracePeriod
+	}(setting.TwoFactor.Requirement, setting.TwoFactor.GracePeriod)
+	setting.TwoFactor.Requirement = "none"
+	setting.TwoFactor.GracePeriod = 24 * time.Hour
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	status, err := GetTwoFactorStatus(user)
+	assert.NoError(t, err)
+	assert.False(t, status.IsRequired())
+	assert.True(t, status.IsCompliant())
+	assert.EqualValues(t, 0, status.GraceDeadline)
+
+	// required by an organization of the user
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	org.RequireTwoFactor = true
+	assert.NoError(t, UpdateUserCols(org, "require_two_factor"))
+	status, err = GetTwoFactorStatus(user)
+	assert.NoError(t, err)
+	assert.False(t, status.RequiredByInstance)
+	if assert.Len(t, status.RequiredByOrgs, 1) {
+		assert.EqualValues(t, 3, status.RequiredByOrgs[0].ID)
+	}
+	assert.False(t, status.IsCompliant())
+	assert.False(t, status.IsEnforced())
+	since := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User).TwoFactorRequiredSince
+	assert.NotZero(t, since)
+	assert.Equal(t, since.AddDuration(24*time.Hour), status.GraceDeadline)
+
+	setting.TwoFactor.GracePeriod = 0
+	status, err = GetTwoFactorStatus(user)
+	assert.NoError(t, err)
+	assert.True(t, status.IsEnforced())
+
+	// the grace period is reset once it's no longer required
+	org.RequireTwoFactor = false
+	assert.NoError(t, UpdateUserCols(org, "require_two_factor"))
+	status, err = GetTwoFactorStatus(user)
+	assert.NoError(t, err)
+	assert.True(t, status.IsCompliant())
+	AssertExistsAndLoadBean(t, &User{ID: 2, TwoFactorRequiredSince: 0})
+
+	// required by the instance
+	setting.TwoFactor.Requirement = "admins"
+	status, err = GetTwoFactorStatus(user)
+	assert.NoError(t, err)
+	assert.False(t, status.IsRequired())
+	status, err = GetTwoFactorStatus(AssertExistsAndLoadBean(t, &User{ID: 1}).(*User))
+	assert.NoError(t, err)
+	assert.True(t, status.RequiredByInstance)
+	assert.True(t, status.IsEnforced())
+
+	setting.TwoFactor.Requirement = "all"
+	status, err = GetTwoFactorStatus(AssertExistsAndLoadBean(t, &User{ID: 24}).(*User))
+	assert.NoError(t, err)
+	assert.True(t, status.RequiredByInstance)
+	assert.True(t, status.Enrolled)
+	assert.True(t, status.IsCompliant())
+	assert.False(t, status.IsEnforced())
+}
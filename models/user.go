@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"code.gitea.io/gitea/modules/auth/ldap"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/generate"
 	"code.gitea.io/gitea/modules/git"
@@ -125,10 +126,15 @@ type User struct {
 	UpdatedUnix   timeutil.TimeStamp `xorm:"INDEX updated"`
 	LastLoginUnix timeutil.TimeStamp `xorm:"INDEX"`
 
+	// Start of the grace period of the user to enroll into two-factor authentication, 0 when it isn't required
+	TwoFactorRequiredSince timeutil.TimeStamp
+
 	// Remember visibility choice for convenience, true for private
 	LastRepoVisibility bool
 	// Maximum repository creation limit, -1 means use global default
 	MaxRepoCreation int `xorm:"NOT NULL DEFAULT -1"`
+	// Storage quota of the repositories in bytes, -1 means use global default
+	MaxStorageSize int64 `xorm:"NOT NULL DEFAULT -1"`
 
 	// Permissions
 	IsActive                bool `xorm:"INDEX"` // Activate primary email
@@ -158,11 +164,16 @@ type User struct {
 	MembersIsPublic           map[int64]bool      `xorm:"-"`
 	Visibility                structs.VisibleType `xorm:"NOT NULL DEFAULT 0"`
 	RepoAdminChangeTeamAccess bool                `xorm:"NOT NULL DEFAULT false"`
+	// RequireTwoFactor requires the members of the organization to enroll into two-factor authentication
+	RequireTwoFactor bool `xorm:"NOT NULL DEFAULT false"`
 
 	// Preferences
-	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
-	Theme               string `xorm:"NOT NULL DEFAULT ''"`
-	KeepActivityPrivate bool   `xorm:"NOT NULL DEFAULT false"`
+	DiffViewStyle          string `xorm:"NOT NULL DEFAULT ''"`
+	DiffWhitespaceBehavior string `xorm:"NOT NULL DEFAULT ''"`
+	DiffContextLines       int    `xorm:"NOT NULL DEFAULT 0"`
+	Theme                  string `xorm:"NOT NULL DEFAULT ''"`
+	KeepActivityPrivate    bool   `xorm:"NOT NULL DEFAULT false"`
+	KeepReviewStatsPrivate bool   `xorm:"NOT NULL DEFAULT false"`
 }
 
 // SearchOrganizationsOptions options to filter organizations
@@ -183,6 +194,9 @@ func (u *User) BeforeUpdate() {
 	if u.MaxRepoCreation < -1 {
 		u.MaxRepoCreation = -1
 	}
+	if u.MaxStorageSize < -1 {
+		u.MaxStorageSize = -1
+	}
 
 	// Organization does not need email
 	u.Email = strings.ToLower(u.Email)
@@ -216,6 +230,18 @@ func (u *User) UpdateDiffViewStyle(style string) error {
 	return UpdateUserCols(u, "diff_view_style")
 }
 
+// UpdateDiffWhitespaceBehavior updates the users diff whitespace behavior
+func (u *User) UpdateDiffWhitespaceBehavior(whitespaceBehavior string) error {
+	u.DiffWhitespaceBehavior = whitespaceBehavior
+	return UpdateUserCols(u, "diff_whitespace_behavior")
+}
+
+// UpdateDiffContextLines updates the users number of diff context lines
+func (u *User) UpdateDiffContextLines(contextLines int) error {
+	u.DiffContextLines = contextLines
+	return UpdateUserCols(u, "diff_context_lines")
+}
+
 // UpdateTheme updates a users' theme irrespective of the site wide theme
 func (u *User) UpdateTheme(themeName string) error {
 	u.Theme = themeName
@@ -735,6 +761,7 @@ var (
 		"raw",
 		"repo",
 		"robots.txt",
+		"s",
 		"search",
 		"stars",
 		"template",
@@ -833,6 +860,7 @@ func CreateUser(u *User) (err error) {
 	u.AllowCreateOrganization = setting.Service.DefaultAllowCreateOrganization && !setting.Admin.DisableRegularOrgCreation
 	u.EmailNotificationsPreference = setting.Admin.DefaultEmailNotification
 	u.MaxRepoCreation = -1
+	u.MaxStorageSize = -1
 	u.Theme = setting.UI.DefaultTheme
 
 	if _, err = sess.Insert(u); err != nil {
@@ -1027,6 +1055,13 @@ func deleteUser(e *xorm.Session, u *User) error {
 		return ErrUserHasOrgs{UID: u.ID}
 	}
 
+	// Check legal holds on the content of the user.
+	if held, err := isUnderLegalHold(e, u.ID, 0); err != nil {
+		return fmt.Errorf("isUnderLegalHold: %v", err)
+	} else if held {
+		return ErrUnderLegalHold{UserID: u.ID}
+	}
+
 	// ***** START: Watch *****
 	watchedRepoIDs := make([]int64, 0, 10)
 	if err = e.Table("watch").Cols("watch.repo_id").
@@ -1082,6 +1117,9 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&IssueReminder{UserID: u.ID},
+		&SavedReply{OwnerID: u.ID},
+		&PullViewedFile{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1186,7 +1224,7 @@ func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) (err erro
 		}
 		if err = DeleteUser(u); err != nil {
 			// Ignore users that were set inactive by admin.
-			if IsErrUserOwnRepos(err) || IsErrUserHasOrgs(err) {
+			if IsErrUserOwnRepos(err) || IsErrUserHasOrgs(err) || IsErrUnderLegalHold(err) {
 				continue
 			}
 			return err
@@ -1325,7 +1363,8 @@ func GetUserIDsByNames(names []string, ignoreNonExistent bool) ([]int64, error)
 
 // UserCommit represents a commit with validation of user.
 type UserCommit struct {
-	User *User
+	User      *User
+	CoAuthors []*CommitTrailer
 	*git.Commit
 }
 
@@ -1364,8 +1403,9 @@ func ValidateCommitsWithEmails(oldCommits *list.List) *list.List {
 		}
 
 		newCommits.PushBack(UserCommit{
-			User:   u,
-			Commit: c,
+			User:      u,
+			CoAuthors: getCommitCoAuthors(c, emails),
+			Commit:    c,
 		})
 		e = e.Next()
 	}
@@ -1436,6 +1476,7 @@ type SearchUserOptions struct {
 	Actor         *User // The user doing the search
 	IsActive      util.OptionalBool
 	SearchByEmail bool // Search by email as well as username/full name
+	UsePrimary    bool // Read from the primary even if a replica of the database is available
 }
 
 func (opts *SearchUserOptions) toConds() builder.Cond {
@@ -1496,22 +1537,30 @@ func (opts *SearchUserOptions) toConds() builder.Cond {
 // it returns results in given range and number of total results.
 func SearchUsers(opts *SearchUserOptions) (users []*User, _ int64, _ error) {
 	cond := opts.toConds()
-	count, err := x.Where(cond).Count(new(User))
-	if err != nil {
-		return nil, 0, fmt.Errorf("Count: %v", err)
-	}
-
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = SearchOrderByAlphabetically
 	}
 
-	sess := x.Where(cond).OrderBy(opts.OrderBy.String())
-	if opts.Page != 0 {
-		sess = opts.setSessionPagination(sess)
-	}
+	var count int64
+	err := withReadEngine(opts.UsePrimary, func(e *xorm.Engine) error {
+		var err error
+		count, err = e.Where(cond).Count(new(User))
+		if err != nil {
+			return fmt.Errorf("Count: %v", err)
+		}
+
+		sess := e.Where(cond).OrderBy(opts.OrderBy.String())
+		if opts.Page != 0 {
+			sess = opts.setSessionPagination(sess)
+		}
 
-	users = make([]*User, 0, opts.PageSize)
-	return users, count, sess.Find(&users)
+		users = make([]*User, 0, opts.PageSize)
+		return sess.Find(&users)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, count, nil
 }
 
 // GetStarredRepos returns the repos starred by a particular user
@@ -1788,10 +1837,14 @@ func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 
 					if err != nil {
 						log.Error("SyncExternalUsers[%s]: Error creating user %s: %v", s.Name, su.Username, err)
-					} else if isAttributeSSHPublicKeySet {
-						log.Trace("SyncExternalUsers[%s]: Adding LDAP Public SSH Keys for user %s", s.Name, usr.Name)
-						if addLdapSSHPublicKeys(usr, s, su.SSHPublicKey) {
-							sshKeysNeedUpdate = true
+					} else {
+						syncLDAPGroupTeams(usr, s, su)
+						syncLDAPAvatar(usr, s, su)
+						if isAttributeSSHPublicKeySet {
+							log.Trace("SyncExternalUsers[%s]: Adding LDAP Public SSH Keys for user %s", s.Name, usr.Name)
+							if addLdapSSHPublicKeys(usr, s, su.SSHPublicKey) {
+								sshKeysNeedUpdate = true
+							}
 						}
 					}
 				} else if updateExisting {
@@ -1828,6 +1881,9 @@ func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 							log.Error("SyncExternalUsers[%s]: Error updating user %s: %v", s.Name, usr.Name, err)
 						}
 					}
+
+					syncLDAPGroupTeams(usr, s, su)
+					syncLDAPAvatar(usr, s, su)
 				}
 			}
 
@@ -1864,6 +1920,11 @@ func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 						if err != nil {
 							log.Error("SyncExternalUsers[%s]: Error deactivating user %s: %v", s.Name, usr.Name, err)
 						}
+
+						// the users missing from LDAP aren't a member of any group anymore
+						if s.LDAP().IsGroupTeamMapSet() {
+							syncLDAPGroupTeams(usr, s, &ldap.SearchResult{Groups: []string{}})
+						}
 					}
 				}
 			}
@@ -1874,17 +1935,17 @@ func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 
 // IterateUser iterate users
 func IterateUser(f func(user *User) error) error {
-	var start int
+	var lastID int64
 	var batchSize = setting.Database.IterateBufferSize
 	for {
 		var users = make([]*User, 0, batchSize)
-		if err := x.Limit(batchSize, start).Find(&users); err != nil {
+		if err := x.Where("id > ?", lastID).OrderBy("id").Limit(batchSize).Find(&users); err != nil {
 			return err
 		}
 		if len(users) == 0 {
 			return nil
 		}
-		start += len(users)
+		lastID = users[len(users)-1].ID
 
 		for _, user := range users {
 			if err := f(user); err != nil {
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/avatar/provider"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -71,30 +72,38 @@ func (u *User) SizedRelAvatarLink(size int) string {
 // RealSizedAvatarLink returns a link to the user's avatar. When
 // applicable, the link is for an avatar of the indicated size (in pixels).
 //
-// This function make take time to return when federated avatars
-// are in use, due to a DNS lookup need
-//
+// This function make take time to return when the avatar providers
+// need network requests, e.g. the DNS lookup of federated avatars
 func (u *User) RealSizedAvatarLink(size int) string {
 	if u.ID == -1 {
 		return base.DefaultAvatarLink()
 	}
 
-	switch {
-	case u.UseCustomAvatar:
+	if u.UseCustomAvatar {
 		if u.Avatar == "" {
 			return base.DefaultAvatarLink()
 		}
-		return setting.AppSubURL + "/avatars/" + u.Avatar
-	case setting.DisableGravatar, setting.OfflineMode:
-		if u.Avatar == "" {
-			if err := u.GenerateRandomAvatar(); err != nil {
-				log.Error("GenerateRandomAvatar: %v", err)
-			}
+		return setting.AppSubURL + "/avatars/" + u.Avatar + avatarSizeQuery(size)
+	}
+	if link := provider.AvatarLink(u.AvatarEmail, size); len(link) > 0 {
+		return link
+	}
+
+	// none of the providers has the avatar, a random one is generated
+	if u.Avatar == "" {
+		if err := u.GenerateRandomAvatar(); err != nil {
+			log.Error("GenerateRandomAvatar: %v", err)
 		}
+	}
+	return setting.AppSubURL + "/avatars/" + u.Avatar + avatarSizeQuery(size)
+}
 
-		return setting.AppSubURL + "/avatars/" + u.Avatar
+// avatarSizeQuery returns the query of the link to the stored avatar resized to the size
+func avatarSizeQuery(size int) string {
+	if setting.Avatar.ServeDirect || !avatar.IsValidVariantSize(size) {
+		return ""
 	}
-	return base.SizedAvatarLink(u.AvatarEmail, size)
+	return "?size=" + strconv.Itoa(size)
 }
 
 // RelAvatarLink returns a relative link to the user's avatar. The link
@@ -113,6 +122,23 @@ func (u *User) AvatarLink() string {
 	return link
 }
 
+// uploadedAvatarID returns the name of the custom avatar of the user uploaded from the image data
+func (u *User) uploadedAvatarID(data []byte) string {
+	// Different users can upload same image as avatar
+	// If we prefix it with u.ID, it will be separated
+	// Otherwise, if any of the users delete his avatar
+	// Other users will lose their avatars too.
+	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d-%x", u.ID, md5.Sum(data)))))
+}
+
+// IsUploadAvatarChanged returns whether the custom avatar of the user differs from the image data
+func (u *User) IsUploadAvatarChanged(data []byte) bool {
+	if !u.UseCustomAvatar || len(u.Avatar) == 0 {
+		return true
+	}
+	return u.Avatar != u.uploadedAvatarID(data)
+}
+
 // UploadAvatar saves custom avatar for user.
 // FIXME: split uploads to different subdirs in case we have massive users.
 func (u *User) UploadAvatar(data []byte) error {
@@ -128,11 +154,7 @@ func (u *User) UploadAvatar(data []byte) error {
 	}
 
 	u.UseCustomAvatar = true
-	// Different users can upload same image as avatar
-	// If we prefix it with u.ID, it will be separated
-	// Otherwise, if any of the users delete his avatar
-	// Other users will lose their avatars too.
-	u.Avatar = fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d-%x", u.ID, md5.Sum(data)))))
+	u.Avatar = u.uploadedAvatarID(data)
 	if err = updateUser(sess, u); err != nil {
 		return fmt.Errorf("updateUser: %v", err)
 	}
@@ -55,6 +55,11 @@ func IsValidHookContentType(name string) bool {
 	return ok
 }
 
+// IsValidWebhookPayloadVersion returns true if given version is a supported version of the webhook payloads.
+func IsValidWebhookPayloadVersion(version int) bool {
+	return version >= 1 && version <= api.WebhookPayloadVersion
+}
+
 // HookEvents is a set of web hook events
 type HookEvents struct {
 	Create               bool `json:"create"`
@@ -75,6 +80,10 @@ type HookEvents struct {
 	PullRequestSync      bool `json:"pull_request_sync"`
 	Repository           bool `json:"repository"`
 	Release              bool `json:"release"`
+	ReviewEnvironment    bool `json:"review_environment"`
+	User                 bool `json:"user"`
+	Organization         bool `json:"organization"`
+	AuthFailure          bool `json:"auth_failure"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -115,6 +124,7 @@ type Webhook struct {
 	HookTaskType    HookTaskType
 	Meta            string     `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus      HookStatus // Last delivery status
+	PayloadVersion  int        `xorm:"NOT NULL DEFAULT 1"` // Version of the payloads sent to the webhook
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -260,6 +270,30 @@ func (w *Webhook) HasRepositoryEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasReviewEnvironmentEvent returns if hook enabled review environment event.
+func (w *Webhook) HasReviewEnvironmentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.ReviewEnvironment)
+}
+
+// HasUserEvent returns if hook enabled user event, only system webhooks receive it.
+func (w *Webhook) HasUserEvent() bool {
+	return w.IsSystemWebhook && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.User))
+}
+
+// HasOrganizationEvent returns if hook enabled organization event, only system webhooks receive it.
+func (w *Webhook) HasOrganizationEvent() bool {
+	return w.IsSystemWebhook && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Organization))
+}
+
+// HasAuthFailureEvent returns if hook enabled authentication failure event, only system webhooks receive it.
+func (w *Webhook) HasAuthFailureEvent() bool {
+	return w.IsSystemWebhook && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.AuthFailure))
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -289,6 +323,10 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasPullRequestSyncEvent, HookEventPullRequestSync},
 		{w.HasRepositoryEvent, HookEventRepository},
 		{w.HasReleaseEvent, HookEventRelease},
+		{w.HasReviewEnvironmentEvent, HookEventReviewEnvironment},
+		{w.HasUserEvent, HookEventUser},
+		{w.HasOrganizationEvent, HookEventOrganization},
+		{w.HasAuthFailureEvent, HookEventAuthFailure},
 	}
 }
 
@@ -304,12 +342,20 @@ func (w *Webhook) EventsArray() []string {
 	return events
 }
 
-// CreateWebhook creates a new web hook.
+// CreateWebhook creates a new web hook, an active one must be allowed to call its URL.
 func CreateWebhook(w *Webhook) error {
+	if w.IsActive {
+		if err := checkWebhookURL(x, w, w.URL); err != nil {
+			return err
+		}
+	}
 	return createWebhook(x, w)
 }
 
 func createWebhook(e Engine, w *Webhook) error {
+	if w.PayloadVersion == 0 {
+		w.PayloadVersion = api.WebhookPayloadVersion
+	}
 	_, err := e.Insert(w)
 	return err
 }
@@ -452,6 +498,11 @@ func getSystemWebhooks(e Engine) ([]*Webhook, error) {
 
 // UpdateWebhook updates information of webhook.
 func UpdateWebhook(w *Webhook) error {
+	if w.IsActive {
+		if err := checkWebhookURL(x, w, w.URL); err != nil {
+			return err
+		}
+	}
 	_, err := x.ID(w.ID).AllCols().Update(w)
 	return err
 }
@@ -635,6 +686,10 @@ const (
 	HookEventPullRequestSync           HookEventType = "pull_request_sync"
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
+	HookEventReviewEnvironment         HookEventType = "review_environment"
+	HookEventUser                      HookEventType = "user"
+	HookEventOrganization              HookEventType = "organization"
+	HookEventAuthFailure               HookEventType = "auth_failure"
 )
 
 // Event returns the HookEventType as an event string
@@ -665,6 +720,14 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventReviewEnvironment:
+		return "review_environment"
+	case HookEventUser:
+		return "user"
+	case HookEventOrganization:
+		return "organization"
+	case HookEventAuthFailure:
+		return "auth_failure"
 	}
 	return ""
 }
@@ -695,6 +758,7 @@ type HookTask struct {
 	HTTPMethod      string `xorm:"http_method"`
 	ContentType     HookContentType
 	EventType       HookEventType
+	PayloadVersion  int `xorm:"NOT NULL DEFAULT 1"`
 	IsSSL           bool
 	IsDelivered     bool
 	Delivered       int64
@@ -708,6 +772,13 @@ type HookTask struct {
 	ResponseInfo    *HookResponse `xorm:"-"`
 }
 
+// BeforeInsert will be invoked by XORM before inserting a record
+func (t *HookTask) BeforeInsert() {
+	if t.ID == 0 {
+		t.ID = nextHighWriteID()
+	}
+}
+
 // BeforeUpdate will be invoked by XORM before updating a record
 // representing this object
 func (t *HookTask) BeforeUpdate() {
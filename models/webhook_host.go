@@ -0,0 +1,200 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// WebhookHostAllowlist represents the hosts which the webhooks of an owner and of its repositories
+// may call, in addition to the [webhook] ALLOWED_HOST_LIST setting. It is managed by the site admins.
+type WebhookHostAllowlist struct {
+	ID      int64 `xorm:"pk autoincr"`
+	OwnerID int64 `xorm:"UNIQUE"`
+	// host patterns, one per line
+	Hosts string `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// HostList returns the host patterns of the allowlist
+func (l *WebhookHostAllowlist) HostList() []string {
+	return parseWebhookHosts(l.Hosts)
+}
+
+// parseWebhookHosts returns the non-empty host patterns separated by new lines or commas
+func parseWebhookHosts(hosts string) []string {
+	patterns := make([]string, 0, 5)
+	for _, pattern := range strings.FieldsFunc(hosts, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ','
+	}) {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); len(pattern) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// matchWebhookHost returns whether the host of a URL, with or without its port, matches
+// one of the patterns. An empty list matches any host.
+func matchWebhookHost(patterns []string, u *url.URL) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	host := strings.ToLower(u.Host)
+	hostname := strings.ToLower(u.Hostname())
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			log.Error("glob.Compile %s failed: %v", pattern, err)
+			continue
+		}
+		if g.Match(hostname) || g.Match(host) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWebhookHostAllowlist returns the allowlist of an owner, an empty one if it has none
+func GetWebhookHostAllowlist(ownerID int64) (*WebhookHostAllowlist, error) {
+	return getWebhookHostAllowlist(x, ownerID)
+}
+
+func getWebhookHostAllowlist(e Engine, ownerID int64) (*WebhookHostAllowlist, error) {
+	allowlist := &WebhookHostAllowlist{OwnerID: ownerID}
+	if _, err := e.Where("owner_id = ?", ownerID).Get(allowlist); err != nil {
+		return nil, err
+	}
+	return allowlist, nil
+}
+
+// ownerID returns the ID of the owner of the webhook or of its repository, 0 for the default
+// and system webhooks
+func (w *Webhook) ownerID(e Engine) (int64, error) {
+	if w.OrgID > 0 {
+		return w.OrgID, nil
+	}
+	if w.RepoID > 0 {
+		repo, err := getRepositoryByID(e, w.RepoID)
+		if err != nil {
+			return 0, err
+		}
+		return repo.OwnerID, nil
+	}
+	return 0, nil
+}
+
+// CheckWebhookURL returns an ErrWebhookHostNotAllowed if a webhook may not call a URL
+// because of the global or the owner allowlist
+func CheckWebhookURL(w *Webhook, rawURL string) error {
+	return checkWebhookURL(x, w, rawURL)
+}
+
+func checkWebhookURL(e Engine, w *Webhook, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !matchWebhookHost(setting.Webhook.AllowedHostList, u) {
+		return ErrWebhookHostNotAllowed{Host: u.Host}
+	}
+
+	ownerID, err := w.ownerID(e)
+	if err != nil {
+		return err
+	}
+	if ownerID == 0 {
+		return nil
+	}
+	allowlist, err := getWebhookHostAllowlist(e, ownerID)
+	if err != nil {
+		return err
+	}
+	if !matchWebhookHost(allowlist.HostList(), u) {
+		return ErrWebhookHostNotAllowed{Host: u.Host}
+	}
+	return nil
+}
+
+// UpdateWebhookHostAllowlist sets the host patterns of the allowlist of an owner, an empty
+// list allows any host. The active webhooks of the owner and of its repositories which may
+// not call their URL anymore are disabled and returned.
+func UpdateWebhookHostAllowlist(ownerID int64, hosts string) ([]*Webhook, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	allowlist, err := getWebhookHostAllowlist(sess, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	allowlist.Hosts = strings.Join(parseWebhookHosts(hosts), "\n")
+	if allowlist.ID == 0 {
+		_, err = sess.Insert(allowlist)
+	} else {
+		_, err = sess.ID(allowlist.ID).Cols("hosts").Update(allowlist)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]*Webhook, 0, 10)
+	if err := sess.
+		Where(builder.Eq{"is_active": true}.And(builder.Eq{"org_id": ownerID}.Or(
+			builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": ownerID})),
+		))).
+		Find(&hooks); err != nil {
+		return nil, err
+	}
+
+	disabled := make([]*Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		if err := checkWebhookURL(sess, hook, hook.URL); err == nil {
+			continue
+		} else if !IsErrWebhookHostNotAllowed(err) {
+			return nil, err
+		}
+		if err := disableWebhook(sess, hook); err != nil {
+			return nil, err
+		}
+		disabled = append(disabled, hook)
+	}
+	return disabled, sess.Commit()
+}
+
+// DisableWebhookForDeniedHost disables a webhook which tried to call a host it isn't allowed to
+// anymore and notifies the site admins
+func DisableWebhookForDeniedHost(w *Webhook, host string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := disableWebhook(sess, w); err != nil {
+		return err
+	}
+	if err := createNotice(sess, NoticeRepository, "Webhook %d has been disabled: it may not call the host %s", w.ID, host); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func disableWebhook(e Engine, w *Webhook) error {
+	w.IsActive = false
+	_, err := e.ID(w.ID).Cols("is_active").Update(w)
+	return err
+}
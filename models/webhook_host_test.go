@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateWebhookHostAllowlist(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// the active hooks of the repositories of user2
+	hook1 := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	hook1.URL = "http://www.example.com/url1"
+	hook4 := AssertExistsAndLoadBean(t, &Webhook{ID: 4}).(*Webhook)
+	hook4.URL = "https://hooks.other.org:8443/url4"
+	for _, hook := range []*Webhook{hook1, hook4} {
+		_, err := x.ID(hook.ID).Cols("url").Update(hook)
+		assert.NoError(t, err)
+	}
+
+	disabled, err := UpdateWebhookHostAllowlist(2, "*.example.com\r\nHooks.other.org:8443\n\n")
+	assert.NoError(t, err)
+	assert.Empty(t, disabled)
+	allowlist, err := GetWebhookHostAllowlist(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*.example.com", "hooks.other.org:8443"}, allowlist.HostList())
+
+	err = CheckWebhookURL(hook1, "http://evil.com/url1")
+	assert.True(t, IsErrWebhookHostNotAllowed(err))
+	assert.NoError(t, CheckWebhookURL(hook1, "http://www.example.com:8080/url1"))
+
+	// the hooks calling a host which isn't allowed anymore are disabled
+	disabled, err = UpdateWebhookHostAllowlist(2, "example.com, *.example.com")
+	assert.NoError(t, err)
+	if assert.Len(t, disabled, 1) {
+		assert.EqualValues(t, 4, disabled[0].ID)
+	}
+	AssertExistsAndLoadBean(t, &Webhook{ID: 1, IsActive: true})
+	AssertExistsAndLoadBean(t, &Webhook{ID: 4}, Cond("is_active = ?", false))
+
+	// and can't be enabled again
+	hook4.IsActive = true
+	assert.True(t, IsErrWebhookHostNotAllowed(UpdateWebhook(hook4)))
+	err = CreateWebhook(&Webhook{RepoID: 1, URL: "http://evil.com", IsActive: true})
+	assert.True(t, IsErrWebhookHostNotAllowed(err))
+	assert.NoError(t, CreateWebhook(&Webhook{RepoID: 1, URL: "http://evil.com"}))
+
+	// the other owners are only restricted by the global setting
+	hook3 := AssertExistsAndLoadBean(t, &Webhook{ID: 3}).(*Webhook)
+	assert.NoError(t, CheckWebhookURL(hook3, "http://evil.com"))
+	defer func(hosts []string) {
+		setting.Webhook.AllowedHostList = hosts
+	}(setting.Webhook.AllowedHostList)
+	setting.Webhook.AllowedHostList = []string{"*.example.com"}
+	assert.True(t, IsErrWebhookHostNotAllowed(CheckWebhookURL(hook3, "http://evil.com")))
+	assert.NoError(t, CheckWebhookURL(hook3, "http://www.example.com"))
+
+	// an empty list allows any host
+	disabled, err = UpdateWebhookHostAllowlist(2, "")
+	assert.NoError(t, err)
+	assert.Empty(t, disabled)
+	setting.Webhook.AllowedHostList = nil
+	assert.NoError(t, CheckWebhookURL(hook1, "http://evil.com/url1"))
+}
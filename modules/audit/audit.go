@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Record records an event in the audit log when it's enabled. The target is the *models.User or the
+// *models.Repository the event applies to, or nil for the instance. A failure to record the event is
+// only logged.
+func Record(action models.AuditAction, doer *models.User, remoteAddr string, target interface{}, format string, args ...interface{}) {
+	if !setting.Audit.Enabled {
+		return
+	}
+
+	event := &models.AuditEvent{
+		Action:      action,
+		IPAddress:   remoteAddr,
+		TargetType:  models.AuditTargetInstance,
+		Description: fmt.Sprintf(format, args...),
+	}
+	if doer != nil {
+		event.DoerID = doer.ID
+		event.DoerName = doer.Name
+	}
+	switch t := target.(type) {
+	case *models.User:
+		event.TargetType = models.AuditTargetUser
+		if t.IsOrganization() {
+			event.TargetType = models.AuditTargetOrganization
+		}
+		event.TargetID = t.ID
+		event.TargetName = t.Name
+	case *models.Repository:
+		event.TargetType = models.AuditTargetRepository
+		event.TargetID = t.ID
+		event.TargetName = t.FullName()
+	}
+
+	if err := models.CreateAuditEvent(event); err != nil {
+		log.Error("Unable to record the audit event %s of %s: %v", action, event.DoerName, err)
+	}
+}
+
+// RecordWebhook records the creation, the edition or the deletion of a webhook, the target is the repository
+// or the organization of the webhook, or the instance for the default and system webhooks.
+func RecordWebhook(action models.AuditAction, doer *models.User, remoteAddr string, w *models.Webhook) {
+	if !setting.Audit.Enabled {
+		return
+	}
+
+	var target interface{}
+	var err error
+	if w.RepoID > 0 {
+		target, err = models.GetRepositoryByID(w.RepoID)
+	} else if w.OrgID > 0 {
+		target, err = models.GetUserByID(w.OrgID)
+	}
+	if err != nil {
+		log.Error("Unable to load the owner of the webhook %d: %v", w.ID, err)
+		return
+	}
+
+	switch action {
+	case models.AuditActionWebhookCreate:
+		Record(action, doer, remoteAddr, target, "Created the %s webhook %d", w.HookTaskType.Name(), w.ID)
+	case models.AuditActionWebhookEdit:
+		Record(action, doer, remoteAddr, target, "Edited the %s webhook %d", w.HookTaskType.Name(), w.ID)
+	default:
+		Record(action, doer, remoteAddr, target, "Deleted the webhook %d", w.ID)
+	}
+}
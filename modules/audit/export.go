@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models"
+)
+
+// ExportCSV writes the audit events matching the options as CSV to w, the oldest first
+func ExportCSV(w io.Writer, opts *models.FindAuditEventsOptions) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "created", "action", "doer_id", "doer_name", "ip_address", "target_type", "target_id", "target_name", "description"}); err != nil {
+		return err
+	}
+	if err := models.IterateAuditEvents(opts, func(event *models.AuditEvent) error {
+		return cw.Write([]string{
+			strconv.FormatInt(event.ID, 10),
+			event.CreatedUnix.AsTime().UTC().Format(time.RFC3339),
+			string(event.Action),
+			strconv.FormatInt(event.DoerID, 10),
+			event.DoerName,
+			event.IPAddress,
+			event.TargetType,
+			strconv.FormatInt(event.TargetID, 10),
+			event.TargetName,
+			event.Description,
+		})
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -35,6 +35,7 @@ type AdminEditUserForm struct {
 	Website                 string `binding:"ValidUrl;MaxSize(255)"`
 	Location                string `binding:"MaxSize(50)"`
 	MaxRepoCreation         int
+	MaxStorageSize          int64
 	Active                  bool
 	Admin                   bool
 	Restricted              bool
@@ -59,3 +60,13 @@ type AdminDashboardForm struct {
 func (f *AdminDashboardForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// AdminWebhookHostsForm form for admin to restrict the hosts the webhooks of an organization may call
+type AdminWebhookHostsForm struct {
+	Hosts string `binding:"MaxSize(10000)"`
+}
+
+// Validate validates form fields
+func (f *AdminWebhookHostsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -44,6 +44,7 @@ type Source struct {
 	AttributeMail         string // E-mail attribute
 	AttributesInBind      bool   // fetch attributes in bind context (not user)
 	AttributeSSHPublicKey string // LDAP SSH Public Key attribute
+	AttributeAvatar       string // LDAP photo attribute, e.g. jpegPhoto or thumbnailPhoto
 	SearchPageSize        uint32 // Search with paging page size
 	Filter                string // Query filter to validate entry
 	AdminFilter           string // Query filter to check if user is admin
@@ -55,6 +56,8 @@ type Source struct {
 	GroupFilter           string // Group Name Filter
 	GroupMemberUID        string // Group Attribute containing array of UserUID
 	UserUID               string // User Attribute listed in Group
+	GroupTeamMap          string // JSON mapping of the group DNs to the teams of the organizations
+	GroupTeamMapRemoval   bool   // remove the users from the mapped teams of the groups they're not a member of
 }
 
 // SearchResult : user data
@@ -64,8 +67,10 @@ type SearchResult struct {
 	Surname      string   // Surname
 	Mail         string   // E-mail address
 	SSHPublicKey []string // SSH Public Key
+	Avatar       []byte   // Photo of the user
 	IsAdmin      bool     // if user is administrator
 	IsRestricted bool     // if user is restricted
+	Groups       []string // DNs of the groups of the user, only listed when the groups are mapped to teams
 }
 
 func (ls *Source) sanitizedUserQuery(username string) (string, bool) {
@@ -229,6 +234,46 @@ func checkRestricted(l *ldap.Conn, ls *Source, userDN string) bool {
 	return false
 }
 
+// listUserGroups returns the DNs of the groups under the group search base the user is a member of
+func listUserGroups(l *ldap.Conn, ls *Source, userDN, uid string) ([]string, error) {
+	member := uid
+	if ls.UserUID == "dn" {
+		member = userDN
+	}
+	if len(member) == 0 {
+		return []string{}, nil
+	}
+	groupFilter := fmt.Sprintf("(%s=%s)", ls.GroupMemberUID, ldap.EscapeFilter(member))
+	if filter, ok := ls.sanitizedGroupFilter(ls.GroupFilter); ok && len(filter) > 0 {
+		groupFilter = fmt.Sprintf("(&%s%s)", filter, groupFilter)
+	}
+	groupDN, ok := ls.sanitizedGroupDN(ls.GroupDN)
+	if !ok {
+		return nil, fmt.Errorf("invalid group search base: %s", ls.GroupDN)
+	}
+
+	log.Trace("Listing groups with filter '%s' and base '%s'", groupFilter, groupDN)
+	search := ldap.NewSearchRequest(
+		groupDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, groupFilter,
+		[]string{"dn"},
+		nil)
+
+	sr, err := l.Search(search)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		groups = append(groups, entry.DN)
+	}
+	return groups, nil
+}
+
+// IsGroupTeamMapSet returns whether the groups of the users are mapped to teams
+func (ls *Source) IsGroupTeamMapSet() bool {
+	return ls.GroupsEnabled && len(strings.TrimSpace(ls.GroupTeamMap)) > 0
+}
+
 // SearchEntry : search an LDAP source if an entry (name, passwd) is valid and in the specific filter
 func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResult {
 	// See https://tools.ietf.org/search/rfc4513#section-5.1.2
@@ -313,6 +358,9 @@ func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResul
 	if isAttributeSSHPublicKeySet {
 		attribs = append(attribs, ls.AttributeSSHPublicKey)
 	}
+	if len(strings.TrimSpace(ls.AttributeAvatar)) > 0 {
+		attribs = append(attribs, ls.AttributeAvatar)
+	}
 
 	log.Trace("Fetching attributes '%v', '%v', '%v', '%v', '%v', '%v' with filter '%s' and base '%s'", ls.AttributeUsername, ls.AttributeName, ls.AttributeSurname, ls.AttributeMail, ls.AttributeSSHPublicKey, ls.UserUID, userFilter, userDN)
 	search := ldap.NewSearchRequest(
@@ -387,11 +435,24 @@ func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResul
 	if isAttributeSSHPublicKeySet {
 		sshPublicKey = sr.Entries[0].GetAttributeValues(ls.AttributeSSHPublicKey)
 	}
+	var avatar []byte
+	if len(strings.TrimSpace(ls.AttributeAvatar)) > 0 {
+		avatar = sr.Entries[0].GetRawAttributeValue(ls.AttributeAvatar)
+	}
 	isAdmin := checkAdmin(l, ls, userDN)
 	var isRestricted bool
 	if !isAdmin {
 		isRestricted = checkRestricted(l, ls, userDN)
 	}
+	var groups []string
+	if ls.IsGroupTeamMapSet() {
+		// the groups of the user must be known to sync its teams
+		groups, err = listUserGroups(l, ls, sr.Entries[0].DN, uid)
+		if err != nil {
+			log.Error("LDAP group search failed unexpectedly! (%v)", err)
+			return nil
+		}
+	}
 
 	if !directBind && ls.AttributesInBind {
 		// binds user (checking password) after looking-up attributes in BindDN context
@@ -407,8 +468,10 @@ func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResul
 		Surname:      surname,
 		Mail:         mail,
 		SSHPublicKey: sshPublicKey,
+		Avatar:       avatar,
 		IsAdmin:      isAdmin,
 		IsRestricted: isRestricted,
+		Groups:       groups,
 	}
 }
 
@@ -446,6 +509,12 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 	if isAttributeSSHPublicKeySet {
 		attribs = append(attribs, ls.AttributeSSHPublicKey)
 	}
+	if ls.IsGroupTeamMapSet() && len(strings.TrimSpace(ls.UserUID)) > 0 {
+		attribs = append(attribs, ls.UserUID)
+	}
+	if len(strings.TrimSpace(ls.AttributeAvatar)) > 0 {
+		attribs = append(attribs, ls.AttributeAvatar)
+	}
 
 	log.Trace("Fetching attributes '%v', '%v', '%v', '%v', '%v' with filter %s and base %s", ls.AttributeUsername, ls.AttributeName, ls.AttributeSurname, ls.AttributeMail, ls.AttributeSSHPublicKey, userFilter, ls.UserBase)
 	search := ldap.NewSearchRequest(
@@ -476,6 +545,15 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 		if !result[i].IsAdmin {
 			result[i].IsRestricted = checkRestricted(l, ls, v.DN)
 		}
+		if ls.IsGroupTeamMapSet() {
+			if result[i].Groups, err = listUserGroups(l, ls, v.DN, v.GetAttributeValue(ls.UserUID)); err != nil {
+				log.Error("LDAP group search failed unexpectedly! (%v)", err)
+				return nil, err
+			}
+		}
+		if len(strings.TrimSpace(ls.AttributeAvatar)) > 0 {
+			result[i].Avatar = v.GetRawAttributeValue(ls.AttributeAvatar)
+		}
 		if isAttributeSSHPublicKeySet {
 			result[i].SSHPublicKey = v.GetAttributeValues(ls.AttributeSSHPublicKey)
 		}
@@ -41,7 +41,9 @@ type UpdateOrgSettingForm struct {
 	Location                  string `binding:"MaxSize(50)"`
 	Visibility                structs.VisibleType
 	MaxRepoCreation           int
+	MaxStorageSize            int64
 	RepoAdminChangeTeamAccess bool
+	RequireTwoFactor          bool
 }
 
 // Validate validates the fields
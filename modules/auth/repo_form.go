@@ -151,9 +151,18 @@ type RepoSettingForm struct {
 	PullsAllowRebase                 bool
 	PullsAllowRebaseMerge            bool
 	PullsAllowSquash                 bool
+	PullsReviewChecklist             string
+	PullsMergeMessageTemplate        string
+	PullsRebaseMergeMessageTemplate  string
+	PullsSquashMessageTemplate       string
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableIssueDependencies          bool
+	IssuesCloseKeywords              string
+	IssuesReopenKeywords             string
+	IssuesKeywordLanguages           string
+	IssuesDisableCrossRepoClose      bool
+	IssuesCloseOnDefaultBranchOnly   bool
 	IsArchived                       bool
 
 	// Signing Settings
@@ -198,6 +207,13 @@ type ProtectBranchForm struct {
 	DismissStaleApprovals         bool
 	RequireSignedCommits          bool
 	ProtectedFilePatterns         string
+	EnableMergeQueue              bool
+	RequireCodeOwnerApproval      bool
+	RequireLinearHistory          bool
+	DismissApprovalsOnPush        bool
+	DismissApprovalsOnForcePush   bool
+	ProtectedPathRules            string
+	RequireSignOff                bool
 }
 
 // Validate validates the fields
@@ -205,6 +221,65 @@ func (f *ProtectBranchForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// StalePolicyForm form for changing the stale policy of a repository
+type StalePolicyForm struct {
+	Enabled        bool
+	IncludeIssues  bool
+	IncludePulls   bool
+	DaysUntilStale int
+	DaysUntilClose int
+	StaleLabel     string `binding:"MaxSize(50)"`
+	ExemptLabels   string
+	WarningComment string
+	CloseComment   string
+}
+
+// Validate validates the fields
+func (f *StalePolicyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ReleaseAutomationForm form for changing the release automation of a repository
+type ReleaseAutomationForm struct {
+	Enabled       bool
+	TagPattern    string `binding:"MaxSize(255)"`
+	Draft         bool
+	Prerelease    bool
+	TitleTemplate string `binding:"MaxSize(255)"`
+	NoteTemplate  string
+}
+
+// Validate validates the fields
+func (f *ReleaseAutomationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// StatusIssueRuleForm form for changing the rule of a repository opening issues for the failing statuses
+type StatusIssueRuleForm struct {
+	Enabled        bool
+	ContextPattern string `binding:"MaxSize(255)"`
+	LabelName      string `binding:"MaxSize(50)"`
+}
+
+// Validate validates the fields
+func (f *StatusIssueRuleForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// RepoBadgeForm form for adding or editing a badge shown on the home of a repository
+type RepoBadgeForm struct {
+	ID       int64
+	Name     string `binding:"Required;MaxSize(50)" locale:"repo.settings.badges.name"`
+	LinkURL  string `binding:"Required;ValidUrl" locale:"repo.settings.badges.link_url"`
+	ImageURL string `binding:"ValidUrl" locale:"repo.settings.badges.image_url"`
+	Sort     int
+}
+
+// Validate validates the fields
+func (f *RepoBadgeForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //  __      __      ___.   .__    .__            __
 // /  \    /  \ ____\_ |__ |  |__ |  |__   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \|  |  \ /  _ \|  |/ /
@@ -233,6 +308,10 @@ type WebhookForm struct {
 	PullRequestReview    bool
 	PullRequestSync      bool
 	Repository           bool
+	ReviewEnvironment    bool
+	User                 bool
+	Organization         bool
+	AuthFailure          bool
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
 }
@@ -254,10 +333,11 @@ func (f WebhookForm) ChooseEvents() bool {
 
 // NewWebhookForm form for creating web hook
 type NewWebhookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	HTTPMethod  string `binding:"Required;In(POST,GET)"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL     string `binding:"Required;ValidUrl"`
+	HTTPMethod     string `binding:"Required;In(POST,GET)"`
+	ContentType    int    `binding:"Required"`
+	Secret         string
+	PayloadVersion int
 	WebhookForm
 }
 
@@ -268,9 +348,10 @@ func (f *NewWebhookForm) Validate(ctx *macaron.Context, errs binding.Errors) bin
 
 // NewGogshookForm form for creating gogs hook
 type NewGogshookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL     string `binding:"Required;ValidUrl"`
+	ContentType    int    `binding:"Required"`
+	Secret         string
+	PayloadVersion int
 	WebhookForm
 }
 
@@ -389,6 +470,8 @@ type CreateIssueForm struct {
 	AssigneeID  int64
 	Content     string
 	Files       []string
+	// the file name of the issue form the issue is created from
+	Template string `form:"template"`
 }
 
 // Validate validates the fields
@@ -545,6 +628,19 @@ func (f *MergePullRequestForm) Validate(ctx *macaron.Context, errs binding.Error
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ResolvePullConflictsForm form for resolving the conflicts of a pull request,
+// the resolutions of the files are submitted by their indexes
+type ResolvePullConflictsForm struct {
+	HeadCommitID  string `binding:"Required"`
+	BaseCommitID  string `binding:"Required"`
+	CommitMessage string
+}
+
+// Validate validates the fields
+func (f *ResolvePullConflictsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // CodeCommentForm form for adding code comments for PRs
 type CodeCommentForm struct {
 	Content        string `binding:"Required"`
@@ -561,11 +657,25 @@ func (f *CodeCommentForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// CommitMessageCommentForm form for adding a comment on a line of the message of a commit of a pull request
+type CommitMessageCommentForm struct {
+	Content        string `binding:"Required"`
+	Line           int64  `binding:"Required"`
+	IsReview       bool   `form:"is_review"`
+	LatestCommitID string
+}
+
+// Validate validates the fields
+func (f *CommitMessageCommentForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // SubmitReviewForm for submitting a finished code review
 type SubmitReviewForm struct {
-	Content  string
-	Type     string `binding:"Required;In(approve,comment,reject)"`
-	CommitID string
+	Content   string
+	Type      string `binding:"Required;In(approve,comment,reject)"`
+	CommitID  string
+	Checklist []string
 }
 
 // Validate validates the fields
@@ -668,6 +778,8 @@ type EditRepoFileForm struct {
 	CommitChoice  string `binding:"Required;MaxSize(50)"`
 	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
 	LastCommit    string
+	// Stage stages the change to commit it later with other changes
+	Stage bool
 }
 
 // Validate validates the fields
@@ -675,6 +787,21 @@ func (f *EditRepoFileForm) Validate(ctx *macaron.Context, errs binding.Errors) b
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ProposeFixForm form for proposing a fix of an issue
+type ProposeFixForm struct {
+	TreePath      string `binding:"Required;MaxSize(500)"`
+	Content       string
+	CommitSummary string `binding:"MaxSize(100)"`
+	LastCommit    string
+	Title         string `binding:"Required;MaxSize(255)"`
+	Description   string
+}
+
+// Validate validates the fields
+func (f *ProposeFixForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // EditPreviewDiffForm form for changing preview diff
 type EditPreviewDiffForm struct {
 	Content string
@@ -732,6 +859,8 @@ type DeleteRepoFileForm struct {
 	CommitChoice  string `binding:"Required;MaxSize(50)"`
 	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
 	LastCommit    string
+	// Stage stages the deletion to commit it later with other changes
+	Stage bool
 }
 
 // Validate validates the fields
@@ -739,6 +868,34 @@ func (f *DeleteRepoFileForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// CommitStagedChangesForm form for committing the staged changes of files of a branch
+type CommitStagedChangesForm struct {
+	CommitSummary string `binding:"MaxSize(100)"`
+	CommitMessage string
+	CommitChoice  string `binding:"Required;MaxSize(50)"`
+	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
+}
+
+// Validate validates the fields
+func (f *CommitStagedChangesForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// CherryPickForm form for cherry-picking or reverting a commit onto a branch
+type CherryPickForm struct {
+	Revert        bool
+	CommitSummary string `binding:"MaxSize(255)"`
+	CommitMessage string
+	CommitChoice  string `binding:"Required;MaxSize(50)"`
+	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
+	LastCommit    string
+}
+
+// Validate validates the fields
+func (f *CherryPickForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ___________.__                 ___________                     __
 // \__    ___/|__| _____   ____   \__    ___/___________    ____ |  | __ ___________
 // |    |   |  |/     \_/ __ \    |    |  \_  __ \__  \ _/ ___\|  |/ // __ \_  __ \
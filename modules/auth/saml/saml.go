@@ -0,0 +1,408 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package saml
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// Source holds the configuration of a SAML 2.0 identity provider, Gitea acting as the service provider
+type Source struct {
+	// IdentityProviderMetadata is the XML metadata of the identity provider, fetched from
+	// IdentityProviderMetadataURL when it is set
+	IdentityProviderMetadata    string
+	IdentityProviderMetadataURL string
+	// ServiceProviderCertificate and ServiceProviderPrivateKey are the PEM encoded key pair used to sign
+	// the requests and decrypt the assertions, generated when the source is created
+	ServiceProviderCertificate string
+	ServiceProviderPrivateKey  string
+	// EntityID of the service provider, the URL of its metadata if empty
+	EntityID          string
+	NameIDFormat      string
+	SignRequests      bool
+	AllowIDPInitiated bool
+
+	// names or friendly names of the attributes mapped to the claims of the users,
+	// the name ID is used as username if UsernameAttribute is empty
+	UsernameAttribute string
+	EmailAttribute    string
+	FullNameAttribute string
+	GroupsAttribute   string
+	// members of AdminGroup are site administrators
+	AdminGroup string
+	// GroupTeamMap maps the groups to the teams of the organizations as JSON, e.g.
+	// {"developers": {"my-org": ["team1", "team2"]}}
+	GroupTeamMap string
+	// GroupTeamMapRemoval removes the users from the mapped teams of the groups they're not a member of
+	GroupTeamMapRemoval bool
+	AutoCreateUsers     bool
+}
+
+// Claims are the claims of a user authenticated by an identity provider
+type Claims struct {
+	NameID       string
+	SessionIndex string
+	Username     string
+	Email        string
+	FullName     string
+	Groups       []string
+}
+
+// ServiceProvider returns the service provider of the source, baseURL is the URL its endpoints are under
+func (source *Source) ServiceProvider(baseURL string) (*saml.ServiceProvider, error) {
+	if len(source.IdentityProviderMetadata) == 0 {
+		return nil, errors.New("the metadata of the identity provider is missing")
+	}
+	idpMetadata, err := ParseMetadata([]byte(source.IdentityProviderMetadata))
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair, err := parseKeyPair(source.ServiceProviderCertificate, source.ServiceProviderPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	sp := &saml.ServiceProvider{
+		EntityID:          source.EntityID,
+		Key:               keyPair.key,
+		Certificate:       keyPair.cert,
+		MetadataURL:       *base.ResolveReference(&url.URL{Path: base.Path + "/metadata"}),
+		AcsURL:            *base.ResolveReference(&url.URL{Path: base.Path + "/acs"}),
+		SloURL:            *base.ResolveReference(&url.URL{Path: base.Path + "/slo"}),
+		IDPMetadata:       idpMetadata,
+		AuthnNameIDFormat: saml.NameIDFormat(source.NameIDFormat),
+		AllowIDPInitiated: source.AllowIDPInitiated,
+	}
+	if source.SignRequests {
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+	return sp, nil
+}
+
+// Claims returns the claims of the user of a verified assertion
+func (source *Source) Claims(assertion *saml.Assertion) (*Claims, error) {
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || len(assertion.Subject.NameID.Value) == 0 {
+		return nil, errors.New("the assertion has no name ID")
+	}
+	claims := &Claims{
+		NameID:   assertion.Subject.NameID.Value,
+		Username: assertion.Subject.NameID.Value,
+	}
+	for _, statement := range assertion.AuthnStatements {
+		if len(statement.SessionIndex) > 0 {
+			claims.SessionIndex = statement.SessionIndex
+			break
+		}
+	}
+	if values := attributeValues(assertion, source.UsernameAttribute); len(values) > 0 {
+		claims.Username = values[0]
+	}
+	if values := attributeValues(assertion, source.EmailAttribute); len(values) > 0 {
+		claims.Email = values[0]
+	}
+	if values := attributeValues(assertion, source.FullNameAttribute); len(values) > 0 {
+		claims.FullName = values[0]
+	}
+	claims.Groups = attributeValues(assertion, source.GroupsAttribute)
+	return claims, nil
+}
+
+// IsAdmin returns whether the user of the claims is a member of the admin group of the source
+func (source *Source) IsAdmin(claims *Claims) bool {
+	if len(source.AdminGroup) == 0 {
+		return false
+	}
+	for _, group := range claims.Groups {
+		if group == source.AdminGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeValues returns the values of the attribute of the assertion with the name or friendly name
+func attributeValues(assertion *saml.Assertion, name string) []string {
+	if len(name) == 0 {
+		return nil
+	}
+	var values []string
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if attribute.Name != name && attribute.FriendlyName != name {
+				continue
+			}
+			for _, value := range attribute.Values {
+				if v := strings.TrimSpace(value.Value); len(v) > 0 {
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// ParseMetadata parses the metadata of an identity provider
+func ParseMetadata(data []byte) (*saml.EntityDescriptor, error) {
+	metadata := new(saml.EntityDescriptor)
+	if err := xml.Unmarshal(data, metadata); err != nil {
+		// the metadata may describe several entities, use the first identity provider
+		entities := new(saml.EntitiesDescriptor)
+		if err := xml.Unmarshal(data, entities); err != nil {
+			return nil, fmt.Errorf("invalid identity provider metadata: %v", err)
+		}
+		metadata = nil
+		for i := range entities.EntityDescriptors {
+			if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+				metadata = &entities.EntityDescriptors[i]
+				break
+			}
+		}
+	}
+	if metadata == nil || len(metadata.IDPSSODescriptors) == 0 {
+		return nil, errors.New("the metadata doesn't describe an identity provider")
+	}
+	return metadata, nil
+}
+
+// FetchMetadata downloads the metadata of an identity provider and checks it's valid
+func FetchMetadata(metadataURL string) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(metadataURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch the metadata from %s: %s", metadataURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if _, err := ParseMetadata(data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+func parseKeyPair(certPEM, keyPEM string) (*keyPair, error) {
+	certBlock, _ := pem.Decode([]byte(certPEM))
+	if certBlock == nil {
+		return nil, errors.New("invalid service provider certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service provider certificate: %v", err)
+	}
+	keyBlock, _ := pem.Decode([]byte(keyPEM))
+	if keyBlock == nil {
+		return nil, errors.New("invalid service provider private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service provider private key: %v", err)
+	}
+	return &keyPair{cert: cert, key: key}, nil
+}
+
+// GenerateKeyPair generates a self-signed certificate and its private key for a service provider, PEM encoded
+func GenerateKeyPair(commonName string) (certPEM, keyPEM string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", err
+	}
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	return certPEM, keyPEM, nil
+}
+
+// ParseResponse parses and verifies a response of the identity provider posted with the HTTP-POST binding and
+// returns its assertion. The response must contain a single assertion, so the verified assertion can't be
+// confused with another one (CVE-2022-41912).
+func ParseResponse(sp *saml.ServiceProvider, req *http.Request, possibleRequestIDs []string) (*saml.Assertion, error) {
+	data, err := base64.StdEncoding.DecodeString(req.PostFormValue("SAMLResponse"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %v", err)
+	}
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid XML: %v", err)
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("empty response")
+	}
+	if count := countAssertions(doc.Root()); count != 1 {
+		return nil, fmt.Errorf("the response has %d assertions instead of one", count)
+	}
+
+	assertion, err := sp.ParseXMLResponse(data, possibleRequestIDs)
+	if err != nil {
+		if invalidErr, ok := err.(*saml.InvalidResponseError); ok {
+			err = invalidErr.PrivateErr
+		}
+		return nil, err
+	}
+	return assertion, nil
+}
+
+// countAssertions returns the number of plain and encrypted assertions in the element and its descendants
+func countAssertions(el *etree.Element) int {
+	count := 0
+	if el.Tag == "Assertion" || el.Tag == "EncryptedAssertion" {
+		count++
+	}
+	for _, child := range el.ChildElements() {
+		count += countAssertions(child)
+	}
+	return count
+}
+
+// ParseLogoutRequest parses and verifies a logout request of the identity provider sent with the HTTP-POST
+// binding, it must be signed by the identity provider
+func ParseLogoutRequest(sp *saml.ServiceProvider, encoded string) (*saml.LogoutRequest, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %v", err)
+	}
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid XML: %v", err)
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("empty logout request")
+	}
+	if err := validateSignature(sp, doc.Root()); err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+
+	req := new(saml.LogoutRequest)
+	if err := xml.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	if req.Destination != sp.SloURL.String() {
+		return nil, fmt.Errorf("the destination %q is not %q", req.Destination, sp.SloURL.String())
+	}
+	if req.Issuer == nil || req.Issuer.Value != sp.IDPMetadata.EntityID {
+		return nil, fmt.Errorf("the issuer is not %q", sp.IDPMetadata.EntityID)
+	}
+	if req.IssueInstant.Add(saml.MaxIssueDelay).Before(saml.TimeNow()) {
+		return nil, errors.New("the logout request expired")
+	}
+	if req.NameID == nil || len(req.NameID.Value) == 0 {
+		return nil, errors.New("the logout request has no name ID")
+	}
+	return req, nil
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// idpSigningCertificates returns the certificates the identity provider signs with
+func idpSigningCertificates(metadata *saml.EntityDescriptor) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, descriptor := range metadata.IDPSSODescriptors {
+		for _, keyDescriptor := range descriptor.KeyDescriptors {
+			if keyDescriptor.Use != "" && keyDescriptor.Use != "signing" {
+				continue
+			}
+			for _, certificate := range keyDescriptor.KeyInfo.X509Data.X509Certificates {
+				der, err := base64.StdEncoding.DecodeString(whitespacePattern.ReplaceAllString(certificate.Data, ""))
+				if err != nil {
+					return nil, err
+				}
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					return nil, err
+				}
+				certs = append(certs, cert)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("the identity provider has no signing certificate")
+	}
+	return certs, nil
+}
+
+// validateSignature verifies the enveloped signature of the element with the certificates of the identity provider
+func validateSignature(sp *saml.ServiceProvider, el *etree.Element) error {
+	certs, err := idpSigningCertificates(sp.IDPMetadata)
+	if err != nil {
+		return err
+	}
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	validationContext.IdAttribute = "ID"
+
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return err
+	}
+	ctx, err = ctx.SubContext(el)
+	if err != nil {
+		return err
+	}
+	el, err = etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		return err
+	}
+	_, err = validationContext.Validate(el)
+	return err
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package saml
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBaseURL = "https://gitea.example.com/user/saml/test"
+
+func newTestIdentityProvider(t *testing.T) *saml.IdentityProvider {
+	certPEM, keyPEM, err := GenerateKeyPair("idp")
+	assert.NoError(t, err)
+	keyPair, err := parseKeyPair(certPEM, keyPEM)
+	assert.NoError(t, err)
+
+	return &saml.IdentityProvider{
+		Key:         keyPair.key,
+		Certificate: keyPair.cert,
+		MetadataURL: url.URL{Scheme: "https", Host: "idp.example.com", Path: "/metadata"},
+		SSOURL:      url.URL{Scheme: "https", Host: "idp.example.com", Path: "/sso"},
+		LogoutURL:   url.URL{Scheme: "https", Host: "idp.example.com", Path: "/slo"},
+	}
+}
+
+func newTestSource(t *testing.T, idp *saml.IdentityProvider) *Source {
+	metadata, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+	certPEM, keyPEM, err := GenerateKeyPair("test")
+	assert.NoError(t, err)
+
+	return &Source{
+		IdentityProviderMetadata:   string(metadata),
+		ServiceProviderCertificate: certPEM,
+		ServiceProviderPrivateKey:  keyPEM,
+		UsernameAttribute:          "uid",
+		EmailAttribute:             "urn:oid:0.9.2342.19200300.100.1.3",
+		FullNameAttribute:          "cn",
+		GroupsAttribute:            "groups",
+		AdminGroup:                 "admins",
+	}
+}
+
+func TestSource_ServiceProvider(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	source := newTestSource(t, idp)
+
+	sp, err := source.ServiceProvider(testBaseURL)
+	assert.NoError(t, err)
+	assert.Equal(t, testBaseURL+"/metadata", sp.MetadataURL.String())
+	assert.Equal(t, testBaseURL+"/acs", sp.AcsURL.String())
+	assert.Equal(t, testBaseURL+"/slo", sp.SloURL.String())
+	assert.Equal(t, idp.MetadataURL.String(), sp.IDPMetadata.EntityID)
+	assert.Equal(t, idp.SSOURL.String(), sp.GetSSOBindingLocation(saml.HTTPRedirectBinding))
+	assert.Empty(t, sp.SignatureMethod)
+
+	source.SignRequests = true
+	sp, err = source.ServiceProvider(testBaseURL)
+	assert.NoError(t, err)
+	assert.Equal(t, dsig.RSASHA256SignatureMethod, sp.SignatureMethod)
+
+	source.ServiceProviderPrivateKey = ""
+	_, err = source.ServiceProvider(testBaseURL)
+	assert.Error(t, err)
+
+	source.IdentityProviderMetadata = ""
+	_, err = source.ServiceProvider(testBaseURL)
+	assert.Error(t, err)
+}
+
+func TestParseMetadata(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+
+	metadata, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+	entity, err := ParseMetadata(metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, idp.MetadataURL.String(), entity.EntityID)
+
+	entities, err := xml.Marshal(&saml.EntitiesDescriptor{
+		EntityDescriptors: []saml.EntityDescriptor{{EntityID: "sp"}, *idp.Metadata()},
+	})
+	assert.NoError(t, err)
+	entity, err = ParseMetadata(entities)
+	assert.NoError(t, err)
+	assert.Equal(t, idp.MetadataURL.String(), entity.EntityID)
+
+	_, err = ParseMetadata([]byte(`<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="sp"></EntityDescriptor>`))
+	assert.Error(t, err)
+	_, err = ParseMetadata([]byte("not xml"))
+	assert.Error(t, err)
+}
+
+func TestSource_Claims(t *testing.T) {
+	source := &Source{
+		EmailAttribute:    "urn:oid:0.9.2342.19200300.100.1.3",
+		FullNameAttribute: "cn",
+		GroupsAttribute:   "groups",
+		AdminGroup:        "admins",
+	}
+	assertion := &saml.Assertion{
+		Subject:         &saml.Subject{NameID: &saml.NameID{Value: "jdoe"}},
+		AuthnStatements: []saml.AuthnStatement{{SessionIndex: "session-1"}},
+		AttributeStatements: []saml.AttributeStatement{{
+			Attributes: []saml.Attribute{
+				{Name: "urn:oid:0.9.2342.19200300.100.1.3", FriendlyName: "mail", Values: []saml.AttributeValue{{Value: "jdoe@example.com"}}},
+				{Name: "urn:oid:2.5.4.3", FriendlyName: "cn", Values: []saml.AttributeValue{{Value: "John Doe"}}},
+				{Name: "groups", Values: []saml.AttributeValue{{Value: "developers"}, {Value: " "}, {Value: "admins"}}},
+			},
+		}},
+	}
+
+	claims, err := source.Claims(assertion)
+	assert.NoError(t, err)
+	assert.Equal(t, &Claims{
+		NameID:       "jdoe",
+		SessionIndex: "session-1",
+		Username:     "jdoe",
+		Email:        "jdoe@example.com",
+		FullName:     "John Doe",
+		Groups:       []string{"developers", "admins"},
+	}, claims)
+	assert.True(t, source.IsAdmin(claims))
+
+	source.UsernameAttribute = "mail"
+	source.AdminGroup = "owners"
+	claims, err = source.Claims(assertion)
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe@example.com", claims.Username)
+	assert.False(t, source.IsAdmin(claims))
+
+	source.AdminGroup = ""
+	assert.False(t, source.IsAdmin(&Claims{}))
+
+	_, err = source.Claims(&saml.Assertion{Subject: &saml.Subject{}})
+	assert.Error(t, err)
+}
+
+// makeLogoutRequest returns a logout request of the identity provider to the service provider, encoded for the
+// HTTP-POST binding
+func makeLogoutRequest(t *testing.T, idp *saml.IdentityProvider, sp *saml.ServiceProvider, nameID string, sign bool) string {
+	issuer := &saml.ServiceProvider{
+		EntityID:    idp.MetadataURL.String(),
+		Key:         idp.Key.(*rsa.PrivateKey),
+		Certificate: idp.Certificate,
+		IDPMetadata: &saml.EntityDescriptor{EntityID: sp.MetadataURL.String()},
+	}
+	if sign {
+		issuer.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+	req, err := issuer.MakeLogoutRequest(sp.SloURL.String(), nameID)
+	assert.NoError(t, err)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.Element())
+	data, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestParseLogoutRequest(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	sp, err := newTestSource(t, idp).ServiceProvider(testBaseURL)
+	assert.NoError(t, err)
+
+	req, err := ParseLogoutRequest(sp, makeLogoutRequest(t, idp, sp, "jdoe", true))
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", req.NameID.Value)
+
+	// unsigned
+	_, err = ParseLogoutRequest(sp, makeLogoutRequest(t, idp, sp, "jdoe", false))
+	assert.Error(t, err)
+
+	// signed by another identity provider
+	other := newTestIdentityProvider(t)
+	_, err = ParseLogoutRequest(sp, makeLogoutRequest(t, other, sp, "jdoe", true))
+	assert.Error(t, err)
+
+	// sent to another service provider
+	otherSP, err := newTestSource(t, idp).ServiceProvider("https://gitea.example.com/user/saml/other")
+	assert.NoError(t, err)
+	_, err = ParseLogoutRequest(sp, makeLogoutRequest(t, idp, otherSP, "jdoe", true))
+	assert.Error(t, err)
+
+	_, err = ParseLogoutRequest(sp, "not base64")
+	assert.Error(t, err)
+}
+
+// makeResponse returns an unsigned response of the identity provider to the service provider with a signed
+// assertion, encoded for the HTTP-POST binding, the extra assertions are appended to the response as they are
+func makeResponse(t *testing.T, idp *saml.IdentityProvider, sp *saml.ServiceProvider, nameID string, extraAssertions ...*etree.Element) string {
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest("POST", sp.AcsURL.String(), nil),
+		Request:                 saml.AuthnRequest{ID: "id-request", IssueInstant: saml.TimeNow()},
+		ServiceProviderMetadata: sp.Metadata(),
+		SPSSODescriptor:         &saml.SPSSODescriptor{},
+		ACSEndpoint:             &saml.IndexedEndpoint{Binding: saml.HTTPPostBinding, Location: sp.AcsURL.String()},
+		Now:                     saml.TimeNow(),
+	}
+	assert.NoError(t, saml.DefaultAssertionMaker{}.MakeAssertion(req, &saml.Session{NameID: nameID, CreateTime: saml.TimeNow()}))
+	assert.NoError(t, req.MakeResponse())
+	if sig := req.ResponseEl.FindElement("./Signature"); sig != nil {
+		req.ResponseEl.RemoveChild(sig)
+	}
+	for _, el := range extraAssertions {
+		req.ResponseEl.AddChild(el)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.ResponseEl)
+	data, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func postResponse(encoded string) *http.Request {
+	req := httptest.NewRequest("POST", testBaseURL+"/acs", strings.NewReader(url.Values{"SAMLResponse": {encoded}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestParseResponse(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	sp, err := newTestSource(t, idp).ServiceProvider(testBaseURL)
+	assert.NoError(t, err)
+
+	assertion, err := ParseResponse(sp, postResponse(makeResponse(t, idp, sp, "jdoe")), []string{"id-request"})
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", assertion.Subject.NameID.Value)
+
+	// a second assertion, unsigned
+	forged := &saml.Assertion{
+		ID:      "id-forged",
+		Version: "2.0",
+		Issuer:  saml.Issuer{Value: idp.MetadataURL.String()},
+		Subject: &saml.Subject{NameID: &saml.NameID{Value: "admin"}},
+	}
+	_, err = ParseResponse(sp, postResponse(makeResponse(t, idp, sp, "jdoe", forged.Element())), []string{"id-request"})
+	assert.Error(t, err)
+
+	// two assertions signed by the identity provider
+	other := makeResponse(t, idp, sp, "admin")
+	data, err := base64.StdEncoding.DecodeString(other)
+	assert.NoError(t, err)
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromBytes(data))
+	signed := doc.Root().FindElement("./Assertion")
+	if assert.NotNil(t, signed) {
+		_, err = ParseResponse(sp, postResponse(makeResponse(t, idp, sp, "jdoe", signed.Copy())), []string{"id-request"})
+		assert.Error(t, err)
+	}
+
+	_, err = ParseResponse(sp, postResponse("not base64"), []string{"id-request"})
+	assert.Error(t, err)
+}
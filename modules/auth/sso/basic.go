@@ -11,8 +11,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/timeutil"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"gitea.com/macaron/macaron"
 	"gitea.com/macaron/session"
@@ -91,10 +92,10 @@ func (b *Basic) VerifyAuthData(ctx *macaron.Context, sess session.Store) *models
 			return nil
 		}
 
-		token.UpdatedUnix = timeutil.TimeStampNow()
-		if err = models.UpdateAccessToken(token); err != nil {
-			log.Error("UpdateAccessToken:  %v", err)
+		if err = models.UpdateAccessTokenLastUsed(token, ctx.RemoteAddr()); err != nil {
+			log.Error("UpdateAccessTokenLastUsed: %v", err)
 		}
+		ctx.Data["ApiToken"] = token
 	} else if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
 		log.Error("GetAccessTokenBySha: %v", err)
 	}
@@ -102,7 +103,9 @@ func (b *Basic) VerifyAuthData(ctx *macaron.Context, sess session.Store) *models
 	if u == nil {
 		u, err = models.UserSignIn(uname, passwd)
 		if err != nil {
-			if !models.IsErrUserNotExist(err) {
+			if models.IsErrUserNotExist(err) {
+				notification.NotifyAuthFailure(uname, ctx.RemoteAddr(), api.HookAuthFailureBasic)
+			} else {
 				log.Error("UserSignIn: %v", err)
 			}
 			return nil
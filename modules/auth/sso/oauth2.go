@@ -11,7 +11,6 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/timeutil"
 
 	"gitea.com/macaron/macaron"
 	"gitea.com/macaron/session"
@@ -98,11 +97,11 @@ func (o *OAuth2) userIDFromToken(ctx *macaron.Context) int64 {
 		}
 		return 0
 	}
-	t.UpdatedUnix = timeutil.TimeStampNow()
-	if err = models.UpdateAccessToken(t); err != nil {
-		log.Error("UpdateAccessToken: %v", err)
+	if err = models.UpdateAccessTokenLastUsed(t, ctx.RemoteAddr()); err != nil {
+		log.Error("UpdateAccessTokenLastUsed: %v", err)
 	}
 	ctx.Data["IsApiToken"] = true
+	ctx.Data["ApiToken"] = t
 	return t.UID
 }
 
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sso
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+
+	"gitea.com/macaron/macaron"
+	"gitea.com/macaron/session"
+)
+
+// Ensure the struct implements the interface.
+var (
+	_ SingleSignOn = &RenderOrigin{}
+)
+
+// RenderOrigin implements the SingleSignOn interface for the requests of the documents rendered on the
+// render origin, the cookies of the users aren't sent there so their links identify the users instead.
+type RenderOrigin struct {
+}
+
+// Init does nothing as the RenderOrigin implementation does not need initialization
+func (r *RenderOrigin) Init() error {
+	return nil
+}
+
+// Free does nothing as the RenderOrigin implementation does not have to release resources
+func (r *RenderOrigin) Free() error {
+	return nil
+}
+
+// IsEnabled returns true if a render origin is configured
+func (r *RenderOrigin) IsEnabled() bool {
+	return len(setting.MarkupRenderOrigin) > 0
+}
+
+// VerifyAuthData returns the user the link of a request sent to the render origin has been given to,
+// if its code is valid and hasn't expired
+func (r *RenderOrigin) VerifyAuthData(ctx *macaron.Context, sess session.Store) *models.User {
+	if !markup.IsRenderOriginRequest(ctx.Req.Request) {
+		return nil
+	}
+	uid := ctx.QueryInt64("uid")
+	code := ctx.Query("code")
+	if uid == 0 || len(code) == 0 || !markup.VerifyRenderOriginCode(ctx.Req.URL.Path, uid, code) {
+		return nil
+	}
+
+	u, err := models.GetUserByID(uid)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			log.Error("GetUserByID: %v", err)
+		}
+		return nil
+	}
+	return u
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 
 	"gitea.com/macaron/macaron"
@@ -111,5 +112,6 @@ func (r *ReverseProxy) newUser(ctx *macaron.Context) *models.User {
 		log.Error("CreateUser: %v", err)
 		return nil
 	}
+	notification.NotifyCreateUser(user, user)
 	return user
 }
@@ -6,6 +6,7 @@ package sso
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 
 	"gitea.com/macaron/macaron"
 	"gitea.com/macaron/session"
@@ -39,11 +40,25 @@ func (s *Session) IsEnabled() bool {
 
 // VerifyAuthData checks if there is a user uid stored in the session and returns the user
 // object for that uid.
-// Returns nil if there is no user uid stored in the session.
+// Returns nil if there is no user uid stored in the session, or if the SAML session the user
+// signed in with was ended by the single logout of the identity provider.
 func (s *Session) VerifyAuthData(ctx *macaron.Context, sess session.Store) *models.User {
 	user := SessionUser(sess)
-	if user != nil {
-		return user
+	if user == nil {
+		return nil
 	}
-	return nil
+	if id, ok := sess.Get("samlSession").(int64); ok {
+		active, err := models.IsSAMLSessionActive(id)
+		if err != nil {
+			log.Error("IsSAMLSessionActive: %v", err)
+			return nil
+		}
+		if !active {
+			_ = sess.Delete("uid")
+			_ = sess.Delete("uname")
+			_ = sess.Delete("samlSession")
+			return nil
+		}
+	}
+	return user
 }
@@ -27,11 +27,15 @@ import (
 //
 // The Session plugin is expected to be executed second, in order to skip authentication
 // for users that have already signed in.
+//
+// The RenderOrigin plugin only signs in the requests sent to the render origin, which don't carry any
+// cookie or credential for the other plugins.
 var ssoMethods = []SingleSignOn{
 	&OAuth2{},
 	&Session{},
 	&ReverseProxy{},
 	&Basic{},
+	&RenderOrigin{},
 }
 
 // The purpose of the following three function variables is to let the linter know that
@@ -178,6 +178,7 @@ type AccessTokenForm struct {
 	RedirectURI  string `json:"redirect_uri"`
 	Code         string `json:"code"`
 	RefreshToken string `json:"refresh_token"`
+	DeviceCode   string `json:"device_code"`
 
 	// PKCE support
 	CodeVerifier string `json:"code_verifier"`
@@ -188,6 +189,28 @@ func (f *AccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// DeviceAuthorizationForm form for starting the device authorization grant of oauth2 clients
+type DeviceAuthorizationForm struct {
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+}
+
+// Validate validates the fields
+func (f *DeviceAuthorizationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// DeviceGrantForm form for approving or denying the device authorization request of an oauth2 client
+type DeviceGrantForm struct {
+	UserCode string `binding:"Required"`
+	Granted  bool
+}
+
+// Validate validates the fields
+func (f *DeviceGrantForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //   __________________________________________.___ _______    ________  _________
 //  /   _____/\_   _____/\__    ___/\__    ___/|   |\      \  /  _____/ /   _____/
 //  \_____  \  |    __)_   |    |     |    |   |   |/   |   \/   \  ___ \_____  \
@@ -197,14 +220,15 @@ func (f *AccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 
 // UpdateProfileForm form for updating profile
 type UpdateProfileForm struct {
-	Name                string `binding:"AlphaDashDot;MaxSize(40)"`
-	FullName            string `binding:"MaxSize(100)"`
-	KeepEmailPrivate    bool
-	Website             string `binding:"ValidUrl;MaxSize(255)"`
-	Location            string `binding:"MaxSize(50)"`
-	Language            string `binding:"Size(5)"`
-	Description         string `binding:"MaxSize(255)"`
-	KeepActivityPrivate bool
+	Name                   string `binding:"AlphaDashDot;MaxSize(40)"`
+	FullName               string `binding:"MaxSize(100)"`
+	KeepEmailPrivate       bool
+	Website                string `binding:"ValidUrl;MaxSize(255)"`
+	Location               string `binding:"MaxSize(50)"`
+	Language               string `binding:"Size(5)"`
+	Description            string `binding:"MaxSize(255)"`
+	KeepActivityPrivate    bool
+	KeepReviewStatsPrivate bool
 }
 
 // Validate validates the fields
@@ -300,9 +324,27 @@ func (f *AddKeyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// SavedReplyForm form for creating or editing a saved reply
+type SavedReplyForm struct {
+	Title   string `binding:"Required;MaxSize(255)"`
+	Content string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *SavedReplyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
 	Name string `binding:"Required;MaxSize(255)"`
+	// name of one of the models.AccessTokenTemplates, empty for a token with the selected scopes
+	Template string
+	// scopes of the token, a token without scopes has full access
+	Scopes []string
+	// comma separated full names of the repositories and names of the owners the token is restricted to
+	Repositories string
+	Owners       string
 }
 
 // Validate validates the fields
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"fmt"
 	"image"
+	"image/color"
 	"image/color/palette"
+	"io"
 
 	// Enable PNG support:
 	_ "image/png"
@@ -25,20 +27,60 @@ import (
 // AvatarSize returns avatar's size
 const AvatarSize = 290
 
+// IdenticonThemes are the palettes of the generated avatars, the "random" theme picks a random
+// palette of web safe colors for each avatar
+var IdenticonThemes = map[string]func() (color.Color, []color.Color){
+	"random": func() (color.Color, []color.Color) {
+		randExtent := len(palette.WebSafe) - 32
+		rand.Seed(time.Now().UnixNano())
+		colorIndex := rand.Intn(randExtent)
+		backColorIndex := colorIndex - 1
+		if backColorIndex < 0 {
+			backColorIndex = randExtent - 1
+		}
+		return palette.WebSafe[backColorIndex], palette.WebSafe[colorIndex : colorIndex+32]
+	},
+	"pastel": func() (color.Color, []color.Color) {
+		return color.White, []color.Color{
+			color.RGBA{0xf4, 0xa6, 0xa6, 0xff},
+			color.RGBA{0xf6, 0xc9, 0x8e, 0xff},
+			color.RGBA{0xf3, 0xe1, 0x8b, 0xff},
+			color.RGBA{0xa8, 0xd8, 0xa0, 0xff},
+			color.RGBA{0x9c, 0xd3, 0xe0, 0xff},
+			color.RGBA{0xa9, 0xb8, 0xec, 0xff},
+			color.RGBA{0xcd, 0xa9, 0xe6, 0xff},
+			color.RGBA{0xef, 0xa9, 0xd0, 0xff},
+		}
+	},
+	"monochrome": func() (color.Color, []color.Color) {
+		return color.RGBA{0xf0, 0xf0, 0xf0, 0xff}, []color.Color{
+			color.RGBA{0x22, 0x22, 0x22, 0xff},
+			color.RGBA{0x44, 0x44, 0x44, 0xff},
+			color.RGBA{0x66, 0x66, 0x66, 0xff},
+			color.RGBA{0x88, 0x88, 0x88, 0xff},
+		}
+	},
+	"gitea": func() (color.Color, []color.Color) {
+		return color.White, []color.Color{
+			color.RGBA{0x60, 0x99, 0x26, 0xff},
+			color.RGBA{0x4b, 0x78, 0x1e, 0xff},
+			color.RGBA{0x7c, 0xb8, 0x3d, 0xff},
+			color.RGBA{0x38, 0x5a, 0x16, 0xff},
+		}
+	},
+}
+
 // RandomImageSize generates and returns a random avatar image unique to input data
-// in custom size (height and width).
+// in custom size (height and width), the colors are those of the IdenticonTheme setting.
 func RandomImageSize(size int, data []byte) (image.Image, error) {
-	randExtent := len(palette.WebSafe) - 32
-	rand.Seed(time.Now().UnixNano())
-	colorIndex := rand.Intn(randExtent)
-	backColorIndex := colorIndex - 1
-	if backColorIndex < 0 {
-		backColorIndex = randExtent - 1
+	theme, ok := IdenticonThemes[setting.Avatar.IdenticonTheme]
+	if !ok {
+		theme = IdenticonThemes["random"]
 	}
+	backColor, foreColors := theme()
 
 	// Define size, background, and forecolor
-	imgMaker, err := identicon.New(size,
-		palette.WebSafe[backColorIndex], palette.WebSafe[colorIndex:colorIndex+32]...)
+	imgMaker, err := identicon.New(size, backColor, foreColors...)
 	if err != nil {
 		return nil, fmt.Errorf("identicon.New: %v", err)
 	}
@@ -93,3 +135,21 @@ func Prepare(data []byte) (*image.Image, error) {
 	img = resize.Resize(AvatarSize, AvatarSize, img, resize.Bilinear)
 	return &img, nil
 }
+
+// IsValidVariantSize returns whether an avatar can be resized to the size, the variants are
+// smaller than the stored avatars
+func IsValidVariantSize(size int) bool {
+	return size > 0 && size < AvatarSize
+}
+
+// ResizeVariant decodes a stored avatar and resizes it to a variant of the size
+func ResizeVariant(r io.Reader, size int) (image.Image, error) {
+	if !IsValidVariantSize(size) {
+		return nil, fmt.Errorf("invalid avatar size: %d", size)
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("Decode: %v", err)
+	}
+	return resize.Resize(uint(size), uint(size), img, resize.Bilinear), nil
+}
@@ -6,6 +6,7 @@ package avatar
 
 import (
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
@@ -66,3 +67,28 @@ func Test_PrepareWithInvalidImageSize(t *testing.T) {
 	_, err = Prepare(data)
 	assert.EqualError(t, err, "Image width is too large: 10 > 5")
 }
+
+func Test_RandomImageThemes(t *testing.T) {
+	defer func(theme string) { setting.Avatar.IdenticonTheme = theme }(setting.Avatar.IdenticonTheme)
+
+	for theme := range IdenticonThemes {
+		setting.Avatar.IdenticonTheme = theme
+		img, err := RandomImageSize(32, []byte("gitea@local"))
+		assert.NoError(t, err, theme)
+		assert.Equal(t, 32, img.Bounds().Dx(), theme)
+	}
+}
+
+func Test_ResizeVariant(t *testing.T) {
+	f, err := os.Open("testdata/avatar.png")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	img, err := ResizeVariant(f, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, 32, img.Bounds().Dx())
+	assert.Equal(t, 32, img.Bounds().Dy())
+
+	_, err = ResizeVariant(f, AvatarSize+1)
+	assert.Error(t, err)
+}
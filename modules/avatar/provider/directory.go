@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+func init() {
+	Register(directory{})
+}
+
+// directory provides the photos of a company directory by email address, the URL of the photos is a template
+// with the {email}, {hash} and {size} placeholders
+type directory struct{}
+
+func (directory) Name() string {
+	return "directory"
+}
+
+func (directory) IsRemote() bool {
+	return len(setting.Avatar.DirectoryURL) > 0
+}
+
+// photoURL returns the URL of the photo of the email address in the directory
+func (directory) photoURL(email string, size int) string {
+	if size == base.DefaultAvatarSize {
+		size = avatar.AvatarSize
+	}
+	return strings.NewReplacer(
+		"{email}", url.QueryEscape(email),
+		"{hash}", base.HashEmail(email),
+		"{size}", strconv.Itoa(size),
+	).Replace(setting.Avatar.DirectoryURL)
+}
+
+// AvatarLink checks that the directory has a photo of the email address, the browsers load it from the directory
+func (d directory) AvatarLink(email string, size int) (string, error) {
+	if len(setting.Avatar.DirectoryURL) == 0 || len(email) == 0 {
+		return "", nil
+	}
+	link := d.photoURL(email, size)
+
+	client := &http.Client{Timeout: setting.Avatar.DirectoryTimeout}
+	resp, err := client.Get(link)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		return "", nil
+	}
+	return link, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package provider
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+func init() {
+	Register(gravatar{})
+}
+
+// gravatar provides the avatars of Gravatar or of a compatible service, and the federated avatars of libravatar
+// when they're enabled
+type gravatar struct{}
+
+func (gravatar) Name() string {
+	return "gravatar"
+}
+
+// IsRemote is set for the federated avatars, which are found through DNS lookups
+func (gravatar) IsRemote() bool {
+	return setting.EnableFederatedAvatar && setting.LibravatarService != nil
+}
+
+func (gravatar) AvatarLink(email string, size int) (string, error) {
+	var avatarURL *url.URL
+	if setting.EnableFederatedAvatar && setting.LibravatarService != nil {
+		urlStr, err := setting.LibravatarService.FromEmail(email)
+		if err != nil {
+			return "", err
+		}
+		if avatarURL, err = url.Parse(urlStr); err != nil {
+			return "", err
+		}
+	} else if setting.GravatarSourceURL != nil {
+		// copy GravatarSourceURL, because we will modify its Path.
+		copyOfGravatarSourceURL := *setting.GravatarSourceURL
+		avatarURL = &copyOfGravatarSourceURL
+		avatarURL.Path = path.Join(avatarURL.Path, base.HashEmail(email))
+	} else {
+		return "", errors.New("no Gravatar source")
+	}
+
+	vals := avatarURL.Query()
+	vals.Set("d", "identicon")
+	if size != base.DefaultAvatarSize {
+		vals.Set("s", strconv.Itoa(size))
+	}
+	avatarURL.RawQuery = vals.Encode()
+	return avatarURL.String(), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package provider resolves the links to the avatars of email addresses through the avatar providers of the
+// instance, tried in the order of the AVATAR_PROVIDERS setting.
+package provider
+
+import (
+	"strconv"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+)
+
+// Internal is the name of the provider of the avatars stored by Gitea, which stops the lookup
+const Internal = "internal"
+
+// Provider provides the avatars of email addresses
+type Provider interface {
+	// Name returns the name of the provider in the AVATAR_PROVIDERS setting
+	Name() string
+	// IsRemote returns whether the provider needs network requests to find the avatars
+	IsRemote() bool
+	// AvatarLink returns the link to the avatar of the email address of the size in pixels, or an empty string
+	// when the provider has no avatar for it
+	AvatarLink(email string, size int) (string, error)
+}
+
+var (
+	providers = make(map[string]Provider)
+	// lookups holds the cache keys of the links being looked up in the background
+	lookups = sync.NewStatusTable()
+)
+
+// Register makes a provider available by its name
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+// AvatarLink returns the link to the avatar of the email address of the first provider having one, an empty
+// string means that the avatar stored by Gitea must be used. The links are cached since the providers may need
+// network requests to find the avatars. Until a link is cached, it's looked up in the background and the remote
+// providers are skipped, so the pages don't wait for them.
+func AvatarLink(email string, size int) string {
+	if !cache.IsEnabled() || !hasRemoteProviders() {
+		link, _ := lookup(email, size, true)
+		return link
+	}
+
+	key := "AvatarProvider:" + base.HashEmail(email) + ":" + strconv.Itoa(size)
+	var link string
+	if has, err := cache.GetJSON(key, &link); err != nil {
+		log.Error("GetJSON: %v", err)
+	} else if has {
+		return link
+	}
+
+	if lookups.StartIfNotRunning(key) {
+		go func() {
+			defer lookups.Stop(key)
+			// the link isn't cached when a provider failed, so it's looked up again by the next request
+			link, err := lookup(email, size, true)
+			if err != nil {
+				return
+			}
+			if err := cache.PutJSON(key, link); err != nil {
+				log.Error("PutJSON: %v", err)
+			}
+		}()
+	}
+	link, _ = lookup(email, size, false)
+	return link
+}
+
+// hasRemoteProviders returns whether a remote provider is tried before the internal one
+func hasRemoteProviders() bool {
+	for _, name := range setting.Avatar.Providers {
+		if name == Internal {
+			return false
+		}
+		if p, ok := providers[name]; ok && p.IsRemote() {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup tries the providers in order, skipping the remote ones unless withRemote is set. The error of the first
+// provider which failed is returned along with the link of the next ones.
+func lookup(email string, size int, withRemote bool) (string, error) {
+	var firstErr error
+	for _, name := range setting.Avatar.Providers {
+		if name == Internal {
+			break
+		}
+		p, ok := providers[name]
+		if !ok || (p.IsRemote() && !withRemote) {
+			continue
+		}
+		link, err := p.AvatarLink(email, size)
+		if err != nil {
+			log.Warn("Avatar provider %s failed for %s: %v", name, email, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if len(link) > 0 {
+			return link, firstErr
+		}
+	}
+	return "", firstErr
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGravatar(t *testing.T) {
+	defer func(source *url.URL) {
+		setting.GravatarSourceURL = source
+	}(setting.GravatarSourceURL)
+
+	var err error
+	setting.GravatarSourceURL, err = url.Parse("https://secure.gravatar.com/avatar/")
+	assert.NoError(t, err)
+	link, err := gravatar{}.AvatarLink("gitea@example.com", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://secure.gravatar.com/avatar/353cbad9b58e69c96154ad99f92bedc7?d=identicon&s=100", link)
+}
+
+func TestAvatarLink(t *testing.T) {
+	defer func(providers []string, directoryURL string, source *url.URL) {
+		setting.Avatar.Providers = providers
+		setting.Avatar.DirectoryURL = directoryURL
+		setting.GravatarSourceURL = source
+	}(setting.Avatar.Providers, setting.Avatar.DirectoryURL, setting.GravatarSourceURL)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("email") != "jdoe@example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "64", r.URL.Query().Get("size"))
+		w.Header().Set("Content-Type", "image/jpeg")
+	}))
+	defer srv.Close()
+	setting.Avatar.DirectoryURL = srv.URL + "/photo?email={email}&size={size}"
+	setting.GravatarSourceURL, _ = url.Parse("https://secure.gravatar.com/avatar/")
+
+	// the photo of the directory is used when it exists, then the next providers
+	setting.Avatar.Providers = []string{"directory", "internal"}
+	assert.Equal(t, srv.URL+"/photo?email=jdoe%40example.com&size=64", AvatarLink("jdoe@example.com", 64))
+	assert.Empty(t, AvatarLink("other@example.com", 64))
+
+	setting.Avatar.Providers = []string{"directory", "gravatar"}
+	assert.Equal(t, "https://secure.gravatar.com/avatar/1f2eb59c9aa0d86bdf2d0c597d8cae88?d=identicon&s=64", AvatarLink("other@example.com", 64))
+
+	setting.Avatar.Providers = []string{"internal", "gravatar"}
+	assert.Empty(t, AvatarLink("jdoe@example.com", 64))
+}
+
+func TestAvatarLinkCached(t *testing.T) {
+	defer func(providers []string, directoryURL string, source *url.URL) {
+		setting.Avatar.Providers = providers
+		setting.Avatar.DirectoryURL = directoryURL
+		setting.GravatarSourceURL = source
+	}(setting.Avatar.Providers, setting.Avatar.DirectoryURL, setting.GravatarSourceURL)
+	assert.NoError(t, cache.NewContext())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+	}))
+	setting.Avatar.Providers = []string{"directory", "gravatar"}
+	setting.GravatarSourceURL, _ = url.Parse("https://secure.gravatar.com/avatar/")
+	const gravatarLink = "https://secure.gravatar.com/avatar/1f2eb59c9aa0d86bdf2d0c597d8cae88?d=identicon&s=64"
+	key := "AvatarProvider:" + base.HashEmail("other@example.com") + ":64"
+	wait := func() {
+		for lookups.IsRunning(key) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// the directory is down: its failure isn't cached
+	setting.Avatar.DirectoryURL = srv.URL + "/photo?email={email}"
+	srv.Close()
+	assert.Equal(t, gravatarLink, AvatarLink("other@example.com", 64))
+	wait()
+	var link string
+	has, err := cache.GetJSON(key, &link)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// the directory is looked up in the background, then its link is cached
+	srv = httptest.NewServer(srv.Config.Handler)
+	defer srv.Close()
+	setting.Avatar.DirectoryURL = srv.URL + "/photo?email={email}"
+	assert.Equal(t, gravatarLink, AvatarLink("other@example.com", 64))
+	wait()
+	assert.Equal(t, srv.URL+"/photo?email=other%40example.com", AvatarLink("other@example.com", 64))
+}
@@ -5,6 +5,7 @@
 package cache
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -132,6 +133,35 @@ func GetInt64(key string, getFunc func() (int64, error)) (int64, error) {
 	}
 }
 
+// IsEnabled returns whether the values are kept in the cache
+func IsEnabled() bool {
+	return conn != nil && setting.CacheService.TTL != 0
+}
+
+// GetJSON decodes the JSON encoded value of key into v, it returns false when no key exists in cache
+func GetJSON(key string, v interface{}) (bool, error) {
+	if !IsEnabled() || !conn.IsExist(key) {
+		return false, nil
+	}
+	value, ok := conn.Get(key).(string)
+	if !ok {
+		return false, fmt.Errorf("Unsupported cached value type: %v", conn.Get(key))
+	}
+	return true, json.Unmarshal([]byte(value), v)
+}
+
+// PutJSON puts the JSON encoding of v into the cache
+func PutJSON(key string, v interface{}) error {
+	if !IsEnabled() {
+		return nil
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return conn.Put(key, string(value), int64(setting.CacheService.TTL.Seconds()))
+}
+
 // Remove key from cache
 func Remove(key string) {
 	if conn == nil {
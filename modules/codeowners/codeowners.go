@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Paths are the paths where a CODEOWNERS file is looked for, in order
+var Paths = []string{"CODEOWNERS", ".gitea/CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule represents a line of a CODEOWNERS file, the files matching its pattern are owned by its owners
+type Rule struct {
+	Pattern string
+	// Owners are user names prefixed by @, teams as @org/team or email addresses
+	Owners []string
+
+	re *regexp.Regexp
+}
+
+// Match returns whether the rule applies to a file
+func (r *Rule) Match(treePath string) bool {
+	return r.re.MatchString(strings.TrimPrefix(treePath, "/"))
+}
+
+// Parse parses the content of a CODEOWNERS file, the lines which can't be parsed are ignored
+func Parse(content string) []*Rule {
+	var rules []*Rule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			continue
+		}
+		rules = append(rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			re:      re,
+		})
+	}
+	return rules
+}
+
+// compilePattern converts a gitignore-like pattern to a regular expression
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	// as in .gitignore a pattern with a slash other than a trailing one is relative to the root
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				if i+2 < len(pattern) && pattern[i+2] == '/' {
+					// "**/" matches any number of directories
+					b.WriteString("(.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// a pattern matching a directory owns all its files
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Owners returns the owners of a file, the last rule matching the file applies.
+// A file without owners is returned nil.
+func Owners(rules []*Rule, treePath string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Match(treePath) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
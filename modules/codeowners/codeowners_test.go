@@ -0,0 +1,61 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	rules := Parse(`# comment
+*       @user1 user2@example.com
+
+*.go    @org3/team1 # inline comment
+/docs/  @user2
+build/logs @user4
+**/vendor/** @user5
+README.md
+`)
+	if assert.Len(t, rules, 6) {
+		assert.Equal(t, "*", rules[0].Pattern)
+		assert.Equal(t, []string{"@user1", "user2@example.com"}, rules[0].Owners)
+		assert.Equal(t, []string{"@org3/team1"}, rules[1].Owners)
+		assert.Empty(t, rules[5].Owners)
+	}
+}
+
+func TestOwners(t *testing.T) {
+	rules := Parse(`*.go @gopher
+/docs/ @writer
+build/logs @builder
+config @config
+**/vendor/** @vendor
+a?c.txt @single
+`)
+	kases := map[string][]string{
+		"main.go":                   {"@gopher"},
+		"cmd/serv.go":               {"@gopher"},
+		"README.md":                 nil,
+		"docs/index.md":             {"@writer"},
+		"docs/usage/index.md":       {"@writer"},
+		"docs":                      nil,
+		"sub/docs/index.md":         nil,
+		"build/logs/out.txt":        {"@builder"},
+		"sub/build/logs/out.txt":    nil,
+		"config":                    {"@config"},
+		"sub/config/app.ini":        {"@config"},
+		"vendor/lib/a.go":           {"@vendor"},
+		"sub/vendor/lib/a.go":       {"@vendor"},
+		"abc.txt":                   {"@single"},
+		"a/c.txt":                   nil,
+		"/docs/absolute/path.md":    {"@writer"},
+		"configuration/settings.md": nil,
+	}
+	for treePath, owners := range kases {
+		assert.Equal(t, owners, Owners(rules, treePath), treePath)
+	}
+}
@@ -9,13 +9,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"unicode"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
+	"gitea.com/macaron/binding"
 	"gitea.com/macaron/csrf"
 	"gitea.com/macaron/macaron"
 )
@@ -29,22 +33,22 @@ type APIContext struct {
 // APIError is error format response
 // swagger:response error
 type APIError struct {
-	Message string `json:"message"`
-	URL     string `json:"url"`
+	// in:body
+	Body api.APIError `json:"body"`
 }
 
 // APIValidationError is error format response related to input validation
 // swagger:response validationError
 type APIValidationError struct {
-	Message string `json:"message"`
-	URL     string `json:"url"`
+	// in:body
+	Body api.APIError `json:"body"`
 }
 
 // APIInvalidTopicsError is error format response to invalid topics
 // swagger:response invalidTopicsError
 type APIInvalidTopicsError struct {
-	Topics  []string `json:"invalidTopics"`
-	Message string   `json:"message"`
+	// in:body
+	Body api.APIInvalidTopicsError `json:"body"`
 }
 
 //APIEmpty is an empty response
@@ -57,13 +61,19 @@ type APIForbiddenError struct {
 	APIError
 }
 
-//APINotFound is a not found empty response
+//APINotFound is a not found error response
 // swagger:response notFound
-type APINotFound struct{}
+type APINotFound struct {
+	// in:body
+	Body api.APIError `json:"body"`
+}
 
-//APIConflict is a conflict empty response
+//APIConflict is a conflict error response
 // swagger:response conflict
-type APIConflict struct{}
+type APIConflict struct {
+	// in:body
+	Body api.APIError `json:"body"`
+}
 
 //APIRedirect is a redirect response
 // swagger:response redirect
@@ -73,6 +83,37 @@ type APIRedirect struct{}
 // swagger:response string
 type APIString string
 
+// apiErrorCode returns the machine-readable code of the errors responded with a status
+func apiErrorCode(status int) string {
+	switch status {
+	case http.StatusUnprocessableEntity:
+		return "validation_failed"
+	case http.StatusInternalServerError:
+		return "internal_error"
+	}
+	if text := http.StatusText(status); len(text) > 0 {
+		return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+	}
+	return "error"
+}
+
+// newAPIError returns an error in the error format of the API
+func newAPIError(status int, message string, details []*api.APIErrorDetail) api.APIError {
+	return api.APIError{
+		Code:    apiErrorCode(status),
+		Message: message,
+		URL:     setting.API.SwaggerURL,
+		Version: api.APIErrorVersion,
+		Errors:  details,
+	}
+}
+
+// writeError responds with the error format of the API
+func (ctx *APIContext) writeError(status int, message string, details []*api.APIErrorDetail) {
+	apiError := newAPIError(status, message, details)
+	ctx.JSON(status, &apiError)
+}
+
 // Error responds with an error message to client with given obj as the message.
 // If status is 500, also it prints error to log.
 func (ctx *APIContext) Error(status int, title string, obj interface{}) {
@@ -91,9 +132,20 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 		}
 	}
 
-	ctx.JSON(status, APIError{
-		Message: message,
-		URL:     setting.API.SwaggerURL,
+	ctx.writeError(status, message, nil)
+}
+
+// ValidationError responds with the problems of the fields of an invalid request
+func (ctx *APIContext) ValidationError(message string, details ...*api.APIErrorDetail) {
+	ctx.writeError(http.StatusUnprocessableEntity, message, details)
+}
+
+// InvalidTopicsError responds with the problems of invalid topics, invalidTopics is also responded
+// as it was before the errors were reported in a versioned format
+func (ctx *APIContext) InvalidTopicsError(message string, invalidTopics interface{}, details ...*api.APIErrorDetail) {
+	ctx.JSON(http.StatusUnprocessableEntity, &api.APIInvalidTopicsError{
+		APIError:      newAPIError(http.StatusUnprocessableEntity, message, details),
+		InvalidTopics: invalidTopics,
 	})
 }
 
@@ -107,10 +159,7 @@ func (ctx *APIContext) InternalServerError(err error) {
 		message = err.Error()
 	}
 
-	ctx.JSON(http.StatusInternalServerError, APIError{
-		Message: message,
-		URL:     setting.API.SwaggerURL,
-	})
+	ctx.writeError(http.StatusInternalServerError, message, nil)
 }
 
 func genAPILinks(curURL *url.URL, total, pageSize, curPage int) []string {
@@ -169,10 +218,29 @@ func (ctx *APIContext) RequireCSRF() {
 	if len(headerToken) > 0 || len(formValueToken) > 0 {
 		csrf.Validate(ctx.Context.Context, ctx.csrf)
 	} else {
-		ctx.Context.Error(401, "Missing CSRF token.")
+		ctx.Error(http.StatusUnauthorized, "RequireCSRF", "Missing CSRF token.")
 	}
 }
 
+// AccessToken returns the access token the user is signed in with, or nil
+func (ctx *APIContext) AccessToken() *models.AccessToken {
+	token, _ := ctx.Data["ApiToken"].(*models.AccessToken)
+	return token
+}
+
+// IsRestrictedToken returns whether the user is signed in with an access token restricted by scopes
+// or to some repositories
+func (ctx *APIContext) IsRestrictedToken() bool {
+	token := ctx.AccessToken()
+	return token != nil && token.IsRestricted()
+}
+
+// IsUserSiteAdmin returns true if current user is a site admin, the scopes of the access
+// tokens never grant the privileges of the site administrators
+func (ctx *APIContext) IsUserSiteAdmin() bool {
+	return ctx.Context.IsUserSiteAdmin() && !ctx.IsRestrictedToken()
+}
+
 // CheckForOTP validates OTP
 func (ctx *APIContext) CheckForOTP() {
 	otpHeader := ctx.Req.Header.Get("X-Gitea-OTP")
@@ -181,16 +249,16 @@ func (ctx *APIContext) CheckForOTP() {
 		if models.IsErrTwoFactorNotEnrolled(err) {
 			return // No 2FA enrollment for this user
 		}
-		ctx.Context.Error(500)
+		ctx.InternalServerError(err)
 		return
 	}
 	ok, err := twofa.ValidateTOTP(otpHeader)
 	if err != nil {
-		ctx.Context.Error(500)
+		ctx.InternalServerError(err)
 		return
 	}
 	if !ok {
-		ctx.Context.Error(401)
+		ctx.Error(http.StatusUnauthorized, "CheckForOTP", "Invalid or missing X-Gitea-OTP header")
 		return
 	}
 }
@@ -239,7 +307,7 @@ func ReferencesGitRepo(allowEmpty bool) macaron.Handler {
 // String will replace message, errors will be added to a slice
 func (ctx *APIContext) NotFound(objs ...interface{}) {
 	var message = "Not Found"
-	var errors []string
+	var details []*api.APIErrorDetail
 	for _, obj := range objs {
 		// Ignore nil
 		if obj == nil {
@@ -247,17 +315,86 @@ func (ctx *APIContext) NotFound(objs ...interface{}) {
 		}
 
 		if err, ok := obj.(error); ok {
-			errors = append(errors, err.Error())
+			details = append(details, &api.APIErrorDetail{Code: "not_found", Message: err.Error()})
 		} else {
 			message = obj.(string)
 		}
 	}
 
-	ctx.JSON(404, map[string]interface{}{
-		"message":           message,
-		"documentation_url": setting.API.SwaggerURL,
-		"errors":            errors,
-	})
+	ctx.writeError(http.StatusNotFound, message, details)
+}
+
+// apiFieldName returns the name of a field of a form in the requests
+func apiFieldName(form reflect.Type, name string) string {
+	for form.Kind() == reflect.Ptr {
+		form = form.Elem()
+	}
+	if form.Kind() != reflect.Struct {
+		return name
+	}
+	field, ok := form.FieldByName(name)
+	if !ok {
+		return name
+	}
+	for _, key := range []string{"json", "form"} {
+		if tag := strings.Split(field.Tag.Get(key), ",")[0]; len(tag) > 0 && tag != "-" {
+			return tag
+		}
+	}
+	return name
+}
+
+// apiErrorDetailCode converts the classification of a binding error, e.g. "AlphaDashError", to a code
+func apiErrorDetailCode(classification string) string {
+	classification = strings.TrimSuffix(classification, "Error")
+	var code strings.Builder
+	for i, r := range classification {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				code.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		code.WriteRune(r)
+	}
+	return code.String()
+}
+
+// APIBind binds the request to a form like binding.Bind, but responds with the error format of the API
+// if the request is invalid
+func APIBind(form interface{}) macaron.Handler {
+	formType := reflect.TypeOf(form)
+	return func(ctx *APIContext) {
+		ctx.Invoke(binding.BindIgnErr(form))
+		errs := ctx.GetVal(reflect.TypeOf(binding.Errors{})).Interface().(binding.Errors)
+		if len(errs) == 0 {
+			return
+		}
+
+		status := http.StatusUnprocessableEntity
+		if errs.Has(binding.ERR_DESERIALIZATION) {
+			status = http.StatusBadRequest
+		} else if errs.Has(binding.ERR_CONTENT_TYPE) {
+			status = http.StatusUnsupportedMediaType
+		}
+
+		details := make([]*api.APIErrorDetail, 0, len(errs))
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			detail := &api.APIErrorDetail{
+				Code:    apiErrorDetailCode(err.Classification),
+				Message: err.Message,
+			}
+			if len(err.FieldNames) > 0 {
+				detail.Field = apiFieldName(formType, err.FieldNames[0])
+				messages = append(messages, detail.Field+": "+detail.Message)
+			} else {
+				messages = append(messages, detail.Message)
+			}
+			details = append(details, detail)
+		}
+		ctx.writeError(status, strings.Join(messages, ", "), details)
+	}
 }
 
 // RepoRefForAPI handles repository reference names when the ref name is not explicitly given
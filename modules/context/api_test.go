@@ -5,11 +5,14 @@
 package context
 
 import (
+	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +52,19 @@ func TestGenAPILinks(t *testing.T) {
 		assert.EqualValues(t, links, response)
 	}
 }
+
+func TestAPIErrorCodes(t *testing.T) {
+	assert.Equal(t, "not_found", apiErrorCode(http.StatusNotFound))
+	assert.Equal(t, "method_not_allowed", apiErrorCode(http.StatusMethodNotAllowed))
+	assert.Equal(t, "validation_failed", apiErrorCode(http.StatusUnprocessableEntity))
+	assert.Equal(t, "internal_error", apiErrorCode(http.StatusInternalServerError))
+	assert.Equal(t, "error", apiErrorCode(599))
+
+	assert.Equal(t, "required", apiErrorDetailCode("RequiredError"))
+	assert.Equal(t, "alpha_dash_dot", apiErrorDetailCode("AlphaDashDotError"))
+
+	form := reflect.TypeOf(api.CreateIssueOption{})
+	assert.Equal(t, "title", apiFieldName(form, "Title"))
+	assert.Equal(t, "title", apiFieldName(reflect.PtrTo(form), "Title"))
+	assert.Equal(t, "Unknown", apiFieldName(form, "Unknown"))
+}
@@ -6,10 +6,14 @@
 package context
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/legal"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"gitea.com/macaron/csrf"
 	"gitea.com/macaron/macaron"
@@ -74,6 +78,53 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 				ctx.Redirect(setting.AppSubURL + "/")
 				return
 			}
+
+			if setting.Legal.RequireAcceptance && !isAPIPath && ctx.Req.URL.Path != "/user/legal" && ctx.Req.URL.Path != "/user/logout" {
+				// the acceptance of the latest versions is remembered by the session to check it once
+				fingerprint := legal.Fingerprint()
+				if accepted, _ := ctx.Session.Get("legalAccepted").(string); accepted != fingerprint {
+					unaccepted, err := models.GetUnacceptedLegalPages(ctx.User.ID, legal.Pages())
+					if err != nil {
+						ctx.ServerError("GetUnacceptedLegalPages", err)
+						return
+					}
+					if len(unaccepted) > 0 {
+						if ctx.Req.URL.Path != "/user/events" {
+							ctx.SetCookie("redirect_to", setting.AppSubURL+ctx.Req.URL.RequestURI(), 0, setting.AppSubURL)
+						}
+						ctx.Redirect(setting.AppSubURL + "/user/legal")
+						return
+					}
+					if err := ctx.Session.Set("legalAccepted", fingerprint); err != nil {
+						log.Error("Session.Set: %v", err)
+					}
+				}
+			}
+
+			if !isAPIPath && !ctx.IsBasicAuth {
+				checkTwoFactorRequirement(ctx)
+				if ctx.Written() {
+					return
+				}
+			} else if ctx.Data["IsApiToken"] != true {
+				// the access tokens remain usable on the API and git over HTTP, but not the passwords and the
+				// sessions of the users who had to enroll into two-factor authentication before the end of their grace period
+				status, err := models.GetTwoFactorStatus(ctx.User)
+				if err != nil {
+					ctx.Error(500)
+					return
+				}
+				if status.IsEnforced() {
+					if isAPIPath {
+						ctx.JSON(403, map[string]string{
+							"message": "You must enroll into two-factor authentication. Enroll at: " + setting.AppURL + "user/settings/security",
+						})
+						return
+					}
+					ctx.HandleText(401, "Two-factor authentication is required, please enroll on the user settings page and use a personal access token")
+					return
+				}
+			}
 		}
 
 		// Redirect to dashboard if user tries to visit any non-login page.
@@ -151,3 +202,54 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 		}
 	}
 }
+
+// twoFactorCheckInterval is how long the compliance of the user with the two-factor authentication requirements
+// is remembered by the session, in seconds
+const twoFactorCheckInterval = 10 * 60
+
+// ResetTwoFactorCheck makes the next request check the compliance of the user with the two-factor
+// authentication requirements again, after an enrollment change
+func (ctx *Context) ResetTwoFactorCheck() {
+	if err := ctx.Session.Delete("twoFactorCheckedUnix"); err != nil {
+		log.Error("Session.Delete: %v", err)
+	}
+}
+
+// checkTwoFactorRequirement redirects the users who must enroll into two-factor authentication to the security
+// settings once their grace period has ended, they're warned during it
+func checkTwoFactorRequirement(ctx *Context) {
+	now := timeutil.TimeStampNow()
+	deadline, _ := ctx.Session.Get("twoFactorGraceDeadline").(int64)
+	checked, _ := ctx.Session.Get("twoFactorCheckedUnix").(int64)
+	if checked == 0 || int64(now)-checked > twoFactorCheckInterval {
+		status, err := models.GetTwoFactorStatus(ctx.User)
+		if err != nil {
+			ctx.ServerError("GetTwoFactorStatus", err)
+			return
+		}
+		deadline = 0
+		if !status.IsCompliant() {
+			deadline = int64(status.GraceDeadline)
+		}
+		if err := ctx.Session.Set("twoFactorGraceDeadline", deadline); err != nil {
+			log.Error("Session.Set: %v", err)
+		}
+		if err := ctx.Session.Set("twoFactorCheckedUnix", int64(now)); err != nil {
+			log.Error("Session.Set: %v", err)
+		}
+	}
+	if deadline == 0 {
+		return
+	}
+
+	if int64(now) < deadline {
+		ctx.Data["TwoFactorGraceDeadline"] = timeutil.TimeStamp(deadline)
+		return
+	}
+	path := ctx.Req.URL.Path
+	if strings.HasPrefix(path, "/user/settings/security") || path == "/user/logout" || path == "/user/events" {
+		return
+	}
+	ctx.Flash.Error(ctx.Tr("settings.twofa_required"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
+}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/legal"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -342,6 +343,7 @@ func Contexter() macaron.Handler {
 		ctx.Data["ShowFooterVersion"] = setting.ShowFooterVersion
 
 		ctx.Data["EnableSwagger"] = setting.API.EnableSwagger
+		ctx.Data["LegalPages"] = legal.Pages()
 		ctx.Data["EnableOpenIDSignIn"] = setting.Service.EnableOpenIDSignIn
 
 		c.Map(ctx)
@@ -8,6 +8,7 @@ package context
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -15,8 +16,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/redirects"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -115,7 +117,7 @@ func (r *Repository) CanCommitToBranch(doer *models.User) (CanCommitToBranchResu
 		requireSigned = protectedBranch.RequireSignedCommits
 	}
 
-	sign, keyID, _, err := r.Repository.SignCRUDAction(doer, r.Repository.RepoPath(), git.BranchPrefix+r.BranchName)
+	sign, key, _, err := r.Repository.SignCRUDAction(doer, r.Repository.RepoPath(), git.BranchPrefix+r.BranchName)
 
 	canCommit := r.CanEnableEditor() && userCanPush
 	if requireSigned {
@@ -137,7 +139,7 @@ func (r *Repository) CanCommitToBranch(doer *models.User) (CanCommitToBranchResu
 		UserCanPush:       userCanPush,
 		RequireSigned:     requireSigned,
 		WillSign:          sign,
-		SigningKey:        keyID,
+		SigningKey:        models.DisplaySigningKey(key),
 		WontSignReason:    wontSignReason,
 	}, err
 }
@@ -173,10 +175,13 @@ func (r *Repository) GetCommitsCount() (int64, error) {
 }
 
 // GetCommitGraphsCount returns cached commit count for current view
-func (r *Repository) GetCommitGraphsCount(hidePRRefs bool, branches []string, files []string) (int64, error) {
-	cacheKey := fmt.Sprintf("commits-count-%d-graph-%t-%s-%s", r.Repository.ID, hidePRRefs, branches, files)
+func (r *Repository) GetCommitGraphsCount(hidePRRefs bool, branches []string, files []string, authors []string) (int64, error) {
+	cacheKey := fmt.Sprintf("commits-count-%d-graph-%t-%s-%s-%s", r.Repository.ID, hidePRRefs, branches, files, authors)
 
 	return cache.GetInt64(cacheKey, func() (int64, error) {
+		if len(authors) > 0 {
+			return git.CommitGraphCount(r.Repository.RepoPath(), hidePRRefs, branches, files, authors)
+		}
 		if len(branches) == 0 {
 			return git.AllCommitsCount(r.Repository.RepoPath(), hidePRRefs, files...)
 		}
@@ -238,6 +243,77 @@ func (r *Repository) GetEditorconfig() (*editorconfig.Editorconfig, error) {
 	return editorconfig.Parse(reader)
 }
 
+// GetRedirects returns the redirects file definition if found in the
+// HEAD of the default repo branch.
+func (r *Repository) GetRedirects() (*redirects.Config, error) {
+	if r.GitRepo == nil {
+		return nil, nil
+	}
+	commit, err := r.GitRepo.GetBranchCommit(r.Repository.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	treeEntry, err := commit.GetTreeEntryByPath(redirects.Path)
+	if err != nil {
+		return nil, err
+	}
+	if treeEntry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+		return nil, git.ErrNotExist{ID: "", RelPath: redirects.Path}
+	}
+	reader, err := treeEntry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return redirects.Parse(content)
+}
+
+// loadRedirects returns the redirects file of the repository, nil if it has none or it is invalid
+func (r *Repository) loadRedirects() *redirects.Config {
+	config, err := r.GetRedirects()
+	if err != nil && !git.IsErrNotExist(err) {
+		log.Debug("Invalid %s in %s: %v", redirects.Path, r.Repository.FullName(), err)
+	}
+	return config
+}
+
+// NotFoundRepoPath renders the not found page of a missing path of the current ref, unless the
+// redirects file of the repository redirects the path or explains why it is missing
+func (ctx *Context) NotFoundRepoPath(title string, err error) {
+	if config := ctx.Repo.loadRedirects(); config != nil {
+		if rule, to := config.MatchPath(ctx.Repo.TreePath); rule != nil {
+			if len(to) > 0 {
+				prefix := strings.TrimSuffix(ctx.Req.URL.Path, ctx.Repo.TreePath)
+				ctx.Redirect(path.Join(setting.AppSubURL, util.PathEscapeSegments(prefix), util.PathEscapeSegments(to)), http.StatusMovedPermanently)
+				return
+			}
+			ctx.Data["NotFoundMessage"] = rule.Message
+		}
+	}
+	ctx.NotFound(title, err)
+}
+
+// notFoundRepoRef is like NotFoundRepoPath for a missing ref, a redirection to another ref
+// is temporary since the missing ref can be created again
+func (ctx *Context) notFoundRepoRef(title string, err error) {
+	if config := ctx.Repo.loadRedirects(); config != nil {
+		refPath := ctx.Params("*")
+		if rule, to := config.MatchRef(refPath); rule != nil {
+			if len(to) > 0 {
+				prefix := strings.TrimSuffix(ctx.Req.URL.Path, refPath)
+				ctx.Redirect(path.Join(setting.AppSubURL, util.PathEscapeSegments(prefix), util.PathEscapeSegments(to)))
+				return
+			}
+			ctx.Data["NotFoundMessage"] = rule.Message
+		}
+	}
+	ctx.NotFound(title, err)
+}
+
 // RetrieveBaseRepo retrieves base repository
 func RetrieveBaseRepo(ctx *Context, repo *models.Repository) {
 	// Non-fork repository will not return error in this method.
@@ -493,7 +569,8 @@ func RepoAssignment() macaron.Handler {
 		ctx.Data["DisableSSH"] = setting.SSH.Disabled
 		ctx.Data["ExposeAnonSSH"] = setting.SSH.ExposeAnonymous
 		ctx.Data["DisableHTTP"] = setting.Repository.DisableHTTPGit
-		ctx.Data["RepoSearchEnabled"] = setting.Indexer.RepoIndexerEnabled
+		// repositories are searched with git grep when the code indexer is disabled
+		ctx.Data["RepoSearchEnabled"] = true
 		ctx.Data["CloneLink"] = repo.CloneLink()
 		ctx.Data["WikiCloneLink"] = repo.WikiCloneLink()
 
@@ -797,7 +874,7 @@ func RepoRefByType(refType RepoRefType) macaron.Handler {
 						util.URLJoin(setting.AppURL, strings.Replace(ctx.Req.URL.RequestURI(), refName, ctx.Repo.Commit.ID.String(), 1))))
 				}
 			} else {
-				ctx.NotFound("RepoRef invalid repo", fmt.Errorf("branch or tag not exist: %s", refName))
+				ctx.notFoundRepoRef("RepoRef invalid repo", fmt.Errorf("branch or tag not exist: %s", refName))
 				return
 			}
 
@@ -877,7 +954,7 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 			return issueTemplates
 		}
 		for _, entry := range entries {
-			if strings.HasSuffix(entry.Name(), ".md") {
+			if issue_template.IsTemplate(entry.Name()) {
 				if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
 					log.Debug("Issue template is too large: %s", entry.Name())
 					continue
@@ -893,17 +970,12 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 					log.Debug("ReadAll: %v", err)
 					continue
 				}
-				var it api.IssueTemplate
-				content, err := markdown.ExtractMetadata(string(data), &it)
+				it, err := issue_template.Unmarshal(entry.Name(), data)
 				if err != nil {
-					log.Debug("ExtractMetadata: %v", err)
+					log.Debug("Invalid issue template %s: %v", entry.Name(), err)
 					continue
 				}
-				it.Content = content
-				it.FileName = entry.Name()
-				if it.Valid() {
-					issueTemplates = append(issueTemplates, it)
-				}
+				issueTemplates = append(issueTemplates, *it)
 			}
 		}
 		if len(issueTemplates) > 0 {
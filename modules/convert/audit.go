@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAuditEvent converts an event of the audit log to its API format
+func ToAuditEvent(event *models.AuditEvent) *api.AuditEvent {
+	return &api.AuditEvent{
+		ID:          event.ID,
+		Action:      string(event.Action),
+		DoerID:      event.DoerID,
+		DoerName:    event.DoerName,
+		IPAddress:   event.IPAddress,
+		TargetType:  event.TargetType,
+		TargetID:    event.TargetID,
+		TargetName:  event.TargetName,
+		Description: event.Description,
+		Created:     event.CreatedUnix.AsTime(),
+	}
+}
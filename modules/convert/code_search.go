@@ -0,0 +1,52 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ToCodeSearchResult converts a code search result of a repository to API format
+func ToCodeSearchResult(repo *models.Repository, result *code_indexer.Result) *api.CodeSearchResult {
+	lines := make([]*api.CodeSearchLine, len(result.Lines))
+	for i := range result.Lines {
+		lines[i] = &api.CodeSearchLine{
+			Number:  result.LineNumbers[i],
+			Content: result.Lines[i],
+		}
+	}
+
+	htmlURL := fmt.Sprintf("%s/src/commit/%s/%s", repo.HTMLURL(), result.CommitID, util.PathEscapeSegments(result.Filename))
+	if len(result.LineNumbers) > 0 {
+		htmlURL += fmt.Sprintf("#L%d-L%d", result.LineNumbers[0], result.LineNumbers[len(result.LineNumbers)-1])
+	}
+	return &api.CodeSearchResult{
+		Repository: &api.RepositoryMeta{
+			ID:       repo.ID,
+			Name:     repo.Name,
+			Owner:    repo.OwnerName,
+			FullName: repo.FullName(),
+		},
+		Path:     result.Filename,
+		Language: result.Language,
+		CommitID: result.CommitID,
+		HTMLURL:  htmlURL,
+		Lines:    lines,
+	}
+}
+
+// ToCodeSearchLanguage converts the number of files of a language matching a code search to API format
+func ToCodeSearchLanguage(language *code_indexer.SearchResultLanguages) *api.CodeSearchLanguage {
+	return &api.CodeSearchLanguage{
+		Language: language.Language,
+		Color:    language.Color,
+		Count:    language.Count,
+	}
+}
@@ -103,6 +103,18 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 	if err != nil {
 		log.Error("GetTeamNamesByID (ApprovalsWhitelistTeamIDs): %v", err)
 	}
+	protectedPathRules := make([]*api.ProtectedPathRule, 0, len(bp.ProtectedPathRules))
+	for _, rule := range bp.ProtectedPathRules {
+		team, err := models.GetTeamByID(rule.TeamID)
+		if err != nil {
+			log.Error("GetTeamByID (ProtectedPathRules): %v", err)
+			continue
+		}
+		protectedPathRules = append(protectedPathRules, &api.ProtectedPathRule{
+			Pattern: rule.Pattern,
+			Team:    team.Name,
+		})
+	}
 
 	return &api.BranchProtection{
 		BranchName:                    bp.BranchName,
@@ -126,6 +138,13 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
 		RequireSignedCommits:          bp.RequireSignedCommits,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
+		EnableMergeQueue:              bp.EnableMergeQueue,
+		RequireCodeOwnerApproval:      bp.RequireCodeOwnerApproval,
+		RequireLinearHistory:          bp.RequireLinearHistory,
+		DismissApprovalsOnPush:        bp.DismissApprovalsOnPush,
+		DismissApprovalsOnForcePush:   bp.DismissApprovalsOnForcePush,
+		ProtectedPathRules:            protectedPathRules,
+		RequireSignOff:                bp.RequireSignOff,
 		Created:                       bp.CreatedUnix.AsTime(),
 		Updated:                       bp.UpdatedUnix.AsTime(),
 	}
@@ -234,14 +253,15 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	}
 
 	return &api.Hook{
-		ID:      w.ID,
-		Type:    w.HookTaskType.Name(),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
-		Active:  w.IsActive,
-		Config:  config,
-		Events:  w.EventsArray(),
-		Updated: w.UpdatedUnix.AsTime(),
-		Created: w.CreatedUnix.AsTime(),
+		ID:             w.ID,
+		Type:           w.HookTaskType.Name(),
+		URL:            fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		Active:         w.IsActive,
+		Config:         config,
+		Events:         w.EventsArray(),
+		PayloadVersion: w.PayloadVersion,
+		Updated:        w.UpdatedUnix.AsTime(),
+		Created:        w.CreatedUnix.AsTime(),
 	}
 }
 
@@ -280,6 +300,7 @@ func ToOrganization(org *models.User) *api.Organization {
 		Location:                  org.Location,
 		Visibility:                org.Visibility.String(),
 		RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		RequireTwoFactor:          org.RequireTwoFactor,
 	}
 }
 
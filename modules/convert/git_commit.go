@@ -5,10 +5,12 @@
 package convert
 
 import (
+	"sort"
 	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitgraph"
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -158,9 +160,97 @@ func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]
 				URL: repo.APIURL() + "/git/trees/" + commit.ID.String(),
 				SHA: commit.ID.String(),
 			},
+			Trailers:  ToCommitTrailers(models.GetCommitTrailers(commit)),
+			SignedOff: commit.IsSignedOff(),
 		},
 		Author:    apiAuthor,
 		Committer: apiCommitter,
 		Parents:   apiParents,
 	}, nil
 }
+
+// ToCommitTrailers convert the trailers of a commit message to api.CommitTrailer
+func ToCommitTrailers(trailers []*models.CommitTrailer) []*api.CommitTrailer {
+	apiTrailers := make([]*api.CommitTrailer, 0, len(trailers))
+	for _, trailer := range trailers {
+		apiTrailer := &api.CommitTrailer{
+			Key:   trailer.Key,
+			Value: trailer.Value,
+		}
+		if trailer.IsIdentity() {
+			apiTrailer.Identity = &api.Identity{
+				Name:  trailer.Name,
+				Email: trailer.Email,
+			}
+		}
+		if trailer.User != nil {
+			apiTrailer.User = ToUser(trailer.User, false, false)
+		}
+		apiTrailers = append(apiTrailers, apiTrailer)
+	}
+	return apiTrailers
+}
+
+// ToCommitGraph convert a gitgraph.Graph to an api.CommitGraph, loading the commits of the graph
+func ToCommitGraph(repo *models.Repository, gitRepo *git.Repository, graph *gitgraph.Graph) (*api.CommitGraph, error) {
+	apiGraph := &api.CommitGraph{
+		Commits: make([]*api.CommitGraphCommit, 0, len(graph.Commits)),
+		Flows:   make([]*api.CommitGraphFlow, 0, len(graph.Flows)),
+	}
+	if len(graph.Commits) > 0 {
+		apiGraph.Width = graph.Width()
+		apiGraph.Height = graph.Height()
+	}
+
+	for _, c := range graph.Commits {
+		if len(c.Rev) == 0 {
+			continue
+		}
+		commit, err := gitRepo.GetCommit(c.Rev)
+		if err != nil {
+			return nil, err
+		}
+
+		refs := make([]string, 0, len(c.Refs))
+		for _, ref := range c.Refs {
+			refs = append(refs, ref.Name)
+		}
+		parents := make([]string, commit.ParentCount())
+		for i := range parents {
+			sha, _ := commit.ParentID(i)
+			parents[i] = sha.String()
+		}
+
+		apiGraph.Commits = append(apiGraph.Commits, &api.CommitGraphCommit{
+			SHA:     c.Rev,
+			HTMLURL: repo.HTMLURL() + "/commit/" + c.Rev,
+			Row:     c.Row - graph.MinRow,
+			Column:  c.Column - graph.MinColumn,
+			FlowID:  c.Flow,
+			Refs:    refs,
+			Subject: c.Subject,
+			Author:  ToCommitUser(commit.Author),
+			Parents: parents,
+		})
+	}
+
+	for _, flow := range graph.Flows {
+		apiFlow := &api.CommitGraphFlow{
+			ID:     flow.ID,
+			Color:  flow.ColorNumber,
+			Glyphs: make([]*api.CommitGraphGlyph, 0, len(flow.Glyphs)),
+		}
+		for _, glyph := range flow.Glyphs {
+			apiFlow.Glyphs = append(apiFlow.Glyphs, &api.CommitGraphGlyph{
+				Row:    glyph.Row - graph.MinRow,
+				Column: glyph.Column - graph.MinColumn,
+				Glyph:  string(glyph.Glyph),
+			})
+		}
+		apiGraph.Flows = append(apiGraph.Flows, apiFlow)
+	}
+	sort.Slice(apiGraph.Flows, func(i, j int) bool {
+		return apiGraph.Flows[i].ID < apiGraph.Flows[j].ID
+	})
+	return apiGraph, nil
+}
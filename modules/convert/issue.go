@@ -27,19 +27,21 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 	}
 
 	apiIssue := &api.Issue{
-		ID:       issue.ID,
-		URL:      issue.APIURL(),
-		HTMLURL:  issue.HTMLURL(),
-		Index:    issue.Index,
-		Poster:   ToUser(issue.Poster, false, false),
-		Title:    issue.Title,
-		Body:     issue.Content,
-		Labels:   ToLabelList(issue.Labels),
-		State:    issue.State(),
-		IsLocked: issue.IsLocked,
-		Comments: issue.NumComments,
-		Created:  issue.CreatedUnix.AsTime(),
-		Updated:  issue.UpdatedUnix.AsTime(),
+		ID:           issue.ID,
+		URL:          issue.APIURL(),
+		HTMLURL:      issue.HTMLURL(),
+		Index:        issue.Index,
+		Poster:       ToUser(issue.Poster, false, false),
+		Title:        issue.Title,
+		Body:         issue.Content,
+		Labels:       ToLabelList(issue.Labels),
+		State:        issue.State(),
+		IsLocked:     issue.IsLocked,
+		Comments:     issue.NumComments,
+		Created:      issue.CreatedUnix.AsTime(),
+		Updated:      issue.UpdatedUnix.AsTime(),
+		ParentID:     issue.ParentID,
+		TimeEstimate: issue.TimeEstimate,
 	}
 
 	apiIssue.Repo = &api.RepositoryMeta{
@@ -203,3 +205,83 @@ func ToAPIMilestone(m *models.Milestone) *api.Milestone {
 	}
 	return apiMilestone
 }
+
+// ToMilestoneStats converts MilestoneStats into API Format
+func ToMilestoneStats(stats *models.MilestoneStats) *api.MilestoneStats {
+	apiStats := &api.MilestoneStats{
+		Interval: string(stats.Interval),
+		Buckets:  make([]*api.MilestoneStatsBucket, 0, len(stats.Buckets)),
+	}
+	for _, bucket := range stats.Buckets {
+		apiStats.Buckets = append(apiStats.Buckets, &api.MilestoneStatsBucket{
+			End:           bucket.End.AsTime(),
+			OpenIssues:    bucket.OpenIssues,
+			ClosedIssues:  bucket.ClosedIssues,
+			EstimatedTime: bucket.EstimatedTime,
+			TrackedTime:   bucket.TrackedTime,
+		})
+	}
+	return apiStats
+}
+
+// ToIssueDependencyGraph converts IssueDependencyGraph into API Format
+func ToIssueDependencyGraph(graph *models.IssueDependencyGraph) *api.IssueDependencyGraph {
+	levels := graph.Levels()
+	issues := make(map[int64]*models.Issue, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		issues[issue.ID] = issue
+	}
+	blocked := make(map[int64]bool, len(graph.Dependencies))
+	apiGraph := &api.IssueDependencyGraph{
+		Nodes:     make([]*api.IssueDependencyNode, 0, len(graph.Issues)),
+		Edges:     make([]*api.IssueDependencyEdge, 0, len(graph.Dependencies)),
+		Truncated: graph.Truncated,
+	}
+	for _, dep := range graph.Dependencies {
+		if !issues[dep.DependencyID].IsClosed {
+			blocked[dep.IssueID] = true
+		}
+		apiGraph.Edges = append(apiGraph.Edges, &api.IssueDependencyEdge{
+			BlockerID: dep.DependencyID,
+			BlockedID: dep.IssueID,
+		})
+	}
+	for _, issue := range graph.Issues {
+		apiGraph.Nodes = append(apiGraph.Nodes, &api.IssueDependencyNode{
+			ID:     issue.ID,
+			Index:  issue.Index,
+			Title:  issue.Title,
+			State:  issue.State(),
+			IsPull: issue.IsPull,
+			Repo: &api.RepositoryMeta{
+				ID:       issue.Repo.ID,
+				Name:     issue.Repo.Name,
+				Owner:    issue.Repo.OwnerName,
+				FullName: issue.Repo.FullName(),
+			},
+			URL:     issue.APIURL(),
+			HTMLURL: issue.HTMLURL(),
+			Level:   levels[issue.ID],
+			Blocked: blocked[issue.ID] && !issue.IsClosed,
+		})
+	}
+	return apiGraph
+}
+
+// ToSubIssuesProgress converts the roll-up progress of sub-issues to API format
+func ToSubIssuesProgress(progress *models.SubIssuesProgress) *api.SubIssuesProgress {
+	return &api.SubIssuesProgress{
+		Total:   progress.Total,
+		Closed:  progress.Closed,
+		Percent: progress.Percent(),
+	}
+}
+
+// ToIssueReminder converts an IssueReminder to API format
+func ToIssueReminder(reminder *models.IssueReminder) *api.IssueReminder {
+	return &api.IssueReminder{
+		ID:       reminder.ID,
+		RemindAt: reminder.RemindUnix.AsTime(),
+		Created:  reminder.CreatedUnix.AsTime(),
+	}
+}
@@ -5,7 +5,10 @@
 package convert
 
 import (
+	"encoding/json"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -22,3 +25,100 @@ func ToComment(c *models.Comment) *api.Comment {
 		Updated:  c.UpdatedUnix.AsTime(),
 	}
 }
+
+// ToTimelineComment converts a models.Comment to the api.TimelineComment format
+// it assumes the Issue of the comment is loaded with its repository
+func ToTimelineComment(c *models.Comment) *api.TimelineComment {
+	if err := c.LoadPoster(); err != nil {
+		log.Error("LoadPoster: %v", err)
+		return nil
+	}
+	if err := c.LoadMilestone(); err != nil {
+		log.Error("LoadMilestone: %v", err)
+		return nil
+	}
+	if err := c.LoadAssigneeUserAndTeam(); err != nil {
+		log.Error("LoadAssigneeUserAndTeam: %v", err)
+		return nil
+	}
+	if err := c.LoadResolveDoer(); err != nil {
+		log.Error("LoadResolveDoer: %v", err)
+		return nil
+	}
+
+	comment := &api.TimelineComment{
+		ID:       c.ID,
+		Type:     c.Type.String(),
+		Poster:   ToUser(c.Poster, false, false),
+		HTMLURL:  c.HTMLURL(),
+		IssueURL: c.IssueURL(),
+		PRURL:    c.PRURL(),
+		Body:     c.Content,
+		Created:  c.CreatedUnix.AsTime(),
+		Updated:  c.UpdatedUnix.AsTime(),
+
+		OldProjectID:    c.OldProjectID,
+		ProjectID:       c.ProjectID,
+		OldTitle:        c.OldTitle,
+		NewTitle:        c.NewTitle,
+		OldRef:          c.OldRef,
+		NewRef:          c.NewRef,
+		RemovedAssignee: c.RemovedAssignee,
+		ReviewID:        c.ReviewID,
+		RefCommitSHA:    c.CommitSHA,
+	}
+
+	if c.OldMilestone != nil {
+		comment.OldMilestone = ToAPIMilestone(c.OldMilestone)
+	}
+	if c.Milestone != nil {
+		comment.Milestone = ToAPIMilestone(c.Milestone)
+	}
+	if c.Assignee != nil {
+		comment.Assignee = ToUser(c.Assignee, false, false)
+	}
+	if c.AssigneeTeam != nil {
+		comment.AssigneeTeam = ToTeam(c.AssigneeTeam)
+	}
+	if c.ResolveDoer != nil {
+		comment.ResolveDoer = ToUser(c.ResolveDoer, false, false)
+	}
+
+	switch {
+	case c.Type == models.CommentTypeLabel:
+		if err := c.LoadLabel(); err != nil {
+			log.Error("LoadLabel: %v", err)
+			return nil
+		}
+		if c.Label != nil {
+			comment.Label = ToLabel(c.Label)
+		}
+	case c.Type == models.CommentTypeAddDependency || c.Type == models.CommentTypeRemoveDependency:
+		if c.DependentIssue != nil {
+			comment.DependentIssue = ToAPIIssue(c.DependentIssue)
+		}
+	case c.Type == models.CommentTypePullPush:
+		var data models.PushActionContent
+		if err := json.Unmarshal([]byte(c.Content), &data); err != nil {
+			log.Error("Unmarshal PushActionContent: %v", err)
+			return nil
+		}
+		comment.Body = ""
+		comment.Commits = data.CommitIDs
+		comment.IsForcePush = data.IsForcePush
+	case models.CommentTypeIsRef(c.Type):
+		if c.RefIssue != nil {
+			comment.RefIssue = ToAPIIssue(c.RefIssue)
+		}
+		if c.RefComment != nil {
+			if err := c.RefComment.LoadPoster(); err != nil {
+				log.Error("LoadPoster: %v", err)
+				return nil
+			}
+			comment.RefComment = ToComment(c.RefComment)
+		}
+		comment.RefAction = c.RefAction.String()
+	}
+
+	return comment
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToIssueSLAPolicy converts a SLA policy to its API format
+func ToIssueSLAPolicy(policy *models.IssueSLAPolicy, doer *models.User) *api.IssueSLAPolicy {
+	return &api.IssueSLAPolicy{
+		ID:              policy.ID,
+		Name:            policy.Name,
+		Enabled:         policy.Enabled,
+		Label:           policy.Label,
+		IncludePulls:    policy.IncludePulls,
+		ResponseHours:   policy.ResponseHours,
+		ResolutionHours: policy.ResolutionHours,
+		BreachLabel:     policy.BreachLabel,
+		BreachComment:   policy.BreachComment,
+		Doer:            ToUser(doer, false, false),
+		Created:         policy.CreatedUnix.AsTime(),
+		Updated:         policy.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToIssueSLAReport converts the report of a SLA policy to its API format
+func ToIssueSLAReport(report *models.IssueSLAReport, doer *models.User) *api.IssueSLAReport {
+	return &api.IssueSLAReport{
+		Policy:                ToIssueSLAPolicy(report.Policy, doer),
+		Issues:                report.Issues,
+		Responded:             report.Responded,
+		ResponseBreaches:      report.ResponseBreaches,
+		Resolved:              report.Resolved,
+		ResolutionBreaches:    report.ResolutionBreaches,
+		AverageResponseTime:   report.AverageResponseTime(),
+		AverageResolutionTime: report.AverageResolutionTime(),
+	}
+}
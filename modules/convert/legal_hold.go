@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToLegalHold converts a legal hold to its API format, its attributes have to be loaded
+func ToLegalHold(hold *models.LegalHold) *api.LegalHold {
+	result := &api.LegalHold{
+		ID:      hold.ID,
+		Reason:  hold.Reason,
+		Creator: ToUser(hold.Doer, true, true),
+		Created: hold.CreatedUnix.AsTime(),
+	}
+	if hold.User != nil {
+		result.User = ToUser(hold.User, true, true)
+	}
+	if hold.Repo != nil {
+		result.Repository = ToRepo(hold.Repo, models.AccessModeAdmin)
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToProjectAutomationRule converts an automation rule of a project to its API format
+func ToProjectAutomationRule(rule *models.ProjectAutomationRule) *api.ProjectAutomationRule {
+	return &api.ProjectAutomationRule{
+		ID:        rule.ID,
+		ProjectID: rule.ProjectID,
+		Event:     string(rule.Event),
+		LabelID:   rule.LabelID,
+		BoardID:   rule.BoardID,
+		Created:   rule.CreatedUnix.AsTime(),
+	}
+}
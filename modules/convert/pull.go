@@ -138,6 +138,23 @@ func ToAPIPullRequest(pr *models.PullRequest) *api.PullRequest {
 		mergeable := !(pr.Status == models.PullRequestStatusConflict || pr.Status == models.PullRequestStatusError) && !pr.IsWorkInProgress()
 		apiPullRequest.Mergeable = mergeable
 	}
+	if err := pr.LoadBasePull(); err != nil && !models.IsErrPullRequestNotExist(err) {
+		log.Error("LoadBasePull[%d]: %v", pr.ID, err)
+		return nil
+	}
+	if pr.BasePull != nil {
+		apiPullRequest.BasePull = pr.BasePull.Index
+	}
+	stacked, err := models.GetStackedPullRequests(pr.ID)
+	if err != nil {
+		log.Error("GetStackedPullRequests[%d]: %v", pr.ID, err)
+		return nil
+	}
+	apiPullRequest.StackedPulls = make([]int64, 0, len(stacked))
+	for _, stackedPR := range stacked {
+		apiPullRequest.StackedPulls = append(apiPullRequest.StackedPulls, stackedPR.Index)
+	}
+
 	if pr.HasMerged {
 		apiPullRequest.Merged = pr.MergedUnix.AsTimePtr()
 		apiPullRequest.MergedCommitID = &pr.MergedCommitID
@@ -146,3 +163,77 @@ func ToAPIPullRequest(pr *models.PullRequest) *api.PullRequest {
 
 	return apiPullRequest
 }
+
+// ToPullViewedFile converts a models.PullViewedFile to an api.PullViewedFile
+func ToPullViewedFile(file *models.PullViewedFile, hasChanged bool) *api.PullViewedFile {
+	return &api.PullViewedFile{
+		Path:       file.TreePath,
+		HasChanged: hasChanged,
+		Viewed:     file.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToMergeQueueEntry converts a models.MergeQueueEntry to an api.MergeQueueEntry,
+// the attributes of the entry must be loaded
+func ToMergeQueueEntry(entry *models.MergeQueueEntry, position int) *api.MergeQueueEntry {
+	return &api.MergeQueueEntry{
+		Position:   position,
+		Index:      entry.Pull.Index,
+		BaseBranch: entry.BaseBranch,
+		Status:     entry.Status.String(),
+		MergeStyle: string(entry.MergeStyle),
+		EnqueuedBy: ToUser(entry.Doer, false, false),
+		TestSHA:    entry.TestSHA,
+		FailReason: entry.FailReason,
+		Created:    entry.CreatedUnix.AsTime(),
+		Updated:    entry.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPullAutoPublish converts a models.PullAutoPublish to an api.PullAutoPublish, its attributes
+// must be loaded
+func ToPullAutoPublish(ap *models.PullAutoPublish) *api.PullAutoPublish {
+	result := &api.PullAutoPublish{
+		Index:         ap.Pull.Index,
+		EnabledBy:     ToUser(ap.Doer, false, false),
+		Reviewers:     make([]*api.User, 0, len(ap.Reviewers)),
+		TeamReviewers: make([]*api.Team, 0, len(ap.TeamReviewers)),
+		Created:       ap.CreatedUnix.AsTime(),
+	}
+	for _, reviewer := range ap.Reviewers {
+		result.Reviewers = append(result.Reviewers, ToUser(reviewer, false, false))
+	}
+	for _, team := range ap.TeamReviewers {
+		result.TeamReviewers = append(result.TeamReviewers, ToTeam(team))
+	}
+	return result
+}
+
+// ToReviewEnvironment converts a models.ReviewEnvironment to an api.ReviewEnvironment,
+// the creator of the environment must be loaded
+func ToReviewEnvironment(env *models.ReviewEnvironment) *api.ReviewEnvironment {
+	return &api.ReviewEnvironment{
+		Name:        env.Name,
+		URL:         env.URL,
+		State:       api.StatusState(env.State),
+		Description: env.Description,
+		Creator:     ToUser(env.Creator, false, false),
+		Expired:     env.IsExpired,
+		Created:     env.CreatedUnix.AsTime(),
+		Updated:     env.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToReviewerStats converts a models.ReviewerStats to an api.ReviewerStats
+func ToReviewerStats(stats *models.ReviewerStats) *api.ReviewerStats {
+	return &api.ReviewerStats{
+		Reviewer:          ToUser(stats.Reviewer, false, false),
+		Reviews:           stats.Reviews,
+		Approvals:         stats.Approvals,
+		Rejections:        stats.Rejections,
+		Comments:          stats.Comments,
+		ReviewRequests:    stats.Requests,
+		ApprovalRatio:     stats.ApprovalRatio(),
+		AverageTurnaround: stats.AverageTurnaround(),
+	}
+}
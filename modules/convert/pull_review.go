@@ -35,6 +35,7 @@ func ToPullReview(r *models.Review, doer *models.User) (*api.PullReview, error)
 		Stale:             r.Stale,
 		Official:          r.Official,
 		CodeCommentsCount: r.GetCodeCommentsCount(),
+		Checklist:         r.Checklist,
 		Submitted:         r.CreatedUnix.AsTime(),
 		HTMLURL:           r.HTMLURL(),
 		HTMLPullURL:       r.Issue.HTMLURL(),
@@ -116,6 +117,23 @@ func ToPullReviewCommentList(review *models.Review, doer *models.User) ([]*api.P
 			}
 		}
 	}
+
+	for _, comment := range review.CommitMessageComments {
+		apiComments = append(apiComments, &api.PullReviewComment{
+			ID:            comment.ID,
+			Body:          comment.Content,
+			Reviewer:      ToUser(review.Reviewer, doer != nil, auth),
+			ReviewID:      review.ID,
+			Created:       comment.CreatedUnix.AsTime(),
+			Updated:       comment.UpdatedUnix.AsTime(),
+			CommitID:      comment.CommitSHA,
+			DiffHunk:      comment.Patch,
+			LineNum:       uint64(comment.Line),
+			CommitMessage: true,
+			HTMLURL:       comment.HTMLURL(),
+			HTMLPullURL:   review.Issue.HTMLURL(),
+		})
+	}
 	return apiComments, nil
 }
 
@@ -0,0 +1,21 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStorageQuota converts the storage quota of a user or an organization and its usage to their API format
+func ToStorageQuota(owner *models.User, usage *models.StorageUsage) *api.StorageQuota {
+	return &api.StorageQuota{
+		Limit:       owner.StorageQuota(),
+		Used:        usage.Total(),
+		Git:         usage.Git,
+		LFS:         usage.LFS,
+		Attachments: usage.Attachments,
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoBadge converts a badge of a repository to its API format
+func ToRepoBadge(badge *models.RepoBadge) *api.RepoBadge {
+	return &api.RepoBadge{
+		ID:       badge.ID,
+		Name:     badge.Name,
+		LinkURL:  badge.LinkURL,
+		ImageURL: badge.ImageURL,
+		Sort:     badge.Sort,
+	}
+}
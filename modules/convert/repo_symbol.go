@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ToCodeSymbol converts a symbol found at the given commit of a repository to API format
+func ToCodeSymbol(repo *models.Repository, commitID string, symbol *models.RepoSymbol) *api.CodeSymbol {
+	return &api.CodeSymbol{
+		Name:     symbol.Name,
+		Kind:     symbol.Kind,
+		Language: symbol.Language,
+		Path:     symbol.Path,
+		Line:     symbol.Line,
+		SHA:      symbol.BlobSHA,
+		HTMLURL:  fmt.Sprintf("%s/src/commit/%s/%s#L%d", repo.HTMLURL(), commitID, util.PathEscapeSegments(symbol.Path), symbol.Line),
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"encoding/json"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoUnitsTask converts a task updating the units of repositories to its API format
+func ToRepoUnitsTask(t *models.Task) (*api.RepoUnitsTask, error) {
+	task := &api.RepoUnitsTask{
+		ID:      t.ID,
+		Status:  t.Status.Name(),
+		Error:   t.Errors,
+		Created: t.Created.AsTime(),
+		Results: []*api.RepoUnitsResult{},
+	}
+	if !t.StartTime.IsZero() {
+		task.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		task.Finished = t.EndTime.AsTimePtr()
+	}
+	if len(t.Result) > 0 {
+		if err := json.Unmarshal([]byte(t.Result), &task.Results); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
@@ -44,6 +44,11 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			CloseKeywords:                    config.CloseKeywords,
+			ReopenKeywords:                   config.ReopenKeywords,
+			KeywordLanguages:                 config.KeywordLanguages,
+			DisableCrossRepoClose:            config.DisableCrossRepoClose,
+			CloseOnDefaultBranchOnly:         config.CloseOnDefaultBranchOnly,
 		}
 	} else if unit, err := repo.GetUnit(models.UnitTypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -71,6 +76,7 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	allowRebase := false
 	allowRebaseMerge := false
 	allowSquash := false
+	var reviewChecklist []string
 	if unit, err := repo.GetUnit(models.UnitTypePullRequests); err == nil {
 		config := unit.PullRequestsConfig()
 		hasPullRequests = true
@@ -79,6 +85,7 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		allowRebase = config.AllowRebase
 		allowRebaseMerge = config.AllowRebaseMerge
 		allowSquash = config.AllowSquash
+		reviewChecklist = config.ReviewChecklist
 	}
 	hasProjects := false
 	if _, err := repo.GetUnit(models.UnitTypeProjects); err == nil {
@@ -131,6 +138,9 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		AllowRebase:               allowRebase,
 		AllowRebaseMerge:          allowRebaseMerge,
 		AllowSquash:               allowSquash,
+		ReviewChecklist:           reviewChecklist,
+		License:                   repo.License,
+		HasCodeOfConduct:          repo.HasCodeOfConduct,
 		AvatarURL:                 repo.AvatarLink(),
 		Internal:                  !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
 	}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToSavedReply converts a saved reply to its API format, the owner has to be loaded
+func ToSavedReply(reply *models.SavedReply) *api.SavedReply {
+	return &api.SavedReply{
+		ID:      reply.ID,
+		Title:   reply.Title,
+		Content: reply.Content,
+		Owner:   ToUser(reply.Owner, false, false),
+		Created: reply.CreatedUnix.AsTime(),
+		Updated: reply.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToSavedReplyList converts a list of saved replies to their API format
+func ToSavedReplyList(replies []*models.SavedReply) []*api.SavedReply {
+	result := make([]*api.SavedReply, len(replies))
+	for i := range replies {
+		result[i] = ToSavedReply(replies[i])
+	}
+	return result
+}
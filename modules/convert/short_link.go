@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToShortLink converts a short link to its API format, target is the path of its permalink relative to
+// its repository, which must be loaded
+func ToShortLink(link *models.ShortLink, target string) *api.ShortLink {
+	return &api.ShortLink{
+		Token:     link.Token,
+		URL:       setting.AppURL + "s/" + link.Token,
+		Permalink: link.Repo.HTMLURL() + "/" + target,
+		Type:      link.Type.Name(),
+		CommitID:  link.CommitID,
+		Path:      link.TreePath,
+		LineStart: link.LineStart,
+		LineEnd:   link.LineEnd,
+		Created:   link.CreatedUnix.AsTime(),
+	}
+}
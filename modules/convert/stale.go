@@ -0,0 +1,44 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStalePolicy converts a stale policy to its API format
+func ToStalePolicy(policy *models.StalePolicy, doer *models.User) *api.StalePolicy {
+	return &api.StalePolicy{
+		Enabled:        policy.Enabled,
+		IncludeIssues:  policy.IncludeIssues,
+		IncludePulls:   policy.IncludePulls,
+		DaysUntilStale: policy.DaysUntilStale,
+		DaysUntilClose: policy.DaysUntilClose,
+		StaleLabel:     policy.StaleLabelName(),
+		ExemptLabels:   policy.ExemptLabelNames(),
+		WarningComment: policy.WarningComment,
+		CloseComment:   policy.CloseComment,
+		Doer:           ToUser(doer, false, false),
+		Updated:        policy.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToStaleActionList converts a list of actions taken by a stale policy to their API format,
+// the issues have to be loaded
+func ToStaleActionList(logs []*models.StaleActionLog) []*api.StaleAction {
+	result := make([]*api.StaleAction, 0, len(logs))
+	for _, log := range logs {
+		if log.Issue == nil {
+			continue
+		}
+		result = append(result, &api.StaleAction{
+			Action:  log.Action.String(),
+			Issue:   ToAPIIssue(log.Issue),
+			Created: log.CreatedUnix.AsTime(),
+		})
+	}
+	return result
+}
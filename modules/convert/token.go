@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAccessToken converts an access token to its API format, without its secret
+func ToAccessToken(t *models.AccessToken) (*api.AccessToken, error) {
+	repos, err := t.RepoNames()
+	if err != nil {
+		return nil, err
+	}
+	owners, err := t.OwnerNames()
+	if err != nil {
+		return nil, err
+	}
+	return &api.AccessToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		TokenLastEight: t.TokenLastEight,
+		Scopes:         t.Scopes(),
+		Repositories:   repos,
+		Owners:         owners,
+	}, nil
+}
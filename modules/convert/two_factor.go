@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTwoFactorStatus converts the compliance of a user with the two-factor authentication requirements to its API format
+func ToTwoFactorStatus(status *models.TwoFactorStatus) *api.TwoFactorStatus {
+	result := &api.TwoFactorStatus{
+		Required:           status.IsRequired(),
+		Enrolled:           status.Enrolled,
+		Compliant:          status.IsCompliant(),
+		Enforced:           status.IsEnforced(),
+		RequiredByInstance: status.RequiredByInstance,
+		RequiredByOrgs:     make([]string, 0, len(status.RequiredByOrgs)),
+	}
+	if status.GraceDeadline != 0 {
+		result.GraceDeadline = status.GraceDeadline.AsTimePtr()
+	}
+	for _, org := range status.RequiredByOrgs {
+		result.RequiredByOrgs = append(result.RequiredByOrgs, org.Name)
+	}
+	return result
+}
@@ -36,3 +36,24 @@ func ToUser(user *models.User, signed, authed bool) *api.User {
 	}
 	return result
 }
+
+// ToMentionUser convert models.User to api.MentionCandidate
+func ToMentionUser(user *models.User) *api.MentionCandidate {
+	return &api.MentionCandidate{
+		Type:      "user",
+		Name:      user.Name,
+		FullName:  markup.Sanitize(user.FullName),
+		AvatarURL: user.AvatarLink(),
+	}
+}
+
+// ToMentionTeam convert models.Team of the given organization to api.MentionCandidate
+func ToMentionTeam(org *models.User, team *models.Team) *api.MentionCandidate {
+	return &api.MentionCandidate{
+		Type:       "team",
+		Name:       team.Name,
+		FullName:   org.Name + "/" + team.Name,
+		AvatarURL:  org.AvatarLink(),
+		NumMembers: team.NumMembers,
+	}
+}
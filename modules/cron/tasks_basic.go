@@ -11,6 +11,8 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
+	analytics_service "code.gitea.io/gitea/services/analytics"
+	issue_service "code.gitea.io/gitea/services/issue"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 )
 
@@ -69,6 +71,20 @@ func registerArchiveCleanup() {
 	})
 }
 
+func registerIssueExportsCleanup() {
+	RegisterTaskFatal("issue_exports_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return issue_service.DeleteOldExports(ctx, realConfig.OlderThan)
+	})
+}
+
 func registerSyncExternalUsers() {
 	RegisterTaskFatal("sync_external_users", &UpdateExistingConfig{
 		BaseConfig: BaseConfig{
@@ -98,6 +114,20 @@ func registerDeletedBranchesCleanup() {
 	})
 }
 
+func registerReviewEnvironmentsCleanup() {
+	RegisterTaskFatal("review_environments_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 7 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return models.DeleteExpiredReviewEnvironments(ctx, realConfig.OlderThan)
+	})
+}
+
 func registerUpdateMigrationPosterID() {
 	RegisterTaskFatal("update_migration_poster_id", &BaseConfig{
 		Enabled:    true,
@@ -108,12 +138,61 @@ func registerUpdateMigrationPosterID() {
 	})
 }
 
+func registerDeliverIssueReminders() {
+	RegisterTaskFatal("deliver_issue_reminders", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 1m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.DeliverIssueReminders(ctx)
+	})
+}
+
+func registerMarkStaleIssues() {
+	RegisterTaskFatal("mark_stale_issues", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.ProcessStaleIssues(ctx)
+	})
+}
+
+func registerProcessIssueSLAs() {
+	RegisterTaskFatal("process_issue_slas", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.ProcessIssueSLAs(ctx)
+	})
+}
+
+func registerAggregateInstanceStats() {
+	RegisterTaskFatal("aggregate_instance_stats", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 1h",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return analytics_service.AggregateInstanceStats(ctx)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
 	registerCheckRepoStats()
 	registerArchiveCleanup()
+	registerIssueExportsCleanup()
 	registerSyncExternalUsers()
 	registerDeletedBranchesCleanup()
+	registerReviewEnvironmentsCleanup()
 	registerUpdateMigrationPosterID()
+	registerDeliverIssueReminders()
+	registerMarkStaleIssues()
+	registerProcessIssueSLAs()
+	registerAggregateInstanceStats()
 }
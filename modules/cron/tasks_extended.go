@@ -11,6 +11,8 @@ import (
 	"code.gitea.io/gitea/models"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/webhook"
 )
 
 func registerDeleteInactiveUsers() {
@@ -117,6 +119,43 @@ func registerRemoveRandomAvatars() {
 	})
 }
 
+func registerCleanupHookTaskTable() {
+	type HookTaskRetentionConfig struct {
+		BaseConfig
+		OlderThan        time.Duration
+		Archive          bool
+		ArchiveRetention time.Duration
+	}
+	RegisterTaskFatal("cleanup_hook_task_table", &HookTaskRetentionConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan:        30 * 24 * time.Hour,
+		Archive:          false,
+		ArchiveRetention: 90 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		retentionConfig := config.(*HookTaskRetentionConfig)
+		return webhook.CleanupHookTasks(ctx, retentionConfig.OlderThan, retentionConfig.Archive, retentionConfig.ArchiveRetention)
+	})
+}
+
+func registerCleanupAuditEvents() {
+	RegisterTaskFatal("cleanup_audit_events", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 365 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		_, err := models.DeleteAuditEventsBefore(timeutil.TimeStamp(time.Now().Add(-olderThanConfig.OlderThan).Unix()))
+		return err
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
 	registerDeleteRepositoryArchives()
@@ -127,4 +166,6 @@ func initExtendedTasks() {
 	registerReinitMissingRepositories()
 	registerDeleteMissingRepositories()
 	registerRemoveRandomAvatars()
+	registerCleanupHookTaskTable()
+	registerCleanupAuditEvents()
 }
@@ -9,9 +9,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"regexp"
+	"strings"
 
 	"code.gitea.io/gitea/modules/process"
 )
@@ -22,18 +24,45 @@ type BlamePart struct {
 	Lines []string
 }
 
+// BlameIgnoreRevsFileName is the name of the file listing the revisions blame should ignore
+const BlameIgnoreRevsFileName = ".git-blame-ignore-revs"
+
 // BlameReader returns part of file blame one by one
 type BlameReader struct {
-	cmd     *exec.Cmd
-	pid     int64
-	output  io.ReadCloser
-	reader  *bufio.Reader
-	lastSha *string
-	cancel  context.CancelFunc
+	cmd            *exec.Cmd
+	pid            int64
+	output         io.ReadCloser
+	reader         *bufio.Reader
+	lastSha        *string
+	cancel         context.CancelFunc
+	ignoreRevsFile string
 }
 
 var shaLineRegex = regexp.MustCompile("^([a-z0-9]{40})")
 
+var ignoreRevLineRegex = regexp.MustCompile("^[0-9a-fA-F]{40}$")
+
+// ParseBlameIgnoreRevs returns the revisions listed in the content of a .git-blame-ignore-revs file.
+// Comments, blank lines and anything that isn't a full SHA1 are skipped as git would refuse them.
+func ParseBlameIgnoreRevs(content string) []string {
+	revs := make([]string, 0, 10)
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if ignoreRevLineRegex.MatchString(line) {
+			revs = append(revs, strings.ToLower(line))
+		}
+	}
+	return revs
+}
+
+// IgnoresRevs returns true if the blame was computed ignoring some revisions
+func (r *BlameReader) IgnoresRevs() bool {
+	return len(r.ignoreRevsFile) > 0
+}
+
 // NextPart returns next part of blame (sequencial code lines with the same commit)
 func (r *BlameReader) NextPart() (*BlamePart, error) {
 	var blamePart *BlamePart
@@ -103,6 +132,10 @@ func (r *BlameReader) Close() error {
 	defer process.GetManager().Remove(r.pid)
 	r.cancel()
 
+	if len(r.ignoreRevsFile) > 0 {
+		_ = os.Remove(r.ignoreRevsFile)
+	}
+
 	_ = r.output.Close()
 
 	if err := r.cmd.Wait(); err != nil {
@@ -112,15 +145,51 @@ func (r *BlameReader) Close() error {
 	return nil
 }
 
-// CreateBlameReader creates reader for given repository, commit and file
-func CreateBlameReader(ctx context.Context, repoPath, commitID, file string) (*BlameReader, error) {
+// CreateBlameReader creates reader for given repository, commit and file.
+// The given revisions are ignored if the installed git supports it (git >= 2.23).
+func CreateBlameReader(ctx context.Context, repoPath, commitID, file string, ignoreRevs []string) (*BlameReader, error) {
 	gitRepo, err := OpenRepository(repoPath)
 	if err != nil {
 		return nil, err
 	}
 	gitRepo.Close()
 
-	return createBlameReader(ctx, repoPath, GitExecutable, "blame", commitID, "--porcelain", "--", file)
+	var ignoreRevsFile string
+	if len(ignoreRevs) > 0 && CheckGitVersionAtLeast("2.23") == nil {
+		if ignoreRevsFile, err = writeIgnoreRevsFile(ignoreRevs); err != nil {
+			return nil, err
+		}
+	}
+
+	command := []string{GitExecutable, "blame", commitID, "--porcelain"}
+	if len(ignoreRevsFile) > 0 {
+		command = append(command, "--ignore-revs-file", ignoreRevsFile)
+	}
+	command = append(command, "--", file)
+
+	reader, err := createBlameReader(ctx, repoPath, command...)
+	if err != nil {
+		if len(ignoreRevsFile) > 0 {
+			_ = os.Remove(ignoreRevsFile)
+		}
+		return nil, err
+	}
+	reader.ignoreRevsFile = ignoreRevsFile
+	return reader, nil
+}
+
+func writeIgnoreRevsFile(ignoreRevs []string) (string, error) {
+	f, err := ioutil.TempFile("", "gitea-blame-ignore-revs")
+	if err != nil {
+		return "", fmt.Errorf("TempFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(strings.Join(ignoreRevs, "\n") + "\n"); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("WriteString: %v", err)
+	}
+	return f.Name(), nil
 }
 
 func createBlameReader(ctx context.Context, dir string, command ...string) (*BlameReader, error) {
@@ -146,11 +215,10 @@ func createBlameReader(ctx context.Context, dir string, command ...string) (*Bla
 	reader := bufio.NewReader(stdout)
 
 	return &BlameReader{
-		cmd,
-		pid,
-		stdout,
-		reader,
-		nil,
-		cancel,
+		cmd:    cmd,
+		pid:    pid,
+		output: stdout,
+		reader: reader,
+		cancel: cancel,
 	}, nil
 }
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"math"
+	"time"
+)
+
+// BlameAgeBuckets is the number of buckets the commits of a blame are distributed into by age
+const BlameAgeBuckets = 10
+
+// BlameAge represents how recent a commit is compared to the other commits of the same blame
+type BlameAge struct {
+	// Score goes from 0 for the oldest to 1 for the most recent commit
+	Score float64
+	// Bucket goes from 0 for the oldest to BlameAgeBuckets-1 for the most recent commits
+	Bucket int
+}
+
+// CalcBlameAges normalizes the given commit times, indexed by commit sha, between the oldest
+// and the most recent one. If all commits have the same time they are all considered recent.
+func CalcBlameAges(times map[string]time.Time) map[string]BlameAge {
+	ages := make(map[string]BlameAge, len(times))
+	if len(times) == 0 {
+		return ages
+	}
+
+	var oldest, newest int64
+	first := true
+	for _, t := range times {
+		unix := t.Unix()
+		if first || unix < oldest {
+			oldest = unix
+		}
+		if first || unix > newest {
+			newest = unix
+		}
+		first = false
+	}
+
+	for sha, t := range times {
+		score := 1.0
+		if newest > oldest {
+			score = float64(t.Unix()-oldest) / float64(newest-oldest)
+		}
+		ages[sha] = BlameAge{
+			Score:  math.Round(score*1000) / 1000,
+			Bucket: int(math.Round(score * (BlameAgeBuckets - 1))),
+		}
+	}
+	return ages
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -142,3 +143,38 @@ func TestReadingBlameOutput(t *testing.T) {
 		assert.Equal(t, part, actualPart)
 	}
 }
+
+func TestParseBlameIgnoreRevs(t *testing.T) {
+	content := `# Reformat everything with gofmt
+4b92a6c2df28054ad766bc262f308db9f6066596
+
+CE21ED6C3490CDFAD797319CBB1145E2330A8FEF # upper case
+e2aa991e10ffd924a828ec149951f2f20eecead22
+not-a-sha
+  e2aa991e10ffd924a828ec149951f2f20eecead2  
+`
+	assert.Equal(t, []string{
+		"4b92a6c2df28054ad766bc262f308db9f6066596",
+		"ce21ed6c3490cdfad797319cbb1145e2330a8fef",
+		"e2aa991e10ffd924a828ec149951f2f20eecead2",
+	}, ParseBlameIgnoreRevs(content))
+
+	assert.Empty(t, ParseBlameIgnoreRevs(""))
+}
+
+func TestCalcBlameAges(t *testing.T) {
+	assert.Empty(t, CalcBlameAges(nil))
+
+	base := time.Unix(1500000000, 0)
+	ages := CalcBlameAges(map[string]time.Time{
+		"oldest": base,
+		"middle": base.Add(50 * time.Hour),
+		"newest": base.Add(100 * time.Hour),
+	})
+	assert.Equal(t, BlameAge{Score: 0, Bucket: 0}, ages["oldest"])
+	assert.Equal(t, BlameAge{Score: 0.5, Bucket: 5}, ages["middle"])
+	assert.Equal(t, BlameAge{Score: 1, Bucket: BlameAgeBuckets - 1}, ages["newest"])
+
+	ages = CalcBlameAges(map[string]time.Time{"only": base})
+	assert.Equal(t, BlameAge{Score: 1, Bucket: BlameAgeBuckets - 1}, ages["only"])
+}
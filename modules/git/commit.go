@@ -282,6 +282,33 @@ func AllCommitsCount(repoPath string, hidePRRefs bool, files ...string) (int64,
 	return strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
 }
 
+// CommitGraphCount returns the number of commits of the graph of the revisions, or of all the refs if
+// there is none, which touch one of the files and whose author matches one of the patterns
+func CommitGraphCount(repoPath string, hidePRRefs bool, revisions, files, authors []string) (int64, error) {
+	cmd := NewCommand("rev-list", "--count")
+	if len(revisions) == 0 {
+		if hidePRRefs {
+			cmd.AddArguments("--exclude=refs/pull/*")
+		}
+		cmd.AddArguments("--all")
+	}
+	for _, author := range authors {
+		cmd.AddArguments("--author=" + author)
+	}
+	cmd.AddArguments(revisions...)
+	if len(files) > 0 {
+		cmd.AddArguments("--")
+		cmd.AddArguments(files...)
+	}
+
+	stdout, err := cmd.RunInDir(repoPath)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+}
+
 // CommitsCountFiles returns number of total commits of until given revision.
 func CommitsCountFiles(repoPath string, revision, relpath []string) (int64, error) {
 	cmd := NewCommand("rev-list", "--count")
@@ -0,0 +1,101 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Well-known keys of commit trailers
+const (
+	TrailerCoAuthoredBy = "Co-authored-by"
+	TrailerReviewedBy   = "Reviewed-by"
+	TrailerSignedOffBy  = "Signed-off-by"
+)
+
+var (
+	trailerPattern         = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s*(.*)$`)
+	trailerIdentityPattern = regexp.MustCompile(`^(.*?)\s*<([^<>\s]+@[^<>\s]+)>$`)
+)
+
+// CommitTrailer represents a trailer of a commit message, e.g. "Signed-off-by: Name <email>"
+type CommitTrailer struct {
+	Key   string
+	Value string
+	// Name and Email are set if the value is an identity like "Name <email>"
+	Name  string
+	Email string
+}
+
+// HasKey returns if the trailer has the given key, the keys are case-insensitive
+func (t *CommitTrailer) HasKey(key string) bool {
+	return strings.EqualFold(t.Key, key)
+}
+
+// IsIdentity returns if the value of the trailer is an identity
+func (t *CommitTrailer) IsIdentity() bool {
+	return len(t.Email) > 0
+}
+
+// ParseCommitTrailers parses the trailers of a commit message. Like git, the trailers are
+// the lines of the last paragraph of the message if it only consists of "Key: value" lines,
+// the lines starting with whitespaces continue the value of the previous one.
+func ParseCommitTrailers(message string) []*CommitTrailer {
+	paragraphs := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n\n")
+	// the first paragraph is the subject of the commit
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	var trailers []*CommitTrailer
+	for _, line := range strings.Split(strings.Trim(paragraphs[len(paragraphs)-1], "\n"), "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			return nil
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(trailers) == 0 {
+				return nil
+			}
+			trailers[len(trailers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		matches := trailerPattern.FindStringSubmatch(line)
+		if matches == nil {
+			return nil
+		}
+		trailers = append(trailers, &CommitTrailer{
+			Key:   matches[1],
+			Value: strings.TrimSpace(matches[2]),
+		})
+	}
+
+	for _, trailer := range trailers {
+		if matches := trailerIdentityPattern.FindStringSubmatch(trailer.Value); matches != nil {
+			trailer.Name = matches[1]
+			trailer.Email = matches[2]
+		}
+	}
+	return trailers
+}
+
+// Trailers returns the trailers of the commit message
+func (c *Commit) Trailers() []*CommitTrailer {
+	return ParseCommitTrailers(c.CommitMessage)
+}
+
+// IsSignedOff returns if the commit message has a Signed-off-by trailer of the author of the commit,
+// as required by the Developer Certificate of Origin
+func (c *Commit) IsSignedOff() bool {
+	if c.Author == nil {
+		return false
+	}
+	for _, trailer := range c.Trailers() {
+		if trailer.HasKey(TrailerSignedOffBy) && strings.EqualFold(trailer.Email, c.Author.Email) {
+			return true
+		}
+	}
+	return false
+}
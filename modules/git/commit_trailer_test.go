@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitTrailers(t *testing.T) {
+	trailers := ParseCommitTrailers(`Fix the thing
+
+Some description: which isn't a trailer.
+
+Co-authored-by: Jane Doe <jane@example.com>
+Reviewed-by: John <john@example.com>
+Signed-off-by: Jane Doe <jane@example.com>
+Fixes: the thing
+  with a continued value
+`)
+	if assert.Len(t, trailers, 4) {
+		assert.Equal(t, &CommitTrailer{Key: "Co-authored-by", Value: "Jane Doe <jane@example.com>", Name: "Jane Doe", Email: "jane@example.com"}, trailers[0])
+		assert.True(t, trailers[1].HasKey(TrailerReviewedBy))
+		assert.Equal(t, "john@example.com", trailers[1].Email)
+		assert.True(t, trailers[2].HasKey("signed-off-by"))
+		assert.Equal(t, &CommitTrailer{Key: "Fixes", Value: "the thing with a continued value"}, trailers[3])
+		assert.False(t, trailers[3].IsIdentity())
+	}
+
+	// the subject is never a trailer
+	assert.Empty(t, ParseCommitTrailers("Signed-off-by: Jane Doe <jane@example.com>"))
+	// the last paragraph must only consist of trailers
+	assert.Empty(t, ParseCommitTrailers("Fix the thing\n\nSigned-off-by: Jane Doe <jane@example.com>\nand some text"))
+	assert.Empty(t, ParseCommitTrailers("Fix the thing\n\nSigned-off-by: Jane Doe <jane@example.com>\n\nSome text"))
+}
+
+func TestCommit_IsSignedOff(t *testing.T) {
+	commit := &Commit{
+		Author:        &Signature{Name: "Jane Doe", Email: "Jane@example.com"},
+		CommitMessage: "Fix the thing\n\nSigned-off-by: John <john@example.com>\n",
+	}
+	assert.False(t, commit.IsSignedOff())
+
+	commit.CommitMessage += "Signed-off-by: Jane Doe <jane@example.com>\n"
+	assert.True(t, commit.IsSignedOff())
+}
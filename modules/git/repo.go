@@ -41,6 +41,7 @@ type Repository struct {
 type GPGSettings struct {
 	Sign             bool
 	KeyID            string
+	Format           string
 	Email            string
 	Name             string
 	PublicKeyContent string
@@ -440,3 +441,27 @@ func GetDivergingCommits(repoPath string, baseBranch string, targetBranch string
 
 	return DivergeObject{ahead, behind}, nil
 }
+
+// GetDivergingCommitsFromRepo returns the number of commits a commit is ahead or behind a commit
+// of another repository, the objects of the other repository are read without being fetched
+func GetDivergingCommitsFromRepo(repoPath, baseRepoPath, baseCommitID, targetCommitID string) (DivergeObject, error) {
+	env := append(os.Environ(), "GIT_ALTERNATE_OBJECT_DIRECTORIES="+filepath.Join(baseRepoPath, "objects"))
+	stdout, err := NewCommand("rev-list", "--count", "--left-right", baseCommitID+"..."+targetCommitID).RunInDirTimeoutEnv(env, -1, repoPath)
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	// $(git rev-list --count --left-right master...feature) commits behind and ahead of master
+	counts := strings.Fields(string(stdout))
+	if len(counts) != 2 {
+		return DivergeObject{}, fmt.Errorf("invalid output of rev-list: %q", stdout)
+	}
+	behind, err := strconv.Atoi(counts[0])
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	ahead, err := strconv.Atoi(counts[1])
+	if err != nil {
+		return DivergeObject{}, err
+	}
+	return DivergeObject{ahead, behind}, nil
+}
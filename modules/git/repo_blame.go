@@ -4,7 +4,14 @@
 
 package git
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// maxBlameIgnoreRevsSize limits how much of a .git-blame-ignore-revs file is read
+const maxBlameIgnoreRevsSize = 1024 * 1024
 
 // FileBlame return the Blame object of file
 func (repo *Repository) FileBlame(revision, path, file string) ([]byte, error) {
@@ -22,3 +29,30 @@ func (repo *Repository) LineBlame(revision, path, file string, line uint) (*Comm
 	}
 	return repo.GetCommit(res[:40])
 }
+
+// GetBlameIgnoreRevs returns the revisions listed in the .git-blame-ignore-revs file of the given branch.
+// No error is returned if the branch has no such file.
+func (repo *Repository) GetBlameIgnoreRevs(branch string) ([]string, error) {
+	commit, err := repo.GetBranchCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := commit.GetBlobByPath(BlameIgnoreRevsFileName)
+	if err != nil {
+		if IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(dataRc, maxBlameIgnoreRevsSize))
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlameIgnoreRevs(string(content)), nil
+}
@@ -129,6 +129,22 @@ func (repo *Repository) GetDiffNumChangedFiles(base, head string) (int, error) {
 	return w.numLines, nil
 }
 
+// GetFilesChangedBetween returns the files changed by head since its merge base with base
+func (repo *Repository) GetFilesChangedBetween(base, head string) ([]string, error) {
+	stdout, err := NewCommand("diff", "-z", "--name-only", base+"..."+head).RunInDir(repo.Path)
+	if err != nil && strings.Contains(err.Error(), "no merge base") {
+		// git >= 2.28 now returns an error if base and head have become unrelated.
+		// previously it would return the results of git diff -z --name-only base head so let's try that...
+		stdout, err = NewCommand("diff", "-z", "--name-only", base, head).RunInDir(repo.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := strings.Split(stdout, "\000")
+	// the output is terminated by a NUL
+	return files[:len(files)-1], nil
+}
+
 // GetDiffShortStat counts number of changed files, number of additions and deletions
 func (repo *Repository) GetDiffShortStat(base, head string) (numFiles, totalAdditions, totalDeletions int, err error) {
 	numFiles, totalAdditions, totalDeletions, err = GetDiffShortStat(repo.Path, base+"..."+head)
@@ -190,18 +206,20 @@ func parseDiffStat(stdout string) (numFiles, totalAdditions, totalDeletions int,
 	return
 }
 
-// GetDiffOrPatch generates either diff or formatted patch data between given revisions
-func (repo *Repository) GetDiffOrPatch(base, head string, w io.Writer, formatted bool) error {
+// GetDiffOrPatch generates either diff or formatted patch data between given revisions,
+// the args like -w or -U<n> are passed to git diff only
+func (repo *Repository) GetDiffOrPatch(base, head string, w io.Writer, formatted bool, args ...string) error {
 	if formatted {
 		return repo.GetPatch(base, head, w)
 	}
-	return repo.GetDiff(base, head, w)
+	return repo.GetDiff(base, head, w, args...)
 }
 
-// GetDiff generates and returns patch data between given revisions.
-func (repo *Repository) GetDiff(base, head string, w io.Writer) error {
-	return NewCommand("diff", "-p", "--binary", base, head).
-		RunInDirPipeline(repo.Path, w, nil)
+// GetDiff generates and returns patch data between given revisions,
+// the args like -w or -U<n> are passed to git diff.
+func (repo *Repository) GetDiff(base, head string, w io.Writer, args ...string) error {
+	cmdArgs := append(append([]string{"diff", "-p", "--binary"}, args...), base, head)
+	return NewCommand(cmdArgs...).RunInDirPipeline(repo.Path, w, nil)
 }
 
 // GetPatch generates and returns format-patch data between given revisions.
@@ -227,3 +245,81 @@ func (repo *Repository) GetDiffFromMergeBase(base, head string, w io.Writer) err
 	}
 	return err
 }
+
+// GetDiffOfFiles generates and returns patch data of some files between given revisions,
+// the old and the new names of a renamed file must both be given. The args are passed to git diff.
+func (repo *Repository) GetDiffOfFiles(base, head string, w io.Writer, files []string, args ...string) error {
+	cmdArgs := append(append([]string{"diff", "-p", "--binary", "-M"}, args...), base, head, "--")
+	return NewCommand(append(cmdArgs, files...)...).RunInDirPipeline(repo.Path, w, nil)
+}
+
+// DiffFileStat represents the changes of a file between two revisions
+type DiffFileStat struct {
+	Name string
+	// OldName is the previous name of a renamed or copied file
+	OldName string
+	// Status is the status letter of git, e.g. A, M, D or R
+	Status    byte
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// GetDiffFileStats returns the files changed between given revisions with their numbers of
+// added and deleted lines, the renames are detected
+func (repo *Repository) GetDiffFileStats(base, head string) ([]*DiffFileStat, error) {
+	stdout, err := NewCommand("diff", "-z", "--name-status", "-M", base, head).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(strings.TrimSuffix(string(stdout), "\x00"), "\x00")
+	stats := make([]*DiffFileStat, 0, len(fields)/2)
+	byName := make(map[string]*DiffFileStat, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if len(fields[i]) == 0 {
+			return nil, fmt.Errorf("unexpected output of diff --name-status: %q", stdout)
+		}
+		stat := &DiffFileStat{Status: fields[i][0], Name: fields[i+1]}
+		if stat.Status == 'R' || stat.Status == 'C' {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unexpected output of diff --name-status: %q", stdout)
+			}
+			stat.OldName, stat.Name = fields[i+1], fields[i+2]
+			i++
+		}
+		stats = append(stats, stat)
+		byName[stat.Name] = stat
+	}
+
+	stdout, err = NewCommand("diff", "-z", "--numstat", "-M", base, head).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	fields = strings.Split(strings.TrimSuffix(string(stdout), "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected output of diff --numstat: %q", stdout)
+		}
+		name := parts[2]
+		if len(name) == 0 {
+			// the names of a renamed file follow
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unexpected output of diff --numstat: %q", stdout)
+			}
+			name = fields[i+2]
+			i += 2
+		}
+		stat, ok := byName[name]
+		if !ok {
+			continue
+		}
+		if parts[0] == "-" {
+			stat.IsBinary = true
+			continue
+		}
+		stat.Additions, _ = strconv.Atoi(parts[0])
+		stat.Deletions, _ = strconv.Atoi(parts[1])
+	}
+	return stats, nil
+}
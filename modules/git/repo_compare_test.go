@@ -31,3 +31,25 @@ func TestGetFormatPatch(t *testing.T) {
 	assert.Regexp(t, "^From 8d92fc95", patch)
 	assert.Contains(t, patch, "Subject: [PATCH] Add file2.txt")
 }
+
+func TestGetDiffFileStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	stats, err := repo.GetDiffFileStats("95bb4d3", "8006ff9")
+	assert.NoError(t, err)
+	assert.Equal(t, []*DiffFileStat{
+		{Name: "file2.txt", Status: 'A', Additions: 1},
+		{Name: "foo/bar/link_to_hello", Status: 'A', Additions: 1},
+		{Name: "foo/nar/hello", Status: 'A', Additions: 1},
+	}, stats)
+
+	stats, err = repo.GetDiffFileStats("8006ff9", "95bb4d3")
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 3) {
+		assert.EqualValues(t, 'D', stats[0].Status)
+		assert.Equal(t, 1, stats[0].Deletions)
+	}
+}
@@ -7,13 +7,48 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"code.gitea.io/gitea/modules/process"
 )
 
-// LoadPublicKeyContent will load the key from gpg
+const (
+	// SigningKeyFormatOpenPGP is the format of the OpenPGP keys, the commits are signed by gpg
+	SigningKeyFormatOpenPGP = "openpgp"
+	// SigningKeyFormatSSH is the format of the SSH keys, the commits are signed by ssh-keygen
+	SigningKeyFormatSSH = "ssh"
+)
+
+// SigningKey represents the key signing the commits, the ID of an SSH key is the path of its key file
+// or its public key prefixed with "key::"
+type SigningKey struct {
+	KeyID  string
+	Format string
+}
+
+// Env returns the environment of the git commands signing with the key, SSH keys need Git 2.34 or later
+func (key *SigningKey) Env() []string {
+	if key == nil || key.Format != SigningKeyFormatSSH {
+		return nil
+	}
+	return []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=gpg.format", "GIT_CONFIG_VALUE_0=" + SigningKeyFormatSSH}
+}
+
+// ParseSigningKeyFormat returns the format of a signing key from the value of gpg.format
+func ParseSigningKeyFormat(value string) string {
+	if strings.ToLower(strings.TrimSpace(value)) == SigningKeyFormatSSH {
+		return SigningKeyFormatSSH
+	}
+	return SigningKeyFormatOpenPGP
+}
+
+// LoadPublicKeyContent will load the key from gpg, the public key of an SSH key is read from its key file
 func (gpgSettings *GPGSettings) LoadPublicKeyContent() error {
+	if gpgSettings.Format == SigningKeyFormatSSH {
+		return gpgSettings.loadSSHPublicKeyContent()
+	}
+
 	content, stderr, err := process.GetManager().Exec(
 		"gpg -a --export",
 		"gpg", "-a", "--export", gpgSettings.KeyID)
@@ -24,6 +59,31 @@ func (gpgSettings *GPGSettings) LoadPublicKeyContent() error {
 	return nil
 }
 
+func (gpgSettings *GPGSettings) loadSSHPublicKeyContent() error {
+	if strings.HasPrefix(gpgSettings.KeyID, "key::") {
+		gpgSettings.PublicKeyContent = strings.TrimPrefix(gpgSettings.KeyID, "key::")
+		return nil
+	}
+	content, err := ioutil.ReadFile(gpgSettings.KeyID)
+	if err != nil {
+		return fmt.Errorf("Unable to read SSH signing key: %s, %v", gpgSettings.KeyID, err)
+	}
+	if !strings.Contains(string(content), "PRIVATE KEY") {
+		gpgSettings.PublicKeyContent = strings.TrimSpace(string(content))
+		return nil
+	}
+
+	// the public key is derived from the private key
+	stdout, stderr, err := process.GetManager().Exec(
+		"ssh-keygen -y -f",
+		"ssh-keygen", "-y", "-f", gpgSettings.KeyID)
+	if err != nil {
+		return fmt.Errorf("Unable to get SSH signing key: %s, %s, %v", gpgSettings.KeyID, stderr, err)
+	}
+	gpgSettings.PublicKeyContent = strings.TrimSpace(stdout)
+	return nil
+}
+
 // GetDefaultPublicGPGKey will return and cache the default public GPG settings for this repository
 func (repo *Repository) GetDefaultPublicGPGKey(forceUpdate bool) (*GPGSettings, error) {
 	if repo.gpgSettings != nil && !forceUpdate {
@@ -45,6 +105,9 @@ func (repo *Repository) GetDefaultPublicGPGKey(forceUpdate bool) (*GPGSettings,
 	signingKey, _ := NewCommand("config", "--get", "user.signingkey").RunInDir(repo.Path)
 	gpgSettings.KeyID = strings.TrimSpace(signingKey)
 
+	format, _ := NewCommand("config", "--get", "gpg.format").RunInDir(repo.Path)
+	gpgSettings.Format = ParseSigningKeyFormat(format)
+
 	defaultEmail, _ := NewCommand("config", "--get", "user.email").RunInDir(repo.Path)
 	gpgSettings.Email = strings.TrimSpace(defaultEmail)
 
@@ -56,7 +56,7 @@ func (repo *Repository) GetTree(idStr string) (*Tree, error) {
 type CommitTreeOpts struct {
 	Parents    []string
 	Message    string
-	KeyID      string
+	Key        *SigningKey
 	NoGPGSign  bool
 	AlwaysSign bool
 }
@@ -89,8 +89,11 @@ func (repo *Repository) CommitTree(author *Signature, committer *Signature, tree
 	_, _ = messageBytes.WriteString(opts.Message)
 	_, _ = messageBytes.WriteString("\n")
 
-	if CheckGitVersionAtLeast("1.7.9") == nil && (opts.KeyID != "" || opts.AlwaysSign) {
-		cmd.AddArguments(fmt.Sprintf("-S%s", opts.KeyID))
+	if CheckGitVersionAtLeast("1.7.9") == nil && opts.Key != nil {
+		cmd.AddArguments(fmt.Sprintf("-S%s", opts.Key.KeyID))
+		env = append(env, opts.Key.Env()...)
+	} else if CheckGitVersionAtLeast("1.7.9") == nil && opts.AlwaysSign {
+		cmd.AddArguments("-S")
 	}
 
 	if CheckGitVersionAtLeast("2.0.0") == nil && opts.NoGPGSign {
@@ -16,15 +16,19 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 )
 
-// GetCommitGraph return a list of commit (GraphItems) from all branches
-func GetCommitGraph(r *git.Repository, page int, maxAllowedColors int, hidePRRefs bool, branches, files []string) (*Graph, error) {
+// GetCommitGraph return a list of commit (GraphItems) from all branches, a page has
+// setting.UI.GraphMaxCommitNum commits unless pageSize is positive
+func GetCommitGraph(r *git.Repository, page, pageSize int, maxAllowedColors int, hidePRRefs bool, branches, files, authors []string) (*Graph, error) {
 	format := "DATA:%D|%H|%ad|%h|%s"
 
 	if page == 0 {
 		page = 1
 	}
+	if pageSize <= 0 {
+		pageSize = setting.UI.GraphMaxCommitNum
+	}
 
-	args := make([]string, 0, 12+len(branches)+len(files))
+	args := make([]string, 0, 12+len(branches)+len(files)+len(authors))
 
 	args = append(args, "--graph", "--date-order", "--decorate=full")
 
@@ -39,10 +43,14 @@ func GetCommitGraph(r *git.Repository, page int, maxAllowedColors int, hidePRRef
 	args = append(args,
 		"-C",
 		"-M",
-		fmt.Sprintf("-n %d", setting.UI.GraphMaxCommitNum*page),
+		fmt.Sprintf("-n %d", pageSize*page),
 		"--date=iso",
 		fmt.Sprintf("--pretty=format:%s", format))
 
+	for _, author := range authors {
+		args = append(args, "--author="+author)
+	}
+
 	if len(branches) > 0 {
 		args = append(args, branches...)
 	}
@@ -60,7 +68,7 @@ func GetCommitGraph(r *git.Repository, page int, maxAllowedColors int, hidePRRef
 	if err != nil {
 		return nil, err
 	}
-	commitsToSkip := setting.UI.GraphMaxCommitNum * (page - 1)
+	commitsToSkip := pageSize * (page - 1)
 
 	scanner := bufio.NewScanner(stdoutReader)
 
@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package highlight
+
+import (
+	"github.com/alecthomas/chroma/lexers"
+)
+
+// monacoLanguages maps the names of the chroma lexers to the ids of the languages of the Monaco editor
+var monacoLanguages = map[string]string{
+	"ABAP":                   "abap",
+	"Bash":                   "shell",
+	"Batchfile":              "bat",
+	"C":                      "c",
+	"C#":                     "csharp",
+	"C++":                    "cpp",
+	"Clojure":                "clojure",
+	"CoffeeScript":           "coffeescript",
+	"CSS":                    "css",
+	"Dart":                   "dart",
+	"Docker":                 "dockerfile",
+	"FSharp":                 "fsharp",
+	"Go":                     "go",
+	"GraphQL":                "graphql",
+	"Handlebars":             "handlebars",
+	"HTML":                   "html",
+	"INI":                    "ini",
+	"Java":                   "java",
+	"JavaScript":             "javascript",
+	"JSON":                   "json",
+	"Julia":                  "julia",
+	"Kotlin":                 "kotlin",
+	"Lua":                    "lua",
+	"markdown":               "markdown",
+	"MySQL":                  "mysql",
+	"Objective-C":            "objective-c",
+	"Perl":                   "perl",
+	"PHP":                    "php",
+	"PL/pgSQL":               "pgsql",
+	"plaintext":              "plaintext",
+	"PostgreSQL SQL dialect": "pgsql",
+	"PowerShell":             "powershell",
+	"Python":                 "python",
+	"Python 3":               "python",
+	"R":                      "r",
+	"react":                  "javascript",
+	"reStructuredText":       "restructuredtext",
+	"Ruby":                   "ruby",
+	"Rust":                   "rust",
+	"Scala":                  "scala",
+	"Scheme":                 "scheme",
+	"SCSS":                   "scss",
+	"Solidity":               "sol",
+	"SQL":                    "sql",
+	"Swift":                  "swift",
+	"systemverilog":          "systemverilog",
+	"Tcl":                    "tcl",
+	"Tcsh":                   "shell",
+	"Transact-SQL":           "sql",
+	"Twig":                   "twig",
+	"TypeScript":             "typescript",
+	"VB.net":                 "vb",
+	"XML":                    "xml",
+	"YAML":                   "yaml",
+}
+
+// EditorLanguage returns the id of the language of the Monaco editor matching the lexer File
+// highlights a file with, it's empty if the editor doesn't support the language of the file
+func EditorLanguage(fileName string, code []byte) string {
+	return monacoLanguages[LexerName(fileName, code)]
+}
+
+// EditorLanguageMapping returns the ids of the languages of the Monaco editor of the file
+// extensions of the custom highlight mapping, so the editor can resolve the language of a
+// renamed file like the server does
+func EditorLanguageMapping() map[string]string {
+	NewContext()
+
+	mapping := make(map[string]string, len(highlightMapping))
+	for ext, val := range highlightMapping {
+		lexer := lexers.Match("mapped." + val)
+		if lexer == nil {
+			continue
+		}
+		if language, ok := monacoLanguages[lexer.Config().Name]; ok {
+			mapping[ext] = language
+		}
+	}
+	return mapping
+}
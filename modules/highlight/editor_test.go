@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package highlight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditorLanguage(t *testing.T) {
+	// don't load the mapping from the config
+	once.Do(func() {})
+	highlightMapping[".gitea"] = "go"
+	highlightMapping[".unknown"] = "txt2"
+	defer func() {
+		delete(highlightMapping, ".gitea")
+		delete(highlightMapping, ".unknown")
+	}()
+
+	kases := []struct {
+		fileName string
+		code     string
+		expected string
+	}{
+		{"main.go", "package main\n", "go"},
+		{"Dockerfile", "FROM alpine\n", "dockerfile"},
+		{"index.ts", "let a: number = 1;\n", "typescript"},
+		{"README.md", "# README\n", "markdown"},
+		{"script.gitea", "package main\n", "go"},
+		{"file.brainfuck", "++++[>++<-]\n", ""},
+	}
+	for _, kase := range kases {
+		assert.Equal(t, kase.expected, EditorLanguage(kase.fileName, []byte(kase.code)), kase.fileName)
+	}
+
+	assert.Equal(t, map[string]string{".gitea": "go"}, EditorLanguageMapping())
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package highlight
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/analyze"
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+)
+
+// embedPreWrapper writes the surrounding pre tags of the embedded snippets without the browser margins
+type embedPreWrapper struct{}
+
+func (embedPreWrapper) Start(code bool, styleAttr string) string {
+	if len(styleAttr) == 0 {
+		return `<pre style="margin:0;padding:8px">`
+	}
+	return "<pre" + strings.TrimSuffix(styleAttr, `"`) + `;margin:0;padding:8px">`
+}
+
+func (embedPreWrapper) End(code bool) string {
+	return "</pre>"
+}
+
+// Embed returns a self-contained HTML version of the lines lineStart to lineEnd of a file, the
+// chroma styles are inlined so it can be embedded outside of Gitea. The whole file is tokenized
+// so the lines are highlighted in their context, it isn't highlighted if it's too large.
+func Embed(fileName string, code []byte, lineStart, lineEnd int) (string, error) {
+	NewContext()
+
+	if val, ok := highlightMapping[filepath.Ext(fileName)]; ok {
+		fileName = "mapped." + val
+	}
+	lexer := lexers.Fallback
+	if len(code) <= sizeLimit {
+		if lexer = lexers.Get(analyze.GetCodeLanguage(fileName, code)); lexer == nil {
+			if lexer = lexers.Match(fileName); lexer == nil {
+				lexer = lexers.Fallback
+			}
+		}
+	}
+
+	iterator, err := lexer.Tokenise(nil, string(code))
+	if err != nil {
+		return "", err
+	}
+	lines := chroma.SplitTokensIntoLines(iterator.Tokens())
+	if lineEnd <= 0 || lineEnd > len(lines) {
+		lineEnd = len(lines)
+	}
+	if lineStart <= 0 {
+		lineStart = 1
+	}
+	var tokens []chroma.Token
+	if lineStart <= lineEnd {
+		for _, line := range lines[lineStart-1 : lineEnd] {
+			tokens = append(tokens, line...)
+		}
+	}
+
+	formatter := html.New(html.WithClasses(false),
+		html.WithLineNumbers(true),
+		html.BaseLineNumber(lineStart),
+		html.TabWidth(4),
+		html.WithPreWrapper(embedPreWrapper{}),
+	)
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, styles.GitHub, chroma.Literator(tokens...)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
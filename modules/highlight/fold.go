@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package highlight
+
+import (
+	"bytes"
+	"sort"
+)
+
+// foldTabWidth is the number of columns a tab counts for when comparing indentations
+const foldTabWidth = 4
+
+// FoldRegion represents lines of a file which can be collapsed. The line Start stays
+// visible while the lines after it up to End, included, are collapsed.
+type FoldRegion struct {
+	Start int
+	End   int
+}
+
+// indentation returns the width of the leading whitespace of a line, or -1 for blank lines
+func indentation(line []byte) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += foldTabWidth - width%foldTabWidth
+		case '\r':
+		default:
+			return width
+		}
+	}
+	return -1
+}
+
+// FoldRegions computes the regions of a file which can be collapsed from the indentation
+// of its lines: a line starts a region when the next non-blank lines are more indented,
+// the region ends with the last of them. Lines are counted from 1, like highlighted lines.
+func FoldRegions(code []byte) []FoldRegion {
+	lines := bytes.Split(code, []byte{'\n'})
+	indents := make([]int, len(lines))
+	for i, line := range lines {
+		indents[i] = indentation(line)
+	}
+
+	type openRegion struct {
+		start  int
+		indent int
+	}
+	regions := make([]FoldRegion, 0, 10)
+	stack := make([]openRegion, 0, 10)
+	lastNonBlank := -1
+	closeRegions := func(indent int) {
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if lastNonBlank > open.start {
+				regions = append(regions, FoldRegion{Start: open.start + 1, End: lastNonBlank + 1})
+			}
+		}
+	}
+
+	for i, indent := range indents {
+		if indent < 0 {
+			continue
+		}
+		if lastNonBlank >= 0 && indent > indents[lastNonBlank] {
+			stack = append(stack, openRegion{start: lastNonBlank, indent: indents[lastNonBlank]})
+		} else {
+			closeRegions(indent)
+		}
+		lastNonBlank = i
+	}
+	closeRegions(0)
+
+	// regions are closed from the innermost, list them by their first line
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Start < regions[j].Start
+	})
+	return regions
+}
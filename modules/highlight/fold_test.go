@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package highlight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldRegions(t *testing.T) {
+	kases := []struct {
+		code     string
+		expected []FoldRegion
+	}{
+		{
+			code:     "",
+			expected: []FoldRegion{},
+		},
+		{
+			code:     "a\nb\nc\n",
+			expected: []FoldRegion{},
+		},
+		{
+			code: `func a() {
+	if b {
+		c()
+
+		d()
+	}
+}
+
+func e() {
+	f()
+}
+`,
+			expected: []FoldRegion{{Start: 1, End: 6}, {Start: 2, End: 5}, {Start: 9, End: 10}},
+		},
+		{
+			code: `class A:
+    def b(self):
+        pass
+
+    def c(self):
+        return 1
+`,
+			expected: []FoldRegion{{Start: 1, End: 6}, {Start: 2, End: 3}, {Start: 5, End: 6}},
+		},
+		{
+			code:     "a:\n  b:\n    c: 1\n  d: 2",
+			expected: []FoldRegion{{Start: 1, End: 4}, {Start: 2, End: 3}},
+		},
+	}
+
+	for _, kase := range kases {
+		assert.Equal(t, kase.expected, FoldRegions([]byte(kase.code)), kase.code)
+	}
+}
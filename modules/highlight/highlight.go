@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/analyze"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"github.com/alecthomas/chroma"
 	"github.com/alecthomas/chroma/formatters/html"
 	"github.com/alecthomas/chroma/lexers"
 	"github.com/alecthomas/chroma/styles"
@@ -43,6 +44,12 @@ func NewContext() {
 
 // Code returns a HTML version of code string with chroma syntax highlighting classes
 func Code(fileName, code string) string {
+	return CodeWithLanguage(fileName, "", code)
+}
+
+// CodeWithLanguage is like Code, the lexer of the language, as detected by the indexers,
+// is preferred to the one matching the file name
+func CodeWithLanguage(fileName, language, code string) string {
 	NewContext()
 
 	// diff view newline will be passed as empty, change to literal \n so it can be copied
@@ -66,12 +73,17 @@ func Code(fileName, code string) string {
 	htmlbuf := bytes.Buffer{}
 	htmlw := bufio.NewWriter(&htmlbuf)
 
+	var lexer chroma.Lexer
 	if val, ok := highlightMapping[filepath.Ext(fileName)]; ok {
 		//change file name to one with mapped extension so we look that up instead
-		fileName = "mapped." + val
+		lexer = lexers.Match("mapped." + val)
+	}
+	if lexer == nil && len(language) > 0 {
+		lexer = lexers.Get(language)
+	}
+	if lexer == nil {
+		lexer = lexers.Match(fileName)
 	}
-
-	lexer := lexers.Match(fileName)
 	if lexer == nil {
 		lexer = lexers.Fallback
 	}
@@ -114,20 +126,7 @@ func File(numLines int, fileName string, code []byte) map[int]string {
 	htmlbuf := bytes.Buffer{}
 	htmlw := bufio.NewWriter(&htmlbuf)
 
-	if val, ok := highlightMapping[filepath.Ext(fileName)]; ok {
-		fileName = "test." + val
-	}
-
-	language := analyze.GetCodeLanguage(fileName, code)
-
-	lexer := lexers.Get(language)
-	if lexer == nil {
-		lexer = lexers.Match(fileName)
-		if lexer == nil {
-			lexer = lexers.Fallback
-		}
-	}
-
+	lexer := fileLexer(fileName, code)
 	iterator, err := lexer.Tokenise(nil, string(code))
 	if err != nil {
 		log.Error("Can't tokenize code: %v", err)
@@ -154,6 +153,35 @@ func File(numLines int, fileName string, code []byte) map[int]string {
 	return m
 }
 
+// fileLexer returns the lexer highlighting a file, the language detected from the content of the
+// file is preferred to the one matching its name
+func fileLexer(fileName string, code []byte) chroma.Lexer {
+	if val, ok := highlightMapping[filepath.Ext(fileName)]; ok {
+		fileName = "test." + val
+	}
+
+	language := analyze.GetCodeLanguage(fileName, code)
+
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Match(fileName)
+		if lexer == nil {
+			lexer = lexers.Fallback
+		}
+	}
+	return lexer
+}
+
+// LexerName returns the name of the lexer File highlights a file with
+func LexerName(fileName string, code []byte) string {
+	NewContext()
+
+	if len(code) > sizeLimit {
+		return lexers.Fallback.Config().Name
+	}
+	return fileLexer(fileName, code).Config().Name
+}
+
 // return unhiglighted map
 func plainText(code string, numLines int) map[int]string {
 	m := make(map[int]string, numLines)
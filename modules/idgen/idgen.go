@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package idgen issues time-ordered IDs without coordinating with the database
+package idgen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Generator issues unique IDs
+type Generator interface {
+	NextID() int64
+}
+
+const (
+	nodeBits     = 5
+	sequenceBits = 7
+
+	// MaxNodeID is the largest ID of a node issuing snowflake IDs
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// Epoch is the time the milliseconds of the snowflake IDs are counted from
+var Epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake issues 53-bit IDs made of the milliseconds since Epoch, the ID of the node and a sequence
+// number of the millisecond. The IDs of a node increase, the IDs of all the nodes are roughly ordered
+// by time, and they stay exact as JavaScript numbers.
+type Snowflake struct {
+	mutex      sync.Mutex
+	nodeID     int64
+	lastMillis int64
+	sequence   int64
+	now        func() time.Time
+}
+
+// NewSnowflake returns a generator of snowflake IDs for a node, the nodes sharing a database need
+// distinct IDs from 0 to MaxNodeID
+func NewSnowflake(nodeID int64) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, fmt.Errorf("the node ID %d isn't between 0 and %d", nodeID, MaxNodeID)
+	}
+	return &Snowflake{nodeID: nodeID, now: time.Now}, nil
+}
+
+// NextID returns a new ID. When the sequence of a millisecond is exhausted or the clock goes
+// backwards, the IDs of the following milliseconds are issued so that the IDs keep increasing.
+func (s *Snowflake) NextID() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	millis := s.now().Sub(Epoch).Milliseconds()
+	if millis <= s.lastMillis {
+		millis = s.lastMillis
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			millis++
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMillis = millis
+	return millis<<(nodeBits+sequenceBits) | s.nodeID<<sequenceBits | s.sequence
+}
+
+// Time returns the time a snowflake ID was issued at, to the millisecond
+func Time(id int64) time.Time {
+	return Epoch.Add(time.Duration(id>>(nodeBits+sequenceBits)) * time.Millisecond)
+}
+
+// NodeID returns the ID of the node which issued a snowflake ID
+func NodeID(id int64) int64 {
+	return id >> sequenceBits & MaxNodeID
+}
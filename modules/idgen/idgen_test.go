@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package idgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnowflake(t *testing.T) {
+	_, err := NewSnowflake(MaxNodeID + 1)
+	assert.Error(t, err)
+
+	s, err := NewSnowflake(3)
+	assert.NoError(t, err)
+	now := time.Date(2020, 10, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	id := s.NextID()
+	assert.Equal(t, now, Time(id))
+	assert.EqualValues(t, 3, NodeID(id))
+	assert.True(t, id < 1<<53)
+
+	// the IDs of a millisecond increase until its sequence is exhausted
+	last := id
+	for i := 0; i < maxSequence; i++ {
+		next := s.NextID()
+		assert.Equal(t, last+1, next)
+		last = next
+	}
+	next := s.NextID()
+	assert.Equal(t, now.Add(time.Millisecond), Time(next))
+	assert.True(t, next > last)
+
+	// the IDs keep increasing when the clock goes backwards
+	now = now.Add(-time.Second)
+	assert.True(t, s.NextID() > next)
+
+	now = now.Add(time.Hour)
+	id = s.NextID()
+	assert.Equal(t, now, Time(id))
+	assert.EqualValues(t, 3, NodeID(id))
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package imagediff computes the differences between the pixels of two images
+package imagediff
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"  // for comparing gif images
+	_ "image/jpeg" // for comparing jpeg images
+	_ "image/png"  // for comparing png images
+	"io"
+	"io/ioutil"
+)
+
+// MaxPixels is the maximum number of pixels of the images which are compared
+const MaxPixels = 4096 * 4096
+
+// ErrTooLarge is returned when an image has more than MaxPixels pixels
+var ErrTooLarge = errors.New("the image is too large to be compared")
+
+// Decode decodes a GIF, JPEG or PNG image, the image is not decoded if it has more than MaxPixels pixels
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// changedColor is the color of the changed pixels, its alpha is proportional to the difference
+var changedColor = color.NRGBA{R: 255, G: 0, B: 140}
+
+// Diff returns an image as large as both images where the unchanged pixels of the image after are faded
+// and the changed pixels are highlighted, the more they changed the more they stand out, and the number
+// of changed pixels. The pixels outside of one of the images are changed.
+func Diff(before, after image.Image) (*image.NRGBA, int) {
+	beforeBounds, afterBounds := before.Bounds(), after.Bounds()
+	width, height := beforeBounds.Dx(), beforeBounds.Dy()
+	if afterBounds.Dx() > width {
+		width = afterBounds.Dx()
+	}
+	if afterBounds.Dy() > height {
+		height = afterBounds.Dy()
+	}
+
+	diff := image.NewNRGBA(image.Rect(0, 0, width, height))
+	changed := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			beforePoint := image.Pt(beforeBounds.Min.X+x, beforeBounds.Min.Y+y)
+			afterPoint := image.Pt(afterBounds.Min.X+x, afterBounds.Min.Y+y)
+			inBefore, inAfter := beforePoint.In(beforeBounds), afterPoint.In(afterBounds)
+			if !inBefore && !inAfter {
+				continue
+			}
+			if !inBefore || !inAfter {
+				changed++
+				diff.SetNRGBA(x, y, changedColor)
+				continue
+			}
+
+			b := color.NRGBAModel.Convert(before.At(beforePoint.X, beforePoint.Y)).(color.NRGBA)
+			a := color.NRGBAModel.Convert(after.At(afterPoint.X, afterPoint.Y)).(color.NRGBA)
+			delta := maxDelta(b, a)
+			if delta == 0 {
+				diff.SetNRGBA(x, y, fade(a))
+				continue
+			}
+			changed++
+			c := changedColor
+			c.A = uint8(64 + int(delta)*191/255)
+			diff.SetNRGBA(x, y, c)
+		}
+	}
+	return diff, changed
+}
+
+// maxDelta returns the largest difference between the channels of two colors, the colors of fully
+// transparent pixels don't matter
+func maxDelta(c1, c2 color.NRGBA) uint8 {
+	if c1.A == 0 && c2.A == 0 {
+		return 0
+	}
+	delta := absDelta(c1.A, c2.A)
+	for _, d := range []uint8{absDelta(c1.R, c2.R), absDelta(c1.G, c2.G), absDelta(c1.B, c2.B)} {
+		if d > delta {
+			delta = d
+		}
+	}
+	return delta
+}
+
+func absDelta(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// fade returns a light gray of the luminance of a color
+func fade(c color.NRGBA) color.NRGBA {
+	luminance := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+	gray := uint8(255 - (255-luminance)/4)
+	return color.NRGBA{R: gray, G: gray, B: gray, A: c.A / 2}
+}
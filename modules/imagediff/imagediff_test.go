@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package imagediff
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	after := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			before.SetNRGBA(x, y, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+			after.SetNRGBA(x, y, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+		}
+	}
+	after.SetNRGBA(1, 1, color.NRGBA{R: 255, G: 20, B: 30, A: 255})
+
+	diff, changed := Diff(before, after)
+	assert.Equal(t, image.Rect(0, 0, 4, 2), diff.Bounds())
+	// the changed pixel and the pixels of the added column
+	assert.Equal(t, 3, changed)
+	assert.Equal(t, changedColor, diff.NRGBAAt(3, 0))
+	assert.EqualValues(t, 64+245*191/255, diff.NRGBAAt(1, 1).A)
+	assert.Equal(t, fade(after.NRGBAAt(0, 0)), diff.NRGBAAt(0, 0))
+
+	_, changed = Diff(before, before)
+	assert.Equal(t, 0, changed)
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 2, 2))))
+	img, err := Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 2, 2), img.Bounds())
+
+	buf.Reset()
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4097, 4096))))
+	_, err = Decode(bytes.NewReader(buf.Bytes()))
+	assert.Equal(t, ErrTooLarge, err)
+
+	_, err = Decode(bytes.NewReader([]byte("not an image")))
+	assert.Error(t, err)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
 	"github.com/blevesearch/bleve/index/upsidedown"
 	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/search"
 	"github.com/blevesearch/bleve/search/query"
 	"github.com/ethantkoenig/rupture"
 	"github.com/go-enry/go-enry/v2"
@@ -87,10 +88,14 @@ func openBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 
 // RepoIndexerData data stored in the repo indexer
 type RepoIndexerData struct {
-	RepoID    int64
-	CommitID  string
-	Content   string
-	Language  string
+	RepoID   int64
+	CommitID string
+	Content  string
+	Language string
+	// Path is the lower case path of the file
+	Path string
+	// Symbols are the names of the symbols defined in the file
+	Symbols   []string
 	UpdatedAt time.Time
 }
 
@@ -102,7 +107,7 @@ func (d *RepoIndexerData) Type() string {
 const (
 	repoIndexerAnalyzer      = "repoIndexerAnalyzer"
 	repoIndexerDocType       = "repoIndexerDocType"
-	repoIndexerLatestVersion = 5
+	repoIndexerLatestVersion = 6
 )
 
 // createBleveIndexer create a bleve repo indexer if one does not already exist
@@ -115,12 +120,14 @@ func createBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 	textFieldMapping := bleve.NewTextFieldMapping()
 	textFieldMapping.IncludeInAll = false
 	docMapping.AddFieldMappingsAt("Content", textFieldMapping)
+	docMapping.AddFieldMappingsAt("Symbols", textFieldMapping)
 
 	termFieldMapping := bleve.NewTextFieldMapping()
 	termFieldMapping.IncludeInAll = false
 	termFieldMapping.Analyzer = analyzer_keyword.Name
 	docMapping.AddFieldMappingsAt("Language", termFieldMapping)
 	docMapping.AddFieldMappingsAt("CommitID", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Path", termFieldMapping)
 
 	timeFieldMapping := bleve.NewDateTimeFieldMapping()
 	timeFieldMapping.IncludeInAll = false
@@ -200,11 +207,14 @@ func (b *BleveIndexer) addUpdate(commitSha string, update fileUpdate, repo *mode
 	}
 
 	id := filenameIndexerID(repo.ID, update.Filename)
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
 	return batch.Index(id, &RepoIndexerData{
 		RepoID:    repo.ID,
 		CommitID:  commitSha,
 		Content:   string(charset.ToUTF8DropErrors(fileContents)),
-		Language:  analyze.GetCodeLanguage(update.Filename, fileContents),
+		Language:  language,
+		Path:      strings.ToLower(update.Filename),
+		Symbols:   symbolNames(update.Filename, language, fileContents),
 		UpdatedAt: time.Now().UTC(),
 	})
 }
@@ -278,32 +288,122 @@ func (b *BleveIndexer) Delete(repoID int64) error {
 	return batch.Flush()
 }
 
-// Search searches for files in the specified repo.
-// Returns the matching file-paths
-func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+// repoIDsQuery returns the query restricting a search to some repositories, or nil for all of them
+func repoIDsQuery(repoIDs []int64) query.Query {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	var repoQueries = make([]query.Query, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		repoQueries = append(repoQueries, numericEqualityQuery(repoID, "RepoID"))
+	}
+	return bleve.NewDisjunctionQuery(repoQueries...)
+}
+
+func keywordQuery(keyword string) query.Query {
 	phraseQuery := bleve.NewMatchPhraseQuery(keyword)
 	phraseQuery.FieldVal = "Content"
 	phraseQuery.Analyzer = repoIndexerAnalyzer
+	return phraseQuery
+}
 
-	var indexerQuery query.Query
-	if len(repoIDs) > 0 {
-		var repoQueries = make([]query.Query, 0, len(repoIDs))
-		for _, repoID := range repoIDs {
-			repoQueries = append(repoQueries, numericEqualityQuery(repoID, "RepoID"))
-		}
+// qualifiersQueries returns the queries restricting a search to the files defining a symbol
+// or whose path matches
+func qualifiersQueries(opts *SearchOptions) []query.Query {
+	queries := make([]query.Query, 0, 2)
+	if len(opts.Symbol) > 0 {
+		symbolQuery := bleve.NewMatchPhraseQuery(opts.Symbol)
+		symbolQuery.FieldVal = "Symbols"
+		symbolQuery.Analyzer = repoIndexerAnalyzer
+		queries = append(queries, symbolQuery)
+	}
+	if len(opts.Path) > 0 {
+		pathQuery := bleve.NewWildcardQuery(opts.pathWildcard())
+		pathQuery.FieldVal = "Path"
+		queries = append(queries, pathQuery)
+	}
+	return queries
+}
 
-		indexerQuery = bleve.NewConjunctionQuery(
-			bleve.NewDisjunctionQuery(repoQueries...),
-			phraseQuery,
-		)
-	} else {
-		indexerQuery = phraseQuery
+func bleveHitToSearchResult(hit *search.DocumentMatch) *SearchResult {
+	language := hit.Fields["Language"].(string)
+	var updatedUnix timeutil.TimeStamp
+	if t, err := time.Parse(time.RFC3339, hit.Fields["UpdatedAt"].(string)); err == nil {
+		updatedUnix = timeutil.TimeStamp(t.Unix())
+	}
+	return &SearchResult{
+		RepoID:      int64(hit.Fields["RepoID"].(float64)),
+		StartIndex:  -1,
+		EndIndex:    -1,
+		Filename:    filenameOfIndexerID(hit.ID),
+		Content:     hit.Fields["Content"].(string),
+		CommitID:    hit.Fields["CommitID"].(string),
+		UpdatedUnix: updatedUnix,
+		Language:    language,
+		Color:       enry.GetColor(language),
+	}
+}
+
+// searchExact fetches the candidates of a regex, case-sensitive or whole-word search from the
+// index and returns the ones matching exactly
+func (b *BleveIndexer) searchExact(opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+	pattern, err := opts.Pattern()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	// The index is case insensitive and can't match regular expressions spanning several
+	// tokens, so the keyword can only narrow down the candidates of plain searches
+	queries := qualifiersQueries(opts)
+	if q := repoIDsQuery(opts.RepoIDs); q != nil {
+		queries = append(queries, q)
+	}
+	if !opts.IsRegex {
+		queries = append(queries, keywordQuery(opts.Keyword))
+	}
+	var indexerQuery query.Query = bleve.NewMatchAllQuery()
+	if len(queries) > 0 {
+		indexerQuery = bleve.NewConjunctionQuery(queries...)
+	}
+
+	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, maxExactMatchCandidates, 0, false)
+	searchRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
+	result, err := b.indexer.Search(searchRequest)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	candidates := make([]*SearchResult, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		candidates = append(candidates, bleveHitToSearchResult(hit))
+	}
+	total, results, languages := filterExactMatches(opts, pattern, candidates)
+	return total, results, languages, nil
+}
+
+// Search searches for files in the specified repo.
+// Returns the matching file-paths
+func (b *BleveIndexer) Search(opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+	if opts.NeedsExactMatch() {
+		return b.searchExact(opts)
+	}
+
+	queries := qualifiersQueries(opts)
+	if len(opts.Keyword) > 0 {
+		queries = append(queries, keywordQuery(opts.Keyword))
+	}
+	if repoQuery := repoIDsQuery(opts.RepoIDs); repoQuery != nil {
+		queries = append(queries, repoQuery)
+	}
+	var indexerQuery query.Query = bleve.NewMatchAllQuery()
+	if len(queries) > 0 {
+		indexerQuery = bleve.NewConjunctionQuery(queries...)
 	}
 
 	// Save for reuse without language filter
 	facetQuery := indexerQuery
-	if len(language) > 0 {
-		languageQuery := bleve.NewMatchQuery(language)
+	if len(opts.Language) > 0 {
+		languageQuery := bleve.NewMatchQuery(opts.Language)
 		languageQuery.FieldVal = "Language"
 		languageQuery.Analyzer = analyzer_keyword.Name
 
@@ -313,12 +413,12 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 		)
 	}
 
-	from := (page - 1) * pageSize
-	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, pageSize, from, false)
+	from := (opts.Page - 1) * opts.PageSize
+	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, opts.PageSize, from, false)
 	searchRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
 	searchRequest.IncludeLocations = true
 
-	if len(language) == 0 {
+	if len(opts.Language) == 0 {
 		searchRequest.AddFacet("languages", bleve.NewFacetRequest("Language", 10))
 	}
 
@@ -343,26 +443,13 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 				endIndex = locationEnd
 			}
 		}
-		language := hit.Fields["Language"].(string)
-		var updatedUnix timeutil.TimeStamp
-		if t, err := time.Parse(time.RFC3339, hit.Fields["UpdatedAt"].(string)); err == nil {
-			updatedUnix = timeutil.TimeStamp(t.Unix())
-		}
-		searchResults[i] = &SearchResult{
-			RepoID:      int64(hit.Fields["RepoID"].(float64)),
-			StartIndex:  startIndex,
-			EndIndex:    endIndex,
-			Filename:    filenameOfIndexerID(hit.ID),
-			Content:     hit.Fields["Content"].(string),
-			CommitID:    hit.Fields["CommitID"].(string),
-			UpdatedUnix: updatedUnix,
-			Language:    language,
-			Color:       enry.GetColor(language),
-		}
+		searchResults[i] = bleveHitToSearchResult(hit)
+		searchResults[i].StartIndex = startIndex
+		searchResults[i].EndIndex = endIndex
 	}
 
 	searchResultLanguages := make([]*SearchResultLanguages, 0, 10)
-	if len(language) > 0 {
+	if len(opts.Language) > 0 {
 		// Use separate query to go get all language counts
 		facetRequest := bleve.NewSearchRequestOptions(facetQuery, 1, 0, false)
 		facetRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
@@ -26,7 +26,7 @@ import (
 )
 
 const (
-	esRepoIndexerLatestVersion = 1
+	esRepoIndexerLatestVersion = 2
 )
 
 var (
@@ -101,6 +101,14 @@ const (
 					"type": "keyword",
 					"index": true
 				},
+				"path": {
+					"type": "keyword",
+					"index": true
+				},
+				"symbols": {
+					"type": "text",
+					"index": true
+				},
 				"updated_at": {
 					"type": "long",
 					"index": true
@@ -195,6 +203,7 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 	}
 
 	id := filenameIndexerID(repo.ID, update.Filename)
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
 
 	return []elastic.BulkableRequest{
 		elastic.NewBulkIndexRequest().
@@ -204,7 +213,9 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 				"repo_id":    repo.ID,
 				"content":    string(charset.ToUTF8DropErrors(fileContents)),
 				"commit_id":  sha,
-				"language":   analyze.GetCodeLanguage(update.Filename, fileContents),
+				"language":   language,
+				"path":       strings.ToLower(update.Filename),
+				"symbols":    symbolNames(update.Filename, language, fileContents),
 				"updated_at": timeutil.TimeStampNow(),
 			}),
 	}, nil
@@ -268,6 +279,28 @@ func indexPos(content, start, end string) (int, int) {
 	return startIdx, startIdx + len(start) + endIdx + len(end)
 }
 
+func esHitToSearchResult(hit *elastic.SearchHit) (*SearchResult, error) {
+	repoID, fileName := parseIndexerID(hit.Id)
+	var res = make(map[string]interface{})
+	if err := json.Unmarshal(hit.Source, &res); err != nil {
+		return nil, err
+	}
+
+	language := res["language"].(string)
+
+	return &SearchResult{
+		RepoID:      repoID,
+		Filename:    fileName,
+		CommitID:    res["commit_id"].(string),
+		Content:     res["content"].(string),
+		UpdatedUnix: timeutil.TimeStamp(res["updated_at"].(float64)),
+		Language:    language,
+		StartIndex:  -1,
+		EndIndex:    -1,
+		Color:       enry.GetColor(language),
+	}, nil
+}
+
 func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	hits := make([]*SearchResult, 0, pageSize)
 	for _, hit := range searchResult.Hits.Hits {
@@ -284,29 +317,21 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 			if startIndex == -1 {
 				panic(fmt.Sprintf("1===%s,,,%#v,,,%s", kw, hit.Highlight, c[0]))
 			}
-		} else {
+		} else if len(kw) > 0 {
+			// the files found by the qualifiers only have no highlight
 			panic(fmt.Sprintf("2===%#v", hit.Highlight))
 		}
 
-		repoID, fileName := parseIndexerID(hit.Id)
-		var res = make(map[string]interface{})
-		if err := json.Unmarshal(hit.Source, &res); err != nil {
+		result, err := esHitToSearchResult(hit)
+		if err != nil {
 			return 0, nil, nil, err
 		}
-
-		language := res["language"].(string)
-
-		hits = append(hits, &SearchResult{
-			RepoID:      repoID,
-			Filename:    fileName,
-			CommitID:    res["commit_id"].(string),
-			Content:     res["content"].(string),
-			UpdatedUnix: timeutil.TimeStamp(res["updated_at"].(float64)),
-			Language:    language,
-			StartIndex:  startIndex,
-			EndIndex:    endIndex - 9, // remove the length <em></em> since we give Content the original data
-			Color:       enry.GetColor(language),
-		})
+		result.StartIndex = startIndex
+		result.EndIndex = endIndex
+		if endIndex >= 0 {
+			result.EndIndex -= 9 // remove the length <em></em> since we give Content the original data
+		}
+		hits = append(hits, result)
 	}
 
 	return searchResult.TotalHits(), hits, extractAggs(searchResult), nil
@@ -329,26 +354,97 @@ func extractAggs(searchResult *elastic.SearchResult) []*SearchResultLanguages {
 	return searchResultLanguages
 }
 
-// Search searches for codes and language stats by given conditions.
-func (b *ElasticSearchIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
-	kwQuery := elastic.NewMultiMatchQuery(keyword, "content")
-	query := elastic.NewBoolQuery()
-	query = query.Must(kwQuery)
-	if len(repoIDs) > 0 {
-		var repoStrs = make([]interface{}, 0, len(repoIDs))
-		for _, repoID := range repoIDs {
-			repoStrs = append(repoStrs, repoID)
+// qualifiersQuery adds to a query the restrictions to the files defining a symbol or whose
+// path matches
+func qualifiersQuery(query *elastic.BoolQuery, opts *SearchOptions) *elastic.BoolQuery {
+	if len(opts.Symbol) > 0 {
+		query = query.Must(elastic.NewMatchPhraseQuery("symbols", opts.Symbol))
+	}
+	if len(opts.Path) > 0 {
+		query = query.Must(elastic.NewWildcardQuery("path", opts.pathWildcard()))
+	}
+	return query
+}
+
+func repoIDsTermsQuery(repoIDs []int64) elastic.Query {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	var repoStrs = make([]interface{}, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		repoStrs = append(repoStrs, repoID)
+	}
+	return elastic.NewTermsQuery("repo_id", repoStrs...)
+}
+
+// searchExact fetches the candidates of a regex, case-sensitive or whole-word search from the
+// index and returns the ones matching exactly
+func (b *ElasticSearchIndexer) searchExact(opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+	pattern, err := opts.Pattern()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	// The content is analyzed case insensitively and regular expressions can't span several
+	// terms, so the keyword can only narrow down the candidates of plain searches
+	query := qualifiersQuery(elastic.NewBoolQuery(), opts)
+	if repoQuery := repoIDsTermsQuery(opts.RepoIDs); repoQuery != nil {
+		query = query.Must(repoQuery)
+	}
+	if !opts.IsRegex {
+		query = query.Must(elastic.NewMultiMatchQuery(opts.Keyword, "content"))
+	}
+
+	searchResult, err := b.client.Search().
+		Index(b.indexerAliasName).
+		Query(query).
+		Size(maxExactMatchCandidates).
+		Do(context.Background())
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	candidates := make([]*SearchResult, 0, len(searchResult.Hits.Hits))
+	for _, hit := range searchResult.Hits.Hits {
+		result, err := esHitToSearchResult(hit)
+		if err != nil {
+			return 0, nil, nil, err
 		}
-		repoQuery := elastic.NewTermsQuery("repo_id", repoStrs...)
+		candidates = append(candidates, result)
+	}
+	total, results, languages := filterExactMatches(opts, pattern, candidates)
+	return total, results, languages, nil
+}
+
+// Search searches for codes and language stats by given conditions.
+func (b *ElasticSearchIndexer) Search(opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+	if opts.NeedsExactMatch() {
+		return b.searchExact(opts)
+	}
+
+	var (
+		language = opts.Language
+		keyword  = opts.Keyword
+		page     = opts.Page
+		pageSize = opts.PageSize
+	)
+	query := qualifiersQuery(elastic.NewBoolQuery(), opts)
+	if len(keyword) > 0 {
+		query = query.Must(elastic.NewMultiMatchQuery(keyword, "content"))
+	}
+	if repoQuery := repoIDsTermsQuery(opts.RepoIDs); repoQuery != nil {
 		query = query.Must(repoQuery)
 	}
 
 	var (
 		start       int
-		kw          = "<em>" + keyword + "</em>"
+		kw          string
 		aggregation = elastic.NewTermsAggregation().Field("language").Size(10).OrderByCountDesc()
 	)
 
+	if len(keyword) > 0 {
+		kw = "<em>" + keyword + "</em>"
+	}
 	if page > 0 {
 		start = (page - 1) * pageSize
 	}
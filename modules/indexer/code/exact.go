@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+
+	"github.com/go-enry/go-enry/v2"
+)
+
+// maxExactMatchCandidates is the maximum number of files checked against the pattern of a
+// regex, case-sensitive or whole-word search
+const maxExactMatchCandidates = 1000
+
+// ErrInvalidSearchPattern represents an invalid regular expression searched for
+type ErrInvalidSearchPattern struct {
+	Pattern string
+	Err     error
+}
+
+// IsErrInvalidSearchPattern checks if an error is an ErrInvalidSearchPattern
+func IsErrInvalidSearchPattern(err error) bool {
+	_, ok := err.(ErrInvalidSearchPattern)
+	return ok
+}
+
+func (err ErrInvalidSearchPattern) Error() string {
+	return fmt.Sprintf("invalid search pattern [pattern: %s]: %v", err.Pattern, err.Err)
+}
+
+// NeedsExactMatch returns whether the results of the indexers must be checked against the
+// pattern of the search, as they only match tokens case insensitively
+func (opts *SearchOptions) NeedsExactMatch() bool {
+	return len(opts.Keyword) > 0 && (opts.IsRegex || opts.IsCaseSensitive || opts.IsWholeWord)
+}
+
+// Pattern returns the regular expression matching the keyword according to the options
+func (opts *SearchOptions) Pattern() (*regexp.Regexp, error) {
+	expr := opts.Keyword
+	if !opts.IsRegex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.IsWholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !opts.IsCaseSensitive {
+		expr = `(?i)` + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, ErrInvalidSearchPattern{Pattern: opts.Keyword, Err: err}
+	}
+	return pattern, nil
+}
+
+// requiredLiteral returns a string which must be contained, case insensitively, by the
+// matches of the search, or an empty string if there is none
+func (opts *SearchOptions) requiredLiteral() string {
+	if !opts.IsRegex {
+		return opts.Keyword
+	}
+	re, err := syntax.Parse(opts.Keyword, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	return longestLiteral(re.Simplify())
+}
+
+func longestLiteral(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCapture, syntax.OpPlus:
+		return longestLiteral(re.Sub[0])
+	case syntax.OpConcat:
+		var longest string
+		for _, sub := range re.Sub {
+			if literal := longestLiteral(sub); len(literal) > len(longest) {
+				longest = literal
+			}
+		}
+		return longest
+	}
+	return ""
+}
+
+// filterExactMatches returns the page of the candidates matching the pattern, the total number of
+// matches and their languages. The matches of all the languages are counted. Without pattern,
+// all the candidates match and are located by displayResults.
+func filterExactMatches(opts *SearchOptions, pattern *regexp.Regexp, candidates []*SearchResult) (int64, []*SearchResult, []*SearchResultLanguages) {
+	matches := make([]*SearchResult, 0, len(candidates))
+	languages := make([]*SearchResultLanguages, 0, 10)
+	languagesIndex := make(map[string]int)
+	for _, candidate := range candidates {
+		loc := []int{-1, -1}
+		if pattern != nil {
+			loc = nil
+			for _, l := range pattern.FindAllStringIndex(candidate.Content, -1) {
+				// patterns like a* match anywhere
+				if l[1] > l[0] {
+					loc = l
+					break
+				}
+			}
+		}
+		if loc == nil {
+			continue
+		}
+
+		if len(candidate.Language) > 0 {
+			if i, ok := languagesIndex[candidate.Language]; ok {
+				languages[i].Count++
+			} else {
+				languagesIndex[candidate.Language] = len(languages)
+				languages = append(languages, &SearchResultLanguages{
+					Language: candidate.Language,
+					Color:    enry.GetColor(candidate.Language),
+					Count:    1,
+				})
+			}
+		}
+		if len(opts.Language) > 0 && candidate.Language != opts.Language {
+			continue
+		}
+		candidate.StartIndex, candidate.EndIndex = loc[0], loc[1]
+		matches = append(matches, candidate)
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].Count > languages[j].Count
+	})
+	if len(languages) > 10 {
+		languages = languages[:10]
+	}
+
+	total := int64(len(matches))
+	start := (opts.Page - 1) * opts.PageSize
+	if start < 0 {
+		start = 0
+	}
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + opts.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return total, matches[start:end], languages
+}
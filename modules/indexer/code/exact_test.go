@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchOptions_Pattern(t *testing.T) {
+	kases := []struct {
+		opts    SearchOptions
+		matches []string
+		misses  []string
+	}{
+		{
+			opts:    SearchOptions{Keyword: "foo.bar"},
+			matches: []string{"foo.bar", "FOO.BAR", "xfoo.barx"},
+			misses:  []string{"fooxbar"},
+		},
+		{
+			opts:    SearchOptions{Keyword: "Foo", IsCaseSensitive: true},
+			matches: []string{"Foo", "xFoo"},
+			misses:  []string{"foo", "FOO"},
+		},
+		{
+			opts:    SearchOptions{Keyword: "foo", IsWholeWord: true},
+			matches: []string{"foo", "a Foo b", "foo()"},
+			misses:  []string{"foobar", "xfoo"},
+		},
+		{
+			opts:    SearchOptions{Keyword: `func \w+\(`, IsRegex: true},
+			matches: []string{"func Foo(", "FUNC bar("},
+			misses:  []string{"func (", "func Foo"},
+		},
+	}
+	for _, kase := range kases {
+		pattern, err := kase.opts.Pattern()
+		assert.NoError(t, err)
+		for _, s := range kase.matches {
+			assert.True(t, pattern.MatchString(s), "%q should match %q", kase.opts.Keyword, s)
+		}
+		for _, s := range kase.misses {
+			assert.False(t, pattern.MatchString(s), "%q should not match %q", kase.opts.Keyword, s)
+		}
+	}
+
+	opts := SearchOptions{Keyword: "foo(", IsRegex: true}
+	_, err := opts.Pattern()
+	assert.True(t, IsErrInvalidSearchPattern(err))
+}
+
+func TestSearchOptions_requiredLiteral(t *testing.T) {
+	kases := map[string]string{
+		`foo`:          "foo",
+		`func \w+\(`:   "func ",
+		`(abc)+defgh`:  "defgh",
+		`foo|bar`:      "",
+		`[a-z]+`:       "",
+		`x*longest\.y`: "longest.y",
+	}
+	for keyword, expected := range kases {
+		opts := SearchOptions{Keyword: keyword, IsRegex: true}
+		assert.Equal(t, expected, opts.requiredLiteral(), keyword)
+	}
+
+	opts := SearchOptions{Keyword: "a.b"}
+	assert.Equal(t, "a.b", opts.requiredLiteral())
+}
+
+func TestFilterExactMatches(t *testing.T) {
+	candidates := []*SearchResult{
+		{Filename: "a.go", Language: "Go", Content: "package a\n\nfunc Foo() {}\n"},
+		{Filename: "b.go", Language: "Go", Content: "package b\n\nfunc foo() {}\n"},
+		{Filename: "c.py", Language: "Python", Content: "def Foo():\n    pass\n"},
+		{Filename: "d.txt", Content: "Foobar\n"},
+	}
+
+	opts := &SearchOptions{Keyword: "Foo", IsCaseSensitive: true, IsWholeWord: true, Page: 1, PageSize: 10}
+	pattern, err := opts.Pattern()
+	assert.NoError(t, err)
+	total, results, languages := filterExactMatches(opts, pattern, candidates)
+	assert.EqualValues(t, 2, total)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "a.go", results[0].Filename)
+		assert.Equal(t, "func Foo() {}", results[0].Content[results[0].StartIndex-5:results[0].EndIndex+5])
+		assert.Equal(t, "c.py", results[1].Filename)
+	}
+	assert.Len(t, languages, 2)
+
+	// the languages are counted before filtering
+	opts.Language = "Python"
+	total, results, languages = filterExactMatches(opts, pattern, candidates)
+	assert.EqualValues(t, 1, total)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "c.py", results[0].Filename)
+	}
+	assert.Len(t, languages, 2)
+
+	opts = &SearchOptions{Keyword: "foo", Page: 2, PageSize: 3}
+	pattern, err = opts.Pattern()
+	assert.NoError(t, err)
+	total, results, _ = filterExactMatches(opts, pattern, candidates)
+	assert.EqualValues(t, 4, total)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "d.txt", results[0].Filename)
+	}
+}
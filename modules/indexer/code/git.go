@@ -50,7 +50,12 @@ func isIndexable(entry *git.TreeEntry) bool {
 	if !entry.IsRegular() && !entry.IsExecutable() {
 		return false
 	}
-	name := strings.ToLower(entry.Name())
+	return isIndexablePath(entry.Name())
+}
+
+// isIndexablePath checks the path of a file against the include and exclude patterns
+func isIndexablePath(filename string) bool {
+	name := strings.ToLower(filename)
 	for _, g := range setting.Indexer.ExcludePatterns {
 		if g.Match(name) {
 			return false
@@ -0,0 +1,136 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-enry/go-enry/v2"
+)
+
+// GrepSearch searches the default branch of a repository with git grep, it is used when the
+// code indexer is disabled. git grep only narrows down the files which are then matched with
+// the same pattern as the indexers, so the results don't depend on the backend.
+func GrepSearch(repo *models.Repository, opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
+	if opts.IsEmpty() || repo.IsEmpty {
+		return 0, nil, nil, nil
+	}
+	var pattern *regexp.Regexp
+	literal := opts.Symbol
+	if len(opts.Keyword) > 0 {
+		var err error
+		if pattern, err = opts.Pattern(); err != nil {
+			return 0, nil, nil, err
+		}
+		literal = opts.requiredLiteral()
+	}
+
+	commitID, err := getDefaultBranchSha(repo)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	// an empty literal matches all the text files
+	stdout, err := git.NewCommand("grep", "--null", "--files-with-matches", "-I", "--ignore-case", "--fixed-strings",
+		"-e", literal, commitID, "--").RunInDirBytes(repo.RepoPath())
+	if err != nil {
+		// git grep exits with 1 when nothing matches
+		if err.Error() == "exit status 1" {
+			return 0, nil, nil, nil
+		}
+		return 0, nil, nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	var pathPattern *regexp.Regexp
+	if len(opts.Path) > 0 {
+		pathPattern = opts.pathPattern()
+	}
+	candidates := make([]*SearchResult, 0, 50)
+	for _, name := range bytes.Split(stdout, []byte{0}) {
+		if len(candidates) >= maxExactMatchCandidates {
+			break
+		}
+		// the names are prefixed by the searched revision
+		filename := strings.TrimPrefix(string(name), commitID+":")
+		if len(filename) == 0 {
+			continue
+		}
+		if setting.Indexer.ExcludeVendored && enry.IsVendor(filename) {
+			continue
+		}
+		if pathPattern != nil && !pathPattern.MatchString(filename) {
+			continue
+		}
+		candidate, err := grepCandidate(repo, commit, filename)
+		if err != nil {
+			return 0, nil, nil, err
+		} else if candidate != nil && (len(opts.Symbol) == 0 || opts.definesSymbol(candidate)) {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	total, results, languages := filterExactMatches(opts, pattern, candidates)
+	display, err := displayResults(opts, results)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return int(total), display, languages, nil
+}
+
+func grepCandidate(repo *models.Repository, commit *git.Commit, filename string) (*SearchResult, error) {
+	entry, err := commit.GetTreeEntryByPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	if (!entry.IsRegular() && !entry.IsExecutable()) || !isIndexablePath(filename) ||
+		entry.Blob().Size() > setting.Indexer.MaxIndexerFileSize {
+		return nil, nil
+	}
+
+	dataRc, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(content) {
+		return nil, nil
+	}
+
+	language := analyze.GetCodeLanguage(filename, content)
+	return &SearchResult{
+		RepoID:     repo.ID,
+		StartIndex: -1,
+		EndIndex:   -1,
+		Filename:   filename,
+		Content:    string(charset.ToUTF8DropErrors(content)),
+		CommitID:   commit.ID.String(),
+		Language:   language,
+		Color:      enry.GetColor(language),
+	}, nil
+}
@@ -39,11 +39,28 @@ type SearchResultLanguages struct {
 	Count    int
 }
 
+// SearchOptions represents the options of a code search
+type SearchOptions struct {
+	RepoIDs  []int64
+	Language string
+	Keyword  string
+	// Symbol restricts the search to the files defining a symbol of this name
+	Symbol string
+	// Path restricts the search to the files whose path contains it, * and ? are wildcards
+	Path string
+	// IsRegex makes the keyword a regular expression using the RE2 syntax
+	IsRegex         bool
+	IsCaseSensitive bool
+	IsWholeWord     bool
+	Page            int
+	PageSize        int
+}
+
 // Indexer defines an interface to index and search code contents
 type Indexer interface {
 	Index(repo *models.Repository, sha string, changes *repoChanges) error
 	Delete(repoID int64) error
-	Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error)
+	Search(opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error)
 	Close()
 }
 
@@ -26,6 +26,7 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 			keywords = []struct {
 				RepoIDs []int64
 				Keyword string
+				Path    string
 				IDs     []int64
 				Langs   int
 			}{
@@ -59,12 +60,32 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 					IDs:     []int64{},
 					Langs:   0,
 				},
+				{
+					RepoIDs: nil,
+					Keyword: "Description",
+					Path:    "readme",
+					IDs:     []int64{repoID},
+					Langs:   1,
+				},
+				{
+					RepoIDs: nil,
+					Path:    "*.md",
+					IDs:     []int64{repoID},
+					Langs:   1,
+				},
+				{
+					RepoIDs: nil,
+					Keyword: "Description",
+					Path:    "src/",
+					IDs:     []int64{},
+					Langs:   0,
+				},
 			}
 		)
 
 		for _, kw := range keywords {
-			t.Run(kw.Keyword, func(t *testing.T) {
-				total, res, langs, err := indexer.Search(kw.RepoIDs, "", kw.Keyword, 1, 10)
+			t.Run(kw.Keyword+kw.Path, func(t *testing.T) {
+				total, res, langs, err := indexer.Search(&SearchOptions{RepoIDs: kw.RepoIDs, Keyword: kw.Keyword, Path: kw.Path, Page: 1, PageSize: 10})
 				assert.NoError(t, err)
 				assert.EqualValues(t, len(kw.IDs), total)
 				assert.EqualValues(t, kw.Langs, len(langs))
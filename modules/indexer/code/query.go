@@ -0,0 +1,114 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/indexer/symbols"
+
+	"github.com/go-enry/go-enry/v2"
+)
+
+// qualifierPattern matches the qualifiers of a search query, e.g. symbol:ParseConfig or path:"cmd/web"
+var qualifierPattern = regexp.MustCompile(`(?:^|\s)(symbol|path|lang):("[^"]*"|\S+)`)
+
+// ParseKeyword sets the keyword of the options from a search query, the qualifiers of the
+// query restrict the search:
+// - symbol:name to the files defining a symbol
+// - path:value to the files whose path contains value, where * and ? are wildcards
+// - lang:name to the files of a language, which overrides the language of the options
+func (opts *SearchOptions) ParseKeyword(query string) {
+	keyword := qualifierPattern.ReplaceAllStringFunc(query, func(qualifier string) string {
+		m := qualifierPattern.FindStringSubmatch(qualifier)
+		value := strings.Trim(m[2], `"`)
+		switch m[1] {
+		case "symbol":
+			opts.Symbol = value
+		case "path":
+			opts.Path = value
+		case "lang":
+			if language, ok := enry.GetLanguageByAlias(value); ok {
+				opts.Language = language
+			} else {
+				opts.Language = value
+			}
+		}
+		return " "
+	})
+	opts.Keyword = strings.TrimSpace(keyword)
+}
+
+// IsEmpty returns whether the options don't search for anything
+func (opts *SearchOptions) IsEmpty() bool {
+	return len(opts.Keyword) == 0 && len(opts.Symbol) == 0 && len(opts.Path) == 0
+}
+
+// pathWildcard returns the wildcard expression matching the paths containing the path of the
+// options, it matches the lower case paths stored by the indexers
+func (opts *SearchOptions) pathWildcard() string {
+	return "*" + strings.ToLower(opts.Path) + "*"
+}
+
+// pathPattern returns the regular expression equivalent to pathWildcard
+func (opts *SearchOptions) pathPattern() *regexp.Regexp {
+	expr := regexp.QuoteMeta(opts.Path)
+	expr = strings.NewReplacer(`\*`, `.*`, `\?`, `.`).Replace(expr)
+	return regexp.MustCompile(`(?i)` + expr)
+}
+
+// symbolNames returns the distinct names of the symbols defined in a file, which are indexed
+func symbolNames(filename, language string, content []byte) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, 10)
+	for _, symbol := range symbols.ExtractFile(filename, language, content) {
+		if !seen[symbol.Name] {
+			seen[symbol.Name] = true
+			names = append(names, symbol.Name)
+		}
+	}
+	return names
+}
+
+// definesSymbol returns whether a file defines the symbol of the options
+func (opts *SearchOptions) definesSymbol(result *SearchResult) bool {
+	for _, name := range symbolNames(result.Filename, result.Language, []byte(result.Content)) {
+		if strings.EqualFold(name, opts.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// locateQualifiers sets the location of the match of a result found by the qualifiers only:
+// the definition of the searched symbol, or the beginning of the file
+func (opts *SearchOptions) locateQualifiers(result *SearchResult) {
+	result.StartIndex, result.EndIndex = 0, 0
+	if len(opts.Symbol) == 0 {
+		return
+	}
+
+	line := 0
+	for _, symbol := range symbols.Extract(result.Language, []byte(result.Content)) {
+		if strings.EqualFold(symbol.Name, opts.Symbol) {
+			line = symbol.Line
+			break
+		}
+	}
+	// the first occurrence of the name from the line of the definition
+	offset := 0
+	for i := 1; i < line; i++ {
+		next := strings.IndexByte(result.Content[offset:], '\n')
+		if next < 0 {
+			break
+		}
+		offset += next + 1
+	}
+	pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(opts.Symbol) + `\b`)
+	if loc := pattern.FindStringIndex(result.Content[offset:]); loc != nil {
+		result.StartIndex, result.EndIndex = offset+loc[0], offset+loc[1]
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchOptions_ParseKeyword(t *testing.T) {
+	kases := []struct {
+		query    string
+		expected SearchOptions
+	}{
+		{
+			query:    "foo bar",
+			expected: SearchOptions{Keyword: "foo bar"},
+		},
+		{
+			query:    "symbol:ParseConfig lang:go",
+			expected: SearchOptions{Symbol: "ParseConfig", Language: "Go"},
+		},
+		{
+			query:    `path:"cmd/web" err != nil lang:Unknown`,
+			expected: SearchOptions{Keyword: "err != nil", Path: "cmd/web", Language: "Unknown"},
+		},
+		{
+			query:    "notpath:foo",
+			expected: SearchOptions{Keyword: "notpath:foo"},
+		},
+	}
+	for _, kase := range kases {
+		var opts SearchOptions
+		opts.ParseKeyword(kase.query)
+		assert.Equal(t, kase.expected, opts, kase.query)
+	}
+
+	opts := &SearchOptions{Language: "Python"}
+	opts.ParseKeyword("lang:js")
+	assert.Equal(t, "JavaScript", opts.Language)
+	// a language alone doesn't search for anything
+	assert.True(t, opts.IsEmpty())
+}
+
+func TestSearchOptions_locateQualifiers(t *testing.T) {
+	result := &SearchResult{
+		Language: "Go",
+		Content:  "package config\n\n// ParseConfig parses\nfunc ParseConfig() {}\n",
+	}
+	opts := &SearchOptions{Symbol: "parseconfig"}
+	opts.locateQualifiers(result)
+	assert.Equal(t, "ParseConfig", result.Content[result.StartIndex:result.EndIndex])
+	assert.Equal(t, "func ParseConfig", result.Content[result.StartIndex-5:result.EndIndex])
+
+	opts = &SearchOptions{Path: "config"}
+	opts.locateQualifiers(result)
+	assert.Equal(t, 0, result.StartIndex)
+	assert.Equal(t, 0, result.EndIndex)
+
+	assert.True(t, (&SearchOptions{Path: "cmd/*.go"}).pathPattern().MatchString("CMD/web.go"))
+	assert.False(t, (&SearchOptions{Path: "cmd/*.go"}).pathPattern().MatchString("models/web.go"))
+}
@@ -5,9 +5,9 @@
 package code
 
 import (
-	"bytes"
 	"strings"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/highlight"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -22,6 +22,7 @@ type Result struct {
 	Language       string
 	Color          string
 	LineNumbers    []int
+	Lines          []string
 	FormattedLines string
 }
 
@@ -51,47 +52,23 @@ func indices(content string, selectionStartIndex, selectionEndIndex int) (int, i
 	return startIndex, endIndex
 }
 
-func writeStrings(buf *bytes.Buffer, strs ...string) error {
-	for _, s := range strs {
-		_, err := buf.WriteString(s)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func searchResult(result *SearchResult, startIndex, endIndex int) (*Result, error) {
 	startLineNum := 1 + strings.Count(result.Content[:startIndex], "\n")
 
-	var formattedLinesBuffer bytes.Buffer
-
-	contentLines := strings.SplitAfter(result.Content[startIndex:endIndex], "\n")
+	fragment := result.Content[startIndex:endIndex]
+	contentLines := strings.SplitAfter(fragment, "\n")
 	lineNumbers := make([]int, len(contentLines))
-	index := startIndex
+	lines := make([]string, len(contentLines))
 	for i, line := range contentLines {
-		var err error
-		if index < result.EndIndex &&
-			result.StartIndex < index+len(line) &&
-			result.StartIndex < result.EndIndex {
-			openActiveIndex := util.Max(result.StartIndex-index, 0)
-			closeActiveIndex := util.Min(result.EndIndex-index, len(line))
-			err = writeStrings(&formattedLinesBuffer,
-				line[:openActiveIndex],
-				line[openActiveIndex:closeActiveIndex],
-				line[closeActiveIndex:],
-			)
-		} else {
-			err = writeStrings(&formattedLinesBuffer,
-				line,
-			)
-		}
-		if err != nil {
-			return nil, err
-		}
-
 		lineNumbers[i] = startLineNum + i
-		index += len(line)
+		lines[i] = strings.TrimRight(line, "\r\n")
+	}
+
+	formattedLines := highlight.CodeWithLanguage(result.Filename, result.Language, fragment)
+	if result.StartIndex < result.EndIndex {
+		formattedLines = markMatch(formattedLines,
+			util.Max(result.StartIndex-startIndex, 0),
+			util.Min(result.EndIndex-startIndex, len(fragment)))
 	}
 	return &Result{
 		RepoID:         result.RepoID,
@@ -101,29 +78,110 @@ func searchResult(result *SearchResult, startIndex, endIndex int) (*Result, erro
 		Language:       result.Language,
 		Color:          result.Color,
 		LineNumbers:    lineNumbers,
-		FormattedLines: highlight.Code(result.Filename, formattedLinesBuffer.String()),
+		Lines:          lines,
+		FormattedLines: formattedLines,
 	}, nil
 }
 
-// PerformSearch perform a search on a repository
-func PerformSearch(repoIDs []int64, language, keyword string, page, pageSize int) (int, []*Result, []*SearchResultLanguages, error) {
-	if len(keyword) == 0 {
-		return 0, nil, nil, nil
-	}
+// markMatch wraps the text of highlighted code between two offsets of the original code
+// in <mark> elements. They are closed before the tags of the highlighted code so that they
+// are always well nested.
+func markMatch(formatted string, start, end int) string {
+	var buf strings.Builder
+	buf.Grow(len(formatted) + 32)
+
+	offset := 0
+	marked := false
+	for i := 0; i < len(formatted); {
+		if formatted[i] == '<' {
+			tagEnd := strings.IndexByte(formatted[i:], '>') + 1
+			if tagEnd <= 0 {
+				tagEnd = len(formatted) - i
+			}
+			if marked {
+				buf.WriteString("</mark>")
+				marked = false
+			}
+			buf.WriteString(formatted[i : i+tagEnd])
+			i += tagEnd
+			continue
+		}
 
-	total, results, resultLanguages, err := indexer.Search(repoIDs, language, keyword, page, pageSize)
-	if err != nil {
-		return 0, nil, nil, err
+		// a character of the original code, which may be escaped
+		size := 1
+		if formatted[i] == '&' {
+			if entityEnd := strings.IndexByte(formatted[i:], ';'); entityEnd > 0 {
+				size = entityEnd + 1
+			}
+		}
+		if inRange := start <= offset && offset < end; inRange != marked {
+			if inRange {
+				buf.WriteString("<mark>")
+			} else {
+				buf.WriteString("</mark>")
+			}
+			marked = inRange
+		}
+		buf.WriteString(formatted[i : i+size])
+		i += size
+		offset++
+	}
+	if marked {
+		buf.WriteString("</mark>")
 	}
+	return buf.String()
+}
 
+func displayResults(opts *SearchOptions, results []*SearchResult) ([]*Result, error) {
 	displayResults := make([]*Result, len(results))
-
 	for i, result := range results {
+		if result.StartIndex < 0 {
+			opts.locateQualifiers(result)
+		}
 		startIndex, endIndex := indices(result.Content, result.StartIndex, result.EndIndex)
+		var err error
 		displayResults[i], err = searchResult(result, startIndex, endIndex)
 		if err != nil {
-			return 0, nil, nil, err
+			return nil, err
 		}
 	}
-	return int(total), displayResults, resultLanguages, nil
+	return displayResults, nil
+}
+
+// PerformSearch perform a search on a repository
+func PerformSearch(opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
+	if opts.IsEmpty() {
+		return 0, nil, nil, nil
+	}
+
+	total, results, resultLanguages, err := indexer.Search(opts)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	display, err := displayResults(opts, results)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return int(total), display, resultLanguages, nil
+}
+
+// PerformUserSearch performs a search on the repositories whose code can be read by a user,
+// or by anyone if user is nil. If ownerID isn't 0, only the repositories of this owner are searched.
+func PerformUserSearch(user *models.User, ownerID int64, opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
+	// site admins can read everything, there is no need to list the repositories
+	if user != nil && user.IsAdmin && ownerID == 0 {
+		opts.RepoIDs = nil
+		return PerformSearch(opts)
+	}
+
+	repoIDs, err := models.FindUserCodeAccessibleRepoIDs(user, ownerID)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if len(repoIDs) == 0 {
+		return 0, nil, nil, nil
+	}
+	opts.RepoIDs = repoIDs
+	return PerformSearch(opts)
 }
@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestMarkMatch(t *testing.T) {
+	formatted := `<span class="kd">func</span> <span class="nf">a</span><span class="p">(</span><span class="s">&#34;x&lt;y&#34;</span><span class="p">)</span>`
+	// the original code is func a("x<y")
+	assert.Equal(t, `<span class="kd">func</span> <span class="nf"><mark>a</mark></span><span class="p"><mark>(</mark></span><span class="s"><mark>&#34;x&lt;</mark>y&#34;</span><span class="p">)</span>`,
+		markMatch(formatted, 5, 10))
+	assert.Equal(t, formatted, markMatch(formatted, 20, 30))
+	assert.Equal(t, "<mark>foo\nb</mark>ar", markMatch("foo\nbar", 0, 5))
+}
+
+func TestSearchResult(t *testing.T) {
+	setting.Cfg = ini.Empty()
+	content := "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"
+	result, err := searchResult(&SearchResult{
+		Filename:   "Makefile.go.txt",
+		Language:   "Go",
+		Content:    content,
+		StartIndex: 38,
+		EndIndex:   43,
+	}, 14, len(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6}, result.LineNumbers)
+	assert.Equal(t, "\tprintln(\"hello\")", result.Lines[1])
+	// the lexer of the language is used even though the file name doesn't match it
+	assert.Contains(t, result.FormattedLines, `<span class="kd">func</span>`)
+	assert.Contains(t, result.FormattedLines, `<mark>hello</mark>`)
+}
@@ -273,6 +273,8 @@ func UpdateRepoIndexer(repo *models.Repository) {
 		RepoIDs:  []int64{repo.ID},
 		IsClosed: util.OptionalBoolNone,
 		IsPull:   util.OptionalBoolNone,
+		// the indexer must not miss the latest changes of the issues
+		UsePrimary: true,
 	})
 	if err != nil {
 		log.Error("Issues: %v", err)
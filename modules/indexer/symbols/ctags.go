@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ctagsTimeout is the time given to ctags to process a single file
+const ctagsTimeout = 30 * time.Second
+
+// ctagsTag is a line of the JSON output of Universal Ctags
+type ctagsTag struct {
+	Type string `json:"_type"`
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+}
+
+// ExtractWithCtags returns the definitions found by Universal Ctags in the content of a file.
+// Ctags chooses the parser from the file name, so the file is written with the same base name.
+func ExtractWithCtags(filename string, content []byte) ([]*Symbol, error) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "gitea-symbols")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := filepath.Join(tmpDir, path.Base(filename))
+	if err := ioutil.WriteFile(tmpFile, content, 0600); err != nil {
+		return nil, fmt.Errorf("Failed to write temporary file: %v", err)
+	}
+
+	stdout, stderr, err := process.GetManager().ExecDir(ctagsTimeout, tmpDir,
+		fmt.Sprintf("ExtractWithCtags: %s", filename),
+		setting.Indexer.SymbolIndexerCtagsPath,
+		"--output-format=json", "--fields=+nK", "-o", "-", path.Base(filename))
+	if err != nil {
+		return nil, fmt.Errorf("ctags: %v - %s", err, stderr)
+	}
+
+	symbols := make([]*Symbol, 0, 10)
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var tag ctagsTag
+		if err := json.Unmarshal(scanner.Bytes(), &tag); err != nil {
+			return nil, fmt.Errorf("Unable to parse ctags output %q: %v", scanner.Text(), err)
+		}
+		if tag.Type != "tag" || len(tag.Name) == 0 || tag.Line <= 0 {
+			continue
+		}
+		if len(tag.Kind) > 20 {
+			tag.Kind = tag.Kind[:20]
+		}
+		symbols = append(symbols, &Symbol{
+			Name: tag.Name,
+			Kind: tag.Kind,
+			Line: tag.Line,
+		})
+		if len(symbols) >= MaxSymbolsPerFile {
+			break
+		}
+	}
+	return symbols, scanner.Err()
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-enry/go-enry/v2"
+)
+
+// DBIndexer implements Indexer interface to store the symbols in the database
+type DBIndexer struct {
+}
+
+// Index extracts the symbols of the files of the default branch of a repository
+// which changed since the last indexed commit
+func (db *DBIndexer) Index(id int64) error {
+	repo, err := models.GetRepositoryByID(id)
+	if err != nil {
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeSymbols)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+	if err != nil {
+		return err
+	}
+	if status.CommitSha == commitID {
+		return nil
+	}
+
+	var changed map[string]bool
+	if len(status.CommitSha) > 0 {
+		changed, err = getChangedPaths(repo, status.CommitSha, commitID)
+		if err != nil {
+			// the previous commit may have been removed by a force push, start afresh
+			log.Warn("Unable to get the changes of %s since %s, reindexing all the symbols: %v", repo.FullName(), status.CommitSha, err)
+			changed = nil
+		}
+	}
+
+	stdout, err := git.NewCommand("ls-tree", "--full-tree", "-r", commitID).RunInDirBytes(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	entries, err := git.ParseTreeEntries(stdout)
+	if err != nil {
+		return err
+	}
+
+	symbols := make([]*models.RepoSymbol, 0, 100)
+	for _, entry := range entries {
+		if changed != nil && !changed[entry.Name()] {
+			continue
+		}
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			continue
+		}
+		if setting.Indexer.ExcludeVendored && enry.IsVendor(entry.Name()) {
+			continue
+		}
+		fileSymbols, err := extractBlob(gitRepo, entry.Name(), entry.ID.String())
+		if err != nil {
+			return err
+		}
+		symbols = append(symbols, fileSymbols...)
+	}
+
+	var paths []string
+	if changed != nil {
+		paths = make([]string, 0, len(changed))
+		for path := range changed {
+			paths = append(paths, path)
+		}
+	}
+	return repo.UpdateSymbols(commitID, paths, symbols)
+}
+
+// getChangedPaths returns the paths added, modified or removed between two commits
+func getChangedPaths(repo *models.Repository, oldCommitID, newCommitID string) (map[string]bool, error) {
+	stdout, err := git.NewCommand("diff", "--name-only", "--no-renames", "-z", oldCommitID, newCommitID).
+		RunInDirBytes(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, path := range bytes.Split(stdout, []byte{0}) {
+		if len(path) > 0 {
+			changed[string(path)] = true
+		}
+	}
+	return changed, nil
+}
+
+func extractBlob(gitRepo *git.Repository, filename, blobSHA string) ([]*models.RepoSymbol, error) {
+	blob, err := gitRepo.GetBlob(blobSHA)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Size() > setting.Indexer.MaxIndexerFileSize {
+		return nil, nil
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(content) {
+		return nil, nil
+	}
+
+	language := analyze.GetCodeLanguage(filename, content)
+	symbols := ExtractFile(filename, language, content)
+
+	repoSymbols := make([]*models.RepoSymbol, 0, len(symbols))
+	for _, symbol := range symbols {
+		if len(symbol.Name) > 255 {
+			continue
+		}
+		repoSymbols = append(repoSymbols, &models.RepoSymbol{
+			Name:     symbol.Name,
+			Kind:     symbol.Kind,
+			Language: language,
+			Path:     filename,
+			BlobSHA:  blobSHA,
+			Line:     symbol.Line,
+		})
+	}
+	return repoSymbols, nil
+}
+
+// Close dummy function
+func (db *DBIndexer) Close() {
+}
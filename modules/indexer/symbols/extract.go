@@ -0,0 +1,163 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"bytes"
+	"regexp"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MaxSymbolsPerFile is the maximum number of symbols extracted from a single file
+const MaxSymbolsPerFile = 1000
+
+// Symbol represents a definition found in a file
+type Symbol struct {
+	Name string
+	Kind string
+	Line int
+}
+
+type symbolPattern struct {
+	kind string
+	// the first group of the regexp must match the name of the symbol
+	regexp *regexp.Regexp
+}
+
+func patterns(kindAndRegexps ...string) []symbolPattern {
+	list := make([]symbolPattern, 0, len(kindAndRegexps)/2)
+	for i := 0; i+1 < len(kindAndRegexps); i += 2 {
+		list = append(list, symbolPattern{
+			kind:   kindAndRegexps[i],
+			regexp: regexp.MustCompile(kindAndRegexps[i+1]),
+		})
+	}
+	return list
+}
+
+var (
+	cLikePatterns = patterns(
+		"macro", `^\s*#\s*define\s+([A-Za-z_]\w*)`,
+		"type", `^\s*(?:typedef\s+)?(?:struct|union|enum|class)\s+([A-Za-z_]\w*)\s*(?:[:{].*)?$`,
+		"function", `^(?:[A-Za-z_][\w:<>,]*[\s*&]+)+([A-Za-z_]\w*)\s*\([^;]*\)\s*(?:const\s*)?\{?\s*$`,
+	)
+	javaLikePatterns = patterns(
+		"class", `^\s*(?:(?:public|private|protected|internal|static|final|abstract|sealed|partial|open|data)\s+)*(?:class|interface|enum|record|struct|object)\s+([A-Za-z_]\w*)`,
+		"method", `^\s*(?:(?:public|private|protected|internal|static|final|abstract|synchronized|override|virtual|async|native)\s+)+[\w<>\[\],.?]+\s+([A-Za-z_]\w*)\s*\(`,
+	)
+	jsPatterns = patterns(
+		"function", `^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`,
+		"class", `^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)`,
+		"function", `^\s*(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s*)?(?:function\b|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>)`,
+		"interface", `^\s*(?:export\s+)?(?:declare\s+)?interface\s+([A-Za-z_$][\w$]*)`,
+		"type", `^\s*(?:export\s+)?(?:declare\s+)?type\s+([A-Za-z_$][\w$]*)\s*(?:<[^=]*>)?\s*=`,
+		"enum", `^\s*(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+([A-Za-z_$][\w$]*)`,
+	)
+
+	languagePatterns = map[string][]symbolPattern{
+		"Go": patterns(
+			"method", `^func\s+\([^)]*\)\s*([A-Za-z_]\w*)`,
+			"function", `^func\s+([A-Za-z_]\w*)`,
+			"type", `^type\s+([A-Za-z_]\w*)`,
+			"constant", `^const\s+([A-Za-z_]\w*)`,
+			"variable", `^var\s+([A-Za-z_]\w*)`,
+		),
+		"Python": patterns(
+			"function", `^\s*(?:async\s+)?def\s+([A-Za-z_]\w*)`,
+			"class", `^\s*class\s+([A-Za-z_]\w*)`,
+		),
+		"JavaScript": jsPatterns,
+		"TypeScript": jsPatterns,
+		"TSX":        jsPatterns,
+		"Vue":        jsPatterns,
+		"Java":       javaLikePatterns,
+		"C#":         javaLikePatterns,
+		"Kotlin": append(patterns(
+			"function", `^\s*(?:(?:public|private|protected|internal|override|open|suspend|inline)\s+)*fun\s+(?:<[^>]*>\s*)?(?:[\w.]+\.)?([A-Za-z_]\w*)`,
+		), javaLikePatterns[0]),
+		"C":   cLikePatterns,
+		"C++": cLikePatterns,
+		"Rust": patterns(
+			"function", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?(?:extern\s+"[^"]*"\s+)?fn\s+([A-Za-z_]\w*)`,
+			"type", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:struct|enum|union|type)\s+([A-Za-z_]\w*)`,
+			"trait", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:unsafe\s+)?trait\s+([A-Za-z_]\w*)`,
+			"module", `^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+([A-Za-z_]\w*)`,
+			"macro", `^\s*macro_rules!\s+([A-Za-z_]\w*)`,
+		),
+		"Ruby": patterns(
+			"method", `^\s*def\s+(?:self\.)?([A-Za-z_]\w*[?!=]?)`,
+			"class", `^\s*class\s+(?:[A-Z]\w*::)*([A-Z]\w*)`,
+			"module", `^\s*module\s+(?:[A-Z]\w*::)*([A-Z]\w*)`,
+		),
+		"PHP": patterns(
+			"function", `^\s*(?:(?:abstract|final|public|private|protected|static)\s+)*function\s+&?([A-Za-z_]\w*)`,
+			"class", `^\s*(?:(?:abstract|final)\s+)*(?:class|interface|trait|enum)\s+([A-Za-z_]\w*)`,
+		),
+		"Shell": patterns(
+			"function", `^\s*(?:function\s+)?([A-Za-z_][\w-]*)\s*\(\)`,
+			"function", `^\s*function\s+([A-Za-z_][\w-]*)`,
+		),
+	}
+
+	// words which the patterns of languages with C like syntax may catch as names
+	keywords = map[string]bool{
+		"if": true, "else": true, "for": true, "while": true, "switch": true, "return": true,
+		"catch": true, "sizeof": true, "new": true, "delete": true, "do": true,
+	}
+)
+
+// IsSupportedLanguage returns whether symbols can be extracted from files of the given language
+// without an external tool
+func IsSupportedLanguage(language string) bool {
+	_, ok := languagePatterns[language]
+	return ok
+}
+
+// Extract returns the definitions found in the content of a file of the given language
+func Extract(language string, content []byte) []*Symbol {
+	list, ok := languagePatterns[language]
+	if !ok {
+		return nil
+	}
+
+	symbols := make([]*Symbol, 0, 10)
+	for i, line := range bytes.Split(content, []byte{'\n'}) {
+		for _, pattern := range list {
+			m := pattern.regexp.FindSubmatch(line)
+			if m == nil {
+				continue
+			}
+			name := string(m[1])
+			if keywords[name] {
+				continue
+			}
+			symbols = append(symbols, &Symbol{
+				Name: name,
+				Kind: pattern.kind,
+				Line: i + 1,
+			})
+			if len(symbols) >= MaxSymbolsPerFile {
+				return symbols
+			}
+			break
+		}
+	}
+	return symbols
+}
+
+// ExtractFile returns the definitions found in the content of a file of the given language,
+// with Universal Ctags if it is configured
+func ExtractFile(filename, language string, content []byte) []*Symbol {
+	if len(setting.Indexer.SymbolIndexerCtagsPath) > 0 {
+		symbols, err := ExtractWithCtags(filename, content)
+		if err == nil {
+			return symbols
+		}
+		log.Error("ExtractWithCtags [%s]: %v", filename, err)
+	}
+	return Extract(language, content)
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	kases := []struct {
+		language string
+		content  string
+		expected []*Symbol
+	}{
+		{
+			language: "Go",
+			content: `package foo
+
+// Bar is a type
+type Bar struct{}
+
+func (b *Bar) Baz() {
+	if true {
+	}
+}
+
+func NewBar() *Bar {
+	return &Bar{}
+}
+
+const MaxBar = 1
+`,
+			expected: []*Symbol{
+				{Name: "Bar", Kind: "type", Line: 4},
+				{Name: "Baz", Kind: "method", Line: 6},
+				{Name: "NewBar", Kind: "function", Line: 11},
+				{Name: "MaxBar", Kind: "constant", Line: 15},
+			},
+		},
+		{
+			language: "Python",
+			content: `class Foo(object):
+    def bar(self):
+        return 1
+
+async def baz():
+    pass
+`,
+			expected: []*Symbol{
+				{Name: "Foo", Kind: "class", Line: 1},
+				{Name: "bar", Kind: "function", Line: 2},
+				{Name: "baz", Kind: "function", Line: 5},
+			},
+		},
+		{
+			language: "JavaScript",
+			content: `export default function init() {}
+export class Foo extends Bar {}
+const handler = async (e) => {
+  if (e) {
+    return foo(e);
+  }
+};
+const value = 1;
+`,
+			expected: []*Symbol{
+				{Name: "init", Kind: "function", Line: 1},
+				{Name: "Foo", Kind: "class", Line: 2},
+				{Name: "handler", Kind: "function", Line: 3},
+			},
+		},
+		{
+			language: "C",
+			content: `#define MAX 10
+struct foo {
+	int a;
+};
+static int add(int a, int b)
+{
+	if (a > b)
+		return a;
+	return add(b, a);
+}
+`,
+			expected: []*Symbol{
+				{Name: "MAX", Kind: "macro", Line: 1},
+				{Name: "foo", Kind: "type", Line: 2},
+				{Name: "add", Kind: "function", Line: 5},
+			},
+		},
+		{
+			language: "Markdown",
+			content:  "# func Foo()\n",
+			expected: nil,
+		},
+	}
+
+	for _, kase := range kases {
+		symbols := Extract(kase.language, []byte(kase.content))
+		if kase.expected == nil {
+			assert.Empty(t, symbols, kase.language)
+			continue
+		}
+		assert.EqualValues(t, kase.expected, symbols, kase.language)
+	}
+}
+
+func TestLinkIdentifiers(t *testing.T) {
+	lines := map[int]string{
+		1: `<span class="kd">func</span> <span class="nf">Foo</span><span class="p">(</span><span class="p">)</span>`,
+		2: `<span class="k">return</span> <span class="nf">Bar</span><span class="p">(</span><span class="nx">baz</span><span class="p">)</span>`,
+	}
+	assert.ElementsMatch(t, []string{"Foo", "Bar", "baz"}, FindIdentifiers(lines))
+
+	LinkIdentifiers(lines, func(name string, line int) string {
+		if name == "Bar" {
+			return "/user2/repo1/src/commit/sha/bar.go#L4"
+		}
+		return ""
+	})
+	assert.Equal(t, `<span class="kd">func</span> <span class="nf">Foo</span><span class="p">(</span><span class="p">)</span>`, lines[1])
+	assert.Equal(t, `<span class="k">return</span> <span class="nf"><a class="symbol-link" href="/user2/repo1/src/commit/sha/bar.go#L4">Bar</a></span><span class="p">(</span><span class="nx">baz</span><span class="p">)</span>`, lines[2])
+}
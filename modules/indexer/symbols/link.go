@@ -0,0 +1,44 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"html"
+	"regexp"
+)
+
+// identifierPattern matches the names highlighted by chroma, e.g. <span class="nf">Foo</span>
+var identifierPattern = regexp.MustCompile(`<span class="(n[a-z]?)">([A-Za-z_$][\w$]*)</span>`)
+
+// FindIdentifiers returns the distinct names found in the highlighted lines of a file
+func FindIdentifiers(lines map[int]string) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, 50)
+	for _, line := range lines {
+		for _, m := range identifierPattern.FindAllStringSubmatch(line, -1) {
+			if !seen[m[2]] {
+				seen[m[2]] = true
+				names = append(names, m[2])
+			}
+		}
+	}
+	return names
+}
+
+// LinkIdentifiers turns the names of the highlighted lines of a file into links to their
+// definition. definitionLink returns the link of a name found on a line, or an empty string
+// if it shouldn't be linked.
+func LinkIdentifiers(lines map[int]string, definitionLink func(name string, line int) string) {
+	for num, line := range lines {
+		lines[num] = identifierPattern.ReplaceAllStringFunc(line, func(span string) string {
+			m := identifierPattern.FindStringSubmatch(span)
+			link := definitionLink(m[2], num)
+			if len(link) == 0 {
+				return span
+			}
+			return `<span class="` + m[1] + `"><a class="symbol-link" href="` + html.EscapeString(link) + `">` + m[2] + `</a></span>`
+		})
+	}
+}
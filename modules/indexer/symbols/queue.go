@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package symbols
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// symbolsQueue represents a queue to handle repository symbols updates
+var symbolsQueue queue.UniqueQueue
+
+// handle passed repository IDs and index their symbols
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		opts := datum.(int64)
+		if err := indexer.Index(opts); err != nil {
+			log.Error("symbols queue indexer.Index(%d) failed: %v", opts, err)
+		}
+	}
+}
+
+func initSymbolsQueue() error {
+	symbolsQueue = queue.CreateUniqueQueue("repo_symbols_update", handle, int64(0)).(queue.UniqueQueue)
+	if symbolsQueue == nil {
+		return fmt.Errorf("Unable to create repo_symbols_update Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(symbolsQueue.Run)
+
+	return nil
+}
+
+// UpdateRepoIndexer update a repository's symbols in the indexer
+func UpdateRepoIndexer(repo *models.Repository) error {
+	if !setting.Indexer.SymbolIndexerEnabled {
+		return nil
+	}
+	if err := symbolsQueue.Push(repo.ID); err != nil {
+		if err != queue.ErrAlreadyInQueue {
+			return err
+		}
+		log.Debug("Repo ID: %d already queued", repo.ID)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/markup/markdown"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"gopkg.in/yaml.v2"
+)
+
+var fieldIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// IsForm returns whether the file of a template is an issue form
+func IsForm(filename string) bool {
+	ext := strings.ToLower(path.Ext(filename))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// IsTemplate returns whether the file is a markdown template or an issue form
+func IsTemplate(filename string) bool {
+	return strings.HasSuffix(filename, ".md") || IsForm(filename)
+}
+
+// Unmarshal parses the content of a markdown template or of an issue form and validates it
+func Unmarshal(filename string, content []byte) (*api.IssueTemplate, error) {
+	it := &api.IssueTemplate{}
+	if IsForm(filename) {
+		if err := yaml.Unmarshal(content, it); err != nil {
+			return nil, err
+		}
+		// the fields without an ID are submitted by position
+		for i, field := range it.Fields {
+			if field != nil && len(field.ID) == 0 {
+				field.ID = strconv.Itoa(i)
+			}
+		}
+	} else {
+		body, err := markdown.ExtractMetadata(string(content), it)
+		if err != nil {
+			return nil, err
+		}
+		it.Content = body
+	}
+	it.FileName = path.Base(filename)
+
+	if err := Validate(it); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// Validate checks whether a template is valid, the fields of an issue form must have a known type,
+// a unique ID and the attributes their type requires
+func Validate(it *api.IssueTemplate) error {
+	if !it.Valid() {
+		return errors.New("a template must have a name and an about")
+	}
+
+	ids := make(map[string]bool, len(it.Fields))
+	for i, field := range it.Fields {
+		if field == nil {
+			return fmt.Errorf("field %d is empty", i)
+		}
+		if !fieldIDPattern.MatchString(field.ID) {
+			return fmt.Errorf("field %d: invalid id %q", i, field.ID)
+		}
+		if ids[field.ID] {
+			return fmt.Errorf("field %d: duplicated id %q", i, field.ID)
+		}
+		ids[field.ID] = true
+
+		switch field.Type {
+		case api.IssueFormFieldTypeMarkdown:
+			if len(strings.TrimSpace(field.Attributes.Value)) == 0 {
+				return fmt.Errorf("field %d: a markdown field must have a value", i)
+			}
+			continue
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if len(field.Attributes.Options) > 0 {
+				return fmt.Errorf("field %d: a %s field can't have options", i, field.Type)
+			}
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			if len(field.Attributes.Options) == 0 {
+				return fmt.Errorf("field %d: a %s field must have options", i, field.Type)
+			}
+			for j, option := range field.Attributes.Options {
+				if option == nil || len(strings.TrimSpace(option.Label)) == 0 {
+					return fmt.Errorf("field %d: option %d must have a label", i, j)
+				}
+			}
+		default:
+			return fmt.Errorf("field %d: unknown type %q", i, field.Type)
+		}
+		if len(strings.TrimSpace(field.Attributes.Label)) == 0 {
+			return fmt.Errorf("field %d: a %s field must have a label", i, field.Type)
+		}
+	}
+	return nil
+}
+
+// FieldName returns the name of the form value of a field, or of an option of a checkboxes field
+func FieldName(field *api.IssueFormField, option ...int) string {
+	if field.Type == api.IssueFormFieldTypeCheckboxes && len(option) > 0 {
+		return fmt.Sprintf("form-field-%s-%d", field.ID, option[0])
+	}
+	return "form-field-" + field.ID
+}
+
+// ErrInvalidFieldValue represents an invalid value submitted for a field of an issue form
+type ErrInvalidFieldValue struct {
+	Field *api.IssueFormField
+	// the label of the option of a checkboxes field which must be checked,
+	// empty if the value of the field is missing or isn't an option
+	Option string
+}
+
+// IsErrInvalidFieldValue checks if an error is a ErrInvalidFieldValue.
+func IsErrInvalidFieldValue(err error) bool {
+	_, ok := err.(ErrInvalidFieldValue)
+	return ok
+}
+
+func (err ErrInvalidFieldValue) Error() string {
+	if len(err.Option) > 0 {
+		return fmt.Sprintf("option %q of field %q must be checked", err.Option, err.Field.ID)
+	}
+	return fmt.Sprintf("invalid value of field %q", err.Field.ID)
+}
+
+// fieldValues returns the submitted non-empty values of a text field or a dropdown
+func fieldValues(field *api.IssueFormField, values url.Values) []string {
+	submitted := make([]string, 0, 1)
+	for _, value := range values[FieldName(field)] {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			submitted = append(submitted, value)
+		}
+	}
+	return submitted
+}
+
+func isOption(field *api.IssueFormField, value string) bool {
+	for _, option := range field.Attributes.Options {
+		if option.Label == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateValues checks the values submitted for an issue form, it returns an ErrInvalidFieldValue
+// if a required value is missing or if a value of a dropdown isn't one of its options
+func ValidateValues(it *api.IssueTemplate, values url.Values) error {
+	for _, field := range it.Fields {
+		switch field.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if field.Validations.Required && len(fieldValues(field, values)) == 0 {
+				return ErrInvalidFieldValue{Field: field}
+			}
+		case api.IssueFormFieldTypeDropdown:
+			submitted := fieldValues(field, values)
+			if field.Validations.Required && len(submitted) == 0 ||
+				!field.Attributes.Multiple && len(submitted) > 1 {
+				return ErrInvalidFieldValue{Field: field}
+			}
+			for _, value := range submitted {
+				if !isOption(field, value) {
+					return ErrInvalidFieldValue{Field: field}
+				}
+			}
+		case api.IssueFormFieldTypeCheckboxes:
+			for i, option := range field.Attributes.Options {
+				if option.Required && len(values.Get(FieldName(field, i))) == 0 {
+					return ErrInvalidFieldValue{Field: field, Option: option.Label}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RenderToMarkdown renders the submitted values of an issue form into the markdown content of
+// the issue, with a section per field
+func RenderToMarkdown(it *api.IssueTemplate, values url.Values) string {
+	var builder strings.Builder
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString("### ")
+		builder.WriteString(field.Attributes.Label)
+		builder.WriteString("\n\n")
+
+		switch field.Type {
+		case api.IssueFormFieldTypeCheckboxes:
+			for i, option := range field.Attributes.Options {
+				if i > 0 {
+					builder.WriteString("\n")
+				}
+				if len(values.Get(FieldName(field, i))) > 0 {
+					builder.WriteString("- [x] ")
+				} else {
+					builder.WriteString("- [ ] ")
+				}
+				builder.WriteString(option.Label)
+			}
+			continue
+		}
+
+		submitted := fieldValues(field, values)
+		switch {
+		case len(submitted) == 0:
+			builder.WriteString("_No response_")
+		case field.Type == api.IssueFormFieldTypeTextarea && len(field.Attributes.Render) > 0:
+			fmt.Fprintf(&builder, "```%s\n%s\n```", field.Attributes.Render, submitted[0])
+		default:
+			builder.WriteString(strings.Join(submitted, ", "))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bugReportForm = `name: Bug Report
+about: File a bug report
+title: "[Bug]: "
+labels: ["bug"]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: version
+    attributes:
+      label: Version
+      placeholder: "1.13.0"
+    validations:
+      required: true
+  - type: dropdown
+    id: database
+    attributes:
+      label: Database
+      options:
+        - SQLite
+        - MySQL
+  - type: textarea
+    attributes:
+      label: Logs
+      render: shell
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow the Code of Conduct
+          required: true
+        - label: I searched the existing issues
+`
+
+func TestUnmarshal(t *testing.T) {
+	it, err := Unmarshal(".gitea/ISSUE_TEMPLATE/bug.yml", []byte(bugReportForm))
+	assert.NoError(t, err)
+	assert.True(t, it.IsForm())
+	assert.Equal(t, "bug.yml", it.FileName)
+	assert.Equal(t, "[Bug]: ", it.Title)
+	assert.Equal(t, []string{"bug"}, it.Labels)
+	assert.Len(t, it.Fields, 5)
+	assert.Equal(t, api.IssueFormFieldTypeDropdown, it.Fields[2].Type)
+	assert.Equal(t, "MySQL", it.Fields[2].Attributes.Options[1].Label)
+	assert.Equal(t, "3", it.Fields[3].ID)
+	assert.True(t, it.Fields[4].Attributes.Options[0].Required)
+	assert.False(t, it.Fields[4].Attributes.Options[1].Required)
+
+	it, err = Unmarshal("bug.md", []byte("---\nname: Bug\nabout: A bug\n---\nContent"))
+	assert.NoError(t, err)
+	assert.False(t, it.IsForm())
+	assert.Equal(t, "Content", it.Content)
+
+	for _, form := range []string{
+		"about: no name",
+		"name: Bug\nabout: A bug\nbody:\n  - type: unknown\n    attributes:\n      label: Label",
+		"name: Bug\nabout: A bug\nbody:\n  - type: input\n    attributes:\n      placeholder: no label",
+		"name: Bug\nabout: A bug\nbody:\n  - type: dropdown\n    attributes:\n      label: No options",
+		"name: Bug\nabout: A bug\nbody:\n  - type: markdown\n    attributes:\n      label: no value",
+		"name: Bug\nabout: A bug\nbody:\n  - type: input\n    id: a b\n    attributes:\n      label: Invalid ID",
+		"name: Bug\nabout: A bug\nbody:\n  - type: input\n    id: a\n    attributes:\n      label: A\n  - type: input\n    id: a\n    attributes:\n      label: B",
+	} {
+		_, err = Unmarshal("bug.yaml", []byte(form))
+		assert.Error(t, err, form)
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	it, err := Unmarshal("bug.yml", []byte(bugReportForm))
+	assert.NoError(t, err)
+
+	values := url.Values{
+		"form-field-version":  {"1.13.1"},
+		"form-field-database": {"SQLite"},
+		"form-field-terms-0":  {"on"},
+	}
+	assert.NoError(t, ValidateValues(it, values))
+
+	values.Set("form-field-version", " ")
+	err = ValidateValues(it, values)
+	assert.True(t, IsErrInvalidFieldValue(err))
+	assert.Equal(t, "version", err.(ErrInvalidFieldValue).Field.ID)
+	values.Set("form-field-version", "1.13.1")
+
+	values["form-field-database"] = []string{"SQLite", "MySQL"}
+	assert.True(t, IsErrInvalidFieldValue(ValidateValues(it, values)))
+	values.Set("form-field-database", "PostgreSQL")
+	assert.True(t, IsErrInvalidFieldValue(ValidateValues(it, values)))
+	values.Set("form-field-database", "MySQL")
+
+	values.Del("form-field-terms-0")
+	err = ValidateValues(it, values)
+	assert.True(t, IsErrInvalidFieldValue(err))
+	assert.Equal(t, "I agree to follow the Code of Conduct", err.(ErrInvalidFieldValue).Option)
+}
+
+func TestRenderToMarkdown(t *testing.T) {
+	it, err := Unmarshal("bug.yml", []byte(bugReportForm))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "### Version\n\n1.13.1\n\n"+
+		"### Database\n\n_No response_\n\n"+
+		"### Logs\n\n```shell\npanic: oops\n```\n\n"+
+		"### Code of Conduct\n\n- [x] I agree to follow the Code of Conduct\n- [ ] I searched the existing issues",
+		RenderToMarkdown(it, url.Values{
+			"form-field-version":   {"1.13.1"},
+			"form-field-3":         {"panic: oops"},
+			"form-field-terms-0":   {"on"},
+			"form-field-unrelated": {"ignored"},
+		}))
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package legal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/unknwon/com"
+)
+
+var (
+	lock  sync.RWMutex
+	pages []*models.LegalPage
+	// contents are the templates of the pages by the names of their files
+	contents map[string]string
+)
+
+func templateDir() string {
+	return path.Join(setting.CustomPath, "templates", "legal")
+}
+
+// Init loads the templates of the legal pages and records a new version of each page whose template changed
+func Init() error {
+	files, err := ioutil.ReadDir(templateDir())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	currentContents := make(map[string]string, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".tmpl") {
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(templateDir(), file.Name()))
+		if err != nil {
+			return err
+		}
+		currentContents[strings.TrimSuffix(file.Name(), ".tmpl")] = string(content)
+	}
+
+	current := make([]*models.LegalPage, 0, len(setting.Legal.Pages))
+	for _, name := range setting.Legal.Pages {
+		content, ok := currentContents[name]
+		if !ok {
+			log.Debug("Legal page %s has no template, it is ignored", name)
+			continue
+		}
+		hash := sha256.Sum256([]byte(content))
+		page, err := models.UpdateLegalPage(name, hex.EncodeToString(hash[:]))
+		if err != nil {
+			return err
+		}
+		current = append(current, page)
+	}
+
+	lock.Lock()
+	pages = current
+	contents = currentContents
+	lock.Unlock()
+	return nil
+}
+
+// Pages returns the latest versions of the legal pages
+func Pages() []*models.LegalPage {
+	lock.RLock()
+	defer lock.RUnlock()
+	return pages
+}
+
+// Page returns the latest version of the legal page of the name, nil if there is none
+func Page(name string) *models.LegalPage {
+	for _, page := range Pages() {
+		if page.Name == name {
+			return page
+		}
+	}
+	return nil
+}
+
+// Content returns the template of a legal page in a language,
+// the template of the page is used when it isn't translated to the language
+func Content(name, lang string) string {
+	lock.RLock()
+	defer lock.RUnlock()
+	if content, ok := contents[name+"."+lang]; ok && len(lang) > 0 {
+		return content
+	}
+	return contents[name]
+}
+
+// Fingerprint returns a string identifying the latest versions of the legal pages
+func Fingerprint() string {
+	ids := make([]string, 0, len(Pages()))
+	for _, page := range Pages() {
+		ids = append(ids, com.ToStr(page.ID))
+	}
+	return strings.Join(ids, ",")
+}
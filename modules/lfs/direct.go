@@ -0,0 +1,237 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+const (
+	// transferMultipart is the multipart-basic transfer adapter, the clients upload the parts
+	// of an object with separate requests then ask the server to assemble them.
+	// https://github.com/datopian/giftless/blob/master/docs/source/multipart-spec.md
+	transferMultipart = "multipart-basic"
+
+	// maxSinglePutSize is the largest object S3 accepts in a single PUT request
+	maxSinglePutSize = 5 * 1024 * 1024 * 1024
+	// maxMultipartParts is the maximum number of parts of a multipart upload accepted by S3
+	maxMultipartParts = 10000
+)
+
+// partLink is the link to upload a part of an object with the multipart-basic transfer adapter
+type partLink struct {
+	link
+	Pos  int64 `json:"pos"`
+	Size int64 `json:"size"`
+}
+
+// MultipartLink builds a URL to complete or abort the multipart upload of the object.
+func (v *RequestVars) MultipartLink(action, uploadID string) string {
+	return v.ObjectLink() + "/multipart/" + action + "?upload_id=" + url.QueryEscape(uploadID)
+}
+
+// directUploadStorage returns the LFS storage if the clients may upload the objects to it directly
+func directUploadStorage() (storage.DirectUploadStorage, bool) {
+	if !setting.LFS.DirectUpload {
+		return nil, false
+	}
+	direct, ok := storage.LFS.(storage.DirectUploadStorage)
+	return direct, ok
+}
+
+func hasTransfer(transfers []string, transfer string) bool {
+	for _, t := range transfers {
+		if t == transfer {
+			return true
+		}
+	}
+	return false
+}
+
+// stagingPath returns the path the clients upload an object to. As the objects are shared by
+// the repositories, they are only moved to their final path once their hash has been checked.
+func stagingPath(meta *models.LFSMetaObject) string {
+	return path.Join("tmp", strconv.FormatInt(meta.RepositoryID, 10), meta.Oid)
+}
+
+// multipartPartSize returns the size of the parts an object is uploaded in
+func multipartPartSize(size int64) int64 {
+	partSize := setting.LFS.MultipartPartSize
+	if size > partSize*maxMultipartParts {
+		partSize = (size + maxMultipartParts - 1) / maxMultipartParts
+	}
+	return partSize
+}
+
+// representDirectUpload returns the representation of an object the client uploads to the
+// storage with presigned URLs, the verify action then checks and moves it to its final path
+func representDirectUpload(rv *RequestVars, meta *models.LFSMetaObject, direct storage.DirectUploadStorage, multipart bool) (*Representation, error) {
+	rep := &Representation{
+		Oid:     meta.Oid,
+		Size:    meta.Size,
+		Actions: make(map[string]interface{}),
+	}
+
+	header := authorizationHeader(rv)
+	expiry := setting.LFS.HTTPAuthExpiry
+	expiresAt := time.Now().Add(expiry)
+	p := stagingPath(meta)
+
+	if !multipart {
+		u, err := direct.UploadURL(p, expiry)
+		if err != nil {
+			return nil, err
+		}
+		// the presigned URL carries the credentials, an Authorization header would be refused
+		rep.Actions["upload"] = &link{Href: u.String(), ExpiresAt: &expiresAt}
+		rep.Actions["verify"] = verifyAction(rv, header)
+		return rep, nil
+	}
+
+	uploadID, err := direct.NewMultipartUpload(p)
+	if err != nil {
+		return nil, err
+	}
+
+	partSize := multipartPartSize(meta.Size)
+	parts := make([]*partLink, 0, meta.Size/partSize+1)
+	for pos, number := int64(0), 1; pos < meta.Size || number == 1; pos, number = pos+partSize, number+1 {
+		size := meta.Size - pos
+		if size > partSize {
+			size = partSize
+		}
+		u, err := direct.UploadPartURL(p, uploadID, number, expiry)
+		if err != nil {
+			if err := direct.AbortMultipartUpload(p, uploadID); err != nil {
+				log.Error("Unable to abort the multipart upload of LFS OID[%s]: %v", meta.Oid, err)
+			}
+			return nil, err
+		}
+		parts = append(parts, &partLink{
+			link: link{Href: u.String(), ExpiresAt: &expiresAt},
+			Pos:  pos,
+			Size: size,
+		})
+	}
+
+	rep.Actions["parts"] = parts
+	rep.Actions["commit"] = &link{Href: rv.MultipartLink("commit", uploadID), Header: header, Method: "POST"}
+	rep.Actions["abort"] = &link{Href: rv.MultipartLink("abort", uploadID), Header: header, Method: "POST"}
+	rep.Actions["verify"] = verifyAction(rv, header)
+	return rep, nil
+}
+
+// finalizeDirectUpload checks the size and the hash of an object uploaded by a client to its
+// staging path and moves it to its final path. Nothing is done if no object has been staged.
+func finalizeDirectUpload(direct storage.DirectUploadStorage, meta *models.LFSMetaObject) error {
+	p := stagingPath(meta)
+	fi, err := direct.Stat(p)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if fi.Size() != meta.Size {
+		removeStagedObject(direct, meta)
+		return errSizeMismatch
+	}
+
+	f, err := direct.Open(p)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != meta.Oid {
+		removeStagedObject(direct, meta)
+		return errHashMismatch
+	}
+
+	return direct.Move(p, meta.RelativePath())
+}
+
+func removeStagedObject(direct storage.DirectUploadStorage, meta *models.LFSMetaObject) {
+	if err := direct.Delete(stagingPath(meta)); err != nil {
+		log.Error("Cleaning the staged LFS OID[%s] failed: %v", meta.Oid, err)
+	}
+}
+
+// MultipartCommitHandler assembles the parts of an object uploaded with the multipart-basic
+// transfer adapter
+func MultipartCommitHandler(ctx *context.Context) {
+	multipartHandler(ctx, func(direct storage.DirectUploadStorage, p, uploadID string) error {
+		return direct.CompleteMultipartUpload(p, uploadID)
+	})
+}
+
+// MultipartAbortHandler discards the parts of an object uploaded with the multipart-basic
+// transfer adapter
+func MultipartAbortHandler(ctx *context.Context) {
+	multipartHandler(ctx, func(direct storage.DirectUploadStorage, p, uploadID string) error {
+		return direct.AbortMultipartUpload(p, uploadID)
+	})
+}
+
+func multipartHandler(ctx *context.Context, fn func(direct storage.DirectUploadStorage, p, uploadID string) error) {
+	if !setting.LFS.StartServer {
+		log.Debug("Attempt to access LFS server but LFS server is disabled")
+		writeStatus(ctx, 404)
+		return
+	}
+
+	direct, ok := directUploadStorage()
+	if !ok {
+		writeStatus(ctx, 404)
+		return
+	}
+
+	uploadID := ctx.Query("upload_id")
+	if len(uploadID) == 0 {
+		writeStatus(ctx, 400)
+		return
+	}
+
+	// The body of the request, if any, is ignored, the oid from the URL is used
+	rv := &RequestVars{
+		User:          ctx.Params("username"),
+		Repo:          strings.TrimSuffix(ctx.Params("reponame"), ".git"),
+		Oid:           ctx.Params("oid"),
+		Authorization: ctx.Req.Header.Get("Authorization"),
+	}
+	meta, _ := getAuthenticatedRepoAndMeta(ctx, rv, true)
+	if meta == nil {
+		// Status already written in getAuthenticatedRepoAndMeta
+		return
+	}
+
+	if err := fn(direct, stagingPath(meta), uploadID); err != nil {
+		log.Error("Unable to handle the multipart upload of LFS OID[%s]: %v", meta.Oid, err)
+		writeStatus(ctx, 500)
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", metaMediaType)
+	ctx.Resp.WriteHeader(200)
+	logRequest(ctx.Req, 200)
+}
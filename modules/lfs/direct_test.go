@@ -0,0 +1,143 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDirectStorage is a local storage whose presigned URLs point to a fake bucket
+type fakeDirectStorage struct {
+	storage.ObjectStorage
+	dir string
+}
+
+func (s *fakeDirectStorage) UploadURL(path string, expiry time.Duration) (*url.URL, error) {
+	return url.Parse("https://bucket.example.com/" + path)
+}
+
+func (s *fakeDirectStorage) NewMultipartUpload(path string) (string, error) {
+	return "upload-1", nil
+}
+
+func (s *fakeDirectStorage) UploadPartURL(path, uploadID string, partNumber int, expiry time.Duration) (*url.URL, error) {
+	return url.Parse(fmt.Sprintf("https://bucket.example.com/%s?uploadId=%s&partNumber=%d", path, uploadID, partNumber))
+}
+
+func (s *fakeDirectStorage) CompleteMultipartUpload(path, uploadID string) error {
+	return nil
+}
+
+func (s *fakeDirectStorage) AbortMultipartUpload(path, uploadID string) error {
+	return nil
+}
+
+func (s *fakeDirectStorage) Move(srcPath, dstPath string) error {
+	dst := filepath.Join(s.dir, dstPath)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(s.dir, srcPath), dst)
+}
+
+func newFakeDirectStorage(t *testing.T) *fakeDirectStorage {
+	dir, err := ioutil.TempDir("", "lfs-direct")
+	assert.NoError(t, err)
+	local, err := storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: dir})
+	assert.NoError(t, err)
+	return &fakeDirectStorage{ObjectStorage: local, dir: dir}
+}
+
+func TestRepresentDirectUpload(t *testing.T) {
+	oldAppURL, oldPartSize := setting.AppURL, setting.LFS.MultipartPartSize
+	defer func() {
+		setting.AppURL, setting.LFS.MultipartPartSize = oldAppURL, oldPartSize
+	}()
+	setting.AppURL = "https://try.gitea.io/"
+	setting.LFS.MultipartPartSize = 10
+
+	direct := newFakeDirectStorage(t)
+	defer os.RemoveAll(direct.dir)
+
+	rv := &RequestVars{User: "user2", Repo: "repo1", Oid: "aabbcc", Authorization: "Basic abc"}
+	meta := &models.LFSMetaObject{Oid: "aabbcc", Size: 25, RepositoryID: 1}
+
+	rep, err := representDirectUpload(rv, meta, direct, false)
+	assert.NoError(t, err)
+	upload := rep.Actions["upload"].(*link)
+	assert.Equal(t, "https://bucket.example.com/tmp/1/aabbcc", upload.Href)
+	assert.Empty(t, upload.Header)
+	assert.Equal(t, "https://try.gitea.io/user2/repo1.git/info/lfs/verify", rep.Actions["verify"].(*link).Href)
+
+	rep, err = representDirectUpload(rv, meta, direct, true)
+	assert.NoError(t, err)
+	parts := rep.Actions["parts"].([]*partLink)
+	if assert.Len(t, parts, 3) {
+		assert.EqualValues(t, 0, parts[0].Pos)
+		assert.EqualValues(t, 10, parts[0].Size)
+		assert.EqualValues(t, 20, parts[2].Pos)
+		assert.EqualValues(t, 5, parts[2].Size)
+		assert.True(t, strings.HasSuffix(parts[2].Href, "partNumber=3"))
+	}
+	commit := rep.Actions["commit"].(*link)
+	assert.Equal(t, "https://try.gitea.io/user2/repo1.git/info/lfs/objects/aabbcc/multipart/commit?upload_id=upload-1", commit.Href)
+	assert.Equal(t, "Basic abc", commit.Header["Authorization"])
+	assert.Contains(t, rep.Actions, "abort")
+}
+
+func TestMultipartPartSize(t *testing.T) {
+	oldPartSize := setting.LFS.MultipartPartSize
+	defer func() {
+		setting.LFS.MultipartPartSize = oldPartSize
+	}()
+	setting.LFS.MultipartPartSize = 100
+
+	assert.EqualValues(t, 100, multipartPartSize(150))
+	assert.EqualValues(t, 100, multipartPartSize(100*maxMultipartParts))
+	assert.EqualValues(t, 101, multipartPartSize(100*maxMultipartParts+1))
+}
+
+func TestFinalizeDirectUpload(t *testing.T) {
+	direct := newFakeDirectStorage(t)
+	defer os.RemoveAll(direct.dir)
+
+	content := "gitea lfs direct upload"
+	hash := sha256.Sum256([]byte(content))
+	meta := &models.LFSMetaObject{Oid: hex.EncodeToString(hash[:]), Size: int64(len(content)), RepositoryID: 1}
+
+	// nothing staged
+	assert.NoError(t, finalizeDirectUpload(direct, meta))
+
+	_, err := direct.Save(stagingPath(meta), strings.NewReader("tampered content here!!"))
+	assert.NoError(t, err)
+	assert.Equal(t, errHashMismatch, finalizeDirectUpload(direct, meta))
+	_, err = direct.Stat(stagingPath(meta))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = direct.Save(stagingPath(meta), strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.NoError(t, finalizeDirectUpload(direct, meta))
+	fi, err := direct.Stat(meta.RelativePath())
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(content), fi.Size())
+	_, err = direct.Stat(stagingPath(meta))
+	assert.True(t, os.IsNotExist(err))
+}
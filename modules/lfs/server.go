@@ -58,10 +58,10 @@ type BatchResponse struct {
 
 // Representation is object metadata as seen by clients of the lfs server.
 type Representation struct {
-	Oid     string           `json:"oid"`
-	Size    int64            `json:"size"`
-	Actions map[string]*link `json:"actions"`
-	Error   *ObjectError     `json:"error,omitempty"`
+	Oid     string                 `json:"oid"`
+	Size    int64                  `json:"size"`
+	Actions map[string]interface{} `json:"actions"`
+	Error   *ObjectError           `json:"error,omitempty"`
 }
 
 // ObjectError defines the JSON structure returned to the client in case of an error
@@ -92,7 +92,8 @@ func (v *RequestVars) VerifyLink() string {
 type link struct {
 	Href      string            `json:"href"`
 	Header    map[string]string `json:"header,omitempty"`
-	ExpiresAt time.Time         `json:"expires_at,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
 }
 
 var oidRegExp = regexp.MustCompile(`^[A-Fa-f0-9]+$`)
@@ -283,6 +284,17 @@ func PostHandler(ctx *context.Context) {
 		return
 	}
 
+	if err := checkStorageQuota(repository, rv.Size); err != nil {
+		if models.IsErrStorageQuotaExceeded(err) {
+			log.Info("Denied LFS OID[%s] upload of size %d to %s/%s: %v", rv.Oid, rv.Size, rv.User, rv.Repo, err)
+			writeStatusMessage(ctx, 413, err.Error())
+		} else {
+			log.Error("Unable to check the storage quota of %s/%s. Error: %v", rv.User, rv.Repo, err)
+			writeStatus(ctx, 500)
+		}
+		return
+	}
+
 	meta, err := models.NewLFSMetaObject(&models.LFSMetaObject{Oid: rv.Oid, Size: rv.Size, RepositoryID: repository.ID})
 	if err != nil {
 		log.Error("Unable to write LFS OID[%s] size %d meta object in %v/%v to database. Error: %v", rv.Oid, rv.Size, rv.User, rv.Repo, err)
@@ -328,7 +340,15 @@ func BatchHandler(ctx *context.Context) {
 
 	bv := unpackbatch(ctx)
 
+	// Let the clients upload to the storage directly when possible and prefer the
+	// multipart-basic transfer adapter for the large objects if they support it
+	direct, useDirect := directUploadStorage()
+	useDirect = useDirect && bv.Operation == "upload"
+	multipart := useDirect && hasTransfer(bv.Transfers, transferMultipart)
+
 	var responseObjects []*Representation
+	// the sizes of the objects to upload counted against the storage quota of each owner
+	quotaSizes := make(map[int64]int64)
 
 	// Create a response object
 	for _, object := range bv.Objects {
@@ -376,6 +396,20 @@ func BatchHandler(ctx *context.Context) {
 			return
 		}
 
+		if requireWrite {
+			quotaSizes[repository.OwnerID] += object.Size
+			if err := checkStorageQuota(repository, quotaSizes[repository.OwnerID]); err != nil {
+				if models.IsErrStorageQuotaExceeded(err) {
+					log.Info("Denied LFS OID[%s] upload of size %d to %s/%s: %v", object.Oid, object.Size, object.User, object.Repo, err)
+					writeStatusMessage(ctx, 413, err.Error())
+				} else {
+					log.Error("Unable to check the storage quota of %s/%s. Error: %v", object.User, object.Repo, err)
+					writeStatus(ctx, 500)
+				}
+				return
+			}
+		}
+
 		// Object is not found
 		meta, err = models.NewLFSMetaObject(&models.LFSMetaObject{Oid: object.Oid, Size: object.Size, RepositoryID: repository.ID})
 		if err == nil {
@@ -385,6 +419,15 @@ func BatchHandler(ctx *context.Context) {
 				writeStatus(ctx, 500)
 				return
 			}
+			if useDirect && !exist && (multipart || meta.Size <= maxSinglePutSize) {
+				rep, err := representDirectUpload(object, meta, direct, multipart)
+				if err != nil {
+					log.Error("Unable to start direct upload of LFS OID[%s] in %s/%s. Error: %v", object.Oid, object.User, object.Repo, err)
+					rep = &Representation{Oid: meta.Oid, Size: meta.Size, Error: &ObjectError{Code: http.StatusInternalServerError, Message: "Internal Server Error"}}
+				}
+				responseObjects = append(responseObjects, rep)
+				continue
+			}
 			responseObjects = append(responseObjects, Represent(object, meta, meta.Existing, !exist))
 		} else {
 			log.Error("Unable to write LFS OID[%s] size %d meta object in %v/%v to database. Error: %v", object.Oid, object.Size, object.User, object.Repo, err)
@@ -394,6 +437,9 @@ func BatchHandler(ctx *context.Context) {
 	ctx.Resp.Header().Set("Content-Type", metaMediaType)
 
 	respobj := &BatchResponse{Objects: responseObjects}
+	if multipart {
+		respobj.Transfer = transferMultipart
+	}
 
 	enc := json.NewEncoder(ctx.Resp)
 	if err := enc.Encode(respobj); err != nil {
@@ -448,12 +494,29 @@ func VerifyHandler(ctx *context.Context) {
 
 	rv := unpack(ctx)
 
-	meta, _ := getAuthenticatedRepoAndMeta(ctx, rv, true)
+	meta, repository := getAuthenticatedRepoAndMeta(ctx, rv, true)
 	if meta == nil {
 		// Status already written in getAuthenticatedRepoAndMeta
 		return
 	}
 
+	if direct, ok := directUploadStorage(); ok {
+		if err := finalizeDirectUpload(direct, meta); err != nil {
+			if err != errSizeMismatch && err != errHashMismatch {
+				log.Error("Unable to finalize the direct upload of LFS OID[%s]: %v", meta.Oid, err)
+				ctx.Resp.WriteHeader(500)
+				fmt.Fprintf(ctx.Resp, `{"message":"Internal Server Error"}`)
+				return
+			}
+			ctx.Resp.WriteHeader(422)
+			fmt.Fprintf(ctx.Resp, `{"message":"%s"}`, err)
+			if _, err = repository.RemoveLFSMetaObjectByOid(meta.Oid); err != nil {
+				log.Error("Whilst removing metaobject for LFS OID[%s] due to preceding error there was another Error: %v", meta.Oid, err)
+			}
+			return
+		}
+	}
+
 	contentStore := &ContentStore{ObjectStorage: storage.LFS}
 	ok, err := contentStore.Verify(meta)
 	if err != nil {
@@ -476,17 +539,10 @@ func Represent(rv *RequestVars, meta *models.LFSMetaObject, download, upload boo
 	rep := &Representation{
 		Oid:     meta.Oid,
 		Size:    meta.Size,
-		Actions: make(map[string]*link),
+		Actions: make(map[string]interface{}),
 	}
 
-	header := make(map[string]string)
-
-	if rv.Authorization == "" {
-		//https://github.com/github/git-lfs/issues/1088
-		header["Authorization"] = "Authorization: Basic dummy"
-	} else {
-		header["Authorization"] = rv.Authorization
-	}
+	header := authorizationHeader(rv)
 
 	if download {
 		rep.Actions["download"] = &link{Href: rv.ObjectLink(), Header: header}
@@ -498,18 +554,35 @@ func Represent(rv *RequestVars, meta *models.LFSMetaObject, download, upload boo
 
 	if upload && !download {
 		// Force client side verify action while gitea lacks proper server side verification
-		verifyHeader := make(map[string]string)
-		for k, v := range header {
-			verifyHeader[k] = v
-		}
+		rep.Actions["verify"] = verifyAction(rv, header)
+	}
 
-		// This is only needed to workaround https://github.com/git-lfs/git-lfs/issues/3662
-		verifyHeader["Accept"] = metaMediaType
+	return rep
+}
+
+// authorizationHeader returns the header the clients must send to the links of Gitea
+func authorizationHeader(rv *RequestVars) map[string]string {
+	header := make(map[string]string)
+
+	if rv.Authorization == "" {
+		//https://github.com/github/git-lfs/issues/1088
+		header["Authorization"] = "Authorization: Basic dummy"
+	} else {
+		header["Authorization"] = rv.Authorization
+	}
+	return header
+}
 
-		rep.Actions["verify"] = &link{Href: rv.VerifyLink(), Header: verifyHeader}
+func verifyAction(rv *RequestVars, header map[string]string) *link {
+	verifyHeader := make(map[string]string)
+	for k, v := range header {
+		verifyHeader[k] = v
 	}
 
-	return rep
+	// This is only needed to workaround https://github.com/git-lfs/git-lfs/issues/3662
+	verifyHeader["Accept"] = metaMediaType
+
+	return &link{Href: rv.VerifyLink(), Header: verifyHeader}
 }
 
 // MetaMatcher provides a mux.MatcherFunc that only allows requests that contain
@@ -573,13 +646,27 @@ func unpackbatch(ctx *context.Context) *BatchVars {
 	return &bv
 }
 
+// checkStorageQuota checks the storage quota of the owner of a repository with the size of objects to upload
+func checkStorageQuota(repository *models.Repository, size int64) error {
+	if !setting.Quota.Enabled {
+		return nil
+	}
+	if err := repository.GetOwner(); err != nil {
+		return err
+	}
+	return models.CheckStorageQuota(repository.Owner, size)
+}
+
 func writeStatus(ctx *context.Context, status int) {
-	message := http.StatusText(status)
+	writeStatusMessage(ctx, status, http.StatusText(status))
+}
 
+func writeStatusMessage(ctx *context.Context, status int, message string) {
 	mediaParts := strings.Split(ctx.Req.Header.Get("Accept"), ";")
 	mt := mediaParts[0]
 	if strings.HasSuffix(mt, "+json") {
-		message = `{"message":"` + message + `"}`
+		data, _ := json.Marshal(map[string]string{"message": message})
+		message = string(data)
 	}
 
 	ctx.Resp.WriteHeader(status)
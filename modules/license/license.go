@@ -0,0 +1,110 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+)
+
+// minSimilarity is the similarity a text must have with the text of a license to be detected as it
+const minSimilarity = 0.9
+
+var wordPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// text represents the words of a text with their number of occurrences
+type text struct {
+	name  string
+	words map[string]int
+	total int
+}
+
+func newText(name string, content []byte) *text {
+	t := &text{name: name, words: make(map[string]int)}
+	for _, word := range wordPattern.FindAll(bytes.ToLower(content), -1) {
+		t.words[string(word)]++
+		t.total++
+	}
+	return t
+}
+
+// similarity returns the Sørensen–Dice coefficient of the words of two texts
+func (t *text) similarity(other *text) float64 {
+	if t.total+other.total == 0 {
+		return 0
+	}
+	common := 0
+	for word, count := range t.words {
+		if otherCount := other.words[word]; otherCount < count {
+			common += otherCount
+		} else {
+			common += count
+		}
+	}
+	return 2 * float64(common) / float64(t.total+other.total)
+}
+
+// maxSimilarity returns an upper bound of the similarity of two texts based on their number of words
+func (t *text) maxSimilarity(other *text) float64 {
+	if t.total+other.total == 0 {
+		return 0
+	}
+	min := t.total
+	if other.total < min {
+		min = other.total
+	}
+	return 2 * float64(min) / float64(t.total+other.total)
+}
+
+var (
+	loadOnce sync.Once
+	licenses []*text
+)
+
+func loadLicenses() {
+	names, err := options.Dir("license")
+	if err != nil {
+		log.Error("Failed to list licenses: %v", err)
+		return
+	}
+	sort.Strings(names)
+
+	licenses = make([]*text, 0, len(names))
+	for _, name := range names {
+		// exceptions are additions to licenses, not licenses
+		if strings.Contains(name, "-exception") {
+			continue
+		}
+		content, err := options.License(name)
+		if err != nil {
+			log.Error("Failed to load license %s: %v", name, err)
+			continue
+		}
+		licenses = append(licenses, newText(name, content))
+	}
+}
+
+// Detect returns the SPDX identifier of the license a text is, or an empty string if it isn't close enough to any license
+func Detect(content []byte) string {
+	loadOnce.Do(loadLicenses)
+
+	t := newText("", content)
+	best, detected := minSimilarity, ""
+	for _, license := range licenses {
+		if t.maxSimilarity(license) < best {
+			continue
+		}
+		if similarity := t.similarity(license); similarity > best || len(detected) == 0 && similarity >= best {
+			best, detected = similarity, license.name
+		}
+	}
+	return detected
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	setting.StaticRootPath = "../.."
+
+	// the license of Gitea itself
+	content, err := ioutil.ReadFile("../../LICENSE")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT", Detect(content))
+
+	content, err = ioutil.ReadFile("../../options/license/Apache-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", Detect(content))
+
+	content, err = ioutil.ReadFile("../../options/license/BSD-3-Clause")
+	assert.NoError(t, err)
+	assert.Equal(t, "BSD-3-Clause", Detect(content))
+
+	assert.Equal(t, "", Detect([]byte("All rights reserved.")))
+	assert.Equal(t, "", Detect(nil))
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// RendererVersion must be increased whenever a change of the renderers or of the
+// post-processing modifies their output, so that cached renderings are not reused
+const RendererVersion = 1
+
+// writeSettingsHash writes all the settings the rendering depends on
+func writeSettingsHash(w io.Writer) {
+	fmt.Fprintf(w, "%d\x00%s\x00%s\x00", RendererVersion, setting.AppURL, setting.AppSubURL)
+	fmt.Fprintf(w, "%t\x00%t\x00%v\x00%v\x00",
+		setting.Markdown.EnableHardLineBreakInComments,
+		setting.Markdown.EnableHardLineBreakInDocuments,
+		setting.Markdown.CustomURLSchemes,
+		setting.Markdown.FileExtensions)
+	for _, parser := range setting.ExternalMarkupParsers {
+		fmt.Fprintf(w, "%s\x00%t\x00%s\x00%v\x00%t\x00%s\x00",
+			parser.MarkupName, parser.Enabled, parser.Command, parser.FileExtensions, parser.IsInputFile, parser.RenderContentMode)
+	}
+	for _, rule := range setting.ExternalSanitizerRules {
+		fmt.Fprintf(w, "%s\x00%s\x00%v\x00", rule.Element, rule.AllowAttr, rule.Regexp)
+	}
+	fmt.Fprintf(w, "%v\x00", setting.ExternalSanitizerURLSchemes)
+}
+
+// renderCacheKey returns the cache key of the rendering of a blob, it changes whenever
+// anything the rendering depends on changes: the content, the file name choosing the
+// renderer, the links prefix, the metas, the renderers and the settings
+func renderCacheKey(filename, blobSHA, urlPrefix string, metas map[string]string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", filename, blobSHA, urlPrefix)
+
+	keys := make([]string, 0, len(metas))
+	for k := range metas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\x00", k, metas[k])
+	}
+
+	writeSettingsHash(h)
+	return "markup_render:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// RenderCached renders a markup file like Render does, the result is cached by the
+// SHA of the blob holding the content so popular files aren't rendered again on every view
+func RenderCached(filename, blobSHA string, rawBytes []byte, urlPrefix string, metas map[string]string) []byte {
+	result, err := cache.GetString(renderCacheKey(filename, blobSHA, urlPrefix, metas), func() (string, error) {
+		return string(Render(filename, rawBytes, urlPrefix, metas)), nil
+	})
+	if err != nil {
+		log.Error("RenderCached [%s, %s]: %v", filename, blobSHA, err)
+		return Render(filename, rawBytes, urlPrefix, metas)
+	}
+	return []byte(result)
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"regexp"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCacheKey(t *testing.T) {
+	metas := map[string]string{"user": "user2", "repo": "repo1", "mode": "document"}
+	key := renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master", metas)
+	assert.Equal(t, key, renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master",
+		map[string]string{"mode": "document", "repo": "repo1", "user": "user2"}))
+
+	assert.NotEqual(t, key, renderCacheKey("README.md", "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", "/user2/repo1/src/branch/master", metas))
+	assert.NotEqual(t, key, renderCacheKey("README.org", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master", metas))
+	assert.NotEqual(t, key, renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/develop", metas))
+	assert.NotEqual(t, key, renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master",
+		map[string]string{"user": "user2", "repo": "repo1", "mode": "document", "format": "https://tracker/{index}"}))
+
+	// Changing the settings the rendering depends on invalidates the cached renderings
+	defer func(old bool) {
+		setting.Markdown.EnableHardLineBreakInDocuments = old
+	}(setting.Markdown.EnableHardLineBreakInDocuments)
+	setting.Markdown.EnableHardLineBreakInDocuments = !setting.Markdown.EnableHardLineBreakInDocuments
+	assert.NotEqual(t, key, renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master", metas))
+	setting.Markdown.EnableHardLineBreakInDocuments = !setting.Markdown.EnableHardLineBreakInDocuments
+
+	defer func(old []setting.MarkupSanitizerRule) {
+		setting.ExternalSanitizerRules = old
+	}(setting.ExternalSanitizerRules)
+	setting.ExternalSanitizerRules = append(setting.ExternalSanitizerRules, setting.MarkupSanitizerRule{
+		Element:   "div",
+		AllowAttr: "class",
+		Regexp:    regexp.MustCompile("^custom$"),
+	})
+	assert.NotEqual(t, key, renderCacheKey("README.md", "65f1bf27bc3bf70f64657658635e66094edbcb4d", "/user2/repo1/src/branch/master", metas))
+}
@@ -6,6 +6,9 @@ package external
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -13,6 +16,7 @@ import (
 	"runtime"
 	"strings"
 
+	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
@@ -50,8 +54,39 @@ func envMark(envName string) string {
 	return "$" + envName
 }
 
+// RenderInIframe returns whether the output of the tool must be shown in a sandboxed iframe
+func (p *Parser) RenderInIframe() bool {
+	return p.RenderContentMode == setting.RenderContentModeIframe
+}
+
+// cacheKey returns the key of the output of the tool for the given input, the tool only
+// depends on the input and the links prefix
+func (p *Parser) cacheKey(rawBytes []byte, urlPrefix string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%t\x00%s\x00", p.MarkupName, p.Command, p.IsInputFile, urlPrefix)
+	_, _ = h.Write(rawBytes)
+	return "markup_external:" + hex.EncodeToString(h.Sum(nil))
+}
+
 // Render renders the data of the document to HTML via the external tool.
 func (p *Parser) Render(rawBytes []byte, urlPrefix string, metas map[string]string, isWiki bool) []byte {
+	if !p.Cache {
+		result, _ := p.render(rawBytes, urlPrefix)
+		return result
+	}
+
+	result, err := cache.GetString(p.cacheKey(rawBytes, urlPrefix), func() (string, error) {
+		result, err := p.render(rawBytes, urlPrefix)
+		return string(result), err
+	})
+	if err != nil {
+		// failures are logged by render and not cached
+		return []byte("")
+	}
+	return []byte(result)
+}
+
+func (p *Parser) render(rawBytes []byte, urlPrefix string) ([]byte, error) {
 	var (
 		bs           []byte
 		buf          = bytes.NewBuffer(bs)
@@ -69,7 +104,7 @@ func (p *Parser) Render(rawBytes []byte, urlPrefix string, metas map[string]stri
 		f, err := ioutil.TempFile("", "gitea_input")
 		if err != nil {
 			log.Error("%s create temp file when rendering %s failed: %v", p.Name(), p.Command, err)
-			return []byte("")
+			return nil, err
 		}
 		tmpPath := f.Name()
 		defer func() {
@@ -82,13 +117,13 @@ func (p *Parser) Render(rawBytes []byte, urlPrefix string, metas map[string]stri
 		if err != nil {
 			f.Close()
 			log.Error("%s write data to temp file when rendering %s failed: %v", p.Name(), p.Command, err)
-			return []byte("")
+			return nil, err
 		}
 
 		err = f.Close()
 		if err != nil {
 			log.Error("%s close temp file when rendering %s failed: %v", p.Name(), p.Command, err)
-			return []byte("")
+			return nil, err
 		}
 		args = append(args, f.Name())
 	}
@@ -105,7 +140,7 @@ func (p *Parser) Render(rawBytes []byte, urlPrefix string, metas map[string]stri
 	cmd.Stdout = buf
 	if err := cmd.Run(); err != nil {
 		log.Error("%s render run command %s %v failed: %v", p.Name(), commands[0], args, err)
-		return []byte("")
+		return nil, err
 	}
-	return buf.Bytes()
+	return buf.Bytes(), nil
 }
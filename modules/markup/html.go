@@ -151,6 +151,7 @@ func (p *postProcessError) Error() string {
 type processor func(ctx *postProcessCtx, node *html.Node)
 
 var defaultProcessors = []processor{
+	codePreviewProcessor,
 	fullIssuePatternProcessor,
 	fullSha1PatternProcessor,
 	shortLinkProcessor,
@@ -0,0 +1,162 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// CodePreviewLink represents a permalink to a line range of a file of this instance
+type CodePreviewLink struct {
+	URL       string
+	OwnerName string
+	RepoName  string
+	CommitID  string
+	FilePath  string
+	LineStart int
+	LineStop  int
+}
+
+// CodePreviewProvider returns the highlighted lines of the line range the link refers to.
+// No lines are returned if the file can't be previewed, the link is then rendered as usual.
+type CodePreviewProvider func(link *CodePreviewLink) ([]string, error)
+
+var (
+	codePreviewProvider CodePreviewProvider
+
+	// regexp for permalinks to a line range of a file of this instance
+	codePreviewPattern *regexp.Regexp
+)
+
+// RegisterCodePreviewProvider registers the provider used to expand permalinks
+// to code snippets in comments
+func RegisterCodePreviewProvider(provider CodePreviewProvider) {
+	codePreviewProvider = provider
+}
+
+func getCodePreviewPattern() *regexp.Regexp {
+	if codePreviewPattern == nil {
+		appURL := setting.AppURL
+		if len(appURL) > 0 && appURL[len(appURL)-1] != '/' {
+			appURL += "/"
+		}
+		codePreviewPattern = regexp.MustCompile(`^` + regexp.QuoteMeta(appURL) +
+			`([^/\s]+)/([^/\s]+)/src/commit/([0-9a-f]{40})/(\S+)#L([1-9][0-9]*)(?:-L([1-9][0-9]*))?$`)
+	}
+	return codePreviewPattern
+}
+
+// codePreviewProcessor expands a permalink to a line range standing on its own line
+// of a comment into a highlighted snippet of the code
+func codePreviewProcessor(ctx *postProcessCtx, node *html.Node) {
+	if ctx.metas == nil || ctx.metas["mode"] != "comment" || codePreviewProvider == nil {
+		return
+	}
+	paragraph := node.Parent
+	if paragraph == nil || paragraph.DataAtom != atom.P || paragraph.FirstChild != node || paragraph.LastChild != node {
+		return
+	}
+
+	m := getCodePreviewPattern().FindStringSubmatch(strings.TrimSpace(node.Data))
+	if m == nil {
+		return
+	}
+
+	link := &CodePreviewLink{
+		URL:       m[0],
+		OwnerName: m[1],
+		RepoName:  m[2],
+		CommitID:  m[3],
+		FilePath:  m[4],
+	}
+	link.LineStart, _ = strconv.Atoi(m[5])
+	link.LineStop = link.LineStart
+	if len(m[6]) > 0 {
+		link.LineStop, _ = strconv.Atoi(m[6])
+	}
+	if link.LineStop < link.LineStart {
+		return
+	}
+
+	lines, err := codePreviewProvider(link)
+	if err != nil {
+		log.Error("CodePreviewProvider [%s]: %v", link.URL, err)
+		return
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	// The paragraph is turned into the preview in place, so the siblings still get visited
+	node.Data = ""
+	paragraph.Data = atom.Div.String()
+	paragraph.DataAtom = atom.Div
+	paragraph.Attr = []html.Attribute{{Key: "class", Val: "code-preview"}}
+
+	header := &html.Node{
+		Type:     html.ElementNode,
+		Data:     atom.Div.String(),
+		DataAtom: atom.Div,
+		Attr:     []html.Attribute{{Key: "class", Val: "code-preview-header"}},
+	}
+	header.AppendChild(createLink(link.URL, link.OwnerName+"/"+link.RepoName+"/"+link.FilePath, ""))
+	lineRange := "L" + strconv.Itoa(link.LineStart)
+	if link.LineStop > link.LineStart {
+		lineRange += "-L" + strconv.Itoa(link.LineStart+len(lines)-1)
+	}
+	header.AppendChild(&html.Node{Type: html.TextNode, Data: " (" + lineRange + ") "})
+	commitURL := util.URLJoin(setting.AppURL, link.OwnerName, link.RepoName, "commit", link.CommitID)
+	header.AppendChild(createCodeLink(commitURL, base.ShortSha(link.CommitID), ""))
+	paragraph.AppendChild(header)
+
+	tbody := &html.Node{Type: html.ElementNode, Data: atom.Tbody.String(), DataAtom: atom.Tbody}
+	for i, line := range lines {
+		tr := &html.Node{Type: html.ElementNode, Data: atom.Tr.String(), DataAtom: atom.Tr}
+		num := &html.Node{
+			Type:     html.ElementNode,
+			Data:     atom.Td.String(),
+			DataAtom: atom.Td,
+			Attr:     []html.Attribute{{Key: "class", Val: "lines-num"}},
+		}
+		num.AppendChild(&html.Node{
+			Type:     html.ElementNode,
+			Data:     atom.Span.String(),
+			DataAtom: atom.Span,
+			Attr:     []html.Attribute{{Key: "data-line-number", Val: strconv.Itoa(link.LineStart + i)}},
+		})
+		tr.AppendChild(num)
+
+		td := &html.Node{
+			Type:     html.ElementNode,
+			Data:     atom.Td.String(),
+			DataAtom: atom.Td,
+			Attr:     []html.Attribute{{Key: "class", Val: "lines-code chroma"}},
+		}
+		code := &html.Node{Type: html.ElementNode, Data: atom.Code.String(), DataAtom: atom.Code}
+		nodes, err := html.ParseFragment(strings.NewReader(line), code)
+		if err != nil {
+			log.Error("ParseFragment [%s]: %v", link.URL, err)
+		}
+		for _, n := range nodes {
+			code.AppendChild(n)
+		}
+		td.AppendChild(code)
+		tr.AppendChild(td)
+		tbody.AppendChild(tr)
+	}
+	table := &html.Node{Type: html.ElementNode, Data: atom.Table.String(), DataAtom: atom.Table}
+	table.AppendChild(tbody)
+	paragraph.AppendChild(table)
+}
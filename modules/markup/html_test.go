@@ -377,3 +377,42 @@ func TestRender_ShortLinks(t *testing.T) {
 		`<p><a href="https://example.org" rel="nofollow">[[foobar]]</a></p>`,
 		`<p><a href="https://example.org" rel="nofollow">[[foobar]]</a></p>`)
 }
+
+func TestRender_CodePreview(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	var sha = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	var permalink = util.URLJoin(AppURL, "gogits/gogs/src/commit", sha, "README.md") + "#L2-L3"
+
+	RegisterCodePreviewProvider(func(link *CodePreviewLink) ([]string, error) {
+		assert.Equal(t, "gogits", link.OwnerName)
+		assert.Equal(t, "gogs", link.RepoName)
+		assert.Equal(t, sha, link.CommitID)
+		assert.Equal(t, "README.md", link.FilePath)
+		assert.Equal(t, 2, link.LineStart)
+		assert.Equal(t, 3, link.LineStop)
+		return []string{`<span class="k">second</span>`, "third"}, nil
+	})
+	defer RegisterCodePreviewProvider(nil)
+
+	metas := map[string]string{"user": "gogits", "repo": "gogs", "mode": "comment"}
+	test := func(input, expected string) {
+		buffer := RenderString(".md", input, setting.AppSubURL, metas)
+		assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(buffer))
+	}
+
+	test(permalink,
+		`<div class="code-preview"><div class="code-preview-header"><a href="`+permalink+`" rel="nofollow">gogits/gogs/README.md</a> (L2-L3) <a href="`+util.URLJoin(AppURL, "gogits/gogs/commit", sha)+`" rel="nofollow"><code>65f1bf27bc</code></a></div>`+
+			`<table><tbody><tr><td class="lines-num"><span data-line-number="2"></span></td><td class="lines-code chroma"><code><span class="k">second</span></code></td></tr>`+
+			`<tr><td class="lines-num"><span data-line-number="3"></span></td><td class="lines-code chroma"><code>third</code></td></tr></tbody></table></div>`)
+
+	// permalinks within a sentence are left as links
+	test("see "+permalink,
+		`<p>see <a href="`+permalink+`" rel="nofollow"><code>65f1bf27bc/README.md (L2-L3)</code></a></p>`)
+
+	// documents don't expand permalinks
+	metas["mode"] = "document"
+	test(permalink,
+		`<p><a href="`+permalink+`" rel="nofollow"><code>65f1bf27bc/README.md (L2-L3)</code></a></p>`)
+}
@@ -36,6 +36,12 @@ type Parser interface {
 	Render(rawBytes []byte, urlPrefix string, metas map[string]string, isWiki bool) []byte
 }
 
+// IframeParser is implemented by the parsers whose output may not be trusted enough to be
+// embedded in the pages, when RenderInIframe returns true it is shown in a sandboxed iframe
+type IframeParser interface {
+	RenderInIframe() bool
+}
+
 var (
 	extParsers = make(map[string]Parser)
 	parsers    = make(map[string]Parser)
@@ -105,6 +111,24 @@ func renderFile(filename string, rawBytes []byte, urlPrefix string, metas map[st
 	return nil
 }
 
+// IsIframeRendered reports whether the file must be shown in a sandboxed iframe
+// instead of being embedded in the page
+func IsIframeRendered(filename string) bool {
+	if parser, ok := GetParserByFileName(filename).(IframeParser); ok {
+		return parser.RenderInIframe()
+	}
+	return false
+}
+
+// RenderIframe renders a file shown in a sandboxed iframe. The output is neither post-processed
+// nor sanitized, it must only be served with a Content-Security-Policy sandboxing it.
+func RenderIframe(filename string, rawBytes []byte, urlPrefix string, metas map[string]string) []byte {
+	if !IsIframeRendered(filename) {
+		return nil
+	}
+	return GetParserByFileName(filename).Render(rawBytes, urlPrefix, metas, false)
+}
+
 // Type returns if markup format via the filename
 func Type(filename string) string {
 	if parser := GetParserByFileName(filename); parser != nil {
@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// renderOriginCodeLifetime is how long the links to the render origin identify the user, in minutes
+const renderOriginCodeLifetime = 60
+
+// IsRenderOriginRequest reports whether the request has been sent to the render origin
+func IsRenderOriginRequest(req *http.Request) bool {
+	if len(setting.MarkupRenderOrigin) == 0 {
+		return false
+	}
+	host := setting.MarkupRenderOrigin[strings.Index(setting.MarkupRenderOrigin, "://")+3:]
+	return strings.EqualFold(req.Host, host)
+}
+
+func renderOriginCodeData(path string, userID int64) string {
+	return fmt.Sprintf("%d:%s", userID, path)
+}
+
+// RenderOriginLink returns the link of a rendered document on the render origin from its escaped path,
+// the cookies of the user aren't sent there so the link identifies the user for a limited time
+func RenderOriginLink(link string, userID int64) string {
+	if userID == 0 {
+		return setting.MarkupRenderOrigin + link
+	}
+	path, err := url.PathUnescape(link)
+	if err != nil {
+		path = link
+	}
+	code := base.CreateTimeLimitCode(renderOriginCodeData(path, userID), renderOriginCodeLifetime, nil)
+	return fmt.Sprintf("%s%s?uid=%d&code=%s", setting.MarkupRenderOrigin, link, userID, url.QueryEscape(code))
+}
+
+// VerifyRenderOriginCode reports whether the code of a link to the render origin has been given to the user
+// for the unescaped path and hasn't expired
+func VerifyRenderOriginCode(path string, userID int64, code string) bool {
+	return base.VerifyTimeLimitCode(renderOriginCodeData(path, userID), renderOriginCodeLifetime, code)
+}
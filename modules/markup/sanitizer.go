@@ -69,6 +69,11 @@ func ReplaceSanitizer() {
 	// Allow icons, checkboxes, emojis, and chroma syntax on span
 	sanitizer.policy.AllowAttrs("class").Matching(regexp.MustCompile(`^((icon(\s+[\p{L}\p{N}_-]+)+)|(ui checkbox)|(ui checked checkbox)|(emoji))$|^([a-z][a-z0-9]{0,2})$`)).OnElements("span")
 
+	// Allow classes and line numbers for code previews of permalinks
+	sanitizer.policy.AllowAttrs("class").Matching(regexp.MustCompile(`^code-preview(-header)?$`)).OnElements("div")
+	sanitizer.policy.AllowAttrs("class").Matching(regexp.MustCompile(`^(lines-num|lines-code chroma)$`)).OnElements("td")
+	sanitizer.policy.AllowAttrs("data-line-number").Matching(regexp.MustCompile(`^[0-9]+$`)).OnElements("span")
+
 	// Allow generally safe attributes
 	generalSafeAttrs := []string{"abbr", "accept", "accept-charset",
 		"accesskey", "action", "align", "alt",
@@ -106,12 +111,22 @@ func ReplaceSanitizer() {
 
 	// Custom keyword markup
 	for _, rule := range setting.ExternalSanitizerRules {
-		if rule.Regexp != nil {
+		switch {
+		case rule.AllowAttr == "":
+			sanitizer.policy.AllowElements(rule.Element)
+		case rule.AllowAttr == setting.DataAttributesWildcard:
+			// bluemonday only supports the data attributes on every allowed element
+			sanitizer.policy.AllowDataAttributes()
+			sanitizer.policy.AllowElements(rule.Element)
+		case rule.Regexp != nil:
 			sanitizer.policy.AllowAttrs(rule.AllowAttr).Matching(rule.Regexp).OnElements(rule.Element)
-		} else {
+		default:
 			sanitizer.policy.AllowAttrs(rule.AllowAttr).OnElements(rule.Element)
 		}
 	}
+	if len(setting.ExternalSanitizerURLSchemes) > 0 {
+		sanitizer.policy.AllowURLSchemes(setting.ExternalSanitizerURLSchemes...)
+	}
 }
 
 // Sanitize takes a string that contains a HTML fragment or document and applies policy whitelist.
@@ -8,6 +8,8 @@ package markup
 import (
 	"testing"
 
+	"code.gitea.io/gitea/modules/setting"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +52,27 @@ func Test_Sanitizer(t *testing.T) {
 		assert.Equal(t, testCases[i+1], string(SanitizeBytes([]byte(testCases[i]))))
 	}
 }
+
+func Test_SanitizerExternalRules(t *testing.T) {
+	oldRules, oldSchemes := setting.ExternalSanitizerRules, setting.ExternalSanitizerURLSchemes
+	defer func() {
+		setting.ExternalSanitizerRules, setting.ExternalSanitizerURLSchemes = oldRules, oldSchemes
+		ReplaceSanitizer()
+	}()
+	setting.ExternalSanitizerRules = []setting.MarkupSanitizerRule{
+		{Element: "video"},
+		{Element: "video", AllowAttr: "controls"},
+		{Element: "div", AllowAttr: setting.DataAttributesWildcard},
+	}
+	setting.ExternalSanitizerURLSchemes = []string{"matrix"}
+	ReplaceSanitizer()
+
+	testCases := []string{
+		`<video controls onplay="alert(1)">test</video>`, `<video controls="">test</video>`,
+		`<div data-graph="1">test</div>`, `<div data-graph="1">test</div>`,
+		`<a href="matrix:r/gitea:matrix.org">room</a>`, `<a href="matrix:r/gitea:matrix.org" rel="nofollow">room</a>`,
+	}
+	for i := 0; i < len(testCases); i += 2 {
+		assert.Equal(t, testCases[i+1], Sanitize(testCases[i]))
+	}
+}
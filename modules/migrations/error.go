@@ -7,6 +7,7 @@ package migrations
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/google/go-github/v32/github"
 )
@@ -14,6 +15,8 @@ import (
 var (
 	// ErrNotSupported returns the error not supported
 	ErrNotSupported = errors.New("not supported")
+	// ErrTrackerNotEmpty returns the error of restoring issues into a repository which has issues or pull requests
+	ErrTrackerNotEmpty = errors.New("the issue tracker of the repository isn't empty")
 )
 
 // IsRateLimitError returns true if the err is github.RateLimitError
@@ -27,3 +30,18 @@ func IsTwoFactorAuthError(err error) bool {
 	_, ok := err.(*github.TwoFactorAuthError)
 	return ok
 }
+
+// ErrInvalidGithubArchive represents an archive which isn't a valid GitHub migration archive
+type ErrInvalidGithubArchive struct {
+	Err error
+}
+
+// IsErrInvalidGithubArchive checks if an error is a ErrInvalidGithubArchive.
+func IsErrInvalidGithubArchive(err error) bool {
+	_, ok := err.(ErrInvalidGithubArchive)
+	return ok
+}
+
+func (err ErrInvalidGithubArchive) Error() string {
+	return fmt.Sprintf("invalid GitHub migration archive: %v", err.Err)
+}
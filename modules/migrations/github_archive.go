@@ -0,0 +1,762 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// The GitHub migration archives are gzipped tarballs of JSON files, the objects of a kind are
+// listed in the files "<kind>_000001.json", "<kind>_000002.json"... and reference each
+// other by their URLs. Only the issue tracker data is dumped and restored: the labels, the
+// milestones, the issues and their comments and reactions.
+
+const githubArchiveSchemaVersion = "1.0.1"
+
+type githubArchiveSchema struct {
+	Version string `json:"version"`
+}
+
+type githubArchiveLabel struct {
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type githubArchiveRepository struct {
+	Type        string                `json:"type"`
+	URL         string                `json:"url"`
+	Owner       string                `json:"owner"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Private     bool                  `json:"private"`
+	HasIssues   bool                  `json:"has_issues"`
+	Labels      []*githubArchiveLabel `json:"labels"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+type githubArchiveUser struct {
+	Type      string    `json:"type"`
+	URL       string    `json:"url"`
+	Login     string    `json:"login"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type githubArchiveReaction struct {
+	User      string    `json:"user"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type githubArchiveMilestone struct {
+	Type        string     `json:"type"`
+	URL         string     `json:"url"`
+	Repository  string     `json:"repository"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+type githubArchiveIssue struct {
+	Type       string                   `json:"type"`
+	URL        string                   `json:"url"`
+	Repository string                   `json:"repository"`
+	User       string                   `json:"user"`
+	Title      string                   `json:"title"`
+	Body       string                   `json:"body"`
+	Milestone  string                   `json:"milestone,omitempty"`
+	Labels     []string                 `json:"labels"`
+	Reactions  []*githubArchiveReaction `json:"reactions"`
+	Locked     bool                     `json:"locked"`
+	ClosedAt   *time.Time               `json:"closed_at"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+type githubArchiveComment struct {
+	Type      string                   `json:"type"`
+	URL       string                   `json:"url"`
+	Issue     string                   `json:"issue"`
+	User      string                   `json:"user"`
+	Body      string                   `json:"body"`
+	Formatter string                   `json:"formatter"`
+	Reactions []*githubArchiveReaction `json:"reactions"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+func writeGithubArchiveFile(tw *tar.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// githubArchiveDumper collects the objects of the dumped repository
+type githubArchiveDumper struct {
+	repo       *models.Repository
+	users      map[int64]*githubArchiveUser
+	labels     map[string]*githubArchiveLabel
+	milestones map[int64]*githubArchiveMilestone
+}
+
+// userURL returns the URL of the user and adds it to the dumped users, the migrated objects
+// whose original authors have no account aren't attributed to anyone
+func (d *githubArchiveDumper) userURL(user *models.User, originalAuthor string) string {
+	if len(originalAuthor) > 0 || user == nil || user.ID <= 0 {
+		return ""
+	}
+	if _, ok := d.users[user.ID]; !ok {
+		d.users[user.ID] = &githubArchiveUser{
+			Type:      "user",
+			URL:       user.HTMLURL(),
+			Login:     user.Name,
+			Name:      user.FullName,
+			CreatedAt: user.CreatedUnix.AsTime(),
+		}
+	}
+	return user.HTMLURL()
+}
+
+func (d *githubArchiveDumper) labelURL(label *models.Label) string {
+	labelURL := d.repo.HTMLURL() + "/labels/" + url.PathEscape(label.Name)
+	if _, ok := d.labels[label.Name]; !ok {
+		d.labels[label.Name] = &githubArchiveLabel{
+			Type:        "label",
+			URL:         labelURL,
+			Name:        label.Name,
+			Color:       strings.TrimPrefix(label.Color, "#"),
+			Description: label.Description,
+		}
+	}
+	return labelURL
+}
+
+func (d *githubArchiveDumper) reactions(reactions models.ReactionList) []*githubArchiveReaction {
+	result := make([]*githubArchiveReaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		result = append(result, &githubArchiveReaction{
+			User:      d.userURL(reaction.User, reaction.OriginalAuthor),
+			Content:   reaction.Type,
+			CreatedAt: reaction.CreatedUnix.AsTime(),
+		})
+	}
+	return result
+}
+
+// DumpGithubArchive writes the labels, the milestones and the issues of the repository with
+// their comments to a GitHub migration archive, the pull requests aren't dumped
+func DumpGithubArchive(repo *models.Repository, w io.Writer) error {
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	d := &githubArchiveDumper{
+		repo:       repo,
+		users:      make(map[int64]*githubArchiveUser),
+		labels:     make(map[string]*githubArchiveLabel),
+		milestones: make(map[int64]*githubArchiveMilestone),
+	}
+	repoURL := repo.HTMLURL()
+
+	labels, err := models.GetLabelsByRepoID(repo.ID, "", models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		d.labelURL(label)
+	}
+
+	milestones, err := models.GetMilestones(models.GetMilestonesOption{RepoID: repo.ID, State: api.StateAll})
+	if err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		dumped := &githubArchiveMilestone{
+			Type:        "milestone",
+			URL:         fmt.Sprintf("%s/milestones/%d", repoURL, milestone.ID),
+			Repository:  repoURL,
+			Title:       milestone.Name,
+			Description: milestone.Content,
+			State:       string(milestone.State()),
+			CreatedAt:   milestone.CreatedUnix.AsTime(),
+			UpdatedAt:   milestone.UpdatedUnix.AsTime(),
+		}
+		// the milestones without deadline have a deadline in the year 9999
+		if milestone.DeadlineUnix > 0 && milestone.DeadlineUnix.Year() < 9999 {
+			dueOn := milestone.DeadlineUnix.AsTime()
+			dumped.DueOn = &dueOn
+		}
+		if milestone.IsClosed && milestone.ClosedDateUnix > 0 {
+			closedAt := milestone.ClosedDateUnix.AsTime()
+			dumped.ClosedAt = &closedAt
+		}
+		d.milestones[milestone.ID] = dumped
+	}
+
+	issues, err := models.Issues(&models.IssuesOptions{
+		RepoIDs:  []int64{repo.ID},
+		IsPull:   util.OptionalBoolFalse,
+		SortType: "oldest",
+	})
+	if err != nil {
+		return err
+	}
+	dumpedIssues := make([]*githubArchiveIssue, 0, len(issues))
+	dumpedComments := make([]*githubArchiveComment, 0, len(issues))
+	for _, issue := range issues {
+		issueURL := fmt.Sprintf("%s/issues/%d", repoURL, issue.Index)
+		reactions, err := models.FindIssueReactions(issue, models.ListOptions{})
+		if err != nil {
+			return err
+		}
+		if _, err := reactions.LoadUsers(repo); err != nil {
+			return err
+		}
+		dumped := &githubArchiveIssue{
+			Type:       "issue",
+			URL:        issueURL,
+			Repository: repoURL,
+			User:       d.userURL(issue.Poster, issue.OriginalAuthor),
+			Title:      issue.Title,
+			Body:       issue.Content,
+			Labels:     make([]string, 0, len(issue.Labels)),
+			Reactions:  d.reactions(reactions),
+			Locked:     issue.IsLocked,
+			CreatedAt:  issue.CreatedUnix.AsTime(),
+			UpdatedAt:  issue.UpdatedUnix.AsTime(),
+		}
+		if milestone, ok := d.milestones[issue.MilestoneID]; ok {
+			dumped.Milestone = milestone.URL
+		}
+		for _, label := range issue.Labels {
+			dumped.Labels = append(dumped.Labels, d.labelURL(label))
+		}
+		if issue.IsClosed {
+			closedAt := issue.ClosedUnix.AsTime()
+			dumped.ClosedAt = &closedAt
+		}
+		dumpedIssues = append(dumpedIssues, dumped)
+
+		comments, err := models.FindComments(models.FindCommentsOptions{
+			IssueID: issue.ID,
+			Type:    models.CommentTypeComment,
+		})
+		if err != nil {
+			return err
+		}
+		if err := models.CommentList(comments).LoadPosters(); err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			reactions, err := models.FindCommentReactions(comment)
+			if err != nil {
+				return err
+			}
+			if _, err := reactions.LoadUsers(repo); err != nil {
+				return err
+			}
+			dumpedComments = append(dumpedComments, &githubArchiveComment{
+				Type:      "issue_comment",
+				URL:       fmt.Sprintf("%s#issuecomment-%d", issueURL, comment.ID),
+				Issue:     issueURL,
+				User:      d.userURL(comment.Poster, comment.OriginalAuthor),
+				Body:      comment.Content,
+				Formatter: "markdown",
+				Reactions: d.reactions(reactions),
+				CreatedAt: comment.CreatedUnix.AsTime(),
+				UpdatedAt: comment.UpdatedUnix.AsTime(),
+			})
+		}
+	}
+
+	dumpedRepo := &githubArchiveRepository{
+		Type:        "repository",
+		URL:         repoURL,
+		Owner:       d.userURL(repo.Owner, ""),
+		Name:        repo.Name,
+		Description: repo.Description,
+		Private:     repo.IsPrivate,
+		HasIssues:   true,
+		Labels:      make([]*githubArchiveLabel, 0, len(d.labels)),
+		CreatedAt:   repo.CreatedUnix.AsTime(),
+	}
+	for _, label := range d.labels {
+		dumpedRepo.Labels = append(dumpedRepo.Labels, label)
+	}
+	sort.Slice(dumpedRepo.Labels, func(i, j int) bool {
+		return dumpedRepo.Labels[i].Name < dumpedRepo.Labels[j].Name
+	})
+	dumpedUsers := make([]*githubArchiveUser, 0, len(d.users))
+	for _, user := range d.users {
+		dumpedUsers = append(dumpedUsers, user)
+	}
+	sort.Slice(dumpedUsers, func(i, j int) bool {
+		return dumpedUsers[i].Login < dumpedUsers[j].Login
+	})
+	dumpedMilestones := make([]*githubArchiveMilestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		dumpedMilestones = append(dumpedMilestones, d.milestones[milestone.ID])
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	for _, file := range []struct {
+		name    string
+		content interface{}
+	}{
+		{"schema.json", &githubArchiveSchema{Version: githubArchiveSchemaVersion}},
+		{"repositories_000001.json", []*githubArchiveRepository{dumpedRepo}},
+		{"users_000001.json", dumpedUsers},
+		{"milestones_000001.json", dumpedMilestones},
+		{"issues_000001.json", dumpedIssues},
+		{"issue_comments_000001.json", dumpedComments},
+	} {
+		if err := writeGithubArchiveFile(tw, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+var (
+	_ base.Downloader = &GithubArchiveDownloader{}
+
+	githubArchiveFilePattern  = regexp.MustCompile(`^([a-z_]+)_\d+\.json$`)
+	githubArchiveIssuePattern = regexp.MustCompile(`/issues/(\d+)$`)
+)
+
+// GithubArchiveDownloader implements a Downloader reading a GitHub migration archive, the
+// users of the archive which are users of this instance keep their contributions
+type GithubArchiveDownloader struct {
+	ctx        context.Context
+	repo       *githubArchiveRepository
+	users      map[string]*githubArchiveUser
+	milestones []*githubArchiveMilestone
+	issues     []*githubArchiveIssue
+	comments   map[int64][]*githubArchiveComment
+	userIDs    map[string]int64
+}
+
+// NewGithubArchiveDownloader reads a GitHub migration archive
+func NewGithubArchiveDownloader(ctx context.Context, r io.Reader) (*GithubArchiveDownloader, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidGithubArchive{Err: err}
+	}
+	defer gzr.Close()
+
+	d := &GithubArchiveDownloader{
+		ctx:      ctx,
+		users:    make(map[string]*githubArchiveUser),
+		comments: make(map[int64][]*githubArchiveComment),
+		userIDs:  make(map[string]int64),
+	}
+	var (
+		repos    []*githubArchiveRepository
+		comments []*githubArchiveComment
+		users    []*githubArchiveUser
+	)
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, ErrInvalidGithubArchive{Err: err}
+		}
+		m := githubArchiveFilePattern.FindStringSubmatch(path.Base(hdr.Name))
+		if hdr.Typeflag != tar.TypeReg || m == nil {
+			continue
+		}
+
+		// the files of the other kinds of objects are ignored
+		var v interface{}
+		switch m[1] {
+		case "repositories":
+			v = &repos
+		case "users":
+			v = &users
+		case "milestones":
+			v = &d.milestones
+		case "issues":
+			v = &d.issues
+		case "issue_comments":
+			v = &comments
+		default:
+			continue
+		}
+		// the files of a kind are appended to the objects of the previous ones
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, ErrInvalidGithubArchive{Err: fmt.Errorf("%s: %v", hdr.Name, err)}
+		}
+	}
+
+	if len(repos) == 0 {
+		return nil, ErrInvalidGithubArchive{Err: errors.New("no repository")}
+	}
+	d.repo = repos[0]
+	for _, user := range users {
+		d.users[user.URL] = user
+	}
+	for _, comment := range comments {
+		index, err := githubArchiveIssueIndex(comment.Issue)
+		if err != nil {
+			return nil, err
+		}
+		d.comments[index] = append(d.comments[index], comment)
+	}
+	sort.SliceStable(d.issues, func(i, j int) bool {
+		return d.issues[i].CreatedAt.Before(d.issues[j].CreatedAt)
+	})
+	return d, nil
+}
+
+func githubArchiveIssueIndex(issueURL string) (int64, error) {
+	m := githubArchiveIssuePattern.FindStringSubmatch(issueURL)
+	if m == nil {
+		return 0, ErrInvalidGithubArchive{Err: fmt.Errorf("invalid issue URL: %s", issueURL)}
+	}
+	return strconv.ParseInt(m[1], 10, 64)
+}
+
+// user returns the ID of the user of this instance and the login of the user of the URL, the
+// ID is 0 if the user isn't a user of this instance
+func (d *GithubArchiveDownloader) user(userURL string) (int64, string) {
+	if len(userURL) == 0 {
+		return 0, ""
+	}
+	login := path.Base(userURL)
+	if user, ok := d.users[userURL]; ok {
+		login = user.Login
+	}
+	if !strings.HasPrefix(userURL, setting.AppURL) {
+		return 0, login
+	}
+	id, ok := d.userIDs[login]
+	if !ok {
+		user, err := models.GetUserByName(login)
+		if err == nil {
+			id = user.ID
+		} else if !models.IsErrUserNotExist(err) {
+			log.Error("GetUserByName: %v", err)
+		}
+		d.userIDs[login] = id
+	}
+	return id, login
+}
+
+// LocalUserIDs returns the IDs of the users of this instance which are users of the archive
+func (d *GithubArchiveDownloader) LocalUserIDs() []int64 {
+	ids := make([]int64, 0, len(d.userIDs))
+	for _, id := range d.userIDs {
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (d *GithubArchiveDownloader) reactions(reactions []*githubArchiveReaction) []*base.Reaction {
+	result := make([]*base.Reaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		id, login := d.user(reaction.User)
+		result = append(result, &base.Reaction{
+			UserID:   id,
+			UserName: login,
+			Content:  reaction.Content,
+		})
+	}
+	return result
+}
+
+// SetContext set context
+func (d *GithubArchiveDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// GetRepoInfo returns the repository of the archive
+func (d *GithubArchiveDownloader) GetRepoInfo() (*base.Repository, error) {
+	_, owner := d.user(d.repo.Owner)
+	return &base.Repository{
+		Name:        d.repo.Name,
+		Owner:       owner,
+		IsPrivate:   d.repo.Private,
+		Description: d.repo.Description,
+		OriginalURL: d.repo.URL,
+	}, nil
+}
+
+// GetTopics returns no topic, the archives have no topic
+func (d *GithubArchiveDownloader) GetTopics() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetMilestones returns the milestones of the archive
+func (d *GithubArchiveDownloader) GetMilestones() ([]*base.Milestone, error) {
+	milestones := make([]*base.Milestone, 0, len(d.milestones))
+	for _, milestone := range d.milestones {
+		updated := milestone.UpdatedAt
+		milestones = append(milestones, &base.Milestone{
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			Deadline:    milestone.DueOn,
+			Created:     milestone.CreatedAt,
+			Updated:     &updated,
+			Closed:      milestone.ClosedAt,
+			State:       milestone.State,
+		})
+	}
+	return milestones, nil
+}
+
+// GetReleases returns no release, the releases aren't restored
+func (d *GithubArchiveDownloader) GetReleases() ([]*base.Release, error) {
+	return []*base.Release{}, nil
+}
+
+// GetAsset returns no asset
+func (d *GithubArchiveDownloader) GetAsset(_ string, _, _ int64) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+// GetLabels returns the labels of the archive
+func (d *GithubArchiveDownloader) GetLabels() ([]*base.Label, error) {
+	labels := make([]*base.Label, 0, len(d.repo.Labels))
+	for _, label := range d.repo.Labels {
+		labels = append(labels, &base.Label{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+	return labels, nil
+}
+
+// GetIssues returns the issues of the archive
+func (d *GithubArchiveDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	start := (page - 1) * perPage
+	if start >= len(d.issues) {
+		return []*base.Issue{}, true, nil
+	}
+	end := start + perPage
+	if end > len(d.issues) {
+		end = len(d.issues)
+	}
+
+	milestones := make(map[string]string, len(d.milestones))
+	for _, milestone := range d.milestones {
+		milestones[milestone.URL] = milestone.Title
+	}
+	labels := make(map[string]*base.Label, len(d.repo.Labels))
+	for _, label := range d.repo.Labels {
+		labels[label.URL] = &base.Label{Name: label.Name}
+	}
+
+	issues := make([]*base.Issue, 0, end-start)
+	for _, issue := range d.issues[start:end] {
+		index, err := githubArchiveIssueIndex(issue.URL)
+		if err != nil {
+			return nil, false, err
+		}
+		id, login := d.user(issue.User)
+		restored := &base.Issue{
+			Number:     index,
+			PosterID:   id,
+			PosterName: login,
+			Title:      issue.Title,
+			Content:    issue.Body,
+			Milestone:  milestones[issue.Milestone],
+			State:      "open",
+			IsLocked:   issue.Locked,
+			Created:    issue.CreatedAt,
+			Updated:    issue.UpdatedAt,
+			Closed:     issue.ClosedAt,
+			Reactions:  d.reactions(issue.Reactions),
+		}
+		if issue.ClosedAt != nil {
+			restored.State = "closed"
+		}
+		for _, labelURL := range issue.Labels {
+			if label, ok := labels[labelURL]; ok {
+				restored.Labels = append(restored.Labels, label)
+			}
+		}
+		issues = append(issues, restored)
+	}
+	return issues, end == len(d.issues), nil
+}
+
+// GetComments returns the comments of the issue of the archive
+func (d *GithubArchiveDownloader) GetComments(issueNumber int64) ([]*base.Comment, error) {
+	comments := make([]*base.Comment, 0, len(d.comments[issueNumber]))
+	for _, comment := range d.comments[issueNumber] {
+		id, login := d.user(comment.User)
+		comments = append(comments, &base.Comment{
+			IssueIndex: issueNumber,
+			PosterID:   id,
+			PosterName: login,
+			Created:    comment.CreatedAt,
+			Updated:    comment.UpdatedAt,
+			Content:    comment.Body,
+			Reactions:  d.reactions(comment.Reactions),
+		})
+	}
+	return comments, nil
+}
+
+// GetPullRequests returns no pull request, the pull requests aren't restored
+func (d *GithubArchiveDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	return []*base.PullRequest{}, true, nil
+}
+
+// GetReviews returns no review, the pull requests aren't restored
+func (d *GithubArchiveDownloader) GetReviews(pullRequestNumber int64) ([]*base.Review, error) {
+	return []*base.Review{}, nil
+}
+
+// RestoreGithubArchive restores the labels, the milestones and the issues of a GitHub migration
+// archive into the repository, whose issue tracker has to be empty to keep the numbers of the
+// issues. The labels and the milestones of the repository are kept, the restored labels and
+// milestones of the same names are merged with them.
+func RestoreGithubArchive(ctx context.Context, doer *models.User, repo *models.Repository, r io.Reader) error {
+	if repo.NumIssues > 0 || repo.NumPulls > 0 {
+		return ErrTrackerNotEmpty
+	}
+
+	downloader, err := NewGithubArchiveDownloader(ctx, r)
+	if err != nil {
+		return err
+	}
+	uploader := NewGiteaLocalUploader(ctx, doer, repo.OwnerName, repo.Name)
+	uploader.repo = repo
+
+	labels, err := models.GetLabelsByRepoID(repo.ID, "", models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		uploader.labels.Store(label.Name, label)
+	}
+	milestones, err := models.GetMilestones(models.GetMilestonesOption{RepoID: repo.ID, State: api.StateAll})
+	if err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		uploader.milestones.Store(milestone.Name, milestone.ID)
+	}
+
+	restoredMilestones, err := downloader.GetMilestones()
+	if err != nil {
+		return err
+	}
+	newMilestones := make([]*base.Milestone, 0, len(restoredMilestones))
+	for _, milestone := range restoredMilestones {
+		if _, ok := uploader.milestones.Load(milestone.Title); !ok {
+			newMilestones = append(newMilestones, milestone)
+		}
+	}
+	if err := uploader.CreateMilestones(newMilestones...); err != nil {
+		return err
+	}
+
+	restoredLabels, err := downloader.GetLabels()
+	if err != nil {
+		return err
+	}
+	newLabels := make([]*base.Label, 0, len(restoredLabels))
+	for _, label := range restoredLabels {
+		if _, ok := uploader.labels.Load(label.Name); !ok {
+			newLabels = append(newLabels, label)
+		}
+	}
+	if len(newLabels) > 0 {
+		if err := uploader.CreateLabels(newLabels...); err != nil {
+			return err
+		}
+	}
+
+	batchSize := uploader.MaxBatchInsertSize("issue")
+	for page := 1; ; page++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		issues, isEnd, err := downloader.GetIssues(page, batchSize)
+		if err != nil {
+			return err
+		}
+		// the users of this instance are mapped to themselves
+		for _, id := range downloader.LocalUserIDs() {
+			uploader.userMap[id] = id
+		}
+		if err := uploader.CreateIssues(issues...); err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			comments, err := downloader.GetComments(issue.Number)
+			if err != nil {
+				return err
+			}
+			for _, id := range downloader.LocalUserIDs() {
+				uploader.userMap[id] = id
+			}
+			if err := uploader.CreateComments(comments...); err != nil {
+				return err
+			}
+		}
+		if isEnd {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubArchive(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	var buf bytes.Buffer
+	assert.NoError(t, DumpGithubArchive(repo, &buf))
+	archive := buf.Bytes()
+
+	downloader, err := NewGithubArchiveDownloader(context.Background(), bytes.NewReader(archive))
+	assert.NoError(t, err)
+	info, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "repo1", info.Name)
+	assert.Equal(t, "user2", info.Owner)
+	// the pull requests aren't dumped
+	issues, isEnd, err := downloader.GetIssues(1, 10)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "open", issues[0].State)
+		assert.EqualValues(t, 4, issues[1].Number)
+		assert.Equal(t, "closed", issues[1].State)
+	}
+
+	// the repository 16 of the same owner has no issue
+	target := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 16}).(*models.Repository)
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NoError(t, RestoreGithubArchive(context.Background(), doer, target, bytes.NewReader(archive)))
+
+	for _, index := range []int64{1, 4} {
+		original, err := models.GetIssueByIndex(repo.ID, index)
+		assert.NoError(t, err)
+		assert.NoError(t, original.LoadAttributes())
+		restored, err := models.GetIssueByIndex(target.ID, index)
+		assert.NoError(t, err)
+		assert.NoError(t, restored.LoadAttributes())
+
+		assert.Equal(t, original.Title, restored.Title)
+		assert.Equal(t, original.Content, restored.Content)
+		assert.Equal(t, original.PosterID, restored.PosterID)
+		assert.Equal(t, original.IsClosed, restored.IsClosed)
+		assert.Equal(t, original.CreatedUnix, restored.CreatedUnix)
+		assert.Len(t, restored.Labels, len(original.Labels))
+		for i := range restored.Labels {
+			assert.Equal(t, original.Labels[i].Name, restored.Labels[i].Name)
+			assert.EqualValues(t, target.ID, restored.Labels[i].RepoID)
+		}
+		assert.Equal(t, original.NumComments, restored.NumComments)
+	}
+
+	target = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 16}).(*models.Repository)
+	assert.EqualValues(t, 2, target.NumIssues)
+	assert.EqualValues(t, 1, target.NumClosedIssues)
+	assert.Equal(t, ErrTrackerNotEmpty, RestoreGithubArchive(context.Background(), doer, target, bytes.NewReader(archive)))
+
+	_, err = NewGithubArchiveDownloader(context.Background(), strings.NewReader("not an archive"))
+	assert.True(t, IsErrInvalidGithubArchive(err))
+}
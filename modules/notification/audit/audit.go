@@ -0,0 +1,38 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+type auditNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &auditNotifier{}
+)
+
+// NewNotifier create a new auditNotifier notifier which records the notified security-relevant events
+// in the audit log
+func NewNotifier() base.Notifier {
+	return &auditNotifier{}
+}
+
+func (*auditNotifier) NotifyAuthFailure(loginName, remoteAddress string, method api.HookAuthFailureMethod) {
+	var target interface{}
+	u, err := models.GetUserByName(loginName)
+	if err == nil {
+		target = u
+	} else if !models.IsErrUserNotExist(err) {
+		log.Error("GetUserByName[%s]: %v", loginName, err)
+	}
+	audit.Record(models.AuditActionUserLoginFailed, nil, remoteAddress, target, "Failed %s authentication as %s", method, loginName)
+}
@@ -7,6 +7,7 @@ package base
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // Notifier defines an interface to notify receiver
@@ -20,6 +21,12 @@ type Notifier interface {
 	NotifyRenameRepository(doer *models.User, repo *models.Repository, oldRepoName string)
 	NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string)
 
+	NotifyCreateUser(doer *models.User, u *models.User)
+	NotifyDeleteUser(doer *models.User, u *models.User)
+	NotifyCreateOrganization(doer *models.User, org *models.User)
+	NotifyDeleteOrganization(doer *models.User, org *models.User)
+	NotifyAuthFailure(loginName, remoteAddress string, method api.HookAuthFailureMethod)
+
 	NotifyNewIssue(*models.Issue)
 	NotifyIssueChangeStatus(*models.User, *models.Issue, *models.Comment, bool)
 	NotifyIssueChangeMilestone(doer *models.User, issue *models.Issue, oldMilestoneID int64)
@@ -38,6 +45,7 @@ type Notifier interface {
 	NotifyPullRequestReview(*models.PullRequest, *models.Review, *models.Comment)
 	NotifyPullRequestChangeTargetBranch(doer *models.User, pr *models.PullRequest, oldBranch string)
 	NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment)
+	NotifyReviewEnvironmentsExpired(doer *models.User, pr *models.PullRequest, envs []*models.ReviewEnvironment)
 
 	NotifyCreateIssueComment(*models.User, *models.Repository,
 		*models.Issue, *models.Comment)
@@ -7,6 +7,7 @@ package base
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // NullNotifier implements a blank notifier
@@ -58,6 +59,10 @@ func (*NullNotifier) NotifyPullRequestChangeTargetBranch(doer *models.User, pr *
 func (*NullNotifier) NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
 }
 
+// NotifyReviewEnvironmentsExpired places a place holder function
+func (*NullNotifier) NotifyReviewEnvironmentsExpired(doer *models.User, pr *models.PullRequest, envs []*models.ReviewEnvironment) {
+}
+
 // NotifyUpdateComment places a place holder function
 func (*NullNotifier) NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
 }
@@ -147,6 +152,26 @@ func (*NullNotifier) NotifyRenameRepository(doer *models.User, repo *models.Repo
 func (*NullNotifier) NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string) {
 }
 
+// NotifyCreateUser places a place holder function
+func (*NullNotifier) NotifyCreateUser(doer *models.User, u *models.User) {
+}
+
+// NotifyDeleteUser places a place holder function
+func (*NullNotifier) NotifyDeleteUser(doer *models.User, u *models.User) {
+}
+
+// NotifyCreateOrganization places a place holder function
+func (*NullNotifier) NotifyCreateOrganization(doer *models.User, org *models.User) {
+}
+
+// NotifyDeleteOrganization places a place holder function
+func (*NullNotifier) NotifyDeleteOrganization(doer *models.User, org *models.User) {
+}
+
+// NotifyAuthFailure places a place holder function
+func (*NullNotifier) NotifyAuthFailure(loginName, remoteAddress string, method api.HookAuthFailureMethod) {
+}
+
 // NotifySyncPushCommits places a place holder function
 func (*NullNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
 }
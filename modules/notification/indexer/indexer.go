@@ -10,6 +10,7 @@ import (
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
+	symbols_indexer "code.gitea.io/gitea/modules/indexer/symbols"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification/base"
 	"code.gitea.io/gitea/modules/repository"
@@ -121,6 +122,9 @@ func (r *indexerNotifier) NotifyMigrateRepository(doer *models.User, u *models.U
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if err := symbols_indexer.UpdateRepoIndexer(repo); err != nil {
+		log.Error("symbols_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+	}
 }
 
 func (r *indexerNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
@@ -130,6 +134,9 @@ func (r *indexerNotifier) NotifyPushCommits(pusher *models.User, repo *models.Re
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if err := symbols_indexer.UpdateRepoIndexer(repo); err != nil {
+		log.Error("symbols_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+	}
 }
 
 func (r *indexerNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
@@ -139,6 +146,9 @@ func (r *indexerNotifier) NotifySyncPushCommits(pusher *models.User, repo *model
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
+	if err := symbols_indexer.UpdateRepoIndexer(repo); err != nil {
+		log.Error("symbols_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
+	}
 }
 
 func (r *indexerNotifier) NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string) {
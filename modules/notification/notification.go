@@ -7,13 +7,16 @@ package notification
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/notification/action"
+	"code.gitea.io/gitea/modules/notification/audit"
 	"code.gitea.io/gitea/modules/notification/base"
 	"code.gitea.io/gitea/modules/notification/indexer"
 	"code.gitea.io/gitea/modules/notification/mail"
+	"code.gitea.io/gitea/modules/notification/project"
 	"code.gitea.io/gitea/modules/notification/ui"
 	"code.gitea.io/gitea/modules/notification/webhook"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 var (
@@ -35,6 +38,8 @@ func NewContext() {
 	RegisterNotifier(indexer.NewNotifier())
 	RegisterNotifier(webhook.NewNotifier())
 	RegisterNotifier(action.NewNotifier())
+	RegisterNotifier(project.NewNotifier())
+	RegisterNotifier(audit.NewNotifier())
 }
 
 // NotifyCreateIssueComment notifies issue comment related message to notifiers
@@ -101,6 +106,13 @@ func NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, com
 	}
 }
 
+// NotifyReviewEnvironmentsExpired notifies when the review environments of a pull request expired
+func NotifyReviewEnvironmentsExpired(doer *models.User, pr *models.PullRequest, envs []*models.ReviewEnvironment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyReviewEnvironmentsExpired(doer, pr, envs)
+	}
+}
+
 // NotifyUpdateComment notifies update comment to notifiers
 func NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
 	for _, notifier := range notifiers {
@@ -214,6 +226,41 @@ func NotifyTransferRepository(doer *models.User, repo *models.Repository, newOwn
 	}
 }
 
+// NotifyCreateUser notifies create user to notifiers
+func NotifyCreateUser(doer *models.User, u *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateUser(doer, u)
+	}
+}
+
+// NotifyDeleteUser notifies delete user to notifiers
+func NotifyDeleteUser(doer *models.User, u *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteUser(doer, u)
+	}
+}
+
+// NotifyCreateOrganization notifies create organization to notifiers
+func NotifyCreateOrganization(doer *models.User, org *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateOrganization(doer, org)
+	}
+}
+
+// NotifyDeleteOrganization notifies delete organization to notifiers
+func NotifyDeleteOrganization(doer *models.User, org *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteOrganization(doer, org)
+	}
+}
+
+// NotifyAuthFailure notifies a failed authentication attempt to notifiers
+func NotifyAuthFailure(loginName, remoteAddress string, method api.HookAuthFailureMethod) {
+	for _, notifier := range notifiers {
+		notifier.NotifyAuthFailure(loginName, remoteAddress, method)
+	}
+}
+
 // NotifyDeleteRepository notifies delete repository to notifiers
 func NotifyDeleteRepository(doer *models.User, repo *models.Repository) {
 	for _, notifier := range notifiers {
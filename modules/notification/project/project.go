@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type projectNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &projectNotifier{}
+)
+
+// NewNotifier create a new projectNotifier notifier which applies the automation rules of the projects
+func NewNotifier() base.Notifier {
+	return &projectNotifier{}
+}
+
+func (*projectNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
+	event := models.ProjectAutomationEventReopened
+	if isClosed {
+		event = models.ProjectAutomationEventClosed
+	}
+	if err := models.ApplyProjectAutomationRules(issue, event); err != nil {
+		log.Error("ApplyProjectAutomationRules[%d]: %v", issue.ID, err)
+	}
+}
+
+func (*projectNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
+	addedLabels []*models.Label, removedLabels []*models.Label) {
+	// the labels which are replaced by themselves aren't added
+	removed := make(map[int64]bool, len(removedLabels))
+	for _, label := range removedLabels {
+		removed[label.ID] = true
+	}
+	labelIDs := make([]int64, 0, len(addedLabels))
+	for _, label := range addedLabels {
+		if !removed[label.ID] {
+			labelIDs = append(labelIDs, label.ID)
+		}
+	}
+	if err := models.ApplyProjectAutomationRules(issue, models.ProjectAutomationEventLabeled, labelIDs...); err != nil {
+		log.Error("ApplyProjectAutomationRules[%d]: %v", issue.ID, err)
+	}
+}
+
+func (*projectNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue[%d]: %v", pr.ID, err)
+		return
+	}
+	if err := models.ApplyProjectAutomationRules(pr.Issue, models.ProjectAutomationEventMerged); err != nil {
+		log.Error("ApplyProjectAutomationRules[%d]: %v", pr.Issue.ID, err)
+	}
+}
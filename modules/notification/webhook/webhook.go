@@ -5,6 +5,8 @@
 package webhook
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
@@ -122,6 +124,57 @@ func (m *webhookNotifier) NotifyDeleteRepository(doer *models.User, repo *models
 	}
 }
 
+func (m *webhookNotifier) NotifyCreateUser(doer *models.User, u *models.User) {
+	if err := webhook_module.PrepareSystemWebhooks(models.HookEventUser, &api.UserPayload{
+		Action: api.HookUserCreated,
+		User:   convert.ToUser(u, false, true),
+		Sender: convert.ToUser(doer, false, false),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [user_id: %d]: %v", u.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyDeleteUser(doer *models.User, u *models.User) {
+	if err := webhook_module.PrepareSystemWebhooks(models.HookEventUser, &api.UserPayload{
+		Action: api.HookUserDeleted,
+		User:   convert.ToUser(u, false, true),
+		Sender: convert.ToUser(doer, false, false),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [user_id: %d]: %v", u.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyCreateOrganization(doer *models.User, org *models.User) {
+	if err := webhook_module.PrepareSystemWebhooks(models.HookEventOrganization, &api.OrganizationPayload{
+		Action:       api.HookOrganizationCreated,
+		Organization: convert.ToOrganization(org),
+		Sender:       convert.ToUser(doer, false, false),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [org_id: %d]: %v", org.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyDeleteOrganization(doer *models.User, org *models.User) {
+	if err := webhook_module.PrepareSystemWebhooks(models.HookEventOrganization, &api.OrganizationPayload{
+		Action:       api.HookOrganizationDeleted,
+		Organization: convert.ToOrganization(org),
+		Sender:       convert.ToUser(doer, false, false),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [org_id: %d]: %v", org.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyAuthFailure(loginName, remoteAddress string, method api.HookAuthFailureMethod) {
+	if err := webhook_module.PrepareSystemWebhooks(models.HookEventAuthFailure, &api.AuthFailurePayload{
+		Method:        method,
+		LoginName:     loginName,
+		RemoteAddress: remoteAddress,
+		Time:          time.Now(),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [login_name: %s]: %v", loginName, err)
+	}
+}
+
 func (m *webhookNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
 	if issue.IsPull {
 		mode, _ := models.AccessLevelUnit(doer, issue.Repo, models.UnitTypePullRequests)
@@ -731,6 +784,36 @@ func (m *webhookNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *m
 	}
 }
 
+func (m *webhookNotifier) NotifyReviewEnvironmentsExpired(doer *models.User, pr *models.PullRequest, envs []*models.ReviewEnvironment) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("pr.LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	apiPullRequest := convert.ToAPIPullRequest(pr)
+	apiRepo := convert.ToRepo(pr.Issue.Repo, models.AccessModeNone)
+	apiSender := convert.ToUser(doer, false, false)
+	for _, env := range envs {
+		if err := env.LoadCreator(); err != nil {
+			log.Error("LoadCreator: %v", err)
+			return
+		}
+		if err := webhook_module.PrepareWebhooks(pr.Issue.Repo, models.HookEventReviewEnvironment, &api.ReviewEnvironmentPayload{
+			Action:      api.HookReviewEnvironmentCleanup,
+			Environment: convert.ToReviewEnvironment(env),
+			PullRequest: apiPullRequest,
+			Repository:  apiRepo,
+			Sender:      apiSender,
+		}); err != nil {
+			log.Error("PrepareWebhooks [pull_id: %v, environment: %s]: %v", pr.ID, env.Name, err)
+		}
+	}
+}
+
 func (m *webhookNotifier) NotifyDeleteRef(pusher *models.User, repo *models.Repository, refType, refFullName string) {
 	apiPusher := convert.ToUser(pusher, false, false)
 	apiRepo := convert.ToRepo(repo, models.AccessModeNone)
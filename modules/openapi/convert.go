@@ -0,0 +1,406 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification the specs are converted to
+const Version = "3.1.0"
+
+type object = map[string]interface{}
+
+// refPrefixes maps the prefixes of the references of Swagger 2.0 to the ones of OpenAPI 3
+var refPrefixes = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/responses/":   "#/components/responses/",
+	"#/parameters/":  "#/components/parameters/",
+}
+
+// ConvertSwagger2 converts a Swagger 2.0 spec in JSON to an OpenAPI 3.1 one, the API generated by
+// go-swagger is documented once and both versions of the spec are served
+func ConvertSwagger2(spec []byte) ([]byte, error) {
+	var v2 object
+	if err := json.Unmarshal(spec, &v2); err != nil {
+		return nil, err
+	}
+	if v2["swagger"] != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version: %v", v2["swagger"])
+	}
+
+	c := &converter{
+		consumes: stringList(v2["consumes"]),
+		produces: stringList(v2["produces"]),
+	}
+	v3 := object{
+		"openapi": Version,
+		"info":    v2["info"],
+		"paths":   c.convertPaths(asObject(v2["paths"])),
+	}
+	server := object{"url": "/"}
+	if basePath, ok := v2["basePath"].(string); ok && len(basePath) > 0 {
+		server["url"] = basePath
+	}
+	v3["servers"] = []interface{}{server}
+
+	components := object{}
+	if definitions := asObject(v2["definitions"]); len(definitions) > 0 {
+		schemas := make(object, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if responses := asObject(v2["responses"]); len(responses) > 0 {
+		converted := make(object, len(responses))
+		for name, response := range responses {
+			converted[name] = c.convertResponse(asObject(response), c.produces)
+		}
+		components["responses"] = converted
+	}
+	if securityDefinitions := asObject(v2["securityDefinitions"]); len(securityDefinitions) > 0 {
+		schemes := make(object, len(securityDefinitions))
+		for name, definition := range securityDefinitions {
+			schemes[name] = convertSecurityScheme(asObject(definition))
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		v3["components"] = components
+	}
+	for _, key := range []string{"security", "tags", "externalDocs"} {
+		if value, ok := v2[key]; ok {
+			v3[key] = value
+		}
+	}
+	return json.MarshalIndent(v3, "", "  ")
+}
+
+type converter struct {
+	consumes []string
+	produces []string
+}
+
+func asObject(value interface{}) object {
+	obj, _ := value.(map[string]interface{})
+	return obj
+}
+
+func stringList(value interface{}) []string {
+	values, _ := value.([]interface{})
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// convertRef converts a Swagger 2.0 reference
+func convertRef(ref string) string {
+	for prefix, newPrefix := range refPrefixes {
+		if strings.HasPrefix(ref, prefix) {
+			return newPrefix + strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+// convertSchema converts a Swagger 2.0 schema to a JSON schema of OpenAPI 3.1
+func convertSchema(value interface{}) interface{} {
+	schema := asObject(value)
+	if schema == nil {
+		return value
+	}
+	converted := make(object, len(schema))
+	for key, v := range schema {
+		switch key {
+		case "$ref":
+			converted[key] = convertRef(v.(string))
+		case "items", "additionalProperties", "not":
+			converted[key] = convertSchema(v)
+		case "properties":
+			properties := asObject(v)
+			convertedProperties := make(object, len(properties))
+			for name, property := range properties {
+				convertedProperties[name] = convertSchema(property)
+			}
+			converted[key] = convertedProperties
+		case "allOf", "anyOf", "oneOf":
+			schemas, _ := v.([]interface{})
+			convertedSchemas := make([]interface{}, 0, len(schemas))
+			for _, s := range schemas {
+				convertedSchemas = append(convertedSchemas, convertSchema(s))
+			}
+			converted[key] = convertedSchemas
+		case "example":
+			converted["examples"] = []interface{}{v}
+		case "x-nullable":
+			// handled with the type
+		case "type":
+			if v == "file" {
+				converted["type"] = "string"
+				converted["format"] = "binary"
+			} else {
+				converted[key] = v
+			}
+		default:
+			converted[key] = v
+		}
+	}
+	if nullable, _ := schema["x-nullable"].(bool); nullable {
+		if t, ok := converted["type"].(string); ok {
+			converted["type"] = []interface{}{t, "null"}
+		}
+	}
+	return converted
+}
+
+// parameterSchemaKeys are the keys of the Swagger 2.0 parameters which belong to their schema in OpenAPI 3
+var parameterSchemaKeys = []string{
+	"type", "format", "items", "default", "enum", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+// parameterSchema returns the schema of a parameter which is not in the body
+func parameterSchema(parameter object) object {
+	schema := object{}
+	for _, key := range parameterSchemaKeys {
+		if value, ok := parameter[key]; ok {
+			schema[key] = value
+		}
+	}
+	return asObject(convertSchema(schema))
+}
+
+func (c *converter) convertPaths(paths object) object {
+	converted := make(object, len(paths))
+	for path, value := range paths {
+		item := asObject(value)
+		convertedItem := make(object, len(item))
+		for method, op := range item {
+			if method == "parameters" {
+				convertedItem[method], _ = c.convertParameters(op)
+				continue
+			}
+			convertedItem[method] = c.convertOperation(asObject(op))
+		}
+		converted[path] = convertedItem
+	}
+	return converted
+}
+
+// convertParameters converts the parameters which are not in the body or the form
+func (c *converter) convertParameters(value interface{}) ([]interface{}, []object) {
+	parameters, _ := value.([]interface{})
+	converted := make([]interface{}, 0, len(parameters))
+	var bodyParameters []object
+	for _, p := range parameters {
+		parameter := asObject(p)
+		if ref, ok := parameter["$ref"].(string); ok {
+			converted = append(converted, object{"$ref": convertRef(ref)})
+			continue
+		}
+		switch parameter["in"] {
+		case "body", "formData":
+			bodyParameters = append(bodyParameters, parameter)
+			continue
+		}
+		convertedParameter := object{
+			"name":   parameter["name"],
+			"in":     parameter["in"],
+			"schema": parameterSchema(parameter),
+		}
+		for _, key := range []string{"description", "required", "deprecated", "allowEmptyValue"} {
+			if v, ok := parameter[key]; ok {
+				convertedParameter[key] = v
+			}
+		}
+		switch parameter["collectionFormat"] {
+		case "multi":
+			convertedParameter["style"] = "form"
+			convertedParameter["explode"] = true
+		case "csv":
+			convertedParameter["style"] = "form"
+			convertedParameter["explode"] = false
+		case "ssv":
+			convertedParameter["style"] = "spaceDelimited"
+		case "pipes":
+			convertedParameter["style"] = "pipeDelimited"
+		}
+		converted = append(converted, convertedParameter)
+	}
+	return converted, bodyParameters
+}
+
+// requestBody converts the body and form parameters of an operation to its request body
+func requestBody(bodyParameters []object, consumes []string) object {
+	body := object{}
+	content := object{}
+	formSchema := object{"type": "object"}
+	formProperties := object{}
+	var formRequired []interface{}
+	for _, parameter := range bodyParameters {
+		if parameter["in"] == "body" {
+			if description, ok := parameter["description"]; ok {
+				body["description"] = description
+			}
+			if required, ok := parameter["required"]; ok {
+				body["required"] = required
+			}
+			schema := convertSchema(parameter["schema"])
+			for _, mediaType := range consumes {
+				content[mediaType] = object{"schema": schema}
+			}
+			continue
+		}
+
+		property := parameterSchema(parameter)
+		if description, ok := parameter["description"]; ok {
+			property["description"] = description
+		}
+		name, _ := parameter["name"].(string)
+		formProperties[name] = property
+		if required, _ := parameter["required"].(bool); required {
+			body["required"] = true
+			formRequired = append(formRequired, name)
+		}
+	}
+	if len(formProperties) > 0 {
+		formSchema["properties"] = formProperties
+		if len(formRequired) > 0 {
+			formSchema["required"] = formRequired
+		}
+		mediaTypes := make([]string, 0, len(consumes))
+		for _, mediaType := range consumes {
+			if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+				mediaTypes = append(mediaTypes, mediaType)
+			}
+		}
+		if len(mediaTypes) == 0 {
+			mediaTypes = append(mediaTypes, "multipart/form-data")
+		}
+		for _, mediaType := range mediaTypes {
+			content[mediaType] = object{"schema": formSchema}
+		}
+	}
+	body["content"] = content
+	return body
+}
+
+func (c *converter) convertOperation(op object) object {
+	consumes, produces := c.consumes, c.produces
+	if _, ok := op["consumes"]; ok {
+		consumes = stringList(op["consumes"])
+	}
+	if _, ok := op["produces"]; ok {
+		produces = stringList(op["produces"])
+	}
+
+	converted := make(object, len(op))
+	for key, value := range op {
+		switch key {
+		case "consumes", "produces", "schemes":
+		case "parameters":
+			parameters, bodyParameters := c.convertParameters(value)
+			if len(parameters) > 0 {
+				converted["parameters"] = parameters
+			}
+			if len(bodyParameters) > 0 {
+				converted["requestBody"] = requestBody(bodyParameters, consumes)
+			}
+		case "responses":
+			responses := asObject(value)
+			convertedResponses := make(object, len(responses))
+			for code, response := range responses {
+				convertedResponses[code] = c.convertResponse(asObject(response), produces)
+			}
+			converted[key] = convertedResponses
+		default:
+			converted[key] = value
+		}
+	}
+	return converted
+}
+
+func (c *converter) convertResponse(response object, produces []string) object {
+	if ref, ok := response["$ref"].(string); ok {
+		return object{"$ref": convertRef(ref)}
+	}
+
+	// a description is required by OpenAPI 3
+	converted := object{"description": ""}
+	if description, ok := response["description"]; ok {
+		converted["description"] = description
+	}
+	if schema, ok := response["schema"]; ok {
+		mediaTypes := produces
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/json"}
+		}
+		content := make(object, len(mediaTypes))
+		convertedSchema := convertSchema(schema)
+		for _, mediaType := range mediaTypes {
+			content[mediaType] = object{"schema": convertedSchema}
+		}
+		converted["content"] = content
+	}
+	if headers := asObject(response["headers"]); len(headers) > 0 {
+		convertedHeaders := make(object, len(headers))
+		for name, value := range headers {
+			header := asObject(value)
+			convertedHeader := object{"schema": parameterSchema(header)}
+			if description, ok := header["description"]; ok {
+				convertedHeader["description"] = description
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+	return converted
+}
+
+func convertSecurityScheme(definition object) object {
+	converted := object{}
+	if description, ok := definition["description"]; ok {
+		converted["description"] = description
+	}
+	switch definition["type"] {
+	case "basic":
+		converted["type"] = "http"
+		converted["scheme"] = "basic"
+	case "apiKey":
+		converted["type"] = "apiKey"
+		converted["name"] = definition["name"]
+		converted["in"] = definition["in"]
+	case "oauth2":
+		converted["type"] = "oauth2"
+		flow := object{"scopes": definition["scopes"]}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if value, ok := definition[key]; ok {
+				flow[key] = value
+			}
+		}
+		flows := object{}
+		switch definition["flow"] {
+		case "implicit":
+			flows["implicit"] = flow
+		case "password":
+			flows["password"] = flow
+		case "application":
+			flows["clientCredentials"] = flow
+		case "accessCode":
+			flows["authorizationCode"] = flow
+		}
+		converted["flows"] = flows
+	default:
+		converted["type"] = definition["type"]
+	}
+	return converted
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const swagger2Spec = `{
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "swagger": "2.0",
+  "info": {"title": "Gitea API.", "version": "1.1.1"},
+  "basePath": "/api/v1",
+  "paths": {
+    "/repos/{owner}/{repo}/issues": {
+      "post": {
+        "operationId": "issueCreateIssue",
+        "parameters": [
+          {"type": "string", "name": "owner", "in": "path", "required": true},
+          {"type": "array", "items": {"type": "string"}, "collectionFormat": "multi", "name": "labels", "in": "query"},
+          {"name": "body", "in": "body", "schema": {"$ref": "#/definitions/CreateIssueOption"}}
+        ],
+        "responses": {
+          "201": {"$ref": "#/responses/Issue"},
+          "204": {"description": "empty", "headers": {"X-Total-Count": {"type": "integer"}}}
+        }
+      }
+    },
+    "/repos/{owner}/{repo}/releases/{id}/assets": {
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "operationId": "repoCreateReleaseAttachment",
+        "parameters": [
+          {"type": "file", "description": "attachment to upload", "name": "attachment", "in": "formData", "required": true}
+        ],
+        "responses": {}
+      }
+    }
+  },
+  "definitions": {
+    "CreateIssueOption": {
+      "type": "object",
+      "properties": {
+        "title": {"type": "string", "example": "bug"},
+        "due_date": {"type": "string", "format": "date-time", "x-nullable": true},
+        "assignees": {"type": "array", "items": {"$ref": "#/definitions/User"}}
+      }
+    }
+  },
+  "responses": {
+    "Issue": {"description": "Issue", "schema": {"$ref": "#/definitions/Issue"}}
+  },
+  "securityDefinitions": {
+    "BasicAuth": {"type": "basic"},
+    "Token": {"type": "apiKey", "name": "token", "in": "query"}
+  },
+  "security": [{"BasicAuth": []}]
+}`
+
+func TestConvertSwagger2(t *testing.T) {
+	spec, err := ConvertSwagger2([]byte(swagger2Spec))
+	assert.NoError(t, err)
+
+	var v3 map[string]interface{}
+	assert.NoError(t, json.Unmarshal(spec, &v3))
+
+	var expected map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+  "openapi": "3.1.0",
+  "info": {"title": "Gitea API.", "version": "1.1.1"},
+  "servers": [{"url": "/api/v1"}],
+  "paths": {
+    "/repos/{owner}/{repo}/issues": {
+      "post": {
+        "operationId": "issueCreateIssue",
+        "parameters": [
+          {"name": "owner", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "labels", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}}
+        ],
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CreateIssueOption"}}}
+        },
+        "responses": {
+          "201": {"$ref": "#/components/responses/Issue"},
+          "204": {"description": "empty", "headers": {"X-Total-Count": {"schema": {"type": "integer"}}}}
+        }
+      }
+    },
+    "/repos/{owner}/{repo}/releases/{id}/assets": {
+      "post": {
+        "operationId": "repoCreateReleaseAttachment",
+        "requestBody": {
+          "required": true,
+          "content": {"multipart/form-data": {"schema": {
+            "type": "object",
+            "properties": {"attachment": {"type": "string", "format": "binary", "description": "attachment to upload"}},
+            "required": ["attachment"]
+          }}}
+        },
+        "responses": {}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "CreateIssueOption": {
+        "type": "object",
+        "properties": {
+          "title": {"type": "string", "examples": ["bug"]},
+          "due_date": {"type": ["string", "null"], "format": "date-time"},
+          "assignees": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}
+        }
+      }
+    },
+    "responses": {
+      "Issue": {"description": "Issue", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Issue"}}}}
+    },
+    "securitySchemes": {
+      "BasicAuth": {"type": "http", "scheme": "basic"},
+      "Token": {"type": "apiKey", "name": "token", "in": "query"}
+    }
+  },
+  "security": [{"BasicAuth": []}]
+}`), &expected))
+	assert.Equal(t, expected, v3)
+
+	_, err = ConvertSwagger2([]byte(`{"openapi": "3.0.0"}`))
+	assert.Error(t, err)
+}
@@ -30,6 +30,13 @@ type GitPushOptions map[string]string
 const (
 	GitPushOptionRepoPrivate  = "repo.private"
 	GitPushOptionRepoTemplate = "repo.template"
+
+	GitPushOptionPullCreate      = "pr.create"
+	GitPushOptionPullTitle       = "pr.title"
+	GitPushOptionPullDescription = "pr.description"
+	GitPushOptionPullBase        = "pr.base"
+	GitPushOptionPullTopic       = "pr.topic"
+	GitPushOptionPullLabels      = "pr.labels"
 )
 
 // Bool checks for a key in the map and parses as a boolean
@@ -70,6 +77,8 @@ type HookPostReceiveBranchResult struct {
 	Create  bool
 	Branch  string
 	URL     string
+	// Warning is shown to the pusher, e.g. when a pull request asked by the push options can't be created
+	Warning string
 }
 
 // HookPreReceive check whether the provided commits are allowed
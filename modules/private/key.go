@@ -8,14 +8,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 
 	"code.gitea.io/gitea/modules/setting"
 )
 
-// UpdatePublicKeyInRepo update public key and if necessary deploy key updates
-func UpdatePublicKeyInRepo(keyID, repoID int64) error {
+// UpdatePublicKeyInRepo update public key and if necessary deploy key updates, ip is the address of the client
+func UpdatePublicKeyInRepo(keyID, repoID int64, ip string) error {
 	// Ask for running deliver hook and test pull request tasks.
-	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/ssh/%d/update/%d", keyID, repoID)
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/ssh/%d/update/%d?ip=%s", keyID, repoID, url.QueryEscape(ip))
 	resp, err := newInternalRequest(reqURL, "POST").Response()
 	if err != nil {
 		return err
@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package recalculation
+
+import (
+	"context"
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var builtinOnce sync.Once
+
+func countRepositories() (int64, error) {
+	return models.CountRepositories(true), nil
+}
+
+// forRepository calls a function with the repository of an ID, the repositories deleted since their IDs
+// were listed are skipped
+func forRepository(id int64, fn func(repo *models.Repository) error) error {
+	repo, err := models.GetRepositoryByID(id)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return fn(repo)
+}
+
+func registerBuiltinRecalculators() {
+	builtinOnce.Do(registerBuiltins)
+}
+
+func registerBuiltins() {
+	Register(&Recalculator{
+		Name:     "repo_stats",
+		Count:    countRepositories,
+		IDsAfter: models.GetRepositoryIDsAfter,
+		Recalculate: func(_ context.Context, id int64) error {
+			return models.RecalculateRepositoryCounts(id)
+		},
+	})
+	Register(&Recalculator{
+		Name:     "language_stats",
+		Count:    countRepositories,
+		IDsAfter: models.GetRepositoryIDsAfter,
+		Recalculate: func(_ context.Context, id int64) error {
+			err := new(stats_indexer.DBIndexer).Index(id)
+			if models.IsErrRepoNotExist(err) {
+				return nil
+			}
+			return err
+		},
+	})
+	Register(&Recalculator{
+		Name:     "label_counts",
+		Count:    models.CountLabels,
+		IDsAfter: models.GetLabelIDsAfter,
+		Recalculate: func(_ context.Context, id int64) error {
+			return models.RecalculateLabelCounts(id)
+		},
+	})
+	Register(&Recalculator{
+		Name:     "search_index",
+		Count:    countRepositories,
+		IDsAfter: models.GetRepositoryIDsAfter,
+		Recalculate: func(_ context.Context, id int64) error {
+			return forRepository(id, func(repo *models.Repository) error {
+				issue_indexer.UpdateRepoIndexer(repo)
+				if setting.Indexer.RepoIndexerEnabled {
+					code_indexer.UpdateRepoIndexer(repo)
+				}
+				return nil
+			})
+		},
+	})
+}
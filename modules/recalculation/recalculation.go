@@ -0,0 +1,238 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package recalculation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrUnknownRecalculation is returned when no recalculator is registered with a name
+	ErrUnknownRecalculation = errors.New("unknown recalculation")
+	// ErrAlreadyRunning is returned when a recalculation is started while it's running
+	ErrAlreadyRunning = errors.New("recalculation is already running")
+	// ErrNotRunning is returned when a recalculation is cancelled while it isn't running
+	ErrNotRunning = errors.New("recalculation isn't running")
+)
+
+// Recalculator recalculates the items of a kind, e.g. the stats of the repositories, in the order of their IDs
+type Recalculator struct {
+	Name string
+	// Count returns the number of items
+	Count func() (int64, error)
+	// IDsAfter returns up to limit IDs of the items greater than afterID in increasing order
+	IDsAfter func(afterID int64, limit int) ([]int64, error)
+	// Recalculate recalculates an item, the errors are logged and the recalculation goes on
+	Recalculate func(ctx context.Context, id int64) error
+}
+
+type runningRecalculation struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+var (
+	lock          sync.Mutex
+	recalculators []*Recalculator
+	running       = make(map[string]*runningRecalculation)
+)
+
+// Register registers a recalculator, it must be called before Init
+func Register(r *Recalculator) {
+	lock.Lock()
+	defer lock.Unlock()
+	recalculators = append(recalculators, r)
+}
+
+// Recalculators returns the registered recalculators in the order of their registration
+func Recalculators() []*Recalculator {
+	lock.Lock()
+	defer lock.Unlock()
+	return append([]*Recalculator(nil), recalculators...)
+}
+
+func getRecalculator(name string) *Recalculator {
+	for _, r := range recalculators {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// IsRunning returns whether the recalculation of a name is running in this instance
+func IsRunning(name string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return running[name] != nil
+}
+
+// Init registers the built-in recalculators and resumes the recalculations which were interrupted by a shutdown
+func Init() error {
+	registerBuiltinRecalculators()
+
+	progresses, err := models.GetRunningRecalculations()
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	for _, progress := range progresses {
+		r := getRecalculator(progress.Name)
+		if r == nil {
+			log.Warn("Unable to resume the unknown recalculation %s", progress.Name)
+			continue
+		}
+		log.Info("Resuming the recalculation %s after ID %d", progress.Name, progress.LastID)
+		run(r, progress)
+	}
+	return nil
+}
+
+// Start starts the recalculation of a name from the first item, or resumes it after the last recalculated item
+func Start(name string, resume bool) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	r := getRecalculator(name)
+	if r == nil {
+		return ErrUnknownRecalculation
+	}
+	if running[name] != nil {
+		return ErrAlreadyRunning
+	}
+
+	progress, err := models.GetRecalculation(name)
+	if err != nil {
+		return err
+	}
+	if !resume || progress.Status == models.RecalculationFinished {
+		progress.LastID = 0
+		progress.Done = 0
+		progress.StartedUnix = timeutil.TimeStampNow()
+	}
+	if progress.Total, err = r.Count(); err != nil {
+		return err
+	}
+	progress.Status = models.RecalculationRunning
+	progress.Error = ""
+	progress.FinishedUnix = 0
+	if err := models.SaveRecalculation(progress); err != nil {
+		return err
+	}
+
+	run(r, progress)
+	return nil
+}
+
+// Cancel cancels the recalculation of a name, it can be resumed later
+func Cancel(name string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	rr := running[name]
+	if rr == nil {
+		return ErrNotRunning
+	}
+	rr.cancelled = true
+	rr.cancel()
+	return nil
+}
+
+// run runs a recalculation in the background, the lock must be held
+func run(r *Recalculator, progress *models.Recalculation) {
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	rr := &runningRecalculation{cancel: cancel}
+	running[r.Name] = rr
+
+	go func() {
+		pid := process.GetManager().Add(fmt.Sprintf("Recalculation: %s", r.Name), cancel)
+		defer func() {
+			process.GetManager().Remove(pid)
+			cancel()
+			lock.Lock()
+			delete(running, r.Name)
+			lock.Unlock()
+		}()
+
+		err := recalculate(ctx, r, progress)
+		switch {
+		case err == nil:
+			progress.Status = models.RecalculationFinished
+			progress.FinishedUnix = timeutil.TimeStampNow()
+		case ctx.Err() != nil:
+			lock.Lock()
+			cancelled := rr.cancelled
+			lock.Unlock()
+			if !cancelled {
+				// the recalculation keeps running and is resumed at the next start
+				log.Info("The recalculation %s is interrupted after ID %d", r.Name, progress.LastID)
+				break
+			}
+			progress.Status = models.RecalculationCancelled
+		default:
+			log.Error("The recalculation %s failed after ID %d: %v", r.Name, progress.LastID, err)
+			progress.Status = models.RecalculationFailed
+			progress.Error = err.Error()
+		}
+		if err := models.SaveRecalculation(progress); err != nil {
+			log.Error("SaveRecalculation: %v", err)
+		}
+	}()
+}
+
+// recalculate recalculates the items after the last recalculated one in batches, the progress is saved
+// after each batch
+func recalculate(ctx context.Context, r *Recalculator, progress *models.Recalculation) error {
+	limit := rate.Inf
+	if setting.Recalculation.ItemsPerSecond > 0 {
+		limit = rate.Limit(setting.Recalculation.ItemsPerSecond)
+	}
+	limiter := rate.NewLimiter(limit, int(math.Max(1, setting.Recalculation.ItemsPerSecond)))
+
+	for {
+		ids, err := r.IDsAfter(progress.LastID, setting.Recalculation.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := limiter.Wait(ctx); err != nil {
+				// the progress of the items recalculated so far is saved by the caller
+				return err
+			}
+			if err := r.Recalculate(ctx, id); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Error("Recalculation %s of ID %d: %v", r.Name, id, err)
+				progress.Error = fmt.Sprintf("ID %d: %v", id, err)
+			}
+			progress.LastID = id
+			progress.Done++
+		}
+
+		if err := models.SaveRecalculation(progress); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package recalculation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	models.MainTest(m, filepath.Join("..", ".."))
+}
+
+func waitUntilStopped(t *testing.T, name string) {
+	for i := 0; i < 100 && IsRunning(name); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.False(t, IsRunning(name))
+}
+
+func TestRecalculation(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	setting.Recalculation.BatchSize = 2
+	setting.Recalculation.ItemsPerSecond = 0
+
+	var mu sync.Mutex
+	var recalculated []int64
+	// the recalculation of the item 3 blocks until it's cancelled
+	block := true
+	Register(&Recalculator{
+		Name: "test",
+		Count: func() (int64, error) {
+			return 5, nil
+		},
+		IDsAfter: func(afterID int64, limit int) ([]int64, error) {
+			ids := make([]int64, 0, limit)
+			for id := afterID + 1; id <= 5 && len(ids) < limit; id++ {
+				ids = append(ids, id)
+			}
+			return ids, nil
+		},
+		Recalculate: func(ctx context.Context, id int64) error {
+			mu.Lock()
+			blocking := block && id == 3
+			mu.Unlock()
+			if blocking {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			if id == 4 {
+				return errors.New("broken item")
+			}
+			mu.Lock()
+			recalculated = append(recalculated, id)
+			mu.Unlock()
+			return nil
+		},
+	})
+
+	assert.Equal(t, ErrUnknownRecalculation, Start("unknown", false))
+	assert.Equal(t, ErrNotRunning, Cancel("test"))
+
+	assert.NoError(t, Start("test", false))
+	assert.Equal(t, ErrAlreadyRunning, Start("test", false))
+	for i := 0; i < 100; i++ {
+		if progress, _ := models.GetRecalculation("test"); progress.LastID == 2 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.NoError(t, Cancel("test"))
+	waitUntilStopped(t, "test")
+
+	progress, err := models.GetRecalculation("test")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecalculationCancelled, progress.Status)
+	assert.EqualValues(t, 2, progress.LastID)
+	assert.EqualValues(t, 2, progress.Done)
+	assert.EqualValues(t, 5, progress.Total)
+
+	// resume after the last recalculated item
+	mu.Lock()
+	block = false
+	mu.Unlock()
+	assert.NoError(t, Start("test", true))
+	waitUntilStopped(t, "test")
+
+	progress, err = models.GetRecalculation("test")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecalculationFinished, progress.Status)
+	assert.EqualValues(t, 5, progress.LastID)
+	assert.EqualValues(t, 5, progress.Done)
+	assert.Equal(t, "ID 4: broken item", progress.Error)
+	assert.NotZero(t, progress.FinishedUnix)
+	mu.Lock()
+	assert.Equal(t, []int64{1, 2, 3, 5}, recalculated)
+	mu.Unlock()
+
+	// start from the beginning
+	assert.NoError(t, Start("test", false))
+	waitUntilStopped(t, "test")
+	progress, err = models.GetRecalculation("test")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecalculationFinished, progress.Status)
+	assert.EqualValues(t, 5, progress.Done)
+}
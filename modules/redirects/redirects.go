@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redirects
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Path is the path of the redirects file in the default branch of a repository
+const Path = ".gitea/redirects.yml"
+
+// Config represents a redirects file, it configures how missing refs and paths of a repository are handled
+type Config struct {
+	Refs  []*Rule `yaml:"refs"`
+	Paths []*Rule `yaml:"paths"`
+}
+
+// Rule represents a missing ref or path, which is either redirected or explained by a message
+type Rule struct {
+	// Name is the name of a missing ref
+	Name string `yaml:"name"`
+	// Path is the path of a missing file, a path ending with a slash matches a directory and its content
+	Path    string `yaml:"path"`
+	To      string `yaml:"to"`
+	Message string `yaml:"message"`
+}
+
+// Parse parses the content of a redirects file, the rules without a name or a path are ignored
+func Parse(content []byte) (*Config, error) {
+	var raw Config
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	for _, rule := range raw.Refs {
+		if rule != nil && len(rule.Name) > 0 {
+			config.Refs = append(config.Refs, rule)
+		}
+	}
+	for _, rule := range raw.Paths {
+		if rule == nil {
+			continue
+		}
+		rule.Path = strings.TrimPrefix(rule.Path, "/")
+		rule.To = strings.TrimPrefix(rule.To, "/")
+		if len(rule.Path) > 0 {
+			config.Paths = append(config.Paths, rule)
+		}
+	}
+	return config, nil
+}
+
+// MatchRef returns the rule of a missing ref and the ref to redirect to, refPath is the
+// requested ref possibly followed by a path, which is kept in the redirection
+func (c *Config) MatchRef(refPath string) (*Rule, string) {
+	for _, rule := range c.Refs {
+		if refPath == rule.Name {
+			return rule, rule.To
+		}
+		if rest := strings.TrimPrefix(refPath, rule.Name+"/"); rest != refPath {
+			if len(rule.To) == 0 {
+				return rule, ""
+			}
+			return rule, rule.To + "/" + rest
+		}
+	}
+	return nil, ""
+}
+
+// MatchPath returns the rule of a missing path and the path to redirect to, the rules are
+// tried in order so a file can be matched before its directory
+func (c *Config) MatchPath(treePath string) (*Rule, string) {
+	treePath = strings.TrimPrefix(treePath, "/")
+	for _, rule := range c.Paths {
+		if !strings.HasSuffix(rule.Path, "/") {
+			if treePath == rule.Path {
+				return rule, rule.To
+			}
+			continue
+		}
+		if treePath+"/" == rule.Path {
+			return rule, strings.TrimSuffix(rule.To, "/")
+		}
+		if rest := strings.TrimPrefix(treePath, rule.Path); rest != treePath {
+			if len(rule.To) == 0 {
+				return rule, ""
+			}
+			return rule, strings.TrimSuffix(rule.To, "/") + "/" + rest
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redirects
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	config, err := Parse([]byte(`
+refs:
+  - name: master
+    to: main
+    message: The master branch was renamed to main.
+  - name: release/v1
+    message: The v1 releases are not maintained anymore.
+  - to: ignored
+paths:
+  - path: /docs/install.md
+    to: /docs/setup/install.md
+  - path: docs/old/
+    to: docs/new/
+  - path: docs/removed/
+    message: The removed documentation is not available anymore.
+`))
+	assert.NoError(t, err)
+	assert.Len(t, config.Refs, 2)
+	assert.Len(t, config.Paths, 3)
+
+	rule, to := config.MatchRef("master")
+	assert.Equal(t, "The master branch was renamed to main.", rule.Message)
+	assert.Equal(t, "main", to)
+	_, to = config.MatchRef("master/docs/install.md")
+	assert.Equal(t, "main/docs/install.md", to)
+	rule, to = config.MatchRef("release/v1/README.md")
+	assert.Equal(t, "The v1 releases are not maintained anymore.", rule.Message)
+	assert.Empty(t, to)
+	rule, _ = config.MatchRef("mastery")
+	assert.Nil(t, rule)
+
+	_, to = config.MatchPath("docs/install.md")
+	assert.Equal(t, "docs/setup/install.md", to)
+	_, to = config.MatchPath("docs/old")
+	assert.Equal(t, "docs/new", to)
+	_, to = config.MatchPath("docs/old/a/b.md")
+	assert.Equal(t, "docs/new/a/b.md", to)
+	rule, to = config.MatchPath("docs/removed/a.md")
+	assert.Equal(t, "The removed documentation is not available anymore.", rule.Message)
+	assert.Empty(t, to)
+	rule, _ = config.MatchPath("docs/oldest.md")
+	assert.Nil(t, rule)
+
+	_, err = Parse([]byte("refs: {"))
+	assert.Error(t, err)
+}
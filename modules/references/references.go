@@ -39,9 +39,13 @@ var (
 	spaceTrimmedPattern = regexp.MustCompile(`(?:.*[0-9a-zA-Z-_])\s`)
 	// timeLogPattern matches string for time tracking
 	timeLogPattern = regexp.MustCompile(`(?:\s|^|\(|\[)(@([0-9]+([\.,][0-9]+)?(w|d|m|h))+)(?:\s|$|\)|\]|[:;,.?!]\s|[:;,.?!]$)`)
+	// keywordPattern matches the keywords closing or reopening issues, they're made of Unicode letters (a-z, á, à, ä, )
+	keywordPattern = regexp.MustCompile(`^[\pL]+$`)
 
 	issueCloseKeywordsPat, issueReopenKeywordsPat *regexp.Regexp
 	issueKeywordsOnce                             sync.Once
+	// customKeywords caches the keywords extended by repositories
+	customKeywords sync.Map
 
 	giteaHostInit         sync.Once
 	giteaHost             string
@@ -62,6 +66,19 @@ const (
 	XRefActionNeutered // 3
 )
 
+// String returns the name of the action as used by the API
+func (a XRefAction) String() string {
+	switch a {
+	case XRefActionCloses:
+		return "closes"
+	case XRefActionReopens:
+		return "reopens"
+	case XRefActionNeutered:
+		return "neutered"
+	}
+	return "none"
+}
+
 // IssueReference contains an unverified cross-reference to a local issue or pull request
 type IssueReference struct {
 	Index   int64
@@ -128,11 +145,9 @@ func makeKeywordsPat(words []string) *regexp.Regexp {
 
 func parseKeywords(words []string) []string {
 	acceptedWords := make([]string, 0, 5)
-	wordPat := regexp.MustCompile(`^[\pL]+$`)
 	for _, word := range words {
 		word = strings.ToLower(strings.TrimSpace(word))
-		// Accept Unicode letter class runes (a-z, á, à, ä, )
-		if wordPat.MatchString(word) {
+		if IsValidKeyword(word) {
 			acceptedWords = append(acceptedWords, word)
 		} else {
 			log.Info("Invalid keyword: %s", word)
@@ -141,6 +156,11 @@ func parseKeywords(words []string) []string {
 	return acceptedWords
 }
 
+// IsValidKeyword returns whether a word can be used as a keyword closing or reopening issues
+func IsValidKeyword(word string) bool {
+	return keywordPattern.MatchString(word)
+}
+
 func newKeywords() {
 	issueKeywordsOnce.Do(func() {
 		// Delay initialization until after the settings module is initialized
@@ -153,6 +173,88 @@ func doNewKeywords(close []string, reopen []string) {
 	issueReopenKeywordsPat = makeKeywordsPat(reopen)
 }
 
+// KeywordSet are the keywords closing and reopening issues in a language
+type KeywordSet struct {
+	// Name is the native name of the language
+	Name   string
+	Close  []string
+	Reopen []string
+}
+
+// KeywordLanguages are the built-in keyword sets which can be added to the keywords of the instance
+var KeywordLanguages = map[string]KeywordSet{
+	"de": {
+		Name:   "Deutsch",
+		Close:  []string{"schließt", "schließe", "geschlossen", "behebt", "behebe", "behoben", "löst", "gelöst"},
+		Reopen: []string{"wiedereröffnet", "wiedereröffne"},
+	},
+	"en": {
+		Name:   "English",
+		Close:  []string{"close", "closes", "closed", "fix", "fixes", "fixed", "resolve", "resolves", "resolved"},
+		Reopen: []string{"reopen", "reopens", "reopened"},
+	},
+	"es": {
+		Name:   "Español",
+		Close:  []string{"cierra", "cerrado", "corrige", "corregido", "resuelve", "resuelto"},
+		Reopen: []string{"reabre", "reabierto"},
+	},
+	"fr": {
+		Name:   "Français",
+		Close:  []string{"ferme", "fermé", "corrige", "corrigé", "résout", "résolu"},
+		Reopen: []string{"rouvre", "rouvert"},
+	},
+	"it": {
+		Name:   "Italiano",
+		Close:  []string{"chiude", "chiuso", "corregge", "corretto", "risolve", "risolto"},
+		Reopen: []string{"riapre", "riaperto"},
+	},
+	"nl": {
+		Name:   "Nederlands",
+		Close:  []string{"sluit", "gesloten", "repareert", "gerepareerd", "opgelost"},
+		Reopen: []string{"heropent", "heropend"},
+	},
+	"pt": {
+		Name:   "Português",
+		Close:  []string{"fecha", "fechado", "corrige", "corrigido", "resolve", "resolvido"},
+		Reopen: []string{"reabre", "reaberto"},
+	},
+}
+
+// Keywords are the keywords closing and reopening the referenced issues, nil is the keywords of the instance
+type Keywords struct {
+	closePat, reopenPat *regexp.Regexp
+}
+
+// NewKeywords returns the keywords of the instance extended by the keyword sets of the languages and the
+// custom keywords, or nil when they aren't extended
+func NewKeywords(languages, close, reopen []string) *Keywords {
+	if len(languages) == 0 && len(close) == 0 && len(reopen) == 0 {
+		return nil
+	}
+	key := strings.Join(languages, ",") + "|" + strings.Join(close, ",") + "|" + strings.Join(reopen, ",")
+	if keywords, ok := customKeywords.Load(key); ok {
+		return keywords.(*Keywords)
+	}
+
+	closeWords := append(append([]string{}, setting.Repository.PullRequest.CloseKeywords...), close...)
+	reopenWords := append(append([]string{}, setting.Repository.PullRequest.ReopenKeywords...), reopen...)
+	for _, language := range languages {
+		set, ok := KeywordLanguages[language]
+		if !ok {
+			log.Info("Unknown keyword language: %s", language)
+			continue
+		}
+		closeWords = append(closeWords, set.Close...)
+		reopenWords = append(reopenWords, set.Reopen...)
+	}
+	keywords := &Keywords{
+		closePat:  makeKeywordsPat(closeWords),
+		reopenPat: makeKeywordsPat(reopenWords),
+	}
+	customKeywords.Store(key, keywords)
+	return keywords
+}
+
 // getGiteaHostName returns a normalized string with the local host name, with no scheme or port information
 func getGiteaHostName() string {
 	giteaHostInit.Do(func() {
@@ -227,12 +329,17 @@ func FindFirstMentionBytes(content []byte) (bool, RefSpan) {
 // FindAllIssueReferencesMarkdown strips content from markdown markup
 // and returns a list of unvalidated references found in it.
 func FindAllIssueReferencesMarkdown(content string) []IssueReference {
-	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content))
+	return FindAllIssueReferencesMarkdownWithKeywords(content, nil)
+}
+
+// FindAllIssueReferencesMarkdownWithKeywords is FindAllIssueReferencesMarkdown with the keywords of a repository
+func FindAllIssueReferencesMarkdownWithKeywords(content string, keywords *Keywords) []IssueReference {
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content, keywords))
 }
 
-func findAllIssueReferencesMarkdown(content string) []*rawReference {
+func findAllIssueReferencesMarkdown(content string, keywords *Keywords) []*rawReference {
 	bcontent, links := mdstripper.StripMarkdownBytes([]byte(content))
-	return findAllIssueReferencesBytes(bcontent, links)
+	return findAllIssueReferencesBytes(bcontent, links, keywords)
 }
 
 func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byte) {
@@ -303,6 +410,11 @@ func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byt
 
 // FindAllIssueReferences returns a list of unvalidated references found in a string.
 func FindAllIssueReferences(content string) []IssueReference {
+	return FindAllIssueReferencesWithKeywords(content, nil)
+}
+
+// FindAllIssueReferencesWithKeywords is FindAllIssueReferences with the keywords of a repository
+func FindAllIssueReferencesWithKeywords(content string, keywords *Keywords) []IssueReference {
 	// Need to convert fully qualified html references to local system to #/! short codes
 	contentBytes := []byte(content)
 	if re := getGiteaIssuePullPattern(); re != nil {
@@ -310,7 +422,7 @@ func FindAllIssueReferences(content string) []IssueReference {
 	} else {
 		log.Debug("No GiteaIssuePullPattern pattern")
 	}
-	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}))
+	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}, keywords))
 }
 
 // FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string.
@@ -321,7 +433,7 @@ func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *Rende
 			return false, nil
 		}
 	}
-	r := getCrossReference([]byte(content), match[2], match[3], false, prOnly)
+	r := getCrossReference([]byte(content), match[2], match[3], false, prOnly, nil)
 	if r == nil {
 		return false, nil
 	}
@@ -344,7 +456,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 		return false, nil
 	}
 
-	action, location := findActionKeywords([]byte(content), match[2])
+	action, location := findActionKeywords([]byte(content), match[2], nil)
 
 	return true, &RenderizableReference{
 		Issue:          string(content[match[2]:match[3]]),
@@ -356,7 +468,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 }
 
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
-func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
+func findAllIssueReferencesBytes(content []byte, links []string, keywords *Keywords) []*rawReference {
 
 	ret := make([]*rawReference, 0, 10)
 	pos := 0
@@ -370,7 +482,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -388,7 +500,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -422,7 +534,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 			}
 			// Note: closing/reopening keywords not supported with URLs
 			bytes := []byte(parts[1] + "/" + parts[2] + sep + parts[4])
-			if ref := getCrossReference(bytes, 0, len(bytes), true, false); ref != nil {
+			if ref := getCrossReference(bytes, 0, len(bytes), true, false, keywords); ref != nil {
 				ref.refLocation = nil
 				ret = append(ret, ref)
 			}
@@ -464,7 +576,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 	return ret
 }
 
-func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool) *rawReference {
+func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool, keywords *Keywords) *rawReference {
 	refid := string(content[start:end])
 	sep := strings.IndexAny(refid, "#!")
 	if sep < 0 {
@@ -485,7 +597,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 			// Markdown links must specify owner/repo
 			return nil
 		}
-		action, location := findActionKeywords(content, start)
+		action, location := findActionKeywords(content, start, keywords)
 		return &rawReference{
 			index:          index,
 			action:         action,
@@ -503,7 +615,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	if !validNamePattern.MatchString(owner) || !validNamePattern.MatchString(name) {
 		return nil
 	}
-	action, location := findActionKeywords(content, start)
+	action, location := findActionKeywords(content, start, keywords)
 	return &rawReference{
 		index:          index,
 		owner:          owner,
@@ -516,17 +628,20 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	}
 }
 
-func findActionKeywords(content []byte, start int) (XRefAction, *RefSpan) {
-	newKeywords()
+func findActionKeywords(content []byte, start int, keywords *Keywords) (XRefAction, *RefSpan) {
+	if keywords == nil {
+		newKeywords()
+		keywords = &Keywords{closePat: issueCloseKeywordsPat, reopenPat: issueReopenKeywordsPat}
+	}
 	var m []int
-	if issueCloseKeywordsPat != nil {
-		m = issueCloseKeywordsPat.FindSubmatchIndex(content[:start])
+	if keywords.closePat != nil {
+		m = keywords.closePat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionCloses, &RefSpan{Start: m[2], End: m[3]}
 		}
 	}
-	if issueReopenKeywordsPat != nil {
-		m = issueReopenKeywordsPat.FindSubmatchIndex(content[:start])
+	if keywords.reopenPat != nil {
+		m = keywords.reopenPat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionReopens, &RefSpan{Start: m[2], End: m[3]}
 		}
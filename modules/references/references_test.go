@@ -280,7 +280,7 @@ func testFixtures(t *testing.T, fixtures []testFixture, context string) {
 		expref := rawToIssueReferenceList(expraw)
 		refs := FindAllIssueReferencesMarkdown(fixture.input)
 		assert.EqualValues(t, expref, refs, "[%s] Failed to parse: {%s}", context, fixture.input)
-		rawrefs := findAllIssueReferencesMarkdown(fixture.input)
+		rawrefs := findAllIssueReferencesMarkdown(fixture.input, nil)
 		assert.EqualValues(t, expraw, rawrefs, "[%s] Failed to parse: {%s}", context, fixture.input)
 	}
 
@@ -452,6 +452,28 @@ func TestCustomizeCloseKeywords(t *testing.T) {
 	doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
 }
 
+func TestNewKeywords(t *testing.T) {
+	assert.Nil(t, NewKeywords(nil, nil, nil))
+
+	keywords := NewKeywords([]string{"fr", "unknown"}, []string{"terminates"}, []string{"revives"})
+	assert.True(t, keywords == NewKeywords([]string{"fr", "unknown"}, []string{"terminates"}, []string{"revives"}))
+
+	actions := func(refs []IssueReference) []XRefAction {
+		result := make([]XRefAction, len(refs))
+		for i, ref := range refs {
+			result[i] = ref.Action
+		}
+		return result
+	}
+	content := "ferme #1, terminates #2, fixes #3, rouvre #4, revives user/repo#5"
+	assert.Equal(t, []XRefAction{XRefActionCloses, XRefActionCloses, XRefActionCloses, XRefActionReopens, XRefActionReopens},
+		actions(FindAllIssueReferencesWithKeywords(content, keywords)))
+	assert.Equal(t, []XRefAction{XRefActionNone, XRefActionNone, XRefActionCloses, XRefActionNone, XRefActionNone},
+		actions(FindAllIssueReferences(content)))
+	assert.Equal(t, []XRefAction{XRefActionCloses, XRefActionCloses, XRefActionCloses, XRefActionReopens, XRefActionReopens},
+		actions(FindAllIssueReferencesMarkdownWithKeywords(content, keywords)))
+}
+
 func TestParseCloseKeywords(t *testing.T) {
 	// Test parsing of CloseKeywords and ReopenKeywords
 	assert.Len(t, parseKeywords([]string{""}), 0)
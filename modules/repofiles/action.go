@@ -124,6 +124,8 @@ func changeIssueStatus(repo *models.Repository, issue *models.Issue, doer *model
 
 // UpdateIssuesCommit checks if issues are manipulated by commit message.
 func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*repository.PushCommit, branchName string) error {
+	keywords := repo.IssuesConfig().Keywords()
+
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -137,7 +139,7 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 		var refRepo *models.Repository
 		var refIssue *models.Issue
 		var err error
-		for _, ref := range references.FindAllIssueReferences(c.Message) {
+		for _, ref := range references.FindAllIssueReferencesWithKeywords(c.Message, keywords) {
 
 			// issue is from another repo
 			if len(ref.Owner) > 0 && len(ref.Name) > 0 {
@@ -190,6 +192,11 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 				continue
 			}
 
+			// The repositories can prevent the others from closing or reopening their issues
+			if refRepo.ID != repo.ID && refRepo.IssuesConfig().DisableCrossRepoClose {
+				continue
+			}
+
 			if !repo.CloseIssuesViaCommitInAnyBranch {
 				// If the issue was specified to be in a particular branch, don't allow commits in other branches to close it
 				if refIssue.Ref != "" {
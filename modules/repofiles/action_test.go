@@ -295,3 +295,76 @@ func TestUpdateIssuesCommit_AnotherRepoNoPermission(t *testing.T) {
 	models.AssertNotExistsBean(t, issueBean, "is_closed=1")
 	models.CheckConsistencyFor(t, &models.Action{})
 }
+
+func TestUpdateIssuesCommit_Keywords(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, models.UpdateRepositoryUnits(repo, []models.RepoUnit{{
+		RepoID: repo.ID,
+		Type:   models.UnitTypeIssues,
+		Config: &models.IssuesConfig{CloseKeywords: []string{"implements"}, KeywordLanguages: []string{"fr"}},
+	}}, nil))
+	repo.Units = nil
+
+	// the keywords of the repository close and reopen the issues too
+	pushCommits := []*repository.PushCommit{
+		{
+			Sha1:           "abcdef1",
+			CommitterEmail: "user2@example.com",
+			CommitterName:  "User Two",
+			AuthorEmail:    "user2@example.com",
+			AuthorName:     "User Two",
+			Message:        "implements #1",
+		},
+		{
+			Sha1:           "abcdef2",
+			CommitterEmail: "user2@example.com",
+			CommitterName:  "User Two",
+			AuthorEmail:    "user2@example.com",
+			AuthorName:     "User Two",
+			Message:        "rouvre #4",
+		},
+	}
+
+	models.AssertNotExistsBean(t, &models.Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, repo.DefaultBranch))
+	models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+	models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 4}, "is_closed=0")
+	models.CheckConsistencyFor(t, &models.Action{})
+}
+
+func TestUpdateIssuesCommit_AnotherRepoCloseDisabled(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NoError(t, models.UpdateRepositoryUnits(&models.Repository{ID: 1}, []models.RepoUnit{{
+		RepoID: 1,
+		Type:   models.UnitTypeIssues,
+		Config: &models.IssuesConfig{DisableCrossRepoClose: true},
+	}}, nil))
+
+	// Test that a push to another repo only references the issues of a repo preventing it from closing them
+	pushCommits := []*repository.PushCommit{
+		{
+			Sha1:           "abcdef1",
+			CommitterEmail: "user2@example.com",
+			CommitterName:  "User Two",
+			AuthorEmail:    "user2@example.com",
+			AuthorName:     "User Two",
+			Message:        "close user2/repo1#1",
+		},
+	}
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 2}).(*models.Repository)
+	commentBean := &models.Comment{
+		Type:      models.CommentTypeCommitRef,
+		CommitSHA: "abcdef1",
+		PosterID:  user.ID,
+		IssueID:   1,
+	}
+
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, repo.DefaultBranch))
+	models.AssertExistsAndLoadBean(t, commentBean)
+	models.AssertNotExistsBean(t, &models.Issue{ID: 1}, "is_closed=1")
+	models.CheckConsistencyFor(t, &models.Action{})
+}
@@ -0,0 +1,477 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+
+	stdcharset "golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+// The operations of the changes of files of ChangeRepoFiles
+const (
+	FileOperationCreate = "create"
+	FileOperationUpdate = "update"
+	FileOperationDelete = "delete"
+)
+
+// ChangeRepoFile holds a change of a file of ChangeRepoFiles
+type ChangeRepoFile struct {
+	Operation string
+	TreePath  string
+	// FromTreePath is the path of the file moved to TreePath by an update
+	FromTreePath string
+	Content      string
+	// SHA is the SHA of the file updated or deleted, the file mustn't have been changed since
+	// LastCommitID if it isn't given
+	SHA string
+}
+
+// ChangeRepoFilesOptions holds the options of the changes of files committed by ChangeRepoFiles
+type ChangeRepoFilesOptions struct {
+	LastCommitID string
+	OldBranch    string
+	NewBranch    string
+	Message      string
+	Files        []*ChangeRepoFile
+	Author       *IdentityOptions
+	Committer    *IdentityOptions
+	Dates        *CommitDateOptions
+}
+
+// checkCanCommitFiles checks that the doer can commit changes of files at the given paths to the
+// new branch, or to the old branch if it's the same
+func checkCanCommitFiles(repo *models.Repository, doer *models.User, oldBranch, newBranch string, treePaths []string) error {
+	// oldBranch must exist for this operation
+	if _, err := repo_module.GetBranch(repo, oldBranch); err != nil {
+		return err
+	}
+
+	if newBranch != oldBranch {
+		existingBranch, err := repo_module.GetBranch(repo, newBranch)
+		if existingBranch != nil {
+			return models.ErrBranchAlreadyExists{
+				BranchName: newBranch,
+			}
+		}
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return err
+		}
+	} else {
+		protectedBranch, err := repo.GetBranchProtection(oldBranch)
+		if err != nil {
+			return err
+		}
+		if protectedBranch != nil {
+			if !protectedBranch.CanUserPush(doer.ID) {
+				return models.ErrUserCannotCommit{
+					UserName: doer.LowerName,
+				}
+			}
+			if protectedBranch.RequireSignedCommits {
+				if _, _, _, err := repo.SignCRUDAction(doer, repo.RepoPath(), oldBranch); err != nil {
+					if !models.IsErrWontSign(err) {
+						return err
+					}
+					return models.ErrUserCannotCommit{
+						UserName: doer.LowerName,
+					}
+				}
+			}
+			patterns := protectedBranch.GetProtectedFilePatterns()
+			for _, treePath := range treePaths {
+				for _, pat := range patterns {
+					if pat.Match(strings.ToLower(treePath)) {
+						return models.ErrFilePathProtected{
+							Path: treePath,
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Check the files aren't locked by another user, will return nil if lock setting not enabled
+	for _, treePath := range treePaths {
+		lfsLock, err := repo.GetTreePathLock(treePath)
+		if err != nil {
+			return err
+		}
+		if lfsLock != nil && lfsLock.OwnerID != doer.ID {
+			return models.ErrLFSFileLocked{RepoID: repo.ID, Path: treePath, UserName: lfsLock.Owner.Name}
+		}
+	}
+	return nil
+}
+
+// checkNewTreePath checks that no parts of the path of a file to be created are existing files or
+// links, and that the file doesn't exist
+func checkNewTreePath(commit *git.Commit, treePath string) error {
+	treePathParts := strings.Split(treePath, "/")
+	subTreePath := ""
+	for index, part := range treePathParts {
+		subTreePath = path.Join(subTreePath, part)
+		entry, err := commit.GetTreeEntryByPath(subTreePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				// Means there is no item with that name, so we're good
+				return nil
+			}
+			return err
+		}
+		if index < len(treePathParts)-1 {
+			if !entry.IsDir() {
+				return models.ErrFilePathInvalid{
+					Message: fmt.Sprintf("a file exists where you’re trying to create a subdirectory [path: %s]", subTreePath),
+					Path:    subTreePath,
+					Name:    part,
+					Type:    git.EntryModeBlob,
+				}
+			}
+		} else if entry.IsLink() {
+			return models.ErrFilePathInvalid{
+				Message: fmt.Sprintf("a symbolic link exists where you’re trying to create a subdirectory [path: %s]", subTreePath),
+				Path:    subTreePath,
+				Name:    part,
+				Type:    git.EntryModeSymlink,
+			}
+		} else if entry.IsDir() {
+			return models.ErrFilePathInvalid{
+				Message: fmt.Sprintf("a directory exists where you’re trying to create a file [path: %s]", subTreePath),
+				Path:    subTreePath,
+				Name:    part,
+				Type:    git.EntryModeTree,
+			}
+		} else {
+			return models.ErrRepoFileAlreadyExists{
+				Path: treePath,
+			}
+		}
+	}
+	return nil
+}
+
+// checkFileUnchanged checks that the existing file at the path hasn't been changed since the
+// SHA or the last commit ID given by the change
+func checkFileUnchanged(commit *git.Commit, entry *git.TreeEntry, treePath, sha string, opts *ChangeRepoFilesOptions) error {
+	if sha != "" {
+		if sha != entry.ID.String() {
+			return models.ErrSHADoesNotMatch{
+				Path:       treePath,
+				GivenSHA:   sha,
+				CurrentSHA: entry.ID.String(),
+			}
+		}
+		return nil
+	}
+	// The head of the branch may have moved since the last commit ID as long as this file
+	// wasn't changed, unless we are creating a new branch
+	if commit.ID.String() != opts.LastCommitID && opts.OldBranch == opts.NewBranch {
+		if changed, err := commit.FileChangedSinceCommit(treePath, opts.LastCommitID); err != nil {
+			return err
+		} else if changed {
+			return models.ErrCommitIDDoesNotMatch{
+				GivenCommitID:   opts.LastCommitID,
+				CurrentCommitID: commit.ID.String(),
+			}
+		}
+	}
+	return nil
+}
+
+// encodeLikeEntry encodes the content of a file in the encoding of the existing file it replaces
+func encodeLikeEntry(repo *models.Repository, entry *git.TreeEntry, treePath, content string) string {
+	encoding, bom := detectEncodingAndBOM(entry, repo)
+	if bom {
+		content = string(charset.UTF8BOM) + content
+	}
+	if encoding != "UTF-8" {
+		charsetEncoding, _ := stdcharset.Lookup(encoding)
+		if charsetEncoding == nil {
+			log.Error("Unknown encoding: %s", encoding)
+			return content
+		}
+		result, _, err := transform.String(charsetEncoding.NewEncoder(), content)
+		if err != nil {
+			// Look if we can't encode back in to the original we should just stick with utf-8
+			log.Error("Error re-encoding %s as %s - will stay as UTF-8: %v", treePath, encoding, err)
+			return content
+		}
+		return result
+	}
+	return content
+}
+
+// ChangeRepoFiles creates, updates and deletes files of the given repository in a single commit
+func ChangeRepoFiles(repo *models.Repository, doer *models.User, opts *ChangeRepoFilesOptions) (*api.FilesResponse, error) {
+	// If no branch name is set, assume default branch
+	if opts.OldBranch == "" {
+		opts.OldBranch = repo.DefaultBranch
+	}
+	if opts.NewBranch == "" {
+		opts.NewBranch = opts.OldBranch
+	}
+	if len(opts.Files) == 0 {
+		return nil, fmt.Errorf("ChangeRepoFiles: no files to change")
+	}
+
+	// Clean up the paths, a path can only be changed once
+	changedPaths := make(map[string]bool, len(opts.Files))
+	treePaths := make([]string, 0, len(opts.Files))
+	for _, file := range opts.Files {
+		switch file.Operation {
+		case FileOperationCreate, FileOperationUpdate, FileOperationDelete:
+		default:
+			return nil, fmt.Errorf("ChangeRepoFiles: unknown operation %q of %s", file.Operation, file.TreePath)
+		}
+
+		// Check that the path given in file.TreePath is valid (not a git path)
+		treePath := CleanUploadFileName(file.TreePath)
+		if treePath == "" {
+			return nil, models.ErrFilenameInvalid{
+				Path: file.TreePath,
+			}
+		}
+		file.TreePath = treePath
+		if file.Operation == FileOperationUpdate && file.FromTreePath != "" {
+			fromTreePath := CleanUploadFileName(file.FromTreePath)
+			if fromTreePath == "" {
+				return nil, models.ErrFilenameInvalid{
+					Path: file.FromTreePath,
+				}
+			}
+			file.FromTreePath = fromTreePath
+		} else {
+			file.FromTreePath = treePath
+		}
+
+		for _, p := range []string{file.FromTreePath, file.TreePath} {
+			if changedPaths[p] {
+				return nil, models.ErrFilePathInvalid{
+					Message: fmt.Sprintf("the file is changed more than once [path: %s]", p),
+					Path:    p,
+					Name:    path.Base(p),
+				}
+			}
+			changedPaths[p] = true
+			treePaths = append(treePaths, p)
+			if file.FromTreePath == file.TreePath {
+				break
+			}
+		}
+	}
+
+	if err := checkCanCommitFiles(repo, doer, opts.OldBranch, opts.NewBranch, treePaths); err != nil {
+		return nil, err
+	}
+
+	message := strings.TrimSpace(opts.Message)
+
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	// Get the commit of the original branch
+	commit, err := t.GetBranchCommit(opts.OldBranch)
+	if err != nil {
+		return nil, err // Couldn't get a commit for the branch
+	}
+
+	// Assigned LastCommitID in opts if it hasn't been set
+	if opts.LastCommitID == "" {
+		opts.LastCommitID = commit.ID.String()
+	} else {
+		lastCommitID, err := t.gitRepo.ConvertToSHA1(opts.LastCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("ChangeRepoFiles: Invalid last commit ID: %v", err)
+		}
+		opts.LastCommitID = lastCommitID.String()
+	}
+
+	// The files to be created or updated may be stored in LFS
+	var filename2attribute2info map[string]map[string]string
+	if setting.LFS.StartServer {
+		names := make([]string, 0, len(opts.Files))
+		for _, file := range opts.Files {
+			if file.Operation != FileOperationDelete {
+				names = append(names, file.TreePath)
+			}
+		}
+		if len(names) > 0 {
+			if filename2attribute2info, err = t.CheckAttribute("filter", names...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	lfsMetaObjects := make([]*models.LFSMetaObject, 0, len(opts.Files))
+	lfsContents := make([]string, 0, len(opts.Files))
+	for _, file := range opts.Files {
+		executable := false
+		content := file.Content
+		if file.Operation == FileOperationCreate {
+			if err := checkNewTreePath(commit, file.TreePath); err != nil {
+				return nil, err
+			}
+		} else {
+			fromEntry, err := commit.GetTreeEntryByPath(file.FromTreePath)
+			if err != nil {
+				if git.IsErrNotExist(err) {
+					return nil, models.ErrRepoFileDoesNotExist{
+						Path: file.FromTreePath,
+					}
+				}
+				return nil, err
+			}
+			if fromEntry.IsDir() {
+				return nil, models.ErrFilePathInvalid{
+					Message: fmt.Sprintf("a directory exists where you’re trying to change a file [path: %s]", file.FromTreePath),
+					Path:    file.FromTreePath,
+					Name:    fromEntry.Name(),
+					Type:    git.EntryModeTree,
+				}
+			}
+			if err := checkFileUnchanged(commit, fromEntry, file.FromTreePath, file.SHA, opts); err != nil {
+				return nil, err
+			}
+
+			if file.Operation == FileOperationDelete {
+				if err := t.RemoveFilesFromIndex(file.TreePath); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if file.FromTreePath != file.TreePath {
+				if err := checkNewTreePath(commit, file.TreePath); err != nil {
+					return nil, err
+				}
+				if err := t.RemoveFilesFromIndex(file.FromTreePath); err != nil {
+					return nil, err
+				}
+			}
+			content = encodeLikeEntry(repo, fromEntry, file.TreePath, content)
+			executable = fromEntry.IsExecutable()
+		}
+
+		if filename2attribute2info[file.TreePath] != nil && filename2attribute2info[file.TreePath]["filter"] == "lfs" {
+			// OK so we are supposed to LFS this data!
+			oid, err := models.GenerateLFSOid(strings.NewReader(content))
+			if err != nil {
+				return nil, err
+			}
+			lfsMetaObject := &models.LFSMetaObject{Oid: oid, Size: int64(len(content)), RepositoryID: repo.ID}
+			lfsMetaObjects = append(lfsMetaObjects, lfsMetaObject)
+			lfsContents = append(lfsContents, content)
+			content = lfsMetaObject.Pointer()
+		}
+
+		// Add the object to the database and to the index
+		objectHash, err := t.HashObject(strings.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		mode := "100644"
+		if executable {
+			mode = "100755"
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, file.TreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	// Now write the tree
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	// Now commit the tree
+	var commitHash string
+	if opts.Dates != nil {
+		commitHash, err = t.CommitTreeWithDate(author, committer, treeHash, message, opts.Dates.Author, opts.Dates.Committer)
+	} else {
+		commitHash, err = t.CommitTree(author, committer, treeHash, message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// We have LFS objects - create them
+	contentStore := &lfs.ContentStore{ObjectStorage: storage.LFS}
+	for i, lfsMetaObject := range lfsMetaObjects {
+		lfsMetaObject, err = models.NewLFSMetaObject(lfsMetaObject)
+		if err != nil {
+			return nil, err
+		}
+		exist, err := contentStore.Exists(lfsMetaObject)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			if err := contentStore.Put(lfsMetaObject, strings.NewReader(lfsContents[i])); err != nil {
+				if _, err2 := repo.RemoveLFSMetaObjectByOid(lfsMetaObject.Oid); err2 != nil {
+					return nil, fmt.Errorf("Error whilst removing failed inserted LFS object %s: %v (Prev Error: %v)", lfsMetaObject.Oid, err2, err)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	// Then push this tree to NewBranch
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		log.Error("%T %v", err, err)
+		return nil, err
+	}
+
+	commit, err = t.GetCommit(commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetFilesResponseFromCommit(repo, commit, opts.NewBranch, opts.Files), nil
+}
+
+// GetFilesResponseFromCommit constructs a FilesResponse of the changes of files of a commit, the
+// content of a deleted file is nil
+func GetFilesResponseFromCommit(repo *models.Repository, commit *git.Commit, branch string, files []*ChangeRepoFile) *api.FilesResponse {
+	contents := make([]*api.ContentsResponse, 0, len(files))
+	for _, file := range files {
+		var fileContents *api.ContentsResponse
+		if file.Operation != FileOperationDelete {
+			fileContents, _ = GetContents(repo, file.TreePath, branch, false) // ok if fails, then will be nil
+		}
+		contents = append(contents, fileContents)
+	}
+	fileCommitResponse, _ := GetFileCommitResponse(repo, commit) // ok if fails, then will be nil
+	return &api.FilesResponse{
+		Files:        contents,
+		Commit:       fileCommitResponse,
+		Verification: GetPayloadCommitVerification(commit),
+	}
+}
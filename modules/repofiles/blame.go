@@ -0,0 +1,126 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetBlame returns the blame of a file at the given ref, which can be a branch, commit or tag.
+// Unless bypassIgnoreRevs is set the revisions listed in the .git-blame-ignore-revs file
+// of the default branch are ignored.
+func GetBlame(ctx context.Context, repo *models.Repository, treePath, ref string, bypassIgnoreRevs bool) (*api.FileBlameResponse, error) {
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+
+	// Check that the path given in opts.treePath is valid (not a git path)
+	cleanTreePath := CleanUploadFileName(treePath)
+	if cleanTreePath == "" {
+		return nil, models.ErrFilenameInvalid{
+			Path: treePath,
+		}
+	}
+	treePath = cleanTreePath
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, models.ErrFilenameInvalid{
+			Path: treePath,
+		}
+	}
+
+	var ignoreRevs []string
+	if !bypassIgnoreRevs {
+		if ignoreRevs, err = gitRepo.GetBlameIgnoreRevs(repo.DefaultBranch); err != nil {
+			log.Error("GetBlameIgnoreRevs: %v", err)
+		}
+	}
+
+	blameReader, err := git.CreateBlameReader(ctx, repo.RepoPath(), commit.ID.String(), treePath, ignoreRevs)
+	if err != nil {
+		return nil, err
+	}
+	defer blameReader.Close()
+
+	resp := &api.FileBlameResponse{
+		Path:        treePath,
+		SHA:         commit.ID.String(),
+		IgnoresRevs: blameReader.IgnoresRevs(),
+		AgeBuckets:  git.BlameAgeBuckets,
+		Hunks:       make([]*api.BlameHunk, 0, 10),
+		Commits:     make([]*api.BlameCommit, 0, 10),
+	}
+
+	times := make(map[string]time.Time)
+	line := 1
+	for {
+		part, err := blameReader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part == nil {
+			break
+		}
+
+		resp.Hunks = append(resp.Hunks, &api.BlameHunk{
+			SHA:       part.Sha,
+			StartLine: line,
+			LineCount: len(part.Lines),
+			Lines:     part.Lines,
+		})
+		line += len(part.Lines)
+
+		if _, ok := times[part.Sha]; ok {
+			continue
+		}
+
+		c, err := gitRepo.GetCommit(part.Sha)
+		if err != nil {
+			return nil, err
+		}
+		times[part.Sha] = c.Committer.When
+		resp.Commits = append(resp.Commits, &api.BlameCommit{
+			CommitMeta: &api.CommitMeta{
+				URL:     util.URLJoin(repo.APIURL(), "git/commits", c.ID.String()),
+				SHA:     c.ID.String(),
+				Created: c.Committer.When,
+			},
+			HTMLURL:   util.URLJoin(repo.HTMLURL(), "commit", c.ID.String()),
+			Author:    convert.ToCommitUser(c.Author),
+			Committer: convert.ToCommitUser(c.Committer),
+			Message:   c.Message(),
+		})
+	}
+
+	ages := git.CalcBlameAges(times)
+	for _, hunk := range resp.Hunks {
+		hunk.AgeScore = ages[hunk.SHA].Score
+		hunk.AgeBucket = ages[hunk.SHA].Bucket
+	}
+
+	return resp, nil
+}
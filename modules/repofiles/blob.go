@@ -5,12 +5,42 @@
 package repofiles
 
 import (
+	"encoding/base64"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
+// GetBlobFoldRegions computes the regions of a text blob which can be collapsed
+// from its content, which must have been loaded
+func GetBlobFoldRegions(blob *api.GitBlobResponse) ([]*api.FoldRegion, error) {
+	if len(blob.Content) == 0 {
+		return nil, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(content) {
+		return nil, nil
+	}
+
+	regions := highlight.FoldRegions(charset.ToUTF8WithFallback(content))
+	apiRegions := make([]*api.FoldRegion, len(regions))
+	for i := range regions {
+		apiRegions[i] = &api.FoldRegion{
+			Start: regions[i].Start,
+			End:   regions[i].End,
+		}
+	}
+	return apiRegions, nil
+}
+
 // GetBlobBySHA get the GitBlobResponse of a repository using a sha hash.
 func GetBlobBySHA(repo *models.Repository, sha string) (*api.GitBlobResponse, error) {
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
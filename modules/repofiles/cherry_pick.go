@@ -0,0 +1,160 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// CherryPickOptions holds the options to cherry-pick or revert a commit onto a branch
+type CherryPickOptions struct {
+	LastCommitID string
+	OldBranch    string
+	NewBranch    string
+	CommitID     string
+	Revert       bool
+	Message      string
+	// Resolutions are the resolutions of the conflicting files by their paths
+	Resolutions map[string]*MergeResolution
+}
+
+// cherryPick clones the branch into the temporary repository and reads the cherry-pick or the revert
+// of the commit into the index, the merge is based on the first parent of the commit
+func cherryPick(t *TemporaryUploadRepository, opts *CherryPickOptions) (*git.Commit, *MergeConflicts, error) {
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, nil, err
+	}
+	commitID, err := t.GetLastCommit()
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.LastCommitID != "" && opts.LastCommitID != commitID {
+		return nil, nil, models.ErrCommitIDDoesNotMatch{
+			GivenCommitID:   opts.LastCommitID,
+			CurrentCommitID: commitID,
+		}
+	}
+
+	commit, err := t.GetCommit(opts.CommitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent := git.EmptyTreeSHA
+	if commit.ParentCount() > 0 {
+		parentID, err := commit.ParentID(0)
+		if err != nil {
+			return nil, nil, err
+		}
+		parent = parentID.String()
+	}
+
+	ancestor, theirs := parent, commit.ID.String()
+	theirsLabel := commitLabel(commit)
+	if opts.Revert {
+		ancestor, theirs = theirs, ancestor
+		theirsLabel = "parent of " + theirsLabel
+	}
+	files, err := readMergeConflicts(t, ancestor, theirs, opts.OldBranch, theirsLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commit, &MergeConflicts{
+		CommitID:      commitID,
+		MergeCommitID: commit.ID.String(),
+		Files:         files,
+	}, nil
+}
+
+// commitLabel returns the label of a commit in conflict markers
+func commitLabel(commit *git.Commit) string {
+	return base.ShortSha(commit.ID.String()) + " " + commit.Summary()
+}
+
+// GetCherryPickConflicts returns the files of a branch of a repository conflicting with the cherry-pick
+// or the revert of a commit
+func GetCherryPickConflicts(repo *models.Repository, opts *CherryPickOptions) (*MergeConflicts, error) {
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	_, conflicts, err := cherryPick(t, opts)
+	return conflicts, err
+}
+
+// CherryPickMessage returns the default message of the cherry-pick or the revert of a commit
+func CherryPickMessage(commit *git.Commit, revert bool) string {
+	if revert {
+		return fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", commit.Summary(), commit.ID)
+	}
+	return fmt.Sprintf("%s\n\n(cherry picked from commit %s)", strings.TrimSpace(commit.Message()), commit.ID)
+}
+
+// CherryPick commits the cherry-pick or the revert of a commit onto a branch of a repository, resolving
+// its conflicts with the given resolutions, and returns the ID of the new commit. The commit is pushed
+// to a new branch when one is given.
+func CherryPick(repo *models.Repository, doer *models.User, opts *CherryPickOptions) (string, error) {
+	if opts.NewBranch == "" {
+		opts.NewBranch = opts.OldBranch
+	}
+	if opts.NewBranch != opts.OldBranch {
+		newBranch, err := repo_module.GetBranch(repo, opts.NewBranch)
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return "", err
+		}
+		if newBranch != nil {
+			return "", models.ErrBranchAlreadyExists{
+				BranchName: opts.NewBranch,
+			}
+		}
+	}
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+
+	commit, conflicts, err := cherryPick(t, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := stageMergeResolutions(t, conflicts.Files, opts.Resolutions); err != nil {
+		return "", err
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = CherryPickMessage(commit, opts.Revert)
+	}
+	// a cherry-pick keeps the author of the commit
+	author := doer
+	authorDate := time.Now()
+	if !opts.Revert {
+		author, _ = GetAuthorAndCommitterUsers(&IdentityOptions{Name: commit.Author.Name, Email: commit.Author.Email}, nil, doer)
+		authorDate = commit.Author.When
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", err
+	}
+	commitHash, err := t.CommitTreeWithDate(author, doer, treeHash, message, authorDate, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MaxCodePreviewLines is the maximum number of lines shown by the preview of a permalink
+const MaxCodePreviewLines = 50
+
+// GetCodePreview returns the highlighted lines a permalink refers to. Comments are rendered
+// once for every reader, so only repositories whose code can be read anonymously are
+// previewed, permalinks to other repositories are left as links.
+func GetCodePreview(link *markup.CodePreviewLink) ([]string, error) {
+	repo, err := models.GetRepositoryByOwnerAndName(link.OwnerName, link.RepoName)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(repo, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		return nil, nil
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(link.CommitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(link.FilePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if entry.IsDir() || entry.IsSubModule() || entry.Blob().Size() > setting.UI.MaxDisplayFileSize {
+		return nil, nil
+	}
+
+	dataRc, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	buf, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(buf) {
+		return nil, nil
+	}
+	buf = charset.ToUTF8WithFallback(buf)
+
+	numLines := bytes.Count(buf, []byte{'\n'})
+	if len(buf) > 0 && !bytes.HasSuffix(buf, []byte{'\n'}) {
+		numLines++
+	}
+	if link.LineStart > numLines {
+		return nil, nil
+	}
+	stop := link.LineStop
+	if stop > numLines {
+		stop = numLines
+	}
+	if stop-link.LineStart >= MaxCodePreviewLines {
+		stop = link.LineStart + MaxCodePreviewLines - 1
+	}
+
+	highlighted := highlight.File(numLines, entry.Name(), buf)
+	lines := make([]string, 0, stop-link.LineStart+1)
+	for i := link.LineStart; i <= stop; i++ {
+		lines = append(lines, highlighted[i])
+	}
+	return lines, nil
+}
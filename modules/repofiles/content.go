@@ -5,13 +5,18 @@
 package repofiles
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"path"
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -215,3 +220,88 @@ func GetContents(repo *models.Repository, treePath, ref string, forList bool) (*
 
 	return contentsResponse, nil
 }
+
+// ErrContentsLinesInvalid represents a range of lines which isn't in a text file
+type ErrContentsLinesInvalid struct {
+	Path      string
+	LineStart int
+	Reason    string
+}
+
+// IsErrContentsLinesInvalid checks if an error is an ErrContentsLinesInvalid.
+func IsErrContentsLinesInvalid(err error) bool {
+	_, ok := err.(ErrContentsLinesInvalid)
+	return ok
+}
+
+func (err ErrContentsLinesInvalid) Error() string {
+	return fmt.Sprintf("invalid lines from %d of %s: %s", err.LineStart, err.Path, err.Reason)
+}
+
+// GetContentsLines gets the lines lineStart to lineEnd of a text file, a lineEnd of 0 or beyond the
+// end of the file gets the lines until its end. The lines are highlighted if highlighted is true.
+func GetContentsLines(repo *models.Repository, treePath, ref string, lineStart, lineEnd int, highlighted bool) (*api.ContentsLines, error) {
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	treePath = CleanUploadFileName(treePath)
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, ErrContentsLinesInvalid{Path: treePath, LineStart: lineStart, Reason: "not a file"}
+	}
+
+	dataRc, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	buf, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(buf) {
+		return nil, ErrContentsLinesInvalid{Path: treePath, LineStart: lineStart, Reason: "not a text file"}
+	}
+	buf = charset.ToUTF8WithFallback(buf)
+
+	lines := bytes.SplitAfter(buf, []byte{'\n'})
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if lineStart > len(lines) {
+		return nil, ErrContentsLinesInvalid{Path: treePath, LineStart: lineStart, Reason: "beyond the end of the file"}
+	}
+	if lineEnd <= 0 || lineEnd > len(lines) {
+		lineEnd = len(lines)
+	}
+
+	result := &api.ContentsLines{
+		Start:   lineStart,
+		End:     lineEnd,
+		Total:   len(lines),
+		Content: string(bytes.Join(lines[lineStart-1:lineEnd], nil)),
+	}
+	if highlighted {
+		code := highlight.File(len(lines), entry.Name(), buf)
+		result.Highlighted = make([]string, 0, lineEnd-lineStart+1)
+		for i := lineStart; i <= lineEnd; i++ {
+			result.Highlighted = append(result.Highlighted, code[i])
+		}
+		result.Lexer = highlight.LexerName(entry.Name(), buf)
+	}
+	return result, nil
+}
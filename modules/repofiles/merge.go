@@ -0,0 +1,323 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+)
+
+// MergeConflict represents a file of a branch conflicting with the branch merged into it
+type MergeConflict struct {
+	TreePath string
+	Mode     string
+	// Ours and Theirs are the contents of the file in the branch and in the merged branch,
+	// they are nil when the file is deleted on this side
+	Ours   []byte
+	Theirs []byte
+	// Content is the file merged with conflict markers, it is empty when the sides can't be merged as text
+	Content string
+
+	ours, theirs *UnmergedEntry
+}
+
+// IsText returns whether the conflict can be resolved by editing the merged content
+func (c *MergeConflict) IsText() bool {
+	return len(c.Content) > 0
+}
+
+// IsDeletedByUs returns whether the file is deleted in the branch
+func (c *MergeConflict) IsDeletedByUs() bool {
+	return c.ours == nil
+}
+
+// IsDeletedByThem returns whether the file is deleted in the merged branch
+func (c *MergeConflict) IsDeletedByThem() bool {
+	return c.theirs == nil
+}
+
+// MergeConflicts represents the conflicts of the merge of a branch into another one
+type MergeConflicts struct {
+	CommitID      string
+	MergeCommitID string
+	Files         []*MergeConflict
+}
+
+// MergeBranchOptions holds the options to merge a branch of a repository into a branch
+type MergeBranchOptions struct {
+	Branch      string
+	MergeRepo   *models.Repository
+	MergeBranch string
+}
+
+// MergeResolutionType represents the way a conflicting file is resolved
+type MergeResolutionType int
+
+const (
+	// MergeResolutionContent resolves the conflict with an edited content
+	MergeResolutionContent MergeResolutionType = iota
+	// MergeResolutionOurs keeps the file of the branch
+	MergeResolutionOurs
+	// MergeResolutionTheirs keeps the file of the merged branch
+	MergeResolutionTheirs
+)
+
+// MergeResolution represents the resolution of a conflicting file
+type MergeResolution struct {
+	Type    MergeResolutionType
+	Content []byte
+}
+
+// ResolveMergeConflictsOptions holds the options to commit the resolution of merge conflicts
+type ResolveMergeConflictsOptions struct {
+	MergeBranchOptions
+	LastCommitID  string
+	MergeCommitID string
+	Message       string
+	// Resolutions are the resolutions of the conflicting files by their paths
+	Resolutions map[string]*MergeResolution
+}
+
+func isBlobMode(mode string) bool {
+	return mode == "100644" || mode == "100755"
+}
+
+func (t *TemporaryUploadRepository) readBlob(entry *UnmergedEntry) ([]byte, error) {
+	blob, err := t.gitRepo.GetBlob(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	return ioutil.ReadAll(dataRc)
+}
+
+// mergeUnmergedFile merges the stages of an unmerged file, it stages the merged file
+// and returns nil when the file merges cleanly
+func mergeUnmergedFile(t *TemporaryUploadRepository, treePath string, stages [4]*UnmergedEntry, oursLabel, theirsLabel string) (*MergeConflict, error) {
+	ancestor, ours, theirs := stages[1], stages[2], stages[3]
+	conflict := &MergeConflict{TreePath: treePath, ours: ours, theirs: theirs}
+	if ours != nil {
+		conflict.Mode = ours.Mode
+	}
+	if theirs != nil && (ours == nil || (ancestor != nil && ancestor.Mode == ours.Mode)) {
+		conflict.Mode = theirs.Mode
+	}
+	for _, entry := range stages[1:] {
+		if entry != nil && !isBlobMode(entry.Mode) {
+			return conflict, nil
+		}
+	}
+
+	var err error
+	if ours != nil {
+		if conflict.Ours, err = t.readBlob(ours); err != nil {
+			return nil, err
+		}
+	}
+	if theirs != nil {
+		if conflict.Theirs, err = t.readBlob(theirs); err != nil {
+			return nil, err
+		}
+	}
+	if ours == nil || theirs == nil || !base.IsTextFile(conflict.Ours) || !base.IsTextFile(conflict.Theirs) {
+		return conflict, nil
+	}
+
+	var ancestorContent []byte
+	if ancestor != nil {
+		if ancestorContent, err = t.readBlob(ancestor); err != nil {
+			return nil, err
+		}
+	}
+	merged, clean, err := t.MergeFile(conflict.Ours, ancestorContent, conflict.Theirs, oursLabel, theirsLabel)
+	if err != nil {
+		return nil, err
+	}
+	if !clean {
+		conflict.Content = string(merged)
+		return conflict, nil
+	}
+
+	objectHash, err := t.HashObject(bytes.NewReader(merged))
+	if err != nil {
+		return nil, err
+	}
+	return nil, t.AddObjectToIndex(conflict.Mode, objectHash, treePath)
+}
+
+// readMergeConflicts reads the three-way merge of the HEAD and a commit into the index of the temporary repository,
+// the merge is based on their merge base when the base is empty. The files which can be merged are staged
+// and the conflicting ones are returned, the labels name both sides in their conflict markers.
+func readMergeConflicts(t *TemporaryUploadRepository, base, commitID, oursLabel, theirsLabel string) ([]*MergeConflict, error) {
+	if err := t.ReadMergeTree(base, commitID); err != nil {
+		return nil, err
+	}
+	entries, err := t.UnmergedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	// the stages of a file are listed consecutively
+	treePaths := make([]string, 0, len(entries))
+	stages := make(map[string][4]*UnmergedEntry, len(entries))
+	for _, entry := range entries {
+		fileStages, ok := stages[entry.TreePath]
+		if !ok {
+			treePaths = append(treePaths, entry.TreePath)
+		}
+		if entry.Stage > 0 && entry.Stage < len(fileStages) {
+			fileStages[entry.Stage] = entry
+		}
+		stages[entry.TreePath] = fileStages
+	}
+
+	conflicts := make([]*MergeConflict, 0, len(treePaths))
+	for _, treePath := range treePaths {
+		conflict, err := mergeUnmergedFile(t, treePath, stages[treePath], oursLabel, theirsLabel)
+		if err != nil {
+			return nil, err
+		}
+		if conflict != nil {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+// mergeBranch clones the branch into the temporary repository and reads its merge with the merged branch into the index
+func mergeBranch(t *TemporaryUploadRepository, opts *MergeBranchOptions) (*MergeConflicts, error) {
+	if err := t.Clone(opts.Branch); err != nil {
+		return nil, err
+	}
+	commitID, err := t.GetLastCommit()
+	if err != nil {
+		return nil, err
+	}
+	mergeCommitID, err := t.FetchBranch(opts.MergeRepo, opts.MergeBranch)
+	if err != nil {
+		return nil, err
+	}
+	files, err := readMergeConflicts(t, "", mergeCommitID, opts.Branch, opts.MergeBranch)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeConflicts{
+		CommitID:      commitID,
+		MergeCommitID: mergeCommitID,
+		Files:         files,
+	}, nil
+}
+
+// GetMergeConflicts returns the files of a branch of a repository conflicting with a branch merged into it
+func GetMergeConflicts(repo *models.Repository, opts *MergeBranchOptions) (*MergeConflicts, error) {
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	return mergeBranch(t, opts)
+}
+
+// hasConflictMarkers returns whether a content still has the conflict markers of a merge
+func hasConflictMarkers(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMergeConflict stages the resolution of a conflicting file
+func resolveMergeConflict(t *TemporaryUploadRepository, conflict *MergeConflict, resolution *MergeResolution) error {
+	var entry *UnmergedEntry
+	switch resolution.Type {
+	case MergeResolutionOurs:
+		entry = conflict.ours
+	case MergeResolutionTheirs:
+		entry = conflict.theirs
+	default:
+		if !conflict.IsText() || hasConflictMarkers(resolution.Content) {
+			return models.ErrMergeConflictNotResolved{Path: conflict.TreePath}
+		}
+		objectHash, err := t.HashObject(bytes.NewReader(resolution.Content))
+		if err != nil {
+			return err
+		}
+		return t.AddObjectToIndex(conflict.Mode, objectHash, conflict.TreePath)
+	}
+
+	if entry == nil {
+		return t.RemoveFilesFromIndex(conflict.TreePath)
+	}
+	return t.AddObjectToIndex(entry.Mode, entry.Hash, conflict.TreePath)
+}
+
+// stageMergeResolutions stages the resolutions of all the conflicting files
+func stageMergeResolutions(t *TemporaryUploadRepository, conflicts []*MergeConflict, resolutions map[string]*MergeResolution) error {
+	for _, conflict := range conflicts {
+		resolution, ok := resolutions[conflict.TreePath]
+		if !ok {
+			return models.ErrMergeConflictNotResolved{Path: conflict.TreePath}
+		}
+		if err := resolveMergeConflict(t, conflict, resolution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveMergeConflicts merges a branch into a branch of a repository, resolving its conflicts
+// with the given resolutions, and returns the ID of the merge commit
+func ResolveMergeConflicts(repo *models.Repository, doer *models.User, opts *ResolveMergeConflictsOptions) (string, error) {
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+
+	conflicts, err := mergeBranch(t, &opts.MergeBranchOptions)
+	if err != nil {
+		return "", err
+	}
+	if conflicts.CommitID != opts.LastCommitID {
+		return "", models.ErrCommitIDDoesNotMatch{
+			GivenCommitID:   opts.LastCommitID,
+			CurrentCommitID: conflicts.CommitID,
+		}
+	}
+	if conflicts.MergeCommitID != opts.MergeCommitID {
+		return "", models.ErrCommitIDDoesNotMatch{
+			GivenCommitID:   opts.MergeCommitID,
+			CurrentCommitID: conflicts.MergeCommitID,
+		}
+	}
+
+	if err := stageMergeResolutions(t, conflicts.Files, opts.Resolutions); err != nil {
+		return "", err
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", err
+	}
+	commitHash, err := t.CommitMergeTree(doer, doer, treeHash, opts.Message, conflicts.MergeCommitID)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Push(doer, commitHash, opts.Branch); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
@@ -9,8 +9,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -191,6 +195,16 @@ func (t *TemporaryUploadRepository) CommitTree(author, committer *models.User, t
 
 // CommitTreeWithDate creates a commit from a given tree for the user with provided message
 func (t *TemporaryUploadRepository) CommitTreeWithDate(author, committer *models.User, treeHash string, message string, authorDate, committerDate time.Time) (string, error) {
+	return t.commitTree(author, committer, treeHash, message, authorDate, committerDate)
+}
+
+// CommitMergeTree creates a commit from a given tree for the user with provided message
+// whose parents are the HEAD and the merged commit
+func (t *TemporaryUploadRepository) CommitMergeTree(author, committer *models.User, treeHash string, message string, mergeCommitID string) (string, error) {
+	return t.commitTree(author, committer, treeHash, message, time.Now(), time.Now(), mergeCommitID)
+}
+
+func (t *TemporaryUploadRepository) commitTree(author, committer *models.User, treeHash string, message string, authorDate, committerDate time.Time, parents ...string) (string, error) {
 	authorSig := author.NewGitSig()
 	committerSig := committer.NewGitSig()
 
@@ -212,12 +226,16 @@ func (t *TemporaryUploadRepository) CommitTreeWithDate(author, committer *models
 	_, _ = messageBytes.WriteString("\n")
 
 	args := []string{"commit-tree", treeHash, "-p", "HEAD"}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
 
 	// Determine if we should sign
 	if git.CheckGitVersionAtLeast("1.7.9") == nil {
-		sign, keyID, signer, _ := t.repo.SignCRUDAction(author, t.basePath, "HEAD")
+		sign, key, signer, _ := t.repo.SignCRUDAction(author, t.basePath, "HEAD")
 		if sign {
-			args = append(args, "-S"+keyID)
+			args = append(args, "-S"+key.KeyID)
+			env = append(env, key.Env()...)
 			if t.repo.GetTrustModel() == models.CommitterTrustModel || t.repo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
 				if committerSig.Name != authorSig.Name || committerSig.Email != authorSig.Email {
 					// Add trailers
@@ -252,6 +270,124 @@ func (t *TemporaryUploadRepository) CommitTreeWithDate(author, committer *models
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// FetchBranch fetches the branch of another repository and returns the ID of its head commit
+func (t *TemporaryUploadRepository) FetchBranch(repo *models.Repository, branch string) (string, error) {
+	if _, err := git.NewCommand("fetch", "--no-tags", repo.RepoPath(), git.BranchPrefix+branch).RunInDir(t.basePath); err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return "", git.ErrBranchNotExist{
+				Name: branch,
+			}
+		}
+		log.Error("Unable to fetch branch %s of %s in temporary repo: %s(%s): Error: %v", branch, repo.FullName(), t.repo.FullName(), t.basePath, err)
+		return "", fmt.Errorf("Unable to fetch branch %s of %s in temporary repo for: %s Error: %v", branch, repo.FullName(), t.repo.FullName(), err)
+	}
+	return t.GetLastCommitByRef("FETCH_HEAD")
+}
+
+// ReadMergeTree reads the three-way merge of the HEAD and a commit based on another commit into a fresh index,
+// the merge is based on their merge base when the base is empty. The files which can't be merged trivially
+// are left unmerged.
+func (t *TemporaryUploadRepository) ReadMergeTree(base, commitID string) error {
+	if err := os.Remove(path.Join(t.basePath, "index")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Unable to remove index of temporary repo for: %s Error: %v", t.repo.FullName(), err)
+	}
+	if base == "" {
+		mergeBase, err := git.NewCommand("merge-base", "HEAD", commitID).RunInDir(t.basePath)
+		if err != nil {
+			log.Error("Unable to find merge base of HEAD and %s in temporary repo: %s(%s): Error: %v", commitID, t.repo.FullName(), t.basePath, err)
+			return fmt.Errorf("Unable to find merge base of HEAD and %s in temporary repo for: %s Error: %v", commitID, t.repo.FullName(), err)
+		}
+		base = strings.TrimSpace(mergeBase)
+	}
+	if _, err := git.NewCommand("read-tree", "-m", "-i", "--aggressive", base, "HEAD", commitID).RunInDir(t.basePath); err != nil {
+		log.Error("Unable to read merge tree of HEAD and %s in temporary repo: %s(%s): Error: %v", commitID, t.repo.FullName(), t.basePath, err)
+		return fmt.Errorf("Unable to read merge tree of HEAD and %s in temporary repo for: %s Error: %v", commitID, t.repo.FullName(), err)
+	}
+	return nil
+}
+
+// UnmergedEntry represents a stage of a file left unmerged in the index
+type UnmergedEntry struct {
+	Mode     string
+	Hash     string
+	Stage    int
+	TreePath string
+}
+
+// UnmergedFiles returns the stages of the files left unmerged in the index
+func (t *TemporaryUploadRepository) UnmergedFiles() ([]*UnmergedEntry, error) {
+	stdout, err := git.NewCommand("ls-files", "-u", "-z").RunInDir(t.basePath)
+	if err != nil {
+		log.Error("Unable to list unmerged files in temporary repo: %s(%s): Error: %v", t.repo.FullName(), t.basePath, err)
+		return nil, fmt.Errorf("Unable to list unmerged files in temporary repo for: %s Error: %v", t.repo.FullName(), err)
+	}
+
+	entries := make([]*UnmergedEntry, 0, 4)
+	for _, line := range strings.Split(stdout, "\000") {
+		if line == "" {
+			continue
+		}
+		// <mode> SP <object> SP <stage> TAB <file>
+		tab := strings.IndexByte(line, '\t')
+		if tab < 0 {
+			return nil, fmt.Errorf("Unable to parse unmerged entry: %q", line)
+		}
+		fields := strings.Fields(line[:tab])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Unable to parse unmerged entry: %q", line)
+		}
+		stage, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse unmerged entry: %q", line)
+		}
+		entries = append(entries, &UnmergedEntry{
+			Mode:     fields[0],
+			Hash:     fields[1],
+			Stage:    stage,
+			TreePath: line[tab+1:],
+		})
+	}
+	return entries, nil
+}
+
+// MergeFile runs a three-way merge of the contents of a file and returns whether the merge is clean,
+// the merged content has conflict markers labelled by the labels of both sides otherwise or is nil
+// when the contents can't be merged as text
+func (t *TemporaryUploadRepository) MergeFile(ours, base, theirs []byte, oursLabel, theirsLabel string) ([]byte, bool, error) {
+	dir, err := ioutil.TempDir(t.basePath, "merge-file")
+	if err != nil {
+		return nil, false, fmt.Errorf("Unable to create merge directory in temporary repo for: %s Error: %v", t.repo.FullName(), err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Error("Unable to remove merge directory %s: %v", dir, err)
+		}
+	}()
+	for name, content := range map[string][]byte{"ours": ours, "base": base, "theirs": theirs} {
+		if err := ioutil.WriteFile(path.Join(dir, name), content, 0600); err != nil {
+			return nil, false, fmt.Errorf("Unable to write %s file to merge in temporary repo for: %s Error: %v", name, t.repo.FullName(), err)
+		}
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err = git.NewCommand("merge-file", "-p", "-L", oursLabel, "-L", "base", "-L", theirsLabel, "ours", "base", "theirs").RunInDirPipeline(dir, stdout, stderr)
+	if err == nil {
+		return stdout.Bytes(), true, nil
+	}
+	// merge-file exits with the number of conflicts, or with -1 when it fails
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if code := exitErr.ExitCode(); code > 0 && code < 128 {
+			return stdout.Bytes(), false, nil
+		}
+		if strings.Contains(stderr.String(), "Cannot merge binary files") {
+			return nil, false, nil
+		}
+	}
+	log.Error("Unable to merge file in temporary repo: %s(%s): Error: %v\nStderr: %s", t.repo.FullName(), t.basePath, err, stderr)
+	return nil, false, fmt.Errorf("Unable to merge file in temporary repo for: %s Error: %v\nStderr: %s", t.repo.FullName(), err, stderr)
+}
+
 // Push the provided commitHash to the repository branch by the provided user
 func (t *TemporaryUploadRepository) Push(doer *models.User, commitHash string, branch string) error {
 	// Because calls hooks we need to pass in the environment
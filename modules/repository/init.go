@@ -132,9 +132,10 @@ func initRepoCommit(tmpPath string, repo *models.Repository, u *models.User, def
 	}
 
 	if git.CheckGitVersionAtLeast("1.7.9") == nil {
-		sign, keyID, signer, _ := models.SignInitialCommit(tmpPath, u)
+		sign, key, signer, _ := models.SignInitialCommit(tmpPath, u)
 		if sign {
-			args = append(args, "-S"+keyID)
+			args = append(args, "-S"+key.KeyID)
+			env = append(env, key.Env()...)
 
 			if repo.GetTrustModel() == models.CommitterTrustModel || repo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
 				// need to set the committer to the KeyID owner
@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+)
+
+// ToggleableUnitTypes are the units which can be enabled and disabled without any configuration
+var ToggleableUnitTypes = []models.UnitType{
+	models.UnitTypeIssues,
+	models.UnitTypePullRequests,
+	models.UnitTypeWiki,
+	models.UnitTypeProjects,
+}
+
+// ParseUnitsToToggle returns the unit types of the name keys of the units to enable and disable, e.g. "repo.wiki",
+// it fails if a unit doesn't exist, is globally disabled, can't be toggled or is both enabled and disabled
+func ParseUnitsToToggle(enable, disable []string) ([]models.UnitType, []models.UnitType, error) {
+	enableTypes, err := parseToggleableUnitTypes(enable)
+	if err != nil {
+		return nil, nil, err
+	}
+	disableTypes, err := parseToggleableUnitTypes(disable)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, tp := range enableTypes {
+		for _, t := range disableTypes {
+			if t == tp {
+				return nil, nil, fmt.Errorf("unit is both enabled and disabled: %s", models.Units[tp].NameKey)
+			}
+		}
+	}
+	return enableTypes, disableTypes, nil
+}
+
+func parseToggleableUnitTypes(nameKeys []string) ([]models.UnitType, error) {
+	unitTypes := make([]models.UnitType, 0, len(nameKeys))
+	for _, key := range nameKeys {
+		tps := models.FindUnitTypes(strings.TrimSpace(key))
+		if len(tps) == 0 {
+			return nil, fmt.Errorf("unknown unit: %s", key)
+		}
+		if tps[0].UnitGlobalDisabled() {
+			return nil, fmt.Errorf("unit is globally disabled: %s", key)
+		}
+		if !isToggleableUnitType(tps[0]) {
+			return nil, fmt.Errorf("unit can't be enabled or disabled: %s", key)
+		}
+		unitTypes = append(unitTypes, tps[0])
+	}
+	return unitTypes, nil
+}
+
+func isToggleableUnitType(tp models.UnitType) bool {
+	for _, t := range ToggleableUnitTypes {
+		if t == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateRepositoryUnits enables and disables units of the repository, the units already in the wanted
+// state are left untouched. It returns whether the units of the repository have been changed.
+func UpdateRepositoryUnits(repo *models.Repository, enable, disable []models.UnitType) (bool, error) {
+	var units []models.RepoUnit
+	var deleteUnitTypes []models.UnitType
+	for _, tp := range enable {
+		if repo.UnitEnabled(tp) {
+			continue
+		}
+		units = append(units, models.NewRepoUnit(repo.ID, tp))
+		// the external units replace the internal ones
+		switch tp {
+		case models.UnitTypeIssues:
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
+		case models.UnitTypeWiki:
+			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalWiki)
+		}
+	}
+	for _, tp := range disable {
+		if repo.UnitEnabled(tp) {
+			deleteUnitTypes = append(deleteUnitTypes, tp)
+		}
+	}
+	if len(units) == 0 && len(deleteUnitTypes) == 0 {
+		return false, nil
+	}
+
+	if err := models.UpdateRepositoryUnits(repo, units, deleteUnitTypes); err != nil {
+		return false, err
+	}
+	repo.Units = nil
+	return true, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnitsToToggle(t *testing.T) {
+	enable, disable, err := ParseUnitsToToggle([]string{"repo.wiki", "Repo.Projects"}, []string{"repo.issues"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.UnitType{models.UnitTypeWiki, models.UnitTypeProjects}, enable)
+	assert.Equal(t, []models.UnitType{models.UnitTypeIssues}, disable)
+
+	_, _, err = ParseUnitsToToggle([]string{"repo.unknown"}, nil)
+	assert.Error(t, err)
+	_, _, err = ParseUnitsToToggle(nil, []string{"repo.code"})
+	assert.Error(t, err)
+	_, _, err = ParseUnitsToToggle([]string{"repo.wiki"}, []string{"repo.wiki"})
+	assert.Error(t, err)
+}
+
+func TestUpdateRepositoryUnits(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	changed, err := UpdateRepositoryUnits(repo, []models.UnitType{models.UnitTypeProjects}, []models.UnitType{models.UnitTypeWiki})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, repo.UnitEnabled(models.UnitTypeProjects))
+	assert.False(t, repo.UnitEnabled(models.UnitTypeWiki))
+	models.AssertNotExistsBean(t, &models.RepoUnit{RepoID: 1, Type: models.UnitTypeWiki})
+
+	// nothing changes if the units are already in the wanted state
+	changed, err = UpdateRepositoryUnits(repo, []models.UnitType{models.UnitTypeProjects}, []models.UnitType{models.UnitTypeWiki})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Filter represents the equality filter of a list request, the only filter supported
+type Filter struct {
+	// Attribute is the lower case path of the filtered attribute
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter of the form `attribute eq "value"`, nil is returned for an empty filter
+func ParseFilter(filter string) (*Filter, error) {
+	filter = strings.TrimSpace(filter)
+	if len(filter) == 0 {
+		return nil, nil
+	}
+
+	fields := strings.SplitN(filter, " ", 3)
+	if len(fields) != 3 || !strings.EqualFold(fields[1], "eq") {
+		return nil, NewError(http.StatusBadRequest, ErrorTypeInvalidFilter, "unsupported filter %q, only the eq operator is supported", filter)
+	}
+
+	var value string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(fields[2])), &value); err != nil {
+		return nil, NewError(http.StatusBadRequest, ErrorTypeInvalidFilter, "invalid value of the filter %q", filter)
+	}
+	return &Filter{
+		Attribute: strings.ToLower(fields[0]),
+		Value:     value,
+	}, nil
+}
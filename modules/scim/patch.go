@@ -0,0 +1,301 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Operations of a patch request
+const (
+	PatchOpAdd     = "add"
+	PatchOpReplace = "replace"
+	PatchOpRemove  = "remove"
+)
+
+// PatchRequest represents a request modifying the attributes of a resource
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation represents an operation of a patch request, the value of an operation without path is an
+// object of the attributes to add or replace
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// attributes returns the attributes the operation applies to by their path, the path of the operation or the
+// attributes of its value when it has no path
+func (op *PatchOperation) attributes() (map[string]json.RawMessage, string, error) {
+	kind := strings.ToLower(op.Op)
+	if kind != PatchOpAdd && kind != PatchOpReplace && kind != PatchOpRemove {
+		return nil, "", NewError(http.StatusBadRequest, ErrorTypeInvalidSyntax, "unsupported patch operation %q", op.Op)
+	}
+	if len(op.Path) > 0 {
+		return map[string]json.RawMessage{op.Path: op.Value}, kind, nil
+	}
+	if kind == PatchOpRemove {
+		return nil, "", NewError(http.StatusBadRequest, "noTarget", "the remove operation requires a path")
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attributes); err != nil {
+		return nil, "", NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "the value of an operation without path must be an object")
+	}
+	return attributes, kind, nil
+}
+
+func unmarshalValue(path string, value json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(value, v); err != nil {
+		return NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "invalid value of %s", path)
+	}
+	return nil
+}
+
+// unmarshalBool accepts the booleans sent as strings by some identity providers
+func unmarshalBool(path string, value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "invalid value of %s", path)
+}
+
+// emailFilterPath returns the filter of a path like `emails[type eq "work"].value`
+func emailFilterPath(path string) (*Filter, bool) {
+	lower := strings.ToLower(path)
+	if !strings.HasPrefix(lower, "emails[") || !strings.HasSuffix(lower, "].value") {
+		return nil, false
+	}
+	filter, err := ParseFilter(path[len("emails[") : len(path)-len("].value")])
+	if err != nil || filter == nil {
+		return nil, false
+	}
+	return filter, true
+}
+
+// ApplyPatch applies the operation of a patch request to the user, the attributes not stored by Gitea are ignored
+func (u *User) ApplyPatch(op PatchOperation) error {
+	attributes, kind, err := op.attributes()
+	if err != nil {
+		return err
+	}
+	for path, value := range attributes {
+		if err := u.applyAttribute(kind, path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *User) applyAttribute(kind, path string, value json.RawMessage) error {
+	remove := kind == PatchOpRemove
+	if u.Name == nil {
+		u.Name = &Name{}
+	}
+
+	var target *string
+	switch strings.ToLower(path) {
+	case "active":
+		if remove {
+			u.Active = nil
+			return nil
+		}
+		active, err := unmarshalBool(path, value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+		return nil
+	case "username":
+		if remove {
+			return NewError(http.StatusBadRequest, ErrorTypeMutability, "userName is required")
+		}
+		target = &u.UserName
+	case "externalid":
+		target = &u.ExternalID
+	case "displayname":
+		target = &u.DisplayName
+	case "password":
+		target = &u.Password
+	case "name":
+		if remove {
+			u.Name = &Name{}
+			return nil
+		}
+		return unmarshalValue(path, value, u.Name)
+	case "name.formatted":
+		target = &u.Name.Formatted
+	case "name.givenname":
+		target = &u.Name.GivenName
+	case "name.familyname":
+		target = &u.Name.FamilyName
+	case "emails":
+		if remove {
+			u.Emails = nil
+			return nil
+		}
+		var emails []Email
+		if err := unmarshalValue(path, value, &emails); err != nil {
+			return err
+		}
+		if kind == PatchOpAdd {
+			u.Emails = append(u.Emails, emails...)
+		} else {
+			u.Emails = emails
+		}
+		return nil
+	default:
+		filter, ok := emailFilterPath(path)
+		if !ok {
+			// the attributes Gitea doesn't store, like the addresses or the phone numbers of the users
+			return nil
+		}
+		return u.applyEmailValue(filter, remove, path, value)
+	}
+
+	if remove {
+		*target = ""
+		return nil
+	}
+	return unmarshalValue(path, value, target)
+}
+
+// applyEmailValue sets the address of the email matching the filter on its type or its primary flag
+func (u *User) applyEmailValue(filter *Filter, remove bool, path string, value json.RawMessage) error {
+	matches := func(email *Email) bool {
+		switch filter.Attribute {
+		case "type":
+			return strings.EqualFold(email.Type, filter.Value)
+		case "primary":
+			return email.Primary
+		}
+		return false
+	}
+
+	for i := range u.Emails {
+		if !matches(&u.Emails[i]) {
+			continue
+		}
+		if remove {
+			u.Emails = append(u.Emails[:i], u.Emails[i+1:]...)
+			return nil
+		}
+		return unmarshalValue(path, value, &u.Emails[i].Value)
+	}
+	if remove {
+		return nil
+	}
+
+	email := Email{Primary: len(u.Emails) == 0}
+	if filter.Attribute == "type" {
+		email.Type = filter.Value
+	}
+	if err := unmarshalValue(path, value, &email.Value); err != nil {
+		return err
+	}
+	u.Emails = append(u.Emails, email)
+	return nil
+}
+
+// ApplyPatch applies the operation of a patch request to the group
+func (g *Group) ApplyPatch(op PatchOperation) error {
+	attributes, kind, err := op.attributes()
+	if err != nil {
+		return err
+	}
+	for path, value := range attributes {
+		if err := g.applyAttribute(kind, path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Group) applyAttribute(kind, path string, value json.RawMessage) error {
+	remove := kind == PatchOpRemove
+	lower := strings.ToLower(path)
+
+	switch lower {
+	case "displayname":
+		if remove {
+			return NewError(http.StatusBadRequest, ErrorTypeMutability, "displayName is required")
+		}
+		return unmarshalValue(path, value, &g.DisplayName)
+	case "externalid":
+		if remove {
+			g.ExternalID = ""
+			return nil
+		}
+		return unmarshalValue(path, value, &g.ExternalID)
+	case "members":
+		var members []Member
+		if len(value) > 0 {
+			if err := unmarshalValue(path, value, &members); err != nil {
+				return err
+			}
+		}
+		switch kind {
+		case PatchOpAdd:
+			for _, member := range members {
+				if !g.hasMember(member.Value) {
+					g.Members = append(g.Members, member)
+				}
+			}
+		case PatchOpReplace:
+			g.Members = members
+		case PatchOpRemove:
+			if len(value) == 0 {
+				g.Members = nil
+			}
+			for _, member := range members {
+				g.removeMember(member.Value)
+			}
+		}
+		return nil
+	}
+
+	// a path like `members[value eq "2"]` removes a member
+	if remove && strings.HasPrefix(lower, "members[") && strings.HasSuffix(lower, "]") {
+		filter, err := ParseFilter(path[len("members[") : len(path)-1])
+		if err != nil || filter == nil || filter.Attribute != "value" {
+			return NewError(http.StatusBadRequest, ErrorTypeInvalidPath, "unsupported path %q", path)
+		}
+		g.removeMember(filter.Value)
+		return nil
+	}
+	return NewError(http.StatusBadRequest, ErrorTypeInvalidPath, "unsupported path %q", path)
+}
+
+func (g *Group) hasMember(value string) bool {
+	for _, member := range g.Members {
+		if member.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Group) removeMember(value string) {
+	members := g.Members[:0]
+	for _, member := range g.Members {
+		if member.Value != value {
+			members = append(members, member)
+		}
+	}
+	g.Members = members
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(`userName eq "jdoe"`)
+	assert.NoError(t, err)
+	assert.Equal(t, &Filter{Attribute: "username", Value: "jdoe"}, filter)
+
+	filter, err = ParseFilter(`displayName EQ "org3/team \"1\""`)
+	assert.NoError(t, err)
+	assert.Equal(t, &Filter{Attribute: "displayname", Value: `org3/team "1"`}, filter)
+
+	filter, err = ParseFilter(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+
+	for _, invalid := range []string{`userName sw "j"`, `userName eq jdoe`, `userName pr`} {
+		_, err = ParseFilter(invalid)
+		if assert.Error(t, err, invalid) {
+			assert.Equal(t, http.StatusBadRequest, err.(*Error).StatusCode())
+			assert.Equal(t, ErrorTypeInvalidFilter, err.(*Error).ScimType)
+		}
+	}
+}
+
+func TestUser(t *testing.T) {
+	u := &User{Name: &Name{GivenName: "John", FamilyName: "Doe"}}
+	assert.Equal(t, "John Doe", u.FullName())
+	u.Name.Formatted = "John R. Doe"
+	assert.Equal(t, "John R. Doe", u.FullName())
+	u.DisplayName = "jd"
+	assert.Equal(t, "jd", u.FullName())
+
+	assert.Empty(t, u.PrimaryEmail())
+	u.Emails = []Email{{Value: "home@example.com"}, {Value: "work@example.com", Primary: true}}
+	assert.Equal(t, "work@example.com", u.PrimaryEmail())
+	u.Emails = u.Emails[:1]
+	assert.Equal(t, "home@example.com", u.PrimaryEmail())
+
+	assert.True(t, u.IsActive())
+	active := false
+	u.Active = &active
+	assert.False(t, u.IsActive())
+}
+
+func TestUser_ApplyPatch(t *testing.T) {
+	active := true
+	u := &User{
+		UserName: "jdoe",
+		Emails:   []Email{{Value: "jdoe@example.com", Type: "work", Primary: true}},
+		Active:   &active,
+	}
+
+	var req PatchRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "john@example.com"},
+			{"op": "add", "value": {"displayName": "John Doe", "name.givenName": "John", "title": "ignored"}},
+			{"op": "replace", "path": "phoneNumbers[type eq \"work\"].value", "value": "ignored"}
+		]
+	}`), &req))
+	for _, op := range req.Operations {
+		assert.NoError(t, u.ApplyPatch(op))
+	}
+	assert.False(t, u.IsActive())
+	assert.Equal(t, "john@example.com", u.PrimaryEmail())
+	assert.Equal(t, "John Doe", u.DisplayName)
+	assert.Equal(t, "John", u.Name.GivenName)
+
+	assert.NoError(t, u.ApplyPatch(PatchOperation{Op: "remove", Path: "displayName"}))
+	assert.Equal(t, "John", u.FullName())
+
+	assert.Error(t, u.ApplyPatch(PatchOperation{Op: "remove", Path: "userName"}))
+	assert.Error(t, u.ApplyPatch(PatchOperation{Op: "remove"}))
+	assert.Error(t, u.ApplyPatch(PatchOperation{Op: "move", Path: "userName"}))
+	assert.Error(t, u.ApplyPatch(PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}))
+}
+
+func TestGroup_ApplyPatch(t *testing.T) {
+	g := &Group{DisplayName: "org3/team1", Members: []Member{{Value: "2"}}}
+
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "2"}, {"value": "4"}, {"value": "5"}]`)}))
+	assert.Equal(t, []Member{{Value: "2"}, {Value: "4"}, {Value: "5"}}, g.Members)
+
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "remove", Path: `members[value eq "2"]`}))
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value": "4"}]`)}))
+	assert.Equal(t, []Member{{Value: "5"}}, g.Members)
+
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "replace", Value: json.RawMessage(`{"displayName": "org3/team2"}`)}))
+	assert.Equal(t, "org3/team2", g.DisplayName)
+
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "replace", Path: "members", Value: json.RawMessage(`[{"value": "2"}]`)}))
+	assert.Equal(t, []Member{{Value: "2"}}, g.Members)
+
+	assert.NoError(t, g.ApplyPatch(PatchOperation{Op: "remove", Path: "members"}))
+	assert.Empty(t, g.Members)
+
+	assert.Error(t, g.ApplyPatch(PatchOperation{Op: "replace", Path: "description", Value: json.RawMessage(`"x"`)}))
+	assert.Error(t, g.ApplyPatch(PatchOperation{Op: "remove", Path: "displayName"}))
+}
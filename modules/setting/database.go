@@ -46,6 +46,12 @@ var (
 		MaxOpenConns      int
 		ConnMaxLifetime   time.Duration
 		IterateBufferSize int
+		IDGenerator       string
+		SnowflakeNodeID   int64
+
+		ReplicaDSN           string
+		ReplicaMaxLag        time.Duration
+		ReplicaCheckInterval time.Duration
 	}{
 		Timeout:           500,
 		IterateBufferSize: 50,
@@ -101,6 +107,12 @@ func InitDBConfig() {
 	Database.LogSQL = sec.Key("LOG_SQL").MustBool(true)
 	Database.DBConnectRetries = sec.Key("DB_RETRIES").MustInt(10)
 	Database.DBConnectBackoff = sec.Key("DB_RETRY_BACKOFF").MustDuration(3 * time.Second)
+	Database.IDGenerator = sec.Key("ID_GENERATOR").In("autoincrement", []string{"autoincrement", "snowflake"})
+	Database.SnowflakeNodeID = sec.Key("SNOWFLAKE_NODE_ID").MustInt64(0)
+
+	Database.ReplicaDSN = sec.Key("REPLICA_DSN").String()
+	Database.ReplicaMaxLag = sec.Key("REPLICA_MAX_LAG").MustDuration(10 * time.Second)
+	Database.ReplicaCheckInterval = sec.Key("REPLICA_CHECK_INTERVAL").MustDuration(10 * time.Second)
 }
 
 // DBConnStr returns database connection string
@@ -45,6 +45,9 @@ var (
 		IncludePatterns    []glob.Glob
 		ExcludePatterns    []glob.Glob
 		ExcludeVendored    bool
+
+		SymbolIndexerEnabled   bool
+		SymbolIndexerCtagsPath string
 	}{
 		IssueType:             "bleve",
 		IssuePath:             "indexers/issues.bleve",
@@ -95,6 +98,9 @@ func newIndexerService() {
 	Indexer.UpdateQueueLength = sec.Key("UPDATE_BUFFER_LEN").MustInt(20)
 	Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(1024 * 1024)
 	Indexer.StartupTimeout = sec.Key("STARTUP_TIMEOUT").MustDuration(30 * time.Second)
+
+	Indexer.SymbolIndexerEnabled = sec.Key("SYMBOL_INDEXER_ENABLED").MustBool(false)
+	Indexer.SymbolIndexerCtagsPath = sec.Key("SYMBOL_INDEXER_CTAGS_PATH").MustString("")
 }
 
 // IndexerGlobFromString parses a comma separated list of patterns and returns a glob.Glob slice suited for repo indexing
@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "code.gitea.io/gitea/modules/log"
+
+// Legal settings
+var (
+	Legal = struct {
+		Pages             []string
+		RequireAcceptance bool
+	}{
+		Pages:             []string{"terms", "privacy"},
+		RequireAcceptance: false,
+	}
+)
+
+func newLegal() {
+	if err := Cfg.Section("legal").MapTo(&Legal); err != nil {
+		log.Fatal("Failed to map Legal settings: %v", err)
+	}
+}
@@ -27,9 +27,19 @@ var LFS = struct {
 	MaxFileSize     int64         `ini:"LFS_MAX_FILE_SIZE"`
 	LocksPagingNum  int           `ini:"LFS_LOCKS_PAGING_NUM"`
 
+	// DirectUpload lets the clients upload the objects to the storage with presigned URLs
+	DirectUpload      bool  `ini:"-"`
+	MultipartPartSize int64 `ini:"-"`
+
+	// MaxPreviewSize is the maximum size of the objects which are previewed in the diffs and file views
+	MaxPreviewSize int64 `ini:"-"`
+
 	Storage
 }{}
 
+// LFSMinMultipartPartSize is the minimum size of the parts of a multipart upload accepted by S3
+const LFSMinMultipartPartSize = 5 * 1024 * 1024
+
 func newLFSService() {
 	sec := Cfg.Section("server")
 	if err := sec.MapTo(&LFS); err != nil {
@@ -44,6 +54,13 @@ func newLFSService() {
 		sec.Key("LFS_CONTENT_PATH").String())
 
 	LFS.Storage = getStorage("lfs", storageType, lfsSec)
+	LFS.DirectUpload = lfsSec.Key("DIRECT_UPLOAD").MustBool(false)
+	LFS.MultipartPartSize = lfsSec.Key("MULTIPART_PART_SIZE").MustInt64(64 * 1024 * 1024)
+	if LFS.MultipartPartSize < LFSMinMultipartPartSize {
+		log.Warn("[lfs] MULTIPART_PART_SIZE %d is below the minimum part size, using %d", LFS.MultipartPartSize, LFSMinMultipartPartSize)
+		LFS.MultipartPartSize = LFSMinMultipartPartSize
+	}
+	LFS.MaxPreviewSize = lfsSec.Key("MAX_PREVIEW_SIZE").MustInt64(8 * 1024 * 1024)
 
 	// Rest of LFS service settings
 	if LFS.LocksPagingNum == 0 {
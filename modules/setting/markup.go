@@ -5,6 +5,7 @@
 package setting
 
 import (
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -17,19 +18,37 @@ import (
 var (
 	ExternalMarkupParsers  []MarkupParser
 	ExternalSanitizerRules []MarkupSanitizerRule
+	// ExternalSanitizerURLSchemes are the URL schemes allowed in links in addition to the default ones
+	ExternalSanitizerURLSchemes []string
+	// MarkupRenderOrigin is the origin, e.g. "https://render.example.com", serving the output of the external
+	// parsers shown in iframes instead of the origin of ROOT_URL, or empty
+	MarkupRenderOrigin string
+)
+
+// DataAttributesWildcard whitelists all the data-* attributes when used as ALLOW_ATTR
+const DataAttributesWildcard = "data-*"
+
+// The modes the output of an external parser can be displayed in
+const (
+	// RenderContentModeSanitized embeds the sanitized output in the pages
+	RenderContentModeSanitized = "sanitized"
+	// RenderContentModeIframe shows the output as is in a sandboxed iframe
+	RenderContentModeIframe = "iframe"
 )
 
 // MarkupParser defines the external parser configured in ini
 type MarkupParser struct {
-	Enabled        bool
-	MarkupName     string
-	Command        string
-	FileExtensions []string
-	IsInputFile    bool
+	Enabled           bool
+	MarkupName        string
+	Command           string
+	FileExtensions    []string
+	IsInputFile       bool
+	Cache             bool
+	RenderContentMode string
 }
 
 // MarkupSanitizerRule defines the policy for whitelisting attributes on
-// certain elements.
+// certain elements. An empty AllowAttr whitelists the element without attributes.
 type MarkupSanitizerRule struct {
 	Element   string
 	AllowAttr string
@@ -37,6 +56,8 @@ type MarkupSanitizerRule struct {
 }
 
 func newMarkup() {
+	MarkupRenderOrigin = newMarkupRenderOrigin(Cfg.Section("markup").Key("RENDER_ORIGIN").String())
+
 	for _, sec := range Cfg.Section("markup").ChildSections() {
 		name := strings.TrimPrefix(sec.Name(), "markup.")
 		if name == "" {
@@ -52,51 +73,129 @@ func newMarkup() {
 	}
 }
 
+// newMarkupRenderOrigin returns the origin of the RENDER_ORIGIN URL, its host name must differ from the one of
+// ROOT_URL as the cookies are shared by all the ports of a host
+func newMarkupRenderOrigin(renderURL string) string {
+	renderURL = strings.TrimSpace(renderURL)
+	if len(renderURL) == 0 {
+		return ""
+	}
+	u, err := url.Parse(renderURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		log.Error("In markup: invalid RENDER_ORIGIN %q, the rendered documents will be served from ROOT_URL", renderURL)
+		return ""
+	}
+	if appURL, err := url.Parse(AppURL); err == nil && strings.EqualFold(appURL.Hostname(), u.Hostname()) {
+		log.Error("In markup: RENDER_ORIGIN %q must use another host name than ROOT_URL, the rendered documents will be served from ROOT_URL", renderURL)
+		return ""
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host)
+}
+
+var (
+	sanitizerNameReg   = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	sanitizerSchemeReg = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+	// elements and attributes which would allow to run scripts or to change the
+	// whole page, they can't be whitelisted through the configuration
+	sanitizerForbiddenElements = []string{"applet", "base", "embed", "frame", "frameset", "iframe",
+		"link", "meta", "object", "script", "style", "template"}
+	sanitizerForbiddenAttrs   = []string{"formaction", "srcdoc", "style"}
+	sanitizerForbiddenSchemes = []string{"javascript", "vbscript", "data"}
+)
+
+func validSanitizerValue(value string, reg *regexp.Regexp, forbidden []string) bool {
+	if !reg.MatchString(value) {
+		return false
+	}
+	for _, f := range forbidden {
+		if value == f {
+			return false
+		}
+	}
+	return true
+}
+
 func newMarkupSanitizer(name string, sec *ini.Section) {
 	haveElement := sec.HasKey("ELEMENT")
 	haveAttr := sec.HasKey("ALLOW_ATTR")
 	haveRegexp := sec.HasKey("REGEXP")
+	haveSchemes := sec.HasKey("ALLOW_URL_SCHEMES")
 
-	if !haveElement && !haveAttr && !haveRegexp {
+	if !haveElement && !haveAttr && !haveRegexp && !haveSchemes {
 		log.Warn("Skipping empty section: markup.%s.", name)
 		return
 	}
 
-	if !haveElement || !haveAttr || !haveRegexp {
-		log.Error("Missing required keys from markup.%s. Must have all three of ELEMENT, ALLOW_ATTR, and REGEXP defined!", name)
-		return
+	schemes := sec.Key("ALLOW_URL_SCHEMES").Strings(",")
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(scheme)
+		if !validSanitizerValue(scheme, sanitizerSchemeReg, sanitizerForbiddenSchemes) {
+			log.Error("In markup.%s: ALLOW_URL_SCHEMES contains the invalid or forbidden scheme %q, the section is ignored", name, scheme)
+			return
+		}
 	}
 
-	elements := sec.Key("ELEMENT").Value()
-	allowAttrs := sec.Key("ALLOW_ATTR").Value()
-	regexpStr := sec.Key("REGEXP").Value()
-
-	if regexpStr == "" {
-		rule := MarkupSanitizerRule{
-			Element:   elements,
-			AllowAttr: allowAttrs,
-			Regexp:    nil,
+	if !haveElement {
+		if haveAttr || haveRegexp {
+			log.Error("Missing required key ELEMENT from markup.%s!", name)
+			return
 		}
-
-		ExternalSanitizerRules = append(ExternalSanitizerRules, rule)
+		ExternalSanitizerURLSchemes = append(ExternalSanitizerURLSchemes, schemes...)
 		return
 	}
 
-	// Validate when parsing the config that this is a valid regular
-	// expression. Then we can use regexp.MustCompile(...) later.
-	compiled, err := regexp.Compile(regexpStr)
-	if err != nil {
-		log.Error("In module.%s: REGEXP (%s) at definition %d failed to compile: %v", regexpStr, name, err)
+	elements := sec.Key("ELEMENT").Strings(",")
+	if len(elements) == 0 {
+		log.Error("In markup.%s: ELEMENT must not be empty!", name)
 		return
 	}
+	for i, element := range elements {
+		elements[i] = strings.ToLower(element)
+		if !validSanitizerValue(elements[i], sanitizerNameReg, sanitizerForbiddenElements) {
+			log.Error("In markup.%s: ELEMENT contains the invalid or forbidden element %q, the section is ignored", name, element)
+			return
+		}
+	}
 
-	rule := MarkupSanitizerRule{
-		Element:   elements,
-		AllowAttr: allowAttrs,
-		Regexp:    compiled,
+	allowAttrs := sec.Key("ALLOW_ATTR").Strings(",")
+	for i, attr := range allowAttrs {
+		allowAttrs[i] = strings.ToLower(attr)
+		if allowAttrs[i] == DataAttributesWildcard {
+			continue
+		}
+		if !validSanitizerValue(allowAttrs[i], sanitizerNameReg, sanitizerForbiddenAttrs) || strings.HasPrefix(allowAttrs[i], "on") {
+			log.Error("In markup.%s: ALLOW_ATTR contains the invalid or forbidden attribute %q, the section is ignored", name, attr)
+			return
+		}
 	}
 
-	ExternalSanitizerRules = append(ExternalSanitizerRules, rule)
+	var compiled *regexp.Regexp
+	if regexpStr := sec.Key("REGEXP").Value(); regexpStr != "" {
+		// Validate when parsing the config that this is a valid regular
+		// expression. Then we can use regexp.MustCompile(...) later.
+		var err error
+		compiled, err = regexp.Compile(regexpStr)
+		if err != nil {
+			log.Error("In markup.%s: REGEXP (%s) failed to compile: %v", name, regexpStr, err)
+			return
+		}
+	}
+
+	// An element without attributes is whitelisted on its own
+	if len(allowAttrs) == 0 {
+		allowAttrs = []string{""}
+	}
+	for _, element := range elements {
+		for _, attr := range allowAttrs {
+			ExternalSanitizerRules = append(ExternalSanitizerRules, MarkupSanitizerRule{
+				Element:   element,
+				AllowAttr: attr,
+				Regexp:    compiled,
+			})
+		}
+	}
+	ExternalSanitizerURLSchemes = append(ExternalSanitizerURLSchemes, schemes...)
 }
 
 func newMarkupRenderer(name string, sec *ini.Section) {
@@ -123,11 +222,19 @@ func newMarkupRenderer(name string, sec *ini.Section) {
 		return
 	}
 
+	renderContentMode := sec.Key("RENDER_CONTENT_MODE").MustString(RenderContentModeSanitized)
+	if renderContentMode != RenderContentModeSanitized && renderContentMode != RenderContentModeIframe {
+		log.Error("In markup.%s: unknown RENDER_CONTENT_MODE %q, the output will be sanitized", name, renderContentMode)
+		renderContentMode = RenderContentModeSanitized
+	}
+
 	ExternalMarkupParsers = append(ExternalMarkupParsers, MarkupParser{
-		Enabled:        sec.Key("ENABLED").MustBool(false),
-		MarkupName:     name,
-		FileExtensions: exts,
-		Command:        command,
-		IsInputFile:    sec.Key("IS_INPUT_FILE").MustBool(false),
+		Enabled:           sec.Key("ENABLED").MustBool(false),
+		MarkupName:        name,
+		FileExtensions:    exts,
+		Command:           command,
+		IsInputFile:       sec.Key("IS_INPUT_FILE").MustBool(false),
+		Cache:             sec.Key("CACHE").MustBool(false),
+		RenderContentMode: renderContentMode,
 	})
 }
@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func Test_newMarkupSanitizer(t *testing.T) {
+	cfg, err := ini.Load([]byte(`
+[markup.sanitizer.video]
+ELEMENT = video, audio
+ALLOW_ATTR = src, controls
+
+[markup.sanitizer.data]
+ELEMENT = div
+ALLOW_ATTR = data-*
+
+[markup.sanitizer.TeX]
+ELEMENT = span
+ALLOW_ATTR = class
+REGEXP = ^math$
+
+[markup.sanitizer.schemes]
+ALLOW_URL_SCHEMES = matrix, xmpp
+
+[markup.sanitizer.script]
+ELEMENT = script
+
+[markup.sanitizer.handler]
+ELEMENT = img
+ALLOW_ATTR = onerror
+
+[markup.sanitizer.scheme]
+ALLOW_URL_SCHEMES = javascript
+
+[markup.sanitizer.regexp]
+ELEMENT = span
+ALLOW_ATTR = class
+REGEXP = ^(math$
+`))
+	assert.NoError(t, err)
+
+	oldCfg, oldRules, oldSchemes := Cfg, ExternalSanitizerRules, ExternalSanitizerURLSchemes
+	defer func() {
+		Cfg, ExternalSanitizerRules, ExternalSanitizerURLSchemes = oldCfg, oldRules, oldSchemes
+	}()
+	Cfg, ExternalSanitizerRules, ExternalSanitizerURLSchemes = cfg, nil, nil
+
+	newMarkup()
+
+	if assert.Len(t, ExternalSanitizerRules, 6) {
+		assert.Equal(t, "video", ExternalSanitizerRules[0].Element)
+		assert.Equal(t, "src", ExternalSanitizerRules[0].AllowAttr)
+		assert.Equal(t, "video", ExternalSanitizerRules[1].Element)
+		assert.Equal(t, "controls", ExternalSanitizerRules[1].AllowAttr)
+		assert.Equal(t, "audio", ExternalSanitizerRules[2].Element)
+		assert.Equal(t, "audio", ExternalSanitizerRules[3].Element)
+		assert.Equal(t, DataAttributesWildcard, ExternalSanitizerRules[4].AllowAttr)
+		assert.Equal(t, "span", ExternalSanitizerRules[5].Element)
+		assert.Equal(t, "^math$", ExternalSanitizerRules[5].Regexp.String())
+	}
+	assert.Equal(t, []string{"matrix", "xmpp"}, ExternalSanitizerURLSchemes)
+}
+
+func Test_newMarkupRenderer(t *testing.T) {
+	cfg, err := ini.Load([]byte(`
+[markup.asciidoc]
+ENABLED = true
+FILE_EXTENSIONS = .adoc
+RENDER_COMMAND = asciidoc --out-file=- -
+
+[markup.jupyter]
+ENABLED = true
+FILE_EXTENSIONS = .ipynb
+RENDER_COMMAND = jupyter nbconvert --stdout --to html
+IS_INPUT_FILE = true
+CACHE = true
+RENDER_CONTENT_MODE = iframe
+
+[markup.unknown]
+ENABLED = true
+FILE_EXTENSIONS = .unknown
+RENDER_COMMAND = cat
+RENDER_CONTENT_MODE = raw
+`))
+	assert.NoError(t, err)
+
+	oldCfg, oldParsers := Cfg, ExternalMarkupParsers
+	defer func() {
+		Cfg, ExternalMarkupParsers = oldCfg, oldParsers
+	}()
+	Cfg, ExternalMarkupParsers = cfg, nil
+
+	newMarkup()
+
+	if assert.Len(t, ExternalMarkupParsers, 3) {
+		assert.False(t, ExternalMarkupParsers[0].Cache)
+		assert.Equal(t, RenderContentModeSanitized, ExternalMarkupParsers[0].RenderContentMode)
+		assert.True(t, ExternalMarkupParsers[1].Cache)
+		assert.Equal(t, RenderContentModeIframe, ExternalMarkupParsers[1].RenderContentMode)
+		assert.Equal(t, RenderContentModeSanitized, ExternalMarkupParsers[2].RenderContentMode)
+	}
+}
+
+func Test_newMarkupRenderOrigin(t *testing.T) {
+	oldAppURL := AppURL
+	defer func() {
+		AppURL = oldAppURL
+	}()
+	AppURL = "https://gitea.example.com/"
+
+	assert.Equal(t, "", newMarkupRenderOrigin(""))
+	assert.Equal(t, "https://render.example.com", newMarkupRenderOrigin("https://Render.example.com/some/path"))
+	assert.Equal(t, "http://render.example.com:3001", newMarkupRenderOrigin("http://render.example.com:3001"))
+	assert.Equal(t, "", newMarkupRenderOrigin("https://gitea.example.com:3001"))
+	assert.Equal(t, "", newMarkupRenderOrigin("https://gitea.example.com/render"))
+	assert.Equal(t, "", newMarkupRenderOrigin("render.example.com"))
+	assert.Equal(t, "", newMarkupRenderOrigin("javascript://render.example.com"))
+}
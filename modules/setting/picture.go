@@ -6,9 +6,12 @@ package setting
 
 import (
 	"net/url"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 
+	ini "gopkg.in/ini.v1"
 	"strk.kbt.io/projects/go/libravatar"
 )
 
@@ -18,13 +21,18 @@ var (
 	Avatar = struct {
 		Storage
 
-		MaxWidth    int
-		MaxHeight   int
-		MaxFileSize int64
+		MaxWidth         int
+		MaxHeight        int
+		MaxFileSize      int64
+		IdenticonTheme   string
+		Providers        []string
+		DirectoryURL     string
+		DirectoryTimeout time.Duration
 	}{
-		MaxWidth:    4096,
-		MaxHeight:   3072,
-		MaxFileSize: 1048576,
+		MaxWidth:         4096,
+		MaxHeight:        3072,
+		MaxFileSize:      1048576,
+		DirectoryTimeout: 5 * time.Second,
 	}
 
 	GravatarSource        string
@@ -55,6 +63,7 @@ func newPictureService() {
 	Avatar.MaxWidth = sec.Key("AVATAR_MAX_WIDTH").MustInt(4096)
 	Avatar.MaxHeight = sec.Key("AVATAR_MAX_HEIGHT").MustInt(3072)
 	Avatar.MaxFileSize = sec.Key("AVATAR_MAX_FILE_SIZE").MustInt64(1048576)
+	Avatar.IdenticonTheme = sec.Key("IDENTICON_THEME").In("random", []string{"random", "pastel", "monochrome", "gitea"})
 
 	switch source := sec.Key("GRAVATAR_SOURCE").MustString("gravatar"); source {
 	case "duoshuo":
@@ -68,10 +77,7 @@ func newPictureService() {
 	}
 	DisableGravatar = sec.Key("DISABLE_GRAVATAR").MustBool()
 	EnableFederatedAvatar = sec.Key("ENABLE_FEDERATED_AVATAR").MustBool(!InstallLock)
-	if OfflineMode {
-		DisableGravatar = true
-		EnableFederatedAvatar = false
-	}
+	newAvatarProviders(sec)
 	if DisableGravatar {
 		EnableFederatedAvatar = false
 	}
@@ -98,6 +104,46 @@ func newPictureService() {
 	newRepoAvatarService()
 }
 
+// newAvatarProviders reads the providers of the avatars of the users, tried in order until one has the avatar of
+// the email address of the user, the avatars stored by Gitea are used when none has it. Gravatar is disabled
+// when it isn't listed.
+func newAvatarProviders(sec *ini.Section) {
+	defaultProviders := "gravatar"
+	if DisableGravatar {
+		defaultProviders = "internal"
+	}
+	Avatar.Providers = make([]string, 0, 3)
+	for _, name := range strings.Split(sec.Key("AVATAR_PROVIDERS").MustString(defaultProviders), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "gravatar", "directory", "internal":
+		default:
+			log.Fatal("Unknown avatar provider: %s", name)
+		}
+		// the external providers can't be reached in offline mode
+		if OfflineMode && name != "internal" {
+			continue
+		}
+		Avatar.Providers = append(Avatar.Providers, name)
+	}
+
+	Avatar.DirectoryURL = sec.Key("AVATAR_DIRECTORY_URL").MustString("")
+	Avatar.DirectoryTimeout = sec.Key("AVATAR_DIRECTORY_TIMEOUT").MustDuration(Avatar.DirectoryTimeout)
+	DisableGravatar = true
+	for _, name := range Avatar.Providers {
+		switch name {
+		case "gravatar":
+			DisableGravatar = false
+		case "directory":
+			if len(Avatar.DirectoryURL) == 0 {
+				log.Fatal("AVATAR_DIRECTORY_URL is required by the directory avatar provider")
+			}
+		}
+	}
+}
+
 func newRepoAvatarService() {
 	sec := Cfg.Section("picture")
 
@@ -0,0 +1,23 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "code.gitea.io/gitea/modules/log"
+
+// Quota settings
+var Quota = struct {
+	Enabled bool
+	// DefaultMaxSize is the storage quota in bytes of the users and organizations without their own, -1 is unlimited
+	DefaultMaxSize int64
+}{
+	Enabled:        false,
+	DefaultMaxSize: -1,
+}
+
+func newQuotaService() {
+	if err := Cfg.Section("quota").MapTo(&Quota); err != nil {
+		log.Fatal("Failed to map Quota settings: %v", err)
+	}
+}
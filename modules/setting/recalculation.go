@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "code.gitea.io/gitea/modules/log"
+
+// Recalculation settings
+var (
+	Recalculation = struct {
+		BatchSize      int
+		ItemsPerSecond float64
+	}{
+		BatchSize:      50,
+		ItemsPerSecond: 10,
+	}
+)
+
+func newRecalculation() {
+	if err := Cfg.Section("recalculation").MapTo(&Recalculation); err != nil {
+		log.Fatal("Failed to map Recalculation settings: %v", err)
+	}
+	if Recalculation.BatchSize <= 0 {
+		Recalculation.BatchSize = 50
+	}
+}
@@ -89,8 +89,14 @@ var (
 			AllowedTypes string
 		} `ini:"repository.release"`
 
+		Compare struct {
+			AllowUnrelatedRepos bool
+			MaxCommits          int
+		} `ini:"repository.compare"`
+
 		Signing struct {
 			SigningKey        string
+			Format            string
 			SigningName       string
 			SigningEmail      string
 			InitialCommit     []string
@@ -223,9 +229,19 @@ var (
 			AllowedTypes: "",
 		},
 
+		// Compare settings
+		Compare: struct {
+			AllowUnrelatedRepos bool
+			MaxCommits          int
+		}{
+			AllowUnrelatedRepos: true,
+			MaxCommits:          250,
+		},
+
 		// Signing settings
 		Signing: struct {
 			SigningKey        string
+			Format            string
 			SigningName       string
 			SigningEmail      string
 			InitialCommit     []string
@@ -235,6 +251,7 @@ var (
 			DefaultTrustModel string
 		}{
 			SigningKey:        "default",
+			Format:            "openpgp",
 			SigningName:       "",
 			SigningEmail:      "",
 			InitialCommit:     []string{"always"},
@@ -291,6 +308,7 @@ func newRepository() {
 	if Repository.Signing.DefaultTrustModel == "default" {
 		Repository.Signing.DefaultTrustModel = "collaborator"
 	}
+	newSigningBackend()
 
 	// Handle preferred charset orders
 	preferred := make([]string, 0, len(Repository.DetectedCharsetsOrder))
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "code.gitea.io/gitea/modules/log"
+
+// SCIM settings
+var SCIM = struct {
+	Enabled bool
+	// DryRun validates and logs the changes requested by the identity providers without applying them
+	DryRun bool
+	// MaxResults is the maximum number of resources returned by a list request
+	MaxResults int
+}{
+	Enabled:    false,
+	DryRun:     false,
+	MaxResults: 100,
+}
+
+func newSCIMService() {
+	if err := Cfg.Section("scim").MapTo(&SCIM); err != nil {
+		log.Fatal("Failed to map SCIM settings: %v", err)
+	}
+	if SCIM.MaxResults <= 0 {
+		SCIM.MaxResults = 100
+	}
+}
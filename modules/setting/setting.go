@@ -158,6 +158,7 @@ var (
 	PasswordComplexity                 []string
 	PasswordHashAlgo                   string
 	PasswordCheckPwn                   bool
+	StaleCredentialDays                int
 
 	// UI settings
 	UI = struct {
@@ -321,6 +322,8 @@ var (
 		AccessTokenExpirationTime  int64
 		RefreshTokenExpirationTime int64
 		InvalidateRefreshTokens    bool
+		DeviceCodeExpirationTime   int64
+		DeviceCodePollingInterval  int64
 		JWTSecretBytes             []byte `ini:"-"`
 		JWTSecretBase64            string `ini:"JWT_SECRET"`
 		MaxTokenLength             int
@@ -329,6 +332,8 @@ var (
 		AccessTokenExpirationTime:  3600,
 		RefreshTokenExpirationTime: 730,
 		InvalidateRefreshTokens:    false,
+		DeviceCodeExpirationTime:   900,
+		DeviceCodePollingInterval:  5,
 		MaxTokenLength:             math.MaxInt16,
 	}
 
@@ -785,6 +790,7 @@ func NewContext() {
 	PasswordHashAlgo = sec.Key("PASSWORD_HASH_ALGO").MustString("argon2")
 	CSRFCookieHTTPOnly = sec.Key("CSRF_COOKIE_HTTP_ONLY").MustBool(true)
 	PasswordCheckPwn = sec.Key("PASSWORD_CHECK_PWN").MustBool(false)
+	StaleCredentialDays = sec.Key("STALE_CREDENTIAL_DAYS").MustInt(90)
 
 	InternalToken = loadInternalToken(sec)
 
@@ -802,6 +808,7 @@ func NewContext() {
 
 	newAttachmentService()
 	newLFSService()
+	newWebhookArchiveStorage()
 
 	timeFormatKey := Cfg.Section("time").Key("FORMAT").MustString("")
 	if timeFormatKey != "" {
@@ -1057,4 +1064,10 @@ func NewServices() {
 	newTaskService()
 	NewQueueService()
 	newProject()
+	newLegal()
+	newRecalculation()
+	newQuotaService()
+	newAuditService()
+	newSCIMService()
+	newTwoFactorService()
 }
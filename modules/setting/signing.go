@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// SigningBackend settings of the backend holding the instance signing key, the key is held by
+// gpg unless an external backend is configured
+var (
+	SigningBackend = struct {
+		// Type is one of gpg, vault, awskms or command
+		Type string
+		// KeyCreatedUnix is the creation time of the OpenPGP key wrapping the external key, its
+		// fingerprint depends on it
+		KeyCreatedUnix int64
+
+		VaultAddress string
+		VaultToken   string
+		VaultMount   string
+		VaultKey     string
+
+		AWSKMSKeyID        string `ini:"AWS_KMS_KEY_ID"`
+		AWSRegion          string `ini:"AWS_REGION"`
+		AWSEndpoint        string `ini:"AWS_ENDPOINT"`
+		AWSAccessKeyID     string `ini:"AWS_ACCESS_KEY_ID"`
+		AWSSecretAccessKey string `ini:"AWS_SECRET_ACCESS_KEY"`
+		AWSSessionToken    string `ini:"AWS_SESSION_TOKEN"`
+
+		Command          string
+		CommandPublicKey string
+	}{
+		Type:       "gpg",
+		VaultMount: "transit",
+	}
+)
+
+func newSigningBackend() {
+	if err := Cfg.Section("repository.signing.backend").MapTo(&SigningBackend); err != nil {
+		log.Fatal("Failed to map Repository.Signing.Backend settings: %v", err)
+	}
+	SigningBackend.Type = strings.ToLower(strings.TrimSpace(SigningBackend.Type))
+	switch SigningBackend.Type {
+	case "", "gpg":
+		SigningBackend.Type = "gpg"
+	case "vault", "awskms", "command":
+	default:
+		log.Fatal("Unknown signing backend: %s", SigningBackend.Type)
+	}
+
+	// the instance signing key is an OpenPGP key held by gpg or a backend, or an SSH key file
+	Repository.Signing.Format = strings.ToLower(strings.TrimSpace(Repository.Signing.Format))
+	switch Repository.Signing.Format {
+	case "", "openpgp":
+		Repository.Signing.Format = "openpgp"
+	case "ssh":
+		if SigningBackend.Type != "gpg" {
+			log.Fatal("The %s signing backend doesn't hold SSH signing keys", SigningBackend.Type)
+		}
+	default:
+		log.Fatal("Unknown signing key format: %s", Repository.Signing.Format)
+	}
+}
@@ -5,6 +5,7 @@
 package setting
 
 import (
+	"fmt"
 	"path/filepath"
 	"reflect"
 
@@ -77,3 +78,15 @@ func getStorage(name, typ string, overrides ...*ini.Section) Storage {
 
 	return storage
 }
+
+// GetStorage returns the storage configured by the section [storage.name]
+func GetStorage(name string) (Storage, error) {
+	sec, err := Cfg.GetSection("storage." + name)
+	if err != nil {
+		return Storage{}, fmt.Errorf("no [storage.%s] section is configured", name)
+	}
+
+	storage := getStorage(name, "", sec)
+	storage.Type = sec.Key("STORAGE_TYPE").String()
+	return storage, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// TwoFactor settings
+var TwoFactor = struct {
+	// Requirement is the users required to enroll into two-factor authentication: "none", "admins" or "all",
+	// the organizations can also require it from their members
+	Requirement string
+	// GracePeriod is the time the users have to enroll once it's required from them
+	GracePeriod time.Duration
+}{
+	Requirement: "none",
+	GracePeriod: 7 * 24 * time.Hour,
+}
+
+func newTwoFactorService() {
+	sec := Cfg.Section("two_factor")
+	TwoFactor.Requirement = sec.Key("REQUIREMENT").In("none", []string{"none", "admins", "all"})
+	TwoFactor.GracePeriod = sec.Key("GRACE_PERIOD").MustDuration(TwoFactor.GracePeriod)
+	if TwoFactor.GracePeriod < 0 {
+		log.Warn("Invalid two-factor grace period %v, no grace period is given", TwoFactor.GracePeriod)
+		TwoFactor.GracePeriod = 0
+	}
+}
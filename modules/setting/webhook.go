@@ -6,6 +6,7 @@ package setting
 
 import (
 	"net/url"
+	"strings"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -21,13 +22,18 @@ var (
 		ProxyURL       string
 		ProxyURLFixed  *url.URL
 		ProxyHosts     []string
+		// host patterns the webhooks may call, any host if empty
+		AllowedHostList []string
+		// ArchiveStorage is where the deliveries are archived before their deletion
+		ArchiveStorage Storage
 	}{
-		QueueLength:    1000,
-		DeliverTimeout: 5,
-		SkipTLSVerify:  false,
-		PagingNum:      10,
-		ProxyURL:       "",
-		ProxyHosts:     []string{},
+		QueueLength:     1000,
+		DeliverTimeout:  5,
+		SkipTLSVerify:   false,
+		PagingNum:       10,
+		ProxyURL:        "",
+		ProxyHosts:      []string{},
+		AllowedHostList: []string{},
 	}
 )
 
@@ -48,4 +54,13 @@ func newWebhookService() {
 		}
 	}
 	Webhook.ProxyHosts = sec.Key("PROXY_HOSTS").Strings(",")
+	Webhook.AllowedHostList = sec.Key("ALLOWED_HOST_LIST").Strings(",")
+	for i := range Webhook.AllowedHostList {
+		Webhook.AllowedHostList[i] = strings.ToLower(Webhook.AllowedHostList[i])
+	}
+}
+
+func newWebhookArchiveStorage() {
+	sec := Cfg.Section("storage.hook_task_archives")
+	Webhook.ArchiveStorage = getStorage("hook_task_archives", sec.Key("STORAGE_TYPE").MustString(""), sec)
 }
@@ -0,0 +1,194 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// awsKMSSigner signs with an asymmetric key of AWS KMS
+type awsKMSSigner struct {
+	client          *http.Client
+	endpoint        string
+	region          string
+	keyID           string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	public          crypto.PublicKey
+}
+
+var awsKMSHashNames = map[crypto.Hash]string{
+	crypto.SHA256: "SHA_256",
+	crypto.SHA384: "SHA_384",
+	crypto.SHA512: "SHA_512",
+}
+
+func newAWSKMSSigner(endpoint, region, keyID, accessKeyID, secretAccessKey, sessionToken string) (*awsKMSSigner, error) {
+	// the credentials default to the ones of the environment like the AWS tools
+	if len(region) == 0 {
+		region = os.Getenv("AWS_REGION")
+	}
+	if len(accessKeyID) == 0 {
+		accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if len(region) == 0 || len(keyID) == 0 || len(accessKeyID) == 0 || len(secretAccessKey) == 0 {
+		return nil, errors.New("the region, the key ID and the credentials of the awskms backend are required")
+	}
+	if len(endpoint) == 0 {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com/", region)
+	}
+	s := &awsKMSSigner{
+		client:          &http.Client{Timeout: time.Minute},
+		endpoint:        endpoint,
+		region:          region,
+		keyID:           keyID,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+	}
+
+	var result struct {
+		PublicKey []byte
+	}
+	if err := s.do("GetPublicKey", map[string]interface{}{"KeyId": keyID}, &result); err != nil {
+		return nil, err
+	}
+	public, err := x509.ParsePKIXPublicKey(result.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("ParsePKIXPublicKey: %v", err)
+	}
+	s.public = public
+	return s, nil
+}
+
+// do calls an action of the KMS JSON API with a request signed by the AWS signature version 4
+func (s *awsKMSSigner) do(action string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	s.signRequest(req, payload, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var kmsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&kmsErr)
+		return fmt.Errorf("kms %s: %s %s %s", action, resp.Status, kmsErr.Type, kmsErr.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func (s *awsKMSSigner) signRequest(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	signedHeaders := "content-type;host;x-amz-date;x-amz-target"
+	if len(s.sessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		signedHeaders = "content-type;host;x-amz-date;x-amz-security-token;x-amz-target"
+	}
+
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if len(s.sessionToken) > 0 {
+		canonicalHeaders += "x-amz-security-token:" + s.sessionToken + "\n"
+	}
+	canonicalHeaders += "x-amz-target:" + req.Header.Get("X-Amz-Target") + "\n"
+
+	payloadHash := sha256.Sum256(payload)
+	canonicalPath := req.URL.EscapedPath()
+	if len(canonicalPath) == 0 {
+		canonicalPath = "/"
+	}
+	canonicalRequest := req.Method + "\n" + canonicalPath + "\n" + url.Values(req.URL.Query()).Encode() + "\n" +
+		canonicalHeaders + "\n" + signedHeaders + "\n" + hex.EncodeToString(payloadHash[:])
+
+	scope := date + "/" + s.region + "/kms/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "kms")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+// Public returns the public key of the KMS key
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign signs the digest with the KMS key
+func (s *awsKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashName, ok := awsKMSHashNames[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash: %v", opts.HashFunc())
+	}
+	var algorithm string
+	switch s.public.(type) {
+	case *rsa.PublicKey:
+		algorithm = "RSASSA_PKCS1_V1_5_" + hashName
+	case *ecdsa.PublicKey:
+		algorithm = "ECDSA_" + hashName
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %T", s.public)
+	}
+
+	var result struct {
+		Signature []byte
+	}
+	if err := s.do("Sign", map[string]interface{}{
+		"KeyId":            s.keyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": algorithm,
+	}, &result); err != nil {
+		return nil, err
+	}
+	return result.Signature, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"code.gitea.io/gitea/modules/process"
+
+	shellquote "github.com/kballard/go-shellquote"
+)
+
+// commandSigner signs with an external command, e.g. a PKCS#11 tool signing with a key of an HSM
+type commandSigner struct {
+	args   []string
+	public crypto.PublicKey
+}
+
+var commandHashNames = map[crypto.Hash]string{
+	crypto.SHA256: "sha256",
+	crypto.SHA384: "sha384",
+	crypto.SHA512: "sha512",
+}
+
+func newCommandSigner(command, publicKeyPath string) (*commandSigner, error) {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing command: %v", err)
+	}
+	if len(args) == 0 || len(publicKeyPath) == 0 {
+		return nil, errors.New("the command and the public key of the command backend are required")
+	}
+
+	content, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM public key", publicKeyPath)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ParsePKIXPublicKey: %v", err)
+	}
+	return &commandSigner{
+		args:   args,
+		public: public,
+	}, nil
+}
+
+// Public returns the public key of the configured file
+func (s *commandSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign runs the command with the name of the hash as its last argument and the digest as its input, it
+// must output the PKCS #1 v1.5 signature of RSA keys or the ASN.1 signature of ECDSA keys
+func (s *commandSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashName, ok := commandHashNames[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash: %v", opts.HashFunc())
+	}
+	args := append(append([]string{}, s.args[1:]...), hashName)
+	stdout, stderr, err := process.GetManager().ExecDirEnvStdIn(time.Minute, "", "signing command", nil,
+		bytes.NewReader(digest), s.args[0], args...)
+	if err != nil {
+		return nil, fmt.Errorf("signing command: %v - %s", err, stderr)
+	}
+	return []byte(stdout), nil
+}
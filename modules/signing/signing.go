@@ -0,0 +1,184 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+)
+
+var (
+	lock   sync.Mutex
+	entity *openpgp.Entity
+)
+
+// IsExternal returns whether the instance signing key is held by an external backend instead of gpg
+func IsExternal() bool {
+	return setting.SigningBackend.Type != "gpg"
+}
+
+// newSigner returns the signer of the configured external backend
+func newSigner() (crypto.Signer, error) {
+	switch setting.SigningBackend.Type {
+	case "vault":
+		return newVaultSigner(setting.SigningBackend.VaultAddress, setting.SigningBackend.VaultToken,
+			setting.SigningBackend.VaultMount, setting.SigningBackend.VaultKey)
+	case "awskms":
+		return newAWSKMSSigner(setting.SigningBackend.AWSEndpoint, setting.SigningBackend.AWSRegion, setting.SigningBackend.AWSKMSKeyID,
+			setting.SigningBackend.AWSAccessKeyID, setting.SigningBackend.AWSSecretAccessKey, setting.SigningBackend.AWSSessionToken)
+	case "command":
+		return newCommandSigner(setting.SigningBackend.Command, setting.SigningBackend.CommandPublicKey)
+	}
+	return nil, fmt.Errorf("the signing key is not held by an external backend: %s", setting.SigningBackend.Type)
+}
+
+// newEntity returns the OpenPGP key wrapping the key of the signer, its user id is self-signed by the signer
+func newEntity(signer crypto.Signer, created time.Time, name, email string) (*openpgp.Entity, error) {
+	switch signer.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %T", signer.Public())
+	}
+	if len(name) == 0 {
+		name = "Gitea"
+	}
+	uid := packet.NewUserId(name, "", email)
+	if uid == nil {
+		return nil, fmt.Errorf("invalid signing name or email: %s <%s>", name, email)
+	}
+
+	priv := packet.NewSignerPrivateKey(created, signer)
+	isPrimaryID := true
+	selfSignature := &packet.Signature{
+		CreationTime: created,
+		SigType:      packet.SigTypePositiveCert,
+		PubKeyAlgo:   priv.PubKeyAlgo,
+		Hash:         crypto.SHA256,
+		IsPrimaryId:  &isPrimaryID,
+		FlagsValid:   true,
+		FlagSign:     true,
+		FlagCertify:  true,
+		IssuerKeyId:  &priv.KeyId,
+	}
+	if err := selfSignature.SignUserId(uid.Id, &priv.PublicKey, priv, nil); err != nil {
+		return nil, fmt.Errorf("SignUserId: %v", err)
+	}
+
+	return &openpgp.Entity{
+		PrimaryKey: &priv.PublicKey,
+		PrivateKey: priv,
+		Identities: map[string]*openpgp.Identity{
+			uid.Id: {
+				Name:          uid.Id,
+				UserId:        uid,
+				SelfSignature: selfSignature,
+			},
+		},
+	}, nil
+}
+
+// getEntity returns the OpenPGP key wrapping the key of the external backend, the public key is only fetched once
+func getEntity() (*openpgp.Entity, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if entity != nil {
+		return entity, nil
+	}
+	signer, err := newSigner()
+	if err != nil {
+		return nil, err
+	}
+	e, err := newEntity(signer, time.Unix(setting.SigningBackend.KeyCreatedUnix, 0),
+		setting.Repository.Signing.SigningName, setting.Repository.Signing.SigningEmail)
+	if err != nil {
+		return nil, err
+	}
+	entity = e
+	return entity, nil
+}
+
+// KeyID returns the ID of the OpenPGP key wrapping the key of the external backend
+func KeyID() (string, error) {
+	e, err := getEntity()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%X", e.PrimaryKey.KeyId), nil
+}
+
+// PublicKey returns the armored OpenPGP public key wrapping the key of the external backend
+func PublicKey() (string, error) {
+	e, err := getEntity()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := e.Serialize(w); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Sign writes the armored detached OpenPGP signature of the payload by the external backend
+func Sign(payload io.Reader, w io.Writer) error {
+	e, err := getEntity()
+	if err != nil {
+		return err
+	}
+	return openpgp.ArmoredDetachSign(w, e, payload, &packet.Config{DefaultHash: crypto.SHA256})
+}
+
+// Init makes git sign the commits with the external backend by using gitea as its gpg program
+func Init() error {
+	if !IsExternal() {
+		return nil
+	}
+
+	programPath := filepath.Join(setting.AppDataPath, "signing", "gpg-program")
+	if err := os.MkdirAll(filepath.Dir(programPath), os.ModePerm); err != nil {
+		return fmt.Errorf("MkdirAll: %v", err)
+	}
+	program := fmt.Sprintf("#!/usr/bin/env %s\nexec %s signing --config=%s -- \"$@\"\n",
+		setting.ScriptType, util.ShellEscape(setting.AppPath), util.ShellEscape(setting.CustomConf))
+	if err := ioutil.WriteFile(programPath, []byte(program), 0755); err != nil {
+		return fmt.Errorf("WriteFile: %v", err)
+	}
+	git.GlobalCommandArgs = append(git.GlobalCommandArgs, "-c", "gpg.program="+programPath)
+
+	keyID, err := KeyID()
+	if err != nil {
+		// the backend may be temporarily unreachable, the key is fetched again when it is used
+		log.Error("Unable to get the signing key from the %s backend: %v", setting.SigningBackend.Type, err)
+		return nil
+	}
+	log.Info("Commits are signed with the key %s of the %s backend", keyID, setting.SigningBackend.Type)
+	return nil
+}
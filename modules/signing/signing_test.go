@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+)
+
+func assertSignatureVerifies(t *testing.T, signer crypto.Signer) {
+	e, err := newEntity(signer, time.Unix(1600000000, 0), "Gitea", "gitea@example.com")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, e) {
+		return
+	}
+
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\ncommit message\n"
+	var signature bytes.Buffer
+	assert.NoError(t, openpgp.ArmoredDetachSign(&signature, e, strings.NewReader(payload), nil))
+
+	var public bytes.Buffer
+	assert.NoError(t, e.Serialize(&public))
+	keyring, err := openpgp.ReadKeyRing(&public)
+	assert.NoError(t, err)
+	signerEntity, err := openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader(payload), &signature)
+	assert.NoError(t, err)
+	if assert.NotNil(t, signerEntity) {
+		assert.Equal(t, e.PrimaryKey.KeyId, signerEntity.PrimaryKey.KeyId)
+		assert.Contains(t, signerEntity.Identities, "Gitea <gitea@example.com>")
+	}
+}
+
+func TestNewEntity(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	assertSignatureVerifies(t, rsaKey)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	assertSignatureVerifies(t, ecdsaKey)
+}
+
+func TestVaultSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/transit/keys/gitea":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"latest_version": 1,
+					"keys": map[string]interface{}{
+						"1": map[string]interface{}{
+							"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+						},
+					},
+				},
+			})
+		case "/v1/transit/sign/gitea":
+			var body struct {
+				Input              string `json:"input"`
+				Prehashed          bool   `json:"prehashed"`
+				HashAlgorithm      string `json:"hash_algorithm"`
+				SignatureAlgorithm string `json:"signature_algorithm"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.True(t, body.Prehashed)
+			assert.Equal(t, "sha2-256", body.HashAlgorithm)
+			assert.Equal(t, "pkcs1v15", body.SignatureAlgorithm)
+			digest, err := base64.StdEncoding.DecodeString(body.Input)
+			assert.NoError(t, err)
+			signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+			assert.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(signature),
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err = newVaultSigner(server.URL, "wrong", "transit", "gitea")
+	assert.Error(t, err)
+
+	signer, err := newVaultSigner(server.URL, "token", "transit", "gitea")
+	assert.NoError(t, err)
+	assert.Equal(t, &key.PublicKey, signer.Public())
+	assertSignatureVerifies(t, signer)
+}
+
+func TestAWSKMSSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request")
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"PublicKey": der})
+		case "TrentService.Sign":
+			var body struct {
+				KeyID            string `json:"KeyId"`
+				Message          []byte
+				MessageType      string
+				SigningAlgorithm string
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "alias/gitea", body.KeyID)
+			assert.Equal(t, "DIGEST", body.MessageType)
+			assert.Equal(t, "ECDSA_SHA_256", body.SigningAlgorithm)
+			signature, err := key.Sign(rand.Reader, body.Message, crypto.SHA256)
+			assert.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"Signature": signature})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	signer, err := newAWSKMSSigner(server.URL, "eu-west-1", "alias/gitea", "AKID", "secret", "")
+	assert.NoError(t, err)
+	assertSignatureVerifies(t, signer)
+}
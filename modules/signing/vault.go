@@ -0,0 +1,147 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vaultSigner signs with a key of the transit secrets engine of HashiCorp Vault
+type vaultSigner struct {
+	client  *http.Client
+	address string
+	token   string
+	mount   string
+	key     string
+	public  crypto.PublicKey
+}
+
+var vaultHashAlgorithms = map[crypto.Hash]string{
+	crypto.SHA256: "sha2-256",
+	crypto.SHA384: "sha2-384",
+	crypto.SHA512: "sha2-512",
+}
+
+func newVaultSigner(address, token, mount, key string) (*vaultSigner, error) {
+	if len(address) == 0 || len(key) == 0 {
+		return nil, errors.New("the address and the key of the vault backend are required")
+	}
+	s := &vaultSigner{
+		client:  &http.Client{Timeout: time.Minute},
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		mount:   strings.Trim(mount, "/"),
+		key:     key,
+	}
+
+	var result struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+			Keys          map[string]struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		} `json:"data"`
+	}
+	if err := s.do("GET", "keys/"+key, nil, &result); err != nil {
+		return nil, err
+	}
+	version, ok := result.Data.Keys[strconv.Itoa(result.Data.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("the vault key %s has no public key", key)
+	}
+	block, _ := pem.Decode([]byte(version.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("the vault key %s has no PEM public key", key)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ParsePKIXPublicKey: %v", err)
+	}
+	s.public = public
+	return s, nil
+}
+
+func (s *vaultSigner) do(method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s/%s", s.address, s.mount, path), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return fmt.Errorf("vault %s %s: %s %s", method, path, resp.Status, strings.Join(vaultErr.Errors, ", "))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// Public returns the public key of the latest version of the key
+func (s *vaultSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign signs the digest with the latest version of the key
+func (s *vaultSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashAlgorithm, ok := vaultHashAlgorithms[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash: %v", opts.HashFunc())
+	}
+	body := map[string]interface{}{
+		"input":          base64.StdEncoding.EncodeToString(digest),
+		"prehashed":      true,
+		"hash_algorithm": hashAlgorithm,
+	}
+	switch s.public.(type) {
+	case *rsa.PublicKey:
+		body["signature_algorithm"] = "pkcs1v15"
+	case *ecdsa.PublicKey:
+		body["marshaling_algorithm"] = "asn1"
+	}
+
+	var result struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := s.do("POST", "sign/"+s.key, body, &result); err != nil {
+		return nil, err
+	}
+	// the signatures are formatted as vault:v<version>:<base64 signature>
+	fields := strings.SplitN(result.Data.Signature, ":", 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid vault signature: %s", result.Data.Signature)
+	}
+	return base64.StdEncoding.DecodeString(fields[2])
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -57,6 +58,13 @@ func getExitStatusFromError(err error) int {
 	return waitStatus.ExitStatus()
 }
 
+// sshConnection returns the value of the SSH_CONNECTION environment variable of OpenSSH for the session
+func sshConnection(session ssh.Session) string {
+	clientHost, clientPort, _ := net.SplitHostPort(session.RemoteAddr().String())
+	serverHost, serverPort, _ := net.SplitHostPort(session.LocalAddr().String())
+	return strings.Join([]string{clientHost, clientPort, serverHost, serverPort}, " ")
+}
+
 func sessionHandler(session ssh.Session) {
 	keyID := session.Context().Value(giteaKeyID).(int64)
 
@@ -70,6 +78,7 @@ func sessionHandler(session ssh.Session) {
 	cmd.Env = append(
 		os.Environ(),
 		"SSH_ORIGINAL_COMMAND="+command,
+		"SSH_CONNECTION="+sshConnection(session),
 		"SKIP_MINWINSVC=1",
 	)
 
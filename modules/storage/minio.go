@@ -7,9 +7,11 @@ package storage
 import (
 	"context"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,7 +22,8 @@ import (
 )
 
 var (
-	_ ObjectStorage = &MinioStorage{}
+	_ ObjectStorage       = &MinioStorage{}
+	_ DirectUploadStorage = &MinioStorage{}
 
 	quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 )
@@ -204,6 +207,71 @@ func (m *MinioStorage) URL(path, name string) (*url.URL, error) {
 	return u, convertMinioErr(err)
 }
 
+// UploadURL returns a presigned URL to upload a file with a single PUT request
+func (m *MinioStorage) UploadURL(path string, expiry time.Duration) (*url.URL, error) {
+	u, err := m.client.PresignedPutObject(m.ctx, m.bucket, m.buildMinioPath(path), expiry)
+	return u, convertMinioErr(err)
+}
+
+// NewMultipartUpload starts a multipart upload of a file and returns its id
+func (m *MinioStorage) NewMultipartUpload(path string) (string, error) {
+	core := minio.Core{Client: m.client}
+	uploadID, err := core.NewMultipartUpload(m.ctx, m.bucket, m.buildMinioPath(path), minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return uploadID, convertMinioErr(err)
+}
+
+// UploadPartURL returns a presigned URL to upload a part of a multipart upload
+func (m *MinioStorage) UploadPartURL(path, uploadID string, partNumber int, expiry time.Duration) (*url.URL, error) {
+	reqParams := make(url.Values)
+	reqParams.Set("partNumber", strconv.Itoa(partNumber))
+	reqParams.Set("uploadId", uploadID)
+	u, err := m.client.Presign(m.ctx, http.MethodPut, m.bucket, m.buildMinioPath(path), expiry, reqParams)
+	return u, convertMinioErr(err)
+}
+
+// CompleteMultipartUpload assembles the parts uploaded by the client, they are listed
+// from the bucket so the client doesn't need to report their ETags
+func (m *MinioStorage) CompleteMultipartUpload(path, uploadID string) error {
+	core := minio.Core{Client: m.client}
+	objectPath := m.buildMinioPath(path)
+
+	var parts []minio.CompletePart
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(m.ctx, m.bucket, objectPath, uploadID, marker, 1000)
+		if err != nil {
+			return convertMinioErr(err)
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	_, err := core.CompleteMultipartUpload(m.ctx, m.bucket, objectPath, uploadID, parts)
+	return convertMinioErr(err)
+}
+
+// AbortMultipartUpload discards the parts of a multipart upload
+func (m *MinioStorage) AbortMultipartUpload(path, uploadID string) error {
+	core := minio.Core{Client: m.client}
+	return convertMinioErr(core.AbortMultipartUpload(m.ctx, m.bucket, m.buildMinioPath(path), uploadID))
+}
+
+// Move copies a file on the server side then deletes the source
+func (m *MinioStorage) Move(srcPath, dstPath string) error {
+	if _, err := m.client.ComposeObject(m.ctx,
+		minio.CopyDestOptions{Bucket: m.bucket, Object: m.buildMinioPath(dstPath)},
+		minio.CopySrcOptions{Bucket: m.bucket, Object: m.buildMinioPath(srcPath)},
+	); err != nil {
+		return convertMinioErr(err)
+	}
+	return m.Delete(srcPath)
+}
+
 // IterateObjects iterates across the objects in the miniostorage
 func (m *MinioStorage) IterateObjects(fn func(path string, obj Object) error) error {
 	var opts = minio.GetObjectOptions{}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -72,6 +73,24 @@ type ObjectStorage interface {
 	IterateObjects(func(path string, obj Object) error) error
 }
 
+// DirectUploadStorage represents an object storage the clients can upload objects to
+// without going through Gitea, using presigned URLs
+type DirectUploadStorage interface {
+	ObjectStorage
+	// UploadURL returns a presigned URL to upload an object with a single PUT request
+	UploadURL(path string, expiry time.Duration) (*url.URL, error)
+	// NewMultipartUpload starts the upload of an object in several parts and returns its id
+	NewMultipartUpload(path string) (string, error)
+	// UploadPartURL returns a presigned URL to upload a part, the part numbers start at 1
+	UploadPartURL(path, uploadID string, partNumber int, expiry time.Duration) (*url.URL, error)
+	// CompleteMultipartUpload assembles all the uploaded parts into the object
+	CompleteMultipartUpload(path, uploadID string) error
+	// AbortMultipartUpload discards the uploaded parts
+	AbortMultipartUpload(path, uploadID string) error
+	// Move renames an object without transferring its content
+	Move(srcPath, dstPath string) error
+}
+
 // Copy copys a file from source ObjectStorage to dest ObjectStorage
 func Copy(dstStorage ObjectStorage, dstPath string, srcStorage ObjectStorage, srcPath string) (int64, error) {
 	f, err := srcStorage.Open(srcPath)
@@ -109,6 +128,9 @@ var (
 	Avatars ObjectStorage
 	// RepoAvatars represents repository avatars storage
 	RepoAvatars ObjectStorage
+
+	// HookTaskArchives represents the storage of the archived webhook deliveries
+	HookTaskArchives ObjectStorage
 )
 
 // Init init the stoarge
@@ -125,6 +147,10 @@ func Init() error {
 		return err
 	}
 
+	if err := initHookTaskArchives(); err != nil {
+		return err
+	}
+
 	return initLFS()
 }
 
@@ -164,3 +190,9 @@ func initRepoAvatars() (err error) {
 	RepoAvatars, err = NewStorage(setting.RepoAvatar.Storage.Type, &setting.RepoAvatar.Storage)
 	return
 }
+
+func initHookTaskArchives() (err error) {
+	log.Info("Initialising Webhook Delivery Archive storage with type: %s", setting.Webhook.ArchiveStorage.Type)
+	HookTaskArchives, err = NewStorage(setting.Webhook.ArchiveStorage.Type, &setting.Webhook.ArchiveStorage)
+	return
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrObjectMismatch represents an error when the copy of an object doesn't match the original object
+type ErrObjectMismatch struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+// IsErrObjectMismatch checks if an error is an ErrObjectMismatch
+func IsErrObjectMismatch(err error) bool {
+	_, ok := err.(ErrObjectMismatch)
+	return ok
+}
+
+func (err ErrObjectMismatch) Error() string {
+	return fmt.Sprintf("copy of %s doesn't match: expected %s, got %s", err.Path, err.Expected, err.Actual)
+}
+
+// SyncResult represents what Sync did with an object
+type SyncResult int
+
+const (
+	// SyncCopied means the object has been copied
+	SyncCopied SyncResult = iota
+	// SyncSkipped means the destination storage already had a copy of the object
+	SyncSkipped
+	// SyncMissing means the object doesn't exist in the source storage
+	SyncMissing
+)
+
+// HashObject returns the hex encoded SHA-256 hash of the content of an object
+func HashObject(objStorage ObjectStorage, p string) (string, error) {
+	f, err := objStorage.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Sync copies a file from source ObjectStorage to dest ObjectStorage unless dest already has a copy of it,
+// so interrupted syncs can be resumed. The copies are compared by size, and by their SHA-256 hashes when
+// verify is set, in which case a copy is also checked after it has been saved.
+func Sync(dstStorage ObjectStorage, dstPath string, srcStorage ObjectStorage, srcPath string, verify bool) (SyncResult, error) {
+	srcInfo, err := srcStorage.Stat(srcPath)
+	if os.IsNotExist(err) {
+		return SyncMissing, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	dstInfo, err := dstStorage.Stat(dstPath)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	} else if err == nil && dstInfo.Size() == srcInfo.Size() {
+		if !verify {
+			return SyncSkipped, nil
+		}
+		srcHash, err := HashObject(srcStorage, srcPath)
+		if err != nil {
+			return 0, err
+		}
+		dstHash, err := HashObject(dstStorage, dstPath)
+		if err != nil {
+			return 0, err
+		}
+		if srcHash == dstHash {
+			return SyncSkipped, nil
+		}
+		// The copy is corrupted, copy it again
+	}
+
+	f, err := srcStorage.Open(srcPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	written, err := dstStorage.Save(dstPath, io.TeeReader(f, h))
+	if err != nil {
+		return 0, err
+	}
+	if written != srcInfo.Size() {
+		return 0, ErrObjectMismatch{
+			Path:     dstPath,
+			Expected: fmt.Sprintf("%d bytes", srcInfo.Size()),
+			Actual:   fmt.Sprintf("%d bytes", written),
+		}
+	}
+
+	if verify {
+		srcHash := hex.EncodeToString(h.Sum(nil))
+		dstHash, err := HashObject(dstStorage, dstPath)
+		if err != nil {
+			return 0, err
+		}
+		if srcHash != dstHash {
+			return 0, ErrObjectMismatch{
+				Path:     dstPath,
+				Expected: srcHash,
+				Actual:   dstHash,
+			}
+		}
+	}
+	return SyncCopied, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSync(t *testing.T) {
+	srcPath, err := ioutil.TempDir("", "storage-src")
+	assert.NoError(t, err)
+	defer util.RemoveAll(srcPath)
+	dstPath, err := ioutil.TempDir("", "storage-dst")
+	assert.NoError(t, err)
+	defer util.RemoveAll(dstPath)
+
+	src, err := NewLocalStorage(context.Background(), LocalStorageConfig{Path: srcPath})
+	assert.NoError(t, err)
+	dst, err := NewLocalStorage(context.Background(), LocalStorageConfig{Path: dstPath})
+	assert.NoError(t, err)
+
+	_, err = src.Save("a/b/object", strings.NewReader("content"))
+	assert.NoError(t, err)
+
+	result, err := Sync(dst, "a/b/object", src, "a/b/object", true)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncCopied, result)
+	content, err := ioutil.ReadFile(filepath.Join(dstPath, "a/b/object"))
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	// the object has already been copied
+	result, err = Sync(dst, "a/b/object", src, "a/b/object", true)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncSkipped, result)
+
+	// an interrupted copy is copied again
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "a/b/object"), []byte("cont"), 0644))
+	result, err = Sync(dst, "a/b/object", src, "a/b/object", false)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncCopied, result)
+
+	// a corrupted copy of the same size is only detected by its hash
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "a/b/object"), []byte("CONTENT"), 0644))
+	result, err = Sync(dst, "a/b/object", src, "a/b/object", false)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncSkipped, result)
+	result, err = Sync(dst, "a/b/object", src, "a/b/object", true)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncCopied, result)
+	srcHash, err := HashObject(src, "a/b/object")
+	assert.NoError(t, err)
+	dstHash, err := HashObject(dst, "a/b/object")
+	assert.NoError(t, err)
+	assert.Equal(t, srcHash, dstHash)
+
+	result, err = Sync(dst, "missing", src, "missing", true)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncMissing, result)
+}
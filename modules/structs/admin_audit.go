@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// AuditEvent represents a security-relevant event recorded in the audit log
+type AuditEvent struct {
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+	// 0 if the event isn't done by a known user, like a failed login
+	DoerID    int64  `json:"doer_id"`
+	DoerName  string `json:"doer_name"`
+	IPAddress string `json:"ip_address"`
+	// enum: user,organization,repository,instance
+	TargetType  string `json:"target_type"`
+	TargetID    int64  `json:"target_id"`
+	TargetName  string `json:"target_name"`
+	Description string `json:"description"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// LegalHold represents a legal hold on the content of a user or a repository,
+// the content under a hold can't be purged and the revisions of its issues and comments are kept
+type LegalHold struct {
+	ID         int64       `json:"id"`
+	User       *User       `json:"user"`
+	Repository *Repository `json:"repository"`
+	Reason     string      `json:"reason"`
+	Creator    *User       `json:"creator"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateLegalHoldOption options for placing a legal hold on either a user or a repository
+type CreateLegalHoldOption struct {
+	// the user to hold the content of
+	Username string `json:"username"`
+	// the owner of the repository to hold the content of
+	Owner string `json:"owner"`
+	// the name of the repository to hold the content of
+	Repo   string `json:"repo"`
+	Reason string `json:"reason"`
+}
@@ -39,6 +39,7 @@ type EditUserOption struct {
 	AllowGitHook            *bool   `json:"allow_git_hook"`
 	AllowImportLocal        *bool   `json:"allow_import_local"`
 	MaxRepoCreation         *int    `json:"max_repo_creation"`
+	MaxStorageSize          *int64  `json:"max_storage_size"`
 	ProhibitLogin           *bool   `json:"prohibit_login"`
 	AllowCreateOrganization *bool   `json:"allow_create_organization"`
 }
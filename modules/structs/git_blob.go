@@ -11,4 +11,14 @@ type GitBlobResponse struct {
 	URL      string `json:"url"`
 	SHA      string `json:"sha"`
 	Size     int64  `json:"size"`
+	// regions of lines which can be collapsed, only listed when requested
+	FoldRegions []*FoldRegion `json:"fold_regions,omitempty"`
+}
+
+// FoldRegion represents lines of a file which can be collapsed, the first line stays visible
+type FoldRegion struct {
+	// first line of the region, starting at 1
+	Start int `json:"start"`
+	// last line of the region, included
+	End int `json:"end"`
 }
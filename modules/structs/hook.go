@@ -12,6 +12,11 @@ import (
 	"time"
 )
 
+// WebhookPayloadVersion is the latest version of the payloads of the gitea and gogs webhooks, it is
+// increased when the payloads change in a way which breaks their consumers. Each webhook is pinned
+// to a version which is sent in the X-Gitea-Event-Version header of its deliveries.
+const WebhookPayloadVersion = 2
+
 var (
 	// ErrInvalidReceiveHook FIXME
 	ErrInvalidReceiveHook = errors.New("Invalid JSON payload received over webhook")
@@ -25,6 +30,8 @@ type Hook struct {
 	Config map[string]string `json:"config"`
 	Events []string          `json:"events"`
 	Active bool              `json:"active"`
+	// version of the payloads sent to the hook
+	PayloadVersion int `json:"payload_version"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
@@ -49,6 +56,8 @@ type CreateHookOption struct {
 	BranchFilter string                 `json:"branch_filter" binding:"GlobPattern"`
 	// default: false
 	Active bool `json:"active"`
+	// version of the payloads sent to the hook, defaults to the latest one
+	PayloadVersion int `json:"payload_version"`
 }
 
 // EditHookOption options when modify one hook
@@ -57,6 +66,8 @@ type EditHookOption struct {
 	Events       []string          `json:"events"`
 	BranchFilter string            `json:"branch_filter" binding:"GlobPattern"`
 	Active       *bool             `json:"active"`
+	// version of the payloads sent to the hook
+	PayloadVersion *int `json:"payload_version"`
 }
 
 // Payloader payload is some part of one hook
@@ -112,6 +123,10 @@ var (
 	_ Payloader = &PullRequestPayload{}
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
+	_ Payloader = &UserPayload{}
+	_ Payloader = &OrganizationPayload{}
+	_ Payloader = &AuthFailurePayload{}
+	_ Payloader = &ReviewEnvironmentPayload{}
 )
 
 // _________                        __
@@ -123,7 +138,7 @@ var (
 
 // CreatePayload FIXME
 type CreatePayload struct {
-	Secret  string      `json:"secret"`
+	Secret  string      `json:"secret,omitempty"`
 	Sha     string      `json:"sha"`
 	Ref     string      `json:"ref"`
 	RefType string      `json:"ref_type"`
@@ -178,7 +193,7 @@ const (
 
 // DeletePayload represents delete payload
 type DeletePayload struct {
-	Secret     string      `json:"secret"`
+	Secret     string      `json:"secret,omitempty"`
 	Ref        string      `json:"ref"`
 	RefType    string      `json:"ref_type"`
 	PusherType PusherType  `json:"pusher_type"`
@@ -205,7 +220,7 @@ func (p *DeletePayload) JSONPayload() ([]byte, error) {
 
 // ForkPayload represents fork payload
 type ForkPayload struct {
-	Secret string      `json:"secret"`
+	Secret string      `json:"secret,omitempty"`
 	Forkee *Repository `json:"forkee"`
 	Repo   *Repository `json:"repository"`
 	Sender *User       `json:"sender"`
@@ -233,7 +248,7 @@ const (
 
 // IssueCommentPayload represents a payload information of issue comment event.
 type IssueCommentPayload struct {
-	Secret     string                 `json:"secret"`
+	Secret     string                 `json:"secret,omitempty"`
 	Action     HookIssueCommentAction `json:"action"`
 	Issue      *Issue                 `json:"issue"`
 	Comment    *Comment               `json:"comment"`
@@ -272,7 +287,7 @@ const (
 
 // ReleasePayload represents a payload information of release event.
 type ReleasePayload struct {
-	Secret     string            `json:"secret"`
+	Secret     string            `json:"secret,omitempty"`
 	Action     HookReleaseAction `json:"action"`
 	Release    *Release          `json:"release"`
 	Repository *Repository       `json:"repository"`
@@ -298,7 +313,7 @@ func (p *ReleasePayload) JSONPayload() ([]byte, error) {
 
 // PushPayload represents a payload information of push event.
 type PushPayload struct {
-	Secret     string           `json:"secret"`
+	Secret     string           `json:"secret,omitempty"`
 	Ref        string           `json:"ref"`
 	Before     string           `json:"before"`
 	After      string           `json:"after"`
@@ -380,7 +395,7 @@ const (
 
 // IssuePayload represents the payload information that is sent along with an issue event.
 type IssuePayload struct {
-	Secret     string          `json:"secret"`
+	Secret     string          `json:"secret,omitempty"`
 	Action     HookIssueAction `json:"action"`
 	Index      int64           `json:"number"`
 	Changes    *ChangesPayload `json:"changes,omitempty"`
@@ -420,7 +435,7 @@ type ChangesPayload struct {
 
 // PullRequestPayload represents a payload information of pull request event.
 type PullRequestPayload struct {
-	Secret      string          `json:"secret"`
+	Secret      string          `json:"secret,omitempty"`
 	Action      HookIssueAction `json:"action"`
 	Index       int64           `json:"number"`
 	Changes     *ChangesPayload `json:"changes,omitempty"`
@@ -465,7 +480,7 @@ const (
 
 // RepositoryPayload payload for repository webhooks
 type RepositoryPayload struct {
-	Secret       string         `json:"secret"`
+	Secret       string         `json:"secret,omitempty"`
 	Action       HookRepoAction `json:"action"`
 	Repository   *Repository    `json:"repository"`
 	Organization *User          `json:"organization"`
@@ -481,3 +496,121 @@ func (p *RepositoryPayload) SetSecret(secret string) {
 func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", " ")
 }
+
+// HookUserAction an action that happens to a user
+type HookUserAction string
+
+const (
+	// HookUserCreated created
+	HookUserCreated HookUserAction = "created"
+	// HookUserDeleted deleted
+	HookUserDeleted HookUserAction = "deleted"
+)
+
+// UserPayload payload for the user system webhooks
+type UserPayload struct {
+	Secret string         `json:"secret,omitempty"`
+	Action HookUserAction `json:"action"`
+	User   *User          `json:"user"`
+	Sender *User          `json:"sender"`
+}
+
+// SetSecret modifies the secret of the UserPayload
+func (p *UserPayload) SetSecret(secret string) {
+	p.Secret = secret
+}
+
+// JSONPayload JSON representation of the payload
+func (p *UserPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", " ")
+}
+
+// HookOrganizationAction an action that happens to an organization
+type HookOrganizationAction string
+
+const (
+	// HookOrganizationCreated created
+	HookOrganizationCreated HookOrganizationAction = "created"
+	// HookOrganizationDeleted deleted
+	HookOrganizationDeleted HookOrganizationAction = "deleted"
+)
+
+// OrganizationPayload payload for the organization system webhooks
+type OrganizationPayload struct {
+	Secret       string                 `json:"secret,omitempty"`
+	Action       HookOrganizationAction `json:"action"`
+	Organization *Organization          `json:"organization"`
+	Sender       *User                  `json:"sender"`
+}
+
+// SetSecret modifies the secret of the OrganizationPayload
+func (p *OrganizationPayload) SetSecret(secret string) {
+	p.Secret = secret
+}
+
+// JSONPayload JSON representation of the payload
+func (p *OrganizationPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", " ")
+}
+
+// HookAuthFailureMethod the way a user failed to authenticate
+type HookAuthFailureMethod string
+
+const (
+	// HookAuthFailurePassword wrong user name or password when signing in
+	HookAuthFailurePassword HookAuthFailureMethod = "password"
+	// HookAuthFailureTwoFactor wrong two-factor passcode or scratch token
+	HookAuthFailureTwoFactor HookAuthFailureMethod = "two_factor"
+	// HookAuthFailureBasic wrong credentials of the basic authentication of an API or git request
+	HookAuthFailureBasic HookAuthFailureMethod = "basic"
+	// HookAuthFailureProhibited the user is prohibited from signing in
+	HookAuthFailureProhibited HookAuthFailureMethod = "prohibited"
+)
+
+// AuthFailurePayload payload for the authentication failure system webhooks
+type AuthFailurePayload struct {
+	Secret        string                `json:"secret,omitempty"`
+	Method        HookAuthFailureMethod `json:"method"`
+	LoginName     string                `json:"login_name"`
+	RemoteAddress string                `json:"remote_address"`
+	// swagger:strfmt date-time
+	Time time.Time `json:"time"`
+}
+
+// SetSecret modifies the secret of the AuthFailurePayload
+func (p *AuthFailurePayload) SetSecret(secret string) {
+	p.Secret = secret
+}
+
+// JSONPayload JSON representation of the payload
+func (p *AuthFailurePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", " ")
+}
+
+// HookReviewEnvironmentAction an action that happens to a review environment
+type HookReviewEnvironmentAction string
+
+const (
+	// HookReviewEnvironmentCleanup the pull request of the environment was closed, the environment can be torn down
+	HookReviewEnvironmentCleanup HookReviewEnvironmentAction = "cleanup"
+)
+
+// ReviewEnvironmentPayload payload for the review environment webhooks
+type ReviewEnvironmentPayload struct {
+	Secret      string                      `json:"secret"`
+	Action      HookReviewEnvironmentAction `json:"action"`
+	Environment *ReviewEnvironment          `json:"environment"`
+	PullRequest *PullRequest                `json:"pull_request"`
+	Repository  *Repository                 `json:"repository"`
+	Sender      *User                       `json:"sender"`
+}
+
+// SetSecret modifies the secret of the ReviewEnvironmentPayload
+func (p *ReviewEnvironmentPayload) SetSecret(secret string) {
+	p.Secret = secret
+}
+
+// JSONPayload JSON representation of the payload
+func (p *ReviewEnvironmentPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", " ")
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// HovercardChecks summary of the latest statuses of a commit
+type HovercardChecks struct {
+	// combined state of the statuses
+	State        StatusState `json:"state"`
+	TotalCount   int         `json:"total_count"`
+	SuccessCount int         `json:"success_count"`
+	PendingCount int         `json:"pending_count"`
+	// number of the statuses in the error, failure or warning state
+	FailureCount int `json:"failure_count"`
+}
+
+// Hovercard summary of the issue, the pull request, the commit or the user of a link
+type Hovercard struct {
+	// type of the summarized object: issue, pull, commit or user
+	Type    string `json:"type"`
+	HTMLURL string `json:"html_url"`
+	// title of the issue or the pull request, summary of the commit message or name of the user
+	Title string `json:"title"`
+	// beginning of the body of the issue or the pull request, of the commit message or of the description of the user
+	Excerpt string `json:"excerpt"`
+	// state of the issue or the pull request: open, closed or merged
+	State  string          `json:"state,omitempty"`
+	Number int64           `json:"number,omitempty"`
+	SHA    string          `json:"sha,omitempty"`
+	Repo   *RepositoryMeta `json:"repository,omitempty"`
+	// author of the issue, the pull request or the commit if the commit author is a user, or the summarized user
+	User         *User       `json:"user,omitempty"`
+	CommitAuthor *CommitUser `json:"commit_author,omitempty"`
+	Labels       []*Label    `json:"labels,omitempty"`
+	Assignees    []*User     `json:"assignees,omitempty"`
+	Comments     int         `json:"comments,omitempty"`
+	// summary of the statuses of the commit or of the head commit of the pull request
+	Checks *HovercardChecks `json:"checks,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
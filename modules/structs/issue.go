@@ -69,6 +69,10 @@ type Issue struct {
 
 	PullRequest *PullRequestMeta `json:"pull_request"`
 	Repo        *RepositoryMeta  `json:"repository"`
+	// ID of the parent issue of a sub-issue
+	ParentID int64 `json:"parent_id"`
+	// estimated time to resolve the issue in seconds
+	TimeEstimate int64 `json:"time_estimate"`
 }
 
 // ListIssueOption list issue options
@@ -105,6 +109,8 @@ type EditIssueOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// estimated time to resolve the issue in seconds
+	TimeEstimate *int64 `json:"time_estimate"`
 }
 
 // EditDeadlineOption options for creating a deadline
@@ -124,15 +130,81 @@ type IssueDeadline struct {
 // IssueTemplate represents an issue template for a repository
 // swagger:model
 type IssueTemplate struct {
-	Name     string   `json:"name" yaml:"name"`
-	Title    string   `json:"title" yaml:"title"`
-	About    string   `json:"about" yaml:"about"`
-	Labels   []string `json:"labels" yaml:"labels"`
-	Content  string   `json:"content" yaml:"-"`
-	FileName string   `json:"file_name" yaml:"-"`
+	Name   string   `json:"name" yaml:"name"`
+	Title  string   `json:"title" yaml:"title"`
+	About  string   `json:"about" yaml:"about"`
+	Labels []string `json:"labels" yaml:"labels"`
+	// the fields of an issue form, empty for a markdown template
+	Fields   []*IssueFormField `json:"body" yaml:"body"`
+	Content  string            `json:"content" yaml:"-"`
+	FileName string            `json:"file_name" yaml:"-"`
 }
 
 // Valid checks whether an IssueTemplate is considered valid, e.g. at least name and about
 func (it IssueTemplate) Valid() bool {
 	return strings.TrimSpace(it.Name) != "" && strings.TrimSpace(it.About) != ""
 }
+
+// IsForm returns whether the template is an issue form
+func (it IssueTemplate) IsForm() bool {
+	return len(it.Fields) > 0
+}
+
+// IssueFormFieldType defines the type of a field of an issue form
+type IssueFormFieldType string
+
+const (
+	// IssueFormFieldTypeMarkdown is a markdown text which is displayed but not submitted
+	IssueFormFieldTypeMarkdown IssueFormFieldType = "markdown"
+	// IssueFormFieldTypeInput is a single line text field
+	IssueFormFieldTypeInput IssueFormFieldType = "input"
+	// IssueFormFieldTypeTextarea is a multi line text field
+	IssueFormFieldTypeTextarea IssueFormFieldType = "textarea"
+	// IssueFormFieldTypeDropdown is a selection of one or several options
+	IssueFormFieldTypeDropdown IssueFormFieldType = "dropdown"
+	// IssueFormFieldTypeCheckboxes is a list of checkboxes
+	IssueFormFieldTypeCheckboxes IssueFormFieldType = "checkboxes"
+)
+
+// IssueFormField represents a field of an issue form
+type IssueFormField struct {
+	Type        IssueFormFieldType        `json:"type" yaml:"type"`
+	ID          string                    `json:"id" yaml:"id"`
+	Attributes  IssueFormFieldAttributes  `json:"attributes" yaml:"attributes"`
+	Validations IssueFormFieldValidations `json:"validations" yaml:"validations"`
+}
+
+// IssueFormFieldAttributes represents the attributes of a field of an issue form
+type IssueFormFieldAttributes struct {
+	Label       string `json:"label,omitempty" yaml:"label"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	Placeholder string `json:"placeholder,omitempty" yaml:"placeholder"`
+	// the default value of a text field or the content of a markdown field
+	Value string `json:"value,omitempty" yaml:"value"`
+	// the language of the code block the value of a textarea is rendered into
+	Render string `json:"render,omitempty" yaml:"render"`
+	// whether several options of a dropdown may be selected
+	Multiple bool                    `json:"multiple,omitempty" yaml:"multiple"`
+	Options  []*IssueFormFieldOption `json:"options,omitempty" yaml:"options"`
+}
+
+// IssueFormFieldOption represents an option of a dropdown or a checkbox
+type IssueFormFieldOption struct {
+	Label string `json:"label" yaml:"label"`
+	// whether a checkbox must be checked
+	Required bool `json:"required,omitempty" yaml:"required"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, the options of a dropdown are plain strings
+func (o *IssueFormFieldOption) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&o.Label); err == nil {
+		return nil
+	}
+	type option IssueFormFieldOption
+	return unmarshal((*option)(o))
+}
+
+// IssueFormFieldValidations represents the validations of a field of an issue form
+type IssueFormFieldValidations struct {
+	Required bool `json:"required,omitempty" yaml:"required"`
+}
@@ -35,3 +35,44 @@ type EditIssueCommentOption struct {
 	// required: true
 	Body string `json:"body" binding:"Required"`
 }
+
+// TimelineComment represents an event of the timeline of an issue or pull request
+type TimelineComment struct {
+	ID int64 `json:"id"`
+	// type of the event, e.g. comment, label, milestone, review or pull_push
+	Type     string `json:"type"`
+	HTMLURL  string `json:"html_url"`
+	PRURL    string `json:"pull_request_url"`
+	IssueURL string `json:"issue_url"`
+	Poster   *User  `json:"user"`
+	Body     string `json:"body"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+
+	OldProjectID    int64      `json:"old_project_id,omitempty"`
+	ProjectID       int64      `json:"project_id,omitempty"`
+	OldMilestone    *Milestone `json:"old_milestone,omitempty"`
+	Milestone       *Milestone `json:"milestone,omitempty"`
+	OldTitle        string     `json:"old_title,omitempty"`
+	NewTitle        string     `json:"new_title,omitempty"`
+	OldRef          string     `json:"old_ref,omitempty"`
+	NewRef          string     `json:"new_ref,omitempty"`
+	Label           *Label     `json:"label,omitempty"`
+	Assignee        *User      `json:"assignee,omitempty"`
+	AssigneeTeam    *Team      `json:"assignee_team,omitempty"`
+	RemovedAssignee bool       `json:"removed_assignee,omitempty"`
+	ResolveDoer     *User      `json:"resolve_doer,omitempty"`
+	DependentIssue  *Issue     `json:"dependent_issue,omitempty"`
+	ReviewID        int64      `json:"review_id,omitempty"`
+
+	RefIssue     *Issue   `json:"ref_issue,omitempty"`
+	RefComment   *Comment `json:"ref_comment,omitempty"`
+	RefAction    string   `json:"ref_action,omitempty"`
+	RefCommitSHA string   `json:"ref_commit_sha,omitempty"`
+
+	// SHAs of the commits pushed to the head branch of a pull request
+	Commits     []string `json:"commits,omitempty"`
+	IsForcePush bool     `json:"is_force_push,omitempty"`
+}
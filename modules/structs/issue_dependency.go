@@ -0,0 +1,37 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IssueDependencyGraph represents the issues which transitively block or are blocked by
+// an issue or the issues of a milestone
+type IssueDependencyGraph struct {
+	Nodes []*IssueDependencyNode `json:"nodes"`
+	Edges []*IssueDependencyEdge `json:"edges"`
+	// whether issues have been left out of a too large graph
+	Truncated bool `json:"truncated"`
+}
+
+// IssueDependencyNode represents an issue of a dependency graph
+type IssueDependencyNode struct {
+	ID      int64           `json:"id"`
+	Index   int64           `json:"number"`
+	Title   string          `json:"title"`
+	State   StateType       `json:"state"`
+	IsPull  bool            `json:"is_pull"`
+	Repo    *RepositoryMeta `json:"repository"`
+	URL     string          `json:"url"`
+	HTMLURL string          `json:"html_url"`
+	// 0 if the issue isn't blocked by an issue of the graph, otherwise one more than the
+	// level of its blockers
+	Level int `json:"level"`
+	// whether the issue is blocked by an open issue
+	Blocked bool `json:"blocked"`
+}
+
+// IssueDependencyEdge represents that an issue is blocked by another one
+type IssueDependencyEdge struct {
+	BlockerID int64 `json:"blocker_id"`
+	BlockedID int64 `json:"blocked_id"`
+}
@@ -43,3 +43,22 @@ type EditMilestoneOption struct {
 	State       *string    `json:"state"`
 	Deadline    *time.Time `json:"due_on"`
 }
+
+// MilestoneStatsBucket represents the state of the issues of a milestone at the end of an interval
+type MilestoneStatsBucket struct {
+	// swagger:strfmt date-time
+	End          time.Time `json:"end"`
+	OpenIssues   int       `json:"open_issues"`
+	ClosedIssues int       `json:"closed_issues"`
+	// sum of the time estimates of the issues in seconds
+	EstimatedTime int64 `json:"estimated_time"`
+	// time tracked on the issues until the end of the interval in seconds
+	TrackedTime int64 `json:"tracked_time"`
+}
+
+// MilestoneStats represents the burndown of a milestone
+type MilestoneStats struct {
+	// enum: day,week
+	Interval string                  `json:"interval"`
+	Buckets  []*MilestoneStatsBucket `json:"buckets"`
+}
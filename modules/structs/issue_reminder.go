@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueReminder represents a notification of an issue scheduled by the user
+type IssueReminder struct {
+	ID int64 `json:"id"`
+	// swagger:strfmt date-time
+	RemindAt time.Time `json:"remind_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateIssueReminderOption options to schedule a reminder, either at a time or in a duration from now
+type CreateIssueReminderOption struct {
+	// swagger:strfmt date-time
+	RemindAt *time.Time `json:"remind_at"`
+	// number of minutes, hours, days or weeks from now, e.g. "30m", "2h", "3d" or "1w"
+	In string `json:"in"`
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueSLAPolicy represents a service level agreement of an organization, the issues having its
+// label in the repositories of the organization must get a first response and be resolved in time
+type IssueSLAPolicy struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// name of the label of the issues the policy applies to, a label of the organization or of its repositories
+	Label        string `json:"label"`
+	IncludePulls bool   `json:"include_pulls"`
+	// number of hours until the first response of a member of the organization, no target if it is 0
+	ResponseHours int `json:"response_hours"`
+	// number of hours until the issue is closed, no target if it is 0
+	ResolutionHours int `json:"resolution_hours"`
+	// name of the label added to the issues breaching a target
+	BreachLabel string `json:"breach_label"`
+	// comment posted on the issues breaching a target
+	BreachComment string `json:"breach_comment"`
+	// the user on behalf of which the actions are taken
+	Doer *User `json:"doer"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateIssueSLAPolicyOption options for creating a SLA policy
+type CreateIssueSLAPolicyOption struct {
+	// required: true
+	Name    string `json:"name" binding:"Required;MaxSize(255)"`
+	Enabled bool   `json:"enabled"`
+	// required: true
+	Label           string `json:"label" binding:"Required;MaxSize(50)"`
+	IncludePulls    bool   `json:"include_pulls"`
+	ResponseHours   int    `json:"response_hours"`
+	ResolutionHours int    `json:"resolution_hours"`
+	// the label is created in the repository of an issue if neither the repository nor the organization has it
+	BreachLabel   string `json:"breach_label" binding:"MaxSize(50)"`
+	BreachComment string `json:"breach_comment"`
+}
+
+// EditIssueSLAPolicyOption options for editing a SLA policy
+type EditIssueSLAPolicyOption struct {
+	Name            *string `json:"name" binding:"OmitEmpty;MaxSize(255)"`
+	Enabled         *bool   `json:"enabled"`
+	Label           *string `json:"label" binding:"OmitEmpty;MaxSize(50)"`
+	IncludePulls    *bool   `json:"include_pulls"`
+	ResponseHours   *int    `json:"response_hours"`
+	ResolutionHours *int    `json:"resolution_hours"`
+	BreachLabel     *string `json:"breach_label" binding:"OmitEmpty;MaxSize(50)"`
+	BreachComment   *string `json:"breach_comment"`
+}
+
+// IssueSLAReport represents how the issues created over a period comply with a SLA policy
+type IssueSLAReport struct {
+	Policy *IssueSLAPolicy `json:"policy"`
+	Issues int64           `json:"issues"`
+	// number of issues which got a first response or were closed
+	Responded        int64 `json:"responded"`
+	ResponseBreaches int64 `json:"response_breaches"`
+	// number of closed issues
+	Resolved           int64 `json:"resolved"`
+	ResolutionBreaches int64 `json:"resolution_breaches"`
+	// average number of seconds until the first response of the responded issues
+	AverageResponseTime int64 `json:"average_response_time"`
+	// average number of seconds until the resolution of the resolved issues
+	AverageResolutionTime int64 `json:"average_resolution_time"`
+}
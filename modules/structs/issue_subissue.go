@@ -0,0 +1,21 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// SubIssuesProgress represents the roll-up progress of the sub-issues of an issue
+type SubIssuesProgress struct {
+	Total  int64 `json:"total"`
+	Closed int64 `json:"closed"`
+	// percentage of the sub-issues which are closed
+	Percent int `json:"percent"`
+}
+
+// AttachSubIssueOption options to attach a sub-issue to an issue
+type AttachSubIssueOption struct {
+	// index of the issue to attach, it must be an issue of the same repository
+	//
+	// required: true
+	Index int64 `json:"index" binding:"Required"`
+}
@@ -36,6 +36,17 @@ type MarkdownOption struct {
 	Wiki bool
 }
 
+// MarkupOption options to render a markup document of a repository
+type MarkupOption struct {
+	// Text of the document to render
+	Text string `json:"text"`
+	// Path of the document in the repository, its extension selects the markup format
+	// and its relative links are resolved from its directory
+	FilePath string `json:"file_path" binding:"Required"`
+	// Branch, tag or commit the relative links point to, the default branch if empty
+	Ref string `json:"ref"`
+}
+
 // MarkdownRender is a rendered markdown document
 // swagger:response MarkdownRender
 type MarkdownRender string
@@ -45,8 +56,37 @@ type ServerVersion struct {
 	Version string `json:"version"`
 }
 
-// APIError is an api error with a message
+// APIErrorVersion is the version of the error format of the API, it is increased when
+// the format changes in a way which is not backward compatible
+const APIErrorVersion = 1
+
+// APIErrorDetail describes one of the problems of a request, e.g. an invalid field
+type APIErrorDetail struct {
+	// name of the field of the request, empty if the problem is not related to a field
+	Field string `json:"field,omitempty"`
+	// machine-readable code of the problem, e.g. "required"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is the error returned by all the endpoints of the API
+// swagger:model
 type APIError struct {
+	// machine-readable code of the error, e.g. "not_found" or "validation_failed"
+	Code    string `json:"code"`
 	Message string `json:"message"`
-	URL     string `json:"url"`
+	// URL of the documentation of the API
+	URL string `json:"url"`
+	// version of the error format
+	Version int               `json:"version"`
+	Errors  []*APIErrorDetail `json:"errors,omitempty"`
+}
+
+// APIInvalidTopicsError is the error returned for invalid topics
+// swagger:model
+type APIInvalidTopicsError struct {
+	APIError
+	// invalid topics, kept for the clients of the previous error format, a list of
+	// names when the topics are replaced and a name when a topic is added or deleted
+	InvalidTopics interface{} `json:"invalidTopics"`
 }
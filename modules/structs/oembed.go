@@ -0,0 +1,20 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// OEmbed represents the oEmbed response of a permalink to lines of a file, see https://oembed.com
+type OEmbed struct {
+	// version of oEmbed, always 1.0
+	Version string `json:"version"`
+	// type of the embedded content, always rich
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	// self-contained HTML snippet of the lines with inline styles
+	HTML   string `json:"html"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
@@ -15,6 +15,7 @@ type Organization struct {
 	Location                  string `json:"location"`
 	Visibility                string `json:"visibility"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	RequireTwoFactor          bool   `json:"require_two_factor"`
 }
 
 // CreateOrgOption options for creating an organization
@@ -41,4 +42,14 @@ type EditOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// require the members to enroll into two-factor authentication
+	RequireTwoFactor *bool `json:"require_two_factor"`
+}
+
+// UpdateOrgAvatarOption options when updating an organization's avatar
+type UpdateOrgAvatarOption struct {
+	// image encoded in base64, it is cropped to a square and resized
+	//
+	// required: true
+	Image string `json:"image" binding:"Required"`
 }
@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ProjectAutomationRule represents a rule which moves the cards of a project to a board
+// when their issues or pull requests trigger its event
+type ProjectAutomationRule struct {
+	ID        int64 `json:"id"`
+	ProjectID int64 `json:"project_id"`
+	// enum: closed,reopened,merged,labeled
+	Event string `json:"event"`
+	// label of the labeled event
+	LabelID int64 `json:"label_id,omitempty"`
+	// board the cards are moved to, 0 is the uncategorized board
+	BoardID int64 `json:"board_id"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateProjectAutomationRuleOption options for creating an automation rule of a project
+type CreateProjectAutomationRuleOption struct {
+	// required: true
+	// enum: closed,reopened,merged,labeled
+	Event string `json:"event" binding:"Required"`
+	// label of the labeled event, required by this event
+	LabelID int64 `json:"label_id"`
+	// board the cards are moved to, 0 is the uncategorized board
+	BoardID int64 `json:"board_id"`
+}
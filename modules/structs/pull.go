@@ -39,6 +39,11 @@ type PullRequest struct {
 	Head      *PRBranchInfo `json:"head"`
 	MergeBase string        `json:"merge_base"`
 
+	// index of the pull request this pull request is stacked on, 0 if it isn't stacked
+	BasePull int64 `json:"base_pull"`
+	// indexes of the open pull requests stacked on this pull request
+	StackedPulls []int64 `json:"stacked_pulls"`
+
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
 
@@ -77,6 +82,8 @@ type CreatePullRequestOption struct {
 	Labels    []int64  `json:"labels"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
+	// index of an open pull request of the repository to stack the pull request on, base must be its head branch
+	BasePull int64 `json:"base_pull"`
 }
 
 // EditPullRequestOption options when modify pull request
@@ -92,4 +99,7 @@ type EditPullRequestOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// index of an open pull request of the repository to stack the pull request on, its head branch becomes
+	// the base of the pull request; 0 unstacks the pull request
+	BasePull *int64 `json:"base_pull"`
 }
@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PullAutoPublish represents a work in progress pull request which is marked ready for review
+// once the status checks of its head commit pass
+type PullAutoPublish struct {
+	Index     int64 `json:"number"`
+	EnabledBy *User `json:"enabled_by"`
+	// users requested to review the pull request once it is published
+	Reviewers []*User `json:"reviewers"`
+	// teams requested to review the pull request once it is published
+	TeamReviewers []*Team `json:"team_reviewers"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// PullAutoPublishOptions are options to publish a work in progress pull request automatically
+type PullAutoPublishOptions struct {
+	Reviewers     []string `json:"reviewers"`
+	TeamReviewers []string `json:"team_reviewers"`
+}
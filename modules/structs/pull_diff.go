@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// DiffFile represents a file of a diff with the lines of its hunks
+type DiffFile struct {
+	Name string `json:"name"`
+	// previous name of a renamed file
+	OldName string `json:"old_name,omitempty"`
+	// enum: added,modified,deleted,renamed,copied
+	Status    string `json:"status"`
+	IsBinary  bool   `json:"binary,omitempty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	// whether lines of the file are left out since the diff is too large
+	IsIncomplete bool        `json:"incomplete,omitempty"`
+	Hunks        []*DiffHunk `json:"hunks"`
+}
+
+// DiffHunk represents a hunk of the diff of a file
+type DiffHunk struct {
+	// header of the hunk, e.g. @@ -1,3 +1,4 @@
+	Header string      `json:"header"`
+	Lines  []*DiffLine `json:"lines"`
+}
+
+// DiffLine represents a line of a hunk
+type DiffLine struct {
+	// enum: context,add,delete
+	Type string `json:"type"`
+	// line number in the old file, it's 0 for added lines
+	OldLine int `json:"old_line"`
+	// line number in the new file, it's 0 for deleted lines
+	NewLine int `json:"new_line"`
+	// content of the line without the leading +, - or space
+	Content string `json:"content"`
+	// whether the line belongs to a block which was moved but not changed
+	Moved bool `json:"moved"`
+}
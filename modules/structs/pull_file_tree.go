@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// PullFileTreeNode represents a directory or a changed file of the tree of the files changed by a
+// pull request, the changes of a directory sum up the changes of the files it contains
+type PullFileTreeNode struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// enum: dir,file
+	Type string `json:"type"`
+	// status of a file
+	// enum: added,modified,deleted,renamed,copied,changed
+	Status string `json:"status,omitempty"`
+	// previous path of a renamed or copied file
+	OldPath   string `json:"old_path,omitempty"`
+	IsBinary  bool   `json:"binary,omitempty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	// number of changed files of a directory
+	ChangedFiles int `json:"changed_files,omitempty"`
+	// link of the diff of a file
+	DiffURL  string              `json:"diff_url,omitempty"`
+	Children []*PullFileTreeNode `json:"children,omitempty"`
+}
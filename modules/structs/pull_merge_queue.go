@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// MergeQueueEntry represents a pull request in the merge queue of its base branch
+type MergeQueueEntry struct {
+	// position of the pull request in the queue, starting at 1
+	Position   int    `json:"position"`
+	Index      int64  `json:"number"`
+	BaseBranch string `json:"base_branch"`
+	// enum: waiting,testing,failed
+	Status     string `json:"status"`
+	MergeStyle string `json:"merge_style"`
+	EnqueuedBy *User  `json:"enqueued_by"`
+	// commit of the merge tested by the queue
+	TestSHA    string `json:"test_sha"`
+	FailReason string `json:"fail_reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
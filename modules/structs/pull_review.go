@@ -37,6 +37,8 @@ type PullReview struct {
 	Stale             bool            `json:"stale"`
 	Official          bool            `json:"official"`
 	CodeCommentsCount int             `json:"comments_count"`
+	// items of the review checklist of the repository acknowledged by the reviewer
+	Checklist []string `json:"checklist"`
 	// swagger:strfmt date-time
 	Submitted time.Time `json:"submitted_at"`
 
@@ -62,6 +64,8 @@ type PullReviewComment struct {
 	DiffHunk     string `json:"diff_hunk"`
 	LineNum      uint64 `json:"position"`
 	OldLineNum   uint64 `json:"original_position"`
+	// whether the comment is on the line `position` of the message of the commit `commit_id`
+	CommitMessage bool `json:"commit_message"`
 
 	HTMLURL     string `json:"html_url"`
 	HTMLPullURL string `json:"pull_request_url"`
@@ -73,6 +77,8 @@ type CreatePullReviewOptions struct {
 	Body     string                    `json:"body"`
 	CommitID string                    `json:"commit_id"`
 	Comments []CreatePullReviewComment `json:"comments"`
+	// items of the review checklist of the repository to acknowledge, an approval has to acknowledge all of them
+	Checklist []string `json:"checklist"`
 }
 
 // CreatePullReviewComment represent a review comment for creation api
@@ -84,12 +90,16 @@ type CreatePullReviewComment struct {
 	OldLineNum int64 `json:"old_position"`
 	// if comment to new file line or 0
 	NewLineNum int64 `json:"new_position"`
+	// sha of a commit of the pull request to comment the line `new_position` of its message instead of a file
+	CommitID string `json:"commit_id"`
 }
 
 // SubmitPullReviewOptions are options to submit a pending pull review
 type SubmitPullReviewOptions struct {
 	Event ReviewStateType `json:"event"`
 	Body  string          `json:"body"`
+	// items of the review checklist of the repository to acknowledge, an approval has to acknowledge all of them
+	Checklist []string `json:"checklist"`
 }
 
 // PullReviewRequestOptions are options to add or remove pull review requests
@@ -97,3 +107,11 @@ type PullReviewRequestOptions struct {
 	Reviewers     []string `json:"reviewers"`
 	TeamReviewers []string `json:"team_reviewers"`
 }
+
+// ApplyPullReviewSuggestionsOptions are options to apply the suggestions of code comments
+type ApplyPullReviewSuggestionsOptions struct {
+	// ids of the code comments whose ```suggestion blocks are applied
+	CommentIDs []int64 `json:"comment_ids" binding:"Required"`
+	// message of the commit, a default message is used if it's empty
+	Message string `json:"message"`
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ReviewEnvironment represents a preview of the changes of a pull request deployed by CI
+type ReviewEnvironment struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	State       StatusState `json:"state"`
+	Description string      `json:"description"`
+	Creator     *User       `json:"creator"`
+	// an environment expires when its pull request is closed
+	Expired bool `json:"expired"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateReviewEnvironmentOption options to register the review environment of a pull request,
+// the environment of the pull request with the same name is updated
+type CreateReviewEnvironmentOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	URL string `json:"url" binding:"Required;ValidUrl"`
+	// pending by default
+	State       StatusState `json:"state"`
+	Description string      `json:"description"`
+}
@@ -0,0 +1,22 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ReviewerStats represents the review activity of a reviewer over a period
+type ReviewerStats struct {
+	Reviewer *User `json:"reviewer"`
+	// number of submitted reviews, i.e. approvals, rejections and comments
+	Reviews    int64 `json:"reviews"`
+	Approvals  int64 `json:"approvals"`
+	Rejections int64 `json:"rejections"`
+	// number of code comments of the submitted reviews
+	Comments int64 `json:"comments"`
+	// number of reviews requested from the reviewer
+	ReviewRequests int64 `json:"review_requests"`
+	// ratio of the approvals to the approvals and the rejections
+	ApprovalRatio float64 `json:"approval_ratio"`
+	// average number of seconds from a review request until the review, 0 if no requested review was submitted
+	AverageTurnaround int64 `json:"average_turnaround"`
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PullViewedFile represents a file of a pull request the user marked as viewed
+type PullViewedFile struct {
+	Path string `json:"path"`
+	// whether the file changed since it was marked as viewed, the mark doesn't apply anymore then
+	HasChanged bool `json:"has_changed"`
+	// swagger:strfmt date-time
+	Viewed time.Time `json:"viewed_at"`
+}
+
+// UpdatePullViewedFilesOption options to mark files of a pull request as viewed or not viewed
+type UpdatePullViewedFilesOption struct {
+	// paths of the files to mark as viewed in their current version
+	Viewed []string `json:"viewed"`
+	// paths of the files to mark as not viewed
+	Unviewed []string `json:"unviewed"`
+}
@@ -0,0 +1,19 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// StorageQuota represents the storage quota of a user or an organization and the storage used by
+// its repositories, the sizes are in bytes
+type StorageQuota struct {
+	// maximum storage size, -1 if it's unlimited
+	Limit int64 `json:"limit"`
+	Used  int64 `json:"used"`
+	// size of the git objects and references
+	Git int64 `json:"git"`
+	// size of the LFS objects
+	LFS int64 `json:"lfs"`
+	// size of the attachments of the issues, comments and releases
+	Attachments int64 `json:"attachments"`
+}
@@ -25,6 +25,16 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Keywords closing the referenced issues in addition to the keywords of the instance (Built-in issue tracker)
+	CloseKeywords []string `json:"close_keywords"`
+	// Keywords reopening the referenced issues in addition to the keywords of the instance (Built-in issue tracker)
+	ReopenKeywords []string `json:"reopen_keywords"`
+	// Languages of the built-in keyword sets added to the keywords of the instance (Built-in issue tracker)
+	KeywordLanguages []string `json:"keyword_languages"`
+	// Prevent the references from other repositories from closing or reopening issues (Built-in issue tracker)
+	DisableCrossRepoClose bool `json:"disable_cross_repo_close"`
+	// Only close or reopen issues when pull requests are merged into the default branch (Built-in issue tracker)
+	CloseOnDefaultBranchOnly bool `json:"close_on_default_branch_only"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -45,6 +55,13 @@ type ExternalWiki struct {
 	ExternalWikiURL string `json:"external_wiki_url"`
 }
 
+// ForkDivergence represents the number of commits the default branch of a fork
+// is ahead or behind the default branch of the forked repository
+type ForkDivergence struct {
+	AheadBy  int `json:"ahead_by"`
+	BehindBy int `json:"behind_by"`
+}
+
 // Repository represents a repository
 type Repository struct {
 	ID            int64       `json:"id"`
@@ -77,6 +94,7 @@ type Repository struct {
 	// swagger:strfmt date-time
 	Updated                   time.Time        `json:"updated_at"`
 	Permissions               *Permission      `json:"permissions,omitempty"`
+	ForkDivergence            *ForkDivergence  `json:"fork_divergence,omitempty"`
 	HasIssues                 bool             `json:"has_issues"`
 	InternalTracker           *InternalTracker `json:"internal_tracker,omitempty"`
 	ExternalTracker           *ExternalTracker `json:"external_tracker,omitempty"`
@@ -89,6 +107,9 @@ type Repository struct {
 	AllowRebase               bool             `json:"allow_rebase"`
 	AllowRebaseMerge          bool             `json:"allow_rebase_explicit"`
 	AllowSquash               bool             `json:"allow_squash_merge"`
+	ReviewChecklist           []string         `json:"review_checklist"`
+	License                   string           `json:"license"`
+	HasCodeOfConduct          bool             `json:"has_code_of_conduct"`
 	AvatarURL                 string           `json:"avatar_url"`
 	Internal                  bool             `json:"internal"`
 }
@@ -166,6 +187,8 @@ type EditRepoOption struct {
 	AllowRebaseMerge *bool `json:"allow_rebase_explicit,omitempty"`
 	// either `true` to allow squash-merging pull requests, or `false` to prevent squash-merging. `has_pull_requests` must be `true`.
 	AllowSquash *bool `json:"allow_squash_merge,omitempty"`
+	// items reviewers have to acknowledge to approve a pull request. `has_pull_requests` must be `true`.
+	ReviewChecklist *[]string `json:"review_checklist,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
 }
@@ -195,6 +218,14 @@ type TransferRepoOption struct {
 	TeamIDs *[]int64 `json:"team_ids"`
 }
 
+// UpdateRepoAvatarOption options when updating a repository's avatar
+type UpdateRepoAvatarOption struct {
+	// image encoded in base64, it is cropped to a square and resized
+	//
+	// required: true
+	Image string `json:"image" binding:"Required"`
+}
+
 // GitServiceType represents a git service
 type GitServiceType int
 
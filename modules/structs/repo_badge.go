@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepoBadge represents a badge or a quick link shown on the home of a repository
+type RepoBadge struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// the URL the badge links to
+	LinkURL string `json:"link_url"`
+	// the URL of the image of the badge, the badge is shown as a link if it is empty
+	ImageURL string `json:"image_url"`
+	// the badges are shown in ascending order
+	Sort int `json:"sort"`
+}
+
+// CreateRepoBadgeOption options for adding a badge to a repository
+type CreateRepoBadgeOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// required: true
+	LinkURL  string `json:"link_url" binding:"Required;ValidUrl"`
+	ImageURL string `json:"image_url" binding:"ValidUrl"`
+	Sort     int    `json:"sort"`
+}
+
+// EditRepoBadgeOption options for editing a badge of a repository
+type EditRepoBadgeOption struct {
+	Name     *string `json:"name"`
+	LinkURL  *string `json:"link_url"`
+	ImageURL *string `json:"image_url"`
+	Sort     *int    `json:"sort"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// BlameHunk represents consecutive lines of a file last changed by the same commit
+type BlameHunk struct {
+	SHA string `json:"sha"`
+	// number of the first line of the hunk, starting at 1
+	StartLine int      `json:"start_line"`
+	LineCount int      `json:"line_count"`
+	Lines     []string `json:"lines"`
+	// age of the commit compared to the other commits of the blame, from 0 for the oldest to 1 for the most recent
+	AgeScore float64 `json:"age_score"`
+	// age of the commit distributed between 0 for the oldest and age_buckets-1 for the most recent commits
+	AgeBucket int `json:"age_bucket"`
+}
+
+// BlameCommit contains information of a commit referenced by the hunks of a blame
+type BlameCommit struct {
+	*CommitMeta
+	HTMLURL   string      `json:"html_url"`
+	Author    *CommitUser `json:"author"`
+	Committer *CommitUser `json:"committer"`
+	Message   string      `json:"message"`
+}
+
+// FileBlameResponse contains the blame of a file
+type FileBlameResponse struct {
+	Path string `json:"path"`
+	// the commit the blame was computed at
+	SHA string `json:"sha"`
+	// whether the revisions listed in the .git-blame-ignore-revs file of the default branch were ignored
+	IgnoresRevs bool `json:"ignores_revs"`
+	// number of age buckets the hunks are distributed into
+	AgeBuckets int            `json:"age_buckets"`
+	Hunks      []*BlameHunk   `json:"hunks"`
+	Commits    []*BlameCommit `json:"commits"`
+}
@@ -23,27 +23,34 @@ type Branch struct {
 
 // BranchProtection represents a branch protection for a repository
 type BranchProtection struct {
-	BranchName                    string   `json:"branch_name"`
-	EnablePush                    bool     `json:"enable_push"`
-	EnablePushWhitelist           bool     `json:"enable_push_whitelist"`
-	PushWhitelistUsernames        []string `json:"push_whitelist_usernames"`
-	PushWhitelistTeams            []string `json:"push_whitelist_teams"`
-	PushWhitelistDeployKeys       bool     `json:"push_whitelist_deploy_keys"`
-	EnableMergeWhitelist          bool     `json:"enable_merge_whitelist"`
-	MergeWhitelistUsernames       []string `json:"merge_whitelist_usernames"`
-	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
-	EnableStatusCheck             bool     `json:"enable_status_check"`
-	StatusCheckContexts           []string `json:"status_check_contexts"`
-	RequiredApprovals             int64    `json:"required_approvals"`
-	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
-	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
-	ApprovalsWhitelistTeams       []string `json:"approvals_whitelist_teams"`
-	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
-	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
-	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
-	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
-	RequireSignedCommits          bool     `json:"require_signed_commits"`
-	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	BranchName                    string               `json:"branch_name"`
+	EnablePush                    bool                 `json:"enable_push"`
+	EnablePushWhitelist           bool                 `json:"enable_push_whitelist"`
+	PushWhitelistUsernames        []string             `json:"push_whitelist_usernames"`
+	PushWhitelistTeams            []string             `json:"push_whitelist_teams"`
+	PushWhitelistDeployKeys       bool                 `json:"push_whitelist_deploy_keys"`
+	EnableMergeWhitelist          bool                 `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames       []string             `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams           []string             `json:"merge_whitelist_teams"`
+	EnableStatusCheck             bool                 `json:"enable_status_check"`
+	StatusCheckContexts           []string             `json:"status_check_contexts"`
+	RequiredApprovals             int64                `json:"required_approvals"`
+	EnableApprovalsWhitelist      bool                 `json:"enable_approvals_whitelist"`
+	ApprovalsWhitelistUsernames   []string             `json:"approvals_whitelist_username"`
+	ApprovalsWhitelistTeams       []string             `json:"approvals_whitelist_teams"`
+	BlockOnRejectedReviews        bool                 `json:"block_on_rejected_reviews"`
+	BlockOnOfficialReviewRequests bool                 `json:"block_on_official_review_requests"`
+	BlockOnOutdatedBranch         bool                 `json:"block_on_outdated_branch"`
+	DismissStaleApprovals         bool                 `json:"dismiss_stale_approvals"`
+	RequireSignedCommits          bool                 `json:"require_signed_commits"`
+	ProtectedFilePatterns         string               `json:"protected_file_patterns"`
+	EnableMergeQueue              bool                 `json:"enable_merge_queue"`
+	RequireCodeOwnerApproval      bool                 `json:"require_code_owner_approval"`
+	RequireLinearHistory          bool                 `json:"require_linear_history"`
+	DismissApprovalsOnPush        bool                 `json:"dismiss_approvals_on_push"`
+	DismissApprovalsOnForcePush   bool                 `json:"dismiss_approvals_on_force_push"`
+	ProtectedPathRules            []*ProtectedPathRule `json:"protected_path_rules"`
+	RequireSignOff                bool                 `json:"require_sign_off"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -52,27 +59,34 @@ type BranchProtection struct {
 
 // CreateBranchProtectionOption options for creating a branch protection
 type CreateBranchProtectionOption struct {
-	BranchName                    string   `json:"branch_name"`
-	EnablePush                    bool     `json:"enable_push"`
-	EnablePushWhitelist           bool     `json:"enable_push_whitelist"`
-	PushWhitelistUsernames        []string `json:"push_whitelist_usernames"`
-	PushWhitelistTeams            []string `json:"push_whitelist_teams"`
-	PushWhitelistDeployKeys       bool     `json:"push_whitelist_deploy_keys"`
-	EnableMergeWhitelist          bool     `json:"enable_merge_whitelist"`
-	MergeWhitelistUsernames       []string `json:"merge_whitelist_usernames"`
-	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
-	EnableStatusCheck             bool     `json:"enable_status_check"`
-	StatusCheckContexts           []string `json:"status_check_contexts"`
-	RequiredApprovals             int64    `json:"required_approvals"`
-	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
-	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
-	ApprovalsWhitelistTeams       []string `json:"approvals_whitelist_teams"`
-	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
-	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
-	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
-	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
-	RequireSignedCommits          bool     `json:"require_signed_commits"`
-	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	BranchName                    string               `json:"branch_name"`
+	EnablePush                    bool                 `json:"enable_push"`
+	EnablePushWhitelist           bool                 `json:"enable_push_whitelist"`
+	PushWhitelistUsernames        []string             `json:"push_whitelist_usernames"`
+	PushWhitelistTeams            []string             `json:"push_whitelist_teams"`
+	PushWhitelistDeployKeys       bool                 `json:"push_whitelist_deploy_keys"`
+	EnableMergeWhitelist          bool                 `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames       []string             `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams           []string             `json:"merge_whitelist_teams"`
+	EnableStatusCheck             bool                 `json:"enable_status_check"`
+	StatusCheckContexts           []string             `json:"status_check_contexts"`
+	RequiredApprovals             int64                `json:"required_approvals"`
+	EnableApprovalsWhitelist      bool                 `json:"enable_approvals_whitelist"`
+	ApprovalsWhitelistUsernames   []string             `json:"approvals_whitelist_username"`
+	ApprovalsWhitelistTeams       []string             `json:"approvals_whitelist_teams"`
+	BlockOnRejectedReviews        bool                 `json:"block_on_rejected_reviews"`
+	BlockOnOfficialReviewRequests bool                 `json:"block_on_official_review_requests"`
+	BlockOnOutdatedBranch         bool                 `json:"block_on_outdated_branch"`
+	DismissStaleApprovals         bool                 `json:"dismiss_stale_approvals"`
+	RequireSignedCommits          bool                 `json:"require_signed_commits"`
+	ProtectedFilePatterns         string               `json:"protected_file_patterns"`
+	EnableMergeQueue              bool                 `json:"enable_merge_queue"`
+	RequireCodeOwnerApproval      bool                 `json:"require_code_owner_approval"`
+	RequireLinearHistory          bool                 `json:"require_linear_history"`
+	DismissApprovalsOnPush        bool                 `json:"dismiss_approvals_on_push"`
+	DismissApprovalsOnForcePush   bool                 `json:"dismiss_approvals_on_force_push"`
+	ProtectedPathRules            []*ProtectedPathRule `json:"protected_path_rules"`
+	RequireSignOff                bool                 `json:"require_sign_off"`
 }
 
 // EditBranchProtectionOption options for editing a branch protection
@@ -97,4 +111,22 @@ type EditBranchProtectionOption struct {
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
 	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
+	EnableMergeQueue              *bool    `json:"enable_merge_queue"`
+	RequireCodeOwnerApproval      *bool    `json:"require_code_owner_approval"`
+	RequireLinearHistory          *bool    `json:"require_linear_history"`
+	DismissApprovalsOnPush        *bool    `json:"dismiss_approvals_on_push"`
+	DismissApprovalsOnForcePush   *bool    `json:"dismiss_approvals_on_force_push"`
+	RequireSignOff                *bool    `json:"require_sign_off"`
+	// the rules replace the current ones if they are given
+	ProtectedPathRules []*ProtectedPathRule `json:"protected_path_rules"`
+}
+
+// ProtectedPathRule requires the changes of the files matching a path pattern to be approved by
+// a member of a team before they are merged into a protected branch
+type ProtectedPathRule struct {
+	// a glob pattern of paths, e.g. migrations/**, a pattern without a slash matches the files of
+	// any directory by their names, e.g. *.tf
+	Pattern string `json:"pattern"`
+	// the name of the team of the organization owning the repository
+	Team string `json:"team"`
 }
@@ -30,13 +30,26 @@ type CommitUser struct {
 	Date string `json:"date"`
 }
 
+// CommitTrailer contains a trailer of a commit message, e.g. "Signed-off-by: Name <email>"
+type CommitTrailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// the identity of the trailer if its value is like "Name <email>"
+	Identity *Identity `json:"identity,omitempty"`
+	// the user matching the identity of the trailer
+	User *User `json:"user,omitempty"`
+}
+
 // RepoCommit contains information of a commit in the context of a repository.
 type RepoCommit struct {
-	URL       string      `json:"url"`
-	Author    *CommitUser `json:"author"`
-	Committer *CommitUser `json:"committer"`
-	Message   string      `json:"message"`
-	Tree      *CommitMeta `json:"tree"`
+	URL       string           `json:"url"`
+	Author    *CommitUser      `json:"author"`
+	Committer *CommitUser      `json:"committer"`
+	Message   string           `json:"message"`
+	Tree      *CommitMeta      `json:"tree"`
+	Trailers  []*CommitTrailer `json:"trailers"`
+	// whether the commit message has a Signed-off-by trailer of its author
+	SignedOff bool `json:"signed_off"`
 }
 
 // Commit contains information generated from a Git commit.
@@ -56,3 +69,40 @@ type CommitDateOptions struct {
 	// swagger:strfmt date-time
 	Committer time.Time `json:"committer"`
 }
+
+// CommitGraph represents a page of the commit graph of a repository, the rows and columns are
+// relative to the page
+type CommitGraph struct {
+	Width   int                  `json:"width"`
+	Height  int                  `json:"height"`
+	Commits []*CommitGraphCommit `json:"commits"`
+	Flows   []*CommitGraphFlow   `json:"flows"`
+}
+
+// CommitGraphCommit represents a commit and its position in the commit graph
+type CommitGraphCommit struct {
+	SHA     string      `json:"sha"`
+	HTMLURL string      `json:"html_url"`
+	Row     int         `json:"row"`
+	Column  int         `json:"column"`
+	FlowID  int64       `json:"flow_id"`
+	Refs    []string    `json:"refs"`
+	Subject string      `json:"subject"`
+	Author  *CommitUser `json:"author"`
+	Parents []string    `json:"parents"`
+}
+
+// CommitGraphFlow represents a lane of the commit graph, drawn with glyphs
+type CommitGraphFlow struct {
+	ID     int64               `json:"id"`
+	Color  int                 `json:"color"`
+	Glyphs []*CommitGraphGlyph `json:"glyphs"`
+}
+
+// CommitGraphGlyph represents a glyph at a position of the commit graph: one of '*', '|',
+// '/', '\', '_', '-' or '.'
+type CommitGraphGlyph struct {
+	Row    int    `json:"row"`
+	Column int    `json:"column"`
+	Glyph  string `json:"glyph"`
+}
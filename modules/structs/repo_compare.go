@@ -0,0 +1,22 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Compare represents the comparison of a reference of a base repository with a reference
+// of a head repository
+type Compare struct {
+	BaseRepo *RepositoryMeta `json:"base_repository"`
+	HeadRepo *RepositoryMeta `json:"head_repository"`
+	// the commit of the base from which the head diverged, or the base commit if the
+	// references have no common history
+	MergeBase    string `json:"merge_base"`
+	FilesChanged int    `json:"files_changed"`
+	TotalCommits int    `json:"total_commits"`
+	// the commits of the head since the merge base, only the first commits are listed
+	// if there are too many of them
+	Commits   []*Commit `json:"commits"`
+	Truncated bool      `json:"truncated"`
+	HTMLURL   string    `json:"html_url"`
+}
@@ -48,6 +48,32 @@ type UpdateFileOptions struct {
 	FromPath string `json:"from_path" binding:"MaxSize(500)"`
 }
 
+// ChangeFileOperation holds a change of a file of ChangeFilesOptions
+type ChangeFileOperation struct {
+	// what to do with the file
+	// required: true
+	// enum: create,update,delete
+	Operation string `json:"operation" binding:"Required;In(create,update,delete)"`
+	// path of the file to create, update or delete
+	// required: true
+	Path string `json:"path" binding:"Required;MaxSize(500)"`
+	// content must be base64 encoded, it's required to create or update the file
+	Content string `json:"content"`
+	// sha is the SHA of the file to update or delete, it's required to update or delete the file
+	SHA string `json:"sha"`
+	// from_path (optional) is the path of the original file which will be moved/renamed to `path` by an update
+	FromPath string `json:"from_path" binding:"MaxSize(500)"`
+}
+
+// ChangeFilesOptions options for creating, updating or deleting several files in a single commit
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+type ChangeFilesOptions struct {
+	FileOptions
+	// the changes of the files, a file can only be changed once
+	// required: true
+	Files []*ChangeFileOperation `json:"files" binding:"Required"`
+}
+
 // FileLinksResponse contains the links for a repo's file
 type FileLinksResponse struct {
 	Self    *string `json:"self"`
@@ -76,6 +102,23 @@ type ContentsResponse struct {
 	// `submodule_git_url` is populated when `type` is `submodule`, otherwise null
 	SubmoduleGitURL *string            `json:"submodule_git_url"`
 	Links           *FileLinksResponse `json:"_links"`
+	// `lines` is populated when a range of lines of a file is requested, `content` is null then
+	Lines *ContentsLines `json:"lines,omitempty"`
+}
+
+// ContentsLines contains a range of lines of a file
+type ContentsLines struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// number of lines of the file
+	Total int `json:"total"`
+	// text of the lines
+	Content string `json:"content"`
+	// `highlighted` is populated when the highlighted lines are requested, it contains the HTML of
+	// each line with the chroma classes
+	Highlighted []string `json:"highlighted,omitempty"`
+	// `lexer` is populated when the highlighted lines are requested
+	Lexer string `json:"lexer,omitempty"`
 }
 
 // FileCommitResponse contains information generated from a Git commit for a repo's file.
@@ -96,6 +139,14 @@ type FileResponse struct {
 	Verification *PayloadCommitVerification `json:"verification"`
 }
 
+// FilesResponse contains information about the files of a repo changed in a single commit
+type FilesResponse struct {
+	// the contents of the files in the order of the changes, the contents of a deleted file is null
+	Files        []*ContentsResponse        `json:"files"`
+	Commit       *FileCommitResponse        `json:"commit"`
+	Verification *PayloadCommitVerification `json:"verification"`
+}
+
 // FileDeleteResponse contains information about a repo's file that was deleted
 type FileDeleteResponse struct {
 	Content      interface{}                `json:"content"` // to be set to nil
@@ -0,0 +1,16 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// MentionCandidate represents a user or a team which can be mentioned in the issues and pull requests of a repository
+type MentionCandidate struct {
+	// enum: user,team
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	FullName  string `json:"full_name"`
+	AvatarURL string `json:"avatar_url"`
+	// number of members of a team, not set for users
+	NumMembers int `json:"num_members,omitempty"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// CodeSearchResults results of a code search
+type CodeSearchResults struct {
+	TotalCount int64                 `json:"total_count"`
+	Items      []*CodeSearchResult   `json:"items"`
+	Languages  []*CodeSearchLanguage `json:"languages"`
+}
+
+// CodeSearchResult a file matching a code search
+type CodeSearchResult struct {
+	Repository *RepositoryMeta `json:"repository"`
+	Path       string          `json:"path"`
+	Language   string          `json:"language"`
+	// SHA of the searched commit
+	CommitID string `json:"commit_id"`
+	// link to the first match of the file
+	HTMLURL string `json:"html_url"`
+	// lines around the first match of the file
+	Lines []*CodeSearchLine `json:"lines"`
+}
+
+// CodeSearchLine a line of a code search result
+type CodeSearchLine struct {
+	// line number, starting at 1
+	Number  int    `json:"number"`
+	Content string `json:"content"`
+}
+
+// CodeSearchLanguage number of matching files of a language
+type CodeSearchLanguage struct {
+	Language string `json:"language"`
+	Color    string `json:"color"`
+	Count    int    `json:"count"`
+}
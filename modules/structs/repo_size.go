@@ -0,0 +1,22 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoSize represents the breakdown of the storage used by a repository, the sizes are in bytes
+type RepoSize struct {
+	// size of the git objects and references
+	Git int64 `json:"git"`
+	// size of the LFS objects
+	LFS int64 `json:"lfs"`
+	// size of the attachments of the issues, comments and releases
+	Attachments int64 `json:"attachments"`
+	Total       int64 `json:"total"`
+	// swagger:strfmt date-time
+	Computed time.Time `json:"computed_at"`
+}
@@ -0,0 +1,20 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// CodeSymbol represents a definition found in a file of the default branch of a repository
+type CodeSymbol struct {
+	Name string `json:"name"`
+	// kind of definition, e.g. function, method, class or type
+	Kind     string `json:"kind"`
+	Language string `json:"language"`
+	Path     string `json:"path"`
+	// line of the definition, starting at 1
+	Line int `json:"line"`
+	// SHA of the blob of the file
+	SHA string `json:"sha"`
+	// link to the definition at the indexed commit
+	HTMLURL string `json:"html_url"`
+}
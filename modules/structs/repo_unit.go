@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// EditRepoUnitsOption options for enabling and disabling the units of a repository
+type EditRepoUnitsOption struct {
+	// units to enable
+	// example: ["repo.wiki","repo.projects"]
+	Enable []string `json:"enable"`
+	// units to disable
+	// example: ["repo.issues"]
+	Disable []string `json:"disable"`
+}
+
+// BulkEditRepoUnitsOption options for enabling and disabling the units of many repositories
+type BulkEditRepoUnitsOption struct {
+	// units to enable
+	// example: ["repo.wiki","repo.projects"]
+	Enable []string `json:"enable"`
+	// units to disable
+	// example: ["repo.issues"]
+	Disable []string `json:"disable"`
+	// glob pattern the names of the repositories to update have to match
+	// example: service-*
+	Pattern string `json:"pattern"`
+	// names of the repositories to update, all the repositories matching the pattern are updated if empty
+	Repositories []string `json:"repositories"`
+}
+
+// RepoUnitsTask represents an asynchronous update of the units of many repositories
+type RepoUnitsTask struct {
+	ID int64 `json:"id"`
+	// status of the task, one of queued, running, failed or finished
+	Status string `json:"status"`
+	// error which made the task fail
+	Error string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at"`
+	// result for each repository once the task has finished
+	Results []*RepoUnitsResult `json:"results"`
+}
+
+// RepoUnitsResult the result of the update of the units of a repository
+type RepoUnitsResult struct {
+	Repository string `json:"repository"`
+	// whether units have been enabled or disabled
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
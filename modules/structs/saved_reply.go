@@ -0,0 +1,36 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// SavedReply represents a reusable reply of a user or of an organization
+type SavedReply struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// the user or the organization owning the reply
+	Owner *User `json:"owner"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateSavedReplyOption options for creating a saved reply
+type CreateSavedReplyOption struct {
+	// required: true
+	Title string `json:"title" binding:"Required;MaxSize(255)"`
+	// required: true
+	Content string `json:"content" binding:"Required"`
+}
+
+// EditSavedReplyOption options for editing a saved reply
+type EditSavedReplyOption struct {
+	Title   *string `json:"title" binding:"OmitEmpty;MaxSize(255)"`
+	Content *string `json:"content"`
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// SearchFacet the number of the results of a type of a global search
+type SearchFacet struct {
+	// type of the results: repositories, code, issues, pulls, users or organizations
+	Type  string `json:"type"`
+	Count int64  `json:"count"`
+}
+
+// GlobalSearchResults results of a global search, only the results of the requested type are listed
+// unless no type is requested, then the first results of every type are listed
+type GlobalSearchResults struct {
+	Facets        []*SearchFacet      `json:"facets"`
+	Repositories  []*Repository       `json:"repositories"`
+	Code          []*CodeSearchResult `json:"code"`
+	Issues        []*Issue            `json:"issues"`
+	PullRequests  []*Issue            `json:"pull_requests"`
+	Users         []*User             `json:"users"`
+	Organizations []*Organization     `json:"organizations"`
+}
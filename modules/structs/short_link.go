@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ShortLink represents a short link to lines of a file or to the diff of a commit
+type ShortLink struct {
+	Token string `json:"token"`
+	// the short link, which can be resolved by signed in users who can read the code of the repository
+	URL string `json:"url"`
+	// the permalink the short link resolves to
+	Permalink string `json:"permalink"`
+	// enum: file,diff
+	Type      string `json:"type"`
+	CommitID  string `json:"commit_id"`
+	Path      string `json:"path"`
+	LineStart int    `json:"line_start"`
+	LineEnd   int    `json:"line_end"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateShortLinkOption options for creating a short link
+type CreateShortLinkOption struct {
+	// lines of a file or the diff of a commit, defaults to file
+	// enum: file,diff
+	Type string `json:"type" binding:"In(,file,diff)"`
+	// branch, tag or commit the link is created at, the commit it refers to is kept. Defaults to the default branch
+	Ref string `json:"ref"`
+	// path of the file, it is optional for the diff of a commit
+	Path string `json:"path"`
+	// first line of the file or of the hunk in the new version of a diff
+	LineStart int `json:"line_start"`
+	// last line of the file, it is ignored for a diff
+	LineEnd int `json:"line_end"`
+}
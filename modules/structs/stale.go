@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// StalePolicy represents the policy of a repository to mark its inactive issues and pull
+// requests as stale and to close them if they stay inactive
+type StalePolicy struct {
+	Enabled        bool `json:"enabled"`
+	IncludeIssues  bool `json:"include_issues"`
+	IncludePulls   bool `json:"include_pulls"`
+	DaysUntilStale int  `json:"days_until_stale"`
+	// the stale issues are never closed if it is 0
+	DaysUntilClose int      `json:"days_until_close"`
+	StaleLabel     string   `json:"stale_label"`
+	ExemptLabels   []string `json:"exempt_labels"`
+	WarningComment string   `json:"warning_comment"`
+	CloseComment   string   `json:"close_comment"`
+	// the user on behalf of which the actions are taken
+	Doer *User `json:"doer"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditStalePolicyOption options for creating or replacing the stale policy of a repository
+type EditStalePolicyOption struct {
+	Enabled       bool `json:"enabled"`
+	IncludeIssues bool `json:"include_issues"`
+	IncludePulls  bool `json:"include_pulls"`
+	// required: true
+	DaysUntilStale int `json:"days_until_stale" binding:"Required"`
+	// the stale issues are never closed if it is 0
+	DaysUntilClose int `json:"days_until_close"`
+	// the label is created if neither the repository nor its organization has it, defaults to "stale"
+	StaleLabel     string   `json:"stale_label" binding:"MaxSize(50)"`
+	ExemptLabels   []string `json:"exempt_labels"`
+	WarningComment string   `json:"warning_comment"`
+	CloseComment   string   `json:"close_comment"`
+}
+
+// StaleAction represents an action taken on an issue by the stale policy of its repository
+type StaleAction struct {
+	// enum: mark,unmark,close
+	Action string `json:"action"`
+	Issue  *Issue `json:"issue"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
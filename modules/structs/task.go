@@ -9,7 +9,8 @@ type TaskType int
 
 // all kinds of task types
 const (
-	TaskTypeMigrateRepo TaskType = iota // migrate repository from external or local disk
+	TaskTypeMigrateRepo     TaskType = iota // migrate repository from external or local disk
+	TaskTypeUpdateRepoUnits                 // enable or disable the units of many repositories
 )
 
 // Name returns the task type name
@@ -17,6 +18,8 @@ func (taskType TaskType) Name() string {
 	switch taskType {
 	case TaskTypeMigrateRepo:
 		return "Migrate Repository"
+	case TaskTypeUpdateRepoUnits:
+		return "Update Repository Units"
 	}
 	return ""
 }
@@ -32,3 +35,20 @@ const (
 	TaskStatusFailed                     // 3 task is failed
 	TaskStatusFinished                   // 4 task is finished
 )
+
+// Name returns the task status name
+func (status TaskStatus) Name() string {
+	switch status {
+	case TaskStatusQueue:
+		return "queued"
+	case TaskStatusRunning:
+		return "running"
+	case TaskStatusStopped:
+		return "stopped"
+	case TaskStatusFailed:
+		return "failed"
+	case TaskStatusFinished:
+		return "finished"
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// TwoFactorStatus represents the compliance of a user with the two-factor authentication requirements
+type TwoFactorStatus struct {
+	// whether the user must enroll into two-factor authentication
+	Required bool `json:"required"`
+	Enrolled bool `json:"enrolled"`
+	// whether the user is enrolled or isn't required to
+	Compliant bool `json:"compliant"`
+	// whether the grace period of a user who isn't compliant has ended
+	Enforced bool `json:"enforced"`
+	// swagger:strfmt date-time
+	GraceDeadline *time.Time `json:"grace_deadline,omitempty"`
+	// whether the instance requires the user to enroll
+	RequiredByInstance bool `json:"required_by_instance"`
+	// names of the organizations requiring their members to enroll
+	RequiredByOrgs []string `json:"required_by_orgs"`
+}
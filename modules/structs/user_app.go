@@ -12,10 +12,15 @@ import (
 // AccessToken represents an API access token.
 // swagger:response AccessToken
 type AccessToken struct {
-	ID             int64  `json:"id"`
-	Name           string `json:"name"`
-	Token          string `json:"sha1"`
-	TokenLastEight string `json:"token_last_eight"`
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Token          string   `json:"sha1"`
+	TokenLastEight string   `json:"token_last_eight"`
+	Scopes         []string `json:"scopes"`
+	// full names of the repositories the token is restricted to
+	Repositories []string `json:"repositories"`
+	// names of the users and organizations whose repositories the token is restricted to
+	Owners []string `json:"owners"`
 }
 
 // AccessTokenList represents a list of API access token.
@@ -23,9 +28,25 @@ type AccessToken struct {
 type AccessTokenList []*AccessToken
 
 // CreateAccessTokenOption options when create access token
-// swagger:parameters userCreateToken
+// swagger:model
 type CreateAccessTokenOption struct {
 	Name string `json:"name" binding:"Required"`
+	// scopes restricting the permissions of the token, e.g. read:code and write:status for CI
+	// systems. The token has full access to the account without scopes.
+	Scopes []string `json:"scopes"`
+	// full names of the repositories, like "owner/name", the token is restricted to
+	Repositories []string `json:"repositories"`
+	// names of the users and organizations whose repositories the token is restricted to
+	Owners []string `json:"owners"`
+}
+
+// EditAccessTokenOption options when editing an access token, its secret never changes
+// swagger:model
+type EditAccessTokenOption struct {
+	Name         *string   `json:"name"`
+	Scopes       *[]string `json:"scopes"`
+	Repositories *[]string `json:"repositories"`
+	Owners       *[]string `json:"owners"`
 }
 
 // CreateOAuth2ApplicationOptions holds options to create an oauth2 application
@@ -0,0 +1,143 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// UpdateRepositoriesUnits adds a task enabling and disabling the units of the repositories of the owner
+func UpdateRepositoriesUnits(doer, owner *models.User, opts structs.BulkEditRepoUnitsOption) (*models.Task, error) {
+	bs, err := json.Marshal(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var task = models.Task{
+		DoerID:         doer.ID,
+		OwnerID:        owner.ID,
+		Type:           structs.TaskTypeUpdateRepoUnits,
+		Status:         structs.TaskStatusQueue,
+		PayloadContent: string(bs),
+	}
+	if err := models.CreateTask(&task); err != nil {
+		return nil, err
+	}
+
+	return &task, taskQueue.Push(&task)
+}
+
+func runUpdateRepoUnitsTask(t *models.Task) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to update repository units: %v", e)
+			log.Critical("PANIC during runUpdateRepoUnitsTask[%d] by DoerID[%d] for OwnerID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, t.OwnerID, e, log.Stack(2))
+		}
+		if err == nil {
+			return
+		}
+
+		t.EndTime = timeutil.TimeStampNow()
+		t.Status = structs.TaskStatusFailed
+		t.Errors = err.Error()
+		if err := t.UpdateCols("status", "errors", "end_time"); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}()
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusRunning
+	if err = t.UpdateCols("start_time", "status"); err != nil {
+		return
+	}
+
+	var opts structs.BulkEditRepoUnitsOption
+	if err = json.Unmarshal([]byte(t.PayloadContent), &opts); err != nil {
+		return
+	}
+	enable, disable, err := repo_module.ParseUnitsToToggle(opts.Enable, opts.Disable)
+	if err != nil {
+		return
+	}
+	if err = t.LoadOwner(); err != nil {
+		return
+	}
+	repos, results, err := findRepositoriesOfUnitsTask(t.Owner, opts)
+	if err != nil {
+		return
+	}
+
+	for _, repo := range repos {
+		result := &structs.RepoUnitsResult{Repository: repo.Name}
+		changed, err := repo_module.UpdateRepositoryUnits(repo, enable, disable)
+		if err != nil {
+			log.Error("UpdateRepositoryUnits[%d]: %v", repo.ID, err)
+			result.Error = err.Error()
+		}
+		result.Changed = changed
+		results = append(results, result)
+	}
+
+	bs, err := json.Marshal(results)
+	if err != nil {
+		return
+	}
+	t.Result = string(bs)
+	t.EndTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusFinished
+	return t.UpdateCols("result", "status", "end_time")
+}
+
+// findRepositoriesOfUnitsTask returns the repositories of the owner to update, with the
+// results of the requested repositories which don't exist
+func findRepositoriesOfUnitsTask(owner *models.User, opts structs.BulkEditRepoUnitsOption) ([]*models.Repository, []*structs.RepoUnitsResult, error) {
+	var g glob.Glob
+	if pattern := strings.TrimSpace(opts.Pattern); len(pattern) > 0 {
+		var err error
+		if g, err = glob.Compile(pattern); err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+
+	lowerNames := make([]string, 0, len(opts.Repositories))
+	for _, name := range opts.Repositories {
+		lowerNames = append(lowerNames, strings.ToLower(name))
+	}
+	if err := owner.GetRepositories(models.ListOptions{}, lowerNames...); err != nil {
+		return nil, nil, err
+	}
+
+	var results []*structs.RepoUnitsResult
+	for _, name := range opts.Repositories {
+		found := false
+		for _, repo := range owner.Repos {
+			if repo.LowerName == strings.ToLower(name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			results = append(results, &structs.RepoUnitsResult{Repository: name, Error: "repository does not exist"})
+		}
+	}
+
+	repos := make([]*models.Repository, 0, len(owner.Repos))
+	for _, repo := range owner.Repos {
+		if g == nil || g.Match(repo.Name) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, results, nil
+}
@@ -26,6 +26,8 @@ func Run(t *models.Task) error {
 	switch t.Type {
 	case structs.TaskTypeMigrateRepo:
 		return runMigrateTask(t)
+	case structs.TaskTypeUpdateRepoUnits:
+		return runUpdateRepoUnitsTask(t)
 	default:
 		return fmt.Errorf("Unknown task type: %d", t.Type)
 	}
@@ -7,11 +7,13 @@ package webhook
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -94,6 +96,7 @@ func Deliver(t *models.HookTask) error {
 	req.Header.Add("X-Gitea-Delivery", t.UUID)
 	req.Header.Add("X-Gitea-Event", t.EventType.Event())
 	req.Header.Add("X-Gitea-Signature", t.Signature)
+	req.Header.Add("X-Gitea-Event-Version", strconv.Itoa(t.PayloadVersion))
 	req.Header.Add("X-Gogs-Delivery", t.UUID)
 	req.Header.Add("X-Gogs-Event", t.EventType.Event())
 	req.Header.Add("X-Gogs-Signature", t.Signature)
@@ -141,6 +144,12 @@ func Deliver(t *models.HookTask) error {
 		}
 	}()
 
+	req, err = checkDeliveryHost(t, req)
+	if err != nil {
+		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
+		return err
+	}
+
 	resp, err := webhookHTTPClient.Do(req)
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
@@ -227,6 +236,43 @@ func DeliverHooks(ctx context.Context) {
 
 }
 
+// webhookContextKey is the context key of the webhook of a delivery request, the hosts
+// the request is redirected to are checked against its allowlist
+type webhookContextKey struct{}
+
+// checkDeliveryHost returns an ErrWebhookHostNotAllowed if the hook may not call the host
+// of the request anymore and disables it
+func checkDeliveryHost(t *models.HookTask, req *http.Request) (*http.Request, error) {
+	w, err := models.GetWebhookByID(t.HookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.CheckWebhookURL(w, req.URL.String()); err != nil {
+		if models.IsErrWebhookHostNotAllowed(err) {
+			log.Warn("Webhook %d of repo %d may not call the host %s, disabling it", w.ID, t.RepoID, req.URL.Host)
+			if err := models.DisableWebhookForDeniedHost(w, req.URL.Host); err != nil {
+				log.Error("DisableWebhookForDeniedHost [%d]: %v", w.ID, err)
+			}
+		}
+		return nil, err
+	}
+	return req.WithContext(context.WithValue(req.Context(), webhookContextKey{}, w)), nil
+}
+
+// checkRedirect stops the redirects to the hosts the webhook of a request may not call
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if w, ok := req.Context().Value(webhookContextKey{}).(*models.Webhook); ok {
+		if err := models.CheckWebhookURL(w, req.URL.String()); err != nil {
+			log.Warn("Webhook %d may not be redirected to the host %s", w.ID, req.URL.Host)
+			return err
+		}
+	}
+	return nil
+}
+
 var (
 	webhookHTTPClient *http.Client
 	once              sync.Once
@@ -263,6 +309,7 @@ func InitDeliverHooks() {
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 
 	webhookHTTPClient = &http.Client{
+		CheckRedirect: checkRedirect,
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify},
 			Proxy:           webhookProxy(),
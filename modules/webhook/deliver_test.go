@@ -6,10 +6,14 @@ package webhook
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +41,64 @@ func TestWebhookProxy(t *testing.T) {
 		}
 	}
 }
+
+func TestDeliverAllowedHosts(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	var called int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	if webhookHTTPClient == nil {
+		webhookHTTPClient = &http.Client{CheckRedirect: checkRedirect}
+	}
+	deliver := func(hookURL string) (*models.HookTask, error) {
+		task := &models.HookTask{
+			RepoID:      1,
+			HookID:      1,
+			Type:        models.GITEA,
+			URL:         hookURL,
+			Payloader:   &api.PushPayload{},
+			HTTPMethod:  http.MethodPost,
+			ContentType: models.ContentTypeJSON,
+			EventType:   models.HookEventPush,
+		}
+		assert.NoError(t, models.CreateHookTask(task))
+		return task, Deliver(task)
+	}
+
+	// the redirects are checked as well
+	redirectURL, err := url.Parse(redirect.URL)
+	assert.NoError(t, err)
+	_, err = models.UpdateWebhookHostAllowlist(2, redirectURL.Host)
+	assert.NoError(t, err)
+	task, err := deliver(redirect.URL)
+	assert.Error(t, err)
+	assert.False(t, task.IsSucceed)
+	assert.Equal(t, 0, called)
+	models.AssertExistsAndLoadBean(t, &models.Webhook{ID: 1, IsActive: true})
+
+	_, err = models.UpdateWebhookHostAllowlist(2, "127.0.0.1")
+	assert.NoError(t, err)
+	task, err = deliver(redirect.URL)
+	assert.NoError(t, err)
+	assert.True(t, task.IsSucceed)
+	assert.Equal(t, 1, called)
+
+	// a hook calling a host which isn't allowed is disabled
+	defer func(hosts []string) {
+		setting.Webhook.AllowedHostList = hosts
+	}(setting.Webhook.AllowedHostList)
+	setting.Webhook.AllowedHostList = []string{"*.example.com"}
+	task, err = deliver(target.URL)
+	assert.True(t, models.IsErrWebhookHostNotAllowed(err))
+	assert.False(t, task.IsSucceed)
+	assert.Contains(t, task.ResponseInfo.Body, "not allowed")
+	assert.Equal(t, 1, called)
+	models.AssertExistsAndLoadBean(t, &models.Webhook{ID: 1}, models.Cond("is_active = ?", false))
+	models.AssertExistsAndLoadBean(t, &models.Notice{Type: models.NoticeRepository}, models.Cond("description LIKE ?", "Webhook 1 has been disabled%"))
+}
@@ -264,6 +264,62 @@ func (d *DingtalkPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (d *DingtalkPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, _ := getUserPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: text,
+		},
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (d *DingtalkPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, _ := getOrganizationPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: text,
+		},
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (d *DingtalkPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, _ := getAuthFailurePayloadInfo(p)
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: text,
+		},
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (d *DingtalkPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, _ := getReviewEnvironmentPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DingtalkPayload{
+		MsgType: "text",
+		Text: struct {
+			Content string `json:"content"`
+		}{
+			Content: text,
+		},
+	}, nil
+}
+
 // GetDingtalkPayload converts a ding talk webhook into a DingtalkPayload
 func GetDingtalkPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(DingtalkPayload), p, event)
@@ -402,6 +402,70 @@ func (d *DiscordPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (d *DiscordPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, color := getUserPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DiscordPayload{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: text,
+				Color: color,
+			},
+		},
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (d *DiscordPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, color := getOrganizationPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DiscordPayload{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: text,
+				Color: color,
+			},
+		},
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (d *DiscordPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, color := getAuthFailurePayloadInfo(p)
+
+	return &DiscordPayload{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: text,
+				Color: color,
+			},
+		},
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (d *DiscordPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, color := getReviewEnvironmentPayloadInfo(p, noneLinkFormatter, true)
+
+	return &DiscordPayload{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title: text,
+				Color: color,
+			},
+		},
+	}, nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	s := new(DiscordPayload)
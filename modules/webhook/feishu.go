@@ -184,6 +184,46 @@ func (f *FeishuPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (f *FeishuPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, _ := getUserPayloadInfo(p, noneLinkFormatter, true)
+
+	return &FeishuPayload{
+		Text:  text,
+		Title: text,
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (f *FeishuPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, _ := getOrganizationPayloadInfo(p, noneLinkFormatter, true)
+
+	return &FeishuPayload{
+		Text:  text,
+		Title: text,
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (f *FeishuPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, _ := getAuthFailurePayloadInfo(p)
+
+	return &FeishuPayload{
+		Text:  text,
+		Title: text,
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (f *FeishuPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, _ := getReviewEnvironmentPayloadInfo(p, noneLinkFormatter, true)
+
+	return &FeishuPayload{
+		Text:  text,
+		Title: text,
+	}, nil
+}
+
 // GetFeishuPayload converts a ding talk webhook into a FeishuPayload
 func GetFeishuPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(FeishuPayload), p, event)
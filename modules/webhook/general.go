@@ -191,3 +191,68 @@ func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFo
 
 	return text, issueTitle, color
 }
+
+func getUserPayloadInfo(p *api.UserPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	userLink := linkFormatter(setting.AppURL+p.User.UserName, p.User.UserName)
+
+	switch p.Action {
+	case api.HookUserCreated:
+		text = fmt.Sprintf("User created: %s", userLink)
+		color = greenColor
+	case api.HookUserDeleted:
+		text = fmt.Sprintf("User deleted: %s", p.User.UserName)
+		color = redColor
+	}
+	if withSender && p.Sender != nil && p.Sender.ID != p.User.ID {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getOrganizationPayloadInfo(p *api.OrganizationPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	orgLink := linkFormatter(setting.AppURL+p.Organization.UserName, p.Organization.UserName)
+
+	switch p.Action {
+	case api.HookOrganizationCreated:
+		text = fmt.Sprintf("Organization created: %s", orgLink)
+		color = greenColor
+	case api.HookOrganizationDeleted:
+		text = fmt.Sprintf("Organization deleted: %s", p.Organization.UserName)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getAuthFailurePayloadInfo(p *api.AuthFailurePayload) (text string, color int) {
+	switch p.Method {
+	case api.HookAuthFailureTwoFactor:
+		text = fmt.Sprintf("Failed two-factor authentication attempt for %s from %s", p.LoginName, p.RemoteAddress)
+	case api.HookAuthFailureProhibited:
+		text = fmt.Sprintf("Prohibited sign-in attempt for %s from %s", p.LoginName, p.RemoteAddress)
+	default:
+		text = fmt.Sprintf("Failed authentication attempt for %s from %s", p.LoginName, p.RemoteAddress)
+	}
+	return text, redColor
+}
+
+func getReviewEnvironmentPayloadInfo(p *api.ReviewEnvironmentPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	titleLink := linkFormatter(p.PullRequest.URL, fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title))
+	envLink := linkFormatter(p.Environment.URL, p.Environment.Name)
+
+	switch p.Action {
+	case api.HookReviewEnvironmentCleanup:
+		text = fmt.Sprintf("[%s] Review environment %s expired: %s", repoLink, envLink, titleLink)
+		color = redColor
+	}
+	if withSender && p.Sender != nil {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
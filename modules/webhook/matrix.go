@@ -153,6 +153,34 @@ func (m *MatrixPayloadUnsafe) Release(p *api.ReleasePayload) (api.Payloader, err
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
 }
 
+// User implements PayloadConvertor User method
+func (m *MatrixPayloadUnsafe) User(p *api.UserPayload) (api.Payloader, error) {
+	text, _ := getUserPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (m *MatrixPayloadUnsafe) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, _ := getOrganizationPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (m *MatrixPayloadUnsafe) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, _ := getAuthFailurePayloadInfo(p)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (m *MatrixPayloadUnsafe) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, _ := getReviewEnvironmentPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (m *MatrixPayloadUnsafe) Push(p *api.PushPayload) (api.Payloader, error) {
 	var commitDesc string
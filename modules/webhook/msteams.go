@@ -557,6 +557,58 @@ func (m *MSTeamsPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (m *MSTeamsPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, color := getUserPayloadInfo(p, noneLinkFormatter, true)
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", color),
+		Title:      text,
+		Summary:    text,
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (m *MSTeamsPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, color := getOrganizationPayloadInfo(p, noneLinkFormatter, true)
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", color),
+		Title:      text,
+		Summary:    text,
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (m *MSTeamsPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, color := getAuthFailurePayloadInfo(p)
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", color),
+		Title:      text,
+		Summary:    text,
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (m *MSTeamsPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, color := getReviewEnvironmentPayloadInfo(p, noneLinkFormatter, true)
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", color),
+		Title:      text,
+		Summary:    text,
+	}, nil
+}
+
 // GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
 func GetMSTeamsPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(MSTeamsPayload), p, event)
@@ -0,0 +1,64 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// versionConvertor converts a payload of a version to the previous version
+type versionConvertor func(w *models.Webhook, event models.HookEventType, payload map[string]interface{})
+
+// versionConvertors converts the payloads of the webhooks pinned to a previous version,
+// versionConvertors[v] converts a payload of the version v+1 to the version v. A convertor
+// must be added here whenever api.WebhookPayloadVersion is increased.
+var versionConvertors = map[int]versionConvertor{
+	// the payloads of the version 1 contain the secret of the webhook
+	1: func(w *models.Webhook, _ models.HookEventType, payload map[string]interface{}) {
+		payload["secret"] = w.Secret
+	},
+}
+
+// versionedPayload is a payload converted to a previous version
+type versionedPayload map[string]interface{}
+
+// SetSecret sets the secret of the payload
+func (p versionedPayload) SetSecret(secret string) {
+	p["secret"] = secret
+}
+
+// JSONPayload marshals the payload
+func (p versionedPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// convertPayloadVersion converts a payload of the latest version to the version the webhook is pinned to
+func convertPayloadVersion(w *models.Webhook, event models.HookEventType, p api.Payloader) (api.Payloader, error) {
+	if w.PayloadVersion <= 0 || w.PayloadVersion >= api.WebhookPayloadVersion {
+		return p, nil
+	}
+
+	data, err := p.JSONPayload()
+	if err != nil {
+		return nil, err
+	}
+	payload := make(versionedPayload)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep the IDs exact
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+	for version := api.WebhookPayloadVersion - 1; version >= w.PayloadVersion; version-- {
+		if convert, ok := versionConvertors[version]; ok {
+			convert(w, event, payload)
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertPayloadVersion(t *testing.T) {
+	p := &api.PushPayload{
+		Ref:  "refs/heads/master",
+		Repo: &api.Repository{ID: 1 << 60},
+	}
+
+	w := &models.Webhook{Secret: "secret", PayloadVersion: api.WebhookPayloadVersion}
+	payloader, err := convertPayloadVersion(w, models.HookEventPush, p)
+	assert.NoError(t, err)
+	assert.Equal(t, p, payloader)
+	data, err := payloader.JSONPayload()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"secret"`)
+
+	w.PayloadVersion = 1
+	payloader, err = convertPayloadVersion(w, models.HookEventPush, p)
+	assert.NoError(t, err)
+	data, err = payloader.JSONPayload()
+	assert.NoError(t, err)
+
+	var payload api.PushPayload
+	assert.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, "secret", payload.Secret)
+	assert.Equal(t, p.Ref, payload.Ref)
+	assert.EqualValues(t, 1<<60, payload.Repo.ID)
+}
+
+func TestPrepareWebhooksPayloadVersion(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	w := &models.Webhook{
+		RepoID:       repo.ID,
+		URL:          "http://www.example.com/versioned",
+		ContentType:  models.ContentTypeJSON,
+		Secret:       "secret",
+		HookEvent:    &models.HookEvent{PushOnly: true},
+		IsActive:     true,
+		HookTaskType: models.GITEA,
+	}
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, models.CreateWebhook(w))
+	assert.EqualValues(t, api.WebhookPayloadVersion, w.PayloadVersion)
+
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventPush, &api.PushPayload{Commits: []*api.PayloadCommit{{}}}))
+
+	// the webhooks of the fixtures were created before the versioning
+	task := models.AssertExistsAndLoadBean(t, &models.HookTask{RepoID: repo.ID, HookID: 1, EventType: models.HookEventPush}).(*models.HookTask)
+	assert.EqualValues(t, 1, task.PayloadVersion)
+	assert.Contains(t, task.PayloadContent, `"secret"`)
+
+	task = models.AssertExistsAndLoadBean(t, &models.HookTask{RepoID: repo.ID, HookID: w.ID, EventType: models.HookEventPush}).(*models.HookTask)
+	assert.EqualValues(t, api.WebhookPayloadVersion, task.PayloadVersion)
+	assert.NotContains(t, task.PayloadContent, `"secret"`)
+}
@@ -22,6 +22,10 @@ type PayloadConvertor interface {
 	Review(*api.PullRequestPayload, models.HookEventType) (api.Payloader, error)
 	Repository(*api.RepositoryPayload) (api.Payloader, error)
 	Release(*api.ReleasePayload) (api.Payloader, error)
+	ReviewEnvironment(*api.ReviewEnvironmentPayload) (api.Payloader, error)
+	User(*api.UserPayload) (api.Payloader, error)
+	Organization(*api.OrganizationPayload) (api.Payloader, error)
+	AuthFailure(*api.AuthFailurePayload) (api.Payloader, error)
 }
 
 func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEventType) (api.Payloader, error) {
@@ -51,6 +55,14 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEven
 		return s.Repository(p.(*api.RepositoryPayload))
 	case models.HookEventRelease:
 		return s.Release(p.(*api.ReleasePayload))
+	case models.HookEventReviewEnvironment:
+		return s.ReviewEnvironment(p.(*api.ReviewEnvironmentPayload))
+	case models.HookEventUser:
+		return s.User(p.(*api.UserPayload))
+	case models.HookEventOrganization:
+		return s.Organization(p.(*api.OrganizationPayload))
+	case models.HookEventAuthFailure:
+		return s.AuthFailure(p.(*api.AuthFailurePayload))
 	}
 	return s, nil
 }
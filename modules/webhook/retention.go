@@ -0,0 +1,142 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	gouuid "github.com/google/uuid"
+)
+
+// hookTasksPerArchive is the maximum number of deliveries deleted at once and stored in an archive
+const hookTasksPerArchive = 1000
+
+// CleanupHookTasks deletes the deliveries of the webhooks done more than olderThan ago. With archive set
+// they're archived to the storage before, and the archives created more than archiveRetention ago are
+// deleted unless archiveRetention is 0.
+func CleanupHookTasks(ctx context.Context, olderThan time.Duration, archive bool, archiveRetention time.Duration) error {
+	log.Trace("Doing: CleanupHookTasks")
+
+	before := time.Now().Add(-olderThan).UnixNano()
+	hookIDs, err := models.GetHookIDsOfTasksDeliveredBefore(before)
+	if err != nil {
+		return err
+	}
+	for _, hookID := range hookIDs {
+		for {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before cleaning up the deliveries of webhook %d", hookID)
+			default:
+			}
+
+			tasks, err := models.FindHookTasksDeliveredBefore(hookID, before, hookTasksPerArchive)
+			if err != nil {
+				return err
+			}
+			if len(tasks) == 0 {
+				break
+			}
+			if archive {
+				if err := archiveHookTasks(hookID, tasks); err != nil {
+					return fmt.Errorf("archiveHookTasks of webhook %d: %v", hookID, err)
+				}
+			}
+			ids := make([]int64, len(tasks))
+			for i, task := range tasks {
+				ids[i] = task.ID
+			}
+			if err := models.DeleteHookTasks(ids); err != nil {
+				return err
+			}
+			if len(tasks) < hookTasksPerArchive {
+				break
+			}
+		}
+	}
+
+	if archiveRetention > 0 {
+		if err := deleteHookTaskArchives(timeutil.TimeStamp(time.Now().Add(-archiveRetention).Unix())); err != nil {
+			return err
+		}
+	}
+
+	log.Trace("Finished: CleanupHookTasks")
+	return nil
+}
+
+// archiveHookTasks saves the deliveries of a webhook as gzipped JSON to the storage
+func archiveHookTasks(hookID int64, tasks []*models.HookTask) error {
+	archive := &models.HookTaskArchive{
+		HookID:         hookID,
+		Path:           fmt.Sprintf("%d/%s.json.gz", hookID, gouuid.New().String()),
+		NumTasks:       len(tasks),
+		FirstDelivered: tasks[0].Delivered,
+		LastDelivered:  tasks[len(tasks)-1].Delivered,
+	}
+	if err := storage.SaveFrom(storage.HookTaskArchives, archive.Path, func(w io.Writer) error {
+		gzw := gzip.NewWriter(w)
+		if err := json.NewEncoder(gzw).Encode(tasks); err != nil {
+			return err
+		}
+		return gzw.Close()
+	}); err != nil {
+		return err
+	}
+	return models.CreateHookTaskArchive(archive)
+}
+
+// deleteHookTaskArchives deletes the archives of deliveries created before a time
+func deleteHookTaskArchives(before timeutil.TimeStamp) error {
+	archives, err := models.GetHookTaskArchivesCreatedBefore(before)
+	if err != nil {
+		return err
+	}
+	for _, archive := range archives {
+		if err := storage.HookTaskArchives.Delete(archive.Path); err != nil {
+			log.Warn("Unable to delete the archived deliveries %s: %v", archive.Path, err)
+		}
+		if err := models.DeleteHookTaskArchive(archive.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreHookTaskArchive brings the deliveries of an archive back to the history of their webhook and deletes the archive
+func RestoreHookTaskArchive(archive *models.HookTaskArchive) error {
+	obj, err := storage.HookTaskArchives.Open(archive.Path)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+	gzr, err := gzip.NewReader(obj)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	tasks := make([]*models.HookTask, 0, archive.NumTasks)
+	if err := json.NewDecoder(gzr).Decode(&tasks); err != nil {
+		return err
+	}
+	if err := models.RestoreHookTaskArchive(archive, tasks); err != nil {
+		return err
+	}
+	if err := storage.HookTaskArchives.Delete(archive.Path); err != nil {
+		log.Warn("Unable to delete the restored deliveries %s: %v", archive.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupHookTasks(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	recent := &models.HookTask{RepoID: 1, HookID: 1, Payloader: &api.PushPayload{}}
+	assert.NoError(t, models.CreateHookTask(recent))
+	recent.IsDelivered = true
+	recent.Delivered = time.Now().UnixNano()
+	assert.NoError(t, models.UpdateHookTask(recent))
+
+	// the old delivery is archived and deleted
+	assert.NoError(t, CleanupHookTasks(context.Background(), time.Hour, true, 0))
+	models.AssertNotExistsBean(t, &models.HookTask{ID: 1})
+	models.AssertExistsAndLoadBean(t, &models.HookTask{ID: recent.ID})
+	archives, err := models.GetHookTaskArchives(1)
+	assert.NoError(t, err)
+	if !assert.Len(t, archives, 1) {
+		return
+	}
+	assert.Equal(t, 1, archives[0].NumTasks)
+
+	assert.NoError(t, RestoreHookTaskArchive(archives[0]))
+	models.AssertExistsAndLoadBean(t, &models.HookTask{ID: 1, UUID: "uuid1", IsDelivered: true})
+	models.AssertNotExistsBean(t, &models.HookTaskArchive{ID: archives[0].ID})
+	_, err = storage.HookTaskArchives.Stat(archives[0].Path)
+	assert.Error(t, err)
+
+	// the old archives are deleted from the storage
+	assert.NoError(t, CleanupHookTasks(context.Background(), time.Hour, true, 0))
+	archives, err = models.GetHookTaskArchives(1)
+	assert.NoError(t, err)
+	assert.Len(t, archives, 1)
+	assert.NoError(t, deleteHookTaskArchives(timeutil.TimeStampNow()+1))
+	models.AssertNotExistsBean(t, &models.HookTaskArchive{ID: archives[0].ID})
+	_, err = storage.HookTaskArchives.Stat(archives[0].Path)
+	assert.Error(t, err)
+
+	// the deliveries aren't archived without archive
+	models.AssertNotExistsBean(t, &models.HookTask{ID: 1})
+	recent.Delivered = time.Now().Add(-2 * time.Hour).UnixNano()
+	assert.NoError(t, models.UpdateHookTask(recent))
+	assert.NoError(t, CleanupHookTasks(context.Background(), time.Hour, false, 0))
+	models.AssertNotExistsBean(t, &models.HookTask{ID: recent.ID})
+	archives, err = models.GetHookTaskArchives(1)
+	assert.NoError(t, err)
+	assert.Empty(t, archives)
+}
@@ -197,6 +197,54 @@ func (s *SlackPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (s *SlackPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, _ := getUserPayloadInfo(p, SlackLinkFormatter, true)
+
+	return &SlackPayload{
+		Channel:  s.Channel,
+		Text:     text,
+		Username: s.Username,
+		IconURL:  s.IconURL,
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (s *SlackPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, _ := getOrganizationPayloadInfo(p, SlackLinkFormatter, true)
+
+	return &SlackPayload{
+		Channel:  s.Channel,
+		Text:     text,
+		Username: s.Username,
+		IconURL:  s.IconURL,
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (s *SlackPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, _ := getAuthFailurePayloadInfo(p)
+
+	return &SlackPayload{
+		Channel:  s.Channel,
+		Text:     text,
+		Username: s.Username,
+		IconURL:  s.IconURL,
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (s *SlackPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, _ := getReviewEnvironmentPayloadInfo(p, SlackLinkFormatter, true)
+
+	return &SlackPayload{
+		Channel:  s.Channel,
+		Text:     text,
+		Username: s.Username,
+		IconURL:  s.IconURL,
+	}, nil
+}
+
 // Push implements PayloadConvertor Push method
 func (s *SlackPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	// n new commits
@@ -78,3 +78,55 @@ func TestSlackPullRequestPayload(t *testing.T) {
 
 	assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Pull request opened: <http://localhost:3000/test/repo/pulls/12|#2 Fix bug> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 }
+
+func TestSlackUserPayload(t *testing.T) {
+	p := &api.UserPayload{
+		Action: api.HookUserCreated,
+		User:   &api.User{ID: 2, UserName: "user2"},
+		Sender: &api.User{ID: 1, UserName: "user1"},
+	}
+	s := new(SlackPayload)
+
+	pl, err := s.User(p)
+	require.NoError(t, err)
+	require.NotNil(t, pl)
+	assert.Equal(t, "User created: <https://try.gitea.io/user2|user2> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+
+	// a user who registered on their own isn't repeated as sender
+	p.Action = api.HookUserDeleted
+	p.Sender = p.User
+	pl, err = s.User(p)
+	require.NoError(t, err)
+	assert.Equal(t, "User deleted: user2", pl.(*SlackPayload).Text)
+}
+
+func TestSlackAuthFailurePayload(t *testing.T) {
+	p := &api.AuthFailurePayload{
+		Method:        api.HookAuthFailureTwoFactor,
+		LoginName:     "user2",
+		RemoteAddress: "192.0.2.1",
+	}
+	s := new(SlackPayload)
+
+	pl, err := s.AuthFailure(p)
+	require.NoError(t, err)
+	require.NotNil(t, pl)
+	assert.Equal(t, "Failed two-factor authentication attempt for user2 from 192.0.2.1", pl.(*SlackPayload).Text)
+}
+
+func TestSlackReviewEnvironmentPayload(t *testing.T) {
+	pr := pullRequestTestPayload()
+	p := &api.ReviewEnvironmentPayload{
+		Action:      api.HookReviewEnvironmentCleanup,
+		Environment: &api.ReviewEnvironment{Name: "preview", URL: "https://preview.example.com/12"},
+		PullRequest: pr.PullRequest,
+		Repository:  pr.Repository,
+		Sender:      pr.Sender,
+	}
+	s := new(SlackPayload)
+
+	pl, err := s.ReviewEnvironment(p)
+	require.NoError(t, err)
+	require.NotNil(t, pl)
+	assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Review environment <https://preview.example.com/12|preview> expired: <http://localhost:3000/test/repo/pulls/12|#2 Fix bug> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+}
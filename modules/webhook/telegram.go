@@ -206,6 +206,42 @@ func (t *TelegramPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	}, nil
 }
 
+// User implements PayloadConvertor User method
+func (t *TelegramPayload) User(p *api.UserPayload) (api.Payloader, error) {
+	text, _ := getUserPayloadInfo(p, htmlLinkFormatter, true)
+
+	return &TelegramPayload{
+		Message: text + "\n",
+	}, nil
+}
+
+// Organization implements PayloadConvertor Organization method
+func (t *TelegramPayload) Organization(p *api.OrganizationPayload) (api.Payloader, error) {
+	text, _ := getOrganizationPayloadInfo(p, htmlLinkFormatter, true)
+
+	return &TelegramPayload{
+		Message: text + "\n",
+	}, nil
+}
+
+// AuthFailure implements PayloadConvertor AuthFailure method
+func (t *TelegramPayload) AuthFailure(p *api.AuthFailurePayload) (api.Payloader, error) {
+	text, _ := getAuthFailurePayloadInfo(p)
+
+	return &TelegramPayload{
+		Message: text + "\n",
+	}, nil
+}
+
+// ReviewEnvironment implements PayloadConvertor ReviewEnvironment method
+func (t *TelegramPayload) ReviewEnvironment(p *api.ReviewEnvironmentPayload) (api.Payloader, error) {
+	text, _ := getReviewEnvironmentPayloadInfo(p, htmlLinkFormatter, true)
+
+	return &TelegramPayload{
+		Message: text + "\n",
+	}, nil
+}
+
 // GetTelegramPayload converts a telegram webhook into a TelegramPayload
 func GetTelegramPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(TelegramPayload), p, event)
@@ -44,7 +44,7 @@ func getPayloadBranch(p api.Payloader) string {
 
 // PrepareWebhook adds special webhook to task queue for given payload.
 func PrepareWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
-	if err := prepareWebhook(w, repo, event, p); err != nil {
+	if err := prepareWebhook(w, repo.ID, event, p); err != nil {
 		return err
 	}
 
@@ -67,7 +67,7 @@ func checkBranch(w *models.Webhook, branch string) bool {
 	return g.Match(branch)
 }
 
-func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+func prepareWebhook(w *models.Webhook, repoID int64, event models.HookEventType, p api.Payloader) error {
 	for _, e := range w.EventCheckers() {
 		if event == e.Type {
 			if !e.Has() {
@@ -133,8 +133,10 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 			return fmt.Errorf("GetMatrixPayload: %v", err)
 		}
 	default:
-		p.SetSecret(w.Secret)
-		payloader = p
+		payloader, err = convertPayloadVersion(w, event, p)
+		if err != nil {
+			return fmt.Errorf("convertPayloadVersion: %v", err)
+		}
 	}
 
 	var signature string
@@ -152,16 +154,17 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	}
 
 	if err = models.CreateHookTask(&models.HookTask{
-		RepoID:      repo.ID,
-		HookID:      w.ID,
-		Type:        w.HookTaskType,
-		URL:         w.URL,
-		Signature:   signature,
-		Payloader:   payloader,
-		HTTPMethod:  w.HTTPMethod,
-		ContentType: w.ContentType,
-		EventType:   event,
-		IsSSL:       w.IsSSL,
+		RepoID:         repoID,
+		HookID:         w.ID,
+		Type:           w.HookTaskType,
+		URL:            w.URL,
+		Signature:      signature,
+		Payloader:      payloader,
+		HTTPMethod:     w.HTTPMethod,
+		ContentType:    w.ContentType,
+		EventType:      event,
+		PayloadVersion: w.PayloadVersion,
+		IsSSL:          w.IsSSL,
 	}); err != nil {
 		return fmt.Errorf("CreateHookTask: %v", err)
 	}
@@ -206,9 +209,33 @@ func prepareWebhooks(repo *models.Repository, event models.HookEventType, p api.
 	}
 
 	for _, w := range ws {
-		if err = prepareWebhook(w, repo, event, p); err != nil {
+		if err = prepareWebhook(w, repo.ID, event, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrepareSystemWebhooks adds the system webhooks to task queue for the payload of an instance
+// event, which doesn't belong to a repository.
+func PrepareSystemWebhooks(event models.HookEventType, p api.Payloader) error {
+	ws, err := models.GetSystemWebhooks()
+	if err != nil {
+		return fmt.Errorf("GetSystemWebhooks: %v", err)
+	}
+	if len(ws) == 0 {
+		return nil
+	}
+
+	for _, w := range ws {
+		if !w.IsActive {
+			continue
+		}
+		if err = prepareWebhook(w, 0, event, p); err != nil {
 			return err
 		}
 	}
+
+	go hookQueue.Add(0)
 	return nil
 }
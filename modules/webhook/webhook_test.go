@@ -74,6 +74,41 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 	}
 }
 
+func TestPrepareSystemWebhooks(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	systemHook := &models.Webhook{
+		IsSystemWebhook: true,
+		URL:             "http://www.example.com/system",
+		ContentType:     models.ContentTypeJSON,
+		Events:          `{"choose_events":true,"events":{"user":true}}`,
+		IsActive:        true,
+		HookTaskType:    models.GITEA,
+	}
+	assert.NoError(t, models.CreateWebhook(systemHook))
+	systemHook.AfterLoad()
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	payload := &api.UserPayload{
+		Action: api.HookUserCreated,
+		User:   &api.User{ID: user.ID, UserName: user.Name},
+		Sender: &api.User{ID: user.ID, UserName: user.Name},
+	}
+	assert.NoError(t, PrepareSystemWebhooks(models.HookEventUser, payload))
+	models.AssertExistsAndLoadBean(t, &models.HookTask{RepoID: 0, HookID: systemHook.ID, EventType: models.HookEventUser})
+
+	// the hook didn't choose the organization events
+	assert.NoError(t, PrepareSystemWebhooks(models.HookEventOrganization, &api.OrganizationPayload{
+		Action:       api.HookOrganizationCreated,
+		Organization: &api.Organization{ID: 3, UserName: "user3"},
+		Sender:       &api.User{ID: user.ID, UserName: user.Name},
+	}))
+	models.AssertNotExistsBean(t, &models.HookTask{HookID: systemHook.ID, EventType: models.HookEventOrganization})
+
+	// the repository webhooks never receive the instance events
+	assert.False(t, (&models.Webhook{RepoID: 1, HookEvent: &models.HookEvent{SendEverything: true}}).HasUserEvent())
+}
+
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks
@@ -294,6 +294,7 @@ func Config(ctx *context.Context) {
 
 	ctx.Data["DisableGravatar"] = setting.DisableGravatar
 	ctx.Data["EnableFederatedAvatar"] = setting.EnableFederatedAvatar
+	ctx.Data["AvatarProviders"] = strings.Join(setting.Avatar.Providers, ", ")
 
 	ctx.Data["Git"] = setting.Git
 
@@ -0,0 +1,165 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	tplAnalytics base.TplName = "admin/analytics"
+
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 365
+	topAPIConsumers      = 10
+)
+
+// analyticsPeriods are the periods in days the analytics page links to
+var analyticsPeriods = []int{7, 30, 90, 365}
+
+// analyticsPeriod returns the beginning of the first day and the end of the current day of
+// the period of the days query parameter
+func analyticsPeriod(ctx *context.Context) (days int, since, before timeutil.TimeStamp) {
+	days = ctx.QueryInt("days")
+	if days <= 0 {
+		days = defaultAnalyticsDays
+	} else if days > maxAnalyticsDays {
+		days = maxAnalyticsDays
+	}
+	before = models.StatsDay(time.Now()).AddDuration(24 * time.Hour)
+	since = before.AddDuration(-time.Duration(days) * 24 * time.Hour)
+	return days, since, before
+}
+
+// Analytics shows the daily stats of the growth, the storage and the API usage of the instance over a period
+func Analytics(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.analytics")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminAnalytics"] = true
+
+	days, since, before := analyticsPeriod(ctx)
+	stats, err := models.GetInstanceStats(since, before)
+	if err != nil {
+		ctx.ServerError("GetInstanceStats", err)
+		return
+	}
+	consumers, err := models.GetTopAPIConsumers(since, before, topAPIConsumers)
+	if err != nil {
+		ctx.ServerError("GetTopAPIConsumers", err)
+		return
+	}
+
+	var newUsers, newRepos, apiRequests int64
+	for _, s := range stats {
+		newUsers += s.NewUsers
+		newRepos += s.NewRepos
+		apiRequests += s.APIRequests
+	}
+	if len(stats) > 0 {
+		first, last := stats[0], stats[len(stats)-1]
+		ctx.Data["Latest"] = last
+		growth, sign := last.StorageSize()-first.StorageSize(), "+"
+		if growth < 0 {
+			growth, sign = -growth, "-"
+		}
+		ctx.Data["StorageGrowth"] = sign + base.FileSize(growth)
+	}
+
+	// the latest day first
+	reversed := make([]*models.InstanceStats, len(stats))
+	for i, s := range stats {
+		reversed[len(stats)-1-i] = s
+	}
+
+	ctx.Data["Days"] = days
+	ctx.Data["Periods"] = analyticsPeriods
+	ctx.Data["Stats"] = reversed
+	ctx.Data["NewUsers"] = newUsers
+	ctx.Data["NewRepos"] = newRepos
+	ctx.Data["APIRequests"] = apiRequests
+	ctx.Data["APIConsumers"] = consumers
+	ctx.HTML(http.StatusOK, tplAnalytics)
+}
+
+type analyticsDayExport struct {
+	Day            time.Time `json:"day"`
+	Users          int64     `json:"users"`
+	NewUsers       int64     `json:"new_users"`
+	ActiveUsers    int64     `json:"active_users"`
+	Orgs           int64     `json:"orgs"`
+	Repos          int64     `json:"repos"`
+	NewRepos       int64     `json:"new_repos"`
+	RepoSize       int64     `json:"repo_size"`
+	LFSSize        int64     `json:"lfs_size"`
+	AttachmentSize int64     `json:"attachment_size"`
+	APIRequests    int64     `json:"api_requests"`
+}
+
+type analyticsConsumerExport struct {
+	UserID   int64  `json:"user_id"`
+	UserName string `json:"user_name,omitempty"`
+	Requests int64  `json:"requests"`
+}
+
+type analyticsExport struct {
+	Since           time.Time                  `json:"since"`
+	Before          time.Time                  `json:"before"`
+	Days            []*analyticsDayExport      `json:"days"`
+	TopAPIConsumers []*analyticsConsumerExport `json:"top_api_consumers"`
+}
+
+// AnalyticsExport downloads the daily stats and the top API consumers of the instance over a period as JSON
+func AnalyticsExport(ctx *context.Context) {
+	days, since, before := analyticsPeriod(ctx)
+	stats, err := models.GetInstanceStats(since, before)
+	if err != nil {
+		ctx.ServerError("GetInstanceStats", err)
+		return
+	}
+	consumers, err := models.GetTopAPIConsumers(since, before, topAPIConsumers)
+	if err != nil {
+		ctx.ServerError("GetTopAPIConsumers", err)
+		return
+	}
+
+	export := &analyticsExport{
+		Since:           since.AsTime().UTC(),
+		Before:          before.AsTime().UTC(),
+		Days:            make([]*analyticsDayExport, 0, len(stats)),
+		TopAPIConsumers: make([]*analyticsConsumerExport, 0, len(consumers)),
+	}
+	for _, s := range stats {
+		export.Days = append(export.Days, &analyticsDayExport{
+			Day:            s.Day.AsTime().UTC(),
+			Users:          s.Users,
+			NewUsers:       s.NewUsers,
+			ActiveUsers:    s.ActiveUsers,
+			Orgs:           s.Orgs,
+			Repos:          s.Repos,
+			NewRepos:       s.NewRepos,
+			RepoSize:       s.RepoSize,
+			LFSSize:        s.LFSSize,
+			AttachmentSize: s.AttachmentSize,
+			APIRequests:    s.APIRequests,
+		})
+	}
+	for _, consumer := range consumers {
+		c := &analyticsConsumerExport{UserID: consumer.UserID, Requests: consumer.Requests}
+		if consumer.User != nil {
+			c.UserName = consumer.User.Name
+		}
+		export.TopAPIConsumers = append(export.TopAPIConsumers, c)
+	}
+
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="instance-stats-%dd.json"`, days))
+	ctx.JSON(http.StatusOK, export)
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	tplAudit base.TplName = "admin/audit"
+
+	auditDateLayout = "2006-01-02"
+)
+
+// auditTargetTypes are the target types the audit log can be filtered by
+var auditTargetTypes = []string{models.AuditTargetUser, models.AuditTargetOrganization, models.AuditTargetRepository, models.AuditTargetInstance}
+
+// auditFilterNames are the query parameters of the filters of the audit log
+var auditFilterNames = []string{"action", "doer", "target_type", "since", "before"}
+
+// auditFilters returns the options of the filters of the audit log from the query parameters, the dates
+// of the since and before parameters are included
+func auditFilters(ctx *context.Context) *models.FindAuditEventsOptions {
+	opts := &models.FindAuditEventsOptions{
+		Action:     models.AuditAction(ctx.Query("action")),
+		DoerName:   strings.TrimSpace(ctx.Query("doer")),
+		TargetType: ctx.Query("target_type"),
+	}
+	if since, err := time.ParseInLocation(auditDateLayout, ctx.Query("since"), setting.DefaultUILocation); err == nil {
+		opts.Since = timeutil.TimeStamp(since.Unix())
+	}
+	if before, err := time.ParseInLocation(auditDateLayout, ctx.Query("before"), setting.DefaultUILocation); err == nil {
+		opts.Before = timeutil.TimeStamp(before.AddDate(0, 0, 1).Unix())
+	}
+	return opts
+}
+
+// Audit shows the events of the audit log matching the filters, the latest first
+func Audit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.audit")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminAudit"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	opts := auditFilters(ctx)
+	opts.ListOptions = models.ListOptions{Page: page, PageSize: setting.UI.Admin.NoticePagingNum}
+	events, total, err := models.FindAuditEvents(opts)
+	if err != nil {
+		ctx.ServerError("FindAuditEvents", err)
+		return
+	}
+
+	ctx.Data["Events"] = events
+	ctx.Data["Total"] = total
+	ctx.Data["Actions"] = models.AuditActions
+	ctx.Data["TargetTypes"] = auditTargetTypes
+	ctx.Data["AuditEnabled"] = setting.Audit.Enabled
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.NoticePagingNum, page, 5)
+	filters := make(map[string]string, len(auditFilterNames))
+	query := url.Values{}
+	for _, name := range auditFilterNames {
+		if value := ctx.Query(name); len(value) > 0 {
+			filters[name] = value
+			query.Set(name, value)
+			pager.AddParamString(name, value)
+		}
+	}
+	ctx.Data["Filters"] = filters
+	ctx.Data["FiltersQuery"] = query.Encode()
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplAudit)
+}
+
+// AuditExport downloads the events of the audit log matching the filters as CSV, the oldest first
+func AuditExport(ctx *context.Context) {
+	opts := auditFilters(ctx)
+
+	ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().UTC().Format(auditDateLayout)))
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if err := audit.ExportCSV(ctx.Resp, opts); err != nil {
+		log.Error("Unable to export the audit log: %v", err)
+	}
+}
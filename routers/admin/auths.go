@@ -7,6 +7,7 @@ package admin
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 
 	"code.gitea.io/gitea/models"
@@ -14,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/auth/ldap"
 	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/auth/pam"
+	"code.gitea.io/gitea/modules/auth/saml"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -65,6 +67,7 @@ var (
 			{models.LoginNames[models.LoginSMTP], models.LoginSMTP},
 			{models.LoginNames[models.LoginOAuth2], models.LoginOAuth2},
 			{models.LoginNames[models.LoginSSPI], models.LoginSSPI},
+			{models.LoginNames[models.LoginSAML], models.LoginSAML},
 		}
 		if pam.Supported {
 			items = append(items, dropdownItem{models.LoginNames[models.LoginPAM], models.LoginPAM})
@@ -103,6 +106,8 @@ func NewAuthSource(ctx *context.Context) {
 	ctx.Data["SSPISeparatorReplacement"] = "_"
 	ctx.Data["SSPIDefaultLanguage"] = ""
 
+	ctx.Data["saml_auto_create_users"] = true
+
 	// only the first as default
 	for key := range models.OAuth2Providers {
 		ctx.Data["oauth2_provider"] = key
@@ -112,7 +117,12 @@ func NewAuthSource(ctx *context.Context) {
 	ctx.HTML(200, tplAuthNew)
 }
 
-func parseLDAPConfig(form auth.AuthenticationForm) *models.LDAPConfig {
+func parseLDAPConfig(ctx *context.Context, form auth.AuthenticationForm) (*models.LDAPConfig, error) {
+	if _, err := models.ParseGroupTeamMap(form.GroupTeamMap); err != nil {
+		ctx.Data["Err_GroupTeamMap"] = true
+		return nil, errors.New(ctx.Tr("admin.auths.invalid_group_team_map", err.Error()))
+	}
+
 	var pageSize uint32
 	if form.UsePagedSearch {
 		pageSize = uint32(form.SearchPageSize)
@@ -134,6 +144,7 @@ func parseLDAPConfig(form auth.AuthenticationForm) *models.LDAPConfig {
 			AttributeMail:         form.AttributeMail,
 			AttributesInBind:      form.AttributesInBind,
 			AttributeSSHPublicKey: form.AttributeSSHPublicKey,
+			AttributeAvatar:       form.AttributeAvatar,
 			SearchPageSize:        pageSize,
 			Filter:                form.Filter,
 			GroupsEnabled:         form.GroupsEnabled,
@@ -141,12 +152,14 @@ func parseLDAPConfig(form auth.AuthenticationForm) *models.LDAPConfig {
 			GroupFilter:           form.GroupFilter,
 			GroupMemberUID:        form.GroupMemberUID,
 			UserUID:               form.UserUID,
+			GroupTeamMap:          form.GroupTeamMap,
+			GroupTeamMapRemoval:   form.GroupTeamMapRemoval,
 			AdminFilter:           form.AdminFilter,
 			RestrictedFilter:      form.RestrictedFilter,
 			AllowDeactivateAll:    form.AllowDeactivateAll,
 			Enabled:               true,
 		},
-	}
+	}, nil
 }
 
 func parseSMTPConfig(form auth.AuthenticationForm) *models.SMTPConfig {
@@ -160,7 +173,12 @@ func parseSMTPConfig(form auth.AuthenticationForm) *models.SMTPConfig {
 	}
 }
 
-func parseOAuth2Config(form auth.AuthenticationForm) *models.OAuth2Config {
+func parseOAuth2Config(ctx *context.Context, form auth.AuthenticationForm) (*models.OAuth2Config, error) {
+	if _, err := models.ParseGroupTeamMap(form.Oauth2GroupTeamMap); err != nil {
+		ctx.Data["Err_Oauth2GroupTeamMap"] = true
+		return nil, errors.New(ctx.Tr("admin.auths.invalid_group_team_map", err.Error()))
+	}
+
 	var customURLMapping *oauth2.CustomURLMapping
 	if form.Oauth2UseCustomURL {
 		customURLMapping = &oauth2.CustomURLMapping{
@@ -178,7 +196,10 @@ func parseOAuth2Config(form auth.AuthenticationForm) *models.OAuth2Config {
 		ClientSecret:                  form.Oauth2Secret,
 		OpenIDConnectAutoDiscoveryURL: form.OpenIDConnectAutoDiscoveryURL,
 		CustomURLMapping:              customURLMapping,
-	}
+		GroupClaimName:                form.Oauth2GroupClaimName,
+		GroupTeamMap:                  form.Oauth2GroupTeamMap,
+		GroupTeamMapRemoval:           form.Oauth2GroupTeamMapRemoval,
+	}, nil
 }
 
 func parseSSPIConfig(ctx *context.Context, form auth.AuthenticationForm) (*models.SSPIConfig, error) {
@@ -205,6 +226,53 @@ func parseSSPIConfig(ctx *context.Context, form auth.AuthenticationForm) (*model
 	}, nil
 }
 
+func parseSAMLConfig(ctx *context.Context, form auth.AuthenticationForm, existing *models.SAMLConfig) (*models.SAMLConfig, error) {
+	metadata := form.SAMLIdentityProviderMetadata
+	if len(form.SAMLIdentityProviderMetadataURL) > 0 {
+		var err error
+		if metadata, err = saml.FetchMetadata(form.SAMLIdentityProviderMetadataURL); err != nil {
+			ctx.Data["Err_SAMLIdentityProviderMetadataURL"] = true
+			return nil, errors.New(ctx.Tr("admin.auths.saml_invalid_metadata", err.Error()))
+		}
+	} else if _, err := saml.ParseMetadata([]byte(metadata)); err != nil {
+		ctx.Data["Err_SAMLIdentityProviderMetadata"] = true
+		return nil, errors.New(ctx.Tr("admin.auths.saml_invalid_metadata", err.Error()))
+	}
+
+	source := &saml.Source{
+		IdentityProviderMetadata:    metadata,
+		IdentityProviderMetadataURL: form.SAMLIdentityProviderMetadataURL,
+		EntityID:                    form.SAMLEntityID,
+		NameIDFormat:                form.SAMLNameIDFormat,
+		SignRequests:                form.SAMLSignRequests,
+		AllowIDPInitiated:           form.SAMLAllowIDPInitiated,
+		UsernameAttribute:           form.SAMLUsernameAttribute,
+		EmailAttribute:              form.SAMLEmailAttribute,
+		FullNameAttribute:           form.SAMLFullNameAttribute,
+		GroupsAttribute:             form.SAMLGroupsAttribute,
+		AdminGroup:                  form.SAMLAdminGroup,
+		GroupTeamMap:                form.SAMLGroupTeamMap,
+		GroupTeamMapRemoval:         form.SAMLGroupTeamMapRemoval,
+		AutoCreateUsers:             form.SAMLAutoCreateUsers,
+	}
+	if _, err := models.ParseGroupTeamMap(source.GroupTeamMap); err != nil {
+		ctx.Data["Err_SAMLGroupTeamMap"] = true
+		return nil, errors.New(ctx.Tr("admin.auths.invalid_group_team_map", err.Error()))
+	}
+
+	// keep the key pair of the service provider, the identity provider trusts its certificate
+	if existing != nil && existing.Source != nil {
+		source.ServiceProviderCertificate = existing.ServiceProviderCertificate
+		source.ServiceProviderPrivateKey = existing.ServiceProviderPrivateKey
+	} else {
+		var err error
+		if source.ServiceProviderCertificate, source.ServiceProviderPrivateKey, err = saml.GenerateKeyPair(form.Name); err != nil {
+			return nil, err
+		}
+	}
+	return &models.SAMLConfig{Source: source}, nil
+}
+
 // NewAuthSourcePost response for adding an auth source
 func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	ctx.Data["Title"] = ctx.Tr("admin.auths.new")
@@ -229,7 +297,12 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	var config convert.Conversion
 	switch models.LoginType(form.Type) {
 	case models.LoginLDAP, models.LoginDLDAP:
-		config = parseLDAPConfig(form)
+		var err error
+		config, err = parseLDAPConfig(ctx, form)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthNew, form)
+			return
+		}
 		hasTLS = ldap.SecurityProtocol(form.SecurityProtocol) > ldap.SecurityProtocolUnencrypted
 	case models.LoginSMTP:
 		config = parseSMTPConfig(form)
@@ -239,7 +312,12 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ServiceName: form.PAMServiceName,
 		}
 	case models.LoginOAuth2:
-		config = parseOAuth2Config(form)
+		var err error
+		config, err = parseOAuth2Config(ctx, form)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthNew, form)
+			return
+		}
 	case models.LoginSSPI:
 		var err error
 		config, err = parseSSPIConfig(ctx, form)
@@ -253,6 +331,13 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ctx.RenderWithErr(ctx.Tr("admin.auths.login_source_of_type_exist"), tplAuthNew, form)
 			return
 		}
+	case models.LoginSAML:
+		var err error
+		config, err = parseSAMLConfig(ctx, form, nil)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthNew, form)
+			return
+		}
 	default:
 		ctx.Error(400)
 		return
@@ -308,6 +393,9 @@ func EditAuthSource(ctx *context.Context) {
 	if source.IsOAuth2() {
 		ctx.Data["CurrentOAuth2Provider"] = models.OAuth2Providers[source.OAuth2().Provider]
 	}
+	if source.IsSAML() {
+		ctx.Data["SAMLMetadataURL"] = setting.AppURL + "user/saml/" + url.PathEscape(source.Name) + "/metadata"
+	}
 	ctx.HTML(200, tplAuthEdit)
 }
 
@@ -337,7 +425,11 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	var config convert.Conversion
 	switch models.LoginType(form.Type) {
 	case models.LoginLDAP, models.LoginDLDAP:
-		config = parseLDAPConfig(form)
+		config, err = parseLDAPConfig(ctx, form)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
+			return
+		}
 	case models.LoginSMTP:
 		config = parseSMTPConfig(form)
 	case models.LoginPAM:
@@ -345,13 +437,27 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ServiceName: form.PAMServiceName,
 		}
 	case models.LoginOAuth2:
-		config = parseOAuth2Config(form)
+		config, err = parseOAuth2Config(ctx, form)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
+			return
+		}
 	case models.LoginSSPI:
 		config, err = parseSSPIConfig(ctx, form)
 		if err != nil {
 			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
 			return
 		}
+	case models.LoginSAML:
+		if !source.IsSAML() {
+			ctx.Error(400)
+			return
+		}
+		config, err = parseSAMLConfig(ctx, form, source.SAML())
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
+			return
+		}
 	default:
 		ctx.Error(400)
 		return
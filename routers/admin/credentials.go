@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const tplStaleCredentials base.TplName = "admin/credentials"
+
+// StaleCredentials shows the SSH keys, deploy keys or access tokens of the instance which have not been used
+// for the configured number of days, the ones not used for the longest time first
+func StaleCredentials(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.credentials")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminCredentials"] = true
+
+	typ := models.CredentialType(ctx.Query("type"))
+	switch typ {
+	case models.CredentialSSHKey, models.CredentialDeployKey, models.CredentialAccessToken:
+	default:
+		typ = models.CredentialSSHKey
+	}
+	ctx.Data["Type"] = typ
+	ctx.Data["Types"] = models.CredentialTypes
+	ctx.Data["StaleDays"] = setting.StaleCredentialDays
+
+	cutoff := models.StaleCredentialCutoff()
+	if cutoff == 0 {
+		ctx.HTML(http.StatusOK, tplStaleCredentials)
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	credentials, total, err := models.FindStaleCredentials(typ, cutoff, models.ListOptions{Page: page, PageSize: setting.UI.Admin.NoticePagingNum})
+	if err != nil {
+		ctx.ServerError("FindStaleCredentials", err)
+		return
+	}
+	ctx.Data["Credentials"] = credentials
+	ctx.Data["Total"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.NoticePagingNum, page, 5)
+	pager.AddParamString("type", string(typ))
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplStaleCredentials)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/legal"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplLegalPages       base.TplName = "admin/legal/list"
+	tplLegalAcceptances base.TplName = "admin/legal/acceptances"
+)
+
+// LegalPages show the versions of the legal pages with their number of acceptances
+func LegalPages(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.legal")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminLegal"] = true
+
+	pages, err := models.GetLegalPages()
+	if err != nil {
+		ctx.ServerError("GetLegalPages", err)
+		return
+	}
+	latest := make(map[int64]bool)
+	for _, page := range legal.Pages() {
+		latest[page.ID] = true
+	}
+
+	ctx.Data["Pages"] = pages
+	ctx.Data["LatestPages"] = latest
+	ctx.Data["NumUsers"] = models.CountUsers()
+	ctx.Data["RequireAcceptance"] = setting.Legal.RequireAcceptance
+	ctx.HTML(200, tplLegalPages)
+}
+
+// LegalAcceptances show the users who accepted a version of a legal page and when
+func LegalAcceptances(ctx *context.Context) {
+	page, err := models.GetLegalPageByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrLegalPageNotExist(err) {
+			ctx.NotFound("GetLegalPageByID", err)
+		} else {
+			ctx.ServerError("GetLegalPageByID", err)
+		}
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("admin.legal")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminLegal"] = true
+	ctx.Data["LegalPage"] = page
+
+	total, err := models.CountLegalAcceptances(page.ID)
+	if err != nil {
+		ctx.ServerError("CountLegalAcceptances", err)
+		return
+	}
+	pageNum := ctx.QueryInt("page")
+	if pageNum <= 1 {
+		pageNum = 1
+	}
+	acceptances, err := models.GetLegalAcceptances(page.ID, models.ListOptions{
+		Page:     pageNum,
+		PageSize: setting.UI.Admin.UserPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetLegalAcceptances", err)
+		return
+	}
+	ctx.Data["Acceptances"] = acceptances
+	ctx.Data["Total"] = total
+	ctx.Data["Page"] = context.NewPagination(int(total), setting.UI.Admin.UserPagingNum, pageNum, 5)
+	ctx.HTML(200, tplLegalAcceptances)
+}
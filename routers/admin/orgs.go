@@ -6,16 +6,21 @@
 package admin
 
 import (
+	"fmt"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers"
 )
 
 const (
-	tplOrgs base.TplName = "admin/org/list"
+	tplOrgs            base.TplName = "admin/org/list"
+	tplOrgWebhookHosts base.TplName = "admin/org/webhook_hosts"
 )
 
 // Organizations show all the organizations
@@ -32,3 +37,69 @@ func Organizations(ctx *context.Context) {
 		Visible: []structs.VisibleType{structs.VisibleTypePublic, structs.VisibleTypeLimited, structs.VisibleTypePrivate},
 	}, tplOrgs)
 }
+
+// OrgWebhookHosts shows the hosts the webhooks of an organization may call
+func OrgWebhookHosts(ctx *context.Context) {
+	org := prepareOrgWebhookHosts(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	allowlist, err := models.GetWebhookHostAllowlist(org.ID)
+	if err != nil {
+		ctx.ServerError("GetWebhookHostAllowlist", err)
+		return
+	}
+	ctx.Data["hosts"] = allowlist.Hosts
+	ctx.HTML(200, tplOrgWebhookHosts)
+}
+
+// OrgWebhookHostsPost updates the hosts the webhooks of an organization may call
+func OrgWebhookHostsPost(ctx *context.Context, form auth.AdminWebhookHostsForm) {
+	org := prepareOrgWebhookHosts(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplOrgWebhookHosts)
+		return
+	}
+
+	disabled, err := models.UpdateWebhookHostAllowlist(org.ID, form.Hosts)
+	if err != nil {
+		ctx.ServerError("UpdateWebhookHostAllowlist", err)
+		return
+	}
+	log.Trace("Webhook hosts of organization %s updated by admin %s", org.Name, ctx.User.Name)
+
+	if len(disabled) > 0 {
+		ctx.Flash.Warning(ctx.Tr("admin.orgs.webhook_hosts.hooks_disabled", len(disabled)))
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.orgs.webhook_hosts.update_success"))
+	}
+	ctx.Redirect(fmt.Sprintf("%s/admin/orgs/%d/webhook_hosts", setting.AppSubURL, org.ID))
+}
+
+func prepareOrgWebhookHosts(ctx *context.Context) *models.User {
+	ctx.Data["Title"] = ctx.Tr("admin.orgs.webhook_hosts")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminOrganizations"] = true
+
+	org, err := models.GetUserByID(ctx.ParamsInt64(":orgid"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound("GetUserByID", err)
+		} else {
+			ctx.ServerError("GetUserByID", err)
+		}
+		return nil
+	}
+	if !org.IsOrganization() {
+		ctx.NotFound("IsOrganization", nil)
+		return nil
+	}
+	ctx.Data["Org"] = org
+	ctx.Data["AllowedHostList"] = setting.Webhook.AllowedHostList
+	return org
+}
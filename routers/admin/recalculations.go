@@ -0,0 +1,115 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/recalculation"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const tplRecalculations base.TplName = "admin/recalculations"
+
+type recalculationProgress struct {
+	*models.Recalculation
+	// Running is whether the recalculation is running in this instance
+	Running bool
+}
+
+// recalculationProgresses returns the progress of all the registered recalculations
+func recalculationProgresses() ([]*recalculationProgress, error) {
+	progresses := make([]*recalculationProgress, 0, 5)
+	for _, r := range recalculation.Recalculators() {
+		progress, err := models.GetRecalculation(r.Name)
+		if err != nil {
+			return nil, err
+		}
+		progresses = append(progresses, &recalculationProgress{
+			Recalculation: progress,
+			Running:       recalculation.IsRunning(r.Name),
+		})
+	}
+	return progresses, nil
+}
+
+// Recalculations shows the progress of the recalculations
+func Recalculations(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.recalculations")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminRecalculations"] = true
+
+	progresses, err := recalculationProgresses()
+	if err != nil {
+		ctx.ServerError("recalculationProgresses", err)
+		return
+	}
+	ctx.Data["Recalculations"] = progresses
+	ctx.HTML(http.StatusOK, tplRecalculations)
+}
+
+type recalculationProgressJSON struct {
+	Name    string `json:"name"`
+	Status  int    `json:"status"`
+	Running bool   `json:"running"`
+	Done    int64  `json:"done"`
+	Total   int64  `json:"total"`
+	Percent int    `json:"percent"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RecalculationsProgress returns the progress of the recalculations as JSON to refresh the progress bars
+func RecalculationsProgress(ctx *context.Context) {
+	progresses, err := recalculationProgresses()
+	if err != nil {
+		ctx.ServerError("recalculationProgresses", err)
+		return
+	}
+	result := make([]*recalculationProgressJSON, 0, len(progresses))
+	for _, p := range progresses {
+		result = append(result, &recalculationProgressJSON{
+			Name:    p.Name,
+			Status:  int(p.Status),
+			Running: p.Running,
+			Done:    p.Done,
+			Total:   p.Total,
+			Percent: p.Percent(),
+			Error:   p.Error,
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// StartRecalculation starts a recalculation from the beginning, or resumes it if the resume field is set
+func StartRecalculation(ctx *context.Context) {
+	name := ctx.Params(":name")
+	switch err := recalculation.Start(name, ctx.QueryBool("resume")); err {
+	case nil:
+		ctx.Flash.Success(ctx.Tr("admin.recalculations.started", ctx.Tr("admin.recalculations."+name)))
+	case recalculation.ErrUnknownRecalculation:
+		ctx.NotFound("Start", err)
+		return
+	case recalculation.ErrAlreadyRunning:
+		ctx.Flash.Error(ctx.Tr("admin.recalculations.already_running", ctx.Tr("admin.recalculations."+name)))
+	default:
+		ctx.ServerError("Start", err)
+		return
+	}
+	ctx.Redirect(setting.AppSubURL + "/admin/recalculations")
+}
+
+// CancelRecalculation cancels a running recalculation
+func CancelRecalculation(ctx *context.Context) {
+	name := ctx.Params(":name")
+	if err := recalculation.Cancel(name); err != nil {
+		ctx.Flash.Error(ctx.Tr("admin.recalculations.not_running", ctx.Tr("admin.recalculations."+name)))
+	} else {
+		ctx.Flash.Info(ctx.Tr("admin.recalculations.cancelled", ctx.Tr("admin.recalculations."+name)))
+	}
+	ctx.Redirect(setting.AppSubURL + "/admin/recalculations")
+}
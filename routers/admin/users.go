@@ -9,10 +9,12 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/password"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/routers"
@@ -147,6 +149,8 @@ func NewUserPost(ctx *context.Context, form auth.AdminCreateUserForm) {
 		return
 	}
 	log.Trace("Account created by admin (%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserCreate, ctx.User, ctx.RemoteAddr(), u, "Created the account %s", u.Name)
+	notification.NotifyCreateUser(ctx.User, u)
 
 	// Send email notification.
 	if form.SendNotify {
@@ -262,6 +266,7 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 	u.Website = form.Website
 	u.Location = form.Location
 	u.MaxRepoCreation = form.MaxRepoCreation
+	u.MaxStorageSize = form.MaxStorageSize
 	u.IsActive = form.Active
 	u.IsAdmin = form.Admin
 	u.IsRestricted = form.Restricted
@@ -289,6 +294,7 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 		return
 	}
 	log.Trace("Account profile updated by admin (%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserEdit, ctx.User, ctx.RemoteAddr(), u, "Edited the account %s (admin: %t, active: %t)", u.Name, u.IsAdmin, u.IsActive)
 
 	ctx.Flash.Success(ctx.Tr("admin.users.update_profile_success"))
 	ctx.Redirect(setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"))
@@ -314,12 +320,19 @@ func DeleteUser(ctx *context.Context) {
 			ctx.JSON(200, map[string]interface{}{
 				"redirect": setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"),
 			})
+		case models.IsErrUnderLegalHold(err):
+			ctx.Flash.Error(ctx.Tr("admin.users.under_legal_hold"))
+			ctx.JSON(200, map[string]interface{}{
+				"redirect": setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"),
+			})
 		default:
 			ctx.ServerError("DeleteUser", err)
 		}
 		return
 	}
 	log.Trace("Account deleted by admin (%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserDelete, ctx.User, ctx.RemoteAddr(), u, "Deleted the account %s", u.Name)
+	notification.NotifyDeleteUser(ctx.User, u)
 
 	ctx.Flash.Success(ctx.Tr("admin.users.deletion_success"))
 	ctx.JSON(200, map[string]interface{}{
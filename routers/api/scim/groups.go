@@ -0,0 +1,419 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/scim"
+)
+
+// toGroup converts a team to a SCIM group named <organization>/<team>, the members are only listed when given
+func toGroup(team *models.Team, org *models.User, members []*models.User) *scim.Group {
+	res := &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		DisplayName: org.Name + "/" + team.Name,
+		Members:     make([]scim.Member, 0, len(members)),
+		Meta:        &scim.Meta{ResourceType: "Group"},
+	}
+	// the teams created in dry-run mode have no ID
+	if team.ID > 0 {
+		res.ID = strconv.FormatInt(team.ID, 10)
+		res.Meta.Location = baseURL() + "/Groups/" + res.ID
+	}
+	for _, member := range members {
+		res.Members = append(res.Members, scim.Member{
+			Value:   strconv.FormatInt(member.ID, 10),
+			Display: member.Name,
+			Ref:     baseURL() + "/Users/" + strconv.FormatInt(member.ID, 10),
+		})
+	}
+	return res
+}
+
+// parseGroupName splits the name of a group into the names of the organization and the team
+func parseGroupName(displayName string) (orgName, teamName string, err error) {
+	fields := strings.SplitN(displayName, "/", 2)
+	if len(fields) != 2 || len(fields[0]) == 0 || len(fields[1]) == 0 {
+		return "", "", scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "the displayName of a group must be <organization>/<team>")
+	}
+	return fields[0], fields[1], nil
+}
+
+// groupMembers returns the users of the members of the group
+func groupMembers(res *scim.Group) ([]*models.User, error) {
+	users := make([]*models.User, 0, len(res.Members))
+	for _, member := range res.Members {
+		id, _ := strconv.ParseInt(member.Value, 10, 64)
+		u, err := models.GetUserByID(id)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				return nil, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "the member %s doesn't exist", member.Value)
+			}
+			return nil, err
+		}
+		if u.IsOrganization() {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "the member %s isn't a user", member.Value)
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// auditTeamMember records a change of the members of a team in the audit log
+func auditTeamMember(ctx *context.APIContext, action models.AuditAction, org *models.User, team *models.Team, member *models.User) {
+	verb := "Added %s to"
+	if action == models.AuditActionTeamMemberRemove {
+		verb = "Removed %s from"
+	}
+	audit.Record(action, ctx.User, ctx.RemoteAddr(), org, verb+" the team %s through SCIM", member.Name, team.Name)
+}
+
+// getGroup returns the team of the request and its organization, an error is rendered if it doesn't exist
+func getGroup(ctx *context.APIContext) (*models.Team, *models.User) {
+	team, err := models.GetTeamByID(resourceID(ctx))
+	if err != nil {
+		if models.IsErrTeamNotExist(err) {
+			renderError(ctx, scim.NewError(http.StatusNotFound, "", "the group %s doesn't exist", ctx.Params(":id")))
+		} else {
+			renderError(ctx, err)
+		}
+		return nil, nil
+	}
+	org, err := models.GetUserByID(team.OrgID)
+	if err != nil {
+		renderError(ctx, err)
+		return nil, nil
+	}
+	return team, org
+}
+
+// excludeMembers returns whether the identity provider doesn't need the members of the groups
+func excludeMembers(ctx *context.APIContext) bool {
+	for _, attr := range strings.Split(ctx.Query("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+// ListGroups responds with the groups matching the filter, only the filter on displayName is supported
+func ListGroups(ctx *context.APIContext) {
+	filter, err := scim.ParseFilter(ctx.Query("filter"))
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	startIndex, count := paginate(ctx)
+	opts := &models.FindSCIMTeamsOptions{
+		Start: startIndex - 1,
+		Limit: count,
+	}
+	if filter != nil {
+		if filter.Attribute != "displayname" {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidFilter, "unsupported filter attribute %s", filter.Attribute))
+			return
+		}
+		if opts.OrgName, opts.TeamName, err = parseGroupName(filter.Value); err != nil {
+			// no team has such a name
+			render(ctx, http.StatusOK, scim.NewListResponse([]*scim.Group{}, 0, 0, startIndex))
+			return
+		}
+	}
+
+	teams, total, err := models.FindSCIMTeams(opts)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	orgs := make(map[int64]*models.User)
+	resources := make([]*scim.Group, 0, len(teams))
+	for _, team := range teams {
+		org, ok := orgs[team.OrgID]
+		if !ok {
+			if org, err = models.GetUserByID(team.OrgID); err != nil {
+				renderError(ctx, err)
+				return
+			}
+			orgs[team.OrgID] = org
+		}
+		var members []*models.User
+		if !excludeMembers(ctx) {
+			if members, err = models.GetTeamMembers(team.ID); err != nil {
+				renderError(ctx, err)
+				return
+			}
+		}
+		resources = append(resources, toGroup(team, org, members))
+	}
+	render(ctx, http.StatusOK, scim.NewListResponse(resources, len(resources), total, startIndex))
+}
+
+// GetGroup responds with a group
+func GetGroup(ctx *context.APIContext) {
+	team, org := getGroup(ctx)
+	if ctx.Written() {
+		return
+	}
+	var members []*models.User
+	if !excludeMembers(ctx) {
+		var err error
+		if members, err = models.GetTeamMembers(team.ID); err != nil {
+			renderError(ctx, err)
+			return
+		}
+	}
+	render(ctx, http.StatusOK, toGroup(team, org, members))
+}
+
+// CreateGroup creates a team in an existing organization, it's granted read access to the default units of the
+// repositories it's given access to
+func CreateGroup(ctx *context.APIContext) {
+	res := new(scim.Group)
+	if !decodeBody(ctx, res) {
+		return
+	}
+	orgName, teamName, err := parseGroupName(res.DisplayName)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	org, err := models.GetOrgByName(orgName)
+	if err != nil {
+		if models.IsErrOrgNotExist(err) {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "the organization %s doesn't exist", orgName))
+		} else {
+			renderError(ctx, err)
+		}
+		return
+	}
+	members, err := groupMembers(res)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	team := &models.Team{
+		OrgID:     org.ID,
+		Name:      teamName,
+		Authorize: models.AccessModeRead,
+	}
+	for _, tp := range models.DefaultRepoUnits {
+		team.Units = append(team.Units, &models.TeamUnit{
+			OrgID: org.ID,
+			Type:  tp,
+		})
+	}
+
+	if dryRun(ctx, "create the team %s of %s with %d members", teamName, org.Name, len(members)) {
+		if err := models.IsUsableTeamName(teamName); err != nil {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "%v", err))
+			return
+		}
+		if _, err := models.GetTeam(org.ID, teamName); err == nil {
+			renderError(ctx, scim.NewError(http.StatusConflict, scim.ErrorTypeUniqueness, "the team %s already exists", res.DisplayName))
+			return
+		} else if !models.IsErrTeamNotExist(err) {
+			renderError(ctx, err)
+			return
+		}
+		render(ctx, http.StatusCreated, toGroup(team, org, members))
+		return
+	}
+
+	if err := models.NewTeam(team); err != nil {
+		if models.IsErrTeamAlreadyExist(err) {
+			renderError(ctx, scim.NewError(http.StatusConflict, scim.ErrorTypeUniqueness, "the team %s already exists", res.DisplayName))
+		} else if models.IsErrNameReserved(err) || models.IsErrNamePatternNotAllowed(err) {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "%v", err))
+		} else {
+			renderError(ctx, err)
+		}
+		return
+	}
+	log.Trace("Team created through SCIM by %s: %s/%s", ctx.User.Name, org.Name, team.Name)
+	for _, member := range members {
+		if err := team.AddMember(member.ID); err != nil {
+			renderError(ctx, err)
+			return
+		}
+		auditTeamMember(ctx, models.AuditActionTeamMemberAdd, org, team, member)
+	}
+
+	res = toGroup(team, org, members)
+	ctx.Resp.Header().Set("Location", res.Meta.Location)
+	render(ctx, http.StatusCreated, res)
+}
+
+// ReplaceGroup replaces the name and the members of a group
+func ReplaceGroup(ctx *context.APIContext) {
+	team, org := getGroup(ctx)
+	if ctx.Written() {
+		return
+	}
+	res := new(scim.Group)
+	if !decodeBody(ctx, res) {
+		return
+	}
+	updateGroup(ctx, team, org, res)
+}
+
+// PatchGroup modifies the name or the members of a group
+func PatchGroup(ctx *context.APIContext) {
+	team, org := getGroup(ctx)
+	if ctx.Written() {
+		return
+	}
+	req := new(scim.PatchRequest)
+	if !decodeBody(ctx, req) {
+		return
+	}
+
+	members, err := models.GetTeamMembers(team.ID)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	res := toGroup(team, org, members)
+	for _, op := range req.Operations {
+		if err := res.ApplyPatch(op); err != nil {
+			renderError(ctx, err)
+			return
+		}
+	}
+	updateGroup(ctx, team, org, res)
+}
+
+// updateGroup renames the team and syncs its members with the members of the group, a team can't be moved to
+// another organization
+func updateGroup(ctx *context.APIContext, team *models.Team, org *models.User, res *scim.Group) {
+	orgName, teamName, err := parseGroupName(res.DisplayName)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	if !strings.EqualFold(orgName, org.Name) {
+		renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeMutability, "the group can't be moved to another organization"))
+		return
+	}
+	members, err := groupMembers(res)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	if team.IsOwnerTeam() && len(members) == 0 {
+		renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeMutability, "the owners of an organization can't be removed"))
+		return
+	}
+
+	current, err := models.GetTeamMembers(team.ID)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	isMember := make(map[int64]bool, len(members))
+	for _, member := range members {
+		isMember[member.ID] = true
+	}
+	wasMember := make(map[int64]bool, len(current))
+	for _, member := range current {
+		wasMember[member.ID] = true
+	}
+
+	dry := dryRun(ctx, "update the team %s of %s (name: %s, members: %d)", team.Name, org.Name, teamName, len(members))
+
+	if teamName != team.Name {
+		if team.IsOwnerTeam() {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeMutability, "the owners team can't be renamed"))
+			return
+		}
+		if err := models.IsUsableTeamName(teamName); err != nil {
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "%v", err))
+			return
+		}
+		oldName := team.Name
+		team.Name = teamName
+		if dry {
+			if existing, err := models.GetTeam(org.ID, teamName); err == nil && existing.ID != team.ID {
+				renderError(ctx, scim.NewError(http.StatusConflict, scim.ErrorTypeUniqueness, "the team %s already exists", res.DisplayName))
+				return
+			}
+		} else {
+			if err := models.UpdateTeam(team, false, false); err != nil {
+				if models.IsErrTeamAlreadyExist(err) {
+					renderError(ctx, scim.NewError(http.StatusConflict, scim.ErrorTypeUniqueness, "the team %s already exists", res.DisplayName))
+				} else {
+					renderError(ctx, err)
+				}
+				return
+			}
+			log.Trace("Team renamed through SCIM by %s: %s/%s -> %s", ctx.User.Name, org.Name, oldName, team.Name)
+		}
+	}
+
+	for _, member := range members {
+		if wasMember[member.ID] {
+			continue
+		}
+		if dryRun(ctx, "add %s to the team %s of %s", member.Name, team.Name, org.Name) {
+			continue
+		}
+		if err := team.AddMember(member.ID); err != nil {
+			renderError(ctx, err)
+			return
+		}
+		auditTeamMember(ctx, models.AuditActionTeamMemberAdd, org, team, member)
+	}
+	for _, member := range current {
+		if isMember[member.ID] {
+			continue
+		}
+		if dryRun(ctx, "remove %s from the team %s of %s", member.Name, team.Name, org.Name) {
+			continue
+		}
+		if err := team.RemoveMember(member.ID); err != nil {
+			if models.IsErrLastOrgOwner(err) {
+				renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeMutability, "%s is the last owner of %s", member.Name, org.Name))
+			} else {
+				renderError(ctx, err)
+			}
+			return
+		}
+		auditTeamMember(ctx, models.AuditActionTeamMemberRemove, org, team, member)
+	}
+
+	render(ctx, http.StatusOK, toGroup(team, org, members))
+}
+
+// DeleteGroup deletes a team, except the owners team of an organization
+func DeleteGroup(ctx *context.APIContext) {
+	team, org := getGroup(ctx)
+	if ctx.Written() {
+		return
+	}
+	if team.IsOwnerTeam() {
+		renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeMutability, "the owners team can't be deleted"))
+		return
+	}
+	if dryRun(ctx, "delete the team %s of %s", team.Name, org.Name) {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := models.DeleteTeam(team); err != nil {
+		renderError(ctx, err)
+		return
+	}
+	log.Trace("Team deleted through SCIM by %s: %s/%s", ctx.User.Name, org.Name, team.Name)
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package scim serves the SCIM 2.0 endpoint the identity providers use to provision the users and to sync their
+// groups to organization teams.
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/scim"
+	"code.gitea.io/gitea/modules/setting"
+
+	"gitea.com/macaron/macaron"
+)
+
+// RegisterRoutes registers the routes of the SCIM endpoint
+func RegisterRoutes(m *macaron.Macaron) {
+	m.Group("/scim/v2", func() {
+		m.Get("/ServiceProviderConfig", GetServiceProviderConfig)
+		m.Get("/ResourceTypes", ListResourceTypes)
+		m.Combo("/Users").Get(ListUsers).Post(CreateUser)
+		m.Combo("/Users/:id").Get(GetUser).Put(ReplaceUser).Patch(PatchUser).Delete(DeleteUser)
+		m.Combo("/Groups").Get(ListGroups).Post(CreateGroup)
+		m.Combo("/Groups/:id").Get(GetGroup).Put(ReplaceGroup).Patch(PatchGroup).Delete(DeleteGroup)
+	}, context.APIContexter(), reqSCIM())
+}
+
+// reqSCIM only lets the access tokens of site administrators use the endpoint, the tokens restricted by scopes
+// must be granted the SCIM scope
+func reqSCIM() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if !setting.SCIM.Enabled {
+			renderError(ctx, scim.NewError(http.StatusNotFound, "", "SCIM is disabled"))
+			return
+		}
+		if true != ctx.Data["IsApiToken"] {
+			ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			renderError(ctx, scim.NewError(http.StatusUnauthorized, "", "an access token is required"))
+			return
+		}
+		if !ctx.Context.IsUserSiteAdmin() {
+			renderError(ctx, scim.NewError(http.StatusForbidden, "", "the token must belong to a site administrator"))
+			return
+		}
+		// the general tokens of the administrators can't be used, the SCIM scope must be granted explicitly
+		if token := ctx.AccessToken(); token == nil || token.IsRestrictedToRepos() || !token.HasScope(models.AccessTokenScopeSCIM) {
+			renderError(ctx, scim.NewError(http.StatusForbidden, "", "the token must be granted the admin:scim scope"))
+			return
+		}
+	}
+}
+
+// baseURL returns the URL of the endpoint
+func baseURL() string {
+	return setting.AppURL + "api/scim/v2"
+}
+
+// render responds with the resource or the message in the SCIM format
+func render(ctx *context.APIContext, status int, obj interface{}) {
+	ctx.Resp.Header().Set("Content-Type", scim.ContentType)
+	ctx.Resp.WriteHeader(status)
+	if err := json.NewEncoder(ctx.Resp).Encode(obj); err != nil {
+		log.Error("Encode: %v", err)
+	}
+}
+
+// renderError responds with the SCIM error, the other errors are internal errors
+func renderError(ctx *context.APIContext, err error) {
+	scimErr, ok := err.(*scim.Error)
+	if !ok {
+		log.ErrorWithSkip(1, "SCIM: %v", err)
+		scimErr = scim.NewError(http.StatusInternalServerError, "", "internal error")
+	}
+	render(ctx, scimErr.StatusCode(), scimErr)
+}
+
+// decodeBody decodes the JSON body of the request into the resource or the message
+func decodeBody(ctx *context.APIContext, obj interface{}) bool {
+	if err := json.NewDecoder(ctx.Req.Request.Body).Decode(obj); err != nil {
+		renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// paginate returns the offset of the first resource and the maximum number of resources of a list request
+func paginate(ctx *context.APIContext) (startIndex, count int) {
+	startIndex = ctx.QueryInt("startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count = setting.SCIM.MaxResults
+	if len(ctx.Query("count")) > 0 {
+		count = ctx.QueryInt("count")
+	}
+	if count < 0 {
+		count = 0
+	} else if count > setting.SCIM.MaxResults {
+		count = setting.SCIM.MaxResults
+	}
+	return startIndex, count
+}
+
+// resourceID parses the ID of the resource of the request, 0 if it is invalid
+func resourceID(ctx *context.APIContext) int64 {
+	id, err := strconv.ParseInt(ctx.Params(":id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// dryRun logs a change that is not applied in dry-run mode, and returns whether the dry-run mode is enabled
+func dryRun(ctx *context.APIContext, format string, args ...interface{}) bool {
+	if !setting.SCIM.DryRun {
+		return false
+	}
+	args = append([]interface{}{ctx.User.Name}, args...)
+	log.Info("SCIM dry run by %s: "+format, args...)
+	return true
+}
+
+// GetServiceProviderConfig responds with the features of the protocol supported by the endpoint
+func GetServiceProviderConfig(ctx *context.APIContext) {
+	render(ctx, http.StatusOK, &scim.ServiceProviderConfig{
+		Schemas: []string{scim.SchemaServiceProviderConfig},
+		Patch:   scim.Supported{Supported: true},
+		Filter: scim.FilterSupported{
+			Supported:  true,
+			MaxResults: setting.SCIM.MaxResults,
+		},
+		AuthenticationSchemes: []scim.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Access token",
+			Description: "Access token of a site administrator, in the Authorization header",
+		}},
+		Meta: &scim.Meta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     baseURL() + "/ServiceProviderConfig",
+		},
+	})
+}
+
+// ListResourceTypes responds with the types of the resources of the endpoint
+func ListResourceTypes(ctx *context.APIContext) {
+	resourceTypes := []*scim.ResourceType{
+		{
+			Schemas:     []string{scim.SchemaResourceType},
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "User account",
+			Schema:      scim.SchemaUser,
+			Meta: &scim.Meta{
+				ResourceType: "ResourceType",
+				Location:     baseURL() + "/ResourceTypes/User",
+			},
+		},
+		{
+			Schemas:     []string{scim.SchemaResourceType},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Organization team, named <organization>/<team>",
+			Schema:      scim.SchemaGroup,
+			Meta: &scim.Meta{
+				ResourceType: "ResourceType",
+				Location:     baseURL() + "/ResourceTypes/Group",
+			},
+		},
+	}
+	render(ctx, http.StatusOK, scim.NewListResponse(resourceTypes, len(resourceTypes), int64(len(resourceTypes)), 1))
+}
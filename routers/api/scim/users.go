@@ -0,0 +1,334 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/password"
+	"code.gitea.io/gitea/modules/scim"
+)
+
+// toUser converts a user to a SCIM user, a user is active when it's allowed to sign in
+func toUser(u *models.User) *scim.User {
+	active := u.IsActive && !u.ProhibitLogin
+	res := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		UserName:    u.Name,
+		DisplayName: u.FullName,
+		Active:      &active,
+		Meta:        &scim.Meta{ResourceType: "User"},
+	}
+	if len(u.FullName) > 0 {
+		res.Name = &scim.Name{Formatted: u.FullName}
+	}
+	if len(u.Email) > 0 {
+		res.Emails = []scim.Email{{Value: u.Email, Primary: true}}
+	}
+	// the users created in dry-run mode have no ID
+	if u.ID > 0 {
+		res.ID = strconv.FormatInt(u.ID, 10)
+		res.Meta.Created = u.CreatedUnix.AsTimePtr()
+		res.Meta.LastModified = u.UpdatedUnix.AsTimePtr()
+		res.Meta.Location = baseURL() + "/Users/" + res.ID
+	}
+	return res
+}
+
+// userError converts the errors of the creation or the update of a user to SCIM errors
+func userError(err error) error {
+	switch {
+	case models.IsErrUserAlreadyExist(err), models.IsErrEmailAlreadyUsed(err):
+		return scim.NewError(http.StatusConflict, scim.ErrorTypeUniqueness, "%v", err)
+	case models.IsErrNameReserved(err), models.IsErrNamePatternNotAllowed(err), models.IsErrNameCharsNotAllowed(err),
+		models.IsErrEmailInvalid(err):
+		return scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "%v", err)
+	}
+	return err
+}
+
+// validateUser checks the attributes of a user created or updated by the identity provider
+func validateUser(res *scim.User) error {
+	if len(res.UserName) == 0 {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "userName is required")
+	}
+	if len(res.PrimaryEmail()) == 0 {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "an email address is required")
+	}
+	if len(res.Password) > 0 && !password.IsComplexEnough(res.Password) {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "the password isn't complex enough")
+	}
+	return models.ValidateEmail(res.PrimaryEmail())
+}
+
+// checkUserName checks that a user can be created or renamed to the name, only needed in dry-run mode
+func checkUserName(name string) error {
+	if err := models.IsUsableUsername(name); err != nil {
+		return err
+	}
+	exist, err := models.IsUserExist(0, name)
+	if err != nil {
+		return err
+	} else if exist {
+		return models.ErrUserAlreadyExist{Name: name}
+	}
+	return nil
+}
+
+// checkEmail checks that the email address isn't used by another user
+func checkEmail(email string) error {
+	used, err := models.IsEmailUsed(email)
+	if err != nil {
+		return err
+	} else if used {
+		return models.ErrEmailAlreadyUsed{Email: email}
+	}
+	return nil
+}
+
+// getUser returns the user of the request, an error is rendered if it doesn't exist
+func getUser(ctx *context.APIContext) *models.User {
+	u, err := models.GetUserByID(resourceID(ctx))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			renderError(ctx, scim.NewError(http.StatusNotFound, "", "the user %s doesn't exist", ctx.Params(":id")))
+		} else {
+			renderError(ctx, err)
+		}
+		return nil
+	}
+	if u.IsOrganization() {
+		renderError(ctx, scim.NewError(http.StatusNotFound, "", "the user %s doesn't exist", ctx.Params(":id")))
+		return nil
+	}
+	return u
+}
+
+// ListUsers responds with the users matching the filter, only the filters on userName and emails are supported
+func ListUsers(ctx *context.APIContext) {
+	filter, err := scim.ParseFilter(ctx.Query("filter"))
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	startIndex, count := paginate(ctx)
+	opts := &models.FindSCIMUsersOptions{
+		Start: startIndex - 1,
+		Limit: count,
+	}
+	if filter != nil {
+		switch filter.Attribute {
+		case "username":
+			opts.Name = filter.Value
+		case "emails", "emails.value":
+			opts.Email = filter.Value
+		default:
+			renderError(ctx, scim.NewError(http.StatusBadRequest, scim.ErrorTypeInvalidFilter, "unsupported filter attribute %s", filter.Attribute))
+			return
+		}
+	}
+
+	users, total, err := models.FindSCIMUsers(opts)
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+	resources := make([]*scim.User, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, toUser(u))
+	}
+	render(ctx, http.StatusOK, scim.NewListResponse(resources, len(resources), total, startIndex))
+}
+
+// GetUser responds with a user
+func GetUser(ctx *context.APIContext) {
+	u := getUser(ctx)
+	if ctx.Written() {
+		return
+	}
+	render(ctx, http.StatusOK, toUser(u))
+}
+
+// CreateUser provisions a user, its password is random unless the identity provider sets it
+func CreateUser(ctx *context.APIContext) {
+	res := new(scim.User)
+	if !decodeBody(ctx, res) {
+		return
+	}
+	if err := validateUser(res); err != nil {
+		renderError(ctx, userError(err))
+		return
+	}
+
+	passwd := res.Password
+	if len(passwd) == 0 {
+		var err error
+		if passwd, err = generate.GetRandomString(40); err != nil {
+			renderError(ctx, err)
+			return
+		}
+	}
+	u := &models.User{
+		Name:          res.UserName,
+		FullName:      res.FullName(),
+		Email:         res.PrimaryEmail(),
+		Passwd:        passwd,
+		IsActive:      true,
+		ProhibitLogin: !res.IsActive(),
+		LoginType:     models.LoginPlain,
+	}
+
+	if dryRun(ctx, "create the user %s", u.Name) {
+		if err := checkUserName(u.Name); err != nil {
+			renderError(ctx, userError(err))
+			return
+		}
+		if err := checkEmail(u.Email); err != nil {
+			renderError(ctx, userError(err))
+			return
+		}
+		render(ctx, http.StatusCreated, toUser(u))
+		return
+	}
+
+	if err := models.CreateUser(u); err != nil {
+		renderError(ctx, userError(err))
+		return
+	}
+	log.Trace("Account provisioned through SCIM by %s: %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserCreate, ctx.User, ctx.RemoteAddr(), u, "Provisioned the account %s through SCIM", u.Name)
+	notification.NotifyCreateUser(ctx.User, u)
+
+	res = toUser(u)
+	ctx.Resp.Header().Set("Location", res.Meta.Location)
+	render(ctx, http.StatusCreated, res)
+}
+
+// ReplaceUser replaces the attributes of a user
+func ReplaceUser(ctx *context.APIContext) {
+	u := getUser(ctx)
+	if ctx.Written() {
+		return
+	}
+	res := new(scim.User)
+	if !decodeBody(ctx, res) {
+		return
+	}
+	updateUser(ctx, u, res)
+}
+
+// PatchUser modifies the attributes of a user, deactivating it sets active to false
+func PatchUser(ctx *context.APIContext) {
+	u := getUser(ctx)
+	if ctx.Written() {
+		return
+	}
+	req := new(scim.PatchRequest)
+	if !decodeBody(ctx, req) {
+		return
+	}
+
+	res := toUser(u)
+	for _, op := range req.Operations {
+		if err := res.ApplyPatch(op); err != nil {
+			renderError(ctx, err)
+			return
+		}
+	}
+	updateUser(ctx, u, res)
+}
+
+// updateUser updates the user with the attributes of the SCIM user, an inactive user is prohibited to sign in
+func updateUser(ctx *context.APIContext, u *models.User, res *scim.User) {
+	if err := validateUser(res); err != nil {
+		renderError(ctx, userError(err))
+		return
+	}
+	dry := dryRun(ctx, "update the user %s (name: %s, email: %s, active: %t)", u.Name, res.UserName, res.PrimaryEmail(), res.IsActive())
+
+	if !strings.EqualFold(u.Name, res.UserName) {
+		var err error
+		if dry {
+			err = checkUserName(res.UserName)
+		} else {
+			err = models.ChangeUserName(u, res.UserName)
+		}
+		if err != nil {
+			renderError(ctx, userError(err))
+			return
+		}
+	}
+	u.Name = res.UserName
+	u.LowerName = strings.ToLower(res.UserName)
+
+	if email := res.PrimaryEmail(); !strings.EqualFold(u.Email, email) {
+		if err := checkEmail(email); err != nil {
+			renderError(ctx, userError(err))
+			return
+		}
+		u.Email = email
+	}
+	u.FullName = res.FullName()
+	u.ProhibitLogin = !res.IsActive()
+	if res.IsActive() {
+		u.IsActive = true
+	}
+	if len(res.Password) > 0 {
+		var err error
+		if u.Salt, err = models.GetUserSalt(); err != nil {
+			renderError(ctx, err)
+			return
+		}
+		u.HashPassword(res.Password)
+	}
+
+	if dry {
+		render(ctx, http.StatusOK, toUser(u))
+		return
+	}
+	if err := models.UpdateUser(u); err != nil {
+		renderError(ctx, userError(err))
+		return
+	}
+	log.Trace("Account updated through SCIM by %s: %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserEdit, ctx.User, ctx.RemoteAddr(), u, "Updated the account %s through SCIM (active: %t)", u.Name, res.IsActive())
+
+	render(ctx, http.StatusOK, toUser(u))
+}
+
+// DeleteUser deletes a user, which fails while it owns repositories or organizations
+func DeleteUser(ctx *context.APIContext) {
+	u := getUser(ctx)
+	if ctx.Written() {
+		return
+	}
+	if dryRun(ctx, "delete the user %s", u.Name) {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := models.DeleteUser(u); err != nil {
+		if models.IsErrUserOwnRepos(err) || models.IsErrUserHasOrgs(err) || models.IsErrUnderLegalHold(err) {
+			renderError(ctx, scim.NewError(http.StatusConflict, "", "%v", err))
+		} else {
+			renderError(ctx, err)
+		}
+		return
+	}
+	log.Trace("Account deleted through SCIM by %s: %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserDelete, ctx.User, ctx.RemoteAddr(), u, "Deleted the account %s through SCIM", u.Name)
+	notification.NotifyDeleteUser(ctx.User, u)
+
+	ctx.Status(http.StatusNoContent)
+}
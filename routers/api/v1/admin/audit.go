@@ -0,0 +1,170 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// auditFilters returns the options of the filters of the audit log from the query parameters,
+// it writes the error to the context if they're invalid
+func auditFilters(ctx *context.APIContext) *models.FindAuditEventsOptions {
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return nil
+	}
+	return &models.FindAuditEventsOptions{
+		Action:     models.AuditAction(ctx.Query("action")),
+		DoerName:   ctx.Query("doer"),
+		TargetType: ctx.Query("target_type"),
+		TargetID:   ctx.QueryInt64("target_id"),
+		Since:      timeutil.TimeStamp(since),
+		Before:     timeutil.TimeStamp(before),
+	}
+}
+
+// ListAuditEvents api for listing the events of the audit log
+func ListAuditEvents(ctx *context.APIContext) {
+	// swagger:operation GET /admin/audit admin adminListAuditEvents
+	// ---
+	// summary: List the events of the audit log, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: only events of this action, e.g. user_login or collaborator_add
+	//   type: string
+	// - name: doer
+	//   in: query
+	//   description: only events done by this user
+	//   type: string
+	// - name: target_type
+	//   in: query
+	//   description: only events applying to this type of target
+	//   type: string
+	//   enum: [user, organization, repository, instance]
+	// - name: target_id
+	//   in: query
+	//   description: only events applying to the user, organization or repository of this id
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: only events recorded at or after this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only events recorded before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuditEventList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := auditFilters(ctx)
+	if ctx.Written() {
+		return
+	}
+	opts.ListOptions = utils.GetListOptions(ctx)
+	events, count, err := models.FindAuditEvents(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAuditEvents", err)
+		return
+	}
+
+	result := make([]*api.AuditEvent, 0, len(events))
+	for _, event := range events {
+		result = append(result, convert.ToAuditEvent(event))
+	}
+
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ExportAuditEvents api for exporting the events of the audit log as CSV
+func ExportAuditEvents(ctx *context.APIContext) {
+	// swagger:operation GET /admin/audit/export admin adminExportAuditEvents
+	// ---
+	// summary: Export the events of the audit log as CSV, the oldest first
+	// description: The columns are id, created, action, doer_id, doer_name, ip_address, target_type,
+	//   target_id, target_name and description.
+	// produces:
+	// - text/csv
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: only events of this action, e.g. user_login or collaborator_add
+	//   type: string
+	// - name: doer
+	//   in: query
+	//   description: only events done by this user
+	//   type: string
+	// - name: target_type
+	//   in: query
+	//   description: only events applying to this type of target
+	//   type: string
+	//   enum: [user, organization, repository, instance]
+	// - name: target_id
+	//   in: query
+	//   description: only events applying to the user, organization or repository of this id
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: only events recorded at or after this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only events recorded before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     description: success
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := auditFilters(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+	if err := audit.ExportCSV(ctx.Resp, opts); err != nil {
+		// the CSV may have been partially written already
+		log.Error("ExportCSV: %v", err)
+		ctx.Error(http.StatusInternalServerError, "ExportCSV", err)
+	}
+}
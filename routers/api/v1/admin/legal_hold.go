@@ -0,0 +1,212 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	legalhold_service "code.gitea.io/gitea/services/legalhold"
+)
+
+// ListLegalHolds api for listing the legal holds
+func ListLegalHolds(ctx *context.APIContext) {
+	// swagger:operation GET /admin/legal_holds admin adminListLegalHolds
+	// ---
+	// summary: List the legal holds
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LegalHoldList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	holds, count, err := models.GetLegalHolds(utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLegalHolds", err)
+		return
+	}
+
+	result := make([]*api.LegalHold, 0, len(holds))
+	for _, hold := range holds {
+		if err := hold.LoadAttributes(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+			return
+		}
+		result = append(result, convert.ToLegalHold(hold))
+	}
+
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateLegalHold api for placing a legal hold on a user or a repository
+func CreateLegalHold(ctx *context.APIContext, form api.CreateLegalHoldOption) {
+	// swagger:operation POST /admin/legal_holds admin adminCreateLegalHold
+	// ---
+	// summary: Place a legal hold on a user or a repository
+	// description: The content of a user or a repository under a legal hold can't be purged, the previous
+	//   and deleted revisions of its issues and comments are kept. Either a username or the owner and
+	//   the name of a repository must be given.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateLegalHoldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/LegalHold"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	hold := &models.LegalHold{
+		Reason: form.Reason,
+		DoerID: ctx.User.ID,
+	}
+	switch {
+	case len(form.Username) > 0 && len(form.Owner) == 0 && len(form.Repo) == 0:
+		user, err := models.GetUserByName(form.Username)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		hold.UserID = user.ID
+	case len(form.Username) == 0 && len(form.Owner) > 0 && len(form.Repo) > 0:
+		repo, err := models.GetRepositoryByOwnerAndName(form.Owner, form.Repo)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		hold.RepoID = repo.ID
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("either a username or the owner and the name of a repository must be given"))
+		return
+	}
+
+	if err := models.CreateLegalHold(hold); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateLegalHold", err)
+		return
+	}
+	if err := hold.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	log.Trace("Legal hold %d placed by admin(%s)", hold.ID, ctx.User.Name)
+
+	ctx.JSON(http.StatusCreated, convert.ToLegalHold(hold))
+}
+
+// DeleteLegalHold api for releasing a legal hold
+func DeleteLegalHold(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/legal_holds/{id} admin adminDeleteLegalHold
+	// ---
+	// summary: Release a legal hold
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the legal hold
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteLegalHold(ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrLegalHoldNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteLegalHold", err)
+		}
+		return
+	}
+	log.Trace("Legal hold %d released by admin(%s)", ctx.ParamsInt64(":id"), ctx.User.Name)
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ExportLegalHold api for exporting the content held by a legal hold
+func ExportLegalHold(ctx *context.APIContext) {
+	// swagger:operation GET /admin/legal_holds/{id}/export admin adminExportLegalHold
+	// ---
+	// summary: Export the content held by a legal hold
+	// description: The archive is a zip file of a content.json file, holding the current issues and comments
+	//   with their previous and deleted revisions, and of a content.json.sig file, holding the hex encoded
+	//   HMAC-SHA256 signature of content.json keyed with the secret key of the instance.
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the legal hold
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: success
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hold, err := models.GetLegalHoldByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrLegalHoldNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetLegalHoldByID", err)
+		}
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "application/zip")
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="legal-hold-%d.zip"`, hold.ID))
+	if err := legalhold_service.Export(hold, ctx.Resp); err != nil {
+		// the archive may have been partially written already
+		log.Error("Export[%d]: %v", hold.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Export", err)
+	}
+}
@@ -11,9 +11,11 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/password"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/user"
@@ -110,6 +112,8 @@ func CreateUser(ctx *context.APIContext, form api.CreateUserOption) {
 		return
 	}
 	log.Trace("Account created by admin (%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserCreate, ctx.User, ctx.RemoteAddr(), u, "Created the account %s", u.Name)
+	notification.NotifyCreateUser(ctx.User, u)
 
 	// Send email notification.
 	if form.SendNotify {
@@ -214,6 +218,9 @@ func EditUser(ctx *context.APIContext, form api.EditUserOption) {
 	if form.MaxRepoCreation != nil {
 		u.MaxRepoCreation = *form.MaxRepoCreation
 	}
+	if form.MaxStorageSize != nil {
+		u.MaxStorageSize = *form.MaxStorageSize
+	}
 	if form.AllowCreateOrganization != nil {
 		u.AllowCreateOrganization = *form.AllowCreateOrganization
 	}
@@ -230,6 +237,7 @@ func EditUser(ctx *context.APIContext, form api.EditUserOption) {
 		return
 	}
 	log.Trace("Account profile updated by admin (%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserEdit, ctx.User, ctx.RemoteAddr(), u, "Edited the account %s (admin: %t, active: %t)", u.Name, u.IsAdmin, u.IsActive)
 
 	ctx.JSON(http.StatusOK, convert.ToUser(u, ctx.IsSigned, ctx.User.IsAdmin))
 }
@@ -267,7 +275,8 @@ func DeleteUser(ctx *context.APIContext) {
 
 	if err := models.DeleteUser(u); err != nil {
 		if models.IsErrUserOwnRepos(err) ||
-			models.IsErrUserHasOrgs(err) {
+			models.IsErrUserHasOrgs(err) ||
+			models.IsErrUnderLegalHold(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "DeleteUser", err)
@@ -275,6 +284,8 @@ func DeleteUser(ctx *context.APIContext) {
 		return
 	}
 	log.Trace("Account deleted by admin(%s): %s", ctx.User.Name, u.Name)
+	audit.Record(models.AuditActionAdminUserDelete, ctx.User, ctx.RemoteAddr(), u, "Deleted the account %s", u.Name)
+	notification.NotifyDeleteUser(ctx.User, u)
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -404,3 +415,44 @@ func GetAllUsers(ctx *context.APIContext) {
 	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
 	ctx.JSON(http.StatusOK, &results)
 }
+
+// GetUserTwoFactorStatus get the compliance of a user with the two-factor authentication requirements
+func GetUserTwoFactorStatus(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/two_factor_status admin adminGetUserTwoFactorStatus
+	// ---
+	// summary: Get the compliance of a user with the two-factor authentication requirements
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TwoFactorStatus"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if u.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is an organization not a user", u.Name))
+		return
+	}
+
+	status, err := models.GetTwoFactorStatus(u)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTwoFactorStatus(status))
+}
@@ -82,8 +82,8 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/settings"
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
 	"code.gitea.io/gitea/routers/api/v1/user"
+	analytics_service "code.gitea.io/gitea/services/analytics"
 
-	"gitea.com/macaron/binding"
 	"gitea.com/macaron/macaron"
 )
 
@@ -95,7 +95,7 @@ func sudo() macaron.Handler {
 		}
 
 		if len(sudo) > 0 {
-			if ctx.IsSigned && ctx.User.IsAdmin {
+			if ctx.IsUserSiteAdmin() {
 				user, err := models.GetUserByName(sudo)
 				if err != nil {
 					if models.IsErrUserNotExist(err) {
@@ -108,15 +108,32 @@ func sudo() macaron.Handler {
 				log.Trace("Sudo from (%s) to: %s", ctx.User.Name, user.Name)
 				ctx.User = user
 			} else {
-				ctx.JSON(http.StatusForbidden, map[string]string{
-					"message": "Only administrators allowed to sudo.",
-				})
+				ctx.Error(http.StatusForbidden, "sudo", "Only administrators allowed to sudo.")
 				return
 			}
 		}
 	}
 }
 
+// countAPIRequest counts the API requests of the signed in users for the instance stats
+func countAPIRequest() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.IsSigned {
+			analytics_service.CountAPIRequest(ctx.User.ID)
+		}
+	}
+}
+
+// tokenScope only lets the access tokens restricted by scopes or to repositories use the routes of
+// a repository, their permissions on the repository are restricted by repoAssignment and reqToken
+func tokenScope() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.IsRestrictedToken() && len(ctx.Params(":reponame")) == 0 {
+			ctx.Error(http.StatusForbidden, "tokenScope", "the scope of the token doesn't allow this operation")
+		}
+	}
+}
+
 func repoAssignment() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		userName := ctx.Params(":username")
@@ -169,6 +186,9 @@ func repoAssignment() macaron.Handler {
 			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
 			return
 		}
+		if token := ctx.AccessToken(); token != nil {
+			ctx.Repo.Permission = token.RestrictPermission(repo, ctx.Repo.Permission)
+		}
 
 		if !ctx.Repo.HasAccess() {
 			ctx.NotFound()
@@ -178,9 +198,17 @@ func repoAssignment() macaron.Handler {
 }
 
 // Contexter middleware already checks token for user sign in process.
-func reqToken() macaron.Handler {
+// Access tokens restricted by scopes must be granted one of the given scopes, or one of the
+// scopes granting the write access to a unit if none is given.
+func reqToken(scopes ...string) macaron.Handler {
+	if len(scopes) == 0 {
+		scopes = models.AccessTokenWriteScopes
+	}
 	return func(ctx *context.APIContext) {
 		if true == ctx.Data["IsApiToken"] {
+			if token := ctx.AccessToken(); token != nil && !token.HasAnyScope(scopes...) {
+				ctx.Error(http.StatusForbidden, "reqToken", "the scope of the token doesn't allow this operation")
+			}
 			return
 		}
 		if ctx.Context.IsBasicAuth {
@@ -195,12 +223,29 @@ func reqToken() macaron.Handler {
 	}
 }
 
+// reqTokenWriteScope requires the access tokens restricted by scopes to be granted one of the given
+// scopes to modify the resources of a group of routes, the units they belong to may be readable otherwise
+func reqTokenWriteScope(scopes ...string) macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.Req.Method == http.MethodGet || ctx.Req.Method == http.MethodHead {
+			return
+		}
+		if token := ctx.AccessToken(); token != nil && !token.HasAnyScope(scopes...) {
+			ctx.Error(http.StatusForbidden, "reqTokenWriteScope", "the scope of the token doesn't allow this operation")
+		}
+	}
+}
+
 func reqBasicAuth() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		if !ctx.Context.IsBasicAuth {
 			ctx.Error(http.StatusUnauthorized, "reqBasicAuth", "basic auth required")
 			return
 		}
+		if ctx.IsRestrictedToken() {
+			ctx.Error(http.StatusForbidden, "reqBasicAuth", "the scope of the token doesn't allow this operation")
+			return
+		}
 		ctx.CheckForOTP()
 	}
 }
@@ -503,7 +548,7 @@ func mustNotBeArchived(ctx *context.APIContext) {
 
 // RegisterRoutes registers all v1 APIs routes to web application.
 func RegisterRoutes(m *macaron.Macaron) {
-	bind := binding.Bind
+	bind := context.APIBind
 
 	if setting.API.EnableSwagger {
 		m.Get("/swagger", misc.Swagger) // Render V1 by default
@@ -516,8 +561,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 		}
 		m.Get("/version", misc.Version)
 		m.Get("/signing-key.gpg", misc.SigningKey)
+		m.Get("/signing-key.pub", misc.SSHSigningKey)
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Get("/search", misc.Search)
+		m.Get("/hovercard", misc.Hovercard)
+		m.Get("/oembed", misc.OEmbed)
 		m.Group("/settings", func() {
 			m.Get("/ui", settings.GetGeneralUISettings)
 			m.Get("/api", settings.GetGeneralAPISettings)
@@ -548,7 +597,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/tokens", func() {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
-					m.Combo("/:id").Delete(user.DeleteAccessToken)
+					m.Combo("/:id").Get(user.GetAccessToken).
+						Patch(bind(api.EditAccessTokenOption{}), user.EditAccessToken).
+						Delete(user.DeleteAccessToken)
 				}, reqBasicAuth())
 			})
 		})
@@ -623,6 +674,18 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
 
 			m.Get("/teams", org.ListUserTeams)
+
+			m.Get("/quota", user.GetQuota)
+
+			m.Get("/two_factor_status", user.GetTwoFactorStatus)
+
+			m.Group("/replies", func() {
+				m.Combo("").Get(user.ListSavedReplies).
+					Post(bind(api.CreateSavedReplyOption{}), user.CreateSavedReply)
+				m.Combo("/:id").Get(user.GetSavedReply).
+					Patch(bind(api.EditSavedReplyOption{}), user.EditSavedReply).
+					Delete(user.DeleteSavedReply)
+			})
 		}, reqToken())
 
 		// Repositories
@@ -633,6 +696,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
 
+			m.Get("/search/code", repo.SearchAllCode)
+
 			m.Get("/issues/search", repo.SearchIssues)
 
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
@@ -642,6 +707,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Delete(reqToken(), reqOwner(), repo.Delete).
 					Patch(reqToken(), reqAdmin(), bind(api.EditRepoOption{}), context.RepoRefForAPI(), repo.Edit)
 				m.Post("/transfer", reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer)
+				m.Patch("/units", reqToken(), reqAdmin(), bind(api.EditRepoUnitsOption{}), repo.EditUnits)
+				m.Combo("/avatar", reqToken(), reqAdmin()).
+					Post(bind(api.UpdateRepoAvatarOption{}), repo.UpdateAvatar).
+					Delete(repo.DeleteAvatar)
 				m.Combo("/notifications").
 					Get(reqToken(), notify.ListRepoNotifications).
 					Put(reqToken(), notify.ReadRepoNotifications)
@@ -670,9 +739,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(reqAdmin(), repo.DeleteCollaborator)
 				}, reqToken())
 				m.Get("/raw/*", context.RepoRefForAPI(), reqRepoReader(models.UnitTypeCode), repo.GetRawFile)
+				m.Get("/blame/*", reqRepoReader(models.UnitTypeCode), repo.GetBlame)
+				m.Get("/symbols", reqRepoReader(models.UnitTypeCode), repo.ListSymbols)
+				m.Get("/search/code", reqRepoReader(models.UnitTypeCode), repo.SearchCode)
 				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
 				m.Combo("/forks").Get(repo.ListForks).
-					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+					Post(reqToken(models.AccessTokenScopeWriteCode), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
@@ -697,6 +769,19 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("/:id").Get(repo.GetDeployKey).
 						Delete(repo.DeleteDeploykey)
 				}, reqToken(), reqAdmin())
+				m.Group("/badges", func() {
+					m.Combo("").Get(repo.ListBadges).
+						Post(reqToken(), reqAdmin(), bind(api.CreateRepoBadgeOption{}), repo.CreateBadge)
+					m.Combo("/:id").Get(repo.GetBadge).
+						Patch(reqToken(), reqAdmin(), bind(api.EditRepoBadgeOption{}), repo.EditBadge).
+						Delete(reqToken(), reqAdmin(), repo.DeleteBadge)
+				})
+				m.Group("/stale_policy", func() {
+					m.Combo("").Get(repo.GetStalePolicy).
+						Put(bind(api.EditStalePolicyOption{}), repo.EditStalePolicy).
+						Delete(repo.DeleteStalePolicy)
+					m.Get("/actions", repo.ListStaleActions)
+				}, reqToken(), reqAdmin())
 				m.Group("/times", func() {
 					m.Combo("").Get(repo.ListTrackedTimesByRepository)
 					m.Combo("/:timetrackingusername").Get(repo.ListTrackedTimesByUser)
@@ -704,6 +789,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Combo("/archive", reqToken(), reqAdmin()).
+						Get(repo.DumpIssues).
+						Post(mustNotBeArchived, repo.RestoreIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/:id", func() {
@@ -726,6 +814,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 							m.Combo("/:id", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
+						m.Get("/timeline", repo.ListIssueTimeline)
+						m.Get("/dependencies/graph", repo.GetIssueDependencyGraph)
+						m.Group("/children", func() {
+							m.Combo("").Get(repo.ListSubIssues).
+								Post(reqToken(), mustNotBeArchived, bind(api.AttachSubIssueOption{}), repo.AttachSubIssue)
+							m.Get("/progress", repo.GetSubIssuesProgress)
+							m.Delete("/:child", reqToken(), mustNotBeArchived, repo.DetachSubIssue)
+						})
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
 								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
@@ -741,6 +837,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 							m.Delete("/:id", repo.DeleteTime)
 						}, reqToken())
 						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Group("/reminders", func() {
+							m.Combo("").Get(repo.ListIssueReminders).
+								Post(bind(api.CreateIssueReminderOption{}), repo.CreateIssueReminder)
+							m.Delete("/:id", repo.DeleteIssueReminder)
+						}, reqToken())
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
@@ -757,7 +858,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 							Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueReaction).
 							Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueReaction)
 					})
-				}, mustEnableIssuesOrPulls)
+				}, mustEnableIssuesOrPulls, reqTokenWriteScope(models.AccessTokenScopeWriteIssues, models.AccessTokenScopeWritePulls))
+				m.Get("/mentions", mustEnableIssuesOrPulls, repo.ListMentionCandidates)
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
 						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateLabelOption{}), repo.CreateLabel)
@@ -767,13 +869,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 				})
 				m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 				m.Post("/markdown/raw", misc.MarkdownRaw)
+				m.Post("/markup", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), bind(api.MarkupOption{}), repo.RenderMarkup)
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
 						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
 					m.Combo("/:id").Get(repo.GetMilestone).
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
+					m.Get("/:id/dependencies/graph", repo.GetMilestoneDependencyGraph)
+					m.Get("/:id/stats", repo.GetMilestoneStats)
 				})
+				m.Group("/projects/:id/automation_rules", func() {
+					m.Combo("").Get(repo.ListProjectAutomationRules).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeProjects), bind(api.CreateProjectAutomationRuleOption{}), repo.CreateProjectAutomationRule)
+					m.Delete("/:ruleid", reqToken(), reqRepoWriter(models.UnitTypeProjects), repo.DeleteProjectAutomationRule)
+				}, reqRepoReader(models.UnitTypeProjects))
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
@@ -815,6 +925,17 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), mustNotBeArchived, bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Combo("/merge_queue").Get(repo.GetPullMergeQueueEntry).
+							Post(reqToken(), mustNotBeArchived, bind(auth.MergePullRequestForm{}), repo.AddPullToMergeQueue).
+							Delete(reqToken(), repo.RemovePullFromMergeQueue)
+						m.Combo("/auto_publish").Get(repo.GetPullAutoPublish).
+							Post(reqToken(), mustNotBeArchived, bind(api.PullAutoPublishOptions{}), repo.SetPullAutoPublish).
+							Delete(reqToken(), repo.CancelPullAutoPublish)
+						m.Group("/environments", func() {
+							m.Combo("").Get(repo.ListPullReviewEnvironments).
+								Post(reqToken(models.AccessTokenScopeWriteStatus), reqRepoWriter(models.UnitTypeCode), bind(api.CreateReviewEnvironmentOption{}), repo.CreatePullReviewEnvironment)
+							m.Delete("/:name", reqToken(models.AccessTokenScopeWriteStatus), reqRepoWriter(models.UnitTypeCode), repo.DeletePullReviewEnvironment)
+						})
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -828,14 +949,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 									Get(repo.GetPullReviewComments)
 							})
 						})
+						m.Get("/file_tree", repo.GetPullFileTree)
+						m.Get("/files", repo.GetPullDiffFiles)
+						m.Combo("/viewed_files", reqToken()).
+							Get(repo.ListPullViewedFiles).
+							Post(bind(api.UpdatePullViewedFilesOption{}), repo.UpdatePullViewedFiles)
+						m.Post("/suggestions", reqToken(), mustNotBeArchived, bind(api.ApplyPullReviewSuggestionsOptions{}), repo.ApplyPullReviewSuggestions)
 						m.Combo("/requested_reviewers").
 							Delete(reqToken(), bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(reqToken(), bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
 					})
-				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
+				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), reqTokenWriteScope(models.AccessTokenScopeWritePulls), context.ReferencesGitRepo(false))
+				m.Get("/merge_queues/*", mustAllowPulls, reqRepoReader(models.UnitTypeCode), repo.ListMergeQueue)
 				m.Group("/statuses", func() {
 					m.Combo("/:sha").Get(repo.GetCommitStatuses).
-						Post(reqToken(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
+						Post(reqToken(models.AccessTokenScopeWriteStatus), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/commits", func() {
 					m.Get("", repo.GetAllCommits)
@@ -853,7 +981,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("/trees/:sha", context.RepoRefForAPI(), repo.GetTree)
 					m.Get("/blobs/:sha", context.RepoRefForAPI(), repo.GetBlob)
 					m.Get("/tags/:sha", context.RepoRefForAPI(), repo.GetTag)
+					m.Get("/graph", context.ReferencesGitRepo(false), repo.GetCommitGraph)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.CompareDiff)
+				// the pattern matches the literal "contents:batch", a colon would start a parameter
+				m.Post(`/^contents:op(\x3Abatch)$`, reqRepoReader(models.UnitTypeCode), reqRepoWriter(models.UnitTypeCode), reqToken(),
+					bind(api.ChangeFilesOptions{}), repo.ChangeFiles)
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContentsList)
 					m.Get("/*", repo.GetContents)
@@ -864,6 +997,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 					}, reqRepoWriter(models.UnitTypeCode), reqToken())
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Get("/signing-key.gpg", misc.SigningKey)
+				m.Get("/signing-key.pub", misc.SSHSigningKey)
+		m.Get("/signing-key.pub", misc.SSHSigningKey)
 				m.Group("/topics", func() {
 					m.Combo("").Get(repo.ListTopics).
 						Put(reqToken(), reqAdmin(), bind(api.RepoTopicOptions{}), repo.UpdateTopics)
@@ -874,6 +1009,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 				}, reqAnyRepoReader())
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
+				m.Get("/size", reqToken(), reqAdmin(), repo.GetSize)
+				m.Get("/insights/reviewers", reqRepoReader(models.UnitTypePullRequests), repo.ListReviewerStats)
+				m.Group("/short_links", func() {
+					m.Post("", reqToken(models.AccessTokenScopeWriteCode), bind(api.CreateShortLinkOption{}), repo.CreateShortLink)
+					m.Get("/:token", repo.GetShortLink)
+				}, reqRepoReader(models.UnitTypeCode))
 			}, repoAssignment())
 		})
 
@@ -886,8 +1027,17 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Combo("").Get(org.Get).
 				Patch(reqToken(), reqOrgOwnership(), bind(api.EditOrgOption{}), org.Edit).
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
+			m.Combo("/avatar", reqToken(), reqOrgOwnership()).
+				Post(bind(api.UpdateOrgAvatarOption{}), org.UpdateAvatar).
+				Delete(org.DeleteAvatar)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Get("/insights/reviewers", org.ListReviewerStats)
+			m.Get("/quota", reqToken(), reqOrgOwnership(), org.GetQuota)
+			m.Group("/repos/units", func() {
+				m.Post("", bind(api.BulkEditRepoUnitsOption{}), org.BulkEditRepoUnits)
+				m.Get("/:id", org.GetRepoUnitsTask)
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/members", func() {
 				m.Get("", org.ListMembers)
 				m.Combo("/:username").Get(org.IsMember).
@@ -904,6 +1054,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Post(reqOrgOwnership(), bind(api.CreateTeamOption{}), org.CreateTeam)
 				m.Get("/search", org.SearchTeam)
 			}, reqOrgMembership())
+			m.Group("/replies", func() {
+				m.Combo("").Get(org.ListSavedReplies).
+					Post(reqOrgOwnership(), bind(api.CreateSavedReplyOption{}), org.CreateSavedReply)
+				m.Combo("/:id").Get(org.GetSavedReply).
+					Patch(reqOrgOwnership(), bind(api.EditSavedReplyOption{}), org.EditSavedReply).
+					Delete(reqOrgOwnership(), org.DeleteSavedReply)
+			}, reqToken(), reqOrgMembership())
+			m.Group("/sla_policies", func() {
+				m.Combo("").Get(org.ListIssueSLAPolicies).
+					Post(reqOrgOwnership(), bind(api.CreateIssueSLAPolicyOption{}), org.CreateIssueSLAPolicy)
+				m.Combo("/:id").Get(org.GetIssueSLAPolicy).
+					Patch(reqOrgOwnership(), bind(api.EditIssueSLAPolicyOption{}), org.EditIssueSLAPolicy).
+					Delete(reqOrgOwnership(), org.DeleteIssueSLAPolicy)
+			}, reqToken(), reqOrgMembership())
+			m.Get("/sla_report", reqToken(), reqOrgMembership(), org.GetIssueSLAReport)
 			m.Group("/labels", func() {
 				m.Get("", org.ListLabels)
 				m.Post("", reqToken(), reqOrgOwnership(), bind(api.CreateLabelOption{}), org.CreateLabel)
@@ -936,6 +1101,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Put(org.AddTeamRepository).
 					Delete(org.RemoveTeamRepository)
 			})
+			m.Group("/watches", func() {
+				m.Get("", org.GetTeamWatchedRepos)
+				m.Combo("/:org/:reponame", reqOrgOwnership()).
+					Put(org.AddTeamWatchRule).
+					Delete(org.RemoveTeamWatchRule)
+			})
 		}, orgAssignment(false, true), reqToken(), reqTeamMembership())
 
 		m.Any("/*", func(ctx *context.APIContext) {
@@ -961,6 +1132,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("/orgs", org.ListUserOrgs)
 					m.Post("/orgs", bind(api.CreateOrgOption{}), admin.CreateOrg)
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
+					m.Get("/two_factor_status", admin.GetUserTwoFactorStatus)
 				})
 			})
 			m.Group("/unadopted", func() {
@@ -968,12 +1140,22 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/:username/:reponame", admin.AdoptRepository)
 				m.Delete("/:username/:reponame", admin.DeleteUnadoptedRepository)
 			})
+			m.Group("/legal_holds", func() {
+				m.Combo("").Get(admin.ListLegalHolds).
+					Post(bind(api.CreateLegalHoldOption{}), admin.CreateLegalHold)
+				m.Delete("/:id", admin.DeleteLegalHold)
+				m.Get("/:id/export", admin.ExportLegalHold)
+			})
+			m.Group("/audit", func() {
+				m.Get("", admin.ListAuditEvents)
+				m.Get("/export", admin.ExportAuditEvents)
+			})
 		}, reqToken(), reqSiteAdmin())
 
 		m.Group("/topics", func() {
 			m.Get("/search", repo.TopicSearch)
 		})
-	}, securityHeaders(), context.APIContexter(), sudo())
+	}, securityHeaders(), context.APIContexter(), countAPIRequest(), sudo(), tokenScope())
 }
 
 func securityHeaders() macaron.Handler {
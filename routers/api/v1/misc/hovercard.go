@@ -0,0 +1,137 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/hovercard"
+)
+
+// hovercardExcerptLength is the maximum number of characters of the excerpts of the cards
+const hovercardExcerptLength = 200
+
+// Hovercard returns the summary card of the issue, the pull request, the commit or the user of a link
+func Hovercard(ctx *context.APIContext) {
+	// swagger:operation GET /hovercard miscellaneous getHovercard
+	// ---
+	// summary: Get the summary card of the issue, the pull request, the commit or the user of a link
+	// description: The links are the web links of this instance, absolute or relative to its root.
+	//   The objects which can't be read by the user aren't found.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: url
+	//   in: query
+	//   description: link of the issue, the pull request, the commit or the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hovercard"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	link := ctx.QueryTrim("url")
+	if len(link) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("a link is required"))
+		return
+	}
+
+	card, err := hovercard.Resolve(ctx.User, link)
+	if err != nil {
+		if err == hovercard.ErrUnknownLink {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "Resolve", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toHovercard(ctx, card))
+}
+
+func toHovercard(ctx *context.APIContext, card *hovercard.Card) *api.Hovercard {
+	result := &api.Hovercard{
+		Type:    string(card.Kind),
+		HTMLURL: card.Link,
+	}
+	if card.Repo != nil {
+		result.Repo = &api.RepositoryMeta{
+			ID:       card.Repo.ID,
+			Name:     card.Repo.Name,
+			Owner:    card.Repo.OwnerName,
+			FullName: card.Repo.FullName(),
+		}
+	}
+
+	switch card.Kind {
+	case hovercard.KindIssue, hovercard.KindPull:
+		issue := card.Issue
+		result.Title = issue.Title
+		result.Excerpt = base.EllipsisString(issue.Content, hovercardExcerptLength)
+		result.Number = issue.Index
+		result.State = string(issue.State())
+		if issue.IsPull && issue.PullRequest.HasMerged {
+			result.State = "merged"
+		}
+		if len(issue.OriginalAuthor) == 0 {
+			result.User = convert.ToUser(issue.Poster, ctx.IsSigned, false)
+		}
+		result.Labels = convert.ToLabelList(issue.Labels)
+		result.Assignees = make([]*api.User, 0, len(issue.Assignees))
+		for _, assignee := range issue.Assignees {
+			result.Assignees = append(result.Assignees, convert.ToUser(assignee, ctx.IsSigned, false))
+		}
+		result.Comments = issue.NumComments
+		result.Created = issue.CreatedUnix.AsTime()
+	case hovercard.KindCommit:
+		commit := card.Commit
+		result.Title = commit.Summary()
+		result.Excerpt = base.EllipsisString(strings.TrimSpace(strings.TrimPrefix(commit.Message(), commit.Summary())), hovercardExcerptLength)
+		result.SHA = commit.ID.String()
+		result.CommitAuthor = convert.ToCommitUser(commit.Author)
+		if author, err := models.GetUserByEmail(commit.Author.Email); err == nil {
+			result.User = convert.ToUser(author, ctx.IsSigned, false)
+		} else if !models.IsErrUserNotExist(err) {
+			log.Error("GetUserByEmail: %v", err)
+		}
+		result.Created = commit.Author.When
+	case hovercard.KindUser:
+		user := card.User
+		result.Title = user.DisplayName()
+		result.Excerpt = base.EllipsisString(user.Description, hovercardExcerptLength)
+		result.User = convert.ToUser(user, ctx.IsSigned, false)
+		result.Created = user.CreatedUnix.AsTime()
+	}
+
+	if len(card.Statuses) > 0 {
+		checks := &api.HovercardChecks{
+			State:      api.StatusState(models.CalcCommitStatus(card.Statuses).State),
+			TotalCount: len(card.Statuses),
+		}
+		for _, status := range card.Statuses {
+			switch {
+			case status.State.IsSuccess():
+				checks.SuccessCount++
+			case status.State.IsPending():
+				checks.PendingCount++
+			default:
+				checks.FailureCount++
+			}
+		}
+		result.Checks = checks
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/embed"
+)
+
+const (
+	// oEmbedWidth is the width of the embedded snippets when the consumer doesn't limit it
+	oEmbedWidth = 800
+	// oEmbedHeaderHeight and oEmbedLineHeight approximate the height of the embedded snippets
+	oEmbedHeaderHeight = 48
+	oEmbedLineHeight   = 18
+)
+
+// OEmbed returns the oEmbed response of a permalink to lines of a file
+func OEmbed(ctx *context.APIContext) {
+	// swagger:operation GET /oembed miscellaneous getOEmbed
+	// ---
+	// summary: Get the oEmbed response of a permalink to lines of a file
+	// description: The permalinks are the links of files at a commit, like
+	//   {owner}/{repo}/src/commit/{sha}/{path}#L10-L20, absolute or relative to the root of this instance.
+	//   The response embeds a self-contained HTML snippet of the highlighted lines, the files which
+	//   can't be read by the user aren't found.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: url
+	//   in: query
+	//   description: permalink of the lines of a file
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the response, only json is supported
+	//   type: string
+	// - name: maxwidth
+	//   in: query
+	//   description: maximum width of the embedded snippet
+	//   type: integer
+	// - name: maxheight
+	//   in: query
+	//   description: maximum height of the embedded snippet
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OEmbed"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "501":
+	//     "$ref": "#/responses/empty"
+
+	link := ctx.QueryTrim("url")
+	if len(link) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("a link is required"))
+		return
+	}
+	if format := ctx.Query("format"); len(format) > 0 && format != "json" {
+		ctx.Status(http.StatusNotImplemented)
+		return
+	}
+
+	snippet, err := embed.Resolve(ctx.User, link)
+	if err != nil {
+		if err == embed.ErrUnknownLink {
+			ctx.NotFound()
+		} else if err == embed.ErrNotEmbeddable {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "Resolve", err)
+		}
+		return
+	}
+
+	width := oEmbedWidth
+	if maxWidth := ctx.QueryInt("maxwidth"); maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+	height := oEmbedHeaderHeight + snippet.Lines()*oEmbedLineHeight
+	if maxHeight := ctx.QueryInt("maxheight"); maxHeight > 0 && maxHeight < height {
+		height = maxHeight
+	}
+	ctx.JSON(http.StatusOK, &api.OEmbed{
+		Version:      "1.0",
+		Type:         "rich",
+		Title:        snippet.Repo.FullName() + "/" + snippet.TreePath,
+		ProviderName: setting.AppName,
+		ProviderURL:  setting.AppURL,
+		HTML:         snippet.HTML,
+		Width:        width,
+		Height:       height,
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/search"
+)
+
+// Search search the repositories, the code, the issues, the pull requests, the users and the organizations
+func Search(ctx *context.APIContext) {
+	// swagger:operation GET /search miscellaneous search
+	// ---
+	// summary: Search the repositories, the code, the issues, the pull requests, the users and the organizations
+	// description: All the types of results are searched in parallel and counted, only the objects readable
+	//   by the user are returned. The code is only searched if the code indexer is enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: type of the listed results, the first results of every type are listed if it is empty
+	//   type: string
+	//   enum: [repositories, code, issues, pulls, users, organizations]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, or of the results of every type if no type is requested
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GlobalSearchResults"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := ctx.QueryTrim("q")
+	if len(keyword) == 0 || strings.IndexByte(keyword, 0) >= 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("a keyword is required"))
+		return
+	}
+	searchType := search.Type(ctx.Query("type"))
+	if len(searchType) > 0 && !search.IsValidType(searchType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("invalid type"))
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	result, err := search.Search(&search.Options{
+		Actor:       ctx.User,
+		Keyword:     keyword,
+		Type:        searchType,
+		Page:        listOptions.Page,
+		PageSize:    listOptions.PageSize,
+		PreviewSize: listOptions.PageSize,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Search", err)
+		return
+	}
+
+	apiResults := &api.GlobalSearchResults{
+		Facets:        make([]*api.SearchFacet, len(result.Facets)),
+		Repositories:  make([]*api.Repository, 0, len(result.Repos)),
+		Code:          make([]*api.CodeSearchResult, 0, len(result.Code)),
+		Issues:        convert.ToAPIIssueList(result.Issues),
+		PullRequests:  convert.ToAPIIssueList(result.Pulls),
+		Users:         make([]*api.User, len(result.Users)),
+		Organizations: make([]*api.Organization, len(result.Organizations)),
+	}
+	for i, facet := range result.Facets {
+		apiResults.Facets[i] = &api.SearchFacet{Type: string(facet.Type), Count: facet.Count}
+	}
+	for _, repo := range result.Repos {
+		accessMode, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiResults.Repositories = append(apiResults.Repositories, convert.ToRepo(repo, accessMode))
+	}
+	for _, codeResult := range result.Code {
+		if repo, ok := result.CodeRepos[codeResult.RepoID]; ok {
+			apiResults.Code = append(apiResults.Code, convert.ToCodeSearchResult(repo, codeResult))
+		}
+	}
+	for i, user := range result.Users {
+		apiResults.Users[i] = convert.ToUser(user, ctx.IsSigned, ctx.User != nil && ctx.User.IsAdmin)
+	}
+	for i, org := range result.Organizations {
+		apiResults.Organizations[i] = convert.ToOrganization(org)
+	}
+	ctx.JSON(http.StatusOK, apiResults)
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 )
 
 // SigningKey returns the public key of the default signing key if it exists
@@ -52,7 +53,7 @@ func SigningKey(ctx *context.APIContext) {
 		path = ctx.Repo.Repository.RepoPath()
 	}
 
-	content, err := models.PublicSigningKey(path)
+	content, err := models.PublicSigningKey(path, git.SigningKeyFormatOpenPGP)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "gpg export", err)
 		return
@@ -62,3 +63,54 @@ func SigningKey(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "gpg export", fmt.Errorf("Error writing key content %v", err))
 	}
 }
+
+// SSHSigningKey returns the public key of the default signing key if it is an SSH key
+func SSHSigningKey(ctx *context.APIContext) {
+	// swagger:operation GET /signing-key.pub miscellaneous getSSHSigningKey
+	// ---
+	// summary: Get default SSH signing-key.pub
+	// produces:
+	//     - text/plain
+	// responses:
+	//   "200":
+	//     description: "SSH public key in the authorized_keys format"
+	//     schema:
+	//       type: string
+
+	// swagger:operation GET /repos/{owner}/{repo}/signing-key.pub repository repoSSHSigningKey
+	// ---
+	// summary: Get SSH signing-key.pub for given repository
+	// produces:
+	//     - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: "SSH public key in the authorized_keys format"
+	//     schema:
+	//       type: string
+
+	path := ""
+	if ctx.Repo != nil && ctx.Repo.Repository != nil {
+		path = ctx.Repo.Repository.RepoPath()
+	}
+
+	content, err := models.PublicSigningKey(path, git.SigningKeyFormatSSH)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ssh-keygen", err)
+		return
+	}
+	_, err = ctx.Write([]byte(content))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ssh-keygen", fmt.Errorf("Error writing key content %v", err))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -107,6 +108,8 @@ func CreateHook(ctx *context.APIContext, form api.CreateHookOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	//TODO in body params
 	if !utils.CheckCreateHookOption(ctx, &form) {
@@ -143,6 +146,8 @@ func EditHook(ctx *context.APIContext, form api.EditHookOption) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	//TODO in body params
 	hookID := ctx.ParamsInt64(":id")
@@ -182,5 +187,6 @@ func DeleteHook(ctx *context.APIContext) {
 		}
 		return
 	}
+	audit.RecordWebhook(models.AuditActionWebhookDelete, ctx.User, ctx.RemoteAddr(), &models.Webhook{ID: hookID, OrgID: org.ID})
 	ctx.Status(http.StatusNoContent)
 }
@@ -0,0 +1,343 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+var errInvalidSLATargets = errors.New("response_hours and resolution_hours can't be negative and one of them must be positive")
+
+func validSLATargets(responseHours, resolutionHours int) bool {
+	return responseHours >= 0 && resolutionHours >= 0 && responseHours+resolutionHours > 0
+}
+
+// getPolicyDoer returns the user which last changed a SLA policy, it is nil if it has been deleted
+func getPolicyDoer(ctx *context.APIContext, policy *models.IssueSLAPolicy) *models.User {
+	doer, err := models.GetUserByID(policy.DoerID)
+	if err != nil && !models.IsErrUserNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+		return nil
+	}
+	return doer
+}
+
+// ListIssueSLAPolicies list the SLA policies of an organization
+func ListIssueSLAPolicies(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/sla_policies organization orgListIssueSLAPolicies
+	// ---
+	// summary: List the SLA policies of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicyList"
+
+	policies, err := models.GetIssueSLAPolicies(ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSLAPolicies", err)
+		return
+	}
+	result := make([]*api.IssueSLAPolicy, 0, len(policies))
+	for _, policy := range policies {
+		doer := getPolicyDoer(ctx, policy)
+		if ctx.Written() {
+			return
+		}
+		result = append(result, convert.ToIssueSLAPolicy(policy, doer))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateIssueSLAPolicy create a SLA policy of an organization
+func CreateIssueSLAPolicy(ctx *context.APIContext, form api.CreateIssueSLAPolicyOption) {
+	// swagger:operation POST /orgs/{org}/sla_policies organization orgCreateIssueSLAPolicy
+	// ---
+	// summary: Create a SLA policy of an organization
+	// description: The open issues breaching a target of an enabled policy are labeled and commented once
+	//   on behalf of the user which last changed the policy.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueSLAPolicyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !validSLATargets(form.ResponseHours, form.ResolutionHours) {
+		ctx.Error(http.StatusUnprocessableEntity, "", errInvalidSLATargets)
+		return
+	}
+
+	policy := &models.IssueSLAPolicy{
+		OrgID:           ctx.Org.Organization.ID,
+		Name:            form.Name,
+		Enabled:         form.Enabled,
+		Label:           form.Label,
+		IncludePulls:    form.IncludePulls,
+		ResponseHours:   form.ResponseHours,
+		ResolutionHours: form.ResolutionHours,
+		BreachLabel:     form.BreachLabel,
+		BreachComment:   form.BreachComment,
+		DoerID:          ctx.User.ID,
+	}
+	if err := models.CreateIssueSLAPolicy(policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateIssueSLAPolicy", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToIssueSLAPolicy(policy, ctx.User))
+}
+
+func getIssueSLAPolicy(ctx *context.APIContext) *models.IssueSLAPolicy {
+	policy, err := models.GetIssueSLAPolicyByID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueSLAPolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueSLAPolicyByID", err)
+		}
+		return nil
+	}
+	return policy
+}
+
+// GetIssueSLAPolicy get a SLA policy of an organization
+func GetIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/sla_policies/{id} organization orgGetIssueSLAPolicy
+	// ---
+	// summary: Get a SLA policy of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the policy
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy := getIssueSLAPolicy(ctx)
+	if ctx.Written() {
+		return
+	}
+	doer := getPolicyDoer(ctx, policy)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueSLAPolicy(policy, doer))
+}
+
+// EditIssueSLAPolicy edit a SLA policy of an organization
+func EditIssueSLAPolicy(ctx *context.APIContext, form api.EditIssueSLAPolicyOption) {
+	// swagger:operation PATCH /orgs/{org}/sla_policies/{id} organization orgEditIssueSLAPolicy
+	// ---
+	// summary: Edit a SLA policy of an organization
+	// description: The actions of the policy are then taken on behalf of the user editing it.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the policy
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueSLAPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	policy := getIssueSLAPolicy(ctx)
+	if ctx.Written() {
+		return
+	}
+	if form.Name != nil && len(*form.Name) > 0 {
+		policy.Name = *form.Name
+	}
+	if form.Enabled != nil {
+		policy.Enabled = *form.Enabled
+	}
+	if form.Label != nil && len(*form.Label) > 0 {
+		policy.Label = *form.Label
+	}
+	if form.IncludePulls != nil {
+		policy.IncludePulls = *form.IncludePulls
+	}
+	if form.ResponseHours != nil {
+		policy.ResponseHours = *form.ResponseHours
+	}
+	if form.ResolutionHours != nil {
+		policy.ResolutionHours = *form.ResolutionHours
+	}
+	if form.BreachLabel != nil {
+		policy.BreachLabel = *form.BreachLabel
+	}
+	if form.BreachComment != nil {
+		policy.BreachComment = *form.BreachComment
+	}
+	if !validSLATargets(policy.ResponseHours, policy.ResolutionHours) {
+		ctx.Error(http.StatusUnprocessableEntity, "", errInvalidSLATargets)
+		return
+	}
+	policy.DoerID = ctx.User.ID
+
+	if err := models.UpdateIssueSLAPolicy(policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateIssueSLAPolicy", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueSLAPolicy(policy, ctx.User))
+}
+
+// DeleteIssueSLAPolicy delete a SLA policy of an organization
+func DeleteIssueSLAPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/sla_policies/{id} organization orgDeleteIssueSLAPolicy
+	// ---
+	// summary: Delete a SLA policy of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the policy
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteIssueSLAPolicy(ctx.Org.Organization.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrIssueSLAPolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteIssueSLAPolicy", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetIssueSLAReport get the reports of the SLA policies of an organization
+func GetIssueSLAReport(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/sla_report organization orgGetIssueSLAReport
+	// ---
+	// summary: Get how the issues created over a period comply with the SLA policies of an organization
+	// description: The clock of an issue starts when the label of a policy is last added to it. The first
+	//   response is the first comment of a member of the organization except the poster, or the closing
+	//   of the issue if it's earlier.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only count the issues created after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only count the issues created before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSLAReportList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+	policies, err := models.GetIssueSLAPolicies(ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSLAPolicies", err)
+		return
+	}
+
+	now := timeutil.TimeStampNow()
+	reports := make([]*api.IssueSLAReport, 0, len(policies))
+	for _, policy := range policies {
+		report, err := models.GetIssueSLAReport(policy, timeutil.TimeStamp(since), timeutil.TimeStamp(before), now)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetIssueSLAReport", err)
+			return
+		}
+		doer := getPolicyDoer(ctx, policy)
+		if ctx.Written() {
+			return
+		}
+		reports = append(reports, convert.ToIssueSLAReport(report, doer))
+	}
+	ctx.JSON(http.StatusOK, reports)
+}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/notification"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -193,6 +194,7 @@ func Create(ctx *context.APIContext, form api.CreateOrgOption) {
 		}
 		return
 	}
+	notification.NotifyCreateOrganization(ctx.User, org)
 
 	ctx.JSON(http.StatusCreated, convert.ToOrganization(org))
 }
@@ -253,7 +255,10 @@ func Edit(ctx *context.APIContext, form api.EditOrgOption) {
 	if form.Visibility != "" {
 		org.Visibility = api.VisibilityModes[form.Visibility]
 	}
-	if err := models.UpdateUserCols(org, "full_name", "description", "website", "location", "visibility"); err != nil {
+	if form.RequireTwoFactor != nil {
+		org.RequireTwoFactor = *form.RequireTwoFactor
+	}
+	if err := models.UpdateUserCols(org, "full_name", "description", "website", "location", "visibility", "require_two_factor"); err != nil {
 		ctx.Error(http.StatusInternalServerError, "EditOrganization", err)
 		return
 	}
@@ -282,5 +287,6 @@ func Delete(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteOrganization", err)
 		return
 	}
+	notification.NotifyDeleteOrganization(ctx.User, ctx.Org.Organization)
 	ctx.Status(http.StatusNoContent)
 }
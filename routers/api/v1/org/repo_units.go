@@ -0,0 +1,119 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/task"
+
+	"github.com/gobwas/glob"
+)
+
+// BulkEditRepoUnits enables and disables units of many repositories of an organization
+func BulkEditRepoUnits(ctx *context.APIContext, opts api.BulkEditRepoUnitsOption) {
+	// swagger:operation POST /orgs/{org}/repos/units organization orgBulkEditRepoUnits
+	// ---
+	// summary: Enable and disable units of many repositories of an organization
+	// description: The repositories are updated asynchronously, the returned task reports the result once finished.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BulkEditRepoUnitsOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoUnitsTask"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if len(opts.Enable) == 0 && len(opts.Disable) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "no unit to enable or disable")
+		return
+	}
+	if _, _, err := repo_module.ParseUnitsToToggle(opts.Enable, opts.Disable); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseUnitsToToggle", err)
+		return
+	}
+	if pattern := strings.TrimSpace(opts.Pattern); len(pattern) > 0 {
+		if _, err := glob.Compile(pattern); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "glob.Compile", err)
+			return
+		}
+	}
+
+	t, err := task.UpdateRepositoriesUnits(ctx.User, ctx.Org.Organization, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	apiTask, err := convert.ToRepoUnitsTask(t)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, apiTask)
+}
+
+// GetRepoUnitsTask returns a task updating the units of repositories of an organization
+func GetRepoUnitsTask(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repos/units/{id} organization orgGetRepoUnitsTask
+	// ---
+	// summary: Get a task updating the units of repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the task
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoUnitsTask"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := models.GetTaskByID(ctx.ParamsInt64(":id"), ctx.Org.Organization.ID, api.TaskTypeUpdateRepoUnits)
+	if err != nil {
+		if models.IsErrTaskDoesNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	apiTask, err := convert.ToRepoUnitsTask(t)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiTask)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListReviewerStats list the review activity of the reviewers of the repositories of an organization
+func ListReviewerStats(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/insights/reviewers organization orgListReviewerStats
+	// ---
+	// summary: List the review activity of the reviewers of the pull requests of the repositories of an organization
+	// description: Only the repositories whose pull requests can be read by the user are counted. The reviewers keeping
+	//   their review statistics private are only listed to themselves and to the admins. The turnaround only counts
+	//   the review requests and the reviews of the period.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only count the activity after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only count the activity before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewerStatsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repos, _, err := models.SearchRepository(&models.SearchRepoOptions{
+		Actor:       ctx.User,
+		OwnerID:     ctx.Org.Organization.ID,
+		Private:     ctx.IsSigned,
+		Collaborate: util.OptionalBoolFalse,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepository", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if perm.CanRead(models.UnitTypePullRequests) {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	utils.ListReviewerStats(ctx, repoIDs)
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSavedReplies list the saved replies of an organization
+func ListSavedReplies(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/replies organization orgListSavedReplies
+	// ---
+	// summary: List the saved replies of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReplyList"
+
+	utils.ListSavedReplies(ctx, ctx.Org.Organization)
+}
+
+// CreateSavedReply create a saved reply of an organization
+func CreateSavedReply(ctx *context.APIContext, form api.CreateSavedReplyOption) {
+	// swagger:operation POST /orgs/{org}/replies organization orgCreateSavedReply
+	// ---
+	// summary: Create a saved reply of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateSavedReplyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SavedReply"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateSavedReply(ctx, ctx.Org.Organization, &form)
+}
+
+// GetSavedReply get a saved reply of an organization
+func GetSavedReply(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/replies/{id} organization orgGetSavedReply
+	// ---
+	// summary: Get a saved reply of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetSavedReply(ctx, ctx.Org.Organization)
+}
+
+// EditSavedReply edit a saved reply of an organization
+func EditSavedReply(ctx *context.APIContext, form api.EditSavedReplyOption) {
+	// swagger:operation PATCH /orgs/{org}/replies/{id} organization orgEditSavedReply
+	// ---
+	// summary: Edit a saved reply of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSavedReplyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditSavedReply(ctx, ctx.Org.Organization, &form)
+}
+
+// DeleteSavedReply delete a saved reply of an organization
+func DeleteSavedReply(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/replies/{id} organization orgDeleteSavedReply
+	// ---
+	// summary: Delete a saved reply of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteSavedReply(ctx, ctx.Org.Organization)
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
@@ -413,9 +414,24 @@ func AddTeamMember(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "AddMember", err)
 		return
 	}
+	auditTeamMember(ctx, models.AuditActionTeamMemberAdd, u.Name)
 	ctx.Status(http.StatusNoContent)
 }
 
+// auditTeamMember records a change of the members of the current team in the audit log
+func auditTeamMember(ctx *context.APIContext, action models.AuditAction, memberName string) {
+	org, err := models.GetUserByID(ctx.Org.Team.OrgID)
+	if err != nil {
+		log.Error("GetUserByID: %v", err)
+		return
+	}
+	verb := "Added %s to"
+	if action == models.AuditActionTeamMemberRemove {
+		verb = "Removed %s from"
+	}
+	audit.Record(action, ctx.User, ctx.RemoteAddr(), org, verb+" the team %s", memberName, ctx.Org.Team.Name)
+}
+
 // RemoveTeamMember api for remove one member from a team
 func RemoveTeamMember(ctx *context.APIContext) {
 	// swagger:operation DELETE /teams/{id}/members/{username} organization orgRemoveTeamMember
@@ -450,6 +466,7 @@ func RemoveTeamMember(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "RemoveMember", err)
 		return
 	}
+	auditTeamMember(ctx, models.AuditActionTeamMemberRemove, u.Name)
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -609,6 +626,140 @@ func RemoveTeamRepository(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetTeamWatchedRepos api for get the repos watched by the members of a team
+func GetTeamWatchedRepos(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/watches organization orgListTeamWatchedRepos
+	// ---
+	// summary: List the repos auto-watched by the members of a team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+
+	repos, err := ctx.Org.Team.GetWatchRuleRepositories(utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetWatchRuleRepositories", err)
+		return
+	}
+	apiRepos := make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		access, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos[i] = convert.ToRepo(repo, access)
+	}
+	ctx.JSON(http.StatusOK, apiRepos)
+}
+
+// AddTeamWatchRule api for making the members of a team watch a repository
+func AddTeamWatchRule(ctx *context.APIContext) {
+	// swagger:operation PUT /teams/{id}/watches/{org}/{repo} organization orgAddTeamWatchRule
+	// ---
+	// summary: Make the current and future members of a team watch a repository of the team
+	// description: The members which explicitly unwatched the repository aren't subscribed again.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: org
+	//   in: path
+	//   description: organization that owns the repo to watch
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to watch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repo := getRepositoryByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := ctx.Org.Team.AddWatchRule(repo); err != nil {
+		if models.IsErrTeamRepoNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "AddWatchRule", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddWatchRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveTeamWatchRule api for removing the rule making the members of a team watch a repository
+func RemoveTeamWatchRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /teams/{id}/watches/{org}/{repo} organization orgRemoveTeamWatchRule
+	// ---
+	// summary: Stop making the members of a team watch a repository
+	// description: The members stop watching the repository unless they watch it by themselves
+	//              or because of another team.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: org
+	//   in: path
+	//   description: organization that owns the repo to unwatch
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to unwatch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	repo := getRepositoryByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := ctx.Org.Team.RemoveWatchRule(repo.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemoveWatchRule", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
 // SearchTeam api for searching teams
 func SearchTeam(ctx *context.APIContext) {
 	// swagger:operation GET /orgs/{org}/teams/search organization teamSearch
@@ -663,22 +814,14 @@ func SearchTeam(ctx *context.APIContext) {
 
 	teams, maxResults, err := models.SearchTeam(opts)
 	if err != nil {
-		log.Error("SearchTeam failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"ok":    false,
-			"error": "SearchTeam internal failure",
-		})
+		ctx.Error(http.StatusInternalServerError, "SearchTeam", err)
 		return
 	}
 
 	apiTeams := make([]*api.Team, len(teams))
 	for i := range teams {
 		if err := teams[i].GetUnits(); err != nil {
-			log.Error("Team GetUnits failed: %v", err)
-			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
-				"ok":    false,
-				"error": "SearchTeam failed to get units",
-			})
+			ctx.Error(http.StatusInternalServerError, "GetUnits", err)
 			return
 		}
 		apiTeams[i] = convert.ToTeam(teams[i])
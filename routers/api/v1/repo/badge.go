@@ -0,0 +1,260 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/validation"
+)
+
+// ListBadges lists the badges of a repository
+func ListBadges(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges repository repoListBadges
+	// ---
+	// summary: List the badges and quick links shown on the home of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoBadgeList"
+
+	badges, err := models.GetRepoBadges(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoBadges", err)
+		return
+	}
+	apiBadges := make([]*api.RepoBadge, 0, len(badges))
+	for _, badge := range badges {
+		apiBadges = append(apiBadges, convert.ToRepoBadge(badge))
+	}
+	ctx.JSON(http.StatusOK, apiBadges)
+}
+
+// GetBadge gets a badge of a repository
+func GetBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/{id} repository repoGetBadge
+	// ---
+	// summary: Get a badge of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the badge
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoBadge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	badge := getRepoBadge(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoBadge(badge))
+}
+
+func getRepoBadge(ctx *context.APIContext) *models.RepoBadge {
+	badge, err := models.GetRepoBadge(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoBadgeNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoBadge", err)
+		}
+		return nil
+	}
+	return badge
+}
+
+// CreateBadge adds a badge to a repository
+func CreateBadge(ctx *context.APIContext, form api.CreateRepoBadgeOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/badges repository repoCreateBadge
+	// ---
+	// summary: Add a badge or a quick link to the home of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoBadgeOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoBadge"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	badge := &models.RepoBadge{
+		RepoID:   ctx.Repo.Repository.ID,
+		Name:     form.Name,
+		LinkURL:  form.LinkURL,
+		ImageURL: form.ImageURL,
+		Sort:     form.Sort,
+	}
+	if err := models.CreateRepoBadge(badge); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateRepoBadge", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToRepoBadge(badge))
+}
+
+// EditBadge edits a badge of a repository
+func EditBadge(ctx *context.APIContext, form api.EditRepoBadgeOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/badges/{id} repository repoEditBadge
+	// ---
+	// summary: Edit a badge of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the badge
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoBadgeOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoBadge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	badge := getRepoBadge(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		if len(*form.Name) == 0 || utf8.RuneCountInString(*form.Name) > 50 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid name")
+			return
+		}
+		badge.Name = *form.Name
+	}
+	if form.LinkURL != nil {
+		if !validation.IsValidURL(*form.LinkURL) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid link_url")
+			return
+		}
+		badge.LinkURL = *form.LinkURL
+	}
+	if form.ImageURL != nil {
+		if len(*form.ImageURL) > 0 && !validation.IsValidURL(*form.ImageURL) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid image_url")
+			return
+		}
+		badge.ImageURL = *form.ImageURL
+	}
+	if form.Sort != nil {
+		badge.Sort = *form.Sort
+	}
+
+	if err := models.UpdateRepoBadge(badge); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRepoBadge", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoBadge(badge))
+}
+
+// DeleteBadge deletes a badge of a repository
+func DeleteBadge(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/badges/{id} repository repoDeleteBadge
+	// ---
+	// summary: Delete a badge of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the badge
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoBadge(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrRepoBadgeNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoBadge", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+)
+
+// GetBlame gets the blame of a file in a repository
+func GetBlame(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/blame/{filepath} repository repoGetBlame
+	// ---
+	// summary: Gets the blame of a file in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file in the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// - name: bypass_blame_ignore
+	//   in: query
+	//   description: do not ignore the revisions listed in the .git-blame-ignore-revs file of the default branch
+	//   type: boolean
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileBlameResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	blame, err := repofiles.GetBlame(ctx.Req.Context(), ctx.Repo.Repository, ctx.Params("*"), ctx.QueryTrim("ref"), ctx.QueryBool("bypass_blame_ignore"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetBlame", err)
+		} else if models.IsErrFilenameInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetBlame", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlame", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, blame)
+}
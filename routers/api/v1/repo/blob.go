@@ -34,6 +34,11 @@ func GetBlob(ctx *context.APIContext) {
 	//   description: sha of the commit
 	//   type: string
 	//   required: true
+	// - name: fold_regions
+	//   in: query
+	//   description: include the regions of lines of a text blob which can be collapsed
+	//   type: boolean
+	//   required: false
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/GitBlobResponse"
@@ -45,9 +50,16 @@ func GetBlob(ctx *context.APIContext) {
 		ctx.Error(http.StatusBadRequest, "", "sha not provided")
 		return
 	}
-	if blob, err := repofiles.GetBlobBySHA(ctx.Repo.Repository, sha); err != nil {
+	blob, err := repofiles.GetBlobBySHA(ctx.Repo.Repository, sha)
+	if err != nil {
 		ctx.Error(http.StatusBadRequest, "", err)
-	} else {
-		ctx.JSON(http.StatusOK, blob)
+		return
+	}
+	if ctx.QueryBool("fold_regions") {
+		if blob.FoldRegions, err = repofiles.GetBlobFoldRegions(blob); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetBlobFoldRegions", err)
+			return
+		}
 	}
+	ctx.JSON(http.StatusOK, blob)
 }
@@ -525,6 +525,20 @@ func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtec
 		RequireSignedCommits:          form.RequireSignedCommits,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		EnableMergeQueue:              form.EnableMergeQueue,
+		RequireCodeOwnerApproval:      form.RequireCodeOwnerApproval,
+		RequireLinearHistory:          form.RequireLinearHistory,
+		DismissApprovalsOnPush:        form.DismissStaleApprovals && form.DismissApprovalsOnPush,
+		DismissApprovalsOnForcePush:   form.DismissStaleApprovals && form.DismissApprovalsOnForcePush,
+		RequireSignOff:                form.RequireSignOff,
+	}
+	if protectBranch.ProtectedPathRules, err = toProtectedPathRules(repo, form.ProtectedPathRules); err != nil {
+		if models.IsErrInvalidProtectedPathRule(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "NewProtectedPathRule", err)
+		return
 	}
 
 	err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
@@ -673,6 +687,47 @@ func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtection
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.EnableMergeQueue != nil {
+		protectBranch.EnableMergeQueue = *form.EnableMergeQueue
+	}
+
+	if form.RequireCodeOwnerApproval != nil {
+		protectBranch.RequireCodeOwnerApproval = *form.RequireCodeOwnerApproval
+	}
+
+	if form.RequireLinearHistory != nil {
+		protectBranch.RequireLinearHistory = *form.RequireLinearHistory
+	}
+
+	if form.DismissApprovalsOnPush != nil {
+		protectBranch.DismissApprovalsOnPush = *form.DismissApprovalsOnPush
+	}
+
+	if form.DismissApprovalsOnForcePush != nil {
+		protectBranch.DismissApprovalsOnForcePush = *form.DismissApprovalsOnForcePush
+	}
+
+	if form.RequireSignOff != nil {
+		protectBranch.RequireSignOff = *form.RequireSignOff
+	}
+
+	// dismissing approvals on pushes extends the dismissal of stale approvals
+	if !protectBranch.DismissStaleApprovals {
+		protectBranch.DismissApprovalsOnPush = false
+		protectBranch.DismissApprovalsOnForcePush = false
+	}
+
+	if form.ProtectedPathRules != nil {
+		if protectBranch.ProtectedPathRules, err = toProtectedPathRules(repo, form.ProtectedPathRules); err != nil {
+			if models.IsErrInvalidProtectedPathRule(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "NewProtectedPathRule", err)
+			return
+		}
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = models.GetUserIDsByNames(form.PushWhitelistUsernames, false)
@@ -839,3 +894,16 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// toProtectedPathRules resolves the teams of the protected path rules of a branch protection
+func toProtectedPathRules(repo *models.Repository, rules []*api.ProtectedPathRule) ([]*models.ProtectedPathRule, error) {
+	result := make([]*models.ProtectedPathRule, 0, len(rules))
+	for _, rule := range rules {
+		protectedPathRule, err := models.NewProtectedPathRule(repo, rule.Pattern, rule.Team)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, protectedPathRule)
+	}
+	return result, nil
+}
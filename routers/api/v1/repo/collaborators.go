@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -163,11 +164,16 @@ func AddCollaborator(ctx *context.APIContext, form api.AddCollaboratorOption) {
 		return
 	}
 
+	audit.Record(models.AuditActionCollaboratorAdd, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Added the collaborator %s", collaborator.Name)
+
 	if form.Permission != nil {
-		if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(collaborator.ID, models.ParseAccessMode(*form.Permission)); err != nil {
+		mode := models.ParseAccessMode(*form.Permission)
+		if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(collaborator.ID, mode); err != nil {
 			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationAccessMode", err)
 			return
 		}
+		audit.Record(models.AuditActionCollaboratorChangeMode, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository,
+			"Changed the access mode of the collaborator %s to %s", collaborator.Name, mode)
 	}
 
 	ctx.Status(http.StatusNoContent)
@@ -216,5 +222,6 @@ func DeleteCollaborator(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteCollaboration", err)
 		return
 	}
+	audit.Record(models.AuditActionCollaboratorRemove, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Removed the collaborator %s", collaborator.Name)
 	ctx.Status(http.StatusNoContent)
 }
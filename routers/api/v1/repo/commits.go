@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitgraph"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/validation"
@@ -120,10 +121,7 @@ func GetAllCommits(ctx *context.APIContext) {
 	//     "$ref": "#/responses/EmptyRepository"
 
 	if ctx.Repo.Repository.IsEmpty {
-		ctx.JSON(http.StatusConflict, api.APIError{
-			Message: "Git Repository is empty.",
-			URL:     setting.API.SwaggerURL,
-		})
+		ctx.Error(http.StatusConflict, "", "Git Repository is empty.")
 		return
 	}
 
@@ -215,3 +213,115 @@ func GetAllCommits(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &apiCommits)
 }
+
+// GetCommitGraph get a page of the commit graph of a repository
+func GetCommitGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/graph repository repoGetCommitGraph
+	// ---
+	// summary: Get a page of the commit graph of a repository, with the lanes of its commits
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branches to draw the graph of, all the refs if empty
+	//   type: array
+	//   items:
+	//     type: string
+	// - name: author
+	//   in: query
+	//   description: patterns the author of the commits must match
+	//   type: array
+	//   items:
+	//     type: string
+	// - name: file
+	//   in: query
+	//   description: paths the commits must touch
+	//   type: array
+	//   items:
+	//     type: string
+	// - name: hide_pr_refs
+	//   in: query
+	//   description: exclude the refs of the pull requests
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/EmptyRepository"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusConflict, "", "Git Repository is empty.")
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	if listOptions.PageSize > setting.UI.GraphMaxCommitNum {
+		listOptions.PageSize = setting.UI.GraphMaxCommitNum
+	}
+
+	hidePRRefs := ctx.QueryBool("hide_pr_refs")
+	branches := ctx.QueryStrings("branch")
+	for i, branch := range branches {
+		// pass full ref names to git log, a name could look like an option
+		switch {
+		case ctx.Repo.GitRepo.IsBranchExist(branch):
+			branches[i] = git.BranchPrefix + branch
+		case ctx.Repo.GitRepo.IsTagExist(branch):
+			branches[i] = git.TagPrefix + branch
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("branch or tag %s doesn't exist", branch))
+			return
+		}
+	}
+	files := ctx.QueryStrings("file")
+	authors := ctx.QueryStrings("author")
+
+	count, err := ctx.Repo.GetCommitGraphsCount(hidePRRefs, branches, files, authors)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitGraphsCount", err)
+		return
+	}
+
+	graph, err := gitgraph.GetCommitGraph(ctx.Repo.GitRepo, listOptions.Page, listOptions.PageSize, 0, hidePRRefs, branches, files, authors)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitGraph", err)
+		return
+	}
+
+	apiGraph, err := convert.ToCommitGraph(ctx.Repo.Repository, ctx.Repo.GitRepo, graph)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToCommitGraph", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, apiGraph)
+}
@@ -0,0 +1,280 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repository_service "code.gitea.io/gitea/services/repository"
+)
+
+// getCompareHeadRepo returns the head repository of a comparison, the head is either
+// "{branch}", "{owner}:{branch}" for the fork of the repository owned by owner or
+// "{owner}/{repo}:{branch}"
+func getCompareHeadRepo(ctx *context.APIContext, head string) (*models.Repository, string) {
+	baseRepo := ctx.Repo.Repository
+
+	infos := strings.SplitN(head, ":", 2)
+	if len(infos) == 1 {
+		return baseRepo, head
+	}
+
+	if ownerAndName := strings.SplitN(infos[0], "/", 2); len(ownerAndName) == 2 {
+		headRepo, err := models.GetRepositoryByOwnerAndName(ownerAndName[0], ownerAndName[1])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return nil, ""
+		}
+		return headRepo, infos[1]
+	}
+
+	headUser, err := models.GetUserByName(infos[0])
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return nil, ""
+	}
+	if headUser.ID == baseRepo.OwnerID {
+		return baseRepo, infos[1]
+	}
+	if headRepo, has := models.HasForkedRepo(headUser.ID, baseRepo.ID); has {
+		return headRepo, infos[1]
+	}
+	if baseRepo.IsFork {
+		if err := baseRepo.GetBaseRepo(); err != nil && !models.IsErrRepoNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetBaseRepo", err)
+			return nil, ""
+		}
+		if baseRepo.BaseRepo != nil && baseRepo.BaseRepo.OwnerID == headUser.ID {
+			return baseRepo.BaseRepo, infos[1]
+		}
+		if headRepo, has := models.HasForkedRepo(headUser.ID, baseRepo.ForkID); has {
+			return headRepo, infos[1]
+		}
+	}
+	ctx.NotFound()
+	return nil, ""
+}
+
+// resolveCompareRef returns the full name of a branch or a tag, or the full ID of a commit
+func resolveCompareRef(gitRepo *git.Repository, ref string) string {
+	if gitRepo.IsBranchExist(ref) {
+		return git.BranchPrefix + ref
+	}
+	if gitRepo.IsTagExist(ref) {
+		return git.TagPrefix + ref
+	}
+	if commit, err := gitRepo.GetCommit(ref); err == nil {
+		return commit.ID.String()
+	}
+	return ""
+}
+
+// getCompareInfo resolves the references "{base}...{head}" of a comparison and returns its info, the head
+// git repository must be closed by the caller if it isn't the one of the context
+func getCompareInfo(ctx *context.APIContext, basehead string) (headRepo *models.Repository, headGitRepo *git.Repository, headRef string, compareInfo *git.CompareInfo, totalCommits int) {
+	infos := strings.SplitN(basehead, "...", 2)
+	if len(infos) != 2 || len(infos[0]) == 0 || len(infos[1]) == 0 {
+		ctx.NotFound()
+		return
+	}
+
+	baseRepo := ctx.Repo.Repository
+	headRepo, headRef = getCompareHeadRepo(ctx, infos[1])
+	if ctx.Written() {
+		return
+	}
+
+	headGitRepo = ctx.Repo.GitRepo
+	if headRepo.ID != baseRepo.ID {
+		permHead, err := models.GetUserRepoPermission(headRepo, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if !permHead.CanRead(models.UnitTypeCode) || !repository_service.CanCompare(baseRepo, headRepo) {
+			ctx.NotFound()
+			return
+		}
+
+		headGitRepo, err = git.OpenRepository(headRepo.RepoPath())
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+			return
+		}
+	}
+
+	baseRef := resolveCompareRef(ctx.Repo.GitRepo, infos[0])
+	headRef = resolveCompareRef(headGitRepo, headRef)
+	if len(baseRef) == 0 || len(headRef) == 0 {
+		if headGitRepo != ctx.Repo.GitRepo {
+			headGitRepo.Close()
+		}
+		ctx.NotFound()
+		return
+	}
+
+	var err error
+	compareInfo, totalCommits, err = repository_service.GetCompareInfo(baseRepo, headRepo, headGitRepo, baseRef, headRef)
+	if err != nil {
+		if headGitRepo != ctx.Repo.GitRepo {
+			headGitRepo.Close()
+		}
+		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
+		return
+	}
+	return
+}
+
+// CompareDiff compare two references of the repository or of different repositories
+func CompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareDiff
+	// ---
+	// summary: Compare two references, the references may be of different repositories
+	// description: The head of the comparison may be a reference of the repository, of the fork
+	//   owned by a user with "{owner}:{ref}" or of any readable repository with "{owner}/{repo}:{ref}".
+	//   Only the first commits of the comparison are listed if there are too many of them.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: the references to compare, "{base}...{head}"
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if strings.HasSuffix(ctx.Params("*"), ".diff") {
+		DownloadCompareDiff(ctx)
+		return
+	}
+
+	baseRepo := ctx.Repo.Repository
+	headRepo, headGitRepo, _, compareInfo, totalCommits := getCompareInfo(ctx, ctx.Params("*"))
+	if ctx.Written() {
+		return
+	}
+	if headGitRepo != ctx.Repo.GitRepo {
+		defer headGitRepo.Close()
+	}
+
+	userCache := make(map[string]*models.User)
+	apiCommits := make([]*api.Commit, 0, compareInfo.Commits.Len())
+	for e := compareInfo.Commits.Front(); e != nil; e = e.Next() {
+		apiCommit, err := convert.ToCommit(headRepo, e.Value.(*git.Commit), userCache)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+			return
+		}
+		apiCommits = append(apiCommits, apiCommit)
+	}
+
+	ctx.JSON(http.StatusOK, &api.Compare{
+		BaseRepo: &api.RepositoryMeta{
+			ID:       baseRepo.ID,
+			Name:     baseRepo.Name,
+			Owner:    baseRepo.OwnerName,
+			FullName: baseRepo.FullName(),
+		},
+		HeadRepo: &api.RepositoryMeta{
+			ID:       headRepo.ID,
+			Name:     headRepo.Name,
+			Owner:    headRepo.OwnerName,
+			FullName: headRepo.FullName(),
+		},
+		MergeBase:    compareInfo.MergeBase,
+		FilesChanged: compareInfo.NumFiles,
+		TotalCommits: totalCommits,
+		Commits:      apiCommits,
+		Truncated:    len(apiCommits) < totalCommits,
+		HTMLURL:      fmt.Sprintf("%s/compare/%s", baseRepo.HTMLURL(), ctx.Params("*")),
+	})
+}
+
+// DownloadCompareDiff writes the raw diff of two references from their merge base
+func DownloadCompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead}.diff repository repoDownloadCompareDiff
+	// ---
+	// summary: Get the diff of two references from their merge base, the references may be of different repositories
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: the references to compare, "{base}...{head}"
+	//   type: string
+	//   required: true
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace changes to ignore, defaults to the diff preference of the user
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol]
+	// - name: context
+	//   in: query
+	//   description: number of lines of context around the changes, 0 is the default of git,
+	//     defaults to the diff preference of the user
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/string"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	diffArgs := getDiffOptions(ctx).GitArgs()
+	if ctx.Written() {
+		return
+	}
+	_, headGitRepo, headRef, compareInfo, _ := getCompareInfo(ctx, strings.TrimSuffix(ctx.Params("*"), ".diff"))
+	if ctx.Written() {
+		return
+	}
+	if headGitRepo != ctx.Repo.GitRepo {
+		defer headGitRepo.Close()
+	}
+
+	if err := headGitRepo.GetDiff(compareInfo.MergeBase, headRef, ctx, diffArgs...); err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiff", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/repofiles"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/repo"
+	"code.gitea.io/gitea/services/embed"
 )
 
 // GetRawFile get a file by path on a repository
@@ -90,6 +91,10 @@ func GetArchive(ctx *context.APIContext) {
 	//   description: the git reference for download with attached archive format (e.g. master.zip)
 	//   type: string
 	//   required: true
+	// - name: lfs
+	//   in: query
+	//   description: replace the LFS pointers by the LFS objects
+	//   type: boolean
 	// responses:
 	//   200:
 	//     description: success
@@ -479,6 +484,143 @@ func DeleteFile(ctx *context.APIContext, apiOpts api.DeleteFileOptions) {
 	}
 }
 
+// ChangeFiles handles API call for creating, updating and deleting several files in a single commit
+func ChangeFiles(ctx *context.APIContext, apiOpts api.ChangeFilesOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/contents:batch repository repoChangeFiles
+	// ---
+	// summary: Create, update and delete several files of a repository in a single commit
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ChangeFilesOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FilesResponse"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+	if !canWriteFiles(ctx.Repo) {
+		ctx.Error(http.StatusForbidden, "ChangeFiles", models.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.User.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+	if len(apiOpts.Files) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "ChangeFiles", fmt.Errorf("no files to change"))
+		return
+	}
+
+	if apiOpts.BranchName == "" {
+		apiOpts.BranchName = ctx.Repo.Repository.DefaultBranch
+	}
+
+	files := make([]*repofiles.ChangeRepoFile, 0, len(apiOpts.Files))
+	for _, file := range apiOpts.Files {
+		change := &repofiles.ChangeRepoFile{
+			Operation: file.Operation,
+			TreePath:  file.Path,
+			SHA:       file.SHA,
+		}
+		switch file.Operation {
+		case repofiles.FileOperationCreate, repofiles.FileOperationUpdate:
+			content, err := base64.StdEncoding.DecodeString(file.Content)
+			if err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "ChangeFiles", fmt.Errorf("content of %s isn't base64 encoded: %v", file.Path, err))
+				return
+			}
+			change.Content = string(content)
+			if file.Operation == repofiles.FileOperationUpdate {
+				change.FromTreePath = file.FromPath
+			}
+		case repofiles.FileOperationDelete:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "ChangeFiles", fmt.Errorf("unknown operation %q of %s", file.Operation, file.Path))
+			return
+		}
+		// the SHA makes sure that the file hasn't been changed since it was read
+		if file.Operation != repofiles.FileOperationCreate && file.SHA == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "ChangeFiles", fmt.Errorf("sha is required to %s %s", file.Operation, file.Path))
+			return
+		}
+		files = append(files, change)
+	}
+
+	opts := &repofiles.ChangeRepoFilesOptions{
+		Files:     files,
+		Message:   apiOpts.Message,
+		OldBranch: apiOpts.BranchName,
+		NewBranch: apiOpts.NewBranchName,
+		Committer: &repofiles.IdentityOptions{
+			Name:  apiOpts.Committer.Name,
+			Email: apiOpts.Committer.Email,
+		},
+		Author: &repofiles.IdentityOptions{
+			Name:  apiOpts.Author.Name,
+			Email: apiOpts.Author.Email,
+		},
+		Dates: &repofiles.CommitDateOptions{
+			Author:    apiOpts.Dates.Author,
+			Committer: apiOpts.Dates.Committer,
+		},
+	}
+	if opts.Dates.Author.IsZero() {
+		opts.Dates.Author = time.Now()
+	}
+	if opts.Dates.Committer.IsZero() {
+		opts.Dates.Committer = time.Now()
+	}
+
+	if opts.Message == "" {
+		opts.Message = ctx.Tr("repo.editor.change_files", len(files))
+	}
+
+	filesResponse, err := repofiles.ChangeRepoFiles(ctx.Repo.Repository, ctx.User, opts)
+	if err != nil {
+		switch {
+		case models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) || models.IsErrLFSFileLocked(err):
+			ctx.Error(http.StatusForbidden, "Access", err)
+		case models.IsErrBranchDoesNotExist(err) || git.IsErrBranchNotExist(err) || models.IsErrRepoFileDoesNotExist(err):
+			ctx.Error(http.StatusNotFound, "ChangeFiles", err)
+		case models.IsErrSHADoesNotMatch(err) || models.IsErrCommitIDDoesNotMatch(err) || git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "ChangeFiles", err)
+		case models.IsErrBranchAlreadyExists(err) || models.IsErrFilenameInvalid(err) ||
+			models.IsErrFilePathInvalid(err) || models.IsErrRepoFileAlreadyExists(err):
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "ChangeRepoFiles", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, filesResponse)
+}
+
 // GetContents Get the metadata and contents (if a file) of an entry in a repository, or a list of entries if a dir
 func GetContents(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/contents/{filepath} repository repoGetContents
@@ -507,11 +649,23 @@ func GetContents(ctx *context.APIContext) {
 	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
 	//   type: string
 	//   required: false
+	// - name: lines
+	//   in: query
+	//   description: range of lines of a file to get instead of its whole content, e.g. 10-20
+	//   type: string
+	//   required: false
+	// - name: highlighted
+	//   in: query
+	//   description: highlight the range of lines
+	//   type: boolean
+	//   required: false
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/ContentsResponse"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	if !canReadFiles(ctx.Repo) {
 		ctx.Error(http.StatusInternalServerError, "GetContentsOrList", models.ErrUserDoesNotHaveAccessToRepo{
@@ -524,6 +678,11 @@ func GetContents(ctx *context.APIContext) {
 	treePath := ctx.Params("*")
 	ref := ctx.QueryTrim("ref")
 
+	if lines := ctx.QueryTrim("lines"); len(lines) > 0 {
+		getContentsLines(ctx, treePath, ref, lines)
+		return
+	}
+
 	if fileList, err := repofiles.GetContentsOrList(ctx.Repo.Repository, treePath, ref); err != nil {
 		if git.IsErrNotExist(err) {
 			ctx.NotFound("GetContentsOrList", err)
@@ -535,6 +694,31 @@ func GetContents(ctx *context.APIContext) {
 	}
 }
 
+// getContentsLines writes the metadata of a file with a range of its lines instead of its content
+func getContentsLines(ctx *context.APIContext, treePath, ref, lines string) {
+	lineStart, lineEnd, err := embed.ParseLines(lines)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseLines", err)
+		return
+	}
+
+	contents, err := repofiles.GetContents(ctx.Repo.Repository, treePath, ref, true)
+	if err == nil {
+		contents.Lines, err = repofiles.GetContentsLines(ctx.Repo.Repository, treePath, ref, lineStart, lineEnd, ctx.QueryBool("highlighted"))
+	}
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetContentsLines", err)
+		} else if repofiles.IsErrContentsLinesInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetContentsLines", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetContentsLines", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, contents)
+}
+
 // GetContentsList Get the metadata of all the entries of the root dir
 func GetContentsList(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/contents repository repoGetContentsList
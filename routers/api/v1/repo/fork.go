@@ -60,6 +60,18 @@ func ListForks(ctx *context.APIContext) {
 			return
 		}
 		apiForks[i] = convert.ToRepo(fork, access)
+
+		divergence, err := repo_service.GetForkDivergence(ctx.Repo.Repository, fork)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetForkDivergence", err)
+			return
+		}
+		if divergence != nil {
+			apiForks[i].ForkDivergence = &api.ForkDivergence{
+				AheadBy:  divergence.Ahead,
+				BehindBy: divergence.Behind,
+			}
+		}
 	}
 	ctx.JSON(http.StatusOK, apiForks)
 }
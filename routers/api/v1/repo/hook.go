@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
@@ -184,6 +185,8 @@ func CreateHook(ctx *context.APIContext, form api.CreateHookOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	if !utils.CheckCreateHookOption(ctx, &form) {
 		return
 	}
@@ -221,6 +224,8 @@ func EditHook(ctx *context.APIContext, form api.EditHookOption) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	hookID := ctx.ParamsInt64(":id")
 	utils.EditRepoHook(ctx, &form, hookID)
 }
@@ -254,7 +259,8 @@ func DeleteHook(ctx *context.APIContext) {
 	//     "$ref": "#/responses/empty"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
-	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+	hookID := ctx.ParamsInt64(":id")
+	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, hookID); err != nil {
 		if models.IsErrWebhookNotExist(err) {
 			ctx.NotFound()
 		} else {
@@ -262,5 +268,6 @@ func DeleteHook(ctx *context.APIContext) {
 		}
 		return
 	}
+	audit.RecordWebhook(models.AuditActionWebhookDelete, ctx.User, ctx.RemoteAddr(), &models.Webhook{ID: hookID, RepoID: ctx.Repo.Repository.ID})
 	ctx.Status(http.StatusNoContent)
 }
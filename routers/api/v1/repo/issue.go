@@ -275,6 +275,11 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: comma separated list of milestone names or ids. It uses names and fall back to ids. Fetch only issues that have any of this milestones. Non existent milestones are discarded
 	//   type: string
+	// - name: parent
+	//   in: query
+	//   description: index of an issue, fetch only its sub-issues
+	//   type: integer
+	//   format: int64
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -353,6 +358,20 @@ func ListIssues(ctx *context.APIContext) {
 		}
 	}
 
+	var parentID int64
+	parentExists := true
+	if parentIndex := ctx.QueryInt64("parent"); parentIndex > 0 {
+		parent, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, parentIndex)
+		if err == nil {
+			parentID = parent.ID
+		} else if models.IsErrIssueNotExist(err) {
+			parentExists = false
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+			return
+		}
+	}
+
 	listOptions := utils.GetListOptions(ctx)
 
 	var isPull util.OptionalBool
@@ -367,7 +386,7 @@ func ListIssues(ctx *context.APIContext) {
 
 	// Only fetch the issues if we either don't have a keyword or the search returned issues
 	// This would otherwise return all issues if no issues were found by the search.
-	if len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0 {
+	if parentExists && (len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0) {
 		issuesOpt := &models.IssuesOptions{
 			ListOptions:  listOptions,
 			RepoIDs:      []int64{ctx.Repo.Repository.ID},
@@ -375,6 +394,7 @@ func ListIssues(ctx *context.APIContext) {
 			IssueIDs:     issueIDs,
 			LabelIDs:     labelIDs,
 			MilestoneIDs: mileIDs,
+			ParentID:     parentID,
 			IsPull:       isPull,
 		}
 
@@ -594,6 +614,8 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	//     "$ref": "#/responses/notFound"
 	//   "412":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -614,7 +636,7 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	}
 
 	if !issue.IsPoster(ctx.User.ID) && !canWrite {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to edit the issue")
 		return
 	}
 
@@ -643,6 +665,17 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 		issue.DeadlineUnix = deadlineUnix
 	}
 
+	if form.TimeEstimate != nil && canWrite {
+		if *form.TimeEstimate < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "time estimate must not be negative")
+			return
+		}
+		if err := models.UpdateIssueTimeEstimate(issue, *form.TimeEstimate); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueTimeEstimate", err)
+			return
+		}
+	}
+
 	// Add/delete assignees
 
 	// Deleting is done the GitHub way (quote from their api documentation):
@@ -692,6 +725,13 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 
 	if statusChangeComment != nil {
 		notification.NotifyIssueChangeStatus(ctx.User, issue, statusChangeComment, issue.IsClosed)
+		if issue.IsClosed && issue.IsPull {
+			if err = issue.LoadPullRequest(); err != nil {
+				ctx.Error(http.StatusInternalServerError, "LoadPullRequest", err)
+				return
+			}
+			issue_service.ExpireReviewEnvironments(ctx.User, issue.PullRequest)
+		}
 	}
 
 	// Refetch from database to assign some automatic values
@@ -0,0 +1,109 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
+)
+
+// DumpIssues dump the issue tracker of a repository to a GitHub migration archive
+func DumpIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/archive issue issueDumpArchive
+	// ---
+	// summary: Dump the labels, the milestones and the issues of a repository to a GitHub migration archive
+	// description: The archive is a gzipped tarball of JSON files, the pull requests aren't dumped.
+	// produces:
+	// - application/gzip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: success
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	ctx.Resp.Header().Set("Content-Type", "application/gzip")
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-issues.tar.gz"`, ctx.Repo.Repository.Name))
+	if err := migrations.DumpGithubArchive(ctx.Repo.Repository, ctx.Resp); err != nil {
+		// the archive may have been partially written already
+		log.Error("DumpGithubArchive[%d]: %v", ctx.Repo.Repository.ID, err)
+		ctx.Error(http.StatusInternalServerError, "DumpGithubArchive", err)
+	}
+}
+
+// RestoreIssues restore the issue tracker of a repository from a GitHub migration archive
+func RestoreIssues(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/archive issue issueRestoreArchive
+	// ---
+	// summary: Restore the labels, the milestones and the issues of a GitHub migration archive into a repository
+	// description: The repository must have neither issues nor pull requests, the issues keep their
+	//   numbers. The contributions of the users of this instance are restored as theirs, the other
+	//   ones are restored on behalf of the authenticated user with the names of their original authors.
+	// consumes:
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: archive
+	//   in: formData
+	//   description: GitHub migration archive to restore
+	//   type: file
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	file, _, err := ctx.GetFile("archive")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetFile", err)
+		return
+	}
+	defer file.Close()
+
+	if err := migrations.RestoreGithubArchive(ctx.Req.Context(), ctx.User, ctx.Repo.Repository, file); err != nil {
+		if err == migrations.ErrTrackerNotEmpty {
+			ctx.Error(http.StatusConflict, "RestoreGithubArchive", err)
+		} else if migrations.IsErrInvalidGithubArchive(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "RestoreGithubArchive", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RestoreGithubArchive", err)
+		}
+		return
+	}
+
+	issue_indexer.UpdateRepoIndexer(ctx.Repo.Repository)
+	ctx.Status(http.StatusNoContent)
+}
@@ -7,6 +7,7 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
@@ -91,6 +92,190 @@ func ListIssueComments(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, &apiComments)
 }
 
+// ListIssueTimeline list all the events of the timeline of an issue
+func ListIssueTimeline(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/timeline issue issueGetTimeline
+	// ---
+	// summary: List all comments and events on an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: types
+	//   in: query
+	//   description: "only return events of the given types, e.g. comment, label, milestone, assignees, review, code, pull_push or commit_ref. Defaults to all types."
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//   required: false
+	// - name: since_id
+	//   in: query
+	//   description: if provided, only events created after the event with the given id are returned. Pass the id of the last event received to page through new events.
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: if provided, only events updated since the specified time are returned.
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: if provided, only events updated before the provided time are returned.
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TimelineList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	types := make([]models.CommentType, 0, 5)
+	for _, name := range ctx.QueryStrings("types") {
+		tp := models.AsCommentType(name)
+		if tp == models.CommentTypeUnknown {
+			ctx.Error(http.StatusUnprocessableEntity, "AsCommentType", fmt.Errorf("unknown timeline event type: %s", name))
+			return
+		}
+		types = append(types, tp)
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	issue.Repo = ctx.Repo.Repository
+
+	comments, err := models.FindComments(models.FindCommentsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		IssueID:     issue.ID,
+		Since:       since,
+		Before:      before,
+		SinceID:     ctx.QueryInt64("since_id"),
+		Type:        models.CommentTypeUnknown,
+		Types:       types,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindComments", err)
+		return
+	}
+
+	apiComments := make([]*api.TimelineComment, 0, len(comments))
+	for _, comment := range comments {
+		comment.Issue = issue
+		visible, err := isTimelineCommentVisible(ctx, comment)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "isTimelineCommentVisible", err)
+			return
+		}
+		if !visible {
+			continue
+		}
+		if apiComment := convert.ToTimelineComment(comment); apiComment != nil {
+			apiComments = append(apiComments, apiComment)
+		}
+	}
+	ctx.JSON(http.StatusOK, &apiComments)
+}
+
+// isTimelineCommentVisible loads the issues referenced by the comment and checks
+// the doer is allowed to see them
+func isTimelineCommentVisible(ctx *context.APIContext, c *models.Comment) (bool, error) {
+	canRead := func(repo *models.Repository, isPull bool) (bool, error) {
+		if repo.ID == ctx.Repo.Repository.ID {
+			return true, nil
+		}
+		perm, err := models.GetUserRepoPermission(repo, ctx.User)
+		if err != nil {
+			return false, err
+		}
+		return perm.CanReadIssuesOrPulls(isPull), nil
+	}
+
+	switch {
+	case models.CommentTypeIsRef(c.Type):
+		if err := c.LoadRefIssue(); err != nil {
+			if models.IsErrIssueNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if c.RefCommentID > 0 {
+			if err := c.LoadRefComment(); err != nil && !models.IsErrCommentNotExist(err) {
+				return false, err
+			}
+		}
+		return canRead(c.RefIssue.Repo, c.RefIsPull)
+	case c.Type == models.CommentTypeAddDependency || c.Type == models.CommentTypeRemoveDependency:
+		if err := c.LoadDepIssueDetails(); err != nil {
+			if models.IsErrIssueNotExist(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if c.DependentIssue == nil {
+			return true, nil
+		}
+		if err := c.DependentIssue.LoadRepo(); err != nil {
+			return false, err
+		}
+		visible, err := canRead(c.DependentIssue.Repo, c.DependentIssue.IsPull)
+		if err != nil {
+			return false, err
+		}
+		if !visible {
+			c.DependentIssue = nil
+		}
+		return true, nil
+	case c.Type == models.CommentTypeCode:
+		// Code comments of pending reviews are only visible to their reviewer
+		if err := c.LoadReview(); err != nil {
+			if models.IsErrReviewNotExist(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return c.Review.Type != models.ReviewTypePending || (ctx.User != nil && c.Review.ReviewerID == ctx.User.ID), nil
+	}
+	return true, nil
+}
+
 // ListRepoIssueComments returns all issue-comments for a repo
 func ListRepoIssueComments(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues/comments issue issueGetRepoComments
@@ -280,7 +465,7 @@ func GetIssueComment(ctx *context.APIContext) {
 		return
 	}
 	if comment.Issue.RepoID != ctx.Repo.Repository.ID {
-		ctx.Status(http.StatusNotFound)
+		ctx.NotFound()
 		return
 	}
 
@@ -401,7 +586,7 @@ func editIssueComment(ctx *context.APIContext, form api.EditIssueCommentOption)
 	}
 
 	if !ctx.IsSigned || (ctx.User.ID != comment.PosterID && !ctx.Repo.IsAdmin()) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to change the comment")
 		return
 	} else if comment.Type != models.CommentTypeComment {
 		ctx.Status(http.StatusNoContent)
@@ -502,7 +687,7 @@ func deleteIssueComment(ctx *context.APIContext) {
 	}
 
 	if !ctx.IsSigned || (ctx.User.ID != comment.PosterID && !ctx.Repo.IsAdmin()) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to change the comment")
 		return
 	} else if comment.Type != models.CommentTypeComment {
 		ctx.Status(http.StatusNoContent)
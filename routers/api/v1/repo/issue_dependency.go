@@ -0,0 +1,115 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+)
+
+// GetIssueDependencyGraph get the dependency graph of an issue
+func GetIssueDependencyGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/dependencies/graph issue issueGetDependencyGraph
+	// ---
+	// summary: Get the issues which transitively block or are blocked by an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueDependencyGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	graph, err := models.GetIssueDependencyGraph([]int64{issue.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueDependencyGraph", err)
+		return
+	}
+	if err := graph.FilterReadable(ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "FilterReadable", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueDependencyGraph(graph))
+}
+
+// GetMilestoneDependencyGraph get the dependency graph of the issues of a milestone
+func GetMilestoneDependencyGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/milestones/{id}/dependencies/graph issue issueGetMilestoneDependencyGraph
+	// ---
+	// summary: Get the issues which transitively block or are blocked by the issues of a milestone
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueDependencyGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	graph, err := models.GetMilestoneDependencyGraph(milestone.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneDependencyGraph", err)
+		return
+	}
+	if err := graph.FilterReadable(ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "FilterReadable", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueDependencyGraph(graph))
+}
@@ -167,7 +167,7 @@ func DeleteIssueLabel(ctx *context.APIContext) {
 	}
 
 	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "User not allowed to change the labels")
 		return
 	}
 
@@ -285,7 +285,7 @@ func ClearIssueLabels(ctx *context.APIContext) {
 	}
 
 	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "User not allowed to change the labels")
 		return
 	}
 
@@ -315,7 +315,7 @@ func prepareForReplaceOrAdd(ctx *context.APIContext, form api.IssueLabelsOption)
 	}
 
 	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "User not allowed to change the labels")
 		return
 	}
 
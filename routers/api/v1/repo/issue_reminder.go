@@ -0,0 +1,220 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// getReminderIssue returns the issue of the index path parameter if the user can read it
+func getReminderIssue(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
+
+// ListIssueReminders list the pending reminders of an issue scheduled by the user
+func ListIssueReminders(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/reminders issue issueListReminders
+	// ---
+	// summary: List the pending reminders of an issue scheduled by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueReminderList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getReminderIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reminders, err := models.GetIssueReminders(ctx.User.ID, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueReminders", err)
+		return
+	}
+	apiReminders := make([]*api.IssueReminder, len(reminders))
+	for i := range reminders {
+		apiReminders[i] = convert.ToIssueReminder(reminders[i])
+	}
+	ctx.JSON(http.StatusOK, &apiReminders)
+}
+
+// CreateIssueReminder schedule a notification of an issue for the user
+func CreateIssueReminder(ctx *context.APIContext, form api.CreateIssueReminderOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/reminders issue issueCreateReminder
+	// ---
+	// summary: Schedule a notification of an issue for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueReminderOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueReminder"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getReminderIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	now := time.Now()
+	var remindAt time.Time
+	switch {
+	case form.RemindAt != nil && len(form.In) > 0:
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("remind_at and in are exclusive"))
+		return
+	case form.RemindAt != nil:
+		if !form.RemindAt.After(now) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("remind_at is in the past"))
+			return
+		}
+		remindAt = *form.RemindAt
+	case len(form.In) > 0:
+		d, err := issue_service.ParseRemindDuration(form.In)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ParseRemindDuration", err)
+			return
+		}
+		remindAt = now.Add(d)
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("remind_at or in is required"))
+		return
+	}
+
+	reminder, err := models.CreateIssueReminder(ctx.User, issue, timeutil.TimeStamp(remindAt.Unix()))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateIssueReminder", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToIssueReminder(reminder))
+}
+
+// DeleteIssueReminder cancel a reminder of an issue scheduled by the user
+func DeleteIssueReminder(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/reminders/{id} issue issueDeleteReminder
+	// ---
+	// summary: Cancel a reminder of an issue scheduled by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the reminder
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getReminderIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reminder, err := models.GetIssueReminderByID(ctx.User.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueReminderNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueReminderByID", err)
+		}
+		return
+	}
+	if reminder.IssueID != issue.ID {
+		ctx.NotFound()
+		return
+	}
+
+	if err := models.DeleteIssueReminder(ctx.User.ID, reminder.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueReminder", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
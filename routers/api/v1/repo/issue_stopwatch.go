@@ -174,12 +174,12 @@ func prepareIssueStopwatch(ctx *context.APIContext, shouldExist bool) (*models.I
 	}
 
 	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "Unable to write to PRs")
 		return nil, errors.New("Unable to write to PRs")
 	}
 
 	if !ctx.Repo.CanUseTimetracker(issue, ctx.User) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "Cannot use time tracker")
 		return nil, errors.New("Cannot use time tracker")
 	}
 
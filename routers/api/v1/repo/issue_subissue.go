@@ -0,0 +1,266 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getSubIssueParent returns the issue of the index path parameter if its sub-issues are readable
+func getSubIssueParent(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	if issue.IsPull || !ctx.Repo.CanReadIssuesOrPulls(false) {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
+
+// ListSubIssues list the sub-issues of an issue
+func ListSubIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/children issue issueListSubIssues
+	// ---
+	// summary: List the sub-issues of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	parent := getSubIssueParent(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	children, err := models.GetSubIssues(parent.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssues", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(children))
+}
+
+// GetSubIssuesProgress get the roll-up progress of the sub-issues of an issue
+func GetSubIssuesProgress(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/children/progress issue issueGetSubIssuesProgress
+	// ---
+	// summary: Get the roll-up progress of the sub-issues of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SubIssuesProgress"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	parent := getSubIssueParent(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	progress, err := parent.GetSubIssuesProgress()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssuesProgress", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSubIssuesProgress(progress))
+}
+
+// AttachSubIssue attach a sub-issue to an issue
+func AttachSubIssue(ctx *context.APIContext, form api.AttachSubIssueOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/children issue issueAttachSubIssue
+	// ---
+	// summary: Attach a sub-issue to an issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the parent issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/AttachSubIssueOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	parent := getSubIssueParent(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(false) {
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "user should have permission to write issues")
+		return
+	}
+
+	child, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, form.Index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetIssueByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if child.IsPull {
+		ctx.Error(http.StatusUnprocessableEntity, "IsPull", errors.New("a pull request can't be a sub-issue"))
+		return
+	}
+
+	if err := models.AttachSubIssue(parent, child); err != nil {
+		if models.IsErrSubIssueHasParent(err) {
+			ctx.Error(http.StatusConflict, "AttachSubIssue", err)
+		} else if models.IsErrCircularSubIssue(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "AttachSubIssue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AttachSubIssue", err)
+		}
+		return
+	}
+	child.ParentID = parent.ID
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(child))
+}
+
+// DetachSubIssue detach a sub-issue from an issue
+func DetachSubIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/children/{child} issue issueDetachSubIssue
+	// ---
+	// summary: Detach a sub-issue from an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the parent issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: child
+	//   in: path
+	//   description: index of the sub-issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	parent := getSubIssueParent(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(false) {
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "user should have permission to write issues")
+		return
+	}
+
+	child, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":child"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if err := models.DetachSubIssue(parent, child); err != nil {
+		if models.IsErrSubIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DetachSubIssue", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -159,7 +159,7 @@ func AddTime(ctx *context.APIContext, form api.AddTimeOption) {
 			ctx.Error(http.StatusBadRequest, "", "time tracking disabled")
 			return
 		}
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to use the time tracker")
 		return
 	}
 
@@ -237,10 +237,10 @@ func ResetIssueTime(ctx *context.APIContext) {
 
 	if !ctx.Repo.CanUseTimetracker(issue, ctx.User) {
 		if !ctx.Repo.Repository.IsTimetrackerEnabled() {
-			ctx.JSON(http.StatusBadRequest, struct{ Message string }{Message: "time tracking disabled"})
+			ctx.Error(http.StatusBadRequest, "", "time tracking disabled")
 			return
 		}
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to use the time tracker")
 		return
 	}
 
@@ -308,10 +308,10 @@ func DeleteTime(ctx *context.APIContext) {
 
 	if !ctx.Repo.CanUseTimetracker(issue, ctx.User) {
 		if !ctx.Repo.Repository.IsTimetrackerEnabled() {
-			ctx.JSON(http.StatusBadRequest, struct{ Message string }{Message: "time tracking disabled"})
+			ctx.Error(http.StatusBadRequest, "", "time tracking disabled")
 			return
 		}
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to use the time tracker")
 		return
 	}
 
@@ -331,7 +331,7 @@ func DeleteTime(ctx *context.APIContext) {
 
 	if !ctx.User.IsAdmin && time.UserID != ctx.User.ID {
 		//Only Admin and User itself can delete their time
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to delete the tracked time")
 		return
 	}
 
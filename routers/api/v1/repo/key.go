@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/setting"
@@ -225,6 +226,7 @@ func CreateDeployKey(ctx *context.APIContext, form api.CreateKeyOption) {
 		HandleAddKeyError(ctx, err)
 		return
 	}
+	audit.Record(models.AuditActionDeployKeyAdd, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Added the deploy key %s (%s)", key.Name, key.Mode)
 
 	key.Content = content
 	apiLink := composeDeployKeysAPILink(ctx.Repo.Owner.Name + "/" + ctx.Repo.Repository.Name)
@@ -259,7 +261,8 @@ func DeleteDeploykey(ctx *context.APIContext) {
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
 
-	if err := models.DeleteDeployKey(ctx.User, ctx.ParamsInt64(":id")); err != nil {
+	id := ctx.ParamsInt64(":id")
+	if err := models.DeleteDeployKey(ctx.User, id); err != nil {
 		if models.IsErrKeyAccessDenied(err) {
 			ctx.Error(http.StatusForbidden, "", "You do not have access to this key")
 		} else {
@@ -267,6 +270,7 @@ func DeleteDeploykey(ctx *context.APIContext) {
 		}
 		return
 	}
+	audit.Record(models.AuditActionDeployKeyRemove, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Removed the deploy key %d", id)
 
 	ctx.Status(http.StatusNoContent)
 }
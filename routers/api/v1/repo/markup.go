@@ -0,0 +1,89 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// refSubURL returns the part of the URL of a file at a ref which names the ref, e.g. branch/master
+func refSubURL(ctx *context.APIContext, ref string) (string, bool) {
+	switch {
+	case ctx.Repo.GitRepo.IsBranchExist(ref):
+		return "branch/" + util.PathEscapeSegments(ref), true
+	case ctx.Repo.GitRepo.IsTagExist(ref):
+		return "tag/" + util.PathEscapeSegments(ref), true
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		return "", false
+	}
+	return "commit/" + commit.ID.String(), true
+}
+
+// RenderMarkup renders a markup document as if it was a file of the repository
+func RenderMarkup(ctx *context.APIContext, form api.MarkupOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/markup repository repoRenderMarkup
+	// ---
+	// summary: Render a markup document as HTML as if it was a file of the repository
+	// description: The markup format is chosen by the extension of the file path, the relative links are resolved from the directory of the file and the references to issues are linked.
+	// consumes:
+	// - application/json
+	// produces:
+	// - text/html
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MarkupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MarkdownRender"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	treePath := strings.TrimPrefix(path.Clean("/"+form.FilePath), "/")
+	if markup.Type(treePath) == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "file_path", fmt.Errorf("%s is not a markup file", form.FilePath))
+		return
+	}
+
+	ref := form.Ref
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	refURL, ok := refSubURL(ctx, ref)
+	if !ok {
+		ctx.NotFound()
+		return
+	}
+
+	treeLink := ctx.Repo.Repository.Link() + "/src/" + refURL + "/" + util.PathEscapeSegments(treePath)
+	_, err := ctx.Write(markup.Render(treePath, []byte(form.Text), path.Dir(treeLink), ctx.Repo.Repository.ComposeDocumentMetas()))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+}
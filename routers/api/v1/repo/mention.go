@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListMentionCandidates list the users and teams which can be mentioned in a repository
+func ListMentionCandidates(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/mentions repository repoListMentionCandidates
+	// ---
+	// summary: List the users and teams which can be mentioned in the issues and pull requests of a repository
+	// description: Only users who can read the repository are returned, ranked by their recent
+	//              interaction with it. Teams are returned first and only for organization repositories.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword to filter by
+	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: maximum number of users and teams to return each
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MentionCandidateList"
+
+	opts := models.MentionOptions{
+		Keyword: ctx.Query("q"),
+		Limit:   convert.ToCorrectPageSize(ctx.QueryInt("limit")),
+	}
+	if ctx.User != nil {
+		opts.ExcludeUserID = ctx.User.ID
+	}
+
+	teams, err := ctx.Repo.Repository.GetMentionableTeams(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMentionableTeams", err)
+		return
+	}
+	users, err := ctx.Repo.Repository.GetMentionableUsers(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMentionableUsers", err)
+		return
+	}
+
+	candidates := make([]*api.MentionCandidate, 0, len(teams)+len(users))
+	for _, team := range teams {
+		candidates = append(candidates, convert.ToMentionTeam(ctx.Repo.Owner, team))
+	}
+	for _, user := range users {
+		candidates = append(candidates, convert.ToMentionUser(user))
+	}
+	ctx.JSON(http.StatusOK, candidates)
+}
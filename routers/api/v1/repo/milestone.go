@@ -258,6 +258,64 @@ func DeleteMilestone(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetMilestoneStats get the burndown of a milestone
+func GetMilestoneStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/milestones/{id}/stats issue issueGetMilestoneStats
+	// ---
+	// summary: Get the number of open and closed issues and the estimated and tracked time of a milestone over time
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// - name: interval
+	//   in: query
+	//   description: length of the intervals, defaults to day
+	//   type: string
+	//   enum: [day, week]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	interval := models.MilestoneStatsIntervalDay
+	if len(ctx.Query("interval")) > 0 {
+		interval = models.MilestoneStatsInterval(ctx.Query("interval"))
+		if !interval.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid interval")
+			return
+		}
+	}
+
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	stats, err := models.GetMilestoneStats(milestone, interval)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneStats", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToMilestoneStats(stats))
+}
+
 // getMilestoneByIDOrName get milestone by ID and if not available by name
 func getMilestoneByIDOrName(ctx *context.APIContext) *models.Milestone {
 	mile := ctx.Params(":id")
@@ -0,0 +1,222 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getRepoProject returns the project of the repository of the ":id" parameter, or writes the error
+func getRepoProject(ctx *context.APIContext) *models.Project {
+	project, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectByID", err)
+		}
+		return nil
+	}
+	if project.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return project
+}
+
+// ListProjectAutomationRules list the automation rules of a project
+func ListProjectAutomationRules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/automation_rules repository repoListProjectAutomationRules
+	// ---
+	// summary: List the automation rules of a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectAutomationRuleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getRepoProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	rules, err := models.GetProjectAutomationRules(project.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectAutomationRules", err)
+		return
+	}
+	apiRules := make([]*api.ProjectAutomationRule, len(rules))
+	for i := range rules {
+		apiRules[i] = convert.ToProjectAutomationRule(rules[i])
+	}
+	ctx.JSON(http.StatusOK, &apiRules)
+}
+
+// CreateProjectAutomationRule create an automation rule of a project
+func CreateProjectAutomationRule(ctx *context.APIContext, form api.CreateProjectAutomationRuleOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/projects/{id}/automation_rules repository repoCreateProjectAutomationRule
+	// ---
+	// summary: Create an automation rule of a project
+	// description: The cards of the issues and the pull requests which trigger the event of the rule are moved
+	//   to its board, the first rule of an event is applied if several rules are triggered.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectAutomationRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectAutomationRule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	project := getRepoProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	event := models.ProjectAutomationEvent(form.Event)
+	if !event.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid event: %s", form.Event))
+		return
+	}
+
+	if event == models.ProjectAutomationEventLabeled {
+		label, err := models.GetLabelByID(form.LabelID)
+		if err != nil && !models.IsErrLabelNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetLabelByID", err)
+			return
+		}
+		repo := ctx.Repo.Repository
+		if label == nil || (label.RepoID != repo.ID && (label.OrgID == 0 || label.OrgID != repo.OwnerID)) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("label does not exist [id: %d]", form.LabelID))
+			return
+		}
+	}
+
+	if form.BoardID != 0 {
+		board, err := models.GetProjectBoard(form.BoardID)
+		if err != nil && !models.IsErrProjectBoardNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetProjectBoard", err)
+			return
+		}
+		if board == nil || board.ProjectID != project.ID {
+			ctx.Error(http.StatusUnprocessableEntity, "", models.ErrProjectBoardNotExist{BoardID: form.BoardID})
+			return
+		}
+	}
+
+	rule := &models.ProjectAutomationRule{
+		ProjectID: project.ID,
+		Event:     event,
+		LabelID:   form.LabelID,
+		BoardID:   form.BoardID,
+		CreatorID: ctx.User.ID,
+	}
+	if err := models.NewProjectAutomationRule(rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewProjectAutomationRule", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToProjectAutomationRule(rule))
+}
+
+// DeleteProjectAutomationRule delete an automation rule of a project
+func DeleteProjectAutomationRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/projects/{id}/automation_rules/{rule_id} repository repoDeleteProjectAutomationRule
+	// ---
+	// summary: Delete an automation rule of a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: rule_id
+	//   in: path
+	//   description: id of the rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getRepoProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectAutomationRule(project.ID, ctx.ParamsInt64(":ruleid")); err != nil {
+		if models.IsErrProjectAutomationRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteProjectAutomationRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -7,6 +7,7 @@ package repo
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/gitdiff"
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
@@ -194,11 +196,27 @@ func DownloadPullDiff(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: path
+	//   in: query
+	//   description: path of a changed file to get the diff of, like the diff_url of the file tree
+	//   type: string
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace changes to ignore, defaults to the diff preference of the user
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol]
+	// - name: context
+	//   in: query
+	//   description: number of lines of context around the changes, 0 is the default of git,
+	//     defaults to the diff preference of the user
+	//   type: integer
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/string"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	DownloadPullDiffOrPatch(ctx, false)
 }
 
@@ -234,8 +252,43 @@ func DownloadPullPatch(ctx *context.APIContext) {
 	DownloadPullDiffOrPatch(ctx, true)
 }
 
+// getDiffOptions returns the diff options of the whitespace and context query parameters,
+// they default to the diff preferences of the signed in user
+func getDiffOptions(ctx *context.APIContext) gitdiff.DiffOptions {
+	var opts gitdiff.DiffOptions
+	if ctx.IsSigned {
+		opts.WhitespaceBehavior = ctx.User.DiffWhitespaceBehavior
+		opts.ContextLines = ctx.User.DiffContextLines
+	}
+
+	query := ctx.Req.URL.Query()
+	if _, ok := query["whitespace"]; ok {
+		opts.WhitespaceBehavior = ctx.Query("whitespace")
+		if !gitdiff.IsValidWhitespaceBehavior(opts.WhitespaceBehavior) {
+			ctx.Error(http.StatusUnprocessableEntity, "InvalidWhitespaceBehavior", fmt.Errorf("unknown whitespace behavior: %s", opts.WhitespaceBehavior))
+			return opts
+		}
+	}
+	if _, ok := query["context"]; ok {
+		contextLines, err := strconv.Atoi(ctx.Query("context"))
+		if err != nil || contextLines < 0 || contextLines > gitdiff.MaxContextLines {
+			ctx.Error(http.StatusUnprocessableEntity, "InvalidContextLines", fmt.Errorf("context must be between 0 and %d", gitdiff.MaxContextLines))
+			return opts
+		}
+		opts.ContextLines = contextLines
+	}
+	return opts
+}
+
 // DownloadPullDiffOrPatch render a pull's raw diff or patch
 func DownloadPullDiffOrPatch(ctx *context.APIContext, patch bool) {
+	var diffArgs []string
+	if !patch {
+		if diffArgs = getDiffOptions(ctx).GitArgs(); ctx.Written() {
+			return
+		}
+	}
+
 	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrPullRequestNotExist(err) {
@@ -246,7 +299,18 @@ func DownloadPullDiffOrPatch(ctx *context.APIContext, patch bool) {
 		return
 	}
 
-	if err := pull_service.DownloadDiffOrPatch(pr, ctx, patch); err != nil {
+	if treePath := ctx.Query("path"); !patch && len(treePath) > 0 {
+		if err := pull_service.DownloadFileDiff(pr, treePath, ctx, diffArgs...); err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.InternalServerError(err)
+			}
+		}
+		return
+	}
+
+	if err := pull_service.DownloadDiffOrPatch(pr, ctx, patch, diffArgs...); err != nil {
 		ctx.InternalServerError(err)
 		return
 	}
@@ -394,6 +458,32 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 		Type:       models.PullRequestGitea,
 	}
 
+	if form.BasePull > 0 {
+		basePull, err := models.GetPullRequestByIndex(repo.ID, form.BasePull)
+		if err != nil {
+			if models.IsErrPullRequestNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "BasePullNotExist", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+			}
+			return
+		}
+		if basePull.HeadBranch != baseBranch {
+			ctx.Error(http.StatusUnprocessableEntity, "BasePullHeadBranch",
+				fmt.Errorf("base must be the head branch '%s' of the base pull request", basePull.HeadBranch))
+			return
+		}
+		if err := models.CheckBasePull(pr, basePull); err != nil {
+			if models.IsErrInvalidBasePull(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "CheckBasePull", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "CheckBasePull", err)
+			}
+			return
+		}
+		pr.BasePullID = basePull.ID
+	}
+
 	// Get all assignee IDs
 	assigneeIDs, err := models.MakeIDsFromAPIAssigneesToAdd(form.Assignee, form.Assignees)
 	if err != nil {
@@ -497,7 +587,7 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 	issue.Repo = ctx.Repo.Repository
 
 	if !issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWrite(models.UnitTypePullRequests) {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to edit the pull request")
 		return
 	}
 
@@ -597,6 +687,9 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 
 	if statusChangeComment != nil {
 		notification.NotifyIssueChangeStatus(ctx.User, issue, statusChangeComment, issue.IsClosed)
+		if issue.IsClosed {
+			issue_service.ExpireReviewEnvironments(ctx.User, pr)
+		}
 	}
 
 	// change pull target branch
@@ -623,6 +716,40 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 		notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, form.Base)
 	}
 
+	// stack the pull request on another pull request, or unstack it
+	if form.BasePull != nil {
+		if *form.BasePull == 0 {
+			if err := pull_service.UnstackPullRequest(pr); err != nil {
+				ctx.Error(http.StatusInternalServerError, "UnstackPullRequest", err)
+				return
+			}
+		} else {
+			basePull, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, *form.BasePull)
+			if err != nil {
+				if models.IsErrPullRequestNotExist(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "BasePullNotExist", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+				}
+				return
+			}
+			oldBranch := pr.BaseBranch
+			if err := pull_service.StackPullRequest(pr, basePull, ctx.User); err != nil {
+				if models.IsErrInvalidBasePull(err) || models.IsErrIssueIsClosed(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "StackPullRequest", err)
+				} else if models.IsErrPullRequestAlreadyExists(err) || models.IsErrPullRequestHasMerged(err) {
+					ctx.Error(http.StatusConflict, "StackPullRequest", err)
+				} else {
+					ctx.InternalServerError(err)
+				}
+				return
+			}
+			if pr.BaseBranch != oldBranch {
+				notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, pr.BaseBranch)
+			}
+		}
+	}
+
 	// Refetch from database
 	pr, err = models.GetPullRequestByIndex(ctx.Repo.Repository.ID, pr.Index)
 	if err != nil {
@@ -780,6 +907,15 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 		return
 	}
 
+	if err := pr.LoadProtectedBranch(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadProtectedBranch", err)
+		return
+	}
+	if pr.ProtectedBranch != nil && pr.ProtectedBranch.EnableMergeQueue {
+		ctx.Error(http.StatusMethodNotAllowed, "Merge", fmt.Sprintf("Pull requests into %s must be added to its merge queue", pr.BaseBranch))
+		return
+	}
+
 	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
 		if !models.IsErrNotAllowedToMerge(err) {
 			ctx.Error(http.StatusInternalServerError, "CheckPRReadyToMerge", err)
@@ -813,11 +949,14 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
+		title, body, err := pull_service.GetDefaultMergeMessage(pr, models.MergeStyle(form.Do))
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+			return
 		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
+		message = title
+		if len(strings.TrimSpace(form.MergeMessageField)) == 0 {
+			form.MergeMessageField = body
 		}
 	}
 
@@ -830,15 +969,8 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", models.MergeStyle(form.Do)))
 			return
-		} else if models.IsErrMergeConflicts(err) {
-			conflictError := err.(models.ErrMergeConflicts)
-			ctx.JSON(http.StatusConflict, conflictError)
-		} else if models.IsErrRebaseConflicts(err) {
-			conflictError := err.(models.ErrRebaseConflicts)
-			ctx.JSON(http.StatusConflict, conflictError)
-		} else if models.IsErrMergeUnrelatedHistories(err) {
-			conflictError := err.(models.ErrMergeUnrelatedHistories)
-			ctx.JSON(http.StatusConflict, conflictError)
+		} else if models.IsErrMergeConflicts(err) || models.IsErrRebaseConflicts(err) || models.IsErrMergeUnrelatedHistories(err) {
+			ctx.Error(http.StatusConflict, "Merge", err)
 		} else if git.IsErrPushOutOfDate(err) {
 			ctx.Error(http.StatusConflict, "Merge", "merge push out of date")
 			return
@@ -1063,7 +1195,7 @@ func UpdatePullRequest(ctx *context.APIContext) {
 	}
 
 	if !allowedUpdate {
-		ctx.Status(http.StatusForbidden)
+		ctx.Error(http.StatusForbidden, "", "User not allowed to update the pull request")
 		return
 	}
 
@@ -0,0 +1,254 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// canChangePullAutoPublish returns whether the user can change the auto publishing of a pull request,
+// like its title it can be changed by its poster and by the writers of the pull requests
+func canChangePullAutoPublish(ctx *context.APIContext, pr *models.PullRequest) bool {
+	return pr.Issue.IsPoster(ctx.User.ID) || ctx.Repo.CanWrite(models.UnitTypePullRequests)
+}
+
+// GetPullAutoPublish returns the auto publishing of a pull request
+func GetPullAutoPublish(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/auto_publish repository repoGetPullAutoPublish
+	// ---
+	// summary: Get the auto publishing of a work in progress pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullAutoPublish"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ap, err := models.GetPullAutoPublishByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrPullAutoPublishNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullAutoPublishByPullID", err)
+		}
+		return
+	}
+	ap.Pull = pr
+	if err := ap.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPullAutoPublish(ap))
+}
+
+// SetPullAutoPublish publishes a work in progress pull request automatically once its status checks pass
+func SetPullAutoPublish(ctx *context.APIContext, opts api.PullAutoPublishOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/auto_publish repository repoSetPullAutoPublish
+	// ---
+	// summary: Mark a work in progress pull request ready for review once the status checks of its head commit pass
+	// description: The work in progress prefix of the title is removed once the status checks required by the base
+	//   branch pass, or once the statuses of the head commit are successful if it requires none. The reviewers are
+	//   requested then. The pull request is published right away if its status checks already pass.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/PullAutoPublishOptions"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if !canChangePullAutoPublish(ctx, pr) {
+		ctx.Error(http.StatusForbidden, "SetPullAutoPublish", "User not allowed to change the title of the PR")
+		return
+	}
+	if pr.Issue.IsClosed || !pr.IsWorkInProgress() {
+		ctx.Error(http.StatusUnprocessableEntity, "SetPullAutoPublish", "PR is not an open work in progress")
+		return
+	}
+
+	reviewers := make([]*models.User, 0, len(opts.Reviewers))
+	for _, name := range opts.Reviewers {
+		var reviewer *models.User
+		var err error
+		if strings.Contains(name, "@") {
+			reviewer, err = models.GetUserByEmail(name)
+		} else {
+			reviewer, err = models.GetUserByName(name)
+		}
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("UserNotExist", fmt.Sprintf("User '%s' not exist", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUser", err)
+			}
+			return
+		}
+		if err := issue_service.IsValidReviewRequest(reviewer, ctx.User, true, pr.Issue, &ctx.Repo.Permission); err != nil {
+			if models.IsErrNotValidReviewRequest(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "NotValidReviewRequest", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "IsValidReviewRequest", err)
+			}
+			return
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	teamReviewers := make([]*models.Team, 0, len(opts.TeamReviewers))
+	if len(opts.TeamReviewers) > 0 && !ctx.Repo.Owner.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "TeamReviewers", "Only the repositories of organizations have teams")
+		return
+	}
+	for _, name := range opts.TeamReviewers {
+		team, err := models.GetTeam(ctx.Repo.Owner.ID, name)
+		if err != nil {
+			if models.IsErrTeamNotExist(err) {
+				ctx.NotFound("TeamNotExist", fmt.Sprintf("Team '%s' not exist", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+			}
+			return
+		}
+		if err := issue_service.IsValidTeamReviewRequest(team, ctx.User, true, pr.Issue); err != nil {
+			if models.IsErrNotValidReviewRequest(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "NotValidReviewRequest", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "IsValidTeamReviewRequest", err)
+			}
+			return
+		}
+		teamReviewers = append(teamReviewers, team)
+	}
+
+	if err := pull_service.SetAutoPublish(pr, ctx.User, reviewers, teamReviewers); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetAutoPublish", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CancelPullAutoPublish cancels the auto publishing of a pull request
+func CancelPullAutoPublish(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/auto_publish repository repoCancelPullAutoPublish
+	// ---
+	// summary: Cancel the auto publishing of a work in progress pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+
+	if !canChangePullAutoPublish(ctx, pr) {
+		ctx.Error(http.StatusForbidden, "CancelPullAutoPublish", "User not allowed to change the title of the PR")
+		return
+	}
+
+	if err := models.DeletePullAutoPublish(pr.ID); err != nil {
+		if models.IsErrPullAutoPublishNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeletePullAutoPublish", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// GetPullDiffFiles returns the files changed by a pull request with the lines of their diffs
+func GetPullDiffFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/files repository repoGetPullDiffFiles
+	// ---
+	// summary: Get the files changed by a pull request with the lines of their diffs
+	// description: The lines of the blocks which were moved but not changed are marked as moved.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace changes to ignore, defaults to the diff preference of the user
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol]
+	// - name: context
+	//   in: query
+	//   description: number of lines of context around the changes, 0 is the default of git,
+	//     defaults to the diff preference of the user
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiffFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := getDiffOptions(ctx)
+	if ctx.Written() {
+		return
+	}
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	headCommitID, err := ctx.Repo.GitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRefCommitID", err)
+		return
+	}
+	diff, err := gitdiff.GetDiffRangeWithOptions(ctx.Repo.GitRepo.Path,
+		pr.MergeBase, headCommitID, setting.Git.MaxGitDiffLines,
+		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffRangeWithOptions", err)
+		return
+	}
+
+	files := make([]*api.DiffFile, 0, len(diff.Files))
+	for _, file := range diff.Files {
+		files = append(files, toDiffFile(file))
+	}
+	ctx.JSON(http.StatusOK, files)
+}
+
+// diffFileStatuses maps the types of the diff files to their statuses
+var diffFileStatuses = map[gitdiff.DiffFileType]string{
+	gitdiff.DiffFileAdd:    "added",
+	gitdiff.DiffFileChange: "modified",
+	gitdiff.DiffFileDel:    "deleted",
+	gitdiff.DiffFileRename: "renamed",
+	gitdiff.DiffFileCopy:   "copied",
+}
+
+// toDiffFile converts a file of a diff, the fake sections of the unchanged lines following the last
+// hunk are left out
+func toDiffFile(file *gitdiff.DiffFile) *api.DiffFile {
+	result := &api.DiffFile{
+		Name:         file.Name,
+		Status:       diffFileStatuses[file.Type],
+		IsBinary:     file.IsBin,
+		Additions:    file.Addition,
+		Deletions:    file.Deletion,
+		IsIncomplete: file.IsIncomplete,
+		Hunks:        make([]*api.DiffHunk, 0, len(file.Sections)),
+	}
+	if file.OldName != file.Name {
+		result.OldName = file.OldName
+	}
+
+	for _, section := range file.Sections {
+		var hunk *api.DiffHunk
+		for _, line := range section.Lines {
+			if line.Type == gitdiff.DiffLineSection {
+				if strings.HasPrefix(line.Content, "@@") {
+					hunk = &api.DiffHunk{Header: line.Content, Lines: make([]*api.DiffLine, 0, len(section.Lines))}
+				}
+				continue
+			}
+			if hunk == nil {
+				continue
+			}
+			apiLine := &api.DiffLine{
+				OldLine: line.LeftIdx,
+				NewLine: line.RightIdx,
+				Content: line.Content[1:],
+				Moved:   line.Moved,
+			}
+			switch line.Type {
+			case gitdiff.DiffLineAdd:
+				apiLine.Type = "add"
+			case gitdiff.DiffLineDel:
+				apiLine.Type = "delete"
+			default:
+				apiLine.Type = "context"
+			}
+			hunk.Lines = append(hunk.Lines, apiLine)
+		}
+		if hunk != nil {
+			result.Hunks = append(result.Hunks, hunk)
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// GetPullFileTree returns the tree of the files changed by a pull request
+func GetPullFileTree(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/file_tree repository repoGetPullFileTree
+	// ---
+	// summary: Get the tree of the files changed by a pull request
+	// description: The directories sum up the additions and the deletions of the files they contain.
+	//   The diff of each file can be loaded separately from its diff_url.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullFileTreeNode"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	root, err := pull_service.GetFileTree(pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFileTree", err)
+		return
+	}
+	diffURL := fmt.Sprintf("%s/pulls/%d.diff", ctx.Repo.Repository.APIURL(), pr.Index)
+	ctx.JSON(http.StatusOK, toPullFileTreeNode(root, diffURL))
+}
+
+// toPullFileTreeNode converts a node of the tree of the files changed by a pull request and its
+// children, diffURL is the link of the diff of the pull request
+func toPullFileTreeNode(node *pull_service.FileTreeNode, diffURL string) *api.PullFileTreeNode {
+	result := &api.PullFileTreeNode{
+		Name:         node.Name,
+		Path:         node.Path,
+		Type:         "file",
+		Status:       node.Status,
+		OldPath:      node.OldPath,
+		IsBinary:     node.IsBinary,
+		Additions:    node.Additions,
+		Deletions:    node.Deletions,
+		ChangedFiles: node.Files,
+	}
+	if !node.IsDir {
+		result.DiffURL = diffURL + "?path=" + url.QueryEscape(node.Path)
+		return result
+	}
+	result.Type = "dir"
+	result.Children = make([]*api.PullFileTreeNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		result.Children = append(result.Children, toPullFileTreeNode(child, diffURL))
+	}
+	return result
+}
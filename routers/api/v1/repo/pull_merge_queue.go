@@ -0,0 +1,353 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// toAPIMergeQueue converts the entries of a merge queue, in their order
+func toAPIMergeQueue(entries []*models.MergeQueueEntry) ([]*api.MergeQueueEntry, error) {
+	apiEntries := make([]*api.MergeQueueEntry, 0, len(entries))
+	for i, entry := range entries {
+		if err := entry.LoadAttributes(); err != nil {
+			return nil, err
+		}
+		apiEntries = append(apiEntries, convert.ToMergeQueueEntry(entry, i+1))
+	}
+	return apiEntries, nil
+}
+
+// ListMergeQueue list the pull requests in the merge queue of a branch
+func ListMergeQueue(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/merge_queues/{branch} repository repoListMergeQueue
+	// ---
+	// summary: List the pull requests in the merge queue of a branch, in their order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueueEntryList"
+
+	entries, err := models.GetMergeQueueEntries(ctx.Repo.Repository.ID, ctx.Params("*"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntries", err)
+		return
+	}
+	apiEntries, err := toAPIMergeQueue(entries)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiEntries)
+}
+
+// getPullRequestByIndex returns the pull request of the index in the path, it writes the
+// error response if the pull request does not exist
+func getPullRequestByIndex(ctx *context.APIContext) *models.PullRequest {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	return pr
+}
+
+// GetPullMergeQueueEntry returns the merge queue entry of a pull request
+func GetPullMergeQueueEntry(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoGetPullMergeQueueEntry
+	// ---
+	// summary: Get the position and status of a pull request in the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntryByPullID", err)
+		}
+		return
+	}
+	entries, err := models.GetMergeQueueEntries(entry.RepoID, entry.BaseBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntries", err)
+		return
+	}
+	apiEntries, err := toAPIMergeQueue(entries)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	for _, apiEntry := range apiEntries {
+		if apiEntry.Index == pr.Index {
+			ctx.JSON(http.StatusOK, apiEntry)
+			return
+		}
+	}
+	// the pull request was removed from the queue in the meantime
+	ctx.NotFound()
+}
+
+// AddPullToMergeQueue adds a pull request to the merge queue of its base branch
+func AddPullToMergeQueue(ctx *context.APIContext, form auth.MergePullRequestForm) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoAddPullToMergeQueue
+	// ---
+	// summary: Add a pull request to the merge queue of its base branch, it is merged once its merge passes the required status checks
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to merge
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     $ref: "#/definitions/MergePullRequestOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "405":
+	//     "$ref": "#/responses/empty"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if pr.Issue.IsClosed {
+		ctx.NotFound()
+		return
+	}
+
+	allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+		return
+	}
+	if !allowedMerge {
+		ctx.Error(http.StatusMethodNotAllowed, "Merge", "User not allowed to merge PR")
+		return
+	}
+
+	if !pr.CanAutoMerge() {
+		ctx.Error(http.StatusMethodNotAllowed, "PR not in mergeable state", "Please try again later")
+		return
+	}
+
+	if pr.HasMerged {
+		ctx.Error(http.StatusMethodNotAllowed, "PR already merged", "")
+		return
+	}
+
+	if pr.IsWorkInProgress() {
+		ctx.Error(http.StatusMethodNotAllowed, "PR is a work in progress", "Work in progress PRs cannot be merged")
+		return
+	}
+
+	if _, err := pull_service.IsSignedIfRequired(pr, ctx.User); err != nil {
+		if !models.IsErrWontSign(err) {
+			ctx.Error(http.StatusInternalServerError, "IsSignedIfRequired", err)
+			return
+		}
+		ctx.Error(http.StatusMethodNotAllowed, fmt.Sprintf("Protected branch %s requires signed commits but this merge would not be signed", pr.BaseBranch), err)
+		return
+	}
+
+	if len(form.Do) == 0 {
+		form.Do = string(models.MergeStyleMerge)
+	}
+
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		title, body, err := pull_service.GetDefaultMergeMessage(pr, models.MergeStyle(form.Do))
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+			return
+		}
+		message = title
+		if len(strings.TrimSpace(form.MergeMessageField)) == 0 {
+			form.MergeMessageField = body
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	if err := pull_service.AddToMergeQueue(pr, ctx.User, models.MergeStyle(form.Do), message); err != nil {
+		if models.IsErrInvalidMergeStyle(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", models.MergeStyle(form.Do)))
+		} else if models.IsErrNotAllowedToMerge(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
+		} else if models.IsErrMergeQueueEntryAlreadyExists(err) {
+			ctx.Error(http.StatusConflict, "AddToMergeQueue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddToMergeQueue", err)
+		}
+		return
+	}
+
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntryByPullID", err)
+		return
+	}
+	entries, err := models.GetMergeQueueEntries(entry.RepoID, entry.BaseBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntries", err)
+		return
+	}
+	position := len(entries)
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			position = i + 1
+			break
+		}
+	}
+	if err := entry.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToMergeQueueEntry(entry, position))
+}
+
+// RemovePullFromMergeQueue removes a pull request from the merge queue of its base branch
+func RemovePullFromMergeQueue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoRemovePullFromMergeQueue
+	// ---
+	// summary: Remove a pull request from the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+		return
+	}
+	if !allowedMerge {
+		ctx.Error(http.StatusForbidden, "RemoveFromMergeQueue", "User not allowed to merge PR")
+		return
+	}
+
+	if err := pull_service.RemoveFromMergeQueue(pr); err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveFromMergeQueue", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
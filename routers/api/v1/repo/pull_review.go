@@ -335,6 +335,27 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 
 	// create review comments
 	for _, c := range opts.Comments {
+		if len(c.CommitID) > 0 {
+			if _, err := pull_service.CreateCommitMessageComment(
+				ctx.User,
+				ctx.Repo.GitRepo,
+				pr.Issue,
+				c.CommitID,
+				c.NewLineNum,
+				c.Body,
+				true, // is review
+				opts.CommitID,
+			); err != nil {
+				if models.IsErrCommitMessageLineNotExist(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "CreateCommitMessageComment", err)
+					return
+				}
+				ctx.Error(http.StatusInternalServerError, "CreateCommitMessageComment", err)
+				return
+			}
+			continue
+		}
+
 		line := c.NewLineNum
 		if c.OldLineNum > 0 {
 			line = c.OldLineNum * -1
@@ -357,8 +378,12 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 	}
 
 	// create review and associate all pending review comments
-	review, _, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID)
+	review, _, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID, opts.Checklist)
 	if err != nil {
+		if models.IsErrReviewChecklistNotAcknowledged(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "SubmitReview", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
 	}
@@ -444,8 +469,12 @@ func SubmitPullReview(ctx *context.APIContext, opts api.SubmitPullReviewOptions)
 	}
 
 	// create review and associate all pending review comments
-	review, _, err = pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, headCommitID)
+	review, _, err = pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, headCommitID, opts.Checklist)
 	if err != nil {
+		if models.IsErrReviewChecklistNotAcknowledged(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "SubmitReview", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
 	}
@@ -751,3 +780,113 @@ func apiReviewRequest(ctx *context.APIContext, opts api.PullReviewRequestOptions
 		return
 	}
 }
+
+// ApplyPullReviewSuggestions applies the suggestions of code comments of a pull request as a single commit
+func ApplyPullReviewSuggestions(ctx *context.APIContext, opts api.ApplyPullReviewSuggestionsOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/suggestions repository repoApplyPullReviewSuggestions
+	// ---
+	// summary: Apply the suggestions of code comments of a pull request, they are pushed to its head branch as a single commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyPullReviewSuggestionsOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		ctx.Error(http.StatusUnprocessableEntity, "ApplySuggestions", "the pull request is closed")
+		return
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadHeadRepo", err)
+		return
+	}
+	if pr.HeadRepo == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ApplySuggestions", "the head repository of the pull request does not exist")
+		return
+	}
+
+	allowedUpdate, err := pull_service.IsUserAllowedToUpdate(pr, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToUpdate", err)
+		return
+	}
+	if !allowedUpdate {
+		ctx.Error(http.StatusForbidden, "ApplySuggestions", "user is not allowed to push to the head branch of the pull request")
+		return
+	}
+
+	commitID, err := pull_service.ApplySuggestions(pr, ctx.User, opts.CommentIDs, opts.Message)
+	if err != nil {
+		if models.IsErrInvalidSuggestion(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ApplySuggestions", err)
+		} else if git.IsErrPushOutOfDate(err) {
+			ctx.Error(http.StatusConflict, "ApplySuggestions", "the head branch of the pull request has changed")
+		} else if git.IsErrPushRejected(err) {
+			ctx.Error(http.StatusConflict, "ApplySuggestions", "PushRejected with remote message: "+err.(*git.ErrPushRejected).Message)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ApplySuggestions", err)
+		}
+		return
+	}
+
+	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return
+	}
+	defer headGitRepo.Close()
+	commit, err := headGitRepo.GetCommit(commitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+	apiCommit, err := convert.ToCommit(pr.HeadRepo, commit, nil)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiCommit)
+}
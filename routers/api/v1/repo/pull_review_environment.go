@@ -0,0 +1,203 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListPullReviewEnvironments lists the review environments of a pull request
+func ListPullReviewEnvironments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/environments repository repoListPullReviewEnvironments
+	// ---
+	// summary: List the review environments of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: expired
+	//   in: query
+	//   description: include the expired environments
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewEnvironmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	envs, err := models.GetReviewEnvironments(pr.ID, ctx.QueryBool("expired"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewEnvironments", err)
+		return
+	}
+	apiEnvs := make([]*api.ReviewEnvironment, 0, len(envs))
+	for _, env := range envs {
+		if err := env.LoadCreator(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadCreator", err)
+			return
+		}
+		apiEnvs = append(apiEnvs, convert.ToReviewEnvironment(env))
+	}
+	ctx.JSON(http.StatusOK, apiEnvs)
+}
+
+// CreatePullReviewEnvironment registers or updates a review environment of a pull request
+func CreatePullReviewEnvironment(ctx *context.APIContext, form api.CreateReviewEnvironmentOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/environments repository repoCreatePullReviewEnvironment
+	// ---
+	// summary: Register a review environment of a pull request, the environment with the same name is updated
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateReviewEnvironmentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ReviewEnvironment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	if pr.Issue.IsClosed {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the pull request is closed")
+		return
+	}
+
+	state := api.CommitStatusState(form.State)
+	switch state {
+	case "":
+		state = api.CommitStatusPending
+	case api.CommitStatusPending, api.CommitStatusSuccess, api.CommitStatusError, api.CommitStatusFailure, api.CommitStatusWarning:
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid state")
+		return
+	}
+
+	env := &models.ReviewEnvironment{
+		RepoID:      ctx.Repo.Repository.ID,
+		PullID:      pr.ID,
+		Name:        form.Name,
+		URL:         form.URL,
+		State:       state,
+		Description: form.Description,
+		CreatorID:   ctx.User.ID,
+		Creator:     ctx.User,
+	}
+	if err := models.UpsertReviewEnvironment(env); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpsertReviewEnvironment", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToReviewEnvironment(env))
+}
+
+// DeletePullReviewEnvironment deletes a review environment of a pull request
+func DeletePullReviewEnvironment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/environments/{name} repository repoDeletePullReviewEnvironment
+	// ---
+	// summary: Delete a review environment of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	env, err := models.GetReviewEnvironment(pr.ID, ctx.Params(":name"))
+	if err != nil {
+		if models.IsErrReviewEnvironmentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetReviewEnvironment", err)
+		}
+		return
+	}
+	if err := models.DeleteReviewEnvironment(env); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteReviewEnvironment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
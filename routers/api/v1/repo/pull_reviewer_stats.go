@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListReviewerStats list the review activity of the reviewers of a repository
+func ListReviewerStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/insights/reviewers repository repoListReviewerStats
+	// ---
+	// summary: List the review activity of the reviewers of the pull requests of a repository
+	// description: The reviewers keeping their review statistics private are only listed to themselves and to the admins.
+	//   The turnaround only counts the review requests and the reviews of the period.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only count the activity after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only count the activity before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewerStatsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListReviewerStats(ctx, []int64{ctx.Repo.Repository.ID})
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// writePullViewedFiles writes the files of a pull request the user marked as viewed
+func writePullViewedFiles(ctx *context.APIContext, pr *models.PullRequest) {
+	files, err := pull_service.GetViewedFiles(pr, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetViewedFiles", err)
+		return
+	}
+	apiFiles := make([]*api.PullViewedFile, 0, len(files))
+	for _, file := range files {
+		apiFiles = append(apiFiles, convert.ToPullViewedFile(file.PullViewedFile, file.HasChanged))
+	}
+	ctx.JSON(http.StatusOK, apiFiles)
+}
+
+// ListPullViewedFiles lists the files of a pull request the user marked as viewed
+func ListPullViewedFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/viewed_files repository repoListPullViewedFiles
+	// ---
+	// summary: List the files of a pull request the authenticated user marked as viewed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullViewedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+	writePullViewedFiles(ctx, pr)
+}
+
+// UpdatePullViewedFiles marks files of a pull request as viewed or not viewed
+func UpdatePullViewedFiles(ctx *context.APIContext, form api.UpdatePullViewedFilesOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/viewed_files repository repoUpdatePullViewedFiles
+	// ---
+	// summary: Mark files of a pull request as viewed or not viewed by the authenticated user, a viewed file is not viewed anymore once it changes
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UpdatePullViewedFilesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullViewedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := pull_service.UpdateViewedFiles(pr, ctx.User, form.Viewed, form.Unviewed); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateViewedFiles", err)
+		return
+	}
+	writePullViewedFiles(ctx, pr)
+}
@@ -152,6 +152,8 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	//     "$ref": "#/responses/Attachment"
 	//   "400":
 	//     "$ref": "#/responses/error"
+	//   "413":
+	//     "$ref": "#/responses/error"
 
 	// Check if attachments are enabled
 	if !setting.Attachment.Enabled {
@@ -188,6 +190,19 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 		return
 	}
 
+	if err := ctx.Repo.Repository.GetOwner(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOwner", err)
+		return
+	}
+	if err := models.CheckStorageQuota(ctx.Repo.Repository.Owner, header.Size); err != nil {
+		if models.IsErrStorageQuotaExceeded(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, "CheckStorageQuota", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CheckStorageQuota", err)
+		}
+		return
+	}
+
 	var filename = header.Filename
 	if query := ctx.Query("name"); query != "" {
 		filename = query
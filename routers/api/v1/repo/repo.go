@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -91,6 +92,14 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: show only archived, non-archived or all repositories (defaults to all)
 	//   type: boolean
+	// - name: license
+	//   in: query
+	//   description: SPDX identifier of the license of the repositories
+	//   type: string
+	// - name: has_code_of_conduct
+	//   in: query
+	//   description: show only repositories with, without or regardless of a code of conduct (defaults to all)
+	//   type: boolean
 	// - name: mode
 	//   in: query
 	//   description: type of repository to search for. Supported values are
@@ -137,6 +146,7 @@ func Search(ctx *context.APIContext) {
 		Template:           util.OptionalBoolNone,
 		StarredByID:        ctx.QueryInt64("starredBy"),
 		IncludeDescription: ctx.QueryBool("includeDesc"),
+		License:            ctx.Query("license"),
 	}
 
 	if ctx.Query("template") != "" {
@@ -173,6 +183,10 @@ func Search(ctx *context.APIContext) {
 		opts.IsPrivate = util.OptionalBoolOf(ctx.QueryBool("is_private"))
 	}
 
+	if ctx.Query("has_code_of_conduct") != "" {
+		opts.HasCodeOfConduct = util.OptionalBoolOf(ctx.QueryBool("has_code_of_conduct"))
+	}
+
 	var sortMode = ctx.Query("sort")
 	if len(sortMode) > 0 {
 		var sortOrder = ctx.Query("order")
@@ -195,28 +209,20 @@ func Search(ctx *context.APIContext) {
 	var err error
 	repos, count, err := models.SearchRepository(opts)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, api.SearchError{
-			OK:    false,
-			Error: err.Error(),
-		})
+		ctx.Error(http.StatusInternalServerError, "SearchRepository", err)
 		return
 	}
 
 	results := make([]*api.Repository, len(repos))
 	for i, repo := range repos {
 		if err = repo.GetOwner(); err != nil {
-			ctx.JSON(http.StatusInternalServerError, api.SearchError{
-				OK:    false,
-				Error: err.Error(),
-			})
+			ctx.Error(http.StatusInternalServerError, "GetOwner", err)
 			return
 		}
 		accessMode, err := models.AccessLevel(ctx.User, repo)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, api.SearchError{
-				OK:    false,
-				Error: err.Error(),
-			})
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
 		}
 		results[i] = convert.ToRepo(repo, accessMode)
 	}
@@ -615,10 +621,29 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			var config *models.IssuesConfig
 
 			if opts.InternalTracker != nil {
+				for _, keyword := range append(append([]string{}, opts.InternalTracker.CloseKeywords...), opts.InternalTracker.ReopenKeywords...) {
+					if !references.IsValidKeyword(keyword) {
+						err := fmt.Errorf("invalid keyword: %s", keyword)
+						ctx.Error(http.StatusUnprocessableEntity, "Invalid keyword", err)
+						return err
+					}
+				}
+				for _, language := range opts.InternalTracker.KeywordLanguages {
+					if _, ok := references.KeywordLanguages[language]; !ok {
+						err := fmt.Errorf("unknown keyword language: %s", language)
+						ctx.Error(http.StatusUnprocessableEntity, "Unknown keyword language", err)
+						return err
+					}
+				}
 				config = &models.IssuesConfig{
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					CloseKeywords:                    opts.InternalTracker.CloseKeywords,
+					ReopenKeywords:                   opts.InternalTracker.ReopenKeywords,
+					KeywordLanguages:                 opts.InternalTracker.KeywordLanguages,
+					DisableCrossRepoClose:            opts.InternalTracker.DisableCrossRepoClose,
+					CloseOnDefaultBranchOnly:         opts.InternalTracker.CloseOnDefaultBranchOnly,
 				}
 			} else if unit, err := repo.GetUnit(models.UnitTypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -717,6 +742,9 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			if opts.AllowSquash != nil {
 				config.AllowSquash = *opts.AllowSquash
 			}
+			if opts.ReviewChecklist != nil {
+				config.ReviewChecklist = *opts.ReviewChecklist
+			}
 
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
@@ -800,6 +828,8 @@ func Delete(ctx *context.APIContext) {
 	//     "$ref": "#/responses/empty"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	owner := ctx.Repo.Owner
 	repo := ctx.Repo.Repository
@@ -814,6 +844,10 @@ func Delete(ctx *context.APIContext) {
 	}
 
 	if err := repo_service.DeleteRepository(ctx.User, repo); err != nil {
+		if models.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "DeleteRepository", err)
 		return
 	}
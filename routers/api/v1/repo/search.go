@@ -0,0 +1,247 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// SearchCode searches the code of the default branch of a repository
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/search/code repository repoSearchCode
+	// ---
+	// summary: Search the code of the default branch of a repository
+	// description: The code indexer is used if it is enabled, otherwise the files are searched with git grep.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: "keyword or regular expression to search for, with the qualifiers `symbol:name`
+	//     for the files defining a symbol, `path:value` for the files whose path contains value
+	//     and `lang:name` for the files of a language"
+	//   type: string
+	//   required: true
+	// - name: l
+	//   in: query
+	//   description: language of the files to search
+	//   type: string
+	// - name: regex
+	//   in: query
+	//   description: whether the keyword is a regular expression
+	//   type: boolean
+	// - name: case_sensitive
+	//   in: query
+	//   description: whether the search is case sensitive
+	//   type: boolean
+	// - name: whole_word
+	//   in: query
+	//   description: whether only whole words are matched
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	opts := &code_indexer.SearchOptions{
+		RepoIDs:         []int64{ctx.Repo.Repository.ID},
+		Language:        ctx.QueryTrim("l"),
+		IsRegex:         ctx.QueryBool("regex"),
+		IsCaseSensitive: ctx.QueryBool("case_sensitive"),
+		IsWholeWord:     ctx.QueryBool("whole_word"),
+		Page:            listOptions.Page,
+		PageSize:        listOptions.PageSize,
+	}
+	opts.ParseKeyword(ctx.QueryTrim("q"))
+
+	var (
+		total     int
+		results   []*code_indexer.Result
+		languages []*code_indexer.SearchResultLanguages
+		err       error
+	)
+	if setting.Indexer.RepoIndexerEnabled {
+		total, results, languages, err = code_indexer.PerformSearch(opts)
+	} else {
+		total, results, languages, err = code_indexer.GrepSearch(ctx.Repo.Repository, opts)
+	}
+	if err != nil {
+		if code_indexer.IsErrInvalidSearchPattern(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SearchCode", err)
+		}
+		return
+	}
+
+	apiResults := &api.CodeSearchResults{
+		TotalCount: int64(total),
+		Items:      make([]*api.CodeSearchResult, len(results)),
+		Languages:  make([]*api.CodeSearchLanguage, len(languages)),
+	}
+	for i := range results {
+		apiResults.Items[i] = convert.ToCodeSearchResult(ctx.Repo.Repository, results[i])
+	}
+	for i := range languages {
+		apiResults.Languages[i] = convert.ToCodeSearchLanguage(languages[i])
+	}
+	ctx.JSON(http.StatusOK, apiResults)
+}
+
+// SearchAllCode searches the code of the default branches of the repositories readable by the user
+func SearchAllCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/search/code repository repoSearchAllCode
+	// ---
+	// summary: Search the code of the default branches of the repositories readable by the user
+	// description: The code indexer must be enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: "keyword or regular expression to search for, with the qualifiers `symbol:name`
+	//     for the files defining a symbol, `path:value` for the files whose path contains value
+	//     and `lang:name` for the files of a language"
+	//   type: string
+	//   required: true
+	// - name: owner
+	//   in: query
+	//   description: name of the user or organization whose repositories are searched
+	//   type: string
+	// - name: l
+	//   in: query
+	//   description: language of the files to search
+	//   type: string
+	// - name: regex
+	//   in: query
+	//   description: whether the keyword is a regular expression
+	//   type: boolean
+	// - name: case_sensitive
+	//   in: query
+	//   description: whether the search is case sensitive
+	//   type: boolean
+	// - name: whole_word
+	//   in: query
+	//   description: whether only whole words are matched
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	var ownerID int64
+	if ownerName := ctx.QueryTrim("owner"); len(ownerName) > 0 {
+		owner, err := models.GetUserByName(ownerName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		if owner.IsOrganization() && !models.HasOrgVisible(owner, ctx.User) {
+			ctx.NotFound()
+			return
+		}
+		ownerID = owner.ID
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	opts := &code_indexer.SearchOptions{
+		Language:        ctx.QueryTrim("l"),
+		IsRegex:         ctx.QueryBool("regex"),
+		IsCaseSensitive: ctx.QueryBool("case_sensitive"),
+		IsWholeWord:     ctx.QueryBool("whole_word"),
+		Page:            listOptions.Page,
+		PageSize:        listOptions.PageSize,
+	}
+	opts.ParseKeyword(ctx.QueryTrim("q"))
+
+	total, results, languages, err := code_indexer.PerformUserSearch(ctx.User, ownerID, opts)
+	if err != nil {
+		if code_indexer.IsErrInvalidSearchPattern(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "PerformUserSearch", err)
+		}
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(results))
+	for _, result := range results {
+		repoIDs = append(repoIDs, result.RepoID)
+	}
+	repos, err := models.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	apiResults := &api.CodeSearchResults{
+		TotalCount: int64(total),
+		Items:      make([]*api.CodeSearchResult, 0, len(results)),
+		Languages:  make([]*api.CodeSearchLanguage, len(languages)),
+	}
+	for _, result := range results {
+		if repo, ok := repos[result.RepoID]; ok {
+			apiResults.Items = append(apiResults.Items, convert.ToCodeSearchResult(repo, result))
+		}
+	}
+	for i := range languages {
+		apiResults.Languages[i] = convert.ToCodeSearchLanguage(languages[i])
+	}
+	ctx.JSON(http.StatusOK, apiResults)
+}
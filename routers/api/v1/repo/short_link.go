@@ -0,0 +1,142 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// CreateShortLink creates a short link to lines of a file or to the diff of a commit
+func CreateShortLink(ctx *context.APIContext, form api.CreateShortLinkOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/short_links repository repoCreateShortLink
+	// ---
+	// summary: Create a short link to lines of a file or to the diff of a commit
+	// description: The link resolves to a permalink at the commit of the ref, it can be resolved by the
+	//   signed in users who can read the code of the repository.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateShortLinkOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ShortLink"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := repo_service.ShortLinkOptions{
+		Type:      models.ShortLinkTypeFile,
+		Ref:       form.Ref,
+		TreePath:  form.Path,
+		LineStart: form.LineStart,
+		LineEnd:   form.LineEnd,
+	}
+	if form.Type == "diff" {
+		opts.Type = models.ShortLinkTypeDiff
+	}
+	if opts.LineStart < 0 || opts.LineEnd < 0 || (opts.LineEnd > 0 && opts.LineEnd < opts.LineStart) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid line range")
+		return
+	}
+	if opts.Type == models.ShortLinkTypeFile && len(opts.TreePath) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "path is required for a file")
+		return
+	}
+
+	link, err := repo_service.CreateShortLink(ctx.Repo.Repository, ctx.User, opts)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateShortLink", err)
+		}
+		return
+	}
+	target, err := repo_service.ShortLinkTarget(link)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ShortLinkTarget", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToShortLink(link, target))
+}
+
+// GetShortLink returns a short link of a repository
+func GetShortLink(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/short_links/{token} repository repoGetShortLink
+	// ---
+	// summary: Get a short link of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: token
+	//   in: path
+	//   description: token of the short link
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ShortLink"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	link, err := models.GetShortLinkByToken(ctx.Params(":token"))
+	if err != nil {
+		if models.IsErrShortLinkNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetShortLinkByToken", err)
+		}
+		return
+	}
+	if link.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+	link.Repo = ctx.Repo.Repository
+
+	target, err := repo_service.ShortLinkTarget(link)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ShortLinkTarget", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToShortLink(link, target))
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetSize returns the breakdown of the storage used by a repository
+func GetSize(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/size repository repoGetSize
+	// ---
+	// summary: Get the breakdown of the storage used by a repository
+	// description: The breakdown is computed in the background and cached, the request has to be
+	//   repeated while the response is 202.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSize"
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	size, err := repo_service.GetSize(ctx.Repo.Repository)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if size == nil {
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+	ctx.JSON(http.StatusOK, size)
+}
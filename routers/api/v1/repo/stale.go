@@ -0,0 +1,208 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// GetStalePolicy get the stale policy of a repository
+func GetStalePolicy(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stale_policy repository repoGetStalePolicy
+	// ---
+	// summary: Get the stale policy of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StalePolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := models.GetStalePolicy(ctx.Repo.Repository.ID)
+	if err != nil {
+		if models.IsErrStalePolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetStalePolicy", err)
+		}
+		return
+	}
+	writeStalePolicy(ctx, http.StatusOK, policy)
+}
+
+func writeStalePolicy(ctx *context.APIContext, status int, policy *models.StalePolicy) {
+	doer, err := models.GetUserByID(policy.DoerID)
+	if err != nil && !models.IsErrUserNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+		return
+	}
+	ctx.JSON(status, convert.ToStalePolicy(policy, doer))
+}
+
+// EditStalePolicy create or replace the stale policy of a repository
+func EditStalePolicy(ctx *context.APIContext, form api.EditStalePolicyOption) {
+	// swagger:operation PUT /repos/{owner}/{repo}/stale_policy repository repoEditStalePolicy
+	// ---
+	// summary: Create or replace the stale policy of a repository
+	// description: The actions of the policy are taken on behalf of the user which last changed it.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditStalePolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StalePolicy"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if form.DaysUntilStale <= 0 || form.DaysUntilClose < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("days_until_stale must be positive and days_until_close can't be negative"))
+		return
+	}
+	for _, name := range form.ExemptLabels {
+		if strings.Contains(name, ",") {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid exempt label: %s", name))
+			return
+		}
+	}
+
+	policy := &models.StalePolicy{
+		RepoID:         ctx.Repo.Repository.ID,
+		Enabled:        form.Enabled,
+		IncludeIssues:  form.IncludeIssues,
+		IncludePulls:   form.IncludePulls,
+		DaysUntilStale: form.DaysUntilStale,
+		DaysUntilClose: form.DaysUntilClose,
+		StaleLabel:     form.StaleLabel,
+		ExemptLabels:   strings.Join(form.ExemptLabels, ","),
+		WarningComment: form.WarningComment,
+		CloseComment:   form.CloseComment,
+		DoerID:         ctx.User.ID,
+	}
+	if err := models.SaveStalePolicy(policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SaveStalePolicy", err)
+		return
+	}
+	policy, err := models.GetStalePolicy(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStalePolicy", err)
+		return
+	}
+	writeStalePolicy(ctx, http.StatusOK, policy)
+}
+
+// DeleteStalePolicy delete the stale policy of a repository
+func DeleteStalePolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/stale_policy repository repoDeleteStalePolicy
+	// ---
+	// summary: Delete the stale policy of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteStalePolicy(ctx.Repo.Repository.ID); err != nil {
+		if models.IsErrStalePolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteStalePolicy", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListStaleActions list the actions taken by the stale policy of a repository
+func ListStaleActions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stale_policy/actions repository repoListStaleActions
+	// ---
+	// summary: List the actions taken by the stale policy of a repository, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StaleActionList"
+
+	listOptions := utils.GetListOptions(ctx)
+	logs, count, err := models.GetStaleActionLogs(ctx.Repo.Repository.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStaleActionLogs", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, convert.ToStaleActionList(logs))
+}
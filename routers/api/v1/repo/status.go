@@ -11,9 +11,12 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/repofiles"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
 )
 
 // NewCommitStatus creates a new CommitStatus
@@ -64,6 +67,15 @@ func NewCommitStatus(ctx *context.APIContext, form api.CreateStatusOption) {
 		ctx.Error(http.StatusInternalServerError, "CreateCommitStatus", err)
 		return
 	}
+	if err := pull_service.ProcessMergeQueuesTestingCommit(ctx.Repo.Repository, sha); err != nil {
+		log.Error("ProcessMergeQueuesTestingCommit[%s]: %v", sha, err)
+	}
+	if err := pull_service.ProcessAutoPublish(ctx.Repo.Repository, sha); err != nil {
+		log.Error("ProcessAutoPublish[%s]: %v", sha, err)
+	}
+	if err := issue_service.ProcessStatusIssue(ctx.Repo.Repository, sha, status); err != nil {
+		log.Error("ProcessStatusIssue[%s]: %v", sha, err)
+	}
 
 	ctx.JSON(http.StatusCreated, convert.ToCommitStatus(status))
 }
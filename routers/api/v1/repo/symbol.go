@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSymbols list the definitions found in the default branch of a repository
+func ListSymbols(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/symbols repository repoListSymbols
+	// ---
+	// summary: List the symbols defined in the default branch of a repository
+	// description: The symbols are extracted by the symbol indexer, which must be enabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword the name of the symbols must contain
+	//   type: string
+	// - name: name
+	//   in: query
+	//   description: exact name of the symbols, case insensitive
+	//   type: string
+	// - name: kind
+	//   in: query
+	//   description: kind of the symbols, e.g. function, method, class or type
+	//   type: string
+	// - name: language
+	//   in: query
+	//   description: language of the files the symbols are defined in
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: file or directory the symbols are defined in
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSymbolList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !setting.Indexer.SymbolIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	status, err := ctx.Repo.Repository.GetIndexerStatus(models.RepoIndexerTypeSymbols)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIndexerStatus", err)
+		return
+	}
+
+	symbols, count, err := models.SearchSymbols(&models.SearchSymbolOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Keyword:     ctx.QueryTrim("q"),
+		Name:        ctx.QueryTrim("name"),
+		Kind:        ctx.QueryTrim("kind"),
+		Language:    ctx.QueryTrim("language"),
+		Path:        ctx.QueryTrim("path"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchSymbols", err)
+		return
+	}
+
+	apiSymbols := make([]*api.CodeSymbol, len(symbols))
+	for i := range symbols {
+		apiSymbols[i] = convert.ToCodeSymbol(ctx.Repo.Repository, status.CommitSha, symbols[i])
+	}
+
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+	ctx.JSON(http.StatusOK, apiSymbols)
+}
@@ -97,18 +97,16 @@ func UpdateTopics(ctx *context.APIContext, form api.RepoTopicOptions) {
 	validTopics, invalidTopics := models.SanitizeAndValidateTopics(topicNames)
 
 	if len(validTopics) > 25 {
-		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
-			"invalidTopics": nil,
-			"message":       "Exceeding maximum number of topics per repo",
-		})
+		ctx.InvalidTopicsError("Exceeding maximum number of topics per repo", nil)
 		return
 	}
 
 	if len(invalidTopics) > 0 {
-		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
-			"invalidTopics": invalidTopics,
-			"message":       "Topic names are invalid",
-		})
+		details := make([]*api.APIErrorDetail, 0, len(invalidTopics))
+		for _, topic := range invalidTopics {
+			details = append(details, &api.APIErrorDetail{Field: "topics", Code: "invalid", Message: topic})
+		}
+		ctx.InvalidTopicsError("Topic names are invalid", invalidTopics, details...)
 		return
 	}
 
@@ -154,10 +152,7 @@ func AddTopic(ctx *context.APIContext) {
 	topicName := strings.TrimSpace(strings.ToLower(ctx.Params(":topic")))
 
 	if !models.ValidateTopic(topicName) {
-		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
-			"invalidTopics": topicName,
-			"message":       "Topic name is invalid",
-		})
+		ctx.InvalidTopicsError("Topic name is invalid", topicName, &api.APIErrorDetail{Field: "topic", Code: "invalid", Message: topicName})
 		return
 	}
 
@@ -171,9 +166,7 @@ func AddTopic(ctx *context.APIContext) {
 		return
 	}
 	if len(topics) >= 25 {
-		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
-			"message": "Exceeding maximum allowed topics per repo.",
-		})
+		ctx.ValidationError("Exceeding maximum allowed topics per repo.")
 		return
 	}
 
@@ -219,10 +212,7 @@ func DeleteTopic(ctx *context.APIContext) {
 	topicName := strings.TrimSpace(strings.ToLower(ctx.Params(":topic")))
 
 	if !models.ValidateTopic(topicName) {
-		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
-			"invalidTopics": topicName,
-			"message":       "Topic name is invalid",
-		})
+		ctx.InvalidTopicsError("Topic name is invalid", topicName, &api.APIErrorDetail{Field: "topic", Code: "invalid", Message: topicName})
 		return
 	}
 
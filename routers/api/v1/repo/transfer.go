@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
@@ -97,6 +98,7 @@ func Transfer(ctx *context.APIContext, opts api.TransferRepoOption) {
 		ctx.InternalServerError(err)
 		return
 	}
+	audit.Record(models.AuditActionRepoTransfer, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Transferred from %s to %s", ctx.Repo.Owner.Name, newOwner.Name)
 
 	newRepo, err := models.GetRepositoryByName(newOwner.ID, ctx.Repo.Repository.Name)
 	if err != nil {
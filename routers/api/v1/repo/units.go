@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// EditUnits enables and disables units of a repository
+func EditUnits(ctx *context.APIContext, opts api.EditRepoUnitsOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/units repository repoEditUnits
+	// ---
+	// summary: Enable and disable units of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoUnitsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	enable, disable, err := repo_module.ParseUnitsToToggle(opts.Enable, opts.Disable)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseUnitsToToggle", err)
+		return
+	}
+	if _, err := repo_module.UpdateRepositoryUnits(ctx.Repo.Repository, enable, disable); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToRepo(ctx.Repo.Repository, ctx.Repo.AccessMode))
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// LegalHold
+// swagger:response LegalHold
+type swaggerLegalHold struct {
+	// in:body
+	Body api.LegalHold `json:"body"`
+}
+
+// LegalHoldList
+// swagger:response LegalHoldList
+type swaggerLegalHoldList struct {
+	// in:body
+	Body []api.LegalHold `json:"body"`
+}
+
+// AuditEventList
+// swagger:response AuditEventList
+type swaggerAuditEventList struct {
+	// in:body
+	Body []api.AuditEvent `json:"body"`
+}
@@ -36,6 +36,13 @@ type swaggerResponseCommentList struct {
 	Body []api.Comment `json:"body"`
 }
 
+// TimelineList
+// swagger:response TimelineList
+type swaggerResponseTimelineList struct {
+	// in:body
+	Body []api.TimelineComment `json:"body"`
+}
+
 // Label
 // swagger:response Label
 type swaggerResponseLabel struct {
@@ -64,6 +71,13 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// MilestoneStats
+// swagger:response MilestoneStats
+type swaggerResponseMilestoneStats struct {
+	// in:body
+	Body api.MilestoneStats `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
@@ -71,6 +85,34 @@ type swaggerResponseTrackedTime struct {
 	Body api.TrackedTime `json:"body"`
 }
 
+// IssueDependencyGraph
+// swagger:response IssueDependencyGraph
+type swaggerResponseIssueDependencyGraph struct {
+	// in:body
+	Body api.IssueDependencyGraph `json:"body"`
+}
+
+// SubIssuesProgress
+// swagger:response SubIssuesProgress
+type swaggerResponseSubIssuesProgress struct {
+	// in:body
+	Body api.SubIssuesProgress `json:"body"`
+}
+
+// IssueReminder
+// swagger:response IssueReminder
+type swaggerResponseIssueReminder struct {
+	// in:body
+	Body api.IssueReminder `json:"body"`
+}
+
+// IssueReminderList
+// swagger:response IssueReminderList
+type swaggerResponseIssueReminderList struct {
+	// in:body
+	Body []api.IssueReminder `json:"body"`
+}
+
 // TrackedTimeList
 // swagger:response TrackedTimeList
 type swaggerResponseTrackedTimeList struct {
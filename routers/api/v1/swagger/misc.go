@@ -21,3 +21,24 @@ type swaggerResponseStringSlice struct {
 	// in:body
 	Body []string `json:"body"`
 }
+
+// Hovercard
+// swagger:response Hovercard
+type swaggerResponseHovercard struct {
+	// in:body
+	Body api.Hovercard `json:"body"`
+}
+
+// GlobalSearchResults
+// swagger:response GlobalSearchResults
+type swaggerResponseGlobalSearchResults struct {
+	// in:body
+	Body api.GlobalSearchResults `json:"body"`
+}
+
+// OEmbed
+// swagger:response OEmbed
+type swaggerResponseOEmbed struct {
+	// in:body
+	Body api.OEmbed `json:"body"`
+}
@@ -18,6 +18,11 @@ type swaggerParameterBodies struct {
 	// in:body
 	AddCollaboratorOption api.AddCollaboratorOption
 
+	// in:body
+	CreateAccessTokenOption api.CreateAccessTokenOption
+	// in:body
+	EditAccessTokenOption api.EditAccessTokenOption
+
 	// in:body
 	CreateEmailOption api.CreateEmailOption
 	// in:body
@@ -34,6 +39,34 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreateIssueOption api.CreateIssueOption
 	// in:body
+	AttachSubIssueOption api.AttachSubIssueOption
+	// in:body
+	CreateIssueReminderOption api.CreateIssueReminderOption
+
+	// in:body
+	UpdateRepoAvatarOption api.UpdateRepoAvatarOption
+	// in:body
+	UpdateOrgAvatarOption api.UpdateOrgAvatarOption
+
+	// in:body
+	CreateSavedReplyOption api.CreateSavedReplyOption
+	// in:body
+	EditSavedReplyOption api.EditSavedReplyOption
+
+	// in:body
+	EditStalePolicyOption api.EditStalePolicyOption
+
+	// in:body
+	CreateRepoBadgeOption api.CreateRepoBadgeOption
+	// in:body
+	EditRepoBadgeOption api.EditRepoBadgeOption
+
+	// in:body
+	CreateLegalHoldOption api.CreateLegalHoldOption
+
+	// in:body
+	CreateProjectAutomationRuleOption api.CreateProjectAutomationRuleOption
+	// in:body
 	EditIssueOption api.EditIssueOption
 	// in:body
 	EditDeadlineOption api.EditDeadlineOption
@@ -57,6 +90,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	MarkdownOption api.MarkdownOption
 
+	// in:body
+	MarkupOption api.MarkupOption
+
 	// in:body
 	CreateMilestoneOption api.CreateMilestoneOption
 	// in:body
@@ -73,6 +109,8 @@ type swaggerParameterBodies struct {
 	EditPullRequestOption api.EditPullRequestOption
 	// in:body
 	MergePullRequestOption auth.MergePullRequestForm
+	// in:body
+	CreateReviewEnvironmentOption api.CreateReviewEnvironmentOption
 
 	// in:body
 	CreateReleaseOption api.CreateReleaseOption
@@ -120,6 +158,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	DeleteFileOptions api.DeleteFileOptions
 
+	// in:body
+	ChangeFilesOptions api.ChangeFilesOptions
+
 	// in:body
 	CommitDateOptions api.CommitDateOptions
 
@@ -155,4 +196,23 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
+
+	// in:body
+	UpdatePullViewedFilesOption api.UpdatePullViewedFilesOption
+
+	// in:body
+	PullAutoPublishOptions api.PullAutoPublishOptions
+
+	// in:body
+	CreateShortLinkOption api.CreateShortLinkOption
+
+	// in:body
+	CreateIssueSLAPolicyOption api.CreateIssueSLAPolicyOption
+	// in:body
+	EditIssueSLAPolicyOption api.EditIssueSLAPolicyOption
+
+	// in:body
+	EditRepoUnitsOption api.EditRepoUnitsOption
+	// in:body
+	BulkEditRepoUnitsOption api.BulkEditRepoUnitsOption
 }
@@ -35,3 +35,31 @@ type swaggerResponseTeamList struct {
 	// in:body
 	Body []api.Team `json:"body"`
 }
+
+// IssueSLAPolicy
+// swagger:response IssueSLAPolicy
+type swaggerResponseIssueSLAPolicy struct {
+	// in:body
+	Body api.IssueSLAPolicy `json:"body"`
+}
+
+// IssueSLAPolicyList
+// swagger:response IssueSLAPolicyList
+type swaggerResponseIssueSLAPolicyList struct {
+	// in:body
+	Body []api.IssueSLAPolicy `json:"body"`
+}
+
+// IssueSLAReportList
+// swagger:response IssueSLAReportList
+type swaggerResponseIssueSLAReportList struct {
+	// in:body
+	Body []api.IssueSLAReport `json:"body"`
+}
+
+// RepoUnitsTask
+// swagger:response RepoUnitsTask
+type swaggerResponseRepoUnitsTask struct {
+	// in:body
+	Body api.RepoUnitsTask `json:"body"`
+}
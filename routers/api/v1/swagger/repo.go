@@ -141,6 +141,55 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// MergeQueueEntry
+// swagger:response MergeQueueEntry
+type swaggerResponseMergeQueueEntry struct {
+	// in:body
+	Body api.MergeQueueEntry `json:"body"`
+}
+
+// MergeQueueEntryList
+// swagger:response MergeQueueEntryList
+type swaggerResponseMergeQueueEntryList struct {
+	// in:body
+	Body []api.MergeQueueEntry `json:"body"`
+}
+
+// PullAutoPublish
+// swagger:response PullAutoPublish
+type swaggerResponsePullAutoPublish struct {
+	// in:body
+	Body api.PullAutoPublish `json:"body"`
+}
+
+// ReviewerStatsList
+// swagger:response ReviewerStatsList
+type swaggerResponseReviewerStatsList struct {
+	// in:body
+	Body []api.ReviewerStats `json:"body"`
+}
+
+// ReviewEnvironment
+// swagger:response ReviewEnvironment
+type swaggerResponseReviewEnvironment struct {
+	// in:body
+	Body api.ReviewEnvironment `json:"body"`
+}
+
+// ReviewEnvironmentList
+// swagger:response ReviewEnvironmentList
+type swaggerResponseReviewEnvironmentList struct {
+	// in:body
+	Body []api.ReviewEnvironment `json:"body"`
+}
+
+// PullViewedFileList
+// swagger:response PullViewedFileList
+type swaggerResponsePullViewedFileList struct {
+	// in:body
+	Body []api.PullViewedFile `json:"body"`
+}
+
 // PullReview
 // swagger:response PullReview
 type swaggerResponsePullReview struct {
@@ -254,6 +303,13 @@ type swaggerCommitList struct {
 	Body []api.Commit `json:"body"`
 }
 
+// CommitGraph
+// swagger:response CommitGraph
+type swaggerCommitGraph struct {
+	// in: body
+	Body api.CommitGraph `json:"body"`
+}
+
 // EmptyRepository
 // swagger:response EmptyRepository
 type swaggerEmptyRepository struct {
@@ -268,6 +324,13 @@ type swaggerFileResponse struct {
 	Body api.FileResponse `json:"body"`
 }
 
+// FilesResponse
+// swagger:response FilesResponse
+type swaggerFilesResponse struct {
+	// in: body
+	Body api.FilesResponse `json:"body"`
+}
+
 // ContentsResponse
 // swagger:response ContentsResponse
 type swaggerContentsResponse struct {
@@ -309,3 +372,108 @@ type swaggerLanguageStatistics struct {
 	// in: body
 	Body map[string]int64 `json:"body"`
 }
+
+// MentionCandidateList
+// swagger:response MentionCandidateList
+type swaggerMentionCandidateList struct {
+	// in: body
+	Body []api.MentionCandidate `json:"body"`
+}
+
+// FileBlameResponse
+// swagger:response FileBlameResponse
+type swaggerFileBlameResponse struct {
+	// in: body
+	Body api.FileBlameResponse `json:"body"`
+}
+
+// CodeSymbolList
+// swagger:response CodeSymbolList
+type swaggerCodeSymbolList struct {
+	// in: body
+	Body []api.CodeSymbol `json:"body"`
+}
+
+// CodeSearchResults
+// swagger:response CodeSearchResults
+type swaggerCodeSearchResults struct {
+	// in: body
+	Body api.CodeSearchResults `json:"body"`
+}
+
+// Compare
+// swagger:response Compare
+type swaggerCompare struct {
+	// in:body
+	Body api.Compare `json:"body"`
+}
+
+// RepoBadge
+// swagger:response RepoBadge
+type swaggerRepoBadge struct {
+	// in:body
+	Body api.RepoBadge `json:"body"`
+}
+
+// RepoBadgeList
+// swagger:response RepoBadgeList
+type swaggerRepoBadgeList struct {
+	// in:body
+	Body []api.RepoBadge `json:"body"`
+}
+
+// StalePolicy
+// swagger:response StalePolicy
+type swaggerStalePolicy struct {
+	// in:body
+	Body api.StalePolicy `json:"body"`
+}
+
+// StaleActionList
+// swagger:response StaleActionList
+type swaggerStaleActionList struct {
+	// in:body
+	Body []api.StaleAction `json:"body"`
+}
+
+// ProjectAutomationRule
+// swagger:response ProjectAutomationRule
+type swaggerProjectAutomationRule struct {
+	// in:body
+	Body api.ProjectAutomationRule `json:"body"`
+}
+
+// ProjectAutomationRuleList
+// swagger:response ProjectAutomationRuleList
+type swaggerProjectAutomationRuleList struct {
+	// in:body
+	Body []api.ProjectAutomationRule `json:"body"`
+}
+
+// ShortLink
+// swagger:response ShortLink
+type swaggerResponseShortLink struct {
+	// in:body
+	Body api.ShortLink `json:"body"`
+}
+
+// PullFileTreeNode
+// swagger:response PullFileTreeNode
+type swaggerResponsePullFileTreeNode struct {
+	// in:body
+	Body api.PullFileTreeNode `json:"body"`
+}
+
+// DiffFileList
+// swagger:response DiffFileList
+type swaggerResponseDiffFileList struct {
+	// in:body
+	Body []api.DiffFile `json:"body"`
+}
+
+// RepoSize
+// swagger:response RepoSize
+type swaggerResponseRepoSize struct {
+	// in:body
+	Body api.RepoSize `json:"body"`
+}
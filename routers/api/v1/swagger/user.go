@@ -42,3 +42,31 @@ type swaggerResponseUserHeatmapData struct {
 	// in:body
 	Body []models.UserHeatmapData `json:"body"`
 }
+
+// SavedReply
+// swagger:response SavedReply
+type swaggerResponseSavedReply struct {
+	// in:body
+	Body api.SavedReply `json:"body"`
+}
+
+// SavedReplyList
+// swagger:response SavedReplyList
+type swaggerResponseSavedReplyList struct {
+	// in:body
+	Body []api.SavedReply `json:"body"`
+}
+
+// StorageQuota
+// swagger:response StorageQuota
+type swaggerResponseStorageQuota struct {
+	// in:body
+	Body api.StorageQuota `json:"body"`
+}
+
+// TwoFactorStatus
+// swagger:response TwoFactorStatus
+type swaggerResponseTwoFactorStatus struct {
+	// in:body
+	Body api.TwoFactorStatus `json:"body"`
+}
@@ -51,10 +51,9 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{
-			ID:             tokens[i].ID,
-			Name:           tokens[i].Name,
-			TokenLastEight: tokens[i].TokenLastEight,
+		if apiTokens[i], err = convert.ToAccessToken(tokens[i]); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToAccessToken", err)
+			return
 		}
 	}
 	ctx.JSON(http.StatusOK, &apiTokens)
@@ -78,19 +77,28 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 	// - name: accessToken
 	//   in: body
 	//   schema:
-	//     type: object
-	//     required:
-	//       - name
-	//     properties:
-	//       name:
-	//         type: string
+	//     "$ref": "#/definitions/CreateAccessTokenOption"
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/AccessToken"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	scope, err := models.NormalizeAccessTokenScope(form.Scopes)
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "NormalizeAccessTokenScope", err)
+		return
+	}
 
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: scope,
+	}
+	if !resolveAccessTokenRepos(ctx, t, form.Repositories, form.Owners) {
+		return
 	}
 
 	exist, err := models.AccessTokenByNameExists(t)
@@ -107,19 +115,77 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, &api.AccessToken{
-		Name:           t.Name,
-		Token:          t.Token,
-		ID:             t.ID,
-		TokenLastEight: t.TokenLastEight,
-	})
+	apiToken, err := convert.ToAccessToken(t)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToAccessToken", err)
+		return
+	}
+	apiToken.Token = t.Token
+	ctx.JSON(http.StatusCreated, apiToken)
 }
 
-// DeleteAccessToken delete access tokens
-func DeleteAccessToken(ctx *context.APIContext) {
-	// swagger:operation DELETE /users/{username}/tokens/{token} user userDeleteAccessToken
+// resolveAccessTokenRepos restricts the token to the repositories and the owners given by their names,
+// it responds with an error if one of them doesn't exist
+func resolveAccessTokenRepos(ctx *context.APIContext, t *models.AccessToken, repoNames, ownerNames []string) bool {
+	repoIDs, ownerIDs, err := models.ResolveAccessTokenRepos(ctx.User, repoNames, ownerNames)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) || models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ResolveAccessTokenRepos", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ResolveAccessTokenRepos", err)
+		}
+		return false
+	}
+	t.RepoIDs = repoIDs
+	t.OwnerIDs = ownerIDs
+	return true
+}
+
+// getAccessToken returns the access token of the user identified by the ":id" parameter, its ID or
+// if not available its name, it responds with an error if the token can't be found
+func getAccessToken(ctx *context.APIContext) *models.AccessToken {
+	token := ctx.Params(":id")
+	tokenID, _ := strconv.ParseInt(token, 0, 64)
+
+	if tokenID == 0 {
+		tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{
+			Name:   token,
+			UserID: ctx.User.ID,
+		})
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ListAccessTokens", err)
+			return nil
+		}
+
+		switch len(tokens) {
+		case 0:
+			ctx.NotFound()
+			return nil
+		case 1:
+			return tokens[0]
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "ListAccessTokens", fmt.Errorf("multible matches for token name '%s'", token))
+			return nil
+		}
+	}
+
+	t, err := models.GetAccessTokenByID(tokenID, ctx.User.ID)
+	if err != nil {
+		if models.IsErrAccessTokenNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAccessTokenByID", err)
+		}
+		return nil
+	}
+	return t
+}
+
+// GetAccessToken get an access token
+func GetAccessToken(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/tokens/{token} user userGetAccessToken
 	// ---
-	// summary: delete an access token
+	// summary: Get an access token
 	// produces:
 	// - application/json
 	// parameters:
@@ -130,45 +196,158 @@ func DeleteAccessToken(ctx *context.APIContext) {
 	//   required: true
 	// - name: token
 	//   in: path
-	//   description: token to be deleted, identified by ID and if not available by name
+	//   description: token to get, identified by ID and if not available by name
 	//   type: string
 	//   required: true
 	// responses:
-	//   "204":
-	//     "$ref": "#/responses/empty"
+	//   "200":
+	//     "$ref": "#/responses/AccessToken"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
 	//   "422":
 	//     "$ref": "#/responses/error"
 
-	token := ctx.Params(":id")
-	tokenID, _ := strconv.ParseInt(token, 0, 64)
+	t := getAccessToken(ctx)
+	if ctx.Written() {
+		return
+	}
 
-	if tokenID == 0 {
-		tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{
-			Name:   token,
-			UserID: ctx.User.ID,
-		})
+	apiToken, err := convert.ToAccessToken(t)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToAccessToken", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiToken)
+}
+
+// EditAccessToken edit the name, the scopes and the repositories of an access token
+func EditAccessToken(ctx *context.APIContext, form api.EditAccessTokenOption) {
+	// swagger:operation PATCH /users/{username}/tokens/{token} user userEditAccessToken
+	// ---
+	// summary: Edit the name, the scopes and the repositories of an access token
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: token
+	//   in: path
+	//   description: token to edit, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditAccessTokenOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AccessToken"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	t := getAccessToken(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil && *form.Name != t.Name {
+		t.Name = *form.Name
+		if len(t.Name) == 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "Name", errors.New("access token name is required"))
+			return
+		}
+		exist, err := models.AccessTokenByNameExists(t)
 		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "ListAccessTokens", err)
+			ctx.InternalServerError(err)
 			return
 		}
-
-		switch len(tokens) {
-		case 0:
-			ctx.NotFound()
+		if exist {
+			ctx.Error(http.StatusBadRequest, "AccessTokenByNameExists", errors.New("access token name has been used already"))
 			return
-		case 1:
-			tokenID = tokens[0].ID
-		default:
-			ctx.Error(http.StatusUnprocessableEntity, "DeleteAccessTokenByID", fmt.Errorf("multible matches for token name '%s'", token))
+		}
+	}
+	if form.Scopes != nil {
+		scope, err := models.NormalizeAccessTokenScope(*form.Scopes)
+		if err != nil {
+			ctx.Error(http.StatusBadRequest, "NormalizeAccessTokenScope", err)
 			return
 		}
+		t.Scope = scope
 	}
-	if tokenID == 0 {
-		ctx.Error(http.StatusInternalServerError, "Invalid TokenID", nil)
+	if form.Repositories != nil || form.Owners != nil {
+		repoNames, err := t.RepoNames()
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "RepoNames", err)
+			return
+		}
+		ownerNames, err := t.OwnerNames()
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "OwnerNames", err)
+			return
+		}
+		if form.Repositories != nil {
+			repoNames = *form.Repositories
+		}
+		if form.Owners != nil {
+			ownerNames = *form.Owners
+		}
+		if !resolveAccessTokenRepos(ctx, t, repoNames, ownerNames) {
+			return
+		}
+	}
+
+	if err := models.UpdateAccessToken(t); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateAccessToken", err)
+		return
+	}
+
+	apiToken, err := convert.ToAccessToken(t)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToAccessToken", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiToken)
+}
+
+// DeleteAccessToken delete access tokens
+func DeleteAccessToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /users/{username}/tokens/{token} user userDeleteAccessToken
+	// ---
+	// summary: delete an access token
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: token
+	//   in: path
+	//   description: token to be deleted, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	t := getAccessToken(ctx)
+	if ctx.Written() {
 		return
 	}
 
-	if err := models.DeleteAccessTokenByID(tokenID, ctx.User.ID); err != nil {
+	if err := models.DeleteAccessTokenByID(t.ID, ctx.User.ID); err != nil {
 		if models.IsErrAccessTokenNotExist(err) {
 			ctx.NotFound()
 		} else {
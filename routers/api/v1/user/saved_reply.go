@@ -0,0 +1,151 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSavedReplies list the saved replies of the authenticated user
+func ListSavedReplies(ctx *context.APIContext) {
+	// swagger:operation GET /user/replies user userCurrentListSavedReplies
+	// ---
+	// summary: List the saved replies of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: include_orgs
+	//   in: query
+	//   description: include the replies shared by the organizations of the user, the replies are not paginated then
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReplyList"
+
+	if ctx.QueryBool("include_orgs") {
+		replies, err := models.GetAvailableSavedReplies(ctx.User.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetAvailableSavedReplies", err)
+			return
+		}
+		ctx.JSON(http.StatusOK, convert.ToSavedReplyList(replies))
+		return
+	}
+	utils.ListSavedReplies(ctx, ctx.User)
+}
+
+// CreateSavedReply create a saved reply of the authenticated user
+func CreateSavedReply(ctx *context.APIContext, form api.CreateSavedReplyOption) {
+	// swagger:operation POST /user/replies user userCurrentCreateSavedReply
+	// ---
+	// summary: Create a saved reply of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateSavedReplyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SavedReply"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateSavedReply(ctx, ctx.User, &form)
+}
+
+// GetSavedReply get a saved reply of the authenticated user
+func GetSavedReply(ctx *context.APIContext) {
+	// swagger:operation GET /user/replies/{id} user userCurrentGetSavedReply
+	// ---
+	// summary: Get a saved reply of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetSavedReply(ctx, ctx.User)
+}
+
+// EditSavedReply edit a saved reply of the authenticated user
+func EditSavedReply(ctx *context.APIContext, form api.EditSavedReplyOption) {
+	// swagger:operation PATCH /user/replies/{id} user userCurrentEditSavedReply
+	// ---
+	// summary: Edit a saved reply of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSavedReplyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditSavedReply(ctx, ctx.User, &form)
+}
+
+// DeleteSavedReply delete a saved reply of the authenticated user
+func DeleteSavedReply(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/replies/{id} user userCurrentDeleteSavedReply
+	// ---
+	// summary: Delete a saved reply of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteSavedReply(ctx, ctx.User)
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+)
+
+// GetTwoFactorStatus get the compliance of the authenticated user with the two-factor authentication requirements
+func GetTwoFactorStatus(ctx *context.APIContext) {
+	// swagger:operation GET /user/two_factor_status user userGetTwoFactorStatus
+	// ---
+	// summary: Get the compliance of the authenticated user with the two-factor authentication requirements
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TwoFactorStatus"
+
+	status, err := models.GetTwoFactorStatus(ctx.User)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTwoFactorStatus(status))
+}
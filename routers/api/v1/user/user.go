@@ -68,10 +68,7 @@ func Search(ctx *context.APIContext) {
 
 	users, maxResults, err := models.SearchUsers(opts)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"ok":    false,
-			"error": err.Error(),
-		})
+		ctx.Error(http.StatusInternalServerError, "SearchUsers", err)
 		return
 	}
 
@@ -159,7 +156,7 @@ func GetUserHeatmapData(ctx *context.APIContext) {
 	user, err := models.GetUserByName(ctx.Params(":username"))
 	if err != nil {
 		if models.IsErrUserNotExist(err) {
-			ctx.Status(http.StatusNotFound)
+			ctx.NotFound()
 		} else {
 			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
 		}
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// DecodeAvatar decodes an avatar image encoded in base64 and checks its size and type
+func DecodeAvatar(image string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(image)
+	if err != nil {
+		return nil, fmt.Errorf("image is not encoded in base64: %v", err)
+	}
+	if int64(len(data)) > setting.Avatar.MaxFileSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", setting.Avatar.MaxFileSize)
+	}
+	if !base.IsImageFile(data) {
+		return nil, errors.New("image is not an image file")
+	}
+	return data, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -66,6 +67,10 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid content type")
 		return false
 	}
+	if form.PayloadVersion != 0 && !models.IsValidWebhookPayloadVersion(form.PayloadVersion) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid payload version")
+		return false
+	}
 	return true
 }
 
@@ -129,11 +134,13 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				PullRequestSync:      pullHook(form.Events, string(models.HookEventPullRequestSync)),
 				Repository:           com.IsSliceContainsStr(form.Events, string(models.HookEventRepository)),
 				Release:              com.IsSliceContainsStr(form.Events, string(models.HookEventRelease)),
+				ReviewEnvironment:    com.IsSliceContainsStr(form.Events, string(models.HookEventReviewEnvironment)),
 			},
 			BranchFilter: form.BranchFilter,
 		},
-		IsActive:     form.Active,
-		HookTaskType: models.ToHookTaskType(form.Type),
+		IsActive:       form.Active,
+		HookTaskType:   models.ToHookTaskType(form.Type),
+		PayloadVersion: form.PayloadVersion,
 	}
 	if w.HookTaskType == models.SLACK {
 		channel, ok := form.Config["channel"]
@@ -164,9 +171,14 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
 		return nil, false
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.Error(http.StatusInternalServerError, "CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateWebhook", err)
+		}
 		return nil, false
 	}
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	return w, true
 }
 
@@ -255,6 +267,7 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest))
 	w.Repository = com.IsSliceContainsStr(form.Events, string(models.HookEventRepository))
 	w.Release = com.IsSliceContainsStr(form.Events, string(models.HookEventRelease))
+	w.ReviewEnvironment = com.IsSliceContainsStr(form.Events, string(models.HookEventReviewEnvironment))
 	w.BranchFilter = form.BranchFilter
 
 	if err := w.UpdateEvent(); err != nil {
@@ -266,9 +279,22 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 		w.IsActive = *form.Active
 	}
 
+	if form.PayloadVersion != nil {
+		if !models.IsValidWebhookPayloadVersion(*form.PayloadVersion) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "Invalid payload version")
+			return false
+		}
+		w.PayloadVersion = *form.PayloadVersion
+	}
+
 	if err := models.UpdateWebhook(w); err != nil {
-		ctx.Error(http.StatusInternalServerError, "UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateWebhook", err)
+		}
 		return false
 	}
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	return true
 }
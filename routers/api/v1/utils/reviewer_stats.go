@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ListReviewerStats list the review activity of the reviewers of some repositories over the period
+// of the since and before query parameters
+func ListReviewerStats(ctx *context.APIContext, repoIDs []int64) {
+	before, since, err := GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	stats, err := models.GetReviewerStats(&models.ReviewerStatsOptions{
+		RepoIDs: repoIDs,
+		Since:   timeutil.TimeStamp(since),
+		Before:  timeutil.TimeStamp(before),
+		Doer:    ctx.User,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewerStats", err)
+		return
+	}
+	apiStats := make([]*api.ReviewerStats, 0, len(stats))
+	for _, s := range stats {
+		apiStats = append(apiStats, convert.ToReviewerStats(s))
+	}
+	ctx.JSON(http.StatusOK, apiStats)
+}
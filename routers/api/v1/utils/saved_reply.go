@@ -0,0 +1,99 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getSavedReply returns the saved reply of the owner of the id path parameter. If there is an
+// error, write to `ctx` accordingly
+func getSavedReply(ctx *context.APIContext, owner *models.User) *models.SavedReply {
+	reply, err := models.GetSavedReplyByID(owner.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrSavedReplyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSavedReplyByID", err)
+		}
+		return nil
+	}
+	reply.Owner = owner
+	return reply
+}
+
+// ListSavedReplies list the saved replies of a user or of an organization
+func ListSavedReplies(ctx *context.APIContext, owner *models.User) {
+	replies, err := models.GetSavedReplies(owner.ID, GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSavedReplies", err)
+		return
+	}
+	for _, reply := range replies {
+		reply.Owner = owner
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReplyList(replies))
+}
+
+// CreateSavedReply create a saved reply of a user or of an organization
+func CreateSavedReply(ctx *context.APIContext, owner *models.User, form *api.CreateSavedReplyOption) {
+	reply := &models.SavedReply{
+		OwnerID: owner.ID,
+		Owner:   owner,
+		Title:   form.Title,
+		Content: form.Content,
+	}
+	if err := models.CreateSavedReply(reply); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateSavedReply", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToSavedReply(reply))
+}
+
+// GetSavedReply get a saved reply of a user or of an organization
+func GetSavedReply(ctx *context.APIContext, owner *models.User) {
+	reply := getSavedReply(ctx, owner)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReply(reply))
+}
+
+// EditSavedReply edit a saved reply of a user or of an organization
+func EditSavedReply(ctx *context.APIContext, owner *models.User, form *api.EditSavedReplyOption) {
+	reply := getSavedReply(ctx, owner)
+	if ctx.Written() {
+		return
+	}
+	if form.Title != nil && len(*form.Title) > 0 {
+		reply.Title = *form.Title
+	}
+	if form.Content != nil && len(*form.Content) > 0 {
+		reply.Content = *form.Content
+	}
+	if err := models.UpdateSavedReply(reply); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateSavedReply", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReply(reply))
+}
+
+// DeleteSavedReply delete a saved reply of a user or of an organization
+func DeleteSavedReply(ctx *context.APIContext, owner *models.User) {
+	if err := models.DeleteSavedReply(owner.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrSavedReplyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteSavedReply", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -299,87 +299,70 @@ func ExploreCode(ctx *context.Context) {
 		page = 1
 	}
 
-	var (
-		repoIDs []int64
-		err     error
-		isAdmin bool
-	)
-	if ctx.User != nil {
-		isAdmin = ctx.User.IsAdmin
-	}
-
-	// guest user or non-admin user
-	if ctx.User == nil || !isAdmin {
-		repoIDs, err = models.FindUserAccessibleRepoIDs(ctx.User)
+	// the search may be restricted to the repositories of a user or an organization
+	var ownerID int64
+	if ownerName := ctx.QueryTrim("owner"); len(ownerName) > 0 {
+		owner, err := models.GetUserByName(ownerName)
 		if err != nil {
-			ctx.ServerError("SearchResults", err)
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByName", err)
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
 			return
 		}
-	}
-
-	var (
-		total                 int
-		searchResults         []*code_indexer.Result
-		searchResultLanguages []*code_indexer.SearchResultLanguages
-	)
-
-	// if non-admin login user, we need check UnitTypeCode at first
-	if ctx.User != nil && len(repoIDs) > 0 {
-		repoMaps, err := models.GetRepositoriesMapByIDs(repoIDs)
-		if err != nil {
-			ctx.ServerError("SearchResults", err)
+		if owner.IsOrganization() && !models.HasOrgVisible(owner, ctx.User) {
+			ctx.NotFound("HasOrgVisible", nil)
 			return
 		}
+		ownerID = owner.ID
+		ctx.Data["OwnerName"] = owner.Name
+	}
 
-		var rightRepoMap = make(map[int64]*models.Repository, len(repoMaps))
-		repoIDs = make([]int64, 0, len(repoMaps))
-		for id, repo := range repoMaps {
-			if repo.CheckUnitUser(ctx.User, models.UnitTypeCode) {
-				rightRepoMap[id] = repo
-				repoIDs = append(repoIDs, id)
-			}
-		}
-
-		ctx.Data["RepoMaps"] = rightRepoMap
+	opts := &code_indexer.SearchOptions{
+		Language:        language,
+		IsRegex:         ctx.QueryBool("regex"),
+		IsCaseSensitive: ctx.QueryBool("case_sensitive"),
+		IsWholeWord:     ctx.QueryBool("whole_word"),
+		Page:            page,
+		PageSize:        setting.UI.RepoSearchPagingNum,
+	}
+	opts.ParseKeyword(keyword)
 
-		total, searchResults, searchResultLanguages, err = code_indexer.PerformSearch(repoIDs, language, keyword, page, setting.UI.RepoSearchPagingNum)
-		if err != nil {
-			ctx.ServerError("SearchResults", err)
-			return
-		}
-		// if non-login user or isAdmin, no need to check UnitTypeCode
-	} else if (ctx.User == nil && len(repoIDs) > 0) || isAdmin {
-		total, searchResults, searchResultLanguages, err = code_indexer.PerformSearch(repoIDs, language, keyword, page, setting.UI.RepoSearchPagingNum)
-		if err != nil {
-			ctx.ServerError("SearchResults", err)
-			return
-		}
+	total, searchResults, searchResultLanguages, err := code_indexer.PerformUserSearch(ctx.User, ownerID, opts)
+	if code_indexer.IsErrInvalidSearchPattern(err) {
+		ctx.Data["SearchError"] = ctx.Tr("repo.search.invalid_pattern", err.(code_indexer.ErrInvalidSearchPattern).Err.Error())
+	} else if err != nil {
+		ctx.ServerError("SearchResults", err)
+		return
+	}
 
-		var loadRepoIDs = make([]int64, 0, len(searchResults))
-		for _, result := range searchResults {
-			var find bool
-			for _, id := range loadRepoIDs {
-				if id == result.RepoID {
-					find = true
-					break
-				}
-			}
-			if !find {
-				loadRepoIDs = append(loadRepoIDs, result.RepoID)
+	var loadRepoIDs = make([]int64, 0, len(searchResults))
+	for _, result := range searchResults {
+		var find bool
+		for _, id := range loadRepoIDs {
+			if id == result.RepoID {
+				find = true
+				break
 			}
 		}
-
-		repoMaps, err := models.GetRepositoriesMapByIDs(loadRepoIDs)
-		if err != nil {
-			ctx.ServerError("SearchResults", err)
-			return
+		if !find {
+			loadRepoIDs = append(loadRepoIDs, result.RepoID)
 		}
+	}
 
-		ctx.Data["RepoMaps"] = repoMaps
+	repoMaps, err := models.GetRepositoriesMapByIDs(loadRepoIDs)
+	if err != nil {
+		ctx.ServerError("SearchResults", err)
+		return
 	}
+	ctx.Data["RepoMaps"] = repoMaps
 
 	ctx.Data["Keyword"] = keyword
 	ctx.Data["Language"] = language
+	ctx.Data["IsRegex"] = opts.IsRegex
+	ctx.Data["IsCaseSensitive"] = opts.IsCaseSensitive
+	ctx.Data["IsWholeWord"] = opts.IsWholeWord
 	ctx.Data["SearchResults"] = searchResults
 	ctx.Data["SearchResultLanguages"] = searchResultLanguages
 	ctx.Data["RequireHighlightJS"] = true
@@ -388,6 +371,10 @@ func ExploreCode(ctx *context.Context) {
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "l", "Language")
+	pager.AddParam(ctx, "owner", "OwnerName")
+	pager.AddParam(ctx, "regex", "IsRegex")
+	pager.AddParam(ctx, "case_sensitive", "IsCaseSensitive")
+	pager.AddParam(ctx, "whole_word", "IsWholeWord")
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(200, tplExploreCode)
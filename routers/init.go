@@ -21,13 +21,18 @@ import (
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
+	symbols_indexer "code.gitea.io/gitea/modules/indexer/symbols"
+	"code.gitea.io/gitea/modules/legal"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/external"
 	repo_migrations "code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/options"
+	"code.gitea.io/gitea/modules/recalculation"
+	"code.gitea.io/gitea/modules/repofiles"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/signing"
 	"code.gitea.io/gitea/modules/ssh"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/svg"
@@ -163,6 +168,9 @@ func GlobalInit(ctx context.Context) {
 	if err := git.Init(ctx); err != nil {
 		log.Fatal("Git module init failed: %v", err)
 	}
+	if err := signing.Init(); err != nil {
+		log.Fatal("Failed to initialize the signing backend: %v", err)
+	}
 	setting.CheckLFSVersion()
 	log.Trace("AppPath: %s", setting.AppPath)
 	log.Trace("AppWorkPath: %s", setting.AppWorkPath)
@@ -177,6 +185,7 @@ func GlobalInit(ctx context.Context) {
 	highlight.NewContext()
 	external.RegisterParsers()
 	markup.Init()
+	markup.RegisterCodePreviewProvider(repofiles.GetCodePreview)
 	if err := initDBEngine(ctx); err == nil {
 		log.Info("ORM engine initialization successful!")
 	} else {
@@ -188,6 +197,9 @@ func GlobalInit(ctx context.Context) {
 	}
 
 	models.NewRepoContext()
+	if err := legal.Init(); err != nil {
+		log.Fatal("Failed to initialize the legal pages: %v", err)
+	}
 
 	// Booting long running goroutines.
 	cron.NewContext()
@@ -196,6 +208,9 @@ func GlobalInit(ctx context.Context) {
 	if err := stats_indexer.Init(); err != nil {
 		log.Fatal("Failed to initialize repository stats indexer queue: %v", err)
 	}
+	if err := symbols_indexer.Init(); err != nil {
+		log.Fatal("Failed to initialize repository symbols indexer queue: %v", err)
+	}
 	mirror_service.InitSyncMirrors()
 	webhook.InitDeliverHooks()
 	if err := pull_service.Init(); err != nil {
@@ -207,6 +222,9 @@ func GlobalInit(ctx context.Context) {
 	if err := repo_migrations.Init(); err != nil {
 		log.Fatal("Failed to initialize repository migrations: %v", err)
 	}
+	if err := recalculation.Init(); err != nil {
+		log.Fatal("Failed to resume the recalculations: %v", err)
+	}
 	eventsource.GetManager().Init()
 
 	if setting.EnableSQLite3 {
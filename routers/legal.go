@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package routers
+
+import (
+	"bytes"
+	"html/template"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/legal"
+	"code.gitea.io/gitea/modules/templates"
+)
+
+// tplLegal legal page template
+const tplLegal base.TplName = "legal"
+
+// LegalPage render a legal page of the instance in the language of the user
+func LegalPage(ctx *context.Context) {
+	page := legal.Page(ctx.Params(":name"))
+	if page == nil {
+		ctx.NotFound("LegalPage", nil)
+		return
+	}
+
+	tmpl := template.New(page.Name)
+	for _, funcs := range templates.NewFuncMap() {
+		tmpl.Funcs(funcs)
+	}
+	if _, err := tmpl.Parse(legal.Content(page.Name, ctx.Locale.Language())); err != nil {
+		ctx.ServerError("Parse", err)
+		return
+	}
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, ctx.Data); err != nil {
+		ctx.ServerError("Execute", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("legal." + page.Name)
+	ctx.Data["LegalPage"] = page
+	ctx.Data["Content"] = template.HTML(content.String())
+	ctx.HTML(200, tplLegal)
+}
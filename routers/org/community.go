@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// tplCommunity template for organization community health report page
+	tplCommunity base.TplName = "org/community"
+)
+
+// Community render the report of the licenses and codes of conduct of the repositories of an organization
+func Community(ctx *context.Context) {
+	org := ctx.Org.Organization
+	ctx.Data["Title"] = ctx.Tr("org.community")
+	ctx.Data["PageIsOrgCommunity"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	opts := &models.SearchRepoOptions{
+		ListOptions: models.ListOptions{
+			PageSize: setting.UI.User.RepoPagingNum,
+			Page:     page,
+		},
+		OwnerID: org.ID,
+		Private: ctx.IsSigned,
+		Actor:   ctx.User,
+	}
+
+	licenses, err := models.CountSearchRepositoryLicenses(opts)
+	if err != nil {
+		ctx.ServerError("CountSearchRepositoryLicenses", err)
+		return
+	}
+	var total, withLicense int64
+	for _, license := range licenses {
+		total += license.Count
+		if license.License != "" {
+			withLicense += license.Count
+		}
+	}
+
+	opts.HasCodeOfConduct = util.OptionalBoolTrue
+	_, withCodeOfConduct, err := models.SearchRepositoryByCondition(opts, models.SearchRepositoryCondition(opts), false)
+	if err != nil {
+		ctx.ServerError("SearchRepositoryByCondition", err)
+		return
+	}
+
+	opts.HasCodeOfConduct = util.OptionalBoolNone
+	if ctx.Query("has_code_of_conduct") != "" {
+		opts.HasCodeOfConduct = util.OptionalBoolOf(ctx.QueryBool("has_code_of_conduct"))
+	}
+	opts.License = ctx.Query("license")
+	repos, count, err := models.SearchRepository(opts)
+	if err != nil {
+		ctx.ServerError("SearchRepository", err)
+		return
+	}
+
+	ctx.Data["Licenses"] = licenses
+	ctx.Data["Total"] = total
+	ctx.Data["WithLicense"] = withLicense
+	ctx.Data["WithCodeOfConduct"] = withCodeOfConduct
+	ctx.Data["Repos"] = repos
+	ctx.Data["License"] = opts.License
+	ctx.Data["HasCodeOfConduct"] = ctx.Query("has_code_of_conduct")
+
+	pager := context.NewPagination(int(count), setting.UI.User.RepoPagingNum, page, 5)
+	pager.SetDefaultParams(ctx)
+	pager.AddParam(ctx, "license", "License")
+	pager.AddParam(ctx, "has_code_of_conduct", "HasCodeOfConduct")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplCommunity)
+}
@@ -9,10 +9,12 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	userSetting "code.gitea.io/gitea/routers/user/setting"
 )
@@ -34,6 +36,7 @@ func Settings(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["CurrentVisibility"] = ctx.Org.Organization.Visibility
 	ctx.Data["RepoAdminChangeTeamAccess"] = ctx.Org.Organization.RepoAdminChangeTeamAccess
+	ctx.Data["RequireTwoFactor"] = ctx.Org.Organization.RequireTwoFactor
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -79,6 +82,7 @@ func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 
 	if ctx.User.IsAdmin {
 		org.MaxRepoCreation = form.MaxRepoCreation
+		org.MaxStorageSize = form.MaxStorageSize
 	}
 
 	org.FullName = form.FullName
@@ -86,6 +90,7 @@ func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 	org.Website = form.Website
 	org.Location = form.Location
 	org.RepoAdminChangeTeamAccess = form.RepoAdminChangeTeamAccess
+	org.RequireTwoFactor = form.RequireTwoFactor
 
 	visibilityChanged := form.Visibility != org.Visibility
 	org.Visibility = form.Visibility
@@ -155,11 +160,15 @@ func SettingsDelete(ctx *context.Context) {
 			if models.IsErrUserOwnRepos(err) {
 				ctx.Flash.Error(ctx.Tr("form.org_still_own_repo"))
 				ctx.Redirect(ctx.Org.OrgLink + "/settings/delete")
+			} else if models.IsErrUnderLegalHold(err) {
+				ctx.Flash.Error(ctx.Tr("form.under_legal_hold"))
+				ctx.Redirect(ctx.Org.OrgLink + "/settings/delete")
 			} else {
 				ctx.ServerError("DeleteOrganization", err)
 			}
 		} else {
 			log.Trace("Organization deleted: %s", org.Name)
+			notification.NotifyDeleteOrganization(ctx.User, org)
 			ctx.Redirect(setting.AppSubURL + "/")
 		}
 		return
@@ -187,9 +196,11 @@ func Webhooks(ctx *context.Context) {
 
 // DeleteWebhook response for delete webhook
 func DeleteWebhook(ctx *context.Context) {
-	if err := models.DeleteWebhookByOrgID(ctx.Org.Organization.ID, ctx.QueryInt64("id")); err != nil {
+	id := ctx.QueryInt64("id")
+	if err := models.DeleteWebhookByOrgID(ctx.Org.Organization.ID, id); err != nil {
 		ctx.Flash.Error("DeleteWebhookByOrgID: " + err.Error())
 	} else {
+		audit.RecordWebhook(models.AuditActionWebhookDelete, ctx.User, ctx.RemoteAddr(), &models.Webhook{ID: id, OrgID: ctx.Org.Organization.ID})
 		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
 	}
 
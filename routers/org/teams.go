@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
@@ -64,15 +65,24 @@ func TeamsAction(ctx *context.Context) {
 			ctx.Error(404)
 			return
 		}
-		err = ctx.Org.Team.AddMember(ctx.User.ID)
+		if err = ctx.Org.Team.AddMember(ctx.User.ID); err == nil {
+			auditTeamMember(ctx, models.AuditActionTeamMemberAdd, ctx.User.Name)
+		}
 	case "leave":
-		err = ctx.Org.Team.RemoveMember(ctx.User.ID)
+		if err = ctx.Org.Team.RemoveMember(ctx.User.ID); err == nil {
+			auditTeamMember(ctx, models.AuditActionTeamMemberRemove, ctx.User.Name)
+		}
 	case "remove":
 		if !ctx.Org.IsOwner {
 			ctx.Error(404)
 			return
 		}
-		err = ctx.Org.Team.RemoveMember(uid)
+		if err = ctx.Org.Team.RemoveMember(uid); err == nil {
+			u, _ := models.GetUserByID(uid)
+			if u != nil {
+				auditTeamMember(ctx, models.AuditActionTeamMemberRemove, u.Name)
+			}
+		}
 		page = "team"
 	case "add":
 		if !ctx.Org.IsOwner {
@@ -101,7 +111,9 @@ func TeamsAction(ctx *context.Context) {
 		if ctx.Org.Team.IsMember(u.ID) {
 			ctx.Flash.Error(ctx.Tr("org.teams.add_duplicate_users"))
 		} else {
-			err = ctx.Org.Team.AddMember(u.ID)
+			if err = ctx.Org.Team.AddMember(u.ID); err == nil {
+				auditTeamMember(ctx, models.AuditActionTeamMemberAdd, u.Name)
+			}
 		}
 
 		page = "team"
@@ -130,6 +142,15 @@ func TeamsAction(ctx *context.Context) {
 	}
 }
 
+// auditTeamMember records a change of the members of the current team in the audit log
+func auditTeamMember(ctx *context.Context, action models.AuditAction, memberName string) {
+	verb := "Added %s to"
+	if action == models.AuditActionTeamMemberRemove {
+		verb = "Removed %s from"
+	}
+	audit.Record(action, ctx.User, ctx.RemoteAddr(), ctx.Org.Organization, verb+" the team %s", memberName, ctx.Org.Team.Name)
+}
+
 // TeamsRepoAction operate team's repository
 func TeamsRepoAction(ctx *context.Context) {
 	if !ctx.Org.IsOwner {
@@ -160,6 +181,23 @@ func TeamsRepoAction(ctx *context.Context) {
 		err = ctx.Org.Team.AddAllRepositories()
 	case "removeall":
 		err = ctx.Org.Team.RemoveAllRepositories()
+	case "watch":
+		var repo *models.Repository
+		repo, err = models.GetRepositoryByID(com.StrTo(ctx.Query("repoid")).MustInt64())
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound("GetRepositoryByID", err)
+				return
+			}
+			ctx.ServerError("GetRepositoryByID", err)
+			return
+		}
+		if err = ctx.Org.Team.AddWatchRule(repo); models.IsErrTeamRepoNotExist(err) {
+			ctx.NotFound("AddWatchRule", err)
+			return
+		}
+	case "unwatch":
+		err = ctx.Org.Team.RemoveWatchRule(com.StrTo(ctx.Query("repoid")).MustInt64())
 	}
 
 	if err != nil {
@@ -262,6 +300,16 @@ func TeamRepositories(ctx *context.Context) {
 		ctx.ServerError("GetRepositories", err)
 		return
 	}
+	watchedRepoIDs, err := ctx.Org.Team.GetWatchRuleRepoIDs()
+	if err != nil {
+		ctx.ServerError("GetWatchRuleRepoIDs", err)
+		return
+	}
+	watchedRepos := make(map[int64]bool, len(watchedRepoIDs))
+	for _, repoID := range watchedRepoIDs {
+		watchedRepos[repoID] = true
+	}
+	ctx.Data["WatchedRepos"] = watchedRepos
 	ctx.HTML(200, tplTeamRepositories)
 }
 
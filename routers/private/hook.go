@@ -117,6 +117,52 @@ func isErrUnverifiedCommit(err error) bool {
 	return ok
 }
 
+// findCommitMissingSignOff returns the first pushed commit, other than merge commits, which isn't signed off by its author
+func findCommitMissingSignOff(oldCommitID, newCommitID string, repo *models.Repository, env []string) (string, error) {
+	args := []string{"log", "-z", "--no-merges", "--format=%H%n%ae%n%B", newCommitID}
+	if oldCommitID == git.EmptySHA {
+		args = append(args, "--not", "--all")
+	} else {
+		args = append(args, "^"+oldCommitID)
+	}
+	output, err := git.NewCommand(args...).RunInDirWithEnv(repo.RepoPath(), env)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range strings.Split(output, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(entry, "\n"), "\n", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		commit := &git.Commit{
+			Author: &git.Signature{Email: fields[1]},
+		}
+		if len(fields) == 3 {
+			commit.CommitMessage = fields[2]
+		}
+		if !commit.IsSignedOff() {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// checkPushStorageQuota checks the storage quota of the owner of a repository with the size of the pushed objects
+func checkPushStorageQuota(repo *models.Repository, quarantinePath string) error {
+	if !setting.Quota.Enabled {
+		return nil
+	}
+	size, err := util.GetDirectorySize(quarantinePath)
+	if err != nil || size == 0 {
+		return err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	return models.CheckStorageQuota(repo.Owner, size)
+}
+
 // HookPreReceive checks whether a individual commit is acceptable
 func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 	ownerName := ctx.Params(":owner")
@@ -153,6 +199,22 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 	if opts.GitQuarantinePath != "" {
 		env = append(env,
 			private.GitQuarantinePath+"="+opts.GitQuarantinePath)
+
+		// The pushed objects wait in the quarantine until the push is accepted
+		if err := checkPushStorageQuota(repo, opts.GitQuarantinePath); err != nil {
+			if models.IsErrStorageQuotaExceeded(err) {
+				log.Warn("Forbidden: Push to %-v exceeds the storage quota: %v", repo, err)
+				ctx.JSON(http.StatusForbidden, map[string]interface{}{
+					"err": err.Error(),
+				})
+			} else {
+				log.Error("Unable to check the storage quota of %-v: %v", repo, err)
+				ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"err": err.Error(),
+				})
+			}
+			return
+		}
 	}
 
 	// Iterate across the provided old commit IDs
@@ -236,9 +298,51 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 			}
 		}
 
+		// 4. Enforce linear history
+		if protectBranch.RequireLinearHistory {
+			args := []string{"rev-list", "--max-count=1", "--merges", newCommitID}
+			if oldCommitID == git.EmptySHA {
+				args = append(args, "--not", "--all")
+			} else {
+				args = append(args, "^"+oldCommitID)
+			}
+			output, err := git.NewCommand(args...).RunInDirWithEnv(repo.RepoPath(), env)
+			if err != nil {
+				log.Error("Unable to detect merge commits between: %s and %s in %-v Error: %v", oldCommitID, newCommitID, repo, err)
+				ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"err": fmt.Sprintf("Fail to detect merge commits: %v", err),
+				})
+				return
+			} else if mergeCommit := strings.TrimSpace(output); len(mergeCommit) > 0 {
+				log.Warn("Forbidden: Branch: %s in %-v requires a linear history and merge commit %s was pushed", branchName, repo, mergeCommit)
+				ctx.JSON(http.StatusForbidden, map[string]interface{}{
+					"err": fmt.Sprintf("branch %s requires a linear history and is protected from merge commit %s", branchName, mergeCommit),
+				})
+				return
+			}
+		}
+
+		// 5. Enforce the sign-off of the pushed commits, the commits of merged pull requests are checked before merging
+		if protectBranch.RequireSignOff && opts.ProtectedBranchID == 0 {
+			missingSignOff, err := findCommitMissingSignOff(oldCommitID, newCommitID, repo, env)
+			if err != nil {
+				log.Error("Unable to check the sign-off of commits from %s to %s in %-v: %v", oldCommitID, newCommitID, repo, err)
+				ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"err": fmt.Sprintf("Unable to check the sign-off of commits from %s to %s: %v", oldCommitID, newCommitID, err),
+				})
+				return
+			} else if len(missingSignOff) > 0 {
+				log.Warn("Forbidden: Branch: %s in %-v requires signed off commits and commit %s isn't signed off", branchName, repo, missingSignOff)
+				ctx.JSON(http.StatusForbidden, map[string]interface{}{
+					"err": fmt.Sprintf("branch %s requires signed off commits and commit %s isn't signed off by its author", branchName, missingSignOff),
+				})
+				return
+			}
+		}
+
 		// Now there are several tests which can be overridden:
 		//
-		// 4. Check protected file patterns - this is overridable from the UI
+		// 6. Check protected file patterns - this is overridable from the UI
 		changedProtectedfiles := false
 		protectedFilePath := ""
 
@@ -259,7 +363,7 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 			}
 		}
 
-		// 5. Check if the doer is allowed to push
+		// 7. Check if the doer is allowed to push
 		canPush := false
 		if opts.IsDeployKey {
 			canPush = !changedProtectedfiles && protectBranch.CanPush && (!protectBranch.EnableWhitelist || protectBranch.WhitelistDeployKeys)
@@ -267,11 +371,11 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 			canPush = !changedProtectedfiles && protectBranch.CanUserPush(opts.UserID)
 		}
 
-		// 6. If we're not allowed to push directly
+		// 8. If we're not allowed to push directly
 		if !canPush {
 			// Is this is a merge from the UI/API?
 			if opts.ProtectedBranchID == 0 {
-				// 6a. If we're not merging from the UI/API then there are two ways we got here:
+				// 8a. If we're not merging from the UI/API then there are two ways we got here:
 				//
 				// We are changing a protected file and we're not allowed to do that
 				if changedProtectedfiles {
@@ -289,7 +393,7 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 				})
 				return
 			}
-			// 6b. Merge (from UI or API)
+			// 8b. Merge (from UI or API)
 
 			// Get the PR, user and permissions for the user in the repository
 			pr, err := models.GetPullRequestByID(opts.ProtectedBranchID)
@@ -498,35 +602,63 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 				}
 			}
 
-			if !repo.IsFork && branch == baseRepo.DefaultBranch {
+			// A pull request can be created by the push options, the topic limits it to one of the pushed branches
+			createPull := opts.GitPushOptions.Bool(private.GitPushOptionPullCreate, false) && !opts.IsDeployKey
+			if topic, ok := opts.GitPushOptions[private.GitPushOptionPullTopic]; ok && topic != branch {
+				createPull = false
+			}
+			baseBranch := baseRepo.DefaultBranch
+			if createPull && len(opts.GitPushOptions[private.GitPushOptionPullBase]) > 0 {
+				baseBranch = opts.GitPushOptions[private.GitPushOptionPullBase]
+			}
+
+			if !repo.IsFork && branch == baseBranch {
 				results = append(results, private.HookPostReceiveBranchResult{})
 				continue
 			}
 
-			pr, err := models.GetUnmergedPullRequest(repo.ID, baseRepo.ID, branch, baseRepo.DefaultBranch)
+			pr, err := models.GetUnmergedPullRequest(repo.ID, baseRepo.ID, branch, baseBranch)
 			if err != nil && !models.IsErrPullRequestNotExist(err) {
-				log.Error("Failed to get active PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseRepo.DefaultBranch, err)
+				log.Error("Failed to get active PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseBranch, err)
 				ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
 					Err: fmt.Sprintf(
-						"Failed to get active PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseRepo.DefaultBranch, err),
+						"Failed to get active PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseBranch, err),
 					RepoWasEmpty: wasEmpty,
 				})
 				return
 			}
 
+			var warning string
+			if pr == nil && createPull {
+				pr, warning, err = createPullRequestFromPush(repo, baseRepo, branch, baseBranch, opts)
+				if err != nil {
+					log.Error("Failed to create PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseBranch, err)
+					ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+						Err: fmt.Sprintf(
+							"Failed to create PR in: %-v Branch: %s to: %-v Branch: %s Error: %v", repo, branch, baseRepo, baseBranch, err),
+						RepoWasEmpty: wasEmpty,
+					})
+					return
+				}
+				if pr == nil {
+					baseBranch = baseRepo.DefaultBranch
+				}
+			}
+
 			if pr == nil {
 				if repo.IsFork {
 					branch = fmt.Sprintf("%s:%s", repo.OwnerName, branch)
 				}
 				results = append(results, private.HookPostReceiveBranchResult{
-					Message: setting.Git.PullRequestPushMessage && repo.AllowsPulls(),
+					Message: (setting.Git.PullRequestPushMessage && repo.AllowsPulls()) || len(warning) > 0,
 					Create:  true,
 					Branch:  branch,
-					URL:     fmt.Sprintf("%s/compare/%s...%s", baseRepo.HTMLURL(), util.PathEscapeSegments(baseRepo.DefaultBranch), util.PathEscapeSegments(branch)),
+					URL:     fmt.Sprintf("%s/compare/%s...%s", baseRepo.HTMLURL(), util.PathEscapeSegments(baseBranch), util.PathEscapeSegments(branch)),
+					Warning: warning,
 				})
 			} else {
 				results = append(results, private.HookPostReceiveBranchResult{
-					Message: setting.Git.PullRequestPushMessage && repo.AllowsPulls(),
+					Message: (setting.Git.PullRequestPushMessage && repo.AllowsPulls()) || createPull,
 					Create:  false,
 					Branch:  branch,
 					URL:     fmt.Sprintf("%s/pulls/%d", baseRepo.HTMLURL(), pr.Index),
@@ -540,6 +672,114 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 	})
 }
 
+// createPullRequestFromPush creates a pull request of a pushed branch configured by the push options.
+// The returned warning explains to the pusher why the pull request hasn't been created.
+func createPullRequestFromPush(headRepo, baseRepo *models.Repository, headBranch, baseBranch string, opts private.HookOptions) (*models.PullRequest, string, error) {
+	pusher, err := models.GetUserByID(opts.UserID)
+	if err != nil {
+		return nil, "", fmt.Errorf("GetUserByID: %v", err)
+	}
+	if err := baseRepo.GetOwner(); err != nil {
+		return nil, "", fmt.Errorf("GetOwner: %v", err)
+	}
+	perm, err := models.GetUserRepoPermission(baseRepo, pusher)
+	if err != nil {
+		return nil, "", fmt.Errorf("GetUserRepoPermission: %v", err)
+	}
+	if !baseRepo.AllowsPulls() || !perm.CanRead(models.UnitTypePullRequests) {
+		return nil, fmt.Sprintf("You are not allowed to create a pull request in %s.", baseRepo.FullName()), nil
+	}
+
+	headGitRepo, err := git.OpenRepository(headRepo.RepoPath())
+	if err != nil {
+		return nil, "", fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer headGitRepo.Close()
+
+	if headRepo.ID == baseRepo.ID && !headGitRepo.IsBranchExist(baseBranch) {
+		return nil, fmt.Sprintf("The base branch '%s' does not exist.", baseBranch), nil
+	} else if headRepo.ID != baseRepo.ID {
+		baseGitRepo, err := git.OpenRepository(baseRepo.RepoPath())
+		if err != nil {
+			return nil, "", fmt.Errorf("OpenRepository: %v", err)
+		}
+		exist := baseGitRepo.IsBranchExist(baseBranch)
+		baseGitRepo.Close()
+		if !exist {
+			return nil, fmt.Sprintf("The base branch '%s' does not exist.", baseBranch), nil
+		}
+	}
+
+	compareInfo, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseBranch, headBranch)
+	if err != nil {
+		return nil, "", fmt.Errorf("GetCompareInfo: %v", err)
+	}
+	if compareInfo.Commits.Len() == 0 {
+		return nil, fmt.Sprintf("There are no changes between '%s' and '%s'.", baseBranch, headBranch), nil
+	}
+
+	// Like the compare page, the title and the description default to the message of a single commit
+	title := strings.TrimSpace(opts.GitPushOptions[private.GitPushOptionPullTitle])
+	content := opts.GitPushOptions[private.GitPushOptionPullDescription]
+	if len(title) == 0 {
+		if compareInfo.Commits.Len() == 1 {
+			c := compareInfo.Commits.Front().Value.(*git.Commit)
+			title = strings.TrimSpace(c.Summary())
+			body := strings.Split(strings.TrimSpace(c.Message()), "\n")
+			if len(body) > 1 && len(content) == 0 {
+				content = strings.TrimSpace(strings.Join(body[1:], "\n"))
+			}
+		} else {
+			title = headBranch
+		}
+	}
+
+	// Only the writers of the base repository can label a pull request, like when it is created on the web
+	var labelIDs []int64
+	if names := opts.GitPushOptions[private.GitPushOptionPullLabels]; len(names) > 0 && perm.CanWrite(models.UnitTypePullRequests) {
+		labelNames := make([]string, 0, 5)
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				labelNames = append(labelNames, name)
+			}
+		}
+		if labelIDs, err = models.GetLabelIDsInRepoByNames(baseRepo.ID, labelNames); err != nil {
+			return nil, "", fmt.Errorf("GetLabelIDsInRepoByNames: %v", err)
+		}
+		if baseRepo.Owner.IsOrganization() {
+			orgLabelIDs, err := models.GetLabelIDsInOrgByNames(baseRepo.OwnerID, labelNames)
+			if err != nil {
+				return nil, "", fmt.Errorf("GetLabelIDsInOrgByNames: %v", err)
+			}
+			labelIDs = append(labelIDs, orgLabelIDs...)
+		}
+	}
+
+	prIssue := &models.Issue{
+		RepoID:   baseRepo.ID,
+		Title:    title,
+		PosterID: pusher.ID,
+		Poster:   pusher,
+		IsPull:   true,
+		Content:  content,
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: headRepo.ID,
+		BaseRepoID: baseRepo.ID,
+		HeadBranch: headBranch,
+		BaseBranch: baseBranch,
+		HeadRepo:   headRepo,
+		BaseRepo:   baseRepo,
+		MergeBase:  compareInfo.MergeBase,
+		Type:       models.PullRequestGitea,
+	}
+	if err := pull_service.NewPullRequest(baseRepo, prIssue, labelIDs, nil, pr, nil); err != nil {
+		return nil, "", fmt.Errorf("NewPullRequest: %v", err)
+	}
+	log.Trace("Pull request created from push options: %d/%d", baseRepo.ID, prIssue.ID)
+	return pr, "", nil
+}
+
 // SetDefaultBranch updates the default branch
 func SetDefaultBranch(ctx *macaron.Context) {
 	ownerName := ctx.Params(":owner")
@@ -18,7 +18,8 @@ import (
 func UpdatePublicKeyInRepo(ctx *macaron.Context) {
 	keyID := ctx.ParamsInt64(":id")
 	repoID := ctx.ParamsInt64(":repoid")
-	if err := models.UpdatePublicKeyUpdated(keyID); err != nil {
+	ip := ctx.Query("ip")
+	if err := models.UpdatePublicKeyUpdated(keyID, ip); err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
 			"err": err.Error(),
 		})
@@ -37,7 +38,9 @@ func UpdatePublicKeyInRepo(ctx *macaron.Context) {
 		return
 	}
 	deployKey.UpdatedUnix = timeutil.TimeStampNow()
-	if err = models.UpdateDeployKeyCols(deployKey, "updated_unix"); err != nil {
+	deployKey.LastUsedUnix = deployKey.UpdatedUnix
+	deployKey.LastUsedIP = ip
+	if err = models.UpdateDeployKeyCols(deployKey, "updated_unix", "last_used_unix", "last_used_ip"); err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
 			"err": err.Error(),
 		})
@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/timeutil"
 )
 
 const (
@@ -64,6 +65,17 @@ func Activity(ctx *context.Context) {
 		return
 	}
 
+	if ctx.Repo.CanRead(models.UnitTypePullRequests) {
+		if ctx.Data["ReviewerStats"], err = models.GetReviewerStats(&models.ReviewerStatsOptions{
+			RepoIDs: []int64{ctx.Repo.Repository.ID},
+			Since:   timeutil.TimeStamp(timeFrom.Unix()),
+			Doer:    ctx.User,
+		}); err != nil {
+			ctx.ServerError("GetReviewerStats", err)
+			return
+		}
+	}
+
 	ctx.HTML(200, tplActivity)
 }
 
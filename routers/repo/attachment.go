@@ -52,6 +52,19 @@ func uploadAttachment(ctx *context.Context, allowedTypes string) {
 		return
 	}
 
+	if err := ctx.Repo.Repository.GetOwner(); err != nil {
+		ctx.Error(500, fmt.Sprintf("GetOwner: %v", err))
+		return
+	}
+	if err := models.CheckStorageQuota(ctx.Repo.Repository.Owner, header.Size); err != nil {
+		if models.IsErrStorageQuotaExceeded(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, err.Error())
+		} else {
+			ctx.Error(500, fmt.Sprintf("CheckStorageQuota: %v", err))
+		}
+		return
+	}
+
 	attach, err := models.NewAttachment(&models.Attachment{
 		UploaderID: ctx.User.ID,
 		Name:       header.Filename,
@@ -12,6 +12,7 @@ import (
 	gotemplate "html/template"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
@@ -123,12 +124,29 @@ func RefBlame(ctx *context.Context) {
 		return
 	}
 
-	blameReader, err := git.CreateBlameReader(ctx.Req.Context(), models.RepoPath(userName, repoName), commitID, fileName)
+	bypassBlameIgnore := ctx.QueryBool("bypass-blame-ignore")
+	ignoreRevs, err := ctx.Repo.GitRepo.GetBlameIgnoreRevs(ctx.Repo.Repository.DefaultBranch)
+	if err != nil {
+		// a broken or missing default branch must not prevent showing the blame
+		log.Error("GetBlameIgnoreRevs: %v", err)
+	}
+	blameLink := ctx.Repo.RepoLink + "/blame/" + ctx.Repo.BranchNameSubURL() + "/" + ctx.Repo.TreePath
+	ctx.Data["HasBlameIgnoreRevs"] = len(ignoreRevs) > 0
+	ctx.Data["BypassBlameIgnore"] = bypassBlameIgnore
+	ctx.Data["BlameIgnoreRevsLink"] = ctx.Repo.RepoLink + "/src/branch/" + ctx.Repo.Repository.DefaultBranch + "/" + git.BlameIgnoreRevsFileName
+	ctx.Data["BlameLink"] = blameLink
+	ctx.Data["BlameBypassLink"] = blameLink + "?bypass-blame-ignore=true"
+	if bypassBlameIgnore {
+		ignoreRevs = nil
+	}
+
+	blameReader, err := git.CreateBlameReader(ctx.Req.Context(), models.RepoPath(userName, repoName), commitID, fileName, ignoreRevs)
 	if err != nil {
 		ctx.NotFound("CreateBlameReader", err)
 		return
 	}
 	defer blameReader.Close()
+	ctx.Data["UsesBlameIgnoreRevs"] = blameReader.IgnoresRevs()
 
 	blameParts := make([]git.BlamePart, 0)
 
@@ -170,10 +188,12 @@ func RefBlame(ctx *context.Context) {
 
 	commits = models.ValidateCommitsWithEmails(commits)
 
+	commitTimes := make(map[string]time.Time, commits.Len())
 	for e := commits.Front(); e != nil; e = e.Next() {
 		c := e.Value.(models.UserCommit)
 
 		commitNames[c.ID.String()] = c
+		commitTimes[c.ID.String()] = c.Committer.When
 	}
 
 	// Get Topics of this repo
@@ -182,12 +202,12 @@ func RefBlame(ctx *context.Context) {
 		return
 	}
 
-	renderBlame(ctx, blameParts, commitNames)
+	renderBlame(ctx, blameParts, commitNames, git.CalcBlameAges(commitTimes))
 
 	ctx.HTML(200, tplBlame)
 }
 
-func renderBlame(ctx *context.Context, blameParts []git.BlamePart, commitNames map[string]models.UserCommit) {
+func renderBlame(ctx *context.Context, blameParts []git.BlamePart, commitNames map[string]models.UserCommit, ages map[string]git.BlameAge) {
 	repoLink := ctx.Repo.RepoLink
 
 	var lines = make([]string, 0)
@@ -207,6 +227,9 @@ func renderBlame(ctx *context.Context, blameParts []git.BlamePart, commitNames m
 				attr = " bottom-line"
 			}
 			commit := commitNames[part.Sha]
+			// The age bucket colors the gutter of every line to render a recency heatmap
+			age := ages[part.Sha]
+			ageAttr := fmt.Sprintf(` class="blame-info blame-age-%d%s" data-age-score="%g"`, age.Bucket, attr, age.Score)
 			if index == 0 {
 				// User avatar image
 				avatar := ""
@@ -220,9 +243,9 @@ func renderBlame(ctx *context.Context, blameParts []git.BlamePart, commitNames m
 				} else {
 					avatar = fmt.Sprintf(`<img class="ui avatar image" src="%s" title="%s"/>`, html.EscapeString(models.AvatarLink(commit.Author.Email)), html.EscapeString(commit.Author.Name))
 				}
-				commitInfo.WriteString(fmt.Sprintf(`<div class="blame-info%s"><div class="blame-data"><div class="blame-avatar">%s</div><div class="blame-message"><a href="%s/commit/%s" title="%[5]s">%[5]s</a></div><div class="blame-time">%s</div></div></div>`, attr, avatar, repoLink, part.Sha, html.EscapeString(commit.CommitMessage), commitSince))
+				commitInfo.WriteString(fmt.Sprintf(`<div%s><div class="blame-data"><div class="blame-avatar">%s</div><div class="blame-message"><a href="%s/commit/%s" title="%[5]s">%[5]s</a></div><div class="blame-time">%s</div></div></div>`, ageAttr, avatar, repoLink, part.Sha, html.EscapeString(commit.CommitMessage), commitSince))
 			} else {
-				commitInfo.WriteString(fmt.Sprintf(`<div class="blame-info%s">&#8203;</div>`, attr))
+				commitInfo.WriteString(fmt.Sprintf(`<div%s>&#8203;</div>`, ageAttr))
 			}
 
 			//Line number
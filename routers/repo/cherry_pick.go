@@ -0,0 +1,197 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/util"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/unknwon/com"
+)
+
+const tplCherryPick base.TplName = "repo/editor/cherry_pick"
+
+// prepareCherryPick returns the commit cherry-picked or reverted onto the branch
+func prepareCherryPick(ctx *context.Context, revert bool) *git.Commit {
+	commit, err := ctx.Repo.GitRepo.GetCommit(ctx.Params(":sha"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetCommit", err)
+		} else {
+			ctx.ServerError("GetCommit", err)
+		}
+		return nil
+	}
+	branches, err := ctx.Repo.GitRepo.GetBranches()
+	if err != nil {
+		ctx.ServerError("GetBranches", err)
+		return nil
+	}
+
+	theirsLabel := base.ShortSha(commit.ID.String())
+	if revert {
+		ctx.Data["Title"] = ctx.Tr("repo.editor.revert_title", theirsLabel)
+		theirsLabel = ctx.Tr("repo.editor.cherry_pick.parent_of", theirsLabel)
+	} else {
+		ctx.Data["Title"] = ctx.Tr("repo.editor.cherry_pick_title", theirsLabel)
+	}
+	ctx.Data["PageIsCherryPick"] = true
+	ctx.Data["Revert"] = revert
+	ctx.Data["CherryPickCommit"] = commit
+	ctx.Data["Branches"] = branches
+	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(ctx.Repo.BranchName)
+	ctx.Data["ConflictOursLabel"] = ctx.Repo.BranchName
+	ctx.Data["ConflictTheirsLabel"] = theirsLabel
+	return commit
+}
+
+// CherryPick renders the form cherry-picking or reverting a commit onto a branch
+func CherryPick(ctx *context.Context) {
+	revert := ctx.QueryBool("revert")
+	commit := prepareCherryPick(ctx, revert)
+	if ctx.Written() {
+		return
+	}
+
+	summary, message := repofiles.CherryPickMessage(commit, revert), ""
+	if i := strings.Index(summary, "\n"); i >= 0 {
+		summary, message = summary[:i], strings.TrimSpace(summary[i:])
+	}
+	ctx.Data["commit_summary"] = summary
+	ctx.Data["commit_message"] = message
+	if renderCommitRights(ctx) {
+		ctx.Data["commit_choice"] = frmCommitChoiceDirect
+	} else {
+		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
+	}
+	ctx.Data["new_branch_name"] = GetUniquePatchBranchName(ctx)
+	ctx.Data["last_commit"] = ctx.Repo.CommitID
+	ctx.HTML(200, tplCherryPick)
+}
+
+// CherryPickPost commits the cherry-pick or the revert of a commit onto a branch, the commit is proposed
+// by a pull request from a new branch when the branch is protected. The conflicting files are resolved
+// with the conflict editor before committing.
+func CherryPickPost(ctx *context.Context, form auth.CherryPickForm) {
+	commit := prepareCherryPick(ctx, form.Revert)
+	if ctx.Written() {
+		return
+	}
+	canCommit := renderCommitRights(ctx)
+	branchName := ctx.Repo.BranchName
+	if form.CommitChoice == frmCommitChoiceNewBranch || !canCommit {
+		form.CommitChoice = frmCommitChoiceNewBranch
+		if form.NewBranchName == "" {
+			form.NewBranchName = GetUniquePatchBranchName(ctx)
+		}
+		branchName = form.NewBranchName
+	}
+
+	ctx.Data["commit_summary"] = form.CommitSummary
+	ctx.Data["commit_message"] = form.CommitMessage
+	ctx.Data["commit_choice"] = form.CommitChoice
+	ctx.Data["new_branch_name"] = form.NewBranchName
+	ctx.Data["last_commit"] = form.LastCommit
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplCherryPick)
+		return
+	}
+
+	opts := &repofiles.CherryPickOptions{
+		LastCommitID: form.LastCommit,
+		OldBranch:    ctx.Repo.BranchName,
+		NewBranch:    branchName,
+		CommitID:     commit.ID.String(),
+		Revert:       form.Revert,
+	}
+	conflicts, err := repofiles.GetCherryPickConflicts(ctx.Repo.Repository, opts)
+	if err != nil {
+		if models.IsErrCommitIDDoesNotMatch(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.editor.cherry_pick.branch_changed", ctx.Repo.BranchName), tplCherryPick, &form)
+		} else {
+			ctx.ServerError("GetCherryPickConflicts", err)
+		}
+		return
+	}
+	files := conflictFiles(ctx, conflicts)
+	ctx.Data["Files"] = files
+	if len(files) > 0 && ctx.Query("resolution_0") == "" {
+		// resolve the conflicts before committing
+		ctx.Data["last_commit"] = conflicts.CommitID
+		ctx.Flash.Warning(ctx.Tr("repo.editor.cherry_pick.conflicts"), true)
+		ctx.HTML(200, tplCherryPick)
+		return
+	}
+	opts.Resolutions = conflictResolutions(files)
+
+	opts.Message = strings.TrimSpace(form.CommitSummary)
+	if len(opts.Message) == 0 {
+		opts.Message = repofiles.CherryPickMessage(commit, form.Revert)
+	} else if message := strings.TrimSpace(form.CommitMessage); len(message) > 0 {
+		opts.Message += "\n\n" + message
+	}
+
+	if _, err := repofiles.CherryPick(ctx.Repo.Repository, ctx.User, opts); err != nil {
+		switch {
+		case models.IsErrMergeConflictNotResolved(err):
+			ctx.RenderWithErr(ctx.Tr("repo.pulls.conflicts.not_resolved", err.(models.ErrMergeConflictNotResolved).Path), tplCherryPick, &form)
+		case models.IsErrBranchAlreadyExists(err):
+			ctx.Data["Err_NewBranchName"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.branch_already_exists", branchName), tplCherryPick, &form)
+		case models.IsErrCommitIDDoesNotMatch(err), git.IsErrPushOutOfDate(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.cherry_pick.branch_changed", ctx.Repo.BranchName), tplCherryPick, &form)
+		case git.IsErrPushRejected(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.push_rejected_no_message"), tplCherryPick, &form)
+		default:
+			ctx.ServerError("CherryPick", err)
+		}
+		return
+	}
+
+	if form.CommitChoice != frmCommitChoiceNewBranch || !ctx.Repo.Repository.UnitEnabled(models.UnitTypePullRequests) {
+		ctx.Redirect(ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(branchName))
+		return
+	}
+
+	// propose the commit to the branch
+	title := opts.Message
+	if i := strings.Index(title, "\n"); i >= 0 {
+		title = title[:i]
+	}
+	pullIssue := &models.Issue{
+		RepoID:   ctx.Repo.Repository.ID,
+		Repo:     ctx.Repo.Repository,
+		Title:    title,
+		PosterID: ctx.User.ID,
+		Poster:   ctx.User,
+		IsPull:   true,
+		Content:  strings.TrimSpace(strings.TrimPrefix(opts.Message, title)),
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: ctx.Repo.Repository.ID,
+		BaseRepoID: ctx.Repo.Repository.ID,
+		HeadBranch: branchName,
+		BaseBranch: ctx.Repo.BranchName,
+		HeadRepo:   ctx.Repo.Repository,
+		BaseRepo:   ctx.Repo.Repository,
+		Type:       models.PullRequestGitea,
+	}
+	if err := pull_service.NewPullRequest(ctx.Repo.Repository, pullIssue, nil, nil, pr, nil); err != nil {
+		ctx.ServerError("NewPullRequest", err)
+		return
+	}
+	log.Trace("Commit %s proposed to %s by pull request %d", commit.ID, ctx.Repo.BranchName, pr.ID)
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pullIssue.Index))
+}
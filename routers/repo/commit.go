@@ -109,6 +109,7 @@ func Graph(ctx *context.Context) {
 	}
 	ctx.Data["SelectedBranches"] = realBranches
 	files := ctx.QueryStrings("file")
+	authors := ctx.QueryStrings("author")
 
 	commitsCount, err := ctx.Repo.GetCommitsCount()
 	if err != nil {
@@ -116,12 +117,12 @@ func Graph(ctx *context.Context) {
 		return
 	}
 
-	graphCommitsCount, err := ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files)
+	graphCommitsCount, err := ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files, authors)
 	if err != nil {
 		log.Warn("GetCommitGraphsCount error for generate graph exclude prs: %t branches: %s in %-v, Will Ignore branches and try again. Underlying Error: %v", hidePRRefs, branches, ctx.Repo.Repository, err)
 		realBranches = []string{}
 		branches = []string{}
-		graphCommitsCount, err = ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files)
+		graphCommitsCount, err = ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files, authors)
 		if err != nil {
 			ctx.ServerError("GetCommitGraphsCount", err)
 			return
@@ -130,7 +131,7 @@ func Graph(ctx *context.Context) {
 
 	page := ctx.QueryInt("page")
 
-	graph, err := gitgraph.GetCommitGraph(ctx.Repo.GitRepo, page, 0, hidePRRefs, realBranches, files)
+	graph, err := gitgraph.GetCommitGraph(ctx.Repo.GitRepo, page, 0, 0, hidePRRefs, realBranches, files, authors)
 	if err != nil {
 		ctx.ServerError("GetCommitGraph", err)
 		return
@@ -164,6 +165,9 @@ func Graph(ctx *context.Context) {
 	for _, file := range files {
 		paginator.AddParamString("file", file)
 	}
+	for _, author := range authors {
+		paginator.AddParamString("author", author)
+	}
 	ctx.Data["Page"] = paginator
 	if ctx.QueryBool("div-only") {
 		ctx.HTML(200, tplGraphDiv)
@@ -334,7 +338,7 @@ func Diff(ctx *context.Context) {
 			return
 		}
 	}
-	setImageCompareContext(ctx, parentCommit, commit)
+	setImageCompareContext(ctx, ctx.Repo.Repository, parentCommit, ctx.Repo.Repository, commit)
 	headTarget := path.Join(userName, repoName)
 	setPathsCompareContext(ctx, parentCommit, commit, headTarget)
 	ctx.Data["Title"] = commit.Summary() + " · " + base.ShortSha(commitID)
@@ -342,6 +346,7 @@ func Diff(ctx *context.Context) {
 	verification := models.ParseCommitWithSignature(commit)
 	ctx.Data["Verification"] = verification
 	ctx.Data["Author"] = models.ValidateCommitWithEmail(commit)
+	ctx.Data["CommitTrailers"] = models.GetCommitTrailers(commit)
 	ctx.Data["Diff"] = diff
 	ctx.Data["Parents"] = parents
 	ctx.Data["DiffNotAvailable"] = diff.NumFiles == 0
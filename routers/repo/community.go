@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+const (
+	tplCommunity base.TplName = "repo/community"
+)
+
+// Community render the checklist of the files helping people to contribute to a repository
+func Community(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.community")
+	ctx.Data["PageIsActivity"] = true
+
+	profile, err := repo_service.GetCommunityProfile(ctx.Repo.Repository, ctx.Repo.Commit)
+	if err != nil {
+		ctx.ServerError("GetCommunityProfile", err)
+		return
+	}
+	ctx.Data["Profile"] = profile
+	ctx.Data["NoAssertion"] = repo_service.NoAssertion
+
+	ctx.HTML(200, tplCommunity)
+}
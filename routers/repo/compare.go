@@ -6,8 +6,12 @@ package repo
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"html"
+	"image"
+	"io"
+	"io/ioutil"
 	"path"
 	"strings"
 
@@ -15,10 +19,12 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/services/gitdiff"
+	repository_service "code.gitea.io/gitea/services/repository"
 )
 
 const (
@@ -26,42 +32,118 @@ const (
 	tplBlobExcerpt base.TplName = "repo/diff/blob_excerpt"
 )
 
-// setPathsCompareContext sets context data for source and raw paths
+// setPathsCompareContext sets context data for source, raw and media paths
 func setPathsCompareContext(ctx *context.Context, base *git.Commit, head *git.Commit, headTarget string) {
 	sourcePath := setting.AppSubURL + "/%s/src/commit/%s"
 	rawPath := setting.AppSubURL + "/%s/raw/commit/%s"
+	mediaPath := setting.AppSubURL + "/%s/media/commit/%s"
 
 	ctx.Data["SourcePath"] = fmt.Sprintf(sourcePath, headTarget, head.ID)
 	ctx.Data["RawPath"] = fmt.Sprintf(rawPath, headTarget, head.ID)
+	ctx.Data["MediaPath"] = fmt.Sprintf(mediaPath, headTarget, head.ID)
 	if base != nil {
 		baseTarget := path.Join(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
 		ctx.Data["BeforeSourcePath"] = fmt.Sprintf(sourcePath, baseTarget, base.ID)
 		ctx.Data["BeforeRawPath"] = fmt.Sprintf(rawPath, baseTarget, base.ID)
+		ctx.Data["BeforeMediaPath"] = fmt.Sprintf(mediaPath, baseTarget, base.ID)
 	}
 }
 
-// setImageCompareContext sets context data that is required by image compare template
-func setImageCompareContext(ctx *context.Context, base *git.Commit, head *git.Commit) {
-	ctx.Data["IsImageFileInHead"] = head.IsImageFile
-	ctx.Data["IsImageFileInBase"] = base.IsImageFile
-	ctx.Data["ImageInfoBase"] = func(name string) *git.ImageMetaData {
-		if base == nil {
-			return nil
+// openCompareBlob returns a reader of the content of a file of the commit and the size of the
+// content, the content of the LFS object is read if the file is a pointer to an object of the
+// repository which isn't larger than the LFS.MaxPreviewSize setting
+func openCompareBlob(repo *models.Repository, commit *git.Commit, name string) (io.ReadCloser, int64, error) {
+	blob, err := commit.GetBlobByPath(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, 0, err
+	}
+	if !setting.LFS.StartServer || blob.Size() > 1024 {
+		return dataRc, blob.Size(), nil
+	}
+
+	buf, err := ioutil.ReadAll(dataRc)
+	dataRc.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+	if meta := lfs.IsPointerFile(&buf); meta != nil {
+		meta, err = repo.GetLFSMetaObjectByOid(meta.Oid)
+		if err != nil && err != models.ErrLFSObjectNotExist {
+			return nil, 0, err
 		}
-		result, err := base.ImageInfo(name)
-		if err != nil {
-			log.Error("ImageInfo failed: %v", err)
-			return nil
+		if meta != nil && (setting.LFS.MaxPreviewSize <= 0 || meta.Size <= setting.LFS.MaxPreviewSize) {
+			lfsDataRc, err := lfs.ReadMetaObject(meta)
+			if err != nil {
+				return nil, 0, err
+			}
+			return lfsDataRc, meta.Size, nil
 		}
-		return result
+	}
+	return ioutil.NopCloser(bytes.NewReader(buf)), blob.Size(), nil
+}
+
+// isCompareImageFile returns whether a file of the commit or the LFS object it points to is an image
+func isCompareImageFile(repo *models.Repository, commit *git.Commit, name string) bool {
+	if commit == nil {
+		return false
+	}
+	dataRc, _, err := openCompareBlob(repo, commit, name)
+	if err != nil {
+		return false
+	}
+	defer dataRc.Close()
+
+	buf := make([]byte, 1024)
+	n, _ := io.ReadFull(dataRc, buf)
+	return base.IsImageFile(buf[:n])
+}
+
+// compareImageInfo returns the dimensions of an image of the commit, the image may be stored in LFS
+func compareImageInfo(repo *models.Repository, commit *git.Commit, name string) *git.ImageMetaData {
+	if commit == nil || !isCompareImageFile(repo, commit, name) {
+		return nil
+	}
+	dataRc, size, err := openCompareBlob(repo, commit, name)
+	if err != nil {
+		log.Error("ImageInfo failed: %v", err)
+		return nil
+	}
+	defer dataRc.Close()
+
+	config, _, err := image.DecodeConfig(dataRc)
+	if err != nil {
+		log.Error("ImageInfo failed: %v", err)
+		return nil
+	}
+	return &git.ImageMetaData{
+		ColorModel: config.ColorModel,
+		Width:      config.Width,
+		Height:     config.Height,
+		ByteSize:   size,
+	}
+}
+
+// setImageCompareContext sets context data that is required by image compare template
+func setImageCompareContext(ctx *context.Context, baseRepo *models.Repository, base *git.Commit, headRepo *models.Repository, head *git.Commit) {
+	ctx.Data["IsImageFileInHead"] = func(name string) bool {
+		return isCompareImageFile(headRepo, head, name)
+	}
+	ctx.Data["IsImageFileInBase"] = func(name string) bool {
+		return isCompareImageFile(baseRepo, base, name)
+	}
+	ctx.Data["ImageInfoBase"] = func(name string) *git.ImageMetaData {
+		return compareImageInfo(baseRepo, base, name)
 	}
 	ctx.Data["ImageInfo"] = func(name string) *git.ImageMetaData {
-		result, err := head.ImageInfo(name)
-		if err != nil {
-			log.Error("ImageInfo failed: %v", err)
-			return nil
-		}
-		return result
+		return compareImageInfo(headRepo, head, name)
+	}
+	// the pixel differences are computed when both commits are in the repository
+	if base != nil && baseRepo.ID == headRepo.ID {
+		ctx.Data["ImageDiffPath"] = fmt.Sprintf("%s/image-diff/%s/%s", baseRepo.Link(), base.ID, head.ID)
 	}
 }
 
@@ -253,7 +335,19 @@ func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *
 		ctx.Data["PageIsComparePull"] = false
 	}
 
-	// 8. Finally open the git repo
+	// 8. The repositories which aren't of the same fork network can only be compared
+	if has && !isSameRepo {
+		if !repository_service.CanCompare(baseRepo, headRepo) {
+			ctx.NotFound("CanCompare", nil)
+			return nil, nil, nil, nil, "", ""
+		}
+		if !repository_service.IsSameForkNetwork(baseRepo, headRepo) {
+			ctx.Data["PageIsComparePull"] = false
+			ctx.Data["IsUnrelatedCompare"] = true
+		}
+	}
+
+	// 9. Finally open the git repo
 	var headGitRepo *git.Repository
 	if isSameRepo {
 		headRepo = ctx.Repo.Repository
@@ -394,12 +488,14 @@ func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *
 		headBranchRef = git.TagPrefix + headBranch
 	}
 
-	compareInfo, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseBranchRef, headBranchRef)
+	compareInfo, totalCommits, err := repository_service.GetCompareInfo(baseRepo, headRepo, headGitRepo, baseBranchRef, headBranchRef)
 	if err != nil {
 		ctx.ServerError("GetCompareInfo", err)
 		return nil, nil, nil, nil, "", ""
 	}
 	ctx.Data["BeforeCommitID"] = compareInfo.MergeBase
+	ctx.Data["TotalCommits"] = totalCommits
+	ctx.Data["IsCommitsTruncated"] = compareInfo.Commits.Len() < totalCommits
 
 	return headUser, headRepo, headGitRepo, compareInfo, baseBranch, headBranch
 }
@@ -483,6 +579,10 @@ func PrepareCompareDiff(
 	compareInfo.Commits = models.ParseCommitsWithStatus(compareInfo.Commits, headRepo)
 	ctx.Data["Commits"] = compareInfo.Commits
 	ctx.Data["CommitCount"] = compareInfo.Commits.Len()
+	if totalCommits, ok := ctx.Data["TotalCommits"].(int); ok {
+		// the list of commits is limited
+		ctx.Data["CommitCount"] = totalCommits
+	}
 
 	if compareInfo.Commits.Len() == 1 {
 		c := compareInfo.Commits.Front().Value.(models.SignCommitWithStatuses)
@@ -499,7 +599,7 @@ func PrepareCompareDiff(
 	ctx.Data["Username"] = headUser.Name
 	ctx.Data["Reponame"] = headRepo.Name
 
-	setImageCompareContext(ctx, baseCommit, headCommit)
+	setImageCompareContext(ctx, repo, baseCommit, headRepo, headCommit)
 	headTarget := path.Join(headUser.Name, repo.Name)
 	setPathsCompareContext(ctx, baseCommit, headCommit, headTarget)
 
@@ -111,7 +111,7 @@ func SingleDownload(ctx *context.Context) {
 	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
 	if err != nil {
 		if git.IsErrNotExist(err) {
-			ctx.NotFound("GetBlobByPath", nil)
+			ctx.NotFoundRepoPath("GetBlobByPath", nil)
 		} else {
 			ctx.ServerError("GetBlobByPath", err)
 		}
@@ -127,7 +127,7 @@ func SingleDownloadOrLFS(ctx *context.Context) {
 	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
 	if err != nil {
 		if git.IsErrNotExist(err) {
-			ctx.NotFound("GetBlobByPath", nil)
+			ctx.NotFoundRepoPath("GetBlobByPath", nil)
 		} else {
 			ctx.ServerError("GetBlobByPath", err)
 		}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/repofiles"
 	repo_module "code.gitea.io/gitea/modules/repository"
@@ -81,7 +82,12 @@ func editFile(ctx *context.Context, isNewFile bool) {
 
 	treeNames, treePaths := getParentTreeFields(ctx.Repo.TreePath)
 
-	if !isNewFile {
+	if staged := getStagedChanges(ctx).get(treePath); !isNewFile && staged != nil && !staged.IsDelete() {
+		// Continue editing the staged content of the file
+		ctx.Data["FileSize"] = len(staged.Content)
+		ctx.Data["FileName"] = path.Base(treePath)
+		ctx.Data["FileContent"] = staged.Content
+	} else if !isNewFile {
 		entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 		if err != nil {
 			ctx.NotFoundOrServerError("GetTreeEntryByPath", git.IsErrNotExist, err)
@@ -148,6 +154,9 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	ctx.Data["LineWrapExtensions"] = strings.Join(setting.Repository.Editor.LineWrapExtensions, ",")
 	ctx.Data["PreviewableFileModes"] = strings.Join(setting.Repository.Editor.PreviewableFileModes, ",")
 	ctx.Data["Editorconfig"] = GetEditorConfig(ctx, treePath)
+	renderEditorLanguage(ctx, treePath)
+	ctx.Data["CanStageChanges"] = true
+	renderStagedChangesCount(ctx)
 
 	ctx.HTML(200, tplEditFile)
 }
@@ -165,6 +174,15 @@ func GetEditorConfig(ctx *context.Context, treePath string) string {
 	return "null"
 }
 
+// renderEditorLanguage sets the language of the editor resolved like the highlighting of the file
+// and the languages of the custom highlight mapping, it must be called after the content is set
+func renderEditorLanguage(ctx *context.Context, treePath string) {
+	content, _ := ctx.Data["FileContent"].(string)
+	ctx.Data["EditorLanguage"] = highlight.EditorLanguage(path.Base(treePath), []byte(content))
+	mapping, _ := json.Marshal(highlight.EditorLanguageMapping())
+	ctx.Data["EditorLanguageMapping"] = string(mapping)
+}
+
 // EditFile render edit file page
 func EditFile(ctx *context.Context) {
 	editFile(ctx, false)
@@ -202,12 +220,36 @@ func editFilePost(ctx *context.Context, form auth.EditRepoFileForm, isNewFile bo
 	ctx.Data["LineWrapExtensions"] = strings.Join(setting.Repository.Editor.LineWrapExtensions, ",")
 	ctx.Data["PreviewableFileModes"] = strings.Join(setting.Repository.Editor.PreviewableFileModes, ",")
 	ctx.Data["Editorconfig"] = GetEditorConfig(ctx, form.TreePath)
+	renderEditorLanguage(ctx, form.TreePath)
+	ctx.Data["CanStageChanges"] = true
+	renderStagedChangesCount(ctx)
 
 	if ctx.HasError() {
 		ctx.HTML(200, tplEditFile)
 		return
 	}
 
+	if form.Stage {
+		treePath := cleanUploadFileName(form.TreePath)
+		if treePath == "" {
+			ctx.Data["Err_TreePath"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.filename_is_invalid", form.TreePath), tplEditFile, &form)
+			return
+		}
+		change := &stagedChange{
+			Operation:    repofiles.FileOperationUpdate,
+			TreePath:     treePath,
+			FromTreePath: ctx.Repo.TreePath,
+			Content:      strings.ReplaceAll(form.Content, "\r", ""),
+		}
+		if isNewFile {
+			change.Operation = repofiles.FileOperationCreate
+			change.FromTreePath = treePath
+		}
+		stageChange(ctx, change, form.LastCommit)
+		return
+	}
+
 	// Cannot commit to a an existing branch if user doesn't have rights
 	if branchName == ctx.Repo.BranchName && !canCommit {
 		ctx.Data["Err_NewBranchName"] = true
@@ -390,6 +432,8 @@ func DeleteFile(ctx *context.Context) {
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
 	}
 	ctx.Data["new_branch_name"] = GetUniquePatchBranchName(ctx)
+	ctx.Data["CanStageChanges"] = true
+	renderStagedChangesCount(ctx)
 
 	ctx.HTML(200, tplDeleteFile)
 }
@@ -410,12 +454,23 @@ func DeleteFilePost(ctx *context.Context, form auth.DeleteRepoFileForm) {
 	ctx.Data["commit_choice"] = form.CommitChoice
 	ctx.Data["new_branch_name"] = form.NewBranchName
 	ctx.Data["last_commit"] = ctx.Repo.CommitID
+	ctx.Data["CanStageChanges"] = true
+	renderStagedChangesCount(ctx)
 
 	if ctx.HasError() {
 		ctx.HTML(200, tplDeleteFile)
 		return
 	}
 
+	if form.Stage {
+		stageChange(ctx, &stagedChange{
+			Operation:    repofiles.FileOperationDelete,
+			TreePath:     ctx.Repo.TreePath,
+			FromTreePath: ctx.Repo.TreePath,
+		}, form.LastCommit)
+		return
+	}
+
 	if branchName == ctx.Repo.BranchName && !canCommit {
 		ctx.Data["Err_NewBranchName"] = true
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
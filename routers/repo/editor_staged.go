@@ -0,0 +1,307 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/utils"
+)
+
+const tplStagedChanges base.TplName = "repo/editor/staged"
+
+// stagedChange is a change of a file staged in the web editor
+type stagedChange struct {
+	Operation    string
+	TreePath     string
+	FromTreePath string
+	Content      string
+}
+
+// IsCreate returns whether the change creates the file
+func (c *stagedChange) IsCreate() bool {
+	return c.Operation == repofiles.FileOperationCreate
+}
+
+// IsDelete returns whether the change deletes the file
+func (c *stagedChange) IsDelete() bool {
+	return c.Operation == repofiles.FileOperationDelete
+}
+
+// IsMove returns whether the change moves the file
+func (c *stagedChange) IsMove() bool {
+	return c.Operation == repofiles.FileOperationUpdate && c.FromTreePath != c.TreePath
+}
+
+// stagedChanges are the changes of files of a branch staged by a user in the web editor, they
+// are kept in the session until they are committed in a single commit
+type stagedChanges struct {
+	// LastCommitID is the head of the branch when the first change was staged
+	LastCommitID string
+	Changes      []*stagedChange
+}
+
+func stagedChangesKey(ctx *context.Context) string {
+	return fmt.Sprintf("staged_changes_%d_%s", ctx.Repo.Repository.ID, ctx.Repo.BranchName)
+}
+
+// getStagedChanges returns the changes staged on the branch of the context
+func getStagedChanges(ctx *context.Context) *stagedChanges {
+	staged := new(stagedChanges)
+	if data, ok := ctx.Session.Get(stagedChangesKey(ctx)).(string); ok {
+		if err := json.Unmarshal([]byte(data), staged); err != nil {
+			log.Error("Unable to unmarshal the staged changes: %v", err)
+			return new(stagedChanges)
+		}
+	}
+	return staged
+}
+
+// saveStagedChanges saves the changes staged on the branch of the context
+func saveStagedChanges(ctx *context.Context, staged *stagedChanges) error {
+	if len(staged.Changes) == 0 {
+		return ctx.Session.Delete(stagedChangesKey(ctx))
+	}
+	data, err := json.Marshal(staged)
+	if err != nil {
+		return err
+	}
+	return ctx.Session.Set(stagedChangesKey(ctx), string(data))
+}
+
+// get returns the staged change resulting in the file at the path
+func (s *stagedChanges) get(treePath string) *stagedChange {
+	for _, change := range s.Changes {
+		if change.TreePath == treePath {
+			return change
+		}
+	}
+	return nil
+}
+
+// stage adds a change on top of the staged changes, a change of a file which has already been
+// changed is folded into the staged change of the file
+func (s *stagedChanges) stage(change *stagedChange, lastCommitID string) {
+	if len(s.Changes) == 0 {
+		s.LastCommitID = lastCommitID
+	}
+
+	for i, staged := range s.Changes {
+		if staged.TreePath != change.FromTreePath {
+			continue
+		}
+		switch {
+		case staged.IsCreate() && change.IsDelete():
+			// the file was never committed
+			s.Changes = append(s.Changes[:i], s.Changes[i+1:]...)
+			return
+		case staged.IsCreate():
+			change.Operation = repofiles.FileOperationCreate
+		case staged.IsDelete() && change.IsCreate():
+			change.Operation = repofiles.FileOperationUpdate
+		case change.IsDelete():
+			// delete the file at its original path
+			change.TreePath = staged.FromTreePath
+		}
+		change.FromTreePath = staged.FromTreePath
+		s.Changes[i] = change
+		return
+	}
+	s.Changes = append(s.Changes, change)
+}
+
+// unstage removes the staged change resulting in the file at the path
+func (s *stagedChanges) unstage(treePath string) {
+	for i, change := range s.Changes {
+		if change.TreePath == treePath {
+			s.Changes = append(s.Changes[:i], s.Changes[i+1:]...)
+			return
+		}
+	}
+}
+
+// stageChange stages a change of a file of the branch of the context and redirects to the staged changes
+func stageChange(ctx *context.Context, change *stagedChange, lastCommitID string) {
+	staged := getStagedChanges(ctx)
+	staged.stage(change, lastCommitID)
+	if err := saveStagedChanges(ctx, staged); err != nil {
+		ctx.ServerError("saveStagedChanges", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.editor.change_staged", change.TreePath))
+	ctx.Redirect(ctx.Repo.RepoLink + "/_staged/" + util.PathEscapeSegments(ctx.Repo.BranchName))
+}
+
+// renderStagedChangesCount shows the number of changes staged on the branch of the context
+func renderStagedChangesCount(ctx *context.Context) {
+	if ctx.IsSigned && ctx.Repo.CanEnableEditor() {
+		ctx.Data["StagedChangesCount"] = len(getStagedChanges(ctx).Changes)
+		ctx.Data["StagedChangesLink"] = ctx.Repo.RepoLink + "/_staged/" + util.PathEscapeSegments(ctx.Repo.BranchName)
+	}
+}
+
+func prepareStagedChanges(ctx *context.Context) *stagedChanges {
+	staged := getStagedChanges(ctx)
+	ctx.Data["Title"] = ctx.Tr("repo.editor.staged_changes")
+	ctx.Data["PageIsStaged"] = true
+	ctx.Data["StagedChanges"] = staged.Changes
+	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL()
+	ctx.Data["UnstageLink"] = ctx.Repo.RepoLink + "/_unstage/" + util.PathEscapeSegments(ctx.Repo.BranchName)
+	return staged
+}
+
+// StagedChanges renders the changes of files staged on a branch and the form committing them
+func StagedChanges(ctx *context.Context) {
+	staged := prepareStagedChanges(ctx)
+	ctx.Data["commit_summary"] = ""
+	ctx.Data["commit_message"] = ""
+	if renderCommitRights(ctx) {
+		ctx.Data["commit_choice"] = frmCommitChoiceDirect
+	} else {
+		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
+	}
+	ctx.Data["new_branch_name"] = GetUniquePatchBranchName(ctx)
+	ctx.Data["last_commit"] = staged.LastCommitID
+	ctx.HTML(200, tplStagedChanges)
+}
+
+// StagedChangesPost commits the changes of files staged on a branch in a single commit
+func StagedChangesPost(ctx *context.Context, form auth.CommitStagedChangesForm) {
+	staged := prepareStagedChanges(ctx)
+	canCommit := renderCommitRights(ctx)
+	branchName := ctx.Repo.BranchName
+	if form.CommitChoice == frmCommitChoiceNewBranch {
+		branchName = form.NewBranchName
+	}
+
+	ctx.Data["commit_summary"] = form.CommitSummary
+	ctx.Data["commit_message"] = form.CommitMessage
+	ctx.Data["commit_choice"] = form.CommitChoice
+	ctx.Data["new_branch_name"] = form.NewBranchName
+	ctx.Data["last_commit"] = staged.LastCommitID
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplStagedChanges)
+		return
+	}
+	if len(staged.Changes) == 0 {
+		ctx.RenderWithErr(ctx.Tr("repo.editor.no_staged_changes"), tplStagedChanges, &form)
+		return
+	}
+
+	// Cannot commit to a an existing branch if user doesn't have rights
+	if branchName == ctx.Repo.BranchName && !canCommit {
+		ctx.Data["Err_NewBranchName"] = true
+		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
+		ctx.RenderWithErr(ctx.Tr("repo.editor.cannot_commit_to_protected_branch", branchName), tplStagedChanges, &form)
+		return
+	}
+
+	message := strings.TrimSpace(form.CommitSummary)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.change_files", len(staged.Changes))
+	}
+	form.CommitMessage = strings.TrimSpace(form.CommitMessage)
+	if len(form.CommitMessage) > 0 {
+		message += "\n\n" + form.CommitMessage
+	}
+
+	files := make([]*repofiles.ChangeRepoFile, 0, len(staged.Changes))
+	for _, change := range staged.Changes {
+		files = append(files, &repofiles.ChangeRepoFile{
+			Operation:    change.Operation,
+			TreePath:     change.TreePath,
+			FromTreePath: change.FromTreePath,
+			Content:      change.Content,
+		})
+	}
+	if _, err := repofiles.ChangeRepoFiles(ctx.Repo.Repository, ctx.User, &repofiles.ChangeRepoFilesOptions{
+		LastCommitID: staged.LastCommitID,
+		OldBranch:    ctx.Repo.BranchName,
+		NewBranch:    branchName,
+		Message:      message,
+		Files:        files,
+	}); err != nil {
+		switch {
+		case models.IsErrRepoFileDoesNotExist(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.staged_file_no_longer_exists", err.(models.ErrRepoFileDoesNotExist).Path), tplStagedChanges, &form)
+		case models.IsErrLFSFileLocked(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.upload_file_is_locked", err.(models.ErrLFSFileLocked).Path, err.(models.ErrLFSFileLocked).UserName), tplStagedChanges, &form)
+		case models.IsErrFilenameInvalid(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.filename_is_invalid", err.(models.ErrFilenameInvalid).Path), tplStagedChanges, &form)
+		case models.IsErrFilePathInvalid(err):
+			ctx.RenderWithErr(err.(models.ErrFilePathInvalid).Message, tplStagedChanges, &form)
+		case models.IsErrRepoFileAlreadyExists(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.file_already_exists", err.(models.ErrRepoFileAlreadyExists).Path), tplStagedChanges, &form)
+		case models.IsErrFilePathProtected(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.file_is_protected", err.(models.ErrFilePathProtected).Path), tplStagedChanges, &form)
+		case models.IsErrBranchAlreadyExists(err):
+			// For when a user specifies a new branch that already exists
+			ctx.Data["Err_NewBranchName"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.branch_already_exists", branchName), tplStagedChanges, &form)
+		case models.IsErrCommitIDDoesNotMatch(err) || git.IsErrPushOutOfDate(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.file_changed_while_editing", ctx.Repo.RepoLink+"/compare/"+staged.LastCommitID+"..."+ctx.Repo.CommitID), tplStagedChanges, &form)
+		case git.IsErrPushRejected(err):
+			errPushRej := err.(*git.ErrPushRejected)
+			if len(errPushRej.Message) == 0 {
+				ctx.RenderWithErr(ctx.Tr("repo.editor.push_rejected_no_message"), tplStagedChanges, &form)
+				return
+			}
+			flashError, err := ctx.HTMLString(string(tplAlertDetails), map[string]interface{}{
+				"Message": ctx.Tr("repo.editor.push_rejected"),
+				"Summary": ctx.Tr("repo.editor.push_rejected_summary"),
+				"Details": utils.SanitizeFlashErrorString(errPushRej.Message),
+			})
+			if err != nil {
+				ctx.ServerError("StagedChangesPost.HTMLString", err)
+				return
+			}
+			ctx.RenderWithErr(flashError, tplStagedChanges, &form)
+		default:
+			ctx.ServerError("ChangeRepoFiles", err)
+		}
+		return
+	}
+
+	// the staged changes have been committed
+	if err := saveStagedChanges(ctx, new(stagedChanges)); err != nil {
+		ctx.ServerError("saveStagedChanges", err)
+		return
+	}
+	if form.CommitChoice == frmCommitChoiceNewBranch && ctx.Repo.Repository.UnitEnabled(models.UnitTypePullRequests) {
+		ctx.Redirect(ctx.Repo.RepoLink + "/compare/" + util.PathEscapeSegments(ctx.Repo.BranchName) + "..." + util.PathEscapeSegments(form.NewBranchName))
+	} else {
+		ctx.Redirect(ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(branchName))
+	}
+}
+
+// UnstageChangePost removes a staged change of a file of a branch, or all of them if no path is given
+func UnstageChangePost(ctx *context.Context) {
+	staged := getStagedChanges(ctx)
+	if treePath := ctx.Query("tree_path"); len(treePath) > 0 {
+		staged.unstage(treePath)
+	} else {
+		staged.Changes = nil
+	}
+	if err := saveStagedChanges(ctx, staged); err != nil {
+		ctx.ServerError("saveStagedChanges", err)
+		return
+	}
+	if len(staged.Changes) == 0 {
+		ctx.Redirect(ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL())
+		return
+	}
+	ctx.Redirect(ctx.Repo.RepoLink + "/_staged/" + util.PathEscapeSegments(ctx.Repo.BranchName))
+}
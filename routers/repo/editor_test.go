@@ -81,3 +81,36 @@ func TestGetClosestParentWithFiles(t *testing.T) {
 		assert.Equal(t, expectedTreePath, treePath)
 	}
 }
+
+func TestStageChanges(t *testing.T) {
+	staged := new(stagedChanges)
+	staged.stage(&stagedChange{Operation: "create", TreePath: "new.txt", FromTreePath: "new.txt", Content: "a"}, "commit1")
+	staged.stage(&stagedChange{Operation: "update", TreePath: "a.txt", FromTreePath: "a.txt", Content: "b"}, "commit2")
+	assert.Equal(t, "commit1", staged.LastCommitID)
+	assert.Len(t, staged.Changes, 2)
+
+	// editing and moving a staged new file keeps creating it
+	staged.stage(&stagedChange{Operation: "update", TreePath: "dir/new.txt", FromTreePath: "new.txt", Content: "c"}, "commit2")
+	assert.Len(t, staged.Changes, 2)
+	assert.Equal(t, &stagedChange{Operation: "create", TreePath: "dir/new.txt", FromTreePath: "new.txt", Content: "c"}, staged.Changes[0])
+
+	// moving a staged update keeps the original path
+	staged.stage(&stagedChange{Operation: "update", TreePath: "b.txt", FromTreePath: "a.txt", Content: "d"}, "commit2")
+	assert.Equal(t, &stagedChange{Operation: "update", TreePath: "b.txt", FromTreePath: "a.txt", Content: "d"}, staged.get("b.txt"))
+	assert.Nil(t, staged.get("a.txt"))
+
+	// deleting a moved file deletes it at its original path
+	staged.stage(&stagedChange{Operation: "delete", TreePath: "b.txt", FromTreePath: "b.txt"}, "commit2")
+	assert.Equal(t, &stagedChange{Operation: "delete", TreePath: "a.txt", FromTreePath: "a.txt"}, staged.get("a.txt"))
+
+	// recreating a deleted file updates it
+	staged.stage(&stagedChange{Operation: "create", TreePath: "a.txt", FromTreePath: "a.txt", Content: "e"}, "commit2")
+	assert.Equal(t, &stagedChange{Operation: "update", TreePath: "a.txt", FromTreePath: "a.txt", Content: "e"}, staged.get("a.txt"))
+
+	// deleting a staged new file drops it
+	staged.stage(&stagedChange{Operation: "delete", TreePath: "dir/new.txt", FromTreePath: "dir/new.txt"}, "commit2")
+	assert.Len(t, staged.Changes, 1)
+
+	staged.unstage("a.txt")
+	assert.Empty(t, staged.Changes)
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"html"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/embed"
+)
+
+// embedContentSecurityPolicy only allows the inline styles of the snippets, which can be framed by any site
+const embedContentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors *"
+
+// EmbedFile serves lines of a file at a commit as a self-contained HTML snippet, which can be
+// embedded in the pages of other sites, e.g. in an iframe
+func EmbedFile(ctx *context.Context) {
+	lineStart, lineEnd, err := embed.ParseLines(ctx.Query("lines"))
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snippet, err := embed.Render(ctx.Repo.Repository, ctx.Repo.Commit, ctx.Repo.TreePath, lineStart, lineEnd)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("Render", nil)
+		} else if err == embed.ErrNotEmbeddable {
+			ctx.Error(http.StatusUnprocessableEntity, err.Error())
+		} else {
+			ctx.ServerError("Render", err)
+		}
+		return
+	}
+
+	ctx.Resp.Header().Del("X-Frame-Options")
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Security-Policy", embedContentSecurityPolicy)
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	page := `<!DOCTYPE html><html><head><meta charset="utf-8"><title>` + html.EscapeString(ctx.Repo.TreePath) +
+		`</title><base target="_blank"></head><body style="margin:0">` + snippet.HTML + "</body></html>\n"
+	if _, err := ctx.Resp.Write([]byte(page)); err != nil {
+		log.Error("EmbedFile: Write: %v", err)
+	}
+}
@@ -27,10 +27,10 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
-	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
@@ -136,6 +136,7 @@ func HTTP(ctx *context.Context) {
 	var (
 		askAuth      = !isPublicPull || setting.Service.RequireSignInView
 		authUser     *models.User
+		accessToken  *models.AccessToken
 		authUsername string
 		authPasswd   string
 		environ      []string
@@ -204,15 +205,15 @@ func HTTP(ctx *context.Context) {
 			// Assume password is a token.
 			token, err := models.GetAccessTokenBySHA(authToken)
 			if err == nil {
+				accessToken = token
 				authUser, err = models.GetUserByID(token.UID)
 				if err != nil {
 					ctx.ServerError("GetUserByID", err)
 					return
 				}
 
-				token.UpdatedUnix = timeutil.TimeStampNow()
-				if err = models.UpdateAccessToken(token); err != nil {
-					ctx.ServerError("UpdateAccessToken", err)
+				if err = models.UpdateAccessTokenLastUsed(token, ctx.RemoteAddr()); err != nil {
+					ctx.ServerError("UpdateAccessTokenLastUsed", err)
 				}
 			} else if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
 				log.Error("GetAccessTokenBySha: %v", err)
@@ -232,6 +233,7 @@ func HTTP(ctx *context.Context) {
 				}
 
 				if authUser == nil {
+					notification.NotifyAuthFailure(authUsername, ctx.RemoteAddr(), structs.HookAuthFailureBasic)
 					ctx.HandleText(http.StatusUnauthorized, fmt.Sprintf("invalid credentials from %s", ctx.RemoteAddr()))
 					return
 				}
@@ -245,6 +247,18 @@ func HTTP(ctx *context.Context) {
 					ctx.ServerError("IsErrTwoFactorNotEnrolled", err)
 					return
 				}
+
+				// the users who had to enroll into two-factor authentication before the end of their grace period
+				// can only use their access tokens
+				twoFactorStatus, err := models.GetTwoFactorStatus(authUser)
+				if err != nil {
+					ctx.ServerError("GetTwoFactorStatus", err)
+					return
+				}
+				if twoFactorStatus.IsEnforced() {
+					ctx.HandleText(http.StatusUnauthorized, "Two-factor authentication is required, please enroll on the user settings page and use a personal access token to perform HTTP/HTTPS operations")
+					return
+				}
 			}
 		}
 
@@ -259,6 +273,9 @@ func HTTP(ctx *context.Context) {
 				ctx.ServerError("GetUserRepoPermission", err)
 				return
 			}
+			if accessToken != nil {
+				perm = accessToken.RestrictPermission(repo, perm)
+			}
 
 			if !perm.CanAccess(accessMode, unitType) {
 				ctx.HandleText(http.StatusForbidden, "User permission denied")
@@ -269,6 +286,9 @@ func HTTP(ctx *context.Context) {
 				ctx.HandleText(http.StatusForbidden, "mirror repository is read-only")
 				return
 			}
+		} else if accessToken != nil && accessToken.IsRestricted() {
+			ctx.HandleText(http.StatusForbidden, "User permission denied")
+			return
 		}
 
 		environ = []string{
@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"image"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/imagediff"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// decodeImageDiffImage decodes an image of a commit of the repository for ImageDiff, it writes the
+// error to the response if the image can't be compared
+func decodeImageDiffImage(ctx *context.Context, commitID, name string) image.Image {
+	commit, err := ctx.Repo.GitRepo.GetCommit(commitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetCommit", err)
+		} else {
+			ctx.ServerError("GetCommit", err)
+		}
+		return nil
+	}
+	dataRc, _, err := openCompareBlob(ctx.Repo.Repository, commit, name)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("openCompareBlob", err)
+		} else {
+			ctx.ServerError("openCompareBlob", err)
+		}
+		return nil
+	}
+	defer dataRc.Close()
+
+	img, err := imagediff.Decode(dataRc)
+	if err != nil {
+		// the image isn't in a supported format or it's too large
+		log.Debug("ImageDiff: unable to decode %s of commit %s: %v", name, commitID, err)
+		ctx.Error(http.StatusUnprocessableEntity, err.Error())
+		return nil
+	}
+	return img
+}
+
+// ImageDiff serves a PNG image highlighting the pixels of an image which changed between two commits
+func ImageDiff(ctx *context.Context) {
+	path := ctx.Query("path")
+	oldPath := ctx.Query("old_path")
+	if len(oldPath) == 0 {
+		oldPath = path
+	}
+
+	before := decodeImageDiffImage(ctx, ctx.Params(":before"), oldPath)
+	if ctx.Written() {
+		return
+	}
+	after := decodeImageDiffImage(ctx, ctx.Params(":after"), path)
+	if ctx.Written() {
+		return
+	}
+
+	diff, changed := imagediff.Diff(before, after)
+	ctx.Resp.Header().Set("Content-Type", "image/png")
+	ctx.Resp.Header().Set("X-Gitea-Changed-Pixels", strconv.Itoa(changed))
+	// the images of the commits never change
+	ctx.Resp.Header().Set("Cache-Control", "private, max-age=86400")
+	if err := png.Encode(ctx.Resp, diff); err != nil {
+		log.Error("ImageDiff: Encode: %v", err)
+	}
+}
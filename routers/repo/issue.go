@@ -11,9 +11,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
@@ -22,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
@@ -120,6 +123,8 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 
 	var (
 		assigneeID  = ctx.QueryInt64("assignee")
+		parentIndex = ctx.QueryInt64("parent")
+		parentID    int64
 		posterID    int64
 		mentionedID int64
 		forceEmpty  bool
@@ -152,6 +157,19 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 		keyword = ""
 	}
 
+	if parentIndex > 0 {
+		parent, err := models.GetIssueByIndex(repo.ID, parentIndex)
+		if err == nil {
+			parentID = parent.ID
+			ctx.Data["ParentIssue"] = parent
+		} else if models.IsErrIssueNotExist(err) {
+			forceEmpty = true
+		} else {
+			ctx.ServerError("GetIssueByIndex", err)
+			return
+		}
+	}
+
 	var issueIDs []int64
 	if len(keyword) > 0 {
 		issueIDs, err = issue_indexer.SearchIssuesByKeyword([]int64{repo.ID}, keyword)
@@ -172,6 +190,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			RepoID:      repo.ID,
 			Labels:      selectLabels,
 			MilestoneID: milestoneID,
+			ParentID:    parentID,
 			AssigneeID:  assigneeID,
 			MentionedID: mentionedID,
 			PosterID:    posterID,
@@ -223,6 +242,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			MentionedID:  mentionedID,
 			MilestoneIDs: mileIDs,
 			ProjectID:    projectID,
+			ParentID:     parentID,
 			IsClosed:     util.OptionalBoolOf(isShowClosed),
 			IsPull:       isPullOption,
 			LabelIDs:     labelIDs,
@@ -241,6 +261,18 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 		return
 	}
 
+	if !isPullOption.IsTrue() {
+		issueIDs := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+		ctx.Data["SubIssuesProgress"], err = models.GetSubIssuesProgress(issueIDs)
+		if err != nil {
+			ctx.ServerError("GetSubIssuesProgress", err)
+			return
+		}
+	}
+
 	var commitStatus = make(map[int64]*models.CommitStatus, len(issues))
 
 	// Get posters.
@@ -328,6 +360,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	ctx.Data["SortType"] = sortType
 	ctx.Data["MilestoneID"] = milestoneID
 	ctx.Data["AssigneeID"] = assigneeID
+	ctx.Data["ParentIndex"] = parentIndex
 	ctx.Data["IsShowClosed"] = isShowClosed
 	ctx.Data["Keyword"] = keyword
 	if isShowClosed {
@@ -343,6 +376,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	pager.AddParam(ctx, "labels", "SelectLabels")
 	pager.AddParam(ctx, "milestone", "MilestoneID")
 	pager.AddParam(ctx, "assignee", "AssigneeID")
+	pager.AddParam(ctx, "parent", "ParentIndex")
 	ctx.Data["Page"] = pager
 }
 
@@ -715,6 +749,17 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 	for _, filename := range templateCandidates {
 		templateContent, found := getFileContentFromDefaultBranch(ctx, filename)
 		if found {
+			if issue_template.IsForm(filename) {
+				form, err := issue_template.Unmarshal(filename, []byte(templateContent))
+				if err != nil {
+					log.Debug("invalid issue form %s [%s]: %v", filename, ctx.Repo.Repository.FullName(), err)
+					continue
+				}
+				setIssueForm(ctx, form, nil)
+				setTemplateLabels(ctx, form.Labels)
+				return
+			}
+
 			var meta api.IssueTemplate
 			templateBody, err := markdown.ExtractMetadata(templateContent, &meta)
 			if err != nil {
@@ -724,24 +769,64 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 			}
 			ctx.Data[issueTemplateTitleKey] = meta.Title
 			ctx.Data[ctxDataKey] = templateBody
-			labelIDs := make([]string, 0, len(meta.Labels))
-			if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", models.ListOptions{}); err == nil {
-				for _, metaLabel := range meta.Labels {
-					for _, repoLabel := range repoLabels {
-						if strings.EqualFold(repoLabel.Name, metaLabel) {
-							repoLabel.IsChecked = true
-							labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
-							break
-						}
-					}
+			setTemplateLabels(ctx, meta.Labels)
+			return
+		}
+	}
+}
+
+// setTemplateLabels checks the labels of the repository named by a template
+func setTemplateLabels(ctx *context.Context, names []string) {
+	labelIDs := make([]string, 0, len(names))
+	if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", models.ListOptions{}); err == nil {
+		for _, metaLabel := range names {
+			for _, repoLabel := range repoLabels {
+				if strings.EqualFold(repoLabel.Name, metaLabel) {
+					repoLabel.IsChecked = true
+					labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
+					break
 				}
-				ctx.Data["Labels"] = repoLabels
 			}
-			ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
-			ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
-			return
 		}
+		ctx.Data["Labels"] = repoLabels
 	}
+	ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
+	ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
+}
+
+// getIssueForm returns the issue form of the default branch with the file name, nil if there is
+// no such valid form
+func getIssueForm(ctx *context.Context, name string) *api.IssueTemplate {
+	if !issue_template.IsForm(name) {
+		return nil
+	}
+	for _, dirName := range context.IssueTemplateDirCandidates {
+		filename := path.Join(dirName, path.Base(name))
+		templateContent, found := getFileContentFromDefaultBranch(ctx, filename)
+		if !found {
+			continue
+		}
+		form, err := issue_template.Unmarshal(filename, []byte(templateContent))
+		if err != nil {
+			log.Debug("invalid issue form %s [%s]: %v", filename, ctx.Repo.Repository.FullName(), err)
+			return nil
+		}
+		return form
+	}
+	return nil
+}
+
+// setIssueForm sets the issue form to render instead of the content editor, with the values
+// submitted so far
+func setIssueForm(ctx *context.Context, form *api.IssueTemplate, values url.Values) {
+	for _, field := range form.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			field.Attributes.Value = markdown.RenderString(field.Attributes.Value, ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas())
+		}
+	}
+	ctx.Data[issueTemplateTitleKey] = form.Title
+	ctx.Data["IssueTemplateForm"] = form
+	ctx.Data["IssueFormValues"] = values
 }
 
 // NewIssue render creating issue page
@@ -938,6 +1023,29 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		return
 	}
 
+	if len(form.Template) > 0 {
+		issueForm := getIssueForm(ctx, form.Template)
+		if issueForm == nil {
+			ctx.Error(http.StatusBadRequest, "GetIssueForm")
+			return
+		}
+		setIssueForm(ctx, issueForm, ctx.Req.Form)
+		if err := issue_template.ValidateValues(issueForm, ctx.Req.Form); err != nil {
+			if !issue_template.IsErrInvalidFieldValue(err) {
+				ctx.ServerError("ValidateValues", err)
+				return
+			}
+			fieldErr := err.(issue_template.ErrInvalidFieldValue)
+			if len(fieldErr.Option) > 0 {
+				ctx.RenderWithErr(ctx.Tr("repo.issues.form.option_required", fieldErr.Option), tplIssueNew, form)
+			} else {
+				ctx.RenderWithErr(ctx.Tr("repo.issues.form.field_invalid", fieldErr.Field.Attributes.Label), tplIssueNew, form)
+			}
+			return
+		}
+		form.Content = issue_template.RenderToMarkdown(issueForm, ctx.Req.Form)
+	}
+
 	if util.IsEmptyString(form.Title) {
 		ctx.RenderWithErr(ctx.Tr("repo.issues.new.title_empty"), tplIssueNew, form)
 		return
@@ -1007,6 +1115,19 @@ func commentTag(repo *models.Repository, poster *models.User, issue *models.Issu
 	return models.CommentTagNone, nil
 }
 
+// loadSavedReplies loads the saved replies which the signed user can insert in comments
+func loadSavedReplies(ctx *context.Context) {
+	if !ctx.IsSigned {
+		return
+	}
+	replies, err := models.GetAvailableSavedReplies(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("GetAvailableSavedReplies", err)
+		return
+	}
+	ctx.Data["SavedReplies"] = replies
+}
+
 func getBranchData(ctx *context.Context, issue *models.Issue) {
 	ctx.Data["BaseBranch"] = nil
 	ctx.Data["HeadBranch"] = nil
@@ -1109,6 +1230,17 @@ func ViewIssue(ctx *context.Context) {
 			ctx.InternalServerError(err)
 			return
 		}
+
+		ctx.Data["IssueReminders"], err = models.GetIssueReminders(ctx.User.ID, issue.ID)
+		if err != nil {
+			ctx.ServerError("GetIssueReminders", err)
+			return
+		}
+
+		loadSavedReplies(ctx)
+		if ctx.Written() {
+			return
+		}
 	}
 	ctx.Data["IssueWatch"] = iw
 
@@ -1129,6 +1261,16 @@ func ViewIssue(ctx *context.Context) {
 		if ctx.Written() {
 			return
 		}
+		prepareViewPullStack(ctx, issue.PullRequest)
+		if ctx.Written() {
+			return
+		}
+		reviewEnvironments, err := models.GetReviewEnvironments(issue.PullRequest.ID, false)
+		if err != nil {
+			ctx.ServerError("GetReviewEnvironments", err)
+			return
+		}
+		ctx.Data["ReviewEnvironments"] = reviewEnvironments
 	}
 
 	// Metas.
@@ -1378,6 +1520,8 @@ func ViewIssue(ctx *context.Context) {
 	// Combine multiple label assignments into a single comment
 	combineLabelComments(issue)
 
+	filterTimelineComments(ctx, issue)
+
 	getBranchData(ctx, issue)
 	if issue.IsPull {
 		pull := issue.PullRequest
@@ -1432,6 +1576,19 @@ func ViewIssue(ctx *context.Context) {
 		}
 		prConfig := prUnit.PullRequestsConfig()
 
+		if err = pull.LoadProtectedBranch(); err != nil {
+			ctx.ServerError("LoadProtectedBranch", err)
+			return
+		}
+		// Merge commits can't be pushed to branches which require a linear history
+		if pull.ProtectedBranch != nil && pull.ProtectedBranch.RequireLinearHistory {
+			linearConfig := *prConfig
+			linearConfig.AllowMerge = false
+			linearConfig.AllowRebaseMerge = false
+			prConfig = &linearConfig
+		}
+		ctx.Data["PullRequestsConfig"] = prConfig
+
 		// Check correct values and select default
 		if ms, ok := ctx.Data["MergeStyle"].(models.MergeStyle); !ok ||
 			!prConfig.IsMergeStyleAllowed(ms) {
@@ -1447,10 +1604,6 @@ func ViewIssue(ctx *context.Context) {
 				ctx.Data["MergeStyle"] = ""
 			}
 		}
-		if err = pull.LoadProtectedBranch(); err != nil {
-			ctx.ServerError("LoadProtectedBranch", err)
-			return
-		}
 		if pull.ProtectedBranch != nil {
 			cnt := pull.ProtectedBranch.GetGrantedApprovalsCount(pull)
 			ctx.Data["IsBlockedByApprovals"] = !pull.ProtectedBranch.HasEnoughApprovals(pull)
@@ -1462,12 +1615,39 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["ChangedProtectedFiles"] = pull.ChangedProtectedFiles
 			ctx.Data["IsBlockedByChangedProtectedFiles"] = len(pull.ChangedProtectedFiles) != 0
 			ctx.Data["ChangedProtectedFilesNum"] = len(pull.ChangedProtectedFiles)
+			if pull.ProtectedBranch.RequireCodeOwnerApproval {
+				filesMissingApproval, err := pull_service.GetFilesMissingCodeOwnerApproval(pull)
+				if err != nil {
+					ctx.ServerError("GetFilesMissingCodeOwnerApproval", err)
+					return
+				}
+				ctx.Data["IsBlockedByCodeOwners"] = len(filesMissingApproval) != 0
+				ctx.Data["FilesMissingCodeOwnerApproval"] = filesMissingApproval
+			}
+			if len(pull.ProtectedBranch.ProtectedPathRules) > 0 {
+				unapprovedPaths, err := pull_service.GetUnapprovedProtectedPaths(pull)
+				if err != nil {
+					ctx.ServerError("GetUnapprovedProtectedPaths", err)
+					return
+				}
+				ctx.Data["IsBlockedByProtectedPaths"] = len(unapprovedPaths) != 0
+				ctx.Data["UnapprovedProtectedPaths"] = unapprovedPaths
+			}
+			if pull.ProtectedBranch.RequireSignOff && !issue.IsClosed {
+				commitsMissingSignOff, err := pull_service.GetCommitsMissingSignOff(pull)
+				if err != nil {
+					ctx.ServerError("GetCommitsMissingSignOff", err)
+					return
+				}
+				ctx.Data["IsBlockedBySignOff"] = len(commitsMissingSignOff) != 0
+				ctx.Data["CommitsMissingSignOff"] = commitsMissingSignOff
+			}
 		}
 		ctx.Data["WillSign"] = false
 		if ctx.User != nil {
 			sign, key, _, err := pull.SignMerge(ctx.User, pull.BaseRepo.RepoPath(), pull.BaseBranch, pull.GetGitRefName())
 			ctx.Data["WillSign"] = sign
-			ctx.Data["SigningKey"] = key
+			ctx.Data["SigningKey"] = models.DisplaySigningKey(key)
 			if err != nil {
 				if models.IsErrWontSign(err) {
 					ctx.Data["WontSignReason"] = err.(*models.ErrWontSign).Reason
@@ -1497,6 +1677,24 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	// Get sub-issues
+	if !issue.IsPull {
+		if err = issue.LoadParent(); err != nil {
+			ctx.ServerError("LoadParent", err)
+			return
+		}
+		ctx.Data["SubIssues"], err = models.GetSubIssues(issue.ID)
+		if err != nil {
+			ctx.ServerError("GetSubIssues", err)
+			return
+		}
+		ctx.Data["SubIssuesProgress"], err = issue.GetSubIssuesProgress()
+		if err != nil {
+			ctx.ServerError("GetSubIssuesProgress", err)
+			return
+		}
+	}
+
 	ctx.Data["Participants"] = participants
 	ctx.Data["NumParticipants"] = len(participants)
 	ctx.Data["Issue"] = issue
@@ -1506,6 +1704,9 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
 	ctx.Data["HasProjectsWritePermission"] = ctx.Repo.CanWrite(models.UnitTypeProjects)
 	ctx.Data["IsRepoAdmin"] = ctx.IsSigned && (ctx.Repo.IsAdmin() || ctx.User.IsAdmin)
+	ctx.Data["CanProposeFix"] = ctx.IsSigned && !issue.IsPull && !issue.IsClosed && !ctx.Repo.Repository.IsArchived &&
+		!ctx.Repo.Repository.IsEmpty && ctx.Repo.CanRead(models.UnitTypeCode) &&
+		ctx.Repo.Repository.CanEnablePulls() && ctx.Repo.CanRead(models.UnitTypePullRequests)
 	ctx.Data["LockReasons"] = setting.Repository.Issue.LockReasons
 	ctx.Data["RefEndName"] = git.RefEndName(issue.Ref)
 	ctx.HTML(200, tplIssueView)
@@ -2003,12 +2204,26 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 		}
 	}()
 
+	content, remindAts, err := issue_service.ExtractRemindCommands(form.Content, time.Now())
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.reminder.invalid"))
+		return
+	}
+	if len(remindAts) > 0 {
+		if err := issue_service.CreateReminders(ctx.User, issue, remindAts); err != nil {
+			ctx.ServerError("CreateReminders", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.issues.reminder.created"))
+		form.Content = content
+	}
+
 	// Fix #321: Allow empty comments, as long as we have attachments.
 	if len(form.Content) == 0 && len(attachments) == 0 {
 		return
 	}
 
-	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Content, attachments)
+	comment, err = comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Content, attachments)
 	if err != nil {
 		ctx.ServerError("CreateIssueComment", err)
 		return
@@ -2326,6 +2541,64 @@ func filterXRefComments(ctx *context.Context, issue *models.Issue) error {
 	return nil
 }
 
+// issueTimelineFilter is a preset of the comment types the timeline of an issue
+// can be filtered by, the type names are the same as accepted by the timeline API
+type issueTimelineFilter struct {
+	Name  string
+	Types []models.CommentType
+}
+
+// Query returns the value of the types query parameter selecting the filter
+func (f issueTimelineFilter) Query() string {
+	names := make([]string, len(f.Types))
+	for i, tp := range f.Types {
+		names[i] = tp.String()
+	}
+	return strings.Join(names, ",")
+}
+
+var issueTimelineFilters = []issueTimelineFilter{
+	{Name: "comments", Types: []models.CommentType{models.CommentTypeComment, models.CommentTypeReview, models.CommentTypeCode}},
+	{Name: "reviews", Types: []models.CommentType{models.CommentTypeReview, models.CommentTypeCode, models.CommentTypeReviewRequest}},
+	{Name: "labels", Types: []models.CommentType{models.CommentTypeLabel}},
+	{Name: "commits", Types: []models.CommentType{models.CommentTypePullPush, models.CommentTypeCommitRef}},
+	{Name: "references", Types: []models.CommentType{models.CommentTypeIssueRef, models.CommentTypeCommentRef, models.CommentTypePullRef, models.CommentTypeCommitRef}},
+}
+
+// filterTimelineComments only keeps the comments of the types given in the query
+func filterTimelineComments(ctx *context.Context, issue *models.Issue) {
+	ctx.Data["TimelineFilters"] = issueTimelineFilters
+	ctx.Data["TimelineFilter"] = ""
+	query := ctx.Query("types")
+	if len(query) == 0 {
+		return
+	}
+
+	types := make(map[models.CommentType]bool)
+	for _, name := range strings.Split(query, ",") {
+		if tp := models.AsCommentType(strings.TrimSpace(name)); tp != models.CommentTypeUnknown {
+			types[tp] = true
+		}
+	}
+	if len(types) == 0 {
+		return
+	}
+	ctx.Data["TimelineTypes"] = query
+	for _, filter := range issueTimelineFilters {
+		if filter.Query() == query {
+			ctx.Data["TimelineFilter"] = filter.Name
+		}
+	}
+
+	comments := issue.Comments[:0]
+	for _, comment := range issue.Comments {
+		if types[comment.Type] {
+			comments = append(comments, comment)
+		}
+	}
+	issue.Comments = comments
+}
+
 // GetIssueAttachments returns attachments for the issue
 func GetIssueAttachments(ctx *context.Context) {
 	issue := GetActionIssue(ctx)
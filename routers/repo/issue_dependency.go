@@ -6,12 +6,16 @@ package repo
 
 import (
 	"net/http"
+	"sort"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
 )
 
+const tplDependencyGraph base.TplName = "repo/issue/dependency_graph"
+
 // AddDependency adds new dependencies
 func AddDependency(ctx *context.Context) {
 	issueIndex := ctx.ParamsInt64("index")
@@ -127,3 +131,101 @@ func RemoveDependency(ctx *context.Context) {
 	// Redirect
 	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
 }
+
+// dependencyGraphLevel is a column of the dependency graph view
+type dependencyGraphLevel struct {
+	Issues []*dependencyGraphIssue
+}
+
+// dependencyGraphIssue is an issue of the dependency graph view with the issues blocking it
+type dependencyGraphIssue struct {
+	*models.Issue
+	BlockedBy []*models.Issue
+	// whether the graph has been requested for the issue or for its milestone
+	IsRoot bool
+}
+
+// renderDependencyGraph renders the issues of the graph by level, the roots are highlighted
+func renderDependencyGraph(ctx *context.Context, graph *models.IssueDependencyGraph, isRoot func(*models.Issue) bool) {
+	if err := graph.FilterReadable(ctx.User); err != nil {
+		ctx.ServerError("FilterReadable", err)
+		return
+	}
+
+	issues := make(map[int64]*dependencyGraphIssue, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		issues[issue.ID] = &dependencyGraphIssue{Issue: issue, IsRoot: isRoot(issue)}
+	}
+	for _, dep := range graph.Dependencies {
+		issues[dep.IssueID].BlockedBy = append(issues[dep.IssueID].BlockedBy, issues[dep.DependencyID].Issue)
+	}
+
+	levels := make([]*dependencyGraphLevel, 0, 5)
+	levelByIssue := graph.Levels()
+	for _, issue := range graph.Issues {
+		level := levelByIssue[issue.ID]
+		for len(levels) <= level {
+			levels = append(levels, &dependencyGraphLevel{})
+		}
+		levels[level].Issues = append(levels[level].Issues, issues[issue.ID])
+	}
+	for _, level := range levels {
+		sort.Slice(level.Issues, func(i, j int) bool {
+			if level.Issues[i].RepoID != level.Issues[j].RepoID {
+				return level.Issues[i].RepoID < level.Issues[j].RepoID
+			}
+			return level.Issues[i].Index < level.Issues[j].Index
+		})
+	}
+
+	ctx.Data["PageIsIssueList"] = true
+	ctx.Data["Levels"] = levels
+	ctx.Data["Truncated"] = graph.Truncated
+	ctx.HTML(http.StatusOK, tplDependencyGraph)
+}
+
+// IssueDependencyGraph renders the issues which transitively block or are blocked by an issue
+func IssueDependencyGraph(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	graph, err := models.GetIssueDependencyGraph([]int64{issue.ID})
+	if err != nil {
+		ctx.ServerError("GetIssueDependencyGraph", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.issues.dependency.graph")
+	ctx.Data["Issue"] = issue
+	renderDependencyGraph(ctx, graph, func(i *models.Issue) bool {
+		return i.ID == issue.ID
+	})
+}
+
+// MilestoneDependencyGraph renders the issues which transitively block or are blocked by the
+// issues of a milestone
+func MilestoneDependencyGraph(ctx *context.Context) {
+	milestone, err := models.GetMilestoneByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrMilestoneNotExist(err) {
+			ctx.NotFound("GetMilestoneByRepoID", err)
+			return
+		}
+		ctx.ServerError("GetMilestoneByRepoID", err)
+		return
+	}
+
+	graph, err := models.GetMilestoneDependencyGraph(milestone.ID)
+	if err != nil {
+		ctx.ServerError("GetMilestoneDependencyGraph", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.issues.dependency.graph")
+	ctx.Data["Milestone"] = milestone
+	renderDependencyGraph(ctx, graph, func(i *models.Issue) bool {
+		return i.MilestoneID == milestone.ID
+	})
+}
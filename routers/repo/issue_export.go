@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const tplIssueExport base.TplName = "repo/issue/export"
+
+// deriveIssuesExport returns the request to export the issues of the comma separated
+// issue_ids query parameter
+func deriveIssuesExport(ctx *context.Context) *issue_service.ExportRequest {
+	issues := getActionIssues(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	if len(issues) == 0 || len(issues) > issue_service.MaxExportIssues {
+		ctx.Error(http.StatusBadRequest)
+		return nil
+	}
+	for _, issue := range issues {
+		if issue.RepoID != ctx.Repo.Repository.ID {
+			ctx.NotFound("IssueNotInRepository", nil)
+			return nil
+		}
+	}
+
+	locale, render, repo := ctx.Locale, ctx.Render, ctx.Repo.Repository
+	r, err := issue_service.NewExportRequest(repo, issues, locale.Language(), func(issues []*issue_service.ExportedIssue) ([]byte, error) {
+		return render.HTMLBytes(string(tplIssueExport), map[string]interface{}{
+			"i18n":       locale,
+			"Lang":       locale.Language(),
+			"Repository": repo,
+			"Issues":     issues,
+			"ExportedAt": time.Now().Format(time.RFC1123),
+		})
+	})
+	if err != nil {
+		ctx.ServerError("NewExportRequest", err)
+		return nil
+	}
+	return r
+}
+
+// InitiateIssuesExport starts to generate the printable document of the selected issues and
+// reports whether it is complete
+func InitiateIssuesExport(ctx *context.Context) {
+	r := deriveIssuesExport(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	complete := r.IsComplete()
+	if !complete {
+		r = issue_service.ExportIssues(r)
+		complete = r.TimedWaitForCompletion(ctx.Req.Context(), 2*time.Second)
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"complete": complete,
+	})
+}
+
+// ExportIssues serves the printable document of the selected issues, the old documents are
+// deleted by the issue_exports_cleanup cron task
+func ExportIssues(ctx *context.Context) {
+	r := deriveIssuesExport(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !r.IsComplete() {
+		r = issue_service.ExportIssues(r)
+		if !r.TimedWaitForCompletion(ctx.Req.Context(), time.Minute) {
+			ctx.ServerError("ExportIssues", errors.New("the export is not complete"))
+			return
+		}
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeFile(ctx.Resp, ctx.Req.Request, r.GetExportPath())
+}
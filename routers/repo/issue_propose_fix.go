@@ -0,0 +1,175 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	repo_service "code.gitea.io/gitea/services/repository"
+
+	"github.com/unknwon/com"
+)
+
+const tplIssueProposeFix base.TplName = "repo/issue/propose_fix"
+
+// getProposeFixIssue returns the open issue whose fix is proposed
+func getProposeFixIssue(ctx *context.Context) *models.Issue {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	if issue.IsPull || issue.IsClosed {
+		ctx.NotFound("ProposeFix", nil)
+		return nil
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.issues.propose_fix.title", issue.Index)
+	ctx.Data["PageIsIssueList"] = true
+	ctx.Data["Issue"] = issue
+	ctx.Data["IsFork"] = !ctx.Repo.CanWrite(models.UnitTypeCode)
+	ctx.Data["HasForkedRepo"] = ctx.User.HasForkedRepo(ctx.Repo.Repository.ID)
+	ctx.Data["LineWrapExtensions"] = strings.Join(setting.Repository.Editor.LineWrapExtensions, ",")
+	return issue
+}
+
+// readEditableFile returns the content of a file if it can be edited online
+func readEditableFile(entry *git.TreeEntry) (string, bool) {
+	if entry.IsDir() || entry.IsSubModule() || entry.IsLink() {
+		return "", false
+	}
+	blob := entry.Blob()
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		return "", false
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		log.Error("DataAsync: %v", err)
+		return "", false
+	}
+	defer dataRc.Close()
+
+	buf, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		log.Error("ReadAll: %v", err)
+		return "", false
+	}
+	// Only text file are editable online.
+	if !base.IsTextFile(buf) {
+		return "", false
+	}
+	content, err := charset.ToUTF8WithErr(buf)
+	if err != nil {
+		log.Error("ToUTF8WithErr: %v", err)
+		return string(buf), true
+	}
+	return content, true
+}
+
+// ProposeFix renders the editor of a file of the default branch to propose a fix of an issue
+func ProposeFix(ctx *context.Context) {
+	issue := getProposeFixIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
+	if err != nil {
+		ctx.ServerError("GetBranchCommit", err)
+		return
+	}
+	ctx.Data["last_commit"] = commit.ID.String()
+	ctx.Data["title"] = issue.Title
+
+	treePath := cleanUploadFileName(ctx.Query("path"))
+	if len(treePath) > 0 {
+		ctx.Data["tree_path"] = treePath
+		ctx.Data["Editorconfig"] = GetEditorConfig(ctx, treePath)
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if !git.IsErrNotExist(err) {
+				ctx.ServerError("GetTreeEntryByPath", err)
+				return
+			}
+			ctx.Data["IsNewFile"] = true
+		} else if content, ok := readEditableFile(entry); ok {
+			ctx.Data["content"] = content
+		} else {
+			ctx.Flash.Error(ctx.Tr("repo.issues.propose_fix.not_editable", treePath), true)
+			ctx.Data["tree_path"] = ""
+		}
+	}
+
+	ctx.HTML(200, tplIssueProposeFix)
+}
+
+// ProposeFixPost commits the change of a file to a new branch and opens a pull request fixing the issue
+func ProposeFixPost(ctx *context.Context, form auth.ProposeFixForm) {
+	issue := getProposeFixIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["Editorconfig"] = GetEditorConfig(ctx, form.TreePath)
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplIssueProposeFix)
+		return
+	}
+
+	treePath := cleanUploadFileName(form.TreePath)
+	if len(treePath) == 0 {
+		ctx.Data["Err_TreePath"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.editor.filename_is_invalid", form.TreePath), tplIssueProposeFix, &form)
+		return
+	}
+
+	message := strings.TrimSpace(form.CommitSummary)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.update", treePath)
+	}
+
+	pr, err := repo_service.ProposeFix(ctx.User, issue, repo_service.ProposeFixOptions{
+		TreePath:     treePath,
+		Content:      form.Content,
+		LastCommitID: form.LastCommit,
+		Message:      message,
+		Title:        form.Title,
+		Description:  form.Description,
+	})
+	if err != nil {
+		switch {
+		case models.IsErrFilePathInvalid(err), models.IsErrFilenameInvalid(err):
+			ctx.Data["Err_TreePath"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.filename_is_invalid", treePath), tplIssueProposeFix, &form)
+		case models.IsErrLFSFileLocked(err):
+			ctx.Data["Err_TreePath"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.upload_file_is_locked", err.(models.ErrLFSFileLocked).Path, err.(models.ErrLFSFileLocked).UserName), tplIssueProposeFix, &form)
+		case models.IsErrCommitIDDoesNotMatch(err), git.IsErrPushOutOfDate(err):
+			ctx.RenderWithErr(ctx.Tr("repo.issues.propose_fix.file_changed", treePath), tplIssueProposeFix, &form)
+		case models.IsErrRepoAlreadyExist(err):
+			ctx.RenderWithErr(ctx.Tr("repo.issues.propose_fix.fork_name_taken", ctx.User.Name+"/"+ctx.Repo.Repository.Name), tplIssueProposeFix, &form)
+		case models.IsErrReachLimitOfRepo(err):
+			ctx.RenderWithErr(ctx.Tr("repo.form.reach_limit_of_creation", ctx.User.MaxCreationLimit()), tplIssueProposeFix, &form)
+		case git.IsErrPushRejected(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.push_rejected_no_message"), tplIssueProposeFix, &form)
+		case models.IsErrUserDoesNotHaveAccessToRepo(err):
+			ctx.NotFound("ProposeFix", err)
+		default:
+			ctx.ServerError("ProposeFix", err)
+		}
+		return
+	}
+
+	log.Trace("Fix of issue %d proposed by pull request %d", issue.ID, pr.ID)
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+}
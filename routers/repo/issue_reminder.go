@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// getReminderIssue returns the issue of the index path parameter if the user can read it
+func getReminderIssue(ctx *context.Context) *models.Issue {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	if !ctx.IsSigned || (ctx.User.ID != issue.PosterID && !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull)) {
+		ctx.Error(http.StatusForbidden)
+		return nil
+	}
+	return issue
+}
+
+// CreateIssueReminder schedules a notification of the issue for the user
+func CreateIssueReminder(ctx *context.Context) {
+	issue := getReminderIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	remindAt, err := issue_service.ParseRemindAt(ctx.Query("remind_at"), time.Now())
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.reminder.invalid"))
+	} else if err := issue_service.CreateReminders(ctx.User, issue, []time.Time{remindAt}); err != nil {
+		ctx.ServerError("CreateReminders", err)
+		return
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.issues.reminder.created"))
+	}
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
+// DeleteIssueReminder cancels a reminder of the user
+func DeleteIssueReminder(ctx *context.Context) {
+	issue := getReminderIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reminder, err := models.GetIssueReminderByID(ctx.User.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueReminderNotExist(err) {
+			ctx.NotFound("GetIssueReminderByID", err)
+		} else {
+			ctx.ServerError("GetIssueReminderByID", err)
+		}
+		return
+	}
+	if reminder.IssueID != issue.ID {
+		ctx.NotFound("GetIssueReminderByID", nil)
+		return
+	}
+
+	if err := models.DeleteIssueReminder(ctx.User.ID, reminder.ID); err != nil && !models.IsErrIssueReminderNotExist(err) {
+		ctx.ServerError("DeleteIssueReminder", err)
+		return
+	}
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
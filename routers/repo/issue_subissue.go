@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// getSubIssues returns the parent issue of the index path parameter and the sub-issue of the
+// child_index form value
+func getSubIssues(ctx *context.Context) (parent, child *models.Issue) {
+	parent = GetActionIssue(ctx)
+	if ctx.Written() {
+		return nil, nil
+	}
+	if parent.IsPull {
+		ctx.NotFound("IsPull", nil)
+		return nil, nil
+	}
+
+	child, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.QueryInt64("child_index"))
+	if err != nil {
+		if !models.IsErrIssueNotExist(err) {
+			ctx.ServerError("GetIssueByIndex", err)
+			return nil, nil
+		}
+		ctx.Flash.Error(ctx.Tr("repo.issues.sub_issues.error_issue_not_exist"))
+		ctx.Redirect(parent.HTMLURL())
+		return nil, nil
+	}
+	return parent, child
+}
+
+// AttachSubIssue makes an issue a sub-issue of the issue
+func AttachSubIssue(ctx *context.Context) {
+	parent, child := getSubIssues(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if child.IsPull {
+		ctx.Flash.Error(ctx.Tr("repo.issues.sub_issues.error_pull"))
+	} else if err := models.AttachSubIssue(parent, child); err != nil {
+		if models.IsErrSubIssueHasParent(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issues.error_has_parent"))
+		} else if models.IsErrCircularSubIssue(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issues.error_circular"))
+		} else {
+			ctx.ServerError("AttachSubIssue", err)
+			return
+		}
+	}
+	ctx.Redirect(parent.HTMLURL())
+}
+
+// DetachSubIssue removes a sub-issue from the issue
+func DetachSubIssue(ctx *context.Context) {
+	parent, child := getSubIssues(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DetachSubIssue(parent, child); err != nil && !models.IsErrSubIssueNotExist(err) {
+		ctx.ServerError("DetachSubIssue", err)
+		return
+	}
+	ctx.RedirectToFirst(ctx.Query("redirect_to"), parent.HTMLURL())
+}
@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/services/gitdiff"
 )
 
 // SetEditorconfigIfExists set editor config as render variable
@@ -60,13 +61,45 @@ func SetDiffViewStyle(ctx *context.Context) {
 	}
 }
 
-// SetWhitespaceBehavior set whitespace behavior as render variable
+// SetWhitespaceBehavior set whitespace behavior and diff context lines as render variables,
+// the choices of signed in users are remembered
 func SetWhitespaceBehavior(ctx *context.Context) {
-	whitespaceBehavior := ctx.Query("whitespace")
-	switch whitespaceBehavior {
-	case "ignore-all", "ignore-eol", "ignore-change":
-		ctx.Data["WhitespaceBehavior"] = whitespaceBehavior
-	default:
-		ctx.Data["WhitespaceBehavior"] = ""
+	var (
+		whitespaceBehavior string
+		contextLines       int
+		query              = ctx.Req.URL.Query()
+	)
+	if ctx.IsSigned {
+		whitespaceBehavior = ctx.User.DiffWhitespaceBehavior
+		contextLines = ctx.User.DiffContextLines
+	}
+	if _, ok := query["whitespace"]; ok {
+		whitespaceBehavior = ctx.Query("whitespace")
+	}
+	if _, ok := query["context"]; ok {
+		contextLines = ctx.QueryInt("context")
+	}
+	if !gitdiff.IsValidWhitespaceBehavior(whitespaceBehavior) {
+		whitespaceBehavior = ""
+	}
+	if contextLines < 0 || contextLines > gitdiff.MaxContextLines {
+		contextLines = 0
+	}
+
+	ctx.Data["WhitespaceBehavior"] = whitespaceBehavior
+	ctx.Data["DiffContextLines"] = contextLines
+	if !ctx.IsSigned {
+		return
+	}
+	if whitespaceBehavior != ctx.User.DiffWhitespaceBehavior {
+		if err := ctx.User.UpdateDiffWhitespaceBehavior(whitespaceBehavior); err != nil {
+			ctx.ServerError("UpdateDiffWhitespaceBehavior", err)
+			return
+		}
+	}
+	if contextLines != ctx.User.DiffContextLines {
+		if err := ctx.User.UpdateDiffContextLines(contextLines); err != nil {
+			ctx.ServerError("UpdateDiffContextLines", err)
+		}
 	}
 }
@@ -36,10 +36,12 @@ import (
 )
 
 const (
-	tplFork        base.TplName = "repo/pulls/fork"
-	tplCompareDiff base.TplName = "repo/diff/compare"
-	tplPullCommits base.TplName = "repo/pulls/commits"
-	tplPullFiles   base.TplName = "repo/pulls/files"
+	tplFork              base.TplName = "repo/pulls/fork"
+	tplCompareDiff       base.TplName = "repo/diff/compare"
+	tplPullCommits       base.TplName = "repo/pulls/commits"
+	tplPullCommitMessage base.TplName = "repo/pulls/commit_message"
+	tplPullFiles         base.TplName = "repo/pulls/files"
+	tplPullFileDiff      base.TplName = "repo/pulls/file_diff"
 
 	pullRequestTemplateKey = "PullRequestTemplate"
 )
@@ -302,6 +304,38 @@ func setMergeTarget(ctx *context.Context, pull *models.PullRequest) {
 	ctx.Data["BaseTarget"] = pull.BaseBranch
 }
 
+// prepareViewPullStack sets the pull request a pull request is stacked on and the pull requests stacked on it
+func prepareViewPullStack(ctx *context.Context, pull *models.PullRequest) {
+	if err := pull.LoadBasePull(); err != nil && !models.IsErrPullRequestNotExist(err) {
+		ctx.ServerError("LoadBasePull", err)
+		return
+	}
+	if pull.BasePull != nil {
+		if err := pull.BasePull.LoadIssue(); err != nil {
+			ctx.ServerError("LoadIssue", err)
+			return
+		}
+		pull.BasePull.Issue.PullRequest = pull.BasePull
+		ctx.Data["StackBasePull"] = pull.BasePull.Issue
+	}
+
+	stacked, err := models.GetStackedPullRequests(pull.ID)
+	if err != nil {
+		ctx.ServerError("GetStackedPullRequests", err)
+		return
+	}
+	issues := make([]*models.Issue, 0, len(stacked))
+	for _, pr := range stacked {
+		if err := pr.LoadIssue(); err != nil {
+			ctx.ServerError("LoadIssue", err)
+			return
+		}
+		pr.Issue.PullRequest = pr
+		issues = append(issues, pr.Issue)
+	}
+	ctx.Data["StackedPulls"] = issues
+}
+
 // PrepareMergedViewPullInfo show meta information for a merged pull request view page
 func PrepareMergedViewPullInfo(ctx *context.Context, issue *models.Issue) *git.CompareInfo {
 	pull := issue.PullRequest
@@ -351,6 +385,19 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 	}
 	ctx.Data["EnableStatusCheck"] = pull.ProtectedBranch != nil && pull.ProtectedBranch.EnableStatusCheck
 
+	defaultMergeTitles := make(map[string]string, 3)
+	defaultMergeBodies := make(map[string]string, 3)
+	for _, mergeStyle := range []models.MergeStyle{models.MergeStyleMerge, models.MergeStyleRebaseMerge, models.MergeStyleSquash} {
+		title, body, err := pull_service.GetDefaultMergeMessage(pull, mergeStyle)
+		if err != nil {
+			ctx.ServerError("GetDefaultMergeMessage", err)
+			return nil
+		}
+		defaultMergeTitles[string(mergeStyle)], defaultMergeBodies[string(mergeStyle)] = title, body
+	}
+	ctx.Data["DefaultMergeTitles"] = defaultMergeTitles
+	ctx.Data["DefaultMergeBodies"] = defaultMergeBodies
+
 	baseGitRepo, err := git.OpenRepository(pull.BaseRepo.RepoPath())
 	if err != nil {
 		ctx.ServerError("OpenRepository", err)
@@ -504,6 +551,13 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 	if pull.IsWorkInProgress() {
 		ctx.Data["IsPullWorkInProgress"] = true
 		ctx.Data["WorkInProgressPrefix"] = pull.GetWorkInProgressPrefix()
+		ctx.Data["CanChangeAutoPublish"] = ctx.IsSigned && (issue.IsPoster(ctx.User.ID) || ctx.Repo.CanWrite(models.UnitTypePullRequests))
+		autoPublish, err := models.GetPullAutoPublishByPullID(pull.ID)
+		if err != nil && !models.IsErrPullAutoPublishNotExist(err) {
+			ctx.ServerError("GetPullAutoPublishByPullID", err)
+			return nil
+		}
+		ctx.Data["IsAutoPublishEnabled"] = autoPublish != nil
 	}
 
 	if pull.IsFilesConflicted() {
@@ -551,10 +605,105 @@ func ViewPullCommits(ctx *context.Context) {
 	ctx.Data["Commits"] = commits
 	ctx.Data["CommitCount"] = commits.Len()
 
+	if err := pull.LoadProtectedBranch(); err != nil {
+		ctx.ServerError("LoadProtectedBranch", err)
+		return
+	}
+	ctx.Data["RequireSignOff"] = pull.ProtectedBranch != nil && pull.ProtectedBranch.RequireSignOff
+
 	getBranchData(ctx, issue)
 	ctx.HTML(200, tplPullCommits)
 }
 
+// commitMessageLine represents a line of a commit message and the comments on it
+type commitMessageLine struct {
+	Num      int64
+	Text     string
+	Comments []*models.Comment
+}
+
+// ViewPullCommitMessage render the message of a commit of a pull request with the review comments on its lines
+func ViewPullCommitMessage(ctx *context.Context) {
+	ctx.Data["PageIsPullList"] = true
+	ctx.Data["PageIsPullCommits"] = true
+
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pull := issue.PullRequest
+
+	var prInfo *git.CompareInfo
+	if pull.HasMerged {
+		prInfo = PrepareMergedViewPullInfo(ctx, issue)
+	} else {
+		prInfo = PrepareViewPullInfo(ctx, issue)
+	}
+	if ctx.Written() {
+		return
+	} else if prInfo == nil {
+		ctx.NotFound("ViewPullCommitMessage", nil)
+		return
+	}
+
+	commitID := ctx.Params(":sha")
+	lines, err := pull_service.GetCommitMessageLines(ctx.Repo.GitRepo, pull, commitID)
+	if err != nil {
+		if models.IsErrCommitMessageLineNotExist(err) {
+			ctx.NotFound("GetCommitMessageLines", err)
+		} else {
+			ctx.ServerError("GetCommitMessageLines", err)
+		}
+		return
+	}
+	comments, err := models.FetchCommitMessageComments(issue, commitID, ctx.User)
+	if err != nil {
+		ctx.ServerError("FetchCommitMessageComments", err)
+		return
+	}
+
+	messageLines := make([]*commitMessageLine, len(lines))
+	for i, line := range lines {
+		messageLines[i] = &commitMessageLine{Num: int64(i + 1), Text: line}
+	}
+	for _, comment := range comments {
+		if comment.Line >= 1 && comment.Line <= int64(len(messageLines)) {
+			messageLines[comment.Line-1].Comments = append(messageLines[comment.Line-1].Comments, comment)
+		}
+	}
+
+	headCommitID, err := ctx.Repo.GitRepo.GetRefCommitID(pull.GetGitRefName())
+	if err != nil {
+		ctx.ServerError("GetRefCommitID", err)
+		return
+	}
+	ctx.Data["CurrentReview"], err = models.GetCurrentReview(ctx.User, issue)
+	if err != nil && !models.IsErrReviewNotExist(err) {
+		ctx.ServerError("GetCurrentReview", err)
+		return
+	}
+
+	ctx.Data["Username"] = ctx.Repo.Owner.Name
+	ctx.Data["Reponame"] = ctx.Repo.Repository.Name
+	ctx.Data["CommitID"] = commitID
+	ctx.Data["AfterCommitID"] = headCommitID
+	ctx.Data["CommitMessageLines"] = messageLines
+	ctx.Data["RequireHighlightJS"] = true
+	ctx.Data["RequireSimpleMDE"] = true
+	ctx.Data["RequireTribute"] = true
+
+	getBranchData(ctx, issue)
+	ctx.HTML(200, tplPullCommitMessage)
+}
+
+// getDiffOptions returns the options of the diffs set by SetWhitespaceBehavior
+func getDiffOptions(ctx *context.Context) gitdiff.DiffOptions {
+	return gitdiff.DiffOptions{
+		WhitespaceBehavior: ctx.Data["WhitespaceBehavior"].(string),
+		ContextLines:       ctx.Data["DiffContextLines"].(int),
+	}
+}
+
 // ViewPullFiles render pull request changed files list page
 func ViewPullFiles(ctx *context.Context) {
 	ctx.Data["PageIsPullList"] = true
@@ -566,12 +715,6 @@ func ViewPullFiles(ctx *context.Context) {
 	}
 	pull := issue.PullRequest
 
-	whitespaceFlags := map[string]string{
-		"ignore-all":    "-w",
-		"ignore-change": "-b",
-		"ignore-eol":    "--ignore-space-at-eol",
-		"":              ""}
-
 	var (
 		diffRepoPath  string
 		startCommitID string
@@ -611,13 +754,23 @@ func ViewPullFiles(ctx *context.Context) {
 	ctx.Data["Reponame"] = ctx.Repo.Repository.Name
 	ctx.Data["AfterCommitID"] = endCommitID
 
-	diff, err := gitdiff.GetDiffRangeWithWhitespaceBehavior(diffRepoPath,
-		startCommitID, endCommitID, setting.Git.MaxGitDiffLines,
-		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
-		whitespaceFlags[ctx.Data["WhitespaceBehavior"].(string)])
-	if err != nil {
-		ctx.ServerError("GetDiffRangeWithWhitespaceBehavior", err)
-		return
+	var diff *gitdiff.Diff
+	if setting.Git.LazyLoadDiffFiles > 0 && prInfo.NumFiles > setting.Git.LazyLoadDiffFiles {
+		// the diff of each file of large pull requests is loaded by ViewPullFileDiff when it's shown
+		diff, err = gitdiff.GetDiffSummary(diffRepoPath, startCommitID, endCommitID)
+		if err != nil {
+			ctx.ServerError("GetDiffSummary", err)
+			return
+		}
+	} else {
+		diff, err = gitdiff.GetDiffRangeWithOptions(diffRepoPath,
+			startCommitID, endCommitID, setting.Git.MaxGitDiffLines,
+			setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
+			getDiffOptions(ctx))
+		if err != nil {
+			ctx.ServerError("GetDiffRangeWithOptions", err)
+			return
+		}
 	}
 
 	if err = diff.LoadComments(issue, ctx.User); err != nil {
@@ -660,27 +813,162 @@ func ViewPullFiles(ctx *context.Context) {
 		}
 	}
 
-	setImageCompareContext(ctx, baseCommit, commit)
+	setImageCompareContext(ctx, ctx.Repo.Repository, baseCommit, ctx.Repo.Repository, commit)
 	setPathsCompareContext(ctx, baseCommit, commit, headTarget)
 
 	ctx.Data["RequireHighlightJS"] = true
 	ctx.Data["RequireSimpleMDE"] = true
 	ctx.Data["RequireTribute"] = true
-	if ctx.Data["Assignees"], err = ctx.Repo.Repository.GetAssignees(); err != nil {
-		ctx.ServerError("GetAssignees", err)
-		return
-	}
+	ctx.Data["ReviewChecklist"] = ctx.Repo.Repository.MustGetUnit(models.UnitTypePullRequests).PullRequestsConfig().ReviewChecklist
 	ctx.Data["CurrentReview"], err = models.GetCurrentReview(ctx.User, issue)
 	if err != nil && !models.IsErrReviewNotExist(err) {
 		ctx.ServerError("GetCurrentReview", err)
 		return
 	}
+	if ctx.IsSigned {
+		ctx.Data["ViewedFiles"], err = pull_service.GetViewedFilesSet(pull, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetViewedFilesSet", err)
+			return
+		}
+	}
 	getBranchData(ctx, issue)
 	ctx.Data["IsIssuePoster"] = ctx.IsSigned && issue.IsPoster(ctx.User.ID)
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
+	loadSavedReplies(ctx)
+	if ctx.Written() {
+		return
+	}
 	ctx.HTML(200, tplPullFiles)
 }
 
+// ViewPullFileDiff renders the diff of a file changed by a pull request, the files page of a large
+// pull request loads the diff of each file separately with it
+func ViewPullFileDiff(ctx *context.Context) {
+	treePath := ctx.Params("*")
+	if !strings.HasSuffix(treePath, "/diff") {
+		ctx.NotFound("ViewPullFileDiff", nil)
+		return
+	}
+	treePath = strings.TrimSuffix(treePath, "/diff")
+	ctx.Data["PageIsPullFiles"] = true
+
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pull := issue.PullRequest
+	gitRepo := ctx.Repo.GitRepo
+
+	headCommitID, err := gitRepo.GetRefCommitID(pull.GetGitRefName())
+	if err != nil {
+		ctx.ServerError("GetRefCommitID", err)
+		return
+	}
+	ctx.Data["AfterCommitID"] = headCommitID
+
+	// the previous path of a renamed file is needed to detect the rename
+	files := []string{treePath}
+	if oldPath := ctx.Query("old_path"); len(oldPath) > 0 && oldPath != treePath {
+		files = append(files, oldPath)
+	}
+	diff, err := gitdiff.GetDiffRangeWithOptions(gitRepo.Path,
+		pull.MergeBase, headCommitID, setting.Git.MaxGitDiffLines,
+		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
+		getDiffOptions(ctx), files...)
+	if err != nil {
+		ctx.ServerError("GetDiffRangeWithOptions", err)
+		return
+	}
+	var file *gitdiff.DiffFile
+	for _, f := range diff.Files {
+		if f.Name == treePath {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		ctx.NotFound("ViewPullFileDiff", nil)
+		return
+	}
+	// the file keeps the index it has in the list of the files of the pull request
+	if index := ctx.QueryInt("index"); index > 0 {
+		file.Index = index
+	}
+
+	if err = diff.LoadComments(issue, ctx.User); err != nil {
+		ctx.ServerError("LoadComments", err)
+		return
+	}
+	if err = pull.LoadProtectedBranch(); err != nil {
+		ctx.ServerError("LoadProtectedBranch", err)
+		return
+	}
+	if pull.ProtectedBranch != nil {
+		if glob := pull.ProtectedBranch.GetProtectedFilePatterns(); len(glob) != 0 {
+			file.IsProtected = pull.ProtectedBranch.IsProtectedFile(glob, file.Name)
+		}
+	}
+	ctx.Data["Diff"] = diff
+	ctx.Data["DiffFile"] = file
+
+	baseCommit, err := gitRepo.GetCommit(pull.MergeBase)
+	if err != nil {
+		ctx.ServerError("GetCommit", err)
+		return
+	}
+	commit, err := gitRepo.GetCommit(headCommitID)
+	if err != nil {
+		ctx.ServerError("GetCommit", err)
+		return
+	}
+	setImageCompareContext(ctx, ctx.Repo.Repository, baseCommit, ctx.Repo.Repository, commit)
+	setPathsCompareContext(ctx, baseCommit, commit, path.Join(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name))
+
+	if ctx.IsSigned {
+		if ctx.Data["CanMarkConversation"], err = models.CanMarkConversation(issue, ctx.User); err != nil {
+			ctx.ServerError("CanMarkConversation", err)
+			return
+		}
+		if ctx.Data["ViewedFiles"], err = pull_service.GetViewedFilesSet(pull, ctx.User); err != nil {
+			ctx.ServerError("GetViewedFilesSet", err)
+			return
+		}
+	}
+	ctx.HTML(http.StatusOK, tplPullFileDiff)
+}
+
+// SetPullAutoPublish enables or cancels the auto publishing of a work in progress pull request
+func SetPullAutoPublish(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsClosed || !issue.PullRequest.IsWorkInProgress() {
+		ctx.NotFound("SetPullAutoPublish", nil)
+		return
+	}
+	if !issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWrite(models.UnitTypePullRequests) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	if ctx.Query("action") == "cancel" {
+		if err := models.DeletePullAutoPublish(issue.PullRequest.ID); err != nil && !models.IsErrPullAutoPublishNotExist(err) {
+			ctx.ServerError("DeletePullAutoPublish", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.auto_publish_canceled"))
+	} else {
+		if err := pull_service.SetAutoPublish(issue.PullRequest, ctx.User, nil, nil); err != nil {
+			ctx.ServerError("SetAutoPublish", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.auto_publish_enabled"))
+	}
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+}
+
 // UpdatePullRequest merge PR's baseBranch into headBranch
 func UpdatePullRequest(ctx *context.Context) {
 	issue := checkPullInfo(ctx)
@@ -796,18 +1084,28 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
-	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
-		if !models.IsErrNotAllowedToMerge(err) {
-			ctx.ServerError("Merge PR status", err)
-			return
-		}
-		if isRepoAdmin, err := models.IsUserRepoAdmin(pr.BaseRepo, ctx.User); err != nil {
-			ctx.ServerError("IsUserRepoAdmin", err)
-			return
-		} else if !isRepoAdmin {
-			ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
-			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
-			return
+	if err := pr.LoadProtectedBranch(); err != nil {
+		ctx.ServerError("LoadProtectedBranch", err)
+		return
+	}
+	// the pull requests into a branch with a merge queue are added to the queue instead of being merged
+	useMergeQueue := pr.ProtectedBranch != nil && pr.ProtectedBranch.EnableMergeQueue
+
+	// the merge queue checks the pull request itself
+	if !useMergeQueue {
+		if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
+			if !models.IsErrNotAllowedToMerge(err) {
+				ctx.ServerError("Merge PR status", err)
+				return
+			}
+			if isRepoAdmin, err := models.IsUserRepoAdmin(pr.BaseRepo, ctx.User); err != nil {
+				ctx.ServerError("IsUserRepoAdmin", err)
+				return
+			} else if !isRepoAdmin {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
+				ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+				return
+			}
 		}
 	}
 
@@ -819,14 +1117,14 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
-			message = pr.GetDefaultMergeMessage()
+		title, body, err := pull_service.GetDefaultMergeMessage(pr, models.MergeStyle(form.Do))
+		if err != nil {
+			ctx.ServerError("GetDefaultMergeMessage", err)
+			return
 		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
+		message = title
+		if len(strings.TrimSpace(form.MergeMessageField)) == 0 {
+			form.MergeMessageField = body
 		}
 	}
 
@@ -849,6 +1147,26 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
+	if useMergeQueue {
+		if err = pull_service.AddToMergeQueue(pr, ctx.User, models.MergeStyle(form.Do), message); err != nil {
+			if models.IsErrInvalidMergeStyle(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
+			} else if models.IsErrNotAllowedToMerge(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
+			} else if models.IsErrMergeQueueEntryAlreadyExists(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue_already_added"))
+			} else {
+				ctx.ServerError("AddToMergeQueue", err)
+				return
+			}
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue_added", pr.BaseBranch))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
 	if err = pull_service.Merge(pr, ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
@@ -958,6 +1276,11 @@ func CompareAndPullRequestPost(ctx *context.Context, form auth.CreateIssueForm)
 	}
 	defer headGitRepo.Close()
 
+	if !repo_service.IsSameForkNetwork(repo, headRepo) {
+		ctx.NotFound("IsSameForkNetwork", nil)
+		return
+	}
+
 	labelIDs, assigneeIDs, milestoneID, _ := ValidateRepoMetas(ctx, form, true)
 	if ctx.Written() {
 		return
@@ -1010,6 +1333,10 @@ func CompareAndPullRequestPost(ctx *context.Context, form auth.CreateIssueForm)
 		MergeBase:  prInfo.MergeBase,
 		Type:       models.PullRequestGitea,
 	}
+	if err := pull_service.DetectBasePull(pullRequest); err != nil {
+		ctx.ServerError("DetectBasePull", err)
+		return
+	}
 	// FIXME: check error in the case two people send pull request at almost same time, give nice error prompt
 	// instead of 500.
 
@@ -0,0 +1,225 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repofiles"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/unknwon/com"
+)
+
+const tplPullConflicts base.TplName = "repo/pulls/conflicts"
+
+// conflictLine represents a line of a file merged with conflict markers,
+// its type is marker, head or base when the line is in a conflict
+type conflictLine struct {
+	Num  int
+	HTML template.HTML
+	Type string
+}
+
+// conflictFile represents a conflicting file and its resolution in the conflict editor
+type conflictFile struct {
+	*repofiles.MergeConflict
+	Index      int
+	Lines      []*conflictLine
+	Resolution string
+	Content    string
+}
+
+// highlightConflict returns the highlighted lines of a file merged with conflict markers
+func highlightConflict(treePath, content string) []*conflictLine {
+	numLines := strings.Count(content, "\n") + 1
+	htmlLines := highlight.File(numLines, treePath, []byte(content))
+
+	lines := make([]*conflictLine, 0, numLines)
+	section := ""
+	for i, line := range strings.SplitN(content, "\n", numLines) {
+		lineType := section
+		switch {
+		case strings.HasPrefix(line, "<<<<<<< "):
+			lineType, section = "marker", "head"
+		case section != "" && line == "=======":
+			lineType, section = "marker", "base"
+		case section != "" && strings.HasPrefix(line, ">>>>>>> "):
+			lineType, section = "marker", ""
+		}
+		lines = append(lines, &conflictLine{Num: i + 1, HTML: template.HTML(htmlLines[i+1]), Type: lineType})
+	}
+	return lines
+}
+
+// getPullConflicts returns the pull request whose conflicts are resolved and the conflicts of its head branch with its base branch
+func getPullConflicts(ctx *context.Context) (*models.Issue, *repofiles.MergeConflicts) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return nil, nil
+	}
+	pull := issue.PullRequest
+	// the resolution is committed to the head branch, which can't be pushed to in an archived repository
+	if issue.IsClosed || pull.HasMerged || pull.HeadRepo == nil || pull.HeadRepo.IsArchived {
+		ctx.NotFound("ResolvePullConflicts", nil)
+		return nil, nil
+	}
+	if err := pull.LoadBaseRepo(); err != nil {
+		ctx.ServerError("LoadBaseRepo", err)
+		return nil, nil
+	}
+
+	allowedUpdate, err := pull_service.IsUserAllowedToUpdate(pull, ctx.User)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToUpdate", err)
+		return nil, nil
+	}
+	if !allowedUpdate {
+		ctx.NotFound("ResolvePullConflicts", nil)
+		return nil, nil
+	}
+
+	conflicts, err := repofiles.GetMergeConflicts(pull.HeadRepo, &repofiles.MergeBranchOptions{
+		Branch:      pull.HeadBranch,
+		MergeRepo:   pull.BaseRepo,
+		MergeBranch: pull.BaseBranch,
+	})
+	if err != nil {
+		if git.IsErrBranchNotExist(err) {
+			ctx.NotFound("GetMergeConflicts", err)
+		} else {
+			ctx.ServerError("GetMergeConflicts", err)
+		}
+		return nil, nil
+	}
+
+	ctx.Data["PageIsPullList"] = true
+	ctx.Data["HeadBranch"] = pull.HeadBranch
+	ctx.Data["BaseBranch"] = pull.BaseBranch
+	ctx.Data["ConflictOursLabel"] = pull.HeadBranch
+	ctx.Data["ConflictTheirsLabel"] = pull.BaseBranch
+	ctx.Data["head_commit_id"] = conflicts.CommitID
+	ctx.Data["base_commit_id"] = conflicts.MergeCommitID
+	ctx.Data["commit_message"] = fmt.Sprintf("Merge branch '%s' into %s", pull.BaseBranch, pull.HeadBranch)
+	return issue, conflicts
+}
+
+// conflictFiles returns the files of the conflicts with their resolutions submitted in the editor
+func conflictFiles(ctx *context.Context, conflicts *repofiles.MergeConflicts) []*conflictFile {
+	files := make([]*conflictFile, len(conflicts.Files))
+	for i, conflict := range conflicts.Files {
+		file := &conflictFile{
+			MergeConflict: conflict,
+			Index:         i,
+			Resolution:    ctx.Query(fmt.Sprintf("resolution_%d", i)),
+			Content:       conflict.Content,
+		}
+		if conflict.IsText() {
+			file.Lines = highlightConflict(conflict.TreePath, conflict.Content)
+			if content, ok := ctx.Req.Form[fmt.Sprintf("content_%d", i)]; ok && len(content) > 0 {
+				file.Content = content[0]
+			}
+		}
+		files[i] = file
+	}
+	return files
+}
+
+// conflictResolutions returns the resolutions of the conflicting files chosen in the editor
+func conflictResolutions(files []*conflictFile) map[string]*repofiles.MergeResolution {
+	resolutions := make(map[string]*repofiles.MergeResolution, len(files))
+	for _, file := range files {
+		switch file.Resolution {
+		case "head":
+			resolutions[file.TreePath] = &repofiles.MergeResolution{Type: repofiles.MergeResolutionOurs}
+		case "base":
+			resolutions[file.TreePath] = &repofiles.MergeResolution{Type: repofiles.MergeResolutionTheirs}
+		case "edit":
+			content := file.Content
+			if !strings.Contains(file.MergeConflict.Content, "\r\n") {
+				content = strings.Replace(content, "\r\n", "\n", -1)
+			}
+			resolutions[file.TreePath] = &repofiles.MergeResolution{Type: repofiles.MergeResolutionContent, Content: []byte(content)}
+		}
+	}
+	return resolutions
+}
+
+// PullConflicts renders the editor resolving the conflicts of a pull request
+func PullConflicts(ctx *context.Context) {
+	issue, conflicts := getPullConflicts(ctx)
+	if ctx.Written() {
+		return
+	}
+	if len(conflicts.Files) == 0 {
+		ctx.Flash.Info(ctx.Tr("repo.pulls.conflicts.none"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+		return
+	}
+
+	ctx.Data["Files"] = conflictFiles(ctx, conflicts)
+	ctx.HTML(200, tplPullConflicts)
+}
+
+// ResolvePullConflicts commits the resolution of the conflicts of a pull request to its head branch
+func ResolvePullConflicts(ctx *context.Context, form auth.ResolvePullConflictsForm) {
+	issue, conflicts := getPullConflicts(ctx)
+	if ctx.Written() {
+		return
+	}
+	pull := issue.PullRequest
+	files := conflictFiles(ctx, conflicts)
+	ctx.Data["Files"] = files
+	ctx.Data["commit_message"] = form.CommitMessage
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplPullConflicts)
+		return
+	}
+
+	message := strings.TrimSpace(form.CommitMessage)
+	if len(message) == 0 {
+		message = fmt.Sprintf("Merge branch '%s' into %s", pull.BaseBranch, pull.HeadBranch)
+	}
+
+	commitID, err := repofiles.ResolveMergeConflicts(pull.HeadRepo, ctx.User, &repofiles.ResolveMergeConflictsOptions{
+		MergeBranchOptions: repofiles.MergeBranchOptions{
+			Branch:      pull.HeadBranch,
+			MergeRepo:   pull.BaseRepo,
+			MergeBranch: pull.BaseBranch,
+		},
+		LastCommitID:  form.HeadCommitID,
+		MergeCommitID: form.BaseCommitID,
+		Message:       message,
+		Resolutions:   conflictResolutions(files),
+	})
+	if err != nil {
+		switch {
+		case models.IsErrMergeConflictNotResolved(err):
+			ctx.RenderWithErr(ctx.Tr("repo.pulls.conflicts.not_resolved", err.(models.ErrMergeConflictNotResolved).Path), tplPullConflicts, &form)
+		case models.IsErrCommitIDDoesNotMatch(err), git.IsErrPushOutOfDate(err):
+			ctx.Flash.Error(ctx.Tr("repo.pulls.conflicts.branch_changed"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index) + "/conflicts")
+		case git.IsErrPushRejected(err):
+			ctx.RenderWithErr(ctx.Tr("repo.editor.push_rejected_no_message"), tplPullConflicts, &form)
+		default:
+			ctx.ServerError("ResolveMergeConflicts", err)
+		}
+		return
+	}
+
+	log.Trace("Conflicts of pull request %d resolved by commit %s", pull.ID, commitID)
+	ctx.Flash.Success(ctx.Tr("repo.pulls.conflicts.resolved"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+}
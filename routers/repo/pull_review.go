@@ -61,6 +61,48 @@ func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
 	ctx.Redirect(comment.HTMLURL())
 }
 
+// CreateCommitMessageComment will create a comment on a line of the message of a commit of a pull request
+// including an pending review if required
+func CreateCommitMessageComment(ctx *context.Context, form auth.CommitMessageCommentForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.NotFound("CreateCommitMessageComment", nil)
+		return
+	}
+
+	commitID := ctx.Params(":sha")
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(fmt.Sprintf("%s/pulls/%d/commits/%s/message", ctx.Repo.RepoLink, issue.Index, commitID))
+		return
+	}
+
+	comment, err := pull_service.CreateCommitMessageComment(
+		ctx.User,
+		ctx.Repo.GitRepo,
+		issue,
+		commitID,
+		form.Line,
+		form.Content,
+		form.IsReview,
+		form.LatestCommitID,
+	)
+	if err != nil {
+		if models.IsErrCommitMessageLineNotExist(err) {
+			ctx.NotFound("CreateCommitMessageComment", err)
+		} else {
+			ctx.ServerError("CreateCommitMessageComment", err)
+		}
+		return
+	}
+
+	log.Trace("Comment created: %-v #%d[%d] Comment[%d]", ctx.Repo.Repository, issue.Index, issue.ID, comment.ID)
+	ctx.Redirect(comment.HTMLURL())
+}
+
 // UpdateResolveConversation add or remove an Conversation resolved mark
 func UpdateResolveConversation(ctx *context.Context) {
 	action := ctx.Query("action")
@@ -108,6 +150,34 @@ func UpdateResolveConversation(ctx *context.Context) {
 	})
 }
 
+// UpdateViewedFile marks a file of a pull request as viewed or not viewed by the user
+func UpdateViewedFile(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	treePath := ctx.Query("path")
+	if len(treePath) == 0 {
+		ctx.Error(400)
+		return
+	}
+	var viewed, unviewed []string
+	if ctx.QueryBool("viewed") {
+		viewed = append(viewed, treePath)
+	} else {
+		unviewed = append(unviewed, treePath)
+	}
+	if err := pull_service.UpdateViewedFiles(issue.PullRequest, ctx.User, viewed, unviewed); err != nil {
+		ctx.ServerError("UpdateViewedFiles", err)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
 func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
 	issue := GetActionIssue(ctx)
@@ -145,11 +215,14 @@ func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
 		}
 	}
 
-	_, comm, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, issue, reviewType, form.Content, form.CommitID)
+	_, comm, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, issue, reviewType, form.Content, form.CommitID, form.Checklist)
 	if err != nil {
 		if models.IsContentEmptyErr(err) {
 			ctx.Flash.Error(ctx.Tr("repo.issues.review.content.empty"))
 			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		} else if models.IsErrReviewChecklistNotAcknowledged(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.review.checklist.not_acknowledged"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
 		} else {
 			ctx.ServerError("SubmitReview", err)
 		}
@@ -74,6 +74,7 @@ func releasesOrTags(ctx *context.Context, isTagList bool) {
 		ctx.Data["Title"] = ctx.Tr("repo.release.releases")
 		ctx.Data["PageIsTagList"] = false
 	}
+	ctx.Data["LFSStartServer"] = setting.LFS.StartServer
 
 	writeAccess := ctx.Repo.CanWrite(models.UnitTypeReleases)
 	ctx.Data["CanCreateRelease"] = writeAccess && !ctx.Repo.Repository.IsArchived
@@ -0,0 +1,117 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// iframeContentSecurityPolicy sandboxes the rendered documents. Without allow-same-origin
+// the document gets a unique opaque origin, so its scripts can neither read the cookies
+// nor call the pages and the API of the instance on behalf of the user.
+const iframeContentSecurityPolicy = "sandbox allow-scripts allow-popups"
+
+// renderPathPattern matches the paths of the documents served by RenderFile
+var renderPathPattern = regexp.MustCompile(`^/[^/]+/[^/]+/render/(branch|tag|commit)/`)
+
+// RenderOriginFilter only lets the render origin serve the rendered documents
+func RenderOriginFilter(ctx *context.Context) {
+	if markup.IsRenderOriginRequest(ctx.Req.Request) && !renderPathPattern.MatchString(strings.TrimPrefix(ctx.Req.URL.Path, setting.AppSubURL)) {
+		ctx.NotFound("RenderOriginFilter", nil)
+	}
+}
+
+// RenderFile serves the output of the external renderer configured with
+// RENDER_CONTENT_MODE = iframe for a file, it is shown in a sandboxed iframe.
+// Once a render origin is configured the documents are only served from it.
+func RenderFile(ctx *context.Context) {
+	if len(setting.MarkupRenderOrigin) > 0 && !markup.IsRenderOriginRequest(ctx.Req.Request) {
+		ctx.NotFound("RenderFile", nil)
+		return
+	}
+	if !markup.IsIframeRendered(ctx.Repo.TreePath) {
+		ctx.NotFound("RenderFile", nil)
+		return
+	}
+
+	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetBlobByPath", nil)
+		} else {
+			ctx.ServerError("GetBlobByPath", err)
+		}
+		return
+	}
+
+	content, err := readRenderedBlob(ctx, blob)
+	if err != nil {
+		ctx.ServerError("readRenderedBlob", err)
+		return
+	}
+	if content == nil {
+		ctx.Error(http.StatusRequestEntityTooLarge, ctx.Tr("repo.file_too_large"))
+		return
+	}
+
+	treeLink := ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL() + "/" + util.PathEscapeSegments(path.Dir(ctx.Repo.TreePath))
+	result := markup.RenderIframe(ctx.Repo.TreePath, charset.ToUTF8WithFallback(content), treeLink, ctx.Repo.Repository.ComposeDocumentMetas())
+
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Security-Policy", iframeContentSecurityPolicy)
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(result); err != nil {
+		log.Error("RenderFile: Write: %v", err)
+	}
+}
+
+// readRenderedBlob returns the content of a blob, or of the LFS object it points to,
+// or nil if it is too large to be displayed
+func readRenderedBlob(ctx *context.Context, blob *git.Blob) ([]byte, error) {
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		return nil, nil
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := lfs.IsPointerFile(&content)
+	if meta == nil {
+		return content, nil
+	}
+	if meta, _ = ctx.Repo.Repository.GetLFSMetaObjectByOid(meta.Oid); meta == nil {
+		// the object isn't available, render the pointer like the file view
+		return content, nil
+	}
+	if meta.Size >= setting.UI.MaxDisplayFileSize {
+		return nil, nil
+	}
+	lfsDataRc, err := lfs.ReadMetaObject(meta)
+	if err != nil {
+		return nil, err
+	}
+	defer lfsDataRc.Close()
+	return ioutil.ReadAll(lfsDataRc)
+}
@@ -16,26 +16,48 @@ import (
 
 const tplSearch base.TplName = "repo/search"
 
-// Search render repository search page
+// Search render repository search page, the default branch is searched with
+// git grep when the code indexer is disabled
 func Search(ctx *context.Context) {
-	if !setting.Indexer.RepoIndexerEnabled {
-		ctx.Redirect(ctx.Repo.RepoLink, 302)
-		return
-	}
 	language := strings.TrimSpace(ctx.Query("l"))
 	keyword := strings.TrimSpace(ctx.Query("q"))
 	page := ctx.QueryInt("page")
 	if page <= 0 {
 		page = 1
 	}
-	total, searchResults, searchResultLanguages, err := code_indexer.PerformSearch([]int64{ctx.Repo.Repository.ID},
-		language, keyword, page, setting.UI.RepoSearchPagingNum)
-	if err != nil {
+	opts := &code_indexer.SearchOptions{
+		RepoIDs:         []int64{ctx.Repo.Repository.ID},
+		Language:        language,
+		IsRegex:         ctx.QueryBool("regex"),
+		IsCaseSensitive: ctx.QueryBool("case_sensitive"),
+		IsWholeWord:     ctx.QueryBool("whole_word"),
+		Page:            page,
+		PageSize:        setting.UI.RepoSearchPagingNum,
+	}
+	opts.ParseKeyword(keyword)
+
+	var (
+		total                 int
+		searchResults         []*code_indexer.Result
+		searchResultLanguages []*code_indexer.SearchResultLanguages
+		err                   error
+	)
+	if setting.Indexer.RepoIndexerEnabled {
+		total, searchResults, searchResultLanguages, err = code_indexer.PerformSearch(opts)
+	} else {
+		total, searchResults, searchResultLanguages, err = code_indexer.GrepSearch(ctx.Repo.Repository, opts)
+	}
+	if code_indexer.IsErrInvalidSearchPattern(err) {
+		ctx.Data["SearchError"] = ctx.Tr("repo.search.invalid_pattern", err.(code_indexer.ErrInvalidSearchPattern).Err.Error())
+	} else if err != nil {
 		ctx.ServerError("SearchResults", err)
 		return
 	}
 	ctx.Data["Keyword"] = keyword
 	ctx.Data["Language"] = language
+	ctx.Data["IsRegex"] = opts.IsRegex
+	ctx.Data["IsCaseSensitive"] = opts.IsCaseSensitive
+	ctx.Data["IsWholeWord"] = opts.IsWholeWord
 	ctx.Data["SourcePath"] = setting.AppSubURL + "/" +
 		path.Join(ctx.Repo.Repository.Owner.Name, ctx.Repo.Repository.Name)
 	ctx.Data["SearchResults"] = searchResults
@@ -46,6 +68,9 @@ func Search(ctx *context.Context) {
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "l", "Language")
+	pager.AddParam(ctx, "regex", "IsRegex")
+	pager.AddParam(ctx, "case_sensitive", "IsCaseSensitive")
+	pager.AddParam(ctx, "whole_word", "IsWholeWord")
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(200, tplSearch)
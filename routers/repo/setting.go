@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -52,12 +54,22 @@ func Settings(ctx *context.Context) {
 	ctx.Data["ForcePrivate"] = setting.Repository.ForcePrivate
 
 	signing, _ := models.SigningKey(ctx.Repo.Repository.RepoPath())
-	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
+	ctx.Data["SigningKeyAvailable"] = signing != nil
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
+	loadIssueKeywords(ctx)
 
 	ctx.HTML(200, tplSettingsOptions)
 }
 
+// loadIssueKeywords loads the keywords closing and reopening the issues referenced in the repository
+func loadIssueKeywords(ctx *context.Context) {
+	config := ctx.Repo.Repository.IssuesConfig()
+	ctx.Data["IssuesCloseKeywords"] = strings.Join(config.CloseKeywords, ", ")
+	ctx.Data["IssuesReopenKeywords"] = strings.Join(config.ReopenKeywords, ", ")
+	ctx.Data["IssuesKeywordLanguages"] = strings.Join(config.KeywordLanguages, ",")
+	ctx.Data["KeywordLanguages"] = references.KeywordLanguages
+}
+
 // SettingsPost response for changes of a repository
 func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
@@ -68,6 +80,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 	switch ctx.Query("action") {
 	case "update":
 		if ctx.HasError() {
+			loadIssueKeywords(ctx)
 			ctx.HTML(200, tplSettingsOptions)
 			return
 		}
@@ -281,6 +294,21 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
 		} else if form.EnableIssues && !form.EnableExternalTracker && !models.UnitTypeIssues.UnitGlobalDisabled() {
+			closeKeywords := parseIssueKeywords(form.IssuesCloseKeywords)
+			reopenKeywords := parseIssueKeywords(form.IssuesReopenKeywords)
+			for _, keyword := range append(append([]string{}, closeKeywords...), reopenKeywords...) {
+				if !references.IsValidKeyword(keyword) {
+					ctx.Flash.Error(ctx.Tr("repo.settings.issues_keyword_invalid", keyword))
+					ctx.Redirect(repo.Link() + "/settings")
+					return
+				}
+			}
+			var languages []string
+			for _, language := range strings.Split(form.IssuesKeywordLanguages, ",") {
+				if _, ok := references.KeywordLanguages[language]; ok {
+					languages = append(languages, language)
+				}
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeIssues,
@@ -288,6 +316,11 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					CloseKeywords:                    closeKeywords,
+					ReopenKeywords:                   reopenKeywords,
+					KeywordLanguages:                 languages,
+					DisableCrossRepoClose:            form.IssuesDisableCrossRepoClose,
+					CloseOnDefaultBranchOnly:         form.IssuesCloseOnDefaultBranchOnly,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
@@ -314,11 +347,15 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 				RepoID: repo.ID,
 				Type:   models.UnitTypePullRequests,
 				Config: &models.PullRequestsConfig{
-					IgnoreWhitespaceConflicts: form.PullsIgnoreWhitespace,
-					AllowMerge:                form.PullsAllowMerge,
-					AllowRebase:               form.PullsAllowRebase,
-					AllowRebaseMerge:          form.PullsAllowRebaseMerge,
-					AllowSquash:               form.PullsAllowSquash,
+					IgnoreWhitespaceConflicts:  form.PullsIgnoreWhitespace,
+					AllowMerge:                 form.PullsAllowMerge,
+					AllowRebase:                form.PullsAllowRebase,
+					AllowRebaseMerge:           form.PullsAllowRebaseMerge,
+					AllowSquash:                form.PullsAllowSquash,
+					ReviewChecklist:            parseReviewChecklist(form.PullsReviewChecklist),
+					MergeMessageTemplate:       strings.TrimSpace(form.PullsMergeMessageTemplate),
+					RebaseMergeMessageTemplate: strings.TrimSpace(form.PullsRebaseMergeMessageTemplate),
+					SquashMessageTemplate:      strings.TrimSpace(form.PullsSquashMessageTemplate),
 				},
 			})
 		} else if !models.UnitTypePullRequests.UnitGlobalDisabled() {
@@ -485,6 +522,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		}
 
 		log.Trace("Repository transferred: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, newOwner)
+		audit.Record(models.AuditActionRepoTransfer, ctx.User, ctx.RemoteAddr(), repo, "Transferred from %s to %s", ctx.Repo.Owner.Name, newOwner.Name)
 		ctx.Flash.Success(ctx.Tr("repo.settings.transfer_succeed"))
 		ctx.Redirect(setting.AppSubURL + "/" + newOwner.Name + "/" + repo.Name)
 
@@ -499,6 +537,11 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		}
 
 		if err := repo_service.DeleteRepository(ctx.User, ctx.Repo.Repository); err != nil {
+			if models.IsErrUnderLegalHold(err) {
+				ctx.Flash.Error(ctx.Tr("repo.settings.deletion_under_legal_hold"))
+				ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+				return
+			}
 			ctx.ServerError("DeleteRepository", err)
 			return
 		}
@@ -641,6 +684,7 @@ func CollaborationPost(ctx *context.Context) {
 		ctx.ServerError("AddCollaborator", err)
 		return
 	}
+	audit.Record(models.AuditActionCollaboratorAdd, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Added the collaborator %s", u.Name)
 
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.User, ctx.Repo.Repository)
@@ -652,18 +696,31 @@ func CollaborationPost(ctx *context.Context) {
 
 // ChangeCollaborationAccessMode response for changing access of a collaboration
 func ChangeCollaborationAccessMode(ctx *context.Context) {
-	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(
-		ctx.QueryInt64("uid"),
-		models.AccessMode(ctx.QueryInt("mode"))); err != nil {
+	uid, mode := ctx.QueryInt64("uid"), models.AccessMode(ctx.QueryInt("mode"))
+	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(uid, mode); err != nil {
 		log.Error("ChangeCollaborationAccessMode: %v", err)
+	} else {
+		audit.Record(models.AuditActionCollaboratorChangeMode, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository,
+			"Changed the access mode of the collaborator %s to %s", collaboratorName(uid), mode)
 	}
 }
 
+// collaboratorName returns the name of a collaborator for the audit log, or its ID if it can't be read
+func collaboratorName(uid int64) string {
+	u, err := models.GetUserByID(uid)
+	if err != nil {
+		return fmt.Sprintf("[id: %d]", uid)
+	}
+	return u.Name
+}
+
 // DeleteCollaboration delete a collaboration for a repository
 func DeleteCollaboration(ctx *context.Context) {
-	if err := ctx.Repo.Repository.DeleteCollaboration(ctx.QueryInt64("id")); err != nil {
+	uid := ctx.QueryInt64("id")
+	if err := ctx.Repo.Repository.DeleteCollaboration(uid); err != nil {
 		ctx.Flash.Error("DeleteCollaboration: " + err.Error())
 	} else {
+		audit.Record(models.AuditActionCollaboratorRemove, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Removed the collaborator %s", collaboratorName(uid))
 		ctx.Flash.Success(ctx.Tr("repo.settings.remove_collaborator_success"))
 	}
 
@@ -743,6 +800,28 @@ func DeleteTeam(ctx *context.Context) {
 	})
 }
 
+// parseReviewChecklist returns the items of a review checklist written one per line
+func parseReviewChecklist(s string) []string {
+	var items []string
+	for _, line := range strings.Split(s, "\n") {
+		if item := strings.TrimSpace(line); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIssueKeywords returns the keywords of a comma separated list
+func parseIssueKeywords(s string) []string {
+	var keywords []string
+	for _, keyword := range strings.Split(s, ",") {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); len(keyword) > 0 {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
 // parseOwnerAndRepo get repos by owner
 func parseOwnerAndRepo(ctx *context.Context) (*models.User, *models.Repository) {
 	owner, err := models.GetUserByName(ctx.Params(":username"))
@@ -893,15 +972,18 @@ func DeployKeysPost(ctx *context.Context, form auth.AddKeyForm) {
 	}
 
 	log.Trace("Deploy key added: %d", ctx.Repo.Repository.ID)
+	audit.Record(models.AuditActionDeployKeyAdd, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Added the deploy key %s (%s)", key.Name, key.Mode)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/keys")
 }
 
 // DeleteDeployKey response for deleting a deploy key
 func DeleteDeployKey(ctx *context.Context) {
-	if err := models.DeleteDeployKey(ctx.User, ctx.QueryInt64("id")); err != nil {
+	id := ctx.QueryInt64("id")
+	if err := models.DeleteDeployKey(ctx.User, id); err != nil {
 		ctx.Flash.Error("DeleteDeployKey: " + err.Error())
 	} else {
+		audit.Record(models.AuditActionDeployKeyRemove, ctx.User, ctx.RemoteAddr(), ctx.Repo.Repository, "Removed the deploy key %d", id)
 		ctx.Flash.Success(ctx.Tr("repo.settings.deploy_key_deletion_success"))
 	}
 
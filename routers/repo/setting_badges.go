@@ -0,0 +1,99 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const tplSettingsBadges base.TplName = "repo/settings/badges"
+
+// Badges render the badges and quick links shown on the home of the repository
+func Badges(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.badges")
+	ctx.Data["PageIsSettingsBadges"] = true
+
+	badges, err := models.GetRepoBadges(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetRepoBadges", err)
+		return
+	}
+	ctx.Data["Badges"] = badges
+
+	if id := ctx.QueryInt64("edit"); id > 0 {
+		badge, err := models.GetRepoBadge(ctx.Repo.Repository.ID, id)
+		if err != nil {
+			if models.IsErrRepoBadgeNotExist(err) {
+				ctx.NotFound("GetRepoBadge", err)
+			} else {
+				ctx.ServerError("GetRepoBadge", err)
+			}
+			return
+		}
+		ctx.Data["EditBadge"] = badge
+	}
+
+	ctx.HTML(200, tplSettingsBadges)
+}
+
+// BadgesPost response for adding or editing a badge of the repository
+func BadgesPost(ctx *context.Context, form auth.RepoBadgeForm) {
+	link := ctx.Repo.RepoLink + "/settings/badges"
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(link)
+		return
+	}
+
+	if form.ID == 0 {
+		if err := models.CreateRepoBadge(&models.RepoBadge{
+			RepoID:   ctx.Repo.Repository.ID,
+			Name:     form.Name,
+			LinkURL:  form.LinkURL,
+			ImageURL: form.ImageURL,
+			Sort:     form.Sort,
+		}); err != nil {
+			ctx.ServerError("CreateRepoBadge", err)
+			return
+		}
+	} else {
+		badge, err := models.GetRepoBadge(ctx.Repo.Repository.ID, form.ID)
+		if err != nil {
+			if models.IsErrRepoBadgeNotExist(err) {
+				ctx.NotFound("GetRepoBadge", err)
+			} else {
+				ctx.ServerError("GetRepoBadge", err)
+			}
+			return
+		}
+		badge.Name = form.Name
+		badge.LinkURL = form.LinkURL
+		badge.ImageURL = form.ImageURL
+		badge.Sort = form.Sort
+		if err := models.UpdateRepoBadge(badge); err != nil {
+			ctx.ServerError("UpdateRepoBadge", err)
+			return
+		}
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.badges.update_success"))
+	ctx.Redirect(link)
+}
+
+// DeleteBadge response for deleting a badge of the repository
+func DeleteBadge(ctx *context.Context) {
+	if err := models.DeleteRepoBadge(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteRepoBadge: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.badges.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/settings/badges",
+	})
+}
@@ -161,6 +161,20 @@ func SettingsProtectedBranch(c *context.Context) {
 		c.Data["whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.WhitelistTeamIDs), ",")
 		c.Data["merge_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.MergeWhitelistTeamIDs), ",")
 		c.Data["approvals_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.ApprovalsWhitelistTeamIDs), ",")
+
+		rules := make([]string, 0, len(protectBranch.ProtectedPathRules))
+		for _, rule := range protectBranch.ProtectedPathRules {
+			team, err := models.GetTeamByID(rule.TeamID)
+			if err != nil {
+				if models.IsErrTeamNotExist(err) {
+					continue
+				}
+				c.ServerError("GetTeamByID", err)
+				return
+			}
+			rules = append(rules, rule.Pattern+" "+team.Name)
+		}
+		c.Data["protected_path_rules"] = strings.Join(rules, "\n")
 	}
 
 	c.Data["Branch"] = protectBranch
@@ -251,6 +265,24 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		protectBranch.RequireSignedCommits = f.RequireSignedCommits
 		protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 		protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+		protectBranch.EnableMergeQueue = f.EnableMergeQueue
+		protectBranch.RequireCodeOwnerApproval = f.RequireCodeOwnerApproval
+		protectBranch.RequireLinearHistory = f.RequireLinearHistory
+		protectBranch.DismissApprovalsOnPush = f.DismissStaleApprovals && f.DismissApprovalsOnPush
+		protectBranch.DismissApprovalsOnForcePush = f.DismissStaleApprovals && f.DismissApprovalsOnForcePush
+		protectBranch.RequireSignOff = f.RequireSignOff
+		if ctx.Repo.Owner.IsOrganization() {
+			protectBranch.ProtectedPathRules, err = parseProtectedPathRules(ctx.Repo.Repository, f.ProtectedPathRules)
+			if err != nil {
+				if invalidErr, ok := err.(models.ErrInvalidProtectedPathRule); ok {
+					ctx.Flash.Error(ctx.Tr("repo.settings.protected_path_rules_invalid", invalidErr.Pattern, invalidErr.Reason))
+					ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
+					return
+				}
+				ctx.ServerError("parseProtectedPathRules", err)
+				return
+			}
+		}
 
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
@@ -281,3 +313,24 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		ctx.Redirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
 	}
 }
+
+// parseProtectedPathRules parses protected path rules given one per line as a path pattern followed by the
+// name of a team
+func parseProtectedPathRules(repo *models.Repository, text string) ([]*models.ProtectedPathRule, error) {
+	var rules []*models.ProtectedPathRule
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, models.ErrInvalidProtectedPathRule{Pattern: strings.TrimSpace(line), Reason: "a rule is a path pattern followed by the name of a team"}
+		}
+		rule, err := models.NewProtectedPathRule(repo, fields[0], fields[1])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+
+	"github.com/gobwas/glob"
+)
+
+const tplSettingsReleaseAutomation base.TplName = "repo/settings/release_automation"
+
+// ReleaseAutomation render the release automation of the repository
+func ReleaseAutomation(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.release_automation")
+	ctx.Data["PageIsSettingsReleaseAutomation"] = true
+
+	automation, err := models.GetReleaseAutomation(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrReleaseAutomationNotExist(err) {
+			ctx.ServerError("GetReleaseAutomation", err)
+			return
+		}
+		automation = &models.ReleaseAutomation{
+			Draft:         true,
+			TitleTemplate: models.DefaultReleaseTitleTemplate,
+		}
+	}
+	ctx.Data["ReleaseAutomation"] = automation
+	ctx.Data["DefaultReleaseTitleTemplate"] = models.DefaultReleaseTitleTemplate
+
+	ctx.HTML(200, tplSettingsReleaseAutomation)
+}
+
+// ReleaseAutomationPost response for changing the release automation of the repository
+func ReleaseAutomationPost(ctx *context.Context, form auth.ReleaseAutomationForm) {
+	link := ctx.Repo.RepoLink + "/settings/release_automation"
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(link)
+		return
+	}
+	tagPattern := strings.TrimSpace(form.TagPattern)
+	if len(tagPattern) > 0 {
+		if _, err := glob.Compile(tagPattern); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.release_automation.invalid_tag_pattern"))
+			ctx.Redirect(link)
+			return
+		}
+	}
+
+	if err := models.SaveReleaseAutomation(&models.ReleaseAutomation{
+		RepoID:        ctx.Repo.Repository.ID,
+		Enabled:       form.Enabled,
+		TagPattern:    tagPattern,
+		Draft:         form.Draft,
+		Prerelease:    form.Prerelease,
+		TitleTemplate: strings.TrimSpace(form.TitleTemplate),
+		NoteTemplate:  strings.TrimSpace(form.NoteTemplate),
+		DoerID:        ctx.User.ID,
+	}); err != nil {
+		ctx.ServerError("SaveReleaseAutomation", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.release_automation.update_success"))
+	ctx.Redirect(link)
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const tplSettingsStale base.TplName = "repo/settings/stale"
+
+// StalePolicy render the stale policy of the repository and the latest actions it has taken
+func StalePolicy(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.stale")
+	ctx.Data["PageIsSettingsStale"] = true
+
+	policy, err := models.GetStalePolicy(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrStalePolicyNotExist(err) {
+			ctx.ServerError("GetStalePolicy", err)
+			return
+		}
+		policy = &models.StalePolicy{
+			IncludeIssues:  true,
+			DaysUntilStale: 60,
+			DaysUntilClose: 7,
+			StaleLabel:     models.DefaultStaleLabel,
+		}
+	}
+	ctx.Data["StalePolicy"] = policy
+	ctx.Data["DefaultStaleLabel"] = models.DefaultStaleLabel
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	logs, count, err := models.GetStaleActionLogs(ctx.Repo.Repository.ID, models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.IssuePagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetStaleActionLogs", err)
+		return
+	}
+	ctx.Data["StaleActionLogs"] = logs
+	ctx.Data["Page"] = context.NewPagination(int(count), setting.UI.IssuePagingNum, page, 5)
+
+	ctx.HTML(200, tplSettingsStale)
+}
+
+// StalePolicyPost response for changing the stale policy of the repository
+func StalePolicyPost(ctx *context.Context, form auth.StalePolicyForm) {
+	link := ctx.Repo.RepoLink + "/settings/stale"
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(link)
+		return
+	}
+	if form.DaysUntilStale <= 0 || form.DaysUntilClose < 0 {
+		ctx.Flash.Error(ctx.Tr("repo.settings.stale.invalid_days"))
+		ctx.Redirect(link)
+		return
+	}
+
+	if err := models.SaveStalePolicy(&models.StalePolicy{
+		RepoID:         ctx.Repo.Repository.ID,
+		Enabled:        form.Enabled,
+		IncludeIssues:  form.IncludeIssues,
+		IncludePulls:   form.IncludePulls,
+		DaysUntilStale: form.DaysUntilStale,
+		DaysUntilClose: form.DaysUntilClose,
+		StaleLabel:     form.StaleLabel,
+		ExemptLabels:   form.ExemptLabels,
+		WarningComment: form.WarningComment,
+		CloseComment:   form.CloseComment,
+		DoerID:         ctx.User.ID,
+	}); err != nil {
+		ctx.ServerError("SaveStalePolicy", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.stale.update_success"))
+	ctx.Redirect(link)
+}
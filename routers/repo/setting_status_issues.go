@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+
+	"github.com/gobwas/glob"
+)
+
+const tplSettingsStatusIssues base.TplName = "repo/settings/status_issues"
+
+// StatusIssues render the rule of the repository opening issues for the failing commit statuses
+func StatusIssues(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.status_issues")
+	ctx.Data["PageIsSettingsStatusIssues"] = true
+
+	rule, err := models.GetStatusIssueRule(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrStatusIssueRuleNotExist(err) {
+			ctx.ServerError("GetStatusIssueRule", err)
+			return
+		}
+		rule = &models.StatusIssueRule{}
+	}
+	ctx.Data["StatusIssueRule"] = rule
+
+	ctx.HTML(200, tplSettingsStatusIssues)
+}
+
+// StatusIssuesPost response for changing the rule of the repository opening issues for the failing commit statuses
+func StatusIssuesPost(ctx *context.Context, form auth.StatusIssueRuleForm) {
+	link := ctx.Repo.RepoLink + "/settings/status_issues"
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(link)
+		return
+	}
+	contextPattern := strings.TrimSpace(form.ContextPattern)
+	if len(contextPattern) > 0 {
+		if _, err := glob.Compile(contextPattern); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.status_issues.invalid_context_pattern"))
+			ctx.Redirect(link)
+			return
+		}
+	}
+
+	if err := models.SaveStatusIssueRule(&models.StatusIssueRule{
+		RepoID:         ctx.Repo.Repository.ID,
+		Enabled:        form.Enabled,
+		ContextPattern: contextPattern,
+		LabelName:      strings.TrimSpace(form.LabelName),
+		DoerID:         ctx.User.ID,
+	}); err != nil {
+		ctx.ServerError("SaveStatusIssueRule", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.status_issues.update_success"))
+	ctx.Redirect(link)
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ShortLink redirects a short link to the permalink it resolves to, only the users who can read
+// the code of its repository can resolve it
+func ShortLink(ctx *context.Context) {
+	link, err := models.GetShortLinkByToken(ctx.Params(":token"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetShortLinkByToken", models.IsErrShortLinkNotExist, err)
+		return
+	}
+	if err := link.LoadRepo(); err != nil {
+		ctx.NotFoundOrServerError("LoadRepo", models.IsErrRepoNotExist, err)
+		return
+	}
+	perm, err := models.GetUserRepoPermission(link.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		ctx.NotFound("ShortLink", nil)
+		return
+	}
+
+	target, err := repo_service.ShortLinkTarget(link)
+	if err != nil {
+		ctx.NotFoundOrServerError("ShortLinkTarget", git.IsErrNotExist, err)
+		return
+	}
+	ctx.Redirect(link.Repo.Link() + "/" + target)
+}
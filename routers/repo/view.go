@@ -23,10 +23,13 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/highlight"
+	symbols_indexer "code.gitea.io/gitea/modules/indexer/symbols"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 const (
@@ -37,12 +40,67 @@ const (
 	tplMigrating base.TplName = "repo/migrate/migrating"
 )
 
+// maxLinkedIdentifiers is the maximum number of distinct identifiers of a file
+// looked up to link them to their definition
+const maxLinkedIdentifiers = 1000
+
 type namedBlob struct {
 	name      string
 	isSymlink bool
 	blob      *git.Blob
 }
 
+// linkSymbolDefinitions turns the identifiers of the highlighted lines of a file into links
+// to their definition in the default branch, as found by the symbols indexer
+func linkSymbolDefinitions(ctx *context.Context, lines map[int]string) {
+	if !setting.Indexer.SymbolIndexerEnabled {
+		return
+	}
+	names := symbols_indexer.FindIdentifiers(lines)
+	if len(names) == 0 {
+		return
+	}
+	if len(names) > maxLinkedIdentifiers {
+		names = names[:maxLinkedIdentifiers]
+	}
+
+	status, err := ctx.Repo.Repository.GetIndexerStatus(models.RepoIndexerTypeSymbols)
+	if err != nil {
+		log.Error("GetIndexerStatus: %v", err)
+		return
+	}
+	symbols, err := ctx.Repo.Repository.GetSymbolsByNames(names)
+	if err != nil {
+		log.Error("GetSymbolsByNames: %v", err)
+		return
+	}
+	definitions := make(map[string][]*models.RepoSymbol, len(symbols))
+	for _, symbol := range symbols {
+		definitions[symbol.Name] = append(definitions[symbol.Name], symbol)
+	}
+
+	symbols_indexer.LinkIdentifiers(lines, func(name string, line int) string {
+		list := definitions[name]
+		if len(list) == 0 {
+			return ""
+		}
+		// Prefer the definitions of the viewed file and don't link a definition to itself
+		definition := list[0]
+		for _, symbol := range list {
+			if symbol.Path == ctx.Repo.TreePath {
+				if symbol.Line == line {
+					return ""
+				}
+				if definition.Path != ctx.Repo.TreePath {
+					definition = symbol
+				}
+			}
+		}
+		return fmt.Sprintf("%s/src/commit/%s/%s#L%d", ctx.Repo.RepoLink, status.CommitSha,
+			util.PathEscapeSegments(definition.Path), definition.Line)
+	})
+}
+
 func linesBytesCount(s []byte) int {
 	nl := []byte{'\n'}
 	n := bytes.Count(s, nl)
@@ -329,7 +387,11 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 				if markupType := markup.Type(readmeFile.name); markupType != "" {
 					ctx.Data["IsMarkup"] = true
 					ctx.Data["MarkupType"] = string(markupType)
-					ctx.Data["FileContent"] = string(markup.Render(readmeFile.name, buf, readmeTreelink, ctx.Repo.Repository.ComposeDocumentMetas()))
+					if markup.IsIframeRendered(readmeFile.name) {
+						ctx.Data["IframeRenderLink"] = iframeRenderLink(ctx, path.Join(ctx.Repo.TreePath, readmeFile.name))
+					} else {
+						ctx.Data["FileContent"] = string(markup.RenderCached(readmeFile.name, readmeFile.blob.ID.String(), buf, readmeTreelink, ctx.Repo.Repository.ComposeDocumentMetas()))
+					}
 				} else {
 					ctx.Data["IsRenderedHTML"] = true
 					ctx.Data["FileContent"] = strings.ReplaceAll(
@@ -369,6 +431,20 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 	ctx.Data["SSHDomain"] = setting.SSH.Domain
 }
 
+// iframeRenderLink returns the link of the output of the external renderer of a file,
+// which is shown in a sandboxed iframe, on the render origin if one is configured
+func iframeRenderLink(ctx *context.Context, treePath string) string {
+	link := ctx.Repo.RepoLink + "/render/commit/" + ctx.Repo.CommitID + "/" + util.PathEscapeSegments(treePath)
+	if len(setting.MarkupRenderOrigin) == 0 {
+		return link
+	}
+	var userID int64
+	if ctx.User != nil {
+		userID = ctx.User.ID
+	}
+	return markup.RenderOriginLink(link, userID)
+}
+
 func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink string) {
 	ctx.Data["IsViewFile"] = true
 	blob := entry.Blob()
@@ -394,6 +470,11 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 	isTextFile := base.IsTextFile(buf)
 	isLFSFile := false
 	ctx.Data["IsTextFile"] = isTextFile
+	if isTextFile {
+		// lets the oEmbed consumers discover the snippets of the file at its commit
+		permalink := ctx.Repo.Repository.HTMLURL() + "/src/commit/" + ctx.Repo.CommitID + "/" + util.PathEscapeSegments(ctx.Repo.TreePath)
+		ctx.Data["OEmbedLink"] = setting.AppURL + "api/v1/oembed?url=" + url.QueryEscape(permalink)
+	}
 
 	//Check for LFS meta file
 	if isTextFile && setting.LFS.StartServer {
@@ -468,7 +549,11 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 		if markupType := markup.Type(blob.Name()); markupType != "" {
 			ctx.Data["IsMarkup"] = true
 			ctx.Data["MarkupType"] = markupType
-			ctx.Data["FileContent"] = string(markup.Render(blob.Name(), buf, path.Dir(treeLink), ctx.Repo.Repository.ComposeDocumentMetas()))
+			if markup.IsIframeRendered(blob.Name()) {
+				ctx.Data["IframeRenderLink"] = iframeRenderLink(ctx, ctx.Repo.TreePath)
+			} else {
+				ctx.Data["FileContent"] = string(markup.RenderCached(blob.Name(), blob.ID.String(), buf, path.Dir(treeLink), ctx.Repo.Repository.ComposeDocumentMetas()))
+			}
 		} else if readmeExist {
 			ctx.Data["IsRenderedHTML"] = true
 			ctx.Data["FileContent"] = strings.ReplaceAll(
@@ -479,7 +564,16 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 			lineNums := linesBytesCount(buf)
 			ctx.Data["NumLines"] = strconv.Itoa(lineNums)
 			ctx.Data["NumLinesSet"] = true
-			ctx.Data["FileContent"] = highlight.File(lineNums, blob.Name(), buf)
+			fileContent := highlight.File(lineNums, blob.Name(), buf)
+			linkSymbolDefinitions(ctx, fileContent)
+			ctx.Data["FileContent"] = fileContent
+
+			regions := highlight.FoldRegions(buf)
+			foldEnds := make(map[int]int, len(regions))
+			for _, region := range regions {
+				foldEnds[region.Start] = region.End
+			}
+			ctx.Data["FoldRegions"] = foldEnds
 		}
 		if !isLFSFile {
 			if ctx.Repo.CanEnableEditor() {
@@ -497,6 +591,8 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 			}
 		}
 
+	case isLFSFile && setting.LFS.MaxPreviewSize > 0 && fileSize > setting.LFS.MaxPreviewSize:
+		ctx.Data["IsLFSPreviewTooLarge"] = true
 	case base.IsPDFFile(buf):
 		ctx.Data["IsPDFFile"] = true
 	case base.IsVideoFile(buf):
@@ -516,7 +612,11 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 			buf = append(buf, d...)
 			ctx.Data["IsMarkup"] = true
 			ctx.Data["MarkupType"] = markupType
-			ctx.Data["FileContent"] = string(markup.Render(blob.Name(), buf, path.Dir(treeLink), ctx.Repo.Repository.ComposeDocumentMetas()))
+			if markup.IsIframeRendered(blob.Name()) {
+				ctx.Data["IframeRenderLink"] = iframeRenderLink(ctx, ctx.Repo.TreePath)
+			} else {
+				ctx.Data["FileContent"] = string(markup.RenderCached(blob.Name(), blob.ID.String(), buf, path.Dir(treeLink), ctx.Repo.Repository.ComposeDocumentMetas()))
+			}
 		}
 
 	}
@@ -610,6 +710,15 @@ func renderRepoTopics(ctx *context.Context) {
 	ctx.Data["Topics"] = topics
 }
 
+func renderRepoBadges(ctx *context.Context) {
+	badges, err := models.GetRepoBadges(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("models.GetRepoBadges", err)
+		return
+	}
+	ctx.Data["RepoBadges"] = badges
+}
+
 func renderCode(ctx *context.Context) {
 	ctx.Data["PageIsViewCode"] = true
 
@@ -623,6 +732,7 @@ func renderCode(ctx *context.Context) {
 		title += ": " + ctx.Repo.Repository.Description
 	}
 	ctx.Data["Title"] = title
+	ctx.Data["LFSStartServer"] = setting.LFS.StartServer
 
 	branchLink := ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL()
 	treeLink := branchLink
@@ -632,16 +742,28 @@ func renderCode(ctx *context.Context) {
 		treeLink += "/" + ctx.Repo.TreePath
 	}
 
+	renderStagedChangesCount(ctx)
+
 	// Get Topics of this repo
 	renderRepoTopics(ctx)
 	if ctx.Written() {
 		return
 	}
 
+	// Get the badges and quick links of this repo
+	renderRepoBadges(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	// Get current entry user currently looking at.
 	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 	if err != nil {
-		ctx.NotFoundOrServerError("Repo.Commit.GetTreeEntryByPath", git.IsErrNotExist, err)
+		if git.IsErrNotExist(err) {
+			ctx.NotFoundRepoPath("Repo.Commit.GetTreeEntryByPath", err)
+		} else {
+			ctx.ServerError("Repo.Commit.GetTreeEntryByPath", err)
+		}
 		return
 	}
 
@@ -726,13 +848,19 @@ func Forks(ctx *context.Context) {
 		return
 	}
 
+	divergences := make(map[int64]*git.DivergeObject, len(forks))
 	for _, fork := range forks {
 		if err = fork.GetOwner(); err != nil {
 			ctx.ServerError("GetOwner", err)
 			return
 		}
+		if divergences[fork.ID], err = repo_service.GetForkDivergence(ctx.Repo.Repository, fork); err != nil {
+			ctx.ServerError("GetForkDivergence", err)
+			return
+		}
 	}
 	ctx.Data["Forks"] = forks
+	ctx.Data["ForkDivergences"] = divergences
 
 	ctx.HTML(200, tplForks)
 }
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
@@ -86,7 +87,8 @@ func getOrgRepoCtx(ctx *context.Context) (*orgRepoCtx, error) {
 			}, nil
 		}
 
-		// Must be system webhooks instead
+		// Must be system webhooks instead, which may receive the instance events
+		ctx.Data["IsSystemWebhook"] = true
 		return &orgRepoCtx{
 			IsAdmin:         true,
 			IsSystemWebhook: true,
@@ -145,6 +147,15 @@ func WebhooksNew(ctx *context.Context) {
 	ctx.HTML(200, orCtx.NewTemplate)
 }
 
+// parseWebhookPayloadVersion returns the payload version chosen in the web form, the latest
+// version is used if the form has none
+func parseWebhookPayloadVersion(version int) int {
+	if models.IsValidWebhookPayloadVersion(version) {
+		return version
+	}
+	return api.WebhookPayloadVersion
+}
+
 // ParseHookEvent convert web form content to models.HookEvent
 func ParseHookEvent(form auth.WebhookForm) *models.HookEvent {
 	return &models.HookEvent{
@@ -170,6 +181,10 @@ func ParseHookEvent(form auth.WebhookForm) *models.HookEvent {
 			PullRequestReview:    form.PullRequestReview,
 			PullRequestSync:      form.PullRequestSync,
 			Repository:           form.Repository,
+			ReviewEnvironment:    form.ReviewEnvironment,
+			User:                 form.User,
+			Organization:         form.Organization,
+			AuthFailure:          form.AuthFailure,
 		},
 		BranchFilter: form.BranchFilter,
 	}
@@ -209,6 +224,7 @@ func GiteaHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		HookTaskType:    models.GITEA,
+		PayloadVersion:  parseWebhookPayloadVersion(form.PayloadVersion),
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
@@ -216,10 +232,15 @@ func GiteaHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -262,6 +283,7 @@ func newGogsWebhookPost(ctx *context.Context, form auth.NewGogshookForm, kind mo
 		HookEvent:       ParseHookEvent(form.WebhookForm),
 		IsActive:        form.Active,
 		HookTaskType:    kind,
+		PayloadVersion:  parseWebhookPayloadVersion(form.PayloadVersion),
 		OrgID:           orCtx.OrgID,
 		IsSystemWebhook: orCtx.IsSystemWebhook,
 	}
@@ -269,10 +291,15 @@ func newGogsWebhookPost(ctx *context.Context, form auth.NewGogshookForm, kind mo
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -320,10 +347,15 @@ func DiscordHooksNewPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -362,10 +394,15 @@ func DingtalkHooksNewPost(ctx *context.Context, form auth.NewDingtalkHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -413,10 +450,15 @@ func TelegramHooksNewPost(ctx *context.Context, form auth.NewTelegramHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -467,10 +509,15 @@ func MatrixHooksNewPost(ctx *context.Context, form auth.NewMatrixHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -509,10 +556,15 @@ func MSTeamsHooksNewPost(ctx *context.Context, form auth.NewMSTeamsHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -568,10 +620,15 @@ func SlackHooksNewPost(ctx *context.Context, form auth.NewSlackHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -610,10 +667,15 @@ func FeishuHooksNewPost(ctx *context.Context, form auth.NewFeishuHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.CreateWebhook(w); err != nil {
-		ctx.ServerError("CreateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("CreateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookCreate, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_hook_success"))
 	ctx.Redirect(orCtx.Link)
 }
@@ -662,6 +724,11 @@ func checkWebhook(ctx *context.Context) (*orgRepoCtx, *models.Webhook) {
 	ctx.Data["History"], err = w.History(1)
 	if err != nil {
 		ctx.ServerError("History", err)
+		return nil, nil
+	}
+	ctx.Data["HookTaskArchives"], err = models.GetHookTaskArchives(w.ID)
+	if err != nil {
+		ctx.ServerError("GetHookTaskArchives", err)
 	}
 	return orCtx, w
 }
@@ -709,14 +776,20 @@ func WebHooksEditPost(ctx *context.Context, form auth.NewWebhookForm) {
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	w.HTTPMethod = form.HTTPMethod
+	w.PayloadVersion = parseWebhookPayloadVersion(form.PayloadVersion)
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("WebHooksEditPost", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("WebHooksEditPost", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -748,14 +821,20 @@ func GogsHooksEditPost(ctx *context.Context, form auth.NewGogshookForm) {
 	w.Secret = form.Secret
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
+	w.PayloadVersion = parseWebhookPayloadVersion(form.PayloadVersion)
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("GogsHooksEditPost", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("GogsHooksEditPost", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -802,10 +881,15 @@ func SlackHooksEditPost(ctx *context.Context, form auth.NewSlackHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -844,10 +928,15 @@ func DiscordHooksEditPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -876,10 +965,15 @@ func DingtalkHooksEditPost(ctx *context.Context, form auth.NewDingtalkHookForm)
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -916,10 +1010,15 @@ func TelegramHooksEditPost(ctx *context.Context, form auth.NewTelegramHookForm)
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -959,10 +1058,15 @@ func MatrixHooksEditPost(ctx *context.Context, form auth.NewMatrixHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -991,10 +1095,15 @@ func MSTeamsHooksEditPost(ctx *context.Context, form auth.NewMSTeamsHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
@@ -1023,14 +1132,44 @@ func FeishuHooksEditPost(ctx *context.Context, form auth.NewFeishuHookForm) {
 		ctx.ServerError("UpdateEvent", err)
 		return
 	} else if err := models.UpdateWebhook(w); err != nil {
-		ctx.ServerError("UpdateWebhook", err)
+		if models.IsErrWebhookHostNotAllowed(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.webhook.host_not_allowed", err.(models.ErrWebhookHostNotAllowed).Host), orCtx.NewTemplate, &form)
+		} else {
+			ctx.ServerError("UpdateWebhook", err)
+		}
 		return
 	}
 
+	audit.RecordWebhook(models.AuditActionWebhookEdit, ctx.User, ctx.RemoteAddr(), w)
 	ctx.Flash.Success(ctx.Tr("repo.settings.update_hook_success"))
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
+// RestoreWebhookArchive brings the archived deliveries back to the history of a web hook
+func RestoreWebhookArchive(ctx *context.Context) {
+	orCtx, w := checkWebhook(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	archive, err := models.GetHookTaskArchive(w.ID, ctx.ParamsInt64(":archiveid"))
+	if err != nil {
+		if models.IsErrHookTaskArchiveNotExist(err) {
+			ctx.NotFound("GetHookTaskArchive", nil)
+		} else {
+			ctx.ServerError("GetHookTaskArchive", err)
+		}
+		return
+	}
+	if err := webhook.RestoreHookTaskArchive(archive); err != nil {
+		ctx.ServerError("RestoreHookTaskArchive", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.webhook.archive_restored", archive.NumTasks))
+	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
+}
+
 // TestWebhook test if web hook is work fine
 func TestWebhook(ctx *context.Context) {
 	hookID := ctx.ParamsInt64(":id")
@@ -1088,9 +1227,11 @@ func TestWebhook(ctx *context.Context) {
 
 // DeleteWebhook delete a webhook
 func DeleteWebhook(ctx *context.Context) {
-	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+	id := ctx.QueryInt64("id")
+	if err := models.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, id); err != nil {
 		ctx.Flash.Error("DeleteWebhookByRepoID: " + err.Error())
 	} else {
+		audit.RecordWebhook(models.AuditActionWebhookDelete, ctx.User, ctx.RemoteAddr(), &models.Webhook{ID: id, RepoID: ctx.Repo.Repository.ID})
 		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
 	}
 
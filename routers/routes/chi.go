@@ -8,14 +8,17 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"image/png"
 	"io"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/httpcache"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/metrics"
@@ -161,7 +164,7 @@ func storageHandler(storageSetting setting.Storage, prefix string, objStore stor
 				return
 			}
 
-			rPath := strings.TrimPrefix(req.RequestURI, "/"+prefix)
+			rPath := strings.TrimPrefix(req.URL.Path, "/"+prefix)
 			rPath = strings.TrimPrefix(rPath, "/")
 
 			fi, err := objStore.Stat(rPath)
@@ -183,6 +186,26 @@ func storageHandler(storageSetting setting.Storage, prefix string, objStore stor
 			}
 			defer fr.Close()
 
+			// The avatars are served in smaller sizes with the size query parameter
+			if sizeStr := req.URL.Query().Get("size"); len(sizeStr) > 0 {
+				size, err := strconv.Atoi(sizeStr)
+				if err != nil || !avatar.IsValidVariantSize(size) {
+					http.Error(w, fmt.Sprintf("Invalid size of %s %s", prefix, rPath), 400)
+					return
+				}
+				img, err := avatar.ResizeVariant(fr, size)
+				if err != nil {
+					log.Error("Error whilst resizing %s %s. Error: %v", prefix, rPath, err)
+					http.Error(w, fmt.Sprintf("Error whilst resizing %s %s", prefix, rPath), 500)
+					return
+				}
+				w.Header().Set("Content-Type", "image/png")
+				if err := png.Encode(w, img); err != nil {
+					log.Error("Error whilst rendering %s %s. Error: %v", prefix, rPath, err)
+				}
+				return
+			}
+
 			_, err = io.Copy(w, fr)
 			if err != nil {
 				log.Error("Error whilst rendering %s %s. Error: %v", prefix, rPath, err)
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers"
 	"code.gitea.io/gitea/routers/admin"
+	"code.gitea.io/gitea/routers/api/scim"
 	apiv1 "code.gitea.io/gitea/routers/api/v1"
 	"code.gitea.io/gitea/routers/dev"
 	"code.gitea.io/gitea/routers/events"
@@ -175,6 +176,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		}
 	}
 
+	m.Use(repo.RenderOriginFilter)
 	m.Use(user.GetNotificationCount)
 	m.Use(func(ctx *context.Context) {
 		ctx.Data["UnitWikiGlobalDisabled"] = models.UnitTypeWiki.UnitGlobalDisabled()
@@ -197,10 +199,13 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Get("/organizations", routers.ExploreOrganizations)
 		m.Get("/code", routers.ExploreCode)
 	}, ignSignIn)
+	m.Get("/search", ignSignIn, routers.Search)
+	m.Get("/legal/:name", routers.LegalPage)
 	m.Combo("/install", routers.InstallInit).Get(routers.Install).
 		Post(bindIgnErr(auth.InstallForm{}), routers.InstallPost)
 	m.Get("/^:type(issues|pulls)$", reqSignIn, user.Issues)
 	m.Get("/milestones", reqSignIn, reqMilestonesDashboardPageEnabled, user.Milestones)
+	m.Get("/s/:token", reqSignIn, repo.ShortLink)
 
 	// ***** START: User *****
 	m.Group("/user", func() {
@@ -244,6 +249,13 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		})
 	}, reqSignOut)
 
+	m.Group("/user/saml/:provider", func() {
+		m.Get("", reqSignOut, user.SignInSAML)
+		m.Get("/metadata", user.SAMLMetadata)
+		m.Post("/acs", user.SAMLACS)
+		m.Combo("/slo").Get(user.SAMLSLO).Post(user.SAMLSLO)
+	}, ignSignInAndCsrf)
+
 	m.Any("/user/events", reqSignIn, events.Events)
 
 	m.Group("/login/oauth", func() {
@@ -253,6 +265,9 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Post("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
 	}, ignSignInAndCsrf, reqSignIn)
 	m.Post("/login/oauth/access_token", bindIgnErr(auth.AccessTokenForm{}), ignSignInAndCsrf, user.AccessTokenOAuth)
+	m.Post("/login/oauth/device_authorization", bindIgnErr(auth.DeviceAuthorizationForm{}), ignSignInAndCsrf, user.DeviceAuthorizationOAuth)
+	m.Combo("/login/device", reqSignIn).Get(user.DeviceAuthorization).
+		Post(bindIgnErr(auth.DeviceGrantForm{}), user.DeviceGrantOAuth)
 
 	m.Group("/user/settings", func() {
 		m.Get("", userSetting.Profile)
@@ -302,6 +317,10 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Combo("/keys").Get(userSetting.Keys).
 			Post(bindIgnErr(auth.AddKeyForm{}), userSetting.KeysPost)
 		m.Post("/keys/delete", userSetting.DeleteKey)
+		m.Combo("/replies").Get(userSetting.SavedReplies).
+			Post(bindIgnErr(auth.SavedReplyForm{}), userSetting.SavedRepliesPost)
+		m.Post("/replies/delete", userSetting.DeleteSavedReply)
+		m.Post("/replies/:id", bindIgnErr(auth.SavedReplyForm{}), userSetting.EditSavedReplyPost)
 		m.Get("/organization", userSetting.Organization)
 		m.Get("/repos", userSetting.Repos)
 		m.Post("/repos/unadopted", userSetting.AdoptOrDeleteRepository)
@@ -321,6 +340,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Get("/forgot_password", user.ForgotPasswd)
 		m.Post("/forgot_password", user.ForgotPasswdPost)
 		m.Post("/logout", user.SignOut)
+		m.Combo("/legal", reqSignIn).Get(user.LegalAccept).Post(user.LegalAcceptPost)
 		m.Get("/task/:task", user.TaskStatus)
 	})
 	// ***** END: User *****
@@ -361,6 +381,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 
 		m.Group("/orgs", func() {
 			m.Get("", admin.Organizations)
+			m.Combo("/:orgid/webhook_hosts").Get(admin.OrgWebhookHosts).
+				Post(bindIgnErr(auth.AdminWebhookHostsForm{}), admin.OrgWebhookHostsPost)
 		})
 
 		m.Group("/repos", func() {
@@ -383,6 +405,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 			m.Post("/feishu/new", bindIgnErr(auth.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 			m.Get("/:id", repo.WebHooksEdit)
+			m.Post("/:id/archives/:archiveid/restore", repo.RestoreWebhookArchive)
 			m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 			m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 			m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -407,6 +430,30 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/delete", admin.DeleteNotices)
 			m.Post("/empty", admin.EmptyNotices)
 		})
+
+		m.Group("/legal", func() {
+			m.Get("", admin.LegalPages)
+			m.Get("/:id", admin.LegalAcceptances)
+		})
+
+		m.Group("/analytics", func() {
+			m.Get("", admin.Analytics)
+			m.Get("/export", admin.AnalyticsExport)
+		})
+
+		m.Group("/recalculations", func() {
+			m.Get("", admin.Recalculations)
+			m.Get("/progress", admin.RecalculationsProgress)
+			m.Post("/:name/start", admin.StartRecalculation)
+			m.Post("/:name/cancel", admin.CancelRecalculation)
+		})
+
+		m.Group("/audit", func() {
+			m.Get("", admin.Audit)
+			m.Get("/export", admin.AuditExport)
+		})
+
+		m.Get("/credentials", admin.StaleCredentials)
 	}, adminReq)
 	// ***** END: Admin *****
 
@@ -452,6 +499,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/members/action/:action", org.MembersAction)
 
 			m.Get("/teams", org.Teams)
+			m.Get("/community", org.Community)
 		}, context.OrgAssignment(true))
 
 		m.Group("/:org", func() {
@@ -488,6 +536,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 					m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 					m.Post("/feishu/new", bindIgnErr(auth.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 					m.Get("/:id", repo.WebHooksEdit)
+					m.Post("/:id/archives/:archiveid/restore", repo.RestoreWebhookArchive)
 					m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 					m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 					m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -507,6 +556,13 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 					m.Post("/initialize", bindIgnErr(auth.InitializeLabelsForm{}), org.InitializeLabels)
 				})
 
+				m.Group("/replies", func() {
+					m.Combo("").Get(org.SavedReplies).
+						Post(bindIgnErr(auth.SavedReplyForm{}), org.SavedRepliesPost)
+					m.Post("/delete", org.DeleteSavedReply)
+					m.Post("/:id", bindIgnErr(auth.SavedReplyForm{}), org.EditSavedReplyPost)
+				})
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
@@ -565,6 +621,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Post("/feishu/new", bindIgnErr(auth.NewFeishuHookForm{}), repo.FeishuHooksNewPost)
 				m.Get("/:id", repo.WebHooksEdit)
 				m.Post("/:id/test", repo.TestWebhook)
+				m.Post("/:id/archives/:archiveid/restore", repo.RestoreWebhookArchive)
 				m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 				m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 				m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
@@ -588,6 +645,19 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Post("/delete", repo.DeleteDeployKey)
 			})
 
+			m.Group("/badges", func() {
+				m.Combo("").Get(repo.Badges).
+					Post(bindIgnErr(auth.RepoBadgeForm{}), repo.BadgesPost)
+				m.Post("/delete", repo.DeleteBadge)
+			})
+
+			m.Combo("/stale").Get(repo.StalePolicy).
+				Post(bindIgnErr(auth.StalePolicyForm{}), repo.StalePolicyPost)
+			m.Combo("/release_automation").Get(repo.ReleaseAutomation).
+				Post(bindIgnErr(auth.ReleaseAutomationForm{}), repo.ReleaseAutomationPost)
+			m.Combo("/status_issues").Get(repo.StatusIssues).
+				Post(bindIgnErr(auth.StatusIssueRuleForm{}), repo.StatusIssuesPost)
+
 			m.Group("/lfs", func() {
 				m.Get("", repo.LFSFiles)
 				m.Get("/show/:oid", repo.LFSFileGet)
@@ -614,7 +684,12 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 	m.Group("/:username/:reponame", func() {
 		m.Group("/milestone", func() {
 			m.Get("/:id", repo.MilestoneIssuesAndPulls)
+			m.Get("/:id/dependencies", repo.MilestoneDependencyGraph)
 		}, reqRepoIssuesOrPullsReader, context.RepoRef())
+		m.Combo("/issues/export", reqRepoIssuesOrPullsReader).
+			Get(repo.ExportIssues).
+			Post(repo.InitiateIssuesExport)
+		m.Get("/issues/:index/dependencies", reqRepoIssuesOrPullsReader, repo.IssueDependencyGraph)
 		m.Combo("/compare/*", repo.MustBeNotEmpty, reqRepoCodeReader, repo.SetEditorconfigIfExists).
 			Get(ignSignIn, repo.SetDiffViewStyle, repo.CompareDiff).
 			Post(reqSignIn, context.RepoMustNotBeArchived(), reqRepoPullsReader, repo.MustAllowPulls, bindIgnErr(auth.CreateIssueForm{}), repo.CompareAndPullRequestPost)
@@ -628,6 +703,9 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 					Post(bindIgnErr(auth.CreateIssueForm{}), repo.NewIssuePost)
 				m.Get("/choose", context.RepoRef(), repo.NewIssueChooseTemplate)
 			})
+			m.Combo("/:index/propose_fix", repo.MustBeNotEmpty, reqRepoCodeReader, repo.MustAllowPulls).
+				Get(repo.ProposeFix).
+				Post(bindIgnErr(auth.ProposeFixForm{}), repo.ProposeFixPost)
 		}, context.RepoMustNotBeArchived(), reqRepoIssueReader)
 		// FIXME: should use different URLs but mostly same logic for comments of issue and pull reuqest.
 		// So they can apply their own enable/disable logic on routers.
@@ -636,11 +714,17 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Post("/title", repo.UpdateIssueTitle)
 				m.Post("/content", repo.UpdateIssueContent)
 				m.Post("/watch", repo.IssueWatch)
+				m.Post("/reminders", repo.CreateIssueReminder)
+				m.Post("/reminders/:id/delete", repo.DeleteIssueReminder)
 				m.Post("/ref", repo.UpdateIssueRef)
 				m.Group("/dependency", func() {
 					m.Post("/add", repo.AddDependency)
 					m.Post("/delete", repo.RemoveDependency)
 				})
+				m.Group("/children", func() {
+					m.Post("/add", repo.AttachSubIssue)
+					m.Post("/delete", repo.DetachSubIssue)
+				}, reqRepoIssueWriter)
 				m.Combo("/comments").Post(repo.MustAllowUserComment, bindIgnErr(auth.CreateCommentForm{}), repo.NewComment)
 				m.Group("/times", func() {
 					m.Post("/add", bindIgnErr(auth.AddTimeManuallyForm{}), repo.AddTimeManually)
@@ -706,6 +790,11 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Combo("/_upload/*", repo.MustBeAbleToUpload).
 					Get(repo.UploadFile).
 					Post(bindIgnErr(auth.UploadRepoFileForm{}), repo.UploadFilePost)
+				m.Combo("/_cherrypick/:sha/*").Get(repo.CherryPick).
+					Post(bindIgnErr(auth.CherryPickForm{}), repo.CherryPickPost)
+				m.Combo("/_staged/*").Get(repo.StagedChanges).
+					Post(bindIgnErr(auth.CommitStagedChangesForm{}), repo.StagedChangesPost)
+				m.Post("/_unstage/*", repo.UnstageChangePost)
 			}, context.RepoRefByType(context.RepoRefBranch), repo.MustBeEditable)
 			m.Group("", func() {
 				m.Post("/upload-file", repo.UploadFileToServer)
@@ -826,6 +915,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/:period", repo.Activity)
 		}, context.RepoRef(), repo.MustBeNotEmpty, context.RequireRepoReaderOr(models.UnitTypePullRequests, models.UnitTypeIssues, models.UnitTypeReleases))
 
+		m.Get("/community", repo.MustBeNotEmpty, context.RepoRef(), reqRepoCodeReader, repo.Community)
+
 		m.Group("/activity_author_data", func() {
 			m.Get("", repo.ActivityAuthors)
 			m.Get("/:period", repo.ActivityAuthors)
@@ -848,11 +939,20 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get(".diff", repo.DownloadPullDiff)
 			m.Get(".patch", repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
+			m.Combo("/commits/:sha/message", context.RepoRef()).
+				Get(repo.ViewPullCommitMessage).
+				Post(reqSignIn, context.RepoMustNotBeArchived(), bindIgnErr(auth.CommitMessageCommentForm{}), repo.CreateCommitMessageComment)
 			m.Post("/merge", context.RepoMustNotBeArchived(), bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/update", repo.UpdatePullRequest)
+			m.Post("/auto_publish", reqSignIn, context.RepoMustNotBeArchived(), repo.SetPullAutoPublish)
+			m.Combo("/conflicts", reqSignIn, context.RepoMustNotBeArchived()).
+				Get(repo.PullConflicts).
+				Post(bindIgnErr(auth.ResolvePullConflictsForm{}), repo.ResolvePullConflicts)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
+				m.Post("/viewed", reqSignIn, repo.UpdateViewedFile)
+				m.Get("/*", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFileDiff)
 				m.Group("/reviews", func() {
 					m.Post("/comments", bindIgnErr(auth.CodeCommentForm{}), repo.CreateCodeComment)
 					m.Post("/submit", bindIgnErr(auth.SubmitReviewForm{}), repo.SubmitReview)
@@ -878,6 +978,15 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownload)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
 
+		m.Group("/render", func() {
+			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RenderFile)
+			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.RenderFile)
+			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.RenderFile)
+		}, repo.MustBeNotEmpty, reqRepoCodeReader)
+
+		m.Get("/embed/commit/*", repo.MustBeNotEmpty, reqRepoCodeReader, context.RepoRefByType(context.RepoRefCommit), repo.EmbedFile)
+		m.Get("/image-diff/:before([a-f0-9]{40})/:after([a-f0-9]{40})", repo.MustBeNotEmpty, reqRepoCodeReader, repo.ImageDiff)
+
 		m.Group("/commits", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RefCommits)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.RefCommits)
@@ -928,6 +1037,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Post("/objects/batch", lfs.BatchHandler)
 				m.Get("/objects/:oid/:filename", lfs.ObjectOidHandler)
 				m.Any("/objects/:oid", lfs.ObjectOidHandler)
+				m.Post("/objects/:oid/multipart/commit", lfs.MultipartCommitHandler)
+				m.Post("/objects/:oid/multipart/abort", lfs.MultipartAbortHandler)
 				m.Post("/objects", lfs.PostHandler)
 				m.Post("/verify", lfs.VerifyHandler)
 				m.Group("/locks", func() {
@@ -954,6 +1065,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 
 	if setting.API.EnableSwagger {
 		m.Get("/swagger.v1.json", templates.JSONRenderer(), routers.SwaggerV1Json)
+		m.Get("/openapi.v1.json", templates.JSONRenderer(), routers.OpenAPIV1Json)
 	}
 
 	var handlers []macaron.Handler
@@ -970,6 +1082,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 	handlers = append(handlers, ignSignIn)
 	m.Group("/api", func() {
 		apiv1.RegisterRoutes(m)
+		scim.RegisterRoutes(m)
 	}, handlers...)
 
 	m.Group("/api/internal", func() {
@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package routers
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/search"
+)
+
+const (
+	// tplSearch global search page template
+	tplSearch base.TplName = "search"
+
+	// searchPreviewSize is the number of the results of every type listed by the search overview
+	searchPreviewSize = 5
+)
+
+// Search render the global search page, the results of every type are counted and the
+// results of the selected type are listed
+func Search(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("search.title")
+	ctx.Data["PageIsSearch"] = true
+
+	keyword := ctx.QueryTrim("q")
+	if !isKeywordValid(keyword) {
+		keyword = ""
+	}
+	searchType := search.Type(ctx.Query("type"))
+	if !search.IsValidType(searchType) {
+		searchType = ""
+	}
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["SearchType"] = string(searchType)
+	ctx.Data["SearchTypes"] = search.Types()
+	ctx.Data["ShowUserEmail"] = setting.UI.ShowUserEmail
+	ctx.Data["RequireHighlightJS"] = true
+
+	if len(keyword) == 0 {
+		ctx.HTML(http.StatusOK, tplSearch)
+		return
+	}
+
+	result, err := search.Search(&search.Options{
+		Actor:       ctx.User,
+		Keyword:     keyword,
+		Type:        searchType,
+		Page:        page,
+		PageSize:    setting.UI.ExplorePagingNum,
+		PreviewSize: searchPreviewSize,
+	})
+	if err != nil {
+		ctx.ServerError("Search", err)
+		return
+	}
+	ctx.Data["Result"] = result
+
+	if len(searchType) > 0 {
+		pager := context.NewPagination(int(result.Count(searchType)), setting.UI.ExplorePagingNum, page, 5)
+		pager.SetDefaultParams(ctx)
+		pager.AddParam(ctx, "type", "SearchType")
+		ctx.Data["Page"] = pager
+	}
+
+	ctx.HTML(http.StatusOK, tplSearch)
+}
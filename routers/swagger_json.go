@@ -5,8 +5,11 @@
 package routers
 
 import (
+	"net/http"
+
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/openapi"
 )
 
 // tplSwaggerV1Json swagger v1 json template
@@ -16,3 +19,20 @@ const tplSwaggerV1Json base.TplName = "swagger/v1_json"
 func SwaggerV1Json(ctx *context.Context) {
 	ctx.HTML(200, tplSwaggerV1Json)
 }
+
+// OpenAPIV1Json renders the v1 json converted to OpenAPI 3.1
+func OpenAPIV1Json(ctx *context.Context) {
+	spec, err := ctx.HTMLString(string(tplSwaggerV1Json), ctx.Data)
+	if err != nil {
+		ctx.ServerError("HTMLString", err)
+		return
+	}
+	converted, err := openapi.ConvertSwagger2([]byte(spec))
+	if err != nil {
+		ctx.ServerError("ConvertSwagger2", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/json")
+	ctx.Status(http.StatusOK)
+	_, _ = ctx.Resp.Write(converted)
+}
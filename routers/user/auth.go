@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/audit"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/base"
@@ -19,9 +20,11 @@ import (
 	"code.gitea.io/gitea/modules/eventsource"
 	"code.gitea.io/gitea/modules/hcaptcha"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/password"
 	"code.gitea.io/gitea/modules/recaptcha"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/routers/utils"
 	"code.gitea.io/gitea/services/externalaccount"
@@ -145,6 +148,13 @@ func SignIn(ctx *context.Context) {
 	ctx.Data["PageIsLogin"] = true
 	ctx.Data["EnableSSPI"] = models.IsSSPIEnabled()
 
+	samlSources, err := models.ActiveLoginSources(models.LoginSAML)
+	if err != nil {
+		ctx.ServerError("ActiveLoginSources", err)
+		return
+	}
+	ctx.Data["SAMLSources"] = samlSources
+
 	ctx.HTML(200, tplSignIn)
 }
 
@@ -165,6 +175,13 @@ func SignInPost(ctx *context.Context, form auth.SignInForm) {
 	ctx.Data["PageIsLogin"] = true
 	ctx.Data["EnableSSPI"] = models.IsSSPIEnabled()
 
+	samlSources, err := models.ActiveLoginSources(models.LoginSAML)
+	if err != nil {
+		ctx.ServerError("ActiveLoginSources", err)
+		return
+	}
+	ctx.Data["SAMLSources"] = samlSources
+
 	if ctx.HasError() {
 		ctx.HTML(200, tplSignIn)
 		return
@@ -175,11 +192,14 @@ func SignInPost(ctx *context.Context, form auth.SignInForm) {
 		if models.IsErrUserNotExist(err) {
 			ctx.RenderWithErr(ctx.Tr("form.username_password_incorrect"), tplSignIn, &form)
 			log.Info("Failed authentication attempt for %s from %s", form.UserName, ctx.RemoteAddr())
+			notification.NotifyAuthFailure(form.UserName, ctx.RemoteAddr(), api.HookAuthFailurePassword)
 		} else if models.IsErrEmailAlreadyUsed(err) {
 			ctx.RenderWithErr(ctx.Tr("form.email_been_used"), tplSignIn, &form)
 			log.Info("Failed authentication attempt for %s from %s", form.UserName, ctx.RemoteAddr())
+			notification.NotifyAuthFailure(form.UserName, ctx.RemoteAddr(), api.HookAuthFailurePassword)
 		} else if models.IsErrUserProhibitLogin(err) {
 			log.Info("Failed authentication attempt for %s from %s", form.UserName, ctx.RemoteAddr())
+			notification.NotifyAuthFailure(form.UserName, ctx.RemoteAddr(), api.HookAuthFailureProhibited)
 			ctx.Data["Title"] = ctx.Tr("auth.prohibit_login")
 			ctx.HTML(200, "user/auth/prohibit_login")
 		} else if models.IsErrUserInactive(err) {
@@ -188,6 +208,7 @@ func SignInPost(ctx *context.Context, form auth.SignInForm) {
 				ctx.HTML(200, TplActivate)
 			} else {
 				log.Info("Failed authentication attempt for %s from %s", form.UserName, ctx.RemoteAddr())
+				notification.NotifyAuthFailure(form.UserName, ctx.RemoteAddr(), api.HookAuthFailureProhibited)
 				ctx.Data["Title"] = ctx.Tr("auth.prohibit_login")
 				ctx.HTML(200, "user/auth/prohibit_login")
 			}
@@ -306,9 +327,21 @@ func TwoFactorPost(ctx *context.Context, form auth.TwoFactorAuthForm) {
 		return
 	}
 
+	notifyTwoFactorFailure(ctx, id)
 	ctx.RenderWithErr(ctx.Tr("auth.twofa_passcode_incorrect"), tplTwofa, auth.TwoFactorAuthForm{})
 }
 
+// notifyTwoFactorFailure logs and notifies a wrong passcode or scratch token of a user
+func notifyTwoFactorFailure(ctx *context.Context, uid int64) {
+	u, err := models.GetUserByID(uid)
+	if err != nil {
+		log.Error("GetUserByID: %v", err)
+		return
+	}
+	log.Info("Failed two-factor authentication attempt for %s from %s", u.Name, ctx.RemoteAddr())
+	notification.NotifyAuthFailure(u.Name, ctx.RemoteAddr(), api.HookAuthFailureTwoFactor)
+}
+
 // TwoFactorScratch shows the scratch code form for two-factor authentication.
 func TwoFactorScratch(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("twofa_scratch")
@@ -371,6 +404,7 @@ func TwoFactorScratchPost(ctx *context.Context, form auth.TwoFactorScratchAuthFo
 		return
 	}
 
+	notifyTwoFactorFailure(ctx, id)
 	ctx.RenderWithErr(ctx.Tr("auth.twofa_scratch_token_incorrect"), tplTwofaScratch, auth.TwoFactorScratchAuthForm{})
 }
 
@@ -498,6 +532,8 @@ func handleSignInFull(ctx *context.Context, u *models.User, remember bool, obeyR
 			setting.CookieRememberName, u.Name, days, setting.AppSubURL, setting.SessionConfig.Domain, setting.SessionConfig.Secure, true)
 	}
 
+	audit.Record(models.AuditActionUserLogin, u, ctx.RemoteAddr(), u, "Signed in")
+
 	_ = ctx.Session.Delete("openid_verified_uri")
 	_ = ctx.Session.Delete("openid_signin_remember")
 	_ = ctx.Session.Delete("openid_determined_email")
@@ -616,6 +652,11 @@ func handleOAuth2SignIn(u *models.User, gothUser goth.User, ctx *context.Context
 		return
 	}
 
+	// the groups of the user are re-evaluated on every sign in
+	if err := models.SyncOAuth2GroupTeams(u, gothUser); err != nil {
+		log.Error("SyncOAuth2GroupTeams [user: %s]: %v", u.Name, err)
+	}
+
 	// If this user is enrolled in 2FA, we can't sign the user in just yet.
 	// Instead, redirect them to the 2FA authentication page.
 	_, err = models.GetTwoFactorByUID(u.ID)
@@ -982,6 +1023,7 @@ func LinkAccountPostRegister(ctx *context.Context, cpt *captcha.Captcha, form au
 		return
 	}
 	log.Trace("Account created: %s", u.Name)
+	notification.NotifyCreateUser(u, u)
 
 	// Auto-set admin for the only user.
 	if models.CountUsers() == 1 {
@@ -998,6 +1040,9 @@ func LinkAccountPostRegister(ctx *context.Context, cpt *captcha.Captcha, form au
 	if err := models.UpdateExternalUser(u, gothUser.(goth.User)); err != nil {
 		log.Error("UpdateExternalUser failed: %v", err)
 	}
+	if err := models.SyncOAuth2GroupTeams(u, gothUser.(goth.User)); err != nil {
+		log.Error("SyncOAuth2GroupTeams [user: %s]: %v", u.Name, err)
+	}
 
 	// Send confirmation email
 	if setting.Service.RegisterEmailConfirm && u.ID > 1 {
@@ -1036,7 +1081,12 @@ func SignOut(ctx *context.Context) {
 			Data: ctx.Session.ID(),
 		})
 	}
+	samlLogoutURL := signOutSAML(ctx)
 	HandleSignOut(ctx)
+	if len(samlLogoutURL) > 0 {
+		ctx.Redirect(samlLogoutURL)
+		return
+	}
 	ctx.Redirect(setting.AppSubURL + "/")
 }
 
@@ -1169,6 +1219,7 @@ func SignUpPost(ctx *context.Context, cpt *captcha.Captcha, form auth.RegisterFo
 		return
 	}
 	log.Trace("Account created: %s", u.Name)
+	notification.NotifyCreateUser(u, u)
 
 	// Auto-set admin for the only user.
 	if models.CountUsers() == 1 {
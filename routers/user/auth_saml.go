@@ -0,0 +1,289 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/saml"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	crewjamsaml "github.com/crewjam/saml"
+)
+
+// samlRequestTTL is the number of seconds the identity provider has to answer an authentication request
+const samlRequestTTL = 300
+
+// samlBaseURL returns the URL the endpoints of the service provider of the SAML login source are under
+func samlBaseURL(source *models.LoginSource) string {
+	return setting.AppURL + "user/saml/" + url.PathEscape(source.Name)
+}
+
+// samlServiceProvider returns the active SAML login source of the request and its service provider
+func samlServiceProvider(ctx *context.Context) (*models.LoginSource, *crewjamsaml.ServiceProvider) {
+	source, err := models.GetActiveSAMLLoginSourceByName(ctx.Params(":provider"))
+	if err != nil {
+		ctx.ServerError("GetActiveSAMLLoginSourceByName", err)
+		return nil, nil
+	}
+	if source == nil {
+		ctx.NotFound("GetActiveSAMLLoginSourceByName", nil)
+		return nil, nil
+	}
+	sp, err := source.SAML().ServiceProvider(samlBaseURL(source))
+	if err != nil {
+		ctx.ServerError("ServiceProvider", err)
+		return nil, nil
+	}
+	return source, sp
+}
+
+// SignInSAML redirects to the identity provider of the SAML login source to authenticate the user
+func SignInSAML(ctx *context.Context) {
+	_, sp := samlServiceProvider(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	idpURL := sp.GetSSOBindingLocation(crewjamsaml.HTTPRedirectBinding)
+	if len(idpURL) == 0 {
+		ctx.ServerError("SignInSAML", errors.New("the identity provider doesn't support the HTTP-Redirect binding"))
+		return
+	}
+	req, err := sp.MakeAuthenticationRequest(idpURL, crewjamsaml.HTTPRedirectBinding, crewjamsaml.HTTPPostBinding)
+	if err != nil {
+		ctx.ServerError("MakeAuthenticationRequest", err)
+		return
+	}
+
+	// the assertion is posted back without the session cookie, the request is kept by its relay state instead
+	relayState, err := generate.GetRandomString(32)
+	if err != nil {
+		ctx.ServerError("GetRandomString", err)
+		return
+	}
+	if err := ctx.Cache.Put("saml_request_"+relayState, req.ID, samlRequestTTL); err != nil {
+		ctx.ServerError("Cache.Put", err)
+		return
+	}
+	redirectURL, err := req.Redirect(relayState, sp)
+	if err != nil {
+		ctx.ServerError("Redirect", err)
+		return
+	}
+	ctx.Redirect(redirectURL.String())
+}
+
+// SAMLMetadata renders the metadata of the service provider of the SAML login source
+func SAMLMetadata(ctx *context.Context) {
+	_, sp := samlServiceProvider(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		ctx.ServerError("MarshalIndent", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/samlmetadata+xml")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(metadata); err != nil {
+		log.Error("Write: %v", err)
+	}
+}
+
+// SAMLACS handles the assertion posted by the identity provider of the SAML login source
+func SAMLACS(ctx *context.Context) {
+	source, sp := samlServiceProvider(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var possibleRequestIDs []string
+	if relayState := ctx.Req.PostFormValue("RelayState"); len(relayState) > 0 {
+		if requestID, ok := ctx.Cache.Get("saml_request_" + relayState).(string); ok {
+			possibleRequestIDs = append(possibleRequestIDs, requestID)
+		}
+		_ = ctx.Cache.Delete("saml_request_" + relayState)
+	}
+
+	assertion, err := saml.ParseResponse(sp, ctx.Req.Request, possibleRequestIDs)
+	if err != nil {
+		log.Warn("Invalid SAML response of %s from %s: %v", source.Name, ctx.RemoteAddr(), err)
+		ctx.Flash.Error(ctx.Tr("auth.saml_invalid_response"))
+		ctx.Redirect(setting.AppSubURL + "/user/login")
+		return
+	}
+
+	claims, err := source.SAML().Claims(assertion)
+	if err != nil {
+		log.Warn("Invalid SAML assertion of %s from %s: %v", source.Name, ctx.RemoteAddr(), err)
+		ctx.Flash.Error(ctx.Tr("auth.saml_invalid_response"))
+		ctx.Redirect(setting.AppSubURL + "/user/login")
+		return
+	}
+
+	u, err := models.LoginViaSAML(source, claims)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			log.Info("Failed authentication attempt for %s via %s from %s", claims.NameID, source.Name, ctx.RemoteAddr())
+			ctx.Flash.Error(ctx.Tr("auth.saml_user_not_exist", claims.Username))
+			ctx.Redirect(setting.AppSubURL + "/user/login")
+		} else if models.IsErrUserProhibitLogin(err) {
+			log.Info("Failed authentication attempt for %s via %s from %s", claims.NameID, source.Name, ctx.RemoteAddr())
+			ctx.Data["Title"] = ctx.Tr("auth.prohibit_login")
+			ctx.HTML(200, "user/auth/prohibit_login")
+		} else {
+			ctx.ServerError("LoginViaSAML", err)
+		}
+		return
+	}
+
+	samlSession, err := models.CreateSAMLSession(source.ID, u.ID, claims)
+	if err != nil {
+		ctx.ServerError("CreateSAMLSession", err)
+		return
+	}
+	if err := ctx.Session.Set("samlSession", samlSession.ID); err != nil {
+		log.Error("Error setting samlSession in session: %v", err)
+	}
+
+	// If this user is enrolled in 2FA, we can't sign the user in just yet.
+	// Instead, redirect them to the 2FA authentication page.
+	_, err = models.GetTwoFactorByUID(u.ID)
+	if err != nil {
+		if models.IsErrTwoFactorNotEnrolled(err) {
+			handleSignIn(ctx, u, false)
+		} else {
+			ctx.ServerError("UserSignIn", err)
+		}
+		return
+	}
+
+	// User needs to use 2FA, save data and redirect to 2FA page.
+	if err := ctx.Session.Set("twofaUid", u.ID); err != nil {
+		log.Error("Error setting twofaUid in session: %v", err)
+	}
+	if err := ctx.Session.Set("twofaRemember", false); err != nil {
+		log.Error("Error setting twofaRemember in session: %v", err)
+	}
+	if err := ctx.Session.Release(); err != nil {
+		log.Error("Error storing session: %v", err)
+	}
+
+	// If U2F is enrolled -> Redirect to U2F instead
+	regs, err := models.GetU2FRegistrationsByUID(u.ID)
+	if err == nil && len(regs) > 0 {
+		ctx.Redirect(setting.AppSubURL + "/user/u2f")
+		return
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/two_factor")
+}
+
+// SAMLSLO handles the single logout of the identity provider of the SAML login source, either its logout
+// request ending the sessions of a user or its response to the logout request of a user signing out
+func SAMLSLO(ctx *context.Context) {
+	source, sp := samlServiceProvider(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if len(ctx.Query("SAMLResponse")) > 0 || len(ctx.Req.PostFormValue("SAMLResponse")) > 0 {
+		// the sessions were already ended when the user signed out
+		if err := sp.ValidateLogoutResponseRequest(ctx.Req.Request); err != nil {
+			log.Warn("Invalid SAML logout response of %s from %s: %v", source.Name, ctx.RemoteAddr(), err)
+		}
+		ctx.Redirect(setting.AppSubURL + "/")
+		return
+	}
+
+	req, err := saml.ParseLogoutRequest(sp, ctx.Req.PostFormValue("SAMLRequest"))
+	if err != nil {
+		log.Warn("Invalid SAML logout request of %s from %s: %v", source.Name, ctx.RemoteAddr(), err)
+		ctx.Error(http.StatusBadRequest)
+		return
+	}
+
+	var sessionIndexes []string
+	if req.SessionIndex != nil && len(req.SessionIndex.Value) > 0 {
+		sessionIndexes = append(sessionIndexes, req.SessionIndex.Value)
+	}
+	if _, err := models.DeleteSAMLSessionsByNameID(source.ID, req.NameID.Value, sessionIndexes); err != nil {
+		ctx.ServerError("DeleteSAMLSessionsByNameID", err)
+		return
+	}
+
+	relayState := ctx.Req.PostFormValue("RelayState")
+	if len(sp.GetSLOBindingLocation(crewjamsaml.HTTPPostBinding)) == 0 {
+		redirect, err := sp.MakeRedirectLogoutResponse(req.ID, relayState)
+		if err != nil {
+			ctx.ServerError("MakeRedirectLogoutResponse", err)
+			return
+		}
+		ctx.Redirect(redirect.String())
+		return
+	}
+	form, err := sp.MakePostLogoutResponse(req.ID, relayState)
+	if err != nil {
+		ctx.ServerError("MakePostLogoutResponse", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(form); err != nil {
+		log.Error("Write: %v", err)
+	}
+}
+
+// signOutSAML ends the SAML session of the signed in user and returns the URL of the single logout of its
+// identity provider, empty if the user didn't sign in with a SAML login source
+func signOutSAML(ctx *context.Context) string {
+	id, ok := ctx.Session.Get("samlSession").(int64)
+	if !ok {
+		return ""
+	}
+	samlSession, err := models.GetSAMLSessionByID(id)
+	if err != nil || samlSession == nil {
+		if err != nil {
+			log.Error("GetSAMLSessionByID: %v", err)
+		}
+		return ""
+	}
+	if err := models.DeleteSAMLSession(samlSession.ID); err != nil {
+		log.Error("DeleteSAMLSession: %v", err)
+	}
+
+	source, err := models.GetLoginSourceByID(samlSession.LoginSourceID)
+	if err != nil {
+		log.Error("GetLoginSourceByID: %v", err)
+		return ""
+	}
+	if !source.IsSAML() || !source.IsActived {
+		return ""
+	}
+	sp, err := source.SAML().ServiceProvider(samlBaseURL(source))
+	if err != nil {
+		log.Error("ServiceProvider: %v", err)
+		return ""
+	}
+	if len(sp.GetSLOBindingLocation(crewjamsaml.HTTPRedirectBinding)) == 0 {
+		return ""
+	}
+	redirect, err := sp.MakeRedirectLogoutRequest(samlSession.NameID, "")
+	if err != nil {
+		log.Error("MakeRedirectLogoutRequest: %v", err)
+		return ""
+	}
+	return redirect.String()
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/avatar/provider"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -64,5 +65,9 @@ func AvatarByEmailHash(ctx *context.Context) {
 	if size == 0 {
 		size = base.DefaultAvatarSize
 	}
-	ctx.Redirect(base.SizedAvatarLinkWithDomain(email, size))
+	link := provider.AvatarLink(email, size)
+	if len(link) == 0 {
+		link = base.DefaultAvatarLink()
+	}
+	ctx.Redirect(link)
 }
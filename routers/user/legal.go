@@ -0,0 +1,66 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/legal"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/routers/utils"
+)
+
+const (
+	// tplLegalAccept template for the acceptance of the legal pages
+	tplLegalAccept base.TplName = "user/auth/legal_accept"
+)
+
+// LegalAccept render the legal pages the user has to accept
+func LegalAccept(ctx *context.Context) {
+	unaccepted, err := models.GetUnacceptedLegalPages(ctx.User.ID, legal.Pages())
+	if err != nil {
+		ctx.ServerError("GetUnacceptedLegalPages", err)
+		return
+	}
+	if len(unaccepted) == 0 {
+		ctx.Redirect(setting.AppSubURL + "/")
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("auth.legal_accept")
+	ctx.Data["UnacceptedPages"] = unaccepted
+	ctx.Data["Fingerprint"] = legal.Fingerprint()
+	ctx.HTML(200, tplLegalAccept)
+}
+
+// LegalAcceptPost records the acceptance of the legal pages by the user
+func LegalAcceptPost(ctx *context.Context) {
+	// the pages changed since the user read them
+	if ctx.Query("fingerprint") != legal.Fingerprint() {
+		ctx.Flash.Error(ctx.Tr("auth.legal_accept_changed"))
+		ctx.Redirect(setting.AppSubURL + "/user/legal")
+		return
+	}
+
+	unaccepted, err := models.GetUnacceptedLegalPages(ctx.User.ID, legal.Pages())
+	if err != nil {
+		ctx.ServerError("GetUnacceptedLegalPages", err)
+		return
+	}
+	if err := models.AcceptLegalPages(ctx.User.ID, unaccepted); err != nil {
+		ctx.ServerError("AcceptLegalPages", err)
+		return
+	}
+	log.Trace("User %s accepted the legal pages %s", ctx.User.Name, legal.Fingerprint())
+
+	if redirectTo := ctx.GetCookie("redirect_to"); len(redirectTo) > 0 && !utils.IsExternalURL(redirectTo) {
+		ctx.SetCookie("redirect_to", "", -1, setting.AppSubURL)
+		ctx.RedirectToFirst(redirectTo)
+		return
+	}
+	ctx.Redirect(setting.AppSubURL + "/")
+}
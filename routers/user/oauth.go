@@ -26,8 +26,13 @@ import (
 const (
 	tplGrantAccess base.TplName = "user/auth/grant"
 	tplGrantError  base.TplName = "user/auth/grant_error"
+	tplDeviceCode  base.TplName = "user/auth/device_code"
+	tplDeviceGrant base.TplName = "user/auth/device_grant"
 )
 
+// GrantTypeDeviceCode is the grant type of the device authorization grant specified in RFC 8628
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
 // TODO move error and responses to SDK or models
 
 // AuthorizeErrorCode represents an error code specified in RFC 6749
@@ -78,6 +83,14 @@ const (
 	AccessTokenErrorCodeUnsupportedGrantType = "unsupported_grant_type"
 	// AccessTokenErrorCodeInvalidScope represents an error code specified in RFC 6749
 	AccessTokenErrorCodeInvalidScope = "invalid_scope"
+	// AccessTokenErrorCodeAuthorizationPending represents an error code specified in RFC 8628
+	AccessTokenErrorCodeAuthorizationPending = "authorization_pending"
+	// AccessTokenErrorCodeSlowDown represents an error code specified in RFC 8628
+	AccessTokenErrorCodeSlowDown = "slow_down"
+	// AccessTokenErrorCodeAccessDenied represents an error code specified in RFC 8628
+	AccessTokenErrorCodeAccessDenied = "access_denied"
+	// AccessTokenErrorCodeExpiredToken represents an error code specified in RFC 8628
+	AccessTokenErrorCodeExpiredToken = "expired_token"
 )
 
 // AccessTokenError represents an error response specified in RFC 6749
@@ -109,6 +122,16 @@ type AccessTokenResponse struct {
 	RefreshToken string    `json:"refresh_token"`
 }
 
+// DeviceAuthorizationResponse represents a successful device authorization response specified in RFC 8628
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
 func newAccessTokenResponse(grant *models.OAuth2Grant) (*AccessTokenResponse, *AccessTokenError) {
 	if setting.OAuth2.InvalidateRefreshTokens {
 		if err := grant.IncreaseCounter(); err != nil {
@@ -373,10 +396,13 @@ func AccessTokenOAuth(ctx *context.Context, form auth.AccessTokenForm) {
 	case "authorization_code":
 		handleAuthorizationCode(ctx, form)
 		return
+	case GrantTypeDeviceCode:
+		handleDeviceCode(ctx, form)
+		return
 	default:
 		handleAccessTokenError(ctx, AccessTokenError{
 			ErrorCode:        AccessTokenErrorCodeUnsupportedGrantType,
-			ErrorDescription: "Only refresh_token or authorization_code grant type is supported",
+			ErrorDescription: "Only refresh_token, authorization_code or device_code grant type is supported",
 		})
 	}
 }
@@ -480,6 +506,219 @@ func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
 	ctx.JSON(200, resp)
 }
 
+// handleDeviceCode issues the access token of a device once the user approved its request, the device
+// polls until then. The clients of the device authorization grant don't need to authenticate.
+func handleDeviceCode(ctx *context.Context, form auth.AccessTokenForm) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+		})
+		return
+	}
+	if form.ClientSecret != "" && !app.ValidateClientSecret([]byte(form.ClientSecret)) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return
+	}
+	deviceCode, err := models.GetOAuth2DeviceCodeByDeviceCode(form.DeviceCode)
+	if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot proceed your request",
+		})
+		return
+	}
+	if deviceCode == nil || deviceCode.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "invalid device code",
+		})
+		return
+	}
+	if deviceCode.IsExpired() {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeExpiredToken,
+			ErrorDescription: "the device code has expired",
+		})
+		return
+	}
+
+	if deviceCode.IsPending() {
+		inInterval, err := deviceCode.Poll()
+		if err != nil {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot proceed your request",
+			})
+		} else if !inInterval {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeSlowDown,
+				ErrorDescription: "the device polls too fast",
+			})
+		} else {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeAuthorizationPending,
+				ErrorDescription: "the user hasn't approved the request yet",
+			})
+		}
+		return
+	}
+
+	// the device code can only be exchanged once
+	if err := deviceCode.Invalidate(); models.IsErrOAuth2DeviceCodeNotExist(err) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "invalid device code",
+		})
+		return
+	} else if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot proceed your request",
+		})
+		return
+	}
+	if deviceCode.Denied {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeAccessDenied,
+			ErrorDescription: "the user denied the request",
+		})
+		return
+	}
+	grant, err := app.GetGrantByUserID(deviceCode.UserID)
+	if err != nil || grant == nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "grant does not exist",
+		})
+		return
+	}
+	resp, tokenErr := newAccessTokenResponse(grant)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+// DeviceAuthorizationOAuth starts the device authorization grant of a client, the user approves
+// its request by entering the returned user code at the verification URI
+func DeviceAuthorizationOAuth(ctx *context.Context, form auth.DeviceAuthorizationForm) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if models.IsErrOauthClientIDInvalid(err) {
+			handleAccessTokenError(ctx, AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidClient,
+				ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+			})
+			return
+		}
+		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
+		return
+	}
+
+	code, err := app.CreateDeviceCode()
+	if err != nil {
+		ctx.ServerError("CreateDeviceCode", err)
+		return
+	}
+
+	verificationURI := setting.AppURL + "login/device"
+	ctx.JSON(200, &DeviceAuthorizationResponse{
+		DeviceCode:              code.DeviceCode,
+		UserCode:                code.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(code.UserCode),
+		ExpiresIn:               setting.OAuth2.DeviceCodeExpirationTime,
+		Interval:                setting.OAuth2.DeviceCodePollingInterval,
+	})
+}
+
+// getPendingDeviceCode returns the pending device authorization request with the user code, or renders
+// the page to enter a user code with an error if there is none
+func getPendingDeviceCode(ctx *context.Context, userCode string) *models.OAuth2DeviceCode {
+	code, err := models.GetOAuth2DeviceCodeByUserCode(userCode)
+	if err != nil {
+		ctx.ServerError("GetOAuth2DeviceCodeByUserCode", err)
+		return nil
+	}
+	if code == nil || code.IsExpired() || !code.IsPending() {
+		ctx.Data["Err_UserCode"] = true
+		ctx.Data["user_code"] = userCode
+		ctx.RenderWithErr(ctx.Tr("auth.device_code_invalid"), tplDeviceCode, nil)
+		return nil
+	}
+	if err := code.LoadApplication(); err != nil {
+		ctx.ServerError("LoadApplication", err)
+		return nil
+	}
+	if err := code.Application.LoadUser(); err != nil {
+		ctx.ServerError("LoadUser", err)
+		return nil
+	}
+	return code
+}
+
+// DeviceAuthorization shows the page to enter the user code of a device, and to approve its request
+func DeviceAuthorization(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("auth.device_title")
+
+	userCode := ctx.Query("user_code")
+	if len(userCode) == 0 {
+		ctx.HTML(200, tplDeviceCode)
+		return
+	}
+
+	code := getPendingDeviceCode(ctx, userCode)
+	if ctx.Written() {
+		return
+	}
+
+	app := code.Application
+	ctx.Data["Application"] = app
+	ctx.Data["UserCode"] = code.UserCode
+	ctx.Data["ApplicationUserLink"] = "<a href=\"" + html.EscapeString(setting.AppURL) + html.EscapeString(url.PathEscape(app.User.LowerName)) + "\">@" + html.EscapeString(app.User.Name) + "</a>"
+	ctx.HTML(200, tplDeviceGrant)
+}
+
+// DeviceGrantOAuth manages the post request submitted when a user approves or denies the request of a device
+func DeviceGrantOAuth(ctx *context.Context, form auth.DeviceGrantForm) {
+	ctx.Data["Title"] = ctx.Tr("auth.device_title")
+
+	code := getPendingDeviceCode(ctx, form.UserCode)
+	if ctx.Written() {
+		return
+	}
+
+	var err error
+	if form.Granted {
+		_, err = code.Approve(ctx.User.ID)
+	} else {
+		err = code.Deny(ctx.User.ID)
+	}
+	if models.IsErrOAuth2DeviceCodeNotPending(err) {
+		// the request was approved or denied in the meantime
+		ctx.Data["Err_UserCode"] = true
+		ctx.Data["user_code"] = form.UserCode
+		ctx.RenderWithErr(ctx.Tr("auth.device_code_invalid"), tplDeviceCode, nil)
+		return
+	} else if err != nil {
+		ctx.ServerError("DeviceGrantOAuth", err)
+		return
+	}
+
+	if form.Granted {
+		ctx.Flash.Success(ctx.Tr("auth.device_authorized", html.EscapeString(code.Application.Name)))
+	} else {
+		ctx.Flash.Info(ctx.Tr("auth.device_denied", html.EscapeString(code.Application.Name)))
+	}
+	ctx.Redirect(setting.AppSubURL + "/login/device")
+}
+
 func handleAccessTokenError(ctx *context.Context, acErr AccessTokenError) {
 	ctx.JSON(400, acErr)
 }
@@ -6,6 +6,8 @@
 package setting
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
@@ -39,9 +41,36 @@ func ApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
 		return
 	}
 
+	scopes := form.Scopes
+	if len(form.Template) > 0 {
+		var ok bool
+		if scopes, ok = models.AccessTokenTemplates[form.Template]; !ok {
+			ctx.Flash.Error(ctx.Tr("settings.token_scope_invalid"))
+			ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+			return
+		}
+	}
+	scope, err := models.NormalizeAccessTokenScope(scopes)
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("settings.token_scope_invalid"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+		return
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: scope,
+	}
+	t.RepoIDs, t.OwnerIDs, err = models.ResolveAccessTokenRepos(ctx.User, splitNames(form.Repositories), splitNames(form.Owners))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) || models.IsErrUserNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("settings.token_repos_invalid"))
+			ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+		} else {
+			ctx.ServerError("ResolveAccessTokenRepos", err)
+		}
+		return
 	}
 
 	exist, err := models.AccessTokenByNameExists(t)
@@ -66,6 +95,17 @@ func ApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
 	ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
 }
 
+// splitNames splits the comma separated names of an input
+func splitNames(input string) []string {
+	var names []string
+	for _, name := range strings.Split(input, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // DeleteApplication response for delete user access token
 func DeleteApplication(ctx *context.Context) {
 	if err := models.DeleteAccessTokenByID(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
@@ -86,6 +126,7 @@ func loadApplicationsData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Tokens"] = tokens
+	ctx.Data["TokenScopes"] = models.AccessTokenScopes
 	ctx.Data["EnableOAuth2"] = setting.OAuth2.Enable
 	if setting.OAuth2.Enable {
 		ctx.Data["Applications"], err = models.GetOAuth2ApplicationsByUserID(ctx.User.ID)
@@ -0,0 +1,96 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsSavedReplies base.TplName = "user/settings/saved_replies"
+)
+
+// SavedReplies render manage saved replies page
+func SavedReplies(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsSavedReplies"] = true
+
+	replies, err := models.GetSavedReplies(ctx.User.ID, models.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetSavedReplies", err)
+		return
+	}
+	ctx.Data["SavedReplies"] = replies
+
+	ctx.HTML(http.StatusOK, tplSettingsSavedReplies)
+}
+
+// SavedRepliesPost response for creating a saved reply
+func SavedRepliesPost(ctx *context.Context, form auth.SavedReplyForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/replies")
+		return
+	}
+
+	if err := models.CreateSavedReply(&models.SavedReply{
+		OwnerID: ctx.User.ID,
+		Title:   form.Title,
+		Content: form.Content,
+	}); err != nil {
+		ctx.ServerError("CreateSavedReply", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.saved_replies.create_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/replies")
+}
+
+// EditSavedReplyPost response for editing a saved reply
+func EditSavedReplyPost(ctx *context.Context, form auth.SavedReplyForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/replies")
+		return
+	}
+
+	reply, err := models.GetSavedReplyByID(ctx.User.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrSavedReplyNotExist(err) {
+			ctx.NotFound("GetSavedReplyByID", err)
+		} else {
+			ctx.ServerError("GetSavedReplyByID", err)
+		}
+		return
+	}
+	reply.Title = form.Title
+	reply.Content = form.Content
+	if err := models.UpdateSavedReply(reply); err != nil {
+		ctx.ServerError("UpdateSavedReply", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.saved_replies.update_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/replies")
+}
+
+// DeleteSavedReply response for deleting a saved reply
+func DeleteSavedReply(ctx *context.Context) {
+	if err := models.DeleteSavedReply(ctx.User.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteSavedReply: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.saved_replies.delete_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/replies",
+	})
+}
@@ -71,6 +71,12 @@ func loadSecurityData(ctx *context.Context) {
 		ctx.Data["RequireU2F"] = true
 	}
 
+	ctx.Data["TwofaStatus"], err = models.GetTwoFactorStatus(ctx.User)
+	if err != nil {
+		ctx.ServerError("GetTwoFactorStatus", err)
+		return
+	}
+
 	tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{UserID: ctx.User.ID})
 	if err != nil {
 		ctx.ServerError("ListAccessTokens", err)
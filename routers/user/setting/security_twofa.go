@@ -77,6 +77,7 @@ func DisableTwoFactor(ctx *context.Context) {
 		return
 	}
 
+	ctx.ResetTwoFactorCheck()
 	ctx.Flash.Success(ctx.Tr("settings.twofa_disabled"))
 	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
 }
@@ -235,6 +236,7 @@ func EnrollTwoFactorPost(ctx *context.Context, form auth.TwoFactorAuthForm) {
 		return
 	}
 
+	ctx.ResetTwoFactorCheck()
 	ctx.Flash.Success(ctx.Tr("settings.twofa_enrolled", token))
 	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
 }
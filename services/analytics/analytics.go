@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package analytics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+type usageKey struct {
+	day    timeutil.TimeStamp
+	userID int64
+}
+
+var (
+	apiUsageMutex sync.Mutex
+	// apiUsage are the API requests counted since they were last saved
+	apiUsage = make(map[usageKey]int64)
+)
+
+// CountAPIRequest counts an API request of a signed in user, the requests are saved with the instance stats
+func CountAPIRequest(userID int64) {
+	key := usageKey{models.StatsDay(time.Now()), userID}
+	apiUsageMutex.Lock()
+	apiUsage[key]++
+	apiUsageMutex.Unlock()
+}
+
+// saveAPIUsage saves the counted API requests
+func saveAPIUsage() error {
+	apiUsageMutex.Lock()
+	usage := apiUsage
+	apiUsage = make(map[usageKey]int64)
+	apiUsageMutex.Unlock()
+
+	days := make(map[timeutil.TimeStamp]map[int64]int64)
+	for key, count := range usage {
+		if days[key.day] == nil {
+			days[key.day] = make(map[int64]int64)
+		}
+		days[key.day][key.userID] = count
+	}
+	var lastErr error
+	for day, requests := range days {
+		if err := models.AddAPIUsage(day, requests); err != nil {
+			lastErr = err
+			// count the requests again so that they are saved next time
+			apiUsageMutex.Lock()
+			for userID, count := range requests {
+				apiUsage[usageKey{day, userID}] += count
+			}
+			apiUsageMutex.Unlock()
+		}
+	}
+	return lastErr
+}
+
+// AggregateInstanceStats saves the counted API requests and updates the snapshot of the current day
+func AggregateInstanceStats(ctx context.Context) error {
+	log.Trace("Doing: AggregateInstanceStats")
+
+	if err := saveAPIUsage(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return models.ErrCancelledf("before updating the instance stats")
+	default:
+	}
+	if _, err := models.AggregateInstanceStats(time.Now()); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: AggregateInstanceStats")
+	return nil
+}
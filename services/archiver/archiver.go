@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
@@ -32,10 +33,12 @@ import (
 type ArchiveRequest struct {
 	uri             string
 	repo            *git.Repository
+	lfsRepo         *models.Repository
 	refName         string
 	ext             string
 	archivePath     string
 	archiveType     git.ArchiveType
+	withLFS         bool
 	archiveComplete bool
 	commit          *git.Commit
 	cchan           chan struct{}
@@ -99,10 +102,10 @@ func (aReq *ArchiveRequest) TimedWaitForCompletion(ctx *context.Context, dur tim
 }
 
 // The caller must hold the archiveMutex across calls to getArchiveRequest.
-func getArchiveRequest(repo *git.Repository, commit *git.Commit, archiveType git.ArchiveType) *ArchiveRequest {
+func getArchiveRequest(repo *git.Repository, commit *git.Commit, archiveType git.ArchiveType, withLFS bool) *ArchiveRequest {
 	for _, r := range archiveInProgress {
 		// Need to be referring to the same repository.
-		if r.repo.Path == repo.Path && r.commit.ID == commit.ID && r.archiveType == archiveType {
+		if r.repo.Path == repo.Path && r.commit.ID == commit.ID && r.archiveType == archiveType && r.withLFS == withLFS {
 			return r
 		}
 	}
@@ -111,15 +114,19 @@ func getArchiveRequest(repo *git.Repository, commit *git.Commit, archiveType git
 
 // DeriveRequestFrom creates an archival request, based on the URI.  The
 // resulting ArchiveRequest is suitable for being passed to ArchiveRepository()
-// if it's determined that the request still needs to be satisfied.
+// if it's determined that the request still needs to be satisfied.  The LFS
+// pointers of the archive are replaced by the LFS objects if the lfs query
+// parameter is set.
 func DeriveRequestFrom(ctx *context.Context, uri string) *ArchiveRequest {
 	if ctx.Repo == nil || ctx.Repo.GitRepo == nil {
 		log.Trace("Repo not initialized")
 		return nil
 	}
 	r := &ArchiveRequest{
-		uri:  uri,
-		repo: ctx.Repo.GitRepo,
+		uri:     uri,
+		repo:    ctx.Repo.GitRepo,
+		lfsRepo: ctx.Repo.Repository,
+		withLFS: setting.LFS.StartServer && ctx.QueryBool("lfs"),
 	}
 
 	switch {
@@ -175,11 +182,15 @@ func DeriveRequestFrom(ctx *context.Context, uri string) *ArchiveRequest {
 
 	archiveMutex.Lock()
 	defer archiveMutex.Unlock()
-	if rExisting := getArchiveRequest(r.repo, r.commit, r.archiveType); rExisting != nil {
+	if rExisting := getArchiveRequest(r.repo, r.commit, r.archiveType, r.withLFS); rExisting != nil {
 		return rExisting
 	}
 
-	r.archivePath = path.Join(r.archivePath, base.ShortSha(r.commit.ID.String())+r.ext)
+	archiveName := base.ShortSha(r.commit.ID.String())
+	if r.withLFS {
+		archiveName += "-lfs"
+	}
+	r.archivePath = path.Join(r.archivePath, archiveName+r.ext)
 	r.archiveComplete, err = util.IsFile(r.archivePath)
 	if err != nil {
 		ctx.ServerError("util.IsFile", err)
@@ -233,12 +244,34 @@ func doArchive(r *ArchiveRequest) {
 		return
 	}
 
+	archive := tmpArchive
+	if r.withLFS {
+		lfsArchive, err := ioutil.TempFile("", "archive-lfs")
+		if err != nil {
+			log.Error("Unable to create a temporary archive file! Error: %v", err)
+			return
+		}
+		defer func() {
+			lfsArchive.Close()
+			os.Remove(lfsArchive.Name())
+		}()
+		if err = replaceLFSPointers(r.lfsRepo, r.archiveType, tmpArchive, lfsArchive); err != nil {
+			log.Error("Download -> replaceLFSPointers %s: %v", tmpArchive.Name(), err)
+			return
+		}
+		archive = lfsArchive
+	}
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		log.Error("Unable to seek archive %s: %v", archive.Name(), err)
+		return
+	}
+
 	// Now we copy it into place
 	if destArchive, err = os.Create(r.archivePath); err != nil {
 		log.Error("Unable to open archive " + r.archivePath)
 		return
 	}
-	_, err = io.Copy(destArchive, tmpArchive)
+	_, err = io.Copy(destArchive, archive)
 	destArchive.Close()
 	if err != nil {
 		log.Error("Unable to write archive " + r.archivePath)
@@ -261,7 +294,7 @@ func ArchiveRepository(request *ArchiveRequest) *ArchiveRequest {
 	// and it is not marked complete.
 	archiveMutex.Lock()
 	defer archiveMutex.Unlock()
-	if rExisting := getArchiveRequest(request.repo, request.commit, request.archiveType); rExisting != nil {
+	if rExisting := getArchiveRequest(request.repo, request.commit, request.archiveType, request.withLFS); rExisting != nil {
 		return rExisting
 	}
 	if request.archiveComplete {
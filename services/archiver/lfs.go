@@ -0,0 +1,175 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package archiver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+)
+
+// maxLFSPointerSize is the size of the largest file which is considered as a LFS pointer
+const maxLFSPointerSize = 1024
+
+// lfsObject returns the LFS object of the repository a file is a pointer to, it's nil if the
+// file isn't a pointer or the object isn't an object of the repository
+func lfsObject(repo *models.Repository, content []byte) *models.LFSMetaObject {
+	pointer := lfs.IsPointerFile(&content)
+	if pointer == nil {
+		return nil
+	}
+	meta, err := repo.GetLFSMetaObjectByOid(pointer.Oid)
+	if err != nil {
+		return nil
+	}
+	return meta
+}
+
+// replaceLFSPointers copies an archive created by git to dst, the LFS pointers of the objects of the
+// repository are replaced by the objects streamed from the LFS storage
+func replaceLFSPointers(repo *models.Repository, archiveType git.ArchiveType, src *os.File, dst io.Writer) error {
+	switch archiveType {
+	case git.ZIP:
+		return replaceLFSPointersOfZip(repo, src, dst)
+	case git.TARGZ:
+		return replaceLFSPointersOfTarGz(repo, src, dst)
+	}
+	return fmt.Errorf("unknown archive type %v", archiveType)
+}
+
+// copyLFSObject writes the LFS object of a meta object to w
+func copyLFSObject(w io.Writer, meta *models.LFSMetaObject) error {
+	rc, err := lfs.ReadMetaObject(meta)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+func replaceLFSPointersOfZip(repo *models.Repository, src *os.File, dst io.Writer) error {
+	stat, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(src, stat.Size())
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(dst)
+	if err := zw.SetComment(zr.Comment); err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if err := copyZipEntry(repo, zw, f); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// copyZipEntry copies an entry of a zip archive, it's replaced by the LFS object it's a pointer to
+func copyZipEntry(repo *models.Repository, zw *zip.Writer, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	header := f.FileHeader
+	if !f.Mode().IsRegular() || f.UncompressedSize64 > maxLFSPointerSize {
+		w, err := zw.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, rc)
+		return err
+	}
+
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	meta := lfsObject(repo, content)
+	if meta != nil {
+		header.Method = zip.Deflate
+	}
+	w, err := zw.CreateHeader(&header)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		_, err = w.Write(content)
+		return err
+	}
+	return copyLFSObject(w, meta)
+}
+
+func replaceLFSPointersOfTarGz(repo *models.Repository, src *os.File, dst io.Writer) error {
+	gzr, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+	tr := tar.NewReader(gzr)
+
+	gzw := gzip.NewWriter(dst)
+	tw := tar.NewWriter(gzw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg || header.Size > maxLFSPointerSize {
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if meta := lfsObject(repo, content); meta != nil {
+			header.Size = meta.Size
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if err := copyLFSObject(tw, meta); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/highlight"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	// ErrUnknownLink represents a link which isn't the permalink of a file of this instance, or the
+	// permalink of a file which can't be read by the doer
+	ErrUnknownLink = errors.New("unknown link")
+	// ErrNotEmbeddable represents a file which is too large to be embedded or isn't a text file
+	ErrNotEmbeddable = errors.New("file can't be embedded")
+)
+
+// snippetTemplate is the self-contained HTML of a snippet, its styles are inlined so it can be
+// embedded in any page
+var snippetTemplate = template.Must(template.New("snippet").Parse(`<div style="border:1px solid #d1d5da;border-radius:4px;overflow:hidden;background-color:#fff;color:#24292e;font-size:12px;line-height:1.5">` +
+	`<div style="padding:6px 10px;border-bottom:1px solid #d1d5da;background-color:#f6f8fa;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Helvetica,Arial,sans-serif">` +
+	`<a href="{{.Link}}" style="color:#0366d6;font-weight:600;text-decoration:none">{{.Repo.FullName}}/{{.TreePath}}</a>` +
+	` <span style="color:#586069">{{.Lines}} at {{.ShortSha}}</span></div>` +
+	`<div style="overflow:auto;font-family:SFMono-Regular,Consolas,'Liberation Mono',Menlo,monospace">{{.Code}}</div></div>`))
+
+// Snippet represents lines of a file at a commit
+type Snippet struct {
+	Repo      *models.Repository
+	CommitID  string
+	TreePath  string
+	LineStart int
+	LineEnd   int
+	// HTML is the self-contained HTML version of the lines
+	HTML string
+}
+
+// Link returns the permalink of the lines of a snippet
+func (s *Snippet) Link() string {
+	return s.Repo.HTMLURL() + "/src/commit/" + s.CommitID + "/" + util.PathEscapeSegments(s.TreePath) + fmt.Sprintf("#L%d-L%d", s.LineStart, s.LineEnd)
+}
+
+// Lines returns the number of lines of a snippet
+func (s *Snippet) Lines() int {
+	return s.LineEnd - s.LineStart + 1
+}
+
+// ParseLines parses a line range like 10, 10-20, L10 or L10-L20, the range of an empty string is the
+// whole file and its end is 0
+func ParseLines(lines string) (lineStart, lineEnd int, err error) {
+	if len(lines) == 0 {
+		return 1, 0, nil
+	}
+	parts := strings.SplitN(lines, "-", 2)
+	if lineStart, err = strconv.Atoi(strings.TrimPrefix(parts[0], "L")); err != nil || lineStart <= 0 {
+		return 0, 0, fmt.Errorf("invalid line range: %s", lines)
+	}
+	lineEnd = lineStart
+	if len(parts) == 2 {
+		if lineEnd, err = strconv.Atoi(strings.TrimPrefix(parts[1], "L")); err != nil || lineEnd < lineStart {
+			return 0, 0, fmt.Errorf("invalid line range: %s", lines)
+		}
+	}
+	return lineStart, lineEnd, nil
+}
+
+// Resolve returns the snippet of a permalink of a file at a commit if the doer can read it, i.e. a link
+// like {owner}/{repo}/src/commit/{sha}/{path}#L10-L20 which may be absolute or relative to the root of
+// this instance. The lines may also be given by a lines query parameter as in the embed links.
+func Resolve(doer *models.User, link string) (*Snippet, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, ErrUnknownLink
+	}
+	if u.IsAbs() {
+		appURL, err := url.Parse(setting.AppURL)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(u.Host, appURL.Host) {
+			return nil, ErrUnknownLink
+		}
+	}
+	p := strings.TrimPrefix(u.Path, setting.AppSubURL)
+	segments := strings.SplitN(strings.Trim(p, "/"), "/", 6)
+	if len(segments) != 6 || (segments[2] != "src" && segments[2] != "embed") || segments[3] != "commit" ||
+		!git.SHAPattern.MatchString(segments[4]) {
+		return nil, ErrUnknownLink
+	}
+	lines := u.Fragment
+	if len(lines) == 0 {
+		lines = u.Query().Get("lines")
+	}
+	lineStart, lineEnd, err := ParseLines(lines)
+	if err != nil {
+		return nil, ErrUnknownLink
+	}
+
+	repo, err := models.GetRepositoryByOwnerAndName(segments[0], segments[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		return nil, ErrUnknownLink
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetCommit(segments[4])
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+
+	snippet, err := Render(repo, commit, segments[5], lineStart, lineEnd)
+	if git.IsErrNotExist(err) {
+		return nil, ErrUnknownLink
+	}
+	return snippet, err
+}
+
+// Render renders the lines lineStart to lineEnd of a file at a commit, a lineEnd of 0 or beyond the end
+// of the file renders the file until its end
+func Render(repo *models.Repository, commit *git.Commit, treePath string, lineStart, lineEnd int) (*Snippet, error) {
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		return nil, ErrNotEmbeddable
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	content, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsTextFile(content) {
+		return nil, ErrNotEmbeddable
+	}
+	content = charset.ToUTF8WithFallback(content)
+
+	numLines := bytes.Count(content, []byte{'\n'})
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		numLines++
+	}
+	if lineStart > numLines {
+		return nil, git.ErrNotExist{ID: commit.ID.String(), RelPath: treePath}
+	}
+	if lineEnd <= 0 || lineEnd > numLines {
+		lineEnd = numLines
+	}
+
+	code, err := highlight.Embed(treePath, content, lineStart, lineEnd)
+	if err != nil {
+		return nil, err
+	}
+	snippet := &Snippet{
+		Repo:      repo,
+		CommitID:  commit.ID.String(),
+		TreePath:  treePath,
+		LineStart: lineStart,
+		LineEnd:   lineEnd,
+	}
+
+	var buf bytes.Buffer
+	if err := snippetTemplate.Execute(&buf, map[string]interface{}{
+		"Link":     snippet.Link(),
+		"Repo":     repo,
+		"TreePath": treePath,
+		"Lines":    snippetLinesTitle(lineStart, lineEnd),
+		"ShortSha": base.ShortSha(snippet.CommitID),
+		"Code":     template.HTML(code),
+	}); err != nil {
+		return nil, err
+	}
+	snippet.HTML = buf.String()
+	return snippet, nil
+}
+
+func snippetLinesTitle(lineStart, lineEnd int) string {
+	if lineStart == lineEnd {
+		return fmt.Sprintf("line %d", lineStart)
+	}
+	return fmt.Sprintf("lines %d to %d", lineStart, lineEnd)
+}
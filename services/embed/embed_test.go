@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLines(t *testing.T) {
+	for lines, expected := range map[string][2]int{
+		"":        {1, 0},
+		"10":      {10, 10},
+		"L10":     {10, 10},
+		"10-20":   {10, 20},
+		"L10-L20": {10, 20},
+	} {
+		lineStart, lineEnd, err := ParseLines(lines)
+		assert.NoError(t, err, lines)
+		assert.Equal(t, expected, [2]int{lineStart, lineEnd}, lines)
+	}
+	for _, lines := range []string{"0", "L", "20-10", "10-", "a-b"} {
+		_, _, err := ParseLines(lines)
+		assert.Error(t, err, lines)
+	}
+}
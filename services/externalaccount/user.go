@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/structs"
 
 	"github.com/markbates/goth"
@@ -44,6 +45,10 @@ func LinkAccountToUser(user *models.User, gothUser goth.User) error {
 		return err
 	}
 
+	if err := models.SyncOAuth2GroupTeams(user, gothUser); err != nil {
+		log.Error("SyncOAuth2GroupTeams [user: %s]: %v", user.Name, err)
+	}
+
 	externalID := externalLoginUser.ExternalID
 
 	var tp structs.GitServiceType
@@ -19,6 +19,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/charset"
@@ -76,6 +77,8 @@ type DiffLine struct {
 	Content     string
 	Comments    []*models.Comment
 	SectionInfo *DiffLineSectionInfo
+	// Moved is set on the added and deleted lines of a block which was moved, not changed
+	Moved bool
 }
 
 // DiffLineSectionInfo represents diff line section meta data
@@ -174,6 +177,8 @@ type DiffSection struct {
 	FileName string
 	Name     string
 	Lines    []*DiffLine
+	// ignoreWhitespace is set when the diff ignores whitespace changes, so they aren't highlighted either
+	ignoreWhitespace bool
 }
 
 var (
@@ -359,9 +364,27 @@ func (diffSection *DiffSection) GetComputedInlineDiffFor(diffLine *DiffLine) tem
 
 	diffRecord = diffMatchPatch.DiffCleanupEfficiency(diffRecord)
 
+	if diffSection.ignoreWhitespace {
+		diffRecord = ignoreWhitespaceChanges(diffRecord, diffLine.Type)
+	}
+
 	return diffToHTML(diffSection.FileName, diffRecord, diffLine.Type)
 }
 
+// ignoreWhitespaceChanges turns the inline changes of whitespace only of a line into unchanged text
+func ignoreWhitespaceChanges(diffs []diffmatchpatch.Diff, lineType DiffLineType) []diffmatchpatch.Diff {
+	for i := range diffs {
+		if diffs[i].Type == diffmatchpatch.DiffEqual || len(strings.TrimSpace(diffs[i].Text)) > 0 {
+			continue
+		}
+		if diffs[i].Type == diffmatchpatch.DiffInsert && lineType == DiffLineAdd ||
+			diffs[i].Type == diffmatchpatch.DiffDelete && lineType == DiffLineDel {
+			diffs[i].Type = diffmatchpatch.DiffEqual
+		}
+	}
+	return diffs
+}
+
 // DiffFile represents a file diff.
 type DiffFile struct {
 	Name               string
@@ -437,6 +460,8 @@ type Diff struct {
 	NumFiles, TotalAddition, TotalDeletion int
 	Files                                  []*DiffFile
 	IsIncomplete                           bool
+	// IsLazy marks a diff whose files have no sections, they are loaded one by one on demand
+	IsLazy bool
 }
 
 // LoadComments loads comments into each line
@@ -868,6 +893,133 @@ func readFileName(rd *strings.Reader) string {
 	return name[2:]
 }
 
+// minMovedBlockChars is the minimum number of alphanumeric characters of a moved block as in git diff
+// --color-moved, so short lines like closing braces aren't marked as moved on their own
+const minMovedBlockChars = 20
+
+// markMovedLines marks the blocks of added lines which are equal to blocks of deleted lines of any file
+// of a diff, and these deleted lines, as moved. The longest block is matched first so a moved block
+// isn't split up, and blocks don't start at blank lines since they are found everywhere.
+func markMovedLines(diff *Diff) {
+	// the runs are the consecutive added or deleted lines of the sections
+	var delRuns, addRuns [][]*DiffLine
+	for _, file := range diff.Files {
+		for _, section := range file.Sections {
+			var delRun, addRun []*DiffLine
+			for _, line := range section.Lines {
+				switch line.Type {
+				case DiffLineDel:
+					delRun = append(delRun, line)
+				case DiffLineAdd:
+					addRun = append(addRun, line)
+				default:
+					if len(delRun) > 0 {
+						delRuns = append(delRuns, delRun)
+					}
+					if len(addRun) > 0 {
+						addRuns = append(addRuns, addRun)
+					}
+					delRun, addRun = nil, nil
+				}
+			}
+			if len(delRun) > 0 {
+				delRuns = append(delRuns, delRun)
+			}
+			if len(addRun) > 0 {
+				addRuns = append(addRuns, addRun)
+			}
+		}
+	}
+	if len(delRuns) == 0 || len(addRuns) == 0 {
+		return
+	}
+
+	type position struct{ run, idx int }
+	deleted := make(map[string][]position)
+	for i, run := range delRuns {
+		for j, line := range run {
+			if content := line.Content[1:]; len(strings.TrimSpace(content)) > 0 {
+				deleted[content] = append(deleted[content], position{i, j})
+			}
+		}
+	}
+
+	for _, run := range addRuns {
+		for i := 0; i < len(run); {
+			var block []*DiffLine
+			for _, pos := range deleted[run[i].Content[1:]] {
+				delRun := delRuns[pos.run]
+				n := 0
+				for i+n < len(run) && pos.idx+n < len(delRun) && !delRun[pos.idx+n].Moved &&
+					delRun[pos.idx+n].Content[1:] == run[i+n].Content[1:] {
+					n++
+				}
+				if n > len(block) {
+					block = delRun[pos.idx : pos.idx+n]
+				}
+			}
+			if len(block) == 0 || countAlphanumeric(block) < minMovedBlockChars {
+				i++
+				continue
+			}
+			for j, line := range block {
+				line.Moved = true
+				run[i+j].Moved = true
+			}
+			i += len(block)
+		}
+	}
+}
+
+func countAlphanumeric(lines []*DiffLine) int {
+	count := 0
+	for _, line := range lines {
+		for _, r := range line.Content[1:] {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// WhitespaceFlags maps the whitespace behaviors of the diffs to their git flags
+var WhitespaceFlags = map[string]string{
+	"ignore-all":    "-w",
+	"ignore-change": "-b",
+	"ignore-eol":    "--ignore-space-at-eol",
+}
+
+// MaxContextLines is the maximum number of lines of context around the changes of a diff
+const MaxContextLines = 100
+
+// IsValidWhitespaceBehavior returns whether a whitespace behavior is known, the empty behavior shows
+// all whitespace changes
+func IsValidWhitespaceBehavior(whitespaceBehavior string) bool {
+	_, ok := WhitespaceFlags[whitespaceBehavior]
+	return ok || len(whitespaceBehavior) == 0
+}
+
+// DiffOptions represents the options of the git diff of two commits
+type DiffOptions struct {
+	// WhitespaceBehavior is one of the keys of WhitespaceFlags or an empty string
+	WhitespaceBehavior string
+	// ContextLines is the number of lines of context around the changes, git's default is used if it's 0
+	ContextLines int
+}
+
+// GitArgs returns the arguments of git diff for the options
+func (opts DiffOptions) GitArgs() []string {
+	var args []string
+	if flag, ok := WhitespaceFlags[opts.WhitespaceBehavior]; ok {
+		args = append(args, flag)
+	}
+	if opts.ContextLines > 0 {
+		args = append(args, fmt.Sprintf("-U%d", opts.ContextLines))
+	}
+	return args
+}
+
 // GetDiffRange builds a Diff between two commits of a repository.
 // passing the empty string as beforeCommitID returns a diff from the
 // parent commit.
@@ -877,8 +1029,25 @@ func GetDiffRange(repoPath, beforeCommitID, afterCommitID string, maxLines, maxL
 
 // GetDiffRangeWithWhitespaceBehavior builds a Diff between two commits of a repository.
 // Passing the empty string as beforeCommitID returns a diff from the parent commit.
-// The whitespaceBehavior is either an empty string or a git flag
-func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters, maxFiles int, whitespaceBehavior string) (*Diff, error) {
+// The whitespaceBehavior is either an empty string or a git flag.
+// The diff is limited to the given files if there are any.
+func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters, maxFiles int, whitespaceBehavior string, files ...string) (*Diff, error) {
+	var diffArgs []string
+	if len(whitespaceBehavior) != 0 {
+		diffArgs = append(diffArgs, whitespaceBehavior)
+	}
+	return getDiffRange(repoPath, beforeCommitID, afterCommitID, maxLines, maxLineCharacters, maxFiles, diffArgs, len(whitespaceBehavior) != 0, files...)
+}
+
+// GetDiffRangeWithOptions builds a Diff between two commits of a repository with the whitespace behavior
+// and the context lines of the options. Passing the empty string as beforeCommitID returns a diff from
+// the parent commit. The diff is limited to the given files if there are any.
+func GetDiffRangeWithOptions(repoPath, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters, maxFiles int, opts DiffOptions, files ...string) (*Diff, error) {
+	_, ignoreWhitespace := WhitespaceFlags[opts.WhitespaceBehavior]
+	return getDiffRange(repoPath, beforeCommitID, afterCommitID, maxLines, maxLineCharacters, maxFiles, opts.GitArgs(), ignoreWhitespace, files...)
+}
+
+func getDiffRange(repoPath, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters, maxFiles int, extraArgs []string, ignoreWhitespace bool, files ...string) (*Diff, error) {
 	gitRepo, err := git.OpenRepository(repoPath)
 	if err != nil {
 		return nil, err
@@ -895,13 +1064,13 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	defer cancel()
 	var cmd *exec.Cmd
 	if (len(beforeCommitID) == 0 || beforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
-		diffArgs := []string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}
-		if len(whitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, whitespaceBehavior)
-		}
+		diffArgs := append([]string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}, extraArgs...)
 		// append empty tree ref
 		diffArgs = append(diffArgs, "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
 		diffArgs = append(diffArgs, afterCommitID)
+		if len(files) > 0 {
+			diffArgs = append(append(diffArgs, "--"), files...)
+		}
 		cmd = exec.CommandContext(ctx, git.GitExecutable, diffArgs...)
 	} else {
 		actualBeforeCommitID := beforeCommitID
@@ -909,12 +1078,12 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 			parentCommit, _ := commit.Parent(0)
 			actualBeforeCommitID = parentCommit.ID.String()
 		}
-		diffArgs := []string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}
-		if len(whitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, whitespaceBehavior)
-		}
+		diffArgs := append([]string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}, extraArgs...)
 		diffArgs = append(diffArgs, actualBeforeCommitID)
 		diffArgs = append(diffArgs, afterCommitID)
+		if len(files) > 0 {
+			diffArgs = append(append(diffArgs, "--"), files...)
+		}
 		cmd = exec.CommandContext(ctx, git.GitExecutable, diffArgs...)
 		beforeCommitID = actualBeforeCommitID
 	}
@@ -937,11 +1106,15 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	if err != nil {
 		return nil, fmt.Errorf("ParsePatch: %v", err)
 	}
+	markMovedLines(diff)
 	for _, diffFile := range diff.Files {
 		tailSection := diffFile.GetTailSection(gitRepo, beforeCommitID, afterCommitID)
 		if tailSection != nil {
 			diffFile.Sections = append(diffFile.Sections, tailSection)
 		}
+		for _, section := range diffFile.Sections {
+			section.ignoreWhitespace = ignoreWhitespace
+		}
 	}
 
 	if err = cmd.Wait(); err != nil {
@@ -952,11 +1125,17 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	if len(beforeCommitID) == 0 || beforeCommitID == git.EmptySHA {
 		shortstatArgs = []string{git.EmptyTreeSHA, afterCommitID}
 	}
+	if len(files) > 0 {
+		shortstatArgs = append(append(shortstatArgs, "--"), files...)
+	}
 	diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, shortstatArgs...)
 	if err != nil && strings.Contains(err.Error(), "no merge base") {
 		// git >= 2.28 now returns an error if base and head have become unrelated.
 		// previously it would return the results of git diff --shortstat base head so let's try that...
 		shortstatArgs = []string{beforeCommitID, afterCommitID}
+		if len(files) > 0 {
+			shortstatArgs = append(append(shortstatArgs, "--"), files...)
+		}
 		diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, shortstatArgs...)
 	}
 	if err != nil {
@@ -966,6 +1145,62 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	return diff, nil
 }
 
+// GetDiffSummary builds a lazy Diff between two commits of a repository, its files have no sections
+// so it can be built for any number of files. The sections of each file are loaded on demand by
+// GetDiffRangeWithOptions.
+func GetDiffSummary(repoPath, beforeCommitID, afterCommitID string) (*Diff, error) {
+	gitRepo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	stats, err := gitRepo.GetDiffFileStats(beforeCommitID, afterCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{
+		NumFiles: len(stats),
+		Files:    make([]*DiffFile, 0, len(stats)),
+		IsLazy:   true,
+	}
+	for i, stat := range stats {
+		file := &DiffFile{
+			Name:     stat.Name,
+			OldName:  stat.Name,
+			Index:    i + 1,
+			Addition: stat.Additions,
+			Deletion: stat.Deletions,
+			Type:     DiffFileChange,
+			IsBin:    stat.IsBinary,
+		}
+		switch stat.Status {
+		case 'A':
+			file.Type = DiffFileAdd
+			file.IsCreated = true
+		case 'D':
+			file.Type = DiffFileDel
+			file.IsDeleted = true
+		case 'C':
+			file.Type = DiffFileCopy
+			file.OldName = stat.OldName
+			file.IsRenamed = true
+		case 'R':
+			// like in the patches only the renames without changes have the rename type
+			if stat.Additions == 0 && stat.Deletions == 0 && !stat.IsBinary {
+				file.Type = DiffFileRename
+			}
+			file.OldName = stat.OldName
+			file.IsRenamed = true
+		}
+		diff.TotalAddition += stat.Additions
+		diff.TotalDeletion += stat.Deletions
+		diff.Files = append(diff.Files, file)
+	}
+	return diff, nil
+}
+
 // GetDiffCommit builds a Diff representing the given commitID.
 func GetDiffCommit(repoPath, commitID string, maxLines, maxLineCharacters, maxFiles int) (*Diff, error) {
 	return GetDiffRange(repoPath, "", commitID, maxLines, maxLineCharacters, maxFiles)
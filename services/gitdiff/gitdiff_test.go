@@ -462,3 +462,145 @@ func TestGetDiffRangeWithWhitespaceBehavior(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDiffSummary(t *testing.T) {
+	before, after := "559c156f8e0178b71cb44355428f24001b08fc68", "bd7063cc7c04689c4d082183d32a604ed27a24f9"
+	diff, err := GetDiffRangeWithWhitespaceBehavior("./testdata/academic-module", before, after,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLines, 1000, "")
+	assert.NoError(t, err)
+
+	summary, err := GetDiffSummary("./testdata/academic-module", before, after)
+	assert.NoError(t, err)
+	assert.True(t, summary.IsLazy)
+	assert.Equal(t, diff.NumFiles, summary.NumFiles)
+	assert.Equal(t, diff.TotalAddition, summary.TotalAddition)
+	assert.Equal(t, diff.TotalDeletion, summary.TotalDeletion)
+	if assert.Len(t, summary.Files, len(diff.Files)) {
+		for i, file := range summary.Files {
+			assert.Equal(t, diff.Files[i].Name, file.Name)
+			assert.Equal(t, diff.Files[i].Type, file.Type)
+			assert.Equal(t, diff.Files[i].Addition, file.Addition)
+			assert.Equal(t, diff.Files[i].Deletion, file.Deletion)
+			assert.Empty(t, file.Sections)
+		}
+	}
+
+	// the sections of a file are loaded separately
+	name := summary.Files[len(summary.Files)-1].Name
+	fileDiff, err := GetDiffRangeWithWhitespaceBehavior("./testdata/academic-module", before, after,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffFiles, "", name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fileDiff.NumFiles)
+	if assert.Len(t, fileDiff.Files, 1) {
+		assert.Equal(t, name, fileDiff.Files[0].Name)
+		assert.NotEmpty(t, fileDiff.Files[0].Sections)
+	}
+}
+
+func TestGetDiffRangeWithOptions(t *testing.T) {
+	before, after := "559c156f8e0178b71cb44355428f24001b08fc68", "bd7063cc7c04689c4d082183d32a604ed27a24f9"
+	countPlainLines := func(diff *Diff) int {
+		count := 0
+		for _, file := range diff.Files {
+			for _, section := range file.Sections {
+				for _, line := range section.Lines {
+					if line.Type == DiffLinePlain {
+						count++
+					}
+				}
+			}
+		}
+		return count
+	}
+
+	diff, err := GetDiffRangeWithOptions("./testdata/academic-module", before, after,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLines, 1000, DiffOptions{})
+	assert.NoError(t, err)
+	wideDiff, err := GetDiffRangeWithOptions("./testdata/academic-module", before, after,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLines, 1000, DiffOptions{ContextLines: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, diff.NumFiles, wideDiff.NumFiles)
+	assert.Greater(t, countPlainLines(wideDiff), countPlainLines(diff))
+
+	ignoreDiff, err := GetDiffRangeWithOptions("./testdata/academic-module", before, after,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLines, 1000, DiffOptions{WhitespaceBehavior: "ignore-all"})
+	assert.NoError(t, err)
+	for _, file := range ignoreDiff.Files {
+		for _, section := range file.Sections {
+			assert.True(t, section.ignoreWhitespace)
+		}
+	}
+}
+
+func TestDiffOptions_GitArgs(t *testing.T) {
+	assert.Empty(t, DiffOptions{}.GitArgs())
+	assert.Equal(t, []string{"-w"}, DiffOptions{WhitespaceBehavior: "ignore-all"}.GitArgs())
+	assert.Equal(t, []string{"--ignore-space-at-eol", "-U10"}, DiffOptions{WhitespaceBehavior: "ignore-eol", ContextLines: 10}.GitArgs())
+	assert.Equal(t, []string{"-U5"}, DiffOptions{WhitespaceBehavior: "unknown", ContextLines: 5}.GitArgs())
+
+	assert.True(t, IsValidWhitespaceBehavior(""))
+	assert.True(t, IsValidWhitespaceBehavior("ignore-change"))
+	assert.False(t, IsValidWhitespaceBehavior("-w"))
+}
+
+func TestDiffSection_GetComputedInlineDiffForIgnoreWhitespace(t *testing.T) {
+	setting.Git.DisableDiffHighlight = false
+	section := &DiffSection{
+		FileName: "test.txt",
+		Lines: []*DiffLine{
+			{LeftIdx: 1, RightIdx: 0, Type: DiffLineDel, Content: "-foo bar"},
+			{LeftIdx: 0, RightIdx: 1, Type: DiffLineAdd, Content: "+foo  bar"},
+		},
+	}
+	assert.Contains(t, string(section.GetComputedInlineDiffFor(section.Lines[1])), `<span class="added-code">`)
+
+	section.ignoreWhitespace = true
+	assert.NotContains(t, string(section.GetComputedInlineDiffFor(section.Lines[1])), `<span class="added-code">`)
+}
+
+func TestMarkMovedLines(t *testing.T) {
+	diff, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(`diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1,7 +1,2 @@
+ package a
+-
+-func moved() {
+-	return computeSomething()
+-}
+ // end
+-	i++
+diff --git a/b.go b/b.go
+index 3333333..4444444 100644
+--- a/b.go
++++ b/b.go
+@@ -1,2 +1,7 @@
+ package b
++
++func moved() {
++	return computeSomething()
++}
++	i++
+-var x = 1
++var x = 2
+`))
+	assert.NoError(t, err)
+	markMovedLines(diff)
+	deleted := diff.Files[0].Sections[0].Lines
+	added := diff.Files[1].Sections[0].Lines
+
+	// blocks don't start at blank lines
+	assert.False(t, deleted[2].Moved)
+	assert.False(t, added[2].Moved)
+	for i := 3; i <= 5; i++ {
+		assert.True(t, deleted[i].Moved)
+		assert.True(t, added[i].Moved)
+	}
+	// the moved line is too short to be marked on its own
+	assert.False(t, deleted[7].Moved)
+	assert.False(t, added[6].Moved)
+	// changed lines aren't moved
+	assert.False(t, added[7].Moved)
+	assert.False(t, added[8].Moved)
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hovercard
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Kind is the kind of the object summarized by a card
+type Kind string
+
+// The kinds of the summarized objects
+const (
+	KindIssue  Kind = "issue"
+	KindPull   Kind = "pull"
+	KindCommit Kind = "commit"
+	KindUser   Kind = "user"
+)
+
+// ErrUnknownLink represents a link which isn't the link of an issue, a pull request, a commit or a user
+// of this instance, or the link of an object which can't be read by the doer
+var ErrUnknownLink = errors.New("unknown link")
+
+// Card is the summary of the object of a link, only the fields of its kind are loaded
+type Card struct {
+	Kind   Kind
+	Link   string
+	Repo   *models.Repository
+	Issue  *models.Issue
+	Commit *git.Commit
+	User   *models.User
+	// Statuses are the latest statuses of the commit, or of the head commit of the pull request
+	Statuses []*models.CommitStatus
+}
+
+// linkSegments returns the segments of the path of the link relative to the root of this instance
+func linkSegments(link string) ([]string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, ErrUnknownLink
+	}
+	if u.IsAbs() {
+		appURL, err := url.Parse(setting.AppURL)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(u.Host, appURL.Host) {
+			return nil, ErrUnknownLink
+		}
+	}
+	p := u.Path
+	if len(setting.AppSubURL) > 0 {
+		if !strings.HasPrefix(p, setting.AppSubURL+"/") {
+			return nil, ErrUnknownLink
+		}
+		p = strings.TrimPrefix(p, setting.AppSubURL)
+	}
+	p = strings.Trim(p, "/")
+	if len(p) == 0 {
+		return nil, ErrUnknownLink
+	}
+	return strings.Split(p, "/"), nil
+}
+
+// Resolve returns the card of the issue, the pull request, the commit or the user of the link if the
+// doer can read it, the link may be absolute or relative to the root of this instance
+func Resolve(doer *models.User, link string) (*Card, error) {
+	segments, err := linkSegments(link)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 1 {
+		return resolveUser(doer, segments[0])
+	}
+	if len(segments) < 4 {
+		return nil, ErrUnknownLink
+	}
+
+	repo, err := models.GetRepositoryByOwnerAndName(segments[0], strings.TrimSuffix(segments[1], ".git"))
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return nil, err
+	}
+
+	switch segments[2] {
+	case "issues", "pulls":
+		index, err := strconv.ParseInt(segments[3], 10, 64)
+		if err != nil {
+			return nil, ErrUnknownLink
+		}
+		return resolveIssue(perm, repo, index)
+	case "commit":
+		if len(segments) != 4 || !git.SHAPattern.MatchString(segments[3]) || !perm.CanRead(models.UnitTypeCode) {
+			return nil, ErrUnknownLink
+		}
+		return resolveCommit(repo, segments[3])
+	}
+	return nil, ErrUnknownLink
+}
+
+func resolveUser(doer *models.User, name string) (*Card, error) {
+	user, err := models.GetUserByName(name)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+	if user.IsOrganization() && !models.HasOrgVisible(user, doer) {
+		return nil, ErrUnknownLink
+	}
+	return &Card{
+		Kind: KindUser,
+		Link: user.HTMLURL(),
+		User: user,
+	}, nil
+}
+
+func resolveIssue(perm models.Permission, repo *models.Repository, index int64) (*Card, error) {
+	issue, err := models.GetIssueByIndex(repo.ID, index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return nil, ErrUnknownLink
+	}
+	if err := issue.LoadAttributes(); err != nil {
+		return nil, err
+	}
+
+	card := &Card{
+		Kind:  KindIssue,
+		Link:  issue.HTMLURL(),
+		Repo:  repo,
+		Issue: issue,
+	}
+	if !issue.IsPull {
+		return card, nil
+	}
+
+	card.Kind = KindPull
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	sha, err := gitRepo.GetRefCommitID(issue.PullRequest.GetGitRefName())
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			// the pull request is broken, its checks are unknown
+			return card, nil
+		}
+		return nil, err
+	}
+	if card.Statuses, err = models.GetLatestCommitStatus(repo, sha, 0); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func resolveCommit(repo *models.Repository, sha string) (*Card, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, ErrUnknownLink
+		}
+		return nil, err
+	}
+	statuses, err := models.GetLatestCommitStatus(repo, commit.ID.String(), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Card{
+		Kind:     KindCommit,
+		Link:     repo.HTMLURL() + "/commit/" + commit.ID.String(),
+		Repo:     repo,
+		Commit:   commit,
+		Statuses: statuses,
+	}, nil
+}
@@ -0,0 +1,285 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxExportIssues is the maximum number of issues of an exported document
+const MaxExportIssues = 100
+
+// ExportRenderer renders the printable document of the exported issues
+type ExportRenderer func(issues []*ExportedIssue) ([]byte, error)
+
+// ExportedIssue is an issue of an exported document with its rendered comments
+// and the manifest of its attachments
+type ExportedIssue struct {
+	*models.Issue
+	Comments []*models.Comment
+	// Attachments of the issue and of its exported comments
+	Attachments []*models.Attachment
+}
+
+// ExportRequest is a request to export a set of issues of a repository to a printable
+// document, which is generated in the background.
+type ExportRequest struct {
+	issues     []*models.Issue
+	render     ExportRenderer
+	exportPath string
+	complete   bool
+	cchan      chan struct{}
+}
+
+var exportInProgress []*ExportRequest
+var exportMutex sync.Mutex
+
+// NewExportRequest creates the request to export issues of a repository, whose attributes are
+// loaded. The document is generated again once one of the issues or their comments is updated,
+// lang is the language of the document.
+func NewExportRequest(repo *models.Repository, issues []*models.Issue, lang string, render ExportRenderer) (*ExportRequest, error) {
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Index < issues[j].Index
+	})
+
+	h := sha1.New()
+	_, _ = fmt.Fprintf(h, "%d:%s", repo.ID, lang)
+	for _, issue := range issues {
+		_, _ = fmt.Fprintf(h, ":%d@%d", issue.ID, issue.UpdatedUnix)
+		// editing a comment doesn't update its issue
+		for _, comment := range issue.Comments {
+			_, _ = fmt.Fprintf(h, ",%d@%d", comment.ID, comment.UpdatedUnix)
+		}
+	}
+
+	dir := filepath.Join(exportsPath(), fmt.Sprint(repo.ID))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	r := &ExportRequest{
+		issues:     issues,
+		render:     render,
+		exportPath: filepath.Join(dir, hex.EncodeToString(h.Sum(nil))+".html"),
+	}
+
+	exportMutex.Lock()
+	defer exportMutex.Unlock()
+	if rExisting := getExportRequest(r.exportPath); rExisting != nil {
+		return rExisting, nil
+	}
+
+	var err error
+	if r.complete, err = util.IsFile(r.exportPath); err != nil || !r.complete {
+		return r, err
+	}
+	// the age of a reused document restarts, so that it isn't deleted before being served
+	now := time.Now()
+	return r, os.Chtimes(r.exportPath, now, now)
+}
+
+// exportsPath returns the directory of the generated documents, grouped by repository
+func exportsPath() string {
+	return filepath.Join(setting.AppDataPath, "issue_exports")
+}
+
+// The caller must hold the exportMutex across calls to getExportRequest.
+func getExportRequest(exportPath string) *ExportRequest {
+	for _, r := range exportInProgress {
+		if r.exportPath == exportPath {
+			return r
+		}
+	}
+	return nil
+}
+
+// GetExportPath returns the path of the generated document
+func (r *ExportRequest) GetExportPath() string {
+	return r.exportPath
+}
+
+// IsComplete returns whether the document has been generated
+func (r *ExportRequest) IsComplete() bool {
+	return r.complete
+}
+
+// TimedWaitForCompletion waits for the document to be generated, at most for the duration.
+// It returns whether the document is complete.
+func (r *ExportRequest) TimedWaitForCompletion(ctx context.Context, dur time.Duration) bool {
+	if r.cchan != nil {
+		select {
+		case <-time.After(dur):
+		case <-r.cchan:
+		case <-ctx.Done():
+		}
+	}
+	return r.IsComplete()
+}
+
+func doExport(r *ExportRequest) {
+	defer close(r.cchan)
+
+	issues := make([]*ExportedIssue, 0, len(r.issues))
+	for _, issue := range r.issues {
+		exported, err := exportIssue(issue)
+		if err != nil {
+			log.Error("Unable to export the issue %d: %v", issue.ID, err)
+			return
+		}
+		issues = append(issues, exported)
+	}
+
+	content, err := r.render(issues)
+	if err != nil {
+		log.Error("Unable to render the export of the issues: %v", err)
+		return
+	}
+
+	// write to a temporary file first, the complete document is then moved into place
+	tmpFile, err := ioutil.TempFile(filepath.Dir(r.exportPath), "export")
+	if err != nil {
+		log.Error("Unable to create a temporary export file: %v", err)
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Error("Unable to write the export file %s: %v", tmpFile.Name(), err)
+		return
+	}
+	if err = os.Rename(tmpFile.Name(), r.exportPath); err != nil {
+		log.Error("Unable to move the export file to %s: %v", r.exportPath, err)
+		return
+	}
+
+	r.complete = true
+}
+
+// exportIssue renders the content and the comments of an issue, the links of the rendered
+// content are absolute so that the document can be read offline
+func exportIssue(issue *models.Issue) (*ExportedIssue, error) {
+	urlPrefix := issue.Repo.HTMLURL()
+	metas := issue.Repo.ComposeMetas()
+
+	exported := &ExportedIssue{
+		Issue:       issue,
+		Comments:    make([]*models.Comment, 0, len(issue.Comments)),
+		Attachments: append(make([]*models.Attachment, 0, len(issue.Attachments)), issue.Attachments...),
+	}
+	issue.RenderedContent = string(markdown.Render([]byte(issue.Content), urlPrefix, metas))
+
+	for _, comment := range issue.Comments {
+		switch comment.Type {
+		case models.CommentTypeComment, models.CommentTypeCode, models.CommentTypeReview:
+			if err := comment.LoadAttachments(); err != nil {
+				return nil, err
+			}
+			if len(comment.Content) == 0 && len(comment.Attachments) == 0 {
+				continue
+			}
+			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), urlPrefix, metas))
+		case models.CommentTypeClose, models.CommentTypeReopen:
+		default:
+			continue
+		}
+		if err := comment.LoadPoster(); err != nil {
+			return nil, err
+		}
+		exported.Comments = append(exported.Comments, comment)
+		exported.Attachments = append(exported.Attachments, comment.Attachments...)
+	}
+	return exported, nil
+}
+
+// ExportIssues generates the document of the request in the background, unless it is already
+// complete. The caller should examine the returned request, which may be a request already
+// in progress for the same document.
+func ExportIssues(request *ExportRequest) *ExportRequest {
+	exportMutex.Lock()
+	defer exportMutex.Unlock()
+	if rExisting := getExportRequest(request.exportPath); rExisting != nil {
+		return rExisting
+	}
+	if request.complete {
+		return request
+	}
+
+	request.cchan = make(chan struct{})
+	exportInProgress = append(exportInProgress, request)
+	go func() {
+		doExport(request)
+
+		exportMutex.Lock()
+		defer exportMutex.Unlock()
+		for i, r := range exportInProgress {
+			if r == request {
+				exportInProgress = append(exportInProgress[:i], exportInProgress[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	return request
+}
+
+// DeleteOldExports deletes the documents generated more than olderThan ago, the documents are
+// shared by the requests exporting the same issues so they are not deleted once served
+func DeleteOldExports(ctx context.Context, olderThan time.Duration) error {
+	log.Trace("Doing: DeleteOldExports")
+
+	minimumOldestTime := time.Now().Add(-olderThan)
+	err := filepath.Walk(exportsPath(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before deleting the old issue export %s", path)
+		default:
+		}
+		if info.IsDir() || !info.ModTime().Before(minimumOldestTime) {
+			return nil
+		}
+
+		exportMutex.Lock()
+		defer exportMutex.Unlock()
+		if getExportRequest(path) != nil {
+			return nil
+		}
+		// This is a best-effort purge, so we do not check error codes to confirm removal.
+		if err := util.Remove(path); err != nil {
+			log.Trace("Unable to delete %s, but proceeding: %v", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Trace("Finished: DeleteOldExports")
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteOldExports(t *testing.T) {
+	defer func(appDataPath string) {
+		setting.AppDataPath = appDataPath
+	}(setting.AppDataPath)
+	var err error
+	setting.AppDataPath, err = ioutil.TempDir("", "issue-exports")
+	assert.NoError(t, err)
+	defer util.RemoveAll(setting.AppDataPath)
+
+	// no document was exported yet
+	assert.NoError(t, DeleteOldExports(context.Background(), time.Hour))
+
+	dir := filepath.Join(exportsPath(), "1")
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	oldPath, newPath := filepath.Join(dir, "old.html"), filepath.Join(dir, "new.html")
+	assert.NoError(t, ioutil.WriteFile(oldPath, []byte("old"), 0644))
+	assert.NoError(t, ioutil.WriteFile(newPath, []byte("new"), 0644))
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(oldPath, twoHoursAgo, twoHoursAgo))
+
+	assert.NoError(t, DeleteOldExports(context.Background(), time.Hour))
+	exists, err := util.IsExist(oldPath)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = util.IsExist(newPath)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var (
+	reminderDurationPattern = regexp.MustCompile(`^(\d+)([mhdw])$`)
+	remindCommandPattern    = regexp.MustCompile(`(?m)^/remind[ \t]+(\S+)[ \t]*(?:\r?\n|$)`)
+)
+
+// ParseRemindDuration returns a duration given as a number of minutes, hours, days or weeks
+// ("30m", "2h", "3d", "1w")
+func ParseRemindDuration(value string) (time.Duration, error) {
+	m := reminderDurationPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid reminder duration: %s", value)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid reminder duration: %s", value)
+	}
+	unit := map[string]time.Duration{
+		"m": time.Minute,
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}[m[2]]
+	return time.Duration(n) * unit, nil
+}
+
+// ParseRemindAt returns the time of a reminder given either as a duration from now (see
+// ParseRemindDuration) or as a date ("2006-01-02")
+func ParseRemindAt(value string, now time.Time) (time.Time, error) {
+	if reminderDurationPattern.MatchString(value) {
+		d, err := ParseRemindDuration(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	remindAt, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid reminder time: %s", value)
+	}
+	if !remindAt.After(now) {
+		return time.Time{}, fmt.Errorf("reminder time is in the past: %s", value)
+	}
+	return remindAt, nil
+}
+
+// ExtractRemindCommands removes the "/remind {time}" lines of the content of a comment and
+// returns the remaining content and the times of the reminders
+func ExtractRemindCommands(content string, now time.Time) (string, []time.Time, error) {
+	matches := remindCommandPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return content, nil, nil
+	}
+
+	remindAts := make([]time.Time, 0, len(matches))
+	for _, m := range matches {
+		remindAt, err := ParseRemindAt(m[1], now)
+		if err != nil {
+			return content, nil, err
+		}
+		remindAts = append(remindAts, remindAt)
+	}
+	return strings.TrimSpace(remindCommandPattern.ReplaceAllString(content, "")), remindAts, nil
+}
+
+// CreateReminders schedules notifications of the issue for the user
+func CreateReminders(doer *models.User, issue *models.Issue, remindAts []time.Time) error {
+	for _, remindAt := range remindAts {
+		if _, err := models.CreateIssueReminder(doer, issue, timeutil.TimeStamp(remindAt.Unix())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
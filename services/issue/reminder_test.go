@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRemindAt(t *testing.T) {
+	now := time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC)
+	for value, expected := range map[string]time.Time{
+		"30m":        now.Add(30 * time.Minute),
+		"2h":         now.Add(2 * time.Hour),
+		"3d":         now.Add(72 * time.Hour),
+		"1w":         now.Add(7 * 24 * time.Hour),
+		"2020-11-10": time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC),
+	} {
+		remindAt, err := ParseRemindAt(value, now)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, remindAt, value)
+	}
+
+	for _, value := range []string{"", "0d", "3y", "d", "2020-11-01", "tomorrow"} {
+		_, err := ParseRemindAt(value, now)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestExtractRemindCommands(t *testing.T) {
+	now := time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC)
+
+	content, remindAts, err := ExtractRemindCommands("Some text\n/remind 3d\nMore text", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "Some text\nMore text", content)
+	assert.Equal(t, []time.Time{now.Add(72 * time.Hour)}, remindAts)
+
+	content, remindAts, err = ExtractRemindCommands("/remind 1h\r\n/remind 2020-11-10", now)
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+	assert.Len(t, remindAts, 2)
+
+	content, remindAts, err = ExtractRemindCommands("Please /remind 1h later", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "Please /remind 1h later", content)
+	assert.Empty(t, remindAts)
+
+	_, _, err = ExtractRemindCommands("/remind soon", now)
+	assert.Error(t, err)
+}
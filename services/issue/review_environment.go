@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// ExpireReviewEnvironments expires the review environments of a closed or merged pull request
+// and notifies that they can be cleaned up
+func ExpireReviewEnvironments(doer *models.User, pr *models.PullRequest) {
+	envs, err := models.ExpireReviewEnvironments(pr.ID)
+	if err != nil {
+		log.Error("ExpireReviewEnvironments [pull_id: %d]: %v", pr.ID, err)
+		return
+	}
+	if len(envs) > 0 {
+		notification.NotifyReviewEnvironmentsExpired(doer, pr, envs)
+	}
+}
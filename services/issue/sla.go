@@ -0,0 +1,137 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+// breachLabelColor is the color of the breach labels created by the SLA policies
+const breachLabelColor = "#e11d21"
+
+// ProcessIssueSLAs applies the enabled SLA policies of all the organizations: the open issues
+// breaching a target of a policy are labeled and commented once
+func ProcessIssueSLAs(ctx context.Context) error {
+	return processIssueSLAs(ctx, timeutil.TimeStampNow())
+}
+
+func processIssueSLAs(ctx context.Context, now timeutil.TimeStamp) error {
+	log.Trace("Doing: ProcessIssueSLAs")
+
+	policies, err := models.GetEnabledIssueSLAPolicies()
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before applying the SLA policy %d", policy.ID)
+		default:
+		}
+
+		if err := applyIssueSLAPolicy(policy, now); err != nil {
+			log.Error("applyIssueSLAPolicy[%d]: %v", policy.ID, err)
+		}
+	}
+
+	log.Trace("Finished: ProcessIssueSLAs")
+	return nil
+}
+
+func applyIssueSLAPolicy(policy *models.IssueSLAPolicy, now timeutil.TimeStamp) error {
+	// the actions are taken on behalf of the user which last changed the policy
+	doer, err := models.GetUserByID(policy.DoerID)
+	if err != nil {
+		return err
+	}
+
+	issues, err := policy.FindOpenIssues()
+	if err != nil {
+		return err
+	}
+	statuses, err := policy.GetIssueSLAStatuses(issues, now)
+	if err != nil {
+		return err
+	}
+
+	// the doer must still be allowed to change the issues of the repositories
+	type repoPerm struct {
+		repo *models.Repository
+		perm models.Permission
+	}
+	repoPerms := make(map[int64]*repoPerm)
+	for _, status := range statuses {
+		if !status.ResponseBreached && !status.ResolutionBreached {
+			continue
+		}
+		issue := status.Issue
+		rp, ok := repoPerms[issue.RepoID]
+		if !ok {
+			rp = new(repoPerm)
+			if rp.repo, err = models.GetRepositoryByID(issue.RepoID); err == nil {
+				if err = rp.repo.GetOwner(); err == nil {
+					rp.perm, err = models.GetUserRepoPermission(rp.repo, doer)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			repoPerms[issue.RepoID] = rp
+		}
+		if !rp.perm.CanWriteIssuesOrPulls(issue.IsPull) {
+			log.Warn("User %s can't change the issue %s#%d, its SLA breaches aren't handled", doer.Name, rp.repo.FullName(), issue.Index)
+			continue
+		}
+		issue.Repo = rp.repo
+
+		if status.ResponseBreached {
+			if err := breachIssueSLA(policy, doer, issue, models.IssueSLATargetResponse, policy.ResponseDeadline(status)); err != nil {
+				log.Error("breachIssueSLA[%d]: %v", issue.ID, err)
+			}
+		}
+		if status.ResolutionBreached {
+			if err := breachIssueSLA(policy, doer, issue, models.IssueSLATargetResolution, policy.ResolutionDeadline(status)); err != nil {
+				log.Error("breachIssueSLA[%d]: %v", issue.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// breachIssueSLA records the breach of a target of the policy by the issue, and adds the breach
+// label and posts the breach comment of the policy unless the breach has already been recorded
+func breachIssueSLA(policy *models.IssueSLAPolicy, doer *models.User, issue *models.Issue, target models.IssueSLATarget, deadline timeutil.TimeStamp) error {
+	has, err := models.HasIssueSLABreach(policy.ID, issue.ID, target)
+	if err != nil || has {
+		return err
+	}
+	if err := models.CreateIssueSLABreach(policy, issue, target, deadline); err != nil {
+		return err
+	}
+
+	if len(policy.BreachLabel) > 0 {
+		label, err := getOrCreateLabel(issue.Repo, policy.BreachLabel, breachLabelColor)
+		if err != nil {
+			return err
+		}
+		if !models.HasIssueLabel(issue.ID, label.ID) {
+			if err := AddLabel(issue, doer, label); err != nil {
+				return err
+			}
+		}
+	}
+	if len(policy.BreachComment) > 0 {
+		if _, err := comment_service.CreateIssueComment(doer, issue.Repo, issue, policy.BreachComment, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
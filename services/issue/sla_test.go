@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessIssueSLAs(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	policy := &models.IssueSLAPolicy{
+		OrgID:         3,
+		Name:          "P1 bugs",
+		Enabled:       true,
+		Label:         "orglabel3",
+		ResponseHours: 4,
+		BreachLabel:   "sla-breach",
+		BreachComment: "The first response is overdue.",
+		DoerID:        2,
+	}
+	assert.NoError(t, models.CreateIssueSLAPolicy(policy))
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 6}).(*models.Issue)
+	label := models.AssertExistsAndLoadBean(t, &models.Label{ID: 3}).(*models.Label)
+	assert.NoError(t, AddLabel(issue, doer, label))
+
+	// the label has just been added, the deadline isn't passed yet
+	now := timeutil.TimeStampNow()
+	assert.NoError(t, processIssueSLAs(context.Background(), now.Add(3*3600)))
+	models.AssertNotExistsBean(t, &models.IssueSLABreach{IssueID: issue.ID})
+
+	assert.NoError(t, processIssueSLAs(context.Background(), now.Add(5*3600)))
+	models.AssertExistsAndLoadBean(t, &models.IssueSLABreach{PolicyID: policy.ID, IssueID: issue.ID, Target: models.IssueSLATargetResponse})
+	breachLabel := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: issue.RepoID, Name: "sla-breach"}).(*models.Label)
+	assert.True(t, models.HasIssueLabel(issue.ID, breachLabel.ID))
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, Content: policy.BreachComment})
+
+	// a breach is only handled once
+	assert.NoError(t, processIssueSLAs(context.Background(), now.Add(6*3600)))
+	models.AssertCount(t, &models.Comment{IssueID: issue.ID, Content: policy.BreachComment}, 1)
+}
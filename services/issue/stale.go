@@ -0,0 +1,212 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+// staleLabelColor is the color of the stale labels created by the stale policies
+const staleLabelColor = "#cccccc"
+
+// ProcessStaleIssues applies the enabled stale policies of all the repositories: the inactive
+// issues are labeled and warned, the activity on a stale issue removes its label and the issues
+// which stay inactive are closed
+func ProcessStaleIssues(ctx context.Context) error {
+	return processStaleIssues(ctx, timeutil.TimeStampNow())
+}
+
+func processStaleIssues(ctx context.Context, now timeutil.TimeStamp) error {
+	log.Trace("Doing: ProcessStaleIssues")
+
+	policies, err := models.GetEnabledStalePolicies()
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before applying the stale policy of the repository %d", policy.RepoID)
+		default:
+		}
+
+		if err := applyStalePolicy(policy, now); err != nil {
+			log.Error("applyStalePolicy[%d]: %v", policy.RepoID, err)
+		}
+	}
+
+	log.Trace("Finished: ProcessStaleIssues")
+	return nil
+}
+
+// getStaleLabel returns the stale label of the policy, it is created in the repository if
+// neither the repository nor its organization has it
+func getStaleLabel(policy *models.StalePolicy) (*models.Label, error) {
+	return getOrCreateLabel(policy.Repo, policy.StaleLabelName(), staleLabelColor)
+}
+
+// getOrCreateLabel returns the label of the repository or of its organization with the given name,
+// it is created in the repository if neither has it. The owner of the repository has to be loaded.
+func getOrCreateLabel(repo *models.Repository, name, color string) (*models.Label, error) {
+	label, err := models.GetLabelInRepoByName(repo.ID, name)
+	if err == nil || !models.IsErrRepoLabelNotExist(err) {
+		return label, err
+	}
+	if repo.Owner.IsOrganization() {
+		label, err = models.GetLabelInOrgByName(repo.OwnerID, name)
+		if err == nil || !models.IsErrOrgLabelNotExist(err) {
+			return label, err
+		}
+	}
+
+	label = &models.Label{
+		RepoID: repo.ID,
+		Name:   name,
+		Color:  color,
+	}
+	return label, models.NewLabel(label)
+}
+
+// getExemptLabelIDs returns the IDs of the exempt labels of the policy
+func getExemptLabelIDs(policy *models.StalePolicy) ([]int64, error) {
+	names := policy.ExemptLabelNames()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ids, err := models.GetLabelIDsInRepoByNames(policy.RepoID, names)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Repo.Owner.IsOrganization() {
+		orgIDs, err := models.GetLabelIDsInOrgByNames(policy.Repo.OwnerID, names)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, orgIDs...)
+	}
+	return ids, nil
+}
+
+func applyStalePolicy(policy *models.StalePolicy, now timeutil.TimeStamp) error {
+	if err := policy.LoadRepo(); err != nil {
+		return err
+	}
+	if err := policy.Repo.GetOwner(); err != nil {
+		return err
+	}
+
+	// the actions are taken on behalf of the user which last changed the policy, it must still
+	// be allowed to change the issues
+	doer, err := models.GetUserByID(policy.DoerID)
+	if err != nil {
+		return err
+	}
+	perm, err := models.GetUserRepoPermission(policy.Repo, doer)
+	if err != nil {
+		return err
+	}
+	if (policy.IncludeIssues && !perm.CanWriteIssuesOrPulls(false)) ||
+		(policy.IncludePulls && !perm.CanWriteIssuesOrPulls(true)) {
+		return fmt.Errorf("user %s can't change the issues of %s", doer.Name, policy.Repo.FullName())
+	}
+
+	label, err := getStaleLabel(policy)
+	if err != nil {
+		return err
+	}
+	exemptLabelIDs, err := getExemptLabelIDs(policy)
+	if err != nil {
+		return err
+	}
+
+	// the marked issues are updated first, the issues which have just been unmarked are then
+	// recently updated and aren't marked again
+	marked, err := policy.FindMarkedIssues(label.ID)
+	if err != nil {
+		return err
+	}
+	for _, issue := range marked {
+		issue.Repo = policy.Repo
+		if err := updateStaleIssue(policy, doer, label, issue, now); err != nil {
+			log.Error("updateStaleIssue[%d]: %v", issue.ID, err)
+		}
+	}
+
+	issues, err := policy.FindIssuesToMark(label.ID, exemptLabelIDs, now)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		issue.Repo = policy.Repo
+		if err := markStaleIssue(policy, doer, label, issue, now); err != nil {
+			log.Error("markStaleIssue[%d]: %v", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+// markStaleIssue labels the issue as stale and warns its participants
+func markStaleIssue(policy *models.StalePolicy, doer *models.User, label *models.Label, issue *models.Issue, now timeutil.TimeStamp) error {
+	if err := AddLabel(issue, doer, label); err != nil {
+		return err
+	}
+	if len(policy.WarningComment) > 0 {
+		if _, err := comment_service.CreateIssueComment(doer, policy.Repo, issue, policy.WarningComment, nil); err != nil {
+			return err
+		}
+	}
+
+	// marking the issue updates it, only the later updates are activity on the stale issue
+	issue, err := models.GetIssueByID(issue.ID)
+	if err != nil {
+		return err
+	}
+	markedUnix := now
+	if issue.UpdatedUnix > markedUnix {
+		markedUnix = issue.UpdatedUnix
+	}
+	return models.CreateStaleActionLog(issue, models.StaleActionMark, markedUnix)
+}
+
+// updateStaleIssue removes the stale label of the issue if it has been updated since it was
+// marked, or closes it if it has been stale for the number of days of the policy
+func updateStaleIssue(policy *models.StalePolicy, doer *models.User, label *models.Label, issue *models.Issue, now timeutil.TimeStamp) error {
+	mark, err := models.GetLastStaleMark(issue.ID)
+	if err != nil {
+		return err
+	}
+
+	// the issue may have been labeled by a user, it is then stale since it was last updated
+	markedUnix := issue.UpdatedUnix
+	if mark != nil {
+		if issue.UpdatedUnix > mark.CreatedUnix {
+			if err := RemoveLabel(issue, doer, label); err != nil {
+				return err
+			}
+			return models.CreateStaleActionLog(issue, models.StaleActionUnmark, now)
+		}
+		markedUnix = mark.CreatedUnix
+	}
+
+	if policy.DaysUntilClose <= 0 || now < markedUnix.Add(int64(policy.DaysUntilClose)*86400) {
+		return nil
+	}
+	if len(policy.CloseComment) > 0 {
+		if _, err := comment_service.CreateIssueComment(doer, policy.Repo, issue, policy.CloseComment, nil); err != nil {
+			return err
+		}
+	}
+	if err := ChangeStatus(issue, doer, true); err != nil {
+		return err
+	}
+	return models.CreateStaleActionLog(issue, models.StaleActionClose, now)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessStaleIssues(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	policy := &models.StalePolicy{
+		RepoID:         1,
+		Enabled:        true,
+		IncludeIssues:  true,
+		DaysUntilStale: 30,
+		DaysUntilClose: 7,
+		WarningComment: "This issue is stale.",
+		CloseComment:   "Closing the stale issue.",
+		DoerID:         2,
+	}
+	assert.NoError(t, models.SaveStalePolicy(policy))
+	assert.NoError(t, policy.LoadRepo())
+	assert.NoError(t, policy.Repo.GetOwner())
+	label, err := getStaleLabel(policy)
+	assert.NoError(t, err)
+	models.AssertExistsAndLoadBean(t, &models.Label{ID: label.ID, RepoID: 1, Name: models.DefaultStaleLabel})
+
+	// the issue has been marked before being updated
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.NoError(t, AddLabel(issue, doer, label))
+	issue = models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.NoError(t, models.CreateStaleActionLog(issue, models.StaleActionMark, issue.UpdatedUnix-10))
+
+	now := timeutil.TimeStampNow()
+	assert.NoError(t, processStaleIssues(context.Background(), now))
+	assert.False(t, models.HasIssueLabel(issue.ID, label.ID))
+	models.AssertExistsAndLoadBean(t, &models.StaleActionLog{IssueID: issue.ID, Action: models.StaleActionUnmark})
+	// the pull requests and the recently updated issues aren't marked
+	models.AssertNotExistsBean(t, &models.StaleActionLog{IssueID: 2})
+	models.AssertNotExistsBean(t, &models.Comment{IssueID: issue.ID, Content: policy.WarningComment})
+
+	now = now.Add(31 * 86400)
+	assert.NoError(t, processStaleIssues(context.Background(), now))
+	assert.True(t, models.HasIssueLabel(issue.ID, label.ID))
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, Content: policy.WarningComment})
+	models.AssertExistsAndLoadBean(t, &models.StaleActionLog{IssueID: issue.ID, Action: models.StaleActionMark, CreatedUnix: now})
+
+	// the issue isn't closed before the number of days of the policy
+	assert.NoError(t, processStaleIssues(context.Background(), now.Add(6*86400)))
+	issue, err = models.GetIssueByID(issue.ID)
+	assert.NoError(t, err)
+	assert.False(t, issue.IsClosed)
+
+	assert.NoError(t, processStaleIssues(context.Background(), now.Add(7*86400)))
+	issue, err = models.GetIssueByID(issue.ID)
+	assert.NoError(t, err)
+	assert.True(t, issue.IsClosed)
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, Content: policy.CloseComment})
+	models.AssertExistsAndLoadBean(t, &models.StaleActionLog{IssueID: issue.ID, Action: models.StaleActionClose})
+}
@@ -17,5 +17,12 @@ func ChangeStatus(issue *models.Issue, doer *models.User, isClosed bool) (err er
 	}
 
 	notification.NotifyIssueChangeStatus(doer, issue, comment, isClosed)
+
+	if isClosed && issue.IsPull {
+		if err = issue.LoadPullRequest(); err != nil {
+			return
+		}
+		ExpireReviewEnvironments(doer, issue.PullRequest)
+	}
 	return nil
 }
@@ -0,0 +1,137 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+// statusIssueLabelColor is the color of the labels created for the tracking issues
+const statusIssueLabelColor = "#e11d21"
+
+// statusContextName returns the name of the context of a status in the tracking issues
+func statusContextName(status *models.CommitStatus) string {
+	if len(status.Context) == 0 {
+		return "default"
+	}
+	return status.Context
+}
+
+// statusDetails returns the description and the link of a status as markdown
+func statusDetails(status *models.CommitStatus) string {
+	var details strings.Builder
+	if len(status.Description) > 0 {
+		details.WriteString("\n\n> " + status.Description)
+	}
+	if len(status.TargetURL) > 0 {
+		details.WriteString("\n\n[Details](" + status.TargetURL + ")")
+	}
+	return details.String()
+}
+
+// ProcessStatusIssue applies the status issue rule of the repository to a new status of a commit: a failure
+// of the head of the default branch opens an issue tracking the failures of its context, or comments on the
+// one still open, and a success closes it
+func ProcessStatusIssue(repo *models.Repository, sha string, status *models.CommitStatus) error {
+	var failed bool
+	switch status.State {
+	case api.CommitStatusFailure, api.CommitStatusError:
+		failed = true
+	case api.CommitStatusSuccess:
+	default:
+		return nil
+	}
+
+	rule, err := models.GetStatusIssueRule(repo.ID)
+	if err != nil {
+		if models.IsErrStatusIssueRuleNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !rule.Enabled || !rule.MatchContext(status.Context) || !repo.UnitEnabled(models.UnitTypeIssues) {
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	headCommitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+	gitRepo.Close()
+	if err != nil || headCommitID != sha {
+		return nil
+	}
+
+	issue, err := models.GetStatusIssue(repo.ID, status.Context)
+	if err != nil {
+		return err
+	}
+	if !failed && (issue == nil || issue.IsClosed) {
+		return nil
+	}
+
+	// the issues are changed on behalf of the user which last changed the rule, it must still be
+	// allowed to change them
+	doer, err := models.GetUserByID(rule.DoerID)
+	if err != nil {
+		return err
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return err
+	}
+	if !perm.CanWriteIssuesOrPulls(false) {
+		return fmt.Errorf("user %s can't change the issues of %s", doer.Name, repo.FullName())
+	}
+
+	name := statusContextName(status)
+	if !failed {
+		issue.Repo = repo
+		content := fmt.Sprintf("`%s` passes again on commit %s.", name, sha)
+		if _, err := comment_service.CreateIssueComment(doer, repo, issue, content, nil); err != nil {
+			return err
+		}
+		return ChangeStatus(issue, doer, true)
+	}
+
+	if issue != nil && !issue.IsClosed {
+		issue.Repo = repo
+		content := fmt.Sprintf("`%s` fails again on commit %s.%s", name, sha, statusDetails(status))
+		_, err := comment_service.CreateIssueComment(doer, repo, issue, content, nil)
+		return err
+	}
+
+	var labelIDs []int64
+	if len(strings.TrimSpace(rule.LabelName)) > 0 {
+		if err := repo.GetOwner(); err != nil {
+			return err
+		}
+		label, err := getOrCreateLabel(repo, strings.TrimSpace(rule.LabelName), statusIssueLabelColor)
+		if err != nil {
+			return err
+		}
+		labelIDs = []int64{label.ID}
+	}
+
+	issue = &models.Issue{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Title:    fmt.Sprintf("%s fails on %s", name, repo.DefaultBranch),
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  fmt.Sprintf("`%s` fails on commit %s of the default branch `%s`.%s", name, sha, repo.DefaultBranch, statusDetails(status)),
+	}
+	if err := NewIssue(repo, issue, labelIDs, nil, nil); err != nil {
+		return err
+	}
+	return models.SetStatusIssue(repo.ID, status.Context, issue.ID)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessStatusIssue(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	const headCommitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	failure := &models.CommitStatus{State: api.CommitStatusFailure, Context: "ci/build", TargetURL: "https://ci.example.com/1"}
+	success := &models.CommitStatus{State: api.CommitStatusSuccess, Context: "ci/build"}
+
+	// nothing is done without an enabled rule
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, failure))
+	models.AssertNotExistsBean(t, &models.StatusIssue{RepoID: repo.ID})
+
+	assert.NoError(t, models.SaveStatusIssueRule(&models.StatusIssueRule{
+		RepoID:         repo.ID,
+		Enabled:        true,
+		ContextPattern: "ci/*",
+		LabelName:      "ci-failure",
+		DoerID:         2,
+	}))
+
+	// only the failures of the head of the default branch with a matching context are tracked
+	assert.NoError(t, ProcessStatusIssue(repo, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", failure))
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, &models.CommitStatus{State: api.CommitStatusFailure, Context: "lint"}))
+	models.AssertNotExistsBean(t, &models.StatusIssue{RepoID: repo.ID})
+
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, failure))
+	issue, err := models.GetStatusIssue(repo.ID, "ci/build")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, issue) {
+		return
+	}
+	assert.Equal(t, "ci/build fails on master", issue.Title)
+	assert.Contains(t, issue.Content, "https://ci.example.com/1")
+	assert.EqualValues(t, 2, issue.PosterID)
+	label := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: repo.ID, Name: "ci-failure"}).(*models.Label)
+	assert.True(t, models.HasIssueLabel(issue.ID, label.ID))
+
+	// a new failure is commented on the open issue
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, &models.CommitStatus{State: api.CommitStatusError, Context: "ci/build"}))
+	again, err := models.GetStatusIssue(repo.ID, "ci/build")
+	assert.NoError(t, err)
+	assert.Equal(t, issue.ID, again.ID)
+	assert.EqualValues(t, 1, again.NumComments)
+
+	// a success closes it
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, success))
+	closed := models.AssertExistsAndLoadBean(t, &models.Issue{ID: issue.ID}).(*models.Issue)
+	assert.True(t, closed.IsClosed)
+
+	// the next failure opens a new issue
+	assert.NoError(t, ProcessStatusIssue(repo, headCommitID, failure))
+	next, err := models.GetStatusIssue(repo.ID, "ci/build")
+	assert.NoError(t, err)
+	assert.NotEqual(t, issue.ID, next.ID)
+	assert.False(t, next.IsClosed)
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package legalhold
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	// ContentFileName is the name of the file holding the content in an export archive
+	ContentFileName = "content.json"
+	// SignatureFileName is the name of the file holding the signature of the content in an export archive
+	SignatureFileName = "content.json.sig"
+)
+
+type exportedHold struct {
+	ID      int64     `json:"id"`
+	UserID  int64     `json:"user_id,omitempty"`
+	RepoID  int64     `json:"repo_id,omitempty"`
+	Reason  string    `json:"reason"`
+	DoerID  int64     `json:"doer_id"`
+	Created time.Time `json:"created_at"`
+}
+
+type exportedIssue struct {
+	ID       int64     `json:"id"`
+	RepoID   int64     `json:"repo_id"`
+	Index    int64     `json:"index"`
+	PosterID int64     `json:"poster_id"`
+	IsPull   bool      `json:"is_pull"`
+	Title    string    `json:"title"`
+	Content  string    `json:"content"`
+	Created  time.Time `json:"created_at"`
+	Updated  time.Time `json:"updated_at"`
+}
+
+type exportedComment struct {
+	ID       int64     `json:"id"`
+	IssueID  int64     `json:"issue_id"`
+	PosterID int64     `json:"poster_id"`
+	Type     int       `json:"type"`
+	Content  string    `json:"content"`
+	Created  time.Time `json:"created_at"`
+	Updated  time.Time `json:"updated_at"`
+}
+
+type exportedRevision struct {
+	ID        int64     `json:"id"`
+	RepoID    int64     `json:"repo_id"`
+	IssueID   int64     `json:"issue_id"`
+	CommentID int64     `json:"comment_id,omitempty"`
+	PosterID  int64     `json:"poster_id"`
+	DoerID    int64     `json:"doer_id"`
+	Content   string    `json:"content"`
+	IsDeleted bool      `json:"is_deleted"`
+	Created   time.Time `json:"created_at"`
+}
+
+type exportedContent struct {
+	Hold       exportedHold        `json:"hold"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Issues     []*exportedIssue    `json:"issues"`
+	Comments   []*exportedComment  `json:"comments"`
+	Revisions  []*exportedRevision `json:"revisions"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of exported content keyed with the secret key of the instance
+func Sign(content []byte) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of exported content
+func Verify(content []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = mac.Write(content)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Export writes a zip archive of the issues and comments held by a legal hold with their revisions,
+// along with the signature of the content
+func Export(hold *models.LegalHold, w io.Writer) error {
+	held, err := models.GetLegalHoldContent(hold)
+	if err != nil {
+		return err
+	}
+
+	content := &exportedContent{
+		Hold: exportedHold{
+			ID:      hold.ID,
+			UserID:  hold.UserID,
+			RepoID:  hold.RepoID,
+			Reason:  hold.Reason,
+			DoerID:  hold.DoerID,
+			Created: hold.CreatedUnix.AsTime(),
+		},
+		ExportedAt: timeutil.TimeStampNow().AsTime(),
+		Issues:     make([]*exportedIssue, 0, len(held.Issues)),
+		Comments:   make([]*exportedComment, 0, len(held.Comments)),
+		Revisions:  make([]*exportedRevision, 0, len(held.Revisions)),
+	}
+	for _, issue := range held.Issues {
+		content.Issues = append(content.Issues, &exportedIssue{
+			ID:       issue.ID,
+			RepoID:   issue.RepoID,
+			Index:    issue.Index,
+			PosterID: issue.PosterID,
+			IsPull:   issue.IsPull,
+			Title:    issue.Title,
+			Content:  issue.Content,
+			Created:  issue.CreatedUnix.AsTime(),
+			Updated:  issue.UpdatedUnix.AsTime(),
+		})
+	}
+	for _, comment := range held.Comments {
+		content.Comments = append(content.Comments, &exportedComment{
+			ID:       comment.ID,
+			IssueID:  comment.IssueID,
+			PosterID: comment.PosterID,
+			Type:     int(comment.Type),
+			Content:  comment.Content,
+			Created:  comment.CreatedUnix.AsTime(),
+			Updated:  comment.UpdatedUnix.AsTime(),
+		})
+	}
+	for _, rev := range held.Revisions {
+		content.Revisions = append(content.Revisions, &exportedRevision{
+			ID:        rev.ID,
+			RepoID:    rev.RepoID,
+			IssueID:   rev.IssueID,
+			CommentID: rev.CommentID,
+			PosterID:  rev.PosterID,
+			DoerID:    rev.DoerID,
+			Content:   rev.Content,
+			IsDeleted: rev.IsDeleted,
+			Created:   rev.CreatedUnix.AsTime(),
+		})
+	}
+
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	f, err := archive.Create(ContentFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	f, err = archive.Create(SignatureFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, Sign(data)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
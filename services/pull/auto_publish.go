@@ -0,0 +1,186 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// SetAutoPublish marks a work in progress pull request ready for review once the status checks of
+// its head commit pass, the reviewers are requested by the doer then. It is published right away
+// if its status checks already pass.
+func SetAutoPublish(pr *models.PullRequest, doer *models.User, reviewers []*models.User, teamReviewers []*models.Team) error {
+	ap := &models.PullAutoPublish{
+		RepoID:          pr.BaseRepoID,
+		PullID:          pr.ID,
+		DoerID:          doer.ID,
+		Doer:            doer,
+		ReviewerIDs:     make([]int64, 0, len(reviewers)),
+		Reviewers:       reviewers,
+		TeamReviewerIDs: make([]int64, 0, len(teamReviewers)),
+		TeamReviewers:   teamReviewers,
+	}
+	for _, reviewer := range reviewers {
+		ap.ReviewerIDs = append(ap.ReviewerIDs, reviewer.ID)
+	}
+	for _, team := range teamReviewers {
+		ap.TeamReviewerIDs = append(ap.TeamReviewerIDs, team.ID)
+	}
+	if err := models.SetPullAutoPublish(ap); err != nil {
+		return err
+	}
+
+	sha, err := getPullHeadCommitID(pr)
+	if err != nil {
+		return err
+	}
+	return autoPublish(pr, ap, sha)
+}
+
+// ProcessAutoPublish publishes the pull requests of a repository waiting for the status checks of
+// a commit to pass, e.g. when a status of the commit is created
+func ProcessAutoPublish(repo *models.Repository, sha string) error {
+	aps, err := models.GetPullAutoPublishesByRepoID(repo.ID)
+	if err != nil || len(aps) == 0 {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	for _, ap := range aps {
+		pr, err := models.GetPullRequestByID(ap.PullID)
+		if err != nil {
+			if models.IsErrPullRequestNotExist(err) {
+				if err := models.DeletePullAutoPublish(ap.PullID); err != nil && !models.IsErrPullAutoPublishNotExist(err) {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+		if err != nil {
+			log.Error("GetRefCommitID[%s]: %v", pr.GetGitRefName(), err)
+			continue
+		}
+		if headCommitID != sha {
+			continue
+		}
+		if err := autoPublish(pr, ap, sha); err != nil {
+			return fmt.Errorf("autoPublish[%d]: %v", pr.ID, err)
+		}
+	}
+	return nil
+}
+
+// getPullHeadCommitID returns the head commit of a pull request in its base repository
+func getPullHeadCommitID(pr *models.PullRequest) (string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", err
+	}
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+	return gitRepo.GetRefCommitID(pr.GetGitRefName())
+}
+
+// isHeadCommitStatusPass returns whether the statuses of the head commit of a pull request pass the
+// status checks required by its base branch, or are successful if it requires none
+func isHeadCommitStatusPass(pr *models.PullRequest, sha string) (bool, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return false, err
+	}
+	var requiredContexts []string
+	if pr.ProtectedBranch != nil && pr.ProtectedBranch.EnableStatusCheck {
+		requiredContexts = pr.ProtectedBranch.StatusCheckContexts
+	}
+
+	commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepo, sha, 0)
+	if err != nil {
+		return false, err
+	}
+	return IsCommitStatusContextSuccess(commitStatuses, requiredContexts), nil
+}
+
+// autoPublish publishes a pull request if the status checks of its head commit pass
+func autoPublish(pr *models.PullRequest, ap *models.PullAutoPublish, sha string) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.Issue.IsClosed || pr.HasMerged || !pr.IsWorkInProgress() {
+		// the pull request was closed or published in the meantime
+		if err := models.DeletePullAutoPublish(pr.ID); err != nil && !models.IsErrPullAutoPublishNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	pass, err := isHeadCommitStatusPass(pr, sha)
+	if err != nil || !pass {
+		return err
+	}
+
+	// the statuses of a commit can be created at the same time, only one of them publishes the pull request
+	if err := models.DeletePullAutoPublish(pr.ID); err != nil {
+		if models.IsErrPullAutoPublishNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := ap.LoadAttributes(); err != nil {
+		return err
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		return err
+	}
+
+	prefix := pr.GetWorkInProgressPrefix()
+	if err := issue_service.ChangeTitle(pr.Issue, ap.Doer, strings.TrimSpace(pr.Issue.Title[len(prefix):])); err != nil {
+		return err
+	}
+	log.Trace("PR[%d] published automatically for %s", pr.ID, ap.Doer.Name)
+
+	// the reviewers which can't be requested anymore are skipped
+	for _, reviewer := range ap.Reviewers {
+		if err := issue_service.IsValidReviewRequest(reviewer, ap.Doer, true, pr.Issue, nil); err != nil {
+			if models.IsErrNotValidReviewRequest(err) {
+				log.Debug("Skip the review request of %s on PR[%d]: %v", reviewer.Name, pr.ID, err)
+				continue
+			}
+			return err
+		}
+		if _, err := issue_service.ReviewRequest(pr.Issue, ap.Doer, reviewer, true); err != nil {
+			return err
+		}
+	}
+	for _, team := range ap.TeamReviewers {
+		if err := issue_service.IsValidTeamReviewRequest(team, ap.Doer, true, pr.Issue); err != nil {
+			if models.IsErrNotValidReviewRequest(err) {
+				log.Debug("Skip the review request of team %s on PR[%d]: %v", team.Name, pr.ID, err)
+				continue
+			}
+			return err
+		}
+		if _, err := issue_service.TeamReviewRequest(pr.Issue, ap.Doer, team, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
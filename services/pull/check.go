@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
 
 	"github.com/unknwon/com"
 )
@@ -171,6 +172,8 @@ func manuallyMerged(pr *models.PullRequest) bool {
 		}
 
 		notification.NotifyMergePullRequest(pr, merger)
+		retargetStackedPullRequests(pr, merger)
+		issue_service.ExpireReviewEnvironments(merger, pr)
 
 		log.Info("manuallyMerged[%d]: Marked as manually merged into %s/%s by commit id: %s", pr.ID, pr.BaseRepo.Name, pr.BaseBranch, commit.ID.String())
 		return true
@@ -242,7 +245,7 @@ func CheckPrsForBaseBranch(baseRepo *models.Repository, baseBranchName string) e
 	return nil
 }
 
-// Init runs the task queue to test all the checking status pull requests
+// Init runs the task queues to test all the checking status pull requests and to process the merge queues
 func Init() error {
 	prQueue = queue.CreateUniqueQueue("pr_patch_checker", handle, "").(queue.UniqueQueue)
 
@@ -250,7 +253,15 @@ func Init() error {
 		return fmt.Errorf("Unable to create pr_patch_checker Queue")
 	}
 
+	mergeQueue = queue.CreateUniqueQueue("pr_merge_queue", handleMergeQueues, "").(queue.UniqueQueue)
+
+	if mergeQueue == nil {
+		return fmt.Errorf("Unable to create pr_merge_queue Queue")
+	}
+
 	go graceful.GetManager().RunWithShutdownFns(prQueue.Run)
+	go graceful.GetManager().RunWithShutdownFns(mergeQueue.Run)
 	go graceful.GetManager().RunWithShutdownContext(InitializePullRequests)
+	go graceful.GetManager().RunWithShutdownContext(initializeMergeQueues)
 	return nil
 }
@@ -0,0 +1,177 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/codeowners"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// maxCodeOwnersSize is the size from which a CODEOWNERS file is truncated
+const maxCodeOwnersSize = 3 * 1024 * 1024
+
+// GetCodeOwnersRules returns the rules of the CODEOWNERS file of a commit, nil if it has none
+func GetCodeOwnersRules(commit *git.Commit) ([]*codeowners.Rule, error) {
+	for _, treePath := range codeowners.Paths {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !entry.IsRegular() {
+			continue
+		}
+		reader, err := entry.Blob().DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(reader, maxCodeOwnersSize))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		return codeowners.Parse(string(content)), nil
+	}
+	return nil, nil
+}
+
+// GetFilesMissingCodeOwnerApproval returns the files changed by a pull request which are owned in the
+// CODEOWNERS file of its base branch but approved by none of their owners
+func GetFilesMissingCodeOwnerApproval(pr *models.PullRequest) ([]string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	baseCommit, err := gitRepo.GetBranchCommit(pr.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := GetCodeOwnersRules(baseCommit)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	files, err := gitRepo.GetFilesChangedBetween(baseCommit.ID.String(), pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	approverIDs, err := getApproverIDs(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	// owners are shared by many files, resolve each once
+	approvedOwners := make(map[string]bool)
+	var missing []string
+	for _, treePath := range files {
+		owners := codeowners.Owners(rules, treePath)
+		if len(owners) == 0 {
+			continue
+		}
+		approved := false
+		for _, owner := range owners {
+			ok, has := approvedOwners[owner]
+			if !has {
+				if ok, err = isCodeOwnerApproved(owner, approverIDs); err != nil {
+					return nil, err
+				}
+				approvedOwners[owner] = ok
+			}
+			if ok {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			missing = append(missing, treePath)
+		}
+	}
+	return missing, nil
+}
+
+// getApproverIDs returns the users who approved a pull request, the stale approvals don't count
+// when its protected branch dismisses them
+func getApproverIDs(pr *models.PullRequest) (map[int64]bool, error) {
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return nil, err
+	}
+	dismissStale := pr.ProtectedBranch != nil && pr.ProtectedBranch.DismissStaleApprovals
+	approverIDs := make(map[int64]bool, len(reviews))
+	for _, review := range reviews {
+		if review.Type == models.ReviewTypeApprove && review.ReviewerTeamID == 0 && !(dismissStale && review.Stale) {
+			approverIDs[review.ReviewerID] = true
+		}
+	}
+	return approverIDs, nil
+}
+
+// isCodeOwnerApproved returns whether an owner of a CODEOWNERS file is one of the approvers,
+// a team is approved by any of its members. Owners which don't exist never approve.
+func isCodeOwnerApproved(owner string, approverIDs map[int64]bool) (bool, error) {
+	if len(approverIDs) == 0 {
+		return false, nil
+	}
+
+	if !strings.HasPrefix(owner, "@") {
+		u, err := models.GetUserByEmail(owner)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return approverIDs[u.ID], nil
+	}
+
+	owner = owner[1:]
+	if i := strings.IndexByte(owner, '/'); i >= 0 {
+		org, err := models.GetUserByName(owner[:i])
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		team, err := models.GetTeam(org.ID, owner[i+1:])
+		if err != nil {
+			if models.IsErrTeamNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		for approverID := range approverIDs {
+			if team.IsMember(approverID) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	u, err := models.GetUserByName(owner)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return approverIDs[u.ID], nil
+}
@@ -86,6 +86,10 @@ func IsPullCommitStatusPass(pr *models.PullRequest) (bool, error) {
 	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableStatusCheck {
 		return true, nil
 	}
+	if pr.ProtectedBranch.EnableMergeQueue {
+		// the status checks of merge queues are run on the merges they test
+		return isMergeQueueTestPass(pr)
+	}
 
 	state, err := GetPullRequestCommitStatusState(pr)
 	if err != nil {
@@ -0,0 +1,160 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"io"
+	"path"
+	"sort"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// FileTreeNode is a directory or a changed file of the tree of the files changed by a pull request,
+// the changes of a directory sum up the changes of the files it contains
+type FileTreeNode struct {
+	Name  string
+	Path  string
+	IsDir bool
+	// Status is the status of a file, e.g. added, modified, deleted or renamed
+	Status string
+	// OldPath is the previous path of a renamed or copied file
+	OldPath   string
+	IsBinary  bool
+	Additions int
+	Deletions int
+	// Files is the number of changed files of a directory
+	Files    int
+	Children []*FileTreeNode
+}
+
+// diffFileStatuses maps the status letters of git to the statuses of the changed files
+var diffFileStatuses = map[byte]string{
+	'A': "added",
+	'C': "copied",
+	'D': "deleted",
+	'M': "modified",
+	'R': "renamed",
+	'T': "changed",
+}
+
+// getDiffFileStats returns the files changed by a pull request since its merge base
+func getDiffFileStats(pr *models.PullRequest) ([]*git.DiffFileStat, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	return gitRepo.GetDiffFileStats(pr.MergeBase, pr.GetGitRefName())
+}
+
+// GetFileTree returns the root of the tree of the files changed by a pull request, the directories
+// are listed before the files of a directory
+func GetFileTree(pr *models.PullRequest) (*FileTreeNode, error) {
+	stats, err := getDiffFileStats(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &FileTreeNode{IsDir: true}
+	dirs := map[string]*FileTreeNode{"": root}
+	var getDir func(dirPath string) *FileTreeNode
+	getDir = func(dirPath string) *FileTreeNode {
+		if dir, ok := dirs[dirPath]; ok {
+			return dir
+		}
+		parent := getDir(parentDir(dirPath))
+		dir := &FileTreeNode{Name: path.Base(dirPath), Path: dirPath, IsDir: true}
+		parent.Children = append(parent.Children, dir)
+		dirs[dirPath] = dir
+		return dir
+	}
+
+	for _, stat := range stats {
+		file := &FileTreeNode{
+			Name:      path.Base(stat.Name),
+			Path:      stat.Name,
+			Status:    diffFileStatuses[stat.Status],
+			OldPath:   stat.OldName,
+			IsBinary:  stat.IsBinary,
+			Additions: stat.Additions,
+			Deletions: stat.Deletions,
+		}
+		if len(file.Status) == 0 {
+			file.Status = "modified"
+		}
+		dirPath := parentDir(stat.Name)
+		dir := getDir(dirPath)
+		dir.Children = append(dir.Children, file)
+
+		// the changes of the file are summed up in all its parent directories
+		for {
+			dir := dirs[dirPath]
+			dir.Files++
+			dir.Additions += stat.Additions
+			dir.Deletions += stat.Deletions
+			if len(dirPath) == 0 {
+				break
+			}
+			dirPath = parentDir(dirPath)
+		}
+	}
+
+	sortFileTree(root)
+	return root, nil
+}
+
+// parentDir returns the parent directory of a path, the root is an empty string
+func parentDir(treePath string) string {
+	if dir := path.Dir(treePath); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+func sortFileTree(node *FileTreeNode) {
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		if node.Children[i].IsDir != node.Children[j].IsDir {
+			return node.Children[i].IsDir
+		}
+		return node.Children[i].Name < node.Children[j].Name
+	})
+	for _, child := range node.Children {
+		if child.IsDir {
+			sortFileTree(child)
+		}
+	}
+}
+
+// DownloadFileDiff writes the diff of a file changed by a pull request, so the diffs of the files of
+// large pull requests can be loaded one by one. It returns a git.ErrNotExist if the file isn't changed.
+// The args are passed to git diff.
+func DownloadFileDiff(pr *models.PullRequest, treePath string, w io.Writer, args ...string) error {
+	stats, err := getDiffFileStats(pr)
+	if err != nil {
+		return err
+	}
+	for _, stat := range stats {
+		if stat.Name != treePath {
+			continue
+		}
+		files := []string{stat.Name}
+		if len(stat.OldName) > 0 {
+			files = append(files, stat.OldName)
+		}
+
+		gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+		if err != nil {
+			return err
+		}
+		defer gitRepo.Close()
+		return gitRepo.GetDiffOfFiles(pr.MergeBase, pr.GetGitRefName(), w, files, args...)
+	}
+	return git.ErrNotExist{ID: pr.GetGitRefName(), RelPath: treePath}
+}
@@ -51,6 +51,15 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
 	}
 
+	// Check if merge style keeps the history of the protected branch linear
+	if err = pr.LoadProtectedBranch(); err != nil {
+		log.Error("LoadProtectedBranch: %v", err)
+		return fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	if pr.ProtectedBranch != nil && !pr.ProtectedBranch.IsMergeStyleAllowed(mergeStyle) {
+		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
 	defer func() {
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
 	}()
@@ -81,9 +90,17 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 
 	notification.NotifyMergePullRequest(pr, doer)
 
+	retargetStackedPullRequests(pr, doer)
+	issue_service.ExpireReviewEnvironments(doer, pr)
+
 	// Reset cached commit count
 	cache.Remove(pr.Issue.Repo.GetCommitsCountCacheKey(pr.BaseBranch, true))
 
+	// The repository can only let the pull requests merged into its default branch close or reopen issues
+	if pr.BaseBranch != pr.BaseRepo.DefaultBranch && pr.BaseRepo.IssuesConfig().CloseOnDefaultBranchOnly {
+		return nil
+	}
+
 	// Resolve cross references
 	refs, err := pr.ResolveCrossReferences()
 	if err != nil {
@@ -98,6 +115,9 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 		if err = ref.Issue.LoadRepo(); err != nil {
 			return err
 		}
+		if ref.Issue.RepoID != pr.BaseRepoID && ref.Issue.Repo.IssuesConfig().DisableCrossRepoClose {
+			continue
+		}
 		close := (ref.RefAction == references.XRefActionCloses)
 		if close != ref.Issue.IsClosed {
 			if err = issue_service.ChangeStatus(ref.Issue, doer, close); err != nil {
@@ -111,6 +131,12 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 
 // rawMerge perform the merge operation without changing any pull information in database
 func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.MergeStyle, message string) (string, error) {
+	return rawMergeTo(pr, doer, mergeStyle, message, pr.BaseBranch)
+}
+
+// rawMergeTo performs the merge operation like rawMerge but pushes the merge to the target branch,
+// which is overwritten if it isn't the base branch
+func rawMergeTo(pr *models.PullRequest, doer *models.User, mergeStyle models.MergeStyle, message, targetBranch string) (string, error) {
 	err := git.LoadGitVersion()
 	if err != nil {
 		log.Error("git.LoadGitVersion: %v", err)
@@ -214,10 +240,12 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 
 	// Determine if we should sign
 	signArg := ""
+	var signingKey *git.SigningKey
 	if git.CheckGitVersionAtLeast("1.7.9") == nil {
-		sign, keyID, signer, _ := pr.SignMerge(doer, tmpBasePath, "HEAD", trackingBranch)
+		sign, key, signer, _ := pr.SignMerge(doer, tmpBasePath, "HEAD", trackingBranch)
 		if sign {
-			signArg = "-S" + keyID
+			signArg = "-S" + key.KeyID
+			signingKey = key
 			if pr.BaseRepo.GetTrustModel() == models.CommitterTrustModel || pr.BaseRepo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
 				committer = signer
 			}
@@ -237,6 +265,7 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 		"GIT_COMMITTER_EMAIL="+committer.Email,
 		"GIT_COMMITTER_DATE="+commitTimeStr,
 	)
+	env = append(env, signingKey.Env()...)
 
 	// Merge commits.
 	switch mergeStyle {
@@ -411,7 +440,11 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 	)
 
 	// Push back to upstream.
-	if err := git.NewCommand("push", "origin", baseBranch+":"+pr.BaseBranch).RunInDirTimeoutEnvPipeline(env, -1, tmpBasePath, &outbuf, &errbuf); err != nil {
+	pushCmd := git.NewCommand("push", "origin", baseBranch+":"+targetBranch)
+	if targetBranch != pr.BaseBranch {
+		pushCmd = git.NewCommand("push", "-f", "origin", baseBranch+":"+git.BranchPrefix+targetBranch)
+	}
+	if err := pushCmd.RunInDirTimeoutEnvPipeline(env, -1, tmpBasePath, &outbuf, &errbuf); err != nil {
 		if strings.Contains(errbuf.String(), "non-fast-forward") {
 			return "", &git.ErrPushOutOfDate{
 				StdOut: outbuf.String(),
@@ -581,6 +614,22 @@ func CheckPRReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) (
 		}
 	}
 
+	missingSignOff, err := GetCommitsMissingSignOff(pr)
+	if err != nil {
+		return fmt.Errorf("GetCommitsMissingSignOff: %v", err)
+	}
+	if len(missingSignOff) > 0 {
+		return models.ErrNotAllowedToMerge{
+			Reason: "Not all commits are signed off by their author",
+		}
+	}
+
+	return checkPRReviewsReadyToMerge(pr, skipProtectedFilesCheck)
+}
+
+// checkPRReviewsReadyToMerge checks whether the reviews and the changes of the PR allow it to be merged
+// into its protected branch
+func checkPRReviewsReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) error {
 	if !pr.ProtectedBranch.HasEnoughApprovals(pr) {
 		return models.ErrNotAllowedToMerge{
 			Reason: "Does not have enough approvals",
@@ -596,6 +645,28 @@ func CheckPRReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) (
 			Reason: "There are official review requests",
 		}
 	}
+	if pr.ProtectedBranch.RequireCodeOwnerApproval {
+		files, err := GetFilesMissingCodeOwnerApproval(pr)
+		if err != nil {
+			return fmt.Errorf("GetFilesMissingCodeOwnerApproval: %v", err)
+		}
+		if len(files) > 0 {
+			return models.ErrNotAllowedToMerge{
+				Reason: "Changed files are not approved by their code owners",
+			}
+		}
+	}
+	if len(pr.ProtectedBranch.ProtectedPathRules) > 0 {
+		unapproved, err := GetUnapprovedProtectedPaths(pr)
+		if err != nil {
+			return fmt.Errorf("GetUnapprovedProtectedPaths: %v", err)
+		}
+		if len(unapproved) > 0 {
+			return models.ErrNotAllowedToMerge{
+				Reason: protectedPathsNotApprovedReason(unapproved),
+			}
+		}
+	}
 
 	if pr.ProtectedBranch.MergeBlockedByOutdatedBranch(pr) {
 		return models.ErrNotAllowedToMerge{
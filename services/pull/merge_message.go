@@ -0,0 +1,132 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/references"
+)
+
+// GetDefaultMergeMessage returns the default title and body of the message of a merge style of a pull request,
+// they are expanded from the template of the merge style of the repository when it has one
+func GetDefaultMergeMessage(pr *models.PullRequest, mergeStyle models.MergeStyle) (string, string, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return "", "", err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", "", err
+	}
+	unit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return "", "", err
+	}
+
+	template := unit.PullRequestsConfig().GetMergeMessageTemplate(mergeStyle)
+	if len(template) == 0 {
+		if mergeStyle == models.MergeStyleSquash {
+			return pr.GetDefaultSquashMessage(), "", nil
+		}
+		return pr.GetDefaultMergeMessage(), "", nil
+	}
+
+	if err := pr.LoadHeadRepo(); err != nil {
+		return "", "", err
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return "", "", err
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		return "", "", err
+	}
+
+	message := os.Expand(strings.Replace(template, "\r\n", "\n", -1), mergeMessageVars(pr))
+	title, body := message, ""
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		title, body = message[:i], message[i+1:]
+	}
+	return strings.TrimSpace(title), strings.TrimSpace(body), nil
+}
+
+// mergeMessageVars returns the mapping of the variables of the templates of merge messages to their values,
+// the commits of the pull request are only read when the template uses them
+func mergeMessageVars(pr *models.PullRequest) func(string) string {
+	var commitsRead bool
+	var commitMessages string
+	var authors []string
+	readCommits := func() {
+		if !commitsRead {
+			commitMessages, authors = getCommitMessagesAndAuthors(pr)
+			commitsRead = true
+		}
+	}
+
+	return func(name string) string {
+		switch name {
+		case "BaseRepoOwnerName":
+			return pr.BaseRepo.OwnerName
+		case "BaseRepoName":
+			return pr.BaseRepo.Name
+		case "BaseBranch":
+			return pr.BaseBranch
+		case "HeadRepoOwnerName":
+			if pr.HeadRepo != nil {
+				return pr.HeadRepo.OwnerName
+			}
+		case "HeadRepoName":
+			if pr.HeadRepo != nil {
+				return pr.HeadRepo.Name
+			}
+		case "HeadBranch":
+			return pr.HeadBranch
+		case "PullRequestTitle":
+			return pr.Issue.Title
+		case "PullRequestDescription":
+			return pr.Issue.Content
+		case "PullRequestPosterName":
+			return pr.Issue.Poster.Name
+		case "PullRequestIndex":
+			return fmt.Sprint(pr.Issue.Index)
+		case "PullRequestReference":
+			if pr.BaseRepo.UnitEnabled(models.UnitTypeExternalTracker) {
+				return fmt.Sprintf("!%d", pr.Issue.Index)
+			}
+			return fmt.Sprintf("#%d", pr.Issue.Index)
+		case "ClosingIssues":
+			return closingIssues(pr.Issue.Content, pr.BaseRepo.IssuesConfig().Keywords())
+		case "ReviewedOn":
+			return "Reviewed-on: " + pr.Issue.HTMLURL()
+		case "ReviewedBy":
+			return strings.TrimSpace(pr.GetApprovers())
+		case "CommitMessages":
+			readCommits()
+			return strings.TrimSpace(commitMessages)
+		case "CoAuthors":
+			readCommits()
+			return strings.TrimSpace(coAuthoredBy(authors))
+		}
+		return ""
+	}
+}
+
+// closingIssues returns the issues closed by the references of the description of a pull request,
+// e.g. "close #1, close user/repo#2"
+func closingIssues(content string, keywords *references.Keywords) string {
+	closing := make([]string, 0, 2)
+	for _, ref := range references.FindAllIssueReferencesWithKeywords(content, keywords) {
+		if ref.Action != references.XRefActionCloses {
+			continue
+		}
+		if len(ref.Owner) > 0 {
+			closing = append(closing, fmt.Sprintf("close %s/%s#%d", ref.Owner, ref.Name, ref.Index))
+		} else {
+			closing = append(closing, fmt.Sprintf("close #%d", ref.Index))
+		}
+	}
+	return strings.Join(closing, ", ")
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDefaultMergeMessage(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+
+	title, body, err := GetDefaultMergeMessage(pr, models.MergeStyleSquash)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue2 (#2)", title)
+	assert.Empty(t, body)
+
+	assert.NoError(t, pr.LoadBaseRepo())
+	assert.NoError(t, models.UpdateRepositoryUnits(pr.BaseRepo, []models.RepoUnit{{
+		RepoID: pr.BaseRepo.ID,
+		Type:   models.UnitTypePullRequests,
+		Config: &models.PullRequestsConfig{
+			AllowSquash:           true,
+			SquashMessageTemplate: "${PullRequestTitle} (${PullRequestReference})\n\n${PullRequestDescription}\n\nMerge ${HeadBranch} into ${BaseRepoOwnerName}/${BaseRepoName}:${BaseBranch}\n${Unknown}${ClosingIssues}",
+		},
+	}}, nil))
+	pr.BaseRepo = nil
+	pr.Issue.Content = "Fixes #1, closes user2/repo2#3 and mentions #4"
+
+	title, body, err = GetDefaultMergeMessage(pr, models.MergeStyleSquash)
+	assert.NoError(t, err)
+	assert.Equal(t, "issue2 (#2)", title)
+	assert.Equal(t, "Fixes #1, closes user2/repo2#3 and mentions #4\n\nMerge branch1 into user2/repo1:master\nclose #1, close user2/repo2#3", body)
+
+	// the merge style has no template
+	title, body, err = GetDefaultMergeMessage(pr, models.MergeStyleMerge)
+	assert.NoError(t, err)
+	assert.Equal(t, "Merge pull request 'issue2' (#2) from branch1 into master", title)
+	assert.Empty(t, body)
+}
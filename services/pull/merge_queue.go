@@ -0,0 +1,298 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/sync"
+)
+
+// mergeQueue represents a queue of the merge queues of the branches to process
+var mergeQueue queue.UniqueQueue
+
+// mergeQueueWorkingPool prevents a merge queue from being processed twice at the same time
+var mergeQueueWorkingPool = sync.NewExclusivePool()
+
+func mergeQueueKey(repoID int64, branch string) string {
+	// ':' is not allowed in branch names
+	return fmt.Sprintf("%d:%s", repoID, branch)
+}
+
+// AddToMergeQueueTaskQueue schedules the processing of the merge queue of a branch
+func AddToMergeQueueTaskQueue(repoID int64, branch string) {
+	if err := mergeQueue.Push(mergeQueueKey(repoID, branch)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding the merge queue of %s in repo %d to the merge queues queue: %v", branch, repoID, err)
+	}
+}
+
+// AddToMergeQueue adds a pull request to the merge queue of its base branch, the pull request is merged
+// once it's first in the queue and its merge into the base branch passes the required status checks
+func AddToMergeQueue(pr *models.PullRequest, doer *models.User, mergeStyle models.MergeStyle, message string) error {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return err
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableMergeQueue {
+		return models.ErrNotAllowedToMerge{
+			Reason: fmt.Sprintf("Branch %s has no merge queue", pr.BaseBranch),
+		}
+	}
+
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return err
+	}
+	if !prUnit.PullRequestsConfig().IsMergeStyleAllowed(mergeStyle) || !pr.ProtectedBranch.IsMergeStyleAllowed(mergeStyle) {
+		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
+	// the status checks are run on the merge tested by the queue
+	if err := checkPRReviewsReadyToMerge(pr, false); err != nil {
+		return err
+	}
+
+	if err := models.AddToMergeQueue(&models.MergeQueueEntry{
+		RepoID:     pr.BaseRepoID,
+		BaseBranch: pr.BaseBranch,
+		PullID:     pr.ID,
+		DoerID:     doer.ID,
+		MergeStyle: mergeStyle,
+		Message:    message,
+	}); err != nil {
+		return err
+	}
+	AddToMergeQueueTaskQueue(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// RemoveFromMergeQueue removes a pull request from the merge queue of its base branch
+func RemoveFromMergeQueue(pr *models.PullRequest) error {
+	if err := models.RemoveFromMergeQueue(pr.ID); err != nil {
+		return err
+	}
+	AddToMergeQueueTaskQueue(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// ProcessMergeQueuesTestingCommit schedules the processing of the merge queues testing a commit,
+// e.g. when a status of the commit is created
+func ProcessMergeQueuesTestingCommit(repo *models.Repository, sha string) error {
+	entries, err := models.GetMergeQueueEntriesByTestSHA(repo.ID, sha)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		AddToMergeQueueTaskQueue(entry.RepoID, entry.BaseBranch)
+	}
+	return nil
+}
+
+// isMergeQueueTestPass returns whether the merge of a pull request tested by the merge queue of its
+// base branch passes the required status checks
+func isMergeQueueTestPass(pr *models.PullRequest) (bool, error) {
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if entry.Status != models.MergeQueueStatusTesting {
+		return false, nil
+	}
+
+	commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepo, entry.TestSHA, 0)
+	if err != nil {
+		return false, err
+	}
+	return MergeRequiredContextsCommitStatus(commitStatuses, pr.ProtectedBranch.StatusCheckContexts).IsSuccess(), nil
+}
+
+func failMergeQueueEntry(entry *models.MergeQueueEntry, reason string) error {
+	log.Trace("PR[%d] failed in the merge queue of %s: %s", entry.PullID, entry.BaseBranch, reason)
+	entry.Status = models.MergeQueueStatusFailed
+	entry.FailReason = reason
+	return models.UpdateMergeQueueEntryCols(entry, "status", "fail_reason")
+}
+
+// mergeFailReason returns the reason to report if err means the pull request can't be merged
+func mergeFailReason(err error) (string, bool) {
+	switch {
+	case models.IsErrMergeConflicts(err), models.IsErrRebaseConflicts(err):
+		return "Merge conflicts with the base branch", true
+	case models.IsErrMergeUnrelatedHistories(err):
+		return "Unrelated histories", true
+	case models.IsErrNotAllowedToMerge(err):
+		return err.(models.ErrNotAllowedToMerge).Reason, true
+	case models.IsErrInvalidMergeStyle(err):
+		return fmt.Sprintf("Merge style %s is not allowed", err.(models.ErrInvalidMergeStyle).Style), true
+	case git.IsErrPushRejected(err):
+		return "Push rejected: " + err.(*git.ErrPushRejected).Message, true
+	}
+	return "", false
+}
+
+// processMergeQueue tests and merges the pull requests of the merge queue of a branch in order
+func processMergeQueue(repoID int64, branch string) {
+	key := mergeQueueKey(repoID, branch)
+	mergeQueueWorkingPool.CheckIn(key)
+	defer mergeQueueWorkingPool.CheckOut(key)
+
+	for {
+		entries, err := models.GetMergeQueueEntries(repoID, branch)
+		if err != nil {
+			log.Error("GetMergeQueueEntries[%d, %s]: %v", repoID, branch, err)
+			return
+		}
+		if len(entries) == 0 || entries[0].Status == models.MergeQueueStatusFailed {
+			return
+		}
+		next, err := processMergeQueueEntry(entries[0])
+		if err != nil {
+			log.Error("processMergeQueueEntry[%d]: %v", entries[0].PullID, err)
+			return
+		} else if !next {
+			return
+		}
+	}
+}
+
+// processMergeQueueEntry tests or merges the first pull request of a merge queue,
+// it returns whether the pull request left the queue
+func processMergeQueueEntry(entry *models.MergeQueueEntry) (bool, error) {
+	if err := entry.LoadAttributes(); err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			return true, models.RemoveFromMergeQueue(entry.PullID)
+		}
+		return false, err
+	}
+	pr := entry.Pull
+	if err := pr.LoadIssue(); err != nil {
+		return false, err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed || pr.BaseBranch != entry.BaseBranch {
+		return true, models.RemoveFromMergeQueue(pr.ID)
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return false, err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return false, err
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableMergeQueue {
+		return true, failMergeQueueEntry(entry, fmt.Sprintf("Branch %s has no merge queue", pr.BaseBranch))
+	}
+
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return false, err
+	}
+	defer baseGitRepo.Close()
+	baseSHA, err := baseGitRepo.GetBranchCommitID(pr.BaseBranch)
+	if err != nil {
+		return false, err
+	}
+	headSHA, err := baseGitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return false, err
+	}
+
+	// (re)test the merge when the base branch or the pull request changed
+	if entry.Status != models.MergeQueueStatusTesting || entry.TestBaseSHA != baseSHA || entry.TestHeadSHA != headSHA {
+		testSHA, err := rawMergeTo(pr, entry.Doer, entry.MergeStyle, entry.Message, entry.TestBranch())
+		if err != nil {
+			if reason, ok := mergeFailReason(err); ok {
+				return true, failMergeQueueEntry(entry, reason)
+			}
+			return false, err
+		}
+		entry.Status = models.MergeQueueStatusTesting
+		entry.TestBaseSHA = baseSHA
+		entry.TestHeadSHA = headSHA
+		entry.TestSHA = testSHA
+		if err := models.UpdateMergeQueueEntryCols(entry, "status", "test_base_sha", "test_head_sha", "test_sha"); err != nil {
+			return false, err
+		}
+	}
+
+	if pr.ProtectedBranch.EnableStatusCheck {
+		commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepo, entry.TestSHA, 0)
+		if err != nil {
+			return false, err
+		}
+		state := MergeRequiredContextsCommitStatus(commitStatuses, pr.ProtectedBranch.StatusCheckContexts)
+		if state.IsPending() {
+			return false, nil
+		} else if !state.IsSuccess() {
+			return true, failMergeQueueEntry(entry, fmt.Sprintf("The required status checks of %s are %s", entry.TestSHA, state))
+		}
+	}
+
+	if err := CheckPRReadyToMerge(pr, false); err != nil {
+		if reason, ok := mergeFailReason(err); ok {
+			return true, failMergeQueueEntry(entry, reason)
+		}
+		return false, err
+	}
+	if err := Merge(pr, entry.Doer, baseGitRepo, entry.MergeStyle, entry.Message); err != nil {
+		if git.IsErrPushOutOfDate(err) {
+			// the base branch changed since the merge was tested, test it again
+			return true, nil
+		} else if reason, ok := mergeFailReason(err); ok {
+			return true, failMergeQueueEntry(entry, reason)
+		}
+		return false, err
+	}
+	log.Trace("PR[%d] merged by the merge queue of %s", pr.ID, pr.BaseBranch)
+	return true, models.RemoveFromMergeQueue(pr.ID)
+}
+
+// handleMergeQueues processes the merge queues of the branches
+func handleMergeQueues(data ...queue.Data) {
+	for _, datum := range data {
+		key := datum.(string)
+		fields := strings.SplitN(key, ":", 2)
+		if len(fields) != 2 {
+			log.Error("Invalid merge queue key: %s", key)
+			continue
+		}
+		repoID, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			log.Error("Invalid merge queue key: %s", key)
+			continue
+		}
+		processMergeQueue(repoID, fields[1])
+	}
+}
+
+// initializeMergeQueues schedules the processing of the merge queues which have pull requests to merge
+func initializeMergeQueues(ctx context.Context) {
+	entries, err := models.GetMergeQueueEntriesToProcess()
+	if err != nil {
+		log.Error("GetMergeQueueEntriesToProcess: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			AddToMergeQueueTaskQueue(entry.RepoID, entry.BaseBranch)
+		}
+	}
+}
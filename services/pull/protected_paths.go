@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// UnapprovedProtectedPath represents a protected path rule of the base branch of a pull request whose
+// matching changed files are approved by no member of its team
+type UnapprovedProtectedPath struct {
+	Rule  *models.ProtectedPathRule
+	Team  *models.Team
+	Files []string
+}
+
+// GetUnapprovedProtectedPaths returns the protected path rules of the base branch of a pull request which
+// are matched by its changed files but not approved yet
+func GetUnapprovedProtectedPaths(pr *models.PullRequest) ([]*UnapprovedProtectedPath, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, err
+	}
+	if pr.ProtectedBranch == nil || len(pr.ProtectedBranch.ProtectedPathRules) == 0 {
+		return nil, nil
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	baseCommitID, err := gitRepo.GetBranchCommitID(pr.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+	files, err := gitRepo.GetFilesChangedBetween(baseCommitID, pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	approverIDs, err := getApproverIDs(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var unapproved []*UnapprovedProtectedPath
+	for _, rule := range pr.ProtectedBranch.ProtectedPathRules {
+		var matched []string
+		for _, treePath := range files {
+			if rule.Match(treePath) {
+				matched = append(matched, treePath)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		team, err := models.GetTeamByID(rule.TeamID)
+		if err != nil {
+			if !models.IsErrTeamNotExist(err) {
+				return nil, err
+			}
+			// nobody can approve the changes of a deleted team
+			team = &models.Team{ID: rule.TeamID, Name: fmt.Sprintf("#%d", rule.TeamID)}
+		} else if isTeamApproved(team, approverIDs) {
+			continue
+		}
+		unapproved = append(unapproved, &UnapprovedProtectedPath{
+			Rule:  rule,
+			Team:  team,
+			Files: matched,
+		})
+	}
+	return unapproved, nil
+}
+
+func isTeamApproved(team *models.Team, approverIDs map[int64]bool) bool {
+	for approverID := range approverIDs {
+		if team.IsMember(approverID) {
+			return true
+		}
+	}
+	return false
+}
+
+// protectedPathsNotApprovedReason returns why a pull request can't be merged because of its unapproved
+// protected paths
+func protectedPathsNotApprovedReason(unapproved []*UnapprovedProtectedPath) string {
+	rules := make([]string, 0, len(unapproved))
+	for _, u := range unapproved {
+		rules = append(rules, fmt.Sprintf("%s (team %s)", u.Rule.Pattern, u.Team.Name))
+	}
+	return "Changed files of protected paths are not approved by a member of their teams: " + strings.Join(rules, ", ")
+}
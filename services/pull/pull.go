@@ -150,6 +150,15 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 		return err
 	}
 
+	// A stacked pull request is unstacked when it no longer targets the head branch of its base pull request
+	if err := pr.LoadBasePull(); err != nil && !models.IsErrPullRequestNotExist(err) {
+		return err
+	}
+	if pr.BasePull != nil && pr.BasePull.HeadBranch != targetBranch {
+		pr.BasePullID = 0
+		pr.BasePull = nil
+	}
+
 	// Set new target branch
 	oldBranch := pr.BaseBranch
 	pr.BaseBranch = targetBranch
@@ -173,7 +182,7 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 	pr.CommitsAhead = divergence.Ahead
 	pr.CommitsBehind = divergence.Behind
 
-	if err := pr.UpdateColsIfNotMerged("merge_base", "status", "conflicted_files", "changed_protected_files", "base_branch", "commits_ahead", "commits_behind"); err != nil {
+	if err := pr.UpdateColsIfNotMerged("merge_base", "status", "conflicted_files", "changed_protected_files", "base_branch", "base_pull_id", "commits_ahead", "commits_behind"); err != nil {
 		return err
 	}
 
@@ -243,8 +252,14 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 						if err != nil {
 							log.Error("checkIfPRContentChanged: %v", err)
 						}
+						if !changed {
+							changed, err = checkIfPushDismissesApprovals(pr, oldCommitID, newCommitID)
+							if err != nil {
+								log.Error("checkIfPushDismissesApprovals: %v", err)
+							}
+						}
 						if changed {
-							// Mark old reviews as stale if diff to mergebase has changed
+							// Mark old reviews as stale if diff to mergebase has changed or the push dismisses them
 							if err := models.MarkReviewsAsStale(pr.IssueID); err != nil {
 								log.Error("MarkReviewsAsStale: %v", err)
 							}
@@ -277,6 +292,7 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 
 			AddToTaskQueue(pr)
+			AddToMergeQueueTaskQueue(pr.BaseRepoID, pr.BaseBranch)
 			comment, err := models.CreatePushPullComment(doer, pr, oldCommitID, newCommitID)
 			if err == nil && comment != nil {
 				notification.NotifyPullRequestPushCommits(doer, pr, comment)
@@ -301,9 +317,39 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 			AddToTaskQueue(pr)
 		}
+		AddToMergeQueueTaskQueue(repoID, branch)
 	})
 }
 
+// checkIfPushDismissesApprovals checks if the protected base branch of a pull request dismisses its approvals
+// when its head branch is pushed to or force-pushed to, even if the content of the pull request is the same
+func checkIfPushDismissesApprovals(pr *models.PullRequest, oldCommitID, newCommitID string) (bool, error) {
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return false, fmt.Errorf("LoadProtectedBranch: %v", err)
+	} else if pr.ProtectedBranch == nil || !pr.ProtectedBranch.DismissStaleApprovals {
+		return false, nil
+	}
+
+	if pr.ProtectedBranch.DismissApprovalsOnPush {
+		return true, nil
+	} else if !pr.ProtectedBranch.DismissApprovalsOnForcePush || oldCommitID == "" || oldCommitID == git.EmptySHA {
+		return false, nil
+	}
+
+	if err := pr.LoadHeadRepo(); err != nil {
+		return false, fmt.Errorf("LoadHeadRepo: %v", err)
+	} else if pr.HeadRepo == nil {
+		return false, nil
+	}
+
+	// The push was forced if the old head commit is no longer in the history of the new one
+	output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).RunInDir(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return false, fmt.Errorf("unable to detect force push between %s and %s: %v", oldCommitID, newCommitID, err)
+	}
+	return len(strings.TrimSpace(output)) > 0, nil
+}
+
 // checkIfPRContentChanged checks if diff to target branch has changed by push
 // A commit can be considered to leave the PR untouched if the patch/diff with its merge base is unchanged
 func checkIfPRContentChanged(pr *models.PullRequest, oldCommitID, newCommitID string) (hasChanged bool, err error) {
@@ -503,14 +549,35 @@ func CloseRepoBranchesPulls(doer *models.User, repo *models.Repository) error {
 
 // GetCommitMessages returns the commit messages between head and merge base (if there is one)
 func GetCommitMessages(pr *models.PullRequest) string {
+	messages, authors := getCommitMessagesAndAuthors(pr)
+	if len(authors) > 0 {
+		messages += "\n" + coAuthoredBy(authors)
+	}
+	return messages
+}
+
+// coAuthoredBy returns the co-authored-by trailers of the authors
+func coAuthoredBy(authors []string) string {
+	stringBuilder := strings.Builder{}
+	for _, author := range authors {
+		stringBuilder.WriteString("Co-authored-by: ")
+		stringBuilder.WriteString(author)
+		stringBuilder.WriteRune('\n')
+	}
+	return stringBuilder.String()
+}
+
+// getCommitMessagesAndAuthors returns the commit messages between head and merge base (if there is one)
+// and the authors of the commits other than the poster of the pull request
+func getCommitMessagesAndAuthors(pr *models.PullRequest) (string, []string) {
 	if err := pr.LoadIssue(); err != nil {
 		log.Error("Cannot load issue %d for PR id %d: Error: %v", pr.IssueID, pr.ID, err)
-		return ""
+		return "", nil
 	}
 
 	if err := pr.Issue.LoadPoster(); err != nil {
 		log.Error("Cannot load poster %d for pr id %d, index %d Error: %v", pr.Issue.PosterID, pr.ID, pr.Index, err)
-		return ""
+		return "", nil
 	}
 
 	if pr.HeadRepo == nil {
@@ -518,27 +585,27 @@ func GetCommitMessages(pr *models.PullRequest) string {
 		pr.HeadRepo, err = models.GetRepositoryByID(pr.HeadRepoID)
 		if err != nil {
 			log.Error("GetRepositoryById[%d]: %v", pr.HeadRepoID, err)
-			return ""
+			return "", nil
 		}
 	}
 
 	gitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
 	if err != nil {
 		log.Error("Unable to open head repository: Error: %v", err)
-		return ""
+		return "", nil
 	}
 	defer gitRepo.Close()
 
 	headCommit, err := gitRepo.GetBranchCommit(pr.HeadBranch)
 	if err != nil {
 		log.Error("Unable to get head commit: %s Error: %v", pr.HeadBranch, err)
-		return ""
+		return "", nil
 	}
 
 	mergeBase, err := gitRepo.GetCommit(pr.MergeBase)
 	if err != nil {
 		log.Error("Unable to get merge base commit: %s Error: %v", pr.MergeBase, err)
-		return ""
+		return "", nil
 	}
 
 	limit := setting.Repository.PullRequest.DefaultMergeMessageCommitsLimit
@@ -546,7 +613,7 @@ func GetCommitMessages(pr *models.PullRequest) string {
 	list, err := gitRepo.CommitsBetweenLimit(headCommit, mergeBase, limit, 0)
 	if err != nil {
 		log.Error("Unable to get commits between: %s %s Error: %v", pr.HeadBranch, pr.MergeBase, err)
-		return ""
+		return "", nil
 	}
 
 	maxSize := setting.Repository.PullRequest.DefaultMergeMessageSize
@@ -568,12 +635,12 @@ func GetCommitMessages(pr *models.PullRequest) string {
 			}
 			if _, err := stringBuilder.Write(toWrite); err != nil {
 				log.Error("Unable to write commit message Error: %v", err)
-				return ""
+				return "", nil
 			}
 
 			if _, err := stringBuilder.WriteRune('\n'); err != nil {
 				log.Error("Unable to write commit message Error: %v", err)
-				return ""
+				return "", nil
 			}
 		}
 
@@ -593,7 +660,7 @@ func GetCommitMessages(pr *models.PullRequest) string {
 			list, err := gitRepo.CommitsBetweenLimit(headCommit, mergeBase, limit, skip)
 			if err != nil {
 				log.Error("Unable to get commits between: %s %s Error: %v", pr.HeadBranch, pr.MergeBase, err)
-				return ""
+				return "", nil
 
 			}
 			if list.Len() == 0 {
@@ -614,29 +681,7 @@ func GetCommitMessages(pr *models.PullRequest) string {
 		}
 	}
 
-	if len(authors) > 0 {
-		if _, err := stringBuilder.WriteRune('\n'); err != nil {
-			log.Error("Unable to write to string builder Error: %v", err)
-			return ""
-		}
-	}
-
-	for _, author := range authors {
-		if _, err := stringBuilder.Write([]byte("Co-authored-by: ")); err != nil {
-			log.Error("Unable to write to string builder Error: %v", err)
-			return ""
-		}
-		if _, err := stringBuilder.Write([]byte(author)); err != nil {
-			log.Error("Unable to write to string builder Error: %v", err)
-			return ""
-		}
-		if _, err := stringBuilder.WriteRune('\n'); err != nil {
-			log.Error("Unable to write to string builder Error: %v", err)
-			return ""
-		}
-	}
-
-	return stringBuilder.String()
+	return stringBuilder.String(), authors
 }
 
 // GetLastCommitStatus returns the last commit status for this pull request.
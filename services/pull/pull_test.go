@@ -5,4 +5,50 @@
 
 package pull
 
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
 // TODO TestPullRequest_PushToBaseRepo
+
+func TestCheckIfPushDismissesApprovals(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	const (
+		base     = "4a357436d925b5c974181ff12a994538ddc5a269"
+		master   = "5f22f7d0d95d614d25a5b68592adb345a4b5c7fd"
+		diverged = "62fb502a7172d4453f0322a2cc85bddffa57f07a"
+	)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	protectBranch := &models.ProtectedBranch{
+		RepoID:     repo.ID,
+		BranchName: "master",
+	}
+	check := func(oldCommitID, newCommitID string) bool {
+		assert.NoError(t, models.UpdateProtectBranch(repo, protectBranch, models.WhitelistOptions{}))
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+		dismissed, err := checkIfPushDismissesApprovals(pr, oldCommitID, newCommitID)
+		assert.NoError(t, err)
+		return dismissed
+	}
+
+	// the options only extend the dismissal of stale approvals
+	protectBranch.DismissApprovalsOnPush = true
+	protectBranch.DismissApprovalsOnForcePush = true
+	assert.False(t, check(base, master))
+
+	protectBranch.DismissStaleApprovals = true
+	assert.True(t, check(base, master))
+
+	protectBranch.DismissApprovalsOnPush = false
+	assert.False(t, check(base, master))
+	assert.True(t, check(master, diverged))
+
+	protectBranch.DismissApprovalsOnForcePush = false
+	assert.False(t, check(master, diverged))
+}
@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // CreateCodeComment creates a comment on the code line
@@ -94,7 +95,86 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 
 	if !isReview && !existsReview {
 		// Submit the review we've just created so the comment shows up in the issue view
-		if _, _, err = SubmitReview(doer, gitRepo, issue, models.ReviewTypeComment, "", latestCommitID); err != nil {
+		if _, _, err = SubmitReview(doer, gitRepo, issue, models.ReviewTypeComment, "", latestCommitID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// NOTICE: if it's a pending review the notifications will not be fired until user submit review.
+
+	return comment, nil
+}
+
+// GetCommitMessageLines returns the lines of the message of a commit of a pull request
+func GetCommitMessageLines(gitRepo *git.Repository, pr *models.PullRequest, commitID string) ([]string, error) {
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID[%s]: %v", pr.GetGitRefName(), err)
+	}
+	commits, err := gitRepo.CommitsBetweenIDs(headCommitID, pr.MergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetweenIDs[%s, %s]: %v", headCommitID, pr.MergeBase, err)
+	}
+	for e := commits.Front(); e != nil; e = e.Next() {
+		if commit := e.Value.(*git.Commit); commit.ID.String() == commitID {
+			return strings.Split(strings.TrimRight(commit.CommitMessage, "\n"), "\n"), nil
+		}
+	}
+	return nil, models.ErrCommitMessageLineNotExist{CommitID: commitID}
+}
+
+// CreateCommitMessageComment creates a comment on a line of the message of a commit of the pull request,
+// either as part of the pending review of the doer or as a single comment
+func CreateCommitMessageComment(doer *models.User, gitRepo *git.Repository, issue *models.Issue, commitID string, line int64, content string, isReview bool, latestCommitID string) (*models.Comment, error) {
+	if err := issue.LoadPullRequest(); err != nil {
+		return nil, fmt.Errorf("GetPullRequestByIssueID: %v", err)
+	}
+	if err := issue.LoadRepo(); err != nil {
+		return nil, err
+	}
+	lines, err := GetCommitMessageLines(gitRepo, issue.PullRequest, commitID)
+	if err != nil {
+		return nil, err
+	}
+	if line < 1 || line > int64(len(lines)) {
+		return nil, models.ErrCommitMessageLineNotExist{CommitID: commitID, Line: line}
+	}
+
+	review, err := models.GetCurrentReview(doer, issue)
+	if err != nil {
+		if !models.IsErrReviewNotExist(err) {
+			return nil, err
+		}
+
+		if review, err = models.CreateReview(models.CreateReviewOptions{
+			Type:     models.ReviewTypePending,
+			Reviewer: doer,
+			Issue:    issue,
+			Official: false,
+			CommitID: latestCommitID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := models.CreateComment(&models.CreateCommentOptions{
+		Type:      models.CommentTypeCommitMessage,
+		Doer:      doer,
+		Repo:      issue.Repo,
+		Issue:     issue,
+		Content:   content,
+		LineNum:   line,
+		CommitSHA: commitID,
+		ReviewID:  review.ID,
+		Patch:     lines[line-1],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !isReview {
+		// Submit the review so the comment shows up in the issue view
+		if comment.Review, _, err = SubmitReview(doer, gitRepo, issue, models.ReviewTypeComment, "", latestCommitID, nil); err != nil {
 			return nil, err
 		}
 	}
@@ -195,13 +275,47 @@ func createCodeComment(doer *models.User, repo *models.Repository, issue *models
 	})
 }
 
-// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
-func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string) (*models.Review, *models.Comment, error) {
+// acknowledgedReviewChecklist returns the items of the review checklist of the repository acknowledged by a reviewer,
+// an approval has to acknowledge all of them
+func acknowledgedReviewChecklist(issue *models.Issue, reviewType models.ReviewType, acknowledged []string) ([]string, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return nil, err
+	}
+	unit, err := issue.Repo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		if models.IsErrUnitTypeNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var checklist, missing []string
+	for _, item := range unit.PullRequestsConfig().ReviewChecklist {
+		if util.IsStringInSlice(item, acknowledged) {
+			checklist = append(checklist, item)
+		} else {
+			missing = append(missing, item)
+		}
+	}
+	if reviewType == models.ReviewTypeApprove && len(missing) > 0 {
+		return nil, models.ErrReviewChecklistNotAcknowledged{Items: missing}
+	}
+	return checklist, nil
+}
+
+// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist.
+// An approval has to acknowledge all items of the review checklist of the repository.
+func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string, checklist []string) (*models.Review, *models.Comment, error) {
 	pr, err := issue.GetPullRequest()
 	if err != nil {
 		return nil, nil, err
 	}
 
+	checklist, err = acknowledgedReviewChecklist(issue, reviewType, checklist)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var stale bool
 	if reviewType != models.ReviewTypeApprove && reviewType != models.ReviewTypeReject {
 		stale = false
@@ -221,7 +335,7 @@ func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issu
 		}
 	}
 
-	review, comm, err := models.SubmitReview(doer, issue, reviewType, content, commitID, stale)
+	review, comm, err := models.SubmitReview(doer, issue, reviewType, content, commitID, stale, checklist)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// GetCommitsMissingSignOff returns the commits of a pull request which aren't signed off by their author if its
+// protected base branch requires it, merge commits don't need to be signed off
+func GetCommitsMissingSignOff(pr *models.PullRequest) ([]*git.Commit, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, err
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.RequireSignOff {
+		return nil, nil
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	baseCommitID, err := gitRepo.GetBranchCommitID(pr.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+	commits, err := gitRepo.CommitsBetweenIDs(headCommitID, baseCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []*git.Commit
+	for e := commits.Front(); e != nil; e = e.Next() {
+		commit := e.Value.(*git.Commit)
+		if commit.ParentCount() > 1 || commit.IsSignedOff() {
+			continue
+		}
+		missing = append(missing, commit)
+	}
+	return missing, nil
+}
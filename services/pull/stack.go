@@ -0,0 +1,98 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// StackPullRequest stacks a pull request on a base pull request, the pull request
+// targets the head branch of the base pull request
+func StackPullRequest(pr, basePull *models.PullRequest, doer *models.User) error {
+	if pr.BasePullID == basePull.ID {
+		return nil
+	}
+	if err := models.CheckBasePull(pr, basePull); err != nil {
+		return err
+	}
+
+	pr.BasePullID = basePull.ID
+	pr.BasePull = basePull
+	if pr.BaseBranch == basePull.HeadBranch {
+		return pr.UpdateBasePull()
+	}
+	return ChangeTargetBranch(pr, doer, basePull.HeadBranch)
+}
+
+// DetectBasePull stacks a new pull request on the open pull request of its repository whose
+// head branch is its base branch, if there is exactly one
+func DetectBasePull(pr *models.PullRequest) error {
+	prs, err := models.GetUnmergedPullRequestsByHeadInfo(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return err
+	}
+	var basePull *models.PullRequest
+	for _, candidate := range prs {
+		if candidate.BaseRepoID != pr.BaseRepoID {
+			continue
+		}
+		if basePull != nil {
+			return nil
+		}
+		basePull = candidate
+	}
+	if basePull == nil {
+		return nil
+	}
+	if err := models.CheckBasePull(pr, basePull); err != nil {
+		if models.IsErrInvalidBasePull(err) {
+			return nil
+		}
+		return err
+	}
+	pr.BasePullID = basePull.ID
+	pr.BasePull = basePull
+	return nil
+}
+
+// UnstackPullRequest unstacks a pull request from its base pull request, its target branch is kept
+func UnstackPullRequest(pr *models.PullRequest) error {
+	if pr.BasePullID == 0 {
+		return nil
+	}
+	pr.BasePullID = 0
+	pr.BasePull = nil
+	return pr.UpdateBasePull()
+}
+
+// retargetStackedPullRequests retargets the pull requests stacked on a merged pull request
+// to its base branch, they are stacked on its base pull request if it has one
+func retargetStackedPullRequests(pr *models.PullRequest, doer *models.User) {
+	prs, err := models.GetStackedPullRequests(pr.ID)
+	if err != nil {
+		log.Error("GetStackedPullRequests[%d]: %v", pr.ID, err)
+		return
+	}
+	for _, stacked := range prs {
+		if err := stacked.LoadIssue(); err != nil {
+			log.Error("LoadIssue[%d]: %v", stacked.ID, err)
+			continue
+		}
+		if err := stacked.Issue.LoadRepo(); err != nil {
+			log.Error("LoadRepo[%d]: %v", stacked.ID, err)
+			continue
+		}
+		stacked.BasePullID = pr.BasePullID
+		stacked.BasePull = nil
+		if err := ChangeTargetBranch(stacked, doer, pr.BaseBranch); err != nil {
+			log.Error("ChangeTargetBranch[%d]: %v", stacked.ID, err)
+			continue
+		}
+		notification.NotifyPullRequestChangeTargetBranch(doer, stacked, pr.BaseBranch)
+		log.Trace("PR[%d] stacked on the merged PR[%d] retargeted to %s", stacked.ID, pr.ID, pr.BaseBranch)
+	}
+}
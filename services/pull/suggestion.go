@@ -0,0 +1,247 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+)
+
+// suggestionPattern matches the ```suggestion blocks of code comments, the content of the
+// block replaces the commented line
+var suggestionPattern = regexp.MustCompile("(?s)(?:^|\n)```suggestion[ \t]*\r?\n(.*?)(?:\r?\n)?```")
+
+// parseSuggestion returns the suggested content of a code comment
+func parseSuggestion(content string) (string, bool) {
+	matches := suggestionPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) != 1 {
+		return "", false
+	}
+	return matches[0][1], true
+}
+
+type suggestion struct {
+	comment *models.Comment
+	lines   []string
+}
+
+// defaultSuggestionsMessage returns the default message of the commit applying suggestions
+func defaultSuggestionsMessage(count int) string {
+	if count == 1 {
+		return "Apply suggestion from code review"
+	}
+	return fmt.Sprintf("Apply %d suggestions from code review", count)
+}
+
+// getSuggestions returns the suggestions of code comments of a pull request grouped by file
+func getSuggestions(pr *models.PullRequest, baseGitRepo *git.Repository, doer *models.User, commentIDs []int64) (map[string][]*suggestion, error) {
+	suggestions := make(map[string][]*suggestion, len(commentIDs))
+	seen := make(map[int64]bool, len(commentIDs))
+	for _, id := range commentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		comment, err := models.GetCommentByID(id)
+		if err != nil {
+			if models.IsErrCommentNotExist(err) {
+				return nil, models.ErrInvalidSuggestion{CommentID: id, Reason: "the comment does not exist"}
+			}
+			return nil, err
+		}
+		if comment.IssueID != pr.IssueID || comment.Type != models.CommentTypeCode {
+			return nil, models.ErrInvalidSuggestion{CommentID: id, Reason: "the comment is not a code comment of the pull request"}
+		}
+		content, ok := parseSuggestion(comment.Content)
+		if !ok {
+			return nil, models.ErrInvalidSuggestion{CommentID: id, Reason: "the comment must contain exactly one suggestion"}
+		}
+		if comment.Line <= 0 {
+			return nil, models.ErrInvalidSuggestion{CommentID: id, Reason: "the comment is not on a line of the proposed changes"}
+		}
+		if !comment.Invalidated {
+			if err := comment.CheckInvalidation(baseGitRepo, doer, pr.GetGitRefName()); err != nil {
+				return nil, err
+			}
+		}
+		if comment.Invalidated {
+			return nil, models.ErrInvalidSuggestion{CommentID: id, Reason: "the commented line has changed"}
+		}
+
+		var lines []string
+		if len(content) > 0 {
+			lines = strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+		}
+		suggestions[comment.TreePath] = append(suggestions[comment.TreePath], &suggestion{comment: comment, lines: lines})
+	}
+	return suggestions, nil
+}
+
+// applySuggestions replaces the commented lines of a file by their suggestions
+func applySuggestions(content string, suggestions []*suggestion) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// replace the lines from the bottom so the line numbers of the other suggestions don't change
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].comment.Line > suggestions[j].comment.Line
+	})
+	for i, s := range suggestions {
+		if i > 0 && suggestions[i-1].comment.Line == s.comment.Line {
+			return "", models.ErrInvalidSuggestion{CommentID: s.comment.ID, Reason: "another suggestion changes the same line"}
+		}
+		index := int(s.comment.Line) - 1
+		if index >= len(lines) {
+			return "", models.ErrInvalidSuggestion{CommentID: s.comment.ID, Reason: "the commented line does not exist"}
+		}
+
+		ending := ""
+		if strings.HasSuffix(lines[index], "\r\n") {
+			ending = "\r\n"
+		} else if strings.HasSuffix(lines[index], "\n") {
+			ending = "\n"
+		}
+		replacement := make([]string, 0, len(s.lines))
+		for j, line := range s.lines {
+			if j < len(s.lines)-1 && ending == "" {
+				// the last line of the file has no line ending but the lines before it need one
+				replacement = append(replacement, line+"\n")
+				continue
+			}
+			replacement = append(replacement, line+ending)
+		}
+		lines = append(lines[:index], append(replacement, lines[index+1:]...)...)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// ApplySuggestions commits the suggestions of code comments of a pull request to its head branch
+// as a single commit, either all the suggestions are applied or none of them
+func ApplySuggestions(pr *models.PullRequest, doer *models.User, commentIDs []int64, message string) (string, error) {
+	if len(commentIDs) == 0 {
+		return "", models.ErrInvalidSuggestion{Reason: "no suggestion to apply"}
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return "", err
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return "", err
+	}
+	if pr.HeadRepo == nil {
+		return "", models.ErrInvalidSuggestion{Reason: "the head repository of the pull request does not exist"}
+	}
+
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer baseGitRepo.Close()
+	headCommitID, err := baseGitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return "", err
+	}
+
+	suggestions, err := getSuggestions(pr, baseGitRepo, doer, commentIDs)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := repofiles.NewTemporaryUploadRepository(pr.HeadRepo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+	if err := t.Clone(pr.HeadBranch); err != nil {
+		return "", err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return "", err
+	}
+	commit, err := t.GetBranchCommit(pr.HeadBranch)
+	if err != nil {
+		return "", err
+	}
+	// the comments refer to the head of the pull request, which is pushed to the base repository
+	if commit.ID.String() != headCommitID {
+		return "", &git.ErrPushOutOfDate{Err: fmt.Errorf("the head branch %s has changed", pr.HeadBranch)}
+	}
+
+	for treePath, fileSuggestions := range suggestions {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return "", models.ErrInvalidSuggestion{CommentID: fileSuggestions[0].comment.ID, Reason: "the commented file does not exist"}
+			}
+			return "", err
+		}
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			return "", models.ErrInvalidSuggestion{CommentID: fileSuggestions[0].comment.ID, Reason: "the commented file is not a regular file"}
+		}
+		reader, err := entry.Blob().DataAsync()
+		if err != nil {
+			return "", err
+		}
+		buf := new(strings.Builder)
+		_, err = io.Copy(buf, reader)
+		reader.Close()
+		if err != nil {
+			return "", err
+		}
+
+		content, err := applySuggestions(buf.String(), fileSuggestions)
+		if err != nil {
+			return "", err
+		}
+		objectHash, err := t.HashObject(strings.NewReader(content))
+		if err != nil {
+			return "", err
+		}
+		mode := "100644"
+		if entry.IsExecutable() {
+			mode = "100755"
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, treePath); err != nil {
+			return "", err
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(message)) == 0 {
+		count := 0
+		for _, fileSuggestions := range suggestions {
+			count += len(fileSuggestions)
+		}
+		message = defaultSuggestionsMessage(count)
+	}
+	commitHash, err := t.CommitTree(doer, doer, treeHash, message)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Push(doer, commitHash, pr.HeadBranch); err != nil {
+		return "", err
+	}
+
+	for _, fileSuggestions := range suggestions {
+		for _, s := range fileSuggestions {
+			if err := models.MarkConversation(s.comment, doer, true); err != nil {
+				return "", err
+			}
+		}
+	}
+	return commitHash, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSuggestion(t *testing.T) {
+	content, ok := parseSuggestion("```suggestion\nfoo\nbar\n```")
+	assert.True(t, ok)
+	assert.Equal(t, "foo\nbar", content)
+
+	content, ok = parseSuggestion("Remove it\r\n```suggestion\r\n```\r\nplease")
+	assert.True(t, ok)
+	assert.Equal(t, "", content)
+
+	_, ok = parseSuggestion("```go\nfoo\n```")
+	assert.False(t, ok)
+
+	_, ok = parseSuggestion("```suggestion\nfoo\n```\n```suggestion\nbar\n```")
+	assert.False(t, ok)
+}
+
+func TestApplySuggestions(t *testing.T) {
+	newSuggestion := func(id, line int64, lines ...string) *suggestion {
+		return &suggestion{comment: &models.Comment{ID: id, Line: line}, lines: lines}
+	}
+
+	content, err := applySuggestions("a\nb\nc\n", []*suggestion{
+		newSuggestion(1, 1, "A"),
+		newSuggestion(2, 3, "c1", "c2"),
+		newSuggestion(3, 2),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "A\nc1\nc2\n", content)
+
+	// the line endings are preserved
+	content, err = applySuggestions("a\r\nb", []*suggestion{
+		newSuggestion(1, 1, "A"),
+		newSuggestion(2, 2, "b1", "b2"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "A\r\nb1\nb2", content)
+
+	_, err = applySuggestions("a\nb\n", []*suggestion{newSuggestion(1, 3, "c")})
+	assert.True(t, models.IsErrInvalidSuggestion(err))
+
+	_, err = applySuggestions("a\nb\n", []*suggestion{
+		newSuggestion(1, 1, "A"),
+		newSuggestion(2, 1, "a"),
+	})
+	assert.True(t, models.IsErrInvalidSuggestion(err))
+}
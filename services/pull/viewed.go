@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// ViewedFile is a file of a pull request a user marked as viewed
+type ViewedFile struct {
+	*models.PullViewedFile
+	// HasChanged is true if the file changed since it was marked, the mark doesn't apply anymore then
+	HasChanged bool
+}
+
+// headBlobs returns the blobs of files at the head of a pull request, the files which
+// don't exist there are mapped to an empty string
+func headBlobs(pr *models.PullRequest, treePaths []string) (map[string]string, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	}
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := gitRepo.GetCommit(headCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make(map[string]string, len(treePaths))
+	for _, treePath := range treePaths {
+		entry, err := headCommit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				blobs[treePath] = ""
+				continue
+			}
+			return nil, err
+		}
+		blobs[treePath] = entry.ID.String()
+	}
+	return blobs, nil
+}
+
+// GetViewedFiles returns the files of a pull request a user marked as viewed, with whether
+// they changed since
+func GetViewedFiles(pr *models.PullRequest, user *models.User) ([]*ViewedFile, error) {
+	marks, err := models.GetPullViewedFiles(pr.ID, user.ID)
+	if err != nil || len(marks) == 0 {
+		return nil, err
+	}
+
+	treePaths := make([]string, 0, len(marks))
+	for _, mark := range marks {
+		treePaths = append(treePaths, mark.TreePath)
+	}
+	blobs, err := headBlobs(pr, treePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ViewedFile, 0, len(marks))
+	for _, mark := range marks {
+		files = append(files, &ViewedFile{
+			PullViewedFile: mark,
+			HasChanged:     blobs[mark.TreePath] != mark.BlobSHA,
+		})
+	}
+	return files, nil
+}
+
+// GetViewedFilesSet returns the paths of the files of a pull request a user marked as viewed
+// and which did not change since
+func GetViewedFilesSet(pr *models.PullRequest, user *models.User) (map[string]bool, error) {
+	files, err := GetViewedFiles(pr, user)
+	if err != nil {
+		return nil, err
+	}
+	viewed := make(map[string]bool, len(files))
+	for _, file := range files {
+		if !file.HasChanged {
+			viewed[file.TreePath] = true
+		}
+	}
+	return viewed, nil
+}
+
+// UpdateViewedFiles marks files of a pull request as viewed in their current version or
+// as not viewed by a user
+func UpdateViewedFiles(pr *models.PullRequest, user *models.User, viewed, unviewed []string) error {
+	viewed = cleanTreePaths(viewed)
+	if len(viewed) > 0 {
+		blobs, err := headBlobs(pr, viewed)
+		if err != nil {
+			return err
+		}
+		if err := models.SetPullFilesViewed(pr, user.ID, blobs); err != nil {
+			return err
+		}
+	}
+	return models.UnsetPullFilesViewed(pr.ID, user.ID, cleanTreePaths(unviewed))
+}
+
+func cleanTreePaths(treePaths []string) []string {
+	cleaned := make([]string, 0, len(treePaths))
+	for _, treePath := range treePaths {
+		treePath = strings.Trim(treePath, "/")
+		if len(treePath) > 0 {
+			cleaned = append(cleaned, treePath)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewedFiles(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	assert.NoError(t, UpdateViewedFiles(pr, user, []string{"README.md", "/3", "removed.txt", ""}, nil))
+	files, err := GetViewedFiles(pr, user)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 3) {
+		assert.Equal(t, "3", files[0].TreePath)
+		assert.Equal(t, "00750edc07d6415dcc07ae0351e9397b0222b7ba", files[0].BlobSHA)
+		assert.Equal(t, "README.md", files[1].TreePath)
+		assert.Equal(t, "4b4851ad51df6a7d9f25c979345979eaeb5b349f", files[1].BlobSHA)
+		assert.Equal(t, "removed.txt", files[2].TreePath)
+		assert.Empty(t, files[2].BlobSHA)
+		for _, file := range files {
+			assert.False(t, file.HasChanged)
+		}
+	}
+
+	// a file changed since it was marked is not viewed anymore
+	assert.NoError(t, models.SetPullFilesViewed(pr, user.ID, map[string]string{"README.md": "65f1bf27bc3bf70f64657658635e66094edbcb4d"}))
+	viewed, err := GetViewedFilesSet(pr, user)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"3": true, "removed.txt": true}, viewed)
+
+	assert.NoError(t, UpdateViewedFiles(pr, user, []string{"README.md"}, []string{"3"}))
+	viewed, err = GetViewedFilesSet(pr, user)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"README.md": true, "removed.txt": true}, viewed)
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"os"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// CreateReleasesFromPushedTags creates the releases of the pushed tags matching the release automation
+// of the repository, the tags have to be already synchronized as releases which are only tags
+func CreateReleasesFromPushedTags(pusher *models.User, repo *models.Repository, gitRepo *git.Repository, tagNames []string) error {
+	if len(tagNames) == 0 {
+		return nil
+	}
+	automation, err := models.GetReleaseAutomation(repo.ID)
+	if err != nil {
+		if models.IsErrReleaseAutomationNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !automation.Enabled {
+		return nil
+	}
+
+	for _, tagName := range tagNames {
+		if !automation.MatchTag(tagName) {
+			continue
+		}
+		rel, err := models.GetRelease(repo.ID, tagName)
+		if err != nil {
+			return err
+		}
+		// the tag belongs to a release created before it was pushed
+		if !rel.IsTag {
+			continue
+		}
+		tag, err := gitRepo.GetTag(tagName)
+		if err != nil {
+			return err
+		}
+
+		vars := releaseTemplateVars(pusher, repo, tag, rel)
+		rel.Title = strings.TrimSpace(os.Expand(automation.GetTitleTemplate(), vars))
+		if len(rel.Title) == 0 {
+			rel.Title = tagName
+		}
+		rel.Note = strings.TrimSpace(os.Expand(strings.Replace(automation.NoteTemplate, "\r\n", "\n", -1), vars))
+		rel.IsTag = false
+		rel.IsDraft = automation.Draft
+		rel.IsPrerelease = automation.Prerelease
+		if rel.PublisherID == 0 && pusher != nil {
+			rel.PublisherID = pusher.ID
+		}
+		if err := models.UpdateRelease(models.DefaultDBContext(), rel); err != nil {
+			return err
+		}
+		log.Trace("Release %s of %-v created from the pushed tag", tagName, repo)
+
+		if !rel.IsDraft {
+			notification.NotifyNewRelease(rel)
+		}
+	}
+	return nil
+}
+
+// releaseTemplateVars returns the mapping of the variables of the templates of the release automations
+// to their values
+func releaseTemplateVars(pusher *models.User, repo *models.Repository, tag *git.Tag, rel *models.Release) func(string) string {
+	return func(name string) string {
+		switch name {
+		case "TagName":
+			return rel.TagName
+		case "TagMessage":
+			return strings.TrimSpace(tag.Message)
+		case "CommitSHA":
+			return rel.Sha1
+		case "ShortCommitSHA":
+			return base.ShortSha(rel.Sha1)
+		case "RepoOwnerName":
+			return repo.OwnerName
+		case "RepoName":
+			return repo.Name
+		case "PusherName":
+			if pusher != nil {
+				return pusher.Name
+			}
+		case "Date":
+			return rel.CreatedUnix.FormatDate()
+		}
+		return ""
+	}
+}
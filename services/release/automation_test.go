@@ -0,0 +1,56 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReleasesFromPushedTags(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	pushTags := func(tagNames ...string) {
+		for _, tagName := range tagNames {
+			assert.NoError(t, gitRepo.CreateTag(tagName, "65f1bf27bc3bf70f64657658635e66094edbcb4d"))
+		}
+		assert.NoError(t, repo_module.PushUpdateAddDeleteTags(repo, gitRepo, tagNames, nil))
+		assert.NoError(t, CreateReleasesFromPushedTags(user, repo, gitRepo, tagNames))
+	}
+
+	// the pushed tags are only tags without an automation
+	pushTags("v2.0.0")
+	models.AssertExistsAndLoadBean(t, &models.Release{RepoID: repo.ID, TagName: "v2.0.0", IsTag: true})
+
+	assert.NoError(t, models.SaveReleaseAutomation(&models.ReleaseAutomation{
+		RepoID:        repo.ID,
+		Enabled:       true,
+		TagPattern:    "v*",
+		Draft:         true,
+		TitleTemplate: "${RepoName} ${TagName}",
+		NoteTemplate:  "Released by ${PusherName} from ${ShortCommitSHA}.",
+		DoerID:        user.ID,
+	}))
+	pushTags("v2.0.1", "nightly")
+
+	rel := models.AssertExistsAndLoadBean(t, &models.Release{RepoID: repo.ID, TagName: "v2.0.1"}).(*models.Release)
+	assert.False(t, rel.IsTag)
+	assert.True(t, rel.IsDraft)
+	assert.False(t, rel.IsPrerelease)
+	assert.Equal(t, "repo1 v2.0.1", rel.Title)
+	assert.Equal(t, "Released by user2 from 65f1bf27bc.", rel.Note)
+	models.AssertExistsAndLoadBean(t, &models.Release{RepoID: repo.ID, TagName: "nightly", IsTag: true})
+}
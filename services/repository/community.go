@@ -0,0 +1,186 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/license"
+)
+
+// maxLicenseSize is the maximum number of bytes of a license file read to classify it
+const maxLicenseSize = 1024 * 1024
+
+// NoAssertion is the license of a repository whose license file isn't recognized
+const NoAssertion = "NOASSERTION"
+
+var (
+	communityFileExtensions = []string{"", ".md", ".markdown", ".txt", ".rst"}
+	// documentation directories which may contain community files besides the root directory
+	communityDirs = []string{"", ".gitea", ".github", "docs"}
+)
+
+// CommunityProfile represents the files of a repository helping people to contribute to it,
+// their paths are empty when they are missing
+type CommunityProfile struct {
+	Description         bool
+	Readme              string
+	License             string
+	LicenseName         string
+	CodeOfConduct       string
+	Contributing        string
+	IssueTemplate       string
+	PullRequestTemplate string
+}
+
+// Percentage returns the percentage of the recommended community files the repository has
+func (p *CommunityProfile) Percentage() int {
+	items := []bool{p.Description, p.Readme != "", p.License != "", p.CodeOfConduct != "", p.Contributing != "", p.IssueTemplate != "", p.PullRequestTemplate != ""}
+	count := 0
+	for _, ok := range items {
+		if ok {
+			count++
+		}
+	}
+	return count * 100 / len(items)
+}
+
+// findCommunityFile returns the path of the first file of the directories named like one of the names,
+// case insensitively and with one of the community file extensions
+func findCommunityFile(commit *git.Commit, dirs []string, names ...string) (string, error) {
+	for _, dir := range dirs {
+		tree := &commit.Tree
+		if dir != "" {
+			var err error
+			if tree, err = commit.SubTree(dir); err != nil {
+				if git.IsErrNotExist(err) {
+					continue
+				}
+				return "", err
+			}
+		}
+		entries, err := tree.ListEntries()
+		if err != nil {
+			return "", err
+		}
+		for _, name := range names {
+			for _, ext := range communityFileExtensions {
+				for _, entry := range entries {
+					if (entry.IsRegular() || entry.IsExecutable()) && strings.EqualFold(entry.Name(), name+ext) {
+						return path.Join(dir, entry.Name()), nil
+					}
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// findTemplateDir returns the path of the first existing directory of templates
+func findTemplateDir(commit *git.Commit, dirs ...string) (string, error) {
+	for _, dir := range dirs {
+		entry, err := commit.GetTreeEntryByPath(dir)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		if entry.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", nil
+}
+
+// detectLicense returns the SPDX identifier of the license of a file
+func detectLicense(commit *git.Commit, treePath string) (string, error) {
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		return "", err
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return "", err
+	}
+	defer dataRc.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(dataRc, maxLicenseSize))
+	if err != nil {
+		return "", err
+	}
+	if name := license.Detect(content); name != "" {
+		return name, nil
+	}
+	return NoAssertion, nil
+}
+
+// GetCommunityProfile returns the community profile of a repository at a commit
+func GetCommunityProfile(repo *models.Repository, commit *git.Commit) (*CommunityProfile, error) {
+	profile := &CommunityProfile{Description: strings.TrimSpace(repo.Description) != ""}
+
+	var err error
+	if profile.Readme, err = findCommunityFile(commit, communityDirs, "README"); err != nil {
+		return nil, err
+	}
+	if profile.License, err = findCommunityFile(commit, []string{""}, "LICENSE", "LICENCE", "COPYING"); err != nil {
+		return nil, err
+	}
+	if profile.License != "" {
+		if profile.LicenseName, err = detectLicense(commit, profile.License); err != nil {
+			return nil, err
+		}
+	}
+	if profile.CodeOfConduct, err = findCommunityFile(commit, communityDirs, "CODE_OF_CONDUCT", "CODE-OF-CONDUCT"); err != nil {
+		return nil, err
+	}
+	if profile.Contributing, err = findCommunityFile(commit, communityDirs, "CONTRIBUTING"); err != nil {
+		return nil, err
+	}
+	if profile.IssueTemplate, err = findCommunityFile(commit, []string{".gitea", ".github"}, "ISSUE_TEMPLATE", "issue_template"); err != nil {
+		return nil, err
+	}
+	if profile.IssueTemplate == "" {
+		if profile.IssueTemplate, err = findTemplateDir(commit, ".gitea/ISSUE_TEMPLATE", ".github/ISSUE_TEMPLATE"); err != nil {
+			return nil, err
+		}
+	}
+	if profile.PullRequestTemplate, err = findCommunityFile(commit, []string{".gitea", ".github"}, "PULL_REQUEST_TEMPLATE", "pull_request_template"); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UpdateRepositoryCommunityFiles updates the license and the code of conduct of a repository
+// detected at the head commit of its default branch
+func UpdateRepositoryCommunityFiles(repo *models.Repository, commit *git.Commit) error {
+	licenseFile, err := findCommunityFile(commit, []string{""}, "LICENSE", "LICENCE", "COPYING")
+	if err != nil {
+		return err
+	}
+	licenseName := ""
+	if licenseFile != "" {
+		if licenseName, err = detectLicense(commit, licenseFile); err != nil {
+			return err
+		}
+	}
+	codeOfConduct, err := findCommunityFile(commit, communityDirs, "CODE_OF_CONDUCT", "CODE-OF-CONDUCT")
+	if err != nil {
+		return err
+	}
+
+	hasCodeOfConduct := codeOfConduct != ""
+	if repo.License == licenseName && repo.HasCodeOfConduct == hasCodeOfConduct {
+		return nil
+	}
+	repo.License = licenseName
+	repo.HasCodeOfConduct = hasCodeOfConduct
+	return models.UpdateRepositoryCols(repo, "license", "has_code_of_conduct")
+}
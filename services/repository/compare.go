@@ -0,0 +1,129 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IsSameForkNetwork returns whether two repositories are the same repository or are forks
+// of each other or of the same repository
+func IsSameForkNetwork(repo1, repo2 *models.Repository) bool {
+	return repo1.ID == repo2.ID ||
+		repo1.ForkID == repo2.ID ||
+		repo2.ForkID == repo1.ID ||
+		(repo1.ForkID > 0 && repo1.ForkID == repo2.ForkID)
+}
+
+// CanCompare returns whether the references of the repositories can be compared, the
+// comparisons of repositories of different fork networks have to be allowed
+func CanCompare(baseRepo, headRepo *models.Repository) bool {
+	return setting.Repository.Compare.AllowUnrelatedRepos || IsSameForkNetwork(baseRepo, headRepo)
+}
+
+// cachedCompareInfo is the cached comparison of references of different repositories
+type cachedCompareInfo struct {
+	MergeBase string
+	Commits   []string
+	NumFiles  int
+}
+
+func compareCacheKey(baseRepo, headRepo *models.Repository, baseCommitID, headCommitID string) string {
+	return fmt.Sprintf("compare:%d:%s:%d:%s", baseRepo.ID, baseCommitID, headRepo.ID, headCommitID)
+}
+
+// GetCompareInfo returns the comparison of a reference of the base repository and of a reference
+// of the head repository and the number of commits of the comparison, the commits are limited
+// to the MaxCommits setting. Comparing different repositories fetches the base reference into
+// the head repository, such comparisons are cached by the commit IDs of the references.
+func GetCompareInfo(baseRepo, headRepo *models.Repository, headGitRepo *git.Repository, baseRef, headRef string) (*git.CompareInfo, int, error) {
+	var (
+		compareInfo *git.CompareInfo
+		err         error
+	)
+	if baseRepo.ID == headRepo.ID {
+		compareInfo, err = headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseRef, headRef)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		compareInfo, err = getCachedCompareInfo(baseRepo, headRepo, headGitRepo, baseRef, headRef)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	total := compareInfo.Commits.Len()
+	if max := setting.Repository.Compare.MaxCommits; max > 0 {
+		for compareInfo.Commits.Len() > max {
+			compareInfo.Commits.Remove(compareInfo.Commits.Back())
+		}
+	}
+	return compareInfo, total, nil
+}
+
+func getCachedCompareInfo(baseRepo, headRepo *models.Repository, headGitRepo *git.Repository, baseRef, headRef string) (*git.CompareInfo, error) {
+	baseCommitID, err := git.GetFullCommitID(baseRepo.RepoPath(), baseRef)
+	if err != nil {
+		return nil, err
+	}
+	headCommitID, err := git.GetFullCommitID(headGitRepo.Path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var compareInfo *git.CompareInfo
+	data, err := cache.GetString(compareCacheKey(baseRepo, headRepo, baseCommitID, headCommitID), func() (string, error) {
+		compareInfo, err = headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseRef, headRef)
+		if err != nil {
+			return "", err
+		}
+		cached := cachedCompareInfo{
+			MergeBase: compareInfo.MergeBase,
+			Commits:   make([]string, 0, compareInfo.Commits.Len()),
+			NumFiles:  compareInfo.NumFiles,
+		}
+		for e := compareInfo.Commits.Front(); e != nil; e = e.Next() {
+			cached.Commits = append(cached.Commits, e.Value.(*git.Commit).ID.String())
+		}
+		data, err := json.Marshal(cached)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if compareInfo != nil {
+		return compareInfo, nil
+	}
+
+	var cached cachedCompareInfo
+	if err = json.Unmarshal([]byte(data), &cached); err != nil {
+		return nil, err
+	}
+	compareInfo = &git.CompareInfo{
+		MergeBase: cached.MergeBase,
+		Commits:   list.New(),
+		NumFiles:  cached.NumFiles,
+	}
+	for _, id := range cached.Commits {
+		commit, err := headGitRepo.GetCommit(id)
+		if err != nil {
+			// the commits may have been garbage collected, the cached comparison is outdated
+			log.Warn("GetCompareInfo: cached commit %s of %s: %v", id, headRepo.FullName(), err)
+			cache.Remove(compareCacheKey(baseRepo, headRepo, baseCommitID, headCommitID))
+			return headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseRef, headRef)
+		}
+		compareInfo.Commits.PushBack(commit)
+	}
+	return compareInfo, nil
+}
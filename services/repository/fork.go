@@ -0,0 +1,61 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+)
+
+func forkDivergenceCacheKey(baseRepo, fork *models.Repository, baseCommitID, forkCommitID string) string {
+	return fmt.Sprintf("fork_divergence:%d:%s:%d:%s", baseRepo.ID, baseCommitID, fork.ID, forkCommitID)
+}
+
+// getDefaultBranchCommitID returns the ID of the head commit of the default branch of a repository,
+// it is empty when the branch doesn't exist
+func getDefaultBranchCommitID(repo *models.Repository) (string, error) {
+	if repo.IsEmpty {
+		return "", nil
+	}
+	commitID, err := git.GetFullCommitID(repo.RepoPath(), git.BranchPrefix+repo.DefaultBranch)
+	if err != nil && git.IsErrNotExist(err) {
+		return "", nil
+	}
+	return commitID, err
+}
+
+// GetForkDivergence returns the number of commits the default branch of a fork is ahead or behind
+// the default branch of the forked repository, it is nil when one of the branches doesn't exist.
+// Divergences are cached by the commit IDs of both branches.
+func GetForkDivergence(baseRepo, fork *models.Repository) (*git.DivergeObject, error) {
+	baseCommitID, err := getDefaultBranchCommitID(baseRepo)
+	if err != nil || baseCommitID == "" {
+		return nil, err
+	}
+	forkCommitID, err := getDefaultBranchCommitID(fork)
+	if err != nil || forkCommitID == "" {
+		return nil, err
+	}
+
+	data, err := cache.GetString(forkDivergenceCacheKey(baseRepo, fork, baseCommitID, forkCommitID), func() (string, error) {
+		divergence, err := git.GetDivergingCommitsFromRepo(fork.RepoPath(), baseRepo.RepoPath(), baseCommitID, forkCommitID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d:%d", divergence.Ahead, divergence.Behind), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	divergence := &git.DivergeObject{}
+	if _, err := fmt.Sscanf(data, "%d:%d", &divergence.Ahead, &divergence.Behind); err != nil {
+		return nil, fmt.Errorf("invalid cached divergence %q: %v", data, err)
+	}
+	return divergence, nil
+}
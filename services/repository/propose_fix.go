@@ -0,0 +1,146 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// ProposeFixOptions represents the change of a file proposed to fix an issue
+type ProposeFixOptions struct {
+	TreePath     string
+	Content      string
+	LastCommitID string
+	Message      string
+	Title        string
+	Description  string
+}
+
+// ProposeFix commits the change of a file to a new branch and opens a pull request fixing an issue with it.
+// The change is based on the default branch, a doer who can't write the code of the repository proposes
+// it from their fork, which is created when they have none.
+func ProposeFix(doer *models.User, issue *models.Issue, opts ProposeFixOptions) (*models.PullRequest, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return nil, err
+	}
+	repo := issue.Repo
+	if err := repo.GetOwner(); err != nil {
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanRead(models.UnitTypeCode) || !repo.UnitEnabled(models.UnitTypePullRequests) {
+		return nil, models.ErrUserDoesNotHaveAccessToRepo{UserID: doer.ID, RepoName: repo.Name}
+	}
+
+	baseGitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer baseGitRepo.Close()
+	baseCommit, err := baseGitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	isNewFile := false
+	if _, err := baseCommit.GetTreeEntryByPath(opts.TreePath); err != nil {
+		if !git.IsErrNotExist(err) {
+			return nil, err
+		}
+		isNewFile = true
+	}
+
+	headRepo := repo
+	if !perm.CanWrite(models.UnitTypeCode) {
+		if headRepo, err = repo.GetUserFork(doer.ID); err != nil {
+			return nil, err
+		} else if headRepo == nil {
+			if headRepo, err = ForkRepository(doer, doer, repo, repo.Name, repo.Description); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	branch, err := proposeFixBranchName(headRepo, issue.Index)
+	if err != nil {
+		return nil, err
+	}
+	oldBranch := repo.DefaultBranch
+	if headRepo.ID != repo.ID {
+		// the fork may be behind, start the branch from the default branch of the repository
+		if err := git.Push(repo.RepoPath(), git.PushOptions{
+			Remote: headRepo.RepoPath(),
+			Branch: baseCommit.ID.String() + ":" + git.BranchPrefix + branch,
+			Env:    models.InternalPushingEnvironment(doer, headRepo),
+		}); err != nil {
+			return nil, fmt.Errorf("Push: %v", err)
+		}
+		oldBranch = branch
+	}
+
+	if _, err := repofiles.CreateOrUpdateRepoFile(headRepo, doer, &repofiles.UpdateRepoFileOptions{
+		LastCommitID: opts.LastCommitID,
+		OldBranch:    oldBranch,
+		NewBranch:    branch,
+		TreePath:     opts.TreePath,
+		Message:      opts.Message,
+		Content:      strings.ReplaceAll(opts.Content, "\r", ""),
+		IsNewFile:    isNewFile,
+	}); err != nil {
+		return nil, err
+	}
+
+	// the pull request closes the issue when it's merged
+	reference := fmt.Sprintf("#%d", issue.Index)
+	if keywords := setting.Repository.PullRequest.CloseKeywords; len(keywords) > 0 {
+		reference = keywords[0] + " " + reference
+	}
+	pullIssue := &models.Issue{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Title:    opts.Title,
+		PosterID: doer.ID,
+		Poster:   doer,
+		IsPull:   true,
+		Content:  strings.TrimSpace(opts.Description + "\n\n" + reference),
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: headRepo.ID,
+		BaseRepoID: repo.ID,
+		HeadBranch: branch,
+		BaseBranch: repo.DefaultBranch,
+		HeadRepo:   headRepo,
+		BaseRepo:   repo,
+		Type:       models.PullRequestGitea,
+	}
+	if err := pull_service.NewPullRequest(repo, pullIssue, nil, nil, pr, nil); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// proposeFixBranchName returns the name of a branch which doesn't exist yet for a fix of an issue
+func proposeFixBranchName(repo *models.Repository, index int64) (string, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	name := fmt.Sprintf("fix-issue-%d", index)
+	for i := 2; gitRepo.IsBranchExist(name); i++ {
+		name = fmt.Sprintf("fix-issue-%d-%d", index, i)
+	}
+	return name, nil
+}
@@ -21,6 +21,7 @@ import (
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	pull_service "code.gitea.io/gitea/services/pull"
+	release_service "code.gitea.io/gitea/services/release"
 )
 
 // pushQueue represents a queue to handle update pull request tests
@@ -87,6 +88,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	if err = repo.UpdateSize(models.DefaultDBContext()); err != nil {
 		log.Error("Failed to update size for repository: %v", err)
 	}
+	ClearSize(repo.ID)
 
 	addTags := make([]string, 0, len(optsList))
 	delTags := make([]string, 0, len(optsList))
@@ -161,6 +163,12 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				if err := repo_module.CacheRef(repo, gitRepo, opts.RefFullName); err != nil {
 					log.Error("repo_module.CacheRef %s/%s failed: %v", repo.ID, branch, err)
 				}
+
+				if branch == repo.DefaultBranch || repo.IsEmpty {
+					if err := UpdateRepositoryCommunityFiles(repo, newCommit); err != nil {
+						log.Error("UpdateRepositoryCommunityFiles %s/%s failed: %v", repo.ID, branch, err)
+					}
+				}
 			} else if err = pull_service.CloseBranchPulls(pusher, repo.ID, branch); err != nil {
 				// close all related pulls
 				log.Error("close related pull request failed: %v", err)
@@ -181,6 +189,16 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	if err := repo_module.PushUpdateAddDeleteTags(repo, gitRepo, addTags, delTags); err != nil {
 		return fmt.Errorf("PushUpdateAddDeleteTags: %v", err)
 	}
+	if len(addTags) > 0 {
+		if pusher == nil || pusher.ID != optsList[0].PusherID {
+			if pusher, err = models.GetUserByID(optsList[0].PusherID); err != nil {
+				return err
+			}
+		}
+		if err := release_service.CreateReleasesFromPushedTags(pusher, repo, gitRepo, addTags); err != nil {
+			log.Error("CreateReleasesFromPushedTags %-v: %v", repo, err)
+		}
+	}
 
 	if err := commitRepoAction(repo, gitRepo, actions...); err != nil {
 		return fmt.Errorf("commitRepoAction: %v", err)
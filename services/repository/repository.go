@@ -60,6 +60,12 @@ func ForkRepository(doer, u *models.User, oldRepo *models.Repository, name, desc
 
 // DeleteRepository deletes a repository for a user or organization.
 func DeleteRepository(doer *models.User, repo *models.Repository) error {
+	if held, err := models.IsRepoUnderLegalHold(repo.ID); err != nil {
+		return err
+	} else if held {
+		return models.ErrUnderLegalHold{RepoID: repo.ID}
+	}
+
 	if err := pull_service.CloseRepoBranchesPulls(doer, repo); err != nil {
 		log.Error("CloseRepoBranchesPulls failed: %v", err)
 	}
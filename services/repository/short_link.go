@@ -0,0 +1,181 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ShortLinkOptions represents the target of a short link
+type ShortLinkOptions struct {
+	Type models.ShortLinkType
+	// Ref is the branch, the tag or the commit the link is created at
+	Ref       string
+	TreePath  string
+	LineStart int
+	LineEnd   int
+}
+
+// CreateShortLink creates a short link to lines of a file or to the diff of a commit. The ref is resolved
+// to its commit, so the link keeps pointing to the same content when the ref moves.
+func CreateShortLink(repo *models.Repository, doer *models.User, opts ShortLinkOptions) (*models.ShortLink, error) {
+	if strings.HasPrefix(opts.Ref, "-") {
+		return nil, git.ErrNotExist{ID: opts.Ref}
+	}
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	ref := opts.Ref
+	if len(ref) == 0 {
+		ref = repo.DefaultBranch
+	}
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.ShortLink{
+		RepoID:    repo.ID,
+		CreatorID: doer.ID,
+		Type:      opts.Type,
+		CommitID:  commit.ID.String(),
+		TreePath:  strings.Trim(opts.TreePath, "/"),
+		LineStart: opts.LineStart,
+		LineEnd:   opts.LineEnd,
+	}
+	switch link.Type {
+	case models.ShortLinkTypeFile:
+		entry, err := commit.GetTreeEntryByPath(link.TreePath)
+		if err != nil {
+			return nil, err
+		}
+		if entry.IsDir() || entry.IsSubModule() {
+			return nil, git.ErrNotExist{ID: link.CommitID, RelPath: link.TreePath}
+		}
+		link.BlobID = entry.ID.String()
+	case models.ShortLinkTypeDiff:
+		if len(link.TreePath) > 0 {
+			if has, err := isFileChangedInCommit(commit, link.TreePath); err != nil {
+				return nil, err
+			} else if !has {
+				return nil, git.ErrNotExist{ID: link.CommitID, RelPath: link.TreePath}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown short link type: %d", link.Type)
+	}
+
+	if err := models.CreateShortLink(link); err != nil {
+		return nil, err
+	}
+	link.Repo = repo
+	return link, nil
+}
+
+// isFileChangedInCommit returns whether a file is changed in the diff of a commit
+func isFileChangedInCommit(commit *git.Commit, treePath string) (bool, error) {
+	if commit.ParentCount() == 0 {
+		// every file of the first commit is added
+		if _, err := commit.GetTreeEntryByPath(treePath); err != nil {
+			if git.IsErrNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	parentID, err := commit.ParentID(0)
+	if err != nil {
+		return false, err
+	}
+	files, err := commit.GetFilesChangedSinceCommit(parentID.String())
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if file == treePath {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ShortLinkTarget returns the path of the permalink a short link resolves to relative to its repository.
+// A file whose commit doesn't exist anymore, e.g. after a force push, is located by its blob in the default branch.
+func ShortLinkTarget(link *models.ShortLink) (string, error) {
+	if err := link.LoadRepo(); err != nil {
+		return "", err
+	}
+	gitRepo, err := git.OpenRepository(link.Repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commitID, treePath := link.CommitID, link.TreePath
+	if _, err := gitRepo.GetCommit(commitID); err != nil {
+		if !git.IsErrNotExist(err) || link.Type != models.ShortLinkTypeFile {
+			return "", err
+		}
+		commit, err := gitRepo.GetBranchCommit(link.Repo.DefaultBranch)
+		if err != nil {
+			return "", err
+		}
+		if treePath, err = findBlobPath(commit, link.BlobID, treePath); err != nil {
+			return "", err
+		}
+		commitID = commit.ID.String()
+	}
+
+	if link.Type == models.ShortLinkTypeDiff {
+		permalink := "commit/" + commitID
+		if len(treePath) > 0 {
+			permalink += "#diff-" + base.EncodeSha1(treePath)
+			if link.LineStart > 0 {
+				permalink += fmt.Sprintf("R%d", link.LineStart)
+			}
+		}
+		return permalink, nil
+	}
+
+	permalink := "src/commit/" + commitID + "/" + util.PathEscapeSegments(treePath)
+	if link.LineStart > 0 {
+		permalink += fmt.Sprintf("#L%d", link.LineStart)
+		if link.LineEnd > link.LineStart {
+			permalink += fmt.Sprintf("-L%d", link.LineEnd)
+		}
+	}
+	return permalink, nil
+}
+
+// findBlobPath returns the path of a blob in the tree of a commit, the previous path of the file is
+// preferred when the blob is found several times
+func findBlobPath(commit *git.Commit, blobID, treePath string) (string, error) {
+	if entry, err := commit.GetTreeEntryByPath(treePath); err == nil && entry.ID.String() == blobID {
+		return treePath, nil
+	} else if err != nil && !git.IsErrNotExist(err) {
+		return "", err
+	}
+
+	entries, err := commit.Tree.ListEntriesRecursive()
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.ID.String() == blobID {
+			return entry.Name(), nil
+		}
+	}
+	return "", git.ErrNotExist{ID: blobID, RelPath: treePath}
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortLinkTarget(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	link := models.AssertExistsAndLoadBean(t, &models.ShortLink{ID: 1}).(*models.ShortLink)
+	target, err := ShortLinkTarget(link)
+	assert.NoError(t, err)
+	assert.Equal(t, "src/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md#L1-L2", target)
+
+	// a file whose commit is gone is located by its blob in the default branch
+	link.CommitID = "0000000000000000000000000000000000000001"
+	link.TreePath = "moved/README.md"
+	target, err = ShortLinkTarget(link)
+	assert.NoError(t, err)
+	assert.Equal(t, "src/commit/65f1bf27bc3bf70f64657658635e66094edbcb4d/README.md#L1-L2", target)
+
+	link.BlobID = "0000000000000000000000000000000000000002"
+	_, err = ShortLinkTarget(link)
+	assert.True(t, git.IsErrNotExist(err))
+}
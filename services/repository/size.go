@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// sizeComputing holds the cache keys of the breakdowns which are being computed
+var sizeComputing = sync.NewStatusTable()
+
+func sizeCacheKey(repoID int64) string {
+	return fmt.Sprintf("repo_size:%d", repoID)
+}
+
+// ComputeSize computes the breakdown of the storage used by a repository
+func ComputeSize(repo *models.Repository) (*api.RepoSize, error) {
+	gitSize, err := util.GetDirectorySize(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("GetDirectorySize: %v", err)
+	}
+	lfsSize, err := repo.GetLFSSize()
+	if err != nil {
+		return nil, err
+	}
+	attachmentsSize, err := repo.GetAttachmentsSize()
+	if err != nil {
+		return nil, err
+	}
+	return &api.RepoSize{
+		Git:         gitSize,
+		LFS:         lfsSize,
+		Attachments: attachmentsSize,
+		Total:       gitSize + lfsSize + attachmentsSize,
+		Computed:    time.Now(),
+	}, nil
+}
+
+// GetSize returns the cached breakdown of the storage used by a repository. When it isn't cached
+// it's computed in the background and nil is returned until it's done, it's computed directly if
+// the cache is disabled.
+func GetSize(repo *models.Repository) (*api.RepoSize, error) {
+	if !cache.IsEnabled() {
+		return ComputeSize(repo)
+	}
+
+	key := sizeCacheKey(repo.ID)
+	size := new(api.RepoSize)
+	has, err := cache.GetJSON(key, size)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return size, nil
+	}
+
+	if sizeComputing.StartIfNotRunning(key) {
+		go func() {
+			defer sizeComputing.Stop(key)
+			size, err := ComputeSize(repo)
+			if err != nil {
+				log.Error("ComputeSize of repository %d: %v", repo.ID, err)
+				return
+			}
+			if err := cache.PutJSON(key, size); err != nil {
+				log.Error("Unable to cache the size of repository %d: %v", repo.ID, err)
+			}
+		}()
+	}
+	return nil, nil
+}
+
+// ClearSize removes the cached breakdown of the storage used by a repository
+func ClearSize(repoID int64) {
+	cache.Remove(sizeCacheKey(repoID))
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSize(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	_, err := models.NewLFSMetaObject(&models.LFSMetaObject{Oid: "5c2c3a4c3a1bf1ab0e4d4fd5d1c2e7cf5e66b1d5e9d3e7bfe0d0a0c4b2b9f5a1", Size: 42, RepositoryID: 1})
+	assert.NoError(t, err)
+
+	// the size is computed directly without the cache
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	size, err := GetSize(repo)
+	assert.NoError(t, err)
+	if assert.NotNil(t, size) {
+		assert.NotZero(t, size.Git)
+		assert.EqualValues(t, 42, size.LFS)
+		assert.EqualValues(t, 0, size.Attachments)
+		assert.Equal(t, size.Git+size.LFS+size.Attachments, size.Total)
+	}
+}
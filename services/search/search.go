@@ -0,0 +1,284 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// Type is a type of the searched objects
+type Type string
+
+// The types of the searched objects
+const (
+	TypeRepository   Type = "repositories"
+	TypeCode         Type = "code"
+	TypeIssue        Type = "issues"
+	TypePull         Type = "pulls"
+	TypeUser         Type = "users"
+	TypeOrganization Type = "organizations"
+)
+
+// Types returns the types which can be searched, the code can only be searched if the code indexer is enabled
+func Types() []Type {
+	types := []Type{TypeRepository}
+	if setting.Indexer.RepoIndexerEnabled {
+		types = append(types, TypeCode)
+	}
+	return append(types, TypeIssue, TypePull, TypeUser, TypeOrganization)
+}
+
+// IsValidType returns whether the objects of the type can be searched
+func IsValidType(t Type) bool {
+	for _, valid := range Types() {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Options represents the options of a global search
+type Options struct {
+	Actor   *models.User
+	Keyword string
+	// Type is the type of the listed results, the first results of every type are listed if it is empty
+	Type     Type
+	Page     int
+	PageSize int
+	// PreviewSize is the number of the listed results of every type if Type is empty
+	PreviewSize int
+}
+
+// Facet is the number of the results of a type
+type Facet struct {
+	Type  Type
+	Count int64
+}
+
+// Result represents the results of a global search, only the results of the types which are
+// listed are loaded but all the types are counted
+type Result struct {
+	Facets        []*Facet
+	Repos         []*models.Repository
+	Code          []*code_indexer.Result
+	CodeRepos     map[int64]*models.Repository
+	CodeLanguages []*code_indexer.SearchResultLanguages
+	Issues        []*models.Issue
+	Pulls         []*models.Issue
+	Users         []*models.User
+	Organizations []*models.User
+}
+
+// Count returns the number of the results of the type
+func (r *Result) Count(t Type) int64 {
+	for _, facet := range r.Facets {
+		if facet.Type == t {
+			return facet.Count
+		}
+	}
+	return 0
+}
+
+// listOptions returns the pagination of the results of the type, the results of the types
+// which are not listed are only counted
+func (opts *Options) listOptions(t Type) models.ListOptions {
+	switch opts.Type {
+	case "":
+		return models.ListOptions{Page: 1, PageSize: opts.PreviewSize}
+	case t:
+		return models.ListOptions{Page: opts.Page, PageSize: opts.PageSize}
+	default:
+		return models.ListOptions{Page: 1, PageSize: 1}
+	}
+}
+
+// Search queries the searchers of all the types in parallel, the results are limited to the
+// objects which the actor can read
+func Search(opts *Options) (*Result, error) {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+
+	types := Types()
+	result := &Result{Facets: make([]*Facet, len(types))}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+	)
+	for i, t := range types {
+		result.Facets[i] = &Facet{Type: t}
+		wg.Add(1)
+		go func(facet *Facet) {
+			defer wg.Done()
+			count, err := searchers[facet.Type](opts, opts.listOptions(facet.Type), result)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			facet.Count = count
+		}(result.Facets[i])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// searcher searches the objects of a type, it sets only its own field of the result
+type searcher func(opts *Options, listOptions models.ListOptions, result *Result) (int64, error)
+
+var searchers = map[Type]searcher{
+	TypeRepository:   searchRepositories,
+	TypeCode:         searchCode,
+	TypeIssue:        searchIssues(false),
+	TypePull:         searchIssues(true),
+	TypeUser:         searchUsers(models.UserTypeIndividual),
+	TypeOrganization: searchUsers(models.UserTypeOrganization),
+}
+
+func searchRepositories(opts *Options, listOptions models.ListOptions, result *Result) (int64, error) {
+	var ownerID int64
+	if opts.Actor != nil && !opts.Actor.IsAdmin {
+		ownerID = opts.Actor.ID
+	}
+	repos, count, err := models.SearchRepository(&models.SearchRepoOptions{
+		ListOptions:        listOptions,
+		Actor:              opts.Actor,
+		OrderBy:            models.SearchOrderByRecentUpdated,
+		Private:            opts.Actor != nil,
+		Keyword:            opts.Keyword,
+		OwnerID:            ownerID,
+		AllPublic:          true,
+		AllLimited:         true,
+		IncludeDescription: setting.UI.SearchRepoDescription,
+	})
+	if err != nil {
+		return 0, err
+	}
+	result.Repos = repos
+	return count, nil
+}
+
+func searchCode(opts *Options, listOptions models.ListOptions, result *Result) (int64, error) {
+	if len(opts.Keyword) == 0 {
+		return 0, nil
+	}
+	codeOpts := &code_indexer.SearchOptions{
+		Page:     listOptions.Page,
+		PageSize: listOptions.PageSize,
+	}
+	codeOpts.ParseKeyword(opts.Keyword)
+
+	total, results, languages, err := code_indexer.PerformUserSearch(opts.Actor, 0, codeOpts)
+	if err != nil {
+		if code_indexer.IsErrInvalidSearchPattern(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	repoIDs := make([]int64, 0, len(results))
+	for _, result := range results {
+		repoIDs = append(repoIDs, result.RepoID)
+	}
+	repos, err := models.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return 0, err
+	}
+	result.Code = results
+	result.CodeRepos = repos
+	result.CodeLanguages = languages
+	return int64(total), nil
+}
+
+func searchIssues(isPull bool) searcher {
+	unitType := models.UnitTypeIssues
+	if isPull {
+		unitType = models.UnitTypePullRequests
+	}
+	return func(opts *Options, listOptions models.ListOptions, result *Result) (int64, error) {
+		repoIDs, err := models.FindUserUnitAccessibleRepoIDs(opts.Actor, 0, unitType)
+		if err != nil || len(repoIDs) == 0 {
+			return 0, err
+		}
+
+		var issueIDs []int64
+		if len(opts.Keyword) > 0 {
+			issueIDs, err = issue_indexer.SearchIssuesByKeyword(repoIDs, opts.Keyword)
+			if err != nil || len(issueIDs) == 0 {
+				return 0, err
+			}
+		}
+
+		issuesOpts := &models.IssuesOptions{
+			ListOptions: listOptions,
+			RepoIDs:     repoIDs,
+			IssueIDs:    issueIDs,
+			IsPull:      util.OptionalBoolOf(isPull),
+			SortType:    "recentupdate",
+		}
+		issues, err := models.Issues(issuesOpts)
+		if err != nil {
+			return 0, err
+		}
+		issuesOpts.ListOptions = models.ListOptions{Page: -1}
+		count, err := models.CountIssues(issuesOpts)
+		if err != nil {
+			return 0, err
+		}
+
+		if isPull {
+			result.Pulls = issues
+		} else {
+			result.Issues = issues
+		}
+		return count, nil
+	}
+}
+
+func searchUsers(userType models.UserType) searcher {
+	return func(opts *Options, listOptions models.ListOptions, result *Result) (int64, error) {
+		searchOpts := &models.SearchUserOptions{
+			Actor:       opts.Actor,
+			Keyword:     opts.Keyword,
+			Type:        userType,
+			ListOptions: listOptions,
+			OrderBy:     models.SearchOrderByAlphabetically,
+		}
+		if userType == models.UserTypeIndividual {
+			searchOpts.IsActive = util.OptionalBoolTrue
+			searchOpts.Visible = []structs.VisibleType{structs.VisibleTypePublic, structs.VisibleTypeLimited, structs.VisibleTypePrivate}
+		} else {
+			searchOpts.Visible = []structs.VisibleType{structs.VisibleTypePublic}
+			if opts.Actor != nil {
+				searchOpts.Visible = append(searchOpts.Visible, structs.VisibleTypeLimited, structs.VisibleTypePrivate)
+			}
+		}
+
+		users, count, err := models.SearchUsers(searchOpts)
+		if err != nil {
+			return 0, err
+		}
+		if userType == models.UserTypeIndividual {
+			result.Users = users
+		} else {
+			result.Organizations = users
+		}
+		return count, nil
+	}
+}
@@ -189,7 +189,7 @@ func updateWikiPage(doer *models.User, repo *models.Repository, oldWikiName, new
 
 	sign, signingKey, signer, _ := repo.SignWikiCommit(doer)
 	if sign {
-		commitTreeOpts.KeyID = signingKey
+		commitTreeOpts.Key = signingKey
 		if repo.GetTrustModel() == models.CommitterTrustModel || repo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
 			committer = signer
 		}
@@ -312,7 +312,7 @@ func DeleteWikiPage(doer *models.User, repo *models.Repository, wikiName string)
 
 	sign, signingKey, signer, _ := repo.SignWikiCommit(doer)
 	if sign {
-		commitTreeOpts.KeyID = signingKey
+		commitTreeOpts.Key = signingKey
 		if repo.GetTrustModel() == models.CommitterTrustModel || repo.GetTrustModel() == models.CollaboratorCommitterTrustModel {
 			committer = signer
 		}
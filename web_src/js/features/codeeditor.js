@@ -11,6 +11,15 @@ function getEditorconfig(input) {
   }
 }
 
+// the languages of the custom highlight mapping of the server by file extension
+function getLanguageMapping(textarea) {
+  try {
+    return JSON.parse(textarea.dataset.languageMapping) || {};
+  } catch {
+    return {};
+  }
+}
+
 function initLanguages(monaco) {
   for (const {filenames, extensions, id} of monaco.languages.getLanguages()) {
     for (const filename of filenames || []) {
@@ -22,15 +31,15 @@ function initLanguages(monaco) {
   }
 }
 
-function getLanguage(filename) {
-  return languagesByFilename[filename] || languagesByExt[extname(filename)] || 'plaintext';
+function getLanguage(filename, languageMapping = {}) {
+  return languageMapping[extname(filename)] || languagesByFilename[filename] || languagesByExt[extname(filename)] || 'plaintext';
 }
 
-function updateEditor(monaco, editor, filename, lineWrapExts) {
+function updateEditor(monaco, editor, filename, lineWrapExts, getNewLanguage) {
   editor.updateOptions({...getFileBasedOptions(filename, lineWrapExts)});
   const model = editor.getModel();
   const language = model.getModeId();
-  const newLanguage = getLanguage(filename);
+  const newLanguage = getNewLanguage(filename);
   if (language !== newLanguage) monaco.editor.setModelLanguage(model, newLanguage);
 }
 
@@ -89,6 +98,9 @@ export async function createCodeEditor(textarea, filenameInput, previewFileModes
   const lineWrapExts = (textarea.dataset.lineWrapExtensions || '').split(',');
   const isMarkdown = markdownExts.includes(extname(filename));
   const editorConfig = getEditorconfig(filenameInput);
+  const languageMapping = getLanguageMapping(textarea);
+  // the language resolved by the server from the name and the content of the file
+  const serverLanguage = textarea.dataset.language;
 
   if (previewLink) {
     if (isMarkdown && (previewFileModes || []).includes('markdown')) {
@@ -100,13 +112,18 @@ export async function createCodeEditor(textarea, filenameInput, previewFileModes
   }
 
   const {monaco, editor} = await createMonaco(textarea, filename, {
+    language: serverLanguage || languageMapping[extname(filename)],
     ...getFileBasedOptions(filenameInput.value, lineWrapExts),
     ...getEditorConfigOptions(editorConfig),
   });
 
+  const getNewLanguage = (newFilename) => {
+    if (serverLanguage && newFilename === filename) return serverLanguage;
+    return getLanguage(newFilename, languageMapping);
+  };
   filenameInput.addEventListener('keyup', () => {
     const filename = filenameInput.value;
-    updateEditor(monaco, editor, filename, lineWrapExts);
+    updateEditor(monaco, editor, filename, lineWrapExts, getNewLanguage);
   });
 
   return editor;
@@ -0,0 +1,22 @@
+// Hide the lines of the collapsed regions, regions may be nested in collapsed ones
+function updateFoldedLines($rows) {
+  let hiddenUntil = 0;
+  $rows.each((_, row) => {
+    const line = Number(row.getAttribute('data-line'));
+    row.classList.toggle('fold-hidden', line <= hiddenUntil);
+    if (line > hiddenUntil && row.classList.contains('folded')) {
+      hiddenUntil = Number(row.getAttribute('data-fold-end'));
+    }
+  });
+}
+
+export default function initCodeFolding() {
+  const $rows = $('.code-view tr[data-line]');
+  if (!$rows.length) return;
+
+  $rows.find('.fold-toggle').on('click', (e) => {
+    e.stopPropagation();
+    $(e.currentTarget).closest('tr').toggleClass('folded');
+    updateFoldedLines($rows);
+  });
+}
@@ -0,0 +1,51 @@
+// Size the stacks of images to the largest of their images so that none of them is cropped
+function fitStack(stack) {
+  let width = 0, height = 0;
+  for (const img of stack.querySelectorAll('img')) {
+    width = Math.max(width, img.naturalWidth);
+    height = Math.max(height, img.naturalHeight);
+  }
+  stack.style.width = `${width}px`;
+  stack.style.height = `${height}px`;
+}
+
+// Load the image of the pixel differences computed by the server, only when it's first shown
+async function loadDifference(row) {
+  const img = row.querySelector('.image-diff-difference');
+  if (!img || img.dataset.loaded) return;
+  img.dataset.loaded = 'true';
+  try {
+    const resp = await fetch(img.dataset.url, {credentials: 'same-origin'});
+    if (!resp.ok) throw new Error(resp.statusText);
+    img.src = URL.createObjectURL(await resp.blob());
+    const changed = row.querySelector('.image-diff-changed');
+    changed.querySelector('.count').textContent = resp.headers.get('X-Gitea-Changed-Pixels');
+    changed.classList.remove('hide');
+  } catch {
+    row.querySelector('.image-diff-unavailable').classList.remove('hide');
+  }
+}
+
+export default function initImageDiff() {
+  $(document).on('click', '.image-diff-modes .item', ({currentTarget}) => {
+    const {mode} = currentTarget.dataset;
+    const $menu = $(currentTarget).closest('.menu');
+    $menu.find('.item').removeClass('active');
+    $(currentTarget).addClass('active');
+    $(currentTarget).closest('tbody').find('.image-diff-mode').each((_, row) => {
+      row.classList.toggle('hide', row.dataset.mode !== mode);
+    });
+    const row = $(currentTarget).closest('tbody').find(`.image-diff-mode[data-mode="${mode}"]`)[0];
+    for (const stack of row.querySelectorAll('.image-diff-stack')) fitStack(stack);
+    if (mode === 'difference') loadDifference(row);
+  });
+
+  $(document).on('input', '.image-diff-slider', ({currentTarget}) => {
+    const row = currentTarget.closest('tr');
+    const value = Number(currentTarget.value);
+    const swipe = row.querySelector('.image-diff-swipe');
+    if (swipe) swipe.style.width = `${value}%`;
+    const onion = row.querySelector('.image-diff-onion');
+    if (onion) onion.style.opacity = String(value / 100);
+  });
+}
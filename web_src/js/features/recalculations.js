@@ -0,0 +1,26 @@
+// refreshes the progress of the running recalculations of the admin page until they stop
+export default function initRecalculations() {
+  const $table = $('#recalculations');
+  if (!$table.length || !$table.find('tr[data-running=true]').length) return;
+
+  const interval = setInterval(async () => {
+    let progresses;
+    try {
+      progresses = await $.get($table.data('url'));
+    } catch {
+      clearInterval(interval);
+      return;
+    }
+    for (const {name, running, done, total, percent} of progresses) {
+      const $row = $table.find(`tr[data-name="${name}"]`);
+      if ($row.data('running') && !running) {
+        // show the final status and buttons
+        clearInterval(interval);
+        window.location.reload();
+        return;
+      }
+      $row.find('.ui.progress').progress('set percent', percent);
+      $row.find('.recalculation-count').text(`${done} / ${total}`);
+    }
+  }, 3000);
+}
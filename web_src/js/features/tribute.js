@@ -1,10 +1,29 @@
 import {emojiKeys, emojiHTML, emojiString} from './emoji.js';
 import {uniq} from '../utils.js';
 
+function fetchMentions(query, cb) {
+  const url = new URL(window.config.MentionsURL, window.location.origin);
+  url.searchParams.set('q', query);
+  url.searchParams.set('limit', 10);
+  fetch(url, {credentials: 'same-origin'}).then((res) => {
+    if (!res.ok) return [];
+    return res.json();
+  }).then((candidates) => {
+    cb(candidates.map((candidate) => ({
+      key: `${candidate.name} ${candidate.full_name}`,
+      value: candidate.name,
+      name: candidate.name,
+      fullname: candidate.full_name,
+      avatar: candidate.avatar_url,
+      members: candidate.num_members,
+    })));
+  }).catch(() => cb([]));
+}
+
 function makeCollections({mentions, emoji}) {
   const collections = [];
 
-  if (mentions) {
+  if (emoji) {
     collections.push({
       trigger: ':',
       requireLeadingSpace: true,
@@ -29,19 +48,22 @@ function makeCollections({mentions, emoji}) {
     });
   }
 
-  if (emoji) {
+  if (mentions && window.config.MentionsURL) {
     collections.push({
-      values: window.config.tributeValues,
-      noMatchTemplate: () => null,
+      values: fetchMentions,
+      // the server already filtered the candidates by the query
+      lookup: 'key',
       menuItemTemplate: (item) => {
         return `
           <div class="tribute-item">
             <img src="${item.original.avatar}"/>
             <span class="name">${item.original.name}</span>
             ${item.original.fullname && item.original.fullname !== '' ? `<span class="fullname">${item.original.fullname}</span>` : ''}
+            ${item.original.members ? `<span class="fullname">(${item.original.members})</span>` : ''}
           </div>
         `;
-      }
+      },
+      noMatchTemplate: () => null,
     });
   }
 
@@ -21,6 +21,9 @@ import attachTribute from './features/tribute.js';
 import createColorPicker from './features/colorpicker.js';
 import createDropzone from './features/dropzone.js';
 import initTableSort from './features/tablesort.js';
+import initCodeFolding from './features/codefold.js';
+import initImageDiff from './features/imagediff.js';
+import initRecalculations from './features/recalculations.js';
 import ActivityTopAuthors from './components/ActivityTopAuthors.vue';
 import {initNotificationsTable, initNotificationCount} from './features/notification.js';
 import {createCodeEditor, createMonaco} from './features/codeeditor.js';
@@ -730,11 +733,19 @@ function initArchiveLinks() {
     return;
   }
 
+  // clicking the checkbox mustn't close the download dropdown
+  $('.archive-lfs-item').on('click', (event) => {
+    event.stopPropagation();
+  });
+
   $('.archive-link').on('click', function (event) {
-    const url = $(this).data('url');
+    let url = $(this).data('url');
     if (typeof url === 'undefined') {
       return;
     }
+    if ($('.archive-lfs:checked').length > 0) {
+      url += '?lfs=true';
+    }
 
     event.preventDefault();
     getArchive($(event.target), url, true);
@@ -923,6 +934,27 @@ async function initRepository() {
       event.preventDefault();
     });
 
+    // Insert a saved reply at the cursor, the forms of the review comments are created dynamically
+    $(document).on('change', 'select.saved-replies', function () {
+      const content = $(this).val();
+      if (!content) return;
+      $(this).val('');
+
+      const $form = $(this).closest('form');
+      const editor = $form.find('.CodeMirror')[0];
+      if (editor && editor.CodeMirror) {
+        editor.CodeMirror.replaceSelection(content);
+        editor.CodeMirror.focus();
+        return;
+      }
+      const textarea = $form.find('textarea[name="content"]')[0];
+      if (!textarea) return;
+      const {selectionStart, selectionEnd, value} = textarea;
+      textarea.value = value.substring(0, selectionStart) + content + value.substring(selectionEnd);
+      textarea.selectionStart = textarea.selectionEnd = selectionStart + content.length;
+      textarea.focus();
+    });
+
     // Edit issue or comment content
     $('.edit-content').on('click', async function (event) {
       $(this).closest('.dropdown').find('.menu').toggle('visible');
@@ -1214,7 +1246,8 @@ function initPullRequestReview() {
     }
   }
 
-  $('.show-outdated').on('click', function (e) {
+  // the handlers are delegated as the diffs of the files of large pull requests are loaded lazily
+  $(document).on('click', '.show-outdated', function (e) {
     e.preventDefault();
     const id = $(this).data('comment');
     $(this).addClass('hide');
@@ -1223,7 +1256,7 @@ function initPullRequestReview() {
     $(`#hide-outdated-${id}`).removeClass('hide');
   });
 
-  $('.hide-outdated').on('click', function (e) {
+  $(document).on('click', '.hide-outdated', function (e) {
     e.preventDefault();
     const id = $(this).data('comment');
     $(this).addClass('hide');
@@ -1232,7 +1265,7 @@ function initPullRequestReview() {
     $(`#show-outdated-${id}`).removeClass('hide');
   });
 
-  $('button.comment-form-reply').on('click', function (e) {
+  $(document).on('click', 'button.comment-form-reply', function (e) {
     e.preventDefault();
     $(this).hide();
     const form = $(this).parent().find('.comment-form');
@@ -1264,7 +1297,7 @@ function initPullRequestReview() {
       $(this).closest('.menu').toggle('visible');
     });
 
-  $('.add-code-comment').on('click', function (e) {
+  $(document).on('click', '.add-code-comment', function (e) {
     if ($(e.target).hasClass('btn-add-single')) return; // https://github.com/go-gitea/gitea/issues/4745
     e.preventDefault();
 
@@ -1589,6 +1622,12 @@ function setCommentSimpleMDE($editArea) {
   return simplemde;
 }
 
+async function initIssueProposeFix() {
+  const textarea = document.getElementById('propose-fix-content');
+  if (!textarea) return;
+  await createCodeEditor(textarea, document.getElementById('propose-fix-tree-path'), []);
+}
+
 async function initEditor() {
   $('.js-quick-pull-choice-option').on('change', function () {
     if ($(this).val() === 'commit-to-new-branch') {
@@ -1655,12 +1694,14 @@ async function initEditor() {
   // Using events from https://github.com/codedance/jquery.AreYouSure#advanced-usage
   // to enable or disable the commit button
   const $commitButton = $('#commit-button');
+  const $stageButton = $('#stage-button');
   const $editForm = $('.ui.edit.form');
   const dirtyFileClass = 'dirty-file';
 
-  // Disabling the button at the start
+  // Disabling the buttons at the start
   if ($('input[name="page_has_posted"]').val() !== 'true') {
     $commitButton.prop('disabled', true);
+    $stageButton.prop('disabled', true);
   }
 
   // Registering a custom listener for the file path and the file content
@@ -1671,6 +1712,7 @@ async function initEditor() {
     change() {
       const dirty = $(this).hasClass(dirtyFileClass);
       $commitButton.prop('disabled', !dirty);
+      $stageButton.prop('disabled', !dirty);
     }
   });
 
@@ -1878,9 +1920,9 @@ function initAdmin() {
   // New authentication
   if ($('.admin.new.authentication').length > 0) {
     $('#auth_type').on('change', function () {
-      $('.ldap, .dldap, .smtp, .pam, .oauth2, .has-tls, .search-page-size, .sspi').hide();
+      $('.ldap, .dldap, .smtp, .pam, .oauth2, .has-tls, .search-page-size, .sspi, .saml').hide();
 
-      $('.ldap input[required], .binddnrequired input[required], .dldap input[required], .smtp input[required], .pam input[required], .oauth2 input[required], .has-tls input[required], .sspi input[required]').removeAttr('required');
+      $('.ldap input[required], .binddnrequired input[required], .dldap input[required], .smtp input[required], .pam input[required], .oauth2 input[required], .has-tls input[required], .sspi input[required], .saml input[required]').removeAttr('required');
       $('.binddnrequired').removeClass('required');
 
       const authType = $(this).val();
@@ -1912,6 +1954,9 @@ function initAdmin() {
           $('.sspi').show();
           $('.sspi div.required input').attr('required', 'required');
           break;
+        case '8': // SAML
+          $('.saml').show();
+          break;
       }
       if (authType === '2' || authType === '5') {
         onSecurityProtocolChange();
@@ -2116,12 +2161,74 @@ function initCodeView() {
     currentTarget.innerHTML = svg(`octicon-chevron-${folded ? 'right' : 'down'}`, 18);
     box.dataset.folded = String(folded);
   });
+  $(document).on('change', '.viewed-file-checkbox input', async ({currentTarget}) => {
+    const {url, path} = currentTarget.dataset;
+    const viewed = currentTarget.checked;
+    await $.post(url, {_csrf: csrf, path, viewed});
+    // the viewed files are folded
+    const box = currentTarget.closest('.file-content');
+    const fold = box.querySelector('.fold-file');
+    if (fold) fold.innerHTML = svg(`octicon-chevron-${viewed ? 'right' : 'down'}`, 18);
+    box.dataset.folded = String(viewed);
+  });
   $(document).on('click', '.blob-excerpt', async ({currentTarget}) => {
     const {url, query, anchor} = currentTarget.dataset;
     if (!url) return;
     const blob = await $.get(`${url}?${query}&anchor=${anchor}`);
     currentTarget.closest('tr').outerHTML = blob;
   });
+  $('.code-diff-split').each((_, diff) => pairSplitDiffLines(diff));
+  initDiffFileLoaders();
+}
+
+// pairSplitDiffLines shows the added lines next to the deleted lines they replace in a split diff,
+// the moved lines don't replace anything so they aren't paired
+function pairSplitDiffLines(diff) {
+  const isPairable = (row) => row.is('.del-code:not(.moved-code)') && row.children().eq(5).text().trim() === '';
+  $(diff).find('tr.add-code:not(.moved-code)').each(function () {
+    let prev = $(this).prev();
+    if (isPairable(prev)) {
+      while (isPairable(prev.prev())) {
+        prev = prev.prev();
+      }
+      prev.children().eq(3).attr('data-line-num', $(this).children().eq(3).attr('data-line-num'));
+      prev.children().eq(3).html($(this).children().eq(3).html());
+      prev.children().eq(4).html($(this).children().eq(4).html());
+      prev.children().eq(5).html($(this).children().eq(5).html());
+
+      prev.children().eq(0).addClass('del-code');
+      prev.children().eq(1).addClass('del-code');
+      prev.children().eq(2).addClass('del-code');
+      prev.children().eq(3).addClass('add-code');
+      prev.children().eq(4).addClass('add-code');
+      prev.children().eq(5).addClass('add-code');
+
+      $(this).remove();
+    }
+  });
+}
+
+// initDiffFileLoaders loads the diffs of the files of large pull requests when they are scrolled to
+function initDiffFileLoaders() {
+  const loaders = document.querySelectorAll('.diff-file-loader');
+  if (!loaders.length) return;
+
+  const observer = new IntersectionObserver(async (entries) => {
+    for (const {target, isIntersecting} of entries) {
+      if (!isIntersecting) continue;
+      observer.unobserve(target);
+      try {
+        const html = await $.get(target.dataset.url);
+        const box = $($.parseHTML(html)).filter('.diff-file-box');
+        $(target).closest('.diff-file-box').replaceWith(box);
+        box.find('.code-diff-split').each((_, diff) => pairSplitDiffLines(diff));
+        box.find('.poping.up').popup();
+      } catch (err) {
+        target.querySelector('td').textContent = err.statusText || String(err);
+      }
+    }
+  }, {rootMargin: '500px'});
+  for (const loader of loaders) observer.observe(loader);
 }
 
 function initU2FAuth() {
@@ -2470,6 +2577,13 @@ $(document).ready(async () => {
     });
   });
 
+  $('.issue-export').on('click', function () {
+    const issueIDs = $('.issue-checkbox').children('input:checked').map(function () {
+      return this.dataset.issueId;
+    }).get().join();
+    getArchive($(this), `${this.dataset.url}?issue_ids=${issueIDs}`, true);
+  });
+
   // NOTICE: This event trigger targets Firefox caching behaviour, as the checkboxes stay checked after reload
   // trigger ckecked event, if checkboxes are checked on load
   $('.issue-checkbox input[type="checkbox"]:checked').first().each((_, e) => {
@@ -2477,7 +2591,7 @@ $(document).ready(async () => {
     $(e).trigger('click');
   });
 
-  $('.resolve-conversation').on('click', function (e) {
+  $(document).on('click', '.resolve-conversation', function (e) {
     e.preventDefault();
     const id = $(this).data('comment-id');
     const action = $(this).data('action');
@@ -2504,10 +2618,14 @@ $(document).ready(async () => {
   initWikiForm();
   initEditForm();
   initEditor();
+  initIssueProposeFix();
   initOrganization();
   initWebhook();
   initAdmin();
   initCodeView();
+  initCodeFolding();
+  initImageDiff();
+  initRecalculations();
   initVueApp();
   initTeamSettings();
   initCtrlEnterSubmit();